@@ -330,6 +330,9 @@ func Init(srv *app.Server) (*API, error) {
 	api.InitUsage()
 	api.InitHostedCustomer()
 	api.InitDrafts()
+	api.InitScheduledPost()
+	api.InitSavedSearch()
+	api.InitPoll()
 	api.InitIPFiltering()
 	api.InitChannelBookmarks()
 	api.InitReports()
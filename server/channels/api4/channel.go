@@ -116,6 +116,10 @@ func createChannel(c *Context, w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if !checkChannelPostExpiryChange(c, "createChannel", 0, channel.PostExpiryMinutes) {
+		return
+	}
+
 	sc, appErr := c.App.CreateChannelWithUser(c.AppContext, channel, c.AppContext.Session().UserId)
 	if appErr != nil {
 		c.Err = appErr
@@ -377,6 +381,10 @@ func patchChannel(c *Context, w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
+	if patch.PostExpiryMinutes != nil && !checkChannelPostExpiryChange(c, "patchChannel", oldChannel.PostExpiryMinutes, *patch.PostExpiryMinutes) {
+		return
+	}
+
 	rchannel, appErr := c.App.PatchChannel(c.AppContext, oldChannel, patch, c.AppContext.Session().UserId)
 	if appErr != nil {
 		c.Err = appErr
@@ -399,6 +407,27 @@ func patchChannel(c *Context, w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// checkChannelPostExpiryChange checks that post expiry is enabled and that the session may
+// change the post expiry policy of a channel, which permanently deletes the posts of every
+// member. Like the channel moderation settings, the policy is managed from the System Console.
+func checkChannelPostExpiryChange(c *Context, where string, oldMinutes, newMinutes int64) bool {
+	if oldMinutes == newMinutes {
+		return true
+	}
+
+	if !*c.App.Config().ServiceSettings.EnablePostExpiry {
+		c.Err = model.NewAppError(where, "api.channel.post_expiry.feature_disabled", nil, "", http.StatusNotImplemented)
+		return false
+	}
+
+	if !c.App.SessionHasPermissionTo(*c.AppContext.Session(), model.PermissionSysconsoleWriteUserManagementChannels) {
+		c.SetPermissionError(model.PermissionSysconsoleWriteUserManagementChannels)
+		return false
+	}
+
+	return true
+}
+
 func restoreChannel(c *Context, w http.ResponseWriter, r *http.Request) {
 	c.RequireChannelId()
 	if c.Err != nil {
@@ -520,6 +520,51 @@ func TestPatchChannel(t *testing.T) {
 	})
 }
 
+func TestPatchChannelPostExpiry(t *testing.T) {
+	th := Setup(t).InitBasic()
+	defer th.TearDown()
+
+	patch := &model.ChannelPatch{PostExpiryMinutes: model.NewPointer(int64(60))}
+
+	t.Run("rejected when post expiry is disabled", func(t *testing.T) {
+		_, resp, err := th.SystemAdminClient.PatchChannel(context.Background(), th.BasicChannel.Id, patch)
+		require.Error(t, err)
+		CheckNotImplementedStatus(t, resp)
+	})
+
+	th.App.UpdateConfig(func(cfg *model.Config) { *cfg.ServiceSettings.EnablePostExpiry = true })
+
+	t.Run("channel members can't set the expiry policy", func(t *testing.T) {
+		_, resp, err := th.Client.PatchChannel(context.Background(), th.BasicChannel.Id, patch)
+		require.Error(t, err)
+		CheckForbiddenStatus(t, resp)
+
+		_, resp, err = th.Client.CreateChannel(context.Background(), &model.Channel{
+			TeamId:            th.BasicTeam.Id,
+			Name:              GenerateTestChannelName(),
+			DisplayName:       "Expiring",
+			Type:              model.ChannelTypeOpen,
+			PostExpiryMinutes: 60,
+		})
+		require.Error(t, err)
+		CheckForbiddenStatus(t, resp)
+	})
+
+	t.Run("channel members can patch other fields", func(t *testing.T) {
+		_, _, err := th.Client.PatchChannel(context.Background(), th.BasicChannel.Id, &model.ChannelPatch{
+			Header:            model.NewPointer("header"),
+			PostExpiryMinutes: model.NewPointer(int64(0)),
+		})
+		require.NoError(t, err)
+	})
+
+	t.Run("system admins can set the expiry policy", func(t *testing.T) {
+		channel, _, err := th.SystemAdminClient.PatchChannel(context.Background(), th.BasicChannel.Id, patch)
+		require.NoError(t, err)
+		assert.Equal(t, int64(60), channel.PostExpiryMinutes)
+	})
+}
+
 func TestChannelUnicodeNames(t *testing.T) {
 	th := Setup(t).InitBasic()
 	defer th.TearDown()
@@ -625,6 +625,40 @@ func TestRegenToken(t *testing.T) {
 	require.Empty(t, token, "should not return the token")
 }
 
+func TestRegenSigningSecret(t *testing.T) {
+	th := Setup(t).InitBasic()
+	defer th.TearDown()
+	client := th.Client
+
+	enableCommands := *th.App.Config().ServiceSettings.EnableCommands
+	defer func() {
+		th.App.UpdateConfig(func(cfg *model.Config) { cfg.ServiceSettings.EnableCommands = &enableCommands })
+	}()
+	th.App.UpdateConfig(func(cfg *model.Config) { *cfg.ServiceSettings.EnableCommands = true })
+
+	newCmd := &model.Command{
+		CreatorId: th.BasicUser.Id,
+		TeamId:    th.BasicTeam.Id,
+		URL:       "http://nowhere.com",
+		Method:    model.CommandMethodPost,
+		Trigger:   "trigger"}
+
+	createdCmd, resp, err := th.SystemAdminClient.CreateCommand(context.Background(), newCmd)
+	require.NoError(t, err)
+	CheckCreatedStatus(t, resp)
+	require.Len(t, createdCmd.SigningSecret, model.IntegrationSigningSecretLength)
+
+	secret, _, err := th.SystemAdminClient.RegenCommandSigningSecret(context.Background(), createdCmd.Id)
+	require.NoError(t, err)
+	require.Len(t, secret, model.IntegrationSigningSecretLength)
+	require.NotEqual(t, createdCmd.SigningSecret, secret, "should update the signing secret")
+
+	secret, resp, err = client.RegenCommandSigningSecret(context.Background(), createdCmd.Id)
+	require.Error(t, err)
+	CheckNotFoundStatus(t, resp)
+	require.Empty(t, secret, "should not return the signing secret")
+}
+
 func TestExecuteInvalidCommand(t *testing.T) {
 	th := Setup(t).InitBasic()
 	defer th.TearDown()
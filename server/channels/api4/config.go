@@ -152,6 +152,15 @@ func updateConfig(c *Context, w http.ResponseWriter, r *http.Request) {
 	// modifications to the slice.
 	cfg.PluginSettings.SignaturePublicKeyFiles = appCfg.PluginSettings.SignaturePublicKeyFiles
 
+	// Do not allow the OCR command to be changed through the API, as it is run by the server
+	*cfg.FileSettings.OCRCommand = *appCfg.FileSettings.OCRCommand
+
+	// Do not allow the image converter command to be changed through the API, as it is run by the server
+	*cfg.FileSettings.ImageConverterCommand = *appCfg.FileSettings.ImageConverterCommand
+
+	// Do not allow the ffmpeg command to be changed through the API, as it is run by the server
+	*cfg.FileSettings.FFmpegCommand = *appCfg.FileSettings.FFmpegCommand
+
 	// Do not allow marketplace URL to be toggled through the API if EnableUploads are disabled.
 	if cfg.PluginSettings.EnableUploads != nil && !*appCfg.PluginSettings.EnableUploads {
 		*cfg.PluginSettings.MarketplaceURL = *appCfg.PluginSettings.MarketplaceURL
@@ -306,6 +315,24 @@ func patchConfig(c *Context, w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Do not allow the OCR command to be changed through the API, as it is run by the server
+	if cfg.FileSettings.OCRCommand != nil && *cfg.FileSettings.OCRCommand != *appCfg.FileSettings.OCRCommand {
+		c.Err = model.NewAppError("patchConfig", "api.config.update_config.not_allowed_security.app_error", map[string]any{"Name": "FileSettings.OCRCommand"}, "", http.StatusForbidden)
+		return
+	}
+
+	// Do not allow the image converter command to be changed through the API, as it is run by the server
+	if cfg.FileSettings.ImageConverterCommand != nil && *cfg.FileSettings.ImageConverterCommand != *appCfg.FileSettings.ImageConverterCommand {
+		c.Err = model.NewAppError("patchConfig", "api.config.update_config.not_allowed_security.app_error", map[string]any{"Name": "FileSettings.ImageConverterCommand"}, "", http.StatusForbidden)
+		return
+	}
+
+	// Do not allow the ffmpeg command to be changed through the API, as it is run by the server
+	if cfg.FileSettings.FFmpegCommand != nil && *cfg.FileSettings.FFmpegCommand != *appCfg.FileSettings.FFmpegCommand {
+		c.Err = model.NewAppError("patchConfig", "api.config.update_config.not_allowed_security.app_error", map[string]any{"Name": "FileSettings.FFmpegCommand"}, "", http.StatusForbidden)
+		return
+	}
+
 	// Do not allow marketplace URL to be toggled if plugin uploads are disabled.
 	if cfg.PluginSettings.MarketplaceURL != nil && cfg.PluginSettings.EnableUploads != nil {
 		// Breaking it down to 2 conditions to make it simple.
@@ -217,6 +217,36 @@ func TestUpdateConfig(t *testing.T) {
 			assert.Equal(t, oldPublicKeys, cfg.PluginSettings.SignaturePublicKeyFiles)
 			assert.Equal(t, oldPublicKeys, th.App.Config().PluginSettings.SignaturePublicKeyFiles)
 		})
+
+		t.Run("Should not be able to modify FileSettings.OCRCommand", func(t *testing.T) {
+			oldCommand := *th.App.Config().FileSettings.OCRCommand
+			*cfg.FileSettings.OCRCommand = "/bin/sh"
+
+			cfg, _, err = client.UpdateConfig(context.Background(), cfg)
+			require.NoError(t, err)
+			assert.Equal(t, oldCommand, *cfg.FileSettings.OCRCommand)
+			assert.Equal(t, oldCommand, *th.App.Config().FileSettings.OCRCommand)
+		})
+
+		t.Run("Should not be able to modify FileSettings.ImageConverterCommand", func(t *testing.T) {
+			oldCommand := *th.App.Config().FileSettings.ImageConverterCommand
+			*cfg.FileSettings.ImageConverterCommand = "/bin/sh"
+
+			cfg, _, err = client.UpdateConfig(context.Background(), cfg)
+			require.NoError(t, err)
+			assert.Equal(t, oldCommand, *cfg.FileSettings.ImageConverterCommand)
+			assert.Equal(t, oldCommand, *th.App.Config().FileSettings.ImageConverterCommand)
+		})
+
+		t.Run("Should not be able to modify FileSettings.FFmpegCommand", func(t *testing.T) {
+			oldCommand := *th.App.Config().FileSettings.FFmpegCommand
+			*cfg.FileSettings.FFmpegCommand = "/bin/sh"
+
+			cfg, _, err = client.UpdateConfig(context.Background(), cfg)
+			require.NoError(t, err)
+			assert.Equal(t, oldCommand, *cfg.FileSettings.FFmpegCommand)
+			assert.Equal(t, oldCommand, *th.App.Config().FileSettings.FFmpegCommand)
+		})
 	})
 
 	t.Run("Should not be able to modify PluginSettings.MarketplaceURL if EnableUploads is disabled", func(t *testing.T) {
@@ -755,6 +785,66 @@ func TestPatchConfig(t *testing.T) {
 			assert.Equal(t, model.FakeSetting, *updatedConfig.SqlSettings.DataSource)
 		})
 
+		t.Run("not allowing to change the OCR command via api", func(t *testing.T) {
+			defer th.App.UpdateConfig(func(cfg *model.Config) {
+				*cfg.FileSettings.OCRCommand = model.FileSettingsDefaultOCRCommand
+			})
+
+			config := model.Config{FileSettings: model.FileSettings{
+				OCRCommand: model.NewPointer("/bin/sh"),
+			}}
+
+			_, resp, err := client.PatchConfig(context.Background(), &config)
+			if client == th.LocalClient {
+				require.NoError(t, err)
+				CheckOKStatus(t, resp)
+			} else {
+				require.Error(t, err)
+				CheckForbiddenStatus(t, resp)
+				assert.Equal(t, model.FileSettingsDefaultOCRCommand, *th.App.Config().FileSettings.OCRCommand)
+			}
+		})
+
+		t.Run("not allowing to change the image converter command via api", func(t *testing.T) {
+			defer th.App.UpdateConfig(func(cfg *model.Config) {
+				*cfg.FileSettings.ImageConverterCommand = model.FileSettingsDefaultImageConverterCommand
+			})
+
+			config := model.Config{FileSettings: model.FileSettings{
+				ImageConverterCommand: model.NewPointer("/bin/sh"),
+			}}
+
+			_, resp, err := client.PatchConfig(context.Background(), &config)
+			if client == th.LocalClient {
+				require.NoError(t, err)
+				CheckOKStatus(t, resp)
+			} else {
+				require.Error(t, err)
+				CheckForbiddenStatus(t, resp)
+				assert.Equal(t, model.FileSettingsDefaultImageConverterCommand, *th.App.Config().FileSettings.ImageConverterCommand)
+			}
+		})
+
+		t.Run("not allowing to change the ffmpeg command via api", func(t *testing.T) {
+			defer th.App.UpdateConfig(func(cfg *model.Config) {
+				*cfg.FileSettings.FFmpegCommand = model.FileSettingsDefaultFFmpegCommand
+			})
+
+			config := model.Config{FileSettings: model.FileSettings{
+				FFmpegCommand: model.NewPointer("/bin/sh"),
+			}}
+
+			_, resp, err := client.PatchConfig(context.Background(), &config)
+			if client == th.LocalClient {
+				require.NoError(t, err)
+				CheckOKStatus(t, resp)
+			} else {
+				require.Error(t, err)
+				CheckForbiddenStatus(t, resp)
+				assert.Equal(t, model.FileSettingsDefaultFFmpegCommand, *th.App.Config().FileSettings.FFmpegCommand)
+			}
+		})
+
 		t.Run("not allowing to toggle enable uploads for plugin via api", func(t *testing.T) {
 			config := model.Config{PluginSettings: model.PluginSettings{
 				EnableUploads: model.NewPointer(true),
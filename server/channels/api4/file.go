@@ -11,7 +11,9 @@ import (
 	"mime"
 	"mime/multipart"
 	"net/http"
+	"path/filepath"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/mattermost/mattermost/server/public/model"
@@ -25,8 +27,10 @@ import (
 const (
 	FileTeamId = "noteam"
 
-	PreviewImageType   = "image/jpeg"
-	ThumbnailImageType = "image/jpeg"
+	PreviewImageType     = "image/jpeg"
+	WebPPreviewImageType = "image/webp"
+	ThumbnailImageType   = "image/jpeg"
+	RenditionType        = "video/mp4"
 )
 
 const maxMultipartFormDataBytes = 10 * 1024 // 10Kb
@@ -37,7 +41,13 @@ func (api *API) InitFile() {
 	api.BaseRoutes.File.Handle("/thumbnail", api.APISessionRequiredTrustRequester(getFileThumbnail)).Methods(http.MethodGet)
 	api.BaseRoutes.File.Handle("/link", api.APISessionRequired(getFileLink)).Methods(http.MethodGet)
 	api.BaseRoutes.File.Handle("/preview", api.APISessionRequiredTrustRequester(getFilePreview)).Methods(http.MethodGet)
+	api.BaseRoutes.File.Handle("/rendition", api.APISessionRequiredTrustRequester(getFileRendition)).Methods(http.MethodGet)
 	api.BaseRoutes.File.Handle("/info", api.APISessionRequired(getFileInfo)).Methods(http.MethodGet)
+	api.BaseRoutes.File.Handle("/quarantine/release", api.APISessionRequired(releaseQuarantinedFile)).Methods(http.MethodPost)
+	api.BaseRoutes.File.Handle("/quarantine", api.APISessionRequired(deleteQuarantinedFile)).Methods(http.MethodDelete)
+
+	// Registered outside of /files, where quarantine would be taken for a file ID.
+	api.BaseRoutes.APIRoot.Handle("/quarantine/files", api.APISessionRequired(getQuarantinedFiles)).Methods(http.MethodGet)
 
 	api.BaseRoutes.Team.Handle("/files/search", api.APISessionRequiredDisableWhenBusy(searchFiles)).Methods(http.MethodPost)
 
@@ -499,6 +509,11 @@ func getFile(c *Context, w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if err = c.App.CheckFileScanStatus(c.AppContext, info); err != nil {
+		c.Err = err
+		return
+	}
+
 	fileReader, err := c.App.FileReader(info.Path)
 	if err != nil {
 		c.Err = err
@@ -542,6 +557,11 @@ func getFileThumbnail(c *Context, w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if err = c.App.CheckFileScanStatus(c.AppContext, info); err != nil {
+		c.Err = err
+		return
+	}
+
 	if info.ThumbnailPath == "" {
 		c.Err = model.NewAppError("getFileThumbnail", "api.file.get_file_thumbnail.no_thumbnail.app_error", nil, "file_id="+info.Id, http.StatusBadRequest)
 		return
@@ -596,6 +616,11 @@ func getFileLink(c *Context, w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if err = c.App.CheckFileScanStatus(c.AppContext, info); err != nil {
+		c.Err = err
+		return
+	}
+
 	if info.PostId == "" && info.CreatorId != model.BookmarkFileOwner {
 		c.Err = model.NewAppError("getPublicLink", "api.file.get_public_link.no_post.app_error", nil, "file_id="+info.Id, http.StatusBadRequest)
 		return
@@ -640,6 +665,11 @@ func getFilePreview(c *Context, w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if err = c.App.CheckFileScanStatus(c.AppContext, info); err != nil {
+		c.Err = err
+		return
+	}
+
 	if info.PreviewPath == "" {
 		c.Err = model.NewAppError("getFilePreview", "api.file.get_file_preview.no_preview.app_error", nil, "file_id="+info.Id, http.StatusBadRequest)
 		return
@@ -653,7 +683,64 @@ func getFilePreview(c *Context, w http.ResponseWriter, r *http.Request) {
 	}
 	defer fileReader.Close()
 
-	web.WriteFileResponse(info.Name, PreviewImageType, 0, time.Unix(0, info.UpdateAt*int64(1000*1000)), *c.App.Config().ServiceSettings.WebserverMode, fileReader, forceDownload, w, r)
+	previewImageType := PreviewImageType
+	if strings.HasSuffix(info.PreviewPath, ".webp") {
+		previewImageType = WebPPreviewImageType
+	}
+
+	web.WriteFileResponse(info.Name, previewImageType, 0, time.Unix(0, info.UpdateAt*int64(1000*1000)), *c.App.Config().ServiceSettings.WebserverMode, fileReader, forceDownload, w, r)
+}
+
+func getFileRendition(c *Context, w http.ResponseWriter, r *http.Request) {
+	c.RequireFileId()
+	if c.Err != nil {
+		return
+	}
+
+	forceDownload, _ := strconv.ParseBool(r.URL.Query().Get("download"))
+	info, err := c.App.GetFileInfo(c.AppContext, c.Params.FileId)
+	if err != nil {
+		c.Err = err
+		setInaccessibleFileHeader(w, err)
+		return
+	}
+
+	channel, err := c.App.GetChannel(c.AppContext, info.ChannelId)
+	if err != nil {
+		c.Err = err
+		return
+	}
+	perm := c.App.SessionHasPermissionToReadChannel(c.AppContext, *c.AppContext.Session(), channel)
+	if info.CreatorId == model.BookmarkFileOwner {
+		if !perm {
+			c.SetPermissionError(model.PermissionReadChannelContent)
+			return
+		}
+	} else if info.CreatorId != c.AppContext.Session().UserId && !perm {
+		c.SetPermissionError(model.PermissionReadChannelContent)
+		return
+	}
+
+	if err = c.App.CheckFileScanStatus(c.AppContext, info); err != nil {
+		c.Err = err
+		return
+	}
+
+	if info.RenditionPath == "" {
+		c.Err = model.NewAppError("getFileRendition", "api.file.get_file_rendition.no_rendition.app_error", nil, "file_id="+info.Id, http.StatusNotFound)
+		return
+	}
+
+	fileReader, err := c.App.FileReader(info.RenditionPath)
+	if err != nil {
+		c.Err = err
+		c.Err.StatusCode = http.StatusNotFound
+		return
+	}
+	defer fileReader.Close()
+
+	name := strings.TrimSuffix(info.Name, filepath.Ext(info.Name)) + ".mp4"
+	web.WriteFileResponse(name, RenditionType, 0, time.Unix(0, info.UpdateAt*int64(1000*1000)), *c.App.Config().ServiceSettings.WebserverMode, fileReader, forceDownload, w, r)
 }
 
 func getFileInfo(c *Context, w http.ResponseWriter, r *http.Request) {
@@ -723,6 +810,12 @@ func getPublicFile(c *Context, w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if err = c.App.CheckFileScanStatus(c.AppContext, info); err != nil {
+		c.Err = err
+		utils.RenderWebAppError(c.App.Config(), w, r, c.Err, c.App.AsymmetricSigningKey())
+		return
+	}
+
 	fileReader, err := c.App.FileReader(info.Path)
 	if err != nil {
 		c.Err = err
@@ -805,6 +898,78 @@ func searchFiles(c *Context, w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+func getQuarantinedFiles(c *Context, w http.ResponseWriter, r *http.Request) {
+	if !c.App.SessionHasPermissionTo(*c.AppContext.Session(), model.PermissionManageSystem) {
+		c.SetPermissionError(model.PermissionManageSystem)
+		return
+	}
+
+	infos, err := c.App.GetQuarantinedFileInfos(c.Params.Page, c.Params.PerPage)
+	if err != nil {
+		c.Err = err
+		return
+	}
+
+	if err := json.NewEncoder(w).Encode(infos); err != nil {
+		c.Logger.Warn("Error while writing response", mlog.Err(err))
+	}
+}
+
+func releaseQuarantinedFile(c *Context, w http.ResponseWriter, r *http.Request) {
+	c.RequireFileId()
+	if c.Err != nil {
+		return
+	}
+
+	auditRec := c.MakeAuditRecord("releaseQuarantinedFile", audit.Fail)
+	defer c.LogAuditRec(auditRec)
+	audit.AddEventParameter(auditRec, "file_id", c.Params.FileId)
+
+	if !c.App.SessionHasPermissionTo(*c.AppContext.Session(), model.PermissionManageSystem) {
+		c.SetPermissionError(model.PermissionManageSystem)
+		return
+	}
+
+	info, err := c.App.ReleaseQuarantinedFile(c.AppContext, c.Params.FileId)
+	if err != nil {
+		c.Err = err
+		return
+	}
+
+	auditRec.Success()
+	auditRec.AddEventResultState(info)
+	auditRec.AddEventObjectType("file_info")
+
+	if err := json.NewEncoder(w).Encode(info); err != nil {
+		c.Logger.Warn("Error while writing response", mlog.Err(err))
+	}
+}
+
+func deleteQuarantinedFile(c *Context, w http.ResponseWriter, r *http.Request) {
+	c.RequireFileId()
+	if c.Err != nil {
+		return
+	}
+
+	auditRec := c.MakeAuditRecord("deleteQuarantinedFile", audit.Fail)
+	defer c.LogAuditRec(auditRec)
+	audit.AddEventParameter(auditRec, "file_id", c.Params.FileId)
+
+	if !c.App.SessionHasPermissionTo(*c.AppContext.Session(), model.PermissionManageSystem) {
+		c.SetPermissionError(model.PermissionManageSystem)
+		return
+	}
+
+	if err := c.App.DeleteQuarantinedFile(c.AppContext, c.Params.FileId); err != nil {
+		c.Err = err
+		return
+	}
+
+	auditRec.Success()
+
+	ReturnStatusOK(w)
+}
+
 func setInaccessibleFileHeader(w http.ResponseWriter, appErr *model.AppError) {
 	// File is inaccessible due to cloud plan's limit.
 	if appErr.Id == "app.file.cloud.get.app_error" {
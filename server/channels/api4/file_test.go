@@ -1154,6 +1154,115 @@ func TestGetPublicFile(t *testing.T) {
 	require.Equal(t, http.StatusNotFound, resp.StatusCode, "should've failed to get file after it is deleted")
 }
 
+func TestQuarantinedFiles(t *testing.T) {
+	th := Setup(t).InitBasic()
+	defer th.TearDown()
+	client := th.Client
+
+	th.App.UpdateConfig(func(cfg *model.Config) { *cfg.FileSettings.EnablePublicLink = true })
+
+	data, err := testutils.ReadTestFile("test.png")
+	require.NoError(t, err)
+
+	upload := func(status string) *model.FileInfo {
+		fileResp, _, err := client.UploadFile(context.Background(), data, th.BasicChannel.Id, "test.png")
+		require.NoError(t, err)
+		fileID := fileResp.FileInfos[0].Id
+
+		err = th.App.Srv().Store().FileInfo().AttachToPost(th.Context, fileID, th.BasicPost.Id, th.BasicPost.ChannelId, th.BasicUser.Id)
+		require.NoError(t, err)
+		err = th.App.Srv().Store().FileInfo().SetScanStatus(th.Context, fileID, status, "Eicar-Test-Signature")
+		require.NoError(t, err)
+
+		info, err := th.App.Srv().Store().FileInfo().Get(fileID)
+		require.NoError(t, err)
+		return info
+	}
+
+	infected := upload(model.FileScanStatusInfected)
+	failed := upload(model.FileScanStatusFailed)
+
+	t.Run("downloads are blocked", func(t *testing.T) {
+		_, resp, err := client.GetFile(context.Background(), infected.Id)
+		require.Error(t, err)
+		CheckForbiddenStatus(t, resp)
+		CheckErrorID(t, err, "app.file.scan_status.infected.app_error")
+
+		_, resp, err = client.GetFileThumbnail(context.Background(), infected.Id)
+		require.Error(t, err)
+		CheckForbiddenStatus(t, resp)
+
+		_, resp, err = client.GetFilePreview(context.Background(), failed.Id)
+		require.Error(t, err)
+		CheckForbiddenStatus(t, resp)
+		CheckErrorID(t, err, "app.file.scan_status.failed.app_error")
+
+		_, resp, err = client.GetFileLink(context.Background(), infected.Id)
+		require.Error(t, err)
+		CheckForbiddenStatus(t, resp)
+
+		httpResp, err := http.Get(th.App.GeneratePublicLink(client.URL, infected))
+		require.NoError(t, err)
+		require.Equal(t, http.StatusForbidden, httpResp.StatusCode)
+
+		info, _, err := client.GetFileInfo(context.Background(), infected.Id)
+		require.NoError(t, err)
+		assert.Equal(t, model.FileScanStatusInfected, info.ScanStatus)
+	})
+
+	t.Run("only admins manage the quarantine", func(t *testing.T) {
+		_, resp, err := client.GetQuarantinedFiles(context.Background(), 0, 100)
+		require.Error(t, err)
+		CheckForbiddenStatus(t, resp)
+
+		_, resp, err = client.ReleaseQuarantinedFile(context.Background(), infected.Id)
+		require.Error(t, err)
+		CheckForbiddenStatus(t, resp)
+
+		resp, err = client.DeleteQuarantinedFile(context.Background(), infected.Id)
+		require.Error(t, err)
+		CheckForbiddenStatus(t, resp)
+	})
+
+	t.Run("list", func(t *testing.T) {
+		infos, _, err := th.SystemAdminClient.GetQuarantinedFiles(context.Background(), 0, 100)
+		require.NoError(t, err)
+
+		ids := make([]string, 0, len(infos))
+		for _, info := range infos {
+			ids = append(ids, info.Id)
+		}
+		assert.Contains(t, ids, infected.Id)
+		assert.Contains(t, ids, failed.Id)
+	})
+
+	t.Run("release", func(t *testing.T) {
+		info, _, err := th.SystemAdminClient.ReleaseQuarantinedFile(context.Background(), infected.Id)
+		require.NoError(t, err)
+		assert.Equal(t, model.FileScanStatusClean, info.ScanStatus)
+
+		_, _, err = client.GetFile(context.Background(), infected.Id)
+		require.NoError(t, err)
+
+		_, resp, err := th.SystemAdminClient.ReleaseQuarantinedFile(context.Background(), infected.Id)
+		require.Error(t, err)
+		CheckBadRequestStatus(t, resp)
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		_, err := th.SystemAdminClient.DeleteQuarantinedFile(context.Background(), failed.Id)
+		require.NoError(t, err)
+
+		_, resp, err := th.SystemAdminClient.GetFileInfo(context.Background(), failed.Id)
+		require.Error(t, err)
+		CheckNotFoundStatus(t, resp)
+
+		resp, err = th.SystemAdminClient.DeleteQuarantinedFile(context.Background(), model.NewId())
+		require.Error(t, err)
+		CheckNotFoundStatus(t, resp)
+	})
+}
+
 func TestSearchFiles(t *testing.T) {
 	th := Setup(t).InitBasic()
 	defer th.TearDown()
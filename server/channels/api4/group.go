@@ -631,7 +631,7 @@ func verifyLinkUnlinkPermission(c *Context, syncableType model.GroupSyncableType
 		return appErr
 	}
 
-	if group.Source != model.GroupSourceLdap {
+	if group.Source != model.GroupSourceLdap && !group.IsScimManaged() {
 		return model.NewAppError("Api4.linkGroupSyncable", "app.group.crud_permission", nil, "", http.StatusBadRequest)
 	}
 
@@ -1158,7 +1158,7 @@ func deleteGroup(c *Context, w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if group.Source != model.GroupSourceCustom {
+	if group.Source != model.GroupSourceCustom || group.IsScimManaged() {
 		c.Err = model.NewAppError("Api4.deleteGroup", "app.group.crud_permission", nil, "", http.StatusBadRequest)
 		return
 	}
@@ -1211,7 +1211,7 @@ func restoreGroup(c *Context, w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if group.Source != model.GroupSourceCustom {
+	if group.Source != model.GroupSourceCustom || group.IsScimManaged() {
 		c.Err = model.NewAppError("Api4.restoreGroup", "app.group.crud_permission", nil, "", http.StatusNotImplemented)
 		return
 	}
@@ -1264,7 +1264,7 @@ func addGroupMembers(c *Context, w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if group.Source != model.GroupSourceCustom {
+	if group.Source != model.GroupSourceCustom || group.IsScimManaged() {
 		c.Err = model.NewAppError("Api4.addGroupMembers", "app.group.crud_permission", nil, "", http.StatusBadRequest)
 		return
 	}
@@ -1323,7 +1323,7 @@ func deleteGroupMembers(c *Context, w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if group.Source != model.GroupSourceCustom {
+	if group.Source != model.GroupSourceCustom || group.IsScimManaged() {
 		c.Err = model.NewAppError("Api4.deleteGroupMembers", "app.group.crud_permission", nil, "", http.StatusBadRequest)
 		return
 	}
@@ -7,7 +7,9 @@ import (
 	"encoding/json"
 	"net/http"
 	"path/filepath"
+	"reflect"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/mattermost/mattermost/server/public/model"
@@ -112,6 +114,16 @@ func createJob(c *Context, w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// The file settings of a migration hold the credentials of the new backend, so they are
+	// sealed before the job is audited and stored.
+	fileSettings := job.Data["file_settings"]
+	if job.Type == model.JobTypeFileMigration {
+		if appErr := c.App.SealFileMigrationSettings(&job); appErr != nil {
+			c.Err = appErr
+			return
+		}
+	}
+
 	auditRec := c.MakeAuditRecord("createJob", audit.Fail)
 	defer c.LogAuditRec(auditRec)
 	audit.AddEventParameterAuditable(auditRec, "job", &job)
@@ -127,6 +139,13 @@ func createJob(c *Context, w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if job.Type == model.JobTypeFileMigration {
+		checkFileMigrationSettings(c, fileSettings)
+		if c.Err != nil {
+			return
+		}
+	}
+
 	rjob, err := c.App.CreateJob(c.AppContext, &job)
 	if err != nil {
 		c.Err = err
@@ -143,6 +162,31 @@ func createJob(c *Context, w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// checkFileMigrationSettings checks that the session may change the file settings a file
+// migration switches to once complete, the same way as when the configuration is updated.
+func checkFileMigrationSettings(c *Context, fileSettings string) {
+	var settings map[string]json.RawMessage
+	if err := json.Unmarshal([]byte(fileSettings), &settings); err != nil {
+		c.SetInvalidParamWithErr("file_settings", err)
+		return
+	}
+
+	settingsType := reflect.TypeOf(model.FileSettings{})
+	for name := range settings {
+		// The settings are matched the same way as when they are unmarshaled.
+		field, ok := settingsType.FieldByNameFunc(func(fieldName string) bool {
+			return strings.EqualFold(fieldName, name)
+		})
+		if !ok {
+			continue
+		}
+		if !writeFilter(c, field) {
+			c.Err = model.NewAppError("createJob", "api.config.update_config.not_allowed_security.app_error", map[string]any{"Name": "FileSettings." + field.Name}, "", http.StatusForbidden)
+			return
+		}
+	}
+}
+
 func getJobs(c *Context, w http.ResponseWriter, r *http.Request) {
 	if c.Err != nil {
 		return
@@ -0,0 +1,90 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package api4
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+	"github.com/mattermost/mattermost/server/v8/channels/app"
+	"github.com/mattermost/mattermost/server/v8/channels/audit"
+)
+
+func (api *API) InitPoll() {
+	api.BaseRoutes.Post.Handle("/poll/vote", api.APISessionRequired(voteOnPoll)).Methods(http.MethodPost)
+	api.BaseRoutes.Post.Handle("/poll/close", api.APISessionRequired(closePoll)).Methods(http.MethodPost)
+}
+
+func voteOnPoll(c *Context, w http.ResponseWriter, r *http.Request) {
+	c.RequirePostId()
+	if c.Err != nil {
+		return
+	}
+
+	var voteRequest model.PollVoteRequest
+	if jsonErr := json.NewDecoder(r.Body).Decode(&voteRequest); jsonErr != nil {
+		c.SetInvalidParamWithErr("vote", jsonErr)
+		return
+	}
+
+	// Voting is akin to posting in the channel, so reading it isn't enough.
+	if !c.App.SessionHasPermissionToChannelByPost(*c.AppContext.Session(), c.Params.PostId, model.PermissionCreatePost) {
+		c.SetPermissionError(model.PermissionCreatePost)
+		return
+	}
+
+	metadata, appErr := c.App.VoteOnPoll(c.AppContext, c.Params.PostId, c.AppContext.Session().UserId, voteRequest.OptionIds)
+	if appErr != nil {
+		c.Err = appErr
+		return
+	}
+
+	if err := json.NewEncoder(w).Encode(metadata); err != nil {
+		c.Logger.Warn("Error while writing response", mlog.Err(err))
+	}
+}
+
+func closePoll(c *Context, w http.ResponseWriter, r *http.Request) {
+	c.RequirePostId()
+	if c.Err != nil {
+		return
+	}
+
+	auditRec := c.MakeAuditRecord("closePoll", audit.Fail)
+	defer c.LogAuditRecWithLevel(auditRec, app.LevelContent)
+	audit.AddEventParameter(auditRec, "post_id", c.Params.PostId)
+
+	post, appErr := c.App.GetSinglePost(c.AppContext, c.Params.PostId, false)
+	if appErr != nil {
+		c.SetPermissionError(model.PermissionEditPost)
+		return
+	}
+	auditRec.AddEventPriorState(post)
+	auditRec.AddEventObjectType("post")
+
+	// Closing a poll is akin to editing it, so the same permissions apply.
+	permission := model.PermissionEditPost
+	if c.AppContext.Session().UserId != post.UserId {
+		permission = model.PermissionEditOthersPosts
+	}
+
+	if !c.App.SessionHasPermissionToChannel(c.AppContext, *c.AppContext.Session(), post.ChannelId, permission) {
+		c.SetPermissionError(permission)
+		return
+	}
+
+	metadata, appErr := c.App.ClosePoll(c.AppContext, c.Params.PostId)
+	if appErr != nil {
+		c.Err = appErr
+		return
+	}
+
+	auditRec.Success()
+
+	if err := json.NewEncoder(w).Encode(metadata); err != nil {
+		c.Logger.Warn("Error while writing response", mlog.Err(err))
+	}
+}
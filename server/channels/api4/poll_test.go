@@ -0,0 +1,200 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package api4
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost/server/public/model"
+)
+
+func createTestPoll(t *testing.T, th *TestHelper, poll *model.Poll) (*model.Post, *model.Poll) {
+	post := &model.Post{
+		ChannelId: th.BasicChannel.Id,
+		Message:   poll.Question,
+		Type:      model.PostTypePoll,
+	}
+	post.AddProp(model.PostPropsPoll, poll)
+
+	rpost, _, err := th.Client.CreatePost(context.Background(), post)
+	require.NoError(t, err)
+	require.NotNil(t, rpost.Metadata)
+	require.NotNil(t, rpost.Metadata.Poll)
+
+	savedPoll, err := rpost.GetPoll()
+	require.NoError(t, err)
+	require.Len(t, savedPoll.Options, len(poll.Options))
+
+	return rpost, savedPoll
+}
+
+func TestVoteOnPoll(t *testing.T) {
+	th := Setup(t).InitBasic()
+	defer th.TearDown()
+
+	post, poll := createTestPoll(t, th, &model.Poll{
+		Question: "What for lunch?",
+		Options:  []*model.PollOption{{Text: "Pizza"}, {Text: "Sushi"}},
+	})
+	pizza := poll.Options[0].Id
+	sushi := poll.Options[1].Id
+
+	t.Run("vote", func(t *testing.T) {
+		metadata, _, err := th.Client.VoteOnPoll(context.Background(), post.Id, []string{pizza})
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), metadata.Votes[pizza])
+		assert.Equal(t, []string{th.BasicUser.Id}, metadata.Voters[pizza])
+		assert.Equal(t, []string{pizza}, metadata.UserVotes)
+	})
+
+	t.Run("change vote", func(t *testing.T) {
+		metadata, _, err := th.Client.VoteOnPoll(context.Background(), post.Id, []string{sushi})
+		require.NoError(t, err)
+		assert.Equal(t, int64(0), metadata.Votes[pizza])
+		assert.Equal(t, int64(1), metadata.Votes[sushi])
+		assert.Equal(t, int64(1), metadata.VoterCount)
+	})
+
+	t.Run("votes are attached to the post", func(t *testing.T) {
+		_, _, err := th.SystemAdminClient.VoteOnPoll(context.Background(), post.Id, []string{sushi})
+		require.NoError(t, err)
+
+		rpost, _, err := th.Client.GetPost(context.Background(), post.Id, "")
+		require.NoError(t, err)
+		require.NotNil(t, rpost.Metadata.Poll)
+		assert.Equal(t, int64(2), rpost.Metadata.Poll.Votes[sushi])
+		assert.Equal(t, []string{sushi}, rpost.Metadata.Poll.UserVotes)
+	})
+
+	t.Run("single choice", func(t *testing.T) {
+		_, resp, err := th.Client.VoteOnPoll(context.Background(), post.Id, []string{pizza, sushi})
+		require.Error(t, err)
+		CheckBadRequestStatus(t, resp)
+	})
+
+	t.Run("unknown option", func(t *testing.T) {
+		_, resp, err := th.Client.VoteOnPoll(context.Background(), post.Id, []string{model.NewId()})
+		require.Error(t, err)
+		CheckBadRequestStatus(t, resp)
+	})
+
+	t.Run("not a poll", func(t *testing.T) {
+		_, resp, err := th.Client.VoteOnPoll(context.Background(), th.BasicPost.Id, []string{pizza})
+		require.Error(t, err)
+		CheckBadRequestStatus(t, resp)
+	})
+
+	t.Run("no access to the channel", func(t *testing.T) {
+		privatePost := th.CreatePostWithClient(th.SystemAdminClient, th.CreateChannelWithClient(th.SystemAdminClient, model.ChannelTypePrivate))
+
+		_, resp, err := th.Client.VoteOnPoll(context.Background(), privatePost.Id, []string{pizza})
+		require.Error(t, err)
+		CheckForbiddenStatus(t, resp)
+	})
+
+	t.Run("not a member of the public channel", func(t *testing.T) {
+		publicChannel := th.CreateChannelWithClient(th.SystemAdminClient, model.ChannelTypeOpen)
+		publicPost := &model.Post{
+			ChannelId: publicChannel.Id,
+			Message:   "Where for lunch?",
+			Type:      model.PostTypePoll,
+		}
+		publicPost.AddProp(model.PostPropsPoll, &model.Poll{
+			Question: publicPost.Message,
+			Options:  []*model.PollOption{{Text: "Here"}, {Text: "There"}},
+		})
+		publicPost, _, err := th.SystemAdminClient.CreatePost(context.Background(), publicPost)
+		require.NoError(t, err)
+		publicPoll, err := publicPost.GetPoll()
+		require.NoError(t, err)
+
+		_, resp, err := th.Client.VoteOnPoll(context.Background(), publicPost.Id, []string{publicPoll.Options[0].Id})
+		require.Error(t, err)
+		CheckForbiddenStatus(t, resp)
+	})
+
+	t.Run("retract vote", func(t *testing.T) {
+		metadata, _, err := th.Client.VoteOnPoll(context.Background(), post.Id, nil)
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), metadata.Votes[sushi])
+		assert.Empty(t, metadata.UserVotes)
+	})
+
+	t.Run("votes are deleted with the post", func(t *testing.T) {
+		_, err := th.Client.DeletePost(context.Background(), post.Id)
+		require.NoError(t, err)
+
+		require.Eventually(t, func() bool {
+			votes, err := th.App.Srv().Store().PollVote().GetForPost(post.Id)
+			return err == nil && len(votes) == 0
+		}, 5*time.Second, 100*time.Millisecond)
+	})
+}
+
+func TestVoteOnAnonymousPoll(t *testing.T) {
+	th := Setup(t).InitBasic()
+	defer th.TearDown()
+
+	post, poll := createTestPoll(t, th, &model.Poll{
+		Question:       "Which days?",
+		Options:        []*model.PollOption{{Text: "Monday"}, {Text: "Tuesday"}},
+		Anonymous:      true,
+		MultipleChoice: true,
+	})
+
+	metadata, _, err := th.Client.VoteOnPoll(context.Background(), post.Id, []string{poll.Options[0].Id, poll.Options[1].Id})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), metadata.Votes[poll.Options[0].Id])
+	assert.Equal(t, int64(1), metadata.Votes[poll.Options[1].Id])
+	assert.Nil(t, metadata.Voters)
+	assert.Len(t, metadata.UserVotes, 2)
+}
+
+func TestClosePoll(t *testing.T) {
+	th := Setup(t).InitBasic()
+	defer th.TearDown()
+
+	post, poll := createTestPoll(t, th, &model.Poll{
+		Question: "What for lunch?",
+		Options:  []*model.PollOption{{Text: "Pizza"}, {Text: "Sushi"}},
+	})
+
+	t.Run("other users cannot close the poll", func(t *testing.T) {
+		th.LoginBasic2()
+		defer th.LoginBasic()
+
+		_, resp, err := th.Client.ClosePoll(context.Background(), post.Id)
+		require.Error(t, err)
+		CheckForbiddenStatus(t, resp)
+	})
+
+	t.Run("close", func(t *testing.T) {
+		metadata, _, err := th.Client.ClosePoll(context.Background(), post.Id)
+		require.NoError(t, err)
+		assert.True(t, metadata.Closed)
+
+		rpost, _, err := th.Client.GetPost(context.Background(), post.Id, "")
+		require.NoError(t, err)
+		closedPoll, err := rpost.GetPoll()
+		require.NoError(t, err)
+		assert.NotZero(t, closedPoll.ClosedAt)
+	})
+
+	t.Run("cannot vote on a closed poll", func(t *testing.T) {
+		_, resp, err := th.Client.VoteOnPoll(context.Background(), post.Id, []string{poll.Options[0].Id})
+		require.Error(t, err)
+		CheckForbiddenStatus(t, resp)
+	})
+
+	t.Run("cannot close twice", func(t *testing.T) {
+		_, resp, err := th.Client.ClosePoll(context.Background(), post.Id)
+		require.Error(t, err)
+		CheckBadRequestStatus(t, resp)
+	})
+}
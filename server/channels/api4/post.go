@@ -26,6 +26,8 @@ func (api *API) InitPost() {
 	api.BaseRoutes.Post.Handle("/edit_history", api.APISessionRequired(getEditHistoryForPost)).Methods(http.MethodGet)
 	api.BaseRoutes.Post.Handle("/thread", api.APISessionRequired(getPostThread)).Methods(http.MethodGet)
 	api.BaseRoutes.Post.Handle("/info", api.APISessionRequired(getPostInfo)).Methods(http.MethodGet)
+	api.BaseRoutes.Post.Handle("/read_receipts", api.APISessionRequired(getPostReadReceipts)).Methods(http.MethodGet)
+	api.BaseRoutes.Post.Handle("/translate", api.APISessionRequired(translatePost)).Methods(http.MethodGet)
 	api.BaseRoutes.Post.Handle("/files/info", api.APISessionRequired(getFileInfosForPost)).Methods(http.MethodGet)
 	api.BaseRoutes.PostsForChannel.Handle("", api.APISessionRequired(getPostsForChannel)).Methods(http.MethodGet)
 	api.BaseRoutes.PostsForUser.Handle("/flagged", api.APISessionRequired(getFlaggedPostsForUser)).Methods(http.MethodGet)
@@ -1308,6 +1310,67 @@ func getPostInfo(c *Context, w http.ResponseWriter, r *http.Request) {
 	w.Write(js)
 }
 
+func getPostReadReceipts(c *Context, w http.ResponseWriter, r *http.Request) {
+	c.RequirePostId()
+	if c.Err != nil {
+		return
+	}
+
+	if !c.App.SessionHasPermissionToChannelByPost(*c.AppContext.Session(), c.Params.PostId, model.PermissionReadChannelContent) {
+		c.SetPermissionError(model.PermissionReadChannelContent)
+		return
+	}
+
+	receipts, appErr := c.App.GetReadReceiptsForPost(c.AppContext, c.Params.PostId)
+	if appErr != nil {
+		c.Err = appErr
+		return
+	}
+
+	js, err := json.Marshal(receipts)
+	if err != nil {
+		c.Err = model.NewAppError("getPostReadReceipts", "api.marshal_error", nil, "", http.StatusInternalServerError).Wrap(err)
+		return
+	}
+
+	w.Write(js)
+}
+
+func translatePost(c *Context, w http.ResponseWriter, r *http.Request) {
+	c.RequirePostId()
+	if c.Err != nil {
+		return
+	}
+
+	lang := r.URL.Query().Get("lang")
+	if !model.IsValidTranslationLanguage(lang) {
+		c.SetInvalidURLParam("lang")
+		return
+	}
+
+	post, appErr := c.App.GetPostIfAuthorized(c.AppContext, c.Params.PostId, c.AppContext.Session(), false)
+	if appErr != nil {
+		c.Err = appErr
+		return
+	}
+
+	post, appErr = c.App.TranslatePost(c.AppContext, post, lang)
+	if appErr != nil {
+		c.Err = appErr
+		return
+	}
+
+	post, appErr = c.App.SanitizePostMetadataForUser(c.AppContext, post, c.AppContext.Session().UserId)
+	if appErr != nil {
+		c.Err = appErr
+		return
+	}
+
+	if err := post.EncodeJSON(w); err != nil {
+		c.Logger.Warn("Error while writing response", mlog.Err(err))
+	}
+}
+
 func hasPermittedWranglerRole(c *Context, user *model.User, channelMember *model.ChannelMember) bool {
 	// If there are no configured PermittedWranglerRoles, skip the check
 	if len(c.App.Config().WranglerSettings.PermittedWranglerRoles) == 0 {
@@ -4335,6 +4335,131 @@ func TestPostGetInfo(t *testing.T) {
 	}
 }
 
+func TestGetPostReadReceipts(t *testing.T) {
+	th := Setup(t).InitBasic()
+	defer th.TearDown()
+	client := th.Client
+
+	post := th.CreatePost()
+
+	t.Run("disabled", func(t *testing.T) {
+		_, resp, err := client.GetPostReadReceipts(context.Background(), post.Id)
+		require.Error(t, err)
+		CheckNotImplementedStatus(t, resp)
+	})
+
+	th.App.UpdateConfig(func(cfg *model.Config) { *cfg.ServiceSettings.EnableReadReceipts = true })
+
+	_, _, err := th.Client.ViewChannel(context.Background(), th.BasicUser.Id, &model.ChannelView{ChannelId: th.BasicChannel.Id})
+	require.NoError(t, err)
+
+	th.LoginBasic2()
+	_, _, err = th.Client.ViewChannel(context.Background(), th.BasicUser2.Id, &model.ChannelView{ChannelId: th.BasicChannel.Id})
+	require.NoError(t, err)
+	th.LoginBasic()
+
+	t.Run("viewers exclude the author", func(t *testing.T) {
+		receipts, _, err := client.GetPostReadReceipts(context.Background(), post.Id)
+		require.NoError(t, err)
+		require.Len(t, receipts, 1)
+		assert.Equal(t, th.BasicUser2.Id, receipts[0].UserId)
+		assert.Equal(t, post.Id, receipts[0].PostId)
+		assert.GreaterOrEqual(t, receipts[0].LastViewedAt, post.CreateAt)
+	})
+
+	t.Run("users can opt out", func(t *testing.T) {
+		appErr := th.App.UpdatePreferences(th.Context, th.BasicUser2.Id, model.Preferences{{
+			UserId:   th.BasicUser2.Id,
+			Category: model.PreferenceCategoryAdvancedSettings,
+			Name:     model.PreferenceNameSendReadReceipts,
+			Value:    "false",
+		}})
+		require.Nil(t, appErr)
+		defer func() {
+			appErr := th.App.DeletePreferences(th.Context, th.BasicUser2.Id, model.Preferences{{
+				UserId:   th.BasicUser2.Id,
+				Category: model.PreferenceCategoryAdvancedSettings,
+				Name:     model.PreferenceNameSendReadReceipts,
+			}})
+			require.Nil(t, appErr)
+		}()
+
+		receipts, _, err := client.GetPostReadReceipts(context.Background(), post.Id)
+		require.NoError(t, err)
+		assert.Empty(t, receipts)
+	})
+
+	t.Run("channel too large", func(t *testing.T) {
+		th.App.UpdateConfig(func(cfg *model.Config) { *cfg.ServiceSettings.ReadReceiptsMaxChannelMembers = 1 })
+		defer th.App.UpdateConfig(func(cfg *model.Config) {
+			*cfg.ServiceSettings.ReadReceiptsMaxChannelMembers = model.ServiceSettingsDefaultReadReceiptsMaxChannelMembers
+		})
+
+		_, resp, err := client.GetPostReadReceipts(context.Background(), post.Id)
+		require.Error(t, err)
+		CheckBadRequestStatus(t, resp)
+	})
+
+	t.Run("no access to the channel", func(t *testing.T) {
+		privatePost := th.CreatePostWithClient(th.SystemAdminClient, th.CreateChannelWithClient(th.SystemAdminClient, model.ChannelTypePrivate))
+
+		_, resp, err := client.GetPostReadReceipts(context.Background(), privatePost.Id)
+		require.Error(t, err)
+		CheckForbiddenStatus(t, resp)
+	})
+}
+
+func TestTranslatePost(t *testing.T) {
+	th := Setup(t).InitBasic()
+	defer th.TearDown()
+	client := th.Client
+
+	post := th.CreatePost()
+
+	t.Run("disabled", func(t *testing.T) {
+		_, resp, err := client.TranslatePost(context.Background(), post.Id, "de")
+		require.Error(t, err)
+		CheckNotImplementedStatus(t, resp)
+	})
+
+	th.App.UpdateConfig(func(cfg *model.Config) {
+		*cfg.TranslationSettings.Enable = true
+		*cfg.TranslationSettings.Provider = model.TranslationProviderLocal
+	})
+
+	t.Run("translation is included in the metadata", func(t *testing.T) {
+		translated, _, err := client.TranslatePost(context.Background(), post.Id, "de")
+		require.NoError(t, err)
+		assert.Equal(t, post.Message, translated.Message)
+		require.NotNil(t, translated.Metadata)
+		require.Contains(t, translated.Metadata.Translations, "de")
+		assert.Equal(t, "[de] "+post.Message, translated.Metadata.Translations["de"].Text)
+	})
+
+	t.Run("edited posts are translated again", func(t *testing.T) {
+		patched, _, err := client.PatchPost(context.Background(), post.Id, &model.PostPatch{Message: model.NewPointer("edited message")})
+		require.NoError(t, err)
+
+		translated, _, err := client.TranslatePost(context.Background(), patched.Id, "de")
+		require.NoError(t, err)
+		assert.Equal(t, "[de] edited message", translated.Metadata.Translations["de"].Text)
+	})
+
+	t.Run("invalid language", func(t *testing.T) {
+		_, resp, err := client.TranslatePost(context.Background(), post.Id, "not a language")
+		require.Error(t, err)
+		CheckBadRequestStatus(t, resp)
+	})
+
+	t.Run("no access to the channel", func(t *testing.T) {
+		privatePost := th.CreatePostWithClient(th.SystemAdminClient, th.CreateChannelWithClient(th.SystemAdminClient, model.ChannelTypePrivate))
+
+		_, resp, err := client.TranslatePost(context.Background(), privatePost.Id, "de")
+		require.Error(t, err)
+		CheckForbiddenStatus(t, resp)
+	})
+}
+
 func TestAcknowledgePost(t *testing.T) {
 	th := Setup(t).InitBasic()
 	defer th.TearDown()
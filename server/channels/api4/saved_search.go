@@ -0,0 +1,210 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package api4
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+	"github.com/mattermost/mattermost/server/v8/channels/audit"
+)
+
+func (api *API) InitSavedSearch() {
+	api.BaseRoutes.Posts.Handle("/saved_searches", api.APISessionRequired(createSavedSearch)).Methods(http.MethodPost)
+	api.BaseRoutes.Posts.Handle("/saved_searches/teams/{team_id:[A-Za-z0-9]+}", api.APISessionRequired(getTeamSavedSearches)).Methods(http.MethodGet)
+	api.BaseRoutes.Posts.Handle("/saved_searches/{saved_search_id:[A-Za-z0-9]+}", api.APISessionRequired(getSavedSearch)).Methods(http.MethodGet)
+	api.BaseRoutes.Posts.Handle("/saved_searches/{saved_search_id:[A-Za-z0-9]+}", api.APISessionRequired(updateSavedSearch)).Methods(http.MethodPut)
+	api.BaseRoutes.Posts.Handle("/saved_searches/{saved_search_id:[A-Za-z0-9]+}", api.APISessionRequired(deleteSavedSearch)).Methods(http.MethodDelete)
+	api.BaseRoutes.Posts.Handle("/saved_searches/{saved_search_id:[A-Za-z0-9]+}/results", api.APISessionRequired(runSavedSearch)).Methods(http.MethodGet)
+}
+
+func createSavedSearch(c *Context, w http.ResponseWriter, r *http.Request) {
+	var savedSearch model.SavedSearch
+	if jsonErr := json.NewDecoder(r.Body).Decode(&savedSearch); jsonErr != nil {
+		c.SetInvalidParamWithErr("saved_search", jsonErr)
+		return
+	}
+
+	savedSearch.Id = ""
+	savedSearch.UserId = c.AppContext.Session().UserId
+
+	if !model.IsValidId(savedSearch.TeamId) {
+		c.SetInvalidParam("team_id")
+		return
+	}
+
+	auditRec := c.MakeAuditRecord("createSavedSearch", audit.Fail)
+	defer c.LogAuditRec(auditRec)
+	audit.AddEventParameterAuditable(auditRec, "savedSearch", &savedSearch)
+
+	if !c.App.SessionHasPermissionToTeam(*c.AppContext.Session(), savedSearch.TeamId, model.PermissionViewTeam) {
+		c.SetPermissionError(model.PermissionViewTeam)
+		return
+	}
+
+	createdSavedSearch, appErr := c.App.SaveSavedSearch(c.AppContext, &savedSearch)
+	if appErr != nil {
+		c.Err = appErr
+		return
+	}
+
+	auditRec.Success()
+	auditRec.AddEventResultState(createdSavedSearch)
+	auditRec.AddEventObjectType("savedSearch")
+
+	w.WriteHeader(http.StatusCreated)
+	if err := json.NewEncoder(w).Encode(createdSavedSearch); err != nil {
+		c.Logger.Warn("Error while writing response", mlog.Err(err))
+	}
+}
+
+func getTeamSavedSearches(c *Context, w http.ResponseWriter, r *http.Request) {
+	c.RequireTeamId()
+	if c.Err != nil {
+		return
+	}
+
+	if !c.App.SessionHasPermissionToTeam(*c.AppContext.Session(), c.Params.TeamId, model.PermissionViewTeam) {
+		c.SetPermissionError(model.PermissionViewTeam)
+		return
+	}
+
+	savedSearches, appErr := c.App.GetUserTeamSavedSearches(c.AppContext, c.AppContext.Session().UserId, c.Params.TeamId)
+	if appErr != nil {
+		c.Err = appErr
+		return
+	}
+
+	if err := json.NewEncoder(w).Encode(savedSearches); err != nil {
+		c.Logger.Warn("Error while writing response", mlog.Err(err))
+	}
+}
+
+func getSavedSearch(c *Context, w http.ResponseWriter, r *http.Request) {
+	c.RequireSavedSearchId()
+	if c.Err != nil {
+		return
+	}
+
+	savedSearch, appErr := c.App.GetSavedSearch(c.AppContext, c.AppContext.Session().UserId, c.Params.SavedSearchId)
+	if appErr != nil {
+		c.Err = appErr
+		return
+	}
+
+	if err := json.NewEncoder(w).Encode(savedSearch); err != nil {
+		c.Logger.Warn("Error while writing response", mlog.Err(err))
+	}
+}
+
+func updateSavedSearch(c *Context, w http.ResponseWriter, r *http.Request) {
+	c.RequireSavedSearchId()
+	if c.Err != nil {
+		return
+	}
+
+	var savedSearch model.SavedSearch
+	if jsonErr := json.NewDecoder(r.Body).Decode(&savedSearch); jsonErr != nil {
+		c.SetInvalidParamWithErr("saved_search", jsonErr)
+		return
+	}
+
+	if savedSearch.Id != c.Params.SavedSearchId {
+		c.SetInvalidParam("id")
+		return
+	}
+
+	auditRec := c.MakeAuditRecord("updateSavedSearch", audit.Fail)
+	defer c.LogAuditRec(auditRec)
+	audit.AddEventParameterAuditable(auditRec, "savedSearch", &savedSearch)
+
+	userID := c.AppContext.Session().UserId
+	existingSavedSearch, appErr := c.App.GetSavedSearch(c.AppContext, userID, c.Params.SavedSearchId)
+	if appErr != nil {
+		c.Err = appErr
+		return
+	}
+	auditRec.AddEventPriorState(existingSavedSearch)
+
+	updatedSavedSearch, appErr := c.App.UpdateSavedSearch(c.AppContext, userID, &savedSearch)
+	if appErr != nil {
+		c.Err = appErr
+		return
+	}
+
+	auditRec.Success()
+	auditRec.AddEventResultState(updatedSavedSearch)
+	auditRec.AddEventObjectType("savedSearch")
+
+	if err := json.NewEncoder(w).Encode(updatedSavedSearch); err != nil {
+		c.Logger.Warn("Error while writing response", mlog.Err(err))
+	}
+}
+
+func deleteSavedSearch(c *Context, w http.ResponseWriter, r *http.Request) {
+	c.RequireSavedSearchId()
+	if c.Err != nil {
+		return
+	}
+
+	auditRec := c.MakeAuditRecord("deleteSavedSearch", audit.Fail)
+	defer c.LogAuditRec(auditRec)
+	audit.AddEventParameter(auditRec, "savedSearchId", c.Params.SavedSearchId)
+
+	deletedSavedSearch, appErr := c.App.DeleteSavedSearch(c.AppContext, c.AppContext.Session().UserId, c.Params.SavedSearchId)
+	if appErr != nil {
+		c.Err = appErr
+		return
+	}
+
+	auditRec.Success()
+	auditRec.AddEventPriorState(deletedSavedSearch)
+	auditRec.AddEventObjectType("savedSearch")
+
+	if err := json.NewEncoder(w).Encode(deletedSavedSearch); err != nil {
+		c.Logger.Warn("Error while writing response", mlog.Err(err))
+	}
+}
+
+func runSavedSearch(c *Context, w http.ResponseWriter, r *http.Request) {
+	c.RequireSavedSearchId()
+	if c.Err != nil {
+		return
+	}
+
+	userID := c.AppContext.Session().UserId
+	savedSearch, appErr := c.App.GetSavedSearch(c.AppContext, userID, c.Params.SavedSearchId)
+	if appErr != nil {
+		c.Err = appErr
+		return
+	}
+
+	// The user may have left the team since the search was saved.
+	if !c.App.SessionHasPermissionToTeam(*c.AppContext.Session(), savedSearch.TeamId, model.PermissionViewTeam) {
+		c.SetPermissionError(model.PermissionViewTeam)
+		return
+	}
+
+	results, appErr := c.App.RunSavedSearch(c.AppContext, savedSearch, c.Params.Page, c.Params.PerPage)
+	if appErr != nil {
+		c.Err = appErr
+		return
+	}
+
+	clientPostList := c.App.PreparePostListForClient(c.AppContext, results.PostList)
+	clientPostList, appErr = c.App.SanitizePostListMetadataForUser(c.AppContext, clientPostList, userID)
+	if appErr != nil {
+		c.Err = appErr
+		return
+	}
+
+	results = model.MakePostSearchResults(clientPostList, results.Matches)
+
+	w.Header().Set("Cache-Control", "no-cache, no-store, must-revalidate")
+	if err := results.EncodeJSON(w); err != nil {
+		c.Logger.Warn("Error while writing response", mlog.Err(err))
+	}
+}
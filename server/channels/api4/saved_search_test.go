@@ -0,0 +1,210 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package api4
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost/server/public/model"
+)
+
+func TestSavedSearchCRUD(t *testing.T) {
+	th := Setup(t).InitBasic()
+	defer th.TearDown()
+
+	client := th.Client
+
+	savedSearch := &model.SavedSearch{
+		TeamId: th.BasicTeam.Id,
+		Name:   "Production errors",
+		Terms:  "in:" + th.BasicChannel.Name + " error",
+	}
+
+	created, resp, err := client.CreateSavedSearch(context.Background(), savedSearch)
+	require.NoError(t, err)
+	CheckCreatedStatus(t, resp)
+	assert.NotEmpty(t, created.Id)
+	assert.Equal(t, th.BasicUser.Id, created.UserId)
+	assert.Equal(t, model.SavedSearchAlertNotification, created.AlertType)
+
+	stored, err := th.App.Srv().Store().SavedSearch().Get(created.Id)
+	require.NoError(t, err)
+	assert.Equal(t, model.StringArray{th.BasicChannel.Id}, stored.InChannelIds)
+
+	t.Run("get saved searches", func(t *testing.T) {
+		savedSearches, _, err := client.GetSavedSearches(context.Background(), th.BasicTeam.Id)
+		require.NoError(t, err)
+		require.Len(t, savedSearches, 1)
+		assert.Equal(t, created.Id, savedSearches[0].Id)
+
+		fetched, _, err := client.GetSavedSearch(context.Background(), created.Id)
+		require.NoError(t, err)
+		assert.Equal(t, created, fetched)
+	})
+
+	t.Run("invalid saved search", func(t *testing.T) {
+		_, resp, err := client.CreateSavedSearch(context.Background(), &model.SavedSearch{TeamId: th.BasicTeam.Id, Terms: "error"})
+		require.Error(t, err)
+		CheckBadRequestStatus(t, resp)
+
+		_, resp, err = client.CreateSavedSearch(context.Background(), &model.SavedSearch{TeamId: th.BasicTeam.Id, Name: "Outages", Terms: "outages semantic:true", Subscribed: true})
+		require.Error(t, err)
+		CheckBadRequestStatus(t, resp)
+	})
+
+	t.Run("no permission to view the team", func(t *testing.T) {
+		team := th.CreateTeamWithClient(th.SystemAdminClient)
+
+		_, resp, err := client.CreateSavedSearch(context.Background(), &model.SavedSearch{TeamId: team.Id, Name: "Other team", Terms: "error"})
+		require.Error(t, err)
+		CheckForbiddenStatus(t, resp)
+	})
+
+	t.Run("update saved search", func(t *testing.T) {
+		update := *created
+		update.Name = "Staging errors"
+		update.UserId = th.BasicUser2.Id
+		update.Subscribed = true
+
+		updated, _, err := client.UpdateSavedSearch(context.Background(), &update)
+		require.NoError(t, err)
+		assert.Equal(t, "Staging errors", updated.Name)
+		assert.Equal(t, th.BasicUser.Id, updated.UserId)
+		assert.True(t, updated.Subscribed)
+	})
+
+	t.Run("run saved search", func(t *testing.T) {
+		post := th.CreateMessagePostWithClient(client, th.BasicChannel, "an error happened")
+		th.CreateMessagePostWithClient(client, th.BasicChannel, "all good")
+
+		results, _, err := client.RunSavedSearch(context.Background(), created.Id, 0, 60)
+		require.NoError(t, err)
+		require.Len(t, results.Order, 1)
+		assert.Equal(t, post.Id, results.Order[0])
+	})
+
+	t.Run("other users can't see the saved search", func(t *testing.T) {
+		th.LoginBasic2()
+		defer th.LoginBasic()
+
+		_, resp, err := th.Client.GetSavedSearch(context.Background(), created.Id)
+		require.Error(t, err)
+		CheckNotFoundStatus(t, resp)
+
+		_, resp, err = th.Client.DeleteSavedSearch(context.Background(), created.Id)
+		require.Error(t, err)
+		CheckNotFoundStatus(t, resp)
+	})
+
+	t.Run("delete saved search", func(t *testing.T) {
+		deleted, _, err := client.DeleteSavedSearch(context.Background(), created.Id)
+		require.NoError(t, err)
+		assert.Equal(t, created.Id, deleted.Id)
+
+		_, resp, err := client.GetSavedSearch(context.Background(), created.Id)
+		require.Error(t, err)
+		CheckNotFoundStatus(t, resp)
+	})
+
+	t.Run("feature disabled", func(t *testing.T) {
+		th.App.UpdateConfig(func(cfg *model.Config) { *cfg.ServiceSettings.EnableSavedSearches = false })
+		defer th.App.UpdateConfig(func(cfg *model.Config) { *cfg.ServiceSettings.EnableSavedSearches = true })
+
+		_, resp, err := client.CreateSavedSearch(context.Background(), savedSearch)
+		require.Error(t, err)
+		CheckNotImplementedStatus(t, resp)
+	})
+}
+
+func TestSavedSearchAlerts(t *testing.T) {
+	th := Setup(t).InitBasic()
+	defer th.TearDown()
+
+	subscribe := func(alertType string) *model.SavedSearch {
+		savedSearch, appErr := th.App.SaveSavedSearch(th.Context, &model.SavedSearch{
+			UserId:     th.BasicUser.Id,
+			TeamId:     th.BasicTeam.Id,
+			Name:       "Production errors " + alertType,
+			Terms:      "from:" + th.BasicUser2.Username + " error",
+			Subscribed: true,
+			AlertType:  alertType,
+		})
+		require.Nil(t, appErr)
+		return savedSearch
+	}
+
+	postAsBasicUser2 := func(message string) *model.Post {
+		post, appErr := th.App.CreatePost(th.Context, &model.Post{
+			UserId:    th.BasicUser2.Id,
+			ChannelId: th.BasicChannel.Id,
+			Message:   message,
+		}, th.BasicChannel, false, true)
+		require.Nil(t, appErr)
+		return post
+	}
+
+	t.Run("notification", func(t *testing.T) {
+		savedSearch := subscribe(model.SavedSearchAlertNotification)
+		defer th.App.DeleteSavedSearch(th.Context, th.BasicUser.Id, savedSearch.Id)
+
+		webSocketClient, err := th.CreateWebSocketClient()
+		require.NoError(t, err)
+		webSocketClient.Listen()
+		defer webSocketClient.Close()
+
+		postAsBasicUser2("nothing to see here")
+		post := postAsBasicUser2("an error happened")
+
+		timeout := time.After(5 * time.Second)
+		for {
+			select {
+			case event := <-webSocketClient.EventChannel:
+				if event.EventType() != model.WebsocketEventSavedSearchMatched {
+					continue
+				}
+				assert.Equal(t, savedSearch.Id, event.GetData()["saved_search_id"])
+				assert.Equal(t, post.Id, event.GetData()["post_id"])
+				return
+			case <-timeout:
+				require.Fail(t, "timed out waiting for the saved search alert")
+			}
+		}
+	})
+
+	t.Run("direct message", func(t *testing.T) {
+		savedSearch := subscribe(model.SavedSearchAlertDirectMessage)
+		defer th.App.DeleteSavedSearch(th.Context, th.BasicUser.Id, savedSearch.Id)
+
+		systemBot, appErr := th.App.GetSystemBot(th.Context)
+		require.Nil(t, appErr)
+		dm, appErr := th.App.GetOrCreateDirectChannel(th.Context, th.BasicUser.Id, systemBot.UserId)
+		require.Nil(t, appErr)
+
+		post := postAsBasicUser2("another error happened")
+		th.CreateMessagePostWithClient(th.Client, th.BasicChannel, "my own error")
+
+		var alerts *model.PostList
+		require.Eventually(t, func() bool {
+			alerts, appErr = th.App.GetPostsPage(model.GetPostsOptions{ChannelId: dm.Id, PerPage: 10})
+			require.Nil(t, appErr)
+			return len(alerts.Order) > 0
+		}, 5*time.Second, 100*time.Millisecond)
+
+		// Give a wrongly sent alert for the user's own post time to arrive.
+		time.Sleep(500 * time.Millisecond)
+		alerts, appErr = th.App.GetPostsPage(model.GetPostsOptions{ChannelId: dm.Id, PerPage: 10})
+		require.Nil(t, appErr)
+		require.Len(t, alerts.Order, 1)
+
+		alert := alerts.Posts[alerts.Order[0]]
+		assert.Equal(t, savedSearch.Id, alert.GetProp(model.PostPropsSavedSearchId))
+		assert.Contains(t, alert.Message, savedSearch.Name)
+		assert.Contains(t, alert.Message, "/pl/"+post.Id)
+	})
+}
@@ -0,0 +1,199 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package api4
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+	"github.com/mattermost/mattermost/server/v8/channels/app"
+	"github.com/mattermost/mattermost/server/v8/channels/audit"
+)
+
+func (api *API) InitScheduledPost() {
+	api.BaseRoutes.Posts.Handle("/schedule", api.APISessionRequired(createSchedulePost)).Methods(http.MethodPost)
+	api.BaseRoutes.Posts.Handle("/schedule/{scheduled_post_id:[A-Za-z0-9]+}", api.APISessionRequired(updateScheduledPost)).Methods(http.MethodPut)
+	api.BaseRoutes.Posts.Handle("/schedule/{scheduled_post_id:[A-Za-z0-9]+}", api.APISessionRequired(deleteScheduledPost)).Methods(http.MethodDelete)
+	api.BaseRoutes.Posts.Handle("/scheduled/teams/{team_id:[A-Za-z0-9]+}", api.APISessionRequired(getTeamScheduledPosts)).Methods(http.MethodGet)
+}
+
+func scheduledPostChecks(where string, c *Context, scheduledPost *model.ScheduledPost) {
+	if !*c.App.Config().ServiceSettings.ScheduledPosts {
+		c.Err = model.NewAppError(where, "api.scheduled_post.disabled.app_error", nil, "", http.StatusNotImplemented)
+		return
+	}
+
+	hasPermission := false
+	if c.App.SessionHasPermissionToChannel(c.AppContext, *c.AppContext.Session(), scheduledPost.ChannelId, model.PermissionCreatePost) {
+		hasPermission = true
+	} else if channel, err := c.App.GetChannel(c.AppContext, scheduledPost.ChannelId); err == nil {
+		// Temporary permission check method until advanced permissions, please do not copy
+		if channel.Type == model.ChannelTypeOpen && c.App.SessionHasPermissionToTeam(*c.AppContext.Session(), channel.TeamId, model.PermissionCreatePostPublic) {
+			hasPermission = true
+		}
+	}
+
+	if !hasPermission {
+		c.SetPermissionError(model.PermissionCreatePost)
+		return
+	}
+
+	if *c.App.Config().ServiceSettings.ExperimentalEnableHardenedMode {
+		if reservedProps := scheduledPost.ToPost().ContainsIntegrationsReservedProps(); len(reservedProps) > 0 && !c.AppContext.Session().IsIntegration() {
+			c.SetInvalidParamWithDetails("props", fmt.Sprintf("Cannot use props reserved for integrations. props: %v", reservedProps))
+			return
+		}
+	}
+}
+
+func createSchedulePost(c *Context, w http.ResponseWriter, r *http.Request) {
+	var scheduledPost model.ScheduledPost
+	if jsonErr := json.NewDecoder(r.Body).Decode(&scheduledPost); jsonErr != nil {
+		c.SetInvalidParamWithErr("scheduled_post", jsonErr)
+		return
+	}
+
+	scheduledPost.SanitizeInput()
+	scheduledPost.UserId = c.AppContext.Session().UserId
+
+	auditRec := c.MakeAuditRecord("createSchedulePost", audit.Fail)
+	defer c.LogAuditRecWithLevel(auditRec, app.LevelContent)
+	audit.AddEventParameterAuditable(auditRec, "scheduledPost", &scheduledPost)
+
+	scheduledPostChecks("Api4.createSchedulePost", c, &scheduledPost)
+	if c.Err != nil {
+		return
+	}
+
+	connectionID := r.Header.Get(model.ConnectionId)
+	createdScheduledPost, appErr := c.App.SaveScheduledPost(c.AppContext, &scheduledPost, connectionID)
+	if appErr != nil {
+		c.Err = appErr
+		return
+	}
+
+	auditRec.Success()
+	auditRec.AddEventResultState(createdScheduledPost)
+	auditRec.AddEventObjectType("scheduledPost")
+
+	w.WriteHeader(http.StatusCreated)
+	if err := json.NewEncoder(w).Encode(createdScheduledPost); err != nil {
+		c.Logger.Warn("Error while writing response", mlog.Err(err))
+	}
+}
+
+func getTeamScheduledPosts(c *Context, w http.ResponseWriter, r *http.Request) {
+	c.RequireTeamId()
+	if c.Err != nil {
+		return
+	}
+
+	if !*c.App.Config().ServiceSettings.ScheduledPosts {
+		c.Err = model.NewAppError("getTeamScheduledPosts", "api.scheduled_post.disabled.app_error", nil, "", http.StatusNotImplemented)
+		return
+	}
+
+	if !c.App.SessionHasPermissionToTeam(*c.AppContext.Session(), c.Params.TeamId, model.PermissionViewTeam) {
+		c.SetPermissionError(model.PermissionViewTeam)
+		return
+	}
+
+	scheduledPosts, appErr := c.App.GetUserTeamScheduledPosts(c.AppContext, c.AppContext.Session().UserId, c.Params.TeamId)
+	if appErr != nil {
+		c.Err = appErr
+		return
+	}
+
+	if err := json.NewEncoder(w).Encode(scheduledPosts); err != nil {
+		c.Logger.Warn("Error while writing response", mlog.Err(err))
+	}
+}
+
+func updateScheduledPost(c *Context, w http.ResponseWriter, r *http.Request) {
+	c.RequireScheduledPostId()
+	if c.Err != nil {
+		return
+	}
+
+	var scheduledPost model.ScheduledPost
+	if jsonErr := json.NewDecoder(r.Body).Decode(&scheduledPost); jsonErr != nil {
+		c.SetInvalidParamWithErr("scheduled_post", jsonErr)
+		return
+	}
+
+	if scheduledPost.Id != c.Params.ScheduledPostId {
+		c.SetInvalidParam("id")
+		return
+	}
+
+	scheduledPost.SanitizeInput()
+
+	auditRec := c.MakeAuditRecord("updateScheduledPost", audit.Fail)
+	defer c.LogAuditRecWithLevel(auditRec, app.LevelContent)
+	audit.AddEventParameterAuditable(auditRec, "scheduledPost", &scheduledPost)
+
+	userID := c.AppContext.Session().UserId
+	existingScheduledPost, appErr := c.App.GetScheduledPost(c.AppContext, userID, c.Params.ScheduledPostId)
+	if appErr != nil {
+		c.Err = appErr
+		return
+	}
+	auditRec.AddEventPriorState(existingScheduledPost)
+
+	// The channel can't be changed, so the permission checks run against the stored one.
+	scheduledPost.RestoreNonUpdatableFields(existingScheduledPost)
+	scheduledPostChecks("Api4.updateScheduledPost", c, &scheduledPost)
+	if c.Err != nil {
+		return
+	}
+
+	connectionID := r.Header.Get(model.ConnectionId)
+	updatedScheduledPost, appErr := c.App.UpdateScheduledPost(c.AppContext, userID, &scheduledPost, connectionID)
+	if appErr != nil {
+		c.Err = appErr
+		return
+	}
+
+	auditRec.Success()
+	auditRec.AddEventResultState(updatedScheduledPost)
+	auditRec.AddEventObjectType("scheduledPost")
+
+	if err := json.NewEncoder(w).Encode(updatedScheduledPost); err != nil {
+		c.Logger.Warn("Error while writing response", mlog.Err(err))
+	}
+}
+
+func deleteScheduledPost(c *Context, w http.ResponseWriter, r *http.Request) {
+	c.RequireScheduledPostId()
+	if c.Err != nil {
+		return
+	}
+
+	if !*c.App.Config().ServiceSettings.ScheduledPosts {
+		c.Err = model.NewAppError("deleteScheduledPost", "api.scheduled_post.disabled.app_error", nil, "", http.StatusNotImplemented)
+		return
+	}
+
+	auditRec := c.MakeAuditRecord("deleteScheduledPost", audit.Fail)
+	defer c.LogAuditRecWithLevel(auditRec, app.LevelContent)
+	audit.AddEventParameter(auditRec, "scheduledPostId", c.Params.ScheduledPostId)
+
+	connectionID := r.Header.Get(model.ConnectionId)
+	deletedScheduledPost, appErr := c.App.DeleteScheduledPost(c.AppContext, c.AppContext.Session().UserId, c.Params.ScheduledPostId, connectionID)
+	if appErr != nil {
+		c.Err = appErr
+		return
+	}
+
+	auditRec.Success()
+	auditRec.AddEventPriorState(deletedScheduledPost)
+	auditRec.AddEventObjectType("scheduledPost")
+
+	if err := json.NewEncoder(w).Encode(deletedScheduledPost); err != nil {
+		c.Logger.Warn("Error while writing response", mlog.Err(err))
+	}
+}
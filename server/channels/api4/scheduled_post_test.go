@@ -0,0 +1,162 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package api4
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost/server/public/model"
+)
+
+func TestCreateScheduledPost(t *testing.T) {
+	th := Setup(t).InitBasic()
+	defer th.TearDown()
+
+	th.App.UpdateConfig(func(cfg *model.Config) { *cfg.ServiceSettings.ScheduledPosts = true })
+
+	client := th.Client
+
+	t.Run("create scheduled post", func(t *testing.T) {
+		scheduledPost := &model.ScheduledPost{
+			Draft: model.Draft{
+				ChannelId: th.BasicChannel.Id,
+				Message:   "scheduled post",
+			},
+			ScheduledAt: model.GetMillis() + 100000,
+		}
+
+		created, resp, err := client.CreateScheduledPost(context.Background(), scheduledPost)
+		require.NoError(t, err)
+		CheckCreatedStatus(t, resp)
+		assert.NotEmpty(t, created.Id)
+		assert.Equal(t, th.BasicUser.Id, created.UserId)
+		assert.Equal(t, scheduledPost.Message, created.Message)
+	})
+
+	t.Run("scheduled time in the past", func(t *testing.T) {
+		scheduledPost := &model.ScheduledPost{
+			Draft: model.Draft{
+				ChannelId: th.BasicChannel.Id,
+				Message:   "scheduled post",
+			},
+			ScheduledAt: model.GetMillis() - 100000,
+		}
+
+		_, resp, err := client.CreateScheduledPost(context.Background(), scheduledPost)
+		require.Error(t, err)
+		CheckBadRequestStatus(t, resp)
+	})
+
+	t.Run("no permission to post in the channel", func(t *testing.T) {
+		privateChannel := th.CreatePrivateChannel()
+		err := th.App.Srv().Store().Channel().RemoveMember(th.Context, privateChannel.Id, th.BasicUser.Id)
+		require.NoError(t, err)
+
+		scheduledPost := &model.ScheduledPost{
+			Draft: model.Draft{
+				ChannelId: privateChannel.Id,
+				Message:   "scheduled post",
+			},
+			ScheduledAt: model.GetMillis() + 100000,
+		}
+
+		_, resp, err := client.CreateScheduledPost(context.Background(), scheduledPost)
+		require.Error(t, err)
+		CheckForbiddenStatus(t, resp)
+	})
+
+	t.Run("feature disabled", func(t *testing.T) {
+		th.App.UpdateConfig(func(cfg *model.Config) { *cfg.ServiceSettings.ScheduledPosts = false })
+		defer th.App.UpdateConfig(func(cfg *model.Config) { *cfg.ServiceSettings.ScheduledPosts = true })
+
+		scheduledPost := &model.ScheduledPost{
+			Draft: model.Draft{
+				ChannelId: th.BasicChannel.Id,
+				Message:   "scheduled post",
+			},
+			ScheduledAt: model.GetMillis() + 100000,
+		}
+
+		_, resp, err := client.CreateScheduledPost(context.Background(), scheduledPost)
+		require.Error(t, err)
+		CheckNotImplementedStatus(t, resp)
+	})
+}
+
+func TestUpdateScheduledPost(t *testing.T) {
+	th := Setup(t).InitBasic()
+	defer th.TearDown()
+
+	th.App.UpdateConfig(func(cfg *model.Config) { *cfg.ServiceSettings.ScheduledPosts = true })
+
+	created, _, err := th.Client.CreateScheduledPost(context.Background(), &model.ScheduledPost{
+		Draft: model.Draft{
+			ChannelId: th.BasicChannel.Id,
+			Message:   "scheduled post",
+		},
+		ScheduledAt: model.GetMillis() + 100000,
+	})
+	require.NoError(t, err)
+
+	t.Run("update scheduled post", func(t *testing.T) {
+		created.Message = "updated message"
+		created.ScheduledAt = created.ScheduledAt + 1000
+
+		updated, resp, err := th.Client.UpdateScheduledPost(context.Background(), created)
+		require.NoError(t, err)
+		CheckOKStatus(t, resp)
+		assert.Equal(t, "updated message", updated.Message)
+		assert.Equal(t, created.ScheduledAt, updated.ScheduledAt)
+	})
+
+	t.Run("cannot update another user's scheduled post", func(t *testing.T) {
+		th.LoginBasic2()
+		defer th.LoginBasic()
+
+		_, resp, err := th.Client.UpdateScheduledPost(context.Background(), created)
+		require.Error(t, err)
+		CheckNotFoundStatus(t, resp)
+	})
+}
+
+func TestGetAndDeleteScheduledPosts(t *testing.T) {
+	th := Setup(t).InitBasic()
+	defer th.TearDown()
+
+	th.App.UpdateConfig(func(cfg *model.Config) { *cfg.ServiceSettings.ScheduledPosts = true })
+
+	created, _, err := th.Client.CreateScheduledPost(context.Background(), &model.ScheduledPost{
+		Draft: model.Draft{
+			ChannelId: th.BasicChannel.Id,
+			Message:   "scheduled post",
+		},
+		ScheduledAt: model.GetMillis() + 100000,
+	})
+	require.NoError(t, err)
+
+	scheduledPosts, resp, err := th.Client.GetUserScheduledPosts(context.Background(), th.BasicTeam.Id)
+	require.NoError(t, err)
+	CheckOKStatus(t, resp)
+	require.Len(t, scheduledPosts, 1)
+	assert.Equal(t, created.Id, scheduledPosts[0].Id)
+
+	th.LoginBasic2()
+	_, resp, err = th.Client.DeleteScheduledPost(context.Background(), created.Id)
+	require.Error(t, err)
+	CheckNotFoundStatus(t, resp)
+	th.LoginBasic()
+
+	deleted, resp, err := th.Client.DeleteScheduledPost(context.Background(), created.Id)
+	require.NoError(t, err)
+	CheckOKStatus(t, resp)
+	assert.Equal(t, created.Id, deleted.Id)
+
+	scheduledPosts, _, err = th.Client.GetUserScheduledPosts(context.Background(), th.BasicTeam.Id)
+	require.NoError(t, err)
+	assert.Empty(t, scheduledPosts)
+}
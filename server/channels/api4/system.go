@@ -44,6 +44,7 @@ func (api *API) InitSystem() {
 	api.BaseRoutes.System.Handle("/timezones", api.APISessionRequired(getSupportedTimezones)).Methods(http.MethodGet)
 
 	api.BaseRoutes.APIRoot.Handle("/audits", api.APISessionRequired(getAudits)).Methods(http.MethodGet)
+	api.BaseRoutes.APIRoot.Handle("/audits/search", api.APISessionRequired(searchAuditRecords)).Methods(http.MethodPost)
 	api.BaseRoutes.APIRoot.Handle("/email/test", api.APISessionRequired(testEmail)).Methods(http.MethodPost)
 	api.BaseRoutes.APIRoot.Handle("/site_url/test", api.APISessionRequired(testSiteURL)).Methods(http.MethodPost)
 	api.BaseRoutes.APIRoot.Handle("/file/s3_test", api.APISessionRequired(testS3)).Methods(http.MethodPost)
@@ -213,6 +214,36 @@ func getSystemPing(c *Context, w http.ResponseWriter, r *http.Request) {
 	w.Write([]byte(model.MapToJSON(s)))
 }
 
+func searchAuditRecords(c *Context, w http.ResponseWriter, r *http.Request) {
+	auditRec := c.MakeAuditRecord("searchAuditRecords", audit.Fail)
+	defer c.LogAuditRec(auditRec)
+
+	if !c.App.SessionHasPermissionTo(*c.AppContext.Session(), model.PermissionReadAudits) {
+		c.SetPermissionError(model.PermissionReadAudits)
+		return
+	}
+
+	var search model.AuditRecordSearch
+	if err := json.NewDecoder(r.Body).Decode(&search); err != nil {
+		c.SetInvalidParamWithErr("search", err)
+		return
+	}
+
+	audit.AddEventParameterAuditable(auditRec, "search", &search)
+
+	records, appErr := c.App.SearchAuditRecords(c.AppContext, &search)
+	if appErr != nil {
+		c.Err = appErr
+		return
+	}
+
+	auditRec.Success()
+
+	if err := json.NewEncoder(w).Encode(records); err != nil {
+		c.Logger.Warn("Error while writing response", mlog.Err(err))
+	}
+}
+
 func testEmail(c *Context, w http.ResponseWriter, r *http.Request) {
 	var cfg *model.Config
 	err := json.NewDecoder(r.Body).Decode(&cfg)
@@ -589,6 +620,12 @@ func testS3(c *Context, w http.ResponseWriter, r *http.Request) {
 	if *cfg.FileSettings.AmazonS3SecretAccessKey == model.FakeSetting {
 		cfg.FileSettings.AmazonS3SecretAccessKey = c.App.Config().FileSettings.AmazonS3SecretAccessKey
 	}
+	if *cfg.FileSettings.AzureAccountKey == model.FakeSetting {
+		cfg.FileSettings.AzureAccountKey = c.App.Config().FileSettings.AzureAccountKey
+	}
+	if *cfg.FileSettings.GCSServiceAccountKey == model.FakeSetting {
+		cfg.FileSettings.GCSServiceAccountKey = c.App.Config().FileSettings.GCSServiceAccountKey
+	}
 
 	appErr = c.App.TestFileStoreConnectionWithConfig(&cfg.FileSettings)
 	if appErr != nil {
@@ -125,6 +125,43 @@ func TestGetAudits(t *testing.T) {
 	CheckUnauthorizedStatus(t, resp)
 }
 
+func TestSearchAuditRecords(t *testing.T) {
+	th := Setup(t)
+	defer th.TearDown()
+	client := th.Client
+
+	objectID := model.NewId()
+	record, err := th.App.Srv().Store().AuditRecord().Save(&model.AuditRecord{
+		EventName: "updateChannel",
+		Status:    "success",
+		UserId:    th.BasicUser.Id,
+		ObjectIds: model.StringArray{objectID},
+	})
+	require.NoError(t, err)
+
+	records, _, err := th.SystemAdminClient.SearchAuditRecords(context.Background(), &model.AuditRecordSearch{ObjectId: objectID})
+	require.NoError(t, err)
+	require.Len(t, records, 1)
+	require.Equal(t, record.Id, records[0].Id)
+
+	records, _, err = th.SystemAdminClient.SearchAuditRecords(context.Background(), &model.AuditRecordSearch{ObjectId: objectID, Status: "fail"})
+	require.NoError(t, err)
+	require.Empty(t, records)
+
+	_, resp, err := th.SystemAdminClient.SearchAuditRecords(context.Background(), &model.AuditRecordSearch{Since: 2, Until: 1})
+	require.Error(t, err)
+	CheckBadRequestStatus(t, resp)
+
+	_, resp, err = client.SearchAuditRecords(context.Background(), &model.AuditRecordSearch{})
+	require.Error(t, err)
+	CheckForbiddenStatus(t, resp)
+
+	client.Logout(context.Background())
+	_, resp, err = client.SearchAuditRecords(context.Background(), &model.AuditRecordSearch{})
+	require.Error(t, err)
+	CheckUnauthorizedStatus(t, resp)
+}
+
 func TestEmailTest(t *testing.T) {
 	th := Setup(t)
 	defer th.TearDown()
@@ -61,9 +61,15 @@ func (api *API) InitUser() {
 	api.BaseRoutes.User.Handle("/mfa", api.APISessionRequiredMfa(updateUserMfa)).Methods(http.MethodPut)
 	api.BaseRoutes.User.Handle("/mfa/generate", api.APISessionRequiredMfa(generateMfaSecret)).Methods(http.MethodPost)
 
+	api.BaseRoutes.User.Handle("/webauthn", api.APISessionRequiredMfa(getWebAuthnCredentials)).Methods(http.MethodGet)
+	api.BaseRoutes.User.Handle("/webauthn", api.APISessionRequiredMfa(registerWebAuthnCredential)).Methods(http.MethodPost)
+	api.BaseRoutes.User.Handle("/webauthn/options", api.APISessionRequiredMfa(getWebAuthnCreationOptions)).Methods(http.MethodPost)
+	api.BaseRoutes.User.Handle("/webauthn/{webauthn_credential_id:[A-Za-z0-9]+}", api.APISessionRequiredMfa(deleteWebAuthnCredential)).Methods(http.MethodDelete)
+
 	api.BaseRoutes.Users.Handle("/login", api.APIHandler(login)).Methods(http.MethodPost)
 	api.BaseRoutes.Users.Handle("/login/desktop_token", api.RateLimitedHandler(api.APIHandler(loginWithDesktopToken), model.RateLimitSettings{PerSec: model.NewPointer(2), MaxBurst: model.NewPointer(1)})).Methods(http.MethodPost)
 	api.BaseRoutes.Users.Handle("/login/switch", api.APIHandler(switchAccountType)).Methods(http.MethodPost)
+	api.BaseRoutes.Users.Handle("/login/webauthn/options", api.RateLimitedHandler(api.APIHandler(getWebAuthnRequestOptions), model.RateLimitSettings{PerSec: model.NewPointer(2), MaxBurst: model.NewPointer(1)})).Methods(http.MethodPost)
 	api.BaseRoutes.Users.Handle("/login/cws", api.APIHandlerTrustRequester(loginCWS)).Methods(http.MethodPost)
 	api.BaseRoutes.Users.Handle("/logout", api.APIHandler(logout)).Methods(http.MethodPost)
 
@@ -1879,6 +1885,7 @@ func login(c *Context, w http.ResponseWriter, r *http.Request) {
 	id := props["id"]
 	loginId := props["login_id"]
 	password := props["password"]
+	// The MFA token is either a one-time password or a JSON encoded WebAuthn assertion
 	mfaToken := props["token"]
 	deviceId := props["device_id"]
 	ldapOnly := props["ldap_only"] == "true"
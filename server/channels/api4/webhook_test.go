@@ -1048,6 +1048,108 @@ func TestRegenOutgoingHookToken(t *testing.T) {
 	CheckNotImplementedStatus(t, resp)
 }
 
+func TestRegenOutgoingHookSigningSecret(t *testing.T) {
+	th := Setup(t).InitBasic()
+	defer th.TearDown()
+	client := th.Client
+
+	th.App.UpdateConfig(func(cfg *model.Config) { *cfg.ServiceSettings.EnableOutgoingWebhooks = true })
+
+	hook := &model.OutgoingWebhook{ChannelId: th.BasicChannel.Id, TeamId: th.BasicChannel.TeamId, CallbackURLs: []string{"http://nowhere.com"}}
+	rhook, _, err := th.SystemAdminClient.CreateOutgoingWebhook(context.Background(), hook)
+	require.NoError(t, err)
+	require.Len(t, rhook.SigningSecret, model.IntegrationSigningSecretLength)
+
+	_, resp, err := th.SystemAdminClient.RegenOutgoingHookSigningSecret(context.Background(), "junk")
+	require.Error(t, err)
+	CheckBadRequestStatus(t, resp)
+
+	regenHook, _, err := th.SystemAdminClient.RegenOutgoingHookSigningSecret(context.Background(), rhook.Id)
+	require.NoError(t, err)
+	require.NotEqual(t, rhook.SigningSecret, regenHook.SigningSecret, "regen didn't work properly")
+	require.Equal(t, rhook.Token, regenHook.Token)
+
+	_, resp, err = client.RegenOutgoingHookSigningSecret(context.Background(), rhook.Id)
+	require.Error(t, err)
+	CheckForbiddenStatus(t, resp)
+
+	th.App.UpdateConfig(func(cfg *model.Config) { *cfg.ServiceSettings.EnableOutgoingWebhooks = false })
+	_, resp, err = th.SystemAdminClient.RegenOutgoingHookSigningSecret(context.Background(), rhook.Id)
+	require.Error(t, err)
+	CheckNotImplementedStatus(t, resp)
+}
+
+func TestOutgoingHookDeadLetters(t *testing.T) {
+	th := Setup(t).InitBasic()
+	defer th.TearDown()
+	client := th.Client
+
+	th.App.UpdateConfig(func(cfg *model.Config) { *cfg.ServiceSettings.EnableOutgoingWebhooks = true })
+
+	hook := &model.OutgoingWebhook{ChannelId: th.BasicChannel.Id, TeamId: th.BasicChannel.TeamId, CallbackURLs: []string{"http://nowhere.com"}}
+	rhook, _, err := th.SystemAdminClient.CreateOutgoingWebhook(context.Background(), hook)
+	require.NoError(t, err)
+
+	delivery, err := th.App.Srv().Store().Webhook().SaveOutgoingDelivery(&model.OutgoingWebhookDelivery{
+		HookId:      rhook.Id,
+		TeamId:      rhook.TeamId,
+		ChannelId:   th.BasicChannel.Id,
+		PostId:      th.BasicPost.Id,
+		CallbackURL: "http://nowhere.com",
+		ContentType: "application/x-www-form-urlencoded",
+		Status:      model.OutgoingWebhookDeliveryStatusDeadLetter,
+		Attempts:    6,
+	})
+	require.NoError(t, err)
+
+	t.Run("list dead letters", func(t *testing.T) {
+		deliveries, _, err := th.SystemAdminClient.GetOutgoingWebhookDeadLetters(context.Background(), rhook.Id, 0, 10)
+		require.NoError(t, err)
+		require.Len(t, deliveries, 1)
+		require.Equal(t, delivery.Id, deliveries[0].Id)
+
+		_, resp, err := th.SystemAdminClient.GetOutgoingWebhookDeadLetters(context.Background(), "junk", 0, 10)
+		require.Error(t, err)
+		CheckBadRequestStatus(t, resp)
+
+		_, resp, err = client.GetOutgoingWebhookDeadLetters(context.Background(), rhook.Id, 0, 10)
+		require.Error(t, err)
+		CheckForbiddenStatus(t, resp)
+	})
+
+	t.Run("replay dead letter", func(t *testing.T) {
+		_, resp, err := client.ReplayOutgoingWebhookDelivery(context.Background(), rhook.Id, delivery.Id)
+		require.Error(t, err)
+		CheckForbiddenStatus(t, resp)
+
+		_, resp, err = th.SystemAdminClient.ReplayOutgoingWebhookDelivery(context.Background(), rhook.Id, model.NewId())
+		require.Error(t, err)
+		CheckNotFoundStatus(t, resp)
+
+		replayed, _, err := th.SystemAdminClient.ReplayOutgoingWebhookDelivery(context.Background(), rhook.Id, delivery.Id)
+		require.NoError(t, err)
+		require.Equal(t, model.OutgoingWebhookDeliveryStatusPending, replayed.Status)
+		require.Zero(t, replayed.Attempts)
+
+		_, resp, err = th.SystemAdminClient.ReplayOutgoingWebhookDelivery(context.Background(), rhook.Id, delivery.Id)
+		require.Error(t, err)
+		CheckBadRequestStatus(t, resp)
+
+		deliveries, _, err := th.SystemAdminClient.GetOutgoingWebhookDeadLetters(context.Background(), rhook.Id, 0, 10)
+		require.NoError(t, err)
+		require.Empty(t, deliveries)
+	})
+
+	t.Run("outgoing webhooks disabled", func(t *testing.T) {
+		th.App.UpdateConfig(func(cfg *model.Config) { *cfg.ServiceSettings.EnableOutgoingWebhooks = false })
+		defer th.App.UpdateConfig(func(cfg *model.Config) { *cfg.ServiceSettings.EnableOutgoingWebhooks = true })
+
+		_, resp, err := th.SystemAdminClient.GetOutgoingWebhookDeadLetters(context.Background(), rhook.Id, 0, 10)
+		require.Error(t, err)
+		CheckNotImplementedStatus(t, resp)
+	})
+}
+
 func TestUpdateOutgoingHook(t *testing.T) {
 	th := Setup(t).InitBasic()
 	defer th.TearDown()
@@ -54,6 +54,9 @@ type AppIface interface {
 	AddPublicKey(name string, key io.Reader) *model.AppError
 	// AddUserToChannel adds a user to a given channel.
 	AddUserToChannel(c request.CTX, user *model.User, channel *model.Channel, skipTeamMemberIntegrityCheck bool) (*model.ChannelMember, *model.AppError)
+	// BeginWebAuthnLogin starts an authentication ceremony for the user with the given
+	// login id. The resulting assertion is then sent as the MFA token when logging in.
+	BeginWebAuthnLogin(rctx request.CTX, loginID string) (*model.WebAuthnRequestOptions, *model.AppError)
 	// Caller must close the first return value
 	ExportFileReader(path string) (filestore.ReadCloseSeeker, *model.AppError)
 	// Caller must close the first return value
@@ -71,10 +74,15 @@ type AppIface interface {
 	// If includeRemovedMembers is true, then channel members who left or were removed from the channel will
 	// be included; otherwise, they will be excluded.
 	ChannelMembersToAdd(since int64, channelID *string, includeRemovedMembers bool) ([]*model.UserChannelIDPair, *model.AppError)
+	// CheckFileScanStatus returns an error if the contents of the file must not be downloaded
+	// because they are quarantined or, while scanning is enabled, not scanned yet.
+	CheckFileScanStatus(rctx request.CTX, info *model.FileInfo) *model.AppError
 	// CheckProviderAttributes returns the empty string if the patch can be applied without
 	// overriding attributes set by the user's login provider; otherwise, the name of the offending
 	// field is returned.
 	CheckProviderAttributes(c request.CTX, user *model.User, patch *model.UserPatch) string
+	// ClosePoll stops a poll from accepting further votes.
+	ClosePoll(c request.CTX, postID string) (*model.PollMetadata, *model.AppError)
 	// CommandsForTeam returns all the plugin commands for the given team.
 	CommandsForTeam(teamID string) []*model.Command
 	// ComputeLastAccessibleFileTime updates cache with CreateAt time of the last accessible file as per the cloud plan's limit.
@@ -102,11 +110,20 @@ type AppIface interface {
 	// CreateGuest creates a guest and sets several fields of the returned User struct to
 	// their zero values.
 	CreateGuest(c request.CTX, user *model.User) (*model.User, *model.AppError)
+	// CreateScimGroup provisions a custom group from a SCIM resource. Adding members to the group
+	// adds them to the teams and channels the group is linked to.
+	CreateScimGroup(c request.CTX, sg *model.ScimGroup) (*model.Group, *model.AppError)
+	// CreateScimUser provisions a user from a SCIM resource. The user authenticates through the
+	// configured auth service, identified by the SCIM externalId or, failing that, the userName.
+	CreateScimUser(c request.CTX, su *model.ScimUser) (*model.User, *model.AppError)
 	// CreateUser creates a user and sets several fields of the returned User struct to
 	// their zero values.
 	CreateUser(c request.CTX, user *model.User) (*model.User, *model.AppError)
 	// Creates and stores FileInfos for a post created before the FileInfos table existed.
 	MigrateFilenamesToFileInfos(rctx request.CTX, post *model.Post) []*model.FileInfo
+	// DeduplicateFileInfo moves the contents of a file info saved before deduplication was
+	// enabled to its blob, and returns the number of bytes saved by doing so.
+	DeduplicateFileInfo(rctx request.CTX, info *model.FileInfo) (int64, *model.AppError)
 	// DefaultChannelNames returns the list of system-wide default channel names.
 	//
 	// By default the list will be (not necessarily in this order):
@@ -120,6 +137,10 @@ type AppIface interface {
 	DefaultChannelNames(c request.CTX) []string
 	// DeleteChannelScheme deletes a channels scheme and sets its SchemeId to nil.
 	DeleteChannelScheme(c request.CTX, channel *model.Channel) (*model.Channel, *model.AppError)
+	// DeleteExpiredPosts permanently deletes, in batches, the posts that are past their expiry
+	// along with their attachments. The replies to an expired root post are deleted with it, as
+	// they can't be shown without it.
+	DeleteExpiredPosts(rctx request.CTX) error
 	// DeleteGroupConstrainedMemberships deletes team and channel memberships of users who aren't members of the allowed
 	// groups of all group-constrained teams and channels.
 	DeleteGroupConstrainedMemberships(rctx request.CTX) error
@@ -127,6 +148,11 @@ type AppIface interface {
 	DeletePersistentNotification(c request.CTX, post *model.Post) *model.AppError
 	// DeletePublicKey will delete plugin public key from the config.
 	DeletePublicKey(name string) *model.AppError
+	// DeleteQuarantinedFile permanently deletes a quarantined file and its contents.
+	DeleteQuarantinedFile(rctx request.CTX, fileID string) *model.AppError
+	// DeleteScimGroup deletes a group provisioned through SCIM, removing its members from the
+	// group-constrained teams and channels it's linked to.
+	DeleteScimGroup(c request.CTX, group *model.Group) *model.AppError
 	// DemoteUserToGuest Convert user's roles and all his membership's roles from
 	// regular user roles to guest roles.
 	DemoteUserToGuest(c request.CTX, user *model.User) *model.AppError
@@ -164,6 +190,17 @@ type AppIface interface {
 	// FilterNonGroupTeamMembers returns the subset of the given user IDs of the users who are not members of groups
 	// associated to the team excluding bots.
 	FilterNonGroupTeamMembers(userIDs []string, team *model.Team) ([]string, error)
+	// GenerateMediaPreviews generates the thumbnail, preview and mini preview of a video or audio
+	// file from a frame of the video, or from its cover art. When FFmpeg is enabled, it also
+	// probes the file, so that the files in any format it supports have their metadata. Without
+	// FFmpeg, only the cover art embedded in the common container formats is used. It returns
+	// whether the file info was updated.
+	GenerateMediaPreviews(rctx request.CTX, info *model.FileInfo) (bool, *model.AppError)
+	// GenerateMissingFilePreviews generates the thumbnail, preview and mini preview of an image
+	// whose previews were never generated, such as the HEIC photos uploaded while the image
+	// converter was disabled, or are missing from the file store. It returns whether the previews
+	// were generated.
+	GenerateMissingFilePreviews(rctx request.CTX, info *model.FileInfo) (bool, *model.AppError)
 	// GetAllLdapGroupsPage retrieves all LDAP groups under the configured base DN using the default or configured group
 	// filter.
 	GetAllLdapGroupsPage(rctx request.CTX, page int, perPage int, opts model.LdapGroupSearchOpts) ([]*model.Group, int, *model.AppError)
@@ -217,6 +254,12 @@ type AppIface interface {
 	// To get the plugins environment when the plugins are disabled, manually acquire the plugins
 	// lock instead.
 	GetPluginsEnvironment() *plugin.Environment
+	// GetPollMetadataForPost tallies the votes of a poll post. If userID is not empty, the
+	// votes of that user are included.
+	GetPollMetadataForPost(post *model.Post, userID string) (*model.PollMetadata, *model.AppError)
+	// GetPollMetadataForPostList tallies the votes of the poll posts of a list, loading the votes
+	// of all of them at once. The metadata is keyed by post id and includes the votes of userID.
+	GetPollMetadataForPostList(rctx request.CTX, list *model.PostList, userID string) (map[string]*model.PollMetadata, *model.AppError)
 	// GetPostsByIds response bool value indicates, if the post is inaccessible due to cloud plan's limit.
 	GetPostsByIds(postIDs []string) ([]*model.Post, int64, *model.AppError)
 	// GetPostsUsage returns the total posts count rounded down to the most
@@ -228,10 +271,26 @@ type AppIface interface {
 	GetProfileImagePath(user *model.User) (string, *model.AppError)
 	// GetPublicKey will return the actual public key saved in the `name` file.
 	GetPublicKey(name string) ([]byte, *model.AppError)
+	// GetQuarantinedFileInfos returns the files that were found to be infected or could not be
+	// scanned, most recent first.
+	GetQuarantinedFileInfos(page, perPage int) ([]*model.FileInfo, *model.AppError)
+	// GetReadReceiptsForPost returns the users that have seen a post, excluding its author
+	// and users that opted out of sending read receipts.
+	GetReadReceiptsForPost(c request.CTX, postID string) ([]*model.PostReadReceipt, *model.AppError)
 	// GetSanitizedConfig gets the configuration for a system admin without any secrets.
 	GetSanitizedConfig() *model.Config
 	// GetSchemeRolesForChannel Checks if a channel or its team has an override scheme for channel roles and returns the scheme roles or default channel roles.
 	GetSchemeRolesForChannel(c request.CTX, channelID string) (guestRoleName string, userRoleName string, adminRoleName string, err *model.AppError)
+	// GetScimGroup returns a group provisioned through SCIM.
+	GetScimGroup(groupID string) (*model.Group, *model.AppError)
+	// GetScimGroups returns the groups provisioned through SCIM that match the given filter,
+	// skipping the first offset ones, along with the total number of matching groups.
+	GetScimGroups(filter model.ScimFilter, offset, limit int) ([]*model.Group, int64, *model.AppError)
+	// GetScimUser returns a user that SCIM manages.
+	GetScimUser(userID string) (*model.User, *model.AppError)
+	// GetScimUsers returns the users matching the given SCIM filter, skipping the first offset
+	// ones, along with the total number of matching users.
+	GetScimUsers(filter model.ScimFilter, offset, limit int) ([]*model.User, int64, *model.AppError)
 	// GetSessionLengthInMillis returns the session length, in milliseconds,
 	// based on the type of session (Mobile, SSO, Web/LDAP).
 	GetSessionLengthInMillis(session *model.Session) int64
@@ -294,6 +353,8 @@ type AppIface interface {
 	PatchBot(rctx request.CTX, botUserId string, botPatch *model.BotPatch) (*model.Bot, *model.AppError)
 	// PatchChannelModerationsForChannel Updates a channels scheme roles based on a given ChannelModerationPatch, if the permissions match the higher scoped role the scheme is deleted.
 	PatchChannelModerationsForChannel(c request.CTX, channel *model.Channel, channelModerationsPatch []*model.ChannelModerationPatch) ([]*model.ChannelModeration, *model.AppError)
+	// PatchScimGroup applies the operations of a SCIM PATCH request to a group.
+	PatchScimGroup(c request.CTX, group *model.Group, operations []*model.ScimGroupOperation) (*model.Group, *model.AppError)
 	// Perform an HTTP POST request to an integration's action endpoint.
 	// Caller must consume and close returned http.Response as necessary.
 	// For internal requests, requests are routed directly to a plugin ServerHTTP hook
@@ -303,25 +364,48 @@ type AppIface interface {
 	// PopulateWebConnConfig checks if the connection id already exists in the hub,
 	// and if so, accordingly populates the other fields of the webconn.
 	PopulateWebConnConfig(s *model.Session, cfg *platform.WebConnConfig, seqVal string) (*platform.WebConnConfig, error)
+	// ProcessScheduledPosts sends every scheduled post that is due. Posts that can no longer be
+	// sent on behalf of their author are kept with an error code explaining why.
+	ProcessScheduledPosts(rctx request.CTX) error
 	// PromoteGuestToUser Convert user's roles and all his membership's roles from
 	// guest roles to regular user roles.
 	PromoteGuestToUser(c request.CTX, user *model.User, requestorId string) *model.AppError
+	// PurgeUnreferencedFileBlobs removes the blobs whose last file info has been permanently
+	// deleted, and returns the number of bytes freed. Each blob is tried once, the blobs failing
+	// to be removed being left for the next purge.
+	PurgeUnreferencedFileBlobs(rctx request.CTX) (int64, *model.AppError)
 	// ReattachPlugin allows the server to bind to an existing plugin instance launched elsewhere.
 	ReattachPlugin(manifest *model.Manifest, pluginReattachConfig *model.PluginReattachConfig) *model.AppError
+	// ReleaseQuarantinedFile marks a quarantined file as clean, allowing it to be downloaded.
+	ReleaseQuarantinedFile(rctx request.CTX, fileID string) (*model.FileInfo, *model.AppError)
 	// Removes a listener function by the unique ID returned when AddConfigListener was called
 	RemoveConfigListener(id string)
 	// RenameChannel is used to rename the channel Name and the DisplayName fields
 	RenameChannel(c request.CTX, channel *model.Channel, newChannelName string, newDisplayName string) (*model.Channel, *model.AppError)
 	// RenameTeam is used to rename the team Name and the DisplayName fields
 	RenameTeam(team *model.Team, newTeamName string, newDisplayName string) (*model.Team, *model.AppError)
+	// ReplayOutgoingWebhookDelivery moves a dead-lettered delivery back to the retry queue, where
+	// the next run of the delivery job picks it up.
+	ReplayOutgoingWebhookDelivery(hookID, deliveryID string) (*model.OutgoingWebhookDelivery, *model.AppError)
 	// ResolvePersistentNotification stops the persistent notifications, if a loggedInUserID(except the post owner) reacts, reply or ack on the post.
 	// Post-owner can only delete the original post to stop the notifications.
 	ResolvePersistentNotification(c request.CTX, post *model.Post, loggedInUserID string) *model.AppError
+	// RetryOutgoingWebhookDeliveries retries every queued outgoing webhook delivery that is due.
+	RetryOutgoingWebhookDeliveries(rctx request.CTX) error
 	// RevokeSessionsFromAllUsers will go through all the sessions active
 	// in the server and revoke them
 	RevokeSessionsFromAllUsers() *model.AppError
+	// RunSavedSearch searches the posts the owner of a saved search can see with its terms.
+	RunSavedSearch(rctx request.CTX, savedSearch *model.SavedSearch, page, perPage int) (*model.PostSearchResults, *model.AppError)
 	// SaveConfig replaces the active configuration, optionally notifying cluster peers.
 	SaveConfig(newCfg *model.Config, sendConfigChangeClusterMessage bool) (*model.Config, *model.Config, *model.AppError)
+	// ScanFile scans the contents of a file for viruses and records the outcome. Files the
+	// scanner fails to scan are quarantined like infected ones.
+	ScanFile(rctx request.CTX, info *model.FileInfo) *model.AppError
+	// SealFileMigrationSettings replaces the file settings of a file migration job by their sealed
+	// form, as they hold the credentials of the new backend while the job data can be read by
+	// anyone allowed to read the jobs.
+	SealFileMigrationSettings(job *model.Job) *model.AppError
 	// SearchAllChannels returns a list of channels, the total count of the results of the search (if the paginate search option is true), and an error.
 	SearchAllChannels(c request.CTX, term string, opts model.ChannelSearchOpts) (model.ChannelListWithTeamData, int64, *model.AppError)
 	// SearchAllTeams returns a team list and the total count of the results
@@ -383,6 +467,12 @@ type AppIface interface {
 	CreateZipFileAndAddFiles(fileBackend filestore.FileBackend, fileDatas []model.FileData, zipFileName, directory string) error
 	// This to be used for places we check the users password when they are already logged in
 	DoubleCheckPassword(rctx request.CTX, user *model.User, password string) *model.AppError
+	// TranscodeMediaFile creates the web-friendly rendition of a video which can't be played by
+	// all the browsers, next to its previews. It returns whether a rendition was created.
+	TranscodeMediaFile(rctx request.CTX, info *model.FileInfo) (bool, *model.AppError)
+	// TranslatePost prepares a post for the client with the machine translation of its message to
+	// lang included in its metadata. Translations are cached until the post is edited.
+	TranslatePost(c request.CTX, post *model.Post, lang string) (*model.Post, *model.AppError)
 	// UpdateBotActive marks a bot as active or inactive, along with its corresponding user.
 	UpdateBotActive(rctx request.CTX, botUserId string, active bool) (*model.Bot, *model.AppError)
 	// UpdateBotOwner changes a bot's owner to the given value.
@@ -396,6 +486,11 @@ type AppIface interface {
 	UpdateDNDStatusOfUsers()
 	// UpdateProductNotices is called periodically from a scheduled worker to fetch new notices and update the cache
 	UpdateProductNotices() *model.AppError
+	// UpdateScimGroup replaces the attributes and members of a group with those of a SCIM resource.
+	UpdateScimGroup(c request.CTX, group *model.Group, sg *model.ScimGroup) (*model.Group, *model.AppError)
+	// UpdateScimUser replaces the attributes of a user with those of a SCIM resource, deactivating
+	// or reactivating the user as needed.
+	UpdateScimUser(c request.CTX, user *model.User, su *model.ScimUser) (*model.User, *model.AppError)
 	// UpdateSharedChannelCursor updates the cursor for the specified channelID and remoteID.
 	// This can be used to manually set the point of last sync, either forward to skip older posts,
 	// or backward to re-sync history.
@@ -424,6 +519,9 @@ type AppIface interface {
 	ValidateUserPermissionsOnChannels(c request.CTX, userId string, channelIds []string) []string
 	// VerifyPlugin checks that the given signature corresponds to the given plugin and matches a trusted certificate.
 	VerifyPlugin(plugin, signature io.ReadSeeker) *model.AppError
+	// VoteOnPoll replaces the votes of a user in a poll. An empty list of options retracts
+	// the user's votes.
+	VoteOnPoll(c request.CTX, postID, userID string, optionIDs []string) (*model.PollMetadata, *model.AppError)
 	// validateMoveOrCopy performs validation on a provided post list to determine
 	// if all permissions are in place to allow the for the posts to be moved or
 	// copied.
@@ -467,6 +565,7 @@ type AppIface interface {
 	AutocompleteChannelsForTeam(c request.CTX, teamID, userID, term string) (model.ChannelList, *model.AppError)
 	AutocompleteUsersInChannel(rctx request.CTX, teamID string, channelID string, term string, options *model.UserSearchOptions) (*model.UserAutocompleteInChannel, *model.AppError)
 	AutocompleteUsersInTeam(rctx request.CTX, teamID string, term string, options *model.UserSearchOptions) (*model.UserAutocompleteInTeam, *model.AppError)
+	BeginWebAuthnRegistration(userID string) (*model.WebAuthnCreationOptions, *model.AppError)
 	BuildPostReactions(ctx request.CTX, postID string) (*[]ReactionImportData, *model.AppError)
 	BuildPushNotificationMessage(c request.CTX, contentsConfig string, post *model.Post, user *model.User, channel *model.Channel, channelName string, senderName string, explicitMention bool, channelWideMention bool, replyToThreadType string) (*model.PushNotification, *model.AppError)
 	BuildSamlMetadataObject(idpMetadata []byte) (*model.SamlMetadataResponse, *model.AppError)
@@ -510,6 +609,7 @@ type AppIface interface {
 	Compliance() einterfaces.ComplianceInterface
 	Config() *model.Config
 	ConvertGroupMessageToChannel(c request.CTX, convertedByUserId string, gmConversionRequest *model.GroupMessageConversionRequestBody) (*model.Channel, *model.AppError)
+	CopyFile(oldPath, newPath string) *model.AppError
 	CopyFileInfos(rctx request.CTX, userID string, fileIDs []string) ([]string, *model.AppError)
 	CopyWranglerPostlist(c request.CTX, wpl *model.WranglerPostList, targetChannel *model.Channel) (*model.Post, *model.AppError)
 	CountNotification(notificationType model.NotificationType, platform string)
@@ -582,10 +682,13 @@ type AppIface interface {
 	DeleteReactionForPost(c request.CTX, reaction *model.Reaction) *model.AppError
 	DeleteRemoteCluster(remoteClusterId string) (bool, *model.AppError)
 	DeleteRetentionPolicy(policyID string) *model.AppError
+	DeleteSavedSearch(rctx request.CTX, userID, savedSearchID string) (*model.SavedSearch, *model.AppError)
+	DeleteScheduledPost(rctx request.CTX, userID, scheduledPostID, connectionID string) (*model.ScheduledPost, *model.AppError)
 	DeleteScheme(schemeId string) (*model.Scheme, *model.AppError)
 	DeleteSharedChannelRemote(id string) (bool, error)
 	DeleteSidebarCategory(c request.CTX, userID, teamID, categoryId string) *model.AppError
 	DeleteToken(token *model.Token) *model.AppError
+	DeleteWebAuthnCredential(userID, credentialID string) *model.AppError
 	DisableAutoResponder(rctx request.CTX, userID string, asAdmin bool) *model.AppError
 	DisableUserAccessToken(c request.CTX, token *model.UserAccessToken) *model.AppError
 	DoAppMigrations()
@@ -617,6 +720,7 @@ type AppIface interface {
 	FilterUsersByVisible(c request.CTX, viewer *model.User, otherUsers []*model.User) ([]*model.User, *model.AppError)
 	FindTeamByName(name string) bool
 	FinishSendAdminNotifyPost(rctx request.CTX, trial bool, now int64, pluginBasedData map[string][]*model.NotifyAdminData)
+	FinishWebAuthnRegistration(rctx request.CTX, userID string, registration *model.WebAuthnRegistration) (*model.WebAuthnCredential, *model.AppError)
 	GenerateAndSaveDesktopToken(createAt int64, user *model.User) (*string, *model.AppError)
 	GenerateMfaSecret(userID string) (*model.MfaSecret, *model.AppError)
 	GeneratePresignURLForExport(name string) (*model.PresignURLResponse, *model.AppError)
@@ -753,6 +857,7 @@ type AppIface interface {
 	GetOpenGraphMetadata(requestURL string) ([]byte, error)
 	GetOrCreateDirectChannel(c request.CTX, userID, otherUserID string, channelOptions ...model.ChannelOption) (*model.Channel, *model.AppError)
 	GetOutgoingWebhook(hookID string) (*model.OutgoingWebhook, *model.AppError)
+	GetOutgoingWebhookDeadLetters(hookID string, page, perPage int) ([]*model.OutgoingWebhookDelivery, *model.AppError)
 	GetOutgoingWebhooksForChannelPageByUser(channelID string, userID string, page, perPage int) ([]*model.OutgoingWebhook, *model.AppError)
 	GetOutgoingWebhooksForTeamPage(teamID string, page, perPage int) ([]*model.OutgoingWebhook, *model.AppError)
 	GetOutgoingWebhooksForTeamPageByUser(teamID string, userID string, page, perPage int) ([]*model.OutgoingWebhook, *model.AppError)
@@ -805,6 +910,8 @@ type AppIface interface {
 	GetSamlMetadata(c request.CTX) (string, *model.AppError)
 	GetSamlMetadataFromIdp(idpMetadataURL string) (*model.SamlMetadataResponse, *model.AppError)
 	GetSanitizeOptions(asAdmin bool) map[string]bool
+	GetSavedSearch(rctx request.CTX, userID, savedSearchID string) (*model.SavedSearch, *model.AppError)
+	GetScheduledPost(rctx request.CTX, userID, scheduledPostID string) (*model.ScheduledPost, *model.AppError)
 	GetScheme(id string) (*model.Scheme, *model.AppError)
 	GetSchemeByName(name string) (*model.Scheme, *model.AppError)
 	GetSchemeRolesForTeam(teamID string) (string, string, string, *model.AppError)
@@ -868,6 +975,8 @@ type AppIface interface {
 	GetUserByUsername(username string) (*model.User, *model.AppError)
 	GetUserCountForReport(filter *model.UserReportOptions) (*int64, *model.AppError)
 	GetUserForLogin(c request.CTX, id, loginId string) (*model.User, *model.AppError)
+	GetUserTeamSavedSearches(rctx request.CTX, userID, teamID string) ([]*model.SavedSearch, *model.AppError)
+	GetUserTeamScheduledPosts(rctx request.CTX, userID, teamID string) ([]*model.ScheduledPost, *model.AppError)
 	GetUserTermsOfService(userID string) (*model.UserTermsOfService, *model.AppError)
 	GetUsers(userIDs []string) ([]*model.User, *model.AppError)
 	GetUsersByGroupChannelIds(c request.CTX, channelIDs []string, asAdmin bool) (map[string][]*model.User, *model.AppError)
@@ -899,6 +1008,7 @@ type AppIface interface {
 	GetUsersWithoutTeamPage(options *model.UserGetOptions, asAdmin bool) ([]*model.User, *model.AppError)
 	GetVerifyEmailToken(token string) (*model.Token, *model.AppError)
 	GetViewUsersRestrictions(c request.CTX, userID string) (*model.ViewUsersRestrictions, *model.AppError)
+	GetWebAuthnCredentialsForUser(userID string) ([]*model.WebAuthnCredential, *model.AppError)
 	HTTPService() httpservice.HTTPService
 	HandleCommandResponse(c request.CTX, command *model.Command, args *model.CommandArgs, response *model.CommandResponse, builtIn bool) (*model.CommandResponse, *model.AppError)
 	HandleCommandResponsePost(c request.CTX, command *model.Command, args *model.CommandArgs, response *model.CommandResponse, builtIn bool) (*model.Post, *model.AppError)
@@ -1006,7 +1116,9 @@ type AppIface interface {
 	QueryLogs(rctx request.CTX, page, perPage int, logFilter *model.LogFilter) (map[string][]string, *model.AppError)
 	ReadFile(path string) ([]byte, *model.AppError)
 	RecycleDatabaseConnection(rctx request.CTX)
+	RegenCommandSigningSecret(cmd *model.Command) (*model.Command, *model.AppError)
 	RegenCommandToken(cmd *model.Command) (*model.Command, *model.AppError)
+	RegenOutgoingWebhookSigningSecret(hook *model.OutgoingWebhook) (*model.OutgoingWebhook, *model.AppError)
 	RegenOutgoingWebhookToken(hook *model.OutgoingWebhook) (*model.OutgoingWebhook, *model.AppError)
 	RegenerateOAuthAppSecret(app *model.OAuthApp) (*model.OAuthApp, *model.AppError)
 	RegenerateTeamInviteId(teamID string) (*model.Team, *model.AppError)
@@ -1061,10 +1173,13 @@ type AppIface interface {
 	SaveComplianceReport(rctx request.CTX, job *model.Compliance) (*model.Compliance, *model.AppError)
 	SaveReactionForPost(c request.CTX, reaction *model.Reaction) (*model.Reaction, *model.AppError)
 	SaveReportChunk(format string, prefix string, count int, reportData []model.ReportableObject) *model.AppError
+	SaveSavedSearch(rctx request.CTX, savedSearch *model.SavedSearch) (*model.SavedSearch, *model.AppError)
+	SaveScheduledPost(rctx request.CTX, scheduledPost *model.ScheduledPost, connectionID string) (*model.ScheduledPost, *model.AppError)
 	SaveSharedChannelRemote(remote *model.SharedChannelRemote) (*model.SharedChannelRemote, error)
 	SaveUserTermsOfService(userID, termsOfServiceId string, accepted bool) *model.AppError
 	SchemesIterator(scope string, batchSize int) func() []*model.Scheme
 	SearchArchivedChannels(c request.CTX, teamID string, term string, userID string) (model.ChannelList, *model.AppError)
+	SearchAuditRecords(rctx request.CTX, search *model.AuditRecordSearch) ([]*model.AuditRecord, *model.AppError)
 	SearchChannels(c request.CTX, teamID string, term string) (model.ChannelList, *model.AppError)
 	SearchChannelsForUser(c request.CTX, userID, teamID, term string) (model.ChannelList, *model.AppError)
 	SearchChannelsUserNotIn(c request.CTX, teamID string, userID string, term string) (model.ChannelList, *model.AppError)
@@ -1196,6 +1311,8 @@ type AppIface interface {
 	UpdateRemoteCluster(rc *model.RemoteCluster) (*model.RemoteCluster, *model.AppError)
 	UpdateRemoteClusterTopics(remoteClusterId string, topics string) (*model.RemoteCluster, *model.AppError)
 	UpdateRole(role *model.Role) (*model.Role, *model.AppError)
+	UpdateSavedSearch(rctx request.CTX, userID string, savedSearch *model.SavedSearch) (*model.SavedSearch, *model.AppError)
+	UpdateScheduledPost(rctx request.CTX, userID string, scheduledPost *model.ScheduledPost, connectionID string) (*model.ScheduledPost, *model.AppError)
 	UpdateScheme(scheme *model.Scheme) (*model.Scheme, *model.AppError)
 	UpdateSharedChannel(sc *model.SharedChannel) (*model.SharedChannel, error)
 	UpdateSharedChannelRemoteCursor(id string, cursor model.GetPostsSinceForSyncCursor) error
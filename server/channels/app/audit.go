@@ -110,6 +110,16 @@ func (a *App) MakeAuditRecord(rctx request.CTX, event string, initialStatus stri
 	return rec
 }
 
+// configureAuditSink starts or stops writing audit records to the database as configured.
+func (s *Server) configureAuditSink(cfg *model.Config) {
+	if !*cfg.ExperimentalAuditSettings.DatabaseEnabled {
+		s.Audit.SetSink(nil, 0)
+		return
+	}
+
+	s.Audit.SetSink(newAuditRecordSink(s.Store()), *cfg.ExperimentalAuditSettings.FileMaxQueueSize)
+}
+
 func (s *Server) configureAudit(adt *audit.Audit, bAllowAdvancedLogging bool) error {
 	adt.OnQueueFull = s.onAuditTargetQueueFull
 	adt.OnError = s.onAuditError
@@ -0,0 +1,113 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package app
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/shared/request"
+	"github.com/mattermost/mattermost/server/v8/channels/audit"
+	"github.com/mattermost/mattermost/server/v8/channels/store"
+)
+
+// auditRecordSink writes audit records to the database, where they can be searched.
+type auditRecordSink struct {
+	store store.AuditRecordStore
+}
+
+func newAuditRecordSink(s store.Store) *auditRecordSink {
+	return &auditRecordSink{store: s.AuditRecord()}
+}
+
+func (s *auditRecordSink) WriteRecord(rec audit.Record) error {
+	data, err := json.Marshal(rec)
+	if err != nil {
+		return errors.Wrapf(err, "failed to encode audit record for event %s", rec.EventName)
+	}
+
+	record := &model.AuditRecord{
+		EventName:  rec.EventName,
+		Status:     rec.Status,
+		UserId:     rec.Actor.UserId,
+		Client:     rec.Actor.Client,
+		IpAddress:  rec.Actor.IpAddress,
+		ObjectType: rec.EventData.ObjectType,
+		ObjectIds:  auditRecordObjectIds(rec.EventData),
+		Data:       string(data),
+	}
+	if model.IsValidId(rec.Actor.SessionId) {
+		record.SessionId = rec.Actor.SessionId
+	}
+
+	if _, err := s.store.Save(record); err != nil {
+		return errors.Wrapf(err, "failed to save audit record for event %s", rec.EventName)
+	}
+
+	return nil
+}
+
+// auditRecordObjectIds returns the ids of the objects an audit event refers to, taken
+// from the "id" and "*_id" keys of the event parameters and the object states.
+func auditRecordObjectIds(eventData audit.EventData) model.StringArray {
+	ids := model.StringArray{}
+	seen := map[string]bool{}
+
+	var collect func(value any)
+	collect = func(value any) {
+		switch v := value.(type) {
+		case map[string]any:
+			for key, field := range v {
+				if id, ok := field.(string); ok {
+					if (key == "id" || strings.HasSuffix(key, "_id")) && model.IsValidId(id) && !seen[id] {
+						seen[id] = true
+						ids = append(ids, id)
+					}
+					continue
+				}
+				collect(field)
+			}
+		case []any:
+			for _, item := range v {
+				collect(item)
+			}
+		}
+	}
+
+	// The event data holds arbitrary values, so go through JSON to walk plain maps.
+	for _, state := range []map[string]any{eventData.Parameters, eventData.PriorState, eventData.ResultState} {
+		data, err := json.Marshal(state)
+		if err != nil {
+			continue
+		}
+		var decoded any
+		if err := json.Unmarshal(data, &decoded); err != nil {
+			continue
+		}
+		collect(decoded)
+	}
+
+	if len(ids) > model.AuditRecordMaxObjectIds {
+		ids = ids[:model.AuditRecordMaxObjectIds]
+	}
+
+	return ids
+}
+
+func (a *App) SearchAuditRecords(rctx request.CTX, search *model.AuditRecordSearch) ([]*model.AuditRecord, *model.AppError) {
+	if appErr := search.IsValid(); appErr != nil {
+		return nil, appErr
+	}
+
+	records, err := a.Srv().Store().AuditRecord().Search(search)
+	if err != nil {
+		return nil, model.NewAppError("SearchAuditRecords", "app.audit_record.search.app_error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+
+	return records, nil
+}
@@ -0,0 +1,71 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package app
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/v8/channels/audit"
+)
+
+func TestAuditRecordObjectIds(t *testing.T) {
+	channelID := model.NewId()
+	teamID := model.NewId()
+	postID := model.NewId()
+
+	ids := auditRecordObjectIds(audit.EventData{
+		Parameters: map[string]any{
+			"channel_id": channelID,
+			"name":       model.NewId(),
+			"page":       1,
+		},
+		PriorState: map[string]any{
+			"id":      channelID,
+			"team_id": teamID,
+		},
+		ResultState: map[string]any{
+			"post": map[string]any{
+				"id":      postID,
+				"user_id": "not-an-id",
+			},
+		},
+	})
+
+	assert.ElementsMatch(t, []string{channelID, teamID, postID}, ids)
+}
+
+func TestAuditRecordSink(t *testing.T) {
+	th := Setup(t)
+	defer th.TearDown()
+
+	rec := th.App.MakeAuditRecord(th.Context, "updateChannel", audit.Fail)
+	rec.Actor.UserId = th.BasicUser.Id
+	audit.AddEventParameterAuditable(rec, "channel", th.BasicChannel)
+	rec.Success()
+
+	sink := newAuditRecordSink(th.App.Srv().Store())
+	require.NoError(t, sink.WriteRecord(*rec))
+
+	records, appErr := th.App.SearchAuditRecords(th.Context, &model.AuditRecordSearch{
+		EventName: "updateChannel",
+		ObjectId:  th.BasicChannel.Id,
+	})
+	require.Nil(t, appErr)
+	require.Len(t, records, 1)
+	assert.Equal(t, th.BasicUser.Id, records[0].UserId)
+	assert.Equal(t, audit.Success, records[0].Status)
+	assert.Contains(t, records[0].ObjectIds, th.BasicTeam.Id)
+
+	var data audit.Record
+	require.NoError(t, json.Unmarshal([]byte(records[0].Data), &data))
+	assert.Equal(t, "updateChannel", data.EventName)
+
+	_, appErr = th.App.SearchAuditRecords(th.Context, &model.AuditRecordSearch{PerPage: model.AuditRecordSearchMaxPerPage + 1})
+	require.NotNil(t, appErr)
+}
@@ -212,6 +212,16 @@ func (a *App) CheckUserMfa(rctx request.CTX, user *model.User, token string) *mo
 		return model.NewAppError("CheckUserMfa", "mfa.mfa_disabled.app_error", nil, "", http.StatusNotImplemented)
 	}
 
+	// Either a one-time password or a WebAuthn assertion satisfies the second factor.
+	if model.IsWebAuthnAssertion(token) {
+		return a.checkUserWebAuthn(rctx, user, token)
+	}
+
+	// Users who only registered WebAuthn credentials have no one-time password secret.
+	if user.MfaSecret == "" {
+		return model.NewAppError("checkUserMfa", "api.user.check_user_mfa.bad_code.app_error", nil, "", http.StatusUnauthorized)
+	}
+
 	ok, err := mfa.New(a.Srv().Store().User()).ValidateToken(user.MfaSecret, token)
 	if err != nil {
 		return model.NewAppError("CheckUserMfa", "mfa.validate_token.authenticate.app_error", nil, "", http.StatusBadRequest).Wrap(err)
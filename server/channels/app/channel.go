@@ -3013,7 +3013,7 @@ func (a *App) MarkChannelsAsViewed(c request.CTX, channelIDs []string, userID st
 		}
 	}
 
-	_, err = a.Srv().Store().Channel().UpdateLastViewedAt(channelsToView, userID)
+	lastViewedAtTimes, err := a.Srv().Store().Channel().UpdateLastViewedAt(channelsToView, userID)
 	if err != nil {
 		var invErr *store.ErrInvalidInput
 		switch {
@@ -3024,6 +3024,14 @@ func (a *App) MarkChannelsAsViewed(c request.CTX, channelIDs []string, userID st
 		}
 	}
 
+	if *a.Config().ServiceSettings.EnableReadReceipts {
+		// The request context is canceled once the response is written.
+		publishCtx := c.WithContext(context.Background())
+		a.Srv().Go(func() {
+			a.publishReadReceipts(publishCtx, userID, lastViewedAtTimes)
+		})
+	}
+
 	if *a.Config().ServiceSettings.EnableChannelViewedMessages {
 		message := model.NewWebSocketEvent(model.WebsocketEventMultipleChannelsViewed, "", "", userID, nil, "")
 		message.Add("channel_times", times)
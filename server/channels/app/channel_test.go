@@ -2688,6 +2688,10 @@ func TestConvertGroupMessageToChannel(t *testing.T) {
 	mockPostStore.On("Save", mock.AnythingOfType("*request.Context"), mock.AnythingOfType("*model.Post")).Return(&model.Post{}, nil)
 	mockPostStore.On("InvalidateLastPostTimeCache", "channelidchannelidchanneli")
 
+	mockSavedSearchStore := mocks.SavedSearchStore{}
+	mockStore.On("SavedSearch").Return(&mockSavedSearchStore)
+	mockSavedSearchStore.On("GetSubscribedForChannel", mock.Anything, mock.Anything).Return([]*model.SavedSearch{}, nil)
+
 	mockSystemStore := mocks.SystemStore{}
 	mockStore.On("System").Return(&mockSystemStore)
 	mockSystemStore.On("GetByName", model.MigrationKeyAdvancedPermissionsPhase2).Return(nil, nil)
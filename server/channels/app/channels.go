@@ -15,10 +15,12 @@ import (
 	"github.com/mattermost/mattermost/server/public/shared/mlog"
 	"github.com/mattermost/mattermost/server/public/shared/request"
 	"github.com/mattermost/mattermost/server/v8/channels/app/imaging"
+	"github.com/mattermost/mattermost/server/v8/channels/app/media"
 	"github.com/mattermost/mattermost/server/v8/config"
 	"github.com/mattermost/mattermost/server/v8/einterfaces"
 	"github.com/mattermost/mattermost/server/v8/platform/services/imageproxy"
-	"github.com/mattermost/mattermost/server/v8/platform/shared/filestore"
+	"github.com/mattermost/mattermost/server/v8/platform/services/translation"
+	"github.com/mattermost/mattermost/server/v8/platform/services/virusscan"
 )
 
 type configService interface {
@@ -31,10 +33,8 @@ type configService interface {
 
 // Channels contains all channels related state.
 type Channels struct {
-	srv             *Server
-	cfgSvc          configService
-	filestore       filestore.FileBackend
-	exportFilestore filestore.FileBackend
+	srv    *Server
+	cfgSvc configService
 
 	postActionCookieSecret []byte
 
@@ -45,7 +45,9 @@ type Channels struct {
 	pluginConfigListenerID        string
 	pluginClusterLeaderListenerID string
 
-	imageProxy *imageproxy.ImageProxy
+	imageProxy   *imageproxy.ImageProxy
+	translator   *translation.Translator
+	virusScanner *virusscan.Service
 
 	// cached counts that are used during notice condition validation
 	cachedPostCount   int64
@@ -68,8 +70,10 @@ type Channels struct {
 	uploadLockMapMut sync.Mutex
 	uploadLockMap    map[string]bool
 
-	imgDecoder *imaging.Decoder
-	imgEncoder *imaging.Encoder
+	imgDecoder   *imaging.Decoder
+	imgEncoder   *imaging.Encoder
+	imgConverter *imaging.Converter
+	ffmpeg       *media.FFmpeg
 
 	dndTaskMut sync.Mutex
 	dndTask    *model.ScheduledTask
@@ -80,12 +84,12 @@ type Channels struct {
 
 func NewChannels(s *Server) (*Channels, error) {
 	ch := &Channels{
-		srv:             s,
-		imageProxy:      imageproxy.MakeImageProxy(s.platform, s.httpService, s.Log()),
-		uploadLockMap:   map[string]bool{},
-		filestore:       s.FileBackend(),
-		exportFilestore: s.ExportFileBackend(),
-		cfgSvc:          s.Platform(),
+		srv:           s,
+		imageProxy:    imageproxy.MakeImageProxy(s.platform, s.httpService, s.Log()),
+		translator:    translation.MakeTranslator(s.platform, s.httpService, s.Log()),
+		virusScanner:  virusscan.MakeService(s.platform, s.Log()),
+		uploadLockMap: map[string]bool{},
+		cfgSvc:        s.Platform(),
 	}
 
 	// We are passing a partially filled Channels struct so that the enterprise
@@ -110,6 +114,10 @@ func NewChannels(s *Server) (*Channels, error) {
 	if notificationInterface != nil {
 		ch.Notification = notificationInterface(New(ServerConnector(ch)))
 	}
+	// Plugins implementing the TranslateText hook are used when they are selected as the
+	// translation provider.
+	ch.translator.SetPluginProvider(&pluginTranslationProvider{ch: ch})
+
 	if samlInterface != nil {
 		ch.Saml = samlInterface(New(ServerConnector(ch)))
 		if err := ch.Saml.ConfigureSP(request.EmptyContext(s.Log())); err != nil {
@@ -123,6 +131,17 @@ func NewChannels(s *Server) (*Channels, error) {
 		})
 	}
 
+	ch.imgConverter = imaging.NewConverter(imageConverterOptions(ch.cfgSvc.Config()))
+	ch.AddConfigListener(func(_, cfg *model.Config) {
+		ch.imgConverter.SetOptions(imageConverterOptions(cfg))
+	})
+	ch.imageProxy.SetImageConverter(ch.imgConverter)
+
+	ch.ffmpeg = media.NewFFmpeg(ffmpegOptions(ch.cfgSvc.Config()))
+	ch.AddConfigListener(func(_, cfg *model.Config) {
+		ch.ffmpeg.SetOptions(ffmpegOptions(cfg))
+	})
+
 	var imgErr error
 	decoderConcurrency := int(*ch.cfgSvc.Config().FileSettings.MaxImageDecoderConcurrency)
 	if decoderConcurrency == -1 {
@@ -130,12 +149,14 @@ func NewChannels(s *Server) (*Channels, error) {
 	}
 	ch.imgDecoder, imgErr = imaging.NewDecoder(imaging.DecoderOptions{
 		ConcurrencyLevel: decoderConcurrency,
+		Converter:        ch.imgConverter,
 	})
 	if imgErr != nil {
 		return nil, errors.Wrap(imgErr, "failed to create image decoder")
 	}
 	ch.imgEncoder, imgErr = imaging.NewEncoder(imaging.EncoderOptions{
 		ConcurrencyLevel: runtime.NumCPU(),
+		Converter:        ch.imgConverter,
 	})
 	if imgErr != nil {
 		return nil, errors.Wrap(imgErr, "failed to create image encoder")
@@ -505,10 +505,11 @@ func (a *App) DoCommandRequest(rctx request.CTX, cmd *model.Command, p url.Value
 	// Prepare the request
 	var req *http.Request
 	var err error
+	payload := p.Encode()
 	if cmd.Method == model.CommandMethodGet {
 		req, err = http.NewRequestWithContext(ctx, http.MethodGet, cmd.URL, nil)
 	} else {
-		req, err = http.NewRequestWithContext(ctx, http.MethodPost, cmd.URL, strings.NewReader(p.Encode()))
+		req, err = http.NewRequestWithContext(ctx, http.MethodPost, cmd.URL, strings.NewReader(payload))
 	}
 
 	if err != nil {
@@ -519,7 +520,10 @@ func (a *App) DoCommandRequest(rctx request.CTX, cmd *model.Command, p url.Value
 		if req.URL.RawQuery != "" {
 			req.URL.RawQuery += "&"
 		}
-		req.URL.RawQuery += p.Encode()
+		req.URL.RawQuery += payload
+		signIntegrationRequest(req, cmd.SigningSecret, []byte(req.URL.RawQuery))
+	} else {
+		signIntegrationRequest(req, cmd.SigningSecret, []byte(payload))
 	}
 
 	req.Header.Set("Accept", "application/json")
@@ -726,6 +730,7 @@ func (a *App) UpdateCommand(oldCmd, updatedCmd *model.Command) (*model.Command,
 	updatedCmd.Trigger = strings.ToLower(updatedCmd.Trigger)
 	updatedCmd.Id = oldCmd.Id
 	updatedCmd.Token = oldCmd.Token
+	updatedCmd.SigningSecret = oldCmd.SigningSecret
 	updatedCmd.CreateAt = oldCmd.CreateAt
 	updatedCmd.UpdateAt = model.GetMillis()
 	updatedCmd.DeleteAt = oldCmd.DeleteAt
@@ -794,6 +799,30 @@ func (a *App) RegenCommandToken(cmd *model.Command) (*model.Command, *model.AppE
 	return command, nil
 }
 
+func (a *App) RegenCommandSigningSecret(cmd *model.Command) (*model.Command, *model.AppError) {
+	if !*a.Config().ServiceSettings.EnableCommands {
+		return nil, model.NewAppError("RegenCommandSigningSecret", "api.command.disabled.app_error", nil, "", http.StatusNotImplemented)
+	}
+
+	cmd.SigningSecret = model.NewIntegrationSigningSecret()
+
+	command, err := a.Srv().Store().Command().Update(cmd)
+	if err != nil {
+		var nfErr *store.ErrNotFound
+		var appErr *model.AppError
+		switch {
+		case errors.As(err, &nfErr):
+			return nil, model.NewAppError("SqlCommandStore.Update", "store.sql_command.update.missing.app_error", map[string]any{"command_id": cmd.Id}, "", http.StatusNotFound).Wrap(err)
+		case errors.As(err, &appErr):
+			return nil, appErr
+		default:
+			return nil, model.NewAppError("RegenCommandSigningSecret", "app.command.regencommandsigningsecret.internal_error", nil, "", http.StatusInternalServerError).Wrap(err)
+		}
+	}
+
+	return command, nil
+}
+
 func (a *App) DeleteCommand(commandID string) *model.AppError {
 	if !*a.Config().ServiceSettings.EnableCommands {
 		return model.NewAppError("DeleteCommand", "api.command.disabled.app_error", nil, "", http.StatusNotImplemented)
@@ -0,0 +1,200 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package app
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+	"github.com/mattermost/mattermost/server/public/shared/request"
+	"github.com/mattermost/mattermost/server/v8/channels/store"
+)
+
+const deleteExpiredPostsBatchSize = 100
+
+// validatePostExpiry checks the expiry requested in the props of a new post.
+func (a *App) validatePostExpiry(post *model.Post) *model.AppError {
+	value := post.GetProp(model.PostPropsExpiryMinutes)
+	if value == nil {
+		return nil
+	}
+
+	if !*a.Config().ServiceSettings.EnablePostExpiry {
+		return model.NewAppError("validatePostExpiry", "app.post.expiry.feature_disabled", nil, "", http.StatusNotImplemented)
+	}
+
+	minutes := post.GetExpiryMinutes()
+	if minutes <= 0 || minutes > model.ChannelPostExpiryMaxMinutes {
+		return model.NewAppError("validatePostExpiry", "app.post.expiry_minutes.invalid.app_error", map[string]any{"Max": model.ChannelPostExpiryMaxMinutes}, "", http.StatusBadRequest)
+	}
+
+	return nil
+}
+
+// scheduleExpiringPost records when a newly created post is due to be deleted, if the
+// channel or the post itself asks for it to expire.
+func (a *App) scheduleExpiringPost(post *model.Post, channel *model.Channel) *model.AppError {
+	if !*a.Config().ServiceSettings.EnablePostExpiry {
+		return nil
+	}
+
+	minutes := channel.PostExpiryMinutesFor(post)
+	if minutes <= 0 {
+		return nil
+	}
+
+	expiringPost := &model.ExpiringPost{
+		PostId:    post.Id,
+		ChannelId: post.ChannelId,
+		ExpireAt:  post.CreateAt + minutes*60*1000,
+	}
+	if _, err := a.Srv().Store().ExpiringPost().Save(expiringPost); err != nil {
+		var appErr *model.AppError
+		if errors.As(err, &appErr) {
+			return appErr
+		}
+		return model.NewAppError("scheduleExpiringPost", "app.post.save_expiry.app_error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+
+	return nil
+}
+
+// DeleteExpiredPosts permanently deletes, in batches, the posts that are past their expiry
+// along with their attachments. The replies to an expired root post are deleted with it, as
+// they can't be shown without it.
+func (a *App) DeleteExpiredPosts(rctx request.CTX) error {
+	for {
+		expiringPosts, err := a.Srv().Store().ExpiringPost().GetExpired(model.GetMillis(), deleteExpiredPostsBatchSize)
+		if err != nil {
+			return err
+		}
+
+		if len(expiringPosts) == 0 {
+			return nil
+		}
+
+		if err := a.deleteExpiredPostsBatch(rctx, expiringPosts); err != nil {
+			return err
+		}
+
+		if len(expiringPosts) < deleteExpiredPostsBatchSize {
+			return nil
+		}
+	}
+}
+
+func (a *App) deleteExpiredPostsBatch(rctx request.CTX, expiringPosts []*model.ExpiringPost) error {
+	postIDs := make([]string, 0, len(expiringPosts))
+	for _, expiringPost := range expiringPosts {
+		postIDs = append(postIDs, expiringPost.PostId)
+	}
+
+	// Posts that were already deleted by other means only need their expiry cleared.
+	posts, err := a.Srv().Store().Post().GetPostsByIds(postIDs)
+	var nfErr *store.ErrNotFound
+	if err != nil && !errors.As(err, &nfErr) {
+		return err
+	}
+
+	// The replies to the expired root posts are deleted with them, and their own expiry cleared.
+	replies, err := a.getRepliesToExpiredPosts(posts)
+	if err != nil {
+		return err
+	}
+	for _, reply := range replies {
+		postIDs = append(postIDs, reply.Id)
+	}
+	posts = append(posts, replies...)
+
+	for _, post := range posts {
+		a.permanentDeletePostFiles(rctx, post.Id)
+	}
+
+	if err := a.Srv().Store().Post().PermanentDeletePosts(rctx, posts); err != nil {
+		return err
+	}
+
+	if err := a.Srv().Store().ExpiringPost().DeleteForPosts(postIDs); err != nil {
+		return err
+	}
+
+	channelIDs := make(map[string]bool)
+	for _, post := range posts {
+		channelIDs[post.ChannelId] = true
+		a.publishExpiredPostDeleted(rctx, post)
+		a.deleteFlaggedPosts(rctx, post.Id)
+	}
+
+	for channelID := range channelIDs {
+		a.invalidateCacheForChannelPosts(channelID)
+	}
+
+	rctx.Logger().Debug("Deleted expired posts", mlog.Int("count", len(posts)))
+
+	return nil
+}
+
+// getRepliesToExpiredPosts returns the replies to the root posts among the given expired
+// posts, leaving out those that are expired themselves.
+func (a *App) getRepliesToExpiredPosts(posts []*model.Post) ([]*model.Post, error) {
+	expired := make(map[string]bool, len(posts))
+	for _, post := range posts {
+		expired[post.Id] = true
+	}
+
+	var replies []*model.Post
+	for _, post := range posts {
+		if post.RootId != "" {
+			continue
+		}
+		threadPosts, err := a.Srv().Store().Post().GetPostsByThread(post.Id, 0)
+		if err != nil {
+			return nil, err
+		}
+		for _, reply := range threadPosts {
+			if !expired[reply.Id] {
+				replies = append(replies, reply)
+			}
+		}
+	}
+	return replies, nil
+}
+
+// permanentDeletePostFiles removes the attachments of a post from the file store along
+// with their file infos.
+func (a *App) permanentDeletePostFiles(rctx request.CTX, postID string) {
+	fileInfos, err := a.Srv().Store().FileInfo().GetForPost(postID, true, true, false)
+	if err != nil {
+		rctx.Logger().Warn("Failed to get files of expired post", mlog.String("post_id", postID), mlog.Err(err))
+		return
+	}
+
+	for _, info := range fileInfos {
+		if err := a.Srv().Store().FileInfo().PermanentDelete(rctx, info.Id); err != nil {
+			rctx.Logger().Warn("Failed to delete file info of expired post", mlog.String("post_id", postID), mlog.String("file_id", info.Id), mlog.Err(err))
+			continue
+		}
+		a.removeFileInfoFiles(rctx, info)
+	}
+
+	if len(fileInfos) > 0 {
+		a.Srv().Store().FileInfo().InvalidateFileInfosForPostCache(postID, true)
+		a.Srv().Store().FileInfo().InvalidateFileInfosForPostCache(postID, false)
+	}
+}
+
+func (a *App) publishExpiredPostDeleted(rctx request.CTX, post *model.Post) {
+	postJSON, err := json.Marshal(post)
+	if err != nil {
+		rctx.Logger().Warn("Failed to encode expired post to JSON", mlog.String("post_id", post.Id), mlog.Err(err))
+		return
+	}
+
+	message := model.NewWebSocketEvent(model.WebsocketEventPostDeleted, "", post.ChannelId, "", nil, "")
+	message.Add("post", string(postJSON))
+	a.Publish(message)
+}
@@ -0,0 +1,132 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package app
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost/server/public/model"
+)
+
+func TestCreatePostWithExpiry(t *testing.T) {
+	th := Setup(t).InitBasic()
+	defer th.TearDown()
+
+	th.App.UpdateConfig(func(cfg *model.Config) { *cfg.ServiceSettings.EnablePostExpiry = true })
+
+	t.Run("invalid expiry is rejected", func(t *testing.T) {
+		post := &model.Post{
+			UserId:    th.BasicUser.Id,
+			ChannelId: th.BasicChannel.Id,
+			Message:   "message",
+		}
+		post.AddProp(model.PostPropsExpiryMinutes, float64(-1))
+
+		_, appErr := th.App.CreatePost(th.Context, post, th.BasicChannel, false, true)
+		require.NotNil(t, appErr)
+		assert.Equal(t, "app.post.expiry_minutes.invalid.app_error", appErr.Id)
+	})
+
+	t.Run("expiry is rejected when post expiry is disabled", func(t *testing.T) {
+		th.App.UpdateConfig(func(cfg *model.Config) { *cfg.ServiceSettings.EnablePostExpiry = false })
+		defer th.App.UpdateConfig(func(cfg *model.Config) { *cfg.ServiceSettings.EnablePostExpiry = true })
+
+		post := &model.Post{
+			UserId:    th.BasicUser.Id,
+			ChannelId: th.BasicChannel.Id,
+			Message:   "message",
+		}
+		post.AddProp(model.PostPropsExpiryMinutes, float64(1))
+
+		_, appErr := th.App.CreatePost(th.Context, post, th.BasicChannel, false, true)
+		require.NotNil(t, appErr)
+		assert.Equal(t, "app.post.expiry.feature_disabled", appErr.Id)
+	})
+
+	t.Run("post in a channel without expiry is not scheduled", func(t *testing.T) {
+		post := th.CreatePost(th.BasicChannel)
+
+		expired, err := th.App.Srv().Store().ExpiringPost().GetExpired(post.CreateAt+model.ChannelPostExpiryMaxMinutes*60*1000, 1000)
+		require.NoError(t, err)
+		for _, expiringPost := range expired {
+			assert.NotEqual(t, post.Id, expiringPost.PostId)
+		}
+	})
+}
+
+func TestDeleteExpiredPosts(t *testing.T) {
+	th := Setup(t).InitBasic()
+	defer th.TearDown()
+
+	th.App.UpdateConfig(func(cfg *model.Config) { *cfg.ServiceSettings.EnablePostExpiry = true })
+
+	channel := th.CreateChannel(th.Context, th.BasicTeam)
+	channel.PostExpiryMinutes = 1
+	channel, appErr := th.App.UpdateChannel(th.Context, channel)
+	require.Nil(t, appErr)
+
+	createPost := func(t *testing.T, rootID string, createAt int64, fileIDs ...string) *model.Post {
+		t.Helper()
+		post, appErr := th.App.CreatePost(th.Context, &model.Post{
+			UserId:    th.BasicUser.Id,
+			ChannelId: channel.Id,
+			RootId:    rootID,
+			Message:   "message_" + model.NewId(),
+			CreateAt:  createAt,
+			FileIds:   fileIDs,
+		}, channel, false, true)
+		require.Nil(t, appErr)
+		return post
+	}
+
+	fileInfo, appErr := th.App.UploadFileForUserAndTeam(th.Context, []byte("data"), channel.Id, "test.txt", th.BasicUser.Id, th.BasicTeam.Id)
+	require.Nil(t, appErr)
+	sharedFileInfo, appErr := th.App.UploadFileForUserAndTeam(th.Context, []byte("shared"), channel.Id, "shared.txt", th.BasicUser.Id, th.BasicTeam.Id)
+	require.Nil(t, appErr)
+	copiedFileIDs, appErr := th.App.CopyFileInfos(th.Context, th.BasicUser.Id, []string{sharedFileInfo.Id})
+	require.Nil(t, appErr)
+
+	past := model.GetMillis() - 2*60*1000
+	expiredRoot := createPost(t, "", past, fileInfo.Id, sharedFileInfo.Id)
+	expiredReply := createPost(t, expiredRoot.Id, past)
+	recentReply := createPost(t, expiredRoot.Id, model.GetMillis())
+	recentPost := createPost(t, "", model.GetMillis(), copiedFileIDs...)
+
+	err := th.App.DeleteExpiredPosts(th.Context)
+	require.NoError(t, err)
+
+	_, err = th.App.Srv().Store().Post().GetSingle(th.Context, expiredRoot.Id, true)
+	require.Error(t, err)
+
+	_, err = th.App.Srv().Store().Post().GetSingle(th.Context, expiredReply.Id, true)
+	require.Error(t, err)
+
+	// Replies expire along with their root post.
+	_, err = th.App.Srv().Store().Post().GetSingle(th.Context, recentReply.Id, true)
+	require.Error(t, err)
+
+	_, err = th.App.Srv().Store().Post().GetSingle(th.Context, recentPost.Id, false)
+	require.NoError(t, err)
+
+	_, err = th.App.Srv().Store().FileInfo().Get(fileInfo.Id)
+	require.Error(t, err)
+
+	exists, appErr := th.App.FileExists(fileInfo.Path)
+	require.Nil(t, appErr)
+	assert.False(t, exists)
+
+	// The files shared with the file infos of other posts are kept.
+	exists, appErr = th.App.FileExists(sharedFileInfo.Path)
+	require.Nil(t, appErr)
+	assert.True(t, exists)
+
+	expired, err := th.App.Srv().Store().ExpiringPost().GetExpired(model.GetMillis(), 1000)
+	require.NoError(t, err)
+	for _, expiringPost := range expired {
+		assert.NotContains(t, []string{expiredRoot.Id, expiredReply.Id, recentReply.Id}, expiringPost.PostId)
+	}
+}
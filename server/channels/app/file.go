@@ -44,16 +44,17 @@ const (
 	miniPreviewImageWidth      = 16
 	miniPreviewImageHeight     = 16
 	jpegEncQuality             = 90
+	webPPreviewFileExt         = "webp"
 	maxUploadInitialBufferSize = 1024 * 1024 // 1MB
 	maxContentExtractionSize   = 1024 * 1024 // 1MB
 )
 
 func (a *App) FileBackend() filestore.FileBackend {
-	return a.ch.filestore
+	return a.Srv().FileBackend()
 }
 
 func (a *App) ExportFileBackend() filestore.FileBackend {
-	return a.ch.exportFilestore
+	return a.Srv().ExportFileBackend()
 }
 
 func (a *App) CheckMandatoryS3Fields(settings *model.FileSettings) *model.AppError {
@@ -199,6 +200,14 @@ func (a *App) MoveFile(oldPath, newPath string) *model.AppError {
 	return nil
 }
 
+func (a *App) CopyFile(oldPath, newPath string) *model.AppError {
+	nErr := a.FileBackend().CopyFile(oldPath, newPath)
+	if nErr != nil {
+		return model.NewAppError("CopyFile", "api.file.copy_file.app_error", nil, "", http.StatusInternalServerError).Wrap(nErr)
+	}
+	return nil
+}
+
 func (a *App) WriteFileContext(ctx context.Context, fr io.Reader, path string) (int64, *model.AppError) {
 	return a.Srv().writeFileContext(ctx, fr, path)
 }
@@ -364,9 +373,9 @@ func (a *App) getInfoForFilename(rctx request.CTX, post *model.Post, teamID, cha
 	info.UpdateAt = post.UpdateAt
 	info.Path = path
 
-	if info.IsImage() && !info.IsSvg() {
+	if info.IsImage() && !info.IsSvg() && a.ch.imgDecoder.CanDecodeMimeType(info.MimeType) {
 		nameWithoutExtension := name[:strings.LastIndex(name, ".")]
-		info.PreviewPath = pathPrefix + nameWithoutExtension + "_preview." + getFileExtFromMimeType(info.MimeType)
+		info.PreviewPath = pathPrefix + nameWithoutExtension + "_preview." + getPreviewFileExt(info.MimeType, a.webPPreviewQuality())
 		info.ThumbnailPath = pathPrefix + nameWithoutExtension + "_thumb." + getFileExtFromMimeType(info.MimeType)
 	}
 
@@ -693,6 +702,9 @@ type UploadFileTask struct {
 
 	imgDecoder *imaging.Decoder
 	imgEncoder *imaging.Encoder
+
+	// The quality of the WebP previews, or 0 if previews are not encoded as WebP.
+	webPPreviewQuality int
 }
 
 func (t *UploadFileTask) init(a *App) {
@@ -748,6 +760,8 @@ func (a *App) UploadFileX(c request.CTX, channelID, name string, input io.Reader
 		imgDecoder:     a.ch.imgDecoder,
 		imgEncoder:     a.ch.imgEncoder,
 		ExtractContent: true,
+
+		webPPreviewQuality: a.webPPreviewQuality(),
 	}
 	for _, o := range opts {
 		o(t)
@@ -804,7 +818,23 @@ func (a *App) UploadFileX(c request.CTX, channelID, name string, input io.Reader
 		t.postprocessImage(file)
 	}
 
+	if !t.Raw && (t.fileinfo.IsVideo() || t.fileinfo.IsAudio()) {
+		file, aerr = a.FileReader(t.fileinfo.Path)
+		if aerr != nil {
+			return nil, aerr
+		}
+		defer file.Close()
+		probeMediaFile(t.fileinfo, file)
+	}
+
+	a.deduplicateUploadedFile(c, t.fileinfo, "")
+	a.markFileForVirusScan(t.fileinfo)
+
 	if _, err := t.saveToDatabase(c, t.fileinfo); err != nil {
+		if t.fileinfo.ContentHash != "" {
+			a.releaseFileBlob(c, t.fileinfo.ContentHash)
+		}
+
 		var appErr *model.AppError
 		switch {
 		case errors.As(err, &appErr):
@@ -814,6 +844,11 @@ func (a *App) UploadFileX(c request.CTX, channelID, name string, input io.Reader
 		}
 	}
 
+	a.scanFileAsync(c, t.fileinfo)
+	if !t.Raw {
+		a.processMediaFileAsync(c, t.fileinfo)
+	}
+
 	if *a.Config().FileSettings.ExtractContent && t.ExtractContent {
 		infoCopy := *t.fileinfo
 		a.Srv().GoBuffered(func() {
@@ -854,9 +889,14 @@ func (t *UploadFileTask) preprocessImage() *model.AppError {
 		return t.newAppError("api.file.upload_file.large_image_detailed.app_error", http.StatusBadRequest)
 	}
 
+	// Images such as HEIC photos can't be decoded without the image converter.
+	if !t.imgDecoder.CanDecodeMimeType(t.fileinfo.MimeType) {
+		return nil
+	}
+
 	t.fileinfo.HasPreviewImage = true
 	nameWithoutExtension := t.Name[:strings.LastIndex(t.Name, ".")]
-	t.fileinfo.PreviewPath = t.pathPrefix() + nameWithoutExtension + "_preview." + getFileExtFromMimeType(t.fileinfo.MimeType)
+	t.fileinfo.PreviewPath = t.pathPrefix() + nameWithoutExtension + "_preview." + getPreviewFileExt(t.fileinfo.MimeType, t.webPPreviewQuality)
 	t.fileinfo.ThumbnailPath = t.pathPrefix() + nameWithoutExtension + "_thumb." + getFileExtFromMimeType(t.fileinfo.MimeType)
 
 	// check the image orientation with goexif; consume the bytes we
@@ -885,8 +925,8 @@ func (t *UploadFileTask) preprocessImage() *model.AppError {
 }
 
 func (t *UploadFileTask) postprocessImage(file io.Reader) {
-	// don't try to process SVG files
-	if t.fileinfo.IsSvg() {
+	// don't try to process SVG files, nor the images that can't be decoded
+	if t.fileinfo.IsSvg() || !t.imgDecoder.CanDecodeMimeType(t.fileinfo.MimeType) {
 		return
 	}
 
@@ -913,13 +953,15 @@ func (t *UploadFileTask) postprocessImage(file io.Reader) {
 			var err error
 			// It's okay to access imgType in a separate goroutine,
 			// because imgType is only written once and never written again.
-			if imgType == "png" {
+			if isWebPPreviewPath(path) {
+				err = t.imgEncoder.EncodeWebP(w, img, t.webPPreviewQuality)
+			} else if imgType == "png" {
 				err = t.imgEncoder.EncodePNG(w, img)
 			} else {
 				err = t.imgEncoder.EncodeJPEG(w, img, jpegEncQuality)
 			}
 			if err != nil {
-				t.Logger.Error("Unable to encode image as jpeg", mlog.String("path", path), mlog.Err(err))
+				t.Logger.Error("Unable to encode image", mlog.String("path", path), mlog.Err(err))
 				w.CloseWithError(err)
 			} else {
 				w.Close()
@@ -1032,9 +1074,14 @@ func (a *App) DoUploadFileExpectModification(c request.CTX, now time.Time, rawTe
 			return nil, data, err
 		}
 
-		nameWithoutExtension := filename[:strings.LastIndex(filename, ".")]
-		info.PreviewPath = pathPrefix + nameWithoutExtension + "_preview." + getFileExtFromMimeType(info.MimeType)
-		info.ThumbnailPath = pathPrefix + nameWithoutExtension + "_thumb." + getFileExtFromMimeType(info.MimeType)
+		if a.ch.imgDecoder.CanDecodeMimeType(info.MimeType) {
+			nameWithoutExtension := filename[:strings.LastIndex(filename, ".")]
+			info.PreviewPath = pathPrefix + nameWithoutExtension + "_preview." + getPreviewFileExt(info.MimeType, a.webPPreviewQuality())
+			info.ThumbnailPath = pathPrefix + nameWithoutExtension + "_thumb." + getFileExtFromMimeType(info.MimeType)
+		} else {
+			// Images such as HEIC photos can't be decoded without the image converter.
+			info.HasPreviewImage = false
+		}
 	}
 
 	var rejectionError *model.AppError
@@ -1064,7 +1111,15 @@ func (a *App) DoUploadFileExpectModification(c request.CTX, now time.Time, rawTe
 		return nil, data, err
 	}
 
+	sum := sha256.Sum256(data)
+	a.deduplicateUploadedFile(c, info, model.FileBlobHash(sum[:]))
+	a.markFileForVirusScan(info)
+
 	if _, err := a.Srv().Store().FileInfo().Save(c, info); err != nil {
+		if info.ContentHash != "" {
+			a.releaseFileBlob(c, info.ContentHash)
+		}
+
 		var appErr *model.AppError
 		switch {
 		case errors.As(err, &appErr):
@@ -1074,6 +1129,9 @@ func (a *App) DoUploadFileExpectModification(c request.CTX, now time.Time, rawTe
 		}
 	}
 
+	a.scanFileAsync(c, info)
+	a.processMediaFileAsync(c, info)
+
 	// The extra boolean extractContent is used to turn off extraction
 	// during the import process. It is unnecessary overhead during the import,
 	// and something we can do without.
@@ -1133,48 +1191,57 @@ func prepareImage(rctx request.CTX, imgDecoder *imaging.Decoder, imgData io.Read
 	return img, imgType, release, nil
 }
 
-func (a *App) generateThumbnailImage(rctx request.CTX, img image.Image, imgType, thumbnailPath string) {
+func (a *App) generateThumbnailImage(rctx request.CTX, img image.Image, imgType, thumbnailPath string) error {
 	var buf bytes.Buffer
 
 	thumb := imaging.GenerateThumbnail(img, imageThumbnailWidth, imageThumbnailHeight)
 	if imgType == "png" {
 		if err := a.ch.imgEncoder.EncodePNG(&buf, thumb); err != nil {
 			rctx.Logger().Error("Unable to encode image as png", mlog.String("path", thumbnailPath), mlog.Err(err))
-			return
+			return err
 		}
 	} else {
 		if err := a.ch.imgEncoder.EncodeJPEG(&buf, thumb, jpegEncQuality); err != nil {
 			rctx.Logger().Error("Unable to encode image as jpeg", mlog.String("path", thumbnailPath), mlog.Err(err))
-			return
+			return err
 		}
 	}
 
 	if _, err := a.WriteFile(&buf, thumbnailPath); err != nil {
 		rctx.Logger().Error("Unable to upload thumbnail", mlog.String("path", thumbnailPath), mlog.Err(err))
-		return
+		return err
 	}
+
+	return nil
 }
 
-func (a *App) generatePreviewImage(rctx request.CTX, img image.Image, imgType, previewPath string) {
+func (a *App) generatePreviewImage(rctx request.CTX, img image.Image, imgType, previewPath string) error {
 	var buf bytes.Buffer
 
 	preview := imaging.GeneratePreview(img, imagePreviewWidth)
-	if imgType == "png" {
+	if isWebPPreviewPath(previewPath) {
+		if err := a.ch.imgEncoder.EncodeWebP(&buf, preview, a.webPPreviewQuality()); err != nil {
+			rctx.Logger().Error("Unable to encode image as preview webp", mlog.Err(err), mlog.String("path", previewPath))
+			return err
+		}
+	} else if imgType == "png" {
 		if err := a.ch.imgEncoder.EncodePNG(&buf, preview); err != nil {
 			rctx.Logger().Error("Unable to encode image as preview png", mlog.Err(err), mlog.String("path", previewPath))
-			return
+			return err
 		}
 	} else {
 		if err := a.ch.imgEncoder.EncodeJPEG(&buf, preview, jpegEncQuality); err != nil {
 			rctx.Logger().Error("Unable to encode image as preview jpg", mlog.Err(err), mlog.String("path", previewPath))
-			return
+			return err
 		}
 	}
 
 	if _, err := a.WriteFile(&buf, previewPath); err != nil {
 		rctx.Logger().Error("Unable to upload preview", mlog.Err(err), mlog.String("path", previewPath))
-		return
+		return err
 	}
+
+	return nil
 }
 
 // generateMiniPreview updates mini preview if needed
@@ -1343,7 +1410,17 @@ func (a *App) CopyFileInfos(rctx request.CTX, userID string, fileIDs []string) (
 		fileInfo.PostId = ""
 		fileInfo.ChannelId = ""
 
+		if fileInfo.ContentHash != "" {
+			if _, _, err := a.Srv().Store().FileBlob().Acquire(fileInfo.ContentHash, fileInfo.Path, fileInfo.Size); err != nil {
+				return nil, model.NewAppError("CopyFileInfos", "app.file_blob.acquire.app_error", nil, "", http.StatusInternalServerError).Wrap(err)
+			}
+		}
+
 		if _, err := a.Srv().Store().FileInfo().Save(rctx, fileInfo); err != nil {
+			if fileInfo.ContentHash != "" {
+				a.releaseFileBlob(rctx, fileInfo.ContentHash)
+			}
+
 			var appErr *model.AppError
 			switch {
 			case errors.As(err, &appErr):
@@ -1457,8 +1534,11 @@ func (a *App) SearchFilesInTeamForUser(c request.CTX, terms string, userId strin
 }
 
 func (a *App) ExtractContentFromFileInfo(rctx request.CTX, fileInfo *model.FileInfo) error {
-	// We don't process images.
-	if fileInfo.IsImage() {
+	fileSettings := a.Config().FileSettings
+
+	// We don't process images, unless their text can be recognized.
+	ocrEnabled := *fileSettings.EnableOCR
+	if fileInfo.IsImage() && !(ocrEnabled && docextractor.IsOCRSupported(fileInfo.Name)) {
 		return nil
 	}
 
@@ -1467,9 +1547,15 @@ func (a *App) ExtractContentFromFileInfo(rctx request.CTX, fileInfo *model.FileI
 		return errors.Wrap(aerr, "failed to open file for extract file content")
 	}
 	defer file.Close()
-	text, err := docextractor.Extract(rctx.Logger(), fileInfo.Name, file, docextractor.ExtractSettings{
-		ArchiveRecursion: *a.Config().FileSettings.ArchiveRecursion,
-	})
+	settings := docextractor.ExtractSettings{
+		ArchiveRecursion: *fileSettings.ArchiveRecursion,
+	}
+	if ocrEnabled {
+		settings.OCRCommand = *fileSettings.OCRCommand
+		settings.OCRLanguages = *fileSettings.OCRLanguages
+		settings.OCRTimeout = time.Duration(*fileSettings.OCRTimeoutSeconds) * time.Second
+	}
+	text, err := docextractor.Extract(rctx.Logger(), fileInfo.Name, file, settings)
 	if err != nil {
 		return errors.Wrap(err, "failed to extract file content")
 	}
@@ -1594,3 +1680,36 @@ func getFileExtFromMimeType(mimeType string) string {
 	}
 	return "jpg"
 }
+
+// getPreviewFileExt returns the extension of the preview of an image, which is encoded as WebP
+// when a WebP quality is given.
+func getPreviewFileExt(mimeType string, webPQuality int) string {
+	if webPQuality > 0 {
+		return webPPreviewFileExt
+	}
+	return getFileExtFromMimeType(mimeType)
+}
+
+func isWebPPreviewPath(previewPath string) bool {
+	return strings.HasSuffix(previewPath, "."+webPPreviewFileExt)
+}
+
+// webPPreviewQuality returns the quality of the WebP previews, or 0 if the previews are not
+// encoded as WebP.
+func (a *App) webPPreviewQuality() int {
+	fileSettings := a.Config().FileSettings
+	if !*fileSettings.EnableImageConverter || !*fileSettings.EnableWebPPreviews {
+		return 0
+	}
+	return *fileSettings.WebPPreviewQuality
+}
+
+func imageConverterOptions(cfg *model.Config) imaging.ConverterOptions {
+	if !*cfg.FileSettings.EnableImageConverter {
+		return imaging.ConverterOptions{}
+	}
+	return imaging.ConverterOptions{
+		Command: *cfg.FileSettings.ImageConverterCommand,
+		Timeout: time.Duration(*cfg.FileSettings.ImageConverterTimeoutSeconds) * time.Second,
+	}
+}
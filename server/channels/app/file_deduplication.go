@@ -0,0 +1,247 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package app
+
+import (
+	"crypto/sha256"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+	"github.com/mattermost/mattermost/server/public/shared/request"
+	"github.com/mattermost/mattermost/server/v8/channels/store"
+)
+
+const fileBlobPurgeBatchSize = 100
+
+// hashStoredFile returns the blob hash of the contents of the file at path.
+func (a *App) hashStoredFile(path string) (string, *model.AppError) {
+	file, appErr := a.FileReader(path)
+	if appErr != nil {
+		return "", appErr
+	}
+	defer file.Close()
+
+	hash := sha256.New()
+	if _, err := io.Copy(hash, file); err != nil {
+		return "", model.NewAppError("hashStoredFile", "api.file.read_file.app_error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+
+	return model.FileBlobHash(hash.Sum(nil)), nil
+}
+
+// deduplicateUploadedFile points a newly uploaded file info, whose contents were written to
+// info.Path, at the blob storing the same contents. The uploaded copy either becomes that
+// blob or is removed. When hash is empty it is computed from the stored file.
+//
+// Deduplication only saves space, so failures are logged and leave the file where it was
+// uploaded. The caller must release the blob if it fails to save the file info.
+func (a *App) deduplicateUploadedFile(rctx request.CTX, info *model.FileInfo, hash string) {
+	if !*a.Config().FileSettings.EnableDeduplication {
+		return
+	}
+
+	logger := rctx.Logger().With(mlog.String("path", info.Path))
+
+	if hash == "" {
+		var appErr *model.AppError
+		if hash, appErr = a.hashStoredFile(info.Path); appErr != nil {
+			logger.Warn("Failed to hash uploaded file for deduplication", mlog.Err(appErr))
+			return
+		}
+	}
+
+	blob, created, err := a.Srv().Store().FileBlob().Acquire(hash, model.FileBlobPath(hash), info.Size)
+	if err != nil {
+		logger.Warn("Failed to acquire file blob", mlog.String("hash", hash), mlog.Err(err))
+		return
+	}
+
+	if created || !a.fileBlobStored(rctx, blob) {
+		if appErr := a.MoveFile(info.Path, blob.Path); appErr != nil {
+			logger.Warn("Failed to move uploaded file to its file blob", mlog.String("hash", hash), mlog.Err(appErr))
+			a.releaseFileBlob(rctx, hash)
+			return
+		}
+	} else if appErr := a.RemoveFile(info.Path); appErr != nil {
+		logger.Warn("Failed to remove duplicate of file blob", mlog.String("hash", hash), mlog.Err(appErr))
+	}
+
+	info.Path = blob.Path
+	info.ContentHash = hash
+}
+
+// fileBlobStored reports whether the contents of an existing blob are in the file store. They
+// may be missing if the blob was purged while it was being acquired.
+func (a *App) fileBlobStored(rctx request.CTX, blob *model.FileBlob) bool {
+	exists, appErr := a.FileExists(blob.Path)
+	if appErr != nil {
+		rctx.Logger().Warn("Failed to check if file blob exists", mlog.String("hash", blob.Hash), mlog.Err(appErr))
+		return false
+	}
+	return exists
+}
+
+// releaseFileBlob removes a reference acquired for a file info that was never saved, and
+// removes the blob if nothing else references it.
+func (a *App) releaseFileBlob(rctx request.CTX, hash string) {
+	refCount, err := a.Srv().Store().FileBlob().Release(hash)
+	if err != nil {
+		rctx.Logger().Warn("Failed to release file blob", mlog.String("hash", hash), mlog.Err(err))
+		return
+	}
+
+	if refCount == 0 {
+		a.removeUnreferencedFileBlob(rctx, &model.FileBlob{Hash: hash, Path: model.FileBlobPath(hash)})
+	}
+}
+
+// removeUnreferencedFileBlob removes the blob from the file store unless it was referenced
+// again in the meantime, and returns whether it was removed. The blob is tombstoned while its
+// contents are removed, so that the same contents uploaded concurrently aren't written to its
+// path only to be removed.
+func (a *App) removeUnreferencedFileBlob(rctx request.CTX, blob *model.FileBlob) bool {
+	logger := rctx.Logger().With(mlog.String("hash", blob.Hash), mlog.String("path", blob.Path))
+
+	tombstoned, err := a.Srv().Store().FileBlob().Tombstone(blob.Hash)
+	if err != nil {
+		logger.Warn("Failed to tombstone unreferenced file blob", mlog.Err(err))
+		return false
+	}
+	if !tombstoned {
+		return false
+	}
+
+	// The contents may already be removed if a previous purge failed to delete the blob.
+	exists, appErr := a.FileExists(blob.Path)
+	if appErr != nil {
+		logger.Warn("Failed to check if unreferenced file blob exists", mlog.Err(appErr))
+		return false
+	}
+	if exists {
+		if appErr = a.RemoveFile(blob.Path); appErr != nil {
+			// The blob stays tombstoned, and is removed again by the next purge.
+			logger.Warn("Failed to remove unreferenced file blob", mlog.Err(appErr))
+			return false
+		}
+	}
+
+	if err := a.Srv().Store().FileBlob().DeleteTombstoned(blob.Hash); err != nil {
+		logger.Warn("Failed to delete tombstoned file blob", mlog.Err(err))
+	}
+
+	return true
+}
+
+// PurgeUnreferencedFileBlobs removes the blobs whose last file info has been permanently
+// deleted, and returns the number of bytes freed. Each blob is tried once, the blobs failing
+// to be removed being left for the next purge.
+func (a *App) PurgeUnreferencedFileBlobs(rctx request.CTX) (int64, *model.AppError) {
+	var freed int64
+	var afterHash string
+	for {
+		blobs, err := a.Srv().Store().FileBlob().GetUnreferenced(afterHash, fileBlobPurgeBatchSize)
+		if err != nil {
+			return freed, model.NewAppError("PurgeUnreferencedFileBlobs", "app.file_blob.get_unreferenced.app_error", nil, "", http.StatusInternalServerError).Wrap(err)
+		}
+
+		for _, blob := range blobs {
+			if a.removeUnreferencedFileBlob(rctx, blob) {
+				freed += blob.Size
+			}
+		}
+		if len(blobs) > 0 {
+			afterHash = blobs[len(blobs)-1].Hash
+		}
+
+		if len(blobs) < fileBlobPurgeBatchSize {
+			return freed, nil
+		}
+	}
+}
+
+// removeFileInfoFiles removes the files of a file info that was permanently deleted. The
+// files still used by other file infos, such as the copies made by CopyFileInfos, are left
+// alone, and deduplicated contents are left to be purged once their last reference is deleted.
+func (a *App) removeFileInfoFiles(rctx request.CTX, info *model.FileInfo) {
+	paths := []string{info.ThumbnailPath, info.PreviewPath, info.RenditionPath}
+	if info.ContentHash == "" {
+		paths = append(paths, info.Path)
+	}
+
+	for _, path := range paths {
+		if path == "" {
+			continue
+		}
+		if count, err := a.Srv().Store().FileInfo().CountByPath(path); err != nil || count > 0 {
+			if err != nil {
+				rctx.Logger().Warn("Failed to check if a file is still used", mlog.String("file_id", info.Id), mlog.String("path", path), mlog.Err(err))
+			}
+			continue
+		}
+		if appErr := a.RemoveFile(path); appErr != nil {
+			rctx.Logger().Warn("Failed to remove file", mlog.String("file_id", info.Id), mlog.String("path", path), mlog.Err(appErr))
+		}
+	}
+}
+
+// DeduplicateFileInfo moves the contents of a file info saved before deduplication was
+// enabled to its blob, and returns the number of bytes saved by doing so.
+func (a *App) DeduplicateFileInfo(rctx request.CTX, info *model.FileInfo) (int64, *model.AppError) {
+	if info.ContentHash != "" {
+		return 0, nil
+	}
+
+	hash, appErr := a.hashStoredFile(info.Path)
+	if appErr != nil {
+		return 0, appErr
+	}
+
+	blob, created, err := a.Srv().Store().FileBlob().Acquire(hash, model.FileBlobPath(hash), info.Size)
+	if err != nil {
+		return 0, model.NewAppError("DeduplicateFileInfo", "app.file_blob.acquire.app_error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+
+	// The file info keeps pointing at its own copy until it is updated, so the contents
+	// are copied rather than moved.
+	copied := created || !a.fileBlobStored(rctx, blob)
+	if copied {
+		if appErr = a.CopyFile(info.Path, blob.Path); appErr != nil {
+			a.releaseFileBlob(rctx, hash)
+			return 0, appErr
+		}
+	}
+
+	if err = a.Srv().Store().FileInfo().SetContentHash(rctx, info.Id, hash, blob.Path); err != nil {
+		a.releaseFileBlob(rctx, hash)
+
+		var nfErr *store.ErrNotFound
+		if errors.As(err, &nfErr) {
+			// The file info was deleted or deduplicated concurrently.
+			return 0, nil
+		}
+		return 0, model.NewAppError("DeduplicateFileInfo", "app.file_info.save.app_error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+
+	a.Srv().Store().FileInfo().InvalidateFileInfosForPostCache(info.PostId, true)
+	a.Srv().Store().FileInfo().InvalidateFileInfosForPostCache(info.PostId, false)
+
+	// Copied file infos share their path, which must then be kept until the last of them
+	// is deduplicated.
+	if count, countErr := a.Srv().Store().FileInfo().CountByPath(info.Path); countErr != nil || count > 0 {
+		return 0, nil
+	}
+
+	if appErr = a.RemoveFile(info.Path); appErr != nil {
+		rctx.Logger().Warn("Failed to remove deduplicated file", mlog.String("file_id", info.Id), mlog.String("path", info.Path), mlog.Err(appErr))
+		return 0, nil
+	}
+
+	if copied {
+		return 0, nil
+	}
+	return info.Size, nil
+}
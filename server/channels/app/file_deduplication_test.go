@@ -0,0 +1,112 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package app
+
+import (
+	"crypto/sha256"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost/server/public/model"
+)
+
+func TestDoUploadFileDeduplication(t *testing.T) {
+	th := Setup(t)
+	defer th.TearDown()
+
+	th.App.UpdateConfig(func(cfg *model.Config) {
+		*cfg.FileSettings.EnableDeduplication = true
+	})
+
+	data := []byte("the same installer posted to many channels")
+	sum := sha256.Sum256(data)
+	hash := model.FileBlobHash(sum[:])
+
+	var infos []*model.FileInfo
+	for i := 0; i < 3; i++ {
+		info, appErr := th.App.DoUploadFile(th.Context, time.Now(), th.BasicTeam.Id, model.NewId(), th.BasicUser.Id, "installer.bin", data, false)
+		require.Nil(t, appErr)
+		infos = append(infos, info)
+
+		assert.Equal(t, hash, info.ContentHash)
+		assert.Equal(t, model.FileBlobPath(hash), info.Path)
+	}
+
+	blob, err := th.App.Srv().Store().FileBlob().Get(hash)
+	require.NoError(t, err)
+	assert.EqualValues(t, 3, blob.RefCount)
+
+	read, appErr := th.App.ReadFile(blob.Path)
+	require.Nil(t, appErr)
+	assert.Equal(t, data, read)
+
+	saved, err := th.App.Srv().Store().FileBlob().GetSavedBytes()
+	require.NoError(t, err)
+	assert.GreaterOrEqual(t, saved, int64(2*len(data)))
+
+	t.Run("the blob is kept until its last reference is deleted", func(t *testing.T) {
+		for i, info := range infos {
+			require.NoError(t, th.App.Srv().Store().FileInfo().PermanentDelete(th.Context, info.Id))
+			_, appErr := th.App.PurgeUnreferencedFileBlobs(th.Context)
+			require.Nil(t, appErr)
+
+			exists, appErr := th.App.FileExists(blob.Path)
+			require.Nil(t, appErr)
+			assert.Equal(t, i < len(infos)-1, exists)
+		}
+	})
+}
+
+func TestDeduplicateFileInfo(t *testing.T) {
+	th := Setup(t)
+	defer th.TearDown()
+
+	data := []byte("uploaded before deduplication was enabled")
+
+	var infos []*model.FileInfo
+	for i := 0; i < 2; i++ {
+		info, appErr := th.App.DoUploadFile(th.Context, time.Now(), th.BasicTeam.Id, model.NewId(), th.BasicUser.Id, "file.txt", data, false)
+		require.Nil(t, appErr)
+		require.Empty(t, info.ContentHash)
+		infos = append(infos, info)
+	}
+	defer func() {
+		for _, info := range infos {
+			th.App.Srv().Store().FileInfo().PermanentDelete(th.Context, info.Id)
+		}
+		th.App.PurgeUnreferencedFileBlobs(th.Context)
+	}()
+
+	saved, appErr := th.App.DeduplicateFileInfo(th.Context, infos[0])
+	require.Nil(t, appErr)
+	assert.EqualValues(t, 0, saved, "the first copy becomes the blob")
+
+	saved, appErr = th.App.DeduplicateFileInfo(th.Context, infos[1])
+	require.Nil(t, appErr)
+	assert.EqualValues(t, len(data), saved)
+
+	for _, info := range infos {
+		exists, appErr := th.App.FileExists(info.Path)
+		require.Nil(t, appErr)
+		assert.False(t, exists, "the original copy should have been removed")
+
+		deduplicated, err := th.App.Srv().Store().FileInfo().GetFromMaster(info.Id)
+		require.NoError(t, err)
+		assert.NotEmpty(t, deduplicated.ContentHash)
+
+		read, appErr := th.App.ReadFile(deduplicated.Path)
+		require.Nil(t, appErr)
+		assert.Equal(t, data, read)
+	}
+
+	// Deduplicating again is a no-op.
+	deduplicated, err := th.App.Srv().Store().FileInfo().GetFromMaster(infos[1].Id)
+	require.NoError(t, err)
+	saved, appErr = th.App.DeduplicateFileInfo(th.Context, deduplicated)
+	require.Nil(t, appErr)
+	assert.EqualValues(t, 0, saved)
+}
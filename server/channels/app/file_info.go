@@ -52,6 +52,8 @@ func getInfoForBytes(name string, data io.ReadSeeker, size int) (*model.FileInfo
 				info.HasPreviewImage = true
 			}
 		}
+	} else {
+		probeMediaFile(info, data)
 	}
 
 	return info, err
@@ -0,0 +1,307 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package app
+
+import (
+	"bytes"
+	"image"
+	"io"
+	"net/http"
+	"os"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+	"github.com/mattermost/mattermost/server/public/shared/request"
+	"github.com/mattermost/mattermost/server/v8/channels/app/imaging"
+	"github.com/mattermost/mattermost/server/v8/channels/app/media"
+)
+
+const (
+	// posterFrameOffset is the offset of the frame used as the poster of a video, skipping
+	// the black frames videos often start with.
+	posterFrameOffset = time.Second
+
+	renditionFileSuffix = "_web.mp4"
+)
+
+// probeMediaFile fills the duration, codecs and resolution of a video or audio file using
+// the built-in parsers. The files in the other container formats are probed by FFmpeg, if
+// enabled, once uploaded.
+func probeMediaFile(info *model.FileInfo, rs io.ReadSeeker) {
+	if !info.IsVideo() && !info.IsAudio() {
+		return
+	}
+	if _, err := rs.Seek(0, io.SeekStart); err != nil {
+		return
+	}
+	if md, err := media.Probe(rs); err == nil {
+		setMediaMetadata(info, md)
+	}
+}
+
+func setMediaMetadata(info *model.FileInfo, md *media.Metadata) {
+	info.Duration = md.Duration.Milliseconds()
+	info.VideoCodec = md.VideoCodec
+	info.AudioCodec = md.AudioCodec
+	if md.HasVideo() {
+		info.Width = md.Width
+		info.Height = md.Height
+	}
+}
+
+// processMediaFileAsync generates the poster of an uploaded video or audio file and
+// schedules the transcoding of videos which can't be played by all the browsers.
+func (a *App) processMediaFileAsync(rctx request.CTX, info *model.FileInfo) {
+	if !info.IsVideo() && !info.IsAudio() {
+		return
+	}
+
+	infoCopy := *info
+	a.Srv().GoBuffered(func() {
+		if _, appErr := a.GenerateMediaPreviews(rctx, &infoCopy); appErr != nil {
+			rctx.Logger().Warn("Failed to generate media previews", mlog.String("file_id", infoCopy.Id), mlog.Err(appErr))
+		}
+
+		if *a.Config().FileSettings.EnableMediaTranscoding && needsWebRendition(&infoCopy) {
+			if _, appErr := a.Srv().Jobs.CreateJob(rctx, model.JobTypeTranscodeMedia, map[string]string{"file_id": infoCopy.Id}); appErr != nil {
+				rctx.Logger().Warn("Failed to create media transcoding job", mlog.String("file_id", infoCopy.Id), mlog.Err(appErr))
+			}
+		}
+	})
+}
+
+// GenerateMediaPreviews generates the thumbnail, preview and mini preview of a video or audio
+// file from a frame of the video, or from its cover art. When FFmpeg is enabled, it also
+// probes the file, so that the files in any format it supports have their metadata. Without
+// FFmpeg, only the cover art embedded in the common container formats is used. It returns
+// whether the file info was updated.
+func (a *App) GenerateMediaPreviews(rctx request.CTX, info *model.FileInfo) (bool, *model.AppError) {
+	if !info.IsVideo() && !info.IsAudio() {
+		return false, nil
+	}
+
+	var updated bool
+	var poster image.Image
+	if a.ch.ffmpeg.IsEnabled() {
+		localPath, cleanup, appErr := a.copyFileToTemp(info.Path)
+		if appErr != nil {
+			return false, appErr
+		}
+		defer cleanup()
+
+		if md, err := a.ch.ffmpeg.Probe(localPath); err != nil {
+			rctx.Logger().Debug("Unable to probe media file", mlog.String("file_id", info.Id), mlog.Err(err))
+		} else {
+			setMediaMetadata(info, md)
+			updated = true
+		}
+
+		var offset time.Duration
+		if info.Duration > 2*posterFrameOffset.Milliseconds() {
+			offset = posterFrameOffset
+		}
+		if img, err := a.ch.ffmpeg.PosterFrame(localPath, offset); err != nil {
+			rctx.Logger().Debug("Unable to extract poster frame", mlog.String("file_id", info.Id), mlog.Err(err))
+		} else {
+			poster = img
+		}
+	}
+
+	if poster == nil {
+		artwork, appErr := a.readMediaArtwork(info)
+		if appErr != nil {
+			return false, appErr
+		}
+		if artwork != nil {
+			poster = a.decodeMediaArtwork(rctx, info, artwork)
+		}
+	}
+
+	if poster != nil {
+		pathPrefix := filePreviewPathPrefix(info)
+		nameWithoutExtension := strings.TrimSuffix(info.Name, path.Ext(info.Name))
+		info.ThumbnailPath = pathPrefix + nameWithoutExtension + "_thumb.jpg"
+		info.PreviewPath = pathPrefix + nameWithoutExtension + "_preview.jpg"
+
+		if err := a.generateThumbnailImage(rctx, poster, "jpeg", info.ThumbnailPath); err != nil {
+			return false, model.NewAppError("GenerateMediaPreviews", "app.file.generate_previews.app_error", nil, "", http.StatusInternalServerError).Wrap(err)
+		}
+		if err := a.generatePreviewImage(rctx, poster, "jpeg", info.PreviewPath); err != nil {
+			return false, model.NewAppError("GenerateMediaPreviews", "app.file.generate_previews.app_error", nil, "", http.StatusInternalServerError).Wrap(err)
+		}
+		if miniPreview, err := imaging.GenerateMiniPreviewImage(poster, miniPreviewImageWidth, miniPreviewImageHeight, jpegEncQuality); err != nil {
+			rctx.Logger().Info("Unable to generate mini preview image", mlog.Err(err))
+		} else {
+			info.MiniPreview = &miniPreview
+		}
+		info.HasPreviewImage = true
+		updated = true
+	}
+
+	if !updated {
+		return false, nil
+	}
+
+	// Only the fields set here are saved, since the file info may have been updated in the
+	// meantime, such as by the extraction of its content.
+	if err := a.Srv().Store().FileInfo().SetMediaPreviews(rctx, info); err != nil {
+		return false, model.NewAppError("GenerateMediaPreviews", "app.file_info.save.app_error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+	a.Srv().Store().FileInfo().InvalidateFileInfosForPostCache(info.PostId, false)
+
+	return true, nil
+}
+
+// readMediaArtwork returns the cover art embedded in a media file, if any.
+func (a *App) readMediaArtwork(info *model.FileInfo) ([]byte, *model.AppError) {
+	file, appErr := a.FileReader(info.Path)
+	if appErr != nil {
+		return nil, appErr
+	}
+	defer file.Close()
+
+	md, err := media.Probe(file)
+	if err != nil {
+		return nil, nil
+	}
+	return md.Artwork, nil
+}
+
+// decodeMediaArtwork decodes the cover art of a media file, unless it is too large.
+func (a *App) decodeMediaArtwork(rctx request.CTX, info *model.FileInfo, artwork []byte) image.Image {
+	config, _, err := a.ch.imgDecoder.DecodeConfig(bytes.NewReader(artwork))
+	if err == nil {
+		err = checkImageResolutionLimit(config.Width, config.Height, *a.Config().FileSettings.MaxImageResolution)
+	}
+	var img image.Image
+	if err == nil {
+		img, _, err = a.ch.imgDecoder.Decode(bytes.NewReader(artwork))
+	}
+	if err != nil {
+		rctx.Logger().Debug("Unable to decode cover art", mlog.String("file_id", info.Id), mlog.Err(err))
+		return nil
+	}
+	return img
+}
+
+// TranscodeMediaFile creates the web-friendly rendition of a video which can't be played by
+// all the browsers, next to its previews. It returns whether a rendition was created.
+func (a *App) TranscodeMediaFile(rctx request.CTX, info *model.FileInfo) (bool, *model.AppError) {
+	if !needsWebRendition(info) {
+		return false, nil
+	}
+	if !a.ch.ffmpeg.IsEnabled() {
+		return false, model.NewAppError("TranscodeMediaFile", "app.file.transcode_media.ffmpeg_disabled.app_error", nil, "", http.StatusNotImplemented)
+	}
+
+	if info.RenditionPath != "" {
+		exists, appErr := a.FileExists(info.RenditionPath)
+		if appErr != nil {
+			return false, appErr
+		}
+		if exists {
+			return false, nil
+		}
+	}
+
+	localPath, cleanup, appErr := a.copyFileToTemp(info.Path)
+	if appErr != nil {
+		return false, appErr
+	}
+	defer cleanup()
+
+	outFile, err := os.CreateTemp("", "rendition-*.mp4")
+	if err != nil {
+		return false, model.NewAppError("TranscodeMediaFile", "app.file.transcode_media.app_error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+	outPath := outFile.Name()
+	outFile.Close()
+	defer os.Remove(outPath)
+
+	if err = a.ch.ffmpeg.Transcode(localPath, outPath); err != nil {
+		return false, model.NewAppError("TranscodeMediaFile", "app.file.transcode_media.app_error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+
+	rendition, err := os.Open(outPath)
+	if err != nil {
+		return false, model.NewAppError("TranscodeMediaFile", "app.file.transcode_media.app_error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+	defer rendition.Close()
+
+	nameWithoutExtension := strings.TrimSuffix(info.Name, path.Ext(info.Name))
+	renditionPath := filePreviewPathPrefix(info) + nameWithoutExtension + renditionFileSuffix
+	if _, appErr = a.WriteFile(rendition, renditionPath); appErr != nil {
+		return false, appErr
+	}
+
+	if err = a.Srv().Store().FileInfo().SetRenditionPath(rctx, info.Id, renditionPath); err != nil {
+		if removeErr := a.RemoveFile(renditionPath); removeErr != nil {
+			rctx.Logger().Warn("Failed to remove rendition", mlog.String("path", renditionPath), mlog.Err(removeErr))
+		}
+		return false, model.NewAppError("TranscodeMediaFile", "app.file_info.save.app_error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+	info.RenditionPath = renditionPath
+	a.Srv().Store().FileInfo().InvalidateFileInfosForPostCache(info.PostId, false)
+
+	return true, nil
+}
+
+// copyFileToTemp copies a file from the file store to a temporary file, for the commands
+// which can only read local files. The returned function removes the temporary file.
+func (a *App) copyFileToTemp(filePath string) (string, func(), *model.AppError) {
+	file, appErr := a.FileReader(filePath)
+	if appErr != nil {
+		return "", nil, appErr
+	}
+	defer file.Close()
+
+	tmpFile, err := os.CreateTemp("", "media-*"+path.Ext(filePath))
+	if err != nil {
+		return "", nil, model.NewAppError("copyFileToTemp", "app.file.copy_to_temp.app_error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+	cleanup := func() {
+		os.Remove(tmpFile.Name())
+	}
+
+	_, err = io.Copy(tmpFile, file)
+	if closeErr := tmpFile.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		cleanup()
+		return "", nil, model.NewAppError("copyFileToTemp", "app.file.copy_to_temp.app_error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+
+	return tmpFile.Name(), cleanup, nil
+}
+
+// needsWebRendition returns true if a video may not be played by all the browsers, which
+// support H.264 videos with AAC or MP3 audio in MP4 files.
+func needsWebRendition(info *model.FileInfo) bool {
+	if !info.IsVideo() {
+		return false
+	}
+	if info.MimeType != "video/mp4" || info.VideoCodec != "h264" {
+		return true
+	}
+	switch info.AudioCodec {
+	case "", "aac", "mp3":
+		return false
+	}
+	return true
+}
+
+func ffmpegOptions(cfg *model.Config) media.FFmpegOptions {
+	if !*cfg.FileSettings.EnableFFmpeg {
+		return media.FFmpegOptions{}
+	}
+	return media.FFmpegOptions{
+		Command:          *cfg.FileSettings.FFmpegCommand,
+		Timeout:          time.Duration(*cfg.FileSettings.FFmpegTimeoutSeconds) * time.Second,
+		TranscodeTimeout: time.Duration(*cfg.FileSettings.MediaTranscodingTimeoutSeconds) * time.Second,
+	}
+}
@@ -0,0 +1,212 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package app
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+	"image/png"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost/server/public/model"
+)
+
+// createTestWAV creates a 16-bit mono WAV file lasting the given number of seconds at 8000 Hz.
+func createTestWAV(seconds int) []byte {
+	data := make([]byte, 44+seconds*8000*2)
+	copy(data, "RIFF")
+	binary.LittleEndian.PutUint32(data[4:], uint32(len(data)-8))
+	copy(data[8:], "WAVEfmt ")
+	binary.LittleEndian.PutUint32(data[16:], 16)
+	binary.LittleEndian.PutUint16(data[20:], 1)
+	binary.LittleEndian.PutUint16(data[22:], 1)
+	binary.LittleEndian.PutUint32(data[24:], 8000)
+	binary.LittleEndian.PutUint32(data[28:], 8000*2)
+	binary.LittleEndian.PutUint16(data[32:], 2)
+	binary.LittleEndian.PutUint16(data[34:], 16)
+	copy(data[36:], "data")
+	binary.LittleEndian.PutUint32(data[40:], uint32(seconds*8000*2))
+	return data
+}
+
+// createFakeFFmpegCommand writes an ffmpeg compatible command probing every file as a 10
+// seconds long HEVC video, extracting a frame from it and transcoding it into a fake MP4 file.
+func createFakeFFmpegCommand(t *testing.T) string {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("the fake ffmpeg command is a shell script")
+	}
+
+	dir := t.TempDir()
+	var frame bytes.Buffer
+	require.NoError(t, png.Encode(&frame, image.NewRGBA(image.Rect(0, 0, 64, 36))))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "frame.png"), frame.Bytes(), 0600))
+
+	script := `#!/bin/sh
+for last; do :; done
+case "$*" in
+*"image2pipe"*) cat "` + dir + `/frame.png" ;;
+*"-f mp4"*) echo "rendition" > "$last" ;;
+*) cat >&2 <<EOF
+Input #0, mov,mp4,m4a,3gp,3g2,mj2, from 'video.mov':
+  Duration: 00:00:10.00, start: 0.000000, bitrate: 8124 kb/s
+  Stream #0:0[0x1](und): Video: hevc (Main) (hvc1 / 0x31637668), yuv420p, 1280x720, 7895 kb/s, 30 fps
+  Stream #0:1[0x2](und): Audio: aac (LC) (mp4a / 0x6134706D), 44100 Hz, stereo, fltp, 174 kb/s (default)
+EOF
+exit 1 ;;
+esac
+`
+	command := filepath.Join(dir, "ffmpeg")
+	require.NoError(t, os.WriteFile(command, []byte(script), 0700))
+	return command
+}
+
+func TestGetInfoForBytesMedia(t *testing.T) {
+	data := createTestWAV(3)
+
+	info, appErr := getInfoForBytes("sound.wav", bytes.NewReader(data), len(data))
+	require.Nil(t, appErr)
+	assert.Equal(t, int64(3000), info.Duration)
+	assert.Equal(t, "pcm_s16le", info.AudioCodec)
+	assert.Empty(t, info.VideoCodec)
+	assert.False(t, info.HasPreviewImage)
+
+	// Media files in unsupported formats are still uploaded.
+	info, appErr = getInfoForBytes("sound.ogg", bytes.NewReader([]byte("OggS")), 4)
+	require.Nil(t, appErr)
+	assert.Zero(t, info.Duration)
+}
+
+func TestNeedsWebRendition(t *testing.T) {
+	for _, tc := range []struct {
+		mimeType   string
+		videoCodec string
+		audioCodec string
+		expected   bool
+	}{
+		{"video/mp4", "h264", "aac", false},
+		{"video/mp4", "h264", "", false},
+		{"video/mp4", "hevc", "aac", true},
+		{"video/mp4", "h264", "opus", true},
+		{"video/quicktime", "h264", "aac", true},
+		{"video/webm", "vp9", "opus", true},
+		{"video/mp4", "", "", true},
+		{"audio/mpeg", "", "mp3", false},
+		{"image/png", "", "", false},
+	} {
+		info := &model.FileInfo{MimeType: tc.mimeType, VideoCodec: tc.videoCodec, AudioCodec: tc.audioCodec}
+		assert.Equal(t, tc.expected, needsWebRendition(info), tc)
+	}
+}
+
+func TestGenerateMediaPreviews(t *testing.T) {
+	th := Setup(t).InitBasic()
+	defer th.TearDown()
+
+	t.Run("files without a poster are not updated", func(t *testing.T) {
+		data := createTestWAV(1)
+		info, appErr := th.App.DoUploadFile(th.Context, time.Now(), th.BasicTeam.Id, th.BasicChannel.Id, th.BasicUser.Id, "sound.wav", data, false)
+		require.Nil(t, appErr)
+		assert.Equal(t, int64(1000), info.Duration)
+
+		generated, appErr := th.App.GenerateMediaPreviews(th.Context, info)
+		require.Nil(t, appErr)
+		assert.False(t, generated)
+		assert.Empty(t, info.ThumbnailPath)
+	})
+
+	t.Run("videos are probed and get a poster with ffmpeg", func(t *testing.T) {
+		th.App.UpdateConfig(func(cfg *model.Config) {
+			*cfg.FileSettings.EnableFFmpeg = true
+			*cfg.FileSettings.FFmpegCommand = createFakeFFmpegCommand(t)
+		})
+		defer th.App.UpdateConfig(func(cfg *model.Config) {
+			*cfg.FileSettings.EnableFFmpeg = false
+			*cfg.FileSettings.FFmpegCommand = model.FileSettingsDefaultFFmpegCommand
+		})
+
+		info, appErr := th.App.DoUploadFile(th.Context, time.Now(), th.BasicTeam.Id, th.BasicChannel.Id, th.BasicUser.Id, "video.mov", []byte("video"), false)
+		require.Nil(t, appErr)
+		// The content extracted in the meantime is kept.
+		require.NoError(t, th.App.Srv().Store().FileInfo().SetContent(th.Context, info.Id, "content"))
+
+		generated, appErr := th.App.GenerateMediaPreviews(th.Context, info)
+		require.Nil(t, appErr)
+		require.True(t, generated)
+		assert.Equal(t, int64(10000), info.Duration)
+		assert.Equal(t, "hevc", info.VideoCodec)
+		assert.Equal(t, "aac", info.AudioCodec)
+		assert.Equal(t, 1280, info.Width)
+		assert.Equal(t, 720, info.Height)
+		assert.True(t, info.HasPreviewImage)
+		assert.NotNil(t, info.MiniPreview)
+
+		exists, appErr := th.App.FileExists(info.ThumbnailPath)
+		require.Nil(t, appErr)
+		assert.True(t, exists)
+
+		saved, err := th.App.Srv().Store().FileInfo().Get(info.Id)
+		require.NoError(t, err)
+		assert.Equal(t, info.PreviewPath, saved.PreviewPath)
+		assert.Equal(t, int64(10000), saved.Duration)
+		assert.Equal(t, "content", saved.Content)
+	})
+}
+
+func TestTranscodeMediaFile(t *testing.T) {
+	th := Setup(t).InitBasic()
+	defer th.TearDown()
+
+	info, appErr := th.App.DoUploadFile(th.Context, time.Now(), th.BasicTeam.Id, th.BasicChannel.Id, th.BasicUser.Id, "video.mov", []byte("video"), false)
+	require.Nil(t, appErr)
+
+	t.Run("ffmpeg is required", func(t *testing.T) {
+		_, appErr := th.App.TranscodeMediaFile(th.Context, info)
+		require.NotNil(t, appErr)
+		assert.Equal(t, "app.file.transcode_media.ffmpeg_disabled.app_error", appErr.Id)
+	})
+
+	t.Run("web-friendly videos are not transcoded", func(t *testing.T) {
+		transcoded, appErr := th.App.TranscodeMediaFile(th.Context, &model.FileInfo{MimeType: "video/mp4", VideoCodec: "h264", AudioCodec: "aac"})
+		require.Nil(t, appErr)
+		assert.False(t, transcoded)
+	})
+
+	t.Run("rendition is stored next to the video", func(t *testing.T) {
+		th.App.UpdateConfig(func(cfg *model.Config) {
+			*cfg.FileSettings.EnableFFmpeg = true
+			*cfg.FileSettings.FFmpegCommand = createFakeFFmpegCommand(t)
+		})
+		defer th.App.UpdateConfig(func(cfg *model.Config) {
+			*cfg.FileSettings.EnableFFmpeg = false
+			*cfg.FileSettings.FFmpegCommand = model.FileSettingsDefaultFFmpegCommand
+		})
+
+		transcoded, appErr := th.App.TranscodeMediaFile(th.Context, info)
+		require.Nil(t, appErr)
+		require.True(t, transcoded)
+		assert.Equal(t, filepath.Dir(info.Path)+"/video_web.mp4", info.RenditionPath)
+
+		data, appErr := th.App.ReadFile(info.RenditionPath)
+		require.Nil(t, appErr)
+		assert.Equal(t, "rendition\n", string(data))
+
+		saved, err := th.App.Srv().Store().FileInfo().Get(info.Id)
+		require.NoError(t, err)
+		assert.Equal(t, info.RenditionPath, saved.RenditionPath)
+
+		// The rendition is only created once.
+		transcoded, appErr = th.App.TranscodeMediaFile(th.Context, info)
+		require.Nil(t, appErr)
+		assert.False(t, transcoded)
+	})
+}
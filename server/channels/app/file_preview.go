@@ -0,0 +1,120 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package app
+
+import (
+	"bytes"
+	"net/http"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+	"github.com/mattermost/mattermost/server/public/shared/request"
+	"github.com/mattermost/mattermost/server/v8/channels/app/imaging"
+)
+
+// GenerateMissingFilePreviews generates the thumbnail, preview and mini preview of an image
+// whose previews were never generated, such as the HEIC photos uploaded while the image
+// converter was disabled, or are missing from the file store. It returns whether the previews
+// were generated.
+func (a *App) GenerateMissingFilePreviews(rctx request.CTX, info *model.FileInfo) (bool, *model.AppError) {
+	if !info.IsImage() || info.IsSvg() || !a.ch.imgDecoder.CanDecodeMimeType(info.MimeType) {
+		return false, nil
+	}
+
+	missing, appErr := a.filePreviewsMissing(info)
+	if appErr != nil {
+		return false, appErr
+	}
+	if !missing {
+		return false, nil
+	}
+
+	data, appErr := a.ReadFile(info.Path)
+	if appErr != nil {
+		return false, appErr
+	}
+
+	config, _, err := a.ch.imgDecoder.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return false, model.NewAppError("GenerateMissingFilePreviews", "app.file.generate_previews.decode.app_error", nil, "", http.StatusBadRequest).Wrap(err)
+	}
+	if limitErr := checkImageResolutionLimit(config.Width, config.Height, *a.Config().FileSettings.MaxImageResolution); limitErr != nil {
+		return false, model.NewAppError("GenerateMissingFilePreviews", "api.file.upload_file.large_image.app_error", map[string]any{"Filename": info.Name}, "", http.StatusBadRequest).Wrap(limitErr)
+	}
+
+	img, imgType, release, err := prepareImage(rctx, a.ch.imgDecoder, bytes.NewReader(data))
+	if err != nil {
+		return false, model.NewAppError("GenerateMissingFilePreviews", "app.file.generate_previews.decode.app_error", nil, "", http.StatusBadRequest).Wrap(err)
+	}
+	defer release()
+
+	if info.PreviewPath == "" || info.ThumbnailPath == "" {
+		pathPrefix := filePreviewPathPrefix(info)
+		nameWithoutExtension := strings.TrimSuffix(info.Name, path.Ext(info.Name))
+		info.PreviewPath = pathPrefix + nameWithoutExtension + "_preview." + getPreviewFileExt(info.MimeType, a.webPPreviewQuality())
+		info.ThumbnailPath = pathPrefix + nameWithoutExtension + "_thumb." + getFileExtFromMimeType(info.MimeType)
+	}
+
+	if err := a.generateThumbnailImage(rctx, img, imgType, info.ThumbnailPath); err != nil {
+		return false, model.NewAppError("GenerateMissingFilePreviews", "app.file.generate_previews.app_error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+	if err := a.generatePreviewImage(rctx, img, imgType, info.PreviewPath); err != nil {
+		return false, model.NewAppError("GenerateMissingFilePreviews", "app.file.generate_previews.app_error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+
+	if info.MiniPreview == nil {
+		if miniPreview, err := imaging.GenerateMiniPreviewImage(img, miniPreviewImageWidth, miniPreviewImageHeight, jpegEncQuality); err != nil {
+			rctx.Logger().Info("Unable to generate mini preview image", mlog.Err(err))
+		} else {
+			info.MiniPreview = &miniPreview
+		}
+	}
+
+	// The dimensions are those of the upright image.
+	info.Width = img.Bounds().Dx()
+	info.Height = img.Bounds().Dy()
+	// Animated GIFs are shown instead of their preview.
+	if info.MimeType != "image/gif" {
+		info.HasPreviewImage = true
+	}
+
+	if _, err := a.Srv().Store().FileInfo().Upsert(rctx, info); err != nil {
+		return false, model.NewAppError("GenerateMissingFilePreviews", "app.file_info.save.app_error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+	a.Srv().Store().FileInfo().InvalidateFileInfosForPostCache(info.PostId, false)
+
+	return true, nil
+}
+
+// filePreviewsMissing returns true if the thumbnail or the preview of an image are missing.
+func (a *App) filePreviewsMissing(info *model.FileInfo) (bool, *model.AppError) {
+	for _, previewPath := range []string{info.ThumbnailPath, info.PreviewPath} {
+		if previewPath == "" {
+			return true, nil
+		}
+		exists, appErr := a.FileExists(previewPath)
+		if appErr != nil {
+			return false, appErr
+		}
+		if !exists {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// filePreviewPathPrefix returns the directory in which the previews of a file are stored,
+// which is the directory of the file unless it was deduplicated into a blob.
+func filePreviewPathPrefix(info *model.FileInfo) string {
+	if info.ContentHash == "" {
+		return path.Dir(info.Path) + "/"
+	}
+	return time.UnixMilli(info.CreateAt).Format("20060102") +
+		"/teams/noteam/channels/" + info.ChannelId +
+		"/users/" + info.CreatorId +
+		"/" + info.Id + "/"
+}
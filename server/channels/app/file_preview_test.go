@@ -0,0 +1,92 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package app
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/v8/channels/utils/testutils"
+)
+
+func TestGenerateMissingFilePreviews(t *testing.T) {
+	th := Setup(t).InitBasic()
+	defer th.TearDown()
+
+	data, err := testutils.ReadTestFile("test.png")
+	require.NoError(t, err)
+
+	t.Run("previews are generated when missing", func(t *testing.T) {
+		info, appErr := th.App.DoUploadFile(th.Context, time.Now(), th.BasicTeam.Id, th.BasicChannel.Id, th.BasicUser.Id, "test.png", data, false)
+		require.Nil(t, appErr)
+		require.NotEmpty(t, info.PreviewPath)
+		require.NotEmpty(t, info.ThumbnailPath)
+
+		regenerated, appErr := th.App.GenerateMissingFilePreviews(th.Context, info)
+		require.Nil(t, appErr)
+		assert.False(t, regenerated)
+
+		require.Nil(t, th.App.RemoveFile(info.PreviewPath))
+
+		regenerated, appErr = th.App.GenerateMissingFilePreviews(th.Context, info)
+		require.Nil(t, appErr)
+		assert.True(t, regenerated)
+
+		exists, appErr := th.App.FileExists(info.PreviewPath)
+		require.Nil(t, appErr)
+		assert.True(t, exists)
+	})
+
+	t.Run("paths are set when empty", func(t *testing.T) {
+		info, appErr := th.App.DoUploadFile(th.Context, time.Now(), th.BasicTeam.Id, th.BasicChannel.Id, th.BasicUser.Id, "test.png", data, false)
+		require.Nil(t, appErr)
+		previewPath, thumbnailPath := info.PreviewPath, info.ThumbnailPath
+		require.Nil(t, th.App.RemoveFile(previewPath))
+		require.Nil(t, th.App.RemoveFile(thumbnailPath))
+
+		info.PreviewPath = ""
+		info.ThumbnailPath = ""
+		info.HasPreviewImage = false
+
+		regenerated, appErr := th.App.GenerateMissingFilePreviews(th.Context, info)
+		require.Nil(t, appErr)
+		assert.True(t, regenerated)
+		assert.Equal(t, previewPath, info.PreviewPath)
+		assert.Equal(t, thumbnailPath, info.ThumbnailPath)
+		assert.True(t, info.HasPreviewImage)
+
+		saved, err := th.App.Srv().Store().FileInfo().Get(info.Id)
+		require.NoError(t, err)
+		assert.Equal(t, previewPath, saved.PreviewPath)
+	})
+
+	t.Run("previews are not generated for other files", func(t *testing.T) {
+		info, appErr := th.App.DoUploadFile(th.Context, time.Now(), th.BasicTeam.Id, th.BasicChannel.Id, th.BasicUser.Id, "test.txt", []byte("text"), false)
+		require.Nil(t, appErr)
+
+		regenerated, appErr := th.App.GenerateMissingFilePreviews(th.Context, info)
+		require.Nil(t, appErr)
+		assert.False(t, regenerated)
+	})
+
+	t.Run("HEIC images require the image converter", func(t *testing.T) {
+		info := &model.FileInfo{Name: "photo.heic", MimeType: "image/heic", Path: "photo.heic"}
+
+		regenerated, appErr := th.App.GenerateMissingFilePreviews(th.Context, info)
+		require.Nil(t, appErr)
+		assert.False(t, regenerated)
+	})
+}
+
+func TestGetPreviewFileExt(t *testing.T) {
+	assert.Equal(t, "png", getPreviewFileExt("image/png", 0))
+	assert.Equal(t, "jpg", getPreviewFileExt("image/heic", 0))
+	assert.Equal(t, "webp", getPreviewFileExt("image/png", 80))
+	assert.True(t, isWebPPreviewPath("20240101/teams/noteam/channels/x/users/y/z/photo_preview.webp"))
+	assert.False(t, isWebPPreviewPath("20240101/teams/noteam/channels/x/users/y/z/photo_preview.jpg"))
+}
@@ -0,0 +1,134 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package imaging
+
+// The converter decodes and encodes the image formats unsupported by the Go image packages,
+// such as HEIC, AVIF and the WebP encoding, using a local ImageMagick compatible command run
+// as:
+//
+//	<command> <input format>:- [options] <output format>:-
+//
+// The command reads the image from its standard input and writes the converted image to its
+// standard output. The formats are always explicit so that the command never guesses them
+// from the contents of the image.
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"image"
+	"image/png"
+	"io"
+	"os/exec"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	defaultConverterTimeout = 30 * time.Second
+
+	converterMaxErrorOutputBytes = 512
+)
+
+// ConverterOptions holds configuration options for an image converter.
+type ConverterOptions struct {
+	// The ImageMagick compatible command used to convert the images. The converter is
+	// disabled when empty.
+	Command string
+	// The maximum duration of a conversion.
+	Timeout time.Duration
+}
+
+// Converter converts images using an external command.
+// This is safe to be used from multiple goroutines.
+type Converter struct {
+	opts atomic.Pointer[ConverterOptions]
+}
+
+// NewConverter creates and returns a new image converter with the given options.
+func NewConverter(opts ConverterOptions) *Converter {
+	var c Converter
+	c.SetOptions(opts)
+	return &c
+}
+
+// SetOptions updates the options of the converter.
+func (c *Converter) SetOptions(opts ConverterOptions) {
+	if opts.Timeout <= 0 {
+		opts.Timeout = defaultConverterTimeout
+	}
+	c.opts.Store(&opts)
+}
+
+// IsEnabled returns true if the converter has a command to run.
+func (c *Converter) IsEnabled() bool {
+	return c != nil && c.opts.Load().Command != ""
+}
+
+// Decode converts the given image, encoded in the given format, and returns the decoded image.
+func (c *Converter) Decode(rd io.Reader, format string) (image.Image, error) {
+	var out bytes.Buffer
+	if err := c.convert(rd, &out, format, "png", nil); err != nil {
+		return nil, err
+	}
+
+	img, err := png.Decode(&out)
+	if err != nil {
+		return nil, fmt.Errorf("imaging: failed to decode converted image: %w", err)
+	}
+	return img, nil
+}
+
+// EncodeWebP encodes the given image in WebP format and writes the data to the passed writer.
+func (c *Converter) EncodeWebP(wr io.Writer, img image.Image, quality int) error {
+	if !c.IsEnabled() {
+		return ErrConverterRequired
+	}
+
+	// The image is only an intermediate step, so its size matters less than the time taken
+	// to compress it.
+	var in bytes.Buffer
+	encoder := png.Encoder{CompressionLevel: png.BestSpeed}
+	if err := encoder.Encode(&in, img); err != nil {
+		return fmt.Errorf("imaging: failed to encode png: %w", err)
+	}
+
+	return c.convert(&in, wr, "png", "webp", []string{"-quality", strconv.Itoa(quality)})
+}
+
+func (c *Converter) convert(rd io.Reader, wr io.Writer, inFormat, outFormat string, args []string) error {
+	if !c.IsEnabled() {
+		return ErrConverterRequired
+	}
+	opts := c.opts.Load()
+
+	ctx, cancel := context.WithTimeout(context.Background(), opts.Timeout)
+	defer cancel()
+
+	cmdArgs := append([]string{inFormat + ":-"}, args...)
+	cmdArgs = append(cmdArgs, outFormat+":-")
+
+	var stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, opts.Command, cmdArgs...)
+	cmd.Stdin = rd
+	cmd.Stdout = wr
+	cmd.Stderr = &stderr
+	// Don't wait for the processes started by the command once it is killed.
+	cmd.WaitDelay = time.Second
+
+	if err := cmd.Run(); err != nil {
+		if ctx.Err() != nil {
+			return fmt.Errorf("imaging: image conversion timed out: %w", ctx.Err())
+		}
+		output := stderr.String()
+		if len(output) > converterMaxErrorOutputBytes {
+			output = output[:converterMaxErrorOutputBytes]
+		}
+		return fmt.Errorf("imaging: failed to convert %s image to %s: %s: %w", inFormat, outFormat, strings.TrimSpace(output), err)
+	}
+
+	return nil
+}
@@ -0,0 +1,199 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package imaging
+
+import (
+	"bytes"
+	"image"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost/server/v8/channels/utils/fileutils"
+)
+
+// createFakeConverterCommand writes an ImageMagick compatible command running script.
+func createFakeConverterCommand(t *testing.T, script string) string {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("the fake converter command is a shell script")
+	}
+
+	command := filepath.Join(t.TempDir(), "magick")
+	require.NoError(t, os.WriteFile(command, []byte("#!/bin/sh\n"+script+"\n"), 0700))
+	return command
+}
+
+// fakeConverterScript "decodes" HEIF images as the test PNG image, and "encodes" WebP images
+// as their quality.
+func fakeConverterScript(t *testing.T) string {
+	imgDir, ok := fileutils.FindDir("tests")
+	require.True(t, ok)
+
+	return `case "$1 $2" in
+"heic:- png:-"|"avif:- png:-") cat > /dev/null; cat "` + imgDir + `/test.png" ;;
+"png:- -quality") [ "$4" = webp:- ] || exit 1; cat > /dev/null; printf 'webp %s' "$3" ;;
+*) echo "unsupported conversion" >&2; exit 1 ;;
+esac`
+}
+
+func TestConverter(t *testing.T) {
+	t.Run("disabled", func(t *testing.T) {
+		var nilConverter *Converter
+		require.False(t, nilConverter.IsEnabled())
+
+		c := NewConverter(ConverterOptions{})
+		require.False(t, c.IsEnabled())
+
+		_, err := c.Decode(bytes.NewReader(createTestHEIF("heic", 4032, 3024, 0)), "heic")
+		require.ErrorIs(t, err, ErrConverterRequired)
+
+		err = c.EncodeWebP(&bytes.Buffer{}, image.NewRGBA(image.Rect(0, 0, 10, 10)), 80)
+		require.ErrorIs(t, err, ErrConverterRequired)
+	})
+
+	t.Run("decode", func(t *testing.T) {
+		c := NewConverter(ConverterOptions{Command: createFakeConverterCommand(t, fakeConverterScript(t))})
+		require.True(t, c.IsEnabled())
+
+		img, err := c.Decode(bytes.NewReader(createTestHEIF("heic", 4032, 3024, 0)), "heic")
+		require.NoError(t, err)
+		require.NotNil(t, img)
+	})
+
+	t.Run("encode webp", func(t *testing.T) {
+		c := NewConverter(ConverterOptions{Command: createFakeConverterCommand(t, fakeConverterScript(t))})
+
+		var buf bytes.Buffer
+		err := c.EncodeWebP(&buf, image.NewRGBA(image.Rect(0, 0, 10, 10)), 75)
+		require.NoError(t, err)
+		require.Equal(t, "webp 75", buf.String())
+	})
+
+	t.Run("failing command", func(t *testing.T) {
+		c := NewConverter(ConverterOptions{Command: createFakeConverterCommand(t, fakeConverterScript(t))})
+
+		_, err := c.Decode(bytes.NewReader([]byte("data")), "jxl")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "unsupported conversion")
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		c := NewConverter(ConverterOptions{
+			Command: createFakeConverterCommand(t, "sleep 10"),
+			Timeout: 100 * time.Millisecond,
+		})
+
+		start := time.Now()
+		_, err := c.Decode(bytes.NewReader([]byte("data")), "heic")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "timed out")
+		require.Less(t, time.Since(start), 5*time.Second)
+	})
+
+	t.Run("set options", func(t *testing.T) {
+		c := NewConverter(ConverterOptions{})
+		c.SetOptions(ConverterOptions{Command: createFakeConverterCommand(t, fakeConverterScript(t))})
+		require.True(t, c.IsEnabled())
+
+		c.SetOptions(ConverterOptions{})
+		require.False(t, c.IsEnabled())
+	})
+}
+
+func TestDecoderDecodeWithConverter(t *testing.T) {
+	imgDir, ok := fileutils.FindDir("tests")
+	require.True(t, ok)
+
+	t.Run("webp", func(t *testing.T) {
+		d, err := NewDecoder(DecoderOptions{})
+		require.NoError(t, err)
+
+		imgFile, err := os.Open(imgDir + "/testwebp.webp")
+		require.NoError(t, err)
+		defer imgFile.Close()
+
+		img, format, err := d.Decode(imgFile)
+		require.NoError(t, err)
+		require.NotNil(t, img)
+		require.Equal(t, "webp", format)
+	})
+
+	t.Run("heic without converter", func(t *testing.T) {
+		d, err := NewDecoder(DecoderOptions{})
+		require.NoError(t, err)
+
+		_, _, err = d.Decode(bytes.NewReader(createTestHEIF("heic", 4032, 3024, 0)))
+		require.ErrorIs(t, err, ErrConverterRequired)
+	})
+
+	t.Run("heic", func(t *testing.T) {
+		d, err := NewDecoder(DecoderOptions{
+			ConcurrencyLevel: 1,
+			Converter:        NewConverter(ConverterOptions{Command: createFakeConverterCommand(t, fakeConverterScript(t))}),
+		})
+		require.NoError(t, err)
+
+		img, format, release, err := d.DecodeMemBounded(bytes.NewReader(createTestHEIF("heic", 4032, 3024, 0)))
+		require.NoError(t, err)
+		defer release()
+		require.NotNil(t, img)
+		require.Equal(t, "heic", format)
+	})
+
+	t.Run("mime types", func(t *testing.T) {
+		d, err := NewDecoder(DecoderOptions{})
+		require.NoError(t, err)
+		require.True(t, d.CanDecodeMimeType("image/png"))
+		require.False(t, d.CanDecodeMimeType("image/heic"))
+		require.False(t, d.CanDecodeMimeType("image/avif"))
+
+		d, err = NewDecoder(DecoderOptions{
+			Converter: NewConverter(ConverterOptions{Command: "magick"}),
+		})
+		require.NoError(t, err)
+		require.True(t, d.CanDecodeMimeType("image/heic"))
+	})
+
+	t.Run("avif config", func(t *testing.T) {
+		d, err := NewDecoder(DecoderOptions{})
+		require.NoError(t, err)
+
+		cfg, format, err := d.DecodeConfig(bytes.NewReader(createTestHEIF("avif", 1920, 1080, 0)))
+		require.NoError(t, err)
+		require.Equal(t, "avif", format)
+		require.Equal(t, 1920, cfg.Width)
+		require.Equal(t, 1080, cfg.Height)
+	})
+}
+
+func TestEncoderEncodeWebP(t *testing.T) {
+	img := image.NewRGBA(image.Rect(0, 0, 10, 10))
+
+	t.Run("without converter", func(t *testing.T) {
+		e, err := NewEncoder(EncoderOptions{})
+		require.NoError(t, err)
+
+		err = e.EncodeWebP(&bytes.Buffer{}, img, 80)
+		require.ErrorIs(t, err, ErrConverterRequired)
+	})
+
+	t.Run("with converter", func(t *testing.T) {
+		e, err := NewEncoder(EncoderOptions{
+			ConcurrencyLevel: 1,
+			Converter:        NewConverter(ConverterOptions{Command: createFakeConverterCommand(t, fakeConverterScript(t))}),
+		})
+		require.NoError(t, err)
+
+		var buf bytes.Buffer
+		err = e.EncodeWebP(&buf, img, 80)
+		require.NoError(t, err)
+		require.Equal(t, "webp 80", buf.String())
+		require.Empty(t, e.sem)
+	})
+}
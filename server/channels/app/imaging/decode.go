@@ -4,6 +4,7 @@
 package imaging
 
 import (
+	"bufio"
 	"errors"
 	"fmt"
 	"image"
@@ -24,6 +25,9 @@ type DecoderOptions struct {
 	// The level of concurrency for the decoder. This defines a limit on the
 	// number of concurrently running encoding goroutines.
 	ConcurrencyLevel int
+	// The converter used to decode the formats unsupported by the Go image packages,
+	// such as HEIC and AVIF. These can't be decoded when nil or disabled.
+	Converter *Converter
 }
 
 func (o *DecoderOptions) validate() error {
@@ -60,7 +64,7 @@ func (d *Decoder) Decode(rd io.Reader) (img image.Image, format string, err erro
 		defer func() { <-d.sem }()
 	}
 
-	img, format, err = image.Decode(rd)
+	img, format, err = d.decode(rd)
 	if err != nil {
 		return nil, "", fmt.Errorf("imaging: failed to decode image: %w", err)
 	}
@@ -81,7 +85,7 @@ func (d *Decoder) DecodeMemBounded(rd io.Reader) (img image.Image, format string
 		}()
 	}
 
-	img, format, err = image.Decode(rd)
+	img, format, err = d.decode(rd)
 	if err != nil {
 		return nil, "", nil, fmt.Errorf("imaging: failed to decode image: %w", err)
 	}
@@ -102,6 +106,22 @@ func (d *Decoder) DecodeMemBounded(rd io.Reader) (img image.Image, format string
 	return img, format, releaseFunc, nil
 }
 
+func (d *Decoder) decode(rd io.Reader) (image.Image, string, error) {
+	br := bufio.NewReader(rd)
+	header, _ := br.Peek(heifFtypBoxSize)
+	if format := heifFormat(header); format != "" && d.opts.Converter.IsEnabled() {
+		img, err := d.opts.Converter.Decode(br, format)
+		return img, format, err
+	}
+
+	return image.Decode(br)
+}
+
+// CanDecodeMimeType returns true if the images of the given MIME type can be decoded.
+func (d *Decoder) CanDecodeMimeType(mimeType string) bool {
+	return !IsHEIFMimeType(mimeType) || d.opts.Converter.IsEnabled()
+}
+
 // DecodeConfig returns the image config for the given data.
 func (d *Decoder) DecodeConfig(rd io.Reader) (image.Config, string, error) {
 	img, format, err := image.DecodeConfig(rd)
@@ -18,6 +18,9 @@ type EncoderOptions struct {
 	// The level of concurrency for the encoder. This defines a limit on the
 	// number of concurrently running encoding goroutines.
 	ConcurrencyLevel int
+	// The converter used to encode the formats unsupported by the Go image packages,
+	// such as WebP. These can't be encoded when nil or disabled.
+	Converter *Converter
 }
 
 func (o *EncoderOptions) validate() error {
@@ -86,3 +89,20 @@ func (e *Encoder) EncodePNG(wr io.Writer, img image.Image) error {
 
 	return nil
 }
+
+// EncodeWebP encodes the given image in WebP format and writes the data to
+// the passed writer.
+func (e *Encoder) EncodeWebP(wr io.Writer, img image.Image, quality int) error {
+	if e.opts.ConcurrencyLevel > 0 {
+		e.sem <- struct{}{}
+		defer func() {
+			<-e.sem
+		}()
+	}
+
+	if err := e.opts.Converter.EncodeWebP(wr, img, quality); err != nil {
+		return fmt.Errorf("imaging: failed to encode webp: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,330 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package imaging
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"image"
+	"io"
+	"mime"
+)
+
+// HEIF images, such as the HEIC photos taken by iOS devices and the AVIF images, store their
+// pixels with video codecs the Go image packages can't decode. Their dimensions are read from
+// the container, while decoding them requires a Converter.
+
+// ErrConverterRequired is returned when decoding an image requires a converter.
+var ErrConverterRequired = errors.New("imaging: decoding this image format requires an image converter")
+
+// heifBrands maps the major brands of the HEIF files to the name of their format.
+var heifBrands = map[string]string{
+	"heic": "heic",
+	"heix": "heic",
+	"heim": "heic",
+	"heis": "heic",
+	"hevc": "heic",
+	"hevx": "heic",
+	"hevm": "heic",
+	"hevs": "heic",
+	"avif": "avif",
+	"avis": "avif",
+	"mif1": "heif",
+	"msf1": "heif",
+}
+
+// heifMimeTypes are the MIME types of the HEIF files.
+var heifMimeTypes = map[string]bool{
+	"image/heic":          true,
+	"image/heic-sequence": true,
+	"image/heif":          true,
+	"image/heif-sequence": true,
+	"image/avif":          true,
+}
+
+const (
+	// heifMaxMetaBoxSize is the maximum size of the box describing the items of a HEIF file.
+	heifMaxMetaBoxSize = 4 * 1024 * 1024
+
+	heifFtypBoxSize = 12
+)
+
+func init() {
+	for brand, format := range heifBrands {
+		image.RegisterFormat(format, "????ftyp"+brand, decodeHEIF, decodeHEIFConfig)
+	}
+
+	// These are missing from the MIME types of some systems.
+	for extension, mimeType := range map[string]string{".heic": "image/heic", ".heif": "image/heif", ".avif": "image/avif"} {
+		if mime.TypeByExtension(extension) == "" {
+			mime.AddExtensionType(extension, mimeType)
+		}
+	}
+}
+
+// IsHEIFFormat returns true if the format, as returned by the decoder, is a HEIF format.
+func IsHEIFFormat(format string) bool {
+	for _, heifFormat := range heifBrands {
+		if format == heifFormat {
+			return true
+		}
+	}
+	return false
+}
+
+// IsHEIFMimeType returns true if the MIME type is the type of a HEIF file.
+func IsHEIFMimeType(mimeType string) bool {
+	return heifMimeTypes[mimeType]
+}
+
+// heifFormat returns the HEIF format of an image given its first bytes, or an empty string if
+// the image is not a HEIF file.
+func heifFormat(header []byte) string {
+	if len(header) < heifFtypBoxSize || string(header[4:8]) != "ftyp" {
+		return ""
+	}
+	return heifBrands[string(header[8:12])]
+}
+
+func decodeHEIF(r io.Reader) (image.Image, error) {
+	return nil, ErrConverterRequired
+}
+
+// decodeHEIFConfig reads the dimensions of the primary image of a HEIF file from the
+// properties of its items, taking its rotation into account.
+func decodeHEIFConfig(r io.Reader) (image.Config, error) {
+	meta, err := readHEIFMetaBox(r)
+	if err != nil {
+		return image.Config{}, err
+	}
+
+	// The meta box is a full box, whose content starts after its version and flags.
+	if len(meta) < 4 {
+		return image.Config{}, errors.New("imaging: invalid heif meta box")
+	}
+	children, err := parseHEIFBoxes(meta[4:])
+	if err != nil {
+		return image.Config{}, err
+	}
+
+	var (
+		primaryItemID uint32
+		properties    []heifBox
+		associations  map[uint32][]int
+	)
+	for _, box := range children {
+		switch box.boxType {
+		case "pitm":
+			if primaryItemID, err = parseHEIFPrimaryItem(box.data); err != nil {
+				return image.Config{}, err
+			}
+		case "iprp":
+			iprp, err := parseHEIFBoxes(box.data)
+			if err != nil {
+				return image.Config{}, err
+			}
+			for _, child := range iprp {
+				switch child.boxType {
+				case "ipco":
+					if properties, err = parseHEIFBoxes(child.data); err != nil {
+						return image.Config{}, err
+					}
+				case "ipma":
+					if associations, err = parseHEIFPropertyAssociations(child.data); err != nil {
+						return image.Config{}, err
+					}
+				}
+			}
+		}
+	}
+
+	var width, height int
+	var rotation byte
+	for _, index := range associations[primaryItemID] {
+		// Property indices start at 1.
+		if index < 1 || index > len(properties) {
+			continue
+		}
+		property := properties[index-1]
+		switch property.boxType {
+		case "ispe":
+			if len(property.data) < 12 {
+				return image.Config{}, errors.New("imaging: invalid heif image spatial extents")
+			}
+			width = int(binary.BigEndian.Uint32(property.data[4:8]))
+			height = int(binary.BigEndian.Uint32(property.data[8:12]))
+		case "irot":
+			if len(property.data) < 1 {
+				return image.Config{}, errors.New("imaging: invalid heif image rotation")
+			}
+			rotation = property.data[0] & 0x03
+		}
+	}
+
+	if width <= 0 || height <= 0 {
+		return image.Config{}, errors.New("imaging: missing heif image dimensions")
+	}
+
+	// The image is displayed rotated by a multiple of 90 degrees.
+	if rotation%2 == 1 {
+		width, height = height, width
+	}
+
+	return image.Config{Width: width, Height: height}, nil
+}
+
+type heifBox struct {
+	boxType string
+	data    []byte
+}
+
+// readHEIFMetaBox reads the top level boxes of a HEIF file until it finds its meta box.
+func readHEIFMetaBox(r io.Reader) ([]byte, error) {
+	var header [16]byte
+	for {
+		if _, err := io.ReadFull(r, header[:8]); err != nil {
+			return nil, fmt.Errorf("imaging: heif meta box not found: %w", err)
+		}
+		size := uint64(binary.BigEndian.Uint32(header[:4]))
+		boxType := string(header[4:8])
+		headerSize := uint64(8)
+
+		switch size {
+		case 0:
+			// The box extends to the end of the file.
+			return nil, errors.New("imaging: heif meta box not found")
+		case 1:
+			if _, err := io.ReadFull(r, header[8:16]); err != nil {
+				return nil, fmt.Errorf("imaging: failed to read heif box: %w", err)
+			}
+			size = binary.BigEndian.Uint64(header[8:16])
+			headerSize = 16
+		}
+		if size < headerSize {
+			return nil, errors.New("imaging: invalid heif box size")
+		}
+
+		if boxType == "meta" {
+			if size-headerSize > heifMaxMetaBoxSize {
+				return nil, errors.New("imaging: heif meta box too large")
+			}
+			data := make([]byte, size-headerSize)
+			if _, err := io.ReadFull(r, data); err != nil {
+				return nil, fmt.Errorf("imaging: failed to read heif meta box: %w", err)
+			}
+			return data, nil
+		}
+
+		if _, err := io.CopyN(io.Discard, r, int64(size-headerSize)); err != nil {
+			return nil, fmt.Errorf("imaging: failed to read heif box: %w", err)
+		}
+	}
+}
+
+// parseHEIFBoxes splits the contents of a box into its children.
+func parseHEIFBoxes(data []byte) ([]heifBox, error) {
+	var boxes []heifBox
+	for len(data) > 0 {
+		if len(data) < 8 {
+			return nil, errors.New("imaging: truncated heif box")
+		}
+		size := uint64(binary.BigEndian.Uint32(data[:4]))
+		boxType := string(data[4:8])
+		headerSize := uint64(8)
+
+		switch size {
+		case 0:
+			size = uint64(len(data))
+		case 1:
+			if len(data) < 16 {
+				return nil, errors.New("imaging: truncated heif box")
+			}
+			size = binary.BigEndian.Uint64(data[8:16])
+			headerSize = 16
+		}
+		if size < headerSize || size > uint64(len(data)) {
+			return nil, errors.New("imaging: invalid heif box size")
+		}
+
+		boxes = append(boxes, heifBox{boxType: boxType, data: data[headerSize:size]})
+		data = data[size:]
+	}
+	return boxes, nil
+}
+
+func parseHEIFPrimaryItem(data []byte) (uint32, error) {
+	if len(data) < 4 {
+		return 0, errors.New("imaging: invalid heif primary item")
+	}
+	version := data[0]
+	data = data[4:]
+
+	if version == 0 {
+		if len(data) < 2 {
+			return 0, errors.New("imaging: invalid heif primary item")
+		}
+		return uint32(binary.BigEndian.Uint16(data)), nil
+	}
+	if len(data) < 4 {
+		return 0, errors.New("imaging: invalid heif primary item")
+	}
+	return binary.BigEndian.Uint32(data), nil
+}
+
+// parseHEIFPropertyAssociations returns the indices of the properties of each item.
+func parseHEIFPropertyAssociations(data []byte) (map[uint32][]int, error) {
+	errInvalid := errors.New("imaging: invalid heif item property associations")
+	if len(data) < 8 {
+		return nil, errInvalid
+	}
+	version := data[0]
+	largeIndices := data[3]&0x01 != 0
+	entryCount := binary.BigEndian.Uint32(data[4:8])
+	data = data[8:]
+
+	associations := map[uint32][]int{}
+	for i := uint32(0); i < entryCount; i++ {
+		var itemID uint32
+		if version < 1 {
+			if len(data) < 2 {
+				return nil, errInvalid
+			}
+			itemID = uint32(binary.BigEndian.Uint16(data))
+			data = data[2:]
+		} else {
+			if len(data) < 4 {
+				return nil, errInvalid
+			}
+			itemID = binary.BigEndian.Uint32(data)
+			data = data[4:]
+		}
+
+		if len(data) < 1 {
+			return nil, errInvalid
+		}
+		count := int(data[0])
+		data = data[1:]
+
+		for j := 0; j < count; j++ {
+			// The highest bit of each association flags the essential properties.
+			var index int
+			if largeIndices {
+				if len(data) < 2 {
+					return nil, errInvalid
+				}
+				index = int(binary.BigEndian.Uint16(data) & 0x7FFF)
+				data = data[2:]
+			} else {
+				if len(data) < 1 {
+					return nil, errInvalid
+				}
+				index = int(data[0] & 0x7F)
+				data = data[1:]
+			}
+			associations[itemID] = append(associations[itemID], index)
+		}
+	}
+	return associations, nil
+}
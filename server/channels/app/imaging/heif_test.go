@@ -0,0 +1,129 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package imaging
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func heifTestBox(boxType string, contents ...[]byte) []byte {
+	data := bytes.Join(contents, nil)
+	box := make([]byte, 8, 8+len(data))
+	binary.BigEndian.PutUint32(box, uint32(8+len(data)))
+	copy(box[4:], boxType)
+	return append(box, data...)
+}
+
+func heifTestUint32(values ...uint32) []byte {
+	data := make([]byte, 4*len(values))
+	for i, value := range values {
+		binary.BigEndian.PutUint32(data[4*i:], value)
+	}
+	return data
+}
+
+// createTestHEIF creates a HEIF file without any image data, whose primary item 2 has the
+// given size and rotation, and whose thumbnail item 1 is 320x240.
+func createTestHEIF(brand string, width, height uint32, rotation byte) []byte {
+	return bytes.Join([][]byte{
+		heifTestBox("ftyp", []byte(brand), heifTestUint32(0), []byte("mif1"), []byte(brand)),
+		heifTestBox("meta",
+			heifTestUint32(0),
+			heifTestBox("hdlr", heifTestUint32(0, 0), []byte("pict"), heifTestUint32(0, 0, 0), []byte{0}),
+			heifTestBox("pitm", heifTestUint32(0), []byte{0, 2}),
+			heifTestBox("iprp",
+				heifTestBox("ipco",
+					heifTestBox("ispe", heifTestUint32(0, 320, 240)),
+					heifTestBox("ispe", heifTestUint32(0, width, height)),
+					heifTestBox("irot", []byte{rotation}),
+				),
+				// Item 1 has the property 1, and item 2 has the essential properties 2 and 3.
+				heifTestBox("ipma", heifTestUint32(0, 2), []byte{0, 1, 1, 0x01}, []byte{0, 2, 2, 0x82, 0x83}),
+			),
+		),
+		heifTestBox("mdat", []byte("image data")),
+	}, nil)
+}
+
+func TestDecodeHEIFConfig(t *testing.T) {
+	for _, tc := range []struct {
+		name           string
+		brand          string
+		rotation       byte
+		expectedFormat string
+		expectedWidth  int
+		expectedHeight int
+	}{
+		{"heic", "heic", 0, "heic", 4032, 3024},
+		{"rotated heic", "heic", 1, "heic", 3024, 4032},
+		{"upside down heic", "heic", 2, "heic", 4032, 3024},
+		{"avif", "avif", 0, "avif", 4032, 3024},
+		{"heif", "mif1", 3, "heif", 3024, 4032},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			data := createTestHEIF(tc.brand, 4032, 3024, tc.rotation)
+
+			cfg, format, err := image.DecodeConfig(bytes.NewReader(data))
+			require.NoError(t, err)
+			require.Equal(t, tc.expectedFormat, format)
+			require.Equal(t, tc.expectedWidth, cfg.Width)
+			require.Equal(t, tc.expectedHeight, cfg.Height)
+
+			width, height, err := GetDimensions(bytes.NewReader(data))
+			require.NoError(t, err)
+			require.Equal(t, tc.expectedWidth, width)
+			require.Equal(t, tc.expectedHeight, height)
+		})
+	}
+
+	t.Run("large property indices", func(t *testing.T) {
+		data := bytes.Join([][]byte{
+			heifTestBox("ftyp", []byte("heic"), heifTestUint32(0)),
+			heifTestBox("meta",
+				heifTestUint32(0),
+				heifTestBox("pitm", heifTestUint32(0), []byte{0, 1}),
+				heifTestBox("iprp",
+					heifTestBox("ipco", heifTestBox("ispe", heifTestUint32(0, 640, 480))),
+					heifTestBox("ipma", heifTestUint32(1, 1), []byte{0, 1, 1, 0x80, 0x01}),
+				),
+			),
+		}, nil)
+
+		cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+		require.NoError(t, err)
+		require.Equal(t, 640, cfg.Width)
+		require.Equal(t, 480, cfg.Height)
+	})
+
+	t.Run("invalid files", func(t *testing.T) {
+		data := createTestHEIF("heic", 4032, 3024, 0)
+
+		_, _, err := image.DecodeConfig(bytes.NewReader(data[:len(data)/2]))
+		require.Error(t, err)
+
+		_, _, err = image.DecodeConfig(bytes.NewReader(heifTestBox("ftyp", []byte("heic"), heifTestUint32(0))))
+		require.Error(t, err)
+
+		_, _, err = image.DecodeConfig(bytes.NewReader(createTestHEIF("heic", 0, 3024, 0)))
+		require.Error(t, err)
+
+		_, _, err = image.DecodeConfig(bytes.NewReader(createTestHEIF("mp41", 4032, 3024, 0)))
+		require.ErrorIs(t, err, image.ErrFormat)
+	})
+}
+
+func TestDecodeHEIF(t *testing.T) {
+	_, format, err := image.Decode(bytes.NewReader(createTestHEIF("heic", 4032, 3024, 0)))
+	require.ErrorIs(t, err, ErrConverterRequired)
+	require.Equal(t, "heic", format)
+
+	require.True(t, IsHEIFFormat("heic"))
+	require.True(t, IsHEIFFormat("avif"))
+	require.False(t, IsHEIFFormat("webp"))
+}
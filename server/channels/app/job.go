@@ -9,6 +9,7 @@ import (
 
 	"github.com/mattermost/mattermost/server/public/model"
 	"github.com/mattermost/mattermost/server/public/shared/request"
+	"github.com/mattermost/mattermost/server/v8/channels/jobs/file_migration"
 	"github.com/mattermost/mattermost/server/v8/channels/store"
 )
 
@@ -64,6 +65,23 @@ func (a *App) CreateJob(c request.CTX, job *model.Job) (*model.Job, *model.AppEr
 	return a.Srv().Jobs.CreateJob(c, job.Type, job.Data)
 }
 
+// SealFileMigrationSettings replaces the file settings of a file migration job by their sealed
+// form, as they hold the credentials of the new backend while the job data can be read by
+// anyone allowed to read the jobs.
+func (a *App) SealFileMigrationSettings(job *model.Job) *model.AppError {
+	fileSettings, ok := job.Data["file_settings"]
+	if !ok {
+		return nil
+	}
+	sealed, err := file_migration.SealFileSettings(a.PostActionCookieSecret(), fileSettings)
+	if err != nil {
+		return model.NewAppError("SealFileMigrationSettings", "app.job.seal_file_settings.app_error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+	delete(job.Data, "file_settings")
+	job.Data["sealed_file_settings"] = sealed
+	return nil
+}
+
 func (a *App) CancelJob(c request.CTX, jobId string) *model.AppError {
 	return a.Srv().Jobs.RequestCancellation(c, jobId)
 }
@@ -95,6 +113,9 @@ func (a *App) SessionHasPermissionToCreateJob(session model.Session, job *model.
 		return a.SessionHasPermissionTo(session, model.PermissionCreateElasticsearchPostAggregationJob), model.PermissionCreateElasticsearchPostAggregationJob
 	case model.JobTypeLdapSync:
 		return a.SessionHasPermissionTo(session, model.PermissionCreateLdapSyncJob), model.PermissionCreateLdapSyncJob
+	case model.JobTypeFileMigration:
+		// The job carries the settings of the new file store, and switches to it once complete.
+		return a.SessionHasPermissionTo(session, model.PermissionManageSystem), model.PermissionManageSystem
 	case
 		model.JobTypeMigrations,
 		model.JobTypePlugins,
@@ -106,7 +127,13 @@ func (a *App) SessionHasPermissionToCreateJob(session model.Session, job *model.
 		model.JobTypeExportProcess,
 		model.JobTypeExportDelete,
 		model.JobTypeCloud,
-		model.JobTypeExtractContent:
+		model.JobTypeExtractContent,
+		model.JobTypeFileEncryption,
+		model.JobTypeFileDeduplication,
+		model.JobTypePurgeFileBlobs,
+		model.JobTypeSemanticSearchIndexing,
+		model.JobTypeRegenerateFilePreviews,
+		model.JobTypeTranscodeMedia:
 		return a.SessionHasPermissionTo(session, model.PermissionManageJobs), model.PermissionManageJobs
 	}
 
@@ -129,6 +156,8 @@ func (a *App) SessionHasPermissionToManageJob(session model.Session, job *model.
 		permission = model.PermissionManageElasticsearchPostAggregationJob
 	case model.JobTypeLdapSync:
 		permission = model.PermissionManageLdapSyncJob
+	case model.JobTypeFileMigration:
+		permission = model.PermissionManageSystem
 	case
 		model.JobTypeMigrations,
 		model.JobTypePlugins,
@@ -140,7 +169,13 @@ func (a *App) SessionHasPermissionToManageJob(session model.Session, job *model.
 		model.JobTypeExportProcess,
 		model.JobTypeExportDelete,
 		model.JobTypeCloud,
-		model.JobTypeExtractContent:
+		model.JobTypeExtractContent,
+		model.JobTypeFileEncryption,
+		model.JobTypeFileDeduplication,
+		model.JobTypePurgeFileBlobs,
+		model.JobTypeSemanticSearchIndexing,
+		model.JobTypeRegenerateFilePreviews,
+		model.JobTypeTranscodeMedia:
 		permission = model.PermissionManageJobs
 	}
 
@@ -163,6 +198,8 @@ func (a *App) SessionHasPermissionToReadJob(session model.Session, jobType strin
 		return a.SessionHasPermissionTo(session, model.PermissionReadElasticsearchPostAggregationJob), model.PermissionReadElasticsearchPostAggregationJob
 	case model.JobTypeLdapSync:
 		return a.SessionHasPermissionTo(session, model.PermissionReadLdapSyncJob), model.PermissionReadLdapSyncJob
+	case model.JobTypeFileMigration:
+		return a.SessionHasPermissionTo(session, model.PermissionManageSystem), model.PermissionManageSystem
 	case
 		model.JobTypeBlevePostIndexing,
 		model.JobTypeMigrations,
@@ -175,7 +212,13 @@ func (a *App) SessionHasPermissionToReadJob(session model.Session, jobType strin
 		model.JobTypeExportProcess,
 		model.JobTypeExportDelete,
 		model.JobTypeCloud,
-		model.JobTypeExtractContent:
+		model.JobTypeExtractContent,
+		model.JobTypeFileEncryption,
+		model.JobTypeFileDeduplication,
+		model.JobTypePurgeFileBlobs,
+		model.JobTypeSemanticSearchIndexing,
+		model.JobTypeRegenerateFilePreviews,
+		model.JobTypeTranscodeMedia:
 		return a.SessionHasPermissionTo(session, model.PermissionReadJobs), model.PermissionReadJobs
 	}
 
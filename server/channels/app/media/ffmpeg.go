@@ -0,0 +1,271 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package media
+
+// FFmpeg probes, extracts the poster frames of and transcodes the media files in all the
+// formats supported by a local ffmpeg command. The files are passed as paths, since most
+// containers can't be read from a pipe without buffering them whole.
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"image"
+	"image/png"
+	"math"
+	"os/exec"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	defaultFFmpegTimeout          = 30 * time.Second
+	defaultFFmpegTranscodeTimeout = time.Hour
+
+	ffmpegMaxErrorOutputBytes = 512
+)
+
+// ffmpegInputFormats are the demuxers the media files may be read with. The playlist demuxers,
+// such as hls, dash and concat, which open the files or URLs listed by their input, aren't
+// allowed.
+var ffmpegInputFormats = strings.Join([]string{
+	"mov", "matroska", "avi", "asf", "flv", "mpeg", "mpegts", "ogg",
+	"wav", "w64", "aiff", "caf", "mp3", "flac", "aac", "amr",
+}, ",")
+
+// ErrFFmpegRequired is returned when FFmpeg is needed but not configured.
+var ErrFFmpegRequired = errors.New("media: ffmpeg is required")
+
+var (
+	ffmpegDurationRegexp    = regexp.MustCompile(`Duration: (\d+):(\d{2}):(\d{2}(?:\.\d+)?)`)
+	ffmpegInputRegexp       = regexp.MustCompile(`Input #0, ([\w,]+), from`)
+	ffmpegVideoStreamRegexp = regexp.MustCompile(`Stream #0:\d+\S*: Video: (\w+)(.*)`)
+	ffmpegAudioStreamRegexp = regexp.MustCompile(`Stream #0:\d+\S*: Audio: (\w+)`)
+	ffmpegSizeRegexp        = regexp.MustCompile(`, (\d{2,5})x(\d{2,5})`)
+	ffmpegRotationRegexp    = regexp.MustCompile(`rotation of (-?\d+(?:\.\d+)?) degrees`)
+)
+
+// FFmpegOptions holds configuration options for FFmpeg.
+type FFmpegOptions struct {
+	// The ffmpeg command. FFmpeg is disabled when empty.
+	Command string
+	// The maximum duration of probing a file or extracting a frame.
+	Timeout time.Duration
+	// The maximum duration of transcoding a file.
+	TranscodeTimeout time.Duration
+}
+
+// FFmpeg processes media files using a local ffmpeg command.
+// This is safe to be used from multiple goroutines.
+type FFmpeg struct {
+	opts atomic.Pointer[FFmpegOptions]
+}
+
+// NewFFmpeg creates and returns a new FFmpeg runner with the given options.
+func NewFFmpeg(opts FFmpegOptions) *FFmpeg {
+	var f FFmpeg
+	f.SetOptions(opts)
+	return &f
+}
+
+// SetOptions updates the options of the runner.
+func (f *FFmpeg) SetOptions(opts FFmpegOptions) {
+	if opts.Timeout <= 0 {
+		opts.Timeout = defaultFFmpegTimeout
+	}
+	if opts.TranscodeTimeout <= 0 {
+		opts.TranscodeTimeout = defaultFFmpegTranscodeTimeout
+	}
+	f.opts.Store(&opts)
+}
+
+// IsEnabled returns true if the runner has a command to run.
+func (f *FFmpeg) IsEnabled() bool {
+	return f != nil && f.opts.Load().Command != ""
+}
+
+// Probe returns the metadata of the media file at the given path. The artwork is never set,
+// since the attached pictures are read as poster frames.
+func (f *FFmpeg) Probe(path string) (*Metadata, error) {
+	// Without any output file, ffmpeg prints the properties of the input and fails.
+	args := append([]string{"-hide_banner", "-nostdin"}, ffmpegInputArgs(path)...)
+	output, err := f.run(f.opts.Load().Timeout, args...)
+	if len(output) == 0 && err != nil {
+		return nil, err
+	}
+
+	md, parseErr := parseFFmpegOutput(output)
+	if parseErr != nil {
+		if err != nil {
+			return nil, err
+		}
+		return nil, parseErr
+	}
+	return md, nil
+}
+
+// PosterFrame returns the frame of the video at the given offset, or its cover art.
+func (f *FFmpeg) PosterFrame(path string, offset time.Duration) (image.Image, error) {
+	args := []string{"-hide_banner", "-nostdin", "-loglevel", "error"}
+	if offset > 0 {
+		// Seeking before the input is fast, as it skips to the closest key frame.
+		args = append(args, "-ss", strconv.FormatFloat(offset.Seconds(), 'f', 3, 64))
+	}
+	args = append(args, ffmpegInputArgs(path)...)
+	args = append(args, "-map", "0:v:0", "-frames:v", "1", "-f", "image2pipe", "-vcodec", "png", "-")
+
+	output, err := f.runOutput(f.opts.Load().Timeout, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	img, err := png.Decode(bytes.NewReader(output))
+	if err != nil {
+		return nil, fmt.Errorf("media: failed to decode poster frame: %w", err)
+	}
+	return img, nil
+}
+
+// Transcode converts the media file at the given path into an MP4 file at the output path,
+// with H.264 video and AAC audio, which can be played by all the browsers. The metadata is
+// moved to the start of the file so that it can be played while it is downloaded.
+func (f *FFmpeg) Transcode(inPath, outPath string) error {
+	args := append([]string{"-hide_banner", "-nostdin", "-loglevel", "error", "-y"}, ffmpegInputArgs(inPath)...)
+	args = append(args,
+		"-map", "0:v:0?", "-map", "0:a:0?",
+		"-c:v", "libx264", "-preset", "veryfast", "-crf", "23", "-pix_fmt", "yuv420p",
+		// H.264 requires even dimensions.
+		"-vf", "scale=trunc(iw/2)*2:trunc(ih/2)*2",
+		"-c:a", "aac", "-b:a", "128k",
+		"-movflags", "+faststart",
+		"-f", "mp4", outPath,
+	)
+	_, err := f.runOutput(f.opts.Load().TranscodeTimeout, args...)
+	return err
+}
+
+// ffmpegInputArgs returns the arguments reading the media file at the given path. The file can
+// only be read from the file system, and with one of the allowed demuxers, so that it can't make
+// ffmpeg read other files or URLs.
+func ffmpegInputArgs(path string) []string {
+	return []string{
+		"-protocol_whitelist", "file",
+		"-format_whitelist", ffmpegInputFormats,
+		"-i", "file:" + path,
+	}
+}
+
+// run runs the command and returns its error output.
+func (f *FFmpeg) run(timeout time.Duration, args ...string) ([]byte, error) {
+	if !f.IsEnabled() {
+		return nil, ErrFFmpegRequired
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	var stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, f.opts.Load().Command, args...)
+	cmd.Stderr = &stderr
+	// Don't wait for the processes started by the command once it is killed.
+	cmd.WaitDelay = time.Second
+
+	if err := cmd.Run(); err != nil {
+		if ctx.Err() != nil {
+			return nil, fmt.Errorf("media: ffmpeg timed out: %w", ctx.Err())
+		}
+		return stderr.Bytes(), fmt.Errorf("media: ffmpeg failed: %w", err)
+	}
+	return stderr.Bytes(), nil
+}
+
+// runOutput runs the command and returns its standard output.
+func (f *FFmpeg) runOutput(timeout time.Duration, args ...string) ([]byte, error) {
+	if !f.IsEnabled() {
+		return nil, ErrFFmpegRequired
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, f.opts.Load().Command, args...)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	cmd.WaitDelay = time.Second
+
+	if err := cmd.Run(); err != nil {
+		if ctx.Err() != nil {
+			return nil, fmt.Errorf("media: ffmpeg timed out: %w", ctx.Err())
+		}
+		output := stderr.String()
+		if len(output) > ffmpegMaxErrorOutputBytes {
+			output = output[:ffmpegMaxErrorOutputBytes]
+		}
+		return nil, fmt.Errorf("media: ffmpeg failed: %s: %w", strings.TrimSpace(output), err)
+	}
+	return stdout.Bytes(), nil
+}
+
+// parseFFmpegOutput parses the description of the input file printed by ffmpeg.
+func parseFFmpegOutput(output []byte) (*Metadata, error) {
+	text := string(output)
+
+	match := ffmpegInputRegexp.FindStringSubmatch(text)
+	if match == nil {
+		return nil, errors.New("media: ffmpeg could not read the file")
+	}
+	md := &Metadata{
+		// ffmpeg lists the names of the formats of a demuxer, as in mov,mp4,m4a,3gp,3g2,mj2.
+		Format: ffmpegFormat(match[1]),
+	}
+
+	if match := ffmpegDurationRegexp.FindStringSubmatch(text); match != nil {
+		hours, _ := strconv.Atoi(match[1])
+		minutes, _ := strconv.Atoi(match[2])
+		seconds, _ := strconv.ParseFloat(match[3], 64)
+		md.Duration = time.Duration(hours)*time.Hour + time.Duration(minutes)*time.Minute +
+			time.Duration(seconds*float64(time.Second))
+	}
+
+	for _, match := range ffmpegVideoStreamRegexp.FindAllStringSubmatch(text, -1) {
+		// The cover art of audio files is listed as a video stream.
+		if strings.Contains(match[2], "(attached pic)") {
+			continue
+		}
+		md.VideoCodec = match[1]
+		if size := ffmpegSizeRegexp.FindStringSubmatch(match[2]); size != nil {
+			md.Width, _ = strconv.Atoi(size[1])
+			md.Height, _ = strconv.Atoi(size[2])
+		}
+		break
+	}
+	if match := ffmpegRotationRegexp.FindStringSubmatch(text); match != nil && md.VideoCodec != "" {
+		rotation, _ := strconv.ParseFloat(match[1], 64)
+		if math.Mod(math.Abs(rotation), 180) == 90 {
+			md.Width, md.Height = md.Height, md.Width
+		}
+	}
+
+	if match := ffmpegAudioStreamRegexp.FindStringSubmatch(text); match != nil {
+		md.AudioCodec = match[1]
+	}
+
+	return md, nil
+}
+
+func ffmpegFormat(names string) string {
+	switch {
+	case strings.Contains(names, "mp4"):
+		return "mp4"
+	case names == "matroska,webm":
+		return "matroska"
+	}
+	name, _, _ := strings.Cut(names, ",")
+	return name
+}
@@ -0,0 +1,190 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package media
+
+import (
+	"bytes"
+	"image"
+	"image/png"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+const testFFmpegProbeOutput = `Input #0, mov,mp4,m4a,3gp,3g2,mj2, from 'video.mov':
+  Metadata:
+    major_brand     : qt
+  Duration: 00:01:02.50, start: 0.000000, bitrate: 8124 kb/s
+  Stream #0:0[0x1](und): Video: hevc (Main) (hvc1 / 0x31637668), yuv420p(tv, bt709), 1920x1080, 7895 kb/s, 29.98 fps, 30 tbr, 600 tbn (default)
+    Side data:
+      displaymatrix: rotation of -90.00 degrees
+  Stream #0:1[0x2](und): Audio: aac (LC) (mp4a / 0x6134706D), 44100 Hz, stereo, fltp, 174 kb/s (default)
+At least one output file must be specified`
+
+// createFakeFFmpegCommand writes an ffmpeg compatible command running script.
+func createFakeFFmpegCommand(t *testing.T, script string) string {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("the fake ffmpeg command is a shell script")
+	}
+
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "probe.txt"), []byte(testFFmpegProbeOutput), 0600))
+
+	var frame bytes.Buffer
+	require.NoError(t, png.Encode(&frame, image.NewRGBA(image.Rect(0, 0, 32, 18))))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "frame.png"), frame.Bytes(), 0600))
+
+	command := filepath.Join(dir, "ffmpeg")
+	require.NoError(t, os.WriteFile(command, []byte("#!/bin/sh\ndir='"+dir+"'\n"+script+"\n"), 0700))
+	return command
+}
+
+// fakeFFmpegScript prints the probe output when given a single input, writes a frame when
+// asked for an image, and writes its arguments to the output file of a transcoding.
+const fakeFFmpegScript = `for last; do :; done
+case "$*" in
+*"-i file:video.mov") cat "$dir/probe.txt" >&2; exit 1 ;;
+*"-i file:missing.mov"*) echo "missing.mov: No such file or directory" >&2; exit 1 ;;
+*"image2pipe"*) cat "$dir/frame.png" ;;
+*"-f mp4"*) echo "$*" > "$last" ;;
+*) exit 1 ;;
+esac`
+
+func TestFFmpeg(t *testing.T) {
+	t.Run("disabled", func(t *testing.T) {
+		var nilFFmpeg *FFmpeg
+		require.False(t, nilFFmpeg.IsEnabled())
+
+		f := NewFFmpeg(FFmpegOptions{})
+		require.False(t, f.IsEnabled())
+
+		_, err := f.Probe("video.mov")
+		require.ErrorIs(t, err, ErrFFmpegRequired)
+
+		_, err = f.PosterFrame("video.mov", 0)
+		require.ErrorIs(t, err, ErrFFmpegRequired)
+
+		err = f.Transcode("video.mov", "video.mp4")
+		require.ErrorIs(t, err, ErrFFmpegRequired)
+	})
+
+	t.Run("probe", func(t *testing.T) {
+		f := NewFFmpeg(FFmpegOptions{Command: createFakeFFmpegCommand(t, fakeFFmpegScript)})
+		require.True(t, f.IsEnabled())
+
+		md, err := f.Probe("video.mov")
+		require.NoError(t, err)
+		require.Equal(t, &Metadata{
+			Format:     "mp4",
+			Duration:   62500 * time.Millisecond,
+			Width:      1080,
+			Height:     1920,
+			VideoCodec: "hevc",
+			AudioCodec: "aac",
+		}, md)
+
+		_, err = f.Probe("missing.mov")
+		require.Error(t, err)
+	})
+
+	t.Run("poster frame", func(t *testing.T) {
+		f := NewFFmpeg(FFmpegOptions{Command: createFakeFFmpegCommand(t, fakeFFmpegScript)})
+
+		img, err := f.PosterFrame("video.mov", time.Second)
+		require.NoError(t, err)
+		require.Equal(t, 32, img.Bounds().Dx())
+		require.Equal(t, 18, img.Bounds().Dy())
+
+		_, err = f.PosterFrame("missing.mov", 0)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "No such file or directory")
+	})
+
+	t.Run("transcode", func(t *testing.T) {
+		f := NewFFmpeg(FFmpegOptions{Command: createFakeFFmpegCommand(t, fakeFFmpegScript)})
+
+		outPath := filepath.Join(t.TempDir(), "video_web.mp4")
+		require.NoError(t, f.Transcode("video.mov", outPath))
+
+		args, err := os.ReadFile(outPath)
+		require.NoError(t, err)
+		require.Contains(t, string(args), "-c:v libx264")
+		require.Contains(t, string(args), "-c:a aac")
+		require.Contains(t, string(args), "-movflags +faststart")
+	})
+
+	t.Run("inputs are restricted", func(t *testing.T) {
+		f := NewFFmpeg(FFmpegOptions{Command: createFakeFFmpegCommand(t, fakeFFmpegScript)})
+
+		outPath := filepath.Join(t.TempDir(), "video_web.mp4")
+		require.NoError(t, f.Transcode("video.mov", outPath))
+
+		args, err := os.ReadFile(outPath)
+		require.NoError(t, err)
+		require.Contains(t, string(args), "-protocol_whitelist file -format_whitelist mov,")
+		require.Contains(t, string(args), "-i file:video.mov")
+		for _, format := range []string{"concat", "hls", "dash"} {
+			require.NotContains(t, ffmpegInputFormats, format)
+		}
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		f := NewFFmpeg(FFmpegOptions{
+			Command: createFakeFFmpegCommand(t, "sleep 10"),
+			Timeout: 100 * time.Millisecond,
+		})
+
+		start := time.Now()
+		_, err := f.PosterFrame("video.mov", 0)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "timed out")
+		require.Less(t, time.Since(start), 5*time.Second)
+	})
+
+	t.Run("set options", func(t *testing.T) {
+		f := NewFFmpeg(FFmpegOptions{})
+		f.SetOptions(FFmpegOptions{Command: "ffmpeg"})
+		require.True(t, f.IsEnabled())
+
+		f.SetOptions(FFmpegOptions{})
+		require.False(t, f.IsEnabled())
+	})
+}
+
+func TestParseFFmpegOutput(t *testing.T) {
+	t.Run("audio with cover art", func(t *testing.T) {
+		md, err := parseFFmpegOutput([]byte(`Input #0, mp3, from 'song.mp3':
+  Duration: 00:03:25.07, start: 0.025057, bitrate: 320 kb/s
+  Stream #0:0: Audio: mp3, 44100 Hz, stereo, fltp, 320 kb/s
+  Stream #0:1: Video: mjpeg (Baseline), yuvj420p(pc, bt470bg/unknown/unknown), 500x500 [SAR 1:1 DAR 1:1], 90k tbr, 90k tbn (attached pic)`))
+		require.NoError(t, err)
+		require.Equal(t, "mp3", md.Format)
+		require.Equal(t, "mp3", md.AudioCodec)
+		require.False(t, md.HasVideo())
+		require.Zero(t, md.Width)
+		require.Equal(t, 205070*time.Millisecond, md.Duration)
+	})
+
+	t.Run("webm", func(t *testing.T) {
+		md, err := parseFFmpegOutput([]byte(`Input #0, matroska,webm, from 'clip.webm':
+  Duration: N/A, start: 0.000000, bitrate: N/A
+  Stream #0:0: Video: vp9 (Profile 0), yuv420p(tv), 640x360, SAR 1:1 DAR 16:9, 30 fps, 30 tbr, 1k tbn (default)`))
+		require.NoError(t, err)
+		require.Equal(t, "matroska", md.Format)
+		require.Equal(t, "vp9", md.VideoCodec)
+		require.Equal(t, 640, md.Width)
+		require.Equal(t, 360, md.Height)
+		require.Zero(t, md.Duration)
+	})
+
+	t.Run("invalid file", func(t *testing.T) {
+		_, err := parseFFmpegOutput([]byte("file.mp4: Invalid data found when processing input"))
+		require.Error(t, err)
+	})
+}
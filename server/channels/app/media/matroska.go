@@ -0,0 +1,412 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package media
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strings"
+	"time"
+)
+
+// The IDs of the Matroska elements read when probing a file.
+const (
+	ebmlIDHeader             = 0x1A45DFA3
+	ebmlIDDocType            = 0x4282
+	mkvIDSegment             = 0x18538067
+	mkvIDInfo                = 0x1549A966
+	mkvIDTimestampScale      = 0x2AD7B1
+	mkvIDDuration            = 0x4489
+	mkvIDTracks              = 0x1654AE6B
+	mkvIDTrackEntry          = 0xAE
+	mkvIDTrackType           = 0x83
+	mkvIDCodecID             = 0x86
+	mkvIDVideo               = 0xE0
+	mkvIDPixelWidth          = 0xB0
+	mkvIDPixelHeight         = 0xBA
+	mkvIDDisplayWidth        = 0x54B0
+	mkvIDDisplayHeight       = 0x54BA
+	mkvIDDisplayUnit         = 0x54B2
+	mkvIDAttachments         = 0x1941A469
+	mkvIDAttachedFile        = 0x61A7
+	mkvIDFileName            = 0x466E
+	mkvIDFileMediaType       = 0x4660
+	mkvIDFileData            = 0x465C
+	mkvTrackTypeVideo        = 1
+	mkvTrackTypeAudio        = 2
+	mkvDefaultTimestampScale = 1000000
+
+	// maxMatroskaElementSize is the maximum size of the elements holding the metadata of a
+	// Matroska file read in memory.
+	maxMatroskaElementSize = 16 * 1024 * 1024
+)
+
+var ebmlMagic = []byte{0x1A, 0x45, 0xDF, 0xA3}
+
+// matroskaCodecs maps the Matroska codec IDs to the names of their codecs. The codec IDs not
+// listed here are matched by prefix, such as A_AAC/MPEG4/LC.
+var matroskaCodecs = map[string]string{
+	"V_MPEG4/ISO/AVC":  "h264",
+	"V_MPEGH/ISO/HEVC": "hevc",
+	"V_MPEG4/ISO/SP":   "mpeg4",
+	"V_MPEG4/ISO/ASP":  "mpeg4",
+	"V_MPEG2":          "mpeg2video",
+	"V_VP8":            "vp8",
+	"V_VP9":            "vp9",
+	"V_AV1":            "av1",
+	"V_THEORA":         "theora",
+	"V_MJPEG":          "mjpeg",
+	"A_OPUS":           "opus",
+	"A_VORBIS":         "vorbis",
+	"A_AAC":            "aac",
+	"A_MPEG/L3":        "mp3",
+	"A_MPEG/L2":        "mp2",
+	"A_AC3":            "ac3",
+	"A_EAC3":           "eac3",
+	"A_DTS":            "dts",
+	"A_FLAC":           "flac",
+	"A_PCM/INT/LIT":    "pcm_s16le",
+	"A_PCM/FLOAT/IEEE": "pcm_f32le",
+}
+
+type ebmlElement struct {
+	id   uint64
+	data []byte
+}
+
+// readEBMLVarInt reads a variable size integer, returning its value, with the length marker
+// kept for IDs, and its length.
+func readEBMLVarInt(rd io.Reader, keepMarker bool) (uint64, int, error) {
+	var buf [8]byte
+	if _, err := io.ReadFull(rd, buf[:1]); err != nil {
+		return 0, 0, err
+	}
+	length := 1
+	for mask := byte(0x80); length <= 8 && buf[0]&mask == 0; mask >>= 1 {
+		length++
+	}
+	if length > 8 {
+		return 0, 0, errors.New("media: invalid ebml variable size integer")
+	}
+	if _, err := io.ReadFull(rd, buf[1:length]); err != nil {
+		return 0, 0, err
+	}
+
+	value := uint64(buf[0])
+	if !keepMarker {
+		value &= 0xFF >> length
+	}
+	for _, b := range buf[1:length] {
+		value = value<<8 | uint64(b)
+	}
+	return value, length, nil
+}
+
+// isEBMLUnknownSize returns true if the size read with the given length has all its bits set,
+// meaning the size of the element is unknown.
+func isEBMLUnknownSize(size uint64, length int) bool {
+	return size == 1<<(7*length)-1
+}
+
+// readEBMLHeader reads the ID and size of an element.
+func readEBMLHeader(rd io.Reader) (uint64, int64, error) {
+	id, _, err := readEBMLVarInt(rd, true)
+	if err != nil {
+		return 0, 0, err
+	}
+	size, length, err := readEBMLVarInt(rd, false)
+	if err != nil {
+		return 0, 0, err
+	}
+	if isEBMLUnknownSize(size, length) {
+		return id, -1, nil
+	}
+	if size > math.MaxInt64 {
+		return 0, 0, errors.New("media: invalid ebml element size")
+	}
+	return id, int64(size), nil
+}
+
+// ebmlElements splits the given data into elements.
+func ebmlElements(data []byte) ([]ebmlElement, error) {
+	var elements []ebmlElement
+	rd := &byteReader{data: data}
+	for rd.remaining() > 0 {
+		id, size, err := readEBMLHeader(rd)
+		if err != nil {
+			return nil, fmt.Errorf("media: invalid ebml element: %w", err)
+		}
+		if size < 0 || size > int64(rd.remaining()) {
+			return nil, fmt.Errorf("media: invalid size of ebml element %x", id)
+		}
+		elements = append(elements, ebmlElement{id: id, data: rd.next(int(size))})
+	}
+	return elements, nil
+}
+
+func ebmlUint(data []byte) uint64 {
+	var value uint64
+	for _, b := range data {
+		value = value<<8 | uint64(b)
+	}
+	return value
+}
+
+func ebmlFloat(data []byte) float64 {
+	switch len(data) {
+	case 4:
+		return float64(math.Float32frombits(binary.BigEndian.Uint32(data)))
+	case 8:
+		return math.Float64frombits(binary.BigEndian.Uint64(data))
+	}
+	return 0
+}
+
+func ebmlString(data []byte) string {
+	return strings.TrimRight(string(data), "\x00")
+}
+
+func probeMatroska(rs io.ReadSeeker) (*Metadata, error) {
+	id, size, err := readEBMLHeader(rs)
+	if err != nil || id != ebmlIDHeader || size < 0 || size > maxMatroskaElementSize {
+		return nil, errors.New("media: invalid ebml header")
+	}
+	header := make([]byte, size)
+	if _, err := io.ReadFull(rs, header); err != nil {
+		return nil, fmt.Errorf("media: failed to read ebml header: %w", err)
+	}
+	elements, err := ebmlElements(header)
+	if err != nil {
+		return nil, err
+	}
+
+	md := &Metadata{}
+	for _, element := range elements {
+		if element.id == ebmlIDDocType {
+			md.Format = ebmlString(element.data)
+		}
+	}
+	if md.Format != "matroska" && md.Format != "webm" {
+		return nil, ErrUnsupportedFormat
+	}
+
+	if id, _, err = readEBMLHeader(rs); err != nil || id != mkvIDSegment {
+		return nil, errors.New("media: matroska file without a segment")
+	}
+
+	// The metadata may be written before or after the clusters holding the media data, so
+	// the clusters are skipped unless their size is unknown, as when the file was streamed.
+	var foundTracks bool
+	for {
+		id, size, err := readEBMLHeader(rs)
+		if err != nil {
+			if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+				break
+			}
+			return nil, fmt.Errorf("media: failed to read matroska element: %w", err)
+		}
+		if size < 0 {
+			break
+		}
+
+		switch id {
+		case mkvIDInfo, mkvIDTracks, mkvIDAttachments:
+			if size > maxMatroskaElementSize {
+				if _, err := rs.Seek(size, io.SeekCurrent); err != nil {
+					return nil, fmt.Errorf("media: failed to seek: %w", err)
+				}
+				continue
+			}
+			data := make([]byte, size)
+			if _, err := io.ReadFull(rs, data); err != nil {
+				return nil, fmt.Errorf("media: failed to read matroska element: %w", err)
+			}
+			children, err := ebmlElements(data)
+			if err != nil {
+				return nil, err
+			}
+			switch id {
+			case mkvIDInfo:
+				md.Duration = parseMatroskaInfo(children)
+			case mkvIDTracks:
+				foundTracks = true
+				parseMatroskaTracks(children, md)
+			case mkvIDAttachments:
+				md.Artwork = parseMatroskaAttachments(children)
+			}
+		default:
+			if _, err := rs.Seek(size, io.SeekCurrent); err != nil {
+				return nil, fmt.Errorf("media: failed to seek: %w", err)
+			}
+		}
+	}
+
+	if !foundTracks {
+		return nil, errors.New("media: matroska file without tracks")
+	}
+	return md, nil
+}
+
+func parseMatroskaInfo(elements []ebmlElement) time.Duration {
+	scale := uint64(mkvDefaultTimestampScale)
+	var duration float64
+	for _, element := range elements {
+		switch element.id {
+		case mkvIDTimestampScale:
+			if value := ebmlUint(element.data); value > 0 {
+				scale = value
+			}
+		case mkvIDDuration:
+			duration = ebmlFloat(element.data)
+		}
+	}
+	if duration <= 0 || math.IsInf(duration, 0) || math.IsNaN(duration) {
+		return 0
+	}
+	// The duration is a number of timestamps, and the scale the duration of a timestamp in
+	// nanoseconds.
+	return time.Duration(duration * float64(scale))
+}
+
+// parseMatroskaTracks sets the codec, and size of video tracks, of the first video and audio
+// tracks.
+func parseMatroskaTracks(elements []ebmlElement, md *Metadata) {
+	for _, element := range elements {
+		if element.id != mkvIDTrackEntry {
+			continue
+		}
+		entry, err := ebmlElements(element.data)
+		if err != nil {
+			continue
+		}
+
+		var trackType uint64
+		var codecID string
+		var video []ebmlElement
+		for _, child := range entry {
+			switch child.id {
+			case mkvIDTrackType:
+				trackType = ebmlUint(child.data)
+			case mkvIDCodecID:
+				codecID = ebmlString(child.data)
+			case mkvIDVideo:
+				video, _ = ebmlElements(child.data)
+			}
+		}
+		if codecID == "" {
+			continue
+		}
+
+		switch {
+		case trackType == mkvTrackTypeVideo && md.VideoCodec == "":
+			md.VideoCodec = matroskaCodec(codecID)
+			var pixelWidth, pixelHeight, displayWidth, displayHeight, displayUnit uint64
+			for _, child := range video {
+				switch child.id {
+				case mkvIDPixelWidth:
+					pixelWidth = ebmlUint(child.data)
+				case mkvIDPixelHeight:
+					pixelHeight = ebmlUint(child.data)
+				case mkvIDDisplayWidth:
+					displayWidth = ebmlUint(child.data)
+				case mkvIDDisplayHeight:
+					displayHeight = ebmlUint(child.data)
+				case mkvIDDisplayUnit:
+					displayUnit = ebmlUint(child.data)
+				}
+			}
+			md.Width, md.Height = int(pixelWidth), int(pixelHeight)
+			// The display size only gives the aspect ratio when its unit is not pixels.
+			if displayUnit == 0 && displayWidth > 0 && displayHeight > 0 {
+				md.Width, md.Height = int(displayWidth), int(displayHeight)
+			}
+		case trackType == mkvTrackTypeAudio && md.AudioCodec == "":
+			md.AudioCodec = matroskaCodec(codecID)
+		}
+	}
+}
+
+func matroskaCodec(codecID string) string {
+	for id := codecID; id != ""; {
+		if name, ok := matroskaCodecs[id]; ok {
+			return name
+		}
+		i := strings.LastIndexByte(id, '/')
+		if i < 0 {
+			break
+		}
+		id = id[:i]
+	}
+	// The codec IDs are prefixed with the type of the track.
+	if len(codecID) > 2 && codecID[1] == '_' {
+		codecID = codecID[2:]
+	}
+	return strings.ToLower(codecID)
+}
+
+// parseMatroskaAttachments returns the first image attached to the file, preferring the ones
+// named as cover art.
+func parseMatroskaAttachments(elements []ebmlElement) []byte {
+	var artwork []byte
+	for _, element := range elements {
+		if element.id != mkvIDAttachedFile {
+			continue
+		}
+		file, err := ebmlElements(element.data)
+		if err != nil {
+			continue
+		}
+
+		var name, mediaType string
+		var data []byte
+		for _, child := range file {
+			switch child.id {
+			case mkvIDFileName:
+				name = ebmlString(child.data)
+			case mkvIDFileMediaType:
+				mediaType = ebmlString(child.data)
+			case mkvIDFileData:
+				data = child.data
+			}
+		}
+		if !strings.HasPrefix(mediaType, "image/") || len(data) == 0 || len(data) > maxArtworkSize {
+			continue
+		}
+		if strings.HasPrefix(strings.ToLower(name), "cover") {
+			return data
+		}
+		if artwork == nil {
+			artwork = data
+		}
+	}
+	return artwork
+}
+
+// byteReader reads elements from a slice without copying them.
+type byteReader struct {
+	data []byte
+	pos  int
+}
+
+func (r *byteReader) Read(p []byte) (int, error) {
+	if r.pos >= len(r.data) {
+		return 0, io.EOF
+	}
+	n := copy(p, r.data[r.pos:])
+	r.pos += n
+	return n, nil
+}
+
+func (r *byteReader) remaining() int {
+	return len(r.data) - r.pos
+}
+
+func (r *byteReader) next(n int) []byte {
+	data := r.data[r.pos : r.pos+n]
+	r.pos += n
+	return data
+}
@@ -0,0 +1,275 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package media
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+)
+
+const (
+	id3HeaderSize = 10
+	// maxID3TagSize is the maximum size of the ID3 tag, holding the cover art, read in memory.
+	maxID3TagSize = 16 * 1024 * 1024
+	// mp3FrameSearchSize is how far past the ID3 tag the first MP3 frame is looked for.
+	mp3FrameSearchSize = 64 * 1024
+)
+
+var id3Magic = []byte("ID3")
+
+// The bitrates of the MPEG Layer III frames, in kbit/s, indexed by the bitrate index of the
+// frame header.
+var (
+	mp3BitratesV1 = [16]int{0, 32, 40, 48, 56, 64, 80, 96, 112, 128, 160, 192, 224, 256, 320, 0}
+	mp3BitratesV2 = [16]int{0, 8, 16, 24, 32, 40, 48, 56, 64, 80, 96, 112, 128, 144, 160, 0}
+	mp3SampleRate = [4]int{44100, 48000, 32000, 0}
+)
+
+// mp3FrameHeader is the header of an MPEG Layer III frame.
+type mp3FrameHeader struct {
+	// The MPEG version: 1 for MPEG-1, 2 for MPEG-2 and 2.5 for MPEG-2.5.
+	version    float64
+	bitrate    int
+	sampleRate int
+	mono       bool
+}
+
+func isMP3FrameHeader(header []byte) bool {
+	_, ok := parseMP3FrameHeader(header)
+	return ok
+}
+
+func parseMP3FrameHeader(header []byte) (mp3FrameHeader, bool) {
+	if len(header) < 4 || header[0] != 0xFF || header[1]&0xE0 != 0xE0 {
+		return mp3FrameHeader{}, false
+	}
+
+	var h mp3FrameHeader
+	switch (header[1] >> 3) & 0x03 {
+	case 0:
+		h.version = 2.5
+	case 2:
+		h.version = 2
+	case 3:
+		h.version = 1
+	default:
+		return mp3FrameHeader{}, false
+	}
+	// Only Layer III is supported.
+	if (header[1]>>1)&0x03 != 1 {
+		return mp3FrameHeader{}, false
+	}
+
+	bitrateIndex := header[2] >> 4
+	if h.version == 1 {
+		h.bitrate = mp3BitratesV1[bitrateIndex] * 1000
+	} else {
+		h.bitrate = mp3BitratesV2[bitrateIndex] * 1000
+	}
+	h.sampleRate = mp3SampleRate[(header[2]>>2)&0x03]
+	if h.bitrate == 0 || h.sampleRate == 0 {
+		return mp3FrameHeader{}, false
+	}
+	switch h.version {
+	case 2:
+		h.sampleRate /= 2
+	case 2.5:
+		h.sampleRate /= 4
+	}
+	h.mono = header[3]>>6 == 3
+	return h, true
+}
+
+func (h mp3FrameHeader) samplesPerFrame() int {
+	if h.version == 1 {
+		return 1152
+	}
+	return 576
+}
+
+// xingOffset returns the offset of the Xing header from the start of the frame, which follows
+// the side information of the frame.
+func (h mp3FrameHeader) xingOffset() int {
+	switch {
+	case h.version == 1 && !h.mono:
+		return 4 + 32
+	case h.version == 1 || !h.mono:
+		return 4 + 17
+	default:
+		return 4 + 9
+	}
+}
+
+func probeMP3(rs io.ReadSeeker) (*Metadata, error) {
+	md := &Metadata{Format: "mp3", AudioCodec: "mp3"}
+
+	var audioStart int64
+	header := make([]byte, id3HeaderSize)
+	if _, err := io.ReadFull(rs, header); err != nil {
+		return nil, fmt.Errorf("media: failed to read mp3 header: %w", err)
+	}
+	if bytes.HasPrefix(header, id3Magic) {
+		size := int64(syncsafeUint32(header[6:]))
+		audioStart = id3HeaderSize + size
+		// The tag may be followed by a footer.
+		if header[5]&0x10 != 0 {
+			audioStart += id3HeaderSize
+		}
+		if size <= maxID3TagSize {
+			tag := make([]byte, size)
+			if _, err := io.ReadFull(rs, tag); err != nil {
+				return nil, fmt.Errorf("media: failed to read id3 tag: %w", err)
+			}
+			md.Artwork = parseID3Artwork(header[3], header[5], tag)
+		}
+	}
+
+	if _, err := rs.Seek(audioStart, io.SeekStart); err != nil {
+		return nil, fmt.Errorf("media: failed to seek: %w", err)
+	}
+	data, err := io.ReadAll(io.LimitReader(rs, mp3FrameSearchSize))
+	if err != nil {
+		return nil, fmt.Errorf("media: failed to read mp3 frames: %w", err)
+	}
+
+	for i := 0; i+4 <= len(data); i++ {
+		frame, ok := parseMP3FrameHeader(data[i:])
+		if !ok {
+			continue
+		}
+
+		frames := mp3VBRFrameCount(frame, data[i:])
+		if frames > 0 {
+			samples := int64(frames) * int64(frame.samplesPerFrame())
+			md.Duration = time.Duration(samples) * time.Second / time.Duration(frame.sampleRate)
+			return md, nil
+		}
+
+		// Without a VBR header, the bitrate of the first frame is assumed to be constant.
+		end, err := rs.Seek(0, io.SeekEnd)
+		if err != nil {
+			return nil, fmt.Errorf("media: failed to seek: %w", err)
+		}
+		audioSize := end - audioStart - int64(i)
+		md.Duration = time.Duration(float64(audioSize*8) / float64(frame.bitrate) * float64(time.Second))
+		return md, nil
+	}
+
+	return nil, errors.New("media: mp3 file without frames")
+}
+
+// mp3VBRFrameCount returns the number of frames of a variable bitrate file from the Xing or
+// VBRI header of its first frame, or 0.
+func mp3VBRFrameCount(frame mp3FrameHeader, data []byte) uint32 {
+	if offset := frame.xingOffset(); len(data) >= offset+12 {
+		tag := string(data[offset : offset+4])
+		flags := binary.BigEndian.Uint32(data[offset+4:])
+		if (tag == "Xing" || tag == "Info") && flags&0x01 != 0 {
+			return binary.BigEndian.Uint32(data[offset+8:])
+		}
+	}
+	// The VBRI header always follows 32 bytes of side information.
+	if offset := 4 + 32; len(data) >= offset+18 && string(data[offset:offset+4]) == "VBRI" {
+		return binary.BigEndian.Uint32(data[offset+14:])
+	}
+	return 0
+}
+
+// parseID3Artwork returns the picture of the APIC frame of an ID3v2.3 or ID3v2.4 tag,
+// preferring the front cover.
+func parseID3Artwork(version, flags byte, tag []byte) []byte {
+	if version != 3 && version != 4 {
+		return nil
+	}
+	// The tag may be unsynchronised to avoid false frame syncs, which is rare as it was only
+	// useful to old players.
+	if flags&0x80 != 0 {
+		tag = bytes.ReplaceAll(tag, []byte{0xFF, 0x00}, []byte{0xFF})
+	}
+	// Skip the extended header.
+	if flags&0x40 != 0 && len(tag) >= 4 {
+		size := int(binary.BigEndian.Uint32(tag))
+		if version == 4 {
+			size = int(syncsafeUint32(tag))
+		} else {
+			size += 4
+		}
+		if size > len(tag) {
+			return nil
+		}
+		tag = tag[size:]
+	}
+
+	var artwork []byte
+	for len(tag) >= 10 && tag[0] != 0 {
+		frameID := string(tag[:4])
+		size := int(binary.BigEndian.Uint32(tag[4:]))
+		if version == 4 {
+			size = int(syncsafeUint32(tag[4:]))
+		}
+		if size < 0 || size > len(tag)-10 {
+			break
+		}
+		frame := tag[10 : 10+size]
+		tag = tag[10+size:]
+
+		if frameID != "APIC" {
+			continue
+		}
+		pictureType, picture := parseID3Picture(frame)
+		if len(picture) == 0 || len(picture) > maxArtworkSize {
+			continue
+		}
+		// The picture type 3 is the front cover.
+		if pictureType == 3 {
+			return picture
+		}
+		if artwork == nil {
+			artwork = picture
+		}
+	}
+	return artwork
+}
+
+// parseID3Picture returns the type and data of the picture of an APIC frame.
+func parseID3Picture(frame []byte) (byte, []byte) {
+	if len(frame) < 2 {
+		return 0, nil
+	}
+	encoding := frame[0]
+	frame = frame[1:]
+
+	// The MIME type is always a Latin-1 string.
+	i := bytes.IndexByte(frame, 0)
+	if i < 0 || i+2 > len(frame) {
+		return 0, nil
+	}
+	pictureType := frame[i+1]
+	frame = frame[i+2:]
+
+	// The description is terminated according to its encoding.
+	if encoding == 1 || encoding == 2 {
+		for i = 0; i+1 < len(frame); i += 2 {
+			if frame[i] == 0 && frame[i+1] == 0 {
+				return pictureType, frame[i+2:]
+			}
+		}
+		return 0, nil
+	}
+	i = bytes.IndexByte(frame, 0)
+	if i < 0 {
+		return 0, nil
+	}
+	return pictureType, frame[i+1:]
+}
+
+// syncsafeUint32 decodes the 28-bit integers of the ID3 tags, whose bytes have their most
+// significant bit cleared.
+func syncsafeUint32(data []byte) uint32 {
+	return uint32(data[0]&0x7F)<<21 | uint32(data[1]&0x7F)<<14 | uint32(data[2]&0x7F)<<7 | uint32(data[3]&0x7F)
+}
@@ -0,0 +1,304 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package media
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// maxMP4MovieBoxSize is the maximum size of the moov box, holding the metadata of an MP4 file,
+// read in memory. The sample tables make it grow with the duration of the file.
+const maxMP4MovieBoxSize = 64 * 1024 * 1024
+
+// mp4TopLevelBoxes are the box types starting an MP4 or QuickTime file. The ftyp box is
+// mandatory in MP4 files, but old QuickTime files may start with any other top-level box.
+var mp4TopLevelBoxes = map[string]bool{
+	"ftyp": true,
+	"moov": true,
+	"mdat": true,
+	"wide": true,
+	"free": true,
+	"skip": true,
+}
+
+// mp4Codecs maps the sample entry types of the MP4 tracks to the names of their codecs.
+var mp4Codecs = map[string]string{
+	"avc1": "h264",
+	"avc3": "h264",
+	"hvc1": "hevc",
+	"hev1": "hevc",
+	"vp08": "vp8",
+	"vp09": "vp9",
+	"av01": "av1",
+	"mp4v": "mpeg4",
+	"jpeg": "mjpeg",
+	"apch": "prores",
+	"apcn": "prores",
+	"apcs": "prores",
+	"apco": "prores",
+	"ap4h": "prores",
+	"mp4a": "aac",
+	"Opus": "opus",
+	"fLaC": "flac",
+	"alac": "alac",
+	"ac-3": "ac3",
+	"ec-3": "eac3",
+	".mp3": "mp3",
+	"sowt": "pcm_s16le",
+	"twos": "pcm_s16be",
+}
+
+type mp4Box struct {
+	boxType string
+	data    []byte
+}
+
+func isMP4(header []byte) bool {
+	return len(header) >= 8 && mp4TopLevelBoxes[string(header[4:8])]
+}
+
+// mp4Boxes splits the given data into boxes.
+func mp4Boxes(data []byte) ([]mp4Box, error) {
+	var boxes []mp4Box
+	for len(data) > 0 {
+		if len(data) < 8 {
+			return nil, errors.New("media: truncated mp4 box header")
+		}
+		size := uint64(binary.BigEndian.Uint32(data))
+		boxType := string(data[4:8])
+		headerSize := uint64(8)
+		switch size {
+		case 0:
+			size = uint64(len(data))
+		case 1:
+			if len(data) < 16 {
+				return nil, errors.New("media: truncated mp4 box header")
+			}
+			size = binary.BigEndian.Uint64(data[8:])
+			headerSize = 16
+		}
+		if size < headerSize || size > uint64(len(data)) {
+			return nil, fmt.Errorf("media: invalid size of mp4 box %q", boxType)
+		}
+		boxes = append(boxes, mp4Box{boxType: boxType, data: data[headerSize:size]})
+		data = data[size:]
+	}
+	return boxes, nil
+}
+
+// mp4Child returns the data of the first child box of the given type, or nil.
+func mp4Child(data []byte, boxType string) []byte {
+	boxes, err := mp4Boxes(data)
+	if err != nil {
+		return nil
+	}
+	for _, box := range boxes {
+		if box.boxType == boxType {
+			return box.data
+		}
+	}
+	return nil
+}
+
+// mp4Path returns the data of the first box found following the given box types, or nil.
+func mp4Path(data []byte, boxTypes ...string) []byte {
+	for _, boxType := range boxTypes {
+		if data = mp4Child(data, boxType); data == nil {
+			return nil
+		}
+	}
+	return data
+}
+
+// readMP4MovieBox finds the moov box among the top-level boxes of the file, which may follow
+// the media data, and returns its contents.
+func readMP4MovieBox(rs io.ReadSeeker) ([]byte, error) {
+	header := make([]byte, 16)
+	for {
+		if _, err := io.ReadFull(rs, header[:8]); err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil, errors.New("media: mp4 file without a moov box")
+			}
+			return nil, fmt.Errorf("media: failed to read mp4 box header: %w", err)
+		}
+		size := int64(binary.BigEndian.Uint32(header))
+		boxType := string(header[4:8])
+		headerSize := int64(8)
+		switch size {
+		case 0:
+			// The box extends to the end of the file.
+			if boxType != "moov" {
+				return nil, errors.New("media: mp4 file without a moov box")
+			}
+			return readAll(rs, maxMP4MovieBoxSize)
+		case 1:
+			if _, err := io.ReadFull(rs, header[8:16]); err != nil {
+				return nil, fmt.Errorf("media: failed to read mp4 box header: %w", err)
+			}
+			size = int64(binary.BigEndian.Uint64(header[8:]))
+			headerSize = 16
+		}
+		if size < headerSize {
+			return nil, fmt.Errorf("media: invalid size of mp4 box %q", boxType)
+		}
+
+		if boxType == "moov" {
+			if size-headerSize > maxMP4MovieBoxSize {
+				return nil, errors.New("media: mp4 moov box too large")
+			}
+			data := make([]byte, size-headerSize)
+			if _, err := io.ReadFull(rs, data); err != nil {
+				return nil, fmt.Errorf("media: failed to read mp4 moov box: %w", err)
+			}
+			return data, nil
+		}
+
+		if _, err := rs.Seek(size-headerSize, io.SeekCurrent); err != nil {
+			return nil, fmt.Errorf("media: failed to seek: %w", err)
+		}
+	}
+}
+
+func readAll(rd io.Reader, limit int64) ([]byte, error) {
+	data, err := io.ReadAll(io.LimitReader(rd, limit+1))
+	if err != nil {
+		return nil, fmt.Errorf("media: failed to read: %w", err)
+	}
+	if int64(len(data)) > limit {
+		return nil, errors.New("media: metadata too large")
+	}
+	return data, nil
+}
+
+func probeMP4(rs io.ReadSeeker) (*Metadata, error) {
+	moov, err := readMP4MovieBox(rs)
+	if err != nil {
+		return nil, err
+	}
+
+	boxes, err := mp4Boxes(moov)
+	if err != nil {
+		return nil, err
+	}
+
+	md := &Metadata{Format: "mp4"}
+	for _, box := range boxes {
+		switch box.boxType {
+		case "mvhd":
+			md.Duration = parseMP4MovieHeader(box.data)
+		case "trak":
+			parseMP4Track(box.data, md)
+		case "udta":
+			md.Artwork = parseMP4Artwork(box.data)
+		}
+	}
+
+	if md.Duration == 0 && md.VideoCodec == "" && md.AudioCodec == "" {
+		return nil, errors.New("media: mp4 file without tracks")
+	}
+	return md, nil
+}
+
+// parseMP4MovieHeader returns the duration of the movie from its mvhd box.
+func parseMP4MovieHeader(data []byte) time.Duration {
+	var timescale, duration uint64
+	switch {
+	case len(data) >= 32 && data[0] == 1:
+		timescale = uint64(binary.BigEndian.Uint32(data[20:]))
+		duration = binary.BigEndian.Uint64(data[24:])
+	case len(data) >= 20 && data[0] == 0:
+		timescale = uint64(binary.BigEndian.Uint32(data[12:]))
+		duration = uint64(binary.BigEndian.Uint32(data[16:]))
+	}
+	return scaleDuration(duration, timescale)
+}
+
+// parseMP4Track sets the codec, and size of video tracks, of the first video and audio
+// tracks.
+func parseMP4Track(trak []byte, md *Metadata) {
+	handler := mp4Path(trak, "mdia", "hdlr")
+	if len(handler) < 12 {
+		return
+	}
+
+	var codec string
+	if stsd := mp4Path(trak, "mdia", "minf", "stbl", "stsd"); len(stsd) >= 16 {
+		// The version, flags and number of entries precede the first sample entry.
+		sampleEntryType := string(stsd[12:16])
+		if name, ok := mp4Codecs[sampleEntryType]; ok {
+			codec = name
+		} else {
+			codec = strings.ToLower(strings.TrimSpace(sampleEntryType))
+		}
+	}
+	if codec == "" {
+		return
+	}
+
+	switch string(handler[8:12]) {
+	case "vide":
+		if md.VideoCodec != "" {
+			return
+		}
+		md.VideoCodec = codec
+		md.Width, md.Height = parseMP4TrackHeader(mp4Child(trak, "tkhd"))
+	case "soun":
+		if md.AudioCodec == "" {
+			md.AudioCodec = codec
+		}
+	}
+}
+
+// parseMP4TrackHeader returns the display size of a track from its tkhd box, taking the
+// rotation of the track into account.
+func parseMP4TrackHeader(data []byte) (int, int) {
+	// The transformation matrix and the size follow the version specific fields.
+	offset := 40
+	if len(data) > 0 && data[0] == 1 {
+		offset = 52
+	}
+	if len(data) < offset+44 {
+		return 0, 0
+	}
+
+	// The size is a 16.16 fixed-point number.
+	width := int(binary.BigEndian.Uint32(data[offset+36:]) >> 16)
+	height := int(binary.BigEndian.Uint32(data[offset+40:]) >> 16)
+
+	// The first coefficient of the matrix is zero when the track is rotated by 90 or 270
+	// degrees.
+	if binary.BigEndian.Uint32(data[offset:]) == 0 {
+		width, height = height, width
+	}
+	return width, height
+}
+
+// parseMP4Artwork returns the cover art from the iTunes metadata of the udta box, if any.
+func parseMP4Artwork(udta []byte) []byte {
+	meta := mp4Child(udta, "meta")
+	// The meta box is a full box, with a version and flags.
+	if len(meta) < 4 {
+		return nil
+	}
+	data := mp4Path(meta[4:], "ilst", "covr", "data")
+	// The type and locale of the value precede the image.
+	if len(data) <= 8 || len(data)-8 > maxArtworkSize {
+		return nil
+	}
+	return data[8:]
+}
+
+func scaleDuration(duration, timescale uint64) time.Duration {
+	if timescale == 0 || duration == 0 || duration == 1<<64-1 || duration == 1<<32-1 {
+		return 0
+	}
+	seconds := duration / timescale
+	remainder := duration % timescale
+	return time.Duration(seconds)*time.Second + time.Duration(remainder*uint64(time.Second)/timescale)
+}
@@ -0,0 +1,76 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package media
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+)
+
+const (
+	// maxArtworkSize is the maximum size of the cover art read from a media file.
+	maxArtworkSize = 10 * 1024 * 1024
+
+	probeHeaderSize = 12
+)
+
+// ErrUnsupportedFormat is returned when probing a media file whose container format can't be
+// parsed without FFmpeg.
+var ErrUnsupportedFormat = errors.New("media: unsupported format")
+
+// Metadata holds the properties of a video or audio file. The codecs are named after the
+// FFmpeg decoders, so that the values don't depend on how the file was probed.
+type Metadata struct {
+	// The container format of the file, such as "mp4", "matroska", "webm", "wav" or "mp3".
+	Format   string
+	Duration time.Duration
+	// The display size of the first video track, if any.
+	Width  int
+	Height int
+	// The codecs of the first video and audio tracks, if any.
+	VideoCodec string
+	AudioCodec string
+	// The cover art embedded in the file, if any. This is usually a JPEG or PNG image.
+	Artwork []byte
+}
+
+// HasVideo returns true if the media file has a video track.
+func (m *Metadata) HasVideo() bool {
+	return m.VideoCodec != ""
+}
+
+// Probe reads the metadata of a media file in one of the common container formats: MP4 and
+// QuickTime, Matroska and WebM, WAV and MP3. It only reads the parts of the file holding the
+// metadata.
+func Probe(rs io.ReadSeeker) (*Metadata, error) {
+	header := make([]byte, probeHeaderSize)
+	n, err := io.ReadFull(rs, header)
+	if err != nil && !errors.Is(err, io.EOF) && !errors.Is(err, io.ErrUnexpectedEOF) {
+		return nil, fmt.Errorf("media: failed to read header: %w", err)
+	}
+	header = header[:n]
+
+	if _, err := rs.Seek(0, io.SeekStart); err != nil {
+		return nil, fmt.Errorf("media: failed to seek: %w", err)
+	}
+
+	var probe func(io.ReadSeeker) (*Metadata, error)
+	switch {
+	case isMP4(header):
+		probe = probeMP4
+	case bytes.HasPrefix(header, ebmlMagic):
+		probe = probeMatroska
+	case len(header) >= 12 && string(header[:4]) == "RIFF" && string(header[8:12]) == "WAVE":
+		probe = probeWAV
+	case bytes.HasPrefix(header, id3Magic) || isMP3FrameHeader(header):
+		probe = probeMP3
+	default:
+		return nil, ErrUnsupportedFormat
+	}
+
+	return probe(rs)
+}
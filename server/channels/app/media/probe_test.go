@@ -0,0 +1,299 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package media
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+var testArtwork = []byte("\x89PNG\r\n\x1a\nartwork")
+
+func mp4TestBox(boxType string, contents ...[]byte) []byte {
+	data := bytes.Join(contents, nil)
+	box := make([]byte, 8, 8+len(data))
+	binary.BigEndian.PutUint32(box, uint32(8+len(data)))
+	copy(box[4:], boxType)
+	return append(box, data...)
+}
+
+func testUint32(values ...uint32) []byte {
+	data := make([]byte, 4*len(values))
+	for i, value := range values {
+		binary.BigEndian.PutUint32(data[4*i:], value)
+	}
+	return data
+}
+
+func mp4TestTrack(handler, sampleEntryType string, width, height uint32, rotated bool) []byte {
+	// The version 0 track header, with its matrix, width and height.
+	tkhd := testUint32(0, 0, 0, 1, 0, 0, 0, 0, 0, 0)
+	matrix := testUint32(0x10000, 0, 0, 0, 0x10000, 0, 0, 0, 0x40000000)
+	if rotated {
+		matrix = testUint32(0, 0x10000, 0, 0xFFFF0000, 0, 0, 0, 0, 0x40000000)
+	}
+	tkhd = append(tkhd, matrix...)
+	tkhd = append(tkhd, testUint32(width<<16, height<<16)...)
+
+	return mp4TestBox("trak",
+		mp4TestBox("tkhd", tkhd),
+		mp4TestBox("mdia",
+			mp4TestBox("hdlr", testUint32(0, 0), []byte(handler), testUint32(0, 0, 0), []byte{0}),
+			mp4TestBox("minf",
+				mp4TestBox("stbl",
+					mp4TestBox("stsd", testUint32(0, 1), mp4TestBox(sampleEntryType, make([]byte, 8))),
+				),
+			),
+		),
+	)
+}
+
+// createTestMP4 creates an MP4 file without any media data, whose movie lasts 12.5 seconds.
+func createTestMP4(moovLast bool, tracks ...[]byte) []byte {
+	moov := mp4TestBox("moov",
+		mp4TestBox("mvhd", testUint32(0, 0, 0, 1000, 12500)),
+		bytes.Join(tracks, nil),
+		mp4TestBox("udta",
+			mp4TestBox("meta", testUint32(0),
+				mp4TestBox("ilst",
+					mp4TestBox("covr", mp4TestBox("data", testUint32(14, 0), testArtwork)),
+				),
+			),
+		),
+	)
+	ftyp := mp4TestBox("ftyp", []byte("isom"), testUint32(0), []byte("isomavc1"))
+	mdat := mp4TestBox("mdat", []byte("media data"))
+	if moovLast {
+		return bytes.Join([][]byte{ftyp, mdat, moov}, nil)
+	}
+	return bytes.Join([][]byte{ftyp, moov, mdat}, nil)
+}
+
+func ebmlTestElement(id uint64, contents ...[]byte) []byte {
+	var idBytes []byte
+	for v := id; v > 0; v >>= 8 {
+		idBytes = append([]byte{byte(v)}, idBytes...)
+	}
+	data := bytes.Join(contents, nil)
+	// The sizes are always written on 8 bytes.
+	size := make([]byte, 8)
+	binary.BigEndian.PutUint64(size, uint64(len(data)))
+	size[0] = 0x01
+	return bytes.Join([][]byte{idBytes, size, data}, nil)
+}
+
+func ebmlTestUint(id, value uint64) []byte {
+	data := make([]byte, 8)
+	binary.BigEndian.PutUint64(data, value)
+	return ebmlTestElement(id, data)
+}
+
+// createTestMatroska creates a WebM file without any media data, lasting 12.5 seconds and
+// holding a 1280x720 VP9 video track, an Opus audio track and a cover.
+func createTestMatroska(docType string) []byte {
+	duration := make([]byte, 8)
+	binary.BigEndian.PutUint64(duration, math.Float64bits(12500))
+
+	return bytes.Join([][]byte{
+		ebmlTestElement(ebmlIDHeader, ebmlTestElement(ebmlIDDocType, []byte(docType))),
+		ebmlTestElement(mkvIDSegment,
+			ebmlTestElement(mkvIDInfo,
+				ebmlTestUint(mkvIDTimestampScale, 1000000),
+				ebmlTestElement(mkvIDDuration, duration),
+			),
+			ebmlTestElement(mkvIDTracks,
+				ebmlTestElement(mkvIDTrackEntry,
+					ebmlTestUint(mkvIDTrackType, mkvTrackTypeAudio),
+					ebmlTestElement(mkvIDCodecID, []byte("A_OPUS")),
+				),
+				ebmlTestElement(mkvIDTrackEntry,
+					ebmlTestUint(mkvIDTrackType, mkvTrackTypeVideo),
+					ebmlTestElement(mkvIDCodecID, []byte("V_VP9")),
+					ebmlTestElement(mkvIDVideo,
+						ebmlTestUint(mkvIDPixelWidth, 1280),
+						ebmlTestUint(mkvIDPixelHeight, 720),
+					),
+				),
+			),
+			// The clusters are skipped.
+			ebmlTestElement(0x1F43B675, []byte("media data")),
+			ebmlTestElement(mkvIDAttachments,
+				ebmlTestElement(mkvIDAttachedFile,
+					ebmlTestElement(mkvIDFileName, []byte("font.ttf")),
+					ebmlTestElement(mkvIDFileMediaType, []byte("font/ttf")),
+					ebmlTestElement(mkvIDFileData, []byte("font")),
+				),
+				ebmlTestElement(mkvIDAttachedFile,
+					ebmlTestElement(mkvIDFileName, []byte("cover.png")),
+					ebmlTestElement(mkvIDFileMediaType, []byte("image/png")),
+					ebmlTestElement(mkvIDFileData, testArtwork),
+				),
+			),
+		),
+	}, nil)
+}
+
+// createTestWAV creates a 16-bit stereo WAV file lasting 2 seconds at 8000 Hz.
+func createTestWAV() []byte {
+	fmtChunk := make([]byte, 16)
+	binary.LittleEndian.PutUint16(fmtChunk, wavFormatPCM)
+	binary.LittleEndian.PutUint16(fmtChunk[2:], 2)
+	binary.LittleEndian.PutUint32(fmtChunk[4:], 8000)
+	binary.LittleEndian.PutUint32(fmtChunk[8:], 8000*4)
+	binary.LittleEndian.PutUint16(fmtChunk[12:], 4)
+	binary.LittleEndian.PutUint16(fmtChunk[14:], 16)
+
+	chunk := func(chunkType string, data []byte) []byte {
+		header := make([]byte, 8)
+		copy(header, chunkType)
+		binary.LittleEndian.PutUint32(header[4:], uint32(len(data)))
+		return append(header, data...)
+	}
+
+	chunks := bytes.Join([][]byte{
+		chunk("LIST", []byte("INFO")),
+		chunk("fmt ", fmtChunk),
+		chunk("data", make([]byte, 2*8000*4)),
+	}, nil)
+	header := make([]byte, 12)
+	copy(header, "RIFF")
+	binary.LittleEndian.PutUint32(header[4:], uint32(4+len(chunks)))
+	copy(header[8:], "WAVE")
+	return append(header, chunks...)
+}
+
+// createTestMP3 creates an MP3 file of 128 kbit/s MPEG-1 stereo frames at 44100 Hz, with an
+// ID3v2.3 tag holding a cover and, if frames isn't 0, a Xing header, followed by audioSize bytes
+// of audio data.
+func createTestMP3(frames uint32, audioSize int) []byte {
+	apic := bytes.Join([][]byte{{0}, []byte("image/png\x00"), {3}, []byte("cover\x00"), testArtwork}, nil)
+	apicFrame := bytes.Join([][]byte{[]byte("APIC"), testUint32(uint32(len(apic))), {0, 0}, apic}, nil)
+	tagSize := len(apicFrame)
+	id3 := []byte{'I', 'D', '3', 3, 0, 0,
+		byte(tagSize >> 21 & 0x7F), byte(tagSize >> 14 & 0x7F), byte(tagSize >> 7 & 0x7F), byte(tagSize & 0x7F)}
+
+	frame := make([]byte, audioSize)
+	copy(frame, []byte{0xFF, 0xFB, 0x90, 0x00})
+	if frames > 0 {
+		copy(frame[36:], "Xing")
+		copy(frame[40:], testUint32(0x01, frames))
+	}
+
+	return bytes.Join([][]byte{id3, apicFrame, frame}, nil)
+}
+
+func TestProbe(t *testing.T) {
+	video := mp4TestTrack("vide", "avc1", 1920, 1080, false)
+	audio := mp4TestTrack("soun", "mp4a", 0, 0, false)
+
+	t.Run("mp4", func(t *testing.T) {
+		md, err := Probe(bytes.NewReader(createTestMP4(false, video, audio)))
+		require.NoError(t, err)
+		require.Equal(t, &Metadata{
+			Format:     "mp4",
+			Duration:   12500 * time.Millisecond,
+			Width:      1920,
+			Height:     1080,
+			VideoCodec: "h264",
+			AudioCodec: "aac",
+			Artwork:    testArtwork,
+		}, md)
+		require.True(t, md.HasVideo())
+	})
+
+	t.Run("mp4 with the metadata last", func(t *testing.T) {
+		md, err := Probe(bytes.NewReader(createTestMP4(true, audio, mp4TestTrack("vide", "hvc1", 1920, 1080, true))))
+		require.NoError(t, err)
+		require.Equal(t, "hevc", md.VideoCodec)
+		require.Equal(t, "aac", md.AudioCodec)
+		require.Equal(t, 1080, md.Width)
+		require.Equal(t, 1920, md.Height)
+	})
+
+	t.Run("m4a", func(t *testing.T) {
+		md, err := Probe(bytes.NewReader(createTestMP4(false, mp4TestTrack("soun", "alac", 0, 0, false))))
+		require.NoError(t, err)
+		require.False(t, md.HasVideo())
+		require.Equal(t, "alac", md.AudioCodec)
+		require.Equal(t, 12500*time.Millisecond, md.Duration)
+	})
+
+	t.Run("webm", func(t *testing.T) {
+		md, err := Probe(bytes.NewReader(createTestMatroska("webm")))
+		require.NoError(t, err)
+		require.Equal(t, &Metadata{
+			Format:     "webm",
+			Duration:   12500 * time.Millisecond,
+			Width:      1280,
+			Height:     720,
+			VideoCodec: "vp9",
+			AudioCodec: "opus",
+			Artwork:    testArtwork,
+		}, md)
+	})
+
+	t.Run("matroska", func(t *testing.T) {
+		md, err := Probe(bytes.NewReader(createTestMatroska("matroska")))
+		require.NoError(t, err)
+		require.Equal(t, "matroska", md.Format)
+	})
+
+	t.Run("wav", func(t *testing.T) {
+		md, err := Probe(bytes.NewReader(createTestWAV()))
+		require.NoError(t, err)
+		require.Equal(t, &Metadata{
+			Format:     "wav",
+			Duration:   2 * time.Second,
+			AudioCodec: "pcm_s16le",
+		}, md)
+	})
+
+	t.Run("vbr mp3", func(t *testing.T) {
+		md, err := Probe(bytes.NewReader(createTestMP3(1000, 4096)))
+		require.NoError(t, err)
+		require.Equal(t, "mp3", md.AudioCodec)
+		require.Equal(t, time.Duration(1000*1152)*time.Second/44100, md.Duration)
+		require.Equal(t, testArtwork, md.Artwork)
+	})
+
+	t.Run("cbr mp3", func(t *testing.T) {
+		md, err := Probe(bytes.NewReader(createTestMP3(0, 160000)))
+		require.NoError(t, err)
+		require.Equal(t, "mp3", md.Format)
+		// The audio data is 160000 bytes at 16000 bytes per second.
+		require.Equal(t, 10*time.Second, md.Duration)
+	})
+
+	t.Run("unsupported formats", func(t *testing.T) {
+		_, err := Probe(bytes.NewReader([]byte("OggS\x00\x02")))
+		require.ErrorIs(t, err, ErrUnsupportedFormat)
+
+		_, err = Probe(bytes.NewReader(nil))
+		require.ErrorIs(t, err, ErrUnsupportedFormat)
+
+		_, err = Probe(bytes.NewReader(createTestMatroska("other")))
+		require.ErrorIs(t, err, ErrUnsupportedFormat)
+	})
+
+	t.Run("invalid files", func(t *testing.T) {
+		data := createTestMP4(true, video)
+		_, err := Probe(bytes.NewReader(data[:len(data)-20]))
+		require.Error(t, err)
+
+		_, err = Probe(bytes.NewReader(createTestMP4(false)[:40]))
+		require.Error(t, err)
+
+		data = createTestMatroska("webm")
+		_, err = Probe(bytes.NewReader(data[:60]))
+		require.Error(t, err)
+
+		_, err = Probe(bytes.NewReader([]byte("RIFF\x00\x00\x00\x00WAVEdata")))
+		require.Error(t, err)
+	})
+}
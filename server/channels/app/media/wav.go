@@ -0,0 +1,120 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package media
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+)
+
+// The WAV formats whose codec isn't named after the bits per sample.
+var wavCodecs = map[uint16]string{
+	0x0006: "pcm_alaw",
+	0x0007: "pcm_mulaw",
+	0x0011: "adpcm_ima_wav",
+	0x0055: "mp3",
+}
+
+const (
+	wavFormatPCM        = 0x0001
+	wavFormatIEEEFloat  = 0x0003
+	wavFormatExtensible = 0xFFFE
+)
+
+func probeWAV(rs io.ReadSeeker) (*Metadata, error) {
+	// The RIFF header is followed by the chunks of the file.
+	if _, err := rs.Seek(12, io.SeekStart); err != nil {
+		return nil, fmt.Errorf("media: failed to seek: %w", err)
+	}
+
+	var format, bitsPerSample uint16
+	var byteRate uint32
+	var dataSize int64
+	header := make([]byte, 8)
+	for format == 0 || dataSize == 0 {
+		if _, err := io.ReadFull(rs, header); err != nil {
+			if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+				break
+			}
+			return nil, fmt.Errorf("media: failed to read wav chunk header: %w", err)
+		}
+		chunkSize := int64(binary.LittleEndian.Uint32(header[4:]))
+
+		switch string(header[:4]) {
+		case "fmt ":
+			if chunkSize < 16 || chunkSize > 1024 {
+				return nil, errors.New("media: invalid wav format chunk")
+			}
+			chunk := make([]byte, chunkSize)
+			if _, err := io.ReadFull(rs, chunk); err != nil {
+				return nil, fmt.Errorf("media: failed to read wav format chunk: %w", err)
+			}
+			format = binary.LittleEndian.Uint16(chunk)
+			byteRate = binary.LittleEndian.Uint32(chunk[8:])
+			bitsPerSample = binary.LittleEndian.Uint16(chunk[14:])
+			// The actual format is the first two bytes of the sub-format GUID.
+			if format == wavFormatExtensible && chunkSize >= 26 {
+				format = binary.LittleEndian.Uint16(chunk[24:])
+			}
+			// The chunks are padded to an even size.
+			if chunkSize%2 == 1 {
+				if _, err := rs.Seek(1, io.SeekCurrent); err != nil {
+					return nil, fmt.Errorf("media: failed to seek: %w", err)
+				}
+			}
+		case "data":
+			dataSize = chunkSize
+			// The size of the data chunk isn't known when it was streamed.
+			if dataSize == 0xFFFFFFFF || dataSize == 0 {
+				pos, err := rs.Seek(0, io.SeekCurrent)
+				if err != nil {
+					return nil, fmt.Errorf("media: failed to seek: %w", err)
+				}
+				end, err := rs.Seek(0, io.SeekEnd)
+				if err != nil {
+					return nil, fmt.Errorf("media: failed to seek: %w", err)
+				}
+				dataSize = end - pos
+			} else if _, err := rs.Seek(chunkSize+chunkSize%2, io.SeekCurrent); err != nil {
+				return nil, fmt.Errorf("media: failed to seek: %w", err)
+			}
+		default:
+			if _, err := rs.Seek(chunkSize+chunkSize%2, io.SeekCurrent); err != nil {
+				return nil, fmt.Errorf("media: failed to seek: %w", err)
+			}
+		}
+	}
+
+	if format == 0 {
+		return nil, errors.New("media: wav file without a format chunk")
+	}
+
+	md := &Metadata{
+		Format:     "wav",
+		AudioCodec: wavCodec(format, bitsPerSample),
+	}
+	if byteRate > 0 {
+		md.Duration = time.Duration(dataSize) * time.Second / time.Duration(byteRate)
+	}
+	return md, nil
+}
+
+func wavCodec(format, bitsPerSample uint16) string {
+	switch format {
+	case wavFormatPCM:
+		if bitsPerSample == 8 {
+			return "pcm_u8"
+		}
+		return fmt.Sprintf("pcm_s%dle", bitsPerSample)
+	case wavFormatIEEEFloat:
+		return fmt.Sprintf("pcm_f%dle", bitsPerSample)
+	}
+	if codec, ok := wavCodecs[format]; ok {
+		return codec
+	}
+	return fmt.Sprintf("wav_0x%04x", format)
+}
@@ -15,6 +15,7 @@ import (
 	"github.com/mattermost/mattermost/server/public/shared/mlog"
 	"github.com/mattermost/mattermost/server/public/shared/request"
 	"github.com/mattermost/mattermost/server/v8/channels/store"
+	"github.com/mattermost/mattermost/server/v8/platform/shared/filestore"
 )
 
 const EmojisPermissionsMigrationKey = "EmojisPermissionsMigrationComplete"
@@ -604,6 +605,59 @@ func (s *Server) doCloudS3PathMigrations(c request.CTX) error {
 	return nil
 }
 
+func (s *Server) doFileEncryption(c request.CTX) error {
+	backend, ok := s.FileBackend().(*filestore.EncryptedFileBackend)
+	if !ok {
+		return nil
+	}
+
+	// If the files are already encrypted with the active key, don't do it again.
+	if system, err := s.Store().System().GetByName(model.SystemFileEncryptionKeyId); err == nil && system.Value == backend.ActiveKeyId() {
+		return nil
+	}
+
+	// If there is a job already pending, no need to schedule again.
+	jobs, err := s.Store().Job().GetAllByTypeAndStatus(c, model.JobTypeFileEncryption, model.JobStatusPending)
+	if err != nil {
+		return fmt.Errorf("failed to get jobs by type and status: %w", err)
+	}
+	if len(jobs) > 0 {
+		return nil
+	}
+
+	if _, appErr := s.Jobs.CreateJobOnce(c, model.JobTypeFileEncryption, nil); appErr != nil {
+		return fmt.Errorf("failed to start job for encrypting files: %w", appErr)
+	}
+
+	return nil
+}
+
+func (s *Server) doFileDeduplication(c request.CTX) error {
+	if !*s.platform.Config().FileSettings.EnableDeduplication {
+		return nil
+	}
+
+	// If the migration is already marked as completed, don't do it again.
+	if _, err := s.Store().System().GetByName(model.MigrationKeyFileDeduplication); err == nil {
+		return nil
+	}
+
+	// If there is a job already pending, no need to schedule again.
+	jobs, err := s.Store().Job().GetAllByTypeAndStatus(c, model.JobTypeFileDeduplication, model.JobStatusPending)
+	if err != nil {
+		return fmt.Errorf("failed to get jobs by type and status: %w", err)
+	}
+	if len(jobs) > 0 {
+		return nil
+	}
+
+	if _, appErr := s.Jobs.CreateJobOnce(c, model.JobTypeFileDeduplication, nil); appErr != nil {
+		return fmt.Errorf("failed to start job for deduplicating files: %w", appErr)
+	}
+
+	return nil
+}
+
 func (s *Server) doDeleteEmptyDraftsMigration(c request.CTX) error {
 	// If the migration is already marked as completed, don't do it again.
 	if _, err := s.Store().System().GetByName(model.MigrationKeyDeleteEmptyDrafts); err == nil {
@@ -707,6 +761,8 @@ func (s *Server) doAppMigrations() {
 	}
 	m2 := []migrationContext{
 		{"Encode S3 Image Paths Migration", s.doCloudS3PathMigrations},
+		{"File Encryption", s.doFileEncryption},
+		{"File Deduplication", s.doFileDeduplication},
 		{"Delete Empty Drafts Migration", s.doDeleteEmptyDraftsMigration},
 		{"Delete Orphan Drafts Migration", s.doDeleteOrphanDraftsMigration},
 		{"Delete Invalid Dms Preferences Migration", s.doDeleteDmsPreferencesMigration},
@@ -140,6 +140,24 @@ func openGraphDataWithProxyAddedToImageURLs(ogdata *opengraph.OpenGraph, toProxy
 	return ogdata
 }
 
+// openGraphDataWithResizedImageURLs returns a copy of the OpenGraph data with its image URLs rewritten
+// by toResizedURL, leaving the original data untouched since it may be cached.
+func openGraphDataWithResizedImageURLs(ogdata *opengraph.OpenGraph, toResizedURL func(string) string) *opengraph.OpenGraph {
+	resized := *ogdata
+	resized.Images = nil
+	for _, image := range ogdata.Images {
+		resizedImage := *image
+		if image.SecureURL != "" {
+			resizedImage.SecureURL = toResizedURL(image.SecureURL)
+		} else if image.URL != "" {
+			resizedImage.URL = toResizedURL(image.URL)
+		}
+		resized.Images = append(resized.Images, &resizedImage)
+	}
+
+	return &resized
+}
+
 func openGraphDecodeHTMLEntities(og *opengraph.OpenGraph) {
 	og.Title = html.UnescapeString(og.Title)
 	og.Description = html.UnescapeString(og.Description)
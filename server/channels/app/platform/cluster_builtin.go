@@ -0,0 +1,49 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package platform
+
+import (
+	"github.com/redis/rueidis"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/v8/channels/store"
+	"github.com/mattermost/mattermost/server/v8/einterfaces"
+	"github.com/mattermost/mattermost/server/v8/platform/services/cache"
+	"github.com/mattermost/mattermost/server/v8/platform/services/cluster"
+)
+
+// clusterServer adapts the platform service to the server interface of the built-in cluster.
+type clusterServer struct {
+	*PlatformService
+}
+
+func (s clusterServer) GetStore() store.Store {
+	return s.Store
+}
+
+func (s clusterServer) GetMetrics() einterfaces.MetricsInterface {
+	return s.Metrics()
+}
+
+func (s clusterServer) RedisClient() rueidis.Client {
+	return cache.RedisClient(s.cacheProvider)
+}
+
+func (s clusterServer) StartClusterDiscovery(discovery model.ClusterDiscovery) func() {
+	cds := s.NewClusterDiscoveryService()
+	cds.ClusterDiscovery = discovery
+	cds.Start()
+	return cds.Stop
+}
+
+// newBuiltinCluster returns the cluster used when clustering is enabled and no other
+// implementation was registered.
+func newBuiltinCluster(ps *PlatformService) einterfaces.ClusterInterface {
+	return cluster.New(clusterServer{ps})
+}
+
+func (ps *PlatformService) isBuiltinCluster() bool {
+	_, ok := ps.clusterIFace.(*cluster.Cluster)
+	return ok
+}
@@ -65,7 +65,7 @@ func (cds *ClusterDiscoveryService) Start() {
 }
 
 func (cds *ClusterDiscoveryService) Stop() {
-	cds.stop <- true
+	close(cds.stop)
 }
 
 func (ps *PlatformService) GetClusterId() string {
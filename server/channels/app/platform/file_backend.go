@@ -0,0 +1,78 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package platform
+
+import (
+	"reflect"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+	"github.com/mattermost/mattermost/server/v8/platform/shared/filestore"
+)
+
+func (ps *PlatformService) fileBackendSettings(cfg *model.Config) filestore.FileBackendSettings {
+	license := ps.License()
+	insecure := cfg.ServiceSettings.EnableInsecureOutgoingConnections
+	settings := filestore.NewFileBackendSettingsFromConfig(&cfg.FileSettings, license != nil && *license.Features.Compliance, insecure != nil && *insecure)
+	if *cfg.FileSettings.EnableFileCache {
+		settings.CacheEnabled = true
+		settings.CacheDirectory = *cfg.FileSettings.FileCacheDirectory
+		settings.CacheMaxSizeBytes = int64(*cfg.FileSettings.FileCacheMaxSizeMB) * 1024 * 1024
+	}
+	return settings
+}
+
+func (ps *PlatformService) exportFileBackendSettings(cfg *model.Config) filestore.FileBackendSettings {
+	license := ps.License()
+	return filestore.NewExportFileBackendSettingsFromConfig(&cfg.FileSettings, license != nil && *license.Features.Compliance, false)
+}
+
+func (ps *PlatformService) newFileBackend(cfg *model.Config) (filestore.FileBackend, error) {
+	return filestore.NewFileBackend(ps.fileBackendSettings(cfg))
+}
+
+func (ps *PlatformService) newExportFileBackend(cfg *model.Config) (filestore.FileBackend, error) {
+	return filestore.NewExportFileBackend(ps.exportFileBackendSettings(cfg))
+}
+
+// updateFileBackends replaces the file backends whose settings were changed, such as when a
+// file migration job switches the files to another backend. A backend that can't be created is
+// logged and the current one kept.
+func (ps *PlatformService) updateFileBackends(oldCfg, newCfg *model.Config) {
+	fileChanged := !reflect.DeepEqual(ps.fileBackendSettings(oldCfg), ps.fileBackendSettings(newCfg))
+	exportChanged := *oldCfg.FileSettings.DedicatedExportStore != *newCfg.FileSettings.DedicatedExportStore ||
+		(*newCfg.FileSettings.DedicatedExportStore && !reflect.DeepEqual(ps.exportFileBackendSettings(oldCfg), ps.exportFileBackendSettings(newCfg)))
+	if !fileChanged && !exportChanged {
+		return
+	}
+
+	ps.filestoreMut.Lock()
+	defer ps.filestoreMut.Unlock()
+
+	if fileChanged {
+		backend, err := ps.newFileBackend(newCfg)
+		if err != nil {
+			ps.logger.Error("Failed to switch to the new file backend", mlog.String("driver_name", *newCfg.FileSettings.DriverName), mlog.Err(err))
+		} else {
+			ps.logger.Info("Switched to the new file backend", mlog.String("driver_name", *newCfg.FileSettings.DriverName))
+			ps.filestore = backend
+			ps.setupFileCache()
+		}
+	}
+
+	if !*newCfg.FileSettings.DedicatedExportStore {
+		ps.exportFilestore = ps.filestore
+		return
+	}
+
+	if exportChanged {
+		backend, err := ps.newExportFileBackend(newCfg)
+		if err != nil {
+			ps.logger.Error("Failed to switch to the new export file backend", mlog.String("driver_name", *newCfg.FileSettings.ExportDriverName), mlog.Err(err))
+			return
+		}
+		ps.logger.Info("Switched to the new export file backend", mlog.String("driver_name", *newCfg.FileSettings.ExportDriverName))
+		ps.exportFilestore = backend
+	}
+}
@@ -0,0 +1,58 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package platform
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+)
+
+func TestUpdateFileBackends(t *testing.T) {
+	oldCfg := &model.Config{}
+	oldCfg.SetDefaults()
+	*oldCfg.FileSettings.DriverName = model.ImageDriverLocal
+	*oldCfg.FileSettings.Directory = t.TempDir()
+
+	ps := &PlatformService{logger: mlog.CreateConsoleTestLogger(t)}
+	backend, err := ps.newFileBackend(oldCfg)
+	require.NoError(t, err)
+	ps.filestore = backend
+	ps.exportFilestore = backend
+
+	t.Run("unchanged settings keep the backends", func(t *testing.T) {
+		newCfg := oldCfg.Clone()
+		*newCfg.FileSettings.MaxFileSize++
+		ps.updateFileBackends(oldCfg, newCfg)
+		assert.Same(t, backend, ps.FileBackend())
+	})
+
+	t.Run("changed settings switch the backends", func(t *testing.T) {
+		newCfg := oldCfg.Clone()
+		*newCfg.FileSettings.Directory = t.TempDir()
+		ps.updateFileBackends(oldCfg, newCfg)
+		require.NotSame(t, backend, ps.FileBackend())
+		assert.Same(t, ps.FileBackend(), ps.ExportFileBackend())
+
+		_, err := ps.FileBackend().WriteFile(bytes.NewReader([]byte("data")), "file")
+		require.NoError(t, err)
+		_, err = os.Stat(filepath.Join(*newCfg.FileSettings.Directory, "file"))
+		assert.NoError(t, err)
+	})
+
+	t.Run("invalid settings keep the backends", func(t *testing.T) {
+		current := ps.FileBackend()
+		newCfg := oldCfg.Clone()
+		*newCfg.FileSettings.DriverName = "unknown"
+		ps.updateFileBackends(oldCfg, newCfg)
+		assert.Same(t, current, ps.FileBackend())
+	})
+}
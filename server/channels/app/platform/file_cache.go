@@ -0,0 +1,45 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package platform
+
+import (
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/v8/platform/shared/filestore"
+)
+
+// setupFileCache counts the hits and misses of the local file cache, if enabled, and sends
+// its invalidations to the other servers of the cluster.
+func (ps *PlatformService) setupFileCache() {
+	cache := filestore.GetCachedFileBackend(ps.filestore)
+	if cache == nil {
+		return
+	}
+
+	if ps.metricsIFace != nil {
+		cache.SetMetrics(ps.metricsIFace)
+	}
+	cache.SetInvalidationListener(ps.sendFileCacheInvalidation)
+}
+
+func (ps *PlatformService) sendFileCacheInvalidation(path string, directory bool) {
+	if ps.clusterIFace == nil {
+		return
+	}
+
+	msg := &model.ClusterMessage{
+		Event:    model.ClusterEventInvalidateFileCache,
+		SendType: model.ClusterSendReliable,
+		Data:     []byte(path),
+	}
+	if directory {
+		msg.Props = map[string]string{"directory": "true"}
+	}
+	ps.clusterIFace.SendClusterMessage(msg)
+}
+
+func (ps *PlatformService) clusterInvalidateFileCacheHandler(msg *model.ClusterMessage) {
+	if cache := filestore.GetCachedFileBackend(ps.FileBackend()); cache != nil {
+		cache.Invalidate(string(msg.Data), msg.Props["directory"] == "true")
+	}
+}
@@ -0,0 +1,82 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package platform
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/v8/einterfaces/mocks"
+	"github.com/mattermost/mattermost/server/v8/platform/shared/filestore"
+)
+
+func TestFileCacheInvalidation(t *testing.T) {
+	dataDir := t.TempDir()
+	newService := func() *PlatformService {
+		backend, err := filestore.NewFileBackend(filestore.FileBackendSettings{
+			DriverName:        model.ImageDriverLocal,
+			Directory:         dataDir,
+			CacheEnabled:      true,
+			CacheDirectory:    t.TempDir(),
+			CacheMaxSizeBytes: 1024,
+		})
+		require.NoError(t, err)
+		return &PlatformService{filestore: backend}
+	}
+
+	var sent []*model.ClusterMessage
+	cluster := &mocks.ClusterInterface{}
+	cluster.On("SendClusterMessage", mock.AnythingOfType("*model.ClusterMessage")).Run(func(args mock.Arguments) {
+		sent = append(sent, args.Get(0).(*model.ClusterMessage))
+	})
+
+	metrics := &mocks.MetricsInterface{}
+	metrics.On("IncrementFileCacheMissCounter").Once()
+	metrics.On("IncrementFileCacheHitCounter").Once()
+
+	ps1 := newService()
+	ps1.clusterIFace = cluster
+	ps1.metricsIFace = metrics
+	ps1.setupFileCache()
+
+	ps2 := newService()
+	ps2.setupFileCache()
+
+	_, err := ps1.filestore.WriteFile(bytes.NewReader([]byte("one")), "dir/file")
+	require.NoError(t, err)
+	require.Len(t, sent, 1)
+	assert.Equal(t, model.ClusterEventInvalidateFileCache, sent[0].Event)
+	assert.Equal(t, "dir/file", string(sent[0].Data))
+
+	// Both servers cache the file, which is then changed by the first one.
+	for i := 0; i < 2; i++ {
+		data, err := ps1.filestore.ReadFile("dir/file")
+		require.NoError(t, err)
+		assert.Equal(t, "one", string(data))
+	}
+	data, err := ps2.filestore.ReadFile("dir/file")
+	require.NoError(t, err)
+	assert.Equal(t, "one", string(data))
+	metrics.AssertExpectations(t)
+
+	require.NoError(t, os.WriteFile(filepath.Join(dataDir, "dir/file"), []byte("two"), 0600))
+	data, err = ps2.filestore.ReadFile("dir/file")
+	require.NoError(t, err)
+	assert.Equal(t, "one", string(data))
+
+	require.NoError(t, ps1.filestore.RemoveDirectory("dir"))
+	require.Len(t, sent, 2)
+	assert.Equal(t, "true", sent[1].Props["directory"])
+
+	ps2.clusterInvalidateFileCacheHandler(sent[1])
+	_, err = ps2.filestore.ReadFile("dir/file")
+	require.Error(t, err)
+}
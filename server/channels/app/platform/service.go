@@ -15,6 +15,7 @@ import (
 
 	"github.com/mattermost/mattermost/server/public/model"
 	"github.com/mattermost/mattermost/server/public/plugin"
+	"github.com/mattermost/mattermost/server/public/shared/httpservice"
 	"github.com/mattermost/mattermost/server/public/shared/mlog"
 	"github.com/mattermost/mattermost/server/v8/channels/app/featureflag"
 	"github.com/mattermost/mattermost/server/v8/channels/jobs"
@@ -29,6 +30,7 @@ import (
 	"github.com/mattermost/mattermost/server/v8/platform/services/cache"
 	"github.com/mattermost/mattermost/server/v8/platform/services/searchengine"
 	"github.com/mattermost/mattermost/server/v8/platform/services/searchengine/bleveengine"
+	"github.com/mattermost/mattermost/server/v8/platform/services/searchengine/semanticengine"
 	"github.com/mattermost/mattermost/server/v8/platform/shared/filestore"
 )
 
@@ -44,6 +46,9 @@ type PlatformService struct {
 
 	configStore *config.Store
 
+	// The file backends are replaced when the file settings change, so that every server of
+	// the cluster switches to a new backend without a restart.
+	filestoreMut    sync.RWMutex
 	filestore       filestore.FileBackend
 	exportFilestore filestore.FileBackend
 
@@ -199,6 +204,11 @@ func New(sc ServiceConfig, options ...Option) (*PlatformService, error) {
 		return nil, err
 	}
 	searchEngine.RegisterBleveEngine(bleveEngine)
+	semanticEngine := semanticengine.NewSemanticEngine(ps.Config(), httpservice.MakeHTTPService(ps))
+	if err := semanticEngine.Start(); err != nil {
+		return nil, err
+	}
+	searchEngine.RegisterSemanticEngine(semanticEngine)
 	ps.SearchEngine = searchEngine
 
 	// Step 4: Init Enterprise
@@ -262,23 +272,22 @@ func New(sc ServiceConfig, options ...Option) (*PlatformService, error) {
 		}
 	}
 
-	license := ps.License()
 	// Step 3: Initialize filestore
 	if ps.filestore == nil {
-		insecure := ps.Config().ServiceSettings.EnableInsecureOutgoingConnections
-		backend, err2 := filestore.NewFileBackend(filestore.NewFileBackendSettingsFromConfig(&ps.Config().FileSettings, license != nil && *license.Features.Compliance, insecure != nil && *insecure))
+		backend, err2 := ps.newFileBackend(ps.Config())
 		if err2 != nil {
 			return nil, fmt.Errorf("failed to initialize filebackend: %w", err2)
 		}
 
 		ps.filestore = backend
 	}
+	ps.setupFileCache()
 
 	if ps.exportFilestore == nil {
 		ps.exportFilestore = ps.filestore
 		if *ps.Config().FileSettings.DedicatedExportStore {
 			mlog.Info("Setting up dedicated export filestore", mlog.String("driver_name", *ps.Config().FileSettings.ExportDriverName))
-			backend, errFileBack := filestore.NewExportFileBackend(filestore.NewExportFileBackendSettingsFromConfig(&ps.Config().FileSettings, license != nil && *license.Features.Compliance, false))
+			backend, errFileBack := ps.newExportFileBackend(ps.Config())
 			if errFileBack != nil {
 				return nil, fmt.Errorf("failed to initialize export filebackend: %w", errFileBack)
 			}
@@ -286,11 +295,13 @@ func New(sc ServiceConfig, options ...Option) (*PlatformService, error) {
 			ps.exportFilestore = backend
 		}
 	}
+	ps.AddConfigListener(ps.updateFileBackends)
 
 	ps.Store, err = ps.newStore()
 	if err != nil {
 		return nil, fmt.Errorf("cannot create store: %w", err)
 	}
+	semanticEngine.SetStore(ps.Store.PostEmbedding())
 
 	// Needed before loading license
 	ps.statusCache, err = ps.cacheProvider.NewCache(&cache.CacheOptions{
@@ -439,6 +450,8 @@ func (ps *PlatformService) SetLogger(logger *mlog.Logger) {
 func (ps *PlatformService) initEnterprise() {
 	if clusterInterface != nil && ps.clusterIFace == nil {
 		ps.clusterIFace = clusterInterface(ps)
+	} else if ps.clusterIFace == nil && *ps.Config().ClusterSettings.Enable {
+		ps.clusterIFace = newBuiltinCluster(ps)
 	}
 
 	if elasticsearchInterface != nil {
@@ -538,9 +551,13 @@ func (ps *PlatformService) GetPluginStatuses() (model.PluginStatuses, *model.App
 }
 
 func (ps *PlatformService) FileBackend() filestore.FileBackend {
+	ps.filestoreMut.RLock()
+	defer ps.filestoreMut.RUnlock()
 	return ps.filestore
 }
 
 func (ps *PlatformService) ExportFileBackend() filestore.FileBackend {
+	ps.filestoreMut.RLock()
+	defer ps.filestoreMut.RUnlock()
 	return ps.exportFilestore
 }
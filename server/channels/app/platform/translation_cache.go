@@ -0,0 +1,25 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package platform
+
+import (
+	"time"
+
+	"github.com/mattermost/mattermost/server/v8/platform/services/cache"
+)
+
+const TranslationCacheSize = 10000
+const TranslationCacheDuration = 24 * time.Hour
+
+var translationCache = cache.NewLRU(&cache.CacheOptions{
+	Size: TranslationCacheSize,
+})
+
+func PurgeTranslationCache() {
+	translationCache.Purge()
+}
+
+func TranslationCache() cache.Cache {
+	return translationCache
+}
@@ -2137,3 +2137,46 @@ func TestUserHasJoinedChannel(t *testing.T) {
 		}
 	})
 }
+
+func TestHookTranslateText(t *testing.T) {
+	th := Setup(t).InitBasic()
+	defer th.TearDown()
+
+	tearDown, _, _ := SetAppEnvironmentWithPlugins(t,
+		[]string{
+			`
+		package main
+
+		import (
+			"github.com/mattermost/mattermost/server/public/model"
+			"github.com/mattermost/mattermost/server/public/plugin"
+		)
+
+		type MyPlugin struct {
+			plugin.MattermostPlugin
+		}
+
+		func (p *MyPlugin) TranslateText(c *plugin.Context, text, targetLanguage string) (*model.Translation, error) {
+			return &model.Translation{SourceLanguage: "en", Text: "translated by plugin: " + text}, nil
+		}
+
+		func main() {
+			plugin.ClientMain(&MyPlugin{})
+		}
+	`}, th.App, th.NewPluginAPI)
+	defer tearDown()
+
+	th.App.UpdateConfig(func(cfg *model.Config) {
+		*cfg.TranslationSettings.Enable = true
+		*cfg.TranslationSettings.Provider = model.TranslationProviderPlugin
+	})
+
+	post, appErr := th.App.TranslatePost(th.Context, th.CreatePost(th.BasicChannel), "fr")
+	require.Nil(t, appErr)
+	require.Contains(t, post.Metadata.Translations, "fr")
+	assert.Equal(t, &model.Translation{
+		Language:       "fr",
+		SourceLanguage: "en",
+		Text:           "translated by plugin: " + post.Message,
+	}, post.Metadata.Translations["fr"])
+}
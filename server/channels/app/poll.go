@@ -0,0 +1,216 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package app
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+	"github.com/mattermost/mattermost/server/public/shared/request"
+)
+
+// preparePollForSave validates the poll definition of a new poll post and assigns ids
+// to its options.
+func (a *App) preparePollForSave(post *model.Post) *model.AppError {
+	poll, err := post.GetPoll()
+	if err != nil {
+		return model.NewAppError("preparePollForSave", "app.poll.invalid.app_error", nil, "", http.StatusBadRequest).Wrap(err)
+	}
+
+	poll.PreSave()
+	poll.ClosedAt = 0
+	if appErr := poll.IsValid(); appErr != nil {
+		return appErr
+	}
+
+	if poll.CloseAt > 0 && poll.CloseAt <= model.GetMillis() {
+		return model.NewAppError("preparePollForSave", "model.poll.is_valid.close_at.app_error", nil, "", http.StatusBadRequest)
+	}
+
+	if err := post.SetPoll(poll); err != nil {
+		return model.NewAppError("preparePollForSave", "app.poll.invalid.app_error", nil, "", http.StatusBadRequest).Wrap(err)
+	}
+
+	return nil
+}
+
+func (a *App) getPollForPost(post *model.Post) (*model.Poll, *model.AppError) {
+	if post.Type != model.PostTypePoll {
+		return nil, model.NewAppError("getPollForPost", "app.poll.not_a_poll.app_error", nil, "post_id="+post.Id, http.StatusBadRequest)
+	}
+
+	poll, err := post.GetPoll()
+	if err != nil {
+		return nil, model.NewAppError("getPollForPost", "app.poll.invalid.app_error", nil, "post_id="+post.Id, http.StatusInternalServerError).Wrap(err)
+	}
+
+	return poll, nil
+}
+
+// GetPollMetadataForPost tallies the votes of a poll post. If userID is not empty, the
+// votes of that user are included.
+func (a *App) GetPollMetadataForPost(post *model.Post, userID string) (*model.PollMetadata, *model.AppError) {
+	poll, appErr := a.getPollForPost(post)
+	if appErr != nil {
+		return nil, appErr
+	}
+
+	votes, err := a.Srv().Store().PollVote().GetForPost(post.Id)
+	if err != nil {
+		return nil, model.NewAppError("GetPollMetadataForPost", "app.poll.get_votes.app_error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+
+	return model.NewPollMetadata(poll, votes, userID, model.GetMillis()), nil
+}
+
+// GetPollMetadataForPostList tallies the votes of the poll posts of a list, loading the votes
+// of all of them at once. The metadata is keyed by post id and includes the votes of userID.
+func (a *App) GetPollMetadataForPostList(rctx request.CTX, list *model.PostList, userID string) (map[string]*model.PollMetadata, *model.AppError) {
+	polls := make(map[string]*model.Poll)
+	postIDs := []string{}
+	for _, post := range list.Posts {
+		if post.Type != model.PostTypePoll || post.DeleteAt > 0 {
+			continue
+		}
+		poll, appErr := a.getPollForPost(post)
+		if appErr != nil {
+			rctx.Logger().Warn("Failed to get the poll of a post", mlog.String("post_id", post.Id), mlog.Err(appErr))
+			continue
+		}
+		polls[post.Id] = poll
+		postIDs = append(postIDs, post.Id)
+	}
+
+	metadata := make(map[string]*model.PollMetadata, len(polls))
+	if len(postIDs) == 0 {
+		return metadata, nil
+	}
+
+	votes, err := a.Srv().Store().PollVote().GetForPosts(postIDs)
+	if err != nil {
+		return nil, model.NewAppError("GetPollMetadataForPostList", "app.poll.get_votes.app_error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+
+	votesByPost := make(map[string][]*model.PollVote, len(polls))
+	for _, vote := range votes {
+		votesByPost[vote.PostId] = append(votesByPost[vote.PostId], vote)
+	}
+
+	now := model.GetMillis()
+	for postID, poll := range polls {
+		metadata[postID] = model.NewPollMetadata(poll, votesByPost[postID], userID, now)
+	}
+
+	return metadata, nil
+}
+
+// VoteOnPoll replaces the votes of a user in a poll. An empty list of options retracts
+// the user's votes.
+func (a *App) VoteOnPoll(c request.CTX, postID, userID string, optionIDs []string) (*model.PollMetadata, *model.AppError) {
+	post, appErr := a.GetSinglePost(c, postID, false)
+	if appErr != nil {
+		return nil, appErr
+	}
+
+	poll, appErr := a.getPollForPost(post)
+	if appErr != nil {
+		return nil, appErr
+	}
+
+	if poll.IsClosed(model.GetMillis()) {
+		return nil, model.NewAppError("VoteOnPoll", "app.poll.closed.app_error", nil, "", http.StatusForbidden)
+	}
+
+	channel, appErr := a.GetChannel(c, post.ChannelId)
+	if appErr != nil {
+		return nil, appErr
+	}
+
+	if channel.DeleteAt > 0 {
+		return nil, model.NewAppError("VoteOnPoll", "app.poll.archived_channel.app_error", nil, "", http.StatusForbidden)
+	}
+
+	optionIDs = model.RemoveDuplicateStrings(optionIDs)
+	for _, optionID := range optionIDs {
+		if !poll.HasOption(optionID) {
+			return nil, model.NewAppError("VoteOnPoll", "app.poll.invalid_option.app_error", nil, "option_id="+optionID, http.StatusBadRequest)
+		}
+	}
+
+	if !poll.MultipleChoice && len(optionIDs) > 1 {
+		return nil, model.NewAppError("VoteOnPoll", "app.poll.single_choice.app_error", nil, "", http.StatusBadRequest)
+	}
+
+	if _, err := a.Srv().Store().PollVote().SaveForUser(postID, userID, optionIDs); err != nil {
+		return nil, model.NewAppError("VoteOnPoll", "app.poll.save_votes.app_error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+
+	// The post is always modified since the UpdateAt always changes
+	a.Srv().Store().Post().InvalidateLastPostTimeCache(channel.Id)
+
+	metadata, appErr := a.GetPollMetadataForPost(post, userID)
+	if appErr != nil {
+		return nil, appErr
+	}
+
+	a.sendPollUpdatedEvent(c, post, metadata)
+
+	return metadata, nil
+}
+
+// ClosePoll stops a poll from accepting further votes.
+func (a *App) ClosePoll(c request.CTX, postID string) (*model.PollMetadata, *model.AppError) {
+	post, appErr := a.GetSinglePost(c, postID, false)
+	if appErr != nil {
+		return nil, appErr
+	}
+
+	poll, appErr := a.getPollForPost(post)
+	if appErr != nil {
+		return nil, appErr
+	}
+
+	if poll.IsClosed(model.GetMillis()) {
+		return nil, model.NewAppError("ClosePoll", "app.poll.closed.app_error", nil, "", http.StatusBadRequest)
+	}
+
+	poll.ClosedAt = model.GetMillis()
+	if err := post.SetPoll(poll); err != nil {
+		return nil, model.NewAppError("ClosePoll", "app.poll.invalid.app_error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+
+	if _, err := a.Srv().Store().Post().Overwrite(c, post); err != nil {
+		return nil, model.NewAppError("ClosePoll", "app.post.overwrite.app_error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+
+	a.invalidateCacheForChannelPosts(post.ChannelId)
+
+	metadata, appErr := a.GetPollMetadataForPost(post, c.Session().UserId)
+	if appErr != nil {
+		return nil, appErr
+	}
+
+	a.sendPollUpdatedEvent(c, post, metadata)
+
+	return metadata, nil
+}
+
+func (a *App) sendPollUpdatedEvent(rctx request.CTX, post *model.Post, metadata *model.PollMetadata) {
+	// The event is broadcast to the whole channel, so it must not carry the votes of the
+	// user that triggered it.
+	broadcastMetadata := *metadata
+	broadcastMetadata.UserVotes = nil
+
+	message := model.NewWebSocketEvent(model.WebsocketEventPollUpdated, "", post.ChannelId, "", nil, "")
+	message.Add("post_id", post.Id)
+
+	pollJSON, err := json.Marshal(broadcastMetadata)
+	if err != nil {
+		rctx.Logger().Warn("Failed to encode poll metadata to JSON", mlog.Err(err))
+	}
+	message.Add("poll", string(pollJSON))
+	a.Publish(message)
+}
@@ -249,6 +249,16 @@ func (a *App) CreatePost(c request.CTX, post *model.Post, channel *model.Channel
 		return nil, err
 	}
 
+	if post.Type == model.PostTypePoll {
+		if err = a.preparePollForSave(post); err != nil {
+			return nil, err
+		}
+	}
+
+	if err = a.validatePostExpiry(post); err != nil {
+		return nil, err
+	}
+
 	// Temporary fix so old plugins don't clobber new fields in SlackAttachment struct, see MM-13088
 	if attachments, ok := post.GetProp("attachments").([]*model.SlackAttachment); ok {
 		jsonAttachments, err := json.Marshal(attachments)
@@ -337,6 +347,15 @@ func (a *App) CreatePost(c request.CTX, post *model.Post, channel *model.Channel
 		}
 	}
 
+	if appErr := a.scheduleExpiringPost(rpost, channel); appErr != nil {
+		c.Logger().Error("Encountered error scheduling the expiry of post", mlog.String("post_id", rpost.Id), mlog.Err(appErr))
+	}
+
+	savedSearchPost := rpost.Clone()
+	a.Srv().Go(func() {
+		a.alertSavedSearchSubscribers(request.EmptyContext(a.Log()), savedSearchPost, channel)
+	})
+
 	// We make a copy of the post for the plugin hook to avoid a race condition,
 	// and to remove the non-GOB-encodable Metadata from it.
 	pluginPost := rpost.ForPlugin()
@@ -681,6 +700,11 @@ func (a *App) UpdatePost(c request.CTX, receivedUpdatedPost *model.Post, safeUpd
 		newPost.HasReactions = receivedUpdatedPost.HasReactions
 		newPost.FileIds = receivedUpdatedPost.FileIds
 		newPost.SetProps(receivedUpdatedPost.GetProps())
+
+		// The poll definition can only change through voting or closing the poll.
+		if oldPost.Type == model.PostTypePoll {
+			newPost.AddProp(model.PostPropsPoll, oldPost.GetProp(model.PostPropsPoll))
+		}
 	}
 
 	// Avoid deep-equal checks if EditAt was already modified through message change
@@ -736,6 +760,11 @@ func (a *App) UpdatePost(c request.CTX, receivedUpdatedPost *model.Post, safeUpd
 	// individually.
 	rpost.IsFollowing = nil
 
+	// Likewise, the poll votes of the current user must not be broadcast.
+	if rpost.Metadata != nil && rpost.Metadata.Poll != nil {
+		rpost.Metadata.Poll.UserVotes = nil
+	}
+
 	rpost, nErr = a.addPostPreviewProp(c, rpost)
 	if nErr != nil {
 		return nil, model.NewAppError("UpdatePost", "app.post.update.app_error", nil, "", http.StatusInternalServerError).Wrap(nErr)
@@ -1421,6 +1450,14 @@ func (a *App) DeletePost(c request.CTX, postID, deleteByID string) (*model.Post,
 		a.deleteFlaggedPosts(c, post.Id)
 	})
 
+	if post.Type == model.PostTypePoll {
+		a.Srv().Go(func() {
+			if err := a.Srv().Store().PollVote().DeleteForPost(post.Id); err != nil {
+				c.Logger().Warn("Failed to delete the votes of a deleted poll", mlog.String("post_id", post.Id), mlog.Err(err))
+			}
+		})
+	}
+
 	pluginPost := post.ForPlugin()
 	pluginContext := pluginContext(c)
 	a.Srv().Go(func() {
@@ -25,6 +25,7 @@ import (
 	"github.com/mattermost/mattermost/server/public/shared/request"
 	"github.com/mattermost/mattermost/server/v8/channels/app/platform"
 	"github.com/mattermost/mattermost/server/v8/channels/utils/imgutils"
+	"github.com/mattermost/mattermost/server/v8/platform/services/imageproxy"
 )
 
 type linkMetadataCache struct {
@@ -37,6 +38,10 @@ const MaxMetadataImageSize = MaxOpenGraphResponseSize
 
 const UnsafeLinksPostProp = "unsafe_links"
 
+// linkPreviewImageOptions are the dimensions the images of link previews are resized to, when the
+// image proxy supports it, which leaves room for high density displays.
+var linkPreviewImageOptions = imageproxy.ImageOptions{Width: 1024, Height: 1024}
+
 func (s *Server) initPostMetadata() {
 	// Dump any cached links if the proxy settings have changed so image URLs can be updated
 	s.platform.AddConfigListener(func(before, after *model.Config) {
@@ -47,6 +52,14 @@ func (s *Server) initPostMetadata() {
 			platform.PurgeLinkCache()
 		}
 	})
+
+	// Dump any cached translations if the translation provider has changed
+	s.platform.AddConfigListener(func(before, after *model.Config) {
+		if *before.TranslationSettings.Provider != *after.TranslationSettings.Provider ||
+			*before.TranslationSettings.ProviderURL != *after.TranslationSettings.ProviderURL {
+			platform.PurgeTranslationCache()
+		}
+	})
 }
 
 func (a *App) PreparePostListForClient(c request.CTX, originalList *model.PostList) *model.PostList {
@@ -60,11 +73,21 @@ func (a *App) PreparePostListForClient(c request.CTX, originalList *model.PostLi
 	}
 
 	for id, originalPost := range originalList.Posts {
-		post := a.PreparePostForClientWithEmbedsAndImages(c, originalPost, false, false, false)
+		// The poll tallies of the whole list are loaded at once below.
+		post := a.preparePostForClient(c, originalPost, false, false, false, false)
+		post = a.getEmbedsAndImages(c, post, false)
 
 		list.Posts[id] = post
 	}
 
+	if pollMetadata, err := a.GetPollMetadataForPostList(c, list, c.Session().UserId); err != nil {
+		c.Logger().Warn("Failed to get poll metadata for a post list", mlog.Err(err))
+	} else {
+		for id, metadata := range pollMetadata {
+			list.Posts[id].Metadata.Poll = metadata
+		}
+	}
+
 	if a.IsPostPriorityEnabled() {
 		priority, _ := a.GetPriorityForPostList(list)
 		acknowledgements, _ := a.GetAcknowledgementsForPostList(list)
@@ -109,6 +132,10 @@ func (a *App) OverrideIconURLIfEmoji(c request.CTX, post *model.Post) {
 }
 
 func (a *App) PreparePostForClient(c request.CTX, originalPost *model.Post, isNewPost, isEditPost, includePriority bool) *model.Post {
+	return a.preparePostForClient(c, originalPost, isNewPost, isEditPost, includePriority, true)
+}
+
+func (a *App) preparePostForClient(c request.CTX, originalPost *model.Post, isNewPost, isEditPost, includePriority, includePoll bool) *model.Post {
 	post := originalPost.Clone()
 
 	// Proxy image links before constructing metadata so that requests go through the proxy
@@ -141,6 +168,15 @@ func (a *App) PreparePostForClient(c request.CTX, originalPost *model.Post, isNe
 		post.Metadata.Files = fileInfos
 	}
 
+	// Poll tallies
+	if includePoll && post.Type == model.PostTypePoll {
+		if pollMetadata, err := a.GetPollMetadataForPost(post, c.Session().UserId); err != nil {
+			c.Logger().Warn("Failed to get poll metadata for a post", mlog.String("post_id", post.Id), mlog.Err(err))
+		} else {
+			post.Metadata.Poll = pollMetadata
+		}
+	}
+
 	if includePriority && a.IsPostPriorityEnabled() && post.RootId == "" {
 		// Post's Priority if any
 		if priority, err := a.GetPriorityForPost(post.Id); err != nil {
@@ -357,6 +393,9 @@ func (a *App) getEmbedForPost(c request.CTX, post *model.Post, firstLink string,
 func (a *App) getImagesForPost(c request.CTX, post *model.Post, imageURLs []string, isNewPost bool) map[string]*model.PostImage {
 	images := map[string]*model.PostImage{}
 
+	resizeImages := a.ImageProxy().SupportsResizing()
+	resizedImageURLs := map[string]bool{}
+
 	for _, embed := range post.Metadata.Embeds {
 		switch embed.Type {
 		case model.PostEmbedImage:
@@ -375,6 +414,13 @@ func (a *App) getImagesForPost(c request.CTX, post *model.Post, imageURLs []stri
 				)
 				continue
 			}
+			if resizeImages {
+				openGraph = openGraphDataWithResizedImageURLs(openGraph, func(imageURL string) string {
+					originalURL := a.ImageProxy().GetUnproxiedImageURL(imageURL)
+					return a.ImageProxy().GetResizedProxiedImageURL(originalURL, linkPreviewImageOptions)
+				})
+				embed.Data = openGraph
+			}
 			for _, image := range openGraph.Images {
 				var imageURL string
 				if image.SecureURL != "" {
@@ -388,6 +434,9 @@ func (a *App) getImagesForPost(c request.CTX, post *model.Post, imageURLs []stri
 				}
 
 				imageURLs = append(imageURLs, imageURL)
+				if resizeImages {
+					resizedImageURLs[imageURL] = true
+				}
 			}
 		}
 	}
@@ -416,6 +465,12 @@ func (a *App) getImagesForPost(c request.CTX, post *model.Post, imageURLs []stri
 				)
 			}
 		} else if image != nil {
+			if resizedImageURLs[imageURL] {
+				// The dimensions are the ones of the original image, which may be cached.
+				resized := *image
+				resized.Width, resized.Height = linkPreviewImageOptions.FitDimensions(image.Width, image.Height)
+				image = &resized
+			}
 			images[imageURL] = image
 		}
 	}
@@ -809,6 +864,33 @@ func cacheLinkMetadata(requestURL string, timestamp int64, og *opengraph.OpenGra
 	platform.LinkCache().SetWithExpiry(strconv.FormatInt(model.GenerateLinkMetadataHash(requestURL, timestamp), 16), metadata, platform.LinkCacheDuration)
 }
 
+// getTranslationForPost returns the translation of the message of a post, translating it if it
+// isn't cached yet. The cache is keyed on the time the post was last edited so that edits are
+// translated again.
+func (a *App) getTranslationForPost(c request.CTX, post *model.Post, lang string) (*model.Translation, error) {
+	if post.Message == "" {
+		return &model.Translation{Language: lang}, nil
+	}
+
+	cacheKey := fmt.Sprintf("%s:%d:%s", post.Id, post.EditAt, lang)
+
+	var cached model.Translation
+	if err := platform.TranslationCache().Get(cacheKey, &cached); err == nil {
+		return &cached, nil
+	}
+
+	translation, err := a.ch.translator.Translate(c.Context(), post.Message, lang)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := platform.TranslationCache().SetWithExpiry(cacheKey, translation, platform.TranslationCacheDuration); err != nil {
+		c.Logger().Warn("Failed to cache post translation", mlog.String("post_id", post.Id), mlog.Err(err))
+	}
+
+	return translation, nil
+}
+
 // peekContentType peeks at the first 512 bytes of p, and attempts to detect
 // the content type.  Returns empty string if error occurs.
 func peekContentType(p *bufio.Reader) string {
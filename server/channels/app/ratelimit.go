@@ -6,8 +6,10 @@ package app
 import (
 	"math"
 	"net/http"
+	"sort"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/pkg/errors"
 	"github.com/throttled/throttled"
@@ -21,30 +23,79 @@ import (
 
 type RateLimiter struct {
 	throttledRateLimiter *throttled.GCRARateLimiter
+	routeRateLimiters    []routeRateLimiter
+	tokenRateLimiters    map[string]*throttled.GCRARateLimiter
 	useAuth              bool
 	useIP                bool
 	header               string
 	trustedProxyIPHeader []string
 }
 
-func NewRateLimiter(settings *model.RateLimitSettings, trustedProxyIPHeader []string) (*RateLimiter, error) {
-	store, err := memstore.New(*settings.MemoryStoreSize)
+type routeRateLimiter struct {
+	route   string
+	limiter *throttled.GCRARateLimiter
+}
+
+// prefixedGCRAStore namespaces the keys of the rate limiters sharing a store.
+type prefixedGCRAStore struct {
+	throttled.GCRAStore
+	prefix string
+}
+
+func (s prefixedGCRAStore) GetWithTime(key string) (int64, time.Time, error) {
+	return s.GCRAStore.GetWithTime(s.prefix + key)
+}
+
+func (s prefixedGCRAStore) SetIfNotExistsWithTTL(key string, value int64, ttl time.Duration) (bool, error) {
+	return s.GCRAStore.SetIfNotExistsWithTTL(s.prefix+key, value, ttl)
+}
+
+func (s prefixedGCRAStore) CompareAndSwapWithTTL(key string, old, new int64, ttl time.Duration) (bool, error) {
+	return s.GCRAStore.CompareAndSwapWithTTL(s.prefix+key, old, new, ttl)
+}
+
+// NewRateLimiter creates a rate limiter keeping its state in store, or in memory if store
+// is nil.
+func NewRateLimiter(settings *model.RateLimitSettings, trustedProxyIPHeader []string, store throttled.GCRAStore) (*RateLimiter, error) {
+	if store == nil {
+		memStore, err := memstore.New(*settings.MemoryStoreSize)
+		if err != nil {
+			return nil, errors.Wrap(err, i18n.T("api.server.start_server.rate_limiting_memory_store"))
+		}
+		store = memStore
+	}
+
+	throttledRateLimiter, err := newGCRARateLimiter(store, *settings.PerSec, *settings.MaxBurst)
 	if err != nil {
-		return nil, errors.Wrap(err, i18n.T("api.server.start_server.rate_limiting_memory_store"))
+		return nil, err
 	}
 
-	quota := throttled.RateQuota{
-		MaxRate:  throttled.PerSec(*settings.PerSec),
-		MaxBurst: *settings.MaxBurst,
+	routeRateLimiters := make([]routeRateLimiter, 0, len(settings.RouteQuotas))
+	for route, quota := range settings.RouteQuotas {
+		limiter, err := newGCRARateLimiter(prefixedGCRAStore{store, "route:" + route + ":"}, quota.PerSec, quota.MaxBurst)
+		if err != nil {
+			return nil, err
+		}
+		routeRateLimiters = append(routeRateLimiters, routeRateLimiter{route: route, limiter: limiter})
 	}
+	// Check the most specific routes first.
+	sort.Slice(routeRateLimiters, func(i, j int) bool {
+		return len(routeRateLimiters[i].route) > len(routeRateLimiters[j].route)
+	})
 
-	throttledRateLimiter, err := throttled.NewGCRARateLimiter(store, quota)
-	if err != nil {
-		return nil, errors.Wrap(err, i18n.T("api.server.start_server.rate_limiting_rate_limiter"))
+	tokenRateLimiters := make(map[string]*throttled.GCRARateLimiter, len(settings.TokenQuotas))
+	for tokenID, quota := range settings.TokenQuotas {
+		limiter, err := newGCRARateLimiter(prefixedGCRAStore{store, "token:"}, quota.PerSec, quota.MaxBurst)
+		if err != nil {
+			return nil, err
+		}
+		tokenRateLimiters[tokenID] = limiter
 	}
 
 	return &RateLimiter{
 		throttledRateLimiter: throttledRateLimiter,
+		routeRateLimiters:    routeRateLimiters,
+		tokenRateLimiters:    tokenRateLimiters,
 		useAuth:              *settings.VaryByUser,
 		useIP:                *settings.VaryByRemoteAddr,
 		header:               settings.VaryByHeader,
@@ -52,6 +103,20 @@ func NewRateLimiter(settings *model.RateLimitSettings, trustedProxyIPHeader []st
 	}, nil
 }
 
+func newGCRARateLimiter(store throttled.GCRAStore, perSec, maxBurst int) (*throttled.GCRARateLimiter, error) {
+	quota := throttled.RateQuota{
+		MaxRate:  throttled.PerSec(perSec),
+		MaxBurst: maxBurst,
+	}
+
+	throttledRateLimiter, err := throttled.NewGCRARateLimiter(store, quota)
+	if err != nil {
+		return nil, errors.Wrap(err, i18n.T("api.server.start_server.rate_limiting_rate_limiter"))
+	}
+
+	return throttledRateLimiter, nil
+}
+
 func (rl *RateLimiter) GenerateKey(r *http.Request) string {
 	key := ""
 
@@ -76,7 +141,11 @@ func (rl *RateLimiter) GenerateKey(r *http.Request) string {
 }
 
 func (rl *RateLimiter) RateLimitWriter(key string, w http.ResponseWriter) bool {
-	limited, context, err := rl.throttledRateLimiter.RateLimit(key, 1)
+	return rl.rateLimitWriter(rl.throttledRateLimiter, key, w)
+}
+
+func (rl *RateLimiter) rateLimitWriter(limiter *throttled.GCRARateLimiter, key string, w http.ResponseWriter) bool {
+	limited, context, err := limiter.RateLimit(key, 1)
 	if err != nil {
 		mlog.Error("Internal server error when rate limiting. Rate Limiting broken.", mlog.Err(err))
 		return false
@@ -99,33 +168,90 @@ func (rl *RateLimiter) UserIdRateLimit(userID string, w http.ResponseWriter) boo
 	return false
 }
 
+// SessionRateLimit rate limits an authenticated request to path per user, with the quota of
+// the route if it has one. Requests made with a personal access token that has a quota of its
+// own are also limited per token, in place of the default quota.
+func (rl *RateLimiter) SessionRateLimit(session *model.Session, path string, w http.ResponseWriter) bool {
+	if !rl.useAuth {
+		return false
+	}
+
+	routeLimiter, hasRouteQuota := rl.routeQuotaRateLimiter(path)
+
+	if tokenID := session.Props[model.SessionPropUserAccessTokenId]; tokenID != "" {
+		if tokenLimiter, ok := rl.tokenRateLimiters[tokenID]; ok {
+			if hasRouteQuota && rl.rateLimitWriter(routeLimiter, session.UserId, w) {
+				return true
+			}
+			return rl.rateLimitWriter(tokenLimiter, tokenID, w)
+		}
+	}
+
+	if hasRouteQuota {
+		return rl.rateLimitWriter(routeLimiter, session.UserId, w)
+	}
+	return rl.UserIdRateLimit(session.UserId, w)
+}
+
+// routeQuotaRateLimiter returns the rate limiter of the most specific route quota matching
+// path, if any.
+func (rl *RateLimiter) routeQuotaRateLimiter(path string) (*throttled.GCRARateLimiter, bool) {
+	for _, routeRateLimiter := range rl.routeRateLimiters {
+		if routeMatchesPath(routeRateLimiter.route, path) {
+			return routeRateLimiter.limiter, true
+		}
+	}
+
+	return nil, false
+}
+
+// routeRateLimiter returns the rate limiter of the most specific route quota matching
+// path, or the default one.
+func (rl *RateLimiter) routeRateLimiter(path string) *throttled.GCRARateLimiter {
+	if limiter, ok := rl.routeQuotaRateLimiter(path); ok {
+		return limiter
+	}
+
+	return rl.throttledRateLimiter
+}
+
+// routeMatchesPath reports whether path is route or below it, matching whole path segments
+// so that "/api/v4/users" doesn't match "/api/v4/usersearch".
+func routeMatchesPath(route, path string) bool {
+	route = strings.TrimSuffix(route, "/")
+	return path == route || strings.HasPrefix(path, route+"/")
+}
+
 func (rl *RateLimiter) RateLimitHandler(wrappedHandler http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		key := rl.GenerateKey(r)
 
-		if !rl.RateLimitWriter(key, w) {
+		if !rl.rateLimitWriter(rl.routeRateLimiter(r.URL.Path), key, w) {
 			wrappedHandler.ServeHTTP(w, r)
 		}
 	})
 }
 
 // Copied from https://github.com/throttled/throttled http.go
+//
+// The headers are set rather than added, so that a request checked against several
+// quotas reports the last one.
 func setRateLimitHeaders(w http.ResponseWriter, context throttled.RateLimitResult) {
 	if v := context.Limit; v >= 0 {
-		w.Header().Add("X-RateLimit-Limit", strconv.Itoa(v))
+		w.Header().Set("X-RateLimit-Limit", strconv.Itoa(v))
 	}
 
 	if v := context.Remaining; v >= 0 {
-		w.Header().Add("X-RateLimit-Remaining", strconv.Itoa(v))
+		w.Header().Set("X-RateLimit-Remaining", strconv.Itoa(v))
 	}
 
 	if v := context.ResetAfter; v >= 0 {
 		vi := int(math.Ceil(v.Seconds()))
-		w.Header().Add("X-RateLimit-Reset", strconv.Itoa(vi))
+		w.Header().Set("X-RateLimit-Reset", strconv.Itoa(vi))
 	}
 
 	if v := context.RetryAfter; v >= 0 {
 		vi := int(math.Ceil(v.Seconds()))
-		w.Header().Add("Retry-After", strconv.Itoa(vi))
+		w.Header().Set("Retry-After", strconv.Itoa(vi))
 	}
 }
@@ -28,11 +28,11 @@ func genRateLimitSettings(useAuth, useIP bool, header string) *model.RateLimitSe
 
 func TestNewRateLimiterSuccess(t *testing.T) {
 	settings := genRateLimitSettings(false, false, "")
-	rateLimiter, err := NewRateLimiter(settings, nil)
+	rateLimiter, err := NewRateLimiter(settings, nil, nil)
 	require.NotNil(t, rateLimiter)
 	require.NoError(t, err)
 
-	rateLimiter, err = NewRateLimiter(settings, []string{"X-Forwarded-For"})
+	rateLimiter, err = NewRateLimiter(settings, []string{"X-Forwarded-For"}, nil)
 	require.NotNil(t, rateLimiter)
 	require.NoError(t, err)
 }
@@ -40,11 +40,11 @@ func TestNewRateLimiterSuccess(t *testing.T) {
 func TestNewRateLimiterFailure(t *testing.T) {
 	invalidSettings := genRateLimitSettings(false, false, "")
 	invalidSettings.MaxBurst = model.NewPointer(-100)
-	rateLimiter, err := NewRateLimiter(invalidSettings, nil)
+	rateLimiter, err := NewRateLimiter(invalidSettings, nil, nil)
 	require.Nil(t, rateLimiter)
 	require.Error(t, err)
 
-	rateLimiter, err = NewRateLimiter(invalidSettings, []string{"X-Forwarded-For", "X-Real-Ip"})
+	rateLimiter, err = NewRateLimiter(invalidSettings, []string{"X-Forwarded-For", "X-Real-Ip"}, nil)
 	require.Nil(t, rateLimiter)
 	require.Error(t, err)
 }
@@ -82,7 +82,7 @@ func TestGenerateKey(t *testing.T) {
 			req.Header.Set(tc.header, tc.headerResult)
 		}
 
-		rateLimiter, _ := NewRateLimiter(genRateLimitSettings(tc.useAuth, tc.useIP, tc.header), nil)
+		rateLimiter, _ := NewRateLimiter(genRateLimitSettings(tc.useAuth, tc.useIP, tc.header), nil, nil)
 
 		key := rateLimiter.GenerateKey(req)
 
@@ -95,11 +95,156 @@ func TestGenerateKey_TrustedHeader(t *testing.T) {
 	req.RemoteAddr = "10.10.10.5:80"
 	req.Header.Set("X-Forwarded-For", "10.6.3.1, 10.5.1.2")
 
-	rateLimiter, _ := NewRateLimiter(genRateLimitSettings(true, true, ""), []string{"X-Forwarded-For"})
+	rateLimiter, _ := NewRateLimiter(genRateLimitSettings(true, true, ""), []string{"X-Forwarded-For"}, nil)
 	key := rateLimiter.GenerateKey(req)
 	require.Equal(t, "10.6.3.1", key, "Wrong key on test with allowed trusted proxy header")
 
-	rateLimiter, _ = NewRateLimiter(genRateLimitSettings(true, true, ""), nil)
+	rateLimiter, _ = NewRateLimiter(genRateLimitSettings(true, true, ""), nil, nil)
 	key = rateLimiter.GenerateKey(req)
 	require.Equal(t, "10.10.10.5", key, "Wrong key on test without allowed trusted proxy header")
 }
+
+func TestRateLimitHandlerRouteQuotas(t *testing.T) {
+	settings := genRateLimitSettings(false, true, "")
+	settings.MaxBurst = model.NewPointer(5)
+	settings.RouteQuotas = map[string]*model.RateLimitQuota{
+		"/api/v4/users/login": {PerSec: 1, MaxBurst: 1},
+	}
+
+	rateLimiter, err := NewRateLimiter(settings, nil, nil)
+	require.NoError(t, err)
+
+	handler := rateLimiter.RateLimitHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	serve := func(path string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("POST", path, nil)
+		req.RemoteAddr = "10.10.10.5:80"
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec
+	}
+
+	// The route quota allows a burst of one request on top of the first one.
+	require.Equal(t, http.StatusOK, serve("/api/v4/users/login").Code)
+	require.Equal(t, http.StatusOK, serve("/api/v4/users/login").Code)
+	rec := serve("/api/v4/users/login")
+	require.Equal(t, http.StatusTooManyRequests, rec.Code)
+	require.Equal(t, "2", rec.Header().Get("X-RateLimit-Limit"))
+	require.NotEmpty(t, rec.Header().Get("Retry-After"))
+
+	// Other routes use the default quota, which is tracked separately.
+	rec = serve("/api/v4/users/me")
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Equal(t, "6", rec.Header().Get("X-RateLimit-Limit"))
+	require.Len(t, rec.Header().Values("X-RateLimit-Remaining"), 1)
+
+	// Routes match whole path segments.
+	rec = serve("/api/v4/users/loginsearch")
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Equal(t, "6", rec.Header().Get("X-RateLimit-Limit"))
+	rec = serve("/api/v4/users/login/switch")
+	require.Equal(t, http.StatusTooManyRequests, rec.Code)
+}
+
+func TestRouteMatchesPath(t *testing.T) {
+	for _, tc := range []struct {
+		route   string
+		path    string
+		matches bool
+	}{
+		{"/api/v4/users", "/api/v4/users", true},
+		{"/api/v4/users", "/api/v4/users/me", true},
+		{"/api/v4/users/", "/api/v4/users/me", true},
+		{"/api/v4/users/", "/api/v4/users", true},
+		{"/api/v4/users", "/api/v4/usersearch", false},
+		{"/api/v4/users", "/api/v4", false},
+		{"/", "/api/v4/users", true},
+	} {
+		require.Equal(t, tc.matches, routeMatchesPath(tc.route, tc.path), "route %q, path %q", tc.route, tc.path)
+	}
+}
+
+func TestSessionRateLimit(t *testing.T) {
+	tokenID := model.NewId()
+	settings := genRateLimitSettings(true, false, "")
+	settings.TokenQuotas = map[string]*model.RateLimitQuota{
+		tokenID: {PerSec: 1, MaxBurst: 1},
+	}
+	settings.RouteQuotas = map[string]*model.RateLimitQuota{
+		"/api/v4/users/search": {PerSec: 1, MaxBurst: 2},
+	}
+
+	rateLimiter, err := NewRateLimiter(settings, nil, nil)
+	require.NoError(t, err)
+
+	t.Run("token with a quota of its own", func(t *testing.T) {
+		session := &model.Session{UserId: model.NewId(), Props: model.StringMap{model.SessionPropUserAccessTokenId: tokenID}}
+
+		require.False(t, rateLimiter.SessionRateLimit(session, "/api/v4/posts", httptest.NewRecorder()))
+		require.False(t, rateLimiter.SessionRateLimit(session, "/api/v4/posts", httptest.NewRecorder()))
+
+		rec := httptest.NewRecorder()
+		require.True(t, rateLimiter.SessionRateLimit(session, "/api/v4/posts", rec))
+		require.Equal(t, http.StatusTooManyRequests, rec.Code)
+		require.Equal(t, "2", rec.Header().Get("X-RateLimit-Limit"))
+	})
+
+	t.Run("other sessions use the per user quota", func(t *testing.T) {
+		session := &model.Session{UserId: model.NewId(), Props: model.StringMap{model.SessionPropUserAccessTokenId: model.NewId()}}
+
+		rec := httptest.NewRecorder()
+		require.False(t, rateLimiter.SessionRateLimit(session, "/api/v4/posts", rec))
+		require.Equal(t, "101", rec.Header().Get("X-RateLimit-Limit"))
+	})
+
+	t.Run("route quotas apply per user", func(t *testing.T) {
+		session := &model.Session{UserId: model.NewId()}
+
+		for i := 0; i < 3; i++ {
+			require.False(t, rateLimiter.SessionRateLimit(session, "/api/v4/users/search", httptest.NewRecorder()))
+		}
+
+		rec := httptest.NewRecorder()
+		require.True(t, rateLimiter.SessionRateLimit(session, "/api/v4/users/search", rec))
+		require.Equal(t, "3", rec.Header().Get("X-RateLimit-Limit"))
+
+		// The other routes of the user are still allowed.
+		require.False(t, rateLimiter.SessionRateLimit(session, "/api/v4/users/searchable", httptest.NewRecorder()))
+	})
+
+	t.Run("route quotas apply to tokens with a quota of their own", func(t *testing.T) {
+		otherTokenID := model.NewId()
+		settings := genRateLimitSettings(true, false, "")
+		settings.TokenQuotas = map[string]*model.RateLimitQuota{
+			otherTokenID: {PerSec: 1, MaxBurst: 10},
+		}
+		settings.RouteQuotas = map[string]*model.RateLimitQuota{
+			"/api/v4/users/search": {PerSec: 1, MaxBurst: 1},
+		}
+		rateLimiter, err := NewRateLimiter(settings, nil, nil)
+		require.NoError(t, err)
+
+		session := &model.Session{UserId: model.NewId(), Props: model.StringMap{model.SessionPropUserAccessTokenId: otherTokenID}}
+		require.False(t, rateLimiter.SessionRateLimit(session, "/api/v4/users/search", httptest.NewRecorder()))
+		require.False(t, rateLimiter.SessionRateLimit(session, "/api/v4/users/search", httptest.NewRecorder()))
+		require.True(t, rateLimiter.SessionRateLimit(session, "/api/v4/users/search", httptest.NewRecorder()))
+		require.False(t, rateLimiter.SessionRateLimit(session, "/api/v4/posts", httptest.NewRecorder()))
+	})
+
+	t.Run("nothing is limited per session unless varying by user", func(t *testing.T) {
+		settings := genRateLimitSettings(false, true, "")
+		settings.MaxBurst = model.NewPointer(0)
+		settings.TokenQuotas = map[string]*model.RateLimitQuota{
+			tokenID: {PerSec: 1, MaxBurst: 0},
+		}
+		rateLimiter, err := NewRateLimiter(settings, nil, nil)
+		require.NoError(t, err)
+
+		session := &model.Session{UserId: model.NewId(), Props: model.StringMap{model.SessionPropUserAccessTokenId: tokenID}}
+		for i := 0; i < 3; i++ {
+			require.False(t, rateLimiter.SessionRateLimit(session, "/api/v4/posts", httptest.NewRecorder()))
+		}
+	})
+}
@@ -0,0 +1,141 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package app
+
+import (
+	"net/http"
+	"sort"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+	"github.com/mattermost/mattermost/server/public/shared/request"
+)
+
+// readReceiptsEnabledForChannel reports whether read receipts are tracked for a channel.
+// They are limited to direct and group messages, and to channels small enough that
+// listing viewers stays meaningful.
+func (a *App) readReceiptsEnabledForChannel(c request.CTX, channel *model.Channel) (bool, *model.AppError) {
+	if !*a.Config().ServiceSettings.EnableReadReceipts {
+		return false, nil
+	}
+
+	if channel.IsGroupOrDirect() {
+		return true, nil
+	}
+
+	memberCount, appErr := a.GetChannelMemberCount(c, channel.Id)
+	if appErr != nil {
+		return false, appErr
+	}
+
+	return memberCount <= int64(*a.Config().ServiceSettings.ReadReceiptsMaxChannelMembers), nil
+}
+
+// sendsReadReceipts reports whether the user allows others to see when they have read
+// their posts.
+func (a *App) sendsReadReceipts(c request.CTX, userID string) bool {
+	pref, appErr := a.GetPreferenceByCategoryAndNameForUser(c, userID, model.PreferenceCategoryAdvancedSettings, model.PreferenceNameSendReadReceipts)
+	if appErr != nil {
+		return true
+	}
+
+	return pref.Value != "false"
+}
+
+// GetReadReceiptsForPost returns the users that have seen a post, excluding its author
+// and users that opted out of sending read receipts.
+func (a *App) GetReadReceiptsForPost(c request.CTX, postID string) ([]*model.PostReadReceipt, *model.AppError) {
+	if !*a.Config().ServiceSettings.EnableReadReceipts {
+		return nil, model.NewAppError("GetReadReceiptsForPost", "app.read_receipts.disabled.app_error", nil, "", http.StatusNotImplemented)
+	}
+
+	post, appErr := a.GetSinglePost(c, postID, false)
+	if appErr != nil {
+		return nil, appErr
+	}
+
+	channel, appErr := a.GetChannel(c, post.ChannelId)
+	if appErr != nil {
+		return nil, appErr
+	}
+
+	enabled, appErr := a.readReceiptsEnabledForChannel(c, channel)
+	if appErr != nil {
+		return nil, appErr
+	}
+	if !enabled {
+		return nil, model.NewAppError("GetReadReceiptsForPost", "app.read_receipts.channel_too_large.app_error", map[string]any{"Max": *a.Config().ServiceSettings.ReadReceiptsMaxChannelMembers}, "", http.StatusBadRequest)
+	}
+
+	times, err := a.Srv().Store().Channel().GetMembersLastViewedAtSince(c.Context(), channel.Id, post.CreateAt)
+	if err != nil {
+		return nil, model.NewAppError("GetReadReceiptsForPost", "app.read_receipts.get.app_error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+	delete(times, post.UserId)
+
+	viewerIDs := make([]string, 0, len(times))
+	for userID := range times {
+		viewerIDs = append(viewerIDs, userID)
+	}
+
+	optedOut, err := a.Srv().Store().Preference().GetCategoryAndNameForUsers(viewerIDs, model.PreferenceCategoryAdvancedSettings, model.PreferenceNameSendReadReceipts)
+	if err != nil {
+		return nil, model.NewAppError("GetReadReceiptsForPost", "app.read_receipts.get.app_error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+	for _, pref := range optedOut {
+		if pref.Value == "false" {
+			delete(times, pref.UserId)
+		}
+	}
+
+	receipts := make([]*model.PostReadReceipt, 0, len(times))
+	for userID, lastViewedAt := range times {
+		receipts = append(receipts, &model.PostReadReceipt{
+			PostId:       post.Id,
+			UserId:       userID,
+			LastViewedAt: lastViewedAt,
+		})
+	}
+
+	sort.Slice(receipts, func(i, j int) bool {
+		if receipts[i].LastViewedAt != receipts[j].LastViewedAt {
+			return receipts[i].LastViewedAt < receipts[j].LastViewedAt
+		}
+		return receipts[i].UserId < receipts[j].UserId
+	})
+
+	return receipts, nil
+}
+
+// publishReadReceipts lets the other members of the viewed channels know up to which
+// point the user has read them, so that clients can mark posts as seen. It runs after the
+// request has been answered, so it must be given a context that outlives the request. The
+// caller checks that read receipts are enabled.
+func (a *App) publishReadReceipts(c request.CTX, userID string, times map[string]int64) {
+	if !a.sendsReadReceipts(c, userID) {
+		return
+	}
+
+	for channelID, lastViewedAt := range times {
+		channel, appErr := a.GetChannel(c, channelID)
+		if appErr != nil {
+			c.Logger().Warn("Failed to get channel to publish read receipts", mlog.String("channel_id", channelID), mlog.Err(appErr))
+			continue
+		}
+
+		enabled, appErr := a.readReceiptsEnabledForChannel(c, channel)
+		if appErr != nil {
+			c.Logger().Warn("Failed to check whether read receipts are enabled for channel", mlog.String("channel_id", channelID), mlog.Err(appErr))
+			continue
+		}
+		if !enabled {
+			continue
+		}
+
+		message := model.NewWebSocketEvent(model.WebsocketEventReadReceiptUpdated, "", channelID, "", map[string]bool{userID: true}, "")
+		message.Add("user_id", userID)
+		message.Add("last_viewed_at", lastViewedAt)
+		a.Publish(message)
+	}
+}
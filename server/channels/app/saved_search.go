@@ -0,0 +1,250 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package app
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/shared/i18n"
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+	"github.com/mattermost/mattermost/server/public/shared/request"
+	"github.com/mattermost/mattermost/server/v8/channels/store"
+)
+
+func (a *App) SaveSavedSearch(rctx request.CTX, savedSearch *model.SavedSearch) (*model.SavedSearch, *model.AppError) {
+	if !*a.Config().ServiceSettings.EnableSavedSearches {
+		return nil, model.NewAppError("SaveSavedSearch", "app.saved_search.feature_disabled", nil, "", http.StatusNotImplemented)
+	}
+
+	count, err := a.Srv().Store().SavedSearch().CountForUser(savedSearch.UserId)
+	if err != nil {
+		return nil, model.NewAppError("SaveSavedSearch", "app.saved_search.save.app_error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+	if count >= model.SavedSearchMaxPerUser {
+		return nil, model.NewAppError("SaveSavedSearch", "app.saved_search.save.too_many.app_error", map[string]any{"Max": model.SavedSearchMaxPerUser}, "", http.StatusBadRequest)
+	}
+
+	a.resolveSavedSearchFilters(rctx, savedSearch)
+
+	savedSavedSearch, err := a.Srv().Store().SavedSearch().Save(savedSearch)
+	if err != nil {
+		var appErr *model.AppError
+		switch {
+		case errors.As(err, &appErr):
+			return nil, appErr
+		default:
+			return nil, model.NewAppError("SaveSavedSearch", "app.saved_search.save.app_error", nil, "", http.StatusInternalServerError).Wrap(err)
+		}
+	}
+
+	return savedSavedSearch, nil
+}
+
+func (a *App) GetUserTeamSavedSearches(rctx request.CTX, userID, teamID string) ([]*model.SavedSearch, *model.AppError) {
+	if !*a.Config().ServiceSettings.EnableSavedSearches {
+		return nil, model.NewAppError("GetUserTeamSavedSearches", "app.saved_search.feature_disabled", nil, "", http.StatusNotImplemented)
+	}
+
+	savedSearches, err := a.Srv().Store().SavedSearch().GetForUser(userID, teamID)
+	if err != nil {
+		return nil, model.NewAppError("GetUserTeamSavedSearches", "app.saved_search.get_for_user.app_error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+
+	return savedSearches, nil
+}
+
+func (a *App) GetSavedSearch(rctx request.CTX, userID, savedSearchID string) (*model.SavedSearch, *model.AppError) {
+	if !*a.Config().ServiceSettings.EnableSavedSearches {
+		return nil, model.NewAppError("GetSavedSearch", "app.saved_search.feature_disabled", nil, "", http.StatusNotImplemented)
+	}
+
+	savedSearch, err := a.Srv().Store().SavedSearch().Get(savedSearchID)
+	if err != nil {
+		var nfErr *store.ErrNotFound
+		switch {
+		case errors.As(err, &nfErr):
+			return nil, model.NewAppError("GetSavedSearch", "app.saved_search.get.app_error", nil, "", http.StatusNotFound).Wrap(err)
+		default:
+			return nil, model.NewAppError("GetSavedSearch", "app.saved_search.get.app_error", nil, "", http.StatusInternalServerError).Wrap(err)
+		}
+	}
+
+	// Saved searches are private to their owner, so don't reveal whether the ID exists.
+	if savedSearch.UserId != userID {
+		return nil, model.NewAppError("GetSavedSearch", "app.saved_search.get.app_error", nil, "", http.StatusNotFound)
+	}
+
+	return savedSearch, nil
+}
+
+func (a *App) UpdateSavedSearch(rctx request.CTX, userID string, savedSearch *model.SavedSearch) (*model.SavedSearch, *model.AppError) {
+	existingSavedSearch, appErr := a.GetSavedSearch(rctx, userID, savedSearch.Id)
+	if appErr != nil {
+		return nil, appErr
+	}
+
+	savedSearch.RestoreNonUpdatableFields(existingSavedSearch)
+	a.resolveSavedSearchFilters(rctx, savedSearch)
+
+	updatedSavedSearch, err := a.Srv().Store().SavedSearch().Update(savedSearch)
+	if err != nil {
+		var appErr *model.AppError
+		switch {
+		case errors.As(err, &appErr):
+			return nil, appErr
+		default:
+			return nil, model.NewAppError("UpdateSavedSearch", "app.saved_search.update.app_error", nil, "", http.StatusInternalServerError).Wrap(err)
+		}
+	}
+
+	return updatedSavedSearch, nil
+}
+
+func (a *App) DeleteSavedSearch(rctx request.CTX, userID, savedSearchID string) (*model.SavedSearch, *model.AppError) {
+	savedSearch, appErr := a.GetSavedSearch(rctx, userID, savedSearchID)
+	if appErr != nil {
+		return nil, appErr
+	}
+
+	if err := a.Srv().Store().SavedSearch().PermanentDelete(savedSearch.Id); err != nil {
+		return nil, model.NewAppError("DeleteSavedSearch", "app.saved_search.delete.app_error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+
+	return savedSearch, nil
+}
+
+// RunSavedSearch searches the posts the owner of a saved search can see with its terms.
+func (a *App) RunSavedSearch(rctx request.CTX, savedSearch *model.SavedSearch, page, perPage int) (*model.PostSearchResults, *model.AppError) {
+	return a.SearchPostsForUser(rctx, savedSearch.Terms, savedSearch.UserId, savedSearch.TeamId, savedSearch.IsOrSearch, false, savedSearch.TimeZoneOffset, page, perPage)
+}
+
+// alertSavedSearchSubscribers alerts the members of the channel of a new post whose subscribed
+// saved searches match it. The post is matched against the searches directly rather than through
+// the search engine, so the alerts don't depend on the post having been indexed yet.
+func (a *App) alertSavedSearchSubscribers(rctx request.CTX, post *model.Post, channel *model.Channel) {
+	if !*a.Config().ServiceSettings.EnableSavedSearches || post.IsSystemMessage() || channel.DeleteAt != 0 {
+		return
+	}
+
+	// Don't alert about the alerts themselves.
+	if post.GetProp(model.PostPropsSavedSearchId) != nil {
+		return
+	}
+
+	savedSearches, err := a.Srv().Store().SavedSearch().GetSubscribedForChannel(channel.Id, channel.TeamId)
+	if err != nil {
+		rctx.Logger().Warn("Failed to get the subscribed saved searches of the channel", mlog.String("channel_id", channel.Id), mlog.Err(err))
+		return
+	}
+
+	// Users are alerted once per post, however many of their saved searches match it.
+	alertedUsers := map[string]bool{}
+	for _, savedSearch := range savedSearches {
+		if savedSearch.UserId == post.UserId || alertedUsers[savedSearch.UserId] {
+			continue
+		}
+
+		if !a.savedSearchMatchesPost(savedSearch, post) {
+			continue
+		}
+		alertedUsers[savedSearch.UserId] = true
+
+		if appErr := a.sendSavedSearchAlert(rctx, savedSearch, post); appErr != nil {
+			rctx.Logger().Warn("Failed to send saved search alert", mlog.String("saved_search_id", savedSearch.Id), mlog.String("post_id", post.Id), mlog.Err(appErr))
+		}
+	}
+}
+
+// resolveSavedSearchFilters stores the IDs of the channels and users named in the filters of a
+// saved search. The filters are the same in all the params, so they're only resolved once.
+// Names that don't resolve are kept as they are and match no post, as they do when searching.
+func (a *App) resolveSavedSearchFilters(rctx request.CTX, savedSearch *model.SavedSearch) {
+	savedSearch.InChannelIds = nil
+	savedSearch.ExcludedChannelIds = nil
+	savedSearch.FromUserIds = nil
+	savedSearch.ExcludedUserIds = nil
+
+	paramsList := savedSearch.SearchParams()
+	if len(paramsList) == 0 {
+		return
+	}
+
+	filters := paramsList[0]
+	savedSearch.InChannelIds = a.convertChannelNamesToChannelIds(rctx, filters.InChannels, savedSearch.UserId, savedSearch.TeamId, false)
+	savedSearch.ExcludedChannelIds = a.convertChannelNamesToChannelIds(rctx, filters.ExcludedChannels, savedSearch.UserId, savedSearch.TeamId, false)
+	savedSearch.FromUserIds = a.convertUserNameToUserIds(rctx, filters.FromUsers)
+	savedSearch.ExcludedUserIds = a.convertUserNameToUserIds(rctx, filters.ExcludedUsers)
+}
+
+// savedSearchMatchesPost matches a post against the terms of a saved search, using the IDs its
+// filters were resolved to when it was saved.
+func (a *App) savedSearchMatchesPost(savedSearch *model.SavedSearch, post *model.Post) bool {
+	for _, params := range savedSearch.SearchParams() {
+		params.InChannels = savedSearch.InChannelIds
+		params.ExcludedChannels = savedSearch.ExcludedChannelIds
+		params.FromUsers = savedSearch.FromUserIds
+		params.ExcludedUsers = savedSearch.ExcludedUserIds
+
+		if params.MatchesPost(post) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (a *App) sendSavedSearchAlert(rctx request.CTX, savedSearch *model.SavedSearch, post *model.Post) *model.AppError {
+	if savedSearch.AlertType != model.SavedSearchAlertDirectMessage {
+		message := model.NewWebSocketEvent(model.WebsocketEventSavedSearchMatched, "", "", savedSearch.UserId, nil, "")
+		message.Add("saved_search_id", savedSearch.Id)
+		message.Add("saved_search_name", savedSearch.Name)
+		message.Add("post_id", post.Id)
+		message.Add("channel_id", post.ChannelId)
+		a.Publish(message)
+		return nil
+	}
+
+	systemBot, appErr := a.GetSystemBot(rctx)
+	if appErr != nil {
+		return appErr
+	}
+
+	user, appErr := a.GetUser(savedSearch.UserId)
+	if appErr != nil {
+		return appErr
+	}
+
+	team, appErr := a.GetTeam(savedSearch.TeamId)
+	if appErr != nil {
+		return appErr
+	}
+
+	channel, appErr := a.GetOrCreateDirectChannel(rctx, savedSearch.UserId, systemBot.UserId)
+	if appErr != nil {
+		return appErr
+	}
+
+	T := i18n.GetUserTranslations(user.Locale)
+	dm := &model.Post{
+		ChannelId: channel.Id,
+		UserId:    systemBot.UserId,
+		Message: T("app.saved_search.alert.message", model.StringInterface{
+			"Name":     savedSearch.Name,
+			"SiteURL":  *a.Config().ServiceSettings.SiteURL,
+			"TeamName": team.Name,
+			"PostId":   post.Id,
+		}),
+		Props: model.StringInterface{
+			model.PostPropsSavedSearchId: savedSearch.Id,
+		},
+	}
+
+	if _, appErr := a.CreatePost(rctx, dm, channel, false, false); appErr != nil {
+		return appErr
+	}
+
+	return nil
+}
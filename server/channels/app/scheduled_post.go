@@ -0,0 +1,268 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package app
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+	"github.com/mattermost/mattermost/server/public/shared/request"
+	"github.com/mattermost/mattermost/server/v8/channels/store"
+)
+
+const getPendingScheduledPostsPageSize = 100
+
+func (a *App) SaveScheduledPost(rctx request.CTX, scheduledPost *model.ScheduledPost, connectionID string) (*model.ScheduledPost, *model.AppError) {
+	if !*a.Config().ServiceSettings.ScheduledPosts {
+		return nil, model.NewAppError("SaveScheduledPost", "app.scheduled_post.feature_disabled", nil, "", http.StatusNotImplemented)
+	}
+
+	channel, errCh := a.Srv().Store().Channel().Get(scheduledPost.ChannelId, true)
+	if errCh != nil {
+		return nil, model.NewAppError("SaveScheduledPost", "api.context.invalid_param.app_error", map[string]any{"Name": "scheduled_post.channel_id"}, "", http.StatusBadRequest).Wrap(errCh)
+	}
+
+	if channel.DeleteAt != 0 {
+		return nil, model.NewAppError("SaveScheduledPost", "app.scheduled_post.save.channel_deleted.app_error", nil, "", http.StatusBadRequest)
+	}
+
+	savedScheduledPost, err := a.Srv().Store().ScheduledPost().Save(scheduledPost)
+	if err != nil {
+		var appErr *model.AppError
+		switch {
+		case errors.As(err, &appErr):
+			return nil, appErr
+		default:
+			return nil, model.NewAppError("SaveScheduledPost", "app.scheduled_post.save.app_error", nil, "", http.StatusInternalServerError).Wrap(err)
+		}
+	}
+
+	a.publishScheduledPostEvent(rctx, model.WebsocketScheduledPostCreated, savedScheduledPost, connectionID)
+
+	return savedScheduledPost, nil
+}
+
+func (a *App) GetUserTeamScheduledPosts(rctx request.CTX, userID, teamID string) ([]*model.ScheduledPost, *model.AppError) {
+	if !*a.Config().ServiceSettings.ScheduledPosts {
+		return nil, model.NewAppError("GetUserTeamScheduledPosts", "app.scheduled_post.feature_disabled", nil, "", http.StatusNotImplemented)
+	}
+
+	scheduledPosts, err := a.Srv().Store().ScheduledPost().GetScheduledPostsForUser(userID, teamID)
+	if err != nil {
+		return nil, model.NewAppError("GetUserTeamScheduledPosts", "app.scheduled_post.get_for_user.app_error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+
+	return scheduledPosts, nil
+}
+
+func (a *App) GetScheduledPost(rctx request.CTX, userID, scheduledPostID string) (*model.ScheduledPost, *model.AppError) {
+	scheduledPost, err := a.Srv().Store().ScheduledPost().Get(scheduledPostID)
+	if err != nil {
+		var nfErr *store.ErrNotFound
+		switch {
+		case errors.As(err, &nfErr):
+			return nil, model.NewAppError("GetScheduledPost", "app.scheduled_post.get.app_error", nil, "", http.StatusNotFound).Wrap(err)
+		default:
+			return nil, model.NewAppError("GetScheduledPost", "app.scheduled_post.get.app_error", nil, "", http.StatusInternalServerError).Wrap(err)
+		}
+	}
+
+	// Scheduled posts are private to their author, so don't reveal whether the ID exists.
+	if scheduledPost.UserId != userID {
+		return nil, model.NewAppError("GetScheduledPost", "app.scheduled_post.get.app_error", nil, "", http.StatusNotFound)
+	}
+
+	return scheduledPost, nil
+}
+
+func (a *App) UpdateScheduledPost(rctx request.CTX, userID string, scheduledPost *model.ScheduledPost, connectionID string) (*model.ScheduledPost, *model.AppError) {
+	if !*a.Config().ServiceSettings.ScheduledPosts {
+		return nil, model.NewAppError("UpdateScheduledPost", "app.scheduled_post.feature_disabled", nil, "", http.StatusNotImplemented)
+	}
+
+	existingScheduledPost, appErr := a.GetScheduledPost(rctx, userID, scheduledPost.Id)
+	if appErr != nil {
+		return nil, appErr
+	}
+
+	scheduledPost.RestoreNonUpdatableFields(existingScheduledPost)
+
+	// Editing a scheduled post that failed to send gives it another chance.
+	scheduledPost.ProcessedAt = 0
+	scheduledPost.ErrorCode = ""
+
+	if err := a.Srv().Store().ScheduledPost().Update(scheduledPost); err != nil {
+		var appErr *model.AppError
+		switch {
+		case errors.As(err, &appErr):
+			return nil, appErr
+		default:
+			return nil, model.NewAppError("UpdateScheduledPost", "app.scheduled_post.update.app_error", nil, "", http.StatusInternalServerError).Wrap(err)
+		}
+	}
+
+	a.publishScheduledPostEvent(rctx, model.WebsocketScheduledPostUpdated, scheduledPost, connectionID)
+
+	return scheduledPost, nil
+}
+
+func (a *App) DeleteScheduledPost(rctx request.CTX, userID, scheduledPostID, connectionID string) (*model.ScheduledPost, *model.AppError) {
+	if !*a.Config().ServiceSettings.ScheduledPosts {
+		return nil, model.NewAppError("DeleteScheduledPost", "app.scheduled_post.feature_disabled", nil, "", http.StatusNotImplemented)
+	}
+
+	scheduledPost, appErr := a.GetScheduledPost(rctx, userID, scheduledPostID)
+	if appErr != nil {
+		return nil, appErr
+	}
+
+	if err := a.Srv().Store().ScheduledPost().PermanentlyDelete([]string{scheduledPost.Id}); err != nil {
+		return nil, model.NewAppError("DeleteScheduledPost", "app.scheduled_post.delete.app_error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+
+	a.publishScheduledPostEvent(rctx, model.WebsocketScheduledPostDeleted, scheduledPost, connectionID)
+
+	return scheduledPost, nil
+}
+
+// ProcessScheduledPosts sends every scheduled post that is due. Posts that can no longer be
+// sent on behalf of their author are kept with an error code explaining why.
+func (a *App) ProcessScheduledPosts(rctx request.CTX) error {
+	beforeTime := model.GetMillis()
+	afterTime := int64(0)
+	lastScheduledPostID := ""
+
+	for {
+		scheduledPosts, err := a.Srv().Store().ScheduledPost().GetPendingScheduledPosts(beforeTime, afterTime, lastScheduledPostID, getPendingScheduledPostsPageSize)
+		if err != nil {
+			return err
+		}
+
+		for _, scheduledPost := range scheduledPosts {
+			a.processScheduledPost(rctx, scheduledPost)
+		}
+
+		if len(scheduledPosts) < getPendingScheduledPostsPageSize {
+			return nil
+		}
+
+		last := scheduledPosts[len(scheduledPosts)-1]
+		afterTime = last.ScheduledAt
+		lastScheduledPostID = last.Id
+	}
+}
+
+func (a *App) processScheduledPost(rctx request.CTX, scheduledPost *model.ScheduledPost) {
+	logger := rctx.Logger().With(
+		mlog.String("scheduled_post_id", scheduledPost.Id),
+		mlog.String("user_id", scheduledPost.UserId),
+		mlog.String("channel_id", scheduledPost.ChannelId),
+	)
+
+	channel, errorCode, err := a.canSendScheduledPost(rctx, scheduledPost)
+	if err != nil {
+		// Transient failures are retried on the next run.
+		logger.Warn("Failed to validate scheduled post", mlog.Err(err))
+		return
+	}
+
+	if errorCode == "" {
+		post := scheduledPost.ToPost()
+		if _, appErr := a.CreatePost(rctx, post, channel, true, false); appErr != nil {
+			logger.Warn("Failed to send scheduled post", mlog.Err(appErr))
+			if appErr.StatusCode == http.StatusBadRequest {
+				errorCode = model.ScheduledPostErrorCodeInvalidPost
+			} else {
+				errorCode = model.ScheduledPostErrorCodeUnableToSend
+			}
+		}
+	}
+
+	if errorCode != "" {
+		scheduledPost.ProcessedAt = model.GetMillis()
+		scheduledPost.ErrorCode = errorCode
+		if err := a.Srv().Store().ScheduledPost().Update(scheduledPost); err != nil {
+			logger.Error("Failed to record scheduled post failure", mlog.String("error_code", errorCode), mlog.Err(err))
+			return
+		}
+		a.publishScheduledPostEvent(rctx, model.WebsocketScheduledPostUpdated, scheduledPost, "")
+		return
+	}
+
+	if err := a.Srv().Store().ScheduledPost().PermanentlyDelete([]string{scheduledPost.Id}); err != nil {
+		logger.Error("Failed to delete sent scheduled post", mlog.Err(err))
+		return
+	}
+	a.publishScheduledPostEvent(rctx, model.WebsocketScheduledPostDeleted, scheduledPost, "")
+}
+
+// canSendScheduledPost re-checks, at send time, that the author is still allowed to post
+// the scheduled post. It returns a scheduled post error code when it is not, and an error
+// when the check itself failed.
+func (a *App) canSendScheduledPost(rctx request.CTX, scheduledPost *model.ScheduledPost) (*model.Channel, string, error) {
+	var nfErr *store.ErrNotFound
+
+	user, err := a.Srv().Store().User().Get(rctx.Context(), scheduledPost.UserId)
+	if err != nil {
+		if errors.As(err, &nfErr) {
+			return nil, model.ScheduledPostErrorCodeUserDoesNotExist, nil
+		}
+		return nil, "", err
+	}
+	if user.DeleteAt != 0 {
+		return nil, model.ScheduledPostErrorCodeUserDeleted, nil
+	}
+
+	channel, err := a.Srv().Store().Channel().Get(scheduledPost.ChannelId, true)
+	if err != nil {
+		if errors.As(err, &nfErr) {
+			return nil, model.ScheduledPostErrorCodeChannelNotFound, nil
+		}
+		return nil, "", err
+	}
+	if channel.DeleteAt != 0 {
+		return nil, model.ScheduledPostErrorCodeChannelArchived, nil
+	}
+
+	if _, err = a.Srv().Store().Channel().GetMember(rctx.Context(), channel.Id, user.Id); err != nil {
+		if errors.As(err, &nfErr) {
+			return nil, model.ScheduledPostErrorCodeNoChannelMember, nil
+		}
+		return nil, "", err
+	}
+
+	if !a.HasPermissionToChannel(rctx, user.Id, channel.Id, model.PermissionCreatePost) {
+		return nil, model.ScheduledPostErrorCodeNoChannelPermission, nil
+	}
+
+	if scheduledPost.RootId != "" {
+		rootPost, err := a.Srv().Store().Post().GetSingle(rctx, scheduledPost.RootId, true)
+		if err != nil {
+			if errors.As(err, &nfErr) {
+				return nil, model.ScheduledPostErrorCodeThreadDeleted, nil
+			}
+			return nil, "", err
+		}
+		if rootPost.DeleteAt != 0 {
+			return nil, model.ScheduledPostErrorCodeThreadDeleted, nil
+		}
+	}
+
+	return channel, "", nil
+}
+
+func (a *App) publishScheduledPostEvent(rctx request.CTX, eventType model.WebsocketEventType, scheduledPost *model.ScheduledPost, connectionID string) {
+	scheduledPostJSON, err := json.Marshal(scheduledPost)
+	if err != nil {
+		rctx.Logger().Warn("Failed to encode scheduled post to JSON", mlog.Err(err))
+		return
+	}
+
+	message := model.NewWebSocketEvent(eventType, "", "", scheduledPost.UserId, nil, connectionID)
+	message.Add("scheduledPost", string(scheduledPostJSON))
+	a.Publish(message)
+}
@@ -0,0 +1,203 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package app
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost/server/public/model"
+)
+
+func TestScheduledPostCRUD(t *testing.T) {
+	th := Setup(t).InitBasic()
+	defer th.TearDown()
+
+	th.App.UpdateConfig(func(cfg *model.Config) { *cfg.ServiceSettings.ScheduledPosts = true })
+
+	user := th.BasicUser
+	channel := th.BasicChannel
+
+	scheduledPost := &model.ScheduledPost{
+		Draft: model.Draft{
+			UserId:    user.Id,
+			ChannelId: channel.Id,
+			Message:   "scheduled post",
+		},
+		ScheduledAt: model.GetMillis() + 100000,
+	}
+
+	saved, appErr := th.App.SaveScheduledPost(th.Context, scheduledPost, "")
+	require.Nil(t, appErr)
+	require.NotEmpty(t, saved.Id)
+
+	t.Run("get scheduled posts for team", func(t *testing.T) {
+		scheduledPosts, appErr := th.App.GetUserTeamScheduledPosts(th.Context, user.Id, th.BasicTeam.Id)
+		require.Nil(t, appErr)
+		require.Len(t, scheduledPosts, 1)
+		assert.Equal(t, saved.Id, scheduledPosts[0].Id)
+	})
+
+	t.Run("other users cannot see the scheduled post", func(t *testing.T) {
+		_, appErr := th.App.GetScheduledPost(th.Context, th.BasicUser2.Id, saved.Id)
+		require.NotNil(t, appErr)
+		assert.Equal(t, http.StatusNotFound, appErr.StatusCode)
+	})
+
+	t.Run("update scheduled post", func(t *testing.T) {
+		update := &model.ScheduledPost{
+			Draft: model.Draft{
+				ChannelId: model.NewId(),
+				Message:   "updated message",
+			},
+			Id:          saved.Id,
+			ScheduledAt: saved.ScheduledAt + 1000,
+		}
+
+		updated, appErr := th.App.UpdateScheduledPost(th.Context, user.Id, update, "")
+		require.Nil(t, appErr)
+		assert.Equal(t, "updated message", updated.Message)
+		assert.Equal(t, channel.Id, updated.ChannelId)
+		assert.Equal(t, saved.ScheduledAt+1000, updated.ScheduledAt)
+	})
+
+	t.Run("cannot schedule a post in an archived channel", func(t *testing.T) {
+		archived := th.CreateChannel(th.Context, th.BasicTeam)
+		require.Nil(t, th.App.DeleteChannel(th.Context, archived, user.Id))
+
+		_, appErr := th.App.SaveScheduledPost(th.Context, &model.ScheduledPost{
+			Draft: model.Draft{
+				UserId:    user.Id,
+				ChannelId: archived.Id,
+				Message:   "scheduled post",
+			},
+			ScheduledAt: model.GetMillis() + 100000,
+		}, "")
+		require.NotNil(t, appErr)
+	})
+
+	t.Run("delete scheduled post", func(t *testing.T) {
+		_, appErr := th.App.DeleteScheduledPost(th.Context, th.BasicUser2.Id, saved.Id, "")
+		require.NotNil(t, appErr)
+
+		deleted, appErr := th.App.DeleteScheduledPost(th.Context, user.Id, saved.Id, "")
+		require.Nil(t, appErr)
+		assert.Equal(t, saved.Id, deleted.Id)
+
+		scheduledPosts, appErr := th.App.GetUserTeamScheduledPosts(th.Context, user.Id, th.BasicTeam.Id)
+		require.Nil(t, appErr)
+		assert.Empty(t, scheduledPosts)
+	})
+
+	t.Run("feature disabled", func(t *testing.T) {
+		th.App.UpdateConfig(func(cfg *model.Config) { *cfg.ServiceSettings.ScheduledPosts = false })
+		defer th.App.UpdateConfig(func(cfg *model.Config) { *cfg.ServiceSettings.ScheduledPosts = true })
+
+		_, appErr := th.App.GetUserTeamScheduledPosts(th.Context, user.Id, th.BasicTeam.Id)
+		require.NotNil(t, appErr)
+		assert.Equal(t, http.StatusNotImplemented, appErr.StatusCode)
+	})
+}
+
+func TestProcessScheduledPosts(t *testing.T) {
+	th := Setup(t).InitBasic()
+	defer th.TearDown()
+
+	th.App.UpdateConfig(func(cfg *model.Config) { *cfg.ServiceSettings.ScheduledPosts = true })
+
+	// Scheduled posts can't be created in the past, so save them straight to the store
+	// with a creation time that makes them already due.
+	saveDueScheduledPost := func(t *testing.T, userID, channelID, rootID string) *model.ScheduledPost {
+		t.Helper()
+		now := model.GetMillis()
+		scheduledPost, err := th.App.Srv().Store().ScheduledPost().Save(&model.ScheduledPost{
+			Draft: model.Draft{
+				CreateAt:  now - 10000,
+				UserId:    userID,
+				ChannelId: channelID,
+				RootId:    rootID,
+				Message:   "scheduled post " + model.NewId(),
+			},
+			ScheduledAt: now - 5000,
+		})
+		require.NoError(t, err)
+		return scheduledPost
+	}
+
+	t.Run("due scheduled post is sent and removed", func(t *testing.T) {
+		scheduledPost := saveDueScheduledPost(t, th.BasicUser.Id, th.BasicChannel.Id, "")
+
+		require.NoError(t, th.App.ProcessScheduledPosts(th.Context))
+
+		posts, err := th.App.Srv().Store().Post().GetPostsSince(model.GetPostsSinceOptions{ChannelId: th.BasicChannel.Id, Time: scheduledPost.CreateAt}, true, map[string]bool{})
+		require.NoError(t, err)
+		found := false
+		for _, post := range posts.Posts {
+			if post.Message == scheduledPost.Message && post.UserId == th.BasicUser.Id {
+				found = true
+			}
+		}
+		assert.True(t, found)
+
+		_, err = th.App.Srv().Store().ScheduledPost().Get(scheduledPost.Id)
+		require.Error(t, err)
+	})
+
+	t.Run("archived channel is recorded as a failure", func(t *testing.T) {
+		channel := th.CreateChannel(th.Context, th.BasicTeam)
+		scheduledPost := saveDueScheduledPost(t, th.BasicUser.Id, channel.Id, "")
+		require.Nil(t, th.App.DeleteChannel(th.Context, channel, th.BasicUser.Id))
+
+		require.NoError(t, th.App.ProcessScheduledPosts(th.Context))
+
+		failed, err := th.App.Srv().Store().ScheduledPost().Get(scheduledPost.Id)
+		require.NoError(t, err)
+		assert.Equal(t, model.ScheduledPostErrorCodeChannelArchived, failed.ErrorCode)
+		assert.NotZero(t, failed.ProcessedAt)
+	})
+
+	t.Run("author no longer in the channel", func(t *testing.T) {
+		channel := th.CreateChannel(th.Context, th.BasicTeam)
+		th.AddUserToChannel(th.BasicUser2, channel)
+		scheduledPost := saveDueScheduledPost(t, th.BasicUser2.Id, channel.Id, "")
+		require.Nil(t, th.App.RemoveUserFromChannel(th.Context, th.BasicUser2.Id, th.BasicUser.Id, channel))
+
+		require.NoError(t, th.App.ProcessScheduledPosts(th.Context))
+
+		failed, err := th.App.Srv().Store().ScheduledPost().Get(scheduledPost.Id)
+		require.NoError(t, err)
+		assert.Equal(t, model.ScheduledPostErrorCodeNoChannelMember, failed.ErrorCode)
+	})
+
+	t.Run("deleted thread", func(t *testing.T) {
+		rootPost := th.CreatePost(th.BasicChannel)
+		scheduledPost := saveDueScheduledPost(t, th.BasicUser.Id, th.BasicChannel.Id, rootPost.Id)
+		_, appErr := th.App.DeletePost(th.Context, rootPost.Id, th.BasicUser.Id)
+		require.Nil(t, appErr)
+
+		require.NoError(t, th.App.ProcessScheduledPosts(th.Context))
+
+		failed, err := th.App.Srv().Store().ScheduledPost().Get(scheduledPost.Id)
+		require.NoError(t, err)
+		assert.Equal(t, model.ScheduledPostErrorCodeThreadDeleted, failed.ErrorCode)
+	})
+
+	t.Run("failed scheduled posts are not retried", func(t *testing.T) {
+		channel := th.CreateChannel(th.Context, th.BasicTeam)
+		scheduledPost := saveDueScheduledPost(t, th.BasicUser.Id, channel.Id, "")
+		require.Nil(t, th.App.DeleteChannel(th.Context, channel, th.BasicUser.Id))
+
+		require.NoError(t, th.App.ProcessScheduledPosts(th.Context))
+		first, err := th.App.Srv().Store().ScheduledPost().Get(scheduledPost.Id)
+		require.NoError(t, err)
+
+		require.NoError(t, th.App.ProcessScheduledPosts(th.Context))
+		second, err := th.App.Srv().Store().ScheduledPost().Get(scheduledPost.Id)
+		require.NoError(t, err)
+		assert.Equal(t, first.ProcessedAt, second.ProcessedAt)
+	})
+}
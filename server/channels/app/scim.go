@@ -0,0 +1,411 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package app
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"slices"
+	"sort"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+	"github.com/mattermost/mattermost/server/public/shared/request"
+	"github.com/mattermost/mattermost/server/v8/channels/store"
+)
+
+// GetScimUsers returns the users matching the given SCIM filter, skipping the first offset
+// ones, along with the total number of matching users.
+func (a *App) GetScimUsers(filter model.ScimFilter, offset, limit int) ([]*model.User, int64, *model.AppError) {
+	if len(filter) > 0 {
+		user, appErr := a.getScimUserByFilter(filter)
+		if appErr != nil {
+			return nil, 0, appErr
+		}
+
+		if user == nil || !a.isScimManagedUser(user) || !filter.MatchesUser(model.ScimUserFromUser(user, "")) {
+			return []*model.User{}, 0, nil
+		}
+
+		if offset > 0 {
+			return []*model.User{}, 1, nil
+		}
+		return []*model.User{user}, 1, nil
+	}
+
+	authService := *a.Config().ScimSettings.AuthService
+	total, err := a.Srv().Store().User().CountNonAdminsUsingAuthService(authService)
+	if err != nil {
+		return nil, 0, model.NewAppError("GetScimUsers", "app.user.get_total_users_count.app_error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+
+	if limit <= 0 {
+		return []*model.User{}, total, nil
+	}
+
+	users, err := a.Srv().Store().User().GetNonAdminsUsingAuthService(authService, offset, limit)
+	if err != nil {
+		return nil, 0, model.NewAppError("GetScimUsers", "app.user.get_profiles.app_error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+
+	return users, total, nil
+}
+
+// GetScimUser returns a user that SCIM manages.
+func (a *App) GetScimUser(userID string) (*model.User, *model.AppError) {
+	user, appErr := a.GetUser(userID)
+	if appErr != nil {
+		return nil, appErr
+	}
+
+	if !a.isScimManagedUser(user) {
+		return nil, model.NewAppError("GetScimUser", MissingAccountError, nil, "", http.StatusNotFound)
+	}
+
+	return user, nil
+}
+
+// isScimManagedUser reports whether SCIM may read and modify a user. Only the users of the
+// configured auth service are managed, and never bots or system admins, so that the provisioning
+// client can't take over or lock out the accounts administering the server.
+func (a *App) isScimManagedUser(user *model.User) bool {
+	return user.AuthService == *a.Config().ScimSettings.AuthService && !user.IsBot && !user.IsSystemAdmin()
+}
+
+// getScimUserByFilter looks up the single user a filter can match, returning nil if there's none.
+func (a *App) getScimUserByFilter(filter model.ScimFilter) (*model.User, *model.AppError) {
+	var user *model.User
+	var err error
+	if id, ok := filter.Get("id"); ok {
+		user, err = a.Srv().Store().User().Get(context.Background(), id)
+	} else if userName, ok := filter.Get("username"); ok {
+		user, err = a.Srv().Store().User().GetByUsername(model.ScimUsername(userName))
+	} else if externalID, ok := filter.Get("externalid"); ok {
+		user, err = a.Srv().Store().User().GetByAuth(&externalID, *a.Config().ScimSettings.AuthService)
+	} else if email, ok := filter.Get("emails"); ok {
+		user, err = a.Srv().Store().User().GetByEmail(email)
+	} else if email, ok := filter.Get("emails.value"); ok {
+		user, err = a.Srv().Store().User().GetByEmail(email)
+	} else {
+		return nil, model.NewAppError("getScimUserByFilter", "app.scim.filter.app_error", nil, "", http.StatusBadRequest)
+	}
+
+	if err != nil {
+		var nfErr *store.ErrNotFound
+		switch {
+		case errors.As(err, &nfErr):
+			return nil, nil
+		default:
+			return nil, model.NewAppError("getScimUserByFilter", "app.user.get.app_error", nil, "", http.StatusInternalServerError).Wrap(err)
+		}
+	}
+
+	return user, nil
+}
+
+// CreateScimUser provisions a user from a SCIM resource. The user authenticates through the
+// configured auth service, identified by the SCIM externalId or, failing that, the userName.
+func (a *App) CreateScimUser(c request.CTX, su *model.ScimUser) (*model.User, *model.AppError) {
+	authData := su.ExternalId
+	if authData == "" {
+		authData = su.UserName
+	}
+
+	user := &model.User{
+		AuthService:   *a.Config().ScimSettings.AuthService,
+		AuthData:      model.NewPointer(authData),
+		EmailVerified: true,
+	}
+	su.ApplyTo(user)
+
+	ruser, appErr := a.CreateUser(c, user)
+	if appErr != nil {
+		return nil, appErr
+	}
+
+	if su.Active != nil && !*su.Active {
+		return a.UpdateActive(c, ruser, false)
+	}
+
+	return ruser, nil
+}
+
+// UpdateScimUser replaces the attributes of a user with those of a SCIM resource, deactivating
+// or reactivating the user as needed.
+func (a *App) UpdateScimUser(c request.CTX, user *model.User, su *model.ScimUser) (*model.User, *model.AppError) {
+	su.ApplyTo(user)
+	if _, appErr := a.UpdateUser(c, user, false); appErr != nil {
+		return nil, appErr
+	}
+
+	if su.ExternalId != "" && su.ExternalId != user.GetAuthData() {
+		userAuth := &model.UserAuth{
+			AuthService: *a.Config().ScimSettings.AuthService,
+			AuthData:    model.NewPointer(su.ExternalId),
+		}
+		if _, appErr := a.UpdateUserAuth(c, user.Id, userAuth); appErr != nil {
+			return nil, appErr
+		}
+	}
+
+	user, appErr := a.GetUser(user.Id)
+	if appErr != nil {
+		return nil, appErr
+	}
+
+	if su.Active != nil && *su.Active != (user.DeleteAt == 0) {
+		if user, appErr = a.UpdateActive(c, user, *su.Active); appErr != nil {
+			return nil, appErr
+		}
+	}
+
+	return user, nil
+}
+
+// GetScimGroup returns a group provisioned through SCIM.
+func (a *App) GetScimGroup(groupID string) (*model.Group, *model.AppError) {
+	group, appErr := a.GetGroup(groupID, nil, nil)
+	if appErr != nil {
+		return nil, appErr
+	}
+
+	if !group.IsScimManaged() || group.DeleteAt != 0 {
+		return nil, model.NewAppError("GetScimGroup", "app.group.no_rows", nil, "", http.StatusNotFound)
+	}
+
+	return group, nil
+}
+
+// GetScimGroups returns the groups provisioned through SCIM that match the given filter,
+// skipping the first offset ones, along with the total number of matching groups.
+func (a *App) GetScimGroups(filter model.ScimFilter, offset, limit int) ([]*model.Group, int64, *model.AppError) {
+	groups, err := a.Srv().Store().Group().GetAllBySource(model.GroupSourceCustom)
+	if err != nil {
+		return nil, 0, model.NewAppError("GetScimGroups", "app.select_error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+
+	_, filterByMember := filter.Get("members")
+	if !filterByMember {
+		_, filterByMember = filter.Get("members.value")
+	}
+
+	var matching []*model.Group
+	for _, group := range groups {
+		if !group.IsScimManaged() {
+			continue
+		}
+
+		var members []*model.User
+		if filterByMember {
+			var appErr *model.AppError
+			if members, appErr = a.GetGroupMemberUsers(group.Id); appErr != nil {
+				return nil, 0, appErr
+			}
+		}
+
+		if filter.MatchesGroup(model.ScimGroupFromGroup(group, members, "")) {
+			matching = append(matching, group)
+		}
+	}
+
+	sort.Slice(matching, func(i, j int) bool {
+		return matching[i].CreateAt < matching[j].CreateAt
+	})
+
+	total := int64(len(matching))
+	if offset >= len(matching) {
+		return []*model.Group{}, total, nil
+	}
+
+	return matching[offset:min(offset+limit, len(matching))], total, nil
+}
+
+// CreateScimGroup provisions a custom group from a SCIM resource. Adding members to the group
+// adds them to the teams and channels the group is linked to.
+func (a *App) CreateScimGroup(c request.CTX, sg *model.ScimGroup) (*model.Group, *model.AppError) {
+	remoteID := model.ScimGroupRemoteID(sg.ExternalId)
+	if existing, err := a.Srv().Store().Group().GetByRemoteID(remoteID, model.GroupSourceCustom); err == nil && existing.DeleteAt == 0 {
+		return nil, model.NewAppError("CreateScimGroup", "app.scim.group_exists.app_error", nil, "", http.StatusConflict)
+	}
+
+	group, appErr := a.CreateGroup(&model.Group{
+		DisplayName: sg.DisplayName,
+		Source:      model.GroupSourceCustom,
+		RemoteId:    model.NewPointer(remoteID),
+	})
+	if appErr != nil {
+		return nil, appErr
+	}
+
+	if appErr := a.addScimGroupMembers(c, group.Id, sg.MemberIDs()); appErr != nil {
+		return nil, appErr
+	}
+
+	return group, nil
+}
+
+// UpdateScimGroup replaces the attributes and members of a group with those of a SCIM resource.
+func (a *App) UpdateScimGroup(c request.CTX, group *model.Group, sg *model.ScimGroup) (*model.Group, *model.AppError) {
+	group.DisplayName = sg.DisplayName
+	if sg.ExternalId != "" {
+		group.RemoteId = model.NewPointer(model.ScimGroupRemoteID(sg.ExternalId))
+	}
+
+	group, appErr := a.UpdateGroup(group)
+	if appErr != nil {
+		return nil, appErr
+	}
+
+	if appErr := a.setScimGroupMembers(c, group.Id, sg.MemberIDs()); appErr != nil {
+		return nil, appErr
+	}
+
+	return group, nil
+}
+
+// PatchScimGroup applies the operations of a SCIM PATCH request to a group.
+func (a *App) PatchScimGroup(c request.CTX, group *model.Group, operations []*model.ScimGroupOperation) (*model.Group, *model.AppError) {
+	changed := false
+	for _, operation := range operations {
+		switch operation.Attribute {
+		case "displayname":
+			if operation.Value != "" {
+				group.DisplayName = operation.Value
+				changed = true
+			}
+		case "externalid":
+			group.RemoteId = model.NewPointer(model.ScimGroupRemoteID(operation.Value))
+			changed = true
+		case "members":
+			var appErr *model.AppError
+			switch operation.Op {
+			case model.ScimPatchOpAdd:
+				appErr = a.addScimGroupMembers(c, group.Id, operation.MemberIDs)
+			case model.ScimPatchOpReplace:
+				appErr = a.setScimGroupMembers(c, group.Id, operation.MemberIDs)
+			case model.ScimPatchOpRemove:
+				if len(operation.MemberIDs) == 0 {
+					appErr = a.setScimGroupMembers(c, group.Id, nil)
+				} else {
+					appErr = a.removeScimGroupMembers(c, group.Id, operation.MemberIDs)
+				}
+			}
+			if appErr != nil {
+				return nil, appErr
+			}
+		}
+	}
+
+	if !changed {
+		return group, nil
+	}
+
+	return a.UpdateGroup(group)
+}
+
+// DeleteScimGroup deletes a group provisioned through SCIM, removing its members from the
+// group-constrained teams and channels it's linked to.
+func (a *App) DeleteScimGroup(c request.CTX, group *model.Group) *model.AppError {
+	// Release the externalId so that a new group can be provisioned with it.
+	group.RemoteId = nil
+	if _, appErr := a.UpdateGroup(group); appErr != nil {
+		return appErr
+	}
+
+	if _, appErr := a.DeleteGroup(group.Id); appErr != nil {
+		return appErr
+	}
+
+	a.deleteScimGroupConstrainedMemberships(c)
+
+	return nil
+}
+
+func (a *App) addScimGroupMembers(c request.CTX, groupID string, userIDs []string) *model.AppError {
+	if len(userIDs) == 0 {
+		return nil
+	}
+
+	userIDs = model.RemoveDuplicateStrings(userIDs)
+	users, err := a.Srv().Store().User().GetMany(context.Background(), userIDs)
+	if err != nil {
+		return model.NewAppError("addScimGroupMembers", "app.user.get_profiles.app_error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+	if len(users) != len(userIDs) {
+		return model.NewAppError("addScimGroupMembers", "app.scim.member_not_found.app_error", nil, "", http.StatusBadRequest)
+	}
+	// The users that SCIM doesn't manage are handled as if they didn't exist, so that the
+	// provisioning client can't grant them the teams and channels of its groups.
+	for _, user := range users {
+		if !a.isScimManagedUser(user) {
+			return model.NewAppError("addScimGroupMembers", "app.scim.member_not_found.app_error", nil, "user_id="+user.Id, http.StatusBadRequest)
+		}
+	}
+
+	if _, appErr := a.UpsertGroupMembers(groupID, userIDs); appErr != nil {
+		return appErr
+	}
+
+	for _, userID := range userIDs {
+		params := model.CreateDefaultMembershipParams{ScopedUserID: model.NewPointer(userID)}
+		if err := a.CreateDefaultMemberships(c, params); err != nil {
+			c.Logger().Warn("Failed to add SCIM group member to the group's teams and channels", mlog.String("group_id", groupID), mlog.String("user_id", userID), mlog.Err(err))
+		}
+	}
+
+	return nil
+}
+
+func (a *App) removeScimGroupMembers(c request.CTX, groupID string, userIDs []string) *model.AppError {
+	if len(userIDs) == 0 {
+		return nil
+	}
+
+	if _, appErr := a.DeleteGroupMembers(groupID, userIDs); appErr != nil {
+		return appErr
+	}
+
+	a.deleteScimGroupConstrainedMemberships(c)
+
+	return nil
+}
+
+func (a *App) setScimGroupMembers(c request.CTX, groupID string, userIDs []string) *model.AppError {
+	current, appErr := a.GetGroupMemberUsers(groupID)
+	if appErr != nil {
+		return appErr
+	}
+
+	var toRemove []string
+	for _, user := range current {
+		if !slices.Contains(userIDs, user.Id) {
+			toRemove = append(toRemove, user.Id)
+		}
+	}
+
+	var toAdd []string
+	for _, userID := range userIDs {
+		if !slices.ContainsFunc(current, func(user *model.User) bool { return user.Id == userID }) {
+			toAdd = append(toAdd, userID)
+		}
+	}
+
+	if appErr := a.addScimGroupMembers(c, groupID, toAdd); appErr != nil {
+		return appErr
+	}
+
+	return a.removeScimGroupMembers(c, groupID, toRemove)
+}
+
+// deleteScimGroupConstrainedMemberships removes users from the group-constrained teams and
+// channels they're no longer allowed in, in the background since it goes through every syncable.
+func (a *App) deleteScimGroupConstrainedMemberships(c request.CTX) {
+	rctx := c.WithContext(context.Background())
+	a.Srv().Go(func() {
+		if err := a.DeleteGroupConstrainedMemberships(rctx); err != nil {
+			rctx.Logger().Warn("Failed to remove former SCIM group members from group-constrained teams and channels", mlog.Err(err))
+		}
+	})
+}
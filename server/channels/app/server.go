@@ -25,6 +25,7 @@ import (
 	"github.com/gorilla/mux"
 	"github.com/pkg/errors"
 	"github.com/rs/cors"
+	"github.com/throttled/throttled"
 	"golang.org/x/crypto/acme/autocert"
 
 	"github.com/mattermost/mattermost/server/public/model"
@@ -44,11 +45,16 @@ import (
 	"github.com/mattermost/mattermost/server/v8/channels/jobs/delete_dms_preferences_migration"
 	"github.com/mattermost/mattermost/server/v8/channels/jobs/delete_empty_drafts_migration"
 	"github.com/mattermost/mattermost/server/v8/channels/jobs/delete_orphan_drafts_migration"
+	"github.com/mattermost/mattermost/server/v8/channels/jobs/expired_audit_records"
+	"github.com/mattermost/mattermost/server/v8/channels/jobs/expired_posts"
 	"github.com/mattermost/mattermost/server/v8/channels/jobs/expirynotify"
 	"github.com/mattermost/mattermost/server/v8/channels/jobs/export_delete"
 	"github.com/mattermost/mattermost/server/v8/channels/jobs/export_process"
 	"github.com/mattermost/mattermost/server/v8/channels/jobs/export_users_to_csv"
 	"github.com/mattermost/mattermost/server/v8/channels/jobs/extract_content"
+	"github.com/mattermost/mattermost/server/v8/channels/jobs/file_deduplication"
+	"github.com/mattermost/mattermost/server/v8/channels/jobs/file_encryption"
+	"github.com/mattermost/mattermost/server/v8/channels/jobs/file_migration"
 	"github.com/mattermost/mattermost/server/v8/channels/jobs/hosted_purchase_screening"
 	"github.com/mattermost/mattermost/server/v8/channels/jobs/import_delete"
 	"github.com/mattermost/mattermost/server/v8/channels/jobs/import_process"
@@ -56,12 +62,17 @@ import (
 	"github.com/mattermost/mattermost/server/v8/channels/jobs/last_accessible_post"
 	"github.com/mattermost/mattermost/server/v8/channels/jobs/migrations"
 	"github.com/mattermost/mattermost/server/v8/channels/jobs/notify_admin"
+	"github.com/mattermost/mattermost/server/v8/channels/jobs/outgoing_webhook_deliveries"
 	"github.com/mattermost/mattermost/server/v8/channels/jobs/plugins"
 	"github.com/mattermost/mattermost/server/v8/channels/jobs/post_persistent_notifications"
 	"github.com/mattermost/mattermost/server/v8/channels/jobs/product_notices"
+	"github.com/mattermost/mattermost/server/v8/channels/jobs/purge_file_blobs"
 	"github.com/mattermost/mattermost/server/v8/channels/jobs/refresh_post_stats"
+	"github.com/mattermost/mattermost/server/v8/channels/jobs/regenerate_file_previews"
 	"github.com/mattermost/mattermost/server/v8/channels/jobs/resend_invitation_email"
 	"github.com/mattermost/mattermost/server/v8/channels/jobs/s3_path_migration"
+	"github.com/mattermost/mattermost/server/v8/channels/jobs/scheduled_posts"
+	"github.com/mattermost/mattermost/server/v8/channels/jobs/transcode_media"
 	"github.com/mattermost/mattermost/server/v8/channels/store"
 	"github.com/mattermost/mattermost/server/v8/channels/utils"
 	"github.com/mattermost/mattermost/server/v8/config"
@@ -71,6 +82,8 @@ import (
 	"github.com/mattermost/mattermost/server/v8/platform/services/remotecluster"
 	"github.com/mattermost/mattermost/server/v8/platform/services/searchengine/bleveengine"
 	"github.com/mattermost/mattermost/server/v8/platform/services/searchengine/bleveengine/indexer"
+	"github.com/mattermost/mattermost/server/v8/platform/services/searchengine/semanticengine"
+	semanticindexer "github.com/mattermost/mattermost/server/v8/platform/services/searchengine/semanticengine/indexer"
 	"github.com/mattermost/mattermost/server/v8/platform/services/sharedchannel"
 	"github.com/mattermost/mattermost/server/v8/platform/services/telemetry"
 	"github.com/mattermost/mattermost/server/v8/platform/services/tracing"
@@ -432,8 +445,16 @@ func NewServer(options ...Option) (*Server, error) {
 		if err = s.configureAudit(s.Audit, allowAdvancedLogging); err != nil {
 			mlog.Error("Error configuring audit", mlog.Err(err))
 		}
+		s.configureAuditSink(s.platform.Config())
 	}
 
+	s.platform.AddConfigListener(func(oldCfg, newCfg *model.Config) {
+		if *oldCfg.ExperimentalAuditSettings.DatabaseEnabled != *newCfg.ExperimentalAuditSettings.DatabaseEnabled ||
+			*oldCfg.ExperimentalAuditSettings.FileMaxQueueSize != *newCfg.ExperimentalAuditSettings.FileMaxQueueSize {
+			s.configureAuditSink(newCfg)
+		}
+	})
+
 	s.platform.RemoveUnlicensedLogTargets(license)
 	s.platform.EnableLoggingMetrics()
 
@@ -872,7 +893,7 @@ func (s *Server) Start() error {
 	err := s.FileBackend().TestConnection()
 	if err != nil {
 		if _, ok := err.(*filestore.S3FileBackendNoBucketError); ok {
-			err = s.FileBackend().(*filestore.S3FileBackend).MakeBucket()
+			err = filestore.UnwrapFileBackend(s.FileBackend()).(*filestore.S3FileBackend).MakeBucket()
 		}
 		if err != nil {
 			mlog.Error("Problem with file storage settings", mlog.Err(err))
@@ -923,7 +944,14 @@ func (s *Server) Start() error {
 	if *s.platform.Config().RateLimitSettings.Enable {
 		mlog.Info("RateLimiter is enabled")
 
-		rateLimiter, err2 := NewRateLimiter(&s.platform.Config().RateLimitSettings, s.platform.Config().ServiceSettings.TrustedProxyIPHeader)
+		// Share the state of the rate limiter between the nodes of the cluster when
+		// the cache provider allows it.
+		var rateLimitStore throttled.GCRAStore
+		if provider, ok := s.platform.CacheProvider().(cache.RateLimitStoreProvider); ok {
+			rateLimitStore = provider.NewRateLimitStore("ratelimit")
+		}
+
+		rateLimiter, err2 := NewRateLimiter(&s.platform.Config().RateLimitSettings, s.platform.Config().ServiceSettings.TrustedProxyIPHeader, rateLimitStore)
 		if err2 != nil {
 			return err2
 		}
@@ -1496,6 +1524,12 @@ func (s *Server) initJobs() {
 		nil,
 	)
 
+	s.Jobs.RegisterJobType(
+		model.JobTypeSemanticSearchIndexing,
+		semanticindexer.MakeWorker(s.Jobs, s.platform.SearchEngine.SemanticEngine.(*semanticengine.SemanticEngine)),
+		nil,
+	)
+
 	s.Jobs.RegisterJobType(
 		model.JobTypeMigrations,
 		migrations.MakeWorker(s.Jobs, s.Store()),
@@ -1537,6 +1571,27 @@ func (s *Server) initJobs() {
 		s3_path_migration.MakeWorker(s.Jobs, s.Store(), s.FileBackend()),
 		nil)
 
+	s.Jobs.RegisterJobType(
+		model.JobTypeFileEncryption,
+		file_encryption.MakeWorker(s.Jobs, s.Store(), s.FileBackend()),
+		nil)
+
+	s.Jobs.RegisterJobType(
+		model.JobTypeFileMigration,
+		file_migration.MakeWorker(s.Jobs, New(ServerConnector(s.Channels()))),
+		nil)
+
+	s.Jobs.RegisterJobType(
+		model.JobTypeFileDeduplication,
+		file_deduplication.MakeWorker(s.Jobs, s.Store(), New(ServerConnector(s.Channels()))),
+		nil)
+
+	s.Jobs.RegisterJobType(
+		model.JobTypePurgeFileBlobs,
+		purge_file_blobs.MakeWorker(s.Jobs, New(ServerConnector(s.Channels()))),
+		purge_file_blobs.MakeScheduler(s.Jobs),
+	)
+
 	s.Jobs.RegisterJobType(
 		model.JobTypeDeleteEmptyDraftsMigration,
 		delete_empty_drafts_migration.MakeWorker(s.Jobs, s.Store(), New(ServerConnector(s.Channels()))),
@@ -1577,6 +1632,18 @@ func (s *Server) initJobs() {
 		nil,
 	)
 
+	s.Jobs.RegisterJobType(
+		model.JobTypeRegenerateFilePreviews,
+		regenerate_file_previews.MakeWorker(s.Jobs, New(ServerConnector(s.Channels())), s.Store()),
+		nil,
+	)
+
+	s.Jobs.RegisterJobType(
+		model.JobTypeTranscodeMedia,
+		transcode_media.MakeWorker(s.Jobs, New(ServerConnector(s.Channels())), s.Store()),
+		nil,
+	)
+
 	s.Jobs.RegisterJobType(
 		model.JobTypeLastAccessiblePost,
 		last_accessible_post.MakeWorker(s.Jobs, s.License(), New(ServerConnector(s.Channels()))),
@@ -1642,6 +1709,30 @@ func (s *Server) initJobs() {
 		delete_dms_preferences_migration.MakeWorker(s.Jobs, s.Store(), New(ServerConnector(s.Channels()))),
 		nil)
 
+	s.Jobs.RegisterJobType(
+		model.JobTypeScheduledPosts,
+		scheduled_posts.MakeWorker(s.Jobs, New(ServerConnector(s.Channels()))),
+		scheduled_posts.MakeScheduler(s.Jobs),
+	)
+
+	s.Jobs.RegisterJobType(
+		model.JobTypeOutgoingWebhookDeliveries,
+		outgoing_webhook_deliveries.MakeWorker(s.Jobs, New(ServerConnector(s.Channels()))),
+		outgoing_webhook_deliveries.MakeScheduler(s.Jobs),
+	)
+
+	s.Jobs.RegisterJobType(
+		model.JobTypeDeleteExpiredPosts,
+		expired_posts.MakeWorker(s.Jobs, New(ServerConnector(s.Channels()))),
+		expired_posts.MakeScheduler(s.Jobs),
+	)
+
+	s.Jobs.RegisterJobType(
+		model.JobTypeDeleteExpiredAuditRecords,
+		expired_audit_records.MakeWorker(s.Jobs),
+		expired_audit_records.MakeScheduler(s.Jobs),
+	)
+
 	s.platform.Jobs = s.Jobs
 }
 
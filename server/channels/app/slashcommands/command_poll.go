@@ -0,0 +1,152 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package slashcommands
+
+import (
+	"errors"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/shared/i18n"
+	"github.com/mattermost/mattermost/server/public/shared/request"
+	"github.com/mattermost/mattermost/server/v8/channels/app"
+)
+
+type PollProvider struct {
+}
+
+const (
+	CmdPoll = "poll"
+)
+
+var (
+	errPollUsage    = errors.New("poll: question and options are required")
+	errPollDuration = errors.New("poll: invalid duration")
+)
+
+func init() {
+	app.RegisterCommandProvider(&PollProvider{})
+}
+
+func (*PollProvider) GetTrigger() string {
+	return CmdPoll
+}
+
+func (*PollProvider) GetCommand(a *app.App, T i18n.TranslateFunc) *model.Command {
+	return &model.Command{
+		Trigger:          CmdPoll,
+		AutoComplete:     true,
+		AutoCompleteDesc: T("api.command_poll.desc"),
+		AutoCompleteHint: T("api.command_poll.hint"),
+		DisplayName:      T("api.command_poll.name"),
+	}
+}
+
+func (*PollProvider) DoCommand(a *app.App, c request.CTX, args *model.CommandArgs, message string) *model.CommandResponse {
+	poll, duration, err := parsePollCommand(message)
+	switch {
+	case errors.Is(err, errPollDuration):
+		return &model.CommandResponse{ResponseType: model.CommandResponseTypeEphemeral, Text: args.T("api.command_poll.invalid_duration")}
+	case err != nil:
+		return &model.CommandResponse{ResponseType: model.CommandResponseTypeEphemeral, Text: args.T("api.command_poll.usage")}
+	}
+
+	if duration > 0 {
+		poll.CloseAt = model.GetMillis() + duration.Milliseconds()
+	}
+
+	poll.PreSave()
+	if appErr := poll.IsValid(); appErr != nil {
+		appErr.Translate(args.T)
+		return &model.CommandResponse{ResponseType: model.CommandResponseTypeEphemeral, Text: appErr.Message}
+	}
+
+	return &model.CommandResponse{
+		ResponseType: model.CommandResponseTypeInChannel,
+		Type:         model.PostTypePoll,
+		Text:         poll.Question,
+		Props:        model.StringInterface{model.PostPropsPoll: poll},
+	}
+}
+
+// parsePollCommand parses the arguments of the poll command, which are of the form
+// `"Question" "Option 1" "Option 2" [--anonymous] [--multiple] [--duration 24h]`.
+func parsePollCommand(message string) (*model.Poll, time.Duration, error) {
+	fields, err := splitQuotedFields(message)
+	if err != nil {
+		return nil, 0, errPollUsage
+	}
+
+	poll := &model.Poll{}
+	var duration time.Duration
+	var texts []string
+	for i := 0; i < len(fields); i++ {
+		switch fields[i] {
+		case "--anonymous":
+			poll.Anonymous = true
+		case "--multiple":
+			poll.MultipleChoice = true
+		case "--duration":
+			if i+1 >= len(fields) {
+				return nil, 0, errPollDuration
+			}
+			i++
+			duration, err = time.ParseDuration(fields[i])
+			if err != nil || duration <= 0 {
+				return nil, 0, errPollDuration
+			}
+		default:
+			texts = append(texts, fields[i])
+		}
+	}
+
+	if len(texts) == 0 {
+		return nil, 0, errPollUsage
+	}
+
+	poll.Question = texts[0]
+	for _, text := range texts[1:] {
+		poll.Options = append(poll.Options, &model.PollOption{Text: text})
+	}
+
+	return poll, duration, nil
+}
+
+// splitQuotedFields splits s around whitespace, keeping text wrapped in double quotes
+// together. Curly quotes are accepted too since some keyboards insert them automatically.
+func splitQuotedFields(s string) ([]string, error) {
+	var fields []string
+	var current strings.Builder
+	inQuotes := false
+	inField := false
+
+	for _, r := range s {
+		switch {
+		case r == '"' || r == '“' || r == '”':
+			inQuotes = !inQuotes
+			inField = true
+		case unicode.IsSpace(r) && !inQuotes:
+			if inField {
+				fields = append(fields, current.String())
+				current.Reset()
+				inField = false
+			}
+		default:
+			current.WriteRune(r)
+			inField = true
+		}
+	}
+
+	if inQuotes {
+		return nil, errors.New("unterminated quote")
+	}
+
+	if inField {
+		fields = append(fields, current.String())
+	}
+
+	return fields, nil
+}
@@ -0,0 +1,75 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package slashcommands
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSplitQuotedFields(t *testing.T) {
+	data := []struct {
+		name   string
+		s      string
+		fields []string
+	}{
+		{"empty", "", nil},
+		{"plain words", "a b  c", []string{"a", "b", "c"}},
+		{"quoted", `"What for lunch?" "Pizza" Sushi`, []string{"What for lunch?", "Pizza", "Sushi"}},
+		{"curly quotes", "“Best day?” “Monday morning”", []string{"Best day?", "Monday morning"}},
+		{"empty quotes", `"" a`, []string{"", "a"}},
+		{"embedded apostrophe", `"O'Brien's pick" yes`, []string{"O'Brien's pick", "yes"}},
+	}
+
+	for _, tt := range data {
+		fields, err := splitQuotedFields(tt.s)
+		require.NoError(t, err, tt.name)
+		assert.Equal(t, tt.fields, fields, tt.name)
+	}
+
+	_, err := splitQuotedFields(`"unterminated a b`)
+	require.Error(t, err)
+}
+
+func TestParsePollCommand(t *testing.T) {
+	t.Run("question and options", func(t *testing.T) {
+		poll, duration, err := parsePollCommand(`"What for lunch?" "Pizza" "Sushi"`)
+		require.NoError(t, err)
+		assert.Equal(t, "What for lunch?", poll.Question)
+		require.Len(t, poll.Options, 2)
+		assert.Equal(t, "Pizza", poll.Options[0].Text)
+		assert.Equal(t, "Sushi", poll.Options[1].Text)
+		assert.False(t, poll.Anonymous)
+		assert.False(t, poll.MultipleChoice)
+		assert.Zero(t, duration)
+	})
+
+	t.Run("flags", func(t *testing.T) {
+		poll, duration, err := parsePollCommand(`"Which days?" --anonymous Monday Tuesday --multiple --duration 90m`)
+		require.NoError(t, err)
+		assert.Equal(t, "Which days?", poll.Question)
+		require.Len(t, poll.Options, 2)
+		assert.True(t, poll.Anonymous)
+		assert.True(t, poll.MultipleChoice)
+		assert.Equal(t, 90*time.Minute, duration)
+	})
+
+	t.Run("invalid duration", func(t *testing.T) {
+		for _, message := range []string{`"Q" a b --duration`, `"Q" a b --duration soon`, `"Q" a b --duration -1h`} {
+			_, _, err := parsePollCommand(message)
+			assert.ErrorIs(t, err, errPollDuration, message)
+		}
+	})
+
+	t.Run("missing question", func(t *testing.T) {
+		_, _, err := parsePollCommand("--anonymous")
+		assert.ErrorIs(t, err, errPollUsage)
+
+		_, _, err = parsePollCommand(`"unterminated`)
+		assert.ErrorIs(t, err, errPollUsage)
+	})
+}
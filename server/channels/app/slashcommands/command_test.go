@@ -506,6 +506,30 @@ func TestDoCommandRequest(t *testing.T) {
 		// which was set to the Authorization header by the command handler.
 		assert.Equal(t, "type token", resp.Text)
 	})
+
+	t.Run("with a signing secret", func(t *testing.T) {
+		secret := model.NewIntegrationSigningSecret()
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			payload := []byte(r.URL.RawQuery)
+			if r.Method == http.MethodPost {
+				var err error
+				payload, err = io.ReadAll(r.Body)
+				require.NoError(t, err)
+			}
+
+			valid := model.VerifyIntegrationSignature(secret, r.Header.Get(model.HeaderIntegrationSignature), r.Header.Get(model.HeaderIntegrationTimestamp), payload, time.Now())
+			io.Copy(w, strings.NewReader(fmt.Sprintf("%t", valid)))
+		}))
+		defer server.Close()
+
+		for _, method := range []string{model.CommandMethodPost, model.CommandMethodGet} {
+			_, resp, err := th.App.DoCommandRequest(th.Context, &model.Command{URL: server.URL, Method: method, SigningSecret: secret}, url.Values{"text": []string{"hello"}})
+			require.Nil(t, err)
+			require.NotNil(t, resp)
+			assert.Equal(t, "true", resp.Text, "method %s", method)
+		}
+	})
 }
 
 func TestMentionsToTeamMembers(t *testing.T) {
@@ -0,0 +1,72 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package app
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/plugin"
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+	"github.com/mattermost/mattermost/server/public/shared/request"
+	"github.com/mattermost/mattermost/server/v8/platform/services/translation"
+)
+
+// pluginTranslationProvider translates text using the plugins that implement the TranslateText
+// hook. The first plugin returning a translation wins.
+type pluginTranslationProvider struct {
+	ch *Channels
+}
+
+func (p *pluginTranslationProvider) Translate(ctx context.Context, text, targetLanguage string) (*model.Translation, error) {
+	var result *model.Translation
+	var resultErr error
+	p.ch.RunMultiHook(func(hooks plugin.Hooks) bool {
+		translation, err := hooks.TranslateText(&plugin.Context{}, text, targetLanguage)
+		if err != nil {
+			p.ch.srv.Log().Warn("Plugin failed to translate text", mlog.String("target_language", targetLanguage), mlog.Err(err))
+			resultErr = err
+			return true
+		}
+
+		result = translation
+		return result == nil
+	}, plugin.TranslateTextID)
+
+	if result == nil && resultErr != nil {
+		return nil, resultErr
+	}
+
+	return result, nil
+}
+
+// TranslatePost prepares a post for the client with the machine translation of its message to
+// lang included in its metadata. Translations are cached until the post is edited.
+func (a *App) TranslatePost(c request.CTX, post *model.Post, lang string) (*model.Post, *model.AppError) {
+	if !a.ch.translator.IsEnabled() {
+		return nil, model.NewAppError("TranslatePost", "app.translation.disabled.app_error", nil, "", http.StatusNotImplemented)
+	}
+
+	if !model.IsValidTranslationLanguage(lang) {
+		return nil, model.NewAppError("TranslatePost", "app.translation.invalid_language.app_error", nil, "lang="+lang, http.StatusBadRequest)
+	}
+
+	postTranslation, err := a.getTranslationForPost(c, post, lang)
+	if err != nil {
+		if errors.Is(err, translation.ErrNotEnabled) {
+			return nil, model.NewAppError("TranslatePost", "app.translation.disabled.app_error", nil, "", http.StatusNotImplemented).Wrap(err)
+		}
+		return nil, model.NewAppError("TranslatePost", "app.translation.translate.app_error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+
+	post = a.PreparePostForClientWithEmbedsAndImages(c, post, false, false, true)
+	if post.Metadata.Translations == nil {
+		post.Metadata.Translations = make(map[string]*model.Translation, 1)
+	}
+	post.Metadata.Translations[lang] = postTranslation
+
+	return post, nil
+}
@@ -44,6 +44,8 @@ func (a *App) genFileInfoFromReader(name string, file io.ReadSeeker, size int64)
 		}
 		info.Width = config.Width
 		info.Height = config.Height
+	} else {
+		probeMediaFile(info, file)
 	}
 	return info, nil
 }
@@ -290,14 +292,17 @@ func (a *App) UploadData(c request.CTX, us *model.UploadSession, rd io.Reader) (
 				map[string]any{"Filename": us.Filename, "Width": info.Width, "Height": info.Height}, "", http.StatusBadRequest)
 		}
 
-		nameWithoutExtension := info.Name[:strings.LastIndex(info.Name, ".")]
-		info.PreviewPath = filepath.Dir(info.Path) + "/" + nameWithoutExtension + "_preview." + getFileExtFromMimeType(info.MimeType)
-		info.ThumbnailPath = filepath.Dir(info.Path) + "/" + nameWithoutExtension + "_thumb." + getFileExtFromMimeType(info.MimeType)
-		imgData, fileErr := a.ReadFile(uploadPath)
-		if fileErr != nil {
-			return nil, fileErr
+		// Images such as HEIC photos can't be decoded without the image converter.
+		if a.ch.imgDecoder.CanDecodeMimeType(info.MimeType) {
+			nameWithoutExtension := info.Name[:strings.LastIndex(info.Name, ".")]
+			info.PreviewPath = filepath.Dir(info.Path) + "/" + nameWithoutExtension + "_preview." + getPreviewFileExt(info.MimeType, a.webPPreviewQuality())
+			info.ThumbnailPath = filepath.Dir(info.Path) + "/" + nameWithoutExtension + "_thumb." + getFileExtFromMimeType(info.MimeType)
+			imgData, fileErr := a.ReadFile(uploadPath)
+			if fileErr != nil {
+				return nil, fileErr
+			}
+			a.HandleImages(c, []string{info.PreviewPath}, []string{info.ThumbnailPath}, [][]byte{imgData})
 		}
-		a.HandleImages(c, []string{info.PreviewPath}, []string{info.ThumbnailPath}, [][]byte{imgData})
 	}
 
 	if us.Type == model.UploadTypeImport {
@@ -306,8 +311,18 @@ func (a *App) UploadData(c request.CTX, us *model.UploadSession, rd io.Reader) (
 		}
 	}
 
+	if us.Type == model.UploadTypeAttachment {
+		a.deduplicateUploadedFile(c, info, "")
+		a.markFileForVirusScan(info)
+	}
+
+	contentHash := info.ContentHash
 	var storeErr error
 	if info, storeErr = a.Srv().Store().FileInfo().Save(c, info); storeErr != nil {
+		if contentHash != "" {
+			a.releaseFileBlob(c, contentHash)
+		}
+
 		var appErr *model.AppError
 		switch {
 		case errors.As(storeErr, &appErr):
@@ -317,6 +332,9 @@ func (a *App) UploadData(c request.CTX, us *model.UploadSession, rd io.Reader) (
 		}
 	}
 
+	a.scanFileAsync(c, info)
+	a.processMediaFileAsync(c, info)
+
 	if *a.Config().FileSettings.ExtractContent {
 		infoCopy := *info
 		a.Srv().Go(func() {
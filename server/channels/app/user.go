@@ -784,6 +784,12 @@ func (a *App) DeactivateMfa(userID string) *model.AppError {
 		return model.NewAppError("DeactivateMfa", "mfa.deactivate.app_error", nil, "", http.StatusInternalServerError).Wrap(err)
 	}
 
+	// The registered WebAuthn credentials would otherwise keep satisfying the second factor,
+	// and resetting MFA is meant to let a user who lost their authenticators back in.
+	if err := a.Srv().Store().WebAuthnCredential().PermanentDeleteByUser(userID); err != nil {
+		return model.NewAppError("DeactivateMfa", "app.webauthn.delete.app_error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+
 	// Make sure old MFA status is not cached locally or in cluster nodes.
 	a.InvalidateCacheForUser(userID)
 
@@ -1773,6 +1779,14 @@ func (a *App) PermanentDeleteUser(c request.CTX, user *model.User) *model.AppErr
 		return model.NewAppError("PermanentDeleteUser", "app.session.permanent_delete_sessions_by_user.app_error", nil, "", http.StatusInternalServerError).Wrap(err)
 	}
 
+	if err := a.Srv().Store().WebAuthnCredential().PermanentDeleteByUser(user.Id); err != nil {
+		return model.NewAppError("PermanentDeleteUser", "app.webauthn.permanent_delete_by_user.app_error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+
+	if err := a.Srv().Store().SavedSearch().PermanentDeleteByUser(user.Id); err != nil {
+		return model.NewAppError("PermanentDeleteUser", "app.saved_search.permanent_delete_by_user.app_error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+
 	if err := a.Srv().Store().UserAccessToken().DeleteAllForUser(user.Id); err != nil {
 		return model.NewAppError("PermanentDeleteUser", "app.user_access_token.delete.app_error", nil, "", http.StatusInternalServerError).Wrap(err)
 	}
@@ -1829,6 +1843,12 @@ func (a *App) PermanentDeleteUser(c request.CTX, user *model.User) *model.AppErr
 	}
 
 	for _, info := range infos {
+		// Deduplicated contents may be shared with other users' files, they are purged
+		// below once their last reference is deleted.
+		if info.ContentHash != "" {
+			continue
+		}
+
 		res, err := a.FileExists(info.Path)
 		if err != nil {
 			c.Logger().Warn(
@@ -6,6 +6,7 @@ package app
 import (
 	"bytes"
 	"context"
+	"encoding/base64"
 	"encoding/json"
 	"errors"
 	"net/http"
@@ -1892,6 +1893,32 @@ func TestDeactivateMfa(t *testing.T) {
 		err := th.App.DeactivateMfa(user.Id)
 		require.Nil(t, err)
 	})
+
+	t.Run("WebAuthn credentials are removed", func(t *testing.T) {
+		th := Setup(t).InitBasic()
+		defer th.TearDown()
+
+		user := th.BasicUser
+		_, err := th.App.Srv().Store().WebAuthnCredential().Save(&model.WebAuthnCredential{
+			UserId:       user.Id,
+			Name:         "key",
+			CredentialId: base64.RawURLEncoding.EncodeToString([]byte(model.NewId())),
+			PublicKey:    base64.RawURLEncoding.EncodeToString([]byte("key")),
+		})
+		require.NoError(t, err)
+		require.NoError(t, th.App.Srv().Store().User().UpdateMfaActive(user.Id, true))
+
+		appErr := th.App.DeactivateMfa(user.Id)
+		require.Nil(t, appErr)
+
+		credentials, err := th.App.Srv().Store().WebAuthnCredential().GetForUser(user.Id)
+		require.NoError(t, err)
+		assert.Empty(t, credentials)
+
+		user, appErr = th.App.GetUser(user.Id)
+		require.Nil(t, appErr)
+		assert.False(t, user.MfaActive)
+	})
 }
 
 func TestPatchUser(t *testing.T) {
@@ -0,0 +1,195 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package app
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+	"github.com/mattermost/mattermost/server/public/shared/request"
+	"github.com/mattermost/mattermost/server/v8/channels/store"
+	"github.com/mattermost/mattermost/server/v8/platform/services/virusscan"
+)
+
+// markFileForVirusScan makes a new file info pending until it is scanned, if scanning is
+// enabled. It must be called before the file info is saved.
+func (a *App) markFileForVirusScan(info *model.FileInfo) {
+	if !a.ch.virusScanner.IsEnabled() {
+		return
+	}
+
+	info.ScanStatus = model.FileScanStatusPending
+	info.ScanResult = ""
+}
+
+// scanFileAsync scans a saved file info that is pending in the background.
+func (a *App) scanFileAsync(rctx request.CTX, info *model.FileInfo) {
+	if info.ScanStatus != model.FileScanStatusPending {
+		return
+	}
+
+	infoCopy := *info
+	a.Srv().GoBuffered(func() {
+		if appErr := a.ScanFile(rctx, &infoCopy); appErr != nil {
+			rctx.Logger().Warn("Failed to scan file for viruses", mlog.String("file_id", infoCopy.Id), mlog.Err(appErr))
+		}
+	})
+}
+
+// ScanFile scans the contents of a file for viruses and records the outcome. Files the
+// scanner fails to scan are quarantined like infected ones.
+func (a *App) ScanFile(rctx request.CTX, info *model.FileInfo) *model.AppError {
+	file, appErr := a.FileReader(info.Path)
+	if appErr != nil {
+		return appErr
+	}
+	defer file.Close()
+
+	status := model.FileScanStatusClean
+	var scanResult string
+
+	result, err := a.ch.virusScanner.Scan(context.Background(), file)
+	switch {
+	case errors.Is(err, virusscan.ErrNotEnabled):
+		return model.NewAppError("ScanFile", "app.file.scan.disabled.app_error", nil, "", http.StatusNotImplemented).Wrap(err)
+	case err != nil:
+		rctx.Logger().Warn("Failed to scan file for viruses, quarantining it", mlog.String("file_id", info.Id), mlog.Err(err))
+		status = model.FileScanStatusFailed
+		scanResult = err.Error()
+	case result.Infected:
+		rctx.Logger().Warn("Virus found in uploaded file, quarantining it",
+			mlog.String("file_id", info.Id),
+			mlog.String("user_id", info.CreatorId),
+			mlog.String("signature", result.Signature),
+		)
+		status = model.FileScanStatusInfected
+		scanResult = result.Signature
+	}
+
+	if runes := []rune(scanResult); len(runes) > model.FileScanResultMaxRunes {
+		scanResult = string(runes[:model.FileScanResultMaxRunes])
+	}
+
+	_, appErr = a.setFileScanStatus(rctx, info.Id, status, scanResult)
+	return appErr
+}
+
+func (a *App) setFileScanStatus(rctx request.CTX, fileID, status, result string) (*model.FileInfo, *model.AppError) {
+	if err := a.Srv().Store().FileInfo().SetScanStatus(rctx, fileID, status, result); err != nil {
+		var nfErr *store.ErrNotFound
+		switch {
+		case errors.As(err, &nfErr):
+			return nil, model.NewAppError("setFileScanStatus", "app.file_info.get.app_error", nil, "", http.StatusNotFound).Wrap(err)
+		default:
+			return nil, model.NewAppError("setFileScanStatus", "app.file_info.set_scan_status.app_error", nil, "", http.StatusInternalServerError).Wrap(err)
+		}
+	}
+
+	info, err := a.Srv().Store().FileInfo().GetFromMaster(fileID)
+	if err != nil {
+		return nil, model.NewAppError("setFileScanStatus", "app.file_info.get.app_error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+
+	a.Srv().Store().FileInfo().InvalidateFileInfosForPostCache(info.PostId, true)
+	a.Srv().Store().FileInfo().InvalidateFileInfosForPostCache(info.PostId, false)
+
+	return info, nil
+}
+
+// CheckFileScanStatus returns an error if the contents of the file must not be downloaded
+// because they are quarantined or, while scanning is enabled, not scanned yet.
+func (a *App) CheckFileScanStatus(rctx request.CTX, info *model.FileInfo) *model.AppError {
+	switch info.ScanStatus {
+	case model.FileScanStatusInfected:
+		return model.NewAppError("CheckFileScanStatus", "app.file.scan_status.infected.app_error", nil, "file_id="+info.Id, http.StatusForbidden)
+	case model.FileScanStatusFailed:
+		return model.NewAppError("CheckFileScanStatus", "app.file.scan_status.failed.app_error", nil, "file_id="+info.Id, http.StatusForbidden)
+	case model.FileScanStatusPending:
+		if !a.ch.virusScanner.IsEnabled() {
+			return nil
+		}
+
+		// The scan was lost if it takes longer than the scanner is allowed to, for instance
+		// because the server restarted, so the file is scanned again.
+		if time.Duration(model.GetMillis()-info.UpdateAt)*time.Millisecond > 2*a.ch.virusScanner.Timeout() {
+			if pending, appErr := a.setFileScanStatus(rctx, info.Id, model.FileScanStatusPending, ""); appErr != nil {
+				rctx.Logger().Warn("Failed to reschedule the virus scan of a file", mlog.String("file_id", info.Id), mlog.Err(appErr))
+			} else {
+				a.scanFileAsync(rctx, pending)
+			}
+		}
+
+		return model.NewAppError("CheckFileScanStatus", "app.file.scan_status.pending.app_error", nil, "file_id="+info.Id, http.StatusForbidden)
+	}
+
+	return nil
+}
+
+// GetQuarantinedFileInfos returns the files that were found to be infected or could not be
+// scanned, most recent first.
+func (a *App) GetQuarantinedFileInfos(page, perPage int) ([]*model.FileInfo, *model.AppError) {
+	infos, err := a.Srv().Store().FileInfo().GetWithOptions(page, perPage, &model.GetFileInfosOptions{
+		ScanStatuses:   []string{model.FileScanStatusInfected, model.FileScanStatusFailed},
+		SortDescending: true,
+	})
+	if err != nil {
+		return nil, model.NewAppError("GetQuarantinedFileInfos", "app.file_info.get_with_options.app_error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+
+	return infos, nil
+}
+
+func (a *App) getQuarantinedFileInfo(fileID string) (*model.FileInfo, *model.AppError) {
+	info, err := a.Srv().Store().FileInfo().GetFromMaster(fileID)
+	if err != nil {
+		var nfErr *store.ErrNotFound
+		switch {
+		case errors.As(err, &nfErr):
+			return nil, model.NewAppError("getQuarantinedFileInfo", "app.file_info.get.app_error", nil, "", http.StatusNotFound).Wrap(err)
+		default:
+			return nil, model.NewAppError("getQuarantinedFileInfo", "app.file_info.get.app_error", nil, "", http.StatusInternalServerError).Wrap(err)
+		}
+	}
+
+	if !info.IsQuarantined() {
+		return nil, model.NewAppError("getQuarantinedFileInfo", "app.file.quarantine.not_quarantined.app_error", nil, "file_id="+fileID, http.StatusBadRequest)
+	}
+
+	return info, nil
+}
+
+// ReleaseQuarantinedFile marks a quarantined file as clean, allowing it to be downloaded.
+func (a *App) ReleaseQuarantinedFile(rctx request.CTX, fileID string) (*model.FileInfo, *model.AppError) {
+	if _, appErr := a.getQuarantinedFileInfo(fileID); appErr != nil {
+		return nil, appErr
+	}
+
+	return a.setFileScanStatus(rctx, fileID, model.FileScanStatusClean, "")
+}
+
+// DeleteQuarantinedFile permanently deletes a quarantined file and its contents.
+func (a *App) DeleteQuarantinedFile(rctx request.CTX, fileID string) *model.AppError {
+	info, appErr := a.getQuarantinedFileInfo(fileID)
+	if appErr != nil {
+		return appErr
+	}
+
+	if err := a.Srv().Store().FileInfo().PermanentDelete(rctx, info.Id); err != nil {
+		return model.NewAppError("DeleteQuarantinedFile", "app.file_info.permanent_delete.app_error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+	a.removeFileInfoFiles(rctx, info)
+
+	a.Srv().Store().FileInfo().InvalidateFileInfosForPostCache(info.PostId, true)
+	a.Srv().Store().FileInfo().InvalidateFileInfosForPostCache(info.PostId, false)
+
+	if info.ContentHash != "" {
+		a.removeUnreferencedFileBlob(rctx, &model.FileBlob{Hash: info.ContentHash, Path: info.Path})
+	}
+
+	return nil
+}
@@ -0,0 +1,187 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package app
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/v8/platform/services/virusscan"
+)
+
+// testVirusScanner reports the files containing "EICAR" as infected, and fails to scan
+// those containing "FAIL".
+type testVirusScanner struct{}
+
+func (s *testVirusScanner) Scan(ctx context.Context, r io.Reader) (*virusscan.Result, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case bytes.Contains(data, []byte("EICAR")):
+		return &virusscan.Result{Infected: true, Signature: "Eicar-Test-Signature"}, nil
+	case bytes.Contains(data, []byte("FAIL")):
+		return nil, errors.New("scanner unavailable")
+	default:
+		return &virusscan.Result{}, nil
+	}
+}
+
+func setupVirusScan(t *testing.T, th *TestHelper) {
+	th.App.UpdateConfig(func(cfg *model.Config) {
+		*cfg.VirusScanSettings.Enable = true
+	})
+	th.App.ch.virusScanner.SetScanner(&testVirusScanner{})
+	t.Cleanup(func() { th.App.ch.virusScanner.SetScanner(nil) })
+}
+
+func waitForFileScan(t *testing.T, th *TestHelper, fileID string) *model.FileInfo {
+	var info *model.FileInfo
+	require.Eventually(t, func() bool {
+		var err error
+		info, err = th.App.Srv().Store().FileInfo().GetFromMaster(fileID)
+		require.NoError(t, err)
+		return info.ScanStatus != model.FileScanStatusPending
+	}, 5*time.Second, 50*time.Millisecond)
+	return info
+}
+
+func TestVirusScanUploadedFiles(t *testing.T) {
+	th := Setup(t).InitBasic()
+	defer th.TearDown()
+
+	setupVirusScan(t, th)
+
+	upload := func(data string) *model.FileInfo {
+		info, appErr := th.App.DoUploadFile(th.Context, time.Now(), th.BasicTeam.Id, th.BasicChannel.Id, th.BasicUser.Id, "file.txt", []byte(data), false)
+		require.Nil(t, appErr)
+		require.Equal(t, model.FileScanStatusPending, info.ScanStatus)
+
+		appErr = th.App.CheckFileScanStatus(th.Context, info)
+		require.NotNil(t, appErr)
+		assert.Equal(t, "app.file.scan_status.pending.app_error", appErr.Id)
+
+		return waitForFileScan(t, th, info.Id)
+	}
+
+	t.Run("clean file", func(t *testing.T) {
+		info := upload("clean")
+		assert.Equal(t, model.FileScanStatusClean, info.ScanStatus)
+		assert.Nil(t, th.App.CheckFileScanStatus(th.Context, info))
+	})
+
+	t.Run("infected file", func(t *testing.T) {
+		info := upload("EICAR")
+		assert.Equal(t, model.FileScanStatusInfected, info.ScanStatus)
+		assert.Equal(t, "Eicar-Test-Signature", info.ScanResult)
+
+		appErr := th.App.CheckFileScanStatus(th.Context, info)
+		require.NotNil(t, appErr)
+		assert.Equal(t, http.StatusForbidden, appErr.StatusCode)
+	})
+
+	t.Run("file that failed to be scanned", func(t *testing.T) {
+		info := upload("FAIL")
+		assert.Equal(t, model.FileScanStatusFailed, info.ScanStatus)
+		assert.Equal(t, "scanner unavailable", info.ScanResult)
+		assert.NotNil(t, th.App.CheckFileScanStatus(th.Context, info))
+	})
+
+	t.Run("files uploaded while scanning is disabled are not scanned", func(t *testing.T) {
+		th.App.UpdateConfig(func(cfg *model.Config) {
+			*cfg.VirusScanSettings.Enable = false
+		})
+		defer th.App.UpdateConfig(func(cfg *model.Config) {
+			*cfg.VirusScanSettings.Enable = true
+		})
+
+		info, appErr := th.App.DoUploadFile(th.Context, time.Now(), th.BasicTeam.Id, th.BasicChannel.Id, th.BasicUser.Id, "file.txt", []byte("EICAR"), false)
+		require.Nil(t, appErr)
+		assert.Empty(t, info.ScanStatus)
+		assert.Nil(t, th.App.CheckFileScanStatus(th.Context, info))
+	})
+
+	t.Run("lost scans are rescheduled", func(t *testing.T) {
+		info, err := th.App.Srv().Store().FileInfo().Save(th.Context, &model.FileInfo{
+			CreatorId:  th.BasicUser.Id,
+			Path:       "lost/file.txt",
+			ScanStatus: model.FileScanStatusPending,
+			CreateAt:   model.GetMillis() - time.Hour.Milliseconds(),
+		})
+		require.NoError(t, err)
+		_, appErr := th.App.WriteFile(bytes.NewReader([]byte("EICAR")), info.Path)
+		require.Nil(t, appErr)
+		defer th.App.RemoveFile(info.Path)
+
+		require.NotNil(t, th.App.CheckFileScanStatus(th.Context, info))
+		info = waitForFileScan(t, th, info.Id)
+		assert.Equal(t, model.FileScanStatusInfected, info.ScanStatus)
+	})
+}
+
+func TestQuarantinedFiles(t *testing.T) {
+	th := Setup(t).InitBasic()
+	defer th.TearDown()
+
+	setupVirusScan(t, th)
+
+	var infected []*model.FileInfo
+	for i := 0; i < 2; i++ {
+		info, appErr := th.App.DoUploadFile(th.Context, time.Now(), th.BasicTeam.Id, th.BasicChannel.Id, th.BasicUser.Id, "virus.exe", []byte("EICAR"), false)
+		require.Nil(t, appErr)
+		infected = append(infected, waitForFileScan(t, th, info.Id))
+	}
+
+	clean, appErr := th.App.DoUploadFile(th.Context, time.Now(), th.BasicTeam.Id, th.BasicChannel.Id, th.BasicUser.Id, "file.txt", []byte("clean"), false)
+	require.Nil(t, appErr)
+	clean = waitForFileScan(t, th, clean.Id)
+
+	quarantined, appErr := th.App.GetQuarantinedFileInfos(0, 100)
+	require.Nil(t, appErr)
+	ids := make([]string, 0, len(quarantined))
+	for _, info := range quarantined {
+		ids = append(ids, info.Id)
+	}
+	assert.Contains(t, ids, infected[0].Id)
+	assert.Contains(t, ids, infected[1].Id)
+	assert.NotContains(t, ids, clean.Id)
+
+	t.Run("release", func(t *testing.T) {
+		released, appErr := th.App.ReleaseQuarantinedFile(th.Context, infected[0].Id)
+		require.Nil(t, appErr)
+		assert.Equal(t, model.FileScanStatusClean, released.ScanStatus)
+		assert.Nil(t, th.App.CheckFileScanStatus(th.Context, released))
+
+		_, appErr = th.App.ReleaseQuarantinedFile(th.Context, infected[0].Id)
+		require.NotNil(t, appErr)
+		assert.Equal(t, "app.file.quarantine.not_quarantined.app_error", appErr.Id)
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		appErr := th.App.DeleteQuarantinedFile(th.Context, infected[1].Id)
+		require.Nil(t, appErr)
+
+		_, err := th.App.Srv().Store().FileInfo().GetFromMaster(infected[1].Id)
+		require.Error(t, err)
+
+		exists, appErr := th.App.FileExists(infected[1].Path)
+		require.Nil(t, appErr)
+		assert.False(t, exists)
+
+		appErr = th.App.DeleteQuarantinedFile(th.Context, clean.Id)
+		require.NotNil(t, appErr)
+		assert.Equal(t, http.StatusBadRequest, appErr.StatusCode)
+	})
+}
@@ -0,0 +1,466 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package app
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/shared/request"
+	"github.com/mattermost/mattermost/server/v8/channels/store"
+	"github.com/mattermost/mattermost/server/v8/platform/shared/mfa"
+)
+
+const (
+	TokenTypeWebAuthnRegistration = "webauthn_registration"
+	TokenTypeWebAuthnLogin        = "webauthn_login"
+
+	webAuthnUserVerification = "preferred"
+
+	// A login challenge holds the time it was created at and a random nonce, followed by their
+	// signature.
+	webAuthnLoginChallengeNonceSize  = 16
+	webAuthnLoginChallengeSignedSize = 8 + webAuthnLoginChallengeNonceSize
+
+	webAuthnDecoyCredentialPurpose = "webauthn_decoy_credential"
+)
+
+var errInvalidWebAuthnChallenge = errors.New("invalid or expired webauthn challenge")
+
+func (a *App) webAuthn() (*mfa.WebAuthn, *model.AppError) {
+	if !*a.Config().ServiceSettings.EnableMultifactorAuthentication {
+		return nil, model.NewAppError("webAuthn", "mfa.mfa_disabled.app_error", nil, "", http.StatusNotImplemented)
+	}
+
+	w, err := mfa.NewWebAuthn(a.GetSiteURL())
+	if err != nil {
+		return nil, model.NewAppError("webAuthn", "app.webauthn.site_url.app_error", nil, "", http.StatusNotImplemented).Wrap(err)
+	}
+
+	return w, nil
+}
+
+// createWebAuthnChallenge starts a ceremony for the given user. The challenge is backed
+// by a token so that any node of the cluster can complete the ceremony.
+func (a *App) createWebAuthnChallenge(tokenType, userID string) (string, *model.AppError) {
+	token := model.NewToken(tokenType, userID)
+	if err := a.Srv().Store().Token().Save(token); err != nil {
+		return "", model.NewAppError("createWebAuthnChallenge", "app.webauthn.save_challenge.app_error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+
+	return base64.RawURLEncoding.EncodeToString([]byte(token.Token)), nil
+}
+
+// consumeWebAuthnChallenge returns the challenge signed in the client data, provided it
+// was issued to the given user for the given ceremony. Challenges can only be used once.
+func (a *App) consumeWebAuthnChallenge(tokenType, userID string, clientDataJSON []byte) ([]byte, error) {
+	challenge, err := mfa.ParseWebAuthnChallenge(clientDataJSON)
+	if err != nil {
+		return nil, err
+	}
+
+	token, err := a.Srv().Store().Token().GetByToken(string(challenge))
+	if err != nil {
+		var nfErr *store.ErrNotFound
+		if errors.As(err, &nfErr) {
+			return nil, errInvalidWebAuthnChallenge
+		}
+		return nil, err
+	}
+
+	if err := a.Srv().Store().Token().Delete(token.Token); err != nil {
+		return nil, err
+	}
+
+	if token.Type != tokenType || token.Extra != userID || model.GetMillis()-token.CreateAt >= model.WebAuthnTimeout {
+		return nil, errInvalidWebAuthnChallenge
+	}
+
+	return challenge, nil
+}
+
+// webAuthnLoginSignature signs the values bound to a login challenge or a decoy credential
+// with a secret shared by the nodes of the cluster.
+func (a *App) webAuthnLoginSignature(purpose string, parts ...[]byte) []byte {
+	mac := hmac.New(sha256.New, a.PostActionCookieSecret())
+	mac.Write([]byte(purpose))
+	for _, part := range parts {
+		mac.Write([]byte{0})
+		mac.Write(part)
+	}
+	return mac.Sum(nil)
+}
+
+// createWebAuthnLoginChallenge starts a login ceremony for the given user. Unlike the other
+// challenges, login challenges are handed out before the user is authenticated, so they are
+// signed rather than stored.
+func (a *App) createWebAuthnLoginChallenge(userID string) (string, *model.AppError) {
+	challenge := make([]byte, webAuthnLoginChallengeSignedSize, webAuthnLoginChallengeSignedSize+sha256.Size)
+	binary.BigEndian.PutUint64(challenge, uint64(model.GetMillis()))
+	if _, err := rand.Read(challenge[8:]); err != nil {
+		return "", model.NewAppError("createWebAuthnLoginChallenge", "app.webauthn.save_challenge.app_error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+	challenge = append(challenge, a.webAuthnLoginSignature(TokenTypeWebAuthnLogin, []byte(userID), challenge)...)
+
+	return base64.RawURLEncoding.EncodeToString(challenge), nil
+}
+
+// consumeWebAuthnLoginChallenge returns the challenge signed in the client data, provided it
+// was issued to the given user. The used challenges are recorded until they expire, so that
+// they can only be used once.
+func (a *App) consumeWebAuthnLoginChallenge(userID string, clientDataJSON []byte) ([]byte, error) {
+	challenge, err := mfa.ParseWebAuthnChallenge(clientDataJSON)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(challenge) != webAuthnLoginChallengeSignedSize+sha256.Size {
+		return nil, errInvalidWebAuthnChallenge
+	}
+	signed := challenge[:webAuthnLoginChallengeSignedSize]
+	if !hmac.Equal(challenge[len(signed):], a.webAuthnLoginSignature(TokenTypeWebAuthnLogin, []byte(userID), signed)) {
+		return nil, errInvalidWebAuthnChallenge
+	}
+	if model.GetMillis()-int64(binary.BigEndian.Uint64(signed)) >= model.WebAuthnTimeout {
+		return nil, errInvalidWebAuthnChallenge
+	}
+
+	hash := sha256.Sum256(challenge)
+	used := model.NewToken(TokenTypeWebAuthnLogin, userID)
+	used.Token = hex.EncodeToString(hash[:])
+	_, err = a.Srv().Store().Token().GetByToken(used.Token)
+	if err == nil {
+		return nil, errInvalidWebAuthnChallenge
+	}
+	var nfErr *store.ErrNotFound
+	if !errors.As(err, &nfErr) {
+		return nil, err
+	}
+	if err = a.Srv().Store().Token().Save(used); err != nil {
+		return nil, err
+	}
+
+	return challenge, nil
+}
+
+// webAuthnDecoyCredentials returns a credential that looks like a real one for the login ids
+// without credentials, so that the login options don't reveal which users exist or registered
+// credentials. It is derived from the login id, so that it doesn't change between requests.
+func (a *App) webAuthnDecoyCredentials(loginID string) []model.WebAuthnCredentialDescriptor {
+	return []model.WebAuthnCredentialDescriptor{{
+		Type: model.WebAuthnPublicKeyCredentialType,
+		Id:   base64.RawURLEncoding.EncodeToString(a.webAuthnLoginSignature(webAuthnDecoyCredentialPurpose, []byte(strings.ToLower(loginID)))),
+	}}
+}
+
+func webAuthnCredentialDescriptors(credentials []*model.WebAuthnCredential) []model.WebAuthnCredentialDescriptor {
+	descriptors := make([]model.WebAuthnCredentialDescriptor, 0, len(credentials))
+	for _, credential := range credentials {
+		descriptors = append(descriptors, model.WebAuthnCredentialDescriptor{
+			Type: model.WebAuthnPublicKeyCredentialType,
+			Id:   credential.CredentialId,
+		})
+	}
+	return descriptors
+}
+
+func formatAAGUID(aaguid []byte) string {
+	if len(aaguid) != 16 {
+		return ""
+	}
+	return fmt.Sprintf("%x-%x-%x-%x-%x", aaguid[0:4], aaguid[4:6], aaguid[6:8], aaguid[8:10], aaguid[10:16])
+}
+
+func (a *App) BeginWebAuthnRegistration(userID string) (*model.WebAuthnCreationOptions, *model.AppError) {
+	user, appErr := a.GetUser(userID)
+	if appErr != nil {
+		return nil, appErr
+	}
+
+	if user.AuthService != "" && user.AuthService != model.UserAuthServiceLdap {
+		return nil, model.NewAppError("BeginWebAuthnRegistration", "api.user.activate_mfa.email_and_ldap_only.app_error", nil, "", http.StatusBadRequest)
+	}
+
+	w, appErr := a.webAuthn()
+	if appErr != nil {
+		return nil, appErr
+	}
+
+	credentials, err := a.Srv().Store().WebAuthnCredential().GetForUser(user.Id)
+	if err != nil {
+		return nil, model.NewAppError("BeginWebAuthnRegistration", "app.webauthn.get.app_error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+
+	challenge, appErr := a.createWebAuthnChallenge(TokenTypeWebAuthnRegistration, user.Id)
+	if appErr != nil {
+		return nil, appErr
+	}
+
+	params := make([]model.WebAuthnCredentialParameter, 0, len(mfa.WebAuthnAlgorithms))
+	for _, alg := range mfa.WebAuthnAlgorithms {
+		params = append(params, model.WebAuthnCredentialParameter{Type: model.WebAuthnPublicKeyCredentialType, Alg: alg})
+	}
+
+	return &model.WebAuthnCreationOptions{
+		Challenge: challenge,
+		RelyingParty: model.WebAuthnRelyingParty{
+			Id:   w.RelyingPartyID(),
+			Name: *a.Config().TeamSettings.SiteName,
+		},
+		User: model.WebAuthnUserEntity{
+			Id:          base64.RawURLEncoding.EncodeToString([]byte(user.Id)),
+			Name:        user.Username,
+			DisplayName: user.GetDisplayName(model.ShowFullName),
+		},
+		PubKeyCredParams:   params,
+		Timeout:            model.WebAuthnTimeout,
+		ExcludeCredentials: webAuthnCredentialDescriptors(credentials),
+		AuthenticatorSelection: model.WebAuthnAuthenticatorSelection{
+			ResidentKey:      "discouraged",
+			UserVerification: webAuthnUserVerification,
+		},
+		Attestation: "none",
+	}, nil
+}
+
+func (a *App) FinishWebAuthnRegistration(rctx request.CTX, userID string, registration *model.WebAuthnRegistration) (*model.WebAuthnCredential, *model.AppError) {
+	user, appErr := a.GetUser(userID)
+	if appErr != nil {
+		return nil, appErr
+	}
+
+	if user.AuthService != "" && user.AuthService != model.UserAuthServiceLdap {
+		return nil, model.NewAppError("FinishWebAuthnRegistration", "api.user.activate_mfa.email_and_ldap_only.app_error", nil, "", http.StatusBadRequest)
+	}
+
+	w, appErr := a.webAuthn()
+	if appErr != nil {
+		return nil, appErr
+	}
+
+	clientDataJSON, err := base64.RawURLEncoding.DecodeString(registration.Response.ClientDataJSON)
+	if err != nil {
+		return nil, model.NewAppError("FinishWebAuthnRegistration", "app.webauthn.invalid_response.app_error", nil, "", http.StatusBadRequest).Wrap(err)
+	}
+
+	attestationObject, err := base64.RawURLEncoding.DecodeString(registration.Response.AttestationObject)
+	if err != nil {
+		return nil, model.NewAppError("FinishWebAuthnRegistration", "app.webauthn.invalid_response.app_error", nil, "", http.StatusBadRequest).Wrap(err)
+	}
+
+	challenge, err := a.consumeWebAuthnChallenge(TokenTypeWebAuthnRegistration, user.Id, clientDataJSON)
+	if err != nil {
+		if errors.Is(err, errInvalidWebAuthnChallenge) || errors.Is(err, mfa.InvalidWebAuthnResponse) {
+			return nil, model.NewAppError("FinishWebAuthnRegistration", "app.webauthn.invalid_response.app_error", nil, "", http.StatusBadRequest).Wrap(err)
+		}
+		return nil, model.NewAppError("FinishWebAuthnRegistration", "app.webauthn.save_challenge.app_error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+
+	verified, err := w.VerifyRegistration(challenge, clientDataJSON, attestationObject)
+	if err != nil {
+		return nil, model.NewAppError("FinishWebAuthnRegistration", "app.webauthn.invalid_response.app_error", nil, "", http.StatusBadRequest).Wrap(err)
+	}
+
+	credential, err := a.Srv().Store().WebAuthnCredential().Save(&model.WebAuthnCredential{
+		UserId:       user.Id,
+		Name:         registration.Name,
+		CredentialId: base64.RawURLEncoding.EncodeToString(verified.ID),
+		PublicKey:    base64.RawURLEncoding.EncodeToString(verified.PublicKey),
+		SignCount:    int64(verified.SignCount),
+		AAGUID:       formatAAGUID(verified.AAGUID),
+	})
+	if err != nil {
+		var appErr *model.AppError
+		var cErr *store.ErrConflict
+		switch {
+		case errors.As(err, &appErr):
+			return nil, appErr
+		case errors.As(err, &cErr):
+			return nil, model.NewAppError("FinishWebAuthnRegistration", "app.webauthn.credential_exists.app_error", nil, "", http.StatusConflict).Wrap(err)
+		default:
+			return nil, model.NewAppError("FinishWebAuthnRegistration", "app.webauthn.save.app_error", nil, "", http.StatusInternalServerError).Wrap(err)
+		}
+	}
+
+	if !user.MfaActive {
+		// Drop any one-time password secret that was generated but never activated, so
+		// that it doesn't become usable alongside the new credential.
+		if err := a.Srv().Store().User().UpdateMfaSecret(user.Id, ""); err != nil {
+			return nil, model.NewAppError("FinishWebAuthnRegistration", "mfa.activate.app_error", nil, "", http.StatusInternalServerError).Wrap(err)
+		}
+
+		if err := a.Srv().Store().User().UpdateMfaActive(user.Id, true); err != nil {
+			return nil, model.NewAppError("FinishWebAuthnRegistration", "mfa.activate.app_error", nil, "", http.StatusInternalServerError).Wrap(err)
+		}
+	}
+
+	// Make sure old MFA status is not cached locally or in cluster nodes.
+	a.InvalidateCacheForUser(user.Id)
+
+	return credential, nil
+}
+
+func (a *App) GetWebAuthnCredentialsForUser(userID string) ([]*model.WebAuthnCredential, *model.AppError) {
+	credentials, err := a.Srv().Store().WebAuthnCredential().GetForUser(userID)
+	if err != nil {
+		return nil, model.NewAppError("GetWebAuthnCredentialsForUser", "app.webauthn.get.app_error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+
+	return credentials, nil
+}
+
+func (a *App) DeleteWebAuthnCredential(userID, credentialID string) *model.AppError {
+	credential, err := a.Srv().Store().WebAuthnCredential().Get(credentialID)
+	if err != nil {
+		var nfErr *store.ErrNotFound
+		if errors.As(err, &nfErr) {
+			return model.NewAppError("DeleteWebAuthnCredential", "app.webauthn.get.app_error", nil, "", http.StatusNotFound).Wrap(err)
+		}
+		return model.NewAppError("DeleteWebAuthnCredential", "app.webauthn.get.app_error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+
+	if credential.UserId != userID {
+		return model.NewAppError("DeleteWebAuthnCredential", "app.webauthn.get.app_error", nil, "", http.StatusNotFound)
+	}
+
+	user, appErr := a.GetUser(userID)
+	if appErr != nil {
+		return appErr
+	}
+
+	if err := a.Srv().Store().WebAuthnCredential().Delete(credential.Id); err != nil {
+		return model.NewAppError("DeleteWebAuthnCredential", "app.webauthn.delete.app_error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+
+	remaining, err := a.Srv().Store().WebAuthnCredential().GetForUser(userID)
+	if err != nil {
+		return model.NewAppError("DeleteWebAuthnCredential", "app.webauthn.get.app_error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+
+	// Without a one-time password to fall back on, removing the last credential turns
+	// multi-factor authentication off for the user.
+	if len(remaining) == 0 && user.MfaActive && user.MfaSecret == "" {
+		if err := a.Srv().Store().User().UpdateMfaActive(userID, false); err != nil {
+			return model.NewAppError("DeleteWebAuthnCredential", "mfa.deactivate.app_error", nil, "", http.StatusInternalServerError).Wrap(err)
+		}
+	}
+
+	// Make sure old MFA status is not cached locally or in cluster nodes.
+	a.InvalidateCacheForUser(userID)
+
+	return nil
+}
+
+// BeginWebAuthnLogin starts an authentication ceremony for the user with the given
+// login id. The resulting assertion is then sent as the MFA token when logging in.
+func (a *App) BeginWebAuthnLogin(rctx request.CTX, loginID string) (*model.WebAuthnRequestOptions, *model.AppError) {
+	w, appErr := a.webAuthn()
+	if appErr != nil {
+		return nil, appErr
+	}
+
+	// Unknown users and users without credentials get options with a decoy credential, so
+	// that the options don't reveal whether a user exists or registered credentials.
+	var userID string
+	var credentials []*model.WebAuthnCredential
+	if user, appErr := a.GetUserForLogin(rctx, "", loginID); appErr == nil {
+		var err error
+		userID = user.Id
+		credentials, err = a.Srv().Store().WebAuthnCredential().GetForUser(user.Id)
+		if err != nil {
+			return nil, model.NewAppError("BeginWebAuthnLogin", "app.webauthn.get.app_error", nil, "", http.StatusInternalServerError).Wrap(err)
+		}
+	}
+
+	allowCredentials := webAuthnCredentialDescriptors(credentials)
+	if len(allowCredentials) == 0 {
+		allowCredentials = a.webAuthnDecoyCredentials(loginID)
+	}
+
+	challenge, appErr := a.createWebAuthnLoginChallenge(userID)
+	if appErr != nil {
+		return nil, appErr
+	}
+
+	return &model.WebAuthnRequestOptions{
+		Challenge:        challenge,
+		Timeout:          model.WebAuthnTimeout,
+		RelyingPartyId:   w.RelyingPartyID(),
+		AllowCredentials: allowCredentials,
+		UserVerification: webAuthnUserVerification,
+	}, nil
+}
+
+// checkUserWebAuthn validates a WebAuthn assertion sent in place of a one-time password.
+func (a *App) checkUserWebAuthn(rctx request.CTX, user *model.User, token string) *model.AppError {
+	var assertion model.WebAuthnAssertion
+	if err := json.Unmarshal([]byte(token), &assertion); err != nil {
+		return model.NewAppError("checkUserWebAuthn", "mfa.validate_token.authenticate.app_error", nil, "", http.StatusBadRequest).Wrap(err)
+	}
+
+	w, appErr := a.webAuthn()
+	if appErr != nil {
+		return appErr
+	}
+
+	var decoded [4][]byte
+	for i, value := range []string{assertion.Id, assertion.Response.ClientDataJSON, assertion.Response.AuthenticatorData, assertion.Response.Signature} {
+		var err error
+		if decoded[i], err = base64.RawURLEncoding.DecodeString(value); err != nil {
+			return model.NewAppError("checkUserWebAuthn", "mfa.validate_token.authenticate.app_error", nil, "", http.StatusBadRequest).Wrap(err)
+		}
+	}
+	credentialID, clientDataJSON, authenticatorData, signature := decoded[0], decoded[1], decoded[2], decoded[3]
+
+	credential, err := a.Srv().Store().WebAuthnCredential().GetByCredentialId(base64.RawURLEncoding.EncodeToString(credentialID))
+	if err != nil {
+		var nfErr *store.ErrNotFound
+		if errors.As(err, &nfErr) {
+			return model.NewAppError("checkUserWebAuthn", "api.user.check_user_mfa.bad_code.app_error", nil, "", http.StatusUnauthorized).Wrap(err)
+		}
+		return model.NewAppError("checkUserWebAuthn", "app.webauthn.get.app_error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+
+	if credential.UserId != user.Id {
+		return model.NewAppError("checkUserWebAuthn", "api.user.check_user_mfa.bad_code.app_error", nil, "", http.StatusUnauthorized)
+	}
+
+	publicKey, err := base64.RawURLEncoding.DecodeString(credential.PublicKey)
+	if err != nil {
+		return model.NewAppError("checkUserWebAuthn", "app.webauthn.get.app_error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+
+	challenge, err := a.consumeWebAuthnLoginChallenge(user.Id, clientDataJSON)
+	if err != nil {
+		if errors.Is(err, errInvalidWebAuthnChallenge) || errors.Is(err, mfa.InvalidWebAuthnResponse) {
+			return model.NewAppError("checkUserWebAuthn", "api.user.check_user_mfa.bad_code.app_error", nil, "", http.StatusUnauthorized).Wrap(err)
+		}
+		return model.NewAppError("checkUserWebAuthn", "app.webauthn.save_challenge.app_error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+
+	signCount, err := w.VerifyAssertion(challenge, &mfa.WebAuthnCredential{
+		ID:        credentialID,
+		PublicKey: publicKey,
+		SignCount: uint32(credential.SignCount),
+	}, clientDataJSON, authenticatorData, signature)
+	if err != nil {
+		return model.NewAppError("checkUserWebAuthn", "api.user.check_user_mfa.bad_code.app_error", nil, "", http.StatusUnauthorized).Wrap(err)
+	}
+
+	if err := a.Srv().Store().WebAuthnCredential().UpdateSignCount(credential.Id, int64(signCount), model.GetMillis()); err != nil {
+		return model.NewAppError("checkUserWebAuthn", "app.webauthn.save.app_error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+
+	return nil
+}
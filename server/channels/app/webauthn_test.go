@@ -0,0 +1,70 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package app
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost/server/public/model"
+)
+
+func TestBeginWebAuthnLogin(t *testing.T) {
+	th := Setup(t).InitBasic()
+	defer th.TearDown()
+
+	th.App.UpdateConfig(func(cfg *model.Config) {
+		*cfg.ServiceSettings.EnableMultifactorAuthentication = true
+		*cfg.ServiceSettings.SiteURL = "https://mattermost.example.com"
+	})
+
+	credential, err := th.App.Srv().Store().WebAuthnCredential().Save(&model.WebAuthnCredential{
+		UserId:       th.BasicUser.Id,
+		Name:         "key",
+		CredentialId: base64.RawURLEncoding.EncodeToString([]byte(model.NewId())),
+		PublicKey:    base64.RawURLEncoding.EncodeToString([]byte("key")),
+	})
+	require.NoError(t, err)
+
+	t.Run("users with credentials get them", func(t *testing.T) {
+		options, appErr := th.App.BeginWebAuthnLogin(th.Context, th.BasicUser.Username)
+		require.Nil(t, appErr)
+		require.Len(t, options.AllowCredentials, 1)
+		assert.Equal(t, credential.CredentialId, options.AllowCredentials[0].Id)
+	})
+
+	t.Run("unknown users and users without credentials get a decoy credential", func(t *testing.T) {
+		for _, loginID := range []string{th.BasicUser2.Username, "unknown" + model.NewId()} {
+			options, appErr := th.App.BeginWebAuthnLogin(th.Context, loginID)
+			require.Nil(t, appErr, loginID)
+			require.Len(t, options.AllowCredentials, 1, loginID)
+			assert.Len(t, options.AllowCredentials[0].Id, len(base64.RawURLEncoding.EncodeToString(make([]byte, 32))))
+
+			again, appErr := th.App.BeginWebAuthnLogin(th.Context, loginID)
+			require.Nil(t, appErr)
+			assert.Equal(t, options.AllowCredentials, again.AllowCredentials, loginID)
+			assert.NotEqual(t, options.Challenge, again.Challenge, loginID)
+		}
+	})
+
+	t.Run("login challenges are bound to the user and used once", func(t *testing.T) {
+		options, appErr := th.App.BeginWebAuthnLogin(th.Context, th.BasicUser.Username)
+		require.Nil(t, appErr)
+		clientDataJSON, err := json.Marshal(map[string]string{"type": "webauthn.get", "challenge": options.Challenge})
+		require.NoError(t, err)
+
+		_, err = th.App.consumeWebAuthnLoginChallenge(th.BasicUser2.Id, clientDataJSON)
+		require.ErrorIs(t, err, errInvalidWebAuthnChallenge)
+
+		_, err = th.App.consumeWebAuthnLoginChallenge(th.BasicUser.Id, clientDataJSON)
+		require.NoError(t, err)
+
+		_, err = th.App.consumeWebAuthnLoginChallenge(th.BasicUser.Id, clientDataJSON)
+		require.ErrorIs(t, err, errInvalidWebAuthnChallenge)
+	})
+}
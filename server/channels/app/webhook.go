@@ -8,9 +8,11 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"io"
 	"net/http"
 	"regexp"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -27,7 +29,8 @@ const (
 	TriggerwordsExactMatch = 0
 	TriggerwordsStartsWith = 1
 
-	MaxIntegrationResponseSize = 1024 * 1024 // Posts can be <100KB at most, so this is likely more than enough
+	MaxIntegrationResponseSize      = 1024 * 1024 // Posts can be <100KB at most, so this is likely more than enough
+	maxOutgoingWebhookErrorBodySize = 512         // Only the start of an error response is kept with a failed delivery
 )
 
 var linkWithTextRegex = regexp.MustCompile(`<([^\n<\|>]+)\|([^\|\n>]+)>`)
@@ -95,28 +98,24 @@ func (a *App) handleWebhookEvents(c request.CTX, post *model.Post, team *model.T
 }
 
 func (a *App) TriggerWebhook(c request.CTX, payload *model.OutgoingWebhookPayload, hook *model.OutgoingWebhook, post *model.Post, channel *model.Channel) {
-	var jsonBytes []byte
+	var body []byte
 	var err error
 
 	contentType := "application/x-www-form-urlencoded"
 	if hook.ContentType == "application/json" {
 		contentType = "application/json"
-		jsonBytes, err = json.Marshal(payload)
+		body, err = json.Marshal(payload)
 		if err != nil {
 			c.Logger().Warn("Failed to encode to JSON", mlog.Err(err))
 			return
 		}
+	} else {
+		body = []byte(payload.ToFormValues())
 	}
 
 	var wg sync.WaitGroup
 
 	for i := range hook.CallbackURLs {
-		var body io.Reader
-		if hook.ContentType == "application/json" {
-			body = bytes.NewReader(jsonBytes)
-		} else {
-			body = strings.NewReader(payload.ToFormValues())
-		}
 		wg.Add(1)
 
 		// Get the callback URL by index to properly capture it for the go func
@@ -125,81 +124,126 @@ func (a *App) TriggerWebhook(c request.CTX, payload *model.OutgoingWebhookPayloa
 		go func() {
 			defer wg.Done()
 
-			var accessToken *model.OutgoingOAuthConnectionToken
+			if err := a.deliverOutgoingWebhook(c, hook, url, body, contentType, post, channel); err != nil {
+				a.queueOutgoingWebhookDelivery(c, hook, url, body, contentType, post, err)
+			}
+		}()
+	}
+	wg.Wait()
+}
 
-			// Retrieve an access token from a connection if one exists to use for the webhook request
-			if a.Config().ServiceSettings.EnableOutgoingOAuthConnections != nil && *a.Config().ServiceSettings.EnableOutgoingOAuthConnections && a.OutgoingOAuthConnections() != nil {
-				connection, err := a.OutgoingOAuthConnections().GetConnectionForAudience(c, url)
-				if err != nil {
-					c.Logger().Error("Failed to find an outgoing oauth connection for the webhook", mlog.Err(err))
-					return
-				}
+// deliverOutgoingWebhook sends an outgoing webhook request to one of the hook's callback URLs
+// and posts the response, if any, in reply. Post and channel may be nil when a retried
+// delivery's post or channel no longer exists, in which case the response is dropped.
+func (a *App) deliverOutgoingWebhook(c request.CTX, hook *model.OutgoingWebhook, url string, body []byte, contentType string, post *model.Post, channel *model.Channel) error {
+	var accessToken *model.OutgoingOAuthConnectionToken
 
-				if connection != nil {
-					accessToken, err = a.OutgoingOAuthConnections().RetrieveTokenForConnection(c, connection)
-					if err != nil {
-						c.Logger().Error("Failed to retrieve token for outgoing oauth connection", mlog.Err(err))
-						return
-					}
-				}
-			}
+	// Retrieve an access token from a connection if one exists to use for the webhook request
+	if a.Config().ServiceSettings.EnableOutgoingOAuthConnections != nil && *a.Config().ServiceSettings.EnableOutgoingOAuthConnections && a.OutgoingOAuthConnections() != nil {
+		connection, err := a.OutgoingOAuthConnections().GetConnectionForAudience(c, url)
+		if err != nil {
+			c.Logger().Error("Failed to find an outgoing oauth connection for the webhook", mlog.Err(err))
+			return err
+		}
 
-			webhookResp, err := a.doOutgoingWebhookRequest(url, body, contentType, accessToken)
+		if connection != nil {
+			accessToken, err = a.OutgoingOAuthConnections().RetrieveTokenForConnection(c, connection)
 			if err != nil {
-				if errors.Is(err, context.DeadlineExceeded) {
-					c.Logger().Error("Outgoing Webhook POST timed out. Consider increasing ServiceSettings.OutgoingIntegrationRequestsTimeout.", mlog.Err(err))
-				} else {
-					c.Logger().Error("Outgoing Webhook POST failed", mlog.Err(err))
-				}
-				return
+				c.Logger().Error("Failed to retrieve token for outgoing oauth connection", mlog.Err(err))
+				return err
 			}
+		}
+	}
 
-			if webhookResp != nil && (webhookResp.Text != nil || len(webhookResp.Attachments) > 0) {
-				postRootId := ""
-				if webhookResp.ResponseType == model.OutgoingHookResponseTypeComment {
-					postRootId = post.Id
-				}
-				if len(webhookResp.Props) == 0 {
-					webhookResp.Props = make(model.StringInterface)
-				}
-				webhookResp.Props["webhook_display_name"] = hook.DisplayName
+	webhookResp, err := a.sendOutgoingWebhookRequest(url, body, contentType, hook.SigningSecret, accessToken)
+	if err != nil {
+		// The delivery is tracked for retries, so an error status counts as a failed attempt and its
+		// response isn't posted.
+		var statusErr *outgoingWebhookStatusError
+		if errors.As(err, &statusErr) {
+			c.Logger().Error("Outgoing Webhook POST failed", mlog.Int("status_code", statusErr.StatusCode))
+			return model.NewAppError("deliverOutgoingWebhook", "api.outgoing_webhook.failed_resp.app_error", map[string]any{"Status": http.StatusText(statusErr.StatusCode)}, "", http.StatusInternalServerError).Wrap(err)
+		}
+		if errors.Is(err, context.DeadlineExceeded) {
+			c.Logger().Error("Outgoing Webhook POST timed out. Consider increasing ServiceSettings.OutgoingIntegrationRequestsTimeout.", mlog.Err(err))
+		} else {
+			c.Logger().Error("Outgoing Webhook POST failed", mlog.Err(err))
+		}
+		return err
+	}
 
-				text := ""
-				if webhookResp.Text != nil {
-					text = a.ProcessSlackText(*webhookResp.Text)
-				}
-				webhookResp.Attachments = a.ProcessSlackAttachments(webhookResp.Attachments)
-				// attachments is in here for slack compatibility
-				if len(webhookResp.Attachments) > 0 {
-					webhookResp.Props["attachments"] = webhookResp.Attachments
-				}
-				if *a.Config().ServiceSettings.EnablePostUsernameOverride && hook.Username != "" && webhookResp.Username == "" {
-					webhookResp.Username = hook.Username
-				}
+	if post == nil || channel == nil {
+		return nil
+	}
 
-				if *a.Config().ServiceSettings.EnablePostIconOverride && hook.IconURL != "" && webhookResp.IconURL == "" {
-					webhookResp.IconURL = hook.IconURL
-				}
-				if _, err := a.CreateWebhookPost(c, hook.CreatorId, channel, text, webhookResp.Username, webhookResp.IconURL, "", webhookResp.Props, webhookResp.Type, postRootId, webhookResp.Priority); err != nil {
-					c.Logger().Error("Failed to create response post.", mlog.Err(err))
-				}
-			}
-		}()
+	if webhookResp != nil && (webhookResp.Text != nil || len(webhookResp.Attachments) > 0) {
+		postRootId := ""
+		if webhookResp.ResponseType == model.OutgoingHookResponseTypeComment {
+			postRootId = post.Id
+		}
+		if len(webhookResp.Props) == 0 {
+			webhookResp.Props = make(model.StringInterface)
+		}
+		webhookResp.Props["webhook_display_name"] = hook.DisplayName
+
+		text := ""
+		if webhookResp.Text != nil {
+			text = a.ProcessSlackText(*webhookResp.Text)
+		}
+		webhookResp.Attachments = a.ProcessSlackAttachments(webhookResp.Attachments)
+		// attachments is in here for slack compatibility
+		if len(webhookResp.Attachments) > 0 {
+			webhookResp.Props["attachments"] = webhookResp.Attachments
+		}
+		if *a.Config().ServiceSettings.EnablePostUsernameOverride && hook.Username != "" && webhookResp.Username == "" {
+			webhookResp.Username = hook.Username
+		}
+
+		if *a.Config().ServiceSettings.EnablePostIconOverride && hook.IconURL != "" && webhookResp.IconURL == "" {
+			webhookResp.IconURL = hook.IconURL
+		}
+		if _, err := a.CreateWebhookPost(c, hook.CreatorId, channel, text, webhookResp.Username, webhookResp.IconURL, "", webhookResp.Props, webhookResp.Type, postRootId, webhookResp.Priority); err != nil {
+			c.Logger().Error("Failed to create response post.", mlog.Err(err))
+		}
 	}
-	wg.Wait()
+
+	return nil
 }
 
-func (a *App) doOutgoingWebhookRequest(url string, body io.Reader, contentType string, accessToken *model.OutgoingOAuthConnectionToken) (*model.OutgoingWebhookResponse, error) {
+// outgoingWebhookStatusError is returned when a callback URL answers an outgoing webhook
+// request with an error status. Body holds the start of the response, which usually says
+// why the request was rejected.
+type outgoingWebhookStatusError struct {
+	StatusCode int
+	Body       string
+}
+
+func (e *outgoingWebhookStatusError) Error() string {
+	return fmt.Sprintf("callback URL responded with status %d: %s", e.StatusCode, e.Body)
+}
+
+// Retryable reports whether the request may succeed if it's sent again. Other client errors
+// mean the receiver rejected the request itself, so sending it again won't help.
+func (e *outgoingWebhookStatusError) Retryable() bool {
+	return e.StatusCode >= http.StatusInternalServerError ||
+		e.StatusCode == http.StatusRequestTimeout ||
+		e.StatusCode == http.StatusTooManyRequests
+}
+
+// sendOutgoingWebhookRequest sends an outgoing webhook request and returns the decoded
+// response, if any. An error status is returned as an *outgoingWebhookStatusError.
+func (a *App) sendOutgoingWebhookRequest(url string, body []byte, contentType string, signingSecret string, accessToken *model.OutgoingOAuthConnectionToken) (*model.OutgoingWebhookResponse, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(*a.Config().ServiceSettings.OutgoingIntegrationRequestsTimeout)*time.Second)
 	defer cancel()
 
-	req, err := http.NewRequestWithContext(ctx, "POST", url, body)
+	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewReader(body))
 	if err != nil {
 		return nil, err
 	}
 
 	req.Header.Set("Content-Type", contentType)
 	req.Header.Set("Accept", "application/json")
+	signIntegrationRequest(req, signingSecret, body)
 
 	if accessToken != nil {
 		req.Header.Add("Authorization", accessToken.AsHeaderValue())
@@ -212,17 +256,36 @@ func (a *App) doOutgoingWebhookRequest(url string, body io.Reader, contentType s
 
 	defer resp.Body.Close()
 
+	if resp.StatusCode >= http.StatusBadRequest {
+		errBody, _ := io.ReadAll(io.LimitReader(resp.Body, maxOutgoingWebhookErrorBodySize))
+		return nil, &outgoingWebhookStatusError{StatusCode: resp.StatusCode, Body: strings.ToValidUTF8(string(errBody), "")}
+	}
+
 	var hookResp model.OutgoingWebhookResponse
 	if jsonErr := json.NewDecoder(io.LimitReader(resp.Body, MaxIntegrationResponseSize)).Decode(&hookResp); jsonErr != nil {
 		if jsonErr == io.EOF {
 			return nil, nil
 		}
-		return nil, model.NewAppError("doOutgoingWebhookRequest", "api.unmarshal_error", nil, "", http.StatusInternalServerError).Wrap(jsonErr)
+		return nil, model.NewAppError("sendOutgoingWebhookRequest", "api.unmarshal_error", nil, "", http.StatusInternalServerError).Wrap(jsonErr)
 	}
 
 	return &hookResp, nil
 }
 
+// signIntegrationRequest adds the timestamp and HMAC signature headers that let the
+// receiver of an outgoing webhook or slash command request check that it was sent by
+// this server and wasn't replayed. Integrations created before signing secrets were
+// introduced have no secret until it is rotated, so their requests are left unsigned.
+func signIntegrationRequest(req *http.Request, signingSecret string, payload []byte) {
+	if signingSecret == "" {
+		return
+	}
+
+	timestamp := time.Now().Unix()
+	req.Header.Set(model.HeaderIntegrationTimestamp, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(model.HeaderIntegrationSignature, model.SignIntegrationPayload(signingSecret, timestamp, payload))
+}
+
 func SplitWebhookPost(post *model.Post, maxPostSize int) ([]*model.Post, *model.AppError) {
 	splits := make([]*model.Post, 0)
 	remainingText := post.Message
@@ -614,6 +677,7 @@ func (a *App) UpdateOutgoingWebhook(c request.CTX, oldHook, updatedHook *model.O
 		}
 	}
 
+	updatedHook.SigningSecret = oldHook.SigningSecret
 	updatedHook.CreatorId = oldHook.CreatorId
 	updatedHook.CreateAt = oldHook.CreateAt
 	updatedHook.DeleteAt = oldHook.DeleteAt
@@ -703,6 +767,10 @@ func (a *App) DeleteOutgoingWebhook(hookID string) *model.AppError {
 		return model.NewAppError("DeleteOutgoingWebhook", "app.webhooks.delete_outgoing.app_error", nil, "", http.StatusInternalServerError).Wrap(err)
 	}
 
+	if err := a.Srv().Store().Webhook().PermanentDeleteOutgoingDeliveriesByHook(hookID); err != nil {
+		a.Log().Warn("Failed to delete the queued deliveries of a deleted outgoing webhook", mlog.String("hook_id", hookID), mlog.Err(err))
+	}
+
 	return nil
 }
 
@@ -721,6 +789,21 @@ func (a *App) RegenOutgoingWebhookToken(hook *model.OutgoingWebhook) (*model.Out
 	return webhook, nil
 }
 
+func (a *App) RegenOutgoingWebhookSigningSecret(hook *model.OutgoingWebhook) (*model.OutgoingWebhook, *model.AppError) {
+	if !*a.Config().ServiceSettings.EnableOutgoingWebhooks {
+		return nil, model.NewAppError("RegenOutgoingWebhookSigningSecret", "api.outgoing_webhook.disabled.app_error", nil, "", http.StatusNotImplemented)
+	}
+
+	hook.SigningSecret = model.NewIntegrationSigningSecret()
+
+	webhook, err := a.Srv().Store().Webhook().UpdateOutgoing(hook)
+	if err != nil {
+		return nil, model.NewAppError("RegenOutgoingWebhookSigningSecret", "app.webhooks.update_outgoing.app_error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+
+	return webhook, nil
+}
+
 func (a *App) HandleIncomingWebhook(c request.CTX, hookID string, req *model.IncomingWebhookRequest) *model.AppError {
 	if !*a.Config().ServiceSettings.EnableIncomingWebhooks {
 		return model.NewAppError("HandleIncomingWebhook", "web.incoming_webhook.disabled.app_error", nil, "", http.StatusNotImplemented)
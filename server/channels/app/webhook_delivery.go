@@ -0,0 +1,215 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package app
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"slices"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+	"github.com/mattermost/mattermost/server/public/shared/request"
+	"github.com/mattermost/mattermost/server/v8/channels/store"
+	"github.com/mattermost/mattermost/server/v8/channels/utils"
+)
+
+const getDueOutgoingWebhookDeliveriesPageSize = 100
+
+// queueOutgoingWebhookDelivery persists an outgoing webhook request that failed on its first
+// attempt so that it's retried later instead of being lost.
+func (a *App) queueOutgoingWebhookDelivery(c request.CTX, hook *model.OutgoingWebhook, url string, body []byte, contentType string, post *model.Post, deliveryErr error) {
+	delivery := &model.OutgoingWebhookDelivery{
+		HookId:      hook.Id,
+		TeamId:      hook.TeamId,
+		ChannelId:   post.ChannelId,
+		PostId:      post.Id,
+		CallbackURL: url,
+		ContentType: contentType,
+		Payload:     string(body),
+	}
+	recordOutgoingWebhookDeliveryFailure(delivery, deliveryErr)
+
+	if _, err := a.Srv().Store().Webhook().SaveOutgoingDelivery(delivery); err != nil {
+		c.Logger().Error("Failed to queue outgoing webhook delivery for retry", mlog.String("hook_id", hook.Id), mlog.Err(err))
+	}
+}
+
+// recordOutgoingWebhookDeliveryFailure counts a failed attempt and schedules the next one,
+// moving the delivery to the dead-letter list once it has used up its retries or when the
+// receiver rejected the request with a client error that retrying won't fix.
+func recordOutgoingWebhookDeliveryFailure(delivery *model.OutgoingWebhookDelivery, deliveryErr error) {
+	now := model.GetMillis()
+
+	delivery.Attempts++
+	delivery.LastError = deliveryErr.Error()
+	delivery.LastAttemptAt = now
+
+	var statusErr *outgoingWebhookStatusError
+	retryable := !errors.As(deliveryErr, &statusErr) || statusErr.Retryable()
+
+	if backoff, ok := utils.DeliveryBackoff(delivery.Attempts); ok && retryable {
+		delivery.Status = model.OutgoingWebhookDeliveryStatusPending
+		delivery.NextAttemptAt = now + backoff.Milliseconds()
+		return
+	}
+
+	delivery.Status = model.OutgoingWebhookDeliveryStatusDeadLetter
+	delivery.NextAttemptAt = 0
+}
+
+// RetryOutgoingWebhookDeliveries retries every queued outgoing webhook delivery that is due.
+func (a *App) RetryOutgoingWebhookDeliveries(rctx request.CTX) error {
+	beforeTime := model.GetMillis()
+	afterTime := int64(0)
+	lastDeliveryID := ""
+
+	for {
+		deliveries, err := a.Srv().Store().Webhook().GetDueOutgoingDeliveries(beforeTime, afterTime, lastDeliveryID, getDueOutgoingWebhookDeliveriesPageSize)
+		if err != nil {
+			return err
+		}
+
+		for _, delivery := range deliveries {
+			a.retryOutgoingWebhookDelivery(rctx, delivery)
+		}
+
+		if len(deliveries) < getDueOutgoingWebhookDeliveriesPageSize {
+			return nil
+		}
+
+		last := deliveries[len(deliveries)-1]
+		afterTime = last.NextAttemptAt
+		lastDeliveryID = last.Id
+	}
+}
+
+func (a *App) retryOutgoingWebhookDelivery(rctx request.CTX, delivery *model.OutgoingWebhookDelivery) {
+	logger := rctx.Logger().With(
+		mlog.String("delivery_id", delivery.Id),
+		mlog.String("hook_id", delivery.HookId),
+		mlog.Int("attempts", delivery.Attempts),
+	)
+
+	hook, err := a.Srv().Store().Webhook().GetOutgoing(delivery.HookId)
+	if err != nil {
+		var nfErr *store.ErrNotFound
+		if !errors.As(err, &nfErr) {
+			logger.Warn("Failed to get the outgoing webhook of a queued delivery", mlog.Err(err))
+			return
+		}
+	}
+
+	// The hook was deleted or no longer calls this URL, so there's nobody left to deliver to.
+	if hook == nil || !slices.Contains(hook.CallbackURLs, delivery.CallbackURL) {
+		if err := a.Srv().Store().Webhook().PermanentDeleteOutgoingDelivery(delivery.Id); err != nil {
+			logger.Warn("Failed to delete obsolete outgoing webhook delivery", mlog.Err(err))
+		}
+		return
+	}
+
+	// The response is only posted if the triggering post and its channel are still around.
+	var post *model.Post
+	channel, err := a.Srv().Store().Channel().Get(delivery.ChannelId, true)
+	if err == nil && channel.DeleteAt == 0 {
+		post, err = a.Srv().Store().Post().GetSingle(rctx, delivery.PostId, false)
+		if err != nil {
+			post = nil
+		}
+	}
+
+	// The hook was read again above, so that the retry carries its current token.
+	payload, err := outgoingWebhookDeliveryPayload(delivery, hook)
+	if err != nil {
+		logger.Warn("Failed to update the token of a queued outgoing webhook delivery", mlog.Err(err))
+		payload = []byte(delivery.Payload)
+	}
+
+	if err := a.deliverOutgoingWebhook(rctx, hook, delivery.CallbackURL, payload, delivery.ContentType, post, channel); err != nil {
+		recordOutgoingWebhookDeliveryFailure(delivery, err)
+		if _, err := a.Srv().Store().Webhook().UpdateOutgoingDelivery(delivery); err != nil {
+			logger.Error("Failed to record outgoing webhook delivery failure", mlog.Err(err))
+			return
+		}
+
+		if delivery.Status == model.OutgoingWebhookDeliveryStatusDeadLetter {
+			logger.Warn("Outgoing webhook delivery failed too many times and was moved to the dead-letter list")
+		}
+		return
+	}
+
+	if err := a.Srv().Store().Webhook().PermanentDeleteOutgoingDelivery(delivery.Id); err != nil {
+		logger.Error("Failed to delete delivered outgoing webhook delivery", mlog.Err(err))
+	}
+}
+
+// outgoingWebhookDeliveryPayload returns the payload of a queued delivery with the current token
+// of its hook, which may have been regenerated since the delivery was queued.
+func outgoingWebhookDeliveryPayload(delivery *model.OutgoingWebhookDelivery, hook *model.OutgoingWebhook) ([]byte, error) {
+	if delivery.ContentType == "application/json" {
+		var payload model.OutgoingWebhookPayload
+		if err := json.Unmarshal([]byte(delivery.Payload), &payload); err != nil {
+			return nil, err
+		}
+		payload.Token = hook.Token
+		return json.Marshal(&payload)
+	}
+
+	values, err := url.ParseQuery(delivery.Payload)
+	if err != nil {
+		return nil, err
+	}
+	values.Set("token", hook.Token)
+	return []byte(values.Encode()), nil
+}
+
+func (a *App) GetOutgoingWebhookDeadLetters(hookID string, page, perPage int) ([]*model.OutgoingWebhookDelivery, *model.AppError) {
+	if !*a.Config().ServiceSettings.EnableOutgoingWebhooks {
+		return nil, model.NewAppError("GetOutgoingWebhookDeadLetters", "api.outgoing_webhook.disabled.app_error", nil, "", http.StatusNotImplemented)
+	}
+
+	deliveries, err := a.Srv().Store().Webhook().GetOutgoingDeliveriesByHook(hookID, model.OutgoingWebhookDeliveryStatusDeadLetter, page*perPage, perPage)
+	if err != nil {
+		return nil, model.NewAppError("GetOutgoingWebhookDeadLetters", "app.webhooks.get_outgoing_deliveries.app_error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+
+	return deliveries, nil
+}
+
+// ReplayOutgoingWebhookDelivery moves a dead-lettered delivery back to the retry queue, where
+// the next run of the delivery job picks it up.
+func (a *App) ReplayOutgoingWebhookDelivery(hookID, deliveryID string) (*model.OutgoingWebhookDelivery, *model.AppError) {
+	if !*a.Config().ServiceSettings.EnableOutgoingWebhooks {
+		return nil, model.NewAppError("ReplayOutgoingWebhookDelivery", "api.outgoing_webhook.disabled.app_error", nil, "", http.StatusNotImplemented)
+	}
+
+	delivery, err := a.Srv().Store().Webhook().GetOutgoingDelivery(deliveryID)
+	if err != nil {
+		var nfErr *store.ErrNotFound
+		switch {
+		case errors.As(err, &nfErr):
+			return nil, model.NewAppError("ReplayOutgoingWebhookDelivery", "app.webhooks.get_outgoing_delivery.app_error", nil, "", http.StatusNotFound).Wrap(err)
+		default:
+			return nil, model.NewAppError("ReplayOutgoingWebhookDelivery", "app.webhooks.get_outgoing_delivery.app_error", nil, "", http.StatusInternalServerError).Wrap(err)
+		}
+	}
+
+	if delivery.HookId != hookID {
+		return nil, model.NewAppError("ReplayOutgoingWebhookDelivery", "app.webhooks.get_outgoing_delivery.app_error", nil, "", http.StatusNotFound)
+	}
+
+	if delivery.Status != model.OutgoingWebhookDeliveryStatusDeadLetter {
+		return nil, model.NewAppError("ReplayOutgoingWebhookDelivery", "app.webhooks.replay_outgoing_delivery.not_dead_letter.app_error", nil, "", http.StatusBadRequest)
+	}
+
+	delivery.Requeue()
+	delivery, err = a.Srv().Store().Webhook().UpdateOutgoingDelivery(delivery)
+	if err != nil {
+		return nil, model.NewAppError("ReplayOutgoingWebhookDelivery", "app.webhooks.update_outgoing_delivery.app_error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+
+	return delivery, nil
+}
@@ -0,0 +1,228 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package app
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost/server/public/model"
+)
+
+func TestOutgoingWebhookDeliveryRetries(t *testing.T) {
+	th := Setup(t).InitBasic()
+	defer th.TearDown()
+
+	th.App.UpdateConfig(func(cfg *model.Config) {
+		*cfg.ServiceSettings.EnableOutgoingWebhooks = true
+		*cfg.ServiceSettings.AllowedUntrustedInternalConnections = "localhost,127.0.0.1"
+	})
+
+	var failing, rejecting atomic.Bool
+	var requests atomic.Int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		if rejecting.Load() {
+			w.WriteHeader(http.StatusUnprocessableEntity)
+			w.Write([]byte("unknown channel"))
+			return
+		}
+		if failing.Load() {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer ts.Close()
+
+	hook, appErr := th.App.CreateOutgoingWebhook(&model.OutgoingWebhook{
+		ChannelId:    th.BasicChannel.Id,
+		TeamId:       th.BasicTeam.Id,
+		CallbackURLs: []string{ts.URL},
+		CreatorId:    th.BasicUser.Id,
+		ContentType:  "application/json",
+	})
+	require.Nil(t, appErr)
+
+	payload := &model.OutgoingWebhookPayload{
+		Token:     hook.Token,
+		TeamId:    hook.TeamId,
+		ChannelId: th.BasicChannel.Id,
+		PostId:    th.BasicPost.Id,
+		Text:      th.BasicPost.Message,
+	}
+
+	triggerFailingWebhook := func(t *testing.T) *model.OutgoingWebhookDelivery {
+		t.Helper()
+		failing.Store(true)
+		th.App.TriggerWebhook(th.Context, payload, hook, th.BasicPost, th.BasicChannel)
+
+		deliveries, err := th.App.Srv().Store().Webhook().GetOutgoingDeliveriesByHook(hook.Id, model.OutgoingWebhookDeliveryStatusPending, 0, 10)
+		require.NoError(t, err)
+		require.Len(t, deliveries, 1)
+		return deliveries[0]
+	}
+
+	makeDue := func(t *testing.T, delivery *model.OutgoingWebhookDelivery) {
+		t.Helper()
+		delivery.NextAttemptAt = model.GetMillis() - 1000
+		_, err := th.App.Srv().Store().Webhook().UpdateOutgoingDelivery(delivery)
+		require.NoError(t, err)
+	}
+
+	t.Run("failed request is queued", func(t *testing.T) {
+		delivery := triggerFailingWebhook(t)
+		defer func() {
+			require.NoError(t, th.App.Srv().Store().Webhook().PermanentDeleteOutgoingDelivery(delivery.Id))
+		}()
+
+		assert.Equal(t, 1, delivery.Attempts)
+		assert.Equal(t, ts.URL, delivery.CallbackURL)
+		assert.Equal(t, th.BasicPost.Id, delivery.PostId)
+		assert.NotEmpty(t, delivery.LastError)
+		assert.Greater(t, delivery.NextAttemptAt, delivery.LastAttemptAt)
+		assert.Contains(t, delivery.Payload, th.BasicPost.Id)
+	})
+
+	t.Run("retried delivery that succeeds is removed", func(t *testing.T) {
+		delivery := triggerFailingWebhook(t)
+		makeDue(t, delivery)
+
+		failing.Store(false)
+		before := requests.Load()
+		require.NoError(t, th.App.RetryOutgoingWebhookDeliveries(th.Context))
+		assert.Equal(t, before+1, requests.Load())
+
+		_, err := th.App.Srv().Store().Webhook().GetOutgoingDelivery(delivery.Id)
+		require.Error(t, err)
+	})
+
+	t.Run("retried delivery that fails is rescheduled", func(t *testing.T) {
+		delivery := triggerFailingWebhook(t)
+		defer func() {
+			require.NoError(t, th.App.Srv().Store().Webhook().PermanentDeleteOutgoingDelivery(delivery.Id))
+		}()
+		makeDue(t, delivery)
+
+		require.NoError(t, th.App.RetryOutgoingWebhookDeliveries(th.Context))
+
+		retried, err := th.App.Srv().Store().Webhook().GetOutgoingDelivery(delivery.Id)
+		require.NoError(t, err)
+		assert.Equal(t, 2, retried.Attempts)
+		assert.Equal(t, model.OutgoingWebhookDeliveryStatusPending, retried.Status)
+		assert.Greater(t, retried.NextAttemptAt, model.GetMillis())
+	})
+
+	t.Run("delivery is dead-lettered after too many attempts and can be replayed", func(t *testing.T) {
+		delivery := triggerFailingWebhook(t)
+		defer func() {
+			require.NoError(t, th.App.Srv().Store().Webhook().PermanentDeleteOutgoingDelivery(delivery.Id))
+		}()
+
+		delivery.Attempts = 5
+		makeDue(t, delivery)
+		require.NoError(t, th.App.RetryOutgoingWebhookDeliveries(th.Context))
+
+		deadLetters, appErr := th.App.GetOutgoingWebhookDeadLetters(hook.Id, 0, 10)
+		require.Nil(t, appErr)
+		require.Len(t, deadLetters, 1)
+		assert.Equal(t, delivery.Id, deadLetters[0].Id)
+		assert.Equal(t, 6, deadLetters[0].Attempts)
+
+		_, appErr = th.App.ReplayOutgoingWebhookDelivery(model.NewId(), delivery.Id)
+		require.NotNil(t, appErr)
+		assert.Equal(t, http.StatusNotFound, appErr.StatusCode)
+
+		replayed, appErr := th.App.ReplayOutgoingWebhookDelivery(hook.Id, delivery.Id)
+		require.Nil(t, appErr)
+		assert.Equal(t, model.OutgoingWebhookDeliveryStatusPending, replayed.Status)
+		assert.Zero(t, replayed.Attempts)
+
+		_, appErr = th.App.ReplayOutgoingWebhookDelivery(hook.Id, delivery.Id)
+		require.NotNil(t, appErr)
+		assert.Equal(t, http.StatusBadRequest, appErr.StatusCode)
+
+		deadLetters, appErr = th.App.GetOutgoingWebhookDeadLetters(hook.Id, 0, 10)
+		require.Nil(t, appErr)
+		assert.Empty(t, deadLetters)
+	})
+
+	t.Run("delivery rejected with a client error is dead-lettered without retries", func(t *testing.T) {
+		rejecting.Store(true)
+		defer rejecting.Store(false)
+		th.App.TriggerWebhook(th.Context, payload, hook, th.BasicPost, th.BasicChannel)
+
+		pending, err := th.App.Srv().Store().Webhook().GetOutgoingDeliveriesByHook(hook.Id, model.OutgoingWebhookDeliveryStatusPending, 0, 10)
+		require.NoError(t, err)
+		assert.Empty(t, pending)
+
+		deadLetters, appErr := th.App.GetOutgoingWebhookDeadLetters(hook.Id, 0, 10)
+		require.Nil(t, appErr)
+		require.Len(t, deadLetters, 1)
+		defer func() {
+			require.NoError(t, th.App.Srv().Store().Webhook().PermanentDeleteOutgoingDelivery(deadLetters[0].Id))
+		}()
+		assert.Equal(t, 1, deadLetters[0].Attempts)
+		assert.Contains(t, deadLetters[0].LastError, "422")
+		assert.Contains(t, deadLetters[0].LastError, "unknown channel")
+	})
+
+	t.Run("deliveries of a deleted hook are dropped", func(t *testing.T) {
+		delivery := triggerFailingWebhook(t)
+
+		require.Nil(t, th.App.DeleteOutgoingWebhook(hook.Id))
+
+		_, err := th.App.Srv().Store().Webhook().GetOutgoingDelivery(delivery.Id)
+		require.Error(t, err)
+	})
+}
+
+func TestOutgoingWebhookDeliveryPayload(t *testing.T) {
+	hook := &model.OutgoingWebhook{Token: model.NewId()}
+	payload := &model.OutgoingWebhookPayload{
+		Token:     model.NewId(),
+		TeamId:    model.NewId(),
+		PostId:    model.NewId(),
+		Text:      "text & more",
+		Timestamp: 1234000,
+	}
+
+	t.Run("json", func(t *testing.T) {
+		body, err := json.Marshal(payload)
+		require.NoError(t, err)
+
+		updated, err := outgoingWebhookDeliveryPayload(&model.OutgoingWebhookDelivery{ContentType: "application/json", Payload: string(body)}, hook)
+		require.NoError(t, err)
+
+		var received model.OutgoingWebhookPayload
+		require.NoError(t, json.Unmarshal(updated, &received))
+		expected := *payload
+		expected.Token = hook.Token
+		assert.Equal(t, expected, received)
+	})
+
+	t.Run("form", func(t *testing.T) {
+		updated, err := outgoingWebhookDeliveryPayload(&model.OutgoingWebhookDelivery{ContentType: "application/x-www-form-urlencoded", Payload: payload.ToFormValues()}, hook)
+		require.NoError(t, err)
+
+		values, err := url.ParseQuery(string(updated))
+		require.NoError(t, err)
+		assert.Equal(t, hook.Token, values.Get("token"))
+		assert.Equal(t, payload.PostId, values.Get("post_id"))
+		assert.Equal(t, payload.Text, values.Get("text"))
+		assert.Equal(t, "1234", values.Get("timestamp"))
+	})
+
+	t.Run("invalid json", func(t *testing.T) {
+		_, err := outgoingWebhookDeliveryPayload(&model.OutgoingWebhookDelivery{ContentType: "application/json", Payload: "{"}, hook)
+		require.Error(t, err)
+	})
+}
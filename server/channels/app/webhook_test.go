@@ -912,7 +912,7 @@ func (r InfiniteReader) Read(p []byte) (n int, err error) {
 	return len(p), nil
 }
 
-func TestDoOutgoingWebhookRequest(t *testing.T) {
+func TestSendOutgoingWebhookRequest(t *testing.T) {
 	th := Setup(t)
 	defer th.TearDown()
 
@@ -927,7 +927,7 @@ func TestDoOutgoingWebhookRequest(t *testing.T) {
 		}))
 		defer server.Close()
 
-		resp, err := th.App.doOutgoingWebhookRequest(server.URL, strings.NewReader(""), "application/json", nil)
+		resp, err := th.App.sendOutgoingWebhookRequest(server.URL, []byte{}, "application/json", "", nil)
 		require.NoError(t, err)
 
 		require.NotNil(t, resp)
@@ -941,7 +941,7 @@ func TestDoOutgoingWebhookRequest(t *testing.T) {
 		}))
 		defer server.Close()
 
-		_, err := th.App.doOutgoingWebhookRequest(server.URL, strings.NewReader(""), "application/json", nil)
+		_, err := th.App.sendOutgoingWebhookRequest(server.URL, []byte{}, "application/json", "", nil)
 		require.Error(t, err)
 		require.Equal(t, "api.unmarshal_error", err.(*model.AppError).Id)
 	})
@@ -952,7 +952,7 @@ func TestDoOutgoingWebhookRequest(t *testing.T) {
 		}))
 		defer server.Close()
 
-		_, err := th.App.doOutgoingWebhookRequest(server.URL, strings.NewReader(""), "application/json", nil)
+		_, err := th.App.sendOutgoingWebhookRequest(server.URL, []byte{}, "application/json", "", nil)
 		require.Error(t, err)
 		require.Equal(t, "api.unmarshal_error", err.(*model.AppError).Id)
 	})
@@ -963,7 +963,7 @@ func TestDoOutgoingWebhookRequest(t *testing.T) {
 		}))
 		defer server.Close()
 
-		_, err := th.App.doOutgoingWebhookRequest(server.URL, strings.NewReader(""), "application/json", nil)
+		_, err := th.App.sendOutgoingWebhookRequest(server.URL, []byte{}, "application/json", "", nil)
 		require.Error(t, err)
 		require.Equal(t, "api.unmarshal_error", err.(*model.AppError).Id)
 	})
@@ -982,7 +982,7 @@ func TestDoOutgoingWebhookRequest(t *testing.T) {
 			cfg.ServiceSettings.OutgoingIntegrationRequestsTimeout = model.NewPointer(int64(1))
 		})
 
-		_, err := th.App.doOutgoingWebhookRequest(server.URL, strings.NewReader(""), "application/json", nil)
+		_, err := th.App.sendOutgoingWebhookRequest(server.URL, []byte{}, "application/json", "", nil)
 		require.Error(t, err)
 		require.IsType(t, &url.Error{}, err)
 	})
@@ -999,7 +999,7 @@ func TestDoOutgoingWebhookRequest(t *testing.T) {
 			cfg.ServiceSettings.OutgoingIntegrationRequestsTimeout = model.NewPointer(int64(2))
 		})
 
-		resp, err := th.App.doOutgoingWebhookRequest(server.URL, strings.NewReader(""), "application/json", nil)
+		resp, err := th.App.sendOutgoingWebhookRequest(server.URL, []byte{}, "application/json", "", nil)
 		require.NoError(t, err)
 		require.NotNil(t, resp)
 		assert.NotNil(t, resp.Text)
@@ -1011,7 +1011,7 @@ func TestDoOutgoingWebhookRequest(t *testing.T) {
 		}))
 		defer server.Close()
 
-		resp, err := th.App.doOutgoingWebhookRequest(server.URL, strings.NewReader(""), "application/json", nil)
+		resp, err := th.App.sendOutgoingWebhookRequest(server.URL, []byte{}, "application/json", "", nil)
 		require.NoError(t, err)
 		require.Nil(t, resp)
 	})
@@ -1022,11 +1022,120 @@ func TestDoOutgoingWebhookRequest(t *testing.T) {
 		}))
 		defer server.Close()
 
-		resp, err := th.App.doOutgoingWebhookRequest(server.URL, strings.NewReader(""), "application/json", &model.OutgoingOAuthConnectionToken{
+		resp, err := th.App.sendOutgoingWebhookRequest(server.URL, []byte{}, "application/json", "", &model.OutgoingOAuthConnectionToken{
 			AccessToken: "test",
 			TokenType:   "Bearer",
 		})
 		require.NoError(t, err)
 		require.Equal(t, `Bearer test`, *resp.Text)
 	})
+
+	t.Run("with a signing secret", func(t *testing.T) {
+		secret := model.NewIntegrationSigningSecret()
+		body := []byte(`{"text":"signed"}`)
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			payload, err := io.ReadAll(r.Body)
+			require.NoError(t, err)
+
+			valid := model.VerifyIntegrationSignature(secret, r.Header.Get(model.HeaderIntegrationSignature), r.Header.Get(model.HeaderIntegrationTimestamp), payload, time.Now())
+			io.Copy(w, strings.NewReader(fmt.Sprintf(`{"text":"%t"}`, valid)))
+		}))
+		defer server.Close()
+
+		resp, err := th.App.sendOutgoingWebhookRequest(server.URL, body, "application/json", secret, nil)
+		require.NoError(t, err)
+		require.Equal(t, "true", *resp.Text)
+	})
+
+	t.Run("without a signing secret", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			io.Copy(w, strings.NewReader(fmt.Sprintf(`{"text":"%s"}`, r.Header.Get(model.HeaderIntegrationSignature))))
+		}))
+		defer server.Close()
+
+		resp, err := th.App.sendOutgoingWebhookRequest(server.URL, []byte{}, "application/json", "", nil)
+		require.NoError(t, err)
+		require.Empty(t, *resp.Text)
+	})
+}
+
+func TestDeliverOutgoingWebhook(t *testing.T) {
+	th := Setup(t).InitBasic()
+	defer th.TearDown()
+
+	th.App.UpdateConfig(func(cfg *model.Config) {
+		cfg.ServiceSettings.AllowedUntrustedInternalConnections = model.NewPointer("127.0.0.1")
+		*cfg.ServiceSettings.EnableOutgoingWebhooks = true
+	})
+
+	hook := &model.OutgoingWebhook{
+		ChannelId: th.BasicChannel.Id,
+		TeamId:    th.BasicTeam.Id,
+		CreatorId: th.BasicUser.Id,
+	}
+
+	t.Run("with a valid response", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			io.Copy(w, strings.NewReader(`{"text": "Hello, World!"}`))
+		}))
+		defer server.Close()
+
+		err := th.App.deliverOutgoingWebhook(th.Context, hook, server.URL, []byte{}, "application/json", th.BasicPost, th.BasicChannel)
+		require.NoError(t, err)
+	})
+
+	t.Run("with an error status", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusBadRequest)
+			io.Copy(w, strings.NewReader(`{"text": "Invalid command"}`))
+		}))
+		defer server.Close()
+
+		err := th.App.deliverOutgoingWebhook(th.Context, hook, server.URL, []byte{}, "application/json", th.BasicPost, th.BasicChannel)
+		require.Error(t, err)
+
+		var statusErr *outgoingWebhookStatusError
+		require.ErrorAs(t, err, &statusErr)
+		assert.Equal(t, http.StatusBadRequest, statusErr.StatusCode)
+		assert.Equal(t, `{"text": "Invalid command"}`, statusErr.Body)
+		assert.False(t, statusErr.Retryable())
+
+		// The response of a failed request isn't posted.
+		posts, appErr := th.App.GetPostsPage(model.GetPostsOptions{ChannelId: th.BasicChannel.Id, PerPage: 10})
+		require.Nil(t, appErr)
+		for _, post := range posts.Posts {
+			assert.NotEqual(t, "Invalid command", post.Message)
+		}
+	})
+
+	t.Run("with a large error response", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			io.Copy(w, InfiniteReader{})
+		}))
+		defer server.Close()
+
+		err := th.App.deliverOutgoingWebhook(th.Context, hook, server.URL, []byte{}, "application/json", th.BasicPost, th.BasicChannel)
+
+		var statusErr *outgoingWebhookStatusError
+		require.ErrorAs(t, err, &statusErr)
+		assert.Len(t, statusErr.Body, maxOutgoingWebhookErrorBodySize)
+		assert.True(t, statusErr.Retryable())
+	})
+}
+
+func TestOutgoingWebhookStatusErrorRetryable(t *testing.T) {
+	for statusCode, retryable := range map[int]bool{
+		http.StatusBadRequest:          false,
+		http.StatusUnauthorized:        false,
+		http.StatusNotFound:            false,
+		http.StatusRequestTimeout:      true,
+		http.StatusTooManyRequests:     true,
+		http.StatusInternalServerError: true,
+		http.StatusBadGateway:          true,
+	} {
+		err := &outgoingWebhookStatusError{StatusCode: statusCode}
+		assert.Equal(t, retryable, err.Retryable(), "status %d", statusCode)
+	}
 }
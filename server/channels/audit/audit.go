@@ -5,6 +5,7 @@ package audit
 
 import (
 	"fmt"
+	"sync"
 
 	"github.com/mattermost/mattermost/server/public/shared/mlog"
 )
@@ -12,6 +13,9 @@ import (
 type Audit struct {
 	logger *mlog.Logger
 
+	sinkMut sync.RWMutex
+	sink    *sinkWorker
+
 	// OnQueueFull is called on an attempt to add an audit record to a full queue.
 	// Return true to drop record, or false to block until there is room in queue.
 	OnQueueFull func(qname string, maxQueueSize int) bool
@@ -41,6 +45,33 @@ func (a *Audit) LogRecord(level mlog.Level, rec Record) {
 	}
 
 	a.logger.Log(level, "", flds...)
+
+	a.sinkMut.RLock()
+	sink := a.sink
+	a.sinkMut.RUnlock()
+
+	if sink != nil {
+		sink.enqueue(rec, a.onSinkQueueFull)
+	}
+}
+
+// SetSink sets a sink that audit records are written to in addition to the log targets,
+// replacing any previous one, or removes it if sink is nil. The records queued for the
+// previous sink are written to it before SetSink returns.
+func (a *Audit) SetSink(sink Sink, maxQueueSize int) {
+	var worker *sinkWorker
+	if sink != nil {
+		worker = newSinkWorker(sink, maxQueueSize, a.onLoggerError)
+	}
+
+	a.sinkMut.Lock()
+	previous := a.sink
+	a.sink = worker
+	a.sinkMut.Unlock()
+
+	if previous != nil {
+		previous.stop()
+	}
 }
 
 // Configure sets zero or more target to output audit logs to.
@@ -59,6 +90,8 @@ func (a *Audit) Flush() error {
 
 // Shutdown cleanly stops the audit engine after making best efforts to flush all targets.
 func (a *Audit) Shutdown() error {
+	a.SetSink(nil, 0)
+
 	err := a.logger.Shutdown()
 	if err != nil {
 		a.onLoggerError(err)
@@ -82,6 +115,14 @@ func (a *Audit) onTargetQueueFull(target mlog.Target, rec *mlog.LogRec, maxQueue
 	return true
 }
 
+func (a *Audit) onSinkQueueFull(maxQueueSize int) bool {
+	if a.OnQueueFull != nil {
+		return a.OnQueueFull("sink", maxQueueSize)
+	}
+	mlog.Error("Audit sink queue full, dropping record.", mlog.Int("queueSize", maxQueueSize))
+	return true
+}
+
 func (a *Audit) onLoggerError(err error) {
 	if a.OnError != nil {
 		a.OnError(err)
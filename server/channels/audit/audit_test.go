@@ -22,12 +22,12 @@ func TestAudit_LogRecord(t *testing.T) {
 	userId := model.NewId()
 	testCases := []struct {
 		description  string
-		auditLogFunc func(audit Audit)
+		auditLogFunc func(audit *Audit)
 		expectedLogs []string
 	}{
 		{
 			"empty record",
-			func(audit Audit) {
+			func(audit *Audit) {
 				rec := Record{}
 				audit.LogRecord(mlog.LvlAuditAPI, rec)
 			},
@@ -37,7 +37,7 @@ func TestAudit_LogRecord(t *testing.T) {
 		},
 		{
 			"update user record, no error",
-			func(audit Audit) {
+			func(audit *Audit) {
 				usr := &model.User{}
 				usr.Id = userId
 				usr.Username = "TestABC"
@@ -84,7 +84,7 @@ func TestAudit_LogRecord(t *testing.T) {
 			require.NoError(t, err)
 			mlog.InitGlobalLogger(logger)
 
-			audit := Audit{}
+			audit := &Audit{}
 			audit.logger = logger
 			testCase.auditLogFunc(audit)
 
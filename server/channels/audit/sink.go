@@ -0,0 +1,75 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package audit
+
+import (
+	"sync"
+)
+
+// Sink is a destination for audit records besides the log targets, such as a database
+// where they can be searched.
+type Sink interface {
+	WriteRecord(rec Record) error
+}
+
+// sinkWorker writes records to a sink in the background, so that slow sinks don't hold
+// up the code emitting the records.
+type sinkWorker struct {
+	sink  Sink
+	queue chan Record
+	done  chan struct{}
+
+	mut    sync.RWMutex
+	closed bool
+}
+
+func newSinkWorker(sink Sink, maxQueueSize int, onError func(err error)) *sinkWorker {
+	w := &sinkWorker{
+		sink:  sink,
+		queue: make(chan Record, maxQueueSize),
+		done:  make(chan struct{}),
+	}
+
+	go func() {
+		defer close(w.done)
+		for rec := range w.queue {
+			if err := w.sink.WriteRecord(rec); err != nil {
+				onError(err)
+			}
+		}
+	}()
+
+	return w
+}
+
+// enqueue queues a record for the sink. If the queue is full, the record is dropped
+// unless onQueueFull returns false, in which case enqueue blocks until there is room.
+func (w *sinkWorker) enqueue(rec Record, onQueueFull func(maxQueueSize int) bool) {
+	w.mut.RLock()
+	defer w.mut.RUnlock()
+
+	if w.closed {
+		return
+	}
+
+	select {
+	case w.queue <- rec:
+	default:
+		if !onQueueFull(cap(w.queue)) {
+			w.queue <- rec
+		}
+	}
+}
+
+// stop writes the queued records to the sink and stops the worker.
+func (w *sinkWorker) stop() {
+	w.mut.Lock()
+	if !w.closed {
+		w.closed = true
+		close(w.queue)
+	}
+	w.mut.Unlock()
+
+	<-w.done
+}
@@ -0,0 +1,113 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package audit
+
+import (
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+)
+
+type testSink struct {
+	mut     sync.Mutex
+	records []Record
+	err     error
+	block   chan struct{}
+}
+
+func (s *testSink) WriteRecord(rec Record) error {
+	if s.block != nil {
+		<-s.block
+	}
+
+	s.mut.Lock()
+	defer s.mut.Unlock()
+	s.records = append(s.records, rec)
+	return s.err
+}
+
+func TestAudit_Sink(t *testing.T) {
+	t.Run("records are written to the sink", func(t *testing.T) {
+		audit := &Audit{}
+		audit.Init(DefMaxQueueSize)
+
+		sink := &testSink{}
+		audit.SetSink(sink, 10)
+
+		audit.LogRecord(mlog.LvlAuditAPI, Record{EventName: "first", Status: Success})
+		audit.LogRecord(mlog.LvlAuditAPI, Record{EventName: "second", Status: Fail})
+		require.NoError(t, audit.Shutdown())
+
+		require.Len(t, sink.records, 2)
+		assert.Equal(t, "first", sink.records[0].EventName)
+		assert.Equal(t, "second", sink.records[1].EventName)
+
+		// Records logged after shutdown are ignored.
+		audit.LogRecord(mlog.LvlAuditAPI, Record{EventName: "third"})
+		assert.Len(t, sink.records, 2)
+	})
+
+	t.Run("sink errors are reported", func(t *testing.T) {
+		var reported error
+		audit := &Audit{OnError: func(err error) { reported = err }}
+		audit.Init(DefMaxQueueSize)
+
+		sink := &testSink{err: errors.New("write failed")}
+		audit.SetSink(sink, 10)
+
+		audit.LogRecord(mlog.LvlAuditAPI, Record{EventName: "first"})
+		require.NoError(t, audit.Shutdown())
+
+		require.EqualError(t, reported, "write failed")
+	})
+
+	t.Run("records are dropped when the queue is full", func(t *testing.T) {
+		var fullQueue string
+		audit := &Audit{OnQueueFull: func(qname string, maxQueueSize int) bool {
+			fullQueue = qname
+			return true
+		}}
+		audit.Init(DefMaxQueueSize)
+
+		sink := &testSink{block: make(chan struct{})}
+		audit.SetSink(sink, 1)
+
+		// The first record may be held by the worker, the second fills the queue.
+		for i := 0; i < 3; i++ {
+			audit.LogRecord(mlog.LvlAuditAPI, Record{EventName: "record"})
+		}
+		close(sink.block)
+		require.NoError(t, audit.Shutdown())
+
+		assert.Equal(t, "sink", fullQueue)
+		assert.Less(t, len(sink.records), 3)
+	})
+	t.Run("sink can be replaced and removed", func(t *testing.T) {
+		audit := &Audit{}
+		audit.Init(DefMaxQueueSize)
+
+		first := &testSink{}
+		audit.SetSink(first, 10)
+		audit.LogRecord(mlog.LvlAuditAPI, Record{EventName: "first"})
+
+		second := &testSink{}
+		audit.SetSink(second, 10)
+		audit.LogRecord(mlog.LvlAuditAPI, Record{EventName: "second"})
+
+		audit.SetSink(nil, 0)
+		audit.LogRecord(mlog.LvlAuditAPI, Record{EventName: "third"})
+		require.NoError(t, audit.Shutdown())
+
+		// The records queued for a sink are written before it's replaced.
+		require.Len(t, first.records, 1)
+		assert.Equal(t, "first", first.records[0].EventName)
+		require.Len(t, second.records, 1)
+		assert.Equal(t, "second", second.records[0].EventName)
+	})
+}
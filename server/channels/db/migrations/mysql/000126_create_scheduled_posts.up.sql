@@ -0,0 +1,18 @@
+CREATE TABLE IF NOT EXISTS ScheduledPosts (
+    Id varchar(26) NOT NULL,
+    CreateAt bigint(20) DEFAULT NULL,
+    UpdateAt bigint(20) DEFAULT NULL,
+    UserId varchar(26) NOT NULL,
+    ChannelId varchar(26) NOT NULL,
+    RootId varchar(26) DEFAULT '',
+    Message text,
+    Props text,
+    FileIds text,
+    Priority text,
+    ScheduledAt bigint(20) NOT NULL,
+    ProcessedAt bigint(20) DEFAULT 0,
+    ErrorCode varchar(200) DEFAULT '',
+    PRIMARY KEY (Id),
+    KEY idx_scheduledposts_userid_channel_id_scheduled_at (UserId, ChannelId, ScheduledAt),
+    KEY idx_scheduledposts_scheduledat_id (ScheduledAt, Id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
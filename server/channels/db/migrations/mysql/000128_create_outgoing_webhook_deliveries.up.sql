@@ -0,0 +1,20 @@
+CREATE TABLE IF NOT EXISTS OutgoingWebhookDeliveries (
+    Id varchar(26) NOT NULL,
+    CreateAt bigint(20) DEFAULT NULL,
+    UpdateAt bigint(20) DEFAULT NULL,
+    HookId varchar(26) NOT NULL,
+    TeamId varchar(26) NOT NULL,
+    ChannelId varchar(26) NOT NULL,
+    PostId varchar(26) NOT NULL,
+    CallbackURL varchar(1024) NOT NULL,
+    ContentType varchar(128) DEFAULT '',
+    Payload mediumtext,
+    Status varchar(32) NOT NULL,
+    Attempts int(11) DEFAULT 0,
+    LastError varchar(1024) DEFAULT '',
+    LastAttemptAt bigint(20) DEFAULT 0,
+    NextAttemptAt bigint(20) DEFAULT 0,
+    PRIMARY KEY (Id),
+    KEY idx_outgoingwebhookdeliveries_status_nextattemptat_id (Status, NextAttemptAt, Id),
+    KEY idx_outgoingwebhookdeliveries_hookid_status (HookId, Status)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
@@ -0,0 +1,7 @@
+CREATE TABLE IF NOT EXISTS PollVotes (
+    PostId varchar(26) NOT NULL,
+    UserId varchar(26) NOT NULL,
+    OptionId varchar(26) NOT NULL,
+    CreateAt bigint(20) DEFAULT NULL,
+    PRIMARY KEY (PostId, UserId, OptionId)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
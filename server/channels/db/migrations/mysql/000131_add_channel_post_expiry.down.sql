@@ -0,0 +1,14 @@
+SET @preparedStatement = (SELECT IF(
+    (
+        SELECT COUNT(*) FROM INFORMATION_SCHEMA.COLUMNS
+        WHERE table_name = 'Channels'
+        AND table_schema = DATABASE()
+        AND column_name = 'PostExpiryMinutes'
+    ) > 0,
+    'ALTER TABLE Channels DROP COLUMN PostExpiryMinutes;',
+    'SELECT 1'
+));
+
+PREPARE alterIfExists FROM @preparedStatement;
+EXECUTE alterIfExists;
+DEALLOCATE PREPARE alterIfExists;
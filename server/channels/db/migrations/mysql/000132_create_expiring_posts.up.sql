@@ -0,0 +1,7 @@
+CREATE TABLE IF NOT EXISTS ExpiringPosts (
+    PostId varchar(26) NOT NULL,
+    ChannelId varchar(26) NOT NULL,
+    ExpireAt bigint(20) NOT NULL,
+    PRIMARY KEY (PostId),
+    KEY idx_expiringposts_expireat (ExpireAt)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS AuditRecordObjects;
+DROP TABLE IF EXISTS AuditRecords;
@@ -0,0 +1,27 @@
+CREATE TABLE IF NOT EXISTS AuditRecords (
+    Id varchar(26) NOT NULL,
+    CreateAt bigint(20) NOT NULL,
+    EventName varchar(256) NOT NULL,
+    Status varchar(32) NOT NULL,
+    UserId varchar(64) NOT NULL,
+    SessionId varchar(26) NOT NULL,
+    Client varchar(512) NOT NULL,
+    IpAddress varchar(64) NOT NULL,
+    ObjectType varchar(64) NOT NULL,
+    ObjectIds text,
+    Data mediumtext,
+    PRIMARY KEY (Id),
+    KEY idx_auditrecords_createat (CreateAt),
+    KEY idx_auditrecords_eventname_createat (EventName, CreateAt),
+    KEY idx_auditrecords_userid_createat (UserId, CreateAt),
+    KEY idx_auditrecords_status_createat (Status, CreateAt)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE TABLE IF NOT EXISTS AuditRecordObjects (
+    ObjectId varchar(26) NOT NULL,
+    RecordId varchar(26) NOT NULL,
+    CreateAt bigint(20) NOT NULL,
+    PRIMARY KEY (ObjectId, RecordId),
+    KEY idx_auditrecordobjects_objectid_createat (ObjectId, CreateAt),
+    KEY idx_auditrecordobjects_recordid (RecordId)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
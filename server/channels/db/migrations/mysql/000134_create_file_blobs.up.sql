@@ -0,0 +1,25 @@
+CREATE TABLE IF NOT EXISTS FileBlobs (
+    Hash varchar(64) NOT NULL,
+    Path varchar(512) NOT NULL,
+    Size bigint(20) NOT NULL,
+    RefCount bigint(20) NOT NULL,
+    CreateAt bigint(20) NOT NULL,
+    UpdateAt bigint(20) NOT NULL,
+    PRIMARY KEY (Hash),
+    KEY idx_fileblobs_refcount_updateat (RefCount, UpdateAt)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+SET @preparedStatement = (SELECT IF(
+    NOT EXISTS(
+        SELECT 1 FROM INFORMATION_SCHEMA.COLUMNS
+        WHERE table_name = 'FileInfo'
+        AND table_schema = DATABASE()
+        AND column_name = 'ContentHash'
+    ),
+    'ALTER TABLE FileInfo ADD COLUMN ContentHash varchar(64) NOT NULL DEFAULT \'\';',
+    'SELECT 1;'
+));
+
+PREPARE addColumnIfNotExists FROM @preparedStatement;
+EXECUTE addColumnIfNotExists;
+DEALLOCATE PREPARE addColumnIfNotExists;
@@ -0,0 +1,20 @@
+CREATE TABLE IF NOT EXISTS SavedSearches (
+    Id varchar(26) NOT NULL,
+    CreateAt bigint(20) DEFAULT NULL,
+    UpdateAt bigint(20) DEFAULT NULL,
+    UserId varchar(26) NOT NULL,
+    TeamId varchar(26) NOT NULL,
+    Name varchar(64) NOT NULL,
+    Terms text,
+    IsOrSearch tinyint(1) DEFAULT 0,
+    TimeZoneOffset int DEFAULT 0,
+    Subscribed tinyint(1) DEFAULT 0,
+    AlertType varchar(32) DEFAULT '',
+    InChannelIds text,
+    ExcludedChannelIds text,
+    FromUserIds text,
+    ExcludedUserIds text,
+    PRIMARY KEY (Id),
+    KEY idx_savedsearches_userid_teamid (UserId, TeamId),
+    KEY idx_savedsearches_subscribed_userid (Subscribed, UserId)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
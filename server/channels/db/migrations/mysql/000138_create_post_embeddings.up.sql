@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS PostEmbeddings (
+    PostId varchar(26) NOT NULL,
+    ChannelId varchar(26) NOT NULL,
+    UserId varchar(26) NOT NULL,
+    CreateAt bigint(20) NOT NULL,
+    Provider varchar(128) NOT NULL,
+    Embedding mediumblob NOT NULL,
+    UpdateAt bigint(20) NOT NULL,
+    PRIMARY KEY (PostId),
+    KEY idx_postembeddings_channelid_provider_createat (ChannelId, Provider, CreateAt),
+    KEY idx_postembeddings_userid (UserId),
+    KEY idx_postembeddings_createat (CreateAt)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
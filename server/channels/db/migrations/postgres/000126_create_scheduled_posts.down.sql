@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_scheduledposts_userid_channel_id_scheduled_at;
+DROP INDEX IF EXISTS idx_scheduledposts_scheduledat_id;
+
+DROP TABLE IF EXISTS scheduledposts;
@@ -0,0 +1,3 @@
+ALTER TABLE outgoingwebhooks ADD COLUMN IF NOT EXISTS signingsecret character varying(64) DEFAULT '';
+
+ALTER TABLE commands ADD COLUMN IF NOT EXISTS signingsecret character varying(64) DEFAULT '';
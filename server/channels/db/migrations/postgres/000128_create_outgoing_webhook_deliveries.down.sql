@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_outgoingwebhookdeliveries_status_nextattemptat_id;
+DROP INDEX IF EXISTS idx_outgoingwebhookdeliveries_hookid_status;
+
+DROP TABLE IF EXISTS outgoingwebhookdeliveries;
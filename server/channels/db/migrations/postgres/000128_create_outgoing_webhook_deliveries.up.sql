@@ -0,0 +1,20 @@
+CREATE TABLE IF NOT EXISTS outgoingwebhookdeliveries (
+    id VARCHAR(26) PRIMARY KEY,
+    createat bigint,
+    updateat bigint,
+    hookid VARCHAR(26) NOT NULL,
+    teamid VARCHAR(26) NOT NULL,
+    channelid VARCHAR(26) NOT NULL,
+    postid VARCHAR(26) NOT NULL,
+    callbackurl VARCHAR(1024) NOT NULL,
+    contenttype VARCHAR(128) DEFAULT '',
+    payload text,
+    status VARCHAR(32) NOT NULL,
+    attempts integer DEFAULT 0,
+    lasterror VARCHAR(1024) DEFAULT '',
+    lastattemptat bigint DEFAULT 0,
+    nextattemptat bigint DEFAULT 0
+);
+
+CREATE INDEX IF NOT EXISTS idx_outgoingwebhookdeliveries_status_nextattemptat_id ON outgoingwebhookdeliveries (status, nextattemptat, id);
+CREATE INDEX IF NOT EXISTS idx_outgoingwebhookdeliveries_hookid_status ON outgoingwebhookdeliveries (hookid, status);
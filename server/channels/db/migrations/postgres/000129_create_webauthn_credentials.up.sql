@@ -0,0 +1,14 @@
+CREATE TABLE IF NOT EXISTS webauthncredentials (
+    id VARCHAR(26) PRIMARY KEY,
+    userid VARCHAR(26) NOT NULL,
+    name VARCHAR(64) NOT NULL,
+    credentialid VARCHAR(512) NOT NULL,
+    publickey text NOT NULL,
+    signcount bigint DEFAULT 0,
+    aaguid VARCHAR(64) DEFAULT '',
+    createat bigint,
+    lastusedat bigint DEFAULT 0
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_webauthncredentials_credentialid ON webauthncredentials (credentialid);
+CREATE INDEX IF NOT EXISTS idx_webauthncredentials_userid ON webauthncredentials (userid);
@@ -0,0 +1,7 @@
+CREATE TABLE IF NOT EXISTS pollvotes (
+    postid VARCHAR(26) NOT NULL,
+    userid VARCHAR(26) NOT NULL,
+    optionid VARCHAR(26) NOT NULL,
+    createat bigint,
+    PRIMARY KEY (postid, userid, optionid)
+);
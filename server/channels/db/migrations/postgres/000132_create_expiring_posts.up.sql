@@ -0,0 +1,7 @@
+CREATE TABLE IF NOT EXISTS expiringposts (
+    postid VARCHAR(26) PRIMARY KEY,
+    channelid VARCHAR(26) NOT NULL,
+    expireat bigint NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_expiringposts_expireat ON expiringposts (expireat);
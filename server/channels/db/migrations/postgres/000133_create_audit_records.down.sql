@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS auditrecordobjects;
+DROP TABLE IF EXISTS auditrecords;
@@ -0,0 +1,28 @@
+CREATE TABLE IF NOT EXISTS auditrecords (
+    id VARCHAR(26) PRIMARY KEY,
+    createat bigint NOT NULL,
+    eventname VARCHAR(256) NOT NULL,
+    status VARCHAR(32) NOT NULL,
+    userid VARCHAR(64) NOT NULL,
+    sessionid VARCHAR(26) NOT NULL,
+    client VARCHAR(512) NOT NULL,
+    ipaddress VARCHAR(64) NOT NULL,
+    objecttype VARCHAR(64) NOT NULL,
+    objectids text,
+    data text
+);
+
+CREATE INDEX IF NOT EXISTS idx_auditrecords_createat ON auditrecords (createat);
+CREATE INDEX IF NOT EXISTS idx_auditrecords_eventname_createat ON auditrecords (eventname, createat);
+CREATE INDEX IF NOT EXISTS idx_auditrecords_userid_createat ON auditrecords (userid, createat);
+CREATE INDEX IF NOT EXISTS idx_auditrecords_status_createat ON auditrecords (status, createat);
+
+CREATE TABLE IF NOT EXISTS auditrecordobjects (
+    objectid VARCHAR(26) NOT NULL,
+    recordid VARCHAR(26) NOT NULL,
+    createat bigint NOT NULL,
+    PRIMARY KEY (objectid, recordid)
+);
+
+CREATE INDEX IF NOT EXISTS idx_auditrecordobjects_objectid_createat ON auditrecordobjects (objectid, createat);
+CREATE INDEX IF NOT EXISTS idx_auditrecordobjects_recordid ON auditrecordobjects (recordid);
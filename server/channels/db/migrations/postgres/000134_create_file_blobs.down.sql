@@ -0,0 +1,3 @@
+ALTER TABLE fileinfo DROP COLUMN IF EXISTS contenthash;
+
+DROP TABLE IF EXISTS fileblobs;
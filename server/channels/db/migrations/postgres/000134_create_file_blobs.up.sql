@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS fileblobs (
+    hash VARCHAR(64) PRIMARY KEY,
+    path VARCHAR(512) NOT NULL,
+    size bigint NOT NULL,
+    refcount bigint NOT NULL,
+    createat bigint NOT NULL,
+    updateat bigint NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_fileblobs_refcount_updateat ON fileblobs (refcount, updateat);
+
+ALTER TABLE fileinfo ADD COLUMN IF NOT EXISTS contenthash VARCHAR(64) NOT NULL DEFAULT '';
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_fileinfo_scanstatus;
+
+ALTER TABLE fileinfo DROP COLUMN IF EXISTS scanresult;
+ALTER TABLE fileinfo DROP COLUMN IF EXISTS scanstatus;
@@ -0,0 +1,4 @@
+ALTER TABLE fileinfo ADD COLUMN IF NOT EXISTS scanstatus VARCHAR(16) NOT NULL DEFAULT '';
+ALTER TABLE fileinfo ADD COLUMN IF NOT EXISTS scanresult VARCHAR(256) NOT NULL DEFAULT '';
+
+CREATE INDEX IF NOT EXISTS idx_fileinfo_scanstatus ON fileinfo (scanstatus);
@@ -0,0 +1,4 @@
+ALTER TABLE fileinfo DROP COLUMN IF EXISTS renditionpath;
+ALTER TABLE fileinfo DROP COLUMN IF EXISTS audiocodec;
+ALTER TABLE fileinfo DROP COLUMN IF EXISTS videocodec;
+ALTER TABLE fileinfo DROP COLUMN IF EXISTS duration;
@@ -0,0 +1,4 @@
+ALTER TABLE fileinfo ADD COLUMN IF NOT EXISTS duration bigint NOT NULL DEFAULT 0;
+ALTER TABLE fileinfo ADD COLUMN IF NOT EXISTS videocodec VARCHAR(64) NOT NULL DEFAULT '';
+ALTER TABLE fileinfo ADD COLUMN IF NOT EXISTS audiocodec VARCHAR(64) NOT NULL DEFAULT '';
+ALTER TABLE fileinfo ADD COLUMN IF NOT EXISTS renditionpath VARCHAR(512) NOT NULL DEFAULT '';
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_savedsearches_userid_teamid;
+DROP INDEX IF EXISTS idx_savedsearches_subscribed_userid;
+
+DROP TABLE IF EXISTS savedsearches;
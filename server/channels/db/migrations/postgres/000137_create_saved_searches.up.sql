@@ -0,0 +1,20 @@
+CREATE TABLE IF NOT EXISTS savedsearches (
+    id VARCHAR(26) PRIMARY KEY,
+    createat bigint,
+    updateat bigint,
+    userid VARCHAR(26) NOT NULL,
+    teamid VARCHAR(26) NOT NULL,
+    name VARCHAR(64) NOT NULL,
+    terms VARCHAR(1024),
+    isorsearch boolean DEFAULT false,
+    timezoneoffset integer DEFAULT 0,
+    subscribed boolean DEFAULT false,
+    alerttype VARCHAR(32) DEFAULT '',
+    inchannelids text,
+    excludedchannelids text,
+    fromuserids text,
+    excludeduserids text
+);
+
+CREATE INDEX IF NOT EXISTS idx_savedsearches_userid_teamid ON savedsearches (userid, teamid);
+CREATE INDEX IF NOT EXISTS idx_savedsearches_subscribed_userid ON savedsearches (subscribed, userid);
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_postembeddings_channelid_provider;
+DROP INDEX IF EXISTS idx_postembeddings_userid;
+DROP INDEX IF EXISTS idx_postembeddings_createat;
+
+DROP TABLE IF EXISTS postembeddings;
@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS postembeddings (
+    postid VARCHAR(26) PRIMARY KEY,
+    channelid VARCHAR(26) NOT NULL,
+    userid VARCHAR(26) NOT NULL,
+    createat bigint NOT NULL,
+    provider VARCHAR(128) NOT NULL,
+    embedding bytea NOT NULL,
+    updateat bigint NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_postembeddings_channelid_provider_createat ON postembeddings (channelid, provider, createat);
+CREATE INDEX IF NOT EXISTS idx_postembeddings_userid ON postembeddings (userid);
+CREATE INDEX IF NOT EXISTS idx_postembeddings_createat ON postembeddings (createat);
@@ -0,0 +1,47 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package expired_audit_records
+
+import (
+	"time"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+	"github.com/mattermost/mattermost/server/v8/channels/jobs"
+)
+
+const (
+	jobName   = "DeleteExpiredAuditRecords"
+	batchSize = 1000
+)
+
+// MakeWorker creates a worker deleting the audit records kept in the database for longer
+// than ExperimentalAuditSettings.DatabaseMaxAgeDays. Records are deleted whether or not the
+// database sink is still enabled, so that turning it off doesn't keep them forever.
+func MakeWorker(jobServer *jobs.JobServer) *jobs.SimpleWorker {
+	isEnabled := func(cfg *model.Config) bool {
+		return *cfg.ExperimentalAuditSettings.DatabaseMaxAgeDays > 0
+	}
+	execute := func(logger mlog.LoggerIFace, job *model.Job) error {
+		defer jobServer.HandleJobPanic(logger, job)
+
+		maxAge := time.Duration(*jobServer.Config().ExperimentalAuditSettings.DatabaseMaxAgeDays) * 24 * time.Hour
+		endTime := model.GetMillisForTime(time.Now().Add(-maxAge))
+
+		var total int64
+		for {
+			deleted, err := jobServer.Store.AuditRecord().PermanentDeleteBatch(endTime, batchSize)
+			if err != nil {
+				return err
+			}
+			total += deleted
+
+			if deleted < batchSize {
+				logger.Info("Deleted expired audit records", mlog.Int("count", total))
+				return nil
+			}
+		}
+	}
+	return jobs.NewSimpleWorker(jobName, jobServer, execute, isEnabled)
+}
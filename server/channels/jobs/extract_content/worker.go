@@ -11,6 +11,7 @@ import (
 	"github.com/mattermost/mattermost/server/public/shared/request"
 	"github.com/mattermost/mattermost/server/v8/channels/jobs"
 	"github.com/mattermost/mattermost/server/v8/channels/store"
+	"github.com/mattermost/mattermost/server/v8/platform/services/docextractor"
 )
 
 var ignoredFiles = map[string]bool{
@@ -64,8 +65,10 @@ func MakeWorker(jobServer *jobs.JobServer, app AppIface, store store.Store) *job
 			if len(fileInfos) == 0 {
 				break
 			}
+			// The text of images is recognized by OCR, if enabled.
+			ocrEnabled := *jobServer.Config().FileSettings.EnableOCR
 			for _, fileInfo := range fileInfos {
-				if !ignoredFiles[fileInfo.Extension] {
+				if !ignoredFiles[fileInfo.Extension] || (ocrEnabled && docextractor.IsOCRSupported(fileInfo.Name)) {
 					logger.Debug("Extracting file", mlog.String("filename", fileInfo.Name), mlog.String("filepath", fileInfo.Path))
 
 					err = app.ExtractContentFromFileInfo(request.EmptyContext(logger), fileInfo)
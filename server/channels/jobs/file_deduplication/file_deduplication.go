@@ -0,0 +1,284 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package file_deduplication
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+	"github.com/mattermost/mattermost/server/public/shared/request"
+	"github.com/mattermost/mattermost/server/v8/channels/jobs"
+	"github.com/mattermost/mattermost/server/v8/channels/store"
+)
+
+const (
+	timeBetweenBatches = 1 * time.Second
+)
+
+type AppIface interface {
+	DeduplicateFileInfo(rctx request.CTX, info *model.FileInfo) (int64, *model.AppError)
+	PurgeUnreferencedFileBlobs(rctx request.CTX) (int64, *model.AppError)
+}
+
+// FileDeduplicationWorker moves the contents of the files stored before deduplication was
+// enabled to their content-addressed blobs, removing the duplicate copies, and purges the
+// blobs that are no longer referenced. The bytes saved are reported in the job data.
+type FileDeduplicationWorker struct {
+	name      string
+	jobServer *jobs.JobServer
+	logger    mlog.LoggerIFace
+	store     store.Store
+	app       AppIface
+
+	stop    chan struct{}
+	stopped chan bool
+	jobs    chan model.Job
+}
+
+func MakeWorker(jobServer *jobs.JobServer, store store.Store, app AppIface) *FileDeduplicationWorker {
+	const workerName = "FileDeduplication"
+	worker := &FileDeduplicationWorker{
+		name:      workerName,
+		jobServer: jobServer,
+		logger:    jobServer.Logger().With(mlog.String("worker_name", workerName)),
+		store:     store,
+		app:       app,
+		stop:      make(chan struct{}),
+		stopped:   make(chan bool, 1),
+		jobs:      make(chan model.Job),
+	}
+	return worker
+}
+
+func (worker *FileDeduplicationWorker) Run() {
+	worker.logger.Debug("Worker started")
+	// We have to re-assign the stop channel again, because
+	// it might happen that the job was restarted due to a config change.
+	worker.stop = make(chan struct{}, 1)
+
+	defer func() {
+		worker.logger.Debug("Worker finished")
+		worker.stopped <- true
+	}()
+
+	for {
+		select {
+		case <-worker.stop:
+			worker.logger.Debug("Worker received stop signal")
+			return
+		case job := <-worker.jobs:
+			worker.DoJob(&job)
+		}
+	}
+}
+
+func (worker *FileDeduplicationWorker) Stop() {
+	worker.logger.Debug("Worker stopping")
+	close(worker.stop)
+	<-worker.stopped
+}
+
+func (worker *FileDeduplicationWorker) JobChannel() chan<- model.Job {
+	return worker.jobs
+}
+
+func (worker *FileDeduplicationWorker) IsEnabled(cfg *model.Config) bool {
+	return *cfg.FileSettings.EnableDeduplication
+}
+
+func (worker *FileDeduplicationWorker) getJobMetadata(job *model.Job, key string) (int64, *model.AppError) {
+	countStr := job.Data[key]
+	var count int64
+	var err error
+	if countStr != "" {
+		count, err = strconv.ParseInt(countStr, 10, 64)
+		if err != nil {
+			return 0, model.NewAppError("getJobMetadata", model.NoTranslation, nil, "", http.StatusInternalServerError).Wrap(err)
+		}
+	}
+	return count, nil
+}
+
+func (worker *FileDeduplicationWorker) DoJob(job *model.Job) {
+	logger := worker.logger.With(jobs.JobLoggerFields(job)...)
+	logger.Debug("Worker: Received a new candidate job.")
+	defer worker.jobServer.HandleJobPanic(logger, job)
+
+	if claimed, err := worker.jobServer.ClaimJob(job); err != nil {
+		logger.Warn("FileDeduplicationWorker experienced an error while trying to claim job", mlog.Err(err))
+		return
+	} else if !claimed {
+		return
+	}
+
+	c := request.EmptyContext(worker.logger)
+
+	var appErr *model.AppError
+	// We get the job again because ClaimJob changes the job status.
+	job, appErr = worker.jobServer.GetJob(c, job.Id)
+	if appErr != nil {
+		logger.Error("FileDeduplicationWorker: job execution error", mlog.Err(appErr))
+		worker.setJobError(logger, job, appErr)
+		return
+	}
+
+	// Check if there is metadata for that job.
+	// If there isn't, it will be empty by default, which is the right value.
+	startFileID := job.Data["start_file_id"]
+
+	metadata := map[string]int64{}
+	for _, key := range []string{"start_create_at", "done_file_count", "deduplicated_file_count", "saved_bytes"} {
+		value, appErr := worker.getJobMetadata(job, key)
+		if appErr != nil {
+			logger.Error("FileDeduplicationWorker: failed to get job metadata", mlog.String("key", key), mlog.Err(appErr))
+			worker.setJobError(logger, job, appErr)
+			return
+		}
+		metadata[key] = value
+	}
+	startTime := metadata["start_create_at"]
+
+	const pageSize = 100
+
+	for {
+		select {
+		case <-worker.stop:
+			logger.Info("Worker: File deduplication has been canceled via Worker Stop. Setting the job back to pending.")
+			if err := worker.jobServer.SetJobPending(job); err != nil {
+				worker.logger.Error("Worker: Failed to mark job as pending", mlog.Err(err))
+			}
+			return
+		case <-time.After(timeBetweenBatches):
+			var files []*model.FileForIndexing
+			tries := 0
+			for files == nil {
+				var err error
+				// Take batches of `pageSize`
+				files, err = worker.store.FileInfo().GetFilesBatchForIndexing(startTime, startFileID, true, pageSize)
+				if err != nil {
+					if tries > 3 {
+						logger.Error("Worker: Failed to get files after multiple retries. Exiting")
+						worker.setJobError(logger, job, model.NewAppError("DoJob", model.NoTranslation, nil, "", http.StatusInternalServerError).Wrap(err))
+						return
+					}
+					logger.Warn("Failed to get file info for file deduplication. Retrying .. ", mlog.Err(err))
+
+					// Wait a bit before trying again.
+					time.Sleep(15 * time.Second)
+				}
+
+				tries++
+			}
+
+			if len(files) == 0 {
+				worker.complete(c, logger, job)
+				return
+			}
+
+			// Iterate through the rows in each page.
+			for _, f := range files {
+				if f.ContentHash != "" {
+					continue
+				}
+
+				logger.Debug("Processing file ID", mlog.String("id", f.Id))
+				// We do not fail the job if a single file failed to be deduplicated.
+				saved, appErr := worker.app.DeduplicateFileInfo(c, &f.FileInfo)
+				if appErr != nil {
+					logger.Warn("Failed to deduplicate file", mlog.String("path", f.Path), mlog.String("id", f.Id), mlog.Err(appErr))
+					continue
+				}
+				if saved > 0 {
+					metadata["deduplicated_file_count"]++
+					metadata["saved_bytes"] += saved
+				}
+			}
+
+			// Work on each batch and save the batch starting ID in metadata
+			lastFile := files[len(files)-1]
+			startFileID = lastFile.Id
+			startTime = lastFile.CreateAt
+			metadata["start_create_at"] = startTime
+			metadata["done_file_count"] += int64(len(files))
+
+			if job.Data == nil {
+				job.Data = make(model.StringMap)
+			}
+			job.Data["start_file_id"] = startFileID
+			for key, value := range metadata {
+				job.Data[key] = strconv.FormatInt(value, 10)
+			}
+			if err := worker.jobServer.UpdateInProgressJobData(job); err != nil {
+				logger.Warn("Worker: Failed to save the progress of the job", mlog.Err(err))
+			}
+		}
+	}
+}
+
+// complete purges the blobs left unreferenced by deleted files, such as those removed by
+// data retention, and reports how many bytes deduplication saves overall.
+func (worker *FileDeduplicationWorker) complete(c request.CTX, logger mlog.LoggerIFace, job *model.Job) {
+	if job.Data == nil {
+		job.Data = make(model.StringMap)
+	}
+
+	purged, appErr := worker.app.PurgeUnreferencedFileBlobs(c)
+	if appErr != nil {
+		logger.Warn("Worker: Failed to purge unreferenced file blobs", mlog.Err(appErr))
+	}
+	job.Data["purged_bytes"] = strconv.FormatInt(purged, 10)
+
+	if totalSaved, err := worker.store.FileBlob().GetSavedBytes(); err != nil {
+		logger.Warn("Worker: Failed to get the bytes saved by file deduplication", mlog.Err(err))
+	} else {
+		job.Data["total_saved_bytes"] = strconv.FormatInt(totalSaved, 10)
+	}
+
+	if err := worker.jobServer.UpdateInProgressJobData(job); err != nil {
+		logger.Warn("Worker: Failed to save the progress of the job", mlog.Err(err))
+	}
+
+	logger.Info("FileDeduplicationWorker: Job is complete",
+		mlog.String("saved_bytes", job.Data["saved_bytes"]),
+		mlog.String("purged_bytes", job.Data["purged_bytes"]),
+		mlog.String("total_saved_bytes", job.Data["total_saved_bytes"]),
+	)
+	worker.setJobSuccess(logger, job)
+	worker.markAsComplete(logger, job)
+}
+
+func (worker *FileDeduplicationWorker) markAsComplete(logger mlog.LoggerIFace, job *model.Job) {
+	system := model.System{
+		Name:  model.MigrationKeyFileDeduplication,
+		Value: "true",
+	}
+
+	// Note that if this fails, then the job would have still succeeded.
+	// So it will run again next time, but it will just fall through
+	// everything because all files would have been deduplicated already.
+	if err := worker.jobServer.Store.System().SaveOrUpdate(&system); err != nil {
+		logger.Error("Worker: Failed to mark file deduplication as completed in the systems table.", mlog.Err(err))
+	}
+}
+
+func (worker *FileDeduplicationWorker) setJobSuccess(logger mlog.LoggerIFace, job *model.Job) {
+	if err := worker.jobServer.SetJobProgress(job, 100); err != nil {
+		logger.Error("Worker: Failed to update progress for job", mlog.Err(err))
+		worker.setJobError(logger, job, err)
+	}
+
+	if err := worker.jobServer.SetJobSuccess(job); err != nil {
+		logger.Error("FileDeduplicationWorker: Failed to set success for job", mlog.Err(err))
+		worker.setJobError(logger, job, err)
+	}
+}
+
+func (worker *FileDeduplicationWorker) setJobError(logger mlog.LoggerIFace, job *model.Job, appError *model.AppError) {
+	if err := worker.jobServer.SetJobError(job, appError); err != nil {
+		logger.Error("FileDeduplicationWorker: Failed to set job error", mlog.Err(err))
+	}
+}
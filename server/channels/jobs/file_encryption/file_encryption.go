@@ -0,0 +1,301 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package file_encryption
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+	"github.com/mattermost/mattermost/server/public/shared/request"
+	"github.com/mattermost/mattermost/server/v8/channels/jobs"
+	"github.com/mattermost/mattermost/server/v8/channels/store"
+	"github.com/mattermost/mattermost/server/v8/platform/shared/filestore"
+)
+
+const (
+	timeBetweenBatches = 1 * time.Second
+)
+
+// FileEncryptionWorker encrypts the files that were stored before encryption at rest was
+// enabled, and wraps the data keys of files encrypted with an older key-encryption key
+// with the active one. Files are rewritten in place.
+//
+// Once the job succeeds, the active key is recorded as SystemFileEncryptionKeyId. Until then,
+// the unencrypted files can only be read in migration mode, and none of the older keys may be
+// removed from the configuration, since files may still need them to be read.
+type FileEncryptionWorker struct {
+	name        string
+	jobServer   *jobs.JobServer
+	logger      mlog.LoggerIFace
+	store       store.Store
+	fileBackend *filestore.EncryptedFileBackend
+
+	stop    chan struct{}
+	stopped chan bool
+	jobs    chan model.Job
+}
+
+func MakeWorker(jobServer *jobs.JobServer, store store.Store, fileBackend filestore.FileBackend) *FileEncryptionWorker {
+	// If the type cast fails, it will be nil
+	// which is checked later.
+	encryptedBackend, _ := fileBackend.(*filestore.EncryptedFileBackend)
+	const workerName = "FileEncryption"
+	worker := &FileEncryptionWorker{
+		name:        workerName,
+		jobServer:   jobServer,
+		logger:      jobServer.Logger().With(mlog.String("worker_name", workerName)),
+		store:       store,
+		fileBackend: encryptedBackend,
+		stop:        make(chan struct{}),
+		stopped:     make(chan bool, 1),
+		jobs:        make(chan model.Job),
+	}
+	return worker
+}
+
+func (worker *FileEncryptionWorker) Run() {
+	worker.logger.Debug("Worker started")
+	// We have to re-assign the stop channel again, because
+	// it might happen that the job was restarted due to a config change.
+	worker.stop = make(chan struct{}, 1)
+
+	defer func() {
+		worker.logger.Debug("Worker finished")
+		worker.stopped <- true
+	}()
+
+	for {
+		select {
+		case <-worker.stop:
+			worker.logger.Debug("Worker received stop signal")
+			return
+		case job := <-worker.jobs:
+			worker.DoJob(&job)
+		}
+	}
+}
+
+func (worker *FileEncryptionWorker) Stop() {
+	worker.logger.Debug("Worker stopping")
+	close(worker.stop)
+	<-worker.stopped
+}
+
+func (worker *FileEncryptionWorker) JobChannel() chan<- model.Job {
+	return worker.jobs
+}
+
+func (worker *FileEncryptionWorker) IsEnabled(cfg *model.Config) bool {
+	return *cfg.FileSettings.EncryptionEnabled
+}
+
+func (worker *FileEncryptionWorker) getJobMetadata(job *model.Job, key string) (int, *model.AppError) {
+	countStr := job.Data[key]
+	count := 0
+	var err error
+	if countStr != "" {
+		count, err = strconv.Atoi(countStr)
+		if err != nil {
+			return 0, model.NewAppError("getJobMetadata", model.NoTranslation, nil, "", http.StatusInternalServerError).Wrap(err)
+		}
+	}
+	return count, nil
+}
+
+func (worker *FileEncryptionWorker) DoJob(job *model.Job) {
+	logger := worker.logger.With(jobs.JobLoggerFields(job)...)
+	logger.Debug("Worker: Received a new candidate job.")
+	defer worker.jobServer.HandleJobPanic(logger, job)
+
+	if claimed, err := worker.jobServer.ClaimJob(job); err != nil {
+		logger.Warn("FileEncryptionWorker experienced an error while trying to claim job", mlog.Err(err))
+		return
+	} else if !claimed {
+		return
+	}
+
+	if worker.fileBackend == nil {
+		err := errors.New("file encryption is not enabled on this server")
+		logger.Error("FileEncryptionWorker: ", mlog.Err(err))
+		worker.setJobError(logger, job, model.NewAppError("DoJob", model.NoTranslation, nil, "", http.StatusInternalServerError).Wrap(err))
+		return
+	}
+
+	c := request.EmptyContext(worker.logger)
+
+	var appErr *model.AppError
+	// We get the job again because ClaimJob changes the job status.
+	job, appErr = worker.jobServer.GetJob(c, job.Id)
+	if appErr != nil {
+		logger.Error("FileEncryptionWorker: job execution error", mlog.Err(appErr))
+		worker.setJobError(logger, job, appErr)
+		return
+	}
+
+	// Every file of the backend is listed, rather than only those of the file infos, so that
+	// emojis, profile and brand images, plugins, imports and exports are encrypted too.
+	paths, err := worker.fileBackend.ListDirectoryRecursively("")
+	if err != nil {
+		logger.Error("FileEncryptionWorker: failed to list the files", mlog.Err(err))
+		worker.setJobError(logger, job, model.NewAppError("DoJob", model.NoTranslation, nil, "", http.StatusInternalServerError).Wrap(err))
+		return
+	}
+	sort.Strings(paths)
+
+	uploadPaths, appErr := worker.incompleteUploadPaths()
+	if appErr != nil {
+		logger.Error("FileEncryptionWorker: failed to get the uploads in progress", mlog.Err(appErr))
+		worker.setJobError(logger, job, appErr)
+		return
+	}
+
+	// Check if there is metadata for that job.
+	// If there isn't, it will be empty by default, which is the right value.
+	lastPath := job.Data["last_path"]
+
+	doneCount, appErr := worker.getJobMetadata(job, "done_file_count")
+	if appErr != nil {
+		logger.Error("FileEncryptionWorker: failed to get done file count", mlog.Err(appErr))
+		worker.setJobError(logger, job, appErr)
+		return
+	}
+
+	rewrittenCount, appErr := worker.getJobMetadata(job, "rewritten_file_count")
+	if appErr != nil {
+		logger.Error("FileEncryptionWorker: failed to get rewritten file count", mlog.Err(appErr))
+		worker.setJobError(logger, job, appErr)
+		return
+	}
+
+	failedCount, appErr := worker.getJobMetadata(job, "failed_file_count")
+	if appErr != nil {
+		logger.Error("FileEncryptionWorker: failed to get failed file count", mlog.Err(appErr))
+		worker.setJobError(logger, job, appErr)
+		return
+	}
+
+	start := 0
+	if lastPath != "" {
+		start = sort.SearchStrings(paths, lastPath)
+		if start < len(paths) && paths[start] == lastPath {
+			start++
+		}
+	}
+
+	const pageSize = 100
+
+	for start < len(paths) {
+		select {
+		case <-worker.stop:
+			logger.Info("Worker: File encryption has been canceled via Worker Stop. Setting the job back to pending.")
+			if err := worker.jobServer.SetJobPending(job); err != nil {
+				worker.logger.Error("Worker: Failed to mark job as pending", mlog.Err(err))
+			}
+			return
+		case <-time.After(timeBetweenBatches):
+			end := min(start+pageSize, len(paths))
+			for _, path := range paths[start:end] {
+				// The partial files of the uploads in progress are still appended to, and
+				// are encrypted by the next append or the next run of the job.
+				if uploadPaths[path] {
+					continue
+				}
+				// We do not fail the job right away if a single file failed to encrypt.
+				rewritten, err := worker.fileBackend.EncryptFile(path)
+				if err != nil {
+					logger.Warn("Failed to encrypt file", mlog.String("path", path), mlog.Err(err))
+					failedCount++
+					continue
+				}
+				if rewritten {
+					rewrittenCount++
+				}
+			}
+
+			doneCount += end - start
+			start = end
+
+			if job.Data == nil {
+				job.Data = make(model.StringMap)
+			}
+			job.Data["last_path"] = paths[end-1]
+			job.Data["done_file_count"] = strconv.Itoa(doneCount)
+			job.Data["rewritten_file_count"] = strconv.Itoa(rewrittenCount)
+			job.Data["failed_file_count"] = strconv.Itoa(failedCount)
+			job.Progress = int64(start * 100 / len(paths))
+			if err := worker.jobServer.UpdateInProgressJobData(job); err != nil {
+				logger.Warn("Worker: Failed to save the progress of the job", mlog.Err(err))
+			}
+		}
+	}
+
+	// The active key is only recorded once every file was encrypted with it, since the
+	// older keys may be removed from then on.
+	if failedCount > 0 {
+		worker.setJobError(logger, job, model.NewAppError("DoJob", model.NoTranslation, nil, fmt.Sprintf("%d files failed to be encrypted", failedCount), http.StatusInternalServerError))
+		return
+	}
+
+	logger.Info("FileEncryptionWorker: Job is complete")
+	worker.setJobSuccess(logger, job)
+	worker.markAsComplete(logger, job)
+}
+
+// incompleteUploadPaths returns the paths of the partial files of the uploads in progress.
+func (worker *FileEncryptionWorker) incompleteUploadPaths() (map[string]bool, *model.AppError) {
+	sessions, err := worker.store.UploadSession().GetIncomplete()
+	if err != nil {
+		return nil, model.NewAppError("incompleteUploadPaths", model.NoTranslation, nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+
+	paths := make(map[string]bool, len(sessions))
+	for _, session := range sessions {
+		path := session.Path
+		if session.Type == model.UploadTypeImport {
+			path += model.IncompleteUploadSuffix
+		}
+		paths[path] = true
+	}
+	return paths, nil
+}
+
+// markAsComplete records the key-encryption key the files were encrypted with, so that
+// the job is only scheduled again once the active key is rotated.
+func (worker *FileEncryptionWorker) markAsComplete(logger mlog.LoggerIFace, job *model.Job) {
+	system := model.System{
+		Name:  model.SystemFileEncryptionKeyId,
+		Value: worker.fileBackend.ActiveKeyId(),
+	}
+
+	// Note that if this fails, then the job would have still succeeded.
+	// So it will run again next time, but it will just fall through
+	// everything because all files would have been encrypted already.
+	if err := worker.jobServer.Store.System().SaveOrUpdate(&system); err != nil {
+		logger.Error("Worker: Failed to mark file encryption as completed in the systems table.", mlog.Err(err))
+	}
+}
+
+func (worker *FileEncryptionWorker) setJobSuccess(logger mlog.LoggerIFace, job *model.Job) {
+	if err := worker.jobServer.SetJobProgress(job, 100); err != nil {
+		logger.Error("Worker: Failed to update progress for job", mlog.Err(err))
+		worker.setJobError(logger, job, err)
+	}
+
+	if err := worker.jobServer.SetJobSuccess(job); err != nil {
+		logger.Error("FileEncryptionWorker: Failed to set success for job", mlog.Err(err))
+		worker.setJobError(logger, job, err)
+	}
+}
+
+func (worker *FileEncryptionWorker) setJobError(logger mlog.LoggerIFace, job *model.Job, appError *model.AppError) {
+	if err := worker.jobServer.SetJobError(job, appError); err != nil {
+		logger.Error("FileEncryptionWorker: Failed to set job error", mlog.Err(err))
+	}
+}
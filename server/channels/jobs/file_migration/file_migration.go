@@ -0,0 +1,641 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package file_migration
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+	"github.com/mattermost/mattermost/server/public/shared/request"
+	"github.com/mattermost/mattermost/server/v8/channels/jobs"
+	"github.com/mattermost/mattermost/server/v8/platform/shared/filestore"
+)
+
+const (
+	batchSize = 100
+
+	// switchGracePeriod is how long the final pass waits once the configuration was switched,
+	// giving the other servers of the cluster time to switch to the new backend.
+	switchGracePeriod = 10 * time.Second
+
+	// storeFiles and storeExport are the values of the "store" job data, telling whether the
+	// files or the dedicated export store is migrated.
+	storeFiles  = "files"
+	storeExport = "export"
+
+	// sealedFileSettingsPurpose separates the key sealing the file settings of the jobs from the
+	// other uses of the secret it is derived from.
+	sealedFileSettingsPurpose = "file_migration_settings"
+)
+
+type AppIface interface {
+	Config() *model.Config
+	License() *model.License
+	SaveConfig(newCfg *model.Config, sendConfigChangeClusterMessage bool) (*model.Config, *model.Config, *model.AppError)
+	PostActionCookieSecret() []byte
+}
+
+// FileMigrationWorker copies the files of the file store, or of the export store, to the
+// backend described by the "sealed_file_settings" job data, a JSON object of the FileSettings
+// to change, limited to those selecting and configuring the backend, sealed by SealFileSettings
+// as it holds the credentials of the backend. The copies are verified by size and checksum,
+// and the configuration is switched to the new backend in a single save once every file was
+// copied. The servers switch to the new backend as the configuration changes, and the files
+// uploaded to the old backend in the meantime, including those of the uploads in progress, are
+// then copied in a final pass.
+type FileMigrationWorker struct {
+	name      string
+	jobServer *jobs.JobServer
+	logger    mlog.LoggerIFace
+	app       AppIface
+
+	stop    chan struct{}
+	stopped chan bool
+	jobs    chan model.Job
+}
+
+func MakeWorker(jobServer *jobs.JobServer, app AppIface) *FileMigrationWorker {
+	const workerName = "FileMigration"
+	worker := &FileMigrationWorker{
+		name:      workerName,
+		jobServer: jobServer,
+		logger:    jobServer.Logger().With(mlog.String("worker_name", workerName)),
+		app:       app,
+		stop:      make(chan struct{}),
+		stopped:   make(chan bool, 1),
+		jobs:      make(chan model.Job),
+	}
+	return worker
+}
+
+func (worker *FileMigrationWorker) Run() {
+	worker.logger.Debug("Worker started")
+	// We have to re-assign the stop channel again, because
+	// it might happen that the job was restarted due to a config change.
+	worker.stop = make(chan struct{}, 1)
+
+	defer func() {
+		worker.logger.Debug("Worker finished")
+		worker.stopped <- true
+	}()
+
+	for {
+		select {
+		case <-worker.stop:
+			worker.logger.Debug("Worker received stop signal")
+			return
+		case job := <-worker.jobs:
+			worker.DoJob(&job)
+		}
+	}
+}
+
+func (worker *FileMigrationWorker) Stop() {
+	worker.logger.Debug("Worker stopping")
+	close(worker.stop)
+	<-worker.stopped
+}
+
+func (worker *FileMigrationWorker) JobChannel() chan<- model.Job {
+	return worker.jobs
+}
+
+func (worker *FileMigrationWorker) IsEnabled(_ *model.Config) bool {
+	return true
+}
+
+func (worker *FileMigrationWorker) getJobMetadata(job *model.Job, key string) (int, *model.AppError) {
+	countStr := job.Data[key]
+	count := 0
+	var err error
+	if countStr != "" {
+		count, err = strconv.Atoi(countStr)
+		if err != nil {
+			return 0, model.NewAppError("getJobMetadata", model.NoTranslation, nil, "", http.StatusInternalServerError).Wrap(err)
+		}
+	}
+	return count, nil
+}
+
+func (worker *FileMigrationWorker) DoJob(job *model.Job) {
+	logger := worker.logger.With(jobs.JobLoggerFields(job)...)
+	logger.Debug("Worker: Received a new candidate job.")
+	defer worker.jobServer.HandleJobPanic(logger, job)
+
+	if claimed, err := worker.jobServer.ClaimJob(job); err != nil {
+		logger.Warn("FileMigrationWorker experienced an error while trying to claim job", mlog.Err(err))
+		return
+	} else if !claimed {
+		return
+	}
+
+	c := request.EmptyContext(worker.logger)
+	cancelCtx, cancelCancelWatcher := context.WithCancel(context.Background())
+	defer cancelCancelWatcher()
+	cancelWatcherChan := make(chan struct{}, 1)
+	go worker.jobServer.CancellationWatcher(c.WithContext(cancelCtx), job.Id, cancelWatcherChan)
+
+	var appErr *model.AppError
+	// We get the job again because ClaimJob changes the job status.
+	job, appErr = worker.jobServer.GetJob(c, job.Id)
+	if appErr != nil {
+		logger.Error("FileMigrationWorker: job execution error", mlog.Err(appErr))
+		worker.setJobError(logger, job, appErr)
+		return
+	}
+
+	export := job.Data["store"] == storeExport
+	if store := job.Data["store"]; store != "" && store != storeFiles && store != storeExport {
+		worker.setJobError(logger, job, model.NewAppError("DoJob", model.NoTranslation, nil, fmt.Sprintf("unknown store %q", store), http.StatusBadRequest))
+		return
+	}
+
+	newCfg, appErr := applyFileSettings(worker.app.Config(), worker.app.PostActionCookieSecret(), job, export)
+	if appErr != nil {
+		logger.Error("FileMigrationWorker: invalid file settings", mlog.Err(appErr))
+		worker.setJobError(logger, job, appErr)
+		return
+	}
+
+	srcSettings, srcBackend, appErr := worker.newBackend(worker.app.Config(), export)
+	if appErr != nil {
+		logger.Error("FileMigrationWorker: failed to create the source backend", mlog.Err(appErr))
+		worker.setJobError(logger, job, appErr)
+		return
+	}
+	dstSettings, dstBackend, appErr := worker.newBackend(newCfg, export)
+	if appErr != nil {
+		logger.Error("FileMigrationWorker: failed to create the destination backend", mlog.Err(appErr))
+		worker.setJobError(logger, job, appErr)
+		return
+	}
+	if reflect.DeepEqual(srcSettings, dstSettings) {
+		worker.setJobError(logger, job, model.NewAppError("DoJob", model.NoTranslation, nil, "the file settings already use this backend", http.StatusBadRequest))
+		return
+	}
+	if err := dstBackend.TestConnection(); err != nil {
+		logger.Error("FileMigrationWorker: failed to connect to the destination backend", mlog.Err(err))
+		worker.setJobError(logger, job, model.NewAppError("DoJob", model.NoTranslation, nil, "", http.StatusInternalServerError).Wrap(err))
+		return
+	}
+
+	// The export store defaults to the file store, in which the exports are stored in the
+	// export directory.
+	dir := ""
+	if export && !*worker.app.Config().FileSettings.DedicatedExportStore {
+		dir = *worker.app.Config().ExportSettings.Directory
+	}
+	paths, appErr := listFiles(srcBackend, dir)
+	if appErr != nil {
+		logger.Error("FileMigrationWorker: failed to list the files", mlog.Err(appErr))
+		worker.setJobError(logger, job, appErr)
+		return
+	}
+
+	// Check if there is metadata for that job.
+	// If there isn't, it will be empty by default, which is the right value.
+	lastPath := job.Data["last_path"]
+
+	doneCount, appErr := worker.getJobMetadata(job, "done_file_count")
+	if appErr != nil {
+		logger.Error("FileMigrationWorker: failed to get done file count", mlog.Err(appErr))
+		worker.setJobError(logger, job, appErr)
+		return
+	}
+	copiedCount, appErr := worker.getJobMetadata(job, "copied_file_count")
+	if appErr != nil {
+		logger.Error("FileMigrationWorker: failed to get copied file count", mlog.Err(appErr))
+		worker.setJobError(logger, job, appErr)
+		return
+	}
+	var failedPaths []string
+	if job.Data["failed_paths"] != "" {
+		if err := json.Unmarshal([]byte(job.Data["failed_paths"]), &failedPaths); err != nil {
+			logger.Error("FileMigrationWorker: failed to get the failed paths", mlog.Err(err))
+			worker.setJobError(logger, job, model.NewAppError("DoJob", model.NoTranslation, nil, "", http.StatusInternalServerError).Wrap(err))
+			return
+		}
+	}
+
+	// The partial files of the uploads in progress are copied once the configuration is
+	// switched, since they are still appended to in the meantime.
+	uploadPaths, appErr := worker.incompleteUploadPaths(export)
+	if appErr != nil {
+		logger.Error("FileMigrationWorker: failed to get the uploads in progress", mlog.Err(appErr))
+		worker.setJobError(logger, job, appErr)
+		return
+	}
+
+	start := 0
+	if lastPath != "" {
+		start = sort.SearchStrings(paths, lastPath)
+		if start < len(paths) && paths[start] == lastPath {
+			start++
+		}
+	}
+
+	// The files that failed to be copied before the job was resumed are retried first.
+	retryPaths := failedPaths
+	failedPaths = nil
+	migrate := func(path string) {
+		if uploadPaths[path] {
+			return
+		}
+		// A single failure doesn't stop the job, but the configuration is only switched once
+		// every file was copied.
+		copied, err := filestore.MigrateFile(cancelCtx, srcBackend, dstBackend, path)
+		if err != nil {
+			logger.Warn("Failed to copy file", mlog.String("path", path), mlog.Err(err))
+			failedPaths = append(failedPaths, path)
+			return
+		}
+		if copied {
+			copiedCount++
+		}
+	}
+	for _, path := range retryPaths {
+		migrate(path)
+	}
+
+	for start < len(paths) {
+		select {
+		case <-cancelWatcherChan:
+			logger.Info("Worker: File migration has been canceled via CancellationWatcher.")
+			worker.setJobCanceled(logger, job)
+			return
+		case <-worker.stop:
+			logger.Info("Worker: File migration has been canceled via Worker Stop. Setting the job back to pending.")
+			if err := worker.jobServer.SetJobPending(job); err != nil {
+				worker.logger.Error("Worker: Failed to mark job as pending", mlog.Err(err))
+			}
+			return
+		default:
+		}
+
+		end := start + batchSize
+		if end > len(paths) {
+			end = len(paths)
+		}
+		for _, path := range paths[start:end] {
+			migrate(path)
+		}
+		doneCount += end - start
+		start = end
+
+		job.Data["last_path"] = paths[end-1]
+		job.Data["total_file_count"] = strconv.Itoa(doneCount + len(paths) - end)
+		job.Data["done_file_count"] = strconv.Itoa(doneCount)
+		job.Data["copied_file_count"] = strconv.Itoa(copiedCount)
+		if appErr := setFailedPaths(job, failedPaths); appErr != nil {
+			logger.Warn("Worker: Failed to save the failed paths of the job", mlog.Err(appErr))
+		}
+		job.Progress = int64(start * 100 / len(paths))
+		if err := worker.jobServer.UpdateInProgressJobData(job); err != nil {
+			logger.Warn("Worker: Failed to save the progress of the job", mlog.Err(err))
+		}
+	}
+
+	if len(failedPaths) > 0 {
+		// The failed paths are kept for them to be retried when the job is run again.
+		if appErr := setFailedPaths(job, failedPaths); appErr != nil {
+			logger.Warn("Worker: Failed to save the failed paths of the job", mlog.Err(appErr))
+		}
+		worker.setJobError(logger, job, model.NewAppError("DoJob", model.NoTranslation, nil, fmt.Sprintf("%d files failed to be copied", len(failedPaths)), http.StatusInternalServerError))
+		return
+	}
+	delete(job.Data, "failed_paths")
+	job.Data["failed_file_count"] = "0"
+
+	if appErr := worker.switchBackend(job, srcSettings, export); appErr != nil {
+		logger.Error("FileMigrationWorker: failed to switch to the new backend", mlog.Err(appErr))
+		worker.setJobError(logger, job, appErr)
+		return
+	}
+
+	// Once the configuration is switched, the job can't be resumed against the old backend,
+	// so the final pass runs to completion.
+	time.Sleep(switchGracePeriod)
+	uploadCopiedCount, appErr := worker.copyUploads(logger, srcBackend, dstBackend, export)
+	if appErr != nil {
+		logger.Error("FileMigrationWorker: failed to copy the uploads in progress", mlog.Err(appErr))
+		worker.setJobError(logger, job, appErr)
+		return
+	}
+	newCopiedCount, appErr := worker.copyNewFiles(logger, srcBackend, dstBackend, dir)
+	if appErr != nil {
+		logger.Error("FileMigrationWorker: failed to copy the files uploaded during the migration", mlog.Err(appErr))
+		worker.setJobError(logger, job, appErr)
+		return
+	}
+	newCopiedCount += uploadCopiedCount
+	job.Data["copied_file_count"] = strconv.Itoa(copiedCount + newCopiedCount)
+
+	logger.Info("FileMigrationWorker: Job is complete.", mlog.Int("new_file_count", newCopiedCount))
+	worker.setJobSuccess(logger, job)
+}
+
+// setFailedPaths records the paths of the files that failed to be copied in the job data, for
+// them to be retried when the job is resumed.
+func setFailedPaths(job *model.Job, failedPaths []string) *model.AppError {
+	job.Data["failed_file_count"] = strconv.Itoa(len(failedPaths))
+	if len(failedPaths) == 0 {
+		delete(job.Data, "failed_paths")
+		return nil
+	}
+	buf, err := json.Marshal(failedPaths)
+	if err != nil {
+		return model.NewAppError("setFailedPaths", model.NoTranslation, nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+	job.Data["failed_paths"] = string(buf)
+	return nil
+}
+
+// incompleteUploadPaths returns the paths of the partial files of the uploads in progress. The
+// uploads are stored in the file store, so there are none to return when the export store is
+// migrated.
+func (worker *FileMigrationWorker) incompleteUploadPaths(export bool) (map[string]bool, *model.AppError) {
+	paths := make(map[string]bool)
+	if export {
+		return paths, nil
+	}
+	sessions, err := worker.jobServer.Store.UploadSession().GetIncomplete()
+	if err != nil {
+		return nil, model.NewAppError("incompleteUploadPaths", model.NoTranslation, nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+	for _, session := range sessions {
+		path := session.Path
+		if session.Type == model.UploadTypeImport {
+			path += model.IncompleteUploadSuffix
+		}
+		paths[path] = true
+	}
+	return paths, nil
+}
+
+// copyUploads copies the partial files of the uploads in progress, which were left out of the
+// migration since they are appended to, once the servers switched to the new backend, for the
+// uploads to be resumed there. It returns the number of copied files.
+func (worker *FileMigrationWorker) copyUploads(logger mlog.LoggerIFace, srcBackend, dstBackend filestore.FileBackend, export bool) (int, *model.AppError) {
+	paths, appErr := worker.incompleteUploadPaths(export)
+	if appErr != nil {
+		return 0, appErr
+	}
+
+	copiedCount := 0
+	failedCount := 0
+	for path := range paths {
+		exists, err := srcBackend.FileExists(path)
+		if err == nil && !exists {
+			// The upload hasn't started yet, or was resumed in the new backend.
+			continue
+		}
+		if err == nil {
+			_, err = filestore.MigrateFile(context.Background(), srcBackend, dstBackend, path)
+		}
+		if err != nil {
+			logger.Error("Failed to copy the file of an upload in progress", mlog.String("path", path), mlog.Err(err))
+			failedCount++
+			continue
+		}
+		copiedCount++
+	}
+
+	if failedCount > 0 {
+		return copiedCount, model.NewAppError("copyUploads", model.NoTranslation, nil, fmt.Sprintf("the configuration was switched to the new backend, but the files of %d uploads in progress failed to be copied from the old backend", failedCount), http.StatusInternalServerError)
+	}
+	return copiedCount, nil
+}
+
+func listFiles(backend filestore.FileBackend, dir string) ([]string, *model.AppError) {
+	paths, err := backend.ListDirectoryRecursively(dir)
+	if err != nil {
+		return nil, model.NewAppError("listFiles", model.NoTranslation, nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+	sort.Strings(paths)
+	return paths, nil
+}
+
+// copyNewFiles copies the files of the old backend that the new one doesn't have, such as those
+// uploaded after the files were listed. Files already in the new backend are left alone, since
+// they may have been written there since the switch. It returns the number of copied files.
+func (worker *FileMigrationWorker) copyNewFiles(logger mlog.LoggerIFace, srcBackend, dstBackend filestore.FileBackend, dir string) (int, *model.AppError) {
+	paths, appErr := listFiles(srcBackend, dir)
+	if appErr != nil {
+		return 0, appErr
+	}
+
+	copiedCount := 0
+	failedCount := 0
+	for _, path := range paths {
+		exists, err := dstBackend.FileExists(path)
+		if err == nil && exists {
+			continue
+		}
+		if err == nil {
+			_, err = filestore.MigrateFile(context.Background(), srcBackend, dstBackend, path)
+		}
+		if err != nil {
+			// The file is left in the old backend to be copied by hand.
+			logger.Error("Failed to copy file uploaded during the migration", mlog.String("path", path), mlog.Err(err))
+			failedCount++
+			continue
+		}
+		copiedCount++
+	}
+
+	if failedCount > 0 {
+		return copiedCount, model.NewAppError("copyNewFiles", model.NoTranslation, nil, fmt.Sprintf("the configuration was switched to the new backend, but %d files uploaded during the migration failed to be copied from the old backend", failedCount), http.StatusInternalServerError)
+	}
+	return copiedCount, nil
+}
+
+// SealFileSettings returns the sealed form of the file settings of a migration, for them to be
+// stored in the job data, which is readable by anyone allowed to read the jobs, without exposing
+// the credentials of the new backend. The settings are encrypted with a key derived from the
+// given secret, shared by the servers of the cluster.
+func SealFileSettings(secret []byte, settings string) (string, error) {
+	gcm, err := newFileSettingsGCM(secret)
+	if err != nil {
+		return "", err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(gcm.Seal(nonce, nonce, []byte(settings), []byte(sealedFileSettingsPurpose))), nil
+}
+
+func openFileSettings(secret []byte, sealed string) (string, error) {
+	gcm, err := newFileSettingsGCM(secret)
+	if err != nil {
+		return "", err
+	}
+	buf, err := base64.StdEncoding.DecodeString(sealed)
+	if err != nil {
+		return "", err
+	}
+	if len(buf) < gcm.NonceSize() {
+		return "", errors.New("the sealed file settings are too short")
+	}
+	settings, err := gcm.Open(nil, buf[:gcm.NonceSize()], buf[gcm.NonceSize():], []byte(sealedFileSettingsPurpose))
+	if err != nil {
+		return "", err
+	}
+	return string(settings), nil
+}
+
+func newFileSettingsGCM(secret []byte) (cipher.AEAD, error) {
+	if len(secret) == 0 {
+		return nil, errors.New("no secret to seal the file settings with")
+	}
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(sealedFileSettingsPurpose))
+	block, err := aes.NewCipher(mac.Sum(nil))
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// applyFileSettings returns a copy of the configuration changed by the sealed file settings of
+// the job.
+func applyFileSettings(cfg *model.Config, secret []byte, job *model.Job, export bool) (*model.Config, *model.AppError) {
+	if job.Data["sealed_file_settings"] == "" {
+		return nil, model.NewAppError("applyFileSettings", model.NoTranslation, nil, "the settings of the new backend are missing", http.StatusBadRequest)
+	}
+	settings, err := openFileSettings(secret, job.Data["sealed_file_settings"])
+	if err != nil {
+		return nil, model.NewAppError("applyFileSettings", model.NoTranslation, nil, "unable to open the settings of the new backend", http.StatusBadRequest).Wrap(err)
+	}
+
+	newCfg := cfg.Clone()
+	if err := json.Unmarshal([]byte(settings), &newCfg.FileSettings); err != nil {
+		return nil, model.NewAppError("applyFileSettings", model.NoTranslation, nil, "", http.StatusBadRequest).Wrap(err)
+	}
+	if export {
+		newCfg.FileSettings.DedicatedExportStore = model.NewPointer(true)
+	}
+
+	// Only the settings of the backend can be changed, the others, such as the commands run
+	// to process files, being restricted when the configuration is saved through the API.
+	oldSettings := reflect.ValueOf(cfg.FileSettings)
+	newSettings := reflect.ValueOf(newCfg.FileSettings)
+	for i := 0; i < newSettings.NumField(); i++ {
+		name := newSettings.Type().Field(i).Name
+		if !isBackendSetting(name, export) && !reflect.DeepEqual(oldSettings.Field(i).Interface(), newSettings.Field(i).Interface()) {
+			return nil, model.NewAppError("applyFileSettings", model.NoTranslation, nil, fmt.Sprintf("the %s setting can't be changed by a file migration", name), http.StatusBadRequest)
+		}
+	}
+	if appErr := newCfg.IsValid(); appErr != nil {
+		return nil, appErr
+	}
+	return newCfg, nil
+}
+
+// isBackendSetting reports whether the file setting of the given name selects or configures the
+// file backend, or the export backend.
+func isBackendSetting(name string, export bool) bool {
+	if export {
+		return name == "DedicatedExportStore" || name == "ExportDriverName" || name == "ExportDirectory" || strings.HasPrefix(name, "ExportAmazonS3")
+	}
+	return name == "DriverName" || name == "Directory" || strings.HasPrefix(name, "AmazonS3") || strings.HasPrefix(name, "Azure") || strings.HasPrefix(name, "GCS")
+}
+
+// newBackend creates the file backend, or the export backend, of the given configuration.
+// The file backend isn't wrapped by the encryption, so that the files are copied as stored.
+func (worker *FileMigrationWorker) newBackend(cfg *model.Config, export bool) (filestore.FileBackendSettings, filestore.FileBackend, *model.AppError) {
+	license := worker.app.License()
+	complianceEnabled := license != nil && *license.Features.Compliance
+	insecure := cfg.ServiceSettings.EnableInsecureOutgoingConnections != nil && *cfg.ServiceSettings.EnableInsecureOutgoingConnections
+
+	var settings filestore.FileBackendSettings
+	var backend filestore.FileBackend
+	var err error
+	switch {
+	case export && *cfg.FileSettings.DedicatedExportStore:
+		settings = filestore.NewExportFileBackendSettingsFromConfig(&cfg.FileSettings, complianceEnabled, false)
+		backend, err = filestore.NewExportFileBackend(settings)
+	case export:
+		// The exports are read from the file store, decrypted.
+		settings = filestore.NewFileBackendSettingsFromConfig(&cfg.FileSettings, complianceEnabled, insecure)
+		backend, err = filestore.NewFileBackend(settings)
+	default:
+		settings = filestore.NewFileBackendSettingsFromConfig(&cfg.FileSettings, complianceEnabled, insecure)
+		settings.EncryptionEnabled = false
+		backend, err = filestore.NewFileBackend(settings)
+	}
+	if err != nil {
+		return settings, nil, model.NewAppError("newBackend", "api.file.no_driver.app_error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+	return settings, backend, nil
+}
+
+// switchBackend saves the configuration changed by the file settings of the job, unless the
+// backend the files were copied from was changed in the meantime.
+func (worker *FileMigrationWorker) switchBackend(job *model.Job, srcSettings filestore.FileBackendSettings, export bool) *model.AppError {
+	cfg := worker.app.Config()
+	currentSettings, _, appErr := worker.newBackend(cfg, export)
+	if appErr != nil {
+		return appErr
+	}
+	if !reflect.DeepEqual(currentSettings, srcSettings) {
+		return model.NewAppError("switchBackend", model.NoTranslation, nil, "", http.StatusConflict).Wrap(errors.New("the file settings were changed during the migration"))
+	}
+
+	newCfg, appErr := applyFileSettings(cfg, worker.app.PostActionCookieSecret(), job, export)
+	if appErr != nil {
+		return appErr
+	}
+	if _, _, appErr := worker.app.SaveConfig(newCfg, true); appErr != nil {
+		return appErr
+	}
+	return nil
+}
+
+func (worker *FileMigrationWorker) setJobSuccess(logger mlog.LoggerIFace, job *model.Job) {
+	// The settings of the new backend, even sealed, aren't kept once the job is over.
+	delete(job.Data, "sealed_file_settings")
+
+	if err := worker.jobServer.SetJobProgress(job, 100); err != nil {
+		logger.Error("Worker: Failed to update progress for job", mlog.Err(err))
+		worker.setJobError(logger, job, err)
+	}
+
+	if err := worker.jobServer.SetJobSuccess(job); err != nil {
+		logger.Error("FileMigrationWorker: Failed to set success for job", mlog.Err(err))
+		worker.setJobError(logger, job, err)
+	}
+}
+
+func (worker *FileMigrationWorker) setJobError(logger mlog.LoggerIFace, job *model.Job, appError *model.AppError) {
+	delete(job.Data, "sealed_file_settings")
+
+	if err := worker.jobServer.SetJobError(job, appError); err != nil {
+		logger.Error("FileMigrationWorker: Failed to set job error", mlog.Err(err))
+	}
+}
+
+func (worker *FileMigrationWorker) setJobCanceled(logger mlog.LoggerIFace, job *model.Job) {
+	delete(job.Data, "sealed_file_settings")
+
+	if err := worker.jobServer.SetJobCanceled(job); err != nil {
+		logger.Error("FileMigrationWorker: Failed to mark job as canceled", mlog.Err(err))
+	}
+}
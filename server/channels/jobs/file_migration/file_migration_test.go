@@ -0,0 +1,71 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package file_migration
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost/server/public/model"
+)
+
+func TestApplyFileSettings(t *testing.T) {
+	cfg := &model.Config{}
+	cfg.SetDefaults()
+
+	secret := []byte("secret")
+	newJob := func(fileSettings string) *model.Job {
+		sealed, err := SealFileSettings(secret, fileSettings)
+		require.NoError(t, err)
+		return &model.Job{Data: model.StringMap{"sealed_file_settings": sealed}}
+	}
+
+	t.Run("backend settings are applied", func(t *testing.T) {
+		newCfg, appErr := applyFileSettings(cfg, secret, newJob(`{"DriverName": "amazons3", "AmazonS3Bucket": "bucket"}`), false)
+		require.Nil(t, appErr)
+		assert.Equal(t, model.ImageDriverS3, *newCfg.FileSettings.DriverName)
+		assert.Equal(t, "bucket", *newCfg.FileSettings.AmazonS3Bucket)
+		assert.Equal(t, model.ImageDriverLocal, *cfg.FileSettings.DriverName)
+	})
+
+	t.Run("unchanged settings are allowed", func(t *testing.T) {
+		_, appErr := applyFileSettings(cfg, secret, newJob(`{"DriverName": "amazons3", "FFmpegCommand": "`+*cfg.FileSettings.FFmpegCommand+`"}`), false)
+		require.Nil(t, appErr)
+	})
+
+	t.Run("other settings are rejected", func(t *testing.T) {
+		for _, fileSettings := range []string{
+			`{"DriverName": "amazons3", "FFmpegCommand": "/tmp/evil"}`,
+			`{"OCRCommand": "/tmp/evil"}`,
+			`{"ImageConverterCommand": "/tmp/evil"}`,
+			`{"EncryptionEnabled": true}`,
+			`{"ExportDriverName": "amazons3"}`,
+		} {
+			_, appErr := applyFileSettings(cfg, secret, newJob(fileSettings), false)
+			require.NotNil(t, appErr, fileSettings)
+			assert.Equal(t, "applyFileSettings", appErr.Where, fileSettings)
+		}
+	})
+
+	t.Run("export migrations only change the export settings", func(t *testing.T) {
+		newCfg, appErr := applyFileSettings(cfg, secret, newJob(`{"ExportDriverName": "amazons3", "ExportAmazonS3Bucket": "bucket"}`), true)
+		require.Nil(t, appErr)
+		assert.True(t, *newCfg.FileSettings.DedicatedExportStore)
+
+		_, appErr = applyFileSettings(cfg, secret, newJob(`{"DriverName": "amazons3"}`), true)
+		require.NotNil(t, appErr)
+	})
+
+	t.Run("settings sealed with another secret are rejected", func(t *testing.T) {
+		_, appErr := applyFileSettings(cfg, []byte("other"), newJob(`{"DriverName": "amazons3"}`), false)
+		require.NotNil(t, appErr)
+	})
+
+	t.Run("unsealed settings are rejected", func(t *testing.T) {
+		_, appErr := applyFileSettings(cfg, secret, &model.Job{Data: model.StringMap{"file_settings": `{"DriverName": "amazons3"}`}}, false)
+		require.NotNil(t, appErr)
+	})
+}
@@ -0,0 +1,43 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package purge_file_blobs
+
+import (
+	"time"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+	"github.com/mattermost/mattermost/server/public/shared/request"
+	"github.com/mattermost/mattermost/server/v8/channels/jobs"
+)
+
+const schedFreq = 1 * time.Hour
+
+// Scheduler schedules a purge when there are unreferenced or tombstoned blobs to remove. It
+// runs whether or not the deduplication is enabled, since the blobs of the files uploaded
+// while it was enabled are only removed by the purge.
+type Scheduler struct {
+	*jobs.PeriodicScheduler
+	jobServer *jobs.JobServer
+}
+
+func (scheduler *Scheduler) ScheduleJob(c request.CTX, cfg *model.Config, pendingJobs bool, lastSuccessfulJob *model.Job) (*model.Job, *model.AppError) {
+	blobs, err := scheduler.jobServer.Store.FileBlob().GetUnreferenced("", 1)
+	if err != nil {
+		c.Logger().Warn("Failed to check for unreferenced file blobs", mlog.String("scheduler", model.JobTypePurgeFileBlobs), mlog.Err(err))
+		return nil, nil
+	}
+	if len(blobs) == 0 {
+		return nil, nil
+	}
+
+	return scheduler.PeriodicScheduler.ScheduleJob(c, cfg, pendingJobs, lastSuccessfulJob)
+}
+
+func MakeScheduler(jobServer *jobs.JobServer) *Scheduler {
+	isEnabled := func(_ *model.Config) bool {
+		return true
+	}
+	return &Scheduler{jobs.NewPeriodicScheduler(jobServer, model.JobTypePurgeFileBlobs, schedFreq, isEnabled), jobServer}
+}
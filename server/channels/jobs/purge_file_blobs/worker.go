@@ -0,0 +1,46 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package purge_file_blobs
+
+import (
+	"strconv"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+	"github.com/mattermost/mattermost/server/public/shared/request"
+	"github.com/mattermost/mattermost/server/v8/channels/jobs"
+)
+
+type AppIface interface {
+	PurgeUnreferencedFileBlobs(rctx request.CTX) (int64, *model.AppError)
+}
+
+// MakeWorker creates a worker removing the file blobs whose last file info was permanently
+// deleted, such as by data retention, from the file store.
+func MakeWorker(jobServer *jobs.JobServer, app AppIface) *jobs.SimpleWorker {
+	const workerName = "PurgeFileBlobs"
+
+	// As for the scheduler, the blobs are purged whether or not the deduplication is enabled.
+	isEnabled := func(_ *model.Config) bool {
+		return true
+	}
+	execute := func(logger mlog.LoggerIFace, job *model.Job) error {
+		defer jobServer.HandleJobPanic(logger, job)
+
+		purged, appErr := app.PurgeUnreferencedFileBlobs(request.EmptyContext(logger))
+		if appErr != nil {
+			return appErr
+		}
+
+		if job.Data == nil {
+			job.Data = make(model.StringMap)
+		}
+		job.Data["purged_bytes"] = strconv.FormatInt(purged, 10)
+		if appErr := jobServer.UpdateInProgressJobData(job); appErr != nil {
+			logger.Warn("Worker: Failed to save the purged bytes of the job", mlog.Err(appErr))
+		}
+		return nil
+	}
+	return jobs.NewSimpleWorker(workerName, jobServer, execute, isEnabled)
+}
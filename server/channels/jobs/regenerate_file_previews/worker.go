@@ -0,0 +1,96 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package regenerate_file_previews
+
+import (
+	"strconv"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+	"github.com/mattermost/mattermost/server/public/shared/request"
+	"github.com/mattermost/mattermost/server/v8/channels/jobs"
+	"github.com/mattermost/mattermost/server/v8/channels/store"
+)
+
+const batchSize = 1000
+
+type AppIface interface {
+	GenerateMissingFilePreviews(rctx request.CTX, info *model.FileInfo) (bool, *model.AppError)
+}
+
+// MakeWorker creates the worker generating the thumbnails and previews of the images created
+// between the optional "from" and "to" times of the job, in seconds, which are missing them.
+func MakeWorker(jobServer *jobs.JobServer, app AppIface, store store.Store) *jobs.SimpleWorker {
+	const workerName = "RegenerateFilePreviews"
+
+	isEnabled := func(cfg *model.Config) bool {
+		return true
+	}
+	execute := func(logger mlog.LoggerIFace, job *model.Job) error {
+		defer jobServer.HandleJobPanic(logger, job)
+
+		var err error
+		var fromTS int64
+		var toTS int64 = model.GetMillis()
+		if fromStr, ok := job.Data["from"]; ok {
+			if fromTS, err = strconv.ParseInt(fromStr, 10, 64); err != nil {
+				return err
+			}
+			fromTS *= 1000
+		}
+		if toStr, ok := job.Data["to"]; ok {
+			if toTS, err = strconv.ParseInt(toStr, 10, 64); err != nil {
+				return err
+			}
+			toTS *= 1000
+		}
+
+		rctx := request.EmptyContext(logger)
+
+		var nFiles, nRegenerated, nErrs int
+		startTime, startFileID := fromTS, ""
+	loop:
+		for {
+			files, err := store.FileInfo().GetFilesBatchForIndexing(startTime, startFileID, false, batchSize)
+			if err != nil {
+				return err
+			}
+
+			for _, file := range files {
+				if file.CreateAt > toTS {
+					break loop
+				}
+
+				info := file.FileInfo
+				if info.IsImage() && !info.IsSvg() {
+					regenerated, appErr := app.GenerateMissingFilePreviews(rctx, &info)
+					if appErr != nil {
+						logger.Warn("Failed to regenerate file previews", mlog.String("file_info_id", info.Id), mlog.Err(appErr))
+						nErrs++
+					} else if regenerated {
+						nRegenerated++
+					}
+					nFiles++
+				}
+			}
+
+			if len(files) < batchSize {
+				break
+			}
+			lastFile := files[len(files)-1]
+			startTime, startFileID = lastFile.CreateAt, lastFile.Id
+		}
+
+		job.Data["processed"] = strconv.Itoa(nFiles)
+		job.Data["regenerated"] = strconv.Itoa(nRegenerated)
+		job.Data["errors"] = strconv.Itoa(nErrs)
+
+		if err := jobServer.UpdateInProgressJobData(job); err != nil {
+			logger.Error("Worker: Failed to update job data", mlog.Err(err))
+		}
+		return nil
+	}
+	worker := jobs.NewSimpleWorker(workerName, jobServer, execute, isEnabled)
+	return worker
+}
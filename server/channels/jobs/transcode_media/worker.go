@@ -0,0 +1,114 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package transcode_media
+
+import (
+	"strconv"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+	"github.com/mattermost/mattermost/server/public/shared/request"
+	"github.com/mattermost/mattermost/server/v8/channels/jobs"
+	"github.com/mattermost/mattermost/server/v8/channels/store"
+)
+
+const batchSize = 1000
+
+type AppIface interface {
+	TranscodeMediaFile(rctx request.CTX, info *model.FileInfo) (bool, *model.AppError)
+}
+
+// MakeWorker creates the worker transcoding the video of the "file_id" of the job, or the
+// videos created between the optional "from" and "to" times of the job, in seconds, into
+// web-friendly renditions.
+func MakeWorker(jobServer *jobs.JobServer, app AppIface, store store.Store) *jobs.SimpleWorker {
+	const workerName = "TranscodeMedia"
+
+	isEnabled := func(cfg *model.Config) bool {
+		return *cfg.FileSettings.EnableFFmpeg && *cfg.FileSettings.EnableMediaTranscoding
+	}
+	execute := func(logger mlog.LoggerIFace, job *model.Job) error {
+		defer jobServer.HandleJobPanic(logger, job)
+
+		rctx := request.EmptyContext(logger)
+
+		if fileID, ok := job.Data["file_id"]; ok {
+			info, err := store.FileInfo().Get(fileID)
+			if err != nil {
+				return err
+			}
+			transcoded, appErr := app.TranscodeMediaFile(rctx, info)
+			if appErr != nil {
+				return appErr
+			}
+
+			job.Data["transcoded"] = strconv.FormatBool(transcoded)
+			if err := jobServer.UpdateInProgressJobData(job); err != nil {
+				logger.Error("Worker: Failed to update job data", mlog.Err(err))
+			}
+			return nil
+		}
+
+		var err error
+		var fromTS int64
+		var toTS int64 = model.GetMillis()
+		if fromStr, ok := job.Data["from"]; ok {
+			if fromTS, err = strconv.ParseInt(fromStr, 10, 64); err != nil {
+				return err
+			}
+			fromTS *= 1000
+		}
+		if toStr, ok := job.Data["to"]; ok {
+			if toTS, err = strconv.ParseInt(toStr, 10, 64); err != nil {
+				return err
+			}
+			toTS *= 1000
+		}
+
+		var nFiles, nTranscoded, nErrs int
+		startTime, startFileID := fromTS, ""
+	loop:
+		for {
+			files, err := store.FileInfo().GetFilesBatchForIndexing(startTime, startFileID, false, batchSize)
+			if err != nil {
+				return err
+			}
+
+			for _, file := range files {
+				if file.CreateAt > toTS {
+					break loop
+				}
+
+				info := file.FileInfo
+				if info.IsVideo() {
+					transcoded, appErr := app.TranscodeMediaFile(rctx, &info)
+					if appErr != nil {
+						logger.Warn("Failed to transcode media file", mlog.String("file_info_id", info.Id), mlog.Err(appErr))
+						nErrs++
+					} else if transcoded {
+						nTranscoded++
+					}
+					nFiles++
+				}
+			}
+
+			if len(files) < batchSize {
+				break
+			}
+			lastFile := files[len(files)-1]
+			startTime, startFileID = lastFile.CreateAt, lastFile.Id
+		}
+
+		job.Data["processed"] = strconv.Itoa(nFiles)
+		job.Data["transcoded"] = strconv.Itoa(nTranscoded)
+		job.Data["errors"] = strconv.Itoa(nErrs)
+
+		if err := jobServer.UpdateInProgressJobData(job); err != nil {
+			logger.Error("Worker: Failed to update job data", mlog.Err(err))
+		}
+		return nil
+	}
+	worker := jobs.NewSimpleWorker(workerName, jobServer, execute, isEnabled)
+	return worker
+}
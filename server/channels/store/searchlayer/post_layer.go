@@ -21,7 +21,7 @@ type SearchPostStore struct {
 }
 
 func (s SearchPostStore) indexPost(rctx request.CTX, post *model.Post) {
-	for _, engine := range s.rootStore.searchEngine.GetActiveEngines() {
+	for _, engine := range s.rootStore.searchEngine.GetActivePostEngines() {
 		if engine.IsIndexingEnabled() {
 			runIndexFn(rctx, engine, func(engineCopy searchengine.SearchEngineInterface) {
 				channel, chanErr := s.rootStore.Channel().Get(post.ChannelId, true)
@@ -39,7 +39,7 @@ func (s SearchPostStore) indexPost(rctx request.CTX, post *model.Post) {
 }
 
 func (s SearchPostStore) deletePostIndex(rctx request.CTX, post *model.Post) {
-	for _, engine := range s.rootStore.searchEngine.GetActiveEngines() {
+	for _, engine := range s.rootStore.searchEngine.GetActivePostEngines() {
 		if engine.IsIndexingEnabled() {
 			runIndexFn(rctx, engine, func(engineCopy searchengine.SearchEngineInterface) {
 				if err := engineCopy.DeletePost(post); err != nil {
@@ -52,7 +52,7 @@ func (s SearchPostStore) deletePostIndex(rctx request.CTX, post *model.Post) {
 }
 
 func (s SearchPostStore) deleteChannelPostsIndex(rctx request.CTX, channelID string) {
-	for _, engine := range s.rootStore.searchEngine.GetActiveEngines() {
+	for _, engine := range s.rootStore.searchEngine.GetActivePostEngines() {
 		if engine.IsIndexingEnabled() {
 			runIndexFn(rctx, engine, func(engineCopy searchengine.SearchEngineInterface) {
 				if err := engineCopy.DeleteChannelPosts(rctx, channelID); err != nil {
@@ -66,7 +66,7 @@ func (s SearchPostStore) deleteChannelPostsIndex(rctx request.CTX, channelID str
 }
 
 func (s SearchPostStore) deleteUserPostsIndex(rctx request.CTX, userID string) {
-	for _, engine := range s.rootStore.searchEngine.GetActiveEngines() {
+	for _, engine := range s.rootStore.searchEngine.GetActivePostEngines() {
 		if engine.IsIndexingEnabled() {
 			runIndexFn(rctx, engine, func(engineCopy searchengine.SearchEngineInterface) {
 				if err := engineCopy.DeleteUserPosts(rctx, userID); err != nil {
@@ -138,6 +138,16 @@ func (s SearchPostStore) PermanentDeleteByChannel(rctx request.CTX, channelID st
 	return err
 }
 
+func (s SearchPostStore) PermanentDeletePosts(rctx request.CTX, posts []*model.Post) error {
+	err := s.PostStore.PermanentDeletePosts(rctx, posts)
+	if err == nil {
+		for _, post := range posts {
+			s.deletePostIndex(rctx, post)
+		}
+	}
+	return err
+}
+
 func (s SearchPostStore) searchPostsForUserByEngine(engine searchengine.SearchEngineInterface, paramsList []*model.SearchParams, userId, teamId string, page, perPage int) (*model.PostSearchResults, error) {
 	if err := model.IsSearchParamsListValid(paramsList); err != nil {
 		return nil, err
@@ -177,6 +187,17 @@ func (s SearchPostStore) searchPostsForUserByEngine(engine searchengine.SearchEn
 }
 
 func (s SearchPostStore) SearchPostsForUser(rctx request.CTX, paramsList []*model.SearchParams, userId, teamId string, page, perPage int) (*model.PostSearchResults, error) {
+	// Semantic searches fall back to the other engines when the semantic engine can't be used.
+	if len(paramsList) > 0 && paramsList[0].Semantic {
+		if engine := s.rootStore.searchEngine.GetSemanticEngine(); engine != nil {
+			results, err := s.searchPostsForUserByEngine(engine, paramsList, userId, teamId, page, perPage)
+			if err == nil {
+				return results, nil
+			}
+			rctx.Logger().Warn("Encountered error on SearchPostsInTeamForUser.", mlog.String("search_engine", engine.GetName()), mlog.Err(err))
+		}
+	}
+
 	for _, engine := range s.rootStore.searchEngine.GetActiveEngines() {
 		if engine.IsSearchEnabled() {
 			results, err := s.searchPostsForUserByEngine(engine, paramsList, userId, teamId, page, perPage)
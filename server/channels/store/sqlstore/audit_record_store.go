@@ -0,0 +1,171 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package sqlstore
+
+import (
+	sq "github.com/mattermost/squirrel"
+	"github.com/pkg/errors"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/v8/channels/store"
+)
+
+type SqlAuditRecordStore struct {
+	*SqlStore
+}
+
+func newSqlAuditRecordStore(sqlStore *SqlStore) store.AuditRecordStore {
+	return &SqlAuditRecordStore{sqlStore}
+}
+
+var auditRecordColumns = []string{
+	"AuditRecords.Id",
+	"AuditRecords.CreateAt",
+	"AuditRecords.EventName",
+	"AuditRecords.Status",
+	"AuditRecords.UserId",
+	"AuditRecords.SessionId",
+	"AuditRecords.Client",
+	"AuditRecords.IpAddress",
+	"AuditRecords.ObjectType",
+	"AuditRecords.ObjectIds",
+	"AuditRecords.Data",
+}
+
+func (s *SqlAuditRecordStore) Save(record *model.AuditRecord) (_ *model.AuditRecord, err error) {
+	record.PreSave()
+	if appErr := record.IsValid(); appErr != nil {
+		return nil, appErr
+	}
+
+	transaction, err := s.GetMasterX().Beginx()
+	if err != nil {
+		return nil, errors.Wrap(err, "begin_transaction")
+	}
+	defer finalizeTransactionX(transaction, &err)
+
+	query := s.getQueryBuilder().
+		Insert("AuditRecords").
+		Columns("Id", "CreateAt", "EventName", "Status", "UserId", "SessionId", "Client", "IpAddress", "ObjectType", "ObjectIds", "Data").
+		Values(record.Id, record.CreateAt, record.EventName, record.Status, record.UserId, record.SessionId, record.Client, record.IpAddress, record.ObjectType, record.ObjectIds, record.Data)
+
+	if _, err = transaction.ExecBuilder(query); err != nil {
+		return nil, errors.Wrapf(err, "failed to save AuditRecord with id=%s", record.Id)
+	}
+
+	if len(record.ObjectIds) > 0 {
+		objectsQuery := s.getQueryBuilder().
+			Insert("AuditRecordObjects").
+			Columns("ObjectId", "RecordId", "CreateAt")
+		seen := make(map[string]bool, len(record.ObjectIds))
+		for _, objectID := range record.ObjectIds {
+			if seen[objectID] {
+				continue
+			}
+			seen[objectID] = true
+			objectsQuery = objectsQuery.Values(objectID, record.Id, record.CreateAt)
+		}
+
+		if _, err = transaction.ExecBuilder(objectsQuery); err != nil {
+			return nil, errors.Wrapf(err, "failed to save AuditRecordObjects for record id=%s", record.Id)
+		}
+	}
+
+	if err = transaction.Commit(); err != nil {
+		return nil, errors.Wrap(err, "commit_transaction")
+	}
+
+	return record, nil
+}
+
+func (s *SqlAuditRecordStore) Search(search *model.AuditRecordSearch) ([]*model.AuditRecord, error) {
+	perPage := search.PerPage
+	if perPage <= 0 {
+		perPage = model.AuditRecordSearchDefaultPerPage
+	}
+
+	query := s.getQueryBuilder().
+		Select(auditRecordColumns...).
+		From("AuditRecords").
+		OrderBy("AuditRecords.CreateAt DESC", "AuditRecords.Id DESC").
+		Limit(uint64(perPage)).
+		Offset(uint64(search.Page * perPage))
+
+	if search.ObjectId != "" {
+		query = query.
+			Join("AuditRecordObjects ON AuditRecordObjects.RecordId = AuditRecords.Id").
+			Where(sq.Eq{"AuditRecordObjects.ObjectId": search.ObjectId})
+	}
+	if search.EventName != "" {
+		query = query.Where(sq.Eq{"AuditRecords.EventName": search.EventName})
+	}
+	if search.UserId != "" {
+		query = query.Where(sq.Eq{"AuditRecords.UserId": search.UserId})
+	}
+	if search.Status != "" {
+		query = query.Where(sq.Eq{"AuditRecords.Status": search.Status})
+	}
+	if search.Since > 0 {
+		query = query.Where(sq.GtOrEq{"AuditRecords.CreateAt": search.Since})
+	}
+	if search.Until > 0 {
+		query = query.Where(sq.LtOrEq{"AuditRecords.CreateAt": search.Until})
+	}
+
+	records := []*model.AuditRecord{}
+	if err := s.GetReplicaX().SelectBuilder(&records, query); err != nil {
+		return nil, errors.Wrap(err, "failed to search AuditRecords")
+	}
+
+	return records, nil
+}
+
+func (s *SqlAuditRecordStore) PermanentDeleteBatch(endTime int64, limit int64) (_ int64, err error) {
+	query := s.getQueryBuilder().
+		Select("Id").
+		From("AuditRecords").
+		Where(sq.Lt{"CreateAt": endTime}).
+		OrderBy("CreateAt").
+		Limit(uint64(limit))
+
+	recordIds := []string{}
+	if err = s.GetMasterX().SelectBuilder(&recordIds, query); err != nil {
+		return 0, errors.Wrapf(err, "failed to get AuditRecords created before %d", endTime)
+	}
+	if len(recordIds) == 0 {
+		return 0, nil
+	}
+
+	transaction, err := s.GetMasterX().Beginx()
+	if err != nil {
+		return 0, errors.Wrap(err, "begin_transaction")
+	}
+	defer finalizeTransactionX(transaction, &err)
+
+	objectsQuery := s.getQueryBuilder().
+		Delete("AuditRecordObjects").
+		Where(sq.Eq{"RecordId": recordIds})
+	if _, err = transaction.ExecBuilder(objectsQuery); err != nil {
+		return 0, errors.Wrap(err, "failed to delete AuditRecordObjects")
+	}
+
+	recordsQuery := s.getQueryBuilder().
+		Delete("AuditRecords").
+		Where(sq.Eq{"Id": recordIds})
+	result, err := transaction.ExecBuilder(recordsQuery)
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to delete AuditRecords")
+	}
+
+	deleted, err := result.RowsAffected()
+	if err != nil {
+		return 0, errors.Wrap(err, "unable to get rows affected")
+	}
+
+	if err = transaction.Commit(); err != nil {
+		return 0, errors.Wrap(err, "commit_transaction")
+	}
+
+	return deleted, nil
+}
@@ -42,11 +42,11 @@ func (s SqlCommandStore) Save(command *model.Command) (*model.Command, error) {
 	// Trigger is a keyword
 	trigger := s.toReserveCase("trigger")
 
-	if _, err := s.GetMasterX().NamedExec(`INSERT INTO Commands (Id, Token, CreateAt,
+	if _, err := s.GetMasterX().NamedExec(`INSERT INTO Commands (Id, Token, SigningSecret, CreateAt,
 		UpdateAt, DeleteAt, CreatorId, TeamId, `+trigger+`, Method, Username,
 		IconURL, AutoComplete, AutoCompleteDesc, AutoCompleteHint, DisplayName, Description,
 		URL, PluginId)
-	VALUES (:Id, :Token, :CreateAt, :UpdateAt, :DeleteAt, :CreatorId, :TeamId, :Trigger, :Method,
+	VALUES (:Id, :Token, :SigningSecret, :CreateAt, :UpdateAt, :DeleteAt, :CreatorId, :TeamId, :Trigger, :Method,
 		:Username, :IconURL, :AutoComplete, :AutoCompleteDesc, :AutoCompleteHint, :DisplayName,
 		:Description, :URL, :PluginId)`, command); err != nil {
 		return nil, errors.Wrapf(err, "insert: command_id=%s", command.Id)
@@ -168,6 +168,7 @@ func (s SqlCommandStore) Update(cmd *model.Command) (*model.Command, error) {
 	query := s.getQueryBuilder().
 		Update("Commands").
 		Set("Token", cmd.Token).
+		Set("SigningSecret", cmd.SigningSecret).
 		Set("CreateAt", cmd.CreateAt).
 		Set("UpdateAt", cmd.UpdateAt).
 		Set("CreatorId", cmd.CreatorId).
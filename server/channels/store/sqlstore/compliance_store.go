@@ -157,6 +157,7 @@ func (s SqlComplianceStore) ComplianceExport(job *model.Compliance, cursor model
 			Channels.Name AS ChannelName,
 			Channels.DisplayName AS ChannelDisplayName,
 			Channels.Type AS ChannelType,
+			Channels.PostExpiryMinutes AS ChannelPostExpiryMinutes,
 			Users.Username AS UserUsername,
 			Users.Email AS UserEmail,
 			Users.Nickname AS UserNickname,
@@ -164,6 +165,7 @@ func (s SqlComplianceStore) ComplianceExport(job *model.Compliance, cursor model
 			Posts.CreateAt AS PostCreateAt,
 			Posts.UpdateAt AS PostUpdateAt,
 			Posts.DeleteAt AS PostDeleteAt,
+			COALESCE(ExpiringPosts.ExpireAt, 0) AS PostExpireAt,
 			Posts.RootId AS PostRootId,
 			Posts.OriginalId AS PostOriginalId,
 			Posts.Message AS PostMessage,
@@ -179,6 +181,8 @@ func (s SqlComplianceStore) ComplianceExport(job *model.Compliance, cursor model
 			Posts
 		LEFT JOIN
 			Bots ON Bots.UserId = Posts.UserId
+		LEFT JOIN
+			ExpiringPosts ON ExpiringPosts.PostId = Posts.Id
 		WHERE
 			Teams.Id = Channels.TeamId
 				AND Posts.ChannelId = Channels.Id
@@ -222,6 +226,7 @@ func (s SqlComplianceStore) ComplianceExport(job *model.Compliance, cursor model
 			Channels.Name AS ChannelName,
 			Channels.DisplayName AS ChannelDisplayName,
 			Channels.Type AS ChannelType,
+			Channels.PostExpiryMinutes AS ChannelPostExpiryMinutes,
 			Users.Username AS UserUsername,
 			Users.Email AS UserEmail,
 			Users.Nickname AS UserNickname,
@@ -229,6 +234,7 @@ func (s SqlComplianceStore) ComplianceExport(job *model.Compliance, cursor model
 			Posts.CreateAt AS PostCreateAt,
 			Posts.UpdateAt AS PostUpdateAt,
 			Posts.DeleteAt AS PostDeleteAt,
+			COALESCE(ExpiringPosts.ExpireAt, 0) AS PostExpireAt,
 			Posts.RootId AS PostRootId,
 			Posts.OriginalId AS PostOriginalId,
 			Posts.Message AS PostMessage,
@@ -243,6 +249,8 @@ func (s SqlComplianceStore) ComplianceExport(job *model.Compliance, cursor model
 			Posts
 		LEFT JOIN
 			Bots ON Bots.UserId = Posts.UserId
+		LEFT JOIN
+			ExpiringPosts ON ExpiringPosts.PostId = Posts.Id
 		WHERE
 			Channels.TeamId = ''
 				AND Posts.ChannelId = Channels.Id
@@ -280,6 +288,7 @@ func (s SqlComplianceStore) MessageExport(c request.CTX, cursor model.MessageExp
 			Posts.CreateAt AS PostCreateAt,
 			Posts.UpdateAt AS PostUpdateAt,
 			Posts.DeleteAt AS PostDeleteAt,
+			ExpiringPosts.ExpireAt AS PostExpireAt,
 			Posts.Message AS PostMessage,
 			Posts.Type AS PostType,
 			Posts.Props AS PostProps,
@@ -297,6 +306,7 @@ func (s SqlComplianceStore) MessageExport(c request.CTX, cursor model.MessageExp
 			END AS ChannelDisplayName,
 			Channels.Name AS ChannelName,
 			Channels.Type AS ChannelType,
+			Channels.PostExpiryMinutes AS ChannelPostExpiryMinutes,
 			Users.Id AS UserId,
 			Users.Email AS UserEmail,
 			Users.Username,
@@ -307,6 +317,7 @@ func (s SqlComplianceStore) MessageExport(c request.CTX, cursor model.MessageExp
 		LEFT OUTER JOIN Teams ON Channels.TeamId = Teams.Id
 		LEFT OUTER JOIN Users ON Posts.UserId = Users.Id
 		LEFT JOIN Bots ON Bots.UserId = Posts.UserId
+		LEFT JOIN ExpiringPosts ON ExpiringPosts.PostId = Posts.Id
 		WHERE (
 			Posts.UpdateAt > ?
 			OR (
@@ -0,0 +1,69 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package sqlstore
+
+import (
+	sq "github.com/mattermost/squirrel"
+	"github.com/pkg/errors"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/v8/channels/store"
+)
+
+type SqlExpiringPostStore struct {
+	*SqlStore
+}
+
+func newSqlExpiringPostStore(sqlStore *SqlStore) store.ExpiringPostStore {
+	return &SqlExpiringPostStore{sqlStore}
+}
+
+func (s *SqlExpiringPostStore) Save(expiringPost *model.ExpiringPost) (*model.ExpiringPost, error) {
+	if appErr := expiringPost.IsValid(); appErr != nil {
+		return nil, appErr
+	}
+
+	query := s.getQueryBuilder().
+		Insert("ExpiringPosts").
+		Columns("PostId", "ChannelId", "ExpireAt").
+		Values(expiringPost.PostId, expiringPost.ChannelId, expiringPost.ExpireAt)
+
+	if _, err := s.GetMasterX().ExecBuilder(query); err != nil {
+		return nil, errors.Wrapf(err, "failed to save ExpiringPost with postId=%s", expiringPost.PostId)
+	}
+
+	return expiringPost, nil
+}
+
+func (s *SqlExpiringPostStore) GetExpired(now int64, limit int) ([]*model.ExpiringPost, error) {
+	query := s.getQueryBuilder().
+		Select("PostId", "ChannelId", "ExpireAt").
+		From("ExpiringPosts").
+		Where(sq.LtOrEq{"ExpireAt": now}).
+		OrderBy("ExpireAt ASC", "PostId ASC").
+		Limit(uint64(limit))
+
+	expiringPosts := []*model.ExpiringPost{}
+	if err := s.GetMasterX().SelectBuilder(&expiringPosts, query); err != nil {
+		return nil, errors.Wrap(err, "failed to get expired ExpiringPosts")
+	}
+
+	return expiringPosts, nil
+}
+
+func (s *SqlExpiringPostStore) DeleteForPosts(postIDs []string) error {
+	if len(postIDs) == 0 {
+		return nil
+	}
+
+	query := s.getQueryBuilder().
+		Delete("ExpiringPosts").
+		Where(sq.Eq{"PostId": postIDs})
+
+	if _, err := s.GetMasterX().ExecBuilder(query); err != nil {
+		return errors.Wrap(err, "failed to delete ExpiringPosts")
+	}
+
+	return nil
+}
@@ -0,0 +1,246 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package sqlstore
+
+import (
+	"database/sql"
+
+	sq "github.com/mattermost/squirrel"
+	"github.com/pkg/errors"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/v8/channels/store"
+)
+
+type SqlFileBlobStore struct {
+	*SqlStore
+}
+
+func newSqlFileBlobStore(sqlStore *SqlStore) store.FileBlobStore {
+	return &SqlFileBlobStore{sqlStore}
+}
+
+var fileBlobColumns = []string{
+	"Hash",
+	"Path",
+	"Size",
+	"RefCount",
+	"CreateAt",
+	"UpdateAt",
+}
+
+func (s *SqlFileBlobStore) Acquire(hash, path string, size int64) (*model.FileBlob, bool, error) {
+	acquired, err := s.addReference(hash)
+	if err != nil {
+		return nil, false, err
+	}
+
+	if !acquired {
+		now := model.GetMillis()
+		blob := &model.FileBlob{
+			Hash:     hash,
+			Path:     path,
+			Size:     size,
+			RefCount: 1,
+			CreateAt: now,
+			UpdateAt: now,
+		}
+		if appErr := blob.IsValid(); appErr != nil {
+			return nil, false, appErr
+		}
+
+		query := s.getQueryBuilder().
+			Insert("FileBlobs").
+			Columns(fileBlobColumns...).
+			Values(blob.Hash, blob.Path, blob.Size, blob.RefCount, blob.CreateAt, blob.UpdateAt)
+
+		_, err = s.GetMasterX().ExecBuilder(query)
+		if err == nil {
+			return blob, true, nil
+		}
+		if !IsUniqueConstraintError(err, []string{"PRIMARY", "fileblobs_pkey"}) {
+			return nil, false, errors.Wrapf(err, "failed to save FileBlob with hash=%s", hash)
+		}
+
+		// The same contents were uploaded concurrently, reference that blob instead. The blob
+		// may also be tombstoned, in which case it can't be referenced until it is deleted.
+		if acquired, err = s.addReference(hash); err != nil {
+			return nil, false, err
+		} else if !acquired {
+			return nil, false, store.NewErrNotFound("FileBlob", hash)
+		}
+	}
+
+	blob, err := s.get(hash, true)
+	if err != nil {
+		return nil, false, err
+	}
+
+	return blob, false, nil
+}
+
+func (s *SqlFileBlobStore) addReference(hash string) (bool, error) {
+	query := s.getQueryBuilder().
+		Update("FileBlobs").
+		Set("RefCount", sq.Expr("RefCount + 1")).
+		Set("UpdateAt", model.GetMillis()).
+		Where(sq.Eq{"Hash": hash}).
+		Where(sq.GtOrEq{"RefCount": 0})
+
+	result, err := s.GetMasterX().ExecBuilder(query)
+	if err != nil {
+		return false, errors.Wrapf(err, "failed to update FileBlob with hash=%s", hash)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, errors.Wrap(err, "unable to retrieve rows affected")
+	}
+
+	return rowsAffected > 0, nil
+}
+
+func (s *SqlFileBlobStore) Release(hash string) (_ int64, err error) {
+	transaction, err := s.GetMasterX().Beginx()
+	if err != nil {
+		return 0, errors.Wrap(err, "begin_transaction")
+	}
+	defer finalizeTransactionX(transaction, &err)
+
+	if err = releaseFileBlobsT(transaction, []string{hash}); err != nil {
+		return 0, err
+	}
+
+	var refCount int64
+	query := s.getQueryBuilder().
+		Select("RefCount").
+		From("FileBlobs").
+		Where(sq.Eq{"Hash": hash})
+	if err = transaction.GetBuilder(&refCount, query); err != nil {
+		if err == sql.ErrNoRows {
+			return 0, store.NewErrNotFound("FileBlob", hash)
+		}
+		return 0, errors.Wrapf(err, "failed to get FileBlob with hash=%s", hash)
+	}
+
+	if err = transaction.Commit(); err != nil {
+		return 0, errors.Wrap(err, "commit_transaction")
+	}
+
+	return refCount, nil
+}
+
+// releaseFileBlobsT removes one reference from the blob of each of the given hashes. A hash
+// appearing multiple times is released as many times. Tombstoned blobs are left as they are.
+func releaseFileBlobsT(transaction *sqlxTxWrapper, hashes []string) error {
+	counts := make(map[string]int64)
+	for _, hash := range hashes {
+		if hash != "" {
+			counts[hash]++
+		}
+	}
+
+	now := model.GetMillis()
+	for hash, count := range counts {
+		if _, err := transaction.Exec(
+			`UPDATE FileBlobs SET RefCount = CASE WHEN RefCount > ? THEN RefCount - ? ELSE 0 END, UpdateAt = ? WHERE Hash = ? AND RefCount > 0`,
+			count, count, now, hash,
+		); err != nil {
+			return errors.Wrapf(err, "failed to release FileBlob with hash=%s", hash)
+		}
+	}
+
+	return nil
+}
+
+func (s *SqlFileBlobStore) Get(hash string) (*model.FileBlob, error) {
+	return s.get(hash, false)
+}
+
+func (s *SqlFileBlobStore) get(hash string, fromMaster bool) (*model.FileBlob, error) {
+	query := s.getQueryBuilder().
+		Select(fileBlobColumns...).
+		From("FileBlobs").
+		Where(sq.Eq{"Hash": hash})
+
+	db := s.GetReplicaX()
+	if fromMaster {
+		db = s.GetMasterX()
+	}
+
+	var blob model.FileBlob
+	if err := db.GetBuilder(&blob, query); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, store.NewErrNotFound("FileBlob", hash)
+		}
+		return nil, errors.Wrapf(err, "failed to get FileBlob with hash=%s", hash)
+	}
+
+	return &blob, nil
+}
+
+func (s *SqlFileBlobStore) GetUnreferenced(afterHash string, limit int) ([]*model.FileBlob, error) {
+	query := s.getQueryBuilder().
+		Select(fileBlobColumns...).
+		From("FileBlobs").
+		Where(sq.LtOrEq{"RefCount": 0}).
+		Where(sq.Gt{"Hash": afterHash}).
+		OrderBy("Hash ASC").
+		Limit(uint64(limit))
+
+	blobs := []*model.FileBlob{}
+	if err := s.GetMasterX().SelectBuilder(&blobs, query); err != nil {
+		return nil, errors.Wrap(err, "failed to find unreferenced FileBlobs")
+	}
+
+	return blobs, nil
+}
+
+func (s *SqlFileBlobStore) Tombstone(hash string) (bool, error) {
+	query := s.getQueryBuilder().
+		Update("FileBlobs").
+		Set("RefCount", model.FileBlobTombstoneRefCount).
+		Set("UpdateAt", model.GetMillis()).
+		Where(sq.Eq{"Hash": hash}).
+		Where(sq.LtOrEq{"RefCount": 0})
+
+	result, err := s.GetMasterX().ExecBuilder(query)
+	if err != nil {
+		return false, errors.Wrapf(err, "failed to tombstone FileBlob with hash=%s", hash)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, errors.Wrap(err, "unable to retrieve rows affected")
+	}
+
+	return rowsAffected > 0, nil
+}
+
+func (s *SqlFileBlobStore) DeleteTombstoned(hash string) error {
+	query := s.getQueryBuilder().
+		Delete("FileBlobs").
+		Where(sq.Eq{"Hash": hash}).
+		Where(sq.Eq{"RefCount": model.FileBlobTombstoneRefCount})
+
+	if _, err := s.GetMasterX().ExecBuilder(query); err != nil {
+		return errors.Wrapf(err, "failed to delete FileBlob with hash=%s", hash)
+	}
+
+	return nil
+}
+
+func (s *SqlFileBlobStore) GetSavedBytes() (int64, error) {
+	query := s.getQueryBuilder().
+		Select("COALESCE(SUM((RefCount - 1) * Size), 0)").
+		From("FileBlobs").
+		Where(sq.Gt{"RefCount": 1})
+
+	var saved int64
+	if err := s.GetReplicaX().GetBuilder(&saved, query); err != nil {
+		return 0, errors.Wrap(err, "failed to get saved bytes of FileBlobs")
+	}
+
+	return saved, nil
+}
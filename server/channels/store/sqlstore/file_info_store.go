@@ -42,6 +42,13 @@ type fileInfoWithChannelID struct {
 	Content         string
 	RemoteId        *string
 	Archived        bool
+	ContentHash     string
+	ScanStatus      string
+	ScanResult      string
+	Duration        int64
+	VideoCodec      string
+	AudioCodec      string
+	RenditionPath   string
 }
 
 func (fi fileInfoWithChannelID) ToModel() *model.FileInfo {
@@ -66,6 +73,13 @@ func (fi fileInfoWithChannelID) ToModel() *model.FileInfo {
 		MiniPreview:     fi.MiniPreview,
 		Content:         fi.Content,
 		RemoteId:        fi.RemoteId,
+		ContentHash:     fi.ContentHash,
+		ScanStatus:      fi.ScanStatus,
+		ScanResult:      fi.ScanResult,
+		Duration:        fi.Duration,
+		VideoCodec:      fi.VideoCodec,
+		AudioCodec:      fi.AudioCodec,
+		RenditionPath:   fi.RenditionPath,
 	}
 }
 
@@ -106,6 +120,13 @@ func newSqlFileInfoStore(sqlStore *SqlStore, metrics einterfaces.MetricsInterfac
 		"Coalesce(FileInfo.Content, '') AS Content",
 		"Coalesce(FileInfo.RemoteId, '') AS RemoteId",
 		"FileInfo.Archived",
+		"FileInfo.ContentHash",
+		"FileInfo.ScanStatus",
+		"FileInfo.ScanResult",
+		"FileInfo.Duration",
+		"FileInfo.VideoCodec",
+		"FileInfo.AudioCodec",
+		"FileInfo.RenditionPath",
 	}
 
 	return s
@@ -120,10 +141,12 @@ func (fs SqlFileInfoStore) Save(rctx request.CTX, info *model.FileInfo) (*model.
 	query := `
 		INSERT INTO FileInfo
 		(Id, CreatorId, PostId, ChannelId, CreateAt, UpdateAt, DeleteAt, Path, ThumbnailPath, PreviewPath,
-			Name, Extension, Size, MimeType, Width, Height, HasPreviewImage, MiniPreview, Content, RemoteId)
+			Name, Extension, Size, MimeType, Width, Height, HasPreviewImage, MiniPreview, Content, RemoteId, ContentHash,
+			ScanStatus, ScanResult, Duration, VideoCodec, AudioCodec, RenditionPath)
 		VALUES
 		(:Id, :CreatorId, :PostId, :ChannelId, :CreateAt, :UpdateAt, :DeleteAt, :Path, :ThumbnailPath, :PreviewPath,
-			:Name, :Extension, :Size, :MimeType, :Width, :Height, :HasPreviewImage, :MiniPreview, :Content, :RemoteId)
+			:Name, :Extension, :Size, :MimeType, :Width, :Height, :HasPreviewImage, :MiniPreview, :Content, :RemoteId, :ContentHash,
+			:ScanStatus, :ScanResult, :Duration, :VideoCodec, :AudioCodec, :RenditionPath)
 	`
 
 	if _, err := fs.GetMasterX().NamedExec(query, info); err != nil {
@@ -168,6 +191,9 @@ func (fs SqlFileInfoStore) Upsert(rctx request.CTX, info *model.FileInfo) (*mode
 
 	// PostID and ChannelID are deliberately ignored
 	// from the list of fields to keep those two immutable.
+	// ContentHash is reference counted and only changed through SetContentHash.
+	// ScanStatus and ScanResult are only changed through SetScanStatus.
+	// RenditionPath is only changed through SetRenditionPath.
 	queryString, args, err := fs.getQueryBuilder().
 		Update("FileInfo").
 		SetMap(map[string]any{
@@ -186,6 +212,9 @@ func (fs SqlFileInfoStore) Upsert(rctx request.CTX, info *model.FileInfo) (*mode
 			"MiniPreview":     info.MiniPreview,
 			"Content":         info.Content,
 			"RemoteId":        info.RemoteId,
+			"Duration":        info.Duration,
+			"VideoCodec":      info.VideoCodec,
+			"AudioCodec":      info.AudioCodec,
 		}).
 		Where(sq.Eq{"Id": info.Id}).
 		ToSql()
@@ -274,6 +303,10 @@ func (fs SqlFileInfoStore) GetWithOptions(page, perPage int, opt *model.GetFileI
 		query = query.Where(sq.GtOrEq{"FileInfo.CreateAt": opt.Since})
 	}
 
+	if len(opt.ScanStatuses) > 0 {
+		query = query.Where(sq.Eq{"FileInfo.ScanStatus": opt.ScanStatuses})
+	}
+
 	if !opt.IncludeDeleted {
 		query = query.Where("FileInfo.DeleteAt = 0")
 	}
@@ -335,6 +368,25 @@ func (fs SqlFileInfoStore) GetByPath(path string) (*model.FileInfo, error) {
 	return info, nil
 }
 
+func (fs SqlFileInfoStore) CountByPath(path string) (int64, error) {
+	query := fs.getQueryBuilder().
+		Select("COUNT(*)").
+		From("FileInfo").
+		Where(sq.Or{
+			sq.Eq{"Path": path},
+			sq.Eq{"ThumbnailPath": path},
+			sq.Eq{"PreviewPath": path},
+			sq.Eq{"RenditionPath": path},
+		})
+
+	var count int64
+	if err := fs.GetMasterX().GetBuilder(&count, query); err != nil {
+		return 0, errors.Wrapf(err, "failed to count FileInfos with path=%s", path)
+	}
+
+	return count, nil
+}
+
 func (fs SqlFileInfoStore) InvalidateFileInfosForPostCache(postId string, deleted bool) {
 }
 
@@ -442,6 +494,107 @@ func (fs SqlFileInfoStore) SetContent(rctx request.CTX, fileId, content string)
 	return nil
 }
 
+func (fs SqlFileInfoStore) SetContentHash(rctx request.CTX, fileId, contentHash, path string) error {
+	query := fs.getQueryBuilder().
+		Update("FileInfo").
+		Set("ContentHash", contentHash).
+		Set("Path", path).
+		Where(sq.Eq{"Id": fileId, "ContentHash": ""})
+
+	result, err := fs.GetMasterX().ExecBuilder(query)
+	if err != nil {
+		return errors.Wrapf(err, "failed to update FileInfo content hash with id=%s", fileId)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return errors.Wrap(err, "unable to retrieve rows affected")
+	}
+	if rowsAffected == 0 {
+		return store.NewErrNotFound("FileInfo", fileId)
+	}
+
+	return nil
+}
+
+func (fs SqlFileInfoStore) SetScanStatus(rctx request.CTX, fileId, status, result string) error {
+	query := fs.getQueryBuilder().
+		Update("FileInfo").
+		Set("ScanStatus", status).
+		Set("ScanResult", result).
+		Set("UpdateAt", model.GetMillis()).
+		Where(sq.Eq{"Id": fileId})
+
+	res, err := fs.GetMasterX().ExecBuilder(query)
+	if err != nil {
+		return errors.Wrapf(err, "failed to update FileInfo scan status with id=%s", fileId)
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return errors.Wrap(err, "unable to retrieve rows affected")
+	}
+	if rowsAffected == 0 {
+		return store.NewErrNotFound("FileInfo", fileId)
+	}
+
+	return nil
+}
+
+func (fs SqlFileInfoStore) SetRenditionPath(rctx request.CTX, fileId, path string) error {
+	query := fs.getQueryBuilder().
+		Update("FileInfo").
+		Set("RenditionPath", path).
+		Set("UpdateAt", model.GetMillis()).
+		Where(sq.Eq{"Id": fileId})
+
+	res, err := fs.GetMasterX().ExecBuilder(query)
+	if err != nil {
+		return errors.Wrapf(err, "failed to update FileInfo rendition path with id=%s", fileId)
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return errors.Wrap(err, "unable to retrieve rows affected")
+	}
+	if rowsAffected == 0 {
+		return store.NewErrNotFound("FileInfo", fileId)
+	}
+
+	return nil
+}
+
+func (fs SqlFileInfoStore) SetMediaPreviews(rctx request.CTX, info *model.FileInfo) error {
+	query := fs.getQueryBuilder().
+		Update("FileInfo").
+		Set("Duration", info.Duration).
+		Set("VideoCodec", info.VideoCodec).
+		Set("AudioCodec", info.AudioCodec).
+		Set("Width", info.Width).
+		Set("Height", info.Height).
+		Set("ThumbnailPath", info.ThumbnailPath).
+		Set("PreviewPath", info.PreviewPath).
+		Set("HasPreviewImage", info.HasPreviewImage).
+		Set("MiniPreview", info.MiniPreview).
+		Set("UpdateAt", model.GetMillis()).
+		Where(sq.Eq{"Id": info.Id})
+
+	res, err := fs.GetMasterX().ExecBuilder(query)
+	if err != nil {
+		return errors.Wrapf(err, "failed to update FileInfo media previews with id=%s", info.Id)
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return errors.Wrap(err, "unable to retrieve rows affected")
+	}
+	if rowsAffected == 0 {
+		return store.NewErrNotFound("FileInfo", info.Id)
+	}
+
+	return nil
+}
+
 func (fs SqlFileInfoStore) DeleteForPost(rctx request.CTX, postId string) (string, error) {
 	if _, err := fs.GetMasterX().Exec(
 		`UPDATE
@@ -456,47 +609,110 @@ func (fs SqlFileInfoStore) DeleteForPost(rctx request.CTX, postId string) (strin
 }
 
 func (fs SqlFileInfoStore) PermanentDelete(rctx request.CTX, fileId string) error {
-	if _, err := fs.GetMasterX().Exec(`DELETE FROM FileInfo WHERE Id = ?`, fileId); err != nil {
+	if _, err := fs.permanentDeleteWhere(sq.Eq{"Id": fileId}, 0); err != nil {
 		return errors.Wrapf(err, "failed to delete FileInfo with id=%s", fileId)
 	}
 	return nil
 }
 
 func (fs SqlFileInfoStore) PermanentDeleteBatch(rctx request.CTX, endTime int64, limit int64) (int64, error) {
-	var query string
-	if fs.DriverName() == "postgres" {
-		query = "DELETE from FileInfo WHERE Id = any (array (SELECT Id FROM FileInfo WHERE CreateAt < ? AND CreatorId != ? LIMIT ?))"
-	} else {
-		query = "DELETE from FileInfo WHERE CreateAt < ? AND CreatorId != ? LIMIT ?"
-	}
-
-	sqlResult, err := fs.GetMasterX().Exec(query, endTime, model.BookmarkFileOwner, limit)
+	rowsAffected, err := fs.permanentDeleteWhere(sq.And{
+		sq.Lt{"CreateAt": endTime},
+		sq.NotEq{"CreatorId": model.BookmarkFileOwner},
+	}, uint64(limit))
 	if err != nil {
 		return 0, errors.Wrap(err, "failed to delete FileInfos in batch")
 	}
 
-	rowsAffected, err := sqlResult.RowsAffected()
-	if err != nil {
-		return 0, errors.Wrapf(err, "unable to retrieve rows affected")
-	}
-
 	return rowsAffected, nil
 }
 
 func (fs SqlFileInfoStore) PermanentDeleteByUser(rctx request.CTX, userId string) (int64, error) {
-	query := "DELETE from FileInfo WHERE CreatorId = ?"
+	const batchSize = 1000
 
-	sqlResult, err := fs.GetMasterX().Exec(query, userId)
-	if err != nil {
-		return 0, errors.Wrapf(err, "failed to delete FileInfo with creatorId=%s", userId)
+	var total int64
+	for {
+		rowsAffected, err := fs.permanentDeleteWhere(sq.Eq{"CreatorId": userId}, batchSize)
+		if err != nil {
+			return total, errors.Wrapf(err, "failed to delete FileInfo with creatorId=%s", userId)
+		}
+
+		total += rowsAffected
+		if rowsAffected < batchSize {
+			return total, nil
+		}
 	}
+}
 
-	rowsAffected, err := sqlResult.RowsAffected()
+// permanentDeleteWhere deletes up to limit file infos matching the condition, or all of
+// them if limit is 0, and releases their references to deduplicated file blobs in the
+// same transaction. Only the file infos actually deleted release their references, so that
+// the file infos deleted concurrently release them once.
+func (fs SqlFileInfoStore) permanentDeleteWhere(where sq.Sqlizer, limit uint64) (_ int64, err error) {
+	transaction, err := fs.GetMasterX().Beginx()
 	if err != nil {
-		return 0, errors.Wrapf(err, "unable to retrieve rows affected")
+		return 0, errors.Wrap(err, "begin_transaction")
 	}
+	defer finalizeTransactionX(transaction, &err)
 
-	return rowsAffected, nil
+	var rows []struct {
+		Id          string
+		ContentHash string
+	}
+	if fs.DriverName() == model.DatabaseDriverPostgres {
+		selectQuery := sq.Select("Id").From("FileInfo").Where(where)
+		if limit > 0 {
+			selectQuery = selectQuery.Limit(limit)
+		}
+		query := fs.getQueryBuilder().
+			Delete("FileInfo").
+			Where(sq.Expr("Id IN (?)", selectQuery)).
+			Suffix("RETURNING Id, ContentHash")
+		if err = transaction.SelectBuilder(&rows, query); err != nil {
+			return 0, errors.Wrap(err, "failed to delete FileInfos")
+		}
+	} else {
+		// MySQL doesn't support the RETURNING clause, so the file infos are locked until they
+		// are deleted.
+		query := fs.getQueryBuilder().
+			Select("Id", "ContentHash").
+			From("FileInfo").
+			Where(where).
+			Suffix("FOR UPDATE")
+		if limit > 0 {
+			query = query.Limit(limit)
+		}
+		if err = transaction.SelectBuilder(&rows, query); err != nil {
+			return 0, errors.Wrap(err, "failed to find FileInfos to delete")
+		}
+		if len(rows) == 0 {
+			return 0, nil
+		}
+
+		ids := make([]string, 0, len(rows))
+		for _, row := range rows {
+			ids = append(ids, row.Id)
+		}
+		if _, err = transaction.ExecBuilder(fs.getQueryBuilder().
+			Delete("FileInfo").
+			Where(sq.Eq{"Id": ids})); err != nil {
+			return 0, errors.Wrap(err, "failed to delete FileInfos")
+		}
+	}
+
+	hashes := make([]string, 0, len(rows))
+	for _, row := range rows {
+		hashes = append(hashes, row.ContentHash)
+	}
+	if err = releaseFileBlobsT(transaction, hashes); err != nil {
+		return 0, err
+	}
+
+	if err = transaction.Commit(); err != nil {
+		return 0, errors.Wrap(err, "commit_transaction")
+	}
+
+	return int64(len(rows)), nil
 }
 
 func (fs SqlFileInfoStore) Search(rctx request.CTX, paramsList []*model.SearchParams, userId, teamId string, page, perPage int) (*model.FileInfoList, error) {
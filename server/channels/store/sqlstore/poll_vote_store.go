@@ -0,0 +1,121 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package sqlstore
+
+import (
+	sq "github.com/mattermost/squirrel"
+	"github.com/pkg/errors"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/v8/channels/store"
+)
+
+type SqlPollVoteStore struct {
+	*SqlStore
+}
+
+func newSqlPollVoteStore(sqlStore *SqlStore) store.PollVoteStore {
+	return &SqlPollVoteStore{sqlStore}
+}
+
+func (s *SqlPollVoteStore) GetForPost(postID string) ([]*model.PollVote, error) {
+	query := s.getQueryBuilder().
+		Select("PostId", "UserId", "OptionId", "CreateAt").
+		From("PollVotes").
+		Where(sq.Eq{"PostId": postID}).
+		OrderBy("CreateAt ASC", "UserId ASC", "OptionId ASC")
+
+	votes := []*model.PollVote{}
+	if err := s.GetReplicaX().SelectBuilder(&votes, query); err != nil {
+		return nil, errors.Wrapf(err, "failed to get PollVotes for postId=%s", postID)
+	}
+
+	return votes, nil
+}
+
+func (s *SqlPollVoteStore) GetForPosts(postIDs []string) ([]*model.PollVote, error) {
+	votes := []*model.PollVote{}
+	if len(postIDs) == 0 {
+		return votes, nil
+	}
+
+	query := s.getQueryBuilder().
+		Select("PostId", "UserId", "OptionId", "CreateAt").
+		From("PollVotes").
+		Where(sq.Eq{"PostId": postIDs}).
+		OrderBy("CreateAt ASC", "UserId ASC", "OptionId ASC")
+
+	if err := s.GetReplicaX().SelectBuilder(&votes, query); err != nil {
+		return nil, errors.Wrap(err, "failed to get PollVotes for posts")
+	}
+
+	return votes, nil
+}
+
+func (s *SqlPollVoteStore) DeleteForPost(postID string) error {
+	query := s.getQueryBuilder().
+		Delete("PollVotes").
+		Where(sq.Eq{"PostId": postID})
+
+	if _, err := s.GetMasterX().ExecBuilder(query); err != nil {
+		return errors.Wrapf(err, "failed to delete PollVotes for postId=%s", postID)
+	}
+
+	return nil
+}
+
+func (s *SqlPollVoteStore) SaveForUser(postID, userID string, optionIDs []string) (_ []*model.PollVote, err error) {
+	createAt := model.GetMillis()
+	votes := make([]*model.PollVote, 0, len(optionIDs))
+	for _, optionID := range optionIDs {
+		vote := &model.PollVote{
+			PostId:   postID,
+			UserId:   userID,
+			OptionId: optionID,
+			CreateAt: createAt,
+		}
+		if appErr := vote.IsValid(); appErr != nil {
+			return nil, appErr
+		}
+		votes = append(votes, vote)
+	}
+
+	transaction, err := s.GetMasterX().Beginx()
+	if err != nil {
+		return nil, errors.Wrap(err, "begin_transaction")
+	}
+	defer finalizeTransactionX(transaction, &err)
+
+	deleteQuery := s.getQueryBuilder().
+		Delete("PollVotes").
+		Where(sq.Eq{"PostId": postID, "UserId": userID})
+
+	if _, err = transaction.ExecBuilder(deleteQuery); err != nil {
+		return nil, errors.Wrapf(err, "failed to delete PollVotes for postId=%s and userId=%s", postID, userID)
+	}
+
+	if len(votes) > 0 {
+		insertQuery := s.getQueryBuilder().
+			Insert("PollVotes").
+			Columns("PostId", "UserId", "OptionId", "CreateAt")
+		for _, vote := range votes {
+			insertQuery = insertQuery.Values(vote.PostId, vote.UserId, vote.OptionId, vote.CreateAt)
+		}
+
+		if _, err = transaction.ExecBuilder(insertQuery); err != nil {
+			return nil, errors.Wrapf(err, "failed to save PollVotes for postId=%s and userId=%s", postID, userID)
+		}
+	}
+
+	// Bump the post so that clients fetching posts since a given time pick up the new tallies.
+	if err = updatePost(transaction, postID); err != nil {
+		return nil, errors.Wrapf(err, "failed to update Post with id=%s", postID)
+	}
+
+	if err = transaction.Commit(); err != nil {
+		return nil, errors.Wrap(err, "commit_transaction")
+	}
+
+	return votes, nil
+}
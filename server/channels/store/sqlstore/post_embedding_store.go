@@ -0,0 +1,188 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package sqlstore
+
+import (
+	"encoding/binary"
+	"math"
+
+	sq "github.com/mattermost/squirrel"
+	"github.com/pkg/errors"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/v8/channels/store"
+)
+
+type SqlPostEmbeddingStore struct {
+	*SqlStore
+}
+
+func newSqlPostEmbeddingStore(sqlStore *SqlStore) store.PostEmbeddingStore {
+	return &SqlPostEmbeddingStore{sqlStore}
+}
+
+var postEmbeddingColumns = []string{
+	"PostId",
+	"ChannelId",
+	"UserId",
+	"CreateAt",
+	"Provider",
+	"Embedding",
+	"UpdateAt",
+}
+
+// encodeEmbedding encodes an embedding as the little endian representation of its values.
+func encodeEmbedding(embedding []float32) []byte {
+	data := make([]byte, 4*len(embedding))
+	for i, value := range embedding {
+		binary.LittleEndian.PutUint32(data[4*i:], math.Float32bits(value))
+	}
+	return data
+}
+
+func decodeEmbedding(data []byte) ([]float32, error) {
+	if len(data)%4 != 0 {
+		return nil, errors.Errorf("invalid embedding of %d bytes", len(data))
+	}
+
+	embedding := make([]float32, len(data)/4)
+	for i := range embedding {
+		embedding[i] = math.Float32frombits(binary.LittleEndian.Uint32(data[4*i:]))
+	}
+	return embedding, nil
+}
+
+func (s *SqlPostEmbeddingStore) Save(embedding *model.PostEmbedding) error {
+	embedding.PreSave()
+	if err := embedding.IsValid(); err != nil {
+		return err
+	}
+
+	data := encodeEmbedding(embedding.Embedding)
+	query := s.getQueryBuilder().
+		Insert("PostEmbeddings").
+		Columns(postEmbeddingColumns...).
+		Values(embedding.PostId, embedding.ChannelId, embedding.UserId, embedding.CreateAt, embedding.Provider, data, embedding.UpdateAt)
+
+	if s.DriverName() == model.DatabaseDriverMysql {
+		query = query.SuffixExpr(sq.Expr("ON DUPLICATE KEY UPDATE ChannelId = ?, Provider = ?, Embedding = ?, UpdateAt = ?", embedding.ChannelId, embedding.Provider, data, embedding.UpdateAt))
+	} else {
+		query = query.SuffixExpr(sq.Expr("ON CONFLICT (PostId) DO UPDATE SET ChannelId = ?, Provider = ?, Embedding = ?, UpdateAt = ?", embedding.ChannelId, embedding.Provider, data, embedding.UpdateAt))
+	}
+
+	if _, err := s.GetMasterX().ExecBuilder(query); err != nil {
+		return errors.Wrapf(err, "failed to save PostEmbedding with postId=%s", embedding.PostId)
+	}
+
+	return nil
+}
+
+func (s *SqlPostEmbeddingStore) GetPostsForChannels(provider string, opts model.PostEmbeddingSearchOptions) ([]*model.PostWithEmbedding, error) {
+	if len(opts.ChannelIDs) == 0 || opts.Limit <= 0 {
+		return []*model.PostWithEmbedding{}, nil
+	}
+
+	query := s.getQueryBuilder().
+		Select("p.Id", "p.ChannelId", "p.UserId", "p.CreateAt", "p.Message", "p.Hashtags", "p.Type", "e.Embedding").
+		From("PostEmbeddings e").
+		Join("Posts p ON p.Id = e.PostId").
+		Where(sq.Eq{
+			"e.ChannelId": opts.ChannelIDs,
+			"e.Provider":  provider,
+			"p.DeleteAt":  0,
+		}).
+		OrderBy("e.CreateAt DESC", "e.PostId DESC").
+		Limit(uint64(opts.Limit))
+
+	if len(opts.UserIDs) > 0 {
+		query = query.Where(sq.Eq{"e.UserId": opts.UserIDs})
+	}
+	if len(opts.ExcludedUserIDs) > 0 {
+		query = query.Where(sq.NotEq{"e.UserId": opts.ExcludedUserIDs})
+	}
+	if opts.CreatedAfter != 0 {
+		query = query.Where(sq.GtOrEq{"e.CreateAt": opts.CreatedAfter})
+	}
+	if opts.CreatedBefore != 0 {
+		query = query.Where(sq.LtOrEq{"e.CreateAt": opts.CreatedBefore})
+	}
+	if opts.BeforeID != "" {
+		query = query.Where(sq.Or{
+			sq.Lt{"e.CreateAt": opts.BeforeCreateAt},
+			sq.And{
+				sq.Eq{"e.CreateAt": opts.BeforeCreateAt},
+				sq.Lt{"e.PostId": opts.BeforeID},
+			},
+		})
+	}
+
+	rows := []struct {
+		model.Post
+		Embedding []byte
+	}{}
+	if err := s.GetReplicaX().SelectBuilder(&rows, query); err != nil {
+		return nil, errors.Wrap(err, "failed to find PostEmbeddings")
+	}
+
+	posts := make([]*model.PostWithEmbedding, 0, len(rows))
+	for i := range rows {
+		embedding, err := decodeEmbedding(rows[i].Embedding)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to decode PostEmbedding with postId=%s", rows[i].Id)
+		}
+		posts = append(posts, &model.PostWithEmbedding{Post: &rows[i].Post, Embedding: embedding})
+	}
+
+	return posts, nil
+}
+
+func (s *SqlPostEmbeddingStore) Delete(postID string) error {
+	query := s.getQueryBuilder().
+		Delete("PostEmbeddings").
+		Where(sq.Eq{"PostId": postID})
+
+	if _, err := s.GetMasterX().ExecBuilder(query); err != nil {
+		return errors.Wrapf(err, "failed to delete PostEmbedding with postId=%s", postID)
+	}
+
+	return nil
+}
+
+func (s *SqlPostEmbeddingStore) deleteWhere(where sq.Sqlizer) (int64, error) {
+	query := s.getQueryBuilder().
+		Delete("PostEmbeddings").
+		Where(where)
+
+	result, err := s.GetMasterX().ExecBuilder(query)
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to delete PostEmbeddings")
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return 0, errors.Wrap(err, "unable to retrieve rows affected")
+	}
+
+	return rowsAffected, nil
+}
+
+func (s *SqlPostEmbeddingStore) DeleteForChannel(channelID string) (int64, error) {
+	return s.deleteWhere(sq.Eq{"ChannelId": channelID})
+}
+
+func (s *SqlPostEmbeddingStore) DeleteForUser(userID string) (int64, error) {
+	return s.deleteWhere(sq.Eq{"UserId": userID})
+}
+
+func (s *SqlPostEmbeddingStore) DeleteBefore(endTime int64) (int64, error) {
+	return s.deleteWhere(sq.Lt{"CreateAt": endTime})
+}
+
+func (s *SqlPostEmbeddingStore) DeleteAll() error {
+	if _, err := s.GetMasterX().Exec("DELETE FROM PostEmbeddings"); err != nil {
+		return errors.Wrap(err, "failed to delete PostEmbeddings")
+	}
+
+	return nil
+}
@@ -0,0 +1,27 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package sqlstore
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost/server/v8/channels/store/storetest"
+)
+
+func TestPostEmbeddingStore(t *testing.T) {
+	StoreTestWithSqlStore(t, storetest.TestPostEmbeddingStore)
+}
+
+func TestEncodeEmbedding(t *testing.T) {
+	embedding := []float32{0, 1, -0.5, 3.25}
+	decoded, err := decodeEmbedding(encodeEmbedding(embedding))
+	require.NoError(t, err)
+	assert.Equal(t, embedding, decoded)
+
+	_, err = decodeEmbedding([]byte{1, 2, 3})
+	assert.Error(t, err)
+}
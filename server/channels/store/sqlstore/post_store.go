@@ -970,6 +970,10 @@ func (s *SqlPostStore) permanentDelete(postIds []string) (err error) {
 		return err
 	}
 
+	if err = s.permanentDeletePollVotes(transaction, postIds); err != nil {
+		return err
+	}
+
 	query := s.getQueryBuilder().
 		Delete("Posts").
 		Where(
@@ -1026,6 +1030,10 @@ func (s *SqlPostStore) permanentDeleteAllCommentByUser(userId string) (err error
 		return err
 	}
 
+	if err = s.permanentDeletePollVotes(transaction, postIds); err != nil {
+		return err
+	}
+
 	if err = transaction.Commit(); err != nil {
 		return errors.Wrap(err, "commit_transaction")
 	}
@@ -1073,7 +1081,7 @@ func (s *SqlPostStore) PermanentDeleteByUser(rctx request.CTX, userId string) er
 
 // Permanent deletes all channel root posts and comments,
 // deletes all threads and thread memberships
-// deletes all reactions
+// deletes all reactions and poll votes
 // no thread comment cleanup needed, since we are deleting threads and thread memberships
 func (s *SqlPostStore) PermanentDeleteByChannel(rctx request.CTX, channelId string) (err error) {
 	transaction, err := s.GetMasterX().Beginx()
@@ -1106,6 +1114,11 @@ func (s *SqlPostStore) PermanentDeleteByChannel(rctx request.CTX, channelId stri
 		}
 		time.Sleep(10 * time.Millisecond)
 
+		if err = s.permanentDeletePollVotes(transaction, ids); err != nil {
+			return err
+		}
+		time.Sleep(10 * time.Millisecond)
+
 		query := s.getQueryBuilder().
 			Delete("Posts").
 			Where(
@@ -1124,6 +1137,66 @@ func (s *SqlPostStore) PermanentDeleteByChannel(rctx request.CTX, channelId stri
 	return nil
 }
 
+// PermanentDeletePosts deletes the given posts along with their edit history, threads, reactions and poll votes.
+// Replies are only deleted when given, and the threads of replies whose root post is not deleted are updated accordingly.
+func (s *SqlPostStore) PermanentDeletePosts(rctx request.CTX, posts []*model.Post) (err error) {
+	if len(posts) == 0 {
+		return nil
+	}
+
+	ids := make([]string, 0, len(posts))
+	deleted := make(map[string]bool, len(posts))
+	for _, post := range posts {
+		ids = append(ids, post.Id)
+		deleted[post.Id] = true
+	}
+
+	transaction, err := s.GetMasterX().Beginx()
+	if err != nil {
+		return errors.Wrap(err, "begin_transaction")
+	}
+	defer finalizeTransactionX(transaction, &err)
+
+	if err = s.permanentDeleteThreads(transaction, ids); err != nil {
+		return err
+	}
+
+	if err = s.permanentDeleteReactions(transaction, ids); err != nil {
+		return err
+	}
+
+	if err = s.permanentDeletePollVotes(transaction, ids); err != nil {
+		return err
+	}
+
+	query := s.getQueryBuilder().
+		Delete("Posts").
+		Where(
+			sq.Or{
+				sq.Eq{"Id": ids},
+				sq.Eq{"OriginalId": ids},
+			},
+		)
+	if _, err = transaction.ExecBuilder(query); err != nil {
+		return errors.Wrap(err, "failed to delete Posts")
+	}
+
+	for _, post := range posts {
+		if post.RootId == "" || deleted[post.RootId] {
+			continue
+		}
+		if err = s.updateThreadAfterReplyDeletion(transaction, post.RootId, post.UserId); err != nil {
+			return err
+		}
+	}
+
+	if err = transaction.Commit(); err != nil {
+		return errors.Wrap(err, "commit_transaction")
+	}
+
+	return nil
+}
+
 func (s *SqlPostStore) prepareThreadedResponse(posts []*postWithExtra, extended, reversed bool, sanitizeOptions map[string]bool) (*model.PostList, error) {
 	list := model.NewPostList()
 	var userIds []string
@@ -2886,6 +2959,19 @@ func (s *SqlPostStore) permanentDeleteReactions(transaction *sqlxTxWrapper, post
 	return nil
 }
 
+func (s *SqlPostStore) permanentDeletePollVotes(transaction *sqlxTxWrapper, postIds []string) error {
+	query := s.getQueryBuilder().
+		Delete("PollVotes").
+		Where(
+			sq.Eq{"PostId": postIds},
+		)
+	if _, err := transaction.ExecBuilder(query); err != nil {
+		return errors.Wrap(err, "failed to delete PollVotes")
+	}
+
+	return nil
+}
+
 // deleteThread marks a thread as deleted at the given time.
 func (s *SqlPostStore) deleteThread(transaction *sqlxTxWrapper, postId string, deleteAtTime int64) error {
 	queryString, args, err := s.getQueryBuilder().
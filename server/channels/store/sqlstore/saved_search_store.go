@@ -0,0 +1,226 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package sqlstore
+
+import (
+	"database/sql"
+
+	sq "github.com/mattermost/squirrel"
+	"github.com/pkg/errors"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/v8/channels/store"
+)
+
+type SqlSavedSearchStore struct {
+	*SqlStore
+}
+
+func newSqlSavedSearchStore(sqlStore *SqlStore) store.SavedSearchStore {
+	return &SqlSavedSearchStore{
+		SqlStore: sqlStore,
+	}
+}
+
+func (s *SqlSavedSearchStore) columns(prefix string) []string {
+	if prefix != "" {
+		prefix = prefix + "."
+	}
+
+	return []string{
+		prefix + "Id",
+		prefix + "CreateAt",
+		prefix + "UpdateAt",
+		prefix + "UserId",
+		prefix + "TeamId",
+		prefix + "Name",
+		prefix + "Terms",
+		prefix + "IsOrSearch",
+		prefix + "TimeZoneOffset",
+		prefix + "Subscribed",
+		prefix + "AlertType",
+		prefix + "InChannelIds",
+		prefix + "ExcludedChannelIds",
+		prefix + "FromUserIds",
+		prefix + "ExcludedUserIds",
+	}
+}
+
+func (s *SqlSavedSearchStore) savedSearchToSlice(savedSearch *model.SavedSearch) []any {
+	return []any{
+		savedSearch.Id,
+		savedSearch.CreateAt,
+		savedSearch.UpdateAt,
+		savedSearch.UserId,
+		savedSearch.TeamId,
+		savedSearch.Name,
+		savedSearch.Terms,
+		savedSearch.IsOrSearch,
+		savedSearch.TimeZoneOffset,
+		savedSearch.Subscribed,
+		savedSearch.AlertType,
+		savedSearch.InChannelIds,
+		savedSearch.ExcludedChannelIds,
+		savedSearch.FromUserIds,
+		savedSearch.ExcludedUserIds,
+	}
+}
+
+func (s *SqlSavedSearchStore) Save(savedSearch *model.SavedSearch) (*model.SavedSearch, error) {
+	savedSearch.PreSave()
+	if err := savedSearch.IsValid(); err != nil {
+		return nil, err
+	}
+
+	query := s.getQueryBuilder().
+		Insert("SavedSearches").
+		Columns(s.columns("")...).
+		Values(s.savedSearchToSlice(savedSearch)...)
+
+	if _, err := s.GetMasterX().ExecBuilder(query); err != nil {
+		return nil, errors.Wrapf(err, "failed to save saved search with id=%s", savedSearch.Id)
+	}
+
+	return savedSearch, nil
+}
+
+func (s *SqlSavedSearchStore) Get(savedSearchId string) (*model.SavedSearch, error) {
+	query := s.getQueryBuilder().
+		Select(s.columns("")...).
+		From("SavedSearches").
+		Where(sq.Eq{"Id": savedSearchId})
+
+	savedSearch := &model.SavedSearch{}
+	if err := s.GetReplicaX().GetBuilder(savedSearch, query); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, store.NewErrNotFound("SavedSearch", savedSearchId)
+		}
+		return nil, errors.Wrapf(err, "failed to get saved search with id=%s", savedSearchId)
+	}
+
+	return savedSearch, nil
+}
+
+func (s *SqlSavedSearchStore) Update(savedSearch *model.SavedSearch) (*model.SavedSearch, error) {
+	savedSearch.PreUpdate()
+	if err := savedSearch.IsValid(); err != nil {
+		return nil, err
+	}
+
+	query := s.getQueryBuilder().
+		Update("SavedSearches").
+		SetMap(map[string]any{
+			"UpdateAt":           savedSearch.UpdateAt,
+			"Name":               savedSearch.Name,
+			"Terms":              savedSearch.Terms,
+			"IsOrSearch":         savedSearch.IsOrSearch,
+			"TimeZoneOffset":     savedSearch.TimeZoneOffset,
+			"Subscribed":         savedSearch.Subscribed,
+			"AlertType":          savedSearch.AlertType,
+			"InChannelIds":       savedSearch.InChannelIds,
+			"ExcludedChannelIds": savedSearch.ExcludedChannelIds,
+			"FromUserIds":        savedSearch.FromUserIds,
+			"ExcludedUserIds":    savedSearch.ExcludedUserIds,
+		}).
+		Where(sq.Eq{"Id": savedSearch.Id})
+
+	result, err := s.GetMasterX().ExecBuilder(query)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to update saved search with id=%s", savedSearch.Id)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to get rows affected")
+	}
+	if rowsAffected == 0 {
+		return nil, store.NewErrNotFound("SavedSearch", savedSearch.Id)
+	}
+
+	return savedSearch, nil
+}
+
+// GetForUser returns the saved searches of a user in the given team, ordered by name.
+func (s *SqlSavedSearchStore) GetForUser(userId, teamId string) ([]*model.SavedSearch, error) {
+	query := s.getQueryBuilder().
+		Select(s.columns("")...).
+		From("SavedSearches").
+		Where(sq.Eq{
+			"UserId": userId,
+			"TeamId": teamId,
+		}).
+		OrderBy("Name", "CreateAt")
+
+	savedSearches := []*model.SavedSearch{}
+	if err := s.GetReplicaX().SelectBuilder(&savedSearches, query); err != nil {
+		return nil, errors.Wrapf(err, "failed to get saved searches for userId=%s teamId=%s", userId, teamId)
+	}
+
+	return savedSearches, nil
+}
+
+func (s *SqlSavedSearchStore) CountForUser(userId string) (int64, error) {
+	query := s.getQueryBuilder().
+		Select("COUNT(*)").
+		From("SavedSearches").
+		Where(sq.Eq{"UserId": userId})
+
+	var count int64
+	if err := s.GetReplicaX().GetBuilder(&count, query); err != nil {
+		return 0, errors.Wrapf(err, "failed to count saved searches for userId=%s", userId)
+	}
+
+	return count, nil
+}
+
+// GetSubscribedForChannel returns the subscribed saved searches of the active members of a
+// channel. Only the saved searches of the given team are returned, unless the team is empty, as
+// it is for direct and group message channels, which are searched from every team.
+func (s *SqlSavedSearchStore) GetSubscribedForChannel(channelId, teamId string) ([]*model.SavedSearch, error) {
+	query := s.getQueryBuilder().
+		Select(s.columns("ss")...).
+		From("SavedSearches ss").
+		InnerJoin("ChannelMembers cm ON cm.UserId = ss.UserId").
+		InnerJoin("Users u ON u.Id = ss.UserId").
+		Where(sq.Eq{
+			"cm.ChannelId":  channelId,
+			"ss.Subscribed": true,
+			"u.DeleteAt":    0,
+		})
+
+	if teamId != "" {
+		query = query.Where(sq.Eq{"ss.TeamId": teamId})
+	}
+
+	savedSearches := []*model.SavedSearch{}
+	if err := s.GetReplicaX().SelectBuilder(&savedSearches, query); err != nil {
+		return nil, errors.Wrapf(err, "failed to get subscribed saved searches for channelId=%s", channelId)
+	}
+
+	return savedSearches, nil
+}
+
+func (s *SqlSavedSearchStore) PermanentDelete(savedSearchId string) error {
+	query := s.getQueryBuilder().
+		Delete("SavedSearches").
+		Where(sq.Eq{"Id": savedSearchId})
+
+	if _, err := s.GetMasterX().ExecBuilder(query); err != nil {
+		return errors.Wrapf(err, "failed to delete saved search with id=%s", savedSearchId)
+	}
+
+	return nil
+}
+
+func (s *SqlSavedSearchStore) PermanentDeleteByUser(userId string) error {
+	query := s.getQueryBuilder().
+		Delete("SavedSearches").
+		Where(sq.Eq{"UserId": userId})
+
+	if _, err := s.GetMasterX().ExecBuilder(query); err != nil {
+		return errors.Wrapf(err, "failed to delete saved searches for userId=%s", userId)
+	}
+
+	return nil
+}
@@ -0,0 +1,218 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package sqlstore
+
+import (
+	"database/sql"
+	"sync"
+
+	sq "github.com/mattermost/squirrel"
+	"github.com/pkg/errors"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/v8/channels/store"
+)
+
+type SqlScheduledPostStore struct {
+	*SqlStore
+	maxMessageSizeOnce   sync.Once
+	maxMessageSizeCached int
+}
+
+func newSqlScheduledPostStore(sqlStore *SqlStore) store.ScheduledPostStore {
+	return &SqlScheduledPostStore{
+		SqlStore:             sqlStore,
+		maxMessageSizeCached: model.PostMessageMaxRunesV1,
+	}
+}
+
+func (s *SqlScheduledPostStore) columns(prefix string) []string {
+	if prefix != "" {
+		prefix = prefix + "."
+	}
+
+	return []string{
+		prefix + "Id",
+		prefix + "CreateAt",
+		prefix + "UpdateAt",
+		prefix + "UserId",
+		prefix + "ChannelId",
+		prefix + "RootId",
+		prefix + "Message",
+		prefix + "Props",
+		prefix + "FileIds",
+		prefix + "Priority",
+		prefix + "ScheduledAt",
+		prefix + "ProcessedAt",
+		prefix + "ErrorCode",
+	}
+}
+
+func (s *SqlScheduledPostStore) scheduledPostToSlice(scheduledPost *model.ScheduledPost) []any {
+	return []any{
+		scheduledPost.Id,
+		scheduledPost.CreateAt,
+		scheduledPost.UpdateAt,
+		scheduledPost.UserId,
+		scheduledPost.ChannelId,
+		scheduledPost.RootId,
+		scheduledPost.Message,
+		model.StringInterfaceToJSON(scheduledPost.GetProps()),
+		model.ArrayToJSON(scheduledPost.FileIds),
+		model.StringInterfaceToJSON(scheduledPost.Priority),
+		scheduledPost.ScheduledAt,
+		scheduledPost.ProcessedAt,
+		scheduledPost.ErrorCode,
+	}
+}
+
+func (s *SqlScheduledPostStore) Save(scheduledPost *model.ScheduledPost) (*model.ScheduledPost, error) {
+	scheduledPost.PreSave()
+	if err := scheduledPost.IsValid(s.getMaxMessageSize()); err != nil {
+		return nil, err
+	}
+
+	query := s.getQueryBuilder().
+		Insert("ScheduledPosts").
+		Columns(s.columns("")...).
+		Values(s.scheduledPostToSlice(scheduledPost)...)
+
+	if _, err := s.GetMasterX().ExecBuilder(query); err != nil {
+		return nil, errors.Wrapf(err, "failed to save scheduled post with id=%s", scheduledPost.Id)
+	}
+
+	return scheduledPost, nil
+}
+
+func (s *SqlScheduledPostStore) Get(scheduledPostId string) (*model.ScheduledPost, error) {
+	query := s.getQueryBuilder().
+		Select(s.columns("")...).
+		From("ScheduledPosts").
+		Where(sq.Eq{"Id": scheduledPostId})
+
+	scheduledPost := &model.ScheduledPost{}
+	if err := s.GetReplicaX().GetBuilder(scheduledPost, query); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, store.NewErrNotFound("ScheduledPost", scheduledPostId)
+		}
+		return nil, errors.Wrapf(err, "failed to get scheduled post with id=%s", scheduledPostId)
+	}
+
+	return scheduledPost, nil
+}
+
+func (s *SqlScheduledPostStore) Update(scheduledPost *model.ScheduledPost) error {
+	scheduledPost.PreUpdate()
+	if err := scheduledPost.IsValid(s.getMaxMessageSize()); err != nil {
+		return err
+	}
+
+	query := s.getQueryBuilder().
+		Update("ScheduledPosts").
+		SetMap(map[string]any{
+			"UpdateAt":    scheduledPost.UpdateAt,
+			"Message":     scheduledPost.Message,
+			"Props":       model.StringInterfaceToJSON(scheduledPost.GetProps()),
+			"FileIds":     model.ArrayToJSON(scheduledPost.FileIds),
+			"Priority":    model.StringInterfaceToJSON(scheduledPost.Priority),
+			"ScheduledAt": scheduledPost.ScheduledAt,
+			"ProcessedAt": scheduledPost.ProcessedAt,
+			"ErrorCode":   scheduledPost.ErrorCode,
+		}).
+		Where(sq.Eq{"Id": scheduledPost.Id})
+
+	result, err := s.GetMasterX().ExecBuilder(query)
+	if err != nil {
+		return errors.Wrapf(err, "failed to update scheduled post with id=%s", scheduledPost.Id)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return errors.Wrap(err, "unable to get rows affected")
+	}
+	if rowsAffected == 0 {
+		return store.NewErrNotFound("ScheduledPost", scheduledPost.Id)
+	}
+
+	return nil
+}
+
+// GetScheduledPostsForUser returns the scheduled posts of a user in the given team,
+// including the ones in direct and group message channels, ordered by schedule time.
+func (s *SqlScheduledPostStore) GetScheduledPostsForUser(userId, teamId string) ([]*model.ScheduledPost, error) {
+	query := s.getQueryBuilder().
+		Select(s.columns("sp")...).
+		From("ScheduledPosts sp").
+		InnerJoin("Channels c ON c.Id = sp.ChannelId").
+		Where(sq.And{
+			sq.Eq{"sp.UserId": userId},
+			sq.Eq{"c.DeleteAt": 0},
+			sq.Or{
+				sq.Eq{"c.TeamId": teamId},
+				sq.Eq{"c.TeamId": ""},
+			},
+		}).
+		OrderBy("sp.ScheduledAt", "sp.CreateAt")
+
+	scheduledPosts := []*model.ScheduledPost{}
+	if err := s.GetReplicaX().SelectBuilder(&scheduledPosts, query); err != nil {
+		return nil, errors.Wrapf(err, "failed to get scheduled posts for userId=%s teamId=%s", userId, teamId)
+	}
+
+	return scheduledPosts, nil
+}
+
+// GetPendingScheduledPosts returns a page of unprocessed scheduled posts due at or before
+// beforeTime, using (afterTime, lastScheduledPostId) as a cursor over (ScheduledAt, Id).
+func (s *SqlScheduledPostStore) GetPendingScheduledPosts(beforeTime, afterTime int64, lastScheduledPostId string, perPage uint64) ([]*model.ScheduledPost, error) {
+	query := s.getQueryBuilder().
+		Select(s.columns("")...).
+		From("ScheduledPosts").
+		Where(sq.And{
+			sq.Eq{"ProcessedAt": 0},
+			sq.Eq{"ErrorCode": ""},
+			sq.LtOrEq{"ScheduledAt": beforeTime},
+			sq.Or{
+				sq.Gt{"ScheduledAt": afterTime},
+				sq.And{
+					sq.Eq{"ScheduledAt": afterTime},
+					sq.Gt{"Id": lastScheduledPostId},
+				},
+			},
+		}).
+		OrderBy("ScheduledAt", "Id").
+		Limit(perPage)
+
+	scheduledPosts := []*model.ScheduledPost{}
+	if err := s.GetReplicaX().SelectBuilder(&scheduledPosts, query); err != nil {
+		return nil, errors.Wrapf(err, "failed to get pending scheduled posts beforeTime=%d afterTime=%d", beforeTime, afterTime)
+	}
+
+	return scheduledPosts, nil
+}
+
+func (s *SqlScheduledPostStore) PermanentlyDelete(scheduledPostIds []string) error {
+	if len(scheduledPostIds) == 0 {
+		return nil
+	}
+
+	query := s.getQueryBuilder().
+		Delete("ScheduledPosts").
+		Where(sq.Eq{"Id": scheduledPostIds})
+
+	if _, err := s.GetMasterX().ExecBuilder(query); err != nil {
+		return errors.Wrapf(err, "failed to delete scheduled posts count=%d", len(scheduledPostIds))
+	}
+
+	return nil
+}
+
+// getMaxMessageSize returns the maximum number of runes that may be stored in a
+// scheduled post. Scheduled posts end up as posts, so they share the posts limit.
+func (s *SqlScheduledPostStore) getMaxMessageSize() int {
+	s.maxMessageSizeOnce.Do(func() {
+		s.maxMessageSizeCached = s.SqlStore.stores.post.(*SqlPostStore).GetMaxPostSize()
+	})
+	return s.maxMessageSizeCached
+}
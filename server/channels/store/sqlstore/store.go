@@ -111,6 +111,14 @@ type SqlStoreStores struct {
 	postPersistentNotification store.PostPersistentNotificationStore
 	desktopTokens              store.DesktopTokensStore
 	channelBookmarks           store.ChannelBookmarkStore
+	scheduledPost              store.ScheduledPostStore
+	savedSearch                store.SavedSearchStore
+	webAuthnCredential         store.WebAuthnCredentialStore
+	pollVote                   store.PollVoteStore
+	expiringPost               store.ExpiringPostStore
+	auditRecord                store.AuditRecordStore
+	fileBlob                   store.FileBlobStore
+	postEmbedding              store.PostEmbeddingStore
 }
 
 type SqlStore struct {
@@ -236,6 +244,14 @@ func New(settings model.SqlSettings, logger mlog.LoggerIFace, metrics einterface
 	store.stores.postPersistentNotification = newSqlPostPersistentNotificationStore(store)
 	store.stores.desktopTokens = newSqlDesktopTokensStore(store, metrics)
 	store.stores.channelBookmarks = newSqlChannelBookmarkStore(store)
+	store.stores.scheduledPost = newSqlScheduledPostStore(store)
+	store.stores.savedSearch = newSqlSavedSearchStore(store)
+	store.stores.webAuthnCredential = newSqlWebAuthnCredentialStore(store)
+	store.stores.pollVote = newSqlPollVoteStore(store)
+	store.stores.expiringPost = newSqlExpiringPostStore(store)
+	store.stores.auditRecord = newSqlAuditRecordStore(store)
+	store.stores.fileBlob = newSqlFileBlobStore(store)
+	store.stores.postEmbedding = newSqlPostEmbeddingStore(store)
 
 	store.stores.preference.(*SqlPreferenceStore).deleteUnusedFeatures()
 
@@ -1039,6 +1055,38 @@ func (ss *SqlStore) ChannelBookmark() store.ChannelBookmarkStore {
 	return ss.stores.channelBookmarks
 }
 
+func (ss *SqlStore) ScheduledPost() store.ScheduledPostStore {
+	return ss.stores.scheduledPost
+}
+
+func (ss *SqlStore) SavedSearch() store.SavedSearchStore {
+	return ss.stores.savedSearch
+}
+
+func (ss *SqlStore) WebAuthnCredential() store.WebAuthnCredentialStore {
+	return ss.stores.webAuthnCredential
+}
+
+func (ss *SqlStore) PollVote() store.PollVoteStore {
+	return ss.stores.pollVote
+}
+
+func (ss *SqlStore) ExpiringPost() store.ExpiringPostStore {
+	return ss.stores.expiringPost
+}
+
+func (ss *SqlStore) AuditRecord() store.AuditRecordStore {
+	return ss.stores.auditRecord
+}
+
+func (ss *SqlStore) FileBlob() store.FileBlobStore {
+	return ss.stores.fileBlob
+}
+
+func (ss *SqlStore) PostEmbedding() store.PostEmbeddingStore {
+	return ss.stores.postEmbedding
+}
+
 func (ss *SqlStore) DropAllTables() {
 	if ss.DriverName() == model.DatabaseDriverPostgres {
 		ss.masterX.Exec(`DO
@@ -118,6 +118,23 @@ func (us SqlUploadSessionStore) GetForUser(userId string) ([]*model.UploadSessio
 	return sessions, nil
 }
 
+func (us SqlUploadSessionStore) GetIncomplete() ([]*model.UploadSession, error) {
+	query, args, err := us.getQueryBuilder().
+		Select("*").
+		From("UploadSessions").
+		Where("FileOffset < FileSize").
+		OrderBy("CreateAt ASC").
+		ToSql()
+	if err != nil {
+		return nil, errors.Wrap(err, "SqlUploadSessionStore.GetIncomplete: failed to build query")
+	}
+	sessions := []*model.UploadSession{}
+	if err := us.GetMasterX().Select(&sessions, query, args...); err != nil {
+		return nil, errors.Wrap(err, "SqlUploadSessionStore.GetIncomplete: failed to select")
+	}
+	return sessions, nil
+}
+
 func (us SqlUploadSessionStore) Delete(id string) error {
 	if !model.IsValidId(id) {
 		return errors.New("SqlUploadSessionStore.Delete: id is not valid")
@@ -1277,6 +1277,43 @@ func (us SqlUserStore) GetAllNotInAuthService(authServices []string) ([]*model.U
 	return users, nil
 }
 
+// GetNonAdminsUsingAuthService returns a page of the users of an auth service, deactivated ones
+// included, leaving out bots and system admins. The users are ordered by creation so that pages
+// stay stable as users are added.
+func (us SqlUserStore) GetNonAdminsUsingAuthService(authService string, offset, limit int) ([]*model.User, error) {
+	query := us.usersQuery.
+		Where(sq.Eq{"u.AuthService": authService}).
+		Where("b.UserId IS NULL").
+		Where(sq.NotLike{"u.Roles": wildcardSearchTerm(model.SystemAdminRoleId)}).
+		OrderBy("u.CreateAt ASC", "u.Id ASC").
+		Offset(uint64(offset)).
+		Limit(uint64(limit))
+
+	users := []*model.User{}
+	if err := us.GetReplicaX().SelectBuilder(&users, query); err != nil {
+		return nil, errors.Wrapf(err, "failed to find non-admin Users with authService=%s", authService)
+	}
+
+	return users, nil
+}
+
+func (us SqlUserStore) CountNonAdminsUsingAuthService(authService string) (int64, error) {
+	query := us.getQueryBuilder().
+		Select("COUNT(*)").
+		From("Users u").
+		LeftJoin("Bots b ON ( b.UserId = u.Id )").
+		Where(sq.Eq{"u.AuthService": authService}).
+		Where("b.UserId IS NULL").
+		Where(sq.NotLike{"u.Roles": wildcardSearchTerm(model.SystemAdminRoleId)})
+
+	var count int64
+	if err := us.GetReplicaX().GetBuilder(&count, query); err != nil {
+		return 0, errors.Wrapf(err, "failed to count non-admin Users with authService=%s", authService)
+	}
+
+	return count, nil
+}
+
 func (us SqlUserStore) GetByUsername(username string) (*model.User, error) {
 	query := us.usersQuery.Where("u.Username = lower(?)", username)
 
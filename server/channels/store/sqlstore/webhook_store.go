@@ -185,10 +185,10 @@ func (s SqlWebhookStore) SaveOutgoing(webhook *model.OutgoingWebhook) (*model.Ou
 	}
 
 	if _, err := s.GetMasterX().NamedExec(`INSERT INTO OutgoingWebhooks
-			(Id, Token, CreateAt, UpdateAt, DeleteAt, CreatorId, ChannelId, TeamId, TriggerWords, TriggerWhen,
+			(Id, Token, SigningSecret, CreateAt, UpdateAt, DeleteAt, CreatorId, ChannelId, TeamId, TriggerWords, TriggerWhen,
 			CallbackURLs, DisplayName, Description, ContentType, Username, IconURL)
 			VALUES
-			(:Id, :Token, :CreateAt, :UpdateAt, :DeleteAt, :CreatorId, :ChannelId, :TeamId, :TriggerWords, :TriggerWhen,
+			(:Id, :Token, :SigningSecret, :CreateAt, :UpdateAt, :DeleteAt, :CreatorId, :ChannelId, :TeamId, :TriggerWords, :TriggerWhen,
 			:CallbackURLs, :DisplayName, :Description, :ContentType, :Username, :IconURL)`, webhook); err != nil {
 		return nil, errors.Wrapf(err, "failed to save OutgoingWebhook with id=%s", webhook.Id)
 	}
@@ -341,7 +341,7 @@ func (s SqlWebhookStore) UpdateOutgoing(hook *model.OutgoingWebhook) (*model.Out
 	hook.UpdateAt = model.GetMillis()
 
 	_, err := s.GetMasterX().NamedExec(`UPDATE OutgoingWebhooks SET
-			CreateAt = :CreateAt, UpdateAt = :UpdateAt, DeleteAt = :DeleteAt, Token = :Token, CreatorId = :CreatorId,
+			CreateAt = :CreateAt, UpdateAt = :UpdateAt, DeleteAt = :DeleteAt, Token = :Token, SigningSecret = :SigningSecret, CreatorId = :CreatorId,
 			ChannelId = :ChannelId, TeamId = :TeamId, TriggerWords = :TriggerWords, TriggerWhen = :TriggerWhen,
 			CallbackURLs = :CallbackURLs, DisplayName = :DisplayName, Description = :Description,
 			ContentType = :ContentType, Username = :Username, IconURL = :IconURL WHERE Id = :Id`, hook)
@@ -401,3 +401,187 @@ func (s SqlWebhookStore) AnalyticsOutgoingCount(teamId string) (int64, error) {
 	}
 	return count, nil
 }
+
+func (s SqlWebhookStore) outgoingDeliveryColumns() []string {
+	return []string{
+		"Id",
+		"CreateAt",
+		"UpdateAt",
+		"HookId",
+		"TeamId",
+		"ChannelId",
+		"PostId",
+		"CallbackURL",
+		"ContentType",
+		"Payload",
+		"Status",
+		"Attempts",
+		"LastError",
+		"LastAttemptAt",
+		"NextAttemptAt",
+	}
+}
+
+func (s SqlWebhookStore) SaveOutgoingDelivery(delivery *model.OutgoingWebhookDelivery) (*model.OutgoingWebhookDelivery, error) {
+	if delivery.Id != "" {
+		return nil, store.NewErrInvalidInput("OutgoingWebhookDelivery", "id", delivery.Id)
+	}
+
+	delivery.PreSave()
+	if err := delivery.IsValid(); err != nil {
+		return nil, err
+	}
+
+	query := s.getQueryBuilder().
+		Insert("OutgoingWebhookDeliveries").
+		Columns(s.outgoingDeliveryColumns()...).
+		Values(
+			delivery.Id,
+			delivery.CreateAt,
+			delivery.UpdateAt,
+			delivery.HookId,
+			delivery.TeamId,
+			delivery.ChannelId,
+			delivery.PostId,
+			delivery.CallbackURL,
+			delivery.ContentType,
+			delivery.Payload,
+			delivery.Status,
+			delivery.Attempts,
+			delivery.LastError,
+			delivery.LastAttemptAt,
+			delivery.NextAttemptAt,
+		)
+
+	if _, err := s.GetMasterX().ExecBuilder(query); err != nil {
+		return nil, errors.Wrapf(err, "failed to save OutgoingWebhookDelivery with id=%s", delivery.Id)
+	}
+
+	return delivery, nil
+}
+
+func (s SqlWebhookStore) GetOutgoingDelivery(id string) (*model.OutgoingWebhookDelivery, error) {
+	query := s.getQueryBuilder().
+		Select(s.outgoingDeliveryColumns()...).
+		From("OutgoingWebhookDeliveries").
+		Where(sq.Eq{"Id": id})
+
+	var delivery model.OutgoingWebhookDelivery
+	if err := s.GetReplicaX().GetBuilder(&delivery, query); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, store.NewErrNotFound("OutgoingWebhookDelivery", id)
+		}
+		return nil, errors.Wrapf(err, "failed to get OutgoingWebhookDelivery with id=%s", id)
+	}
+
+	return &delivery, nil
+}
+
+func (s SqlWebhookStore) UpdateOutgoingDelivery(delivery *model.OutgoingWebhookDelivery) (*model.OutgoingWebhookDelivery, error) {
+	delivery.PreUpdate()
+	if err := delivery.IsValid(); err != nil {
+		return nil, err
+	}
+
+	query := s.getQueryBuilder().
+		Update("OutgoingWebhookDeliveries").
+		SetMap(map[string]any{
+			"UpdateAt":      delivery.UpdateAt,
+			"Status":        delivery.Status,
+			"Attempts":      delivery.Attempts,
+			"LastError":     delivery.LastError,
+			"LastAttemptAt": delivery.LastAttemptAt,
+			"NextAttemptAt": delivery.NextAttemptAt,
+		}).
+		Where(sq.Eq{"Id": delivery.Id})
+
+	result, err := s.GetMasterX().ExecBuilder(query)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to update OutgoingWebhookDelivery with id=%s", delivery.Id)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to get rows affected")
+	}
+	if rowsAffected == 0 {
+		return nil, store.NewErrNotFound("OutgoingWebhookDelivery", delivery.Id)
+	}
+
+	return delivery, nil
+}
+
+// GetDueOutgoingDeliveries returns a page of pending deliveries whose next attempt is due at
+// or before beforeTime, using (afterTime, lastDeliveryID) as a cursor over (NextAttemptAt, Id).
+func (s SqlWebhookStore) GetDueOutgoingDeliveries(beforeTime, afterTime int64, lastDeliveryID string, limit uint64) ([]*model.OutgoingWebhookDelivery, error) {
+	query := s.getQueryBuilder().
+		Select(s.outgoingDeliveryColumns()...).
+		From("OutgoingWebhookDeliveries").
+		Where(sq.And{
+			sq.Eq{"Status": model.OutgoingWebhookDeliveryStatusPending},
+			sq.LtOrEq{"NextAttemptAt": beforeTime},
+			sq.Or{
+				sq.Gt{"NextAttemptAt": afterTime},
+				sq.And{
+					sq.Eq{"NextAttemptAt": afterTime},
+					sq.Gt{"Id": lastDeliveryID},
+				},
+			},
+		}).
+		OrderBy("NextAttemptAt", "Id").
+		Limit(limit)
+
+	deliveries := []*model.OutgoingWebhookDelivery{}
+	if err := s.GetReplicaX().SelectBuilder(&deliveries, query); err != nil {
+		return nil, errors.Wrapf(err, "failed to get due OutgoingWebhookDeliveries beforeTime=%d afterTime=%d", beforeTime, afterTime)
+	}
+
+	return deliveries, nil
+}
+
+func (s SqlWebhookStore) GetOutgoingDeliveriesByHook(hookID string, status string, offset, limit int) ([]*model.OutgoingWebhookDelivery, error) {
+	query := s.getQueryBuilder().
+		Select(s.outgoingDeliveryColumns()...).
+		From("OutgoingWebhookDeliveries").
+		Where(sq.Eq{"HookId": hookID}).
+		OrderBy("CreateAt DESC", "Id")
+
+	if status != "" {
+		query = query.Where(sq.Eq{"Status": status})
+	}
+
+	if limit >= 0 && offset >= 0 {
+		query = query.Limit(uint64(limit)).Offset(uint64(offset))
+	}
+
+	deliveries := []*model.OutgoingWebhookDelivery{}
+	if err := s.GetReplicaX().SelectBuilder(&deliveries, query); err != nil {
+		return nil, errors.Wrapf(err, "failed to get OutgoingWebhookDeliveries with hookId=%s", hookID)
+	}
+
+	return deliveries, nil
+}
+
+func (s SqlWebhookStore) PermanentDeleteOutgoingDelivery(id string) error {
+	query := s.getQueryBuilder().
+		Delete("OutgoingWebhookDeliveries").
+		Where(sq.Eq{"Id": id})
+
+	if _, err := s.GetMasterX().ExecBuilder(query); err != nil {
+		return errors.Wrapf(err, "failed to delete OutgoingWebhookDelivery with id=%s", id)
+	}
+
+	return nil
+}
+
+func (s SqlWebhookStore) PermanentDeleteOutgoingDeliveriesByHook(hookID string) error {
+	query := s.getQueryBuilder().
+		Delete("OutgoingWebhookDeliveries").
+		Where(sq.Eq{"HookId": hookID})
+
+	if _, err := s.GetMasterX().ExecBuilder(query); err != nil {
+		return errors.Wrapf(err, "failed to delete OutgoingWebhookDeliveries with hookId=%s", hookID)
+	}
+
+	return nil
+}
@@ -91,6 +91,14 @@ type Store interface {
 	PostPersistentNotification() PostPersistentNotificationStore
 	DesktopTokens() DesktopTokensStore
 	ChannelBookmark() ChannelBookmarkStore
+	ScheduledPost() ScheduledPostStore
+	SavedSearch() SavedSearchStore
+	WebAuthnCredential() WebAuthnCredentialStore
+	PollVote() PollVoteStore
+	ExpiringPost() ExpiringPostStore
+	AuditRecord() AuditRecordStore
+	FileBlob() FileBlobStore
+	PostEmbedding() PostEmbeddingStore
 }
 
 type RetentionPolicyStore interface {
@@ -228,6 +236,9 @@ type ChannelStore interface {
 	GetMembers(channelID string, offset, limit int) (model.ChannelMembers, error)
 	GetMember(ctx context.Context, channelID string, userID string) (*model.ChannelMember, error)
 	GetMemberLastViewedAt(ctx context.Context, channelID string, userID string) (int64, error)
+	// GetMembersLastViewedAtSince returns the LastViewedAt of the members of a channel
+	// that viewed it at or after since, keyed by user id.
+	GetMembersLastViewedAtSince(ctx context.Context, channelID string, since int64) (map[string]int64, error)
 	GetChannelMembersTimezones(channelID string) ([]model.StringMap, error)
 	GetAllChannelMembersForUser(ctx request.CTX, userID string, allowFromCache bool, includeDeleted bool) (map[string]string, error)
 	GetChannelsMemberCount(channelIDs []string) (map[string]int64, error)
@@ -361,6 +372,10 @@ type PostStore interface {
 	Delete(rctx request.CTX, postID string, timestamp int64, deleteByID string) error
 	PermanentDeleteByUser(rctx request.CTX, userID string) error
 	PermanentDeleteByChannel(rctx request.CTX, channelID string) error
+	// PermanentDeletePosts deletes the given posts along with their edit history, threads,
+	// reactions and poll votes. Replies are only deleted when given, so the callers deleting a
+	// root post are expected to give its replies too.
+	PermanentDeletePosts(rctx request.CTX, posts []*model.Post) error
 	GetPosts(options model.GetPostsOptions, allowFromCache bool, sanitizeOptions map[string]bool) (*model.PostList, error)
 	GetFlaggedPosts(userID string, offset int, limit int) (*model.PostList, error)
 	// @openTracingParams userID, teamID, offset, limit
@@ -440,6 +455,8 @@ type UserStore interface {
 	GetByAuth(authData *string, authService string) (*model.User, error)
 	GetAllUsingAuthService(authService string) ([]*model.User, error)
 	GetAllNotInAuthService(authServices []string) ([]*model.User, error)
+	GetNonAdminsUsingAuthService(authService string, offset, limit int) ([]*model.User, error)
+	CountNonAdminsUsingAuthService(authService string) (int64, error)
 	GetByUsername(username string) (*model.User, error)
 	GetForLogin(loginID string, allowSignInWithUsername, allowSignInWithEmail bool) (*model.User, error)
 	VerifyEmail(userID, email string) (string, error)
@@ -619,6 +636,14 @@ type WebhookStore interface {
 	PermanentDeleteOutgoingByUser(userID string) error
 	UpdateOutgoing(hook *model.OutgoingWebhook) (*model.OutgoingWebhook, error)
 
+	SaveOutgoingDelivery(delivery *model.OutgoingWebhookDelivery) (*model.OutgoingWebhookDelivery, error)
+	GetOutgoingDelivery(id string) (*model.OutgoingWebhookDelivery, error)
+	UpdateOutgoingDelivery(delivery *model.OutgoingWebhookDelivery) (*model.OutgoingWebhookDelivery, error)
+	GetDueOutgoingDeliveries(beforeTime, afterTime int64, lastDeliveryID string, limit uint64) ([]*model.OutgoingWebhookDelivery, error)
+	GetOutgoingDeliveriesByHook(hookID string, status string, offset, limit int) ([]*model.OutgoingWebhookDelivery, error)
+	PermanentDeleteOutgoingDelivery(id string) error
+	PermanentDeleteOutgoingDeliveriesByHook(hookID string) error
+
 	AnalyticsIncomingCount(teamID string, userID string) (int64, error)
 	AnalyticsOutgoingCount(teamID string) (int64, error)
 	InvalidateWebhookCache(webhook string)
@@ -648,6 +673,7 @@ type PreferenceStore interface {
 	Save(preferences model.Preferences) error
 	GetCategory(userID string, category string) (model.Preferences, error)
 	GetCategoryAndName(category string, nane string) (model.Preferences, error)
+	GetCategoryAndNameForUsers(userIDs []string, category string, name string) (model.Preferences, error)
 	Get(userID string, category string, name string) (*model.Preference, error)
 	GetAll(userID string) (model.Preferences, error)
 	Delete(userID, category, name string) error
@@ -682,6 +708,16 @@ type DesktopTokensStore interface {
 	DeleteOlderThan(minCreatedAt int64) error
 }
 
+type WebAuthnCredentialStore interface {
+	Save(credential *model.WebAuthnCredential) (*model.WebAuthnCredential, error)
+	Get(id string) (*model.WebAuthnCredential, error)
+	GetByCredentialId(credentialID string) (*model.WebAuthnCredential, error)
+	GetForUser(userID string) ([]*model.WebAuthnCredential, error)
+	UpdateSignCount(id string, signCount, lastUsedAt int64) error
+	Delete(id string) error
+	PermanentDeleteByUser(userID string) error
+}
+
 type EmojiStore interface {
 	Save(emoji *model.Emoji) (*model.Emoji, error)
 	Get(c request.CTX, id string, allowFromCache bool) (*model.Emoji, error)
@@ -709,6 +745,9 @@ type FileInfoStore interface {
 	GetFromMaster(id string) (*model.FileInfo, error)
 	GetByIds(ids []string) ([]*model.FileInfo, error)
 	GetByPath(path string) (*model.FileInfo, error)
+	// CountByPath returns the number of file infos, including deleted ones, whose file,
+	// thumbnail, preview or rendition is stored at path.
+	CountByPath(path string) (int64, error)
 	GetForPost(postID string, readFromMaster, includeDeleted, allowFromCache bool) ([]*model.FileInfo, error)
 	GetForUser(userID string) ([]*model.FileInfo, error)
 	GetWithOptions(page, perPage int, opt *model.GetFileInfosOptions) ([]*model.FileInfo, error)
@@ -719,6 +758,16 @@ type FileInfoStore interface {
 	PermanentDeleteBatch(ctx request.CTX, endTime int64, limit int64) (int64, error)
 	PermanentDeleteByUser(ctx request.CTX, userID string) (int64, error)
 	SetContent(ctx request.CTX, fileID, content string) error
+	// SetContentHash points a file info that is not deduplicated yet at the FileBlob with
+	// the given hash and path.
+	SetContentHash(ctx request.CTX, fileID, contentHash, path string) error
+	// SetScanStatus records the outcome of the virus scan of a file info.
+	SetScanStatus(ctx request.CTX, fileID, status, result string) error
+	// SetRenditionPath records the path of the web-friendly transcoding of a video.
+	SetRenditionPath(ctx request.CTX, fileID, path string) error
+	// SetMediaPreviews records the metadata and the previews of a video or audio file, leaving
+	// its other fields, such as its extracted content, alone.
+	SetMediaPreviews(ctx request.CTX, info *model.FileInfo) error
 	Search(ctx request.CTX, paramsList []*model.SearchParams, userID, teamID string, page, perPage int) (*model.FileInfoList, error)
 	CountAll() (int64, error)
 	GetFilesBatchForIndexing(startTime int64, startFileID string, includeDeleted bool, limit int) ([]*model.FileForIndexing, error)
@@ -733,6 +782,8 @@ type UploadSessionStore interface {
 	Update(session *model.UploadSession) error
 	Get(c request.CTX, id string) (*model.UploadSession, error)
 	GetForUser(userID string) ([]*model.UploadSession, error)
+	// GetIncomplete returns the upload sessions whose file wasn't fully uploaded yet.
+	GetIncomplete() ([]*model.UploadSession, error)
 	Delete(id string) error
 }
 
@@ -1025,6 +1076,71 @@ type PostAcknowledgementStore interface {
 	Delete(acknowledgement *model.PostAcknowledgement) error
 }
 
+type PollVoteStore interface {
+	GetForPost(postID string) ([]*model.PollVote, error)
+	GetForPosts(postIDs []string) ([]*model.PollVote, error)
+	// SaveForUser replaces the votes of a user in a poll. An empty list of options
+	// retracts the user's votes.
+	SaveForUser(postID, userID string, optionIDs []string) ([]*model.PollVote, error)
+	DeleteForPost(postID string) error
+}
+
+type ExpiringPostStore interface {
+	Save(expiringPost *model.ExpiringPost) (*model.ExpiringPost, error)
+	// GetExpired returns the posts that expired at or before the given time, oldest first.
+	GetExpired(now int64, limit int) ([]*model.ExpiringPost, error)
+	DeleteForPosts(postIDs []string) error
+}
+
+type AuditRecordStore interface {
+	Save(record *model.AuditRecord) (*model.AuditRecord, error)
+	// Search returns the audit records matching the search, newest first.
+	Search(search *model.AuditRecordSearch) ([]*model.AuditRecord, error)
+	// PermanentDeleteBatch deletes up to limit of the oldest audit records created before
+	// endTime, along with their objects, and returns how many records were deleted.
+	PermanentDeleteBatch(endTime int64, limit int64) (int64, error)
+}
+
+// FileBlobStore keeps the reference counts of deduplicated file contents. Deleting a file
+// info through the FileInfoStore releases its reference in the same transaction.
+type FileBlobStore interface {
+	// Acquire adds a reference to the blob with the given hash, creating it with the given
+	// path and size if it does not exist yet. The returned bool is true if it was created.
+	// Tombstoned blobs can't be acquired, and an ErrNotFound is returned for them.
+	Acquire(hash, path string, size int64) (*model.FileBlob, bool, error)
+	// Release removes a reference to the blob and returns the remaining reference count.
+	Release(hash string) (int64, error)
+	Get(hash string) (*model.FileBlob, error)
+	// GetUnreferenced returns the blobs that are no longer referenced by any file info,
+	// including the tombstoned ones whose contents may not have been removed yet, ordered by
+	// hash and starting after the given hash.
+	GetUnreferenced(afterHash string, limit int) ([]*model.FileBlob, error)
+	// Tombstone marks the blob as being removed if it is still unreferenced, so that it can't
+	// be acquired while its contents are removed, and returns whether it was marked.
+	Tombstone(hash string) (bool, error)
+	// DeleteTombstoned deletes the blob once its contents were removed, if it is tombstoned.
+	DeleteTombstoned(hash string) error
+	// GetSavedBytes returns the number of bytes the deduplication currently saves.
+	GetSavedBytes() (int64, error)
+}
+
+// PostEmbeddingStore keeps the embeddings of the posts indexed by the semantic search engine, so
+// that they are shared by all the nodes of a cluster.
+type PostEmbeddingStore interface {
+	// Save saves the embedding of a post, replacing the embedding it already has.
+	Save(embedding *model.PostEmbedding) error
+	// GetPostsForChannels returns a page of the posts matching the options that aren't deleted,
+	// from the most recent, along with the embeddings of their messages computed by the given
+	// provider. The posts without such an embedding aren't returned.
+	GetPostsForChannels(provider string, opts model.PostEmbeddingSearchOptions) ([]*model.PostWithEmbedding, error)
+	Delete(postID string) error
+	DeleteForChannel(channelID string) (int64, error)
+	DeleteForUser(userID string) (int64, error)
+	// DeleteBefore deletes the embeddings of the posts created before endTime.
+	DeleteBefore(endTime int64) (int64, error)
+	DeleteAll() error
+}
+
 type PostPersistentNotificationStore interface {
 	Get(params model.GetPersistentNotificationsPostsParams) ([]*model.PostPersistentNotifications, error)
 	GetSingle(postID string) (*model.PostPersistentNotifications, error)
@@ -1044,6 +1160,26 @@ type ChannelBookmarkStore interface {
 	GetBookmarksForChannelSince(channelId string, since int64) ([]*model.ChannelBookmarkWithFileInfo, error)
 }
 
+type ScheduledPostStore interface {
+	Save(scheduledPost *model.ScheduledPost) (*model.ScheduledPost, error)
+	Get(scheduledPostId string) (*model.ScheduledPost, error)
+	Update(scheduledPost *model.ScheduledPost) error
+	GetScheduledPostsForUser(userId, teamId string) ([]*model.ScheduledPost, error)
+	GetPendingScheduledPosts(beforeTime, afterTime int64, lastScheduledPostId string, perPage uint64) ([]*model.ScheduledPost, error)
+	PermanentlyDelete(scheduledPostIds []string) error
+}
+
+type SavedSearchStore interface {
+	Save(savedSearch *model.SavedSearch) (*model.SavedSearch, error)
+	Get(savedSearchId string) (*model.SavedSearch, error)
+	Update(savedSearch *model.SavedSearch) (*model.SavedSearch, error)
+	GetForUser(userId, teamId string) ([]*model.SavedSearch, error)
+	CountForUser(userId string) (int64, error)
+	GetSubscribedForChannel(channelId, teamId string) ([]*model.SavedSearch, error)
+	PermanentDelete(savedSearchId string) error
+	PermanentDeleteByUser(userId string) error
+}
+
 // ChannelSearchOpts contains options for searching channels.
 //
 // NotAssociatedToGroup will exclude channels that have associated, active GroupChannels records.
@@ -0,0 +1,149 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package storetest
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/shared/request"
+	"github.com/mattermost/mattermost/server/v8/channels/store"
+)
+
+func TestAuditRecordStore(t *testing.T, rctx request.CTX, ss store.Store, s SqlStore) {
+	t.Run("Save", func(t *testing.T) { testAuditRecordStoreSave(t, rctx, ss) })
+	t.Run("Search", func(t *testing.T) { testAuditRecordStoreSearch(t, rctx, ss) })
+	t.Run("PermanentDeleteBatch", func(t *testing.T) { testAuditRecordStorePermanentDeleteBatch(t, rctx, ss) })
+}
+
+func testAuditRecordStoreSave(t *testing.T, rctx request.CTX, ss store.Store) {
+	t.Run("valid", func(t *testing.T) {
+		objectID := model.NewId()
+		record, err := ss.AuditRecord().Save(&model.AuditRecord{
+			EventName: "updateChannel",
+			Status:    "success",
+			UserId:    model.NewId(),
+			ObjectIds: model.StringArray{objectID, objectID},
+			Data:      `{"event_name":"updateChannel"}`,
+		})
+		require.NoError(t, err)
+		assert.True(t, model.IsValidId(record.Id))
+		assert.NotZero(t, record.CreateAt)
+
+		records, err := ss.AuditRecord().Search(&model.AuditRecordSearch{ObjectId: objectID})
+		require.NoError(t, err)
+		require.Len(t, records, 1)
+		assert.Equal(t, record.Id, records[0].Id)
+		assert.Equal(t, model.StringArray{objectID, objectID}, records[0].ObjectIds)
+		assert.Equal(t, record.Data, records[0].Data)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		_, err := ss.AuditRecord().Save(&model.AuditRecord{})
+		require.Error(t, err)
+
+		_, err = ss.AuditRecord().Save(&model.AuditRecord{EventName: "updateChannel", ObjectIds: model.StringArray{"junk"}})
+		require.Error(t, err)
+	})
+}
+
+func testAuditRecordStoreSearch(t *testing.T, rctx request.CTX, ss store.Store) {
+	userID := model.NewId()
+	otherUserID := model.NewId()
+	objectID := model.NewId()
+	now := model.GetMillis()
+
+	save := func(eventName, status, userID string, createAt int64, objectIDs ...string) *model.AuditRecord {
+		t.Helper()
+		record, err := ss.AuditRecord().Save(&model.AuditRecord{
+			CreateAt:  createAt,
+			EventName: eventName,
+			Status:    status,
+			UserId:    userID,
+			ObjectIds: objectIDs,
+		})
+		require.NoError(t, err)
+		return record
+	}
+
+	r1 := save("createPost", "success", userID, now-3000, objectID)
+	r2 := save("createPost", "fail", userID, now-2000)
+	r3 := save("deletePost", "success", otherUserID, now-1000, objectID)
+	r4 := save("deletePost", "success", userID, now, model.NewId())
+
+	ids := func(records []*model.AuditRecord) []string {
+		result := make([]string, 0, len(records))
+		for _, record := range records {
+			result = append(result, record.Id)
+		}
+		return result
+	}
+
+	testCases := []struct {
+		Description string
+		Search      *model.AuditRecordSearch
+		Expected    []string
+	}{
+		{"by user", &model.AuditRecordSearch{UserId: userID}, []string{r4.Id, r2.Id, r1.Id}},
+		{"by user and event", &model.AuditRecordSearch{UserId: userID, EventName: "createPost"}, []string{r2.Id, r1.Id}},
+		{"by user and status", &model.AuditRecordSearch{UserId: userID, Status: "fail"}, []string{r2.Id}},
+		{"by object", &model.AuditRecordSearch{ObjectId: objectID}, []string{r3.Id, r1.Id}},
+		{"by object and user", &model.AuditRecordSearch{ObjectId: objectID, UserId: otherUserID}, []string{r3.Id}},
+		{"by time range", &model.AuditRecordSearch{UserId: userID, Since: now - 2000, Until: now - 1}, []string{r2.Id}},
+		{"first page", &model.AuditRecordSearch{UserId: userID, PerPage: 2}, []string{r4.Id, r2.Id}},
+		{"second page", &model.AuditRecordSearch{UserId: userID, Page: 1, PerPage: 2}, []string{r1.Id}},
+		{"no match", &model.AuditRecordSearch{UserId: model.NewId()}, []string{}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Description, func(t *testing.T) {
+			records, err := ss.AuditRecord().Search(tc.Search)
+			require.NoError(t, err)
+			assert.Equal(t, tc.Expected, ids(records))
+		})
+	}
+}
+
+func testAuditRecordStorePermanentDeleteBatch(t *testing.T, rctx request.CTX, ss store.Store) {
+	objectID := model.NewId()
+
+	// The records are older than those of the other tests, so that only they are deleted.
+	save := func(createAt int64) *model.AuditRecord {
+		t.Helper()
+		record, err := ss.AuditRecord().Save(&model.AuditRecord{
+			CreateAt:  createAt,
+			EventName: "updateChannel",
+			Status:    "success",
+			UserId:    model.NewId(),
+			ObjectIds: model.StringArray{objectID},
+		})
+		require.NoError(t, err)
+		return record
+	}
+
+	save(1000)
+	save(2000)
+	kept := save(3000)
+
+	deleted, err := ss.AuditRecord().PermanentDeleteBatch(3000, 1)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), deleted)
+
+	deleted, err = ss.AuditRecord().PermanentDeleteBatch(3000, 10)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), deleted)
+
+	deleted, err = ss.AuditRecord().PermanentDeleteBatch(3000, 10)
+	require.NoError(t, err)
+	assert.Zero(t, deleted)
+
+	// The objects of the deleted records are gone with them.
+	records, err := ss.AuditRecord().Search(&model.AuditRecordSearch{ObjectId: objectID})
+	require.NoError(t, err)
+	require.Len(t, records, 1)
+	assert.Equal(t, kept.Id, records[0].Id)
+}
@@ -112,6 +112,7 @@ func TestChannelStore(t *testing.T, rctx request.CTX, ss store.Store, s SqlStore
 	t.Run("UpdateChannelMember", func(t *testing.T) { testUpdateChannelMember(t, rctx, ss) })
 	t.Run("GetMember", func(t *testing.T) { testGetMember(t, rctx, ss) })
 	t.Run("GetMemberLastViewedAt", func(t *testing.T) { testGetMemberLastViewedAt(t, rctx, ss) })
+	t.Run("GetMembersLastViewedAtSince", func(t *testing.T) { testGetMembersLastViewedAtSince(t, rctx, ss) })
 	t.Run("GetMemberForPost", func(t *testing.T) { testChannelStoreGetMemberForPost(t, rctx, ss) })
 	t.Run("GetMemberCount", func(t *testing.T) { testGetMemberCount(t, rctx, ss) })
 	t.Run("GetMemberCountsByGroup", func(t *testing.T) { testGetMemberCountsByGroup(t, rctx, ss) })
@@ -5070,6 +5071,40 @@ func testGetMemberLastViewedAt(t *testing.T, rctx request.CTX, ss store.Store) {
 	ss.Channel().InvalidateCacheForChannelMembersNotifyProps(c2.Id)
 }
 
+func testGetMembersLastViewedAtSince(t *testing.T, rctx request.CTX, ss store.Store) {
+	c1 := &model.Channel{
+		TeamId:      model.NewId(),
+		DisplayName: model.NewId(),
+		Name:        model.NewId(),
+		Type:        model.ChannelTypeOpen,
+	}
+	_, nErr := ss.Channel().Save(rctx, c1, -1)
+	require.NoError(t, nErr)
+
+	userIDs := []string{model.NewId(), model.NewId(), model.NewId()}
+	for i, userID := range userIDs {
+		_, err := ss.Channel().SaveMember(rctx, &model.ChannelMember{
+			ChannelId:    c1.Id,
+			UserId:       userID,
+			NotifyProps:  model.GetDefaultChannelNotifyProps(),
+			LastViewedAt: int64(100 * (i + 1)),
+		})
+		require.NoError(t, err)
+	}
+
+	times, err := ss.Channel().GetMembersLastViewedAtSince(context.Background(), c1.Id, 200)
+	require.NoError(t, err)
+	require.Equal(t, map[string]int64{userIDs[1]: 200, userIDs[2]: 300}, times)
+
+	times, err = ss.Channel().GetMembersLastViewedAtSince(context.Background(), c1.Id, 1000)
+	require.NoError(t, err)
+	require.Empty(t, times)
+
+	times, err = ss.Channel().GetMembersLastViewedAtSince(context.Background(), model.NewId(), 0)
+	require.NoError(t, err)
+	require.Empty(t, times)
+}
+
 func testChannelStoreGetMemberForPost(t *testing.T, rctx request.CTX, ss store.Store) {
 	ch := &model.Channel{
 		TeamId:      model.NewId(),
@@ -55,6 +55,8 @@ func testCommandStoreGet(t *testing.T, rctx request.CTX, ss store.Store) {
 	r1, nErr := ss.Command().Get(o1.Id)
 	require.NoError(t, nErr)
 	require.Equal(t, r1.CreateAt, o1.CreateAt, "invalid returned command")
+	require.NotEmpty(t, r1.SigningSecret)
+	require.Equal(t, o1.SigningSecret, r1.SigningSecret)
 
 	_, err := ss.Command().Get("123")
 	require.Error(t, err)
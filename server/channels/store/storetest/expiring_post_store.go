@@ -0,0 +1,118 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package storetest
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/shared/request"
+	"github.com/mattermost/mattermost/server/v8/channels/store"
+)
+
+func TestExpiringPostStore(t *testing.T, rctx request.CTX, ss store.Store, s SqlStore) {
+	t.Run("Save", func(t *testing.T) { testExpiringPostStoreSave(t, rctx, ss) })
+	t.Run("GetExpired", func(t *testing.T) { testExpiringPostStoreGetExpired(t, rctx, ss) })
+	t.Run("DeleteForPosts", func(t *testing.T) { testExpiringPostStoreDeleteForPosts(t, rctx, ss) })
+}
+
+func cleanupExpiringPosts(t *testing.T, ss store.Store) {
+	expiringPosts, err := ss.ExpiringPost().GetExpired(model.GetMillis()+1000*60*60*24*365*10, 10000)
+	require.NoError(t, err)
+
+	postIDs := make([]string, 0, len(expiringPosts))
+	for _, expiringPost := range expiringPosts {
+		postIDs = append(postIDs, expiringPost.PostId)
+	}
+	require.NoError(t, ss.ExpiringPost().DeleteForPosts(postIDs))
+}
+
+func testExpiringPostStoreSave(t *testing.T, rctx request.CTX, ss store.Store) {
+	t.Cleanup(func() { cleanupExpiringPosts(t, ss) })
+
+	t.Run("valid", func(t *testing.T) {
+		expiringPost := &model.ExpiringPost{
+			PostId:    model.NewId(),
+			ChannelId: model.NewId(),
+			ExpireAt:  model.GetMillis(),
+		}
+
+		saved, err := ss.ExpiringPost().Save(expiringPost)
+		require.NoError(t, err)
+		assert.Equal(t, expiringPost, saved)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		_, err := ss.ExpiringPost().Save(&model.ExpiringPost{
+			PostId:    "invalid",
+			ChannelId: model.NewId(),
+			ExpireAt:  model.GetMillis(),
+		})
+		require.Error(t, err)
+	})
+
+	t.Run("duplicate", func(t *testing.T) {
+		expiringPost := &model.ExpiringPost{
+			PostId:    model.NewId(),
+			ChannelId: model.NewId(),
+			ExpireAt:  model.GetMillis(),
+		}
+
+		_, err := ss.ExpiringPost().Save(expiringPost)
+		require.NoError(t, err)
+
+		_, err = ss.ExpiringPost().Save(expiringPost)
+		require.Error(t, err)
+	})
+}
+
+func testExpiringPostStoreGetExpired(t *testing.T, rctx request.CTX, ss store.Store) {
+	t.Cleanup(func() { cleanupExpiringPosts(t, ss) })
+	cleanupExpiringPosts(t, ss)
+
+	now := model.GetMillis()
+	channelID := model.NewId()
+
+	expired1, err := ss.ExpiringPost().Save(&model.ExpiringPost{PostId: model.NewId(), ChannelId: channelID, ExpireAt: now - 2000})
+	require.NoError(t, err)
+	expired2, err := ss.ExpiringPost().Save(&model.ExpiringPost{PostId: model.NewId(), ChannelId: channelID, ExpireAt: now - 1000})
+	require.NoError(t, err)
+	_, err = ss.ExpiringPost().Save(&model.ExpiringPost{PostId: model.NewId(), ChannelId: channelID, ExpireAt: now + 60000})
+	require.NoError(t, err)
+
+	t.Run("returns only expired posts, oldest first", func(t *testing.T) {
+		expiringPosts, err := ss.ExpiringPost().GetExpired(now, 10)
+		require.NoError(t, err)
+		assert.Equal(t, []*model.ExpiringPost{expired1, expired2}, expiringPosts)
+	})
+
+	t.Run("limit", func(t *testing.T) {
+		expiringPosts, err := ss.ExpiringPost().GetExpired(now, 1)
+		require.NoError(t, err)
+		assert.Equal(t, []*model.ExpiringPost{expired1}, expiringPosts)
+	})
+}
+
+func testExpiringPostStoreDeleteForPosts(t *testing.T, rctx request.CTX, ss store.Store) {
+	t.Cleanup(func() { cleanupExpiringPosts(t, ss) })
+	cleanupExpiringPosts(t, ss)
+
+	now := model.GetMillis()
+	channelID := model.NewId()
+
+	expired1, err := ss.ExpiringPost().Save(&model.ExpiringPost{PostId: model.NewId(), ChannelId: channelID, ExpireAt: now - 1000})
+	require.NoError(t, err)
+	expired2, err := ss.ExpiringPost().Save(&model.ExpiringPost{PostId: model.NewId(), ChannelId: channelID, ExpireAt: now - 1000})
+	require.NoError(t, err)
+
+	require.NoError(t, ss.ExpiringPost().DeleteForPosts(nil))
+	require.NoError(t, ss.ExpiringPost().DeleteForPosts([]string{expired1.PostId}))
+
+	expiringPosts, err := ss.ExpiringPost().GetExpired(now, 10)
+	require.NoError(t, err)
+	assert.Equal(t, []*model.ExpiringPost{expired2}, expiringPosts)
+}
@@ -0,0 +1,182 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package storetest
+
+import (
+	"crypto/sha256"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/shared/request"
+	"github.com/mattermost/mattermost/server/v8/channels/store"
+)
+
+func TestFileBlobStore(t *testing.T, rctx request.CTX, ss store.Store, s SqlStore) {
+	t.Cleanup(func() {
+		s.GetMasterX().Exec("TRUNCATE FileBlobs")
+	})
+	t.Run("AcquireRelease", func(t *testing.T) { testFileBlobStoreAcquireRelease(t, rctx, ss) })
+	t.Run("Unreferenced", func(t *testing.T) { testFileBlobStoreUnreferenced(t, rctx, ss) })
+	t.Run("GetSavedBytes", func(t *testing.T) { testFileBlobStoreGetSavedBytes(t, rctx, ss) })
+	t.Run("FileInfoPermanentDeleteReleases", func(t *testing.T) { testFileBlobStoreFileInfoPermanentDeleteReleases(t, rctx, ss) })
+}
+
+func newFileBlobHash() string {
+	sum := sha256.Sum256([]byte(model.NewId()))
+	return model.FileBlobHash(sum[:])
+}
+
+func testFileBlobStoreAcquireRelease(t *testing.T, rctx request.CTX, ss store.Store) {
+	hash := newFileBlobHash()
+
+	blob, created, err := ss.FileBlob().Acquire(hash, model.FileBlobPath(hash), 100)
+	require.NoError(t, err)
+	assert.True(t, created)
+	assert.EqualValues(t, 1, blob.RefCount)
+
+	blob, created, err = ss.FileBlob().Acquire(hash, "ignored", 100)
+	require.NoError(t, err)
+	assert.False(t, created)
+	assert.EqualValues(t, 2, blob.RefCount)
+	assert.Equal(t, model.FileBlobPath(hash), blob.Path)
+
+	refCount, err := ss.FileBlob().Release(hash)
+	require.NoError(t, err)
+	assert.EqualValues(t, 1, refCount)
+
+	refCount, err = ss.FileBlob().Release(hash)
+	require.NoError(t, err)
+	assert.EqualValues(t, 0, refCount)
+
+	// Releasing an unreferenced blob does not make the count negative.
+	refCount, err = ss.FileBlob().Release(hash)
+	require.NoError(t, err)
+	assert.EqualValues(t, 0, refCount)
+
+	_, err = ss.FileBlob().Release(newFileBlobHash())
+	var nfErr *store.ErrNotFound
+	require.ErrorAs(t, err, &nfErr)
+
+	_, _, err = ss.FileBlob().Acquire("invalid", "path", 1)
+	require.Error(t, err)
+}
+
+func testFileBlobStoreUnreferenced(t *testing.T, rctx request.CTX, ss store.Store) {
+	referenced := newFileBlobHash()
+	_, _, err := ss.FileBlob().Acquire(referenced, model.FileBlobPath(referenced), 10)
+	require.NoError(t, err)
+
+	unreferenced := newFileBlobHash()
+	_, _, err = ss.FileBlob().Acquire(unreferenced, model.FileBlobPath(unreferenced), 10)
+	require.NoError(t, err)
+	_, err = ss.FileBlob().Release(unreferenced)
+	require.NoError(t, err)
+
+	blobs, err := ss.FileBlob().GetUnreferenced("", 100)
+	require.NoError(t, err)
+	hashes := make([]string, 0, len(blobs))
+	for _, blob := range blobs {
+		hashes = append(hashes, blob.Hash)
+	}
+	assert.Contains(t, hashes, unreferenced)
+	assert.NotContains(t, hashes, referenced)
+
+	// The blobs are paged by hash.
+	blobs, err = ss.FileBlob().GetUnreferenced(unreferenced, 100)
+	require.NoError(t, err)
+	for _, blob := range blobs {
+		assert.Greater(t, blob.Hash, unreferenced)
+	}
+
+	tombstoned, err := ss.FileBlob().Tombstone(referenced)
+	require.NoError(t, err)
+	assert.False(t, tombstoned)
+
+	tombstoned, err = ss.FileBlob().Tombstone(unreferenced)
+	require.NoError(t, err)
+	assert.True(t, tombstoned)
+
+	blob, err := ss.FileBlob().Get(unreferenced)
+	require.NoError(t, err)
+	assert.EqualValues(t, model.FileBlobTombstoneRefCount, blob.RefCount)
+
+	// Tombstoned blobs can't be acquired or released until they are deleted.
+	_, _, err = ss.FileBlob().Acquire(unreferenced, model.FileBlobPath(unreferenced), 10)
+	var nfErr *store.ErrNotFound
+	require.ErrorAs(t, err, &nfErr)
+	refCount, err := ss.FileBlob().Release(unreferenced)
+	require.NoError(t, err)
+	assert.EqualValues(t, model.FileBlobTombstoneRefCount, refCount)
+
+	blobs, err = ss.FileBlob().GetUnreferenced("", 100)
+	require.NoError(t, err)
+	hashes = make([]string, 0, len(blobs))
+	for _, blob := range blobs {
+		hashes = append(hashes, blob.Hash)
+	}
+	assert.Contains(t, hashes, unreferenced)
+
+	require.NoError(t, ss.FileBlob().DeleteTombstoned(referenced))
+	_, err = ss.FileBlob().Get(referenced)
+	require.NoError(t, err)
+
+	require.NoError(t, ss.FileBlob().DeleteTombstoned(unreferenced))
+	_, err = ss.FileBlob().Get(unreferenced)
+	require.ErrorAs(t, err, &nfErr)
+
+	_, created, err := ss.FileBlob().Acquire(unreferenced, model.FileBlobPath(unreferenced), 10)
+	require.NoError(t, err)
+	assert.True(t, created)
+}
+
+func testFileBlobStoreGetSavedBytes(t *testing.T, rctx request.CTX, ss store.Store) {
+	before, err := ss.FileBlob().GetSavedBytes()
+	require.NoError(t, err)
+
+	hash := newFileBlobHash()
+	for i := 0; i < 3; i++ {
+		_, _, err = ss.FileBlob().Acquire(hash, model.FileBlobPath(hash), 1000)
+		require.NoError(t, err)
+	}
+
+	after, err := ss.FileBlob().GetSavedBytes()
+	require.NoError(t, err)
+	assert.EqualValues(t, 2000, after-before)
+}
+
+func testFileBlobStoreFileInfoPermanentDeleteReleases(t *testing.T, rctx request.CTX, ss store.Store) {
+	hash := newFileBlobHash()
+	path := model.FileBlobPath(hash)
+	creatorID := model.NewId()
+
+	var infos []*model.FileInfo
+	for i := 0; i < 4; i++ {
+		_, _, err := ss.FileBlob().Acquire(hash, path, 10)
+		require.NoError(t, err)
+
+		info, err := ss.FileInfo().Save(rctx, &model.FileInfo{
+			CreatorId:   creatorID,
+			Path:        path,
+			ContentHash: hash,
+		})
+		require.NoError(t, err)
+		infos = append(infos, info)
+	}
+
+	require.NoError(t, ss.FileInfo().PermanentDelete(rctx, infos[0].Id))
+	blob, err := ss.FileBlob().Get(hash)
+	require.NoError(t, err)
+	assert.EqualValues(t, 3, blob.RefCount)
+
+	deleted, err := ss.FileInfo().PermanentDeleteByUser(rctx, creatorID)
+	require.NoError(t, err)
+	assert.EqualValues(t, 3, deleted)
+
+	blob, err = ss.FileBlob().Get(hash)
+	require.NoError(t, err)
+	assert.EqualValues(t, 0, blob.RefCount)
+}
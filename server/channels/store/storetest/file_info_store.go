@@ -6,6 +6,7 @@ package storetest
 import (
 	"fmt"
 	"sort"
+	"sync"
 	"testing"
 	"time"
 
@@ -33,6 +34,11 @@ func TestFileInfoStore(t *testing.T, rctx request.CTX, ss store.Store, s SqlStor
 	t.Run("FileInfoPermanentDeleteBatch", func(t *testing.T) { testFileInfoPermanentDeleteBatch(t, rctx, ss) })
 	t.Run("FileInfoPermanentDeleteByUser", func(t *testing.T) { testFileInfoPermanentDeleteByUser(t, rctx, ss) })
 	t.Run("FileInfoUpdateMinipreview", func(t *testing.T) { testFileInfoUpdateMinipreview(t, rctx, ss) })
+	t.Run("FileInfoSetContentHash", func(t *testing.T) { testFileInfoSetContentHash(t, rctx, ss) })
+	t.Run("FileInfoPermanentDeleteReleasesOnce", func(t *testing.T) { testFileInfoPermanentDeleteReleasesOnce(t, rctx, ss) })
+	t.Run("FileInfoSetScanStatus", func(t *testing.T) { testFileInfoSetScanStatus(t, rctx, ss) })
+	t.Run("FileInfoSetRenditionPath", func(t *testing.T) { testFileInfoSetRenditionPath(t, rctx, ss) })
+	t.Run("FileInfoSetMediaPreviews", func(t *testing.T) { testFileInfoSetMediaPreviews(t, rctx, ss) })
 	t.Run("GetFilesBatchForIndexing", func(t *testing.T) { testFileInfoStoreGetFilesBatchForIndexing(t, rctx, ss) })
 	t.Run("CountAll", func(t *testing.T) { testFileInfoStoreCountAll(t, rctx, ss) })
 	t.Run("GetStorageUsage", func(t *testing.T) { testFileInfoGetStorageUsage(t, rctx, ss) })
@@ -654,6 +660,120 @@ func testFileInfoPermanentDeleteByUser(t *testing.T, rctx request.CTX, ss store.
 	require.NoError(t, err)
 }
 
+func testFileInfoPermanentDeleteReleasesOnce(t *testing.T, rctx request.CTX, ss store.Store) {
+	hash := newFileBlobHash()
+	for i := 0; i < 2; i++ {
+		_, _, err := ss.FileBlob().Acquire(hash, model.FileBlobPath(hash), 10)
+		require.NoError(t, err)
+	}
+
+	info, err := ss.FileInfo().Save(rctx, &model.FileInfo{
+		CreatorId:   model.NewId(),
+		Path:        model.FileBlobPath(hash),
+		ContentHash: hash,
+	})
+	require.NoError(t, err)
+
+	// The file info deleted concurrently only releases its blob once.
+	var wg sync.WaitGroup
+	for i := 0; i < 2; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			assert.NoError(t, ss.FileInfo().PermanentDelete(rctx, info.Id))
+		}()
+	}
+	wg.Wait()
+
+	blob, err := ss.FileBlob().Get(hash)
+	require.NoError(t, err)
+	assert.EqualValues(t, 1, blob.RefCount)
+}
+
+func testFileInfoSetContentHash(t *testing.T, rctx request.CTX, ss store.Store) {
+	info, err := ss.FileInfo().Save(rctx, &model.FileInfo{
+		CreatorId: model.NewId(),
+		Path:      "file.txt",
+	})
+	require.NoError(t, err)
+	defer ss.FileInfo().PermanentDelete(rctx, info.Id)
+
+	hash := newFileBlobHash()
+	err = ss.FileInfo().SetContentHash(rctx, info.Id, hash, model.FileBlobPath(hash))
+	require.NoError(t, err)
+
+	saved, err := ss.FileInfo().GetFromMaster(info.Id)
+	require.NoError(t, err)
+	assert.Equal(t, hash, saved.ContentHash)
+	assert.Equal(t, model.FileBlobPath(hash), saved.Path)
+
+	count, err := ss.FileInfo().CountByPath("file.txt")
+	require.NoError(t, err)
+	assert.EqualValues(t, 0, count)
+	count, err = ss.FileInfo().CountByPath(model.FileBlobPath(hash))
+	require.NoError(t, err)
+	assert.EqualValues(t, 1, count)
+
+	// The thumbnails, previews and renditions are counted as well.
+	thumbnail, err := ss.FileInfo().Save(rctx, &model.FileInfo{
+		CreatorId:     model.NewId(),
+		Path:          "other.png",
+		ThumbnailPath: "file_thumb.jpg",
+	})
+	require.NoError(t, err)
+	defer ss.FileInfo().PermanentDelete(rctx, thumbnail.Id)
+	count, err = ss.FileInfo().CountByPath("file_thumb.jpg")
+	require.NoError(t, err)
+	assert.EqualValues(t, 1, count)
+
+	// A file info is only pointed at a blob once.
+	err = ss.FileInfo().SetContentHash(rctx, info.Id, newFileBlobHash(), "other")
+	var nfErr *store.ErrNotFound
+	require.ErrorAs(t, err, &nfErr)
+}
+
+func testFileInfoSetScanStatus(t *testing.T, rctx request.CTX, ss store.Store) {
+	creatorID := model.NewId()
+	infos := make(map[string]*model.FileInfo)
+	for _, status := range []string{model.FileScanStatusPending, model.FileScanStatusClean, model.FileScanStatusInfected} {
+		info, err := ss.FileInfo().Save(rctx, &model.FileInfo{
+			CreatorId:  creatorID,
+			Path:       "file.txt",
+			ScanStatus: status,
+		})
+		require.NoError(t, err)
+		infos[status] = info
+	}
+	defer ss.FileInfo().PermanentDeleteByUser(rctx, creatorID)
+
+	saved, err := ss.FileInfo().GetFromMaster(infos[model.FileScanStatusPending].Id)
+	require.NoError(t, err)
+	assert.Equal(t, model.FileScanStatusPending, saved.ScanStatus)
+
+	err = ss.FileInfo().SetScanStatus(rctx, saved.Id, model.FileScanStatusFailed, "timeout")
+	require.NoError(t, err)
+
+	saved, err = ss.FileInfo().GetFromMaster(saved.Id)
+	require.NoError(t, err)
+	assert.Equal(t, model.FileScanStatusFailed, saved.ScanStatus)
+	assert.Equal(t, "timeout", saved.ScanResult)
+
+	quarantined, err := ss.FileInfo().GetWithOptions(0, 10, &model.GetFileInfosOptions{
+		UserIds:      []string{creatorID},
+		ScanStatuses: []string{model.FileScanStatusInfected, model.FileScanStatusFailed},
+	})
+	require.NoError(t, err)
+	ids := make([]string, 0, len(quarantined))
+	for _, info := range quarantined {
+		ids = append(ids, info.Id)
+	}
+	assert.ElementsMatch(t, []string{infos[model.FileScanStatusPending].Id, infos[model.FileScanStatusInfected].Id}, ids)
+
+	err = ss.FileInfo().SetScanStatus(rctx, model.NewId(), model.FileScanStatusClean, "")
+	var nfErr *store.ErrNotFound
+	require.ErrorAs(t, err, &nfErr)
+}
+
 func testFileInfoUpdateMinipreview(t *testing.T, rctx request.CTX, ss store.Store) {
 	info := &model.FileInfo{
 		CreatorId: model.NewId(),
@@ -941,3 +1061,85 @@ func testGetUptoNSizeFileTime(t *testing.T, rctx request.CTX, ss store.Store, s
 	require.NoError(t, err)
 	assert.Equal(t, f2.CreateAt, createAt)
 }
+
+func testFileInfoSetRenditionPath(t *testing.T, rctx request.CTX, ss store.Store) {
+	info, err := ss.FileInfo().Save(rctx, &model.FileInfo{
+		CreatorId:  model.NewId(),
+		Path:       "dir/video.mov",
+		MimeType:   "video/quicktime",
+		Duration:   62500,
+		VideoCodec: "hevc",
+		AudioCodec: "aac",
+	})
+	require.NoError(t, err)
+	defer ss.FileInfo().PermanentDelete(rctx, info.Id)
+
+	err = ss.FileInfo().SetRenditionPath(rctx, info.Id, "dir/video_web.mp4")
+	require.NoError(t, err)
+
+	saved, err := ss.FileInfo().GetFromMaster(info.Id)
+	require.NoError(t, err)
+	assert.Equal(t, "dir/video_web.mp4", saved.RenditionPath)
+	assert.Equal(t, int64(62500), saved.Duration)
+	assert.Equal(t, "hevc", saved.VideoCodec)
+	assert.Equal(t, "aac", saved.AudioCodec)
+
+	// The rendition path isn't changed by an upsert.
+	saved.RenditionPath = ""
+	saved.Duration = 1000
+	_, err = ss.FileInfo().Upsert(rctx, saved)
+	require.NoError(t, err)
+
+	saved, err = ss.FileInfo().GetFromMaster(info.Id)
+	require.NoError(t, err)
+	assert.Equal(t, "dir/video_web.mp4", saved.RenditionPath)
+	assert.Equal(t, int64(1000), saved.Duration)
+
+	err = ss.FileInfo().SetRenditionPath(rctx, model.NewId(), "dir/video_web.mp4")
+	var nfErr *store.ErrNotFound
+	require.ErrorAs(t, err, &nfErr)
+}
+
+func testFileInfoSetMediaPreviews(t *testing.T, rctx request.CTX, ss store.Store) {
+	info, err := ss.FileInfo().Save(rctx, &model.FileInfo{
+		CreatorId: model.NewId(),
+		Path:      "dir/video.mov",
+		MimeType:  "video/quicktime",
+	})
+	require.NoError(t, err)
+	defer ss.FileInfo().PermanentDelete(rctx, info.Id)
+
+	stale := *info
+	err = ss.FileInfo().SetContent(rctx, info.Id, "content")
+	require.NoError(t, err)
+
+	miniPreview := []byte{1, 2, 3}
+	stale.Duration = 62500
+	stale.VideoCodec = "hevc"
+	stale.AudioCodec = "aac"
+	stale.Width = 1080
+	stale.Height = 1920
+	stale.ThumbnailPath = "dir/video_thumb.jpg"
+	stale.PreviewPath = "dir/video_preview.jpg"
+	stale.HasPreviewImage = true
+	stale.MiniPreview = &miniPreview
+	err = ss.FileInfo().SetMediaPreviews(rctx, &stale)
+	require.NoError(t, err)
+
+	saved, err := ss.FileInfo().GetFromMaster(info.Id)
+	require.NoError(t, err)
+	assert.Equal(t, int64(62500), saved.Duration)
+	assert.Equal(t, "hevc", saved.VideoCodec)
+	assert.Equal(t, "aac", saved.AudioCodec)
+	assert.Equal(t, 1080, saved.Width)
+	assert.Equal(t, 1920, saved.Height)
+	assert.Equal(t, "dir/video_thumb.jpg", saved.ThumbnailPath)
+	assert.Equal(t, "dir/video_preview.jpg", saved.PreviewPath)
+	assert.True(t, saved.HasPreviewImage)
+	assert.Equal(t, &miniPreview, saved.MiniPreview)
+	assert.Equal(t, "content", saved.Content)
+
+	err = ss.FileInfo().SetMediaPreviews(rctx, &model.FileInfo{Id: model.NewId()})
+	var nfErr *store.ErrNotFound
+	assert.ErrorAs(t, err, &nfErr)
+}
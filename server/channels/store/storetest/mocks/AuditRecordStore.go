@@ -0,0 +1,117 @@
+// Code generated by mockery v2.42.2. DO NOT EDIT.
+
+// Regenerate this file using `make store-mocks`.
+
+package mocks
+
+import (
+	model "github.com/mattermost/mattermost/server/public/model"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// AuditRecordStore is an autogenerated mock type for the AuditRecordStore type
+type AuditRecordStore struct {
+	mock.Mock
+}
+
+// PermanentDeleteBatch provides a mock function with given fields: endTime, limit
+func (_m *AuditRecordStore) PermanentDeleteBatch(endTime int64, limit int64) (int64, error) {
+	ret := _m.Called(endTime, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for PermanentDeleteBatch")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(int64, int64) (int64, error)); ok {
+		return rf(endTime, limit)
+	}
+	if rf, ok := ret.Get(0).(func(int64, int64) int64); ok {
+		r0 = rf(endTime, limit)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(int64, int64) error); ok {
+		r1 = rf(endTime, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Save provides a mock function with given fields: record
+func (_m *AuditRecordStore) Save(record *model.AuditRecord) (*model.AuditRecord, error) {
+	ret := _m.Called(record)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Save")
+	}
+
+	var r0 *model.AuditRecord
+	var r1 error
+	if rf, ok := ret.Get(0).(func(*model.AuditRecord) (*model.AuditRecord, error)); ok {
+		return rf(record)
+	}
+	if rf, ok := ret.Get(0).(func(*model.AuditRecord) *model.AuditRecord); ok {
+		r0 = rf(record)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.AuditRecord)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(*model.AuditRecord) error); ok {
+		r1 = rf(record)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Search provides a mock function with given fields: search
+func (_m *AuditRecordStore) Search(search *model.AuditRecordSearch) ([]*model.AuditRecord, error) {
+	ret := _m.Called(search)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Search")
+	}
+
+	var r0 []*model.AuditRecord
+	var r1 error
+	if rf, ok := ret.Get(0).(func(*model.AuditRecordSearch) ([]*model.AuditRecord, error)); ok {
+		return rf(search)
+	}
+	if rf, ok := ret.Get(0).(func(*model.AuditRecordSearch) []*model.AuditRecord); ok {
+		r0 = rf(search)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*model.AuditRecord)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(*model.AuditRecordSearch) error); ok {
+		r1 = rf(search)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewAuditRecordStore creates a new instance of AuditRecordStore. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewAuditRecordStore(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *AuditRecordStore {
+	mock := &AuditRecordStore{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
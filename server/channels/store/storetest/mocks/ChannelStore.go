@@ -1730,6 +1730,36 @@ func (_m *ChannelStore) GetMembersInfoByChannelIds(channelIDs []string) (map[str
 	return r0, r1
 }
 
+// GetMembersLastViewedAtSince provides a mock function with given fields: ctx, channelID, since
+func (_m *ChannelStore) GetMembersLastViewedAtSince(ctx context.Context, channelID string, since int64) (map[string]int64, error) {
+	ret := _m.Called(ctx, channelID, since)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetMembersLastViewedAtSince")
+	}
+
+	var r0 map[string]int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, int64) (map[string]int64, error)); ok {
+		return rf(ctx, channelID, since)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, int64) map[string]int64); ok {
+		r0 = rf(ctx, channelID, since)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(map[string]int64)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, int64) error); ok {
+		r1 = rf(ctx, channelID, since)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetMoreChannels provides a mock function with given fields: teamID, userID, offset, limit
 func (_m *ChannelStore) GetMoreChannels(teamID string, userID string, offset int, limit int) (model.ChannelList, error) {
 	ret := _m.Called(teamID, userID, offset, limit)
@@ -0,0 +1,107 @@
+// Code generated by mockery v2.42.2. DO NOT EDIT.
+
+// Regenerate this file using `make store-mocks`.
+
+package mocks
+
+import (
+	model "github.com/mattermost/mattermost/server/public/model"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// ExpiringPostStore is an autogenerated mock type for the ExpiringPostStore type
+type ExpiringPostStore struct {
+	mock.Mock
+}
+
+// DeleteForPosts provides a mock function with given fields: postIDs
+func (_m *ExpiringPostStore) DeleteForPosts(postIDs []string) error {
+	ret := _m.Called(postIDs)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteForPosts")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func([]string) error); ok {
+		r0 = rf(postIDs)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetExpired provides a mock function with given fields: now, limit
+func (_m *ExpiringPostStore) GetExpired(now int64, limit int) ([]*model.ExpiringPost, error) {
+	ret := _m.Called(now, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetExpired")
+	}
+
+	var r0 []*model.ExpiringPost
+	var r1 error
+	if rf, ok := ret.Get(0).(func(int64, int) ([]*model.ExpiringPost, error)); ok {
+		return rf(now, limit)
+	}
+	if rf, ok := ret.Get(0).(func(int64, int) []*model.ExpiringPost); ok {
+		r0 = rf(now, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*model.ExpiringPost)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(int64, int) error); ok {
+		r1 = rf(now, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Save provides a mock function with given fields: expiringPost
+func (_m *ExpiringPostStore) Save(expiringPost *model.ExpiringPost) (*model.ExpiringPost, error) {
+	ret := _m.Called(expiringPost)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Save")
+	}
+
+	var r0 *model.ExpiringPost
+	var r1 error
+	if rf, ok := ret.Get(0).(func(*model.ExpiringPost) (*model.ExpiringPost, error)); ok {
+		return rf(expiringPost)
+	}
+	if rf, ok := ret.Get(0).(func(*model.ExpiringPost) *model.ExpiringPost); ok {
+		r0 = rf(expiringPost)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.ExpiringPost)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(*model.ExpiringPost) error); ok {
+		r1 = rf(expiringPost)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewExpiringPostStore creates a new instance of ExpiringPostStore. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewExpiringPostStore(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *ExpiringPostStore {
+	mock := &ExpiringPostStore{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -66,6 +66,34 @@ func (_m *FileInfoStore) CountAll() (int64, error) {
 	return r0, r1
 }
 
+// CountByPath provides a mock function with given fields: path
+func (_m *FileInfoStore) CountByPath(path string) (int64, error) {
+	ret := _m.Called(path)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CountByPath")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) (int64, error)); ok {
+		return rf(path)
+	}
+	if rf, ok := ret.Get(0).(func(string) int64); ok {
+		r0 = rf(path)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(path)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // DeleteForPost provides a mock function with given fields: c, postID
 func (_m *FileInfoStore) DeleteForPost(c request.CTX, postID string) (string, error) {
 	ret := _m.Called(c, postID)
@@ -547,6 +575,78 @@ func (_m *FileInfoStore) SetContent(ctx request.CTX, fileID string, content stri
 	return r0
 }
 
+// SetContentHash provides a mock function with given fields: ctx, fileID, contentHash, path
+func (_m *FileInfoStore) SetContentHash(ctx request.CTX, fileID string, contentHash string, path string) error {
+	ret := _m.Called(ctx, fileID, contentHash, path)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetContentHash")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(request.CTX, string, string, string) error); ok {
+		r0 = rf(ctx, fileID, contentHash, path)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SetMediaPreviews provides a mock function with given fields: ctx, info
+func (_m *FileInfoStore) SetMediaPreviews(ctx request.CTX, info *model.FileInfo) error {
+	ret := _m.Called(ctx, info)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetMediaPreviews")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(request.CTX, *model.FileInfo) error); ok {
+		r0 = rf(ctx, info)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SetRenditionPath provides a mock function with given fields: ctx, fileID, path
+func (_m *FileInfoStore) SetRenditionPath(ctx request.CTX, fileID string, path string) error {
+	ret := _m.Called(ctx, fileID, path)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetRenditionPath")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(request.CTX, string, string) error); ok {
+		r0 = rf(ctx, fileID, path)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SetScanStatus provides a mock function with given fields: ctx, fileID, status, result
+func (_m *FileInfoStore) SetScanStatus(ctx request.CTX, fileID string, status string, result string) error {
+	ret := _m.Called(ctx, fileID, status, result)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetScanStatus")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(request.CTX, string, string, string) error); ok {
+		r0 = rf(ctx, fileID, status, result)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // Upsert provides a mock function with given fields: rctx, info
 func (_m *FileInfoStore) Upsert(rctx request.CTX, info *model.FileInfo) (*model.FileInfo, error) {
 	ret := _m.Called(rctx, info)
@@ -1145,6 +1145,24 @@ func (_m *PostStore) PermanentDeleteByUser(rctx request.CTX, userID string) erro
 	return r0
 }
 
+// PermanentDeletePosts provides a mock function with given fields: rctx, posts
+func (_m *PostStore) PermanentDeletePosts(rctx request.CTX, posts []*model.Post) error {
+	ret := _m.Called(rctx, posts)
+
+	if len(ret) == 0 {
+		panic("no return value specified for PermanentDeletePosts")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(request.CTX, []*model.Post) error); ok {
+		r0 = rf(rctx, posts)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // Save provides a mock function with given fields: rctx, post
 func (_m *PostStore) Save(rctx request.CTX, post *model.Post) (*model.Post, error) {
 	ret := _m.Called(rctx, post)
@@ -44,6 +44,26 @@ func (_m *Store) Audit() store.AuditStore {
 	return r0
 }
 
+// AuditRecord provides a mock function with given fields:
+func (_m *Store) AuditRecord() store.AuditRecordStore {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for AuditRecord")
+	}
+
+	var r0 store.AuditRecordStore
+	if rf, ok := ret.Get(0).(func() store.AuditRecordStore); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(store.AuditRecordStore)
+		}
+	}
+
+	return r0
+}
+
 // Bot provides a mock function with given fields:
 func (_m *Store) Bot() store.BotStore {
 	ret := _m.Called()
@@ -314,6 +334,46 @@ func (_m *Store) Emoji() store.EmojiStore {
 	return r0
 }
 
+// ExpiringPost provides a mock function with given fields:
+func (_m *Store) ExpiringPost() store.ExpiringPostStore {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for ExpiringPost")
+	}
+
+	var r0 store.ExpiringPostStore
+	if rf, ok := ret.Get(0).(func() store.ExpiringPostStore); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(store.ExpiringPostStore)
+		}
+	}
+
+	return r0
+}
+
+// FileBlob provides a mock function with given fields:
+func (_m *Store) FileBlob() store.FileBlobStore {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for FileBlob")
+	}
+
+	var r0 store.FileBlobStore
+	if rf, ok := ret.Get(0).(func() store.FileBlobStore); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(store.FileBlobStore)
+		}
+	}
+
+	return r0
+}
+
 // FileInfo provides a mock function with given fields:
 func (_m *Store) FileInfo() store.FileInfoStore {
 	ret := _m.Called()
@@ -678,6 +738,26 @@ func (_m *Store) Plugin() store.PluginStore {
 	return r0
 }
 
+// PollVote provides a mock function with given fields:
+func (_m *Store) PollVote() store.PollVoteStore {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for PollVote")
+	}
+
+	var r0 store.PollVoteStore
+	if rf, ok := ret.Get(0).(func() store.PollVoteStore); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(store.PollVoteStore)
+		}
+	}
+
+	return r0
+}
+
 // Post provides a mock function with given fields:
 func (_m *Store) Post() store.PostStore {
 	ret := _m.Called()
@@ -718,6 +798,26 @@ func (_m *Store) PostAcknowledgement() store.PostAcknowledgementStore {
 	return r0
 }
 
+// PostEmbedding provides a mock function with given fields:
+func (_m *Store) PostEmbedding() store.PostEmbeddingStore {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for PostEmbedding")
+	}
+
+	var r0 store.PostEmbeddingStore
+	if rf, ok := ret.Get(0).(func() store.PostEmbeddingStore); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(store.PostEmbeddingStore)
+		}
+	}
+
+	return r0
+}
+
 // PostPersistentNotification provides a mock function with given fields:
 func (_m *Store) PostPersistentNotification() store.PostPersistentNotificationStore {
 	ret := _m.Called()
@@ -919,6 +1019,46 @@ func (_m *Store) Role() store.RoleStore {
 	return r0
 }
 
+// SavedSearch provides a mock function with given fields:
+func (_m *Store) SavedSearch() store.SavedSearchStore {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for SavedSearch")
+	}
+
+	var r0 store.SavedSearchStore
+	if rf, ok := ret.Get(0).(func() store.SavedSearchStore); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(store.SavedSearchStore)
+		}
+	}
+
+	return r0
+}
+
+// ScheduledPost provides a mock function with given fields:
+func (_m *Store) ScheduledPost() store.ScheduledPostStore {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for ScheduledPost")
+	}
+
+	var r0 store.ScheduledPostStore
+	if rf, ok := ret.Get(0).(func() store.ScheduledPostStore); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(store.ScheduledPostStore)
+		}
+	}
+
+	return r0
+}
+
 // Scheme provides a mock function with given fields:
 func (_m *Store) Scheme() store.SchemeStore {
 	ret := _m.Called()
@@ -1243,6 +1383,26 @@ func (_m *Store) UserTermsOfService() store.UserTermsOfServiceStore {
 	return r0
 }
 
+// WebAuthnCredential provides a mock function with given fields:
+func (_m *Store) WebAuthnCredential() store.WebAuthnCredentialStore {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for WebAuthnCredential")
+	}
+
+	var r0 store.WebAuthnCredentialStore
+	if rf, ok := ret.Get(0).(func() store.WebAuthnCredentialStore); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(store.WebAuthnCredentialStore)
+		}
+	}
+
+	return r0
+}
+
 // Webhook provides a mock function with given fields:
 func (_m *Store) Webhook() store.WebhookStore {
 	ret := _m.Called()
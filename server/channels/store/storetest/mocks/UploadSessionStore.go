@@ -93,6 +93,36 @@ func (_m *UploadSessionStore) GetForUser(userID string) ([]*model.UploadSession,
 	return r0, r1
 }
 
+// GetIncomplete provides a mock function with given fields:
+func (_m *UploadSessionStore) GetIncomplete() ([]*model.UploadSession, error) {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetIncomplete")
+	}
+
+	var r0 []*model.UploadSession
+	var r1 error
+	if rf, ok := ret.Get(0).(func() ([]*model.UploadSession, error)); ok {
+		return rf()
+	}
+	if rf, ok := ret.Get(0).(func() []*model.UploadSession); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*model.UploadSession)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Save provides a mock function with given fields: session
 func (_m *UploadSessionStore) Save(session *model.UploadSession) (*model.UploadSession, error) {
 	ret := _m.Called(session)
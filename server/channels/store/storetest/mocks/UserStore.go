@@ -269,6 +269,34 @@ func (_m *UserStore) Count(options model.UserCountOptions) (int64, error) {
 	return r0, r1
 }
 
+// CountNonAdminsUsingAuthService provides a mock function with given fields: authService
+func (_m *UserStore) CountNonAdminsUsingAuthService(authService string) (int64, error) {
+	ret := _m.Called(authService)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CountNonAdminsUsingAuthService")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) (int64, error)); ok {
+		return rf(authService)
+	}
+	if rf, ok := ret.Get(0).(func(string) int64); ok {
+		r0 = rf(authService)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(authService)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // DeactivateGuests provides a mock function with given fields:
 func (_m *UserStore) DeactivateGuests() ([]string, error) {
 	ret := _m.Called()
@@ -891,6 +919,36 @@ func (_m *UserStore) GetNewUsersForTeam(teamID string, offset int, limit int, vi
 	return r0, r1
 }
 
+// GetNonAdminsUsingAuthService provides a mock function with given fields: authService, offset, limit
+func (_m *UserStore) GetNonAdminsUsingAuthService(authService string, offset int, limit int) ([]*model.User, error) {
+	ret := _m.Called(authService, offset, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetNonAdminsUsingAuthService")
+	}
+
+	var r0 []*model.User
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, int, int) ([]*model.User, error)); ok {
+		return rf(authService, offset, limit)
+	}
+	if rf, ok := ret.Get(0).(func(string, int, int) []*model.User); ok {
+		r0 = rf(authService, offset, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*model.User)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string, int, int) error); ok {
+		r1 = rf(authService, offset, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetProfileByGroupChannelIdsForUser provides a mock function with given fields: userID, channelIds
 func (_m *UserStore) GetProfileByGroupChannelIdsForUser(userID string, channelIds []string) (map[string][]*model.User, error) {
 	ret := _m.Called(userID, channelIds)
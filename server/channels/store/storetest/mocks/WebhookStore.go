@@ -111,6 +111,36 @@ func (_m *WebhookStore) DeleteOutgoing(webhookID string, timestamp int64) error
 	return r0
 }
 
+// GetDueOutgoingDeliveries provides a mock function with given fields: beforeTime, afterTime, lastDeliveryID, limit
+func (_m *WebhookStore) GetDueOutgoingDeliveries(beforeTime int64, afterTime int64, lastDeliveryID string, limit uint64) ([]*model.OutgoingWebhookDelivery, error) {
+	ret := _m.Called(beforeTime, afterTime, lastDeliveryID, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetDueOutgoingDeliveries")
+	}
+
+	var r0 []*model.OutgoingWebhookDelivery
+	var r1 error
+	if rf, ok := ret.Get(0).(func(int64, int64, string, uint64) ([]*model.OutgoingWebhookDelivery, error)); ok {
+		return rf(beforeTime, afterTime, lastDeliveryID, limit)
+	}
+	if rf, ok := ret.Get(0).(func(int64, int64, string, uint64) []*model.OutgoingWebhookDelivery); ok {
+		r0 = rf(beforeTime, afterTime, lastDeliveryID, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*model.OutgoingWebhookDelivery)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(int64, int64, string, uint64) error); ok {
+		r1 = rf(beforeTime, afterTime, lastDeliveryID, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetIncoming provides a mock function with given fields: id, allowFromCache
 func (_m *WebhookStore) GetIncoming(id string, allowFromCache bool) (*model.IncomingWebhook, error) {
 	ret := _m.Called(id, allowFromCache)
@@ -441,6 +471,66 @@ func (_m *WebhookStore) GetOutgoingByTeamByUser(teamID string, userID string, of
 	return r0, r1
 }
 
+// GetOutgoingDeliveriesByHook provides a mock function with given fields: hookID, status, offset, limit
+func (_m *WebhookStore) GetOutgoingDeliveriesByHook(hookID string, status string, offset int, limit int) ([]*model.OutgoingWebhookDelivery, error) {
+	ret := _m.Called(hookID, status, offset, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetOutgoingDeliveriesByHook")
+	}
+
+	var r0 []*model.OutgoingWebhookDelivery
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, string, int, int) ([]*model.OutgoingWebhookDelivery, error)); ok {
+		return rf(hookID, status, offset, limit)
+	}
+	if rf, ok := ret.Get(0).(func(string, string, int, int) []*model.OutgoingWebhookDelivery); ok {
+		r0 = rf(hookID, status, offset, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*model.OutgoingWebhookDelivery)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string, string, int, int) error); ok {
+		r1 = rf(hookID, status, offset, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetOutgoingDelivery provides a mock function with given fields: id
+func (_m *WebhookStore) GetOutgoingDelivery(id string) (*model.OutgoingWebhookDelivery, error) {
+	ret := _m.Called(id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetOutgoingDelivery")
+	}
+
+	var r0 *model.OutgoingWebhookDelivery
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) (*model.OutgoingWebhookDelivery, error)); ok {
+		return rf(id)
+	}
+	if rf, ok := ret.Get(0).(func(string) *model.OutgoingWebhookDelivery); ok {
+		r0 = rf(id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.OutgoingWebhookDelivery)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetOutgoingList provides a mock function with given fields: offset, limit
 func (_m *WebhookStore) GetOutgoingList(offset int, limit int) ([]*model.OutgoingWebhook, error) {
 	ret := _m.Called(offset, limit)
@@ -578,6 +668,42 @@ func (_m *WebhookStore) PermanentDeleteOutgoingByUser(userID string) error {
 	return r0
 }
 
+// PermanentDeleteOutgoingDeliveriesByHook provides a mock function with given fields: hookID
+func (_m *WebhookStore) PermanentDeleteOutgoingDeliveriesByHook(hookID string) error {
+	ret := _m.Called(hookID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for PermanentDeleteOutgoingDeliveriesByHook")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string) error); ok {
+		r0 = rf(hookID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// PermanentDeleteOutgoingDelivery provides a mock function with given fields: id
+func (_m *WebhookStore) PermanentDeleteOutgoingDelivery(id string) error {
+	ret := _m.Called(id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for PermanentDeleteOutgoingDelivery")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string) error); ok {
+		r0 = rf(id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // SaveIncoming provides a mock function with given fields: webhook
 func (_m *WebhookStore) SaveIncoming(webhook *model.IncomingWebhook) (*model.IncomingWebhook, error) {
 	ret := _m.Called(webhook)
@@ -638,6 +764,36 @@ func (_m *WebhookStore) SaveOutgoing(webhook *model.OutgoingWebhook) (*model.Out
 	return r0, r1
 }
 
+// SaveOutgoingDelivery provides a mock function with given fields: delivery
+func (_m *WebhookStore) SaveOutgoingDelivery(delivery *model.OutgoingWebhookDelivery) (*model.OutgoingWebhookDelivery, error) {
+	ret := _m.Called(delivery)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SaveOutgoingDelivery")
+	}
+
+	var r0 *model.OutgoingWebhookDelivery
+	var r1 error
+	if rf, ok := ret.Get(0).(func(*model.OutgoingWebhookDelivery) (*model.OutgoingWebhookDelivery, error)); ok {
+		return rf(delivery)
+	}
+	if rf, ok := ret.Get(0).(func(*model.OutgoingWebhookDelivery) *model.OutgoingWebhookDelivery); ok {
+		r0 = rf(delivery)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.OutgoingWebhookDelivery)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(*model.OutgoingWebhookDelivery) error); ok {
+		r1 = rf(delivery)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // UpdateIncoming provides a mock function with given fields: webhook
 func (_m *WebhookStore) UpdateIncoming(webhook *model.IncomingWebhook) (*model.IncomingWebhook, error) {
 	ret := _m.Called(webhook)
@@ -698,6 +854,36 @@ func (_m *WebhookStore) UpdateOutgoing(hook *model.OutgoingWebhook) (*model.Outg
 	return r0, r1
 }
 
+// UpdateOutgoingDelivery provides a mock function with given fields: delivery
+func (_m *WebhookStore) UpdateOutgoingDelivery(delivery *model.OutgoingWebhookDelivery) (*model.OutgoingWebhookDelivery, error) {
+	ret := _m.Called(delivery)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateOutgoingDelivery")
+	}
+
+	var r0 *model.OutgoingWebhookDelivery
+	var r1 error
+	if rf, ok := ret.Get(0).(func(*model.OutgoingWebhookDelivery) (*model.OutgoingWebhookDelivery, error)); ok {
+		return rf(delivery)
+	}
+	if rf, ok := ret.Get(0).(func(*model.OutgoingWebhookDelivery) *model.OutgoingWebhookDelivery); ok {
+		r0 = rf(delivery)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.OutgoingWebhookDelivery)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(*model.OutgoingWebhookDelivery) error); ok {
+		r1 = rf(delivery)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // NewWebhookStore creates a new instance of WebhookStore. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewWebhookStore(t interface {
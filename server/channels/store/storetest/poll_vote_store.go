@@ -0,0 +1,166 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package storetest
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/shared/request"
+	"github.com/mattermost/mattermost/server/v8/channels/store"
+)
+
+func TestPollVoteStore(t *testing.T, rctx request.CTX, ss store.Store, s SqlStore) {
+	t.Run("SaveForUser", func(t *testing.T) { testPollVoteStoreSaveForUser(t, rctx, ss) })
+	t.Run("GetForPosts", func(t *testing.T) { testPollVoteStoreGetForPosts(t, rctx, ss) })
+	t.Run("DeleteForPost", func(t *testing.T) { testPollVoteStoreDeleteForPost(t, rctx, ss) })
+}
+
+func testPollVoteStoreSaveForUser(t *testing.T, rctx request.CTX, ss store.Store) {
+	post, err := ss.Post().Save(rctx, &model.Post{
+		ChannelId: model.NewId(),
+		UserId:    model.NewId(),
+		Message:   NewTestId(),
+		Type:      model.PostTypePoll,
+	})
+	require.NoError(t, err)
+
+	userID1 := model.NewId()
+	userID2 := model.NewId()
+	optionID1 := model.NewId()
+	optionID2 := model.NewId()
+
+	t.Run("save votes", func(t *testing.T) {
+		votes, err := ss.PollVote().SaveForUser(post.Id, userID1, []string{optionID1, optionID2})
+		require.NoError(t, err)
+		require.Len(t, votes, 2)
+
+		_, err = ss.PollVote().SaveForUser(post.Id, userID2, []string{optionID1})
+		require.NoError(t, err)
+
+		votes, err = ss.PollVote().GetForPost(post.Id)
+		require.NoError(t, err)
+		assert.Len(t, votes, 3)
+
+		updatedPost, err := ss.Post().GetSingle(rctx, post.Id, false)
+		require.NoError(t, err)
+		assert.Greater(t, updatedPost.UpdateAt, post.UpdateAt)
+	})
+
+	t.Run("replace votes", func(t *testing.T) {
+		_, err := ss.PollVote().SaveForUser(post.Id, userID1, []string{optionID2})
+		require.NoError(t, err)
+
+		votes, err := ss.PollVote().GetForPost(post.Id)
+		require.NoError(t, err)
+		require.Len(t, votes, 2)
+
+		for _, vote := range votes {
+			if vote.UserId == userID1 {
+				assert.Equal(t, optionID2, vote.OptionId)
+			}
+		}
+	})
+
+	t.Run("retract votes", func(t *testing.T) {
+		votes, err := ss.PollVote().SaveForUser(post.Id, userID1, nil)
+		require.NoError(t, err)
+		assert.Empty(t, votes)
+
+		votes, err = ss.PollVote().GetForPost(post.Id)
+		require.NoError(t, err)
+		require.Len(t, votes, 1)
+		assert.Equal(t, userID2, votes[0].UserId)
+	})
+
+	t.Run("invalid option", func(t *testing.T) {
+		_, err := ss.PollVote().SaveForUser(post.Id, userID1, []string{"invalid"})
+		require.Error(t, err)
+	})
+
+	t.Run("no votes", func(t *testing.T) {
+		votes, err := ss.PollVote().GetForPost(model.NewId())
+		require.NoError(t, err)
+		assert.Empty(t, votes)
+	})
+}
+
+func testPollVoteStoreGetForPosts(t *testing.T, rctx request.CTX, ss store.Store) {
+	newPoll := func() *model.Post {
+		post, err := ss.Post().Save(rctx, &model.Post{
+			ChannelId: model.NewId(),
+			UserId:    model.NewId(),
+			Message:   NewTestId(),
+			Type:      model.PostTypePoll,
+		})
+		require.NoError(t, err)
+		return post
+	}
+
+	post := newPoll()
+	otherPost := newPoll()
+	excludedPost := newPoll()
+	optionID := model.NewId()
+	for _, postID := range []string{post.Id, post.Id, otherPost.Id, excludedPost.Id} {
+		_, err := ss.PollVote().SaveForUser(postID, model.NewId(), []string{optionID})
+		require.NoError(t, err)
+	}
+
+	votes, err := ss.PollVote().GetForPosts([]string{post.Id, otherPost.Id})
+	require.NoError(t, err)
+	require.Len(t, votes, 3)
+	for _, vote := range votes {
+		assert.NotEqual(t, excludedPost.Id, vote.PostId)
+	}
+
+	t.Run("no posts", func(t *testing.T) {
+		votes, err := ss.PollVote().GetForPosts(nil)
+		require.NoError(t, err)
+		assert.Empty(t, votes)
+	})
+}
+
+func testPollVoteStoreDeleteForPost(t *testing.T, rctx request.CTX, ss store.Store) {
+	newPoll := func() *model.Post {
+		post, err := ss.Post().Save(rctx, &model.Post{
+			ChannelId: model.NewId(),
+			UserId:    model.NewId(),
+			Message:   NewTestId(),
+			Type:      model.PostTypePoll,
+		})
+		require.NoError(t, err)
+		return post
+	}
+
+	post := newPoll()
+	otherPost := newPoll()
+	optionID := model.NewId()
+	for _, postID := range []string{post.Id, otherPost.Id} {
+		_, err := ss.PollVote().SaveForUser(postID, model.NewId(), []string{optionID})
+		require.NoError(t, err)
+	}
+
+	err := ss.PollVote().DeleteForPost(post.Id)
+	require.NoError(t, err)
+
+	votes, err := ss.PollVote().GetForPost(post.Id)
+	require.NoError(t, err)
+	assert.Empty(t, votes)
+
+	votes, err = ss.PollVote().GetForPost(otherPost.Id)
+	require.NoError(t, err)
+	assert.Len(t, votes, 1)
+
+	t.Run("permanently deleting the post deletes its votes", func(t *testing.T) {
+		err := ss.Post().PermanentDeleteByUser(rctx, otherPost.UserId)
+		require.NoError(t, err)
+
+		votes, err := ss.PollVote().GetForPost(otherPost.Id)
+		require.NoError(t, err)
+		assert.Empty(t, votes)
+	})
+}
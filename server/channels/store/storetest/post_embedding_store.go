@@ -0,0 +1,182 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package storetest
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/shared/request"
+	"github.com/mattermost/mattermost/server/v8/channels/store"
+)
+
+func TestPostEmbeddingStore(t *testing.T, rctx request.CTX, ss store.Store, s SqlStore) {
+	t.Cleanup(func() {
+		s.GetMasterX().Exec("DELETE FROM PostEmbeddings")
+	})
+	t.Run("SaveGet", func(t *testing.T) { testPostEmbeddingStoreSaveGet(t, rctx, ss) })
+	t.Run("Delete", func(t *testing.T) { testPostEmbeddingStoreDelete(t, rctx, ss) })
+	t.Run("FiltersAndPaging", func(t *testing.T) { testPostEmbeddingStoreFiltersAndPaging(t, rctx, ss) })
+}
+
+func savePostWithEmbedding(t *testing.T, rctx request.CTX, ss store.Store, channelID, userID string, createAt int64, embedding []float32) *model.Post {
+	t.Helper()
+
+	post, err := ss.Post().Save(rctx, &model.Post{
+		ChannelId: channelID,
+		UserId:    userID,
+		Message:   "message " + model.NewId(),
+		CreateAt:  createAt,
+	})
+	require.NoError(t, err)
+	require.NoError(t, ss.PostEmbedding().Save(model.NewPostEmbedding(post, "local:2", embedding)))
+	return post
+}
+
+func postWithEmbeddingIDs(posts []*model.PostWithEmbedding) []string {
+	ids := make([]string, 0, len(posts))
+	for _, post := range posts {
+		ids = append(ids, post.Post.Id)
+	}
+	return ids
+}
+
+func testPostEmbeddingStoreSaveGet(t *testing.T, rctx request.CTX, ss store.Store) {
+	channelA := model.NewId()
+	channelB := model.NewId()
+	userID := model.NewId()
+
+	postA := savePostWithEmbedding(t, rctx, ss, channelA, userID, 1000, []float32{1, 0})
+	postB := savePostWithEmbedding(t, rctx, ss, channelB, userID, 2000, []float32{0, 1})
+
+	t.Run("get by channels", func(t *testing.T) {
+		posts, err := ss.PostEmbedding().GetPostsForChannels("local:2", model.PostEmbeddingSearchOptions{ChannelIDs: []string{channelA}, Limit: 100})
+		require.NoError(t, err)
+		require.Len(t, posts, 1)
+		assert.Equal(t, postA.Id, posts[0].Post.Id)
+		assert.Equal(t, postA.Message, posts[0].Post.Message)
+		assert.Equal(t, []float32{1, 0}, posts[0].Embedding)
+
+		posts, err = ss.PostEmbedding().GetPostsForChannels("local:2", model.PostEmbeddingSearchOptions{ChannelIDs: []string{channelA, channelB}, Limit: 100})
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []string{postA.Id, postB.Id}, postWithEmbeddingIDs(posts))
+
+		posts, err = ss.PostEmbedding().GetPostsForChannels("local:2", model.PostEmbeddingSearchOptions{ChannelIDs: []string{}, Limit: 100})
+		require.NoError(t, err)
+		assert.Empty(t, posts)
+	})
+
+	t.Run("embeddings of other providers are ignored", func(t *testing.T) {
+		posts, err := ss.PostEmbedding().GetPostsForChannels("local:3", model.PostEmbeddingSearchOptions{ChannelIDs: []string{channelA}, Limit: 100})
+		require.NoError(t, err)
+		assert.Empty(t, posts)
+	})
+
+	t.Run("saving replaces the embedding", func(t *testing.T) {
+		require.NoError(t, ss.PostEmbedding().Save(model.NewPostEmbedding(postA, "local:2", []float32{0.5, 0.5})))
+
+		posts, err := ss.PostEmbedding().GetPostsForChannels("local:2", model.PostEmbeddingSearchOptions{ChannelIDs: []string{channelA}, Limit: 100})
+		require.NoError(t, err)
+		require.Len(t, posts, 1)
+		assert.Equal(t, []float32{0.5, 0.5}, posts[0].Embedding)
+	})
+
+	t.Run("deleted posts are ignored", func(t *testing.T) {
+		require.NoError(t, ss.Post().Delete(rctx, postB.Id, model.GetMillis(), userID))
+
+		posts, err := ss.PostEmbedding().GetPostsForChannels("local:2", model.PostEmbeddingSearchOptions{ChannelIDs: []string{channelB}, Limit: 100})
+		require.NoError(t, err)
+		assert.Empty(t, posts)
+	})
+
+	t.Run("invalid embeddings are rejected", func(t *testing.T) {
+		err := ss.PostEmbedding().Save(model.NewPostEmbedding(postA, "local:2", []float32{}))
+		require.Error(t, err)
+	})
+}
+
+func testPostEmbeddingStoreDelete(t *testing.T, rctx request.CTX, ss store.Store) {
+	channelA := model.NewId()
+	channelB := model.NewId()
+	userA := model.NewId()
+	userB := model.NewId()
+
+	post1 := savePostWithEmbedding(t, rctx, ss, channelA, userA, 1000, []float32{1, 0})
+	post2 := savePostWithEmbedding(t, rctx, ss, channelA, userB, 2000, []float32{1, 0})
+	post3 := savePostWithEmbedding(t, rctx, ss, channelB, userA, 3000, []float32{1, 0})
+	post4 := savePostWithEmbedding(t, rctx, ss, channelB, userB, model.GetMillis(), []float32{1, 0})
+	post5 := savePostWithEmbedding(t, rctx, ss, channelB, userB, model.GetMillis(), []float32{1, 0})
+	channels := []string{channelA, channelB}
+
+	getIDs := func() []string {
+		posts, err := ss.PostEmbedding().GetPostsForChannels("local:2", model.PostEmbeddingSearchOptions{ChannelIDs: channels, Limit: 100})
+		require.NoError(t, err)
+		return postWithEmbeddingIDs(posts)
+	}
+
+	require.NoError(t, ss.PostEmbedding().Delete(post1.Id))
+	assert.ElementsMatch(t, []string{post2.Id, post3.Id, post4.Id, post5.Id}, getIDs())
+
+	deleted, err := ss.PostEmbedding().DeleteBefore(2500)
+	require.NoError(t, err)
+	assert.EqualValues(t, 1, deleted)
+	assert.ElementsMatch(t, []string{post3.Id, post4.Id, post5.Id}, getIDs())
+
+	deleted, err = ss.PostEmbedding().DeleteForUser(userA)
+	require.NoError(t, err)
+	assert.EqualValues(t, 1, deleted)
+	assert.ElementsMatch(t, []string{post4.Id, post5.Id}, getIDs())
+
+	require.NoError(t, ss.PostEmbedding().Delete(post5.Id))
+	deleted, err = ss.PostEmbedding().DeleteForChannel(channelB)
+	require.NoError(t, err)
+	assert.EqualValues(t, 1, deleted)
+	assert.Empty(t, getIDs())
+
+	savePostWithEmbedding(t, rctx, ss, channelA, userA, 1000, []float32{1, 0})
+	require.NoError(t, ss.PostEmbedding().DeleteAll())
+	assert.Empty(t, getIDs())
+}
+
+func testPostEmbeddingStoreFiltersAndPaging(t *testing.T, rctx request.CTX, ss store.Store) {
+	channelID := model.NewId()
+	userA := model.NewId()
+	userB := model.NewId()
+
+	post1 := savePostWithEmbedding(t, rctx, ss, channelID, userA, 1000, []float32{1, 0})
+	post2 := savePostWithEmbedding(t, rctx, ss, channelID, userB, 2000, []float32{1, 0})
+	post3 := savePostWithEmbedding(t, rctx, ss, channelID, userA, 3000, []float32{1, 0})
+	post4 := savePostWithEmbedding(t, rctx, ss, channelID, userB, 4000, []float32{1, 0})
+
+	get := func(opts model.PostEmbeddingSearchOptions) []string {
+		opts.ChannelIDs = []string{channelID}
+		if opts.Limit == 0 {
+			opts.Limit = 100
+		}
+		posts, err := ss.PostEmbedding().GetPostsForChannels("local:2", opts)
+		require.NoError(t, err)
+		return postWithEmbeddingIDs(posts)
+	}
+
+	t.Run("most recent first", func(t *testing.T) {
+		assert.Equal(t, []string{post4.Id, post3.Id, post2.Id, post1.Id}, get(model.PostEmbeddingSearchOptions{}))
+	})
+
+	t.Run("users", func(t *testing.T) {
+		assert.Equal(t, []string{post3.Id, post1.Id}, get(model.PostEmbeddingSearchOptions{UserIDs: []string{userA}}))
+		assert.Equal(t, []string{post4.Id, post2.Id}, get(model.PostEmbeddingSearchOptions{ExcludedUserIDs: []string{userA}}))
+	})
+
+	t.Run("dates", func(t *testing.T) {
+		assert.Equal(t, []string{post3.Id, post2.Id}, get(model.PostEmbeddingSearchOptions{CreatedAfter: 2000, CreatedBefore: 3000}))
+	})
+
+	t.Run("paging", func(t *testing.T) {
+		assert.Equal(t, []string{post4.Id, post3.Id}, get(model.PostEmbeddingSearchOptions{Limit: 2}))
+		assert.Equal(t, []string{post2.Id, post1.Id}, get(model.PostEmbeddingSearchOptions{Limit: 2, BeforeCreateAt: post3.CreateAt, BeforeID: post3.Id}))
+	})
+}
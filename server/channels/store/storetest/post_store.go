@@ -49,6 +49,7 @@ func TestPostStore(t *testing.T, rctx request.CTX, ss store.Store, s SqlStore) {
 	t.Run("GetPostsByIds", func(t *testing.T) { testPostStoreGetPostsByIds(t, rctx, ss) })
 	t.Run("GetPostsBatchForIndexing", func(t *testing.T) { testPostStoreGetPostsBatchForIndexing(t, rctx, ss) })
 	t.Run("PermanentDeleteBatch", func(t *testing.T) { testPostStorePermanentDeleteBatch(t, rctx, ss) })
+	t.Run("PermanentDeletePosts", func(t *testing.T) { testPostStorePermanentDeletePosts(t, rctx, ss) })
 	t.Run("GetOldest", func(t *testing.T) { testPostStoreGetOldest(t, rctx, ss) })
 	t.Run("TestGetMaxPostSize", func(t *testing.T) { testGetMaxPostSize(t, rctx, ss) })
 	t.Run("GetParentsForExportAfter", func(t *testing.T) { testPostStoreGetParentsForExportAfter(t, rctx, ss) })
@@ -3957,6 +3958,53 @@ func testPostStoreGetPostsBatchForIndexing(t *testing.T, rctx request.CTX, ss st
 	require.Len(t, r, 0, "Expected 0 post in results. Got %v", len(r))
 }
 
+func testPostStorePermanentDeletePosts(t *testing.T, rctx request.CTX, ss store.Store) {
+	channelID := model.NewId()
+
+	root, err := ss.Post().Save(rctx, &model.Post{ChannelId: channelID, UserId: model.NewId(), Message: NewTestId()})
+	require.NoError(t, err)
+	reply, err := ss.Post().Save(rctx, &model.Post{ChannelId: channelID, UserId: model.NewId(), RootId: root.Id, Message: NewTestId()})
+	require.NoError(t, err)
+	oldRoot, err := ss.Post().Save(rctx, &model.Post{ChannelId: channelID, UserId: root.UserId, OriginalId: root.Id, Message: NewTestId(), DeleteAt: model.GetMillis()})
+	require.NoError(t, err)
+
+	otherRoot, err := ss.Post().Save(rctx, &model.Post{ChannelId: channelID, UserId: model.NewId(), Message: NewTestId()})
+	require.NoError(t, err)
+	otherReply1, err := ss.Post().Save(rctx, &model.Post{ChannelId: channelID, UserId: model.NewId(), RootId: otherRoot.Id, Message: NewTestId()})
+	require.NoError(t, err)
+	otherReply2, err := ss.Post().Save(rctx, &model.Post{ChannelId: channelID, UserId: model.NewId(), RootId: otherRoot.Id, Message: NewTestId()})
+	require.NoError(t, err)
+
+	_, err = ss.Reaction().Save(&model.Reaction{UserId: model.NewId(), PostId: root.Id, EmojiName: "smile", ChannelId: channelID})
+	require.NoError(t, err)
+
+	require.NoError(t, ss.Post().PermanentDeletePosts(rctx, nil))
+	require.NoError(t, ss.Post().PermanentDeletePosts(rctx, []*model.Post{root, otherReply1}))
+
+	for _, id := range []string{root.Id, oldRoot.Id, otherReply1.Id} {
+		_, err = ss.Post().GetSingle(rctx, id, true)
+		require.Error(t, err, "post %s should have been deleted", id)
+	}
+
+	for _, id := range []string{reply.Id, otherRoot.Id, otherReply2.Id} {
+		_, err = ss.Post().GetSingle(rctx, id, false)
+		require.NoError(t, err, "post %s should not have been deleted", id)
+	}
+
+	reactions, err := ss.Reaction().GetForPost(root.Id, false)
+	require.NoError(t, err)
+	require.Empty(t, reactions)
+
+	thread, err := ss.Thread().Get(root.Id)
+	require.NoError(t, err)
+	require.Nil(t, thread)
+
+	thread, err = ss.Thread().Get(otherRoot.Id)
+	require.NoError(t, err)
+	require.EqualValues(t, 1, thread.ReplyCount)
+	require.EqualValues(t, model.StringArray{otherReply2.UserId}, thread.Participants)
+}
+
 func testPostStorePermanentDeleteBatch(t *testing.T, rctx request.CTX, ss store.Store) {
 	team, err := ss.Team().Save(&model.Team{
 		DisplayName: "DisplayName",
@@ -18,6 +18,7 @@ func TestPreferenceStore(t *testing.T, rctx request.CTX, ss store.Store, s SqlSt
 	t.Run("PreferenceSave", func(t *testing.T) { testPreferenceSave(t, rctx, ss) })
 	t.Run("PreferenceGet", func(t *testing.T) { testPreferenceGet(t, rctx, ss) })
 	t.Run("PreferenceGetCategory", func(t *testing.T) { testPreferenceGetCategory(t, rctx, ss) })
+	t.Run("PreferenceGetCategoryAndNameForUsers", func(t *testing.T) { testPreferenceGetCategoryAndNameForUsers(t, rctx, ss) })
 	t.Run("PreferenceGetAll", func(t *testing.T) { testPreferenceGetAll(t, rctx, ss) })
 	t.Run("PreferenceDeleteByUser", func(t *testing.T) { testPreferenceDeleteByUser(t, rctx, ss) })
 	t.Run("PreferenceDelete", func(t *testing.T) { testPreferenceDelete(t, rctx, ss) })
@@ -152,6 +153,52 @@ func testPreferenceGetCategory(t *testing.T, rctx request.CTX, ss store.Store) {
 	require.Equal(t, 0, len(preferencesByCategory), "shouldn't have got any preferences")
 }
 
+func testPreferenceGetCategoryAndNameForUsers(t *testing.T, rctx request.CTX, ss store.Store) {
+	userId1 := model.NewId()
+	userId2 := model.NewId()
+	category := model.PreferenceCategoryAdvancedSettings
+	name := model.NewId()
+
+	preferences := model.Preferences{
+		{
+			UserId:   userId1,
+			Category: category,
+			Name:     name,
+			Value:    "value1",
+		},
+		{
+			UserId:   userId2,
+			Category: category,
+			Name:     name,
+			Value:    "value2",
+		},
+		// same user/category, different name
+		{
+			UserId:   userId1,
+			Category: category,
+			Name:     model.NewId(),
+		},
+		// same name/category, user not asked for
+		{
+			UserId:   model.NewId(),
+			Category: category,
+			Name:     name,
+		},
+	}
+
+	err := ss.Preference().Save(preferences)
+	require.NoError(t, err)
+
+	received, err := ss.Preference().GetCategoryAndNameForUsers([]string{userId1, userId2, model.NewId()}, category, name)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, model.Preferences{preferences[0], preferences[1]}, received)
+
+	// make sure asking for no users doesn't fail
+	received, err = ss.Preference().GetCategoryAndNameForUsers([]string{}, category, name)
+	require.NoError(t, err)
+	assert.Empty(t, received)
+}
+
 func testPreferenceGetAll(t *testing.T, rctx request.CTX, ss store.Store) {
 	userId := model.NewId()
 	category := model.PreferenceCategoryDirectChannelShow
@@ -0,0 +1,216 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package storetest
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/shared/request"
+	"github.com/mattermost/mattermost/server/v8/channels/store"
+)
+
+func TestSavedSearchStore(t *testing.T, rctx request.CTX, ss store.Store, s SqlStore) {
+	t.Run("SaveSavedSearch", func(t *testing.T) { testSaveSavedSearch(t, rctx, ss) })
+	t.Run("UpdateSavedSearch", func(t *testing.T) { testUpdateSavedSearch(t, rctx, ss) })
+	t.Run("GetSavedSearchesForUser", func(t *testing.T) { testGetSavedSearchesForUser(t, rctx, ss) })
+	t.Run("GetSubscribedSavedSearchesForChannel", func(t *testing.T) { testGetSubscribedSavedSearchesForChannel(t, rctx, ss) })
+	t.Run("PermanentDeleteSavedSearchesByUser", func(t *testing.T) { testPermanentDeleteSavedSearchesByUser(t, rctx, ss) })
+}
+
+func newTestSavedSearch(userId, teamId, name string) *model.SavedSearch {
+	return &model.SavedSearch{
+		UserId: userId,
+		TeamId: teamId,
+		Name:   name,
+		Terms:  "from:alertbot in:prod error",
+	}
+}
+
+func testSaveSavedSearch(t *testing.T, rctx request.CTX, ss store.Store) {
+	userId := model.NewId()
+	teamId := model.NewId()
+
+	t.Run("save and get a saved search", func(t *testing.T) {
+		savedSearch := newTestSavedSearch(userId, teamId, "Production errors")
+		savedSearch.IsOrSearch = true
+		savedSearch.Subscribed = true
+		savedSearch.AlertType = model.SavedSearchAlertDirectMessage
+		savedSearch.InChannelIds = model.StringArray{model.NewId()}
+		savedSearch.FromUserIds = model.StringArray{model.NewId()}
+
+		saved, err := ss.SavedSearch().Save(savedSearch)
+		require.NoError(t, err)
+		require.True(t, model.IsValidId(saved.Id))
+		defer func() {
+			require.NoError(t, ss.SavedSearch().PermanentDelete(saved.Id))
+		}()
+
+		fetched, err := ss.SavedSearch().Get(saved.Id)
+		require.NoError(t, err)
+		assert.Equal(t, saved, fetched)
+	})
+
+	t.Run("invalid saved search is rejected", func(t *testing.T) {
+		savedSearch := newTestSavedSearch(userId, teamId, "")
+		_, err := ss.SavedSearch().Save(savedSearch)
+		require.Error(t, err)
+	})
+
+	t.Run("get missing saved search", func(t *testing.T) {
+		_, err := ss.SavedSearch().Get(model.NewId())
+		var nfErr *store.ErrNotFound
+		require.ErrorAs(t, err, &nfErr)
+	})
+}
+
+func testUpdateSavedSearch(t *testing.T, rctx request.CTX, ss store.Store) {
+	saved, err := ss.SavedSearch().Save(newTestSavedSearch(model.NewId(), model.NewId(), "Production errors"))
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, ss.SavedSearch().PermanentDelete(saved.Id))
+	}()
+
+	t.Run("update a saved search", func(t *testing.T) {
+		saved.Name = "Staging errors"
+		saved.Terms = "in:staging error"
+		saved.Subscribed = true
+		saved.InChannelIds = model.StringArray{model.NewId()}
+
+		updated, err := ss.SavedSearch().Update(saved)
+		require.NoError(t, err)
+
+		fetched, err := ss.SavedSearch().Get(saved.Id)
+		require.NoError(t, err)
+		assert.Equal(t, updated, fetched)
+	})
+
+	t.Run("update a missing saved search", func(t *testing.T) {
+		missing := newTestSavedSearch(model.NewId(), model.NewId(), "Missing")
+		missing.PreSave()
+
+		_, err := ss.SavedSearch().Update(missing)
+		var nfErr *store.ErrNotFound
+		require.ErrorAs(t, err, &nfErr)
+	})
+}
+
+func testGetSavedSearchesForUser(t *testing.T, rctx request.CTX, ss store.Store) {
+	userId := model.NewId()
+	teamId := model.NewId()
+
+	second, err := ss.SavedSearch().Save(newTestSavedSearch(userId, teamId, "B"))
+	require.NoError(t, err)
+	first, err := ss.SavedSearch().Save(newTestSavedSearch(userId, teamId, "A"))
+	require.NoError(t, err)
+	otherTeam, err := ss.SavedSearch().Save(newTestSavedSearch(userId, model.NewId(), "C"))
+	require.NoError(t, err)
+	otherUser, err := ss.SavedSearch().Save(newTestSavedSearch(model.NewId(), teamId, "D"))
+	require.NoError(t, err)
+	defer func() {
+		for _, savedSearch := range []*model.SavedSearch{first, second, otherTeam, otherUser} {
+			require.NoError(t, ss.SavedSearch().PermanentDelete(savedSearch.Id))
+		}
+	}()
+
+	savedSearches, err := ss.SavedSearch().GetForUser(userId, teamId)
+	require.NoError(t, err)
+	require.Len(t, savedSearches, 2)
+	assert.Equal(t, first.Id, savedSearches[0].Id)
+	assert.Equal(t, second.Id, savedSearches[1].Id)
+
+	count, err := ss.SavedSearch().CountForUser(userId)
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), count)
+}
+
+func testGetSubscribedSavedSearchesForChannel(t *testing.T, rctx request.CTX, ss store.Store) {
+	teamId := model.NewId()
+	channel, err := ss.Channel().Save(rctx, &model.Channel{
+		TeamId:      teamId,
+		DisplayName: "Saved searches channel",
+		Name:        NewTestId(),
+		Type:        model.ChannelTypeOpen,
+	}, -1)
+	require.NoError(t, err)
+
+	saveMember := func(deleteAt int64) string {
+		user, err := ss.User().Save(rctx, &model.User{
+			Username: "user" + model.NewId(),
+			Email:    MakeEmail(),
+			DeleteAt: deleteAt,
+		})
+		require.NoError(t, err)
+
+		_, err = ss.Channel().SaveMember(rctx, &model.ChannelMember{
+			ChannelId:   channel.Id,
+			UserId:      user.Id,
+			NotifyProps: model.GetDefaultChannelNotifyProps(),
+		})
+		require.NoError(t, err)
+		return user.Id
+	}
+	memberId := saveMember(0)
+	deactivatedId := saveMember(model.GetMillis())
+
+	newSavedSearch := func(userId, teamId string, subscribed bool) *model.SavedSearch {
+		savedSearch := newTestSavedSearch(userId, teamId, "Production errors")
+		savedSearch.Subscribed = subscribed
+		saved, err := ss.SavedSearch().Save(savedSearch)
+		require.NoError(t, err)
+		return saved
+	}
+
+	subscribed := newSavedSearch(memberId, teamId, true)
+	notSubscribed := newSavedSearch(memberId, teamId, false)
+	otherTeam := newSavedSearch(memberId, model.NewId(), true)
+	notMember := newSavedSearch(model.NewId(), teamId, true)
+	deactivated := newSavedSearch(deactivatedId, teamId, true)
+	defer func() {
+		for _, savedSearch := range []*model.SavedSearch{subscribed, notSubscribed, otherTeam, notMember, deactivated} {
+			require.NoError(t, ss.SavedSearch().PermanentDelete(savedSearch.Id))
+		}
+	}()
+
+	t.Run("team channel", func(t *testing.T) {
+		savedSearches, err := ss.SavedSearch().GetSubscribedForChannel(channel.Id, teamId)
+		require.NoError(t, err)
+		require.Len(t, savedSearches, 1)
+		assert.Equal(t, subscribed.Id, savedSearches[0].Id)
+	})
+
+	t.Run("channel without a team", func(t *testing.T) {
+		savedSearches, err := ss.SavedSearch().GetSubscribedForChannel(channel.Id, "")
+		require.NoError(t, err)
+		ids := []string{}
+		for _, savedSearch := range savedSearches {
+			ids = append(ids, savedSearch.Id)
+		}
+		assert.ElementsMatch(t, []string{subscribed.Id, otherTeam.Id}, ids)
+	})
+}
+
+func testPermanentDeleteSavedSearchesByUser(t *testing.T, rctx request.CTX, ss store.Store) {
+	userId := model.NewId()
+
+	saved, err := ss.SavedSearch().Save(newTestSavedSearch(userId, model.NewId(), "Production errors"))
+	require.NoError(t, err)
+	other, err := ss.SavedSearch().Save(newTestSavedSearch(model.NewId(), model.NewId(), "Production errors"))
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, ss.SavedSearch().PermanentDelete(other.Id))
+	}()
+
+	require.NoError(t, ss.SavedSearch().PermanentDeleteByUser(userId))
+
+	_, err = ss.SavedSearch().Get(saved.Id)
+	var nfErr *store.ErrNotFound
+	require.ErrorAs(t, err, &nfErr)
+
+	_, err = ss.SavedSearch().Get(other.Id)
+	require.NoError(t, err)
+}
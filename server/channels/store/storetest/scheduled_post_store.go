@@ -0,0 +1,219 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package storetest
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/shared/request"
+	"github.com/mattermost/mattermost/server/v8/channels/store"
+)
+
+func TestScheduledPostStore(t *testing.T, rctx request.CTX, ss store.Store, s SqlStore) {
+	t.Run("SaveScheduledPost", func(t *testing.T) { testSaveScheduledPost(t, rctx, ss) })
+	t.Run("UpdateScheduledPost", func(t *testing.T) { testUpdateScheduledPost(t, rctx, ss) })
+	t.Run("GetScheduledPostsForUser", func(t *testing.T) { testGetScheduledPostsForUser(t, rctx, ss) })
+	t.Run("GetPendingScheduledPosts", func(t *testing.T) { testGetPendingScheduledPosts(t, rctx, ss) })
+	t.Run("PermanentlyDeleteScheduledPosts", func(t *testing.T) { testPermanentlyDeleteScheduledPosts(t, rctx, ss) })
+}
+
+func makeScheduledPostChannel(t *testing.T, rctx request.CTX, ss store.Store, teamId string) *model.Channel {
+	channel, err := ss.Channel().Save(rctx, &model.Channel{
+		TeamId:      teamId,
+		DisplayName: "Scheduled posts channel",
+		Name:        NewTestId(),
+		Type:        model.ChannelTypeOpen,
+	}, -1)
+	require.NoError(t, err)
+	return channel
+}
+
+func newTestScheduledPost(userId, channelId string, scheduledAt int64) *model.ScheduledPost {
+	return &model.ScheduledPost{
+		Draft: model.Draft{
+			UserId:    userId,
+			ChannelId: channelId,
+			Message:   "scheduled post " + model.NewId(),
+		},
+		ScheduledAt: scheduledAt,
+	}
+}
+
+func testSaveScheduledPost(t *testing.T, rctx request.CTX, ss store.Store) {
+	channel := makeScheduledPostChannel(t, rctx, ss, model.NewId())
+	userId := model.NewId()
+
+	t.Run("save and get a scheduled post", func(t *testing.T) {
+		scheduledPost := newTestScheduledPost(userId, channel.Id, model.GetMillis()+100000)
+		scheduledPost.SetProps(model.StringInterface{"key": "value"})
+
+		saved, err := ss.ScheduledPost().Save(scheduledPost)
+		require.NoError(t, err)
+		require.True(t, model.IsValidId(saved.Id))
+		defer func() {
+			require.NoError(t, ss.ScheduledPost().PermanentlyDelete([]string{saved.Id}))
+		}()
+
+		fetched, err := ss.ScheduledPost().Get(saved.Id)
+		require.NoError(t, err)
+		assert.Equal(t, saved.Message, fetched.Message)
+		assert.Equal(t, saved.ScheduledAt, fetched.ScheduledAt)
+		assert.Equal(t, "value", fetched.GetProps()["key"])
+		assert.Zero(t, fetched.ProcessedAt)
+		assert.Empty(t, fetched.ErrorCode)
+	})
+
+	t.Run("invalid scheduled post is rejected", func(t *testing.T) {
+		scheduledPost := newTestScheduledPost(userId, channel.Id, 1)
+		_, err := ss.ScheduledPost().Save(scheduledPost)
+		require.Error(t, err)
+	})
+
+	t.Run("get missing scheduled post", func(t *testing.T) {
+		_, err := ss.ScheduledPost().Get(model.NewId())
+		var nfErr *store.ErrNotFound
+		require.ErrorAs(t, err, &nfErr)
+	})
+}
+
+func testUpdateScheduledPost(t *testing.T, rctx request.CTX, ss store.Store) {
+	channel := makeScheduledPostChannel(t, rctx, ss, model.NewId())
+	userId := model.NewId()
+
+	scheduledPost, err := ss.ScheduledPost().Save(newTestScheduledPost(userId, channel.Id, model.GetMillis()+100000))
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, ss.ScheduledPost().PermanentlyDelete([]string{scheduledPost.Id}))
+	}()
+
+	t.Run("update message and schedule", func(t *testing.T) {
+		scheduledPost.Message = "updated message"
+		scheduledPost.ScheduledAt = scheduledPost.ScheduledAt + 1000
+		require.NoError(t, ss.ScheduledPost().Update(scheduledPost))
+
+		fetched, err := ss.ScheduledPost().Get(scheduledPost.Id)
+		require.NoError(t, err)
+		assert.Equal(t, "updated message", fetched.Message)
+		assert.Equal(t, scheduledPost.ScheduledAt, fetched.ScheduledAt)
+		assert.GreaterOrEqual(t, fetched.UpdateAt, fetched.CreateAt)
+	})
+
+	t.Run("record a failure", func(t *testing.T) {
+		scheduledPost.ProcessedAt = scheduledPost.ScheduledAt + 1
+		scheduledPost.ErrorCode = model.ScheduledPostErrorCodeChannelArchived
+		require.NoError(t, ss.ScheduledPost().Update(scheduledPost))
+
+		fetched, err := ss.ScheduledPost().Get(scheduledPost.Id)
+		require.NoError(t, err)
+		assert.Equal(t, scheduledPost.ProcessedAt, fetched.ProcessedAt)
+		assert.Equal(t, model.ScheduledPostErrorCodeChannelArchived, fetched.ErrorCode)
+	})
+
+	t.Run("update missing scheduled post", func(t *testing.T) {
+		missing := newTestScheduledPost(userId, channel.Id, model.GetMillis()+100000)
+		missing.PreSave()
+		err := ss.ScheduledPost().Update(missing)
+		var nfErr *store.ErrNotFound
+		require.ErrorAs(t, err, &nfErr)
+	})
+}
+
+func testGetScheduledPostsForUser(t *testing.T, rctx request.CTX, ss store.Store) {
+	teamId := model.NewId()
+	channel := makeScheduledPostChannel(t, rctx, ss, teamId)
+	otherTeamChannel := makeScheduledPostChannel(t, rctx, ss, model.NewId())
+	dmChannel := makeScheduledPostChannel(t, rctx, ss, "")
+	userId := model.NewId()
+
+	now := model.GetMillis()
+	later, err := ss.ScheduledPost().Save(newTestScheduledPost(userId, channel.Id, now+200000))
+	require.NoError(t, err)
+	sooner, err := ss.ScheduledPost().Save(newTestScheduledPost(userId, dmChannel.Id, now+100000))
+	require.NoError(t, err)
+	otherTeam, err := ss.ScheduledPost().Save(newTestScheduledPost(userId, otherTeamChannel.Id, now+100000))
+	require.NoError(t, err)
+	otherUser, err := ss.ScheduledPost().Save(newTestScheduledPost(model.NewId(), channel.Id, now+100000))
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, ss.ScheduledPost().PermanentlyDelete([]string{later.Id, sooner.Id, otherTeam.Id, otherUser.Id}))
+	}()
+
+	scheduledPosts, err := ss.ScheduledPost().GetScheduledPostsForUser(userId, teamId)
+	require.NoError(t, err)
+	require.Len(t, scheduledPosts, 2)
+	assert.Equal(t, sooner.Id, scheduledPosts[0].Id)
+	assert.Equal(t, later.Id, scheduledPosts[1].Id)
+
+	scheduledPosts, err = ss.ScheduledPost().GetScheduledPostsForUser(model.NewId(), teamId)
+	require.NoError(t, err)
+	assert.Empty(t, scheduledPosts)
+}
+
+func testGetPendingScheduledPosts(t *testing.T, rctx request.CTX, ss store.Store) {
+	channel := makeScheduledPostChannel(t, rctx, ss, model.NewId())
+	userId := model.NewId()
+
+	now := model.GetMillis()
+	var ids []string
+	for i := 1; i <= 3; i++ {
+		scheduledPost, err := ss.ScheduledPost().Save(newTestScheduledPost(userId, channel.Id, now+int64(i*1000)))
+		require.NoError(t, err)
+		ids = append(ids, scheduledPost.Id)
+	}
+
+	notYetDue, err := ss.ScheduledPost().Save(newTestScheduledPost(userId, channel.Id, now+100000))
+	require.NoError(t, err)
+
+	failed, err := ss.ScheduledPost().Save(newTestScheduledPost(userId, channel.Id, now+500))
+	require.NoError(t, err)
+	failed.ProcessedAt = now + 600
+	failed.ErrorCode = model.ScheduledPostErrorCodeUnknown
+	require.NoError(t, ss.ScheduledPost().Update(failed))
+
+	defer func() {
+		require.NoError(t, ss.ScheduledPost().PermanentlyDelete(append(ids, notYetDue.Id, failed.Id)))
+	}()
+
+	beforeTime := now + 10000
+
+	page, err := ss.ScheduledPost().GetPendingScheduledPosts(beforeTime, 0, "", 2)
+	require.NoError(t, err)
+	require.Len(t, page, 2)
+	assert.Equal(t, ids[0], page[0].Id)
+	assert.Equal(t, ids[1], page[1].Id)
+
+	last := page[len(page)-1]
+	page, err = ss.ScheduledPost().GetPendingScheduledPosts(beforeTime, last.ScheduledAt, last.Id, 2)
+	require.NoError(t, err)
+	require.Len(t, page, 1)
+	assert.Equal(t, ids[2], page[0].Id)
+
+	last = page[len(page)-1]
+	page, err = ss.ScheduledPost().GetPendingScheduledPosts(beforeTime, last.ScheduledAt, last.Id, 2)
+	require.NoError(t, err)
+	assert.Empty(t, page)
+}
+
+func testPermanentlyDeleteScheduledPosts(t *testing.T, rctx request.CTX, ss store.Store) {
+	channel := makeScheduledPostChannel(t, rctx, ss, model.NewId())
+	userId := model.NewId()
+
+	first, err := ss.ScheduledPost().Save(newTestScheduledPost(userId, channel.Id, model.GetMillis()+100000))
+	require.NoError(t, err)
+	second, err := ss.ScheduledPost().Save(newTestScheduledPost(userId, channel.Id, model.GetMillis()+100000))
+	require.NoError(t, err)
+
+	require.NoError(t, ss.ScheduledPost().PermanentlyDelete(nil))
+	require.NoError(t, ss.ScheduledPost().PermanentlyDelete([]string{first.Id, second.Id}))
+
+	var nfErr *store.ErrNotFound
+	_, err = ss.ScheduledPost().Get(first.Id)
+	require.ErrorAs(t, err, &nfErr)
+	_, err = ss.ScheduledPost().Get(second.Id)
+	require.ErrorAs(t, err, &nfErr)
+}
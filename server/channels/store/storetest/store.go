@@ -65,6 +65,14 @@ type Store struct {
 	PostPersistentNotificationStore mocks.PostPersistentNotificationStore
 	DesktopTokensStore              mocks.DesktopTokensStore
 	ChannelBookmarkStore            mocks.ChannelBookmarkStore
+	ScheduledPostStore              mocks.ScheduledPostStore
+	SavedSearchStore                mocks.SavedSearchStore
+	WebAuthnCredentialStore         mocks.WebAuthnCredentialStore
+	PollVoteStore                   mocks.PollVoteStore
+	ExpiringPostStore               mocks.ExpiringPostStore
+	AuditRecordStore                mocks.AuditRecordStore
+	FileBlobStore                   mocks.FileBlobStore
+	PostEmbeddingStore              mocks.PostEmbeddingStore
 }
 
 func (s *Store) SetContext(context context.Context)            { s.context = context }
@@ -112,6 +120,8 @@ func (s *Store) ChannelMemberHistory() store.ChannelMemberHistoryStore {
 }
 func (s *Store) ChannelBookmark() store.ChannelBookmarkStore { return &s.ChannelBookmarkStore }
 func (s *Store) DesktopTokens() store.DesktopTokensStore     { return &s.DesktopTokensStore }
+func (s *Store) ScheduledPost() store.ScheduledPostStore     { return &s.ScheduledPostStore }
+func (s *Store) SavedSearch() store.SavedSearchStore         { return &s.SavedSearchStore }
 func (s *Store) NotifyAdmin() store.NotifyAdminStore         { return &s.NotifyAdminStore }
 func (s *Store) Group() store.GroupStore                     { return &s.GroupStore }
 func (s *Store) LinkMetadata() store.LinkMetadataStore       { return &s.LinkMetadataStore }
@@ -123,6 +133,22 @@ func (s *Store) PostAcknowledgement() store.PostAcknowledgementStore {
 func (s *Store) PostPersistentNotification() store.PostPersistentNotificationStore {
 	return &s.PostPersistentNotificationStore
 }
+func (s *Store) WebAuthnCredential() store.WebAuthnCredentialStore {
+	return &s.WebAuthnCredentialStore
+}
+func (s *Store) ExpiringPost() store.ExpiringPostStore {
+	return &s.ExpiringPostStore
+}
+func (s *Store) AuditRecord() store.AuditRecordStore {
+	return &s.AuditRecordStore
+}
+func (s *Store) FileBlob() store.FileBlobStore {
+	return &s.FileBlobStore
+}
+func (s *Store) PostEmbedding() store.PostEmbeddingStore {
+	return &s.PostEmbeddingStore
+}
+func (s *Store) PollVote() store.PollVoteStore       { return &s.PollVoteStore }
 func (s *Store) MarkSystemRanUnitTests()             { /* do nothing */ }
 func (s *Store) Close()                              { /* do nothing */ }
 func (s *Store) LockToMaster()                       { /* do nothing */ }
@@ -188,5 +214,13 @@ func (s *Store) AssertExpectations(t mock.TestingT) bool {
 		&s.PostPersistentNotificationStore,
 		&s.DesktopTokensStore,
 		&s.ChannelBookmarkStore,
+		&s.ScheduledPostStore,
+		&s.SavedSearchStore,
+		&s.WebAuthnCredentialStore,
+		&s.PollVoteStore,
+		&s.ExpiringPostStore,
+		&s.AuditRecordStore,
+		&s.FileBlobStore,
+		&s.PostEmbeddingStore,
 	)
 }
@@ -18,6 +18,7 @@ func TestUploadSessionStore(t *testing.T, rctx request.CTX, ss store.Store) {
 	t.Run("UploadSessionStoreSaveGet", func(t *testing.T) { testUploadSessionStoreSaveGet(t, rctx, ss) })
 	t.Run("UploadSessionStoreUpdate", func(t *testing.T) { testUploadSessionStoreUpdate(t, rctx, ss) })
 	t.Run("UploadSessionStoreGetForUser", func(t *testing.T) { testUploadSessionStoreGetForUser(t, rctx, ss) })
+	t.Run("UploadSessionStoreGetIncomplete", func(t *testing.T) { testUploadSessionStoreGetIncomplete(t, rctx, ss) })
 	t.Run("UploadSessionStoreDelete", func(t *testing.T) { testUploadSessionStoreDelete(t, rctx, ss) })
 }
 
@@ -175,6 +176,41 @@ func testUploadSessionStoreGetForUser(t *testing.T, rctx request.CTX, ss store.S
 	})
 }
 
+func testUploadSessionStoreGetIncomplete(t *testing.T, rctx request.CTX, ss store.Store) {
+	incomplete, err := ss.UploadSession().Save(&model.UploadSession{
+		Type:       model.UploadTypeAttachment,
+		UserId:     model.NewId(),
+		ChannelId:  model.NewId(),
+		Filename:   "incomplete",
+		FileSize:   1024,
+		FileOffset: 512,
+		Path:       "/tmp/incomplete",
+	})
+	require.NoError(t, err)
+	defer ss.UploadSession().Delete(incomplete.Id)
+
+	complete, err := ss.UploadSession().Save(&model.UploadSession{
+		Type:       model.UploadTypeAttachment,
+		UserId:     model.NewId(),
+		ChannelId:  model.NewId(),
+		Filename:   "complete",
+		FileSize:   1024,
+		FileOffset: 1024,
+		Path:       "/tmp/complete",
+	})
+	require.NoError(t, err)
+	defer ss.UploadSession().Delete(complete.Id)
+
+	sessions, err := ss.UploadSession().GetIncomplete()
+	require.NoError(t, err)
+	ids := make([]string, 0, len(sessions))
+	for _, session := range sessions {
+		ids = append(ids, session.Id)
+	}
+	require.Contains(t, ids, incomplete.Id)
+	require.NotContains(t, ids, complete.Id)
+}
+
 func testUploadSessionStoreDelete(t *testing.T, rctx request.CTX, ss store.Store) {
 	session := &model.UploadSession{
 		Id:        model.NewId(),
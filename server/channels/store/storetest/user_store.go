@@ -55,6 +55,7 @@ func TestUserStore(t *testing.T, rctx request.CTX, ss store.Store, s SqlStore) {
 	t.Run("UpdateFailedPasswordAttempts", func(t *testing.T) { testUserStoreUpdateFailedPasswordAttempts(t, rctx, ss) })
 	t.Run("Get", func(t *testing.T) { testUserStoreGet(t, rctx, ss) })
 	t.Run("GetAllUsingAuthService", func(t *testing.T) { testGetAllUsingAuthService(t, rctx, ss) })
+	t.Run("GetNonAdminsUsingAuthService", func(t *testing.T) { testGetNonAdminsUsingAuthService(t, rctx, ss) })
 	t.Run("GetAllProfiles", func(t *testing.T) { testUserStoreGetAllProfiles(t, rctx, ss) })
 	t.Run("GetProfiles", func(t *testing.T) { testUserStoreGetProfiles(t, rctx, ss) })
 	t.Run("GetProfilesInChannel", func(t *testing.T) { testUserStoreGetProfilesInChannel(t, rctx, ss) })
@@ -411,6 +412,91 @@ func testGetAllUsingAuthService(t *testing.T, rctx request.CTX, ss store.Store)
 	})
 }
 
+func testGetNonAdminsUsingAuthService(t *testing.T, rctx request.CTX, ss store.Store) {
+	authService := "service" + model.NewId()
+	now := model.GetMillis()
+
+	var users []*model.User
+	for i := 0; i < 3; i++ {
+		user, err := ss.User().Save(rctx, &model.User{
+			Email:       MakeEmail(),
+			Username:    "u" + model.NewId(),
+			AuthService: authService,
+			CreateAt:    now + int64(i),
+		})
+		require.NoError(t, err)
+		defer func() { require.NoError(t, ss.User().PermanentDelete(rctx, user.Id)) }()
+		users = append(users, user)
+	}
+
+	deactivated, err := ss.User().Save(rctx, &model.User{
+		Email:       MakeEmail(),
+		Username:    "u" + model.NewId(),
+		AuthService: authService,
+		CreateAt:    now + 3,
+		DeleteAt:    now,
+	})
+	require.NoError(t, err)
+	defer func() { require.NoError(t, ss.User().PermanentDelete(rctx, deactivated.Id)) }()
+	users = append(users, deactivated)
+
+	admin, err := ss.User().Save(rctx, &model.User{
+		Email:       MakeEmail(),
+		Username:    "u" + model.NewId(),
+		AuthService: authService,
+		Roles:       model.SystemUserRoleId + " " + model.SystemAdminRoleId,
+	})
+	require.NoError(t, err)
+	defer func() { require.NoError(t, ss.User().PermanentDelete(rctx, admin.Id)) }()
+
+	bot, err := ss.User().Save(rctx, &model.User{
+		Email:       MakeEmail(),
+		Username:    "u" + model.NewId(),
+		AuthService: authService,
+	})
+	require.NoError(t, err)
+	defer func() { require.NoError(t, ss.User().PermanentDelete(rctx, bot.Id)) }()
+	_, nErr := ss.Bot().Save(&model.Bot{
+		UserId:   bot.Id,
+		Username: bot.Username,
+		OwnerId:  admin.Id,
+	})
+	require.NoError(t, nErr)
+	defer func() { require.NoError(t, ss.Bot().PermanentDelete(bot.Id)) }()
+
+	userIDs := func(users []*model.User) []string {
+		ids := []string{}
+		for _, user := range users {
+			ids = append(ids, user.Id)
+		}
+		return ids
+	}
+
+	t.Run("count leaves out admins and bots", func(t *testing.T) {
+		count, err := ss.User().CountNonAdminsUsingAuthService(authService)
+		require.NoError(t, err)
+		assert.Equal(t, int64(4), count)
+	})
+
+	t.Run("get all", func(t *testing.T) {
+		result, err := ss.User().GetNonAdminsUsingAuthService(authService, 0, 10)
+		require.NoError(t, err)
+		assert.Equal(t, userIDs(users), userIDs(result))
+	})
+
+	t.Run("get from an offset", func(t *testing.T) {
+		result, err := ss.User().GetNonAdminsUsingAuthService(authService, 1, 2)
+		require.NoError(t, err)
+		assert.Equal(t, userIDs(users[1:3]), userIDs(result))
+	})
+
+	t.Run("get by unknown auth service", func(t *testing.T) {
+		result, err := ss.User().GetNonAdminsUsingAuthService("unknown", 0, 10)
+		require.NoError(t, err)
+		assert.Empty(t, result)
+	})
+}
+
 func sanitized(user *model.User) *model.User {
 	clonedUser := user.DeepCopy()
 	clonedUser.Sanitize(map[string]bool{})
@@ -0,0 +1,144 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package storetest
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/shared/request"
+	"github.com/mattermost/mattermost/server/v8/channels/store"
+)
+
+func TestWebAuthnCredentialStore(t *testing.T, rctx request.CTX, ss store.Store, s SqlStore) {
+	t.Run("Save", func(t *testing.T) { testWebAuthnCredentialStoreSave(t, rctx, ss) })
+	t.Run("GetForUser", func(t *testing.T) { testWebAuthnCredentialStoreGetForUser(t, rctx, ss) })
+	t.Run("UpdateSignCount", func(t *testing.T) { testWebAuthnCredentialStoreUpdateSignCount(t, rctx, ss) })
+	t.Run("Delete", func(t *testing.T) { testWebAuthnCredentialStoreDelete(t, rctx, ss) })
+}
+
+func newTestWebAuthnCredential(userID string) *model.WebAuthnCredential {
+	return &model.WebAuthnCredential{
+		UserId:       userID,
+		Name:         "Security key",
+		CredentialId: model.NewId() + model.NewId(),
+		PublicKey:    "pQECAyYgASFYIA",
+	}
+}
+
+func testWebAuthnCredentialStoreSave(t *testing.T, rctx request.CTX, ss store.Store) {
+	credential, err := ss.WebAuthnCredential().Save(newTestWebAuthnCredential(model.NewId()))
+	require.NoError(t, err)
+	require.NotEmpty(t, credential.Id)
+	require.NotZero(t, credential.CreateAt)
+
+	t.Run("get by id", func(t *testing.T) {
+		got, err := ss.WebAuthnCredential().Get(credential.Id)
+		require.NoError(t, err)
+		assert.Equal(t, credential, got)
+	})
+
+	t.Run("get by credential id", func(t *testing.T) {
+		got, err := ss.WebAuthnCredential().GetByCredentialId(credential.CredentialId)
+		require.NoError(t, err)
+		assert.Equal(t, credential, got)
+
+		_, err = ss.WebAuthnCredential().GetByCredentialId(model.NewId())
+		var nfErr *store.ErrNotFound
+		assert.ErrorAs(t, err, &nfErr)
+	})
+
+	t.Run("duplicate credential id", func(t *testing.T) {
+		duplicate := newTestWebAuthnCredential(model.NewId())
+		duplicate.CredentialId = credential.CredentialId
+		_, err := ss.WebAuthnCredential().Save(duplicate)
+		var cErr *store.ErrConflict
+		assert.ErrorAs(t, err, &cErr)
+	})
+
+	t.Run("invalid credential", func(t *testing.T) {
+		invalid := newTestWebAuthnCredential(model.NewId())
+		invalid.Name = ""
+		_, err := ss.WebAuthnCredential().Save(invalid)
+		var appErr *model.AppError
+		assert.ErrorAs(t, err, &appErr)
+	})
+}
+
+func testWebAuthnCredentialStoreGetForUser(t *testing.T, rctx request.CTX, ss store.Store) {
+	userID := model.NewId()
+
+	first := newTestWebAuthnCredential(userID)
+	first.CreateAt = 1000
+	first, err := ss.WebAuthnCredential().Save(first)
+	require.NoError(t, err)
+
+	second := newTestWebAuthnCredential(userID)
+	second.CreateAt = 2000
+	second, err = ss.WebAuthnCredential().Save(second)
+	require.NoError(t, err)
+
+	_, err = ss.WebAuthnCredential().Save(newTestWebAuthnCredential(model.NewId()))
+	require.NoError(t, err)
+
+	credentials, err := ss.WebAuthnCredential().GetForUser(userID)
+	require.NoError(t, err)
+	assert.Equal(t, []*model.WebAuthnCredential{first, second}, credentials)
+
+	credentials, err = ss.WebAuthnCredential().GetForUser(model.NewId())
+	require.NoError(t, err)
+	assert.Empty(t, credentials)
+}
+
+func testWebAuthnCredentialStoreUpdateSignCount(t *testing.T, rctx request.CTX, ss store.Store) {
+	credential, err := ss.WebAuthnCredential().Save(newTestWebAuthnCredential(model.NewId()))
+	require.NoError(t, err)
+
+	err = ss.WebAuthnCredential().UpdateSignCount(credential.Id, 42, 5000)
+	require.NoError(t, err)
+
+	got, err := ss.WebAuthnCredential().Get(credential.Id)
+	require.NoError(t, err)
+	assert.Equal(t, int64(42), got.SignCount)
+	assert.Equal(t, int64(5000), got.LastUsedAt)
+
+	err = ss.WebAuthnCredential().UpdateSignCount(model.NewId(), 1, 1)
+	var nfErr *store.ErrNotFound
+	assert.ErrorAs(t, err, &nfErr)
+}
+
+func testWebAuthnCredentialStoreDelete(t *testing.T, rctx request.CTX, ss store.Store) {
+	userID := model.NewId()
+
+	credential, err := ss.WebAuthnCredential().Save(newTestWebAuthnCredential(userID))
+	require.NoError(t, err)
+	_, err = ss.WebAuthnCredential().Save(newTestWebAuthnCredential(userID))
+	require.NoError(t, err)
+
+	other, err := ss.WebAuthnCredential().Save(newTestWebAuthnCredential(model.NewId()))
+	require.NoError(t, err)
+
+	err = ss.WebAuthnCredential().Delete(credential.Id)
+	require.NoError(t, err)
+
+	_, err = ss.WebAuthnCredential().Get(credential.Id)
+	var nfErr *store.ErrNotFound
+	assert.ErrorAs(t, err, &nfErr)
+
+	err = ss.WebAuthnCredential().Delete(credential.Id)
+	assert.ErrorAs(t, err, &nfErr)
+
+	err = ss.WebAuthnCredential().PermanentDeleteByUser(userID)
+	require.NoError(t, err)
+
+	credentials, err := ss.WebAuthnCredential().GetForUser(userID)
+	require.NoError(t, err)
+	assert.Empty(t, credentials)
+
+	_, err = ss.WebAuthnCredential().Get(other.Id)
+	require.NoError(t, err)
+}
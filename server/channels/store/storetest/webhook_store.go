@@ -41,6 +41,11 @@ func TestWebhookStore(t *testing.T, rctx request.CTX, ss store.Store) {
 	t.Run("UpdateOutgoing", func(t *testing.T) { testWebhookStoreUpdateOutgoing(t, rctx, ss) })
 	t.Run("CountIncoming", func(t *testing.T) { testWebhookStoreCountIncoming(t, rctx, ss) })
 	t.Run("CountOutgoing", func(t *testing.T) { testWebhookStoreCountOutgoing(t, rctx, ss) })
+	t.Run("SaveOutgoingDelivery", func(t *testing.T) { testWebhookStoreSaveOutgoingDelivery(t, rctx, ss) })
+	t.Run("UpdateOutgoingDelivery", func(t *testing.T) { testWebhookStoreUpdateOutgoingDelivery(t, rctx, ss) })
+	t.Run("GetDueOutgoingDeliveries", func(t *testing.T) { testWebhookStoreGetDueOutgoingDeliveries(t, rctx, ss) })
+	t.Run("GetOutgoingDeliveriesByHook", func(t *testing.T) { testWebhookStoreGetOutgoingDeliveriesByHook(t, rctx, ss) })
+	t.Run("DeleteOutgoingDeliveries", func(t *testing.T) { testWebhookStoreDeleteOutgoingDeliveries(t, rctx, ss) })
 }
 
 func testWebhookStoreSaveIncoming(t *testing.T, rctx request.CTX, ss store.Store) {
@@ -305,6 +310,8 @@ func testWebhookStoreGetOutgoing(t *testing.T, rctx request.CTX, ss store.Store)
 	webhook, err := ss.Webhook().GetOutgoing(o1.Id)
 	require.NoError(t, err)
 	require.Equal(t, webhook.CreateAt, o1.CreateAt, "invalid returned webhook")
+	require.NotEmpty(t, webhook.SigningSecret)
+	require.Equal(t, o1.SigningSecret, webhook.SigningSecret)
 
 	_, err = ss.Webhook().GetOutgoing("123")
 	require.Error(t, err, "Missing id should have failed")
@@ -615,3 +622,167 @@ func testWebhookStoreCountOutgoing(t *testing.T, rctx request.CTX, ss store.Stor
 	require.NoError(t, err)
 	require.NotEqual(t, 0, r, "should have at least 1 outgoing hook")
 }
+
+func buildOutgoingWebhookDelivery(hookID string) *model.OutgoingWebhookDelivery {
+	return &model.OutgoingWebhookDelivery{
+		HookId:      hookID,
+		TeamId:      model.NewId(),
+		ChannelId:   model.NewId(),
+		PostId:      model.NewId(),
+		CallbackURL: "http://nowhere.com/",
+		ContentType: "application/json",
+		Payload:     `{"text":"payload"}`,
+	}
+}
+
+func testWebhookStoreSaveOutgoingDelivery(t *testing.T, rctx request.CTX, ss store.Store) {
+	d1 := buildOutgoingWebhookDelivery(model.NewId())
+
+	saved, err := ss.Webhook().SaveOutgoingDelivery(d1)
+	require.NoError(t, err)
+	require.NotEmpty(t, saved.Id)
+	require.Equal(t, model.OutgoingWebhookDeliveryStatusPending, saved.Status)
+
+	_, err = ss.Webhook().SaveOutgoingDelivery(d1)
+	require.Error(t, err, "shouldn't be able to update from save")
+
+	got, err := ss.Webhook().GetOutgoingDelivery(saved.Id)
+	require.NoError(t, err)
+	require.Equal(t, saved, got)
+
+	_, err = ss.Webhook().GetOutgoingDelivery(model.NewId())
+	var nfErr *store.ErrNotFound
+	require.True(t, errors.As(err, &nfErr))
+
+	d2 := buildOutgoingWebhookDelivery(model.NewId())
+	d2.CallbackURL = "nowhere.com"
+	_, err = ss.Webhook().SaveOutgoingDelivery(d2)
+	require.Error(t, err, "shouldn't save an invalid delivery")
+}
+
+func testWebhookStoreUpdateOutgoingDelivery(t *testing.T, rctx request.CTX, ss store.Store) {
+	d1, err := ss.Webhook().SaveOutgoingDelivery(buildOutgoingWebhookDelivery(model.NewId()))
+	require.NoError(t, err)
+
+	d1.Attempts = 3
+	d1.LastError = "connection refused"
+	d1.LastAttemptAt = model.GetMillis()
+	d1.Status = model.OutgoingWebhookDeliveryStatusDeadLetter
+	_, err = ss.Webhook().UpdateOutgoingDelivery(d1)
+	require.NoError(t, err)
+
+	got, err := ss.Webhook().GetOutgoingDelivery(d1.Id)
+	require.NoError(t, err)
+	require.Equal(t, 3, got.Attempts)
+	require.Equal(t, "connection refused", got.LastError)
+	require.Equal(t, d1.LastAttemptAt, got.LastAttemptAt)
+	require.Equal(t, model.OutgoingWebhookDeliveryStatusDeadLetter, got.Status)
+
+	missing := buildOutgoingWebhookDelivery(model.NewId())
+	missing.PreSave()
+	_, err = ss.Webhook().UpdateOutgoingDelivery(missing)
+	var nfErr *store.ErrNotFound
+	require.True(t, errors.As(err, &nfErr))
+}
+
+func testWebhookStoreGetDueOutgoingDeliveries(t *testing.T, rctx request.CTX, ss store.Store) {
+	// Keep the deliveries of this test apart from the ones other tests leave behind.
+	now := model.GetMillis() - 10*time.Hour.Milliseconds()
+
+	due1 := buildOutgoingWebhookDelivery(model.NewId())
+	due1.NextAttemptAt = now - 2000
+	due1, err := ss.Webhook().SaveOutgoingDelivery(due1)
+	require.NoError(t, err)
+
+	due2 := buildOutgoingWebhookDelivery(model.NewId())
+	due2.NextAttemptAt = now - 1000
+	due2, err = ss.Webhook().SaveOutgoingDelivery(due2)
+	require.NoError(t, err)
+
+	notDue := buildOutgoingWebhookDelivery(model.NewId())
+	notDue.NextAttemptAt = now + 1000
+	_, err = ss.Webhook().SaveOutgoingDelivery(notDue)
+	require.NoError(t, err)
+
+	deadLetter := buildOutgoingWebhookDelivery(model.NewId())
+	deadLetter.NextAttemptAt = now - 1500
+	deadLetter.Status = model.OutgoingWebhookDeliveryStatusDeadLetter
+	_, err = ss.Webhook().SaveOutgoingDelivery(deadLetter)
+	require.NoError(t, err)
+
+	deliveries, err := ss.Webhook().GetDueOutgoingDeliveries(now, now-3000, "", 10)
+	require.NoError(t, err)
+	require.Len(t, deliveries, 2)
+	require.Equal(t, due1.Id, deliveries[0].Id)
+	require.Equal(t, due2.Id, deliveries[1].Id)
+
+	deliveries, err = ss.Webhook().GetDueOutgoingDeliveries(now, now-3000, "", 1)
+	require.NoError(t, err)
+	require.Len(t, deliveries, 1)
+	require.Equal(t, due1.Id, deliveries[0].Id)
+
+	deliveries, err = ss.Webhook().GetDueOutgoingDeliveries(now, deliveries[0].NextAttemptAt, deliveries[0].Id, 1)
+	require.NoError(t, err)
+	require.Len(t, deliveries, 1)
+	require.Equal(t, due2.Id, deliveries[0].Id)
+}
+
+func testWebhookStoreGetOutgoingDeliveriesByHook(t *testing.T, rctx request.CTX, ss store.Store) {
+	hookID := model.NewId()
+
+	pending, err := ss.Webhook().SaveOutgoingDelivery(buildOutgoingWebhookDelivery(hookID))
+	require.NoError(t, err)
+
+	deadLetter := buildOutgoingWebhookDelivery(hookID)
+	deadLetter.Status = model.OutgoingWebhookDeliveryStatusDeadLetter
+	deadLetter, err = ss.Webhook().SaveOutgoingDelivery(deadLetter)
+	require.NoError(t, err)
+
+	_, err = ss.Webhook().SaveOutgoingDelivery(buildOutgoingWebhookDelivery(model.NewId()))
+	require.NoError(t, err)
+
+	deliveries, err := ss.Webhook().GetOutgoingDeliveriesByHook(hookID, "", 0, 10)
+	require.NoError(t, err)
+	require.Len(t, deliveries, 2)
+
+	deliveries, err = ss.Webhook().GetOutgoingDeliveriesByHook(hookID, model.OutgoingWebhookDeliveryStatusDeadLetter, 0, 10)
+	require.NoError(t, err)
+	require.Len(t, deliveries, 1)
+	require.Equal(t, deadLetter.Id, deliveries[0].Id)
+
+	deliveries, err = ss.Webhook().GetOutgoingDeliveriesByHook(hookID, model.OutgoingWebhookDeliveryStatusPending, 0, 10)
+	require.NoError(t, err)
+	require.Len(t, deliveries, 1)
+	require.Equal(t, pending.Id, deliveries[0].Id)
+
+	deliveries, err = ss.Webhook().GetOutgoingDeliveriesByHook(hookID, "", 1, 10)
+	require.NoError(t, err)
+	require.Len(t, deliveries, 1)
+}
+
+func testWebhookStoreDeleteOutgoingDeliveries(t *testing.T, rctx request.CTX, ss store.Store) {
+	hookID := model.NewId()
+
+	d1, err := ss.Webhook().SaveOutgoingDelivery(buildOutgoingWebhookDelivery(hookID))
+	require.NoError(t, err)
+	d2, err := ss.Webhook().SaveOutgoingDelivery(buildOutgoingWebhookDelivery(hookID))
+	require.NoError(t, err)
+	other, err := ss.Webhook().SaveOutgoingDelivery(buildOutgoingWebhookDelivery(model.NewId()))
+	require.NoError(t, err)
+
+	err = ss.Webhook().PermanentDeleteOutgoingDelivery(d1.Id)
+	require.NoError(t, err)
+
+	_, err = ss.Webhook().GetOutgoingDelivery(d1.Id)
+	require.Error(t, err)
+	_, err = ss.Webhook().GetOutgoingDelivery(d2.Id)
+	require.NoError(t, err)
+
+	err = ss.Webhook().PermanentDeleteOutgoingDeliveriesByHook(hookID)
+	require.NoError(t, err)
+
+	_, err = ss.Webhook().GetOutgoingDelivery(d2.Id)
+	require.Error(t, err)
+	_, err = ss.Webhook().GetOutgoingDelivery(other.Id)
+	require.NoError(t, err)
+}
@@ -20,6 +20,7 @@ type TimerLayer struct {
 	store.Store
 	Metrics                         einterfaces.MetricsInterface
 	AuditStore                      store.AuditStore
+	AuditRecordStore                store.AuditRecordStore
 	BotStore                        store.BotStore
 	ChannelStore                    store.ChannelStore
 	ChannelBookmarkStore            store.ChannelBookmarkStore
@@ -31,6 +32,8 @@ type TimerLayer struct {
 	DesktopTokensStore              store.DesktopTokensStore
 	DraftStore                      store.DraftStore
 	EmojiStore                      store.EmojiStore
+	ExpiringPostStore               store.ExpiringPostStore
+	FileBlobStore                   store.FileBlobStore
 	FileInfoStore                   store.FileInfoStore
 	GroupStore                      store.GroupStore
 	JobStore                        store.JobStore
@@ -40,8 +43,10 @@ type TimerLayer struct {
 	OAuthStore                      store.OAuthStore
 	OutgoingOAuthConnectionStore    store.OutgoingOAuthConnectionStore
 	PluginStore                     store.PluginStore
+	PollVoteStore                   store.PollVoteStore
 	PostStore                       store.PostStore
 	PostAcknowledgementStore        store.PostAcknowledgementStore
+	PostEmbeddingStore              store.PostEmbeddingStore
 	PostPersistentNotificationStore store.PostPersistentNotificationStore
 	PostPriorityStore               store.PostPriorityStore
 	PreferenceStore                 store.PreferenceStore
@@ -50,6 +55,8 @@ type TimerLayer struct {
 	RemoteClusterStore              store.RemoteClusterStore
 	RetentionPolicyStore            store.RetentionPolicyStore
 	RoleStore                       store.RoleStore
+	SavedSearchStore                store.SavedSearchStore
+	ScheduledPostStore              store.ScheduledPostStore
 	SchemeStore                     store.SchemeStore
 	SessionStore                    store.SessionStore
 	SharedChannelStore              store.SharedChannelStore
@@ -63,6 +70,7 @@ type TimerLayer struct {
 	UserStore                       store.UserStore
 	UserAccessTokenStore            store.UserAccessTokenStore
 	UserTermsOfServiceStore         store.UserTermsOfServiceStore
+	WebAuthnCredentialStore         store.WebAuthnCredentialStore
 	WebhookStore                    store.WebhookStore
 }
 
@@ -70,6 +78,10 @@ func (s *TimerLayer) Audit() store.AuditStore {
 	return s.AuditStore
 }
 
+func (s *TimerLayer) AuditRecord() store.AuditRecordStore {
+	return s.AuditRecordStore
+}
+
 func (s *TimerLayer) Bot() store.BotStore {
 	return s.BotStore
 }
@@ -114,6 +126,14 @@ func (s *TimerLayer) Emoji() store.EmojiStore {
 	return s.EmojiStore
 }
 
+func (s *TimerLayer) ExpiringPost() store.ExpiringPostStore {
+	return s.ExpiringPostStore
+}
+
+func (s *TimerLayer) FileBlob() store.FileBlobStore {
+	return s.FileBlobStore
+}
+
 func (s *TimerLayer) FileInfo() store.FileInfoStore {
 	return s.FileInfoStore
 }
@@ -150,6 +170,10 @@ func (s *TimerLayer) Plugin() store.PluginStore {
 	return s.PluginStore
 }
 
+func (s *TimerLayer) PollVote() store.PollVoteStore {
+	return s.PollVoteStore
+}
+
 func (s *TimerLayer) Post() store.PostStore {
 	return s.PostStore
 }
@@ -158,6 +182,10 @@ func (s *TimerLayer) PostAcknowledgement() store.PostAcknowledgementStore {
 	return s.PostAcknowledgementStore
 }
 
+func (s *TimerLayer) PostEmbedding() store.PostEmbeddingStore {
+	return s.PostEmbeddingStore
+}
+
 func (s *TimerLayer) PostPersistentNotification() store.PostPersistentNotificationStore {
 	return s.PostPersistentNotificationStore
 }
@@ -190,6 +218,14 @@ func (s *TimerLayer) Role() store.RoleStore {
 	return s.RoleStore
 }
 
+func (s *TimerLayer) SavedSearch() store.SavedSearchStore {
+	return s.SavedSearchStore
+}
+
+func (s *TimerLayer) ScheduledPost() store.ScheduledPostStore {
+	return s.ScheduledPostStore
+}
+
 func (s *TimerLayer) Scheme() store.SchemeStore {
 	return s.SchemeStore
 }
@@ -242,6 +278,10 @@ func (s *TimerLayer) UserTermsOfService() store.UserTermsOfServiceStore {
 	return s.UserTermsOfServiceStore
 }
 
+func (s *TimerLayer) WebAuthnCredential() store.WebAuthnCredentialStore {
+	return s.WebAuthnCredentialStore
+}
+
 func (s *TimerLayer) Webhook() store.WebhookStore {
 	return s.WebhookStore
 }
@@ -251,6 +291,11 @@ type TimerLayerAuditStore struct {
 	Root *TimerLayer
 }
 
+type TimerLayerAuditRecordStore struct {
+	store.AuditRecordStore
+	Root *TimerLayer
+}
+
 type TimerLayerBotStore struct {
 	store.BotStore
 	Root *TimerLayer
@@ -306,6 +351,16 @@ type TimerLayerEmojiStore struct {
 	Root *TimerLayer
 }
 
+type TimerLayerExpiringPostStore struct {
+	store.ExpiringPostStore
+	Root *TimerLayer
+}
+
+type TimerLayerFileBlobStore struct {
+	store.FileBlobStore
+	Root *TimerLayer
+}
+
 type TimerLayerFileInfoStore struct {
 	store.FileInfoStore
 	Root *TimerLayer
@@ -351,6 +406,11 @@ type TimerLayerPluginStore struct {
 	Root *TimerLayer
 }
 
+type TimerLayerPollVoteStore struct {
+	store.PollVoteStore
+	Root *TimerLayer
+}
+
 type TimerLayerPostStore struct {
 	store.PostStore
 	Root *TimerLayer
@@ -361,6 +421,11 @@ type TimerLayerPostAcknowledgementStore struct {
 	Root *TimerLayer
 }
 
+type TimerLayerPostEmbeddingStore struct {
+	store.PostEmbeddingStore
+	Root *TimerLayer
+}
+
 type TimerLayerPostPersistentNotificationStore struct {
 	store.PostPersistentNotificationStore
 	Root *TimerLayer
@@ -401,6 +466,16 @@ type TimerLayerRoleStore struct {
 	Root *TimerLayer
 }
 
+type TimerLayerSavedSearchStore struct {
+	store.SavedSearchStore
+	Root *TimerLayer
+}
+
+type TimerLayerScheduledPostStore struct {
+	store.ScheduledPostStore
+	Root *TimerLayer
+}
+
 type TimerLayerSchemeStore struct {
 	store.SchemeStore
 	Root *TimerLayer
@@ -466,6 +541,11 @@ type TimerLayerUserTermsOfServiceStore struct {
 	Root *TimerLayer
 }
 
+type TimerLayerWebAuthnCredentialStore struct {
+	store.WebAuthnCredentialStore
+	Root *TimerLayer
+}
+
 type TimerLayerWebhookStore struct {
 	store.WebhookStore
 	Root *TimerLayer
@@ -519,6 +599,54 @@ func (s *TimerLayerAuditStore) Save(audit *model.Audit) error {
 	return err
 }
 
+func (s *TimerLayerAuditRecordStore) PermanentDeleteBatch(endTime int64, limit int64) (int64, error) {
+	start := time.Now()
+
+	result, err := s.AuditRecordStore.PermanentDeleteBatch(endTime, limit)
+
+	elapsed := float64(time.Since(start)) / float64(time.Second)
+	if s.Root.Metrics != nil {
+		success := "false"
+		if err == nil {
+			success = "true"
+		}
+		s.Root.Metrics.ObserveStoreMethodDuration("AuditRecordStore.PermanentDeleteBatch", success, elapsed)
+	}
+	return result, err
+}
+
+func (s *TimerLayerAuditRecordStore) Save(record *model.AuditRecord) (*model.AuditRecord, error) {
+	start := time.Now()
+
+	result, err := s.AuditRecordStore.Save(record)
+
+	elapsed := float64(time.Since(start)) / float64(time.Second)
+	if s.Root.Metrics != nil {
+		success := "false"
+		if err == nil {
+			success = "true"
+		}
+		s.Root.Metrics.ObserveStoreMethodDuration("AuditRecordStore.Save", success, elapsed)
+	}
+	return result, err
+}
+
+func (s *TimerLayerAuditRecordStore) Search(search *model.AuditRecordSearch) ([]*model.AuditRecord, error) {
+	start := time.Now()
+
+	result, err := s.AuditRecordStore.Search(search)
+
+	elapsed := float64(time.Since(start)) / float64(time.Second)
+	if s.Root.Metrics != nil {
+		success := "false"
+		if err == nil {
+			success = "true"
+		}
+		s.Root.Metrics.ObserveStoreMethodDuration("AuditRecordStore.Search", success, elapsed)
+	}
+	return result, err
+}
+
 func (s *TimerLayerBotStore) Get(userID string, includeDeleted bool) (*model.Bot, error) {
 	start := time.Now()
 
@@ -1573,6 +1701,22 @@ func (s *TimerLayerChannelStore) GetMembersInfoByChannelIds(channelIDs []string)
 	return result, err
 }
 
+func (s *TimerLayerChannelStore) GetMembersLastViewedAtSince(ctx context.Context, channelID string, since int64) (map[string]int64, error) {
+	start := time.Now()
+
+	result, err := s.ChannelStore.GetMembersLastViewedAtSince(ctx, channelID, since)
+
+	elapsed := float64(time.Since(start)) / float64(time.Second)
+	if s.Root.Metrics != nil {
+		success := "false"
+		if err == nil {
+			success = "true"
+		}
+		s.Root.Metrics.ObserveStoreMethodDuration("ChannelStore.GetMembersLastViewedAtSince", success, elapsed)
+	}
+	return result, err
+}
+
 func (s *TimerLayerChannelStore) GetMoreChannels(teamID string, userID string, offset int, limit int) (model.ChannelList, error) {
 	start := time.Now()
 
@@ -3421,6 +3565,166 @@ func (s *TimerLayerEmojiStore) Search(name string, prefixOnly bool, limit int) (
 	return result, err
 }
 
+func (s *TimerLayerExpiringPostStore) DeleteForPosts(postIDs []string) error {
+	start := time.Now()
+
+	err := s.ExpiringPostStore.DeleteForPosts(postIDs)
+
+	elapsed := float64(time.Since(start)) / float64(time.Second)
+	if s.Root.Metrics != nil {
+		success := "false"
+		if err == nil {
+			success = "true"
+		}
+		s.Root.Metrics.ObserveStoreMethodDuration("ExpiringPostStore.DeleteForPosts", success, elapsed)
+	}
+	return err
+}
+
+func (s *TimerLayerExpiringPostStore) GetExpired(now int64, limit int) ([]*model.ExpiringPost, error) {
+	start := time.Now()
+
+	result, err := s.ExpiringPostStore.GetExpired(now, limit)
+
+	elapsed := float64(time.Since(start)) / float64(time.Second)
+	if s.Root.Metrics != nil {
+		success := "false"
+		if err == nil {
+			success = "true"
+		}
+		s.Root.Metrics.ObserveStoreMethodDuration("ExpiringPostStore.GetExpired", success, elapsed)
+	}
+	return result, err
+}
+
+func (s *TimerLayerExpiringPostStore) Save(expiringPost *model.ExpiringPost) (*model.ExpiringPost, error) {
+	start := time.Now()
+
+	result, err := s.ExpiringPostStore.Save(expiringPost)
+
+	elapsed := float64(time.Since(start)) / float64(time.Second)
+	if s.Root.Metrics != nil {
+		success := "false"
+		if err == nil {
+			success = "true"
+		}
+		s.Root.Metrics.ObserveStoreMethodDuration("ExpiringPostStore.Save", success, elapsed)
+	}
+	return result, err
+}
+
+func (s *TimerLayerFileBlobStore) Acquire(hash string, path string, size int64) (*model.FileBlob, bool, error) {
+	start := time.Now()
+
+	result, resultVar1, err := s.FileBlobStore.Acquire(hash, path, size)
+
+	elapsed := float64(time.Since(start)) / float64(time.Second)
+	if s.Root.Metrics != nil {
+		success := "false"
+		if err == nil {
+			success = "true"
+		}
+		s.Root.Metrics.ObserveStoreMethodDuration("FileBlobStore.Acquire", success, elapsed)
+	}
+	return result, resultVar1, err
+}
+
+func (s *TimerLayerFileBlobStore) DeleteTombstoned(hash string) error {
+	start := time.Now()
+
+	err := s.FileBlobStore.DeleteTombstoned(hash)
+
+	elapsed := float64(time.Since(start)) / float64(time.Second)
+	if s.Root.Metrics != nil {
+		success := "false"
+		if err == nil {
+			success = "true"
+		}
+		s.Root.Metrics.ObserveStoreMethodDuration("FileBlobStore.DeleteTombstoned", success, elapsed)
+	}
+	return err
+}
+
+func (s *TimerLayerFileBlobStore) Get(hash string) (*model.FileBlob, error) {
+	start := time.Now()
+
+	result, err := s.FileBlobStore.Get(hash)
+
+	elapsed := float64(time.Since(start)) / float64(time.Second)
+	if s.Root.Metrics != nil {
+		success := "false"
+		if err == nil {
+			success = "true"
+		}
+		s.Root.Metrics.ObserveStoreMethodDuration("FileBlobStore.Get", success, elapsed)
+	}
+	return result, err
+}
+
+func (s *TimerLayerFileBlobStore) GetSavedBytes() (int64, error) {
+	start := time.Now()
+
+	result, err := s.FileBlobStore.GetSavedBytes()
+
+	elapsed := float64(time.Since(start)) / float64(time.Second)
+	if s.Root.Metrics != nil {
+		success := "false"
+		if err == nil {
+			success = "true"
+		}
+		s.Root.Metrics.ObserveStoreMethodDuration("FileBlobStore.GetSavedBytes", success, elapsed)
+	}
+	return result, err
+}
+
+func (s *TimerLayerFileBlobStore) GetUnreferenced(afterHash string, limit int) ([]*model.FileBlob, error) {
+	start := time.Now()
+
+	result, err := s.FileBlobStore.GetUnreferenced(afterHash, limit)
+
+	elapsed := float64(time.Since(start)) / float64(time.Second)
+	if s.Root.Metrics != nil {
+		success := "false"
+		if err == nil {
+			success = "true"
+		}
+		s.Root.Metrics.ObserveStoreMethodDuration("FileBlobStore.GetUnreferenced", success, elapsed)
+	}
+	return result, err
+}
+
+func (s *TimerLayerFileBlobStore) Release(hash string) (int64, error) {
+	start := time.Now()
+
+	result, err := s.FileBlobStore.Release(hash)
+
+	elapsed := float64(time.Since(start)) / float64(time.Second)
+	if s.Root.Metrics != nil {
+		success := "false"
+		if err == nil {
+			success = "true"
+		}
+		s.Root.Metrics.ObserveStoreMethodDuration("FileBlobStore.Release", success, elapsed)
+	}
+	return result, err
+}
+
+func (s *TimerLayerFileBlobStore) Tombstone(hash string) (bool, error) {
+	start := time.Now()
+
+	result, err := s.FileBlobStore.Tombstone(hash)
+
+	elapsed := float64(time.Since(start)) / float64(time.Second)
+	if s.Root.Metrics != nil {
+		success := "false"
+		if err == nil {
+			success = "true"
+		}
+		s.Root.Metrics.ObserveStoreMethodDuration("FileBlobStore.Tombstone", success, elapsed)
+	}
+	return result, err
+}
+
 func (s *TimerLayerFileInfoStore) AttachToPost(c request.CTX, fileID string, postID string, channelID string, creatorID string) error {
 	start := time.Now()
 
@@ -3468,6 +3772,22 @@ func (s *TimerLayerFileInfoStore) CountAll() (int64, error) {
 	return result, err
 }
 
+func (s *TimerLayerFileInfoStore) CountByPath(path string) (int64, error) {
+	start := time.Now()
+
+	result, err := s.FileInfoStore.CountByPath(path)
+
+	elapsed := float64(time.Since(start)) / float64(time.Second)
+	if s.Root.Metrics != nil {
+		success := "false"
+		if err == nil {
+			success = "true"
+		}
+		s.Root.Metrics.ObserveStoreMethodDuration("FileInfoStore.CountByPath", success, elapsed)
+	}
+	return result, err
+}
+
 func (s *TimerLayerFileInfoStore) DeleteForPost(c request.CTX, postID string) (string, error) {
 	start := time.Now()
 
@@ -3755,10 +4075,10 @@ func (s *TimerLayerFileInfoStore) SetContent(ctx request.CTX, fileID string, con
 	return err
 }
 
-func (s *TimerLayerFileInfoStore) Upsert(rctx request.CTX, info *model.FileInfo) (*model.FileInfo, error) {
+func (s *TimerLayerFileInfoStore) SetContentHash(ctx request.CTX, fileID string, contentHash string, path string) error {
 	start := time.Now()
 
-	result, err := s.FileInfoStore.Upsert(rctx, info)
+	err := s.FileInfoStore.SetContentHash(ctx, fileID, contentHash, path)
 
 	elapsed := float64(time.Since(start)) / float64(time.Second)
 	if s.Root.Metrics != nil {
@@ -3766,15 +4086,15 @@ func (s *TimerLayerFileInfoStore) Upsert(rctx request.CTX, info *model.FileInfo)
 		if err == nil {
 			success = "true"
 		}
-		s.Root.Metrics.ObserveStoreMethodDuration("FileInfoStore.Upsert", success, elapsed)
+		s.Root.Metrics.ObserveStoreMethodDuration("FileInfoStore.SetContentHash", success, elapsed)
 	}
-	return result, err
+	return err
 }
 
-func (s *TimerLayerGroupStore) AdminRoleGroupsForSyncableMember(userID string, syncableID string, syncableType model.GroupSyncableType) ([]string, error) {
+func (s *TimerLayerFileInfoStore) SetMediaPreviews(ctx request.CTX, info *model.FileInfo) error {
 	start := time.Now()
 
-	result, err := s.GroupStore.AdminRoleGroupsForSyncableMember(userID, syncableID, syncableType)
+	err := s.FileInfoStore.SetMediaPreviews(ctx, info)
 
 	elapsed := float64(time.Since(start)) / float64(time.Second)
 	if s.Root.Metrics != nil {
@@ -3782,15 +4102,15 @@ func (s *TimerLayerGroupStore) AdminRoleGroupsForSyncableMember(userID string, s
 		if err == nil {
 			success = "true"
 		}
-		s.Root.Metrics.ObserveStoreMethodDuration("GroupStore.AdminRoleGroupsForSyncableMember", success, elapsed)
+		s.Root.Metrics.ObserveStoreMethodDuration("FileInfoStore.SetMediaPreviews", success, elapsed)
 	}
-	return result, err
+	return err
 }
 
-func (s *TimerLayerGroupStore) ChannelMembersMinusGroupMembers(channelID string, groupIDs []string, page int, perPage int) ([]*model.UserWithGroups, error) {
+func (s *TimerLayerFileInfoStore) SetRenditionPath(ctx request.CTX, fileID string, path string) error {
 	start := time.Now()
 
-	result, err := s.GroupStore.ChannelMembersMinusGroupMembers(channelID, groupIDs, page, perPage)
+	err := s.FileInfoStore.SetRenditionPath(ctx, fileID, path)
 
 	elapsed := float64(time.Since(start)) / float64(time.Second)
 	if s.Root.Metrics != nil {
@@ -3798,15 +4118,15 @@ func (s *TimerLayerGroupStore) ChannelMembersMinusGroupMembers(channelID string,
 		if err == nil {
 			success = "true"
 		}
-		s.Root.Metrics.ObserveStoreMethodDuration("GroupStore.ChannelMembersMinusGroupMembers", success, elapsed)
+		s.Root.Metrics.ObserveStoreMethodDuration("FileInfoStore.SetRenditionPath", success, elapsed)
 	}
-	return result, err
+	return err
 }
 
-func (s *TimerLayerGroupStore) ChannelMembersToAdd(since int64, channelID *string, includeRemovedMembers bool) ([]*model.UserChannelIDPair, error) {
+func (s *TimerLayerFileInfoStore) SetScanStatus(ctx request.CTX, fileID string, status string, result string) error {
 	start := time.Now()
 
-	result, err := s.GroupStore.ChannelMembersToAdd(since, channelID, includeRemovedMembers)
+	err := s.FileInfoStore.SetScanStatus(ctx, fileID, status, result)
 
 	elapsed := float64(time.Since(start)) / float64(time.Second)
 	if s.Root.Metrics != nil {
@@ -3814,12 +4134,76 @@ func (s *TimerLayerGroupStore) ChannelMembersToAdd(since int64, channelID *strin
 		if err == nil {
 			success = "true"
 		}
-		s.Root.Metrics.ObserveStoreMethodDuration("GroupStore.ChannelMembersToAdd", success, elapsed)
+		s.Root.Metrics.ObserveStoreMethodDuration("FileInfoStore.SetScanStatus", success, elapsed)
 	}
-	return result, err
+	return err
 }
 
-func (s *TimerLayerGroupStore) ChannelMembersToRemove(channelID *string) ([]*model.ChannelMember, error) {
+func (s *TimerLayerFileInfoStore) Upsert(rctx request.CTX, info *model.FileInfo) (*model.FileInfo, error) {
+	start := time.Now()
+
+	result, err := s.FileInfoStore.Upsert(rctx, info)
+
+	elapsed := float64(time.Since(start)) / float64(time.Second)
+	if s.Root.Metrics != nil {
+		success := "false"
+		if err == nil {
+			success = "true"
+		}
+		s.Root.Metrics.ObserveStoreMethodDuration("FileInfoStore.Upsert", success, elapsed)
+	}
+	return result, err
+}
+
+func (s *TimerLayerGroupStore) AdminRoleGroupsForSyncableMember(userID string, syncableID string, syncableType model.GroupSyncableType) ([]string, error) {
+	start := time.Now()
+
+	result, err := s.GroupStore.AdminRoleGroupsForSyncableMember(userID, syncableID, syncableType)
+
+	elapsed := float64(time.Since(start)) / float64(time.Second)
+	if s.Root.Metrics != nil {
+		success := "false"
+		if err == nil {
+			success = "true"
+		}
+		s.Root.Metrics.ObserveStoreMethodDuration("GroupStore.AdminRoleGroupsForSyncableMember", success, elapsed)
+	}
+	return result, err
+}
+
+func (s *TimerLayerGroupStore) ChannelMembersMinusGroupMembers(channelID string, groupIDs []string, page int, perPage int) ([]*model.UserWithGroups, error) {
+	start := time.Now()
+
+	result, err := s.GroupStore.ChannelMembersMinusGroupMembers(channelID, groupIDs, page, perPage)
+
+	elapsed := float64(time.Since(start)) / float64(time.Second)
+	if s.Root.Metrics != nil {
+		success := "false"
+		if err == nil {
+			success = "true"
+		}
+		s.Root.Metrics.ObserveStoreMethodDuration("GroupStore.ChannelMembersMinusGroupMembers", success, elapsed)
+	}
+	return result, err
+}
+
+func (s *TimerLayerGroupStore) ChannelMembersToAdd(since int64, channelID *string, includeRemovedMembers bool) ([]*model.UserChannelIDPair, error) {
+	start := time.Now()
+
+	result, err := s.GroupStore.ChannelMembersToAdd(since, channelID, includeRemovedMembers)
+
+	elapsed := float64(time.Since(start)) / float64(time.Second)
+	if s.Root.Metrics != nil {
+		success := "false"
+		if err == nil {
+			success = "true"
+		}
+		s.Root.Metrics.ObserveStoreMethodDuration("GroupStore.ChannelMembersToAdd", success, elapsed)
+	}
+	return result, err
+}
+
+func (s *TimerLayerGroupStore) ChannelMembersToRemove(channelID *string) ([]*model.ChannelMember, error) {
 	start := time.Now()
 
 	result, err := s.GroupStore.ChannelMembersToRemove(channelID)
@@ -5627,6 +6011,70 @@ func (s *TimerLayerPluginStore) SetWithOptions(pluginID string, key string, valu
 	return result, err
 }
 
+func (s *TimerLayerPollVoteStore) DeleteForPost(postID string) error {
+	start := time.Now()
+
+	err := s.PollVoteStore.DeleteForPost(postID)
+
+	elapsed := float64(time.Since(start)) / float64(time.Second)
+	if s.Root.Metrics != nil {
+		success := "false"
+		if err == nil {
+			success = "true"
+		}
+		s.Root.Metrics.ObserveStoreMethodDuration("PollVoteStore.DeleteForPost", success, elapsed)
+	}
+	return err
+}
+
+func (s *TimerLayerPollVoteStore) GetForPost(postID string) ([]*model.PollVote, error) {
+	start := time.Now()
+
+	result, err := s.PollVoteStore.GetForPost(postID)
+
+	elapsed := float64(time.Since(start)) / float64(time.Second)
+	if s.Root.Metrics != nil {
+		success := "false"
+		if err == nil {
+			success = "true"
+		}
+		s.Root.Metrics.ObserveStoreMethodDuration("PollVoteStore.GetForPost", success, elapsed)
+	}
+	return result, err
+}
+
+func (s *TimerLayerPollVoteStore) GetForPosts(postIDs []string) ([]*model.PollVote, error) {
+	start := time.Now()
+
+	result, err := s.PollVoteStore.GetForPosts(postIDs)
+
+	elapsed := float64(time.Since(start)) / float64(time.Second)
+	if s.Root.Metrics != nil {
+		success := "false"
+		if err == nil {
+			success = "true"
+		}
+		s.Root.Metrics.ObserveStoreMethodDuration("PollVoteStore.GetForPosts", success, elapsed)
+	}
+	return result, err
+}
+
+func (s *TimerLayerPollVoteStore) SaveForUser(postID string, userID string, optionIDs []string) ([]*model.PollVote, error) {
+	start := time.Now()
+
+	result, err := s.PollVoteStore.SaveForUser(postID, userID, optionIDs)
+
+	elapsed := float64(time.Since(start)) / float64(time.Second)
+	if s.Root.Metrics != nil {
+		success := "false"
+		if err == nil {
+			success = "true"
+		}
+		s.Root.Metrics.ObserveStoreMethodDuration("PollVoteStore.SaveForUser", success, elapsed)
+	}
+	return result, err
+}
+
 func (s *TimerLayerPostStore) AnalyticsPostCount(options *model.PostCountOptions) (int64, error) {
 	start := time.Now()
 
@@ -6281,6 +6729,22 @@ func (s *TimerLayerPostStore) PermanentDeleteByUser(rctx request.CTX, userID str
 	return err
 }
 
+func (s *TimerLayerPostStore) PermanentDeletePosts(rctx request.CTX, posts []*model.Post) error {
+	start := time.Now()
+
+	err := s.PostStore.PermanentDeletePosts(rctx, posts)
+
+	elapsed := float64(time.Since(start)) / float64(time.Second)
+	if s.Root.Metrics != nil {
+		success := "false"
+		if err == nil {
+			success = "true"
+		}
+		s.Root.Metrics.ObserveStoreMethodDuration("PostStore.PermanentDeletePosts", success, elapsed)
+	}
+	return err
+}
+
 func (s *TimerLayerPostStore) Save(rctx request.CTX, post *model.Post) (*model.Post, error) {
 	start := time.Now()
 
@@ -6457,6 +6921,118 @@ func (s *TimerLayerPostAcknowledgementStore) Save(postID string, userID string,
 	return result, err
 }
 
+func (s *TimerLayerPostEmbeddingStore) Delete(postID string) error {
+	start := time.Now()
+
+	err := s.PostEmbeddingStore.Delete(postID)
+
+	elapsed := float64(time.Since(start)) / float64(time.Second)
+	if s.Root.Metrics != nil {
+		success := "false"
+		if err == nil {
+			success = "true"
+		}
+		s.Root.Metrics.ObserveStoreMethodDuration("PostEmbeddingStore.Delete", success, elapsed)
+	}
+	return err
+}
+
+func (s *TimerLayerPostEmbeddingStore) DeleteAll() error {
+	start := time.Now()
+
+	err := s.PostEmbeddingStore.DeleteAll()
+
+	elapsed := float64(time.Since(start)) / float64(time.Second)
+	if s.Root.Metrics != nil {
+		success := "false"
+		if err == nil {
+			success = "true"
+		}
+		s.Root.Metrics.ObserveStoreMethodDuration("PostEmbeddingStore.DeleteAll", success, elapsed)
+	}
+	return err
+}
+
+func (s *TimerLayerPostEmbeddingStore) DeleteBefore(endTime int64) (int64, error) {
+	start := time.Now()
+
+	result, err := s.PostEmbeddingStore.DeleteBefore(endTime)
+
+	elapsed := float64(time.Since(start)) / float64(time.Second)
+	if s.Root.Metrics != nil {
+		success := "false"
+		if err == nil {
+			success = "true"
+		}
+		s.Root.Metrics.ObserveStoreMethodDuration("PostEmbeddingStore.DeleteBefore", success, elapsed)
+	}
+	return result, err
+}
+
+func (s *TimerLayerPostEmbeddingStore) DeleteForChannel(channelID string) (int64, error) {
+	start := time.Now()
+
+	result, err := s.PostEmbeddingStore.DeleteForChannel(channelID)
+
+	elapsed := float64(time.Since(start)) / float64(time.Second)
+	if s.Root.Metrics != nil {
+		success := "false"
+		if err == nil {
+			success = "true"
+		}
+		s.Root.Metrics.ObserveStoreMethodDuration("PostEmbeddingStore.DeleteForChannel", success, elapsed)
+	}
+	return result, err
+}
+
+func (s *TimerLayerPostEmbeddingStore) DeleteForUser(userID string) (int64, error) {
+	start := time.Now()
+
+	result, err := s.PostEmbeddingStore.DeleteForUser(userID)
+
+	elapsed := float64(time.Since(start)) / float64(time.Second)
+	if s.Root.Metrics != nil {
+		success := "false"
+		if err == nil {
+			success = "true"
+		}
+		s.Root.Metrics.ObserveStoreMethodDuration("PostEmbeddingStore.DeleteForUser", success, elapsed)
+	}
+	return result, err
+}
+
+func (s *TimerLayerPostEmbeddingStore) GetPostsForChannels(provider string, opts model.PostEmbeddingSearchOptions) ([]*model.PostWithEmbedding, error) {
+	start := time.Now()
+
+	result, err := s.PostEmbeddingStore.GetPostsForChannels(provider, opts)
+
+	elapsed := float64(time.Since(start)) / float64(time.Second)
+	if s.Root.Metrics != nil {
+		success := "false"
+		if err == nil {
+			success = "true"
+		}
+		s.Root.Metrics.ObserveStoreMethodDuration("PostEmbeddingStore.GetPostsForChannels", success, elapsed)
+	}
+	return result, err
+}
+
+func (s *TimerLayerPostEmbeddingStore) Save(embedding *model.PostEmbedding) error {
+	start := time.Now()
+
+	err := s.PostEmbeddingStore.Save(embedding)
+
+	elapsed := float64(time.Since(start)) / float64(time.Second)
+	if s.Root.Metrics != nil {
+		success := "false"
+		if err == nil {
+			success = "true"
+		}
+		s.Root.Metrics.ObserveStoreMethodDuration("PostEmbeddingStore.Save", success, elapsed)
+	}
+	return err
+}
+
 func (s *TimerLayerPostPersistentNotificationStore) Delete(postIds []string) error {
 	start := time.Now()
 
@@ -6761,6 +7337,22 @@ func (s *TimerLayerPreferenceStore) GetCategoryAndName(category string, nane str
 	return result, err
 }
 
+func (s *TimerLayerPreferenceStore) GetCategoryAndNameForUsers(userIDs []string, category string, name string) (model.Preferences, error) {
+	start := time.Now()
+
+	result, err := s.PreferenceStore.GetCategoryAndNameForUsers(userIDs, category, name)
+
+	elapsed := float64(time.Since(start)) / float64(time.Second)
+	if s.Root.Metrics != nil {
+		success := "false"
+		if err == nil {
+			success = "true"
+		}
+		s.Root.Metrics.ObserveStoreMethodDuration("PreferenceStore.GetCategoryAndNameForUsers", success, elapsed)
+	}
+	return result, err
+}
+
 func (s *TimerLayerPreferenceStore) PermanentDeleteByUser(userID string) error {
 	start := time.Now()
 
@@ -7513,10 +8105,234 @@ func (s *TimerLayerRoleStore) AllChannelSchemeRoles() ([]*model.Role, error) {
 	return result, err
 }
 
-func (s *TimerLayerRoleStore) ChannelHigherScopedPermissions(roleNames []string) (map[string]*model.RolePermissions, error) {
+func (s *TimerLayerRoleStore) ChannelHigherScopedPermissions(roleNames []string) (map[string]*model.RolePermissions, error) {
+	start := time.Now()
+
+	result, err := s.RoleStore.ChannelHigherScopedPermissions(roleNames)
+
+	elapsed := float64(time.Since(start)) / float64(time.Second)
+	if s.Root.Metrics != nil {
+		success := "false"
+		if err == nil {
+			success = "true"
+		}
+		s.Root.Metrics.ObserveStoreMethodDuration("RoleStore.ChannelHigherScopedPermissions", success, elapsed)
+	}
+	return result, err
+}
+
+func (s *TimerLayerRoleStore) ChannelRolesUnderTeamRole(roleName string) ([]*model.Role, error) {
+	start := time.Now()
+
+	result, err := s.RoleStore.ChannelRolesUnderTeamRole(roleName)
+
+	elapsed := float64(time.Since(start)) / float64(time.Second)
+	if s.Root.Metrics != nil {
+		success := "false"
+		if err == nil {
+			success = "true"
+		}
+		s.Root.Metrics.ObserveStoreMethodDuration("RoleStore.ChannelRolesUnderTeamRole", success, elapsed)
+	}
+	return result, err
+}
+
+func (s *TimerLayerRoleStore) Delete(roleID string) (*model.Role, error) {
+	start := time.Now()
+
+	result, err := s.RoleStore.Delete(roleID)
+
+	elapsed := float64(time.Since(start)) / float64(time.Second)
+	if s.Root.Metrics != nil {
+		success := "false"
+		if err == nil {
+			success = "true"
+		}
+		s.Root.Metrics.ObserveStoreMethodDuration("RoleStore.Delete", success, elapsed)
+	}
+	return result, err
+}
+
+func (s *TimerLayerRoleStore) Get(roleID string) (*model.Role, error) {
+	start := time.Now()
+
+	result, err := s.RoleStore.Get(roleID)
+
+	elapsed := float64(time.Since(start)) / float64(time.Second)
+	if s.Root.Metrics != nil {
+		success := "false"
+		if err == nil {
+			success = "true"
+		}
+		s.Root.Metrics.ObserveStoreMethodDuration("RoleStore.Get", success, elapsed)
+	}
+	return result, err
+}
+
+func (s *TimerLayerRoleStore) GetAll() ([]*model.Role, error) {
+	start := time.Now()
+
+	result, err := s.RoleStore.GetAll()
+
+	elapsed := float64(time.Since(start)) / float64(time.Second)
+	if s.Root.Metrics != nil {
+		success := "false"
+		if err == nil {
+			success = "true"
+		}
+		s.Root.Metrics.ObserveStoreMethodDuration("RoleStore.GetAll", success, elapsed)
+	}
+	return result, err
+}
+
+func (s *TimerLayerRoleStore) GetByName(ctx context.Context, name string) (*model.Role, error) {
+	start := time.Now()
+
+	result, err := s.RoleStore.GetByName(ctx, name)
+
+	elapsed := float64(time.Since(start)) / float64(time.Second)
+	if s.Root.Metrics != nil {
+		success := "false"
+		if err == nil {
+			success = "true"
+		}
+		s.Root.Metrics.ObserveStoreMethodDuration("RoleStore.GetByName", success, elapsed)
+	}
+	return result, err
+}
+
+func (s *TimerLayerRoleStore) GetByNames(names []string) ([]*model.Role, error) {
+	start := time.Now()
+
+	result, err := s.RoleStore.GetByNames(names)
+
+	elapsed := float64(time.Since(start)) / float64(time.Second)
+	if s.Root.Metrics != nil {
+		success := "false"
+		if err == nil {
+			success = "true"
+		}
+		s.Root.Metrics.ObserveStoreMethodDuration("RoleStore.GetByNames", success, elapsed)
+	}
+	return result, err
+}
+
+func (s *TimerLayerRoleStore) PermanentDeleteAll() error {
+	start := time.Now()
+
+	err := s.RoleStore.PermanentDeleteAll()
+
+	elapsed := float64(time.Since(start)) / float64(time.Second)
+	if s.Root.Metrics != nil {
+		success := "false"
+		if err == nil {
+			success = "true"
+		}
+		s.Root.Metrics.ObserveStoreMethodDuration("RoleStore.PermanentDeleteAll", success, elapsed)
+	}
+	return err
+}
+
+func (s *TimerLayerRoleStore) Save(role *model.Role) (*model.Role, error) {
+	start := time.Now()
+
+	result, err := s.RoleStore.Save(role)
+
+	elapsed := float64(time.Since(start)) / float64(time.Second)
+	if s.Root.Metrics != nil {
+		success := "false"
+		if err == nil {
+			success = "true"
+		}
+		s.Root.Metrics.ObserveStoreMethodDuration("RoleStore.Save", success, elapsed)
+	}
+	return result, err
+}
+
+func (s *TimerLayerSavedSearchStore) CountForUser(userId string) (int64, error) {
+	start := time.Now()
+
+	result, err := s.SavedSearchStore.CountForUser(userId)
+
+	elapsed := float64(time.Since(start)) / float64(time.Second)
+	if s.Root.Metrics != nil {
+		success := "false"
+		if err == nil {
+			success = "true"
+		}
+		s.Root.Metrics.ObserveStoreMethodDuration("SavedSearchStore.CountForUser", success, elapsed)
+	}
+	return result, err
+}
+
+func (s *TimerLayerSavedSearchStore) Get(savedSearchId string) (*model.SavedSearch, error) {
+	start := time.Now()
+
+	result, err := s.SavedSearchStore.Get(savedSearchId)
+
+	elapsed := float64(time.Since(start)) / float64(time.Second)
+	if s.Root.Metrics != nil {
+		success := "false"
+		if err == nil {
+			success = "true"
+		}
+		s.Root.Metrics.ObserveStoreMethodDuration("SavedSearchStore.Get", success, elapsed)
+	}
+	return result, err
+}
+
+func (s *TimerLayerSavedSearchStore) GetForUser(userId string, teamId string) ([]*model.SavedSearch, error) {
+	start := time.Now()
+
+	result, err := s.SavedSearchStore.GetForUser(userId, teamId)
+
+	elapsed := float64(time.Since(start)) / float64(time.Second)
+	if s.Root.Metrics != nil {
+		success := "false"
+		if err == nil {
+			success = "true"
+		}
+		s.Root.Metrics.ObserveStoreMethodDuration("SavedSearchStore.GetForUser", success, elapsed)
+	}
+	return result, err
+}
+
+func (s *TimerLayerSavedSearchStore) GetSubscribedForChannel(channelId string, teamId string) ([]*model.SavedSearch, error) {
+	start := time.Now()
+
+	result, err := s.SavedSearchStore.GetSubscribedForChannel(channelId, teamId)
+
+	elapsed := float64(time.Since(start)) / float64(time.Second)
+	if s.Root.Metrics != nil {
+		success := "false"
+		if err == nil {
+			success = "true"
+		}
+		s.Root.Metrics.ObserveStoreMethodDuration("SavedSearchStore.GetSubscribedForChannel", success, elapsed)
+	}
+	return result, err
+}
+
+func (s *TimerLayerSavedSearchStore) PermanentDelete(savedSearchId string) error {
+	start := time.Now()
+
+	err := s.SavedSearchStore.PermanentDelete(savedSearchId)
+
+	elapsed := float64(time.Since(start)) / float64(time.Second)
+	if s.Root.Metrics != nil {
+		success := "false"
+		if err == nil {
+			success = "true"
+		}
+		s.Root.Metrics.ObserveStoreMethodDuration("SavedSearchStore.PermanentDelete", success, elapsed)
+	}
+	return err
+}
+
+func (s *TimerLayerSavedSearchStore) PermanentDeleteByUser(userId string) error {
 	start := time.Now()
 
-	result, err := s.RoleStore.ChannelHigherScopedPermissions(roleNames)
+	err := s.SavedSearchStore.PermanentDeleteByUser(userId)
 
 	elapsed := float64(time.Since(start)) / float64(time.Second)
 	if s.Root.Metrics != nil {
@@ -7524,15 +8340,15 @@ func (s *TimerLayerRoleStore) ChannelHigherScopedPermissions(roleNames []string)
 		if err == nil {
 			success = "true"
 		}
-		s.Root.Metrics.ObserveStoreMethodDuration("RoleStore.ChannelHigherScopedPermissions", success, elapsed)
+		s.Root.Metrics.ObserveStoreMethodDuration("SavedSearchStore.PermanentDeleteByUser", success, elapsed)
 	}
-	return result, err
+	return err
 }
 
-func (s *TimerLayerRoleStore) ChannelRolesUnderTeamRole(roleName string) ([]*model.Role, error) {
+func (s *TimerLayerSavedSearchStore) Save(savedSearch *model.SavedSearch) (*model.SavedSearch, error) {
 	start := time.Now()
 
-	result, err := s.RoleStore.ChannelRolesUnderTeamRole(roleName)
+	result, err := s.SavedSearchStore.Save(savedSearch)
 
 	elapsed := float64(time.Since(start)) / float64(time.Second)
 	if s.Root.Metrics != nil {
@@ -7540,15 +8356,15 @@ func (s *TimerLayerRoleStore) ChannelRolesUnderTeamRole(roleName string) ([]*mod
 		if err == nil {
 			success = "true"
 		}
-		s.Root.Metrics.ObserveStoreMethodDuration("RoleStore.ChannelRolesUnderTeamRole", success, elapsed)
+		s.Root.Metrics.ObserveStoreMethodDuration("SavedSearchStore.Save", success, elapsed)
 	}
 	return result, err
 }
 
-func (s *TimerLayerRoleStore) Delete(roleID string) (*model.Role, error) {
+func (s *TimerLayerSavedSearchStore) Update(savedSearch *model.SavedSearch) (*model.SavedSearch, error) {
 	start := time.Now()
 
-	result, err := s.RoleStore.Delete(roleID)
+	result, err := s.SavedSearchStore.Update(savedSearch)
 
 	elapsed := float64(time.Since(start)) / float64(time.Second)
 	if s.Root.Metrics != nil {
@@ -7556,15 +8372,15 @@ func (s *TimerLayerRoleStore) Delete(roleID string) (*model.Role, error) {
 		if err == nil {
 			success = "true"
 		}
-		s.Root.Metrics.ObserveStoreMethodDuration("RoleStore.Delete", success, elapsed)
+		s.Root.Metrics.ObserveStoreMethodDuration("SavedSearchStore.Update", success, elapsed)
 	}
 	return result, err
 }
 
-func (s *TimerLayerRoleStore) Get(roleID string) (*model.Role, error) {
+func (s *TimerLayerScheduledPostStore) Get(scheduledPostId string) (*model.ScheduledPost, error) {
 	start := time.Now()
 
-	result, err := s.RoleStore.Get(roleID)
+	result, err := s.ScheduledPostStore.Get(scheduledPostId)
 
 	elapsed := float64(time.Since(start)) / float64(time.Second)
 	if s.Root.Metrics != nil {
@@ -7572,15 +8388,15 @@ func (s *TimerLayerRoleStore) Get(roleID string) (*model.Role, error) {
 		if err == nil {
 			success = "true"
 		}
-		s.Root.Metrics.ObserveStoreMethodDuration("RoleStore.Get", success, elapsed)
+		s.Root.Metrics.ObserveStoreMethodDuration("ScheduledPostStore.Get", success, elapsed)
 	}
 	return result, err
 }
 
-func (s *TimerLayerRoleStore) GetAll() ([]*model.Role, error) {
+func (s *TimerLayerScheduledPostStore) GetPendingScheduledPosts(beforeTime int64, afterTime int64, lastScheduledPostId string, perPage uint64) ([]*model.ScheduledPost, error) {
 	start := time.Now()
 
-	result, err := s.RoleStore.GetAll()
+	result, err := s.ScheduledPostStore.GetPendingScheduledPosts(beforeTime, afterTime, lastScheduledPostId, perPage)
 
 	elapsed := float64(time.Since(start)) / float64(time.Second)
 	if s.Root.Metrics != nil {
@@ -7588,15 +8404,15 @@ func (s *TimerLayerRoleStore) GetAll() ([]*model.Role, error) {
 		if err == nil {
 			success = "true"
 		}
-		s.Root.Metrics.ObserveStoreMethodDuration("RoleStore.GetAll", success, elapsed)
+		s.Root.Metrics.ObserveStoreMethodDuration("ScheduledPostStore.GetPendingScheduledPosts", success, elapsed)
 	}
 	return result, err
 }
 
-func (s *TimerLayerRoleStore) GetByName(ctx context.Context, name string) (*model.Role, error) {
+func (s *TimerLayerScheduledPostStore) GetScheduledPostsForUser(userId string, teamId string) ([]*model.ScheduledPost, error) {
 	start := time.Now()
 
-	result, err := s.RoleStore.GetByName(ctx, name)
+	result, err := s.ScheduledPostStore.GetScheduledPostsForUser(userId, teamId)
 
 	elapsed := float64(time.Since(start)) / float64(time.Second)
 	if s.Root.Metrics != nil {
@@ -7604,15 +8420,15 @@ func (s *TimerLayerRoleStore) GetByName(ctx context.Context, name string) (*mode
 		if err == nil {
 			success = "true"
 		}
-		s.Root.Metrics.ObserveStoreMethodDuration("RoleStore.GetByName", success, elapsed)
+		s.Root.Metrics.ObserveStoreMethodDuration("ScheduledPostStore.GetScheduledPostsForUser", success, elapsed)
 	}
 	return result, err
 }
 
-func (s *TimerLayerRoleStore) GetByNames(names []string) ([]*model.Role, error) {
+func (s *TimerLayerScheduledPostStore) PermanentlyDelete(scheduledPostIds []string) error {
 	start := time.Now()
 
-	result, err := s.RoleStore.GetByNames(names)
+	err := s.ScheduledPostStore.PermanentlyDelete(scheduledPostIds)
 
 	elapsed := float64(time.Since(start)) / float64(time.Second)
 	if s.Root.Metrics != nil {
@@ -7620,15 +8436,15 @@ func (s *TimerLayerRoleStore) GetByNames(names []string) ([]*model.Role, error)
 		if err == nil {
 			success = "true"
 		}
-		s.Root.Metrics.ObserveStoreMethodDuration("RoleStore.GetByNames", success, elapsed)
+		s.Root.Metrics.ObserveStoreMethodDuration("ScheduledPostStore.PermanentlyDelete", success, elapsed)
 	}
-	return result, err
+	return err
 }
 
-func (s *TimerLayerRoleStore) PermanentDeleteAll() error {
+func (s *TimerLayerScheduledPostStore) Save(scheduledPost *model.ScheduledPost) (*model.ScheduledPost, error) {
 	start := time.Now()
 
-	err := s.RoleStore.PermanentDeleteAll()
+	result, err := s.ScheduledPostStore.Save(scheduledPost)
 
 	elapsed := float64(time.Since(start)) / float64(time.Second)
 	if s.Root.Metrics != nil {
@@ -7636,15 +8452,15 @@ func (s *TimerLayerRoleStore) PermanentDeleteAll() error {
 		if err == nil {
 			success = "true"
 		}
-		s.Root.Metrics.ObserveStoreMethodDuration("RoleStore.PermanentDeleteAll", success, elapsed)
+		s.Root.Metrics.ObserveStoreMethodDuration("ScheduledPostStore.Save", success, elapsed)
 	}
-	return err
+	return result, err
 }
 
-func (s *TimerLayerRoleStore) Save(role *model.Role) (*model.Role, error) {
+func (s *TimerLayerScheduledPostStore) Update(scheduledPost *model.ScheduledPost) error {
 	start := time.Now()
 
-	result, err := s.RoleStore.Save(role)
+	err := s.ScheduledPostStore.Update(scheduledPost)
 
 	elapsed := float64(time.Since(start)) / float64(time.Second)
 	if s.Root.Metrics != nil {
@@ -7652,9 +8468,9 @@ func (s *TimerLayerRoleStore) Save(role *model.Role) (*model.Role, error) {
 		if err == nil {
 			success = "true"
 		}
-		s.Root.Metrics.ObserveStoreMethodDuration("RoleStore.Save", success, elapsed)
+		s.Root.Metrics.ObserveStoreMethodDuration("ScheduledPostStore.Update", success, elapsed)
 	}
-	return result, err
+	return err
 }
 
 func (s *TimerLayerSchemeStore) CountByScope(scope string) (int64, error) {
@@ -10134,6 +10950,22 @@ func (s *TimerLayerUploadSessionStore) GetForUser(userID string) ([]*model.Uploa
 	return result, err
 }
 
+func (s *TimerLayerUploadSessionStore) GetIncomplete() ([]*model.UploadSession, error) {
+	start := time.Now()
+
+	result, err := s.UploadSessionStore.GetIncomplete()
+
+	elapsed := float64(time.Since(start)) / float64(time.Second)
+	if s.Root.Metrics != nil {
+		success := "false"
+		if err == nil {
+			success = "true"
+		}
+		s.Root.Metrics.ObserveStoreMethodDuration("UploadSessionStore.GetIncomplete", success, elapsed)
+	}
+	return result, err
+}
+
 func (s *TimerLayerUploadSessionStore) Save(session *model.UploadSession) (*model.UploadSession, error) {
 	start := time.Now()
 
@@ -10325,6 +11157,22 @@ func (s *TimerLayerUserStore) Count(options model.UserCountOptions) (int64, erro
 	return result, err
 }
 
+func (s *TimerLayerUserStore) CountNonAdminsUsingAuthService(authService string) (int64, error) {
+	start := time.Now()
+
+	result, err := s.UserStore.CountNonAdminsUsingAuthService(authService)
+
+	elapsed := float64(time.Since(start)) / float64(time.Second)
+	if s.Root.Metrics != nil {
+		success := "false"
+		if err == nil {
+			success = "true"
+		}
+		s.Root.Metrics.ObserveStoreMethodDuration("UserStore.CountNonAdminsUsingAuthService", success, elapsed)
+	}
+	return result, err
+}
+
 func (s *TimerLayerUserStore) DeactivateGuests() ([]string, error) {
 	start := time.Now()
 
@@ -10677,6 +11525,22 @@ func (s *TimerLayerUserStore) GetNewUsersForTeam(teamID string, offset int, limi
 	return result, err
 }
 
+func (s *TimerLayerUserStore) GetNonAdminsUsingAuthService(authService string, offset int, limit int) ([]*model.User, error) {
+	start := time.Now()
+
+	result, err := s.UserStore.GetNonAdminsUsingAuthService(authService, offset, limit)
+
+	elapsed := float64(time.Since(start)) / float64(time.Second)
+	if s.Root.Metrics != nil {
+		success := "false"
+		if err == nil {
+			success = "true"
+		}
+		s.Root.Metrics.ObserveStoreMethodDuration("UserStore.GetNonAdminsUsingAuthService", success, elapsed)
+	}
+	return result, err
+}
+
 func (s *TimerLayerUserStore) GetProfileByGroupChannelIdsForUser(userID string, channelIds []string) (map[string][]*model.User, error) {
 	start := time.Now()
 
@@ -11666,6 +12530,118 @@ func (s *TimerLayerUserTermsOfServiceStore) Save(userTermsOfService *model.UserT
 	return result, err
 }
 
+func (s *TimerLayerWebAuthnCredentialStore) Delete(id string) error {
+	start := time.Now()
+
+	err := s.WebAuthnCredentialStore.Delete(id)
+
+	elapsed := float64(time.Since(start)) / float64(time.Second)
+	if s.Root.Metrics != nil {
+		success := "false"
+		if err == nil {
+			success = "true"
+		}
+		s.Root.Metrics.ObserveStoreMethodDuration("WebAuthnCredentialStore.Delete", success, elapsed)
+	}
+	return err
+}
+
+func (s *TimerLayerWebAuthnCredentialStore) Get(id string) (*model.WebAuthnCredential, error) {
+	start := time.Now()
+
+	result, err := s.WebAuthnCredentialStore.Get(id)
+
+	elapsed := float64(time.Since(start)) / float64(time.Second)
+	if s.Root.Metrics != nil {
+		success := "false"
+		if err == nil {
+			success = "true"
+		}
+		s.Root.Metrics.ObserveStoreMethodDuration("WebAuthnCredentialStore.Get", success, elapsed)
+	}
+	return result, err
+}
+
+func (s *TimerLayerWebAuthnCredentialStore) GetByCredentialId(credentialID string) (*model.WebAuthnCredential, error) {
+	start := time.Now()
+
+	result, err := s.WebAuthnCredentialStore.GetByCredentialId(credentialID)
+
+	elapsed := float64(time.Since(start)) / float64(time.Second)
+	if s.Root.Metrics != nil {
+		success := "false"
+		if err == nil {
+			success = "true"
+		}
+		s.Root.Metrics.ObserveStoreMethodDuration("WebAuthnCredentialStore.GetByCredentialId", success, elapsed)
+	}
+	return result, err
+}
+
+func (s *TimerLayerWebAuthnCredentialStore) GetForUser(userID string) ([]*model.WebAuthnCredential, error) {
+	start := time.Now()
+
+	result, err := s.WebAuthnCredentialStore.GetForUser(userID)
+
+	elapsed := float64(time.Since(start)) / float64(time.Second)
+	if s.Root.Metrics != nil {
+		success := "false"
+		if err == nil {
+			success = "true"
+		}
+		s.Root.Metrics.ObserveStoreMethodDuration("WebAuthnCredentialStore.GetForUser", success, elapsed)
+	}
+	return result, err
+}
+
+func (s *TimerLayerWebAuthnCredentialStore) PermanentDeleteByUser(userID string) error {
+	start := time.Now()
+
+	err := s.WebAuthnCredentialStore.PermanentDeleteByUser(userID)
+
+	elapsed := float64(time.Since(start)) / float64(time.Second)
+	if s.Root.Metrics != nil {
+		success := "false"
+		if err == nil {
+			success = "true"
+		}
+		s.Root.Metrics.ObserveStoreMethodDuration("WebAuthnCredentialStore.PermanentDeleteByUser", success, elapsed)
+	}
+	return err
+}
+
+func (s *TimerLayerWebAuthnCredentialStore) Save(credential *model.WebAuthnCredential) (*model.WebAuthnCredential, error) {
+	start := time.Now()
+
+	result, err := s.WebAuthnCredentialStore.Save(credential)
+
+	elapsed := float64(time.Since(start)) / float64(time.Second)
+	if s.Root.Metrics != nil {
+		success := "false"
+		if err == nil {
+			success = "true"
+		}
+		s.Root.Metrics.ObserveStoreMethodDuration("WebAuthnCredentialStore.Save", success, elapsed)
+	}
+	return result, err
+}
+
+func (s *TimerLayerWebAuthnCredentialStore) UpdateSignCount(id string, signCount int64, lastUsedAt int64) error {
+	start := time.Now()
+
+	err := s.WebAuthnCredentialStore.UpdateSignCount(id, signCount, lastUsedAt)
+
+	elapsed := float64(time.Since(start)) / float64(time.Second)
+	if s.Root.Metrics != nil {
+		success := "false"
+		if err == nil {
+			success = "true"
+		}
+		s.Root.Metrics.ObserveStoreMethodDuration("WebAuthnCredentialStore.UpdateSignCount", success, elapsed)
+	}
+	return err
+}
+
 func (s *TimerLayerWebhookStore) AnalyticsIncomingCount(teamID string, userID string) (int64, error) {
 	start := time.Now()
 
@@ -11745,6 +12721,22 @@ func (s *TimerLayerWebhookStore) DeleteOutgoing(webhookID string, timestamp int6
 	return err
 }
 
+func (s *TimerLayerWebhookStore) GetDueOutgoingDeliveries(beforeTime int64, afterTime int64, lastDeliveryID string, limit uint64) ([]*model.OutgoingWebhookDelivery, error) {
+	start := time.Now()
+
+	result, err := s.WebhookStore.GetDueOutgoingDeliveries(beforeTime, afterTime, lastDeliveryID, limit)
+
+	elapsed := float64(time.Since(start)) / float64(time.Second)
+	if s.Root.Metrics != nil {
+		success := "false"
+		if err == nil {
+			success = "true"
+		}
+		s.Root.Metrics.ObserveStoreMethodDuration("WebhookStore.GetDueOutgoingDeliveries", success, elapsed)
+	}
+	return result, err
+}
+
 func (s *TimerLayerWebhookStore) GetIncoming(id string, allowFromCache bool) (*model.IncomingWebhook, error) {
 	start := time.Now()
 
@@ -11921,6 +12913,38 @@ func (s *TimerLayerWebhookStore) GetOutgoingByTeamByUser(teamID string, userID s
 	return result, err
 }
 
+func (s *TimerLayerWebhookStore) GetOutgoingDeliveriesByHook(hookID string, status string, offset int, limit int) ([]*model.OutgoingWebhookDelivery, error) {
+	start := time.Now()
+
+	result, err := s.WebhookStore.GetOutgoingDeliveriesByHook(hookID, status, offset, limit)
+
+	elapsed := float64(time.Since(start)) / float64(time.Second)
+	if s.Root.Metrics != nil {
+		success := "false"
+		if err == nil {
+			success = "true"
+		}
+		s.Root.Metrics.ObserveStoreMethodDuration("WebhookStore.GetOutgoingDeliveriesByHook", success, elapsed)
+	}
+	return result, err
+}
+
+func (s *TimerLayerWebhookStore) GetOutgoingDelivery(id string) (*model.OutgoingWebhookDelivery, error) {
+	start := time.Now()
+
+	result, err := s.WebhookStore.GetOutgoingDelivery(id)
+
+	elapsed := float64(time.Since(start)) / float64(time.Second)
+	if s.Root.Metrics != nil {
+		success := "false"
+		if err == nil {
+			success = "true"
+		}
+		s.Root.Metrics.ObserveStoreMethodDuration("WebhookStore.GetOutgoingDelivery", success, elapsed)
+	}
+	return result, err
+}
+
 func (s *TimerLayerWebhookStore) GetOutgoingList(offset int, limit int) ([]*model.OutgoingWebhook, error) {
 	start := time.Now()
 
@@ -12032,6 +13056,38 @@ func (s *TimerLayerWebhookStore) PermanentDeleteOutgoingByUser(userID string) er
 	return err
 }
 
+func (s *TimerLayerWebhookStore) PermanentDeleteOutgoingDeliveriesByHook(hookID string) error {
+	start := time.Now()
+
+	err := s.WebhookStore.PermanentDeleteOutgoingDeliveriesByHook(hookID)
+
+	elapsed := float64(time.Since(start)) / float64(time.Second)
+	if s.Root.Metrics != nil {
+		success := "false"
+		if err == nil {
+			success = "true"
+		}
+		s.Root.Metrics.ObserveStoreMethodDuration("WebhookStore.PermanentDeleteOutgoingDeliveriesByHook", success, elapsed)
+	}
+	return err
+}
+
+func (s *TimerLayerWebhookStore) PermanentDeleteOutgoingDelivery(id string) error {
+	start := time.Now()
+
+	err := s.WebhookStore.PermanentDeleteOutgoingDelivery(id)
+
+	elapsed := float64(time.Since(start)) / float64(time.Second)
+	if s.Root.Metrics != nil {
+		success := "false"
+		if err == nil {
+			success = "true"
+		}
+		s.Root.Metrics.ObserveStoreMethodDuration("WebhookStore.PermanentDeleteOutgoingDelivery", success, elapsed)
+	}
+	return err
+}
+
 func (s *TimerLayerWebhookStore) SaveIncoming(webhook *model.IncomingWebhook) (*model.IncomingWebhook, error) {
 	start := time.Now()
 
@@ -12064,6 +13120,22 @@ func (s *TimerLayerWebhookStore) SaveOutgoing(webhook *model.OutgoingWebhook) (*
 	return result, err
 }
 
+func (s *TimerLayerWebhookStore) SaveOutgoingDelivery(delivery *model.OutgoingWebhookDelivery) (*model.OutgoingWebhookDelivery, error) {
+	start := time.Now()
+
+	result, err := s.WebhookStore.SaveOutgoingDelivery(delivery)
+
+	elapsed := float64(time.Since(start)) / float64(time.Second)
+	if s.Root.Metrics != nil {
+		success := "false"
+		if err == nil {
+			success = "true"
+		}
+		s.Root.Metrics.ObserveStoreMethodDuration("WebhookStore.SaveOutgoingDelivery", success, elapsed)
+	}
+	return result, err
+}
+
 func (s *TimerLayerWebhookStore) UpdateIncoming(webhook *model.IncomingWebhook) (*model.IncomingWebhook, error) {
 	start := time.Now()
 
@@ -12096,6 +13168,22 @@ func (s *TimerLayerWebhookStore) UpdateOutgoing(hook *model.OutgoingWebhook) (*m
 	return result, err
 }
 
+func (s *TimerLayerWebhookStore) UpdateOutgoingDelivery(delivery *model.OutgoingWebhookDelivery) (*model.OutgoingWebhookDelivery, error) {
+	start := time.Now()
+
+	result, err := s.WebhookStore.UpdateOutgoingDelivery(delivery)
+
+	elapsed := float64(time.Since(start)) / float64(time.Second)
+	if s.Root.Metrics != nil {
+		success := "false"
+		if err == nil {
+			success = "true"
+		}
+		s.Root.Metrics.ObserveStoreMethodDuration("WebhookStore.UpdateOutgoingDelivery", success, elapsed)
+	}
+	return result, err
+}
+
 func (s *TimerLayer) Close() {
 	s.Store.Close()
 }
@@ -12139,6 +13227,7 @@ func New(childStore store.Store, metrics einterfaces.MetricsInterface) *TimerLay
 	}
 
 	newStore.AuditStore = &TimerLayerAuditStore{AuditStore: childStore.Audit(), Root: &newStore}
+	newStore.AuditRecordStore = &TimerLayerAuditRecordStore{AuditRecordStore: childStore.AuditRecord(), Root: &newStore}
 	newStore.BotStore = &TimerLayerBotStore{BotStore: childStore.Bot(), Root: &newStore}
 	newStore.ChannelStore = &TimerLayerChannelStore{ChannelStore: childStore.Channel(), Root: &newStore}
 	newStore.ChannelBookmarkStore = &TimerLayerChannelBookmarkStore{ChannelBookmarkStore: childStore.ChannelBookmark(), Root: &newStore}
@@ -12150,6 +13239,8 @@ func New(childStore store.Store, metrics einterfaces.MetricsInterface) *TimerLay
 	newStore.DesktopTokensStore = &TimerLayerDesktopTokensStore{DesktopTokensStore: childStore.DesktopTokens(), Root: &newStore}
 	newStore.DraftStore = &TimerLayerDraftStore{DraftStore: childStore.Draft(), Root: &newStore}
 	newStore.EmojiStore = &TimerLayerEmojiStore{EmojiStore: childStore.Emoji(), Root: &newStore}
+	newStore.ExpiringPostStore = &TimerLayerExpiringPostStore{ExpiringPostStore: childStore.ExpiringPost(), Root: &newStore}
+	newStore.FileBlobStore = &TimerLayerFileBlobStore{FileBlobStore: childStore.FileBlob(), Root: &newStore}
 	newStore.FileInfoStore = &TimerLayerFileInfoStore{FileInfoStore: childStore.FileInfo(), Root: &newStore}
 	newStore.GroupStore = &TimerLayerGroupStore{GroupStore: childStore.Group(), Root: &newStore}
 	newStore.JobStore = &TimerLayerJobStore{JobStore: childStore.Job(), Root: &newStore}
@@ -12159,8 +13250,10 @@ func New(childStore store.Store, metrics einterfaces.MetricsInterface) *TimerLay
 	newStore.OAuthStore = &TimerLayerOAuthStore{OAuthStore: childStore.OAuth(), Root: &newStore}
 	newStore.OutgoingOAuthConnectionStore = &TimerLayerOutgoingOAuthConnectionStore{OutgoingOAuthConnectionStore: childStore.OutgoingOAuthConnection(), Root: &newStore}
 	newStore.PluginStore = &TimerLayerPluginStore{PluginStore: childStore.Plugin(), Root: &newStore}
+	newStore.PollVoteStore = &TimerLayerPollVoteStore{PollVoteStore: childStore.PollVote(), Root: &newStore}
 	newStore.PostStore = &TimerLayerPostStore{PostStore: childStore.Post(), Root: &newStore}
 	newStore.PostAcknowledgementStore = &TimerLayerPostAcknowledgementStore{PostAcknowledgementStore: childStore.PostAcknowledgement(), Root: &newStore}
+	newStore.PostEmbeddingStore = &TimerLayerPostEmbeddingStore{PostEmbeddingStore: childStore.PostEmbedding(), Root: &newStore}
 	newStore.PostPersistentNotificationStore = &TimerLayerPostPersistentNotificationStore{PostPersistentNotificationStore: childStore.PostPersistentNotification(), Root: &newStore}
 	newStore.PostPriorityStore = &TimerLayerPostPriorityStore{PostPriorityStore: childStore.PostPriority(), Root: &newStore}
 	newStore.PreferenceStore = &TimerLayerPreferenceStore{PreferenceStore: childStore.Preference(), Root: &newStore}
@@ -12169,6 +13262,8 @@ func New(childStore store.Store, metrics einterfaces.MetricsInterface) *TimerLay
 	newStore.RemoteClusterStore = &TimerLayerRemoteClusterStore{RemoteClusterStore: childStore.RemoteCluster(), Root: &newStore}
 	newStore.RetentionPolicyStore = &TimerLayerRetentionPolicyStore{RetentionPolicyStore: childStore.RetentionPolicy(), Root: &newStore}
 	newStore.RoleStore = &TimerLayerRoleStore{RoleStore: childStore.Role(), Root: &newStore}
+	newStore.SavedSearchStore = &TimerLayerSavedSearchStore{SavedSearchStore: childStore.SavedSearch(), Root: &newStore}
+	newStore.ScheduledPostStore = &TimerLayerScheduledPostStore{ScheduledPostStore: childStore.ScheduledPost(), Root: &newStore}
 	newStore.SchemeStore = &TimerLayerSchemeStore{SchemeStore: childStore.Scheme(), Root: &newStore}
 	newStore.SessionStore = &TimerLayerSessionStore{SessionStore: childStore.Session(), Root: &newStore}
 	newStore.SharedChannelStore = &TimerLayerSharedChannelStore{SharedChannelStore: childStore.SharedChannel(), Root: &newStore}
@@ -12182,6 +13277,7 @@ func New(childStore store.Store, metrics einterfaces.MetricsInterface) *TimerLay
 	newStore.UserStore = &TimerLayerUserStore{UserStore: childStore.User(), Root: &newStore}
 	newStore.UserAccessTokenStore = &TimerLayerUserAccessTokenStore{UserAccessTokenStore: childStore.UserAccessToken(), Root: &newStore}
 	newStore.UserTermsOfServiceStore = &TimerLayerUserTermsOfServiceStore{UserTermsOfServiceStore: childStore.UserTermsOfService(), Root: &newStore}
+	newStore.WebAuthnCredentialStore = &TimerLayerWebAuthnCredentialStore{WebAuthnCredentialStore: childStore.WebAuthnCredential(), Root: &newStore}
 	newStore.WebhookStore = &TimerLayerWebhookStore{WebhookStore: childStore.Webhook(), Root: &newStore}
 	return &newStore
 }
@@ -11,6 +11,8 @@ var shortBackoffTimeouts = []time.Duration{50 * time.Millisecond, 100 * time.Mil
 
 var longBackoffTimeouts = []time.Duration{500 * time.Millisecond, 1 * time.Second, 2 * time.Second, 5 * time.Second, 8 * time.Second, 10 * time.Second}
 
+var deliveryBackoffTimeouts = []time.Duration{1 * time.Minute, 5 * time.Minute, 15 * time.Minute, 1 * time.Hour, 4 * time.Hour}
+
 // ProgressiveRetry executes a BackoffOperation and waits an increasing time before retrying the operation.
 func ProgressiveRetry(operation func() error) error {
 	return CustomProgressiveRetry(operation, shortBackoffTimeouts)
@@ -34,3 +36,18 @@ func CustomProgressiveRetry(operation func() error, backoffTimeouts []time.Durat
 
 	return err
 }
+
+// DeliveryBackoff returns how long to wait before retrying a persisted delivery, such as an
+// outgoing webhook request, that has failed the given number of times. It returns false once
+// the delivery has used up its retries.
+func DeliveryBackoff(failures int) (time.Duration, bool) {
+	return CustomBackoff(failures, deliveryBackoffTimeouts)
+}
+
+func CustomBackoff(failures int, backoffTimeouts []time.Duration) (time.Duration, bool) {
+	if failures < 1 || failures > len(backoffTimeouts) {
+		return 0, false
+	}
+
+	return backoffTimeouts[failures-1], true
+}
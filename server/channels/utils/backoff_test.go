@@ -5,6 +5,7 @@ package utils
 
 import (
 	"testing"
+	"time"
 
 	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
@@ -63,3 +64,21 @@ func TestProgressiveRetry(t *testing.T) {
 		})
 	}
 }
+
+func TestCustomBackoff(t *testing.T) {
+	backoffTimeouts := []time.Duration{time.Second, time.Minute}
+
+	_, ok := CustomBackoff(0, backoffTimeouts)
+	assert.False(t, ok)
+
+	timeout, ok := CustomBackoff(1, backoffTimeouts)
+	assert.True(t, ok)
+	assert.Equal(t, time.Second, timeout)
+
+	timeout, ok = CustomBackoff(2, backoffTimeouts)
+	assert.True(t, ok)
+	assert.Equal(t, time.Minute, timeout)
+
+	_, ok = CustomBackoff(3, backoffTimeouts)
+	assert.False(t, ok)
+}
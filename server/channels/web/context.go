@@ -763,6 +763,50 @@ func (c *Context) RequireBotUserId() *Context {
 	return c
 }
 
+func (c *Context) RequireScheduledPostId() *Context {
+	if c.Err != nil {
+		return c
+	}
+
+	if !model.IsValidId(c.Params.ScheduledPostId) {
+		c.SetInvalidURLParam("scheduled_post_id")
+	}
+	return c
+}
+
+func (c *Context) RequireDeliveryId() *Context {
+	if c.Err != nil {
+		return c
+	}
+
+	if !model.IsValidId(c.Params.DeliveryId) {
+		c.SetInvalidURLParam("delivery_id")
+	}
+	return c
+}
+
+func (c *Context) RequireWebAuthnCredentialId() *Context {
+	if c.Err != nil {
+		return c
+	}
+
+	if !model.IsValidId(c.Params.WebAuthnCredentialId) {
+		c.SetInvalidURLParam("webauthn_credential_id")
+	}
+	return c
+}
+
+func (c *Context) RequireSavedSearchId() *Context {
+	if c.Err != nil {
+		return c
+	}
+
+	if !model.IsValidId(c.Params.SavedSearchId) {
+		c.SetInvalidURLParam("saved_search_id")
+	}
+	return c
+}
+
 func (c *Context) RequireInvoiceId() *Context {
 	if c.Err != nil {
 		return c
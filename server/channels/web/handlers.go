@@ -308,9 +308,9 @@ func (h Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 			c.AppContext = c.AppContext.WithSession(session)
 		}
 
-		// Rate limit by UserID
+		// Rate limit by UserID with the quota of the route, and by token for tokens with a quota of their own
 		if c.App.Srv().RateLimiter != nil {
-			rateLimitExceeded = c.App.Srv().RateLimiter.UserIdRateLimit(c.AppContext.Session().UserId, w)
+			rateLimitExceeded = c.App.Srv().RateLimiter.SessionRateLimit(c.AppContext.Session(), r.URL.Path, w)
 			if rateLimitExceeded {
 				return
 			}
@@ -92,6 +92,10 @@ type Params struct {
 	FilterHasMember           string
 	IncludeChannelMemberCount string
 	OutgoingOAuthConnectionID string
+	ScheduledPostId           string
+	SavedSearchId             string
+	DeliveryId                string
+	WebAuthnCredentialId      string
 	ExcludeOffline            bool
 	InChannel                 string
 	NotInChannel              string
@@ -163,6 +167,10 @@ func ParamsFromRequest(r *http.Request) *Params {
 	params.RemoteId = props["remote_id"]
 	params.InvoiceId = props["invoice_id"]
 	params.OutgoingOAuthConnectionID = props["outgoing_oauth_connection_id"]
+	params.ScheduledPostId = props["scheduled_post_id"]
+	params.SavedSearchId = props["saved_search_id"]
+	params.DeliveryId = props["delivery_id"]
+	params.WebAuthnCredentialId = props["webauthn_credential_id"]
 	params.ExcludeOffline, _ = strconv.ParseBool(query.Get("exclude_offline"))
 	params.InChannel = query.Get("in_channel")
 	params.NotInChannel = query.Get("not_in_channel")
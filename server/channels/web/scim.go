@@ -0,0 +1,546 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package web
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gorilla/mux"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+	"github.com/mattermost/mattermost/server/v8/channels/audit"
+)
+
+const (
+	scimContentType = "application/scim+json"
+	scimRoute       = "/scim/v2"
+)
+
+func (w *Web) InitScim() {
+	scim := w.MainRouter.PathPrefix(scimRoute).Subrouter()
+
+	scim.Handle("/ServiceProviderConfig", w.APIHandlerTrustRequester(getScimServiceProviderConfig)).Methods(http.MethodGet)
+
+	scim.Handle("/Users", w.APIHandlerTrustRequester(getScimUsers)).Methods(http.MethodGet)
+	scim.Handle("/Users", w.APIHandlerTrustRequester(createScimUser)).Methods(http.MethodPost)
+	scim.Handle("/Users/{id:[A-Za-z0-9]+}", w.APIHandlerTrustRequester(getScimUser)).Methods(http.MethodGet)
+	scim.Handle("/Users/{id:[A-Za-z0-9]+}", w.APIHandlerTrustRequester(updateScimUser)).Methods(http.MethodPut)
+	scim.Handle("/Users/{id:[A-Za-z0-9]+}", w.APIHandlerTrustRequester(patchScimUser)).Methods(http.MethodPatch)
+	scim.Handle("/Users/{id:[A-Za-z0-9]+}", w.APIHandlerTrustRequester(deleteScimUser)).Methods(http.MethodDelete)
+
+	scim.Handle("/Groups", w.APIHandlerTrustRequester(getScimGroups)).Methods(http.MethodGet)
+	scim.Handle("/Groups", w.APIHandlerTrustRequester(createScimGroup)).Methods(http.MethodPost)
+	scim.Handle("/Groups/{id:[A-Za-z0-9]+}", w.APIHandlerTrustRequester(getScimGroup)).Methods(http.MethodGet)
+	scim.Handle("/Groups/{id:[A-Za-z0-9]+}", w.APIHandlerTrustRequester(updateScimGroup)).Methods(http.MethodPut)
+	scim.Handle("/Groups/{id:[A-Za-z0-9]+}", w.APIHandlerTrustRequester(patchScimGroup)).Methods(http.MethodPatch)
+	scim.Handle("/Groups/{id:[A-Za-z0-9]+}", w.APIHandlerTrustRequester(deleteScimGroup)).Methods(http.MethodDelete)
+}
+
+// scimAuthorized checks that SCIM is enabled and that the request carries the configured bearer
+// token, writing a SCIM error otherwise.
+func scimAuthorized(c *Context, w http.ResponseWriter, r *http.Request) bool {
+	settings := c.App.Config().ScimSettings
+	if !*settings.Enable {
+		writeScimError(c, w, model.NewScimError(http.StatusNotImplemented, "", "SCIM provisioning is disabled"))
+		return false
+	}
+
+	scheme, token, _ := strings.Cut(r.Header.Get(model.HeaderAuth), " ")
+	if !strings.EqualFold(scheme, model.HeaderBearer) || *settings.Token == "" || subtle.ConstantTimeCompare([]byte(strings.TrimSpace(token)), []byte(*settings.Token)) != 1 {
+		writeScimError(c, w, model.NewScimError(http.StatusUnauthorized, "", "invalid or missing bearer token"))
+		return false
+	}
+
+	return true
+}
+
+func scimBaseURL(c *Context) string {
+	return c.GetSiteURLHeader() + scimRoute
+}
+
+func writeScimResponse(c *Context, w http.ResponseWriter, statusCode int, v any) {
+	w.Header().Set("Content-Type", scimContentType)
+	w.WriteHeader(statusCode)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		c.Logger.Warn("Error while writing response", mlog.Err(err))
+	}
+}
+
+func writeScimError(c *Context, w http.ResponseWriter, scimErr *model.ScimError) {
+	writeScimResponse(c, w, scimErr.StatusCode, scimErr)
+}
+
+// writeScimAppError writes an app error in the SCIM error format, telling identity providers
+// about conflicts so they can link existing accounts instead.
+func writeScimAppError(c *Context, w http.ResponseWriter, appErr *model.AppError) {
+	appErr.Translate(c.AppContext.T)
+
+	var scimErr *model.ScimError
+	switch {
+	case appErr.Id == "app.user.save.email_exists.app_error",
+		appErr.Id == "app.user.save.username_exists.app_error",
+		appErr.Id == "app.user.update_auth_data.email_exists.app_error",
+		appErr.Id == "app.scim.group_exists.app_error":
+		scimErr = model.NewScimError(http.StatusConflict, model.ScimErrorTypeUniqueness, appErr.Message)
+	case appErr.Id == "app.scim.filter.app_error":
+		scimErr = model.NewScimError(http.StatusBadRequest, model.ScimErrorTypeInvalidFilter, appErr.Message)
+	case appErr.StatusCode == http.StatusBadRequest:
+		scimErr = model.NewScimError(http.StatusBadRequest, model.ScimErrorTypeInvalidValue, appErr.Message)
+	case appErr.StatusCode == http.StatusNotFound:
+		scimErr = model.NewScimError(http.StatusNotFound, "", appErr.Message)
+	default:
+		c.LogErrorByCode(appErr)
+		scimErr = model.NewScimError(appErr.StatusCode, "", appErr.Message)
+	}
+
+	writeScimError(c, w, scimErr)
+}
+
+func decodeScimBody(c *Context, w http.ResponseWriter, r *http.Request, v any) bool {
+	if err := json.NewDecoder(r.Body).Decode(v); err != nil {
+		writeScimError(c, w, model.NewScimError(http.StatusBadRequest, model.ScimErrorTypeInvalidSyntax, "invalid request body"))
+		return false
+	}
+	return true
+}
+
+// getScimListParams parses the filter and the 1-based pagination of a SCIM list request.
+func getScimListParams(r *http.Request) (filter model.ScimFilter, startIndex, count int, scimErr *model.ScimError) {
+	query := r.URL.Query()
+
+	if value := query.Get("filter"); value != "" {
+		if filter, scimErr = model.ParseScimFilter(value); scimErr != nil {
+			return nil, 0, 0, scimErr
+		}
+	}
+
+	startIndex = 1
+	if value, err := strconv.Atoi(query.Get("startIndex")); err == nil && value > 1 {
+		startIndex = value
+	}
+
+	count = model.ScimDefaultCount
+	if value, err := strconv.Atoi(query.Get("count")); err == nil {
+		count = max(0, min(value, model.ScimMaxCount))
+	}
+
+	return filter, startIndex, count, nil
+}
+
+func getScimServiceProviderConfig(c *Context, w http.ResponseWriter, r *http.Request) {
+	if !scimAuthorized(c, w, r) {
+		return
+	}
+
+	writeScimResponse(c, w, http.StatusOK, model.NewScimServiceProviderConfig(scimBaseURL(c)))
+}
+
+func getScimUsers(c *Context, w http.ResponseWriter, r *http.Request) {
+	if !scimAuthorized(c, w, r) {
+		return
+	}
+
+	filter, startIndex, count, scimErr := getScimListParams(r)
+	if scimErr != nil {
+		writeScimError(c, w, scimErr)
+		return
+	}
+
+	users, total, appErr := c.App.GetScimUsers(filter, startIndex-1, count)
+	if appErr != nil {
+		writeScimAppError(c, w, appErr)
+		return
+	}
+
+	resources := make([]*model.ScimUser, 0, len(users))
+	for _, user := range users {
+		resources = append(resources, model.ScimUserFromUser(user, scimBaseURL(c)))
+	}
+
+	writeScimResponse(c, w, http.StatusOK, model.NewScimListResponse(resources, total, startIndex, len(resources)))
+}
+
+func getScimUser(c *Context, w http.ResponseWriter, r *http.Request) {
+	if !scimAuthorized(c, w, r) {
+		return
+	}
+
+	user, appErr := c.App.GetScimUser(mux.Vars(r)["id"])
+	if appErr != nil {
+		writeScimAppError(c, w, appErr)
+		return
+	}
+
+	writeScimResponse(c, w, http.StatusOK, model.ScimUserFromUser(user, scimBaseURL(c)))
+}
+
+func createScimUser(c *Context, w http.ResponseWriter, r *http.Request) {
+	if !scimAuthorized(c, w, r) {
+		return
+	}
+
+	var su model.ScimUser
+	if !decodeScimBody(c, w, r, &su) {
+		return
+	}
+
+	if scimErr := su.IsValid(); scimErr != nil {
+		writeScimError(c, w, scimErr)
+		return
+	}
+
+	auditRec := c.MakeAuditRecord("createScimUser", audit.Fail)
+	defer c.LogAuditRec(auditRec)
+	audit.AddEventParameter(auditRec, "user_name", su.UserName)
+	audit.AddEventParameter(auditRec, "external_id", su.ExternalId)
+
+	user, appErr := c.App.CreateScimUser(c.AppContext, &su)
+	if appErr != nil {
+		writeScimAppError(c, w, appErr)
+		return
+	}
+
+	auditRec.Success()
+	auditRec.AddEventResultState(user)
+	auditRec.AddEventObjectType("user")
+
+	resource := model.ScimUserFromUser(user, scimBaseURL(c))
+	w.Header().Set("Location", resource.Meta.Location)
+	writeScimResponse(c, w, http.StatusCreated, resource)
+}
+
+func updateScimUser(c *Context, w http.ResponseWriter, r *http.Request) {
+	if !scimAuthorized(c, w, r) {
+		return
+	}
+
+	var su model.ScimUser
+	if !decodeScimBody(c, w, r, &su) {
+		return
+	}
+
+	if scimErr := su.IsValid(); scimErr != nil {
+		writeScimError(c, w, scimErr)
+		return
+	}
+
+	auditRec := c.MakeAuditRecord("updateScimUser", audit.Fail)
+	defer c.LogAuditRec(auditRec)
+	audit.AddEventParameter(auditRec, "user_id", mux.Vars(r)["id"])
+
+	user, appErr := c.App.GetScimUser(mux.Vars(r)["id"])
+	if appErr != nil {
+		writeScimAppError(c, w, appErr)
+		return
+	}
+	auditRec.AddEventPriorState(user)
+
+	user, appErr = c.App.UpdateScimUser(c.AppContext, user.DeepCopy(), &su)
+	if appErr != nil {
+		writeScimAppError(c, w, appErr)
+		return
+	}
+
+	auditRec.Success()
+	auditRec.AddEventResultState(user)
+	auditRec.AddEventObjectType("user")
+
+	writeScimResponse(c, w, http.StatusOK, model.ScimUserFromUser(user, scimBaseURL(c)))
+}
+
+func patchScimUser(c *Context, w http.ResponseWriter, r *http.Request) {
+	if !scimAuthorized(c, w, r) {
+		return
+	}
+
+	var patch model.ScimPatchRequest
+	if !decodeScimBody(c, w, r, &patch) {
+		return
+	}
+
+	auditRec := c.MakeAuditRecord("patchScimUser", audit.Fail)
+	defer c.LogAuditRec(auditRec)
+	audit.AddEventParameter(auditRec, "user_id", mux.Vars(r)["id"])
+
+	user, appErr := c.App.GetScimUser(mux.Vars(r)["id"])
+	if appErr != nil {
+		writeScimAppError(c, w, appErr)
+		return
+	}
+	auditRec.AddEventPriorState(user)
+
+	su := model.ScimUserFromUser(user, scimBaseURL(c))
+	if scimErr := su.ApplyPatch(&patch); scimErr != nil {
+		writeScimError(c, w, scimErr)
+		return
+	}
+
+	if scimErr := su.IsValid(); scimErr != nil {
+		writeScimError(c, w, scimErr)
+		return
+	}
+
+	user, appErr = c.App.UpdateScimUser(c.AppContext, user.DeepCopy(), su)
+	if appErr != nil {
+		writeScimAppError(c, w, appErr)
+		return
+	}
+
+	auditRec.Success()
+	auditRec.AddEventResultState(user)
+	auditRec.AddEventObjectType("user")
+
+	writeScimResponse(c, w, http.StatusOK, model.ScimUserFromUser(user, scimBaseURL(c)))
+}
+
+// deleteScimUser deactivates the user rather than deleting it so that its content is kept.
+func deleteScimUser(c *Context, w http.ResponseWriter, r *http.Request) {
+	if !scimAuthorized(c, w, r) {
+		return
+	}
+
+	auditRec := c.MakeAuditRecord("deleteScimUser", audit.Fail)
+	defer c.LogAuditRec(auditRec)
+	audit.AddEventParameter(auditRec, "user_id", mux.Vars(r)["id"])
+
+	user, appErr := c.App.GetScimUser(mux.Vars(r)["id"])
+	if appErr != nil {
+		writeScimAppError(c, w, appErr)
+		return
+	}
+	auditRec.AddEventPriorState(user)
+
+	if user.DeleteAt == 0 {
+		if _, appErr = c.App.UpdateActive(c.AppContext, user, false); appErr != nil {
+			writeScimAppError(c, w, appErr)
+			return
+		}
+	}
+
+	auditRec.Success()
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// getScimGroupResource converts a group to its SCIM representation, including its members
+// unless the request excludes them.
+func getScimGroupResource(c *Context, r *http.Request, group *model.Group) (*model.ScimGroup, *model.AppError) {
+	var members []*model.User
+	if !strings.Contains(strings.ToLower(r.URL.Query().Get("excludedAttributes")), "members") {
+		var appErr *model.AppError
+		if members, appErr = c.App.GetGroupMemberUsers(group.Id); appErr != nil {
+			return nil, appErr
+		}
+	}
+
+	return model.ScimGroupFromGroup(group, members, scimBaseURL(c)), nil
+}
+
+func getScimGroups(c *Context, w http.ResponseWriter, r *http.Request) {
+	if !scimAuthorized(c, w, r) {
+		return
+	}
+
+	filter, startIndex, count, scimErr := getScimListParams(r)
+	if scimErr != nil {
+		writeScimError(c, w, scimErr)
+		return
+	}
+
+	groups, total, appErr := c.App.GetScimGroups(filter, startIndex-1, count)
+	if appErr != nil {
+		writeScimAppError(c, w, appErr)
+		return
+	}
+
+	resources := make([]*model.ScimGroup, 0, len(groups))
+	for _, group := range groups {
+		resource, appErr := getScimGroupResource(c, r, group)
+		if appErr != nil {
+			writeScimAppError(c, w, appErr)
+			return
+		}
+		resources = append(resources, resource)
+	}
+
+	writeScimResponse(c, w, http.StatusOK, model.NewScimListResponse(resources, total, startIndex, len(resources)))
+}
+
+func getScimGroup(c *Context, w http.ResponseWriter, r *http.Request) {
+	if !scimAuthorized(c, w, r) {
+		return
+	}
+
+	group, appErr := c.App.GetScimGroup(mux.Vars(r)["id"])
+	if appErr != nil {
+		writeScimAppError(c, w, appErr)
+		return
+	}
+
+	resource, appErr := getScimGroupResource(c, r, group)
+	if appErr != nil {
+		writeScimAppError(c, w, appErr)
+		return
+	}
+
+	writeScimResponse(c, w, http.StatusOK, resource)
+}
+
+func createScimGroup(c *Context, w http.ResponseWriter, r *http.Request) {
+	if !scimAuthorized(c, w, r) {
+		return
+	}
+
+	var sg model.ScimGroup
+	if !decodeScimBody(c, w, r, &sg) {
+		return
+	}
+
+	if scimErr := sg.IsValid(); scimErr != nil {
+		writeScimError(c, w, scimErr)
+		return
+	}
+
+	auditRec := c.MakeAuditRecord("createScimGroup", audit.Fail)
+	defer c.LogAuditRec(auditRec)
+	audit.AddEventParameter(auditRec, "display_name", sg.DisplayName)
+	audit.AddEventParameter(auditRec, "external_id", sg.ExternalId)
+	audit.AddEventParameter(auditRec, "member_ids", sg.MemberIDs())
+
+	group, appErr := c.App.CreateScimGroup(c.AppContext, &sg)
+	if appErr != nil {
+		writeScimAppError(c, w, appErr)
+		return
+	}
+
+	auditRec.Success()
+	auditRec.AddEventResultState(group)
+	auditRec.AddEventObjectType("group")
+
+	resource, appErr := getScimGroupResource(c, r, group)
+	if appErr != nil {
+		writeScimAppError(c, w, appErr)
+		return
+	}
+
+	w.Header().Set("Location", resource.Meta.Location)
+	writeScimResponse(c, w, http.StatusCreated, resource)
+}
+
+func updateScimGroup(c *Context, w http.ResponseWriter, r *http.Request) {
+	if !scimAuthorized(c, w, r) {
+		return
+	}
+
+	var sg model.ScimGroup
+	if !decodeScimBody(c, w, r, &sg) {
+		return
+	}
+
+	if scimErr := sg.IsValid(); scimErr != nil {
+		writeScimError(c, w, scimErr)
+		return
+	}
+
+	auditRec := c.MakeAuditRecord("updateScimGroup", audit.Fail)
+	defer c.LogAuditRec(auditRec)
+	audit.AddEventParameter(auditRec, "group_id", mux.Vars(r)["id"])
+	audit.AddEventParameter(auditRec, "member_ids", sg.MemberIDs())
+
+	group, appErr := c.App.GetScimGroup(mux.Vars(r)["id"])
+	if appErr != nil {
+		writeScimAppError(c, w, appErr)
+		return
+	}
+	auditRec.AddEventPriorState(group)
+
+	group, appErr = c.App.UpdateScimGroup(c.AppContext, group, &sg)
+	if appErr != nil {
+		writeScimAppError(c, w, appErr)
+		return
+	}
+
+	auditRec.Success()
+	auditRec.AddEventResultState(group)
+	auditRec.AddEventObjectType("group")
+
+	resource, appErr := getScimGroupResource(c, r, group)
+	if appErr != nil {
+		writeScimAppError(c, w, appErr)
+		return
+	}
+
+	writeScimResponse(c, w, http.StatusOK, resource)
+}
+
+func patchScimGroup(c *Context, w http.ResponseWriter, r *http.Request) {
+	if !scimAuthorized(c, w, r) {
+		return
+	}
+
+	var patch model.ScimPatchRequest
+	if !decodeScimBody(c, w, r, &patch) {
+		return
+	}
+
+	operations, scimErr := patch.GroupOperations()
+	if scimErr != nil {
+		writeScimError(c, w, scimErr)
+		return
+	}
+
+	auditRec := c.MakeAuditRecord("patchScimGroup", audit.Fail)
+	defer c.LogAuditRec(auditRec)
+	audit.AddEventParameter(auditRec, "group_id", mux.Vars(r)["id"])
+
+	group, appErr := c.App.GetScimGroup(mux.Vars(r)["id"])
+	if appErr != nil {
+		writeScimAppError(c, w, appErr)
+		return
+	}
+	auditRec.AddEventPriorState(group)
+
+	group, appErr = c.App.PatchScimGroup(c.AppContext, group, operations)
+	if appErr != nil {
+		writeScimAppError(c, w, appErr)
+		return
+	}
+
+	auditRec.Success()
+	auditRec.AddEventResultState(group)
+	auditRec.AddEventObjectType("group")
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func deleteScimGroup(c *Context, w http.ResponseWriter, r *http.Request) {
+	if !scimAuthorized(c, w, r) {
+		return
+	}
+
+	auditRec := c.MakeAuditRecord("deleteScimGroup", audit.Fail)
+	defer c.LogAuditRec(auditRec)
+	audit.AddEventParameter(auditRec, "group_id", mux.Vars(r)["id"])
+
+	group, appErr := c.App.GetScimGroup(mux.Vars(r)["id"])
+	if appErr != nil {
+		writeScimAppError(c, w, appErr)
+		return
+	}
+	auditRec.AddEventPriorState(group)
+
+	if appErr := c.App.DeleteScimGroup(c.AppContext, group); appErr != nil {
+		writeScimAppError(c, w, appErr)
+		return
+	}
+
+	auditRec.Success()
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,258 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package web
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost/server/public/model"
+)
+
+func TestScim(t *testing.T) {
+	th := Setup(t).InitBasic()
+	defer th.TearDown()
+
+	token := model.NewId() + model.NewId()
+	th.App.UpdateConfig(func(cfg *model.Config) {
+		*cfg.ScimSettings.Enable = true
+		*cfg.ScimSettings.Token = token
+	})
+
+	doScimRequest := func(t *testing.T, method, path string, body any, v any) *http.Response {
+		t.Helper()
+
+		var data []byte
+		if body != nil {
+			var err error
+			data, err = json.Marshal(body)
+			require.NoError(t, err)
+		}
+
+		req, err := http.NewRequest(method, apiClient.URL+"/scim/v2"+path, bytes.NewReader(data))
+		require.NoError(t, err)
+		req.Header.Set("Content-Type", "application/scim+json")
+		req.Header.Set(model.HeaderAuth, "Bearer "+token)
+
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+
+		if v != nil && resp.StatusCode != http.StatusNoContent {
+			require.NoError(t, json.NewDecoder(resp.Body).Decode(v))
+		}
+		return resp
+	}
+
+	t.Run("requires the bearer token", func(t *testing.T) {
+		for _, authorization := range []string{"", "Bearer wrong", "Basic " + token} {
+			req, err := http.NewRequest(http.MethodGet, apiClient.URL+"/scim/v2/Users", nil)
+			require.NoError(t, err)
+			req.Header.Set(model.HeaderAuth, authorization)
+
+			resp, err := http.DefaultClient.Do(req)
+			require.NoError(t, err)
+			resp.Body.Close()
+			assert.Equal(t, http.StatusUnauthorized, resp.StatusCode, authorization)
+		}
+
+		var config model.ScimServiceProviderConfig
+		resp := doScimRequest(t, http.MethodGet, "/ServiceProviderConfig", nil, &config)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.True(t, config.Patch.Supported)
+	})
+
+	externalID := model.NewId()
+	userName := "scim" + model.NewId()
+	var scimUser model.ScimUser
+
+	t.Run("create user", func(t *testing.T) {
+		resp := doScimRequest(t, http.MethodPost, "/Users", &model.ScimUser{
+			Schemas:    []string{model.ScimSchemaUser},
+			ExternalId: externalID,
+			UserName:   userName + "@example.com",
+			Name:       &model.ScimName{GivenName: "Scim", FamilyName: "User"},
+			Emails:     []model.ScimEmail{{Value: userName + "@example.com", Primary: true}},
+			Active:     model.NewPointer(true),
+		}, &scimUser)
+		require.Equal(t, http.StatusCreated, resp.StatusCode)
+		assert.Equal(t, userName, scimUser.UserName)
+		assert.Equal(t, externalID, scimUser.ExternalId)
+
+		user, appErr := th.App.GetUser(scimUser.Id)
+		require.Nil(t, appErr)
+		assert.Equal(t, model.UserAuthServiceSaml, user.AuthService)
+		assert.Equal(t, externalID, user.GetAuthData())
+		assert.Equal(t, "Scim", user.FirstName)
+		assert.True(t, user.EmailVerified)
+
+		var scimErr model.ScimError
+		resp = doScimRequest(t, http.MethodPost, "/Users", &scimUser, &scimErr)
+		require.Equal(t, http.StatusConflict, resp.StatusCode)
+		assert.Equal(t, model.ScimErrorTypeUniqueness, scimErr.ScimType)
+	})
+
+	t.Run("filter users", func(t *testing.T) {
+		var list model.ScimListResponse
+		resp := doScimRequest(t, http.MethodGet, "/Users?filter="+url.QueryEscape(`userName eq "`+userName+`@example.com"`), nil, &list)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, int64(1), list.TotalResults)
+
+		resp = doScimRequest(t, http.MethodGet, "/Users?filter="+url.QueryEscape(`externalId eq "`+model.NewId()+`"`), nil, &list)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, int64(0), list.TotalResults)
+
+		var scimErr model.ScimError
+		resp = doScimRequest(t, http.MethodGet, "/Users?filter="+url.QueryEscape(`userName co "scim"`), nil, &scimErr)
+		require.Equal(t, http.StatusBadRequest, resp.StatusCode)
+		assert.Equal(t, model.ScimErrorTypeInvalidFilter, scimErr.ScimType)
+	})
+
+	t.Run("patch and deactivate user", func(t *testing.T) {
+		var patched model.ScimUser
+		resp := doScimRequest(t, http.MethodPatch, "/Users/"+scimUser.Id, &model.ScimPatchRequest{
+			Schemas: []string{model.ScimSchemaPatchOp},
+			Operations: []*model.ScimPatchOperation{
+				{Op: "replace", Path: "title", Value: json.RawMessage(`"Engineer"`)},
+				{Op: "replace", Path: "active", Value: json.RawMessage(`"False"`)},
+			},
+		}, &patched)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, "Engineer", patched.Title)
+		assert.False(t, *patched.Active)
+
+		user, appErr := th.App.GetUser(scimUser.Id)
+		require.Nil(t, appErr)
+		assert.NotZero(t, user.DeleteAt)
+
+		resp = doScimRequest(t, http.MethodPatch, "/Users/"+scimUser.Id, &model.ScimPatchRequest{
+			Operations: []*model.ScimPatchOperation{{Op: "replace", Value: json.RawMessage(`{"active":true}`)}},
+		}, &patched)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.True(t, *patched.Active)
+
+		resp = doScimRequest(t, http.MethodDelete, "/Users/"+scimUser.Id, nil, nil)
+		require.Equal(t, http.StatusNoContent, resp.StatusCode)
+
+		user, appErr = th.App.GetUser(scimUser.Id)
+		require.Nil(t, appErr)
+		assert.NotZero(t, user.DeleteAt)
+
+		_, appErr = th.App.UpdateActive(th.Context, user, true)
+		require.Nil(t, appErr)
+	})
+
+	t.Run("users outside of the auth service are not managed", func(t *testing.T) {
+		for _, user := range []*model.User{th.BasicUser, th.SystemAdminUser} {
+			var scimErr model.ScimError
+			resp := doScimRequest(t, http.MethodGet, "/Users/"+user.Id, nil, &scimErr)
+			require.Equal(t, http.StatusNotFound, resp.StatusCode)
+
+			resp = doScimRequest(t, http.MethodPatch, "/Users/"+user.Id, &model.ScimPatchRequest{
+				Operations: []*model.ScimPatchOperation{{Op: "replace", Path: "active", Value: json.RawMessage(`false`)}},
+			}, &scimErr)
+			require.Equal(t, http.StatusNotFound, resp.StatusCode)
+
+			resp = doScimRequest(t, http.MethodDelete, "/Users/"+user.Id, nil, &scimErr)
+			require.Equal(t, http.StatusNotFound, resp.StatusCode)
+
+			fetchedUser, appErr := th.App.GetUser(user.Id)
+			require.Nil(t, appErr)
+			assert.Zero(t, fetchedUser.DeleteAt)
+		}
+
+		managedCount, err := th.App.Srv().Store().User().CountNonAdminsUsingAuthService(model.UserAuthServiceSaml)
+		require.NoError(t, err)
+
+		var list model.ScimListResponse
+		resp := doScimRequest(t, http.MethodGet, "/Users", nil, &list)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, managedCount, list.TotalResults)
+
+		resp = doScimRequest(t, http.MethodGet, "/Users?filter="+url.QueryEscape(`userName eq "`+th.SystemAdminUser.Username+`"`), nil, &list)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, int64(0), list.TotalResults)
+	})
+
+	t.Run("group membership drives team membership", func(t *testing.T) {
+		var scimGroup model.ScimGroup
+		resp := doScimRequest(t, http.MethodPost, "/Groups", &model.ScimGroup{
+			Schemas:     []string{model.ScimSchemaGroup},
+			ExternalId:  model.NewId(),
+			DisplayName: "Engineering",
+		}, &scimGroup)
+		require.Equal(t, http.StatusCreated, resp.StatusCode)
+		assert.Empty(t, scimGroup.Members)
+
+		group, appErr := th.App.GetGroup(scimGroup.Id, nil, nil)
+		require.Nil(t, appErr)
+		assert.True(t, group.IsScimManaged())
+
+		team, appErr := th.App.CreateTeam(th.Context, &model.Team{DisplayName: "SCIM", Name: "z-z-" + model.NewId() + "a", Type: model.TeamOpen})
+		require.Nil(t, appErr)
+		_, appErr = th.App.UpsertGroupSyncable(model.NewGroupTeam(group.Id, team.Id, true))
+		require.Nil(t, appErr)
+
+		resp = doScimRequest(t, http.MethodPatch, "/Groups/"+scimGroup.Id, &model.ScimPatchRequest{
+			Operations: []*model.ScimPatchOperation{
+				{Op: "add", Path: "members", Value: json.RawMessage(`[{"value":"` + scimUser.Id + `"}]`)},
+			},
+		}, nil)
+		require.Equal(t, http.StatusNoContent, resp.StatusCode)
+
+		member, appErr := th.App.GetTeamMember(th.Context, team.Id, scimUser.Id)
+		require.Nil(t, appErr)
+		assert.Zero(t, member.DeleteAt)
+
+		resp = doScimRequest(t, http.MethodGet, "/Groups/"+scimGroup.Id, nil, &scimGroup)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, []string{scimUser.Id}, scimGroup.MemberIDs())
+
+		var scimErr model.ScimError
+		resp = doScimRequest(t, http.MethodPatch, "/Groups/"+scimGroup.Id, &model.ScimPatchRequest{
+			Operations: []*model.ScimPatchOperation{
+				{Op: "add", Path: "members", Value: json.RawMessage(`[{"value":"` + model.NewId() + `"}]`)},
+			},
+		}, &scimErr)
+		require.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+		// The users that SCIM doesn't manage can't be added to its groups.
+		for _, user := range []*model.User{th.BasicUser, th.SystemAdminUser} {
+			resp = doScimRequest(t, http.MethodPatch, "/Groups/"+scimGroup.Id, &model.ScimPatchRequest{
+				Operations: []*model.ScimPatchOperation{
+					{Op: "add", Path: "members", Value: json.RawMessage(`[{"value":"` + user.Id + `"}]`)},
+				},
+			}, &scimErr)
+			require.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+			_, appErr = th.App.GetTeamMember(th.Context, team.Id, user.Id)
+			require.NotNil(t, appErr)
+		}
+
+		resp = doScimRequest(t, http.MethodGet, "/Groups/"+scimGroup.Id, nil, &scimGroup)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, []string{scimUser.Id}, scimGroup.MemberIDs())
+
+		resp = doScimRequest(t, http.MethodPatch, "/Groups/"+scimGroup.Id, &model.ScimPatchRequest{
+			Operations: []*model.ScimPatchOperation{{Op: "remove", Path: `members[value eq "` + scimUser.Id + `"]`}},
+		}, nil)
+		require.Equal(t, http.StatusNoContent, resp.StatusCode)
+
+		resp = doScimRequest(t, http.MethodGet, "/Groups/"+scimGroup.Id, nil, &scimGroup)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Empty(t, scimGroup.Members)
+
+		resp = doScimRequest(t, http.MethodDelete, "/Groups/"+scimGroup.Id, nil, nil)
+		require.Equal(t, http.StatusNoContent, resp.StatusCode)
+
+		resp = doScimRequest(t, http.MethodGet, "/Groups/"+scimGroup.Id, nil, &scimErr)
+		require.Equal(t, http.StatusNotFound, resp.StatusCode)
+	})
+}
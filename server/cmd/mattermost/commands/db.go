@@ -323,11 +323,35 @@ func dbVersionCmdF(command *cobra.Command, args []string) error {
 }
 
 func ConfigToFileBackendSettings(s *model.FileSettings, enableComplianceFeature bool, skipVerify bool) filestore.FileBackendSettings {
-	if *s.DriverName == model.ImageDriverLocal {
+	switch *s.DriverName {
+	case model.ImageDriverLocal:
 		return filestore.FileBackendSettings{
 			DriverName: *s.DriverName,
 			Directory:  *s.Directory,
 		}
+	case model.ImageDriverAzure:
+		return filestore.FileBackendSettings{
+			DriverName:                      *s.DriverName,
+			AzureAccountName:                *s.AzureAccountName,
+			AzureAccountKey:                 *s.AzureAccountKey,
+			AzureContainer:                  *s.AzureContainer,
+			AzurePathPrefix:                 *s.AzurePathPrefix,
+			AzureEndpoint:                   *s.AzureEndpoint,
+			AzureRequestTimeoutMilliseconds: *s.AzureRequestTimeoutMilliseconds,
+			AzureSignedURLExpiresSeconds:    *s.AzureSignedURLExpiresSeconds,
+			SkipVerify:                      skipVerify,
+		}
+	case model.ImageDriverGCS:
+		return filestore.FileBackendSettings{
+			DriverName:                    *s.DriverName,
+			GCSBucket:                     *s.GCSBucket,
+			GCSPathPrefix:                 *s.GCSPathPrefix,
+			GCSServiceAccountKey:          *s.GCSServiceAccountKey,
+			GCSEndpoint:                   *s.GCSEndpoint,
+			GCSRequestTimeoutMilliseconds: *s.GCSRequestTimeoutMilliseconds,
+			GCSSignedURLExpiresSeconds:    *s.GCSSignedURLExpiresSeconds,
+			SkipVerify:                    skipVerify,
+		}
 	}
 	return filestore.FileBackendSettings{
 		DriverName:                         *s.DriverName,
@@ -52,6 +52,7 @@ type Client interface {
 	UploadLicenseFile(ctx context.Context, data []byte) (*model.Response, error)
 	RemoveLicenseFile(ctx context.Context) (*model.Response, error)
 	GetLogs(ctx context.Context, page, perPage int) ([]string, *model.Response, error)
+	SearchAuditRecords(ctx context.Context, search *model.AuditRecordSearch) ([]*model.AuditRecord, *model.Response, error)
 	GetRoleByName(ctx context.Context, name string) (*model.Role, *model.Response, error)
 	PatchRole(ctx context.Context, roleID string, patch *model.RolePatch) (*model.Role, *model.Response, error)
 	UploadPlugin(ctx context.Context, file io.Reader) (*model.Manifest, *model.Response, error)
@@ -75,6 +76,8 @@ type Client interface {
 	SendPasswordResetEmail(ctx context.Context, email string) (*model.Response, error)
 	UpdateUser(ctx context.Context, user *model.User) (*model.User, *model.Response, error)
 	UpdateUserMfa(ctx context.Context, userID, code string, activate bool) (*model.Response, error)
+	GetWebAuthnCredentials(ctx context.Context, userID string) ([]*model.WebAuthnCredential, *model.Response, error)
+	DeleteWebAuthnCredential(ctx context.Context, userID, credentialID string) (*model.Response, error)
 	UpdateUserPassword(ctx context.Context, userID, currentPassword, newPassword string) (*model.Response, error)
 	UpdateUserHashedPassword(ctx context.Context, userID, newHashedPassword string) (*model.Response, error)
 	CreateUserAccessToken(ctx context.Context, userID, description string) (*model.UserAccessToken, *model.Response, error)
@@ -90,6 +93,7 @@ type Client interface {
 	UpdateCommand(ctx context.Context, cmd *model.Command) (*model.Command, *model.Response, error)
 	MoveCommand(ctx context.Context, teamID string, commandID string) (*model.Response, error)
 	DeleteCommand(ctx context.Context, commandID string) (*model.Response, error)
+	RegenCommandSigningSecret(ctx context.Context, commandID string) (string, *model.Response, error)
 	GetConfig(ctx context.Context) (*model.Config, *model.Response, error)
 	GetOldClientConfig(ctx context.Context, etag string) (map[string]string, *model.Response, error)
 	UpdateConfig(context.Context, *model.Config) (*model.Config, *model.Response, error)
@@ -146,6 +150,9 @@ type Client interface {
 	GetOutgoingWebhooksForChannel(ctx context.Context, channelID string, page int, perPage int, etag string) ([]*model.OutgoingWebhook, *model.Response, error)
 	GetOutgoingWebhooksForTeam(ctx context.Context, teamID string, page int, perPage int, etag string) ([]*model.OutgoingWebhook, *model.Response, error)
 	RegenOutgoingHookToken(ctx context.Context, hookID string) (*model.OutgoingWebhook, *model.Response, error)
+	RegenOutgoingHookSigningSecret(ctx context.Context, hookID string) (*model.OutgoingWebhook, *model.Response, error)
+	GetOutgoingWebhookDeadLetters(ctx context.Context, hookID string, page int, perPage int) ([]*model.OutgoingWebhookDelivery, *model.Response, error)
+	ReplayOutgoingWebhookDelivery(ctx context.Context, hookID, deliveryID string) (*model.OutgoingWebhookDelivery, *model.Response, error)
 	DeleteOutgoingWebhook(ctx context.Context, hookID string) (*model.Response, error)
 	ListExports(ctx context.Context) ([]string, *model.Response, error)
 	DeleteExport(ctx context.Context, name string) (*model.Response, error)
@@ -0,0 +1,143 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package commands
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/mattermost/mattermost/server/public/model"
+
+	"github.com/mattermost/mattermost/server/v8/cmd/mmctl/client"
+	"github.com/mattermost/mattermost/server/v8/cmd/mmctl/printer"
+)
+
+var AuditCmd = &cobra.Command{
+	Use:   "audit",
+	Short: "Management of audit records",
+}
+
+var AuditSearchCmd = &cobra.Command{
+	Use:   "search",
+	Short: "Search audit records",
+	Long:  "Search the audit records stored in the database. Requires ExperimentalAuditSettings.DatabaseEnabled to be set on the server.",
+	Example: `  audit search --user 4xp9fdt77pncbef59f4k1qe83o --since 2024-01-01
+  audit search --event updateChannel --status fail --since 2024-01-01T00:00:00Z --until 2024-02-01T00:00:00Z
+  audit search --object-id 8fnsnr1rdjrrpmx1htyxcofgxh --csv`,
+	Args: cobra.NoArgs,
+	RunE: withClient(auditSearchCmdF),
+}
+
+const auditDateLayout = "2006-01-02"
+
+var auditRecordCSVHeader = []string{"id", "create_at", "event_name", "status", "user_id", "session_id", "client", "ip_address", "object_type", "object_ids", "data"}
+
+func init() {
+	AuditSearchCmd.Flags().String("event", "", "Only return records for this event name")
+	AuditSearchCmd.Flags().String("user", "", "Only return records for this user id")
+	AuditSearchCmd.Flags().String("status", "", "Only return records with this status")
+	AuditSearchCmd.Flags().String("object-id", "", "Only return records referring to this object id")
+	AuditSearchCmd.Flags().String("since", "", "Only return records created at or after this time (RFC 3339 or YYYY-MM-DD)")
+	AuditSearchCmd.Flags().String("until", "", "Only return records created at or before this time (RFC 3339 or YYYY-MM-DD)")
+	AuditSearchCmd.Flags().Int("page", 0, "Page number to fetch")
+	AuditSearchCmd.Flags().Int("per-page", model.AuditRecordSearchDefaultPerPage, "Number of records to fetch per page")
+	AuditSearchCmd.Flags().Bool("csv", false, "Output the records as CSV")
+
+	AuditCmd.AddCommand(
+		AuditSearchCmd,
+	)
+
+	RootCmd.AddCommand(AuditCmd)
+}
+
+// parseAuditTime parses a time given either in RFC 3339 format or as a date, in which
+// case the start of the day in UTC is used.
+func parseAuditTime(value string) (int64, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return model.GetMillisForTime(t), nil
+	}
+
+	t, err := time.Parse(auditDateLayout, value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid time %q, use RFC 3339 or YYYY-MM-DD", value)
+	}
+	return model.GetMillisForTime(t), nil
+}
+
+func auditSearchCmdF(c client.Client, cmd *cobra.Command, args []string) error {
+	search := &model.AuditRecordSearch{}
+	search.EventName, _ = cmd.Flags().GetString("event")
+	search.UserId, _ = cmd.Flags().GetString("user")
+	search.Status, _ = cmd.Flags().GetString("status")
+	search.ObjectId, _ = cmd.Flags().GetString("object-id")
+	search.Page, _ = cmd.Flags().GetInt("page")
+	search.PerPage, _ = cmd.Flags().GetInt("per-page")
+
+	if since, _ := cmd.Flags().GetString("since"); since != "" {
+		millis, err := parseAuditTime(since)
+		if err != nil {
+			return err
+		}
+		search.Since = millis
+	}
+	if until, _ := cmd.Flags().GetString("until"); until != "" {
+		millis, err := parseAuditTime(until)
+		if err != nil {
+			return err
+		}
+		search.Until = millis
+	}
+
+	records, _, err := c.SearchAuditRecords(context.TODO(), search)
+	if err != nil {
+		return errors.Wrap(err, "failed to search audit records")
+	}
+
+	if asCSV, _ := cmd.Flags().GetBool("csv"); asCSV {
+		return writeAuditRecordsCSV(cmd, records)
+	}
+
+	for _, record := range records {
+		createAt := model.GetTimeForMillis(record.CreateAt).UTC().Format(time.RFC3339)
+		printer.PrintT(createAt+" {{.EventName}} {{.Status}} user={{.UserId}} ip={{.IpAddress}}", record)
+	}
+
+	return nil
+}
+
+func writeAuditRecordsCSV(cmd *cobra.Command, records []*model.AuditRecord) error {
+	w := csv.NewWriter(cmd.OutOrStdout())
+	if err := w.Write(auditRecordCSVHeader); err != nil {
+		return errors.Wrap(err, "failed to write CSV")
+	}
+
+	for _, record := range records {
+		row := []string{
+			record.Id,
+			strconv.FormatInt(record.CreateAt, 10),
+			record.EventName,
+			record.Status,
+			record.UserId,
+			record.SessionId,
+			record.Client,
+			record.IpAddress,
+			record.ObjectType,
+			strings.Join(record.ObjectIds, " "),
+			record.Data,
+		}
+		if err := w.Write(row); err != nil {
+			return errors.Wrap(err, "failed to write CSV")
+		}
+	}
+
+	w.Flush()
+	return errors.Wrap(w.Error(), "failed to write CSV")
+}
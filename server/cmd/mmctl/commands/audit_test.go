@@ -0,0 +1,131 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package commands
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"errors"
+
+	"github.com/mattermost/mattermost/server/public/model"
+
+	"github.com/mattermost/mattermost/server/v8/cmd/mmctl/printer"
+
+	"github.com/spf13/cobra"
+)
+
+func newAuditSearchTestCmd() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Flags().String("event", "", "")
+	cmd.Flags().String("user", "", "")
+	cmd.Flags().String("status", "", "")
+	cmd.Flags().String("object-id", "", "")
+	cmd.Flags().String("since", "", "")
+	cmd.Flags().String("until", "", "")
+	cmd.Flags().Int("page", 0, "")
+	cmd.Flags().Int("per-page", model.AuditRecordSearchDefaultPerPage, "")
+	cmd.Flags().Bool("csv", false, "")
+	return cmd
+}
+
+func (s *MmctlUnitTestSuite) TestAuditSearchCmdF() {
+	records := []*model.AuditRecord{
+		{
+			Id:        model.NewId(),
+			CreateAt:  1704067200000,
+			EventName: "updateChannel",
+			Status:    "success",
+			UserId:    model.NewId(),
+			ObjectIds: model.StringArray{model.NewId(), model.NewId()},
+			Data:      `{"event_name":"updateChannel"}`,
+		},
+	}
+
+	s.Run("search with filters", func() {
+		printer.Clean()
+
+		userID := model.NewId()
+		cmd := newAuditSearchTestCmd()
+		s.Require().NoError(cmd.Flags().Set("event", "updateChannel"))
+		s.Require().NoError(cmd.Flags().Set("user", userID))
+		s.Require().NoError(cmd.Flags().Set("since", "2024-01-01"))
+		s.Require().NoError(cmd.Flags().Set("until", "2024-01-02T12:00:00Z"))
+		s.Require().NoError(cmd.Flags().Set("page", "2"))
+
+		s.client.
+			EXPECT().
+			SearchAuditRecords(context.TODO(), &model.AuditRecordSearch{
+				EventName: "updateChannel",
+				UserId:    userID,
+				Since:     1704067200000,
+				Until:     1704196800000,
+				Page:      2,
+				PerPage:   model.AuditRecordSearchDefaultPerPage,
+			}).
+			Return(records, &model.Response{}, nil).
+			Times(1)
+
+		err := auditSearchCmdF(s.client, cmd, nil)
+		s.Require().NoError(err)
+		s.Require().Len(printer.GetLines(), 1)
+		s.Equal(records[0], printer.GetLines()[0])
+		s.Empty(printer.GetErrorLines())
+	})
+
+	s.Run("csv output", func() {
+		printer.Clean()
+
+		cmd := newAuditSearchTestCmd()
+		s.Require().NoError(cmd.Flags().Set("csv", "true"))
+		var buf bytes.Buffer
+		cmd.SetOut(&buf)
+
+		s.client.
+			EXPECT().
+			SearchAuditRecords(context.TODO(), &model.AuditRecordSearch{PerPage: model.AuditRecordSearchDefaultPerPage}).
+			Return(records, &model.Response{}, nil).
+			Times(1)
+
+		err := auditSearchCmdF(s.client, cmd, nil)
+		s.Require().NoError(err)
+		s.Empty(printer.GetLines())
+
+		rows, err := csv.NewReader(&buf).ReadAll()
+		s.Require().NoError(err)
+		s.Require().Len(rows, 2)
+		s.Equal(auditRecordCSVHeader, rows[0])
+		s.Equal(records[0].Id, rows[1][0])
+		s.Equal("1704067200000", rows[1][1])
+		s.Equal(records[0].ObjectIds[0]+" "+records[0].ObjectIds[1], rows[1][9])
+		s.Equal(records[0].Data, rows[1][10])
+	})
+
+	s.Run("invalid time", func() {
+		printer.Clean()
+
+		cmd := newAuditSearchTestCmd()
+		s.Require().NoError(cmd.Flags().Set("since", "yesterday"))
+
+		err := auditSearchCmdF(s.client, cmd, nil)
+		s.Require().Error(err)
+		s.Contains(err.Error(), "invalid time")
+	})
+
+	s.Run("search fails", func() {
+		printer.Clean()
+
+		cmd := newAuditSearchTestCmd()
+
+		s.client.
+			EXPECT().
+			SearchAuditRecords(context.TODO(), &model.AuditRecordSearch{PerPage: model.AuditRecordSearchDefaultPerPage}).
+			Return(nil, &model.Response{}, errors.New("mock error")).
+			Times(1)
+
+		err := auditSearchCmdF(s.client, cmd, nil)
+		s.Require().Error(err)
+		s.Contains(err.Error(), "mock error")
+	})
+}
@@ -86,6 +86,15 @@ var CommandShowCmd = &cobra.Command{
 	RunE:    withClient(showCommandCmdF),
 }
 
+var CommandRotateSecretCmd = &cobra.Command{
+	Use:     "rotate-secret [commandID]",
+	Short:   "Rotate the signing secret of a slash command",
+	Long:    `Generate a new secret used to sign the requests sent by a slash command. The previous secret stops working immediately. Commands can be specified by command ID.`,
+	Args:    cobra.ExactArgs(1),
+	Example: `  command rotate-secret commandID`,
+	RunE:    withClient(rotateCommandSecretCmdF),
+}
+
 func addCommandFieldsFlags(cmd *cobra.Command) {
 	cmd.Flags().String("title", "", "Command Title")
 	cmd.Flags().String("description", "", "Command Description")
@@ -118,6 +127,7 @@ func init() {
 		CommandMoveCmd,
 		CommandShowCmd,
 		CommandArchiveCmd,
+		CommandRotateSecretCmd,
 	)
 	RootCmd.AddCommand(CommandCmd)
 }
@@ -352,3 +362,15 @@ method:             {{.Method}}`
 	printer.PrintT(template, command)
 	return nil
 }
+
+func rotateCommandSecretCmdF(c client.Client, cmd *cobra.Command, args []string) error {
+	printer.SetSingle(true)
+
+	secret, _, err := c.RegenCommandSigningSecret(context.TODO(), args[0])
+	if err != nil {
+		return errors.New("Unable to rotate the signing secret of command '" + args[0] + "' error: " + err.Error())
+	}
+
+	printer.PrintT("Command {{.id}} signing secret rotated: {{.signing_secret}}", map[string]interface{}{"id": args[0], "signing_secret": secret})
+	return nil
+}
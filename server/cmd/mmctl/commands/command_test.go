@@ -1062,3 +1062,41 @@ func (s *MmctlUnitTestSuite) TestCommandShowCmd() {
 		s.EqualError(err, "unable to find command '\"test/../hello?\"move'")
 	})
 }
+
+func (s *MmctlUnitTestSuite) TestRotateCommandSecretCmd() {
+	s.Run("Rotate without errors", func() {
+		printer.Clean()
+		arg := "cmd1"
+		outputMessage := map[string]interface{}{"id": arg, "signing_secret": "newsecret"}
+
+		s.client.
+			EXPECT().
+			RegenCommandSigningSecret(context.TODO(), arg).
+			Return("newsecret", &model.Response{StatusCode: http.StatusOK}, nil).
+			Times(1)
+
+		err := rotateCommandSecretCmdF(s.client, &cobra.Command{}, []string{arg})
+		s.Require().Nil(err)
+		s.Require().Len(printer.GetLines(), 1)
+		s.Require().Equal(outputMessage, printer.GetLines()[0])
+		s.Require().Len(printer.GetErrorLines(), 0)
+	})
+
+	s.Run("Rotate with response error", func() {
+		printer.Clean()
+		arg := "cmd1"
+		mockError := errors.New("mock error")
+
+		s.client.
+			EXPECT().
+			RegenCommandSigningSecret(context.TODO(), arg).
+			Return("", &model.Response{StatusCode: http.StatusBadRequest}, mockError).
+			Times(1)
+
+		err := rotateCommandSecretCmdF(s.client, &cobra.Command{}, []string{arg})
+		s.Require().NotNil(err)
+		s.Require().Equal("Unable to rotate the signing secret of command '"+arg+"' error: "+mockError.Error(), err.Error())
+		s.Require().Len(printer.GetLines(), 0)
+		s.Require().Len(printer.GetErrorLines(), 0)
+	})
+}
@@ -0,0 +1,142 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package commands
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/mattermost/mattermost/server/v8/cmd/mmctl/client"
+	"github.com/mattermost/mattermost/server/v8/cmd/mmctl/printer"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/spf13/cobra"
+)
+
+var FileCmd = &cobra.Command{
+	Use:   "file",
+	Short: "Management of the file store.",
+}
+
+var FileMigrateCmd = &cobra.Command{
+	Use:     "migrate [settingsFile]",
+	Example: "  file migrate s3.json\n  file migrate --export export-s3.json",
+	Short:   "Start a job copying the stored files to another backend.",
+	Long: `Start a job copying every stored file to the backend described by a JSON file holding the FileSettings to change, such as {"DriverName": "amazons3", "AmazonS3Bucket": "mattermost"}. Each copy is verified by size and checksum, files already copied are skipped, and the FileSettings are switched to the new backend once every file was copied. Only the settings of the backend can be changed. The servers switch to the new backend without a restart, and the files uploaded in the meantime are then copied in a final pass.
+
+With the --export flag, the exports are copied to the dedicated export store described by the Export FileSettings, such as {"ExportDriverName": "amazons3", "ExportAmazonS3Bucket": "exports"}, which is enabled once every file was copied.
+
+The settings are kept in the job data until the job is over.`,
+	Args: cobra.ExactArgs(1),
+	RunE: withClient(fileMigrateCmdF),
+}
+
+var FileJobCmd = &cobra.Command{
+	Use:   "job",
+	Short: "List and show file migration jobs",
+}
+
+var FileJobListCmd = &cobra.Command{
+	Use:     "list",
+	Example: "  file job list",
+	Short:   "List file migration jobs",
+	Aliases: []string{"ls"},
+	Args:    cobra.NoArgs,
+	RunE:    withClient(fileJobListCmdF),
+}
+
+var FileJobShowCmd = &cobra.Command{
+	Use:     "show [fileJobID]",
+	Example: "  file job show f3d68qkkm7n8xgsfxwuo498rah",
+	Short:   "Show file migration job",
+	Args:    cobra.ExactArgs(1),
+	RunE:    withClient(fileJobShowCmdF),
+}
+
+func init() {
+	FileMigrateCmd.Flags().Bool("export", false, "Migrate the exports to a dedicated export store instead of the files.")
+	FileJobListCmd.Flags().Int("page", 0, "Page number to fetch for the list of file migration jobs")
+	FileJobListCmd.Flags().Int("per-page", DefaultPageSize, "Number of file migration jobs to be fetched")
+	FileJobListCmd.Flags().Bool("all", false, "Fetch all file migration jobs. --page flag will be ignore if provided")
+	FileJobCmd.AddCommand(
+		FileJobListCmd,
+		FileJobShowCmd,
+	)
+	FileCmd.AddCommand(
+		FileMigrateCmd,
+		FileJobCmd,
+	)
+	RootCmd.AddCommand(FileCmd)
+}
+
+func fileMigrateCmdF(c client.Client, command *cobra.Command, args []string) error {
+	export, err := command.Flags().GetBool("export")
+	if err != nil {
+		return err
+	}
+
+	settingsBytes, err := os.ReadFile(args[0])
+	if err != nil {
+		return err
+	}
+	var fileSettings model.FileSettings
+	if err = json.Unmarshal(settingsBytes, &fileSettings); err != nil {
+		return fmt.Errorf("invalid file settings: %w", err)
+	}
+	if export && fileSettings.ExportDriverName == nil {
+		return fmt.Errorf("the file settings must include the ExportDriverName")
+	} else if !export && fileSettings.DriverName == nil {
+		return fmt.Errorf("the file settings must include the DriverName")
+	}
+	var compacted bytes.Buffer
+	if err = json.Compact(&compacted, settingsBytes); err != nil {
+		return fmt.Errorf("invalid file settings: %w", err)
+	}
+
+	store := "files"
+	if export {
+		store = "export"
+	}
+	job, _, err := c.CreateJob(context.TODO(), &model.Job{
+		Type: model.JobTypeFileMigration,
+		Data: map[string]string{
+			"store":         store,
+			"file_settings": compacted.String(),
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create file migration job: %w", err)
+	}
+
+	printer.PrintT("File migration job successfully created, ID: {{.Id}}", job)
+
+	return nil
+}
+
+func fileJobShowCmdF(c client.Client, command *cobra.Command, args []string) error {
+	job, _, err := c.GetJob(context.TODO(), args[0])
+	if err != nil {
+		return fmt.Errorf("failed to get file migration job: %w", err)
+	}
+	printFileJob(job)
+	return nil
+}
+
+func fileJobListCmdF(c client.Client, command *cobra.Command, args []string) error {
+	return jobListCmdF(c, command, model.JobTypeFileMigration, "")
+}
+
+func printFileJob(job *model.Job) {
+	if job.StartAt > 0 {
+		printer.PrintT(fmt.Sprintf("  ID: {{.Id}}\n  Status: {{.Status}}\n  Created: %s\n  Started: %s\n  Store: %s\n  Progress: {{.Progress}}%%\n  Files: %s/%s\n  Copied: %s\n  Failed: %s\n",
+			time.Unix(job.CreateAt/1000, 0), time.Unix(job.StartAt/1000, 0), job.Data["store"], job.Data["done_file_count"], job.Data["total_file_count"], job.Data["copied_file_count"], job.Data["failed_file_count"]), job)
+	} else {
+		printer.PrintT(fmt.Sprintf("  ID: {{.Id}}\n  Status: {{.Status}}\n  Created: %s\n\n",
+			time.Unix(job.CreateAt/1000, 0)), job)
+	}
+}
@@ -0,0 +1,152 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package commands
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+
+	"github.com/mattermost/mattermost/server/public/model"
+
+	"github.com/mattermost/mattermost/server/v8/cmd/mmctl/printer"
+
+	"github.com/spf13/cobra"
+)
+
+func newFileMigrateTestCmd() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Flags().Bool("export", false, "")
+	return cmd
+}
+
+func (s *MmctlUnitTestSuite) writeFileSettings(settings string) string {
+	path := filepath.Join(s.T().TempDir(), "settings.json")
+	s.Require().NoError(os.WriteFile(path, []byte(settings), 0600))
+	return path
+}
+
+func (s *MmctlUnitTestSuite) TestFileMigrateCmdF() {
+	s.Run("migrate the files", func() {
+		printer.Clean()
+		mockJob := &model.Job{
+			Type: model.JobTypeFileMigration,
+			Data: map[string]string{
+				"store":         "files",
+				"file_settings": `{"DriverName":"amazons3","AmazonS3Bucket":"mattermost"}`,
+			},
+		}
+		path := s.writeFileSettings("{\n  \"DriverName\": \"amazons3\",\n  \"AmazonS3Bucket\": \"mattermost\"\n}\n")
+
+		s.client.
+			EXPECT().
+			CreateJob(context.TODO(), mockJob).
+			Return(mockJob, &model.Response{}, nil).
+			Times(1)
+
+		err := fileMigrateCmdF(s.client, newFileMigrateTestCmd(), []string{path})
+		s.Require().NoError(err)
+		s.Len(printer.GetLines(), 1)
+		s.Empty(printer.GetErrorLines())
+		s.Equal(mockJob, printer.GetLines()[0].(*model.Job))
+	})
+
+	s.Run("migrate the exports", func() {
+		printer.Clean()
+		mockJob := &model.Job{
+			Type: model.JobTypeFileMigration,
+			Data: map[string]string{
+				"store":         "export",
+				"file_settings": `{"ExportDriverName":"amazons3","ExportAmazonS3Bucket":"exports"}`,
+			},
+		}
+		path := s.writeFileSettings(`{"ExportDriverName": "amazons3", "ExportAmazonS3Bucket": "exports"}`)
+
+		cmd := newFileMigrateTestCmd()
+		s.Require().NoError(cmd.Flags().Set("export", "true"))
+
+		s.client.
+			EXPECT().
+			CreateJob(context.TODO(), mockJob).
+			Return(mockJob, &model.Response{}, nil).
+			Times(1)
+
+		err := fileMigrateCmdF(s.client, cmd, []string{path})
+		s.Require().NoError(err)
+		s.Len(printer.GetLines(), 1)
+		s.Equal(mockJob, printer.GetLines()[0].(*model.Job))
+	})
+
+	s.Run("driver name is required", func() {
+		printer.Clean()
+		path := s.writeFileSettings(`{"ExportDriverName": "amazons3"}`)
+
+		err := fileMigrateCmdF(s.client, newFileMigrateTestCmd(), []string{path})
+		s.Require().EqualError(err, "the file settings must include the DriverName")
+		s.Empty(printer.GetLines())
+	})
+
+	s.Run("invalid settings", func() {
+		printer.Clean()
+		path := s.writeFileSettings(`{"DriverName": 1}`)
+
+		err := fileMigrateCmdF(s.client, newFileMigrateTestCmd(), []string{path})
+		s.Require().Error(err)
+		s.Empty(printer.GetLines())
+	})
+
+	s.Run("failed to create job", func() {
+		printer.Clean()
+		path := s.writeFileSettings(`{"DriverName": "local", "Directory": "/var/mattermost/data"}`)
+
+		s.client.
+			EXPECT().
+			CreateJob(context.TODO(), &model.Job{
+				Type: model.JobTypeFileMigration,
+				Data: map[string]string{
+					"store":         "files",
+					"file_settings": `{"DriverName":"local","Directory":"/var/mattermost/data"}`,
+				},
+			}).
+			Return(nil, &model.Response{}, errors.New("mock error")).
+			Times(1)
+
+		err := fileMigrateCmdF(s.client, newFileMigrateTestCmd(), []string{path})
+		s.Require().EqualError(err, "failed to create file migration job: mock error")
+		s.Empty(printer.GetLines())
+	})
+}
+
+func (s *MmctlUnitTestSuite) TestFileJobShowCmdF() {
+	s.Run("show job", func() {
+		printer.Clean()
+		mockJob := &model.Job{
+			Id:       model.NewId(),
+			Type:     model.JobTypeFileMigration,
+			Status:   model.JobStatusInProgress,
+			CreateAt: 1704067200000,
+			StartAt:  1704067201000,
+			Progress: 50,
+			Data: map[string]string{
+				"store":             "files",
+				"done_file_count":   "100",
+				"total_file_count":  "200",
+				"copied_file_count": "90",
+				"failed_file_count": "0",
+			},
+		}
+
+		s.client.
+			EXPECT().
+			GetJob(context.TODO(), mockJob.Id).
+			Return(mockJob, &model.Response{}, nil).
+			Times(1)
+
+		err := fileJobShowCmdF(s.client, &cobra.Command{}, []string{mockJob.Id})
+		s.Require().NoError(err)
+		s.Require().Len(printer.GetLines(), 1)
+		s.Equal(mockJob, printer.GetLines()[0].(*model.Job))
+	})
+}
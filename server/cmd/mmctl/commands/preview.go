@@ -0,0 +1,125 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package commands
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/mattermost/mattermost/server/v8/cmd/mmctl/client"
+	"github.com/mattermost/mattermost/server/v8/cmd/mmctl/printer"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/spf13/cobra"
+)
+
+var PreviewCmd = &cobra.Command{
+	Use:   "preview",
+	Short: "Management of file thumbnails and previews.",
+}
+
+var PreviewRegenerateCmd = &cobra.Command{
+	Use:     "regenerate",
+	Example: "  preview regenerate --from 1704067200",
+	Short:   "Start a job generating the missing thumbnails and previews of images.",
+	Long:    "Start a job generating the thumbnails and previews of the images which are missing them, such as the HEIC photos uploaded before the image converter was enabled.",
+	Args:    cobra.NoArgs,
+	RunE:    withClient(previewRegenerateCmdF),
+}
+
+var PreviewJobCmd = &cobra.Command{
+	Use:   "job",
+	Short: "List and show preview regeneration jobs",
+}
+
+var PreviewJobListCmd = &cobra.Command{
+	Use:     "list",
+	Example: "  preview job list",
+	Short:   "List preview regeneration jobs",
+	Aliases: []string{"ls"},
+	Args:    cobra.NoArgs,
+	RunE:    withClient(previewJobListCmdF),
+}
+
+var PreviewJobShowCmd = &cobra.Command{
+	Use:     "show [previewJobID]",
+	Example: "  preview job show f3d68qkkm7n8xgsfxwuo498rah",
+	Short:   "Show preview regeneration job",
+	Args:    cobra.ExactArgs(1),
+	RunE:    withClient(previewJobShowCmdF),
+}
+
+func init() {
+	PreviewRegenerateCmd.Flags().Int64("from", 0, "The timestamp of the earliest file to process, expressed in seconds since the unix epoch.")
+	PreviewRegenerateCmd.Flags().Int64("to", 0, "The timestamp of the latest file to process, expressed in seconds since the unix epoch. Defaults to the current time.")
+	PreviewJobListCmd.Flags().Int("page", 0, "Page number to fetch for the list of preview regeneration jobs")
+	PreviewJobListCmd.Flags().Int("per-page", DefaultPageSize, "Number of preview regeneration jobs to be fetched")
+	PreviewJobListCmd.Flags().Bool("all", false, "Fetch all preview regeneration jobs. --page flag will be ignore if provided")
+	PreviewJobCmd.AddCommand(
+		PreviewJobListCmd,
+		PreviewJobShowCmd,
+	)
+	PreviewCmd.AddCommand(
+		PreviewRegenerateCmd,
+		PreviewJobCmd,
+	)
+	RootCmd.AddCommand(PreviewCmd)
+}
+
+func previewRegenerateCmdF(c client.Client, command *cobra.Command, args []string) error {
+	from, err := command.Flags().GetInt64("from")
+	if err != nil {
+		return err
+	}
+	to, err := command.Flags().GetInt64("to")
+	if err != nil {
+		return err
+	}
+	if to == 0 {
+		to = model.GetMillis() / 1000
+	}
+	if from > to {
+		return fmt.Errorf("the --from timestamp must be before the --to timestamp")
+	}
+
+	job, _, err := c.CreateJob(context.TODO(), &model.Job{
+		Type: model.JobTypeRegenerateFilePreviews,
+		Data: map[string]string{
+			"from": strconv.FormatInt(from, 10),
+			"to":   strconv.FormatInt(to, 10),
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create preview regeneration job: %w", err)
+	}
+
+	printer.PrintT("Preview regeneration job successfully created, ID: {{.Id}}", job)
+
+	return nil
+}
+
+func previewJobShowCmdF(c client.Client, command *cobra.Command, args []string) error {
+	job, _, err := c.GetJob(context.TODO(), args[0])
+	if err != nil {
+		return fmt.Errorf("failed to get preview regeneration job: %w", err)
+	}
+	printPreviewJob(job)
+	return nil
+}
+
+func previewJobListCmdF(c client.Client, command *cobra.Command, args []string) error {
+	return jobListCmdF(c, command, model.JobTypeRegenerateFilePreviews, "")
+}
+
+func printPreviewJob(job *model.Job) {
+	if job.StartAt > 0 {
+		printer.PrintT(fmt.Sprintf("  ID: {{.Id}}\n  Status: {{.Status}}\n  Created: %s\n  Started: %s\n  Processed: %s\n  Regenerated: %s\n  Errors: %s\n",
+			time.Unix(job.CreateAt/1000, 0), time.Unix(job.StartAt/1000, 0), job.Data["processed"], job.Data["regenerated"], job.Data["errors"]), job)
+	} else {
+		printer.PrintT(fmt.Sprintf("  ID: {{.Id}}\n  Status: {{.Status}}\n  Created: %s\n\n",
+			time.Unix(job.CreateAt/1000, 0)), job)
+	}
+}
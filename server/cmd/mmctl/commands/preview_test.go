@@ -0,0 +1,115 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package commands
+
+import (
+	"context"
+	"errors"
+
+	"github.com/mattermost/mattermost/server/public/model"
+
+	"github.com/mattermost/mattermost/server/v8/cmd/mmctl/printer"
+
+	"github.com/spf13/cobra"
+)
+
+func newPreviewRegenerateTestCmd() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Flags().Int64("from", 0, "")
+	cmd.Flags().Int64("to", 0, "")
+	return cmd
+}
+
+func (s *MmctlUnitTestSuite) TestPreviewRegenerateCmdF() {
+	s.Run("regenerate previews of a time range", func() {
+		printer.Clean()
+		mockJob := &model.Job{
+			Type: model.JobTypeRegenerateFilePreviews,
+			Data: map[string]string{
+				"from": "1704067200",
+				"to":   "1704153600",
+			},
+		}
+
+		cmd := newPreviewRegenerateTestCmd()
+		s.Require().NoError(cmd.Flags().Set("from", "1704067200"))
+		s.Require().NoError(cmd.Flags().Set("to", "1704153600"))
+
+		s.client.
+			EXPECT().
+			CreateJob(context.TODO(), mockJob).
+			Return(mockJob, &model.Response{}, nil).
+			Times(1)
+
+		err := previewRegenerateCmdF(s.client, cmd, nil)
+		s.Require().NoError(err)
+		s.Len(printer.GetLines(), 1)
+		s.Empty(printer.GetErrorLines())
+		s.Equal(mockJob, printer.GetLines()[0].(*model.Job))
+	})
+
+	s.Run("invalid time range", func() {
+		printer.Clean()
+
+		cmd := newPreviewRegenerateTestCmd()
+		s.Require().NoError(cmd.Flags().Set("from", "1704153600"))
+		s.Require().NoError(cmd.Flags().Set("to", "1704067200"))
+
+		err := previewRegenerateCmdF(s.client, cmd, nil)
+		s.Require().Error(err)
+		s.Empty(printer.GetLines())
+	})
+
+	s.Run("failed to create job", func() {
+		printer.Clean()
+
+		cmd := newPreviewRegenerateTestCmd()
+		s.Require().NoError(cmd.Flags().Set("to", "1704153600"))
+
+		s.client.
+			EXPECT().
+			CreateJob(context.TODO(), &model.Job{
+				Type: model.JobTypeRegenerateFilePreviews,
+				Data: map[string]string{
+					"from": "0",
+					"to":   "1704153600",
+				},
+			}).
+			Return(nil, &model.Response{}, errors.New("mock error")).
+			Times(1)
+
+		err := previewRegenerateCmdF(s.client, cmd, nil)
+		s.Require().EqualError(err, "failed to create preview regeneration job: mock error")
+		s.Empty(printer.GetLines())
+	})
+}
+
+func (s *MmctlUnitTestSuite) TestPreviewJobShowCmdF() {
+	s.Run("show job", func() {
+		printer.Clean()
+		mockJob := &model.Job{
+			Id:       model.NewId(),
+			Type:     model.JobTypeRegenerateFilePreviews,
+			Status:   model.JobStatusSuccess,
+			CreateAt: 1704067200000,
+			StartAt:  1704067201000,
+			Data: map[string]string{
+				"processed":   "10",
+				"regenerated": "4",
+				"errors":      "1",
+			},
+		}
+
+		s.client.
+			EXPECT().
+			GetJob(context.TODO(), mockJob.Id).
+			Return(mockJob, &model.Response{}, nil).
+			Times(1)
+
+		err := previewJobShowCmdF(s.client, &cobra.Command{}, []string{mockJob.Id})
+		s.Require().NoError(err)
+		s.Require().Len(printer.GetLines(), 1)
+		s.Equal(mockJob, printer.GetLines()[0].(*model.Job))
+	})
+}
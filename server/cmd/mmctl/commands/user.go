@@ -133,11 +133,38 @@ var ResetUserMfaCmd = &cobra.Command{
 	Use:   "resetmfa [users]",
 	Short: "Turn off MFA",
 	Long: `Turn off multi-factor authentication for a user.
-If MFA enforcement is enabled, the user will be forced to re-enable MFA as soon as they log in.`,
+If MFA enforcement is enabled, the user will be forced to re-enable MFA as soon as they log in.
+WebAuthn credentials registered by the user are removed as well.`,
 	Example: "  user resetmfa user@example.com",
 	RunE:    withClient(resetUserMfaCmdF),
 }
 
+var UserWebAuthnCmd = &cobra.Command{
+	Use:   "webauthn",
+	Short: "Management of WebAuthn credentials",
+	Long:  "Management of the security keys and passkeys that users registered as a second authentication factor.",
+}
+
+var ListUserWebAuthnCmd = &cobra.Command{
+	Use:     "list [user]",
+	Short:   "List WebAuthn credentials",
+	Long:    "List the WebAuthn credentials registered by a user.",
+	Example: "  user webauthn list user@example.com",
+	Args:    cobra.ExactArgs(1),
+	RunE:    withClient(listUserWebAuthnCmdF),
+}
+
+var RevokeUserWebAuthnCmd = &cobra.Command{
+	Use:   "revoke [user] [credentialIds]",
+	Short: "Revoke WebAuthn credentials",
+	Long: `Revoke WebAuthn credentials registered by a user.
+Revoking the last credential of a user without a one-time password turns off MFA for them.`,
+	Example: `  user webauthn revoke user@example.com 7ikqfebj8bg1tgxjahh8gfngph
+  user webauthn revoke user@example.com --all`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: withClient(revokeUserWebAuthnCmdF),
+}
+
 var DeleteUsersCmd = &cobra.Command{
 	Use:   "delete [users]",
 	Short: "Delete users",
@@ -382,6 +409,8 @@ Global Flags:
 {{.InheritedFlags.FlagUsages | trimTrailingWhitespaces}}
 `)
 
+	RevokeUserWebAuthnCmd.Flags().Bool("all", false, "Revoke all the WebAuthn credentials of the user")
+
 	PreferenceListCmd.Flags().StringP("category", "c", "", "The optional category by which to filter")
 	PreferenceGetCmd.Flags().StringP("category", "c", "", "The category of the preference")
 	PreferenceGetCmd.Flags().StringP("name", "n", "", "The name of the preference")
@@ -408,6 +437,7 @@ Global Flags:
 		UpdateUsernameCmd,
 		ChangePasswordUserCmd,
 		ResetUserMfaCmd,
+		UserWebAuthnCmd,
 		DeleteUsersCmd,
 		DeleteAllUsersCmd,
 		SearchUserCmd,
@@ -419,6 +449,10 @@ Global Flags:
 		DemoteUserToGuestCmd,
 		PreferenceCmd,
 	)
+	UserWebAuthnCmd.AddCommand(
+		ListUserWebAuthnCmd,
+		RevokeUserWebAuthnCmd,
+	)
 	PreferenceCmd.AddCommand(
 		PreferenceListCmd,
 		PreferenceGetCmd,
@@ -723,6 +757,59 @@ func resetUserMfaCmdF(c client.Client, cmd *cobra.Command, args []string) error
 	return result.ErrorOrNil()
 }
 
+func listUserWebAuthnCmdF(c client.Client, cmd *cobra.Command, args []string) error {
+	user, err := getUserFromArg(c, args[0])
+	if err != nil {
+		return err
+	}
+
+	credentials, _, err := c.GetWebAuthnCredentials(context.TODO(), user.Id)
+	if err != nil {
+		return fmt.Errorf("unable to get the WebAuthn credentials of user %q. Error: %w", args[0], err)
+	}
+
+	for _, credential := range credentials {
+		printer.PrintT("{{.Id}}: {{.Name}}", credential)
+	}
+
+	return nil
+}
+
+func revokeUserWebAuthnCmdF(c client.Client, cmd *cobra.Command, args []string) error {
+	credentialIDs := args[1:]
+
+	all, _ := cmd.Flags().GetBool("all")
+	if all == (len(credentialIDs) > 0) {
+		return errors.New("either specify the credentials to revoke or use the --all flag")
+	}
+
+	user, err := getUserFromArg(c, args[0])
+	if err != nil {
+		return err
+	}
+
+	if all {
+		credentials, _, err := c.GetWebAuthnCredentials(context.TODO(), user.Id)
+		if err != nil {
+			return fmt.Errorf("unable to get the WebAuthn credentials of user %q. Error: %w", args[0], err)
+		}
+		for _, credential := range credentials {
+			credentialIDs = append(credentialIDs, credential.Id)
+		}
+	}
+
+	var result *multierror.Error
+	for _, credentialID := range credentialIDs {
+		if _, err := c.DeleteWebAuthnCredential(context.TODO(), user.Id, credentialID); err != nil {
+			result = multierror.Append(result, fmt.Errorf("unable to revoke WebAuthn credential %q. Error: %w", credentialID, err))
+			continue
+		}
+		printer.Print("WebAuthn credential " + credentialID + " revoked")
+	}
+
+	return result.ErrorOrNil()
+}
+
 func deleteUsersCmdF(c client.Client, cmd *cobra.Command, args []string) error {
 	confirmFlag, _ := cmd.Flags().GetBool("confirm")
 	if !confirmFlag {
@@ -1864,6 +1864,134 @@ func (s *MmctlUnitTestSuite) TestResetUserMfaCmd() {
 	})
 }
 
+func (s *MmctlUnitTestSuite) TestListUserWebAuthnCmd() {
+	s.Run("List the credentials of a user", func() {
+		printer.Clean()
+		credentials := []*model.WebAuthnCredential{
+			{Id: "credential1", Name: "YubiKey"},
+			{Id: "credential2", Name: "Laptop"},
+		}
+
+		s.client.
+			EXPECT().
+			GetUserByEmail(context.TODO(), "userId", "").
+			Return(&model.User{Id: "userId"}, nil, nil).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			GetWebAuthnCredentials(context.TODO(), "userId").
+			Return(credentials, &model.Response{StatusCode: http.StatusOK}, nil).
+			Times(1)
+
+		err := listUserWebAuthnCmdF(s.client, &cobra.Command{}, []string{"userId"})
+		s.Require().Nil(err)
+		s.Require().Len(printer.GetLines(), 2)
+		s.Require().Equal(credentials[0], printer.GetLines()[0])
+		s.Require().Equal(credentials[1], printer.GetLines()[1])
+		s.Require().Len(printer.GetErrorLines(), 0)
+	})
+
+	s.Run("Unable to list the credentials", func() {
+		printer.Clean()
+		mockError := errors.New("mock error")
+
+		s.client.
+			EXPECT().
+			GetUserByEmail(context.TODO(), "userId", "").
+			Return(&model.User{Id: "userId"}, nil, nil).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			GetWebAuthnCredentials(context.TODO(), "userId").
+			Return(nil, &model.Response{StatusCode: http.StatusBadRequest}, mockError).
+			Times(1)
+
+		err := listUserWebAuthnCmdF(s.client, &cobra.Command{}, []string{"userId"})
+		s.Require().EqualError(err, "unable to get the WebAuthn credentials of user \"userId\". Error: mock error")
+		s.Require().Len(printer.GetLines(), 0)
+	})
+}
+
+func (s *MmctlUnitTestSuite) TestRevokeUserWebAuthnCmd() {
+	s.Run("Revoke some credentials, one of which fails", func() {
+		printer.Clean()
+		mockError := errors.New("mock error")
+
+		s.client.
+			EXPECT().
+			GetUserByEmail(context.TODO(), "userId", "").
+			Return(&model.User{Id: "userId"}, nil, nil).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			DeleteWebAuthnCredential(context.TODO(), "userId", "credential1").
+			Return(&model.Response{StatusCode: http.StatusOK}, nil).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			DeleteWebAuthnCredential(context.TODO(), "userId", "credential2").
+			Return(&model.Response{StatusCode: http.StatusNotFound}, mockError).
+			Times(1)
+
+		cmd := &cobra.Command{}
+		cmd.Flags().Bool("all", false, "")
+
+		err := revokeUserWebAuthnCmdF(s.client, cmd, []string{"userId", "credential1", "credential2"})
+
+		var expected error
+		expected = multierror.Append(expected, fmt.Errorf("unable to revoke WebAuthn credential \"credential2\". Error: "+mockError.Error()))
+
+		s.Require().EqualError(err, expected.Error())
+		s.Require().Len(printer.GetLines(), 1)
+		s.Require().Equal("WebAuthn credential credential1 revoked", printer.GetLines()[0])
+	})
+
+	s.Run("Revoke all the credentials", func() {
+		printer.Clean()
+
+		s.client.
+			EXPECT().
+			GetUserByEmail(context.TODO(), "userId", "").
+			Return(&model.User{Id: "userId"}, nil, nil).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			GetWebAuthnCredentials(context.TODO(), "userId").
+			Return([]*model.WebAuthnCredential{{Id: "credential1"}, {Id: "credential2"}}, &model.Response{StatusCode: http.StatusOK}, nil).
+			Times(1)
+
+		for _, credentialID := range []string{"credential1", "credential2"} {
+			s.client.
+				EXPECT().
+				DeleteWebAuthnCredential(context.TODO(), "userId", credentialID).
+				Return(&model.Response{StatusCode: http.StatusOK}, nil).
+				Times(1)
+		}
+
+		cmd := &cobra.Command{}
+		cmd.Flags().Bool("all", true, "")
+
+		err := revokeUserWebAuthnCmdF(s.client, cmd, []string{"userId"})
+		s.Require().Nil(err)
+		s.Require().Len(printer.GetLines(), 2)
+	})
+
+	s.Run("Neither credentials nor --all", func() {
+		printer.Clean()
+
+		cmd := &cobra.Command{}
+		cmd.Flags().Bool("all", false, "")
+
+		err := revokeUserWebAuthnCmdF(s.client, cmd, []string{"userId"})
+		s.Require().EqualError(err, "either specify the credentials to revoke or use the --all flag")
+	})
+}
+
 func (s *MmctlUnitTestSuite) TestListUserCmdF() {
 	s.Run("Listing users with paging", func() {
 		printer.Clean()
@@ -11,6 +11,7 @@ import (
 	"github.com/mattermost/mattermost/server/v8/cmd/mmctl/client"
 	"github.com/mattermost/mattermost/server/v8/cmd/mmctl/printer"
 
+	"github.com/hashicorp/go-multierror"
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 )
@@ -86,6 +87,40 @@ var DeleteWebhookCmd = &cobra.Command{
 	RunE:    withClient(deleteWebhookCmdF),
 }
 
+var RotateWebhookSecretCmd = &cobra.Command{
+	Use:     "rotate-secret [webhookId]",
+	Short:   "Rotate the signing secret of an outgoing webhook",
+	Long:    "Generate a new secret used to sign the requests sent by the outgoing webhook. The previous secret stops working immediately.",
+	Args:    cobra.ExactArgs(1),
+	Example: "  webhook rotate-secret w16zb5tu3n1zkqo18goqry1je",
+	RunE:    withClient(rotateWebhookSecretCmdF),
+}
+
+var WebhookDeliveriesCmd = &cobra.Command{
+	Use:   "deliveries",
+	Short: "Management of failed outgoing webhook deliveries",
+	Long:  "Outgoing webhook requests that fail are retried with an increasing delay. Deliveries that keep failing are moved to a dead-letter list, where they can be inspected and replayed.",
+}
+
+var ListWebhookDeliveriesCmd = &cobra.Command{
+	Use:     "list [webhookId]",
+	Short:   "List dead-lettered deliveries",
+	Long:    "List the deliveries of the outgoing webhook that failed too many times and were moved to the dead-letter list",
+	Args:    cobra.ExactArgs(1),
+	Example: "  webhook deliveries list w16zb5tu3n1zkqo18goqry1je",
+	RunE:    withClient(listWebhookDeliveriesCmdF),
+}
+
+var ReplayWebhookDeliveriesCmd = &cobra.Command{
+	Use:   "replay [webhookId] [deliveryIds]",
+	Short: "Replay dead-lettered deliveries",
+	Long:  "Move dead-lettered deliveries of the outgoing webhook back to the retry queue, where they are sent again within a minute",
+	Example: `  webhook deliveries replay w16zb5tu3n1zkqo18goqry1je 7ikqfebj8bg1tgxjahh8gfngph
+  webhook deliveries replay w16zb5tu3n1zkqo18goqry1je --all`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: withClient(replayWebhookDeliveriesCmdF),
+}
+
 func listWebhookCmdF(c client.Client, command *cobra.Command, args []string) error {
 	var teams []*model.Team
 
@@ -428,6 +463,71 @@ func showWebhookCmdF(c client.Client, command *cobra.Command, args []string) err
 	return errors.New("Webhook with id '" + webhookID + "' not found")
 }
 
+func rotateWebhookSecretCmdF(c client.Client, command *cobra.Command, args []string) error {
+	printer.SetSingle(true)
+
+	webhookID := args[0]
+	hook, _, err := c.RegenOutgoingHookSigningSecret(context.TODO(), webhookID)
+	if err != nil {
+		printer.PrintError("Unable to rotate the signing secret of webhook '" + webhookID + "'")
+		return err
+	}
+
+	printer.PrintT("Webhook {{.Id}} signing secret rotated: {{.SigningSecret}}", hook)
+	return nil
+}
+
+func listWebhookDeliveriesCmdF(c client.Client, command *cobra.Command, args []string) error {
+	webhookID := args[0]
+	deliveries, err := getPages(func(page, numPerPage int, etag string) ([]*model.OutgoingWebhookDelivery, *model.Response, error) {
+		return c.GetOutgoingWebhookDeadLetters(context.TODO(), webhookID, page, numPerPage)
+	}, DefaultPageSize)
+	if err != nil {
+		return errors.Wrapf(err, "unable to get the dead-lettered deliveries of webhook '%s'", webhookID)
+	}
+
+	for _, delivery := range deliveries {
+		printer.PrintT("{{.Id}}: {{.CallbackURL}} failed {{.Attempts}} times, last error: {{.LastError}}", delivery)
+	}
+
+	return nil
+}
+
+func replayWebhookDeliveriesCmdF(c client.Client, command *cobra.Command, args []string) error {
+	webhookID := args[0]
+	deliveryIDs := args[1:]
+
+	all, _ := command.Flags().GetBool("all")
+	if all == (len(deliveryIDs) > 0) {
+		return errors.New("either specify the deliveries to replay or use the --all flag")
+	}
+
+	if all {
+		deliveries, err := getPages(func(page, numPerPage int, etag string) ([]*model.OutgoingWebhookDelivery, *model.Response, error) {
+			return c.GetOutgoingWebhookDeadLetters(context.TODO(), webhookID, page, numPerPage)
+		}, DefaultPageSize)
+		if err != nil {
+			return errors.Wrapf(err, "unable to get the dead-lettered deliveries of webhook '%s'", webhookID)
+		}
+		for _, delivery := range deliveries {
+			deliveryIDs = append(deliveryIDs, delivery.Id)
+		}
+	}
+
+	var result *multierror.Error
+	for _, deliveryID := range deliveryIDs {
+		delivery, _, err := c.ReplayOutgoingWebhookDelivery(context.TODO(), webhookID, deliveryID)
+		if err != nil {
+			printer.PrintError("Unable to replay delivery '" + deliveryID + "'")
+			result = multierror.Append(result, errors.Wrapf(err, "unable to replay delivery '%s'", deliveryID))
+			continue
+		}
+		printer.PrintT("Delivery {{.Id}} queued for replay", delivery)
+	}
+
+	return result.ErrorOrNil()
+}
+
 func init() {
 	CreateIncomingWebhookCmd.Flags().String("channel", "", "Channel ID (required)")
 	_ = CreateIncomingWebhookCmd.MarkFlagRequired("channel")
@@ -469,6 +569,13 @@ func init() {
 	ModifyOutgoingWebhookCmd.Flags().StringArray("url", []string{}, "Callback URL")
 	ModifyOutgoingWebhookCmd.Flags().String("content-type", "", "Content-type")
 
+	ReplayWebhookDeliveriesCmd.Flags().Bool("all", false, "Replay all the dead-lettered deliveries of the webhook")
+
+	WebhookDeliveriesCmd.AddCommand(
+		ListWebhookDeliveriesCmd,
+		ReplayWebhookDeliveriesCmd,
+	)
+
 	WebhookCmd.AddCommand(
 		ListWebhookCmd,
 		CreateIncomingWebhookCmd,
@@ -477,6 +584,8 @@ func init() {
 		ModifyOutgoingWebhookCmd,
 		DeleteWebhookCmd,
 		ShowWebhookCmd,
+		RotateWebhookSecretCmd,
+		WebhookDeliveriesCmd,
 	)
 
 	RootCmd.AddCommand(WebhookCmd)
@@ -788,3 +788,184 @@ func (s *MmctlUnitTestSuite) TestShowWebhookCmd() {
 		s.Require().Equal("Webhook with id '"+nonExistentID+"' not found", err.Error())
 	})
 }
+
+func (s *MmctlUnitTestSuite) TestRotateWebhookSecretCmd() {
+	outgoingWebhookID := "outgoingWebhookID"
+
+	s.Run("Successfully rotate the signing secret", func() {
+		printer.Clean()
+
+		mockOutgoingWebhook := model.OutgoingWebhook{Id: outgoingWebhookID, SigningSecret: "newsecret"}
+
+		s.client.
+			EXPECT().
+			RegenOutgoingHookSigningSecret(context.TODO(), outgoingWebhookID).
+			Return(&mockOutgoingWebhook, &model.Response{}, nil).
+			Times(1)
+
+		err := rotateWebhookSecretCmdF(s.client, &cobra.Command{}, []string{outgoingWebhookID})
+		s.Require().Nil(err)
+		s.Len(printer.GetLines(), 1)
+		s.Len(printer.GetErrorLines(), 0)
+		s.Require().Equal(&mockOutgoingWebhook, printer.GetLines()[0])
+	})
+
+	s.Run("rotate signing secret error", func() {
+		printer.Clean()
+
+		mockError := errors.New("mock error")
+
+		s.client.
+			EXPECT().
+			RegenOutgoingHookSigningSecret(context.TODO(), outgoingWebhookID).
+			Return(nil, &model.Response{}, mockError).
+			Times(1)
+
+		err := rotateWebhookSecretCmdF(s.client, &cobra.Command{}, []string{outgoingWebhookID})
+		s.Require().Equal(mockError, err)
+		s.Len(printer.GetLines(), 0)
+		s.Len(printer.GetErrorLines(), 1)
+		s.Require().Equal("Unable to rotate the signing secret of webhook '"+outgoingWebhookID+"'", printer.GetErrorLines()[0])
+	})
+}
+
+func (s *MmctlUnitTestSuite) TestListWebhookDeliveriesCmd() {
+	outgoingWebhookID := model.NewId()
+
+	s.Run("Successfully list dead-lettered deliveries", func() {
+		printer.Clean()
+
+		mockDelivery := model.OutgoingWebhookDelivery{Id: model.NewId(), HookId: outgoingWebhookID, CallbackURL: "http://nowhere.com", Attempts: 6, LastError: "connection refused"}
+
+		s.client.
+			EXPECT().
+			GetOutgoingWebhookDeadLetters(context.TODO(), outgoingWebhookID, 0, DefaultPageSize).
+			Return([]*model.OutgoingWebhookDelivery{&mockDelivery}, &model.Response{}, nil).
+			Times(1)
+		s.client.
+			EXPECT().
+			GetOutgoingWebhookDeadLetters(context.TODO(), outgoingWebhookID, 1, DefaultPageSize).
+			Return([]*model.OutgoingWebhookDelivery{}, &model.Response{}, nil).
+			Times(1)
+
+		err := listWebhookDeliveriesCmdF(s.client, &cobra.Command{}, []string{outgoingWebhookID})
+		s.Require().Nil(err)
+		s.Len(printer.GetLines(), 1)
+		s.Len(printer.GetErrorLines(), 0)
+		s.Require().Equal(&mockDelivery, printer.GetLines()[0])
+	})
+
+	s.Run("list dead-lettered deliveries error", func() {
+		printer.Clean()
+
+		mockError := errors.New("mock error")
+
+		s.client.
+			EXPECT().
+			GetOutgoingWebhookDeadLetters(context.TODO(), outgoingWebhookID, 0, DefaultPageSize).
+			Return(nil, &model.Response{}, mockError).
+			Times(1)
+
+		err := listWebhookDeliveriesCmdF(s.client, &cobra.Command{}, []string{outgoingWebhookID})
+		s.Require().ErrorIs(err, mockError)
+		s.Len(printer.GetLines(), 0)
+	})
+}
+
+func (s *MmctlUnitTestSuite) TestReplayWebhookDeliveriesCmd() {
+	outgoingWebhookID := model.NewId()
+	deliveryID := model.NewId()
+
+	s.Run("Successfully replay a delivery", func() {
+		printer.Clean()
+
+		mockDelivery := model.OutgoingWebhookDelivery{Id: deliveryID, HookId: outgoingWebhookID, Status: model.OutgoingWebhookDeliveryStatusPending}
+
+		s.client.
+			EXPECT().
+			ReplayOutgoingWebhookDelivery(context.TODO(), outgoingWebhookID, deliveryID).
+			Return(&mockDelivery, &model.Response{}, nil).
+			Times(1)
+
+		cmd := &cobra.Command{}
+		cmd.Flags().Bool("all", false, "")
+
+		err := replayWebhookDeliveriesCmdF(s.client, cmd, []string{outgoingWebhookID, deliveryID})
+		s.Require().Nil(err)
+		s.Len(printer.GetLines(), 1)
+		s.Len(printer.GetErrorLines(), 0)
+		s.Require().Equal(&mockDelivery, printer.GetLines()[0])
+	})
+
+	s.Run("Successfully replay all deliveries", func() {
+		printer.Clean()
+
+		otherDeliveryID := model.NewId()
+
+		s.client.
+			EXPECT().
+			GetOutgoingWebhookDeadLetters(context.TODO(), outgoingWebhookID, 0, DefaultPageSize).
+			Return([]*model.OutgoingWebhookDelivery{{Id: deliveryID}, {Id: otherDeliveryID}}, &model.Response{}, nil).
+			Times(1)
+		s.client.
+			EXPECT().
+			GetOutgoingWebhookDeadLetters(context.TODO(), outgoingWebhookID, 1, DefaultPageSize).
+			Return([]*model.OutgoingWebhookDelivery{}, &model.Response{}, nil).
+			Times(1)
+		s.client.
+			EXPECT().
+			ReplayOutgoingWebhookDelivery(context.TODO(), outgoingWebhookID, deliveryID).
+			Return(&model.OutgoingWebhookDelivery{Id: deliveryID}, &model.Response{}, nil).
+			Times(1)
+		s.client.
+			EXPECT().
+			ReplayOutgoingWebhookDelivery(context.TODO(), outgoingWebhookID, otherDeliveryID).
+			Return(&model.OutgoingWebhookDelivery{Id: otherDeliveryID}, &model.Response{}, nil).
+			Times(1)
+
+		cmd := &cobra.Command{}
+		cmd.Flags().Bool("all", true, "")
+
+		err := replayWebhookDeliveriesCmdF(s.client, cmd, []string{outgoingWebhookID})
+		s.Require().Nil(err)
+		s.Len(printer.GetLines(), 2)
+		s.Len(printer.GetErrorLines(), 0)
+	})
+
+	s.Run("replay delivery error", func() {
+		printer.Clean()
+
+		mockError := errors.New("mock error")
+
+		s.client.
+			EXPECT().
+			ReplayOutgoingWebhookDelivery(context.TODO(), outgoingWebhookID, deliveryID).
+			Return(nil, &model.Response{}, mockError).
+			Times(1)
+
+		cmd := &cobra.Command{}
+		cmd.Flags().Bool("all", false, "")
+
+		err := replayWebhookDeliveriesCmdF(s.client, cmd, []string{outgoingWebhookID, deliveryID})
+		s.Require().ErrorIs(err, mockError)
+		s.Len(printer.GetLines(), 0)
+		s.Len(printer.GetErrorLines(), 1)
+		s.Require().Equal("Unable to replay delivery '"+deliveryID+"'", printer.GetErrorLines()[0])
+	})
+
+	s.Run("either delivery ids or the all flag are required", func() {
+		printer.Clean()
+
+		cmd := &cobra.Command{}
+		cmd.Flags().Bool("all", false, "")
+
+		err := replayWebhookDeliveriesCmdF(s.client, cmd, []string{outgoingWebhookID})
+		s.Require().Error(err)
+
+		cmd = &cobra.Command{}
+		cmd.Flags().Bool("all", true, "")
+
+		err = replayWebhookDeliveriesCmdF(s.client, cmd, []string{outgoingWebhookID, deliveryID})
+		s.Require().Error(err)
+	})
+}
@@ -432,6 +432,21 @@ func (mr *MockClientMockRecorder) DeletePreferences(arg0, arg1, arg2 interface{}
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeletePreferences", reflect.TypeOf((*MockClient)(nil).DeletePreferences), arg0, arg1, arg2)
 }
 
+// DeleteWebAuthnCredential mocks base method.
+func (m *MockClient) DeleteWebAuthnCredential(arg0 context.Context, arg1, arg2 string) (*model.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteWebAuthnCredential", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*model.Response)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteWebAuthnCredential indicates an expected call of DeleteWebAuthnCredential.
+func (mr *MockClientMockRecorder) DeleteWebAuthnCredential(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteWebAuthnCredential", reflect.TypeOf((*MockClient)(nil).DeleteWebAuthnCredential), arg0, arg1, arg2)
+}
+
 // DemoteUserToGuest mocks base method.
 func (m *MockClient) DemoteUserToGuest(arg0 context.Context, arg1 string) (*model.Response, error) {
 	m.ctrl.T.Helper()
@@ -990,6 +1005,22 @@ func (mr *MockClientMockRecorder) GetOutgoingWebhook(arg0, arg1 interface{}) *go
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOutgoingWebhook", reflect.TypeOf((*MockClient)(nil).GetOutgoingWebhook), arg0, arg1)
 }
 
+// GetOutgoingWebhookDeadLetters mocks base method.
+func (m *MockClient) GetOutgoingWebhookDeadLetters(arg0 context.Context, arg1 string, arg2, arg3 int) ([]*model.OutgoingWebhookDelivery, *model.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOutgoingWebhookDeadLetters", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].([]*model.OutgoingWebhookDelivery)
+	ret1, _ := ret[1].(*model.Response)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetOutgoingWebhookDeadLetters indicates an expected call of GetOutgoingWebhookDeadLetters.
+func (mr *MockClientMockRecorder) GetOutgoingWebhookDeadLetters(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOutgoingWebhookDeadLetters", reflect.TypeOf((*MockClient)(nil).GetOutgoingWebhookDeadLetters), arg0, arg1, arg2, arg3)
+}
+
 // GetOutgoingWebhooks mocks base method.
 func (m *MockClient) GetOutgoingWebhooks(arg0 context.Context, arg1, arg2 int, arg3 string) ([]*model.OutgoingWebhook, *model.Response, error) {
 	m.ctrl.T.Helper()
@@ -1454,6 +1485,22 @@ func (mr *MockClientMockRecorder) GetUsersWithCustomQueryParameters(arg0, arg1,
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUsersWithCustomQueryParameters", reflect.TypeOf((*MockClient)(nil).GetUsersWithCustomQueryParameters), arg0, arg1, arg2, arg3, arg4)
 }
 
+// GetWebAuthnCredentials mocks base method.
+func (m *MockClient) GetWebAuthnCredentials(arg0 context.Context, arg1 string) ([]*model.WebAuthnCredential, *model.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWebAuthnCredentials", arg0, arg1)
+	ret0, _ := ret[0].([]*model.WebAuthnCredential)
+	ret1, _ := ret[1].(*model.Response)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetWebAuthnCredentials indicates an expected call of GetWebAuthnCredentials.
+func (mr *MockClientMockRecorder) GetWebAuthnCredentials(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWebAuthnCredentials", reflect.TypeOf((*MockClient)(nil).GetWebAuthnCredentials), arg0, arg1)
+}
+
 // InstallMarketplacePlugin mocks base method.
 func (m *MockClient) InstallMarketplacePlugin(arg0 context.Context, arg1 *model.InstallMarketplacePluginRequest) (*model.Manifest, *model.Response, error) {
 	m.ctrl.T.Helper()
@@ -1795,6 +1842,38 @@ func (mr *MockClientMockRecorder) PromoteGuestToUser(arg0, arg1 interface{}) *go
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PromoteGuestToUser", reflect.TypeOf((*MockClient)(nil).PromoteGuestToUser), arg0, arg1)
 }
 
+// RegenCommandSigningSecret mocks base method.
+func (m *MockClient) RegenCommandSigningSecret(arg0 context.Context, arg1 string) (string, *model.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RegenCommandSigningSecret", arg0, arg1)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(*model.Response)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// RegenCommandSigningSecret indicates an expected call of RegenCommandSigningSecret.
+func (mr *MockClientMockRecorder) RegenCommandSigningSecret(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RegenCommandSigningSecret", reflect.TypeOf((*MockClient)(nil).RegenCommandSigningSecret), arg0, arg1)
+}
+
+// RegenOutgoingHookSigningSecret mocks base method.
+func (m *MockClient) RegenOutgoingHookSigningSecret(arg0 context.Context, arg1 string) (*model.OutgoingWebhook, *model.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RegenOutgoingHookSigningSecret", arg0, arg1)
+	ret0, _ := ret[0].(*model.OutgoingWebhook)
+	ret1, _ := ret[1].(*model.Response)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// RegenOutgoingHookSigningSecret indicates an expected call of RegenOutgoingHookSigningSecret.
+func (mr *MockClientMockRecorder) RegenOutgoingHookSigningSecret(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RegenOutgoingHookSigningSecret", reflect.TypeOf((*MockClient)(nil).RegenOutgoingHookSigningSecret), arg0, arg1)
+}
+
 // RegenOutgoingHookToken mocks base method.
 func (m *MockClient) RegenOutgoingHookToken(arg0 context.Context, arg1 string) (*model.OutgoingWebhook, *model.Response, error) {
 	m.ctrl.T.Helper()
@@ -1886,6 +1965,22 @@ func (mr *MockClientMockRecorder) RemoveUserFromChannel(arg0, arg1, arg2 interfa
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveUserFromChannel", reflect.TypeOf((*MockClient)(nil).RemoveUserFromChannel), arg0, arg1, arg2)
 }
 
+// ReplayOutgoingWebhookDelivery mocks base method.
+func (m *MockClient) ReplayOutgoingWebhookDelivery(arg0 context.Context, arg1, arg2 string) (*model.OutgoingWebhookDelivery, *model.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReplayOutgoingWebhookDelivery", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*model.OutgoingWebhookDelivery)
+	ret1, _ := ret[1].(*model.Response)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ReplayOutgoingWebhookDelivery indicates an expected call of ReplayOutgoingWebhookDelivery.
+func (mr *MockClientMockRecorder) ReplayOutgoingWebhookDelivery(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReplayOutgoingWebhookDelivery", reflect.TypeOf((*MockClient)(nil).ReplayOutgoingWebhookDelivery), arg0, arg1, arg2)
+}
+
 // ResetSamlAuthDataToEmail mocks base method.
 func (m *MockClient) ResetSamlAuthDataToEmail(arg0 context.Context, arg1, arg2 bool, arg3 []string) (int64, *model.Response, error) {
 	m.ctrl.T.Helper()
@@ -1965,6 +2060,22 @@ func (mr *MockClientMockRecorder) RevokeUserAccessToken(arg0, arg1 interface{})
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeUserAccessToken", reflect.TypeOf((*MockClient)(nil).RevokeUserAccessToken), arg0, arg1)
 }
 
+// SearchAuditRecords mocks base method.
+func (m *MockClient) SearchAuditRecords(arg0 context.Context, arg1 *model.AuditRecordSearch) ([]*model.AuditRecord, *model.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SearchAuditRecords", arg0, arg1)
+	ret0, _ := ret[0].([]*model.AuditRecord)
+	ret1, _ := ret[1].(*model.Response)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// SearchAuditRecords indicates an expected call of SearchAuditRecords.
+func (mr *MockClientMockRecorder) SearchAuditRecords(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchAuditRecords", reflect.TypeOf((*MockClient)(nil).SearchAuditRecords), arg0, arg1)
+}
+
 // SearchTeams mocks base method.
 func (m *MockClient) SearchTeams(arg0 context.Context, arg1 *model.TeamSearch) ([]*model.Team, *model.Response, error) {
 	m.ctrl.T.Helper()
@@ -141,6 +141,14 @@ func GenerateClientConfig(c *model.Config, telemetryID string, license *model.Li
 	props["PersistentNotificationIntervalMinutes"] = strconv.FormatInt(int64(*c.ServiceSettings.PersistentNotificationIntervalMinutes), 10)
 	props["PersistentNotificationMaxRecipients"] = strconv.FormatInt(int64(*c.ServiceSettings.PersistentNotificationMaxRecipients), 10)
 	props["AllowSyncedDrafts"] = strconv.FormatBool(*c.ServiceSettings.AllowSyncedDrafts)
+	props["ScheduledPosts"] = strconv.FormatBool(*c.ServiceSettings.ScheduledPosts)
+	props["EnableSavedSearches"] = strconv.FormatBool(*c.ServiceSettings.EnableSavedSearches)
+	props["EnablePostExpiry"] = strconv.FormatBool(*c.ServiceSettings.EnablePostExpiry)
+	props["EnableReadReceipts"] = strconv.FormatBool(*c.ServiceSettings.EnableReadReceipts)
+	props["ReadReceiptsMaxChannelMembers"] = strconv.FormatInt(int64(*c.ServiceSettings.ReadReceiptsMaxChannelMembers), 10)
+	props["EnablePostTranslation"] = strconv.FormatBool(*c.TranslationSettings.Enable)
+	props["EnableVirusScan"] = strconv.FormatBool(*c.VirusScanSettings.Enable)
+	props["EnableSemanticSearch"] = strconv.FormatBool(*c.SemanticSearchSettings.EnableSearching)
 	props["DelayChannelAutocomplete"] = strconv.FormatBool(*c.ExperimentalSettings.DelayChannelAutocomplete)
 	props["YoutubeReferrerPolicy"] = strconv.FormatBool(*c.ExperimentalSettings.YoutubeReferrerPolicy)
 	props["UniqueEmojiReactionLimitPerPost"] = strconv.FormatInt(int64(*c.ServiceSettings.UniqueEmojiReactionLimitPerPost), 10)
@@ -33,6 +33,27 @@ func desanitize(actual, target *model.Config) {
 	if *target.FileSettings.AmazonS3SecretAccessKey == model.FakeSetting {
 		target.FileSettings.AmazonS3SecretAccessKey = actual.FileSettings.AmazonS3SecretAccessKey
 	}
+	if *target.FileSettings.AzureAccountKey == model.FakeSetting {
+		target.FileSettings.AzureAccountKey = actual.FileSettings.AzureAccountKey
+	}
+	if *target.FileSettings.GCSServiceAccountKey == model.FakeSetting {
+		target.FileSettings.GCSServiceAccountKey = actual.FileSettings.GCSServiceAccountKey
+	}
+	if *target.FileSettings.EncryptionKeys == model.FakeSetting {
+		target.FileSettings.EncryptionKeys = actual.FileSettings.EncryptionKeys
+	}
+
+	if *target.SemanticSearchSettings.EmbeddingAPIKey == model.FakeSetting {
+		target.SemanticSearchSettings.EmbeddingAPIKey = actual.SemanticSearchSettings.EmbeddingAPIKey
+	}
+
+	if *target.TranslationSettings.APIKey == model.FakeSetting {
+		target.TranslationSettings.APIKey = actual.TranslationSettings.APIKey
+	}
+
+	if *target.ScimSettings.Token == model.FakeSetting {
+		target.ScimSettings.Token = actual.ScimSettings.Token
+	}
 
 	if *target.EmailSettings.SMTPPassword == model.FakeSetting {
 		target.EmailSettings.SMTPPassword = actual.EmailSettings.SMTPPassword
@@ -25,6 +25,12 @@ func TestDesanitize(t *testing.T) {
 	actual.LdapSettings.BindPassword = model.NewPointer("bind_password")
 	actual.FileSettings.PublicLinkSalt = model.NewPointer("public_link_salt")
 	actual.FileSettings.AmazonS3SecretAccessKey = model.NewPointer("amazon_s3_secret_access_key")
+	actual.FileSettings.AzureAccountKey = model.NewPointer("azure_account_key")
+	actual.FileSettings.GCSServiceAccountKey = model.NewPointer("gcs_service_account_key")
+	actual.FileSettings.EncryptionKeys = model.NewPointer("encryption_keys")
+	actual.SemanticSearchSettings.EmbeddingAPIKey = model.NewPointer("embedding_api_key")
+	actual.ScimSettings.Token = model.NewPointer("scim_token")
+	actual.TranslationSettings.APIKey = model.NewPointer("translation_api_key")
 	actual.EmailSettings.SMTPPassword = model.NewPointer("smtp_password")
 	actual.GitLabSettings.Secret = model.NewPointer("secret")
 	actual.OpenIdSettings.Secret = model.NewPointer("secret")
@@ -47,6 +53,12 @@ func TestDesanitize(t *testing.T) {
 	target.LdapSettings.BindPassword = model.NewPointer(model.FakeSetting)
 	target.FileSettings.PublicLinkSalt = model.NewPointer(model.FakeSetting)
 	target.FileSettings.AmazonS3SecretAccessKey = model.NewPointer(model.FakeSetting)
+	target.FileSettings.AzureAccountKey = model.NewPointer(model.FakeSetting)
+	target.FileSettings.GCSServiceAccountKey = model.NewPointer(model.FakeSetting)
+	target.FileSettings.EncryptionKeys = model.NewPointer(model.FakeSetting)
+	target.SemanticSearchSettings.EmbeddingAPIKey = model.NewPointer(model.FakeSetting)
+	target.ScimSettings.Token = model.NewPointer(model.FakeSetting)
+	target.TranslationSettings.APIKey = model.NewPointer(model.FakeSetting)
 	target.EmailSettings.SMTPPassword = model.NewPointer(model.FakeSetting)
 	target.GitLabSettings.Secret = model.NewPointer(model.FakeSetting)
 	target.OpenIdSettings.Secret = model.NewPointer(model.FakeSetting)
@@ -68,6 +80,12 @@ func TestDesanitize(t *testing.T) {
 	assert.Equal(t, *actual.LdapSettings.BindPassword, *target.LdapSettings.BindPassword)
 	assert.Equal(t, *actual.FileSettings.PublicLinkSalt, *target.FileSettings.PublicLinkSalt)
 	assert.Equal(t, *actual.FileSettings.AmazonS3SecretAccessKey, *target.FileSettings.AmazonS3SecretAccessKey)
+	assert.Equal(t, *actual.FileSettings.AzureAccountKey, *target.FileSettings.AzureAccountKey)
+	assert.Equal(t, *actual.FileSettings.GCSServiceAccountKey, *target.FileSettings.GCSServiceAccountKey)
+	assert.Equal(t, *actual.FileSettings.EncryptionKeys, *target.FileSettings.EncryptionKeys)
+	assert.Equal(t, *actual.SemanticSearchSettings.EmbeddingAPIKey, *target.SemanticSearchSettings.EmbeddingAPIKey)
+	assert.Equal(t, *actual.ScimSettings.Token, *target.ScimSettings.Token)
+	assert.Equal(t, *actual.TranslationSettings.APIKey, *target.TranslationSettings.APIKey)
 	assert.Equal(t, *actual.EmailSettings.SMTPPassword, *target.EmailSettings.SMTPPassword)
 	assert.Equal(t, *actual.GitLabSettings.Secret, *target.GitLabSettings.Secret)
 	assert.Equal(t, *actual.OpenIdSettings.Secret, *target.OpenIdSettings.Secret)
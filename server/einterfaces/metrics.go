@@ -28,6 +28,9 @@ type MetricsInterface interface {
 	IncrementClusterRequest()
 	ObserveClusterRequestDuration(elapsed float64)
 	IncrementClusterEventType(eventType model.ClusterEvent)
+	SetClusterQueueSize(queue string, size float64)
+	IncrementClusterDroppedMessages(queue string)
+	IncrementClusterTransportReconnect()
 
 	IncrementLogin()
 	IncrementLoginFail()
@@ -42,6 +45,9 @@ type MetricsInterface interface {
 	IncrementMemCacheHitCounterSession()
 	IncrementMemCacheInvalidationCounterSession()
 
+	IncrementFileCacheHitCounter()
+	IncrementFileCacheMissCounter()
+
 	IncrementWebsocketEvent(eventType model.WebsocketEventType)
 	IncrementWebSocketBroadcast(eventType model.WebsocketEventType)
 	IncrementWebSocketBroadcastBufferSize(hub string, amount float64)
@@ -78,6 +78,11 @@ func (_m *MetricsInterface) IncrementClientLongTasks(platform string, agent stri
 	_m.Called(platform, agent, inc)
 }
 
+// IncrementClusterDroppedMessages provides a mock function with given fields: queue
+func (_m *MetricsInterface) IncrementClusterDroppedMessages(queue string) {
+	_m.Called(queue)
+}
+
 // IncrementClusterEventType provides a mock function with given fields: eventType
 func (_m *MetricsInterface) IncrementClusterEventType(eventType model.ClusterEvent) {
 	_m.Called(eventType)
@@ -88,6 +93,11 @@ func (_m *MetricsInterface) IncrementClusterRequest() {
 	_m.Called()
 }
 
+// IncrementClusterTransportReconnect provides a mock function with given fields:
+func (_m *MetricsInterface) IncrementClusterTransportReconnect() {
+	_m.Called()
+}
+
 // IncrementEtagHitCounter provides a mock function with given fields: route
 func (_m *MetricsInterface) IncrementEtagHitCounter(route string) {
 	_m.Called(route)
@@ -98,6 +108,16 @@ func (_m *MetricsInterface) IncrementEtagMissCounter(route string) {
 	_m.Called(route)
 }
 
+// IncrementFileCacheHitCounter provides a mock function with given fields:
+func (_m *MetricsInterface) IncrementFileCacheHitCounter() {
+	_m.Called()
+}
+
+// IncrementFileCacheMissCounter provides a mock function with given fields:
+func (_m *MetricsInterface) IncrementFileCacheMissCounter() {
+	_m.Called()
+}
+
 // IncrementFileIndexCounter provides a mock function with given fields:
 func (_m *MetricsInterface) IncrementFileIndexCounter() {
 	_m.Called()
@@ -463,6 +483,11 @@ func (_m *MetricsInterface) RegisterDBCollector(db *sql.DB, name string) {
 	_m.Called(db, name)
 }
 
+// SetClusterQueueSize provides a mock function with given fields: queue, size
+func (_m *MetricsInterface) SetClusterQueueSize(queue string, size float64) {
+	_m.Called(queue, size)
+}
+
 // SetReplicaLagAbsolute provides a mock function with given fields: node, value
 func (_m *MetricsInterface) SetReplicaLagAbsolute(node string, value float64) {
 	_m.Called(node, value)
@@ -79,6 +79,10 @@ type MetricsInterfaceImpl struct {
 	ClusterEventTypeCounters *prometheus.CounterVec
 	ClusterEventMap          map[model.ClusterEvent]prometheus.Counter
 
+	ClusterQueueSizeGauge             *prometheus.GaugeVec
+	ClusterDroppedMessagesCounter     *prometheus.CounterVec
+	ClusterTransportReconnectsCounter prometheus.Counter
+
 	LoginCounter     prometheus.Counter
 	LoginFailCounter prometheus.Counter
 
@@ -93,6 +97,9 @@ type MetricsInterfaceImpl struct {
 	MemCacheMissCounterSession         prometheus.Counter
 	MemCacheInvalidationCounterSession prometheus.Counter
 
+	FileCacheHitCounter  prometheus.Counter
+	FileCacheMissCounter prometheus.Counter
+
 	WebsocketEventCounters *prometheus.CounterVec
 
 	WebSocketBroadcastCounters                    *prometheus.CounterVec
@@ -493,11 +500,45 @@ func New(ps *platform.PlatformService, driver, dataSource string) *MetricsInterf
 		model.ClusterEventPluginEvent,
 		model.ClusterEventInvalidateCacheForTermsOfService,
 		model.ClusterEventBusyStateChanged,
+		model.ClusterEventInvalidateFileCache,
 	} {
 		m.ClusterEventMap[event] = m.ClusterEventTypeCounters.With(prometheus.Labels{"name": string(event)})
 	}
 	m.ClusterEventMap[model.ClusterEvent("other")] = m.ClusterEventTypeCounters.With(prometheus.Labels{"name": "other"})
 
+	m.ClusterQueueSizeGauge = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace:   MetricsNamespace,
+			Subsystem:   MetricsSubsystemCluster,
+			Name:        "cluster_queue_size",
+			Help:        "The number of cluster messages waiting to be sent or handled.",
+			ConstLabels: additionalLabels,
+		},
+		[]string{"queue"},
+	)
+	m.Registry.MustRegister(m.ClusterQueueSizeGauge)
+
+	m.ClusterDroppedMessagesCounter = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace:   MetricsNamespace,
+			Subsystem:   MetricsSubsystemCluster,
+			Name:        "cluster_dropped_messages_total",
+			Help:        "The total number of cluster messages dropped because a queue was full.",
+			ConstLabels: additionalLabels,
+		},
+		[]string{"queue"},
+	)
+	m.Registry.MustRegister(m.ClusterDroppedMessagesCounter)
+
+	m.ClusterTransportReconnectsCounter = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace:   MetricsNamespace,
+		Subsystem:   MetricsSubsystemCluster,
+		Name:        "cluster_transport_reconnects_total",
+		Help:        "The total number of times the cluster transport reconnected after losing its subscription.",
+		ConstLabels: additionalLabels,
+	})
+	m.Registry.MustRegister(m.ClusterTransportReconnectsCounter)
+
 	// Login Subsystem
 
 	m.LoginCounter = prometheus.NewCounter(prometheus.CounterOpts{
@@ -583,6 +624,24 @@ func New(ps *platform.PlatformService, driver, dataSource string) *MetricsInterf
 	m.Registry.MustRegister(m.MemCacheInvalidationCounters)
 	m.MemCacheInvalidationCounterSession = m.MemCacheInvalidationCounters.With(prometheus.Labels{"name": "Session"})
 
+	m.FileCacheHitCounter = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace:   MetricsNamespace,
+		Subsystem:   MetricsSubsystemCaching,
+		Name:        "file_hit_total",
+		Help:        "Total number of local file cache hits",
+		ConstLabels: additionalLabels,
+	})
+	m.Registry.MustRegister(m.FileCacheHitCounter)
+
+	m.FileCacheMissCounter = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace:   MetricsNamespace,
+		Subsystem:   MetricsSubsystemCaching,
+		Name:        "file_miss_total",
+		Help:        "Total number of local file cache misses",
+		ConstLabels: additionalLabels,
+	})
+	m.Registry.MustRegister(m.FileCacheMissCounter)
+
 	// Websocket Subsystem
 
 	m.WebSocketBroadcastCounters = prometheus.NewCounterVec(
@@ -1527,6 +1586,18 @@ func (mi *MetricsInterfaceImpl) IncrementClusterEventType(eventType model.Cluste
 	mi.ClusterEventMap[model.ClusterEvent("other")].Inc()
 }
 
+func (mi *MetricsInterfaceImpl) SetClusterQueueSize(queue string, size float64) {
+	mi.ClusterQueueSizeGauge.With(prometheus.Labels{"queue": queue}).Set(size)
+}
+
+func (mi *MetricsInterfaceImpl) IncrementClusterDroppedMessages(queue string) {
+	mi.ClusterDroppedMessagesCounter.With(prometheus.Labels{"queue": queue}).Inc()
+}
+
+func (mi *MetricsInterfaceImpl) IncrementClusterTransportReconnect() {
+	mi.ClusterTransportReconnectsCounter.Inc()
+}
+
 func (mi *MetricsInterfaceImpl) IncrementLogin() {
 	mi.LoginCounter.Inc()
 }
@@ -1567,6 +1638,14 @@ func (mi *MetricsInterfaceImpl) IncrementMemCacheInvalidationCounterSession() {
 	mi.MemCacheInvalidationCounterSession.Inc()
 }
 
+func (mi *MetricsInterfaceImpl) IncrementFileCacheHitCounter() {
+	mi.FileCacheHitCounter.Inc()
+}
+
+func (mi *MetricsInterfaceImpl) IncrementFileCacheMissCounter() {
+	mi.FileCacheMissCounter.Inc()
+}
+
 func (mi *MetricsInterfaceImpl) AddMemCacheMissCounter(cacheName string, amount float64) {
 	mi.MemCacheMissCounters.With(prometheus.Labels{"name": cacheName}).Add(amount)
 }
@@ -116,6 +116,15 @@ func (r *redisProvider) Connect() (string, error) {
 	return res, nil
 }
 
+// RedisClient returns the client of a Redis provider, so that other services can share its
+// connections. It returns nil for the other providers.
+func RedisClient(p Provider) rueidis.Client {
+	if r, ok := p.(*redisProvider); ok {
+		return r.client
+	}
+	return nil
+}
+
 func (r *redisProvider) SetMetrics(metrics einterfaces.MetricsInterface) {
 	r.metrics = metrics
 }
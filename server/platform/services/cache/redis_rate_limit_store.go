@@ -0,0 +1,123 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package cache
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/redis/rueidis"
+	"github.com/throttled/throttled"
+)
+
+// RateLimitStoreProvider is implemented by the cache providers that can hold the state of
+// rate limiters, so that it is shared between the nodes of a cluster.
+type RateLimitStoreProvider interface {
+	// NewRateLimitStore creates a store for rate limiters whose keys are prefixed with name.
+	NewRateLimitStore(name string) throttled.GCRAStore
+}
+
+// compareAndSwapScript sets the key to a new value only if it still holds the old one.
+var compareAndSwapScript = rueidis.NewLuaScript(`
+if redis.call('GET', KEYS[1]) == ARGV[1] then
+	redis.call('SET', KEYS[1], ARGV[2], 'PX', ARGV[3])
+	return 1
+end
+return 0
+`)
+
+// RedisRateLimitStore is a throttled.GCRAStore backed by Redis. It uses the clock of the
+// Redis server, so that all the nodes sharing it agree on the time.
+type RedisRateLimitStore struct {
+	name   string
+	client rueidis.Client
+}
+
+func NewRedisRateLimitStore(name string, client rueidis.Client) *RedisRateLimitStore {
+	return &RedisRateLimitStore{
+		name:   name,
+		client: client,
+	}
+}
+
+func (r *redisProvider) NewRateLimitStore(name string) throttled.GCRAStore {
+	return NewRedisRateLimitStore(name, r.client)
+}
+
+func (s *RedisRateLimitStore) key(key string) string {
+	return s.name + ":" + key
+}
+
+// GetWithTime returns the value of the key, or -1 if it doesn't exist, along with the
+// current time of the Redis server.
+func (s *RedisRateLimitStore) GetWithTime(key string) (int64, time.Time, error) {
+	ctx := context.Background()
+	results := s.client.DoMulti(ctx,
+		s.client.B().Get().Key(s.key(key)).Build(),
+		s.client.B().Time().Build(),
+	)
+
+	serverTime, err := results[1].AsIntSlice()
+	if err != nil {
+		return 0, time.Time{}, err
+	}
+	if len(serverTime) != 2 {
+		return 0, time.Time{}, fmt.Errorf("unexpected reply to TIME: %v", serverTime)
+	}
+	now := time.Unix(serverTime[0], serverTime[1]*int64(time.Microsecond))
+
+	value, err := results[0].AsInt64()
+	if rueidis.IsRedisNil(err) {
+		return -1, now, nil
+	} else if err != nil {
+		return 0, now, err
+	}
+
+	return value, now, nil
+}
+
+// SetIfNotExistsWithTTL sets the value of the key if it doesn't exist, and reports whether
+// it did so.
+func (s *RedisRateLimitStore) SetIfNotExistsWithTTL(key string, value int64, ttl time.Duration) (bool, error) {
+	err := s.client.Do(context.Background(),
+		s.client.B().Set().
+			Key(s.key(key)).
+			Value(strconv.FormatInt(value, 10)).
+			Nx().
+			PxMilliseconds(ttlMilliseconds(ttl)).
+			Build(),
+	).Error()
+	if rueidis.IsRedisNil(err) {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// CompareAndSwapWithTTL atomically sets the key to new if it holds old, and reports whether
+// it did so.
+func (s *RedisRateLimitStore) CompareAndSwapWithTTL(key string, old, new int64, ttl time.Duration) (bool, error) {
+	swapped, err := compareAndSwapScript.Exec(context.Background(), s.client,
+		[]string{s.key(key)},
+		[]string{strconv.FormatInt(old, 10), strconv.FormatInt(new, 10), strconv.FormatInt(ttlMilliseconds(ttl), 10)},
+	).AsInt64()
+	if err != nil {
+		return false, err
+	}
+
+	return swapped == 1, nil
+}
+
+// ttlMilliseconds rounds up the ttl, since Redis rejects expiries of zero.
+func ttlMilliseconds(ttl time.Duration) int64 {
+	ms := int64((ttl + time.Millisecond - 1) / time.Millisecond)
+	if ms < 1 {
+		return 1
+	}
+	return ms
+}
@@ -0,0 +1,136 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package cache
+
+import (
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/rueidis"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/throttled/throttled"
+)
+
+func newTestRedisRateLimitStore(t *testing.T) (*RedisRateLimitStore, *miniredis.Miniredis) {
+	t.Helper()
+
+	server := miniredis.RunT(t)
+	client, err := rueidis.NewClient(rueidis.ClientOption{
+		InitAddress:  []string{server.Addr()},
+		DisableCache: true,
+	})
+	require.NoError(t, err)
+	t.Cleanup(client.Close)
+
+	return NewRedisRateLimitStore("ratelimit", client), server
+}
+
+func TestRedisRateLimitStore(t *testing.T) {
+	t.Run("get a missing key", func(t *testing.T) {
+		store, _ := newTestRedisRateLimitStore(t)
+
+		value, now, err := store.GetWithTime("missing")
+		require.NoError(t, err)
+		assert.Equal(t, int64(-1), value)
+		assert.WithinDuration(t, time.Now(), now, time.Minute)
+	})
+
+	t.Run("uses the time of the server", func(t *testing.T) {
+		store, server := newTestRedisRateLimitStore(t)
+
+		serverTime := time.Date(2020, time.January, 2, 3, 4, 5, 6000, time.UTC)
+		server.SetTime(serverTime)
+
+		_, now, err := store.GetWithTime("key")
+		require.NoError(t, err)
+		assert.True(t, serverTime.Equal(now))
+	})
+
+	t.Run("set if not exists", func(t *testing.T) {
+		store, server := newTestRedisRateLimitStore(t)
+
+		set, err := store.SetIfNotExistsWithTTL("key", 1, time.Minute)
+		require.NoError(t, err)
+		assert.True(t, set)
+
+		set, err = store.SetIfNotExistsWithTTL("key", 2, time.Minute)
+		require.NoError(t, err)
+		assert.False(t, set)
+
+		value, _, err := store.GetWithTime("key")
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), value)
+
+		// The keys are namespaced by the name of the store and expire.
+		assert.True(t, server.Exists("ratelimit:key"))
+		assert.Equal(t, time.Minute, server.TTL("ratelimit:key"))
+	})
+
+	t.Run("zero ttl is rounded up", func(t *testing.T) {
+		store, server := newTestRedisRateLimitStore(t)
+
+		set, err := store.SetIfNotExistsWithTTL("key", 1, 0)
+		require.NoError(t, err)
+		assert.True(t, set)
+		assert.Equal(t, time.Millisecond, server.TTL("ratelimit:key"))
+	})
+
+	t.Run("compare and swap", func(t *testing.T) {
+		store, server := newTestRedisRateLimitStore(t)
+
+		swapped, err := store.CompareAndSwapWithTTL("key", 1, 2, time.Minute)
+		require.NoError(t, err)
+		assert.False(t, swapped, "a missing key isn't swapped")
+
+		_, err = store.SetIfNotExistsWithTTL("key", 1, time.Second)
+		require.NoError(t, err)
+
+		swapped, err = store.CompareAndSwapWithTTL("key", 3, 2, time.Minute)
+		require.NoError(t, err)
+		assert.False(t, swapped)
+
+		swapped, err = store.CompareAndSwapWithTTL("key", 1, 2, time.Minute)
+		require.NoError(t, err)
+		assert.True(t, swapped)
+
+		value, _, err := store.GetWithTime("key")
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), value)
+		assert.Equal(t, time.Minute, server.TTL("ratelimit:key"))
+	})
+
+	t.Run("rate limits shared between stores", func(t *testing.T) {
+		store, server := newTestRedisRateLimitStore(t)
+
+		client, err := rueidis.NewClient(rueidis.ClientOption{
+			InitAddress:  []string{server.Addr()},
+			DisableCache: true,
+		})
+		require.NoError(t, err)
+		defer client.Close()
+		otherStore := NewRedisRateLimitStore("ratelimit", client)
+
+		quota := throttled.RateQuota{MaxRate: throttled.PerMin(1), MaxBurst: 1}
+		limiter, err := throttled.NewGCRARateLimiter(store, quota)
+		require.NoError(t, err)
+		otherLimiter, err := throttled.NewGCRARateLimiter(otherStore, quota)
+		require.NoError(t, err)
+
+		for i := 0; i < 2; i++ {
+			limited, _, err := limiter.RateLimit("user", 1)
+			require.NoError(t, err)
+			assert.False(t, limited)
+		}
+
+		limited, _, err := otherLimiter.RateLimit("user", 1)
+		require.NoError(t, err)
+		assert.True(t, limited)
+
+		limited, _, err = otherLimiter.RateLimit("other-user", 1)
+		require.NoError(t, err)
+		assert.False(t, limited)
+	})
+}
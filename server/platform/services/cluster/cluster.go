@@ -0,0 +1,627 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package cluster
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/redis/rueidis"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+	"github.com/mattermost/mattermost/server/public/shared/request"
+	"github.com/mattermost/mattermost/server/v8/channels/store"
+	"github.com/mattermost/mattermost/server/v8/einterfaces"
+)
+
+const (
+	// DefaultRefreshInterval is how often the nodes send a heartbeat to each other, and read
+	// the cluster discovery table to find the nodes that joined the cluster.
+	DefaultRefreshInterval = 5 * time.Second
+
+	// A node is considered gone once no heartbeat was received from it for this many intervals.
+	missedHeartbeats = 3
+
+	sendQueueSize    = 5000
+	sendQueueName    = "send"
+	reliableRetries  = 3
+	sendRetryBackoff = 200 * time.Millisecond
+
+	// Internal event exchanged by the nodes to tell each other they are alive.
+	clusterEventHeartbeat model.ClusterEvent = "gossip_heartbeat"
+)
+
+// ServerIface is the part of the server that the cluster relies on.
+type ServerIface interface {
+	Config() *model.Config
+	Log() mlog.LoggerIFace
+	GetStore() store.Store
+	GetMetrics() einterfaces.MetricsInterface
+	// StartClusterDiscovery saves the node in the cluster discovery table, and keeps its row
+	// alive until stop is called.
+	StartClusterDiscovery(discovery model.ClusterDiscovery) (stop func())
+	InvokeClusterLeaderChangedListeners()
+	ReloadConfig() error
+	// RedisClient returns the client of the Redis cache, or nil if the cache doesn't use Redis.
+	RedisClient() rueidis.Client
+
+	// The state of the node requested by the other nodes.
+	TotalWebsocketConnections() int
+	WebConnCountForUser(userID string) int
+	GetLogsSkipSend(page, perPage int, logFilter *model.LogFilter) ([]string, *model.AppError)
+	GetPluginStatuses() (model.PluginStatuses, *model.AppError)
+	GetLogFile(rctx request.CTX) (*model.FileData, error)
+	CreateGoroutineProfile(rctx request.CTX) (*model.FileData, error)
+}
+
+// envelope is the message exchanged by the nodes.
+type envelope struct {
+	From    string                `json:"from"`
+	Message *model.ClusterMessage `json:"message"`
+}
+
+type outgoingMessage struct {
+	event    model.ClusterEvent
+	node     *model.ClusterDiscovery // nil to send to every node
+	buf      []byte
+	reliable bool
+	done     chan struct{}
+}
+
+type peer struct {
+	discovery *model.ClusterDiscovery
+	info      *model.ClusterInfo
+	lastSeen  time.Time
+}
+
+// Cluster is the built-in implementation of einterfaces.ClusterInterface. The nodes find each
+// other through the cluster discovery table, exchange messages through a Transport, and elect
+// as leader the oldest node that is still sending heartbeats.
+type Cluster struct {
+	server    ServerIface
+	transport Transport
+	id        string
+
+	// refreshInterval can be lowered by the tests.
+	refreshInterval time.Duration
+
+	handlersMut sync.RWMutex
+	handlers    map[model.ClusterEvent]einterfaces.ClusterMessageHandler
+
+	mut           sync.RWMutex
+	running       bool
+	startAt       time.Time
+	discovery     model.ClusterDiscovery
+	info          *model.ClusterInfo
+	peers         map[string]*peer
+	leader        bool
+	stopDiscovery func()
+	sendQueue     chan *outgoingMessage
+	stop          chan struct{}
+	stopped       sync.WaitGroup
+
+	requestsMut sync.Mutex
+	requests    map[string]chan *envelope
+}
+
+// New creates a cluster that exchanges messages through the transport set in the
+// configuration.
+func New(server ServerIface) *Cluster {
+	c := &Cluster{
+		server:          server,
+		id:              model.NewId(),
+		refreshInterval: DefaultRefreshInterval,
+		handlers:        make(map[model.ClusterEvent]einterfaces.ClusterMessageHandler),
+		peers:           make(map[string]*peer),
+		requests:        make(map[string]chan *envelope),
+	}
+
+	if *server.Config().ClusterSettings.MessageTransport == model.ClusterMessageTransportRedis {
+		if client := server.RedisClient(); client != nil {
+			c.transport = newRedisTransport(c, &rueidisPubSub{client: client})
+		} else {
+			server.Log().Error("The Redis cluster message transport requires the Redis cache, using the HTTP transport instead.")
+		}
+	}
+	if c.transport == nil {
+		c.transport = newHTTPTransport(c)
+	}
+	return c
+}
+
+func (c *Cluster) logger() mlog.LoggerIFace {
+	return c.server.Log()
+}
+
+func (c *Cluster) StartInterNodeCommunication() {
+	c.mut.Lock()
+	defer c.mut.Unlock()
+
+	if c.running {
+		return
+	}
+
+	settings := c.server.Config().ClusterSettings
+	if *settings.ClusterName == "" {
+		c.logger().Error("Unable to start the cluster: the cluster name is not set.")
+		return
+	}
+
+	if err := c.transport.Start(c.NotifyMsg); err != nil {
+		c.logger().Error("Unable to start the cluster transport.", mlog.Err(err))
+		return
+	}
+
+	c.discovery = model.ClusterDiscovery{
+		Id:          c.id,
+		Type:        model.CDSTypeApp,
+		ClusterName: *settings.ClusterName,
+		Hostname:    *settings.OverrideHostname,
+		GossipPort:  int32(*settings.GossipPort),
+	}
+	if t, ok := c.transport.(interface{ Port() int }); ok {
+		c.discovery.GossipPort = int32(t.Port())
+	}
+	if *settings.UseIPAddress {
+		c.discovery.AutoFillIPAddress(*settings.NetworkInterface, *settings.AdvertiseAddress)
+	} else {
+		c.discovery.AutoFillHostname()
+	}
+	c.discovery.CreateAt = model.GetMillis()
+	c.discovery.LastPingAt = c.discovery.CreateAt
+	c.stopDiscovery = c.server.StartClusterDiscovery(c.discovery)
+
+	c.info = c.makeClusterInfo()
+	c.running = true
+	c.startAt = time.Now()
+	c.leader = false
+	c.peers = make(map[string]*peer)
+	c.sendQueue = make(chan *outgoingMessage, sendQueueSize)
+	c.stop = make(chan struct{})
+
+	c.stopped.Add(2)
+	go c.sendLoop(c.sendQueue, c.stop)
+	go c.refreshLoop(c.stop)
+
+	c.logger().Info("Cluster started.",
+		mlog.String("cluster_id", c.id),
+		mlog.String("cluster_name", c.discovery.ClusterName),
+		mlog.String("hostname", c.discovery.Hostname),
+		mlog.Int("gossip_port", int(c.discovery.GossipPort)),
+	)
+}
+
+func (c *Cluster) StopInterNodeCommunication() {
+	c.mut.Lock()
+	if !c.running {
+		c.mut.Unlock()
+		return
+	}
+	c.running = false
+	c.leader = false
+	close(c.stop)
+	stopDiscovery := c.stopDiscovery
+	c.mut.Unlock()
+
+	c.stopped.Wait()
+	c.transport.Stop()
+	stopDiscovery()
+
+	c.logger().Info("Cluster stopped.", mlog.String("cluster_id", c.id))
+}
+
+func (c *Cluster) RegisterClusterMessageHandler(event model.ClusterEvent, crm einterfaces.ClusterMessageHandler) {
+	c.handlersMut.Lock()
+	defer c.handlersMut.Unlock()
+	c.handlers[event] = crm
+}
+
+func (c *Cluster) GetClusterId() string {
+	return c.id
+}
+
+func (c *Cluster) IsLeader() bool {
+	c.mut.RLock()
+	defer c.mut.RUnlock()
+	return c.leader
+}
+
+// HealthScore returns the number of nodes in the discovery table that stopped sending
+// heartbeats.
+func (c *Cluster) HealthScore() int {
+	c.mut.RLock()
+	defer c.mut.RUnlock()
+
+	score := 0
+	for _, p := range c.peers {
+		if !c.isAliveLocked(p) {
+			score++
+		}
+	}
+	return score
+}
+
+func (c *Cluster) makeClusterInfo() *model.ClusterInfo {
+	schemaVersion := ""
+	if version, err := c.server.GetStore().GetDBSchemaVersion(); err == nil {
+		schemaVersion = fmt.Sprintf("%d", version)
+	}
+
+	return &model.ClusterInfo{
+		Id:            c.id,
+		Version:       model.CurrentVersion,
+		SchemaVersion: schemaVersion,
+		ConfigHash:    configHash(c.server.Config()),
+		IPAddress:     c.discovery.Hostname,
+		Hostname:      c.discovery.Hostname,
+	}
+}
+
+func (c *Cluster) GetMyClusterInfo() *model.ClusterInfo {
+	c.mut.RLock()
+	defer c.mut.RUnlock()
+
+	if c.info == nil {
+		return nil
+	}
+	info := *c.info
+	return &info
+}
+
+// GetClusterInfos returns the information of this node and of the other nodes that are alive,
+// as sent with their last heartbeat.
+func (c *Cluster) GetClusterInfos() []*model.ClusterInfo {
+	c.mut.RLock()
+	defer c.mut.RUnlock()
+
+	infos := []*model.ClusterInfo{}
+	if c.info != nil {
+		info := *c.info
+		infos = append(infos, &info)
+	}
+	for _, p := range c.peers {
+		if c.isAliveLocked(p) && p.info != nil {
+			info := *p.info
+			infos = append(infos, &info)
+		}
+	}
+	return infos
+}
+
+func (c *Cluster) SendClusterMessage(msg *model.ClusterMessage) {
+	c.send(nil, msg)
+}
+
+func (c *Cluster) SendClusterMessageToNode(nodeID string, msg *model.ClusterMessage) error {
+	c.mut.RLock()
+	var node *model.ClusterDiscovery
+	if p, ok := c.peers[nodeID]; ok {
+		node = p.discovery
+	}
+	c.mut.RUnlock()
+	if node == nil {
+		return fmt.Errorf("unknown cluster node %q", nodeID)
+	}
+
+	c.send(node, msg)
+	return nil
+}
+
+func (c *Cluster) send(node *model.ClusterDiscovery, msg *model.ClusterMessage) {
+	buf, err := json.Marshal(&envelope{From: c.id, Message: msg})
+	if err != nil {
+		c.logger().Warn("Failed to encode the cluster message.", mlog.String("event", string(msg.Event)), mlog.Err(err))
+		return
+	}
+
+	c.mut.RLock()
+	running, queue, stop := c.running, c.sendQueue, c.stop
+	c.mut.RUnlock()
+	if !running {
+		return
+	}
+
+	out := &outgoingMessage{
+		event:    msg.Event,
+		node:     node,
+		buf:      buf,
+		reliable: msg.SendType == model.ClusterSendReliable,
+	}
+	if msg.WaitForAllToSend {
+		out.done = make(chan struct{})
+	}
+
+	if out.reliable || out.done != nil {
+		select {
+		case queue <- out:
+		case <-stop:
+			return
+		}
+	} else {
+		select {
+		case queue <- out:
+		default:
+			c.logger().Warn("The cluster send queue is full, dropping a best effort message.", mlog.String("event", string(msg.Event)))
+			if metrics := c.server.GetMetrics(); metrics != nil {
+				metrics.IncrementClusterDroppedMessages(sendQueueName)
+			}
+			return
+		}
+	}
+	c.observeSendQueue(queue)
+
+	if out.done != nil {
+		select {
+		case <-out.done:
+		case <-stop:
+		}
+	}
+}
+
+// sendLoop sends the queued messages one after the other, so that they are received in the
+// order they were sent.
+func (c *Cluster) sendLoop(queue chan *outgoingMessage, stop chan struct{}) {
+	defer c.stopped.Done()
+
+	for {
+		select {
+		case out := <-queue:
+			c.observeSendQueue(queue)
+			c.sendNow(out, stop)
+			if out.done != nil {
+				close(out.done)
+			}
+		case <-stop:
+			return
+		}
+	}
+}
+
+func (c *Cluster) observeSendQueue(queue chan *outgoingMessage) {
+	if metrics := c.server.GetMetrics(); metrics != nil {
+		metrics.SetClusterQueueSize(sendQueueName, float64(len(queue)))
+	}
+}
+
+func (c *Cluster) sendNow(out *outgoingMessage, stop chan struct{}) {
+	attempts := 1
+	if out.reliable {
+		attempts += reliableRetries
+	}
+
+	for attempt := 0; attempt < attempts; attempt++ {
+		if attempt > 0 {
+			select {
+			case <-time.After(sendRetryBackoff * time.Duration(1<<(attempt-1))):
+			case <-stop:
+				return
+			}
+		}
+
+		start := time.Now()
+		var err error
+		if out.node != nil {
+			err = c.transport.Send(context.Background(), out.event, out.node, out.buf)
+		} else {
+			err = c.transport.Broadcast(context.Background(), out.event, c.alivePeers(), out.buf)
+		}
+		if metrics := c.server.GetMetrics(); metrics != nil {
+			metrics.IncrementClusterRequest()
+			metrics.ObserveClusterRequestDuration(time.Since(start).Seconds())
+		}
+		if err == nil {
+			return
+		}
+		c.logger().Debug("Failed to send the cluster message.", mlog.Int("attempt", attempt+1), mlog.Err(err))
+	}
+
+	c.logger().Warn("Failed to send the cluster message.", mlog.Bool("reliable", out.reliable))
+}
+
+// invalidateAllCaches invalidates the caches of the node, as when another node asks it to.
+// The transports call it when they may have missed cache invalidations.
+func (c *Cluster) invalidateAllCaches() {
+	c.handlersMut.RLock()
+	handler, ok := c.handlers[model.ClusterEventInvalidateAllCaches]
+	c.handlersMut.RUnlock()
+	if ok {
+		handler(&model.ClusterMessage{Event: model.ClusterEventInvalidateAllCaches})
+	}
+}
+
+// NotifyMsg handles a message received from another node.
+func (c *Cluster) NotifyMsg(buf []byte) {
+	var env envelope
+	if err := json.Unmarshal(buf, &env); err != nil || env.Message == nil {
+		c.logger().Warn("Failed to decode the cluster message.", mlog.Err(err))
+		return
+	}
+	if env.From == c.id {
+		return
+	}
+	msg := env.Message
+
+	if metrics := c.server.GetMetrics(); metrics != nil {
+		metrics.IncrementClusterEventType(msg.Event)
+	}
+
+	switch {
+	case msg.Event == clusterEventHeartbeat:
+		c.handleHeartbeat(&env)
+		return
+	case isGossipResponse(msg.Event):
+		c.handleResponse(&env)
+		return
+	case isGossipRequest(msg.Event):
+		// Requests can take a while, and must not hold up the other messages.
+		go c.handleRequest(&env)
+		return
+	}
+
+	c.handlersMut.RLock()
+	handler, ok := c.handlers[msg.Event]
+	c.handlersMut.RUnlock()
+	if !ok {
+		c.logger().Debug("No handler registered for the cluster message.", mlog.String("event", string(msg.Event)))
+		return
+	}
+	handler(msg)
+}
+
+func (c *Cluster) handleHeartbeat(env *envelope) {
+	var info model.ClusterInfo
+	if err := json.Unmarshal(env.Message.Data, &info); err != nil {
+		c.logger().Warn("Failed to decode the cluster heartbeat.", mlog.String("from", env.From), mlog.Err(err))
+		return
+	}
+
+	c.mut.Lock()
+	p, ok := c.peers[env.From]
+	if !ok {
+		// The node joined after the last refresh, its discovery row is read on the next one.
+		p = &peer{discovery: &model.ClusterDiscovery{Id: env.From}}
+		c.peers[env.From] = p
+	}
+	p.info = &info
+	p.lastSeen = time.Now()
+	c.mut.Unlock()
+}
+
+func (c *Cluster) refreshLoop(stop chan struct{}) {
+	defer c.stopped.Done()
+
+	ticker := time.NewTicker(c.refreshInterval)
+	defer ticker.Stop()
+
+	c.refresh()
+	for {
+		select {
+		case <-ticker.C:
+			c.refresh()
+		case <-stop:
+			return
+		}
+	}
+}
+
+// refresh reads the nodes from the cluster discovery table, sends them a heartbeat, and elects
+// the leader among the nodes that are alive.
+func (c *Cluster) refresh() {
+	c.mut.RLock()
+	clusterName := c.discovery.ClusterName
+	c.mut.RUnlock()
+
+	discoveries, err := c.server.GetStore().ClusterDiscovery().GetAll(model.CDSTypeApp, clusterName)
+	if err != nil {
+		c.logger().Warn("Failed to get the cluster nodes.", mlog.Err(err))
+		return
+	}
+
+	c.mut.Lock()
+	if !c.running {
+		c.mut.Unlock()
+		return
+	}
+	c.info.ConfigHash = configHash(c.server.Config())
+	info := *c.info
+
+	peers := make(map[string]*peer, len(discoveries))
+	for _, discovery := range discoveries {
+		if discovery.Id == c.id {
+			continue
+		}
+		p, ok := c.peers[discovery.Id]
+		if !ok {
+			p = &peer{}
+		}
+		p.discovery = discovery
+		peers[discovery.Id] = p
+	}
+	c.peers = peers
+
+	targets := make([]*model.ClusterDiscovery, 0, len(peers))
+	for _, p := range peers {
+		targets = append(targets, p.discovery)
+	}
+
+	wasLeader := c.leader
+	c.leader = c.electLeaderLocked() == c.id
+	isLeader := c.leader
+	c.mut.Unlock()
+
+	if wasLeader != isLeader {
+		c.logger().Info("Cluster leader changed.", mlog.String("cluster_id", c.id), mlog.Bool("is_leader", isLeader))
+		c.server.InvokeClusterLeaderChangedListeners()
+	}
+
+	data, err := json.Marshal(&info)
+	if err != nil {
+		return
+	}
+	buf, err := json.Marshal(&envelope{From: c.id, Message: &model.ClusterMessage{Event: clusterEventHeartbeat, Data: data}})
+	if err != nil {
+		return
+	}
+	if err := c.transport.Broadcast(context.Background(), clusterEventHeartbeat, targets, buf); err != nil {
+		c.logger().Debug("Failed to send the cluster heartbeat.", mlog.Err(err))
+	}
+}
+
+// electLeaderLocked returns the ID of the oldest node that is alive.
+func (c *Cluster) electLeaderLocked() string {
+	candidates := []*model.ClusterDiscovery{&c.discovery}
+	for _, p := range c.peers {
+		if c.isAliveLocked(p) && p.discovery.CreateAt != 0 {
+			candidates = append(candidates, p.discovery)
+		}
+	}
+
+	sort.Slice(candidates, func(i, j int) bool {
+		if candidates[i].CreateAt != candidates[j].CreateAt {
+			return candidates[i].CreateAt < candidates[j].CreateAt
+		}
+		return candidates[i].Id < candidates[j].Id
+	})
+	return candidates[0].Id
+}
+
+// isAliveLocked returns true if a heartbeat was received recently from the node. Right after
+// starting, the nodes that weren't heard from yet are assumed to be alive, so that a node
+// joining the cluster doesn't elect itself as leader before hearing from the others.
+func (c *Cluster) isAliveLocked(p *peer) bool {
+	timeout := missedHeartbeats * c.refreshInterval
+	if p.lastSeen.IsZero() {
+		return time.Since(c.startAt) < timeout
+	}
+	return time.Since(p.lastSeen) < timeout
+}
+
+func (c *Cluster) alivePeers() []*model.ClusterDiscovery {
+	c.mut.RLock()
+	defer c.mut.RUnlock()
+
+	nodes := []*model.ClusterDiscovery{}
+	for _, p := range c.peers {
+		if c.isAliveLocked(p) && p.discovery.Hostname != "" {
+			nodes = append(nodes, p.discovery)
+		}
+	}
+	return nodes
+}
+
+func configHash(cfg *model.Config) string {
+	buf, err := json.Marshal(cfg)
+	if err != nil {
+		return ""
+	}
+	hash := sha256.Sum256(buf)
+	return hex.EncodeToString(hash[:])
+}
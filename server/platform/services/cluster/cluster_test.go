@@ -0,0 +1,315 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package cluster
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/shared/request"
+)
+
+const (
+	testRefreshInterval = 50 * time.Millisecond
+	testEvent           = model.ClusterEvent("test_event")
+)
+
+type testNode struct {
+	cluster *Cluster
+	server  *mockServer
+}
+
+// startNodes starts several nodes sharing a cluster discovery table, and waits for them to
+// hear from each other.
+func startNodes(t *testing.T, count int) []*testNode {
+	return startNodesWith(t, count, nil)
+}
+
+// startNodesWith starts several nodes like startNodes, calling setup on each node before
+// starting it.
+func startNodesWith(t *testing.T, count int, setup func(c *Cluster, server *mockServer)) []*testNode {
+	t.Helper()
+
+	discovery := newDiscoveryStore()
+	key := model.NewId()
+
+	nodes := make([]*testNode, 0, count)
+	for i := 0; i < count; i++ {
+		server := newMockServer(t, discovery, key)
+		c := New(server)
+		c.refreshInterval = testRefreshInterval
+		if setup != nil {
+			setup(c, server)
+		}
+		c.RegisterClusterMessageHandler(testEvent, server.handler)
+		c.StartInterNodeCommunication()
+		t.Cleanup(c.StopInterNodeCommunication)
+
+		nodes = append(nodes, &testNode{cluster: c, server: server})
+	}
+
+	require.Eventually(t, func() bool {
+		for _, node := range nodes {
+			if len(node.cluster.GetClusterInfos()) != count || len(node.cluster.alivePeers()) != count-1 {
+				return false
+			}
+		}
+		return true
+	}, 5*time.Second, 10*time.Millisecond)
+
+	return nodes
+}
+
+func leaders(nodes []*testNode) []*testNode {
+	result := []*testNode{}
+	for _, node := range nodes {
+		if node.cluster.IsLeader() {
+			result = append(result, node)
+		}
+	}
+	return result
+}
+
+func TestClusterMessages(t *testing.T) {
+	nodes := startNodes(t, 3)
+
+	t.Run("broadcast", func(t *testing.T) {
+		nodes[0].cluster.SendClusterMessage(&model.ClusterMessage{
+			Event:            testEvent,
+			SendType:         model.ClusterSendReliable,
+			WaitForAllToSend: true,
+			Data:             []byte("broadcast"),
+		})
+
+		for _, node := range nodes[1:] {
+			received := node.server.received()
+			require.Len(t, received, 1)
+			assert.Equal(t, []byte("broadcast"), received[0].Data)
+		}
+		assert.Empty(t, nodes[0].server.received())
+	})
+
+	t.Run("messages are received in order", func(t *testing.T) {
+		for i := 0; i < 20; i++ {
+			nodes[1].cluster.SendClusterMessage(&model.ClusterMessage{
+				Event:    testEvent,
+				SendType: model.ClusterSendBestEffort,
+				Data:     []byte(fmt.Sprint(i)),
+			})
+		}
+
+		require.Eventually(t, func() bool {
+			return len(nodes[2].server.received()) == 21
+		}, 5*time.Second, 10*time.Millisecond)
+		for i, msg := range nodes[2].server.received()[1:] {
+			assert.Equal(t, []byte(fmt.Sprint(i)), msg.Data)
+		}
+	})
+
+	t.Run("send to a node", func(t *testing.T) {
+		before := len(nodes[1].server.received())
+
+		err := nodes[2].cluster.SendClusterMessageToNode(nodes[0].cluster.GetClusterId(), &model.ClusterMessage{
+			Event:            testEvent,
+			WaitForAllToSend: true,
+			Data:             []byte("direct"),
+		})
+		require.NoError(t, err)
+
+		received := nodes[0].server.received()
+		require.NotEmpty(t, received)
+		assert.Equal(t, []byte("direct"), received[len(received)-1].Data)
+		assert.Len(t, nodes[1].server.received(), before)
+	})
+
+	t.Run("send to an unknown node", func(t *testing.T) {
+		err := nodes[0].cluster.SendClusterMessageToNode(model.NewId(), &model.ClusterMessage{Event: testEvent})
+		require.Error(t, err)
+	})
+
+	t.Run("unsigned messages are rejected", func(t *testing.T) {
+		discovery := nodes[0].cluster.discovery
+		url := fmt.Sprintf("http://127.0.0.1:%d%s", discovery.GossipPort, clusterMessagePath)
+		req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader([]byte(`{"from":"x","message":{"event":"test_event"}}`)))
+		require.NoError(t, err)
+		req.Header.Set(clusterNameHeader, discovery.ClusterName)
+
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		resp.Body.Close()
+		assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+	})
+
+	t.Run("large unsigned messages are rejected before reading them", func(t *testing.T) {
+		discovery := nodes[0].cluster.discovery
+		url := fmt.Sprintf("http://127.0.0.1:%d%s", discovery.GossipPort, clusterMessagePath)
+		prefix := make([]byte, sealPrefixSize)
+		binary.BigEndian.PutUint32(prefix[8+sealNonceSize:], maxClusterMessageSize)
+		body := io.MultiReader(bytes.NewReader(prefix), io.LimitReader(zeroReader{}, maxClusterMessageSize))
+		req, err := http.NewRequest(http.MethodPost, url, body)
+		require.NoError(t, err)
+		req.Header.Set(clusterNameHeader, discovery.ClusterName)
+
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		resp.Body.Close()
+		assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+	})
+}
+
+type zeroReader struct{}
+
+func (zeroReader) Read(p []byte) (int, error) {
+	clear(p)
+	return len(p), nil
+}
+
+func TestClusterEncryption(t *testing.T) {
+	discovery := newDiscoveryStore()
+	key := model.NewId()
+
+	nodes := []*testNode{}
+	for i := 0; i < 2; i++ {
+		server := newMockServer(t, discovery, key)
+		*server.config.ClusterSettings.EnableExperimentalGossipEncryption = true
+		c := New(server)
+		c.refreshInterval = testRefreshInterval
+		c.RegisterClusterMessageHandler(testEvent, server.handler)
+		c.StartInterNodeCommunication()
+		t.Cleanup(c.StopInterNodeCommunication)
+		nodes = append(nodes, &testNode{cluster: c, server: server})
+	}
+
+	require.Eventually(t, func() bool {
+		return len(nodes[0].cluster.alivePeers()) == 1
+	}, 5*time.Second, 10*time.Millisecond)
+
+	nodes[0].cluster.SendClusterMessage(&model.ClusterMessage{
+		Event:            testEvent,
+		WaitForAllToSend: true,
+		Data:             []byte("encrypted"),
+	})
+	received := nodes[1].server.received()
+	require.Len(t, received, 1)
+	assert.Equal(t, []byte("encrypted"), received[0].Data)
+}
+
+func TestClusterLeader(t *testing.T) {
+	nodes := startNodes(t, 3)
+
+	require.Eventually(t, func() bool {
+		return len(leaders(nodes)) == 1
+	}, 5*time.Second, 10*time.Millisecond)
+	leader := leaders(nodes)[0]
+
+	// The leader is the oldest node.
+	for _, node := range nodes {
+		assert.LessOrEqual(t, leader.cluster.discovery.CreateAt, node.cluster.discovery.CreateAt)
+	}
+	leader.server.mut.Lock()
+	assert.Equal(t, 1, leader.server.leaderChanges)
+	leader.server.mut.Unlock()
+
+	leader.cluster.StopInterNodeCommunication()
+	assert.False(t, leader.cluster.IsLeader())
+
+	remaining := []*testNode{}
+	for _, node := range nodes {
+		if node != leader {
+			remaining = append(remaining, node)
+		}
+	}
+	require.Eventually(t, func() bool {
+		return len(leaders(remaining)) == 1
+	}, 5*time.Second, 10*time.Millisecond)
+	require.Eventually(t, func() bool {
+		for _, node := range remaining {
+			if len(node.cluster.GetClusterInfos()) != 2 {
+				return false
+			}
+		}
+		return true
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
+func TestClusterRequests(t *testing.T) {
+	nodes := startNodes(t, 3)
+	nodes[1].server.webConnCount = 2
+	nodes[2].server.webConnCount = 3
+
+	t.Run("cluster infos", func(t *testing.T) {
+		infos := nodes[0].cluster.GetClusterInfos()
+		require.Len(t, infos, 3)
+		ids := []string{}
+		for _, info := range infos {
+			ids = append(ids, info.Id)
+			assert.Equal(t, model.CurrentVersion, info.Version)
+			assert.Equal(t, "136", info.SchemaVersion)
+			assert.NotEmpty(t, info.ConfigHash)
+		}
+		for _, node := range nodes {
+			assert.Contains(t, ids, node.cluster.GetClusterId())
+		}
+		assert.Equal(t, nodes[0].cluster.GetClusterId(), nodes[0].cluster.GetMyClusterInfo().Id)
+	})
+
+	t.Run("cluster stats", func(t *testing.T) {
+		stats, appErr := nodes[0].cluster.GetClusterStats()
+		require.Nil(t, appErr)
+		require.Len(t, stats, 2)
+		for _, stat := range stats {
+			assert.NotEqual(t, nodes[0].cluster.GetClusterId(), stat.Id)
+			assert.Equal(t, 1, stat.TotalWebsocketConnections)
+			assert.Equal(t, 3, stat.TotalMasterDbConnections)
+		}
+	})
+
+	t.Run("webconn count", func(t *testing.T) {
+		count, appErr := nodes[0].cluster.WebConnCountForUser(model.NewId())
+		require.Nil(t, appErr)
+		assert.Equal(t, 5, count)
+	})
+
+	t.Run("plugin statuses", func(t *testing.T) {
+		statuses, appErr := nodes[0].cluster.GetPluginStatuses()
+		require.Nil(t, appErr)
+		assert.Len(t, statuses, 2)
+	})
+
+	t.Run("logs", func(t *testing.T) {
+		logs, appErr := nodes[0].cluster.QueryLogs(0, 10)
+		require.Nil(t, appErr)
+		assert.Equal(t, map[string][]string{"127.0.0.1": {"line"}}, logs)
+	})
+
+	t.Run("config changed", func(t *testing.T) {
+		appErr := nodes[0].cluster.ConfigChanged(nil, nil, true)
+		require.Nil(t, appErr)
+		for _, node := range nodes[1:] {
+			node.server.mut.Lock()
+			assert.Equal(t, 1, node.server.configReloads)
+			node.server.mut.Unlock()
+		}
+	})
+
+	t.Run("support packet", func(t *testing.T) {
+		files, err := nodes[0].cluster.GenerateSupportPacket(request.TestContext(t), &model.SupportPacketOptions{IncludeLogs: true})
+		require.NoError(t, err)
+		require.Len(t, files, 2)
+		for _, nodeFiles := range files {
+			require.Len(t, nodeFiles, 2)
+			assert.Equal(t, "127.0.0.1/goroutines", nodeFiles[0].Filename)
+			assert.Equal(t, "127.0.0.1/mattermost.log", nodeFiles[1].Filename)
+		}
+	})
+}
@@ -0,0 +1,249 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package cluster
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+)
+
+const (
+	clusterMessagePath = "/cluster/msg"
+	clusterNameHeader  = "X-Mattermost-Cluster-Name"
+
+	httpSendTimeout       = 10 * time.Second
+	maxClusterMessageSize = 256 * 1024 * 1024
+)
+
+// httpTransport posts the messages to an HTTP server that each node runs on its gossip port.
+// The messages are sealed with a key shared by the nodes through the database.
+type httpTransport struct {
+	cluster *Cluster
+	client  *http.Client
+
+	mut      sync.Mutex
+	listener net.Listener
+	server   *http.Server
+	sealer   *messageSealer
+	name     string
+}
+
+func newHTTPTransport(cluster *Cluster) *httpTransport {
+	return &httpTransport{
+		cluster: cluster,
+		client: &http.Client{
+			Timeout: httpSendTimeout,
+			Transport: &http.Transport{
+				MaxIdleConnsPerHost: 8,
+				IdleConnTimeout:     90 * time.Second,
+			},
+		},
+	}
+}
+
+// clusterKey returns the key shared by the nodes of the cluster, generating it if no node did
+// yet.
+func clusterKey(cluster *Cluster) ([]byte, error) {
+	random := make([]byte, 32)
+	if _, err := rand.Read(random); err != nil {
+		return nil, errors.Wrap(err, "unable to generate the cluster key")
+	}
+
+	system, err := cluster.server.GetStore().System().InsertIfExists(&model.System{
+		Name:  model.SystemClusterEncryptionKey,
+		Value: base64.StdEncoding.EncodeToString(random),
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to get the cluster key")
+	}
+
+	key := sha256.Sum256([]byte(system.Value))
+	return key[:], nil
+}
+
+func (t *httpTransport) Start(receive func(buf []byte)) error {
+	t.mut.Lock()
+	defer t.mut.Unlock()
+
+	key, err := clusterKey(t.cluster)
+	if err != nil {
+		return err
+	}
+	settings := t.cluster.server.Config().ClusterSettings
+	t.sealer = newMessageSealer(key, *settings.EnableExperimentalGossipEncryption, *settings.ClusterName)
+	t.name = *settings.ClusterName
+
+	address := net.JoinHostPort(*settings.BindAddress, strconv.Itoa(*settings.GossipPort))
+	listener, err := net.Listen("tcp", address)
+	if err != nil {
+		return errors.Wrapf(err, "unable to listen on %s", address)
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc(clusterMessagePath, func(w http.ResponseWriter, r *http.Request) {
+		t.handleMessage(w, r, receive)
+	})
+	t.listener = listener
+	t.server = &http.Server{
+		Handler:           mux,
+		ReadHeaderTimeout: httpSendTimeout,
+	}
+
+	go func() {
+		if err := t.server.Serve(listener); err != nil && err != http.ErrServerClosed {
+			t.cluster.logger().Error("The cluster HTTP server stopped.", mlog.Err(err))
+		}
+	}()
+	return nil
+}
+
+// Port returns the port the transport listens on, which is picked by the system when the
+// gossip port is 0.
+func (t *httpTransport) Port() int {
+	t.mut.Lock()
+	defer t.mut.Unlock()
+
+	if t.listener == nil {
+		return 0
+	}
+	return t.listener.Addr().(*net.TCPAddr).Port
+}
+
+func (t *httpTransport) Stop() {
+	t.mut.Lock()
+	server := t.server
+	t.server = nil
+	t.listener = nil
+	t.mut.Unlock()
+
+	// The connections opened to the other nodes would otherwise delay their own shutdown.
+	t.client.CloseIdleConnections()
+
+	if server == nil {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), httpSendTimeout)
+	defer cancel()
+	if err := server.Shutdown(ctx); err != nil {
+		t.cluster.logger().Warn("Failed to stop the cluster HTTP server.", mlog.Err(err))
+	}
+}
+
+func (t *httpTransport) Broadcast(ctx context.Context, event model.ClusterEvent, nodes []*model.ClusterDiscovery, buf []byte) error {
+	var wg sync.WaitGroup
+	errs := make([]error, len(nodes))
+	for i, node := range nodes {
+		wg.Add(1)
+		go func(i int, node *model.ClusterDiscovery) {
+			defer wg.Done()
+			errs[i] = t.Send(ctx, event, node, buf)
+		}(i, node)
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (t *httpTransport) Send(ctx context.Context, event model.ClusterEvent, node *model.ClusterDiscovery, buf []byte) error {
+	sealer, name := t.getSealer()
+	if sealer == nil {
+		return errors.New("the cluster transport is not started")
+	}
+	body, err := sealer.seal(buf, node.Id)
+	if err != nil {
+		return err
+	}
+
+	url := fmt.Sprintf("http://%s%s", net.JoinHostPort(node.Hostname, strconv.Itoa(int(node.GossipPort))), clusterMessagePath)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set(clusterNameHeader, name)
+
+	resp, err := t.client.Do(req)
+	if err != nil {
+		return errors.Wrapf(err, "unable to send the message to %s", node.Id)
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unable to send the message to %s: status %d", node.Id, resp.StatusCode)
+	}
+	return nil
+}
+
+func (t *httpTransport) handleMessage(w http.ResponseWriter, r *http.Request, receive func(buf []byte)) {
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+	sealer, name := t.getSealer()
+	if sealer == nil || r.Header.Get(clusterNameHeader) != name {
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+
+	// Only the prefix of the message is read before it is authenticated, so that the senders
+	// that don't know the cluster key can't make the node buffer large bodies.
+	prefix := make([]byte, sealPrefixSize)
+	if _, err := io.ReadFull(r.Body, prefix); err != nil {
+		t.rejectMessage(w, r, http.StatusUnauthorized, err)
+		return
+	}
+	// The messages are always sent to a single node, even when they are broadcast.
+	bodySize, err := sealer.openPrefix(prefix, t.cluster.id)
+	if err != nil {
+		t.rejectMessage(w, r, http.StatusUnauthorized, err)
+		return
+	}
+
+	body := make([]byte, bodySize)
+	if _, err = io.ReadFull(r.Body, body); err != nil {
+		t.rejectMessage(w, r, http.StatusBadRequest, err)
+		return
+	}
+	buf, err := sealer.openBody(prefix, body, t.cluster.id)
+	if err != nil {
+		t.rejectMessage(w, r, http.StatusUnauthorized, err)
+		return
+	}
+
+	// The message is handled before answering, so that a node receives the messages of another
+	// one in the order they were sent.
+	receive(buf)
+	w.WriteHeader(http.StatusOK)
+}
+
+func (t *httpTransport) rejectMessage(w http.ResponseWriter, r *http.Request, status int, err error) {
+	t.cluster.logger().Warn("Rejected a cluster message.", mlog.String("remote_addr", r.RemoteAddr), mlog.Err(err))
+	w.WriteHeader(status)
+}
+
+func (t *httpTransport) getSealer() (*messageSealer, string) {
+	t.mut.Lock()
+	defer t.mut.Unlock()
+	return t.sealer, t.name
+}
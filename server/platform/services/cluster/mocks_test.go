@@ -0,0 +1,174 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package cluster
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/redis/rueidis"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/plugin/plugintest/mock"
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+	"github.com/mattermost/mattermost/server/public/shared/request"
+	"github.com/mattermost/mattermost/server/v8/channels/store"
+	"github.com/mattermost/mattermost/server/v8/channels/store/storetest/mocks"
+	"github.com/mattermost/mattermost/server/v8/einterfaces"
+)
+
+// discoveryStore is an in-memory cluster discovery table shared by the nodes of a test.
+type discoveryStore struct {
+	mut  sync.Mutex
+	rows map[string]*model.ClusterDiscovery
+}
+
+func newDiscoveryStore() *discoveryStore {
+	return &discoveryStore{rows: make(map[string]*model.ClusterDiscovery)}
+}
+
+func (s *discoveryStore) Save(discovery *model.ClusterDiscovery) error {
+	s.mut.Lock()
+	defer s.mut.Unlock()
+	row := *discovery
+	s.rows[discovery.Id] = &row
+	return nil
+}
+
+func (s *discoveryStore) Delete(discovery *model.ClusterDiscovery) (bool, error) {
+	s.mut.Lock()
+	defer s.mut.Unlock()
+	_, ok := s.rows[discovery.Id]
+	delete(s.rows, discovery.Id)
+	return ok, nil
+}
+
+func (s *discoveryStore) Exists(discovery *model.ClusterDiscovery) (bool, error) {
+	s.mut.Lock()
+	defer s.mut.Unlock()
+	_, ok := s.rows[discovery.Id]
+	return ok, nil
+}
+
+func (s *discoveryStore) GetAll(discoveryType, clusterName string) ([]*model.ClusterDiscovery, error) {
+	s.mut.Lock()
+	defer s.mut.Unlock()
+	rows := []*model.ClusterDiscovery{}
+	for _, row := range s.rows {
+		if row.Type == discoveryType && row.ClusterName == clusterName {
+			discovery := *row
+			rows = append(rows, &discovery)
+		}
+	}
+	return rows, nil
+}
+
+func (s *discoveryStore) SetLastPingAt(discovery *model.ClusterDiscovery) error {
+	return nil
+}
+
+func (s *discoveryStore) Cleanup() error {
+	return nil
+}
+
+type mockServer struct {
+	config    *model.Config
+	logger    *mlog.Logger
+	discovery *discoveryStore
+	key       string
+	metrics   einterfaces.MetricsInterface
+
+	webConnCount int
+
+	mut            sync.Mutex
+	leaderChanges  int
+	configReloads  int
+	receivedEvents []*model.ClusterMessage
+}
+
+func newMockServer(t *testing.T, discovery *discoveryStore, key string) *mockServer {
+	config := &model.Config{}
+	config.SetDefaults()
+	*config.ClusterSettings.Enable = true
+	*config.ClusterSettings.ClusterName = "test_cluster"
+	*config.ClusterSettings.OverrideHostname = "127.0.0.1"
+	*config.ClusterSettings.BindAddress = "127.0.0.1"
+	*config.ClusterSettings.GossipPort = 0
+
+	return &mockServer{
+		config:    config,
+		logger:    mlog.CreateConsoleTestLogger(t),
+		discovery: discovery,
+		key:       key,
+	}
+}
+
+func (ms *mockServer) Config() *model.Config                    { return ms.config }
+func (ms *mockServer) Log() mlog.LoggerIFace                    { return ms.logger }
+func (ms *mockServer) GetMetrics() einterfaces.MetricsInterface { return ms.metrics }
+func (ms *mockServer) RedisClient() rueidis.Client              { return nil }
+func (ms *mockServer) TotalWebsocketConnections() int           { return 1 }
+func (ms *mockServer) WebConnCountForUser(userID string) int    { return ms.webConnCount }
+
+func (ms *mockServer) GetStore() store.Store {
+	systemStoreMock := &mocks.SystemStore{}
+	systemStoreMock.On("InsertIfExists", mock.AnythingOfType("*model.System")).Return(&model.System{Name: model.SystemClusterEncryptionKey, Value: ms.key}, nil)
+
+	storeMock := &mocks.Store{}
+	storeMock.On("ClusterDiscovery").Return(ms.discovery)
+	storeMock.On("System").Return(systemStoreMock)
+	storeMock.On("GetDBSchemaVersion").Return(136, nil)
+	storeMock.On("TotalReadDbConnections").Return(2)
+	storeMock.On("TotalMasterDbConnections").Return(3)
+	storeMock.On("TotalSearchDbConnections").Return(0)
+	return storeMock
+}
+
+func (ms *mockServer) StartClusterDiscovery(discovery model.ClusterDiscovery) func() {
+	ms.discovery.Save(&discovery)
+	return func() {
+		ms.discovery.Delete(&discovery)
+	}
+}
+
+func (ms *mockServer) InvokeClusterLeaderChangedListeners() {
+	ms.mut.Lock()
+	defer ms.mut.Unlock()
+	ms.leaderChanges++
+}
+
+func (ms *mockServer) ReloadConfig() error {
+	ms.mut.Lock()
+	defer ms.mut.Unlock()
+	ms.configReloads++
+	return nil
+}
+
+func (ms *mockServer) GetLogsSkipSend(page, perPage int, logFilter *model.LogFilter) ([]string, *model.AppError) {
+	return []string{"line"}, nil
+}
+
+func (ms *mockServer) GetPluginStatuses() (model.PluginStatuses, *model.AppError) {
+	return model.PluginStatuses{{PluginId: "plugin"}}, nil
+}
+
+func (ms *mockServer) GetLogFile(rctx request.CTX) (*model.FileData, error) {
+	return &model.FileData{Filename: "mattermost.log", Body: []byte("log")}, nil
+}
+
+func (ms *mockServer) CreateGoroutineProfile(rctx request.CTX) (*model.FileData, error) {
+	return &model.FileData{Filename: "goroutines", Body: []byte("profile")}, nil
+}
+
+func (ms *mockServer) handler(msg *model.ClusterMessage) {
+	ms.mut.Lock()
+	defer ms.mut.Unlock()
+	ms.receivedEvents = append(ms.receivedEvents, msg)
+}
+
+func (ms *mockServer) received() []*model.ClusterMessage {
+	ms.mut.Lock()
+	defer ms.mut.Unlock()
+	return append([]*model.ClusterMessage{}, ms.receivedEvents...)
+}
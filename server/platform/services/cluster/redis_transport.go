@@ -0,0 +1,332 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package cluster
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/redis/rueidis"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+)
+
+const (
+	redisChannelPrefix = "mattermost_cluster"
+
+	receiveQueueSize = 1000
+	receiveQueueName = "receive"
+
+	redisSubscribeTimeout        = 10 * time.Second
+	redisResubscribeBackoff      = 100 * time.Millisecond
+	redisResubscribeMaxBackoff   = 10 * time.Second
+	redisSubscriptionStableAfter = time.Minute
+)
+
+// pubSub is the part of the Redis client used by the Redis transport.
+type pubSub interface {
+	// Publish publishes a message to a channel, and returns the number of subscribers that
+	// received it.
+	Publish(ctx context.Context, channel string, buf []byte) (int64, error)
+	// Subscribe subscribes to the channels matching the patterns, calls subscribed once the
+	// subscription is active, and passes the messages to receive until the subscription is
+	// lost or ctx is done.
+	Subscribe(ctx context.Context, patterns []string, subscribed func(), receive func(channel string, buf []byte)) error
+}
+
+// rueidisPubSub implements pubSub with the client of the Redis cache provider.
+type rueidisPubSub struct {
+	client rueidis.Client
+}
+
+func (p *rueidisPubSub) Publish(ctx context.Context, channel string, buf []byte) (int64, error) {
+	return p.client.Do(ctx, p.client.B().Publish().Channel(channel).Message(rueidis.BinaryString(buf)).Build()).AsInt64()
+}
+
+func (p *rueidisPubSub) Subscribe(ctx context.Context, patterns []string, subscribed func(), receive func(channel string, buf []byte)) error {
+	// The subscription uses its own connection, so that it doesn't hold up the commands of the
+	// cache. Releasing the connection clears the subscription.
+	client, release := p.client.Dedicate()
+	defer release()
+
+	lost := client.SetPubSubHooks(rueidis.PubSubHooks{
+		OnMessage: func(m rueidis.PubSubMessage) {
+			receive(m.Channel, []byte(m.Message))
+		},
+	})
+	if err := client.Do(ctx, client.B().Psubscribe().Pattern(patterns...).Build()).Error(); err != nil {
+		return err
+	}
+	subscribed()
+
+	select {
+	case err := <-lost:
+		if err == nil {
+			err = errors.New("the subscription was closed")
+		}
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// receiveQueue holds the opened messages of an event type until they are handled.
+type receiveQueue struct {
+	messages chan []byte
+}
+
+// redisTransport exchanges the messages through Redis pub/sub, so that the nodes don't need to
+// reach each other. Each message is published to a channel named after its event type and its
+// recipients. The received messages are handled by one goroutine per event type, which keeps
+// the order of the messages of each event type without letting a slow event type hold up the
+// others. The messages are sealed like the ones of the HTTP transport, so that the clients of
+// the Redis server that don't know the cluster key can't send or replay messages.
+type redisTransport struct {
+	cluster *Cluster
+	pubSub  pubSub
+
+	mut         sync.Mutex
+	clusterName string
+	sealer      *messageSealer
+	receive     func(buf []byte)
+	queues      map[model.ClusterEvent]*receiveQueue
+	queued      int64
+	cancel      context.CancelFunc
+	stopped     sync.WaitGroup
+}
+
+func newRedisTransport(cluster *Cluster, pubSub pubSub) *redisTransport {
+	return &redisTransport{
+		cluster: cluster,
+		pubSub:  pubSub,
+		queues:  make(map[model.ClusterEvent]*receiveQueue),
+	}
+}
+
+func (t *redisTransport) Start(receive func(buf []byte)) error {
+	key, err := clusterKey(t.cluster)
+	if err != nil {
+		return err
+	}
+	settings := t.cluster.server.Config().ClusterSettings
+	ctx, cancel := context.WithCancel(context.Background())
+
+	t.mut.Lock()
+	t.clusterName = *settings.ClusterName
+	t.sealer = newMessageSealer(key, *settings.EnableExperimentalGossipEncryption, *settings.ClusterName)
+	t.receive = receive
+	t.queues = make(map[model.ClusterEvent]*receiveQueue)
+	t.cancel = cancel
+	atomic.StoreInt64(&t.queued, 0)
+	t.mut.Unlock()
+
+	ready := make(chan error, 1)
+	t.stopped.Add(1)
+	go t.subscribeLoop(ctx, ready)
+
+	select {
+	case err := <-ready:
+		if err != nil {
+			t.Stop()
+			return errors.Wrap(err, "unable to subscribe to the cluster messages")
+		}
+	case <-time.After(redisSubscribeTimeout):
+		t.Stop()
+		return errors.New("timed out subscribing to the cluster messages")
+	}
+	return nil
+}
+
+func (t *redisTransport) Stop() {
+	// The transport is stopped while holding the lock, so that no goroutine handling the
+	// messages starts once the others are being waited for.
+	t.mut.Lock()
+	cancel := t.cancel
+	t.cancel = nil
+	if cancel != nil {
+		cancel()
+	}
+	t.mut.Unlock()
+
+	t.stopped.Wait()
+}
+
+// subscribeLoop keeps the node subscribed to its messages, subscribing again whenever the
+// subscription is lost. The messages published while the node is not subscribed are lost.
+func (t *redisTransport) subscribeLoop(ctx context.Context, ready chan<- error) {
+	defer t.stopped.Done()
+
+	patterns := []string{
+		escapeRedisPattern(t.channel(sealBroadcastDestination, "")) + "*",
+		escapeRedisPattern(t.channel("node:"+t.cluster.id, "")) + "*",
+	}
+	backoff := redisResubscribeBackoff
+	started := false
+
+	for {
+		var subscribedAt time.Time
+		err := t.pubSub.Subscribe(ctx, patterns, func() {
+			subscribedAt = time.Now()
+			if !started {
+				started = true
+				ready <- nil
+			} else {
+				// The messages published while the node wasn't subscribed are lost, including
+				// the cache invalidations, so the caches may hold stale data.
+				t.cluster.logger().Info("Subscribed again to the cluster messages, invalidating all caches.")
+				t.cluster.invalidateAllCaches()
+			}
+		}, func(channel string, buf []byte) {
+			t.dispatch(ctx, channel, buf)
+		})
+		if ctx.Err() != nil {
+			return
+		}
+		if !started {
+			ready <- err
+			return
+		}
+
+		if metrics := t.cluster.server.GetMetrics(); metrics != nil {
+			metrics.IncrementClusterTransportReconnect()
+		}
+		if !subscribedAt.IsZero() && time.Since(subscribedAt) > redisSubscriptionStableAfter {
+			backoff = redisResubscribeBackoff
+		}
+		t.cluster.logger().Warn("Lost the subscription to the cluster messages.", mlog.Duration("retry_in", backoff), mlog.Err(err))
+
+		select {
+		case <-time.After(backoff):
+		case <-ctx.Done():
+			return
+		}
+		backoff = min(backoff*2, redisResubscribeMaxBackoff)
+	}
+}
+
+// dispatch opens a received message and queues it for the goroutine handling its event type.
+// The message is opened first, so that only the nodes knowing the cluster key can start the
+// goroutine of an event type. It blocks while the queue is full, which slows down the
+// subscription rather than dropping messages.
+func (t *redisTransport) dispatch(ctx context.Context, channel string, message []byte) {
+	index := strings.LastIndexByte(channel, ':')
+	if index == -1 {
+		return
+	}
+	event := model.ClusterEvent(channel[index+1:])
+
+	// The destination the message is opened for depends on the channel it was published to.
+	destination := t.cluster.id
+	if channel == t.channel(sealBroadcastDestination, event) {
+		destination = sealBroadcastDestination
+	}
+	buf, err := t.getSealer().open(message, destination)
+	if err != nil {
+		t.cluster.logger().Warn("Rejected a cluster message.", mlog.Err(err))
+		return
+	}
+
+	t.mut.Lock()
+	if ctx.Err() != nil {
+		t.mut.Unlock()
+		return
+	}
+	queue, ok := t.queues[event]
+	if !ok {
+		queue = &receiveQueue{messages: make(chan []byte, receiveQueueSize)}
+		t.queues[event] = queue
+		t.stopped.Add(1)
+		go t.handleLoop(ctx, queue)
+	}
+	t.mut.Unlock()
+
+	select {
+	case queue.messages <- buf:
+		t.observeQueue(1)
+	default:
+		t.cluster.logger().Warn("The cluster receive queue is full.", mlog.String("event", string(event)))
+		select {
+		case queue.messages <- buf:
+			t.observeQueue(1)
+		case <-ctx.Done():
+		}
+	}
+}
+
+func (t *redisTransport) handleLoop(ctx context.Context, queue *receiveQueue) {
+	defer t.stopped.Done()
+
+	for {
+		select {
+		case buf := <-queue.messages:
+			t.observeQueue(-1)
+			t.receive(buf)
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (t *redisTransport) observeQueue(delta int64) {
+	queued := atomic.AddInt64(&t.queued, delta)
+	if metrics := t.cluster.server.GetMetrics(); metrics != nil {
+		metrics.SetClusterQueueSize(receiveQueueName, float64(queued))
+	}
+}
+
+// channel returns the name of the channel of the messages of an event type, sent to every node
+// or to a single one.
+func (t *redisTransport) channel(target string, event model.ClusterEvent) string {
+	return fmt.Sprintf("%s:%s:%s:%s", redisChannelPrefix, t.clusterName, target, event)
+}
+
+func (t *redisTransport) getSealer() *messageSealer {
+	t.mut.Lock()
+	defer t.mut.Unlock()
+	return t.sealer
+}
+
+// seal seals a message before publishing it to the given destination.
+func (t *redisTransport) seal(buf []byte, destination string) ([]byte, error) {
+	sealer := t.getSealer()
+	if sealer == nil {
+		return nil, errors.New("the cluster transport is not started")
+	}
+	return sealer.seal(buf, destination)
+}
+
+func (t *redisTransport) Broadcast(ctx context.Context, event model.ClusterEvent, _ []*model.ClusterDiscovery, buf []byte) error {
+	message, err := t.seal(buf, sealBroadcastDestination)
+	if err != nil {
+		return err
+	}
+	_, err = t.pubSub.Publish(ctx, t.channel(sealBroadcastDestination, event), message)
+	return err
+}
+
+func (t *redisTransport) Send(ctx context.Context, event model.ClusterEvent, node *model.ClusterDiscovery, buf []byte) error {
+	message, err := t.seal(buf, node.Id)
+	if err != nil {
+		return err
+	}
+	received, err := t.pubSub.Publish(ctx, t.channel("node:"+node.Id, event), message)
+	if err != nil {
+		return err
+	}
+	if received == 0 {
+		return fmt.Errorf("the node %s is not subscribed to the cluster messages", node.Id)
+	}
+	return nil
+}
+
+// escapeRedisPattern escapes the characters that have a meaning in the patterns of PSUBSCRIBE.
+func escapeRedisPattern(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `*`, `\*`, `?`, `\?`, `[`, `\[`, `]`, `\]`).Replace(s)
+}
@@ -0,0 +1,311 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package cluster
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/plugin/plugintest/mock"
+	"github.com/mattermost/mattermost/server/v8/einterfaces/mocks"
+)
+
+type fakeMessage struct {
+	channel string
+	buf     []byte
+}
+
+type fakeSubscription struct {
+	patterns []string
+	messages chan fakeMessage
+	lost     chan struct{}
+}
+
+// fakeBroker is an in-memory Redis pub/sub shared by the nodes of a test.
+type fakeBroker struct {
+	mut           sync.Mutex
+	subscriptions map[*fakeSubscription]bool
+}
+
+func newFakeBroker() *fakeBroker {
+	return &fakeBroker{subscriptions: make(map[*fakeSubscription]bool)}
+}
+
+func (b *fakeBroker) Publish(ctx context.Context, channel string, buf []byte) (int64, error) {
+	b.mut.Lock()
+	defer b.mut.Unlock()
+
+	received := int64(0)
+	for subscription := range b.subscriptions {
+		for _, pattern := range subscription.patterns {
+			if ok, _ := path.Match(pattern, channel); ok {
+				subscription.messages <- fakeMessage{channel: channel, buf: buf}
+				received++
+				break
+			}
+		}
+	}
+	return received, nil
+}
+
+func (b *fakeBroker) Subscribe(ctx context.Context, patterns []string, subscribed func(), receive func(channel string, buf []byte)) error {
+	subscription := &fakeSubscription{
+		patterns: patterns,
+		messages: make(chan fakeMessage, 10000),
+		lost:     make(chan struct{}),
+	}
+	b.mut.Lock()
+	b.subscriptions[subscription] = true
+	b.mut.Unlock()
+	defer func() {
+		b.mut.Lock()
+		delete(b.subscriptions, subscription)
+		b.mut.Unlock()
+	}()
+	subscribed()
+
+	for {
+		select {
+		case msg := <-subscription.messages:
+			receive(msg.channel, msg.buf)
+		case <-subscription.lost:
+			return errors.New("connection lost")
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// disconnect drops every subscription, as if the connections to Redis were lost.
+func (b *fakeBroker) disconnect() {
+	b.mut.Lock()
+	defer b.mut.Unlock()
+	for subscription := range b.subscriptions {
+		close(subscription.lost)
+		delete(b.subscriptions, subscription)
+	}
+}
+
+func (b *fakeBroker) count() int {
+	b.mut.Lock()
+	defer b.mut.Unlock()
+	return len(b.subscriptions)
+}
+
+// recordingPubSub lets a test see the messages a node publishes.
+type recordingPubSub struct {
+	pubSub
+	publish func(ctx context.Context, channel string, buf []byte) (int64, error)
+}
+
+func (p *recordingPubSub) Publish(ctx context.Context, channel string, buf []byte) (int64, error) {
+	return p.publish(ctx, channel, buf)
+}
+
+func newTestMetrics() *mocks.MetricsInterface {
+	metrics := &mocks.MetricsInterface{}
+	metrics.On("IncrementClusterRequest").Return()
+	metrics.On("ObserveClusterRequestDuration", mock.Anything).Return()
+	metrics.On("IncrementClusterEventType", mock.Anything).Return()
+	metrics.On("SetClusterQueueSize", mock.Anything, mock.Anything).Return()
+	metrics.On("IncrementClusterDroppedMessages", mock.Anything).Return()
+	metrics.On("IncrementClusterTransportReconnect").Return()
+	return metrics
+}
+
+func startRedisNodes(t *testing.T, broker *fakeBroker, count int) []*testNode {
+	return startNodesWith(t, count, func(c *Cluster, server *mockServer) {
+		server.metrics = newTestMetrics()
+		c.transport = newRedisTransport(c, broker)
+	})
+}
+
+func TestRedisTransport(t *testing.T) {
+	t.Run("messages", func(t *testing.T) {
+		broker := newFakeBroker()
+		nodes := startRedisNodes(t, broker, 3)
+
+		for i := 0; i < 20; i++ {
+			nodes[0].cluster.SendClusterMessage(&model.ClusterMessage{
+				Event:            testEvent,
+				SendType:         model.ClusterSendReliable,
+				WaitForAllToSend: true,
+				Data:             []byte(fmt.Sprint(i)),
+			})
+		}
+
+		for _, node := range nodes[1:] {
+			require.Eventually(t, func() bool {
+				return len(node.server.received()) == 20
+			}, 5*time.Second, 10*time.Millisecond)
+			for i, msg := range node.server.received() {
+				assert.Equal(t, []byte(fmt.Sprint(i)), msg.Data)
+			}
+		}
+		assert.Empty(t, nodes[0].server.received())
+
+		err := nodes[1].cluster.SendClusterMessageToNode(nodes[2].cluster.GetClusterId(), &model.ClusterMessage{
+			Event: testEvent,
+			Data:  []byte("direct"),
+		})
+		require.NoError(t, err)
+		require.Eventually(t, func() bool {
+			return len(nodes[2].server.received()) == 21
+		}, 5*time.Second, 10*time.Millisecond)
+		assert.Len(t, nodes[0].server.received(), 0)
+		assert.Len(t, nodes[1].server.received(), 20)
+	})
+
+	t.Run("requests", func(t *testing.T) {
+		broker := newFakeBroker()
+		nodes := startRedisNodes(t, broker, 3)
+		nodes[1].server.webConnCount = 1
+		nodes[2].server.webConnCount = 2
+
+		stats, appErr := nodes[0].cluster.GetClusterStats()
+		require.Nil(t, appErr)
+		assert.Len(t, stats, 2)
+
+		count, appErr := nodes[0].cluster.WebConnCountForUser(model.NewId())
+		require.Nil(t, appErr)
+		assert.Equal(t, 3, count)
+	})
+
+	t.Run("a slow event type doesn't hold up the others", func(t *testing.T) {
+		broker := newFakeBroker()
+		nodes := startRedisNodes(t, broker, 2)
+
+		slowEvent := model.ClusterEvent("slow_event")
+		release := make(chan struct{})
+		var mut sync.Mutex
+		slowReceived := []string{}
+		nodes[1].cluster.RegisterClusterMessageHandler(slowEvent, func(msg *model.ClusterMessage) {
+			<-release
+			mut.Lock()
+			defer mut.Unlock()
+			slowReceived = append(slowReceived, string(msg.Data))
+		})
+
+		for i := 0; i < 5; i++ {
+			nodes[0].cluster.SendClusterMessage(&model.ClusterMessage{Event: slowEvent, Data: []byte(fmt.Sprint(i))})
+		}
+		nodes[0].cluster.SendClusterMessage(&model.ClusterMessage{Event: testEvent, Data: []byte("fast")})
+
+		require.Eventually(t, func() bool {
+			return len(nodes[1].server.received()) == 1
+		}, 5*time.Second, 10*time.Millisecond)
+
+		close(release)
+		require.Eventually(t, func() bool {
+			mut.Lock()
+			defer mut.Unlock()
+			return len(slowReceived) == 5
+		}, 5*time.Second, 10*time.Millisecond)
+		assert.Equal(t, []string{"0", "1", "2", "3", "4"}, slowReceived)
+	})
+
+	t.Run("resubscribe after losing the connection", func(t *testing.T) {
+		broker := newFakeBroker()
+		nodes := startRedisNodes(t, broker, 2)
+		var invalidated atomic.Int32
+		nodes[1].cluster.RegisterClusterMessageHandler(model.ClusterEventInvalidateAllCaches, func(msg *model.ClusterMessage) {
+			invalidated.Add(1)
+		})
+
+		broker.disconnect()
+		require.Eventually(t, func() bool {
+			return broker.count() == 2
+		}, 5*time.Second, 10*time.Millisecond)
+
+		nodes[0].cluster.SendClusterMessage(&model.ClusterMessage{Event: testEvent, Data: []byte("again")})
+		require.Eventually(t, func() bool {
+			return len(nodes[1].server.received()) == 1
+		}, 5*time.Second, 10*time.Millisecond)
+
+		for _, node := range nodes {
+			node.server.metrics.(*mocks.MetricsInterface).AssertCalled(t, "IncrementClusterTransportReconnect")
+		}
+		// The cache invalidations published while the node wasn't subscribed are lost.
+		assert.NotZero(t, invalidated.Load())
+	})
+
+	t.Run("unsealed and replayed messages are rejected", func(t *testing.T) {
+		broker := newFakeBroker()
+		nodes := startRedisNodes(t, broker, 2)
+		transport := nodes[0].cluster.transport.(*redisTransport)
+
+		var published [][]byte
+		publish := func(ctx context.Context, channel string, buf []byte) (int64, error) {
+			published = append(published, buf)
+			return broker.Publish(ctx, channel, buf)
+		}
+		transport.pubSub = &recordingPubSub{pubSub: broker, publish: publish}
+
+		nodes[0].cluster.SendClusterMessage(&model.ClusterMessage{Event: testEvent, WaitForAllToSend: true, Data: []byte("sealed")})
+		require.Eventually(t, func() bool {
+			return len(nodes[1].server.received()) == 1
+		}, 5*time.Second, 10*time.Millisecond)
+		require.Len(t, published, 1)
+
+		channel := transport.channel("all", testEvent)
+		_, err := broker.Publish(context.Background(), channel, published[0])
+		require.NoError(t, err)
+		_, err = broker.Publish(context.Background(), channel, []byte(`{"from":"x","message":{"event":"test_event"}}`))
+		require.NoError(t, err)
+		_, err = broker.Publish(context.Background(), transport.channel("all", "unknown_event"), []byte("unsealed"))
+		require.NoError(t, err)
+
+		nodes[0].cluster.SendClusterMessage(&model.ClusterMessage{Event: testEvent, WaitForAllToSend: true, Data: []byte("last")})
+		require.Eventually(t, func() bool {
+			return len(nodes[1].server.received()) == 2
+		}, 5*time.Second, 10*time.Millisecond)
+		assert.Equal(t, []byte("last"), nodes[1].server.received()[1].Data)
+
+		// The rejected messages don't start a goroutine for their event type.
+		receiver := nodes[1].cluster.transport.(*redisTransport)
+		receiver.mut.Lock()
+		defer receiver.mut.Unlock()
+		assert.NotContains(t, receiver.queues, model.ClusterEvent("unknown_event"))
+	})
+
+	t.Run("send to a node that isn't subscribed", func(t *testing.T) {
+		broker := newFakeBroker()
+		nodes := startRedisNodes(t, broker, 1)
+
+		err := nodes[0].cluster.transport.Send(context.Background(), testEvent, &model.ClusterDiscovery{Id: model.NewId()}, []byte("{}"))
+		require.Error(t, err)
+	})
+
+	t.Run("stop", func(t *testing.T) {
+		broker := newFakeBroker()
+		nodes := startRedisNodes(t, broker, 1)
+		require.Equal(t, 1, broker.count())
+
+		nodes[0].cluster.StopInterNodeCommunication()
+		assert.Equal(t, 0, broker.count())
+	})
+}
+
+func TestEscapeRedisPattern(t *testing.T) {
+	assert.Equal(t, "cluster", escapeRedisPattern("cluster"))
+	assert.Equal(t, `a\*b\?c\[d\]e\\f`, escapeRedisPattern(`a*b?c[d]e\f`))
+
+	pattern := escapeRedisPattern("mattermost_cluster:my*cluster:all:") + "*"
+	ok, err := path.Match(pattern, "mattermost_cluster:my*cluster:all:publish")
+	require.NoError(t, err)
+	assert.True(t, ok)
+	ok, err = path.Match(pattern, "mattermost_cluster:my-other-cluster:all:publish")
+	require.NoError(t, err)
+	assert.False(t, ok)
+}
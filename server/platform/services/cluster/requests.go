@@ -0,0 +1,408 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package cluster
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"path"
+	"strconv"
+	"time"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+	"github.com/mattermost/mattermost/server/public/shared/request"
+)
+
+const (
+	requestTimeout              = 15 * time.Second
+	supportPacketRequestTimeout = 2 * time.Minute
+)
+
+// The responses to each of the requests the nodes send to each other.
+var gossipResponses = map[model.ClusterEvent]model.ClusterEvent{
+	model.ClusterGossipEventRequestGetLogs:               model.ClusterGossipEventResponseGetLogs,
+	model.ClusterGossipEventRequestGenerateSupportPacket: model.ClusterGossipEventResponseGenerateSupportPacket,
+	model.ClusterGossipEventRequestGetClusterStats:       model.ClusterGossipEventResponseGetClusterStats,
+	model.ClusterGossipEventRequestGetPluginStatuses:     model.ClusterGossipEventResponseGetPluginStatuses,
+	model.ClusterGossipEventRequestSaveConfig:            model.ClusterGossipEventResponseSaveConfig,
+	model.ClusterGossipEventRequestWebConnCount:          model.ClusterGossipEventResponseWebConnCount,
+}
+
+func isGossipRequest(event model.ClusterEvent) bool {
+	_, ok := gossipResponses[event]
+	return ok
+}
+
+func isGossipResponse(event model.ClusterEvent) bool {
+	for _, response := range gossipResponses {
+		if event == response {
+			return true
+		}
+	}
+	return false
+}
+
+type logsRequest struct {
+	Page    int `json:"page"`
+	PerPage int `json:"per_page"`
+}
+
+type nodeLogs struct {
+	Hostname string   `json:"hostname"`
+	Lines    []string `json:"lines"`
+}
+
+// request sends a request to every node that is alive, and returns the responses received
+// before the timeout, keyed by node ID.
+func (c *Cluster) request(event model.ClusterEvent, data any, timeout time.Duration) (map[string]*model.ClusterMessage, *model.AppError) {
+	c.mut.RLock()
+	running := c.running
+	c.mut.RUnlock()
+	if !running {
+		return map[string]*model.ClusterMessage{}, nil
+	}
+
+	var buf []byte
+	if data != nil {
+		var err error
+		if buf, err = json.Marshal(data); err != nil {
+			return nil, model.NewAppError("ClusterRequest", "ent.cluster.json_encode.error", nil, "", http.StatusInternalServerError).Wrap(err)
+		}
+	}
+
+	requestID := model.NewId()
+	msg, err := json.Marshal(&envelope{
+		From: c.id,
+		Message: &model.ClusterMessage{
+			Event: event,
+			Data:  buf,
+			Props: map[string]string{"request_id": requestID},
+		},
+	})
+	if err != nil {
+		return nil, model.NewAppError("ClusterRequest", "ent.cluster.json_encode.error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+
+	nodes := c.alivePeers()
+	responses := make(chan *envelope, len(nodes))
+	c.requestsMut.Lock()
+	c.requests[requestID] = responses
+	c.requestsMut.Unlock()
+	defer func() {
+		c.requestsMut.Lock()
+		delete(c.requests, requestID)
+		c.requestsMut.Unlock()
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	if err := c.transport.Broadcast(ctx, event, nodes, msg); err != nil {
+		c.logger().Warn("Failed to send the cluster request to some nodes.", mlog.String("event", string(event)), mlog.Err(err))
+	}
+
+	results := make(map[string]*model.ClusterMessage, len(nodes))
+	for len(results) < len(nodes) {
+		select {
+		case env := <-responses:
+			results[env.From] = env.Message
+		case <-ctx.Done():
+			return results, model.NewAppError("ClusterRequest", "ent.cluster.timeout.error", nil, "event="+string(event), http.StatusGatewayTimeout)
+		}
+	}
+	return results, nil
+}
+
+func (c *Cluster) handleResponse(env *envelope) {
+	c.requestsMut.Lock()
+	responses, ok := c.requests[env.Message.Props["request_id"]]
+	c.requestsMut.Unlock()
+	if !ok {
+		// The request timed out.
+		return
+	}
+
+	select {
+	case responses <- env:
+	default:
+	}
+}
+
+func (c *Cluster) handleRequest(env *envelope) {
+	msg := env.Message
+	data, err := c.answerRequest(msg)
+
+	response := &model.ClusterMessage{
+		Event: gossipResponses[msg.Event],
+		Data:  data,
+		Props: map[string]string{"request_id": msg.Props["request_id"]},
+	}
+	if err != nil {
+		c.logger().Warn("Failed to answer the cluster request.", mlog.String("event", string(msg.Event)), mlog.String("from", env.From), mlog.Err(err))
+		response.Props["error"] = err.Error()
+	}
+
+	buf, err := json.Marshal(&envelope{From: c.id, Message: response})
+	if err != nil {
+		return
+	}
+
+	c.mut.RLock()
+	var node *model.ClusterDiscovery
+	if p, ok := c.peers[env.From]; ok {
+		node = p.discovery
+	}
+	c.mut.RUnlock()
+	if node == nil {
+		c.logger().Warn("Received a cluster request from an unknown node.", mlog.String("from", env.From))
+		return
+	}
+	if err := c.transport.Send(context.Background(), response.Event, node, buf); err != nil {
+		c.logger().Warn("Failed to send the cluster response.", mlog.String("to", env.From), mlog.Err(err))
+	}
+}
+
+func (c *Cluster) answerRequest(msg *model.ClusterMessage) ([]byte, error) {
+	rctx := request.EmptyContext(c.logger())
+
+	switch msg.Event {
+	case model.ClusterGossipEventRequestGetClusterStats:
+		return json.Marshal(&model.ClusterStats{
+			Id:                        c.id,
+			TotalWebsocketConnections: c.server.TotalWebsocketConnections(),
+			TotalReadDbConnections:    c.server.GetStore().TotalReadDbConnections(),
+			TotalMasterDbConnections:  c.server.GetStore().TotalMasterDbConnections(),
+		})
+
+	case model.ClusterGossipEventRequestGetLogs:
+		var req logsRequest
+		if err := json.Unmarshal(msg.Data, &req); err != nil {
+			return nil, err
+		}
+		lines, appErr := c.server.GetLogsSkipSend(req.Page, req.PerPage, &model.LogFilter{})
+		if appErr != nil {
+			return nil, appErr
+		}
+		return json.Marshal(&nodeLogs{Hostname: c.hostname(), Lines: lines})
+
+	case model.ClusterGossipEventRequestGetPluginStatuses:
+		statuses, appErr := c.server.GetPluginStatuses()
+		if appErr != nil {
+			return nil, appErr
+		}
+		return json.Marshal(statuses)
+
+	case model.ClusterGossipEventRequestWebConnCount:
+		var userID string
+		if err := json.Unmarshal(msg.Data, &userID); err != nil {
+			return nil, err
+		}
+		return []byte(strconv.Itoa(c.server.WebConnCountForUser(userID))), nil
+
+	case model.ClusterGossipEventRequestSaveConfig:
+		return nil, c.server.ReloadConfig()
+
+	case model.ClusterGossipEventRequestGenerateSupportPacket:
+		var options model.SupportPacketOptions
+		if err := json.Unmarshal(msg.Data, &options); err != nil {
+			return nil, err
+		}
+		return json.Marshal(c.supportPacketFiles(rctx, &options))
+	}
+
+	return nil, nil
+}
+
+func (c *Cluster) hostname() string {
+	c.mut.RLock()
+	defer c.mut.RUnlock()
+	return c.discovery.Hostname
+}
+
+// supportPacketFiles returns the files of this node to add to a Support Packet, in a directory
+// named after the node.
+func (c *Cluster) supportPacketFiles(rctx request.CTX, options *model.SupportPacketOptions) []model.FileData {
+	functions := []func(request.CTX) (*model.FileData, error){c.server.CreateGoroutineProfile}
+	if options.IncludeLogs {
+		functions = append(functions, c.server.GetLogFile)
+	}
+
+	files := []model.FileData{}
+	for _, fn := range functions {
+		file, err := fn(rctx)
+		if err != nil {
+			rctx.Logger().Warn("Failed to generate a file of the Support Packet.", mlog.Err(err))
+			continue
+		}
+		file.Filename = path.Join(c.hostname(), file.Filename)
+		files = append(files, *file)
+	}
+	return files
+}
+
+func responseError(msg *model.ClusterMessage) string {
+	if msg.Props == nil {
+		return ""
+	}
+	return msg.Props["error"]
+}
+
+// GetClusterStats returns the stats of the other nodes.
+func (c *Cluster) GetClusterStats() ([]*model.ClusterStats, *model.AppError) {
+	responses, appErr := c.request(model.ClusterGossipEventRequestGetClusterStats, nil, requestTimeout)
+	if appErr != nil {
+		return nil, appErr
+	}
+
+	stats := make([]*model.ClusterStats, 0, len(responses))
+	for nodeID, response := range responses {
+		var stat model.ClusterStats
+		if err := json.Unmarshal(response.Data, &stat); err != nil {
+			c.logger().Warn("Failed to decode the cluster stats.", mlog.String("node_id", nodeID), mlog.Err(err))
+			continue
+		}
+		stats = append(stats, &stat)
+	}
+	return stats, nil
+}
+
+// GetLogs returns the logs of the other nodes, each one preceded by the hostname of the node.
+func (c *Cluster) GetLogs(page, perPage int) ([]string, *model.AppError) {
+	logs, appErr := c.queryLogs(page, perPage)
+	if appErr != nil {
+		return nil, appErr
+	}
+
+	lines := []string{}
+	for _, node := range logs {
+		lines = append(lines, "-----------------------------------------------------------------------------------------------------------")
+		lines = append(lines, "-----------------------------------------------------------------------------------------------------------")
+		lines = append(lines, node.Hostname)
+		lines = append(lines, "-----------------------------------------------------------------------------------------------------------")
+		lines = append(lines, "-----------------------------------------------------------------------------------------------------------")
+		lines = append(lines, node.Lines...)
+	}
+	return lines, nil
+}
+
+// QueryLogs returns the logs of the other nodes, keyed by their hostname.
+func (c *Cluster) QueryLogs(page, perPage int) (map[string][]string, *model.AppError) {
+	logs, appErr := c.queryLogs(page, perPage)
+	if appErr != nil {
+		return nil, appErr
+	}
+
+	result := make(map[string][]string, len(logs))
+	for _, node := range logs {
+		result[node.Hostname] = node.Lines
+	}
+	return result, nil
+}
+
+func (c *Cluster) queryLogs(page, perPage int) ([]*nodeLogs, *model.AppError) {
+	responses, appErr := c.request(model.ClusterGossipEventRequestGetLogs, &logsRequest{Page: page, PerPage: perPage}, requestTimeout)
+	if appErr != nil {
+		return nil, appErr
+	}
+
+	logs := make([]*nodeLogs, 0, len(responses))
+	for nodeID, response := range responses {
+		if errMsg := responseError(response); errMsg != "" {
+			c.logger().Warn("Failed to get the logs of a cluster node.", mlog.String("node_id", nodeID), mlog.String("error", errMsg))
+			continue
+		}
+		var node nodeLogs
+		if err := json.Unmarshal(response.Data, &node); err != nil {
+			c.logger().Warn("Failed to decode the logs of a cluster node.", mlog.String("node_id", nodeID), mlog.Err(err))
+			continue
+		}
+		logs = append(logs, &node)
+	}
+	return logs, nil
+}
+
+// GenerateSupportPacket returns the Support Packet files of the other nodes, keyed by node ID.
+func (c *Cluster) GenerateSupportPacket(rctx request.CTX, options *model.SupportPacketOptions) (map[string][]model.FileData, error) {
+	responses, appErr := c.request(model.ClusterGossipEventRequestGenerateSupportPacket, options, supportPacketRequestTimeout)
+	if appErr != nil {
+		return nil, appErr
+	}
+
+	files := make(map[string][]model.FileData, len(responses))
+	for nodeID, response := range responses {
+		var nodeFiles []model.FileData
+		if err := json.Unmarshal(response.Data, &nodeFiles); err != nil {
+			rctx.Logger().Warn("Failed to decode the Support Packet files of a cluster node.", mlog.String("node_id", nodeID), mlog.Err(err))
+			continue
+		}
+		files[nodeID] = nodeFiles
+	}
+	return files, nil
+}
+
+// GetPluginStatuses returns the statuses of the plugins of the other nodes.
+func (c *Cluster) GetPluginStatuses() (model.PluginStatuses, *model.AppError) {
+	responses, appErr := c.request(model.ClusterGossipEventRequestGetPluginStatuses, nil, requestTimeout)
+	if appErr != nil {
+		return nil, appErr
+	}
+
+	statuses := model.PluginStatuses{}
+	for nodeID, response := range responses {
+		if errMsg := responseError(response); errMsg != "" {
+			c.logger().Warn("Failed to get the plugin statuses of a cluster node.", mlog.String("node_id", nodeID), mlog.String("error", errMsg))
+			continue
+		}
+		var nodeStatuses model.PluginStatuses
+		if err := json.Unmarshal(response.Data, &nodeStatuses); err != nil {
+			c.logger().Warn("Failed to decode the plugin statuses of a cluster node.", mlog.String("node_id", nodeID), mlog.Err(err))
+			continue
+		}
+		statuses = append(statuses, nodeStatuses...)
+	}
+	return statuses, nil
+}
+
+// WebConnCountForUser returns the number of websocket connections of the user on the other
+// nodes. It fails if any node didn't answer, so that the user isn't set offline by mistake.
+func (c *Cluster) WebConnCountForUser(userID string) (int, *model.AppError) {
+	responses, appErr := c.request(model.ClusterGossipEventRequestWebConnCount, userID, requestTimeout)
+	if appErr != nil {
+		return 0, appErr
+	}
+
+	count := 0
+	for nodeID, response := range responses {
+		if errMsg := responseError(response); errMsg != "" {
+			return 0, model.NewAppError("WebConnCountForUser", "ent.cluster.json_encode.error", nil, "node_id="+nodeID+", "+errMsg, http.StatusInternalServerError)
+		}
+		nodeCount, err := strconv.Atoi(string(response.Data))
+		if err != nil {
+			return 0, model.NewAppError("WebConnCountForUser", "ent.cluster.json_encode.error", nil, "node_id="+nodeID, http.StatusInternalServerError).Wrap(err)
+		}
+		count += nodeCount
+	}
+	return count, nil
+}
+
+// ConfigChanged asks the other nodes to reload the configuration, when it was changed on this
+// node.
+func (c *Cluster) ConfigChanged(previousConfig *model.Config, newConfig *model.Config, sendToOtherServer bool) *model.AppError {
+	if !sendToOtherServer {
+		return nil
+	}
+
+	responses, appErr := c.request(model.ClusterGossipEventRequestSaveConfig, nil, requestTimeout)
+	if appErr != nil {
+		return appErr
+	}
+	for nodeID, response := range responses {
+		if errMsg := responseError(response); errMsg != "" {
+			c.logger().Error("A cluster node failed to reload the configuration.", mlog.String("node_id", nodeID), mlog.String("error", errMsg))
+		}
+	}
+	return nil
+}
@@ -0,0 +1,223 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package cluster
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// A sealed message starts with a header holding the time it was sealed at, a random nonce
+	// and the length of the body, followed by the signature of the header.
+	sealNonceSize     = 16
+	sealHeaderSize    = 8 + sealNonceSize + 4
+	sealSignatureSize = sha256.Size
+	sealPrefixSize    = sealHeaderSize + sealSignatureSize
+
+	// The messages sealed longer ago, or further in the future, are rejected, which bounds how
+	// long the nonces of the received messages must be remembered.
+	maxSealedMessageAge = 2 * time.Minute
+
+	sealHeaderPurpose = "header"
+	sealBodyPurpose   = "body"
+
+	// sealBroadcastDestination is the destination of the messages sent to every node at once.
+	sealBroadcastDestination = "all"
+)
+
+// messageSealer authenticates the messages exchanged by the nodes with the key shared by the
+// cluster, and encrypts them when gossip encryption is enabled.
+//
+// The header of a message is signed on its own, so that a receiver can reject the messages of
+// unauthenticated senders before reading their body. The nonce of each received message is
+// remembered until its timestamp expires, so that a message can't be replayed. The message is
+// also bound to its destination, the id of the receiving node or sealBroadcastDestination, so
+// that a message sent to one node can't be delivered to another.
+type messageSealer struct {
+	key     []byte
+	encrypt bool
+	name    string
+	now     func() time.Time
+
+	mut          sync.Mutex
+	seen         map[[sealNonceSize]byte]struct{}
+	previousSeen map[[sealNonceSize]byte]struct{}
+	rotatedAt    time.Time
+}
+
+func newMessageSealer(key []byte, encrypt bool, name string) *messageSealer {
+	return &messageSealer{
+		key:          key,
+		encrypt:      encrypt,
+		name:         name,
+		now:          time.Now,
+		seen:         make(map[[sealNonceSize]byte]struct{}),
+		previousSeen: make(map[[sealNonceSize]byte]struct{}),
+	}
+}
+
+// seal returns the message to send for buf to the given destination.
+func (s *messageSealer) seal(buf []byte, destination string) ([]byte, error) {
+	var nonce [sealNonceSize]byte
+	if _, err := rand.Read(nonce[:]); err != nil {
+		return nil, errors.Wrap(err, "unable to generate the message nonce")
+	}
+
+	bodySize := len(buf) + sealSignatureSize
+	var gcm cipher.AEAD
+	if s.encrypt {
+		var err error
+		if gcm, err = newGCM(s.key); err != nil {
+			return nil, err
+		}
+		bodySize = len(buf) + gcm.Overhead()
+	}
+
+	message := make([]byte, sealPrefixSize, sealPrefixSize+bodySize)
+	binary.BigEndian.PutUint64(message, uint64(s.now().UnixMilli()))
+	copy(message[8:], nonce[:])
+	binary.BigEndian.PutUint32(message[8+sealNonceSize:], uint32(bodySize))
+	header := message[:sealHeaderSize]
+	copy(message[sealHeaderSize:], s.signature(sealHeaderPurpose, destination, header))
+
+	if gcm != nil {
+		return gcm.Seal(message, nonce[:gcm.NonceSize()], buf, s.additionalData(destination, header)), nil
+	}
+	message = append(message, buf...)
+	return append(message, s.signature(sealBodyPurpose, destination, header, buf)...), nil
+}
+
+// openPrefix checks the header of a message received for the given destination and its
+// signature, and returns the length of the body that follows them.
+func (s *messageSealer) openPrefix(prefix []byte, destination string) (int, error) {
+	if len(prefix) != sealPrefixSize {
+		return 0, errors.New("the message is too short")
+	}
+	header := prefix[:sealHeaderSize]
+	if !hmac.Equal(prefix[sealHeaderSize:], s.signature(sealHeaderPurpose, destination, header)) {
+		return 0, errors.New("invalid message signature")
+	}
+
+	sealedAt := time.UnixMilli(int64(binary.BigEndian.Uint64(header)))
+	if age := s.now().Sub(sealedAt); age > maxSealedMessageAge || age < -maxSealedMessageAge {
+		return 0, errors.Errorf("the message was sealed at %s, which is out of the accepted time window", sealedAt)
+	}
+
+	bodySize := int64(binary.BigEndian.Uint32(header[8+sealNonceSize:]))
+	if bodySize > maxClusterMessageSize {
+		return 0, errors.New("the message is too large")
+	}
+	return int(bodySize), nil
+}
+
+// openBody checks the body of a message whose prefix was checked by openPrefix, and returns its
+// contents, decrypting them if needed. Each message can only be opened once.
+func (s *messageSealer) openBody(prefix, body []byte, destination string) ([]byte, error) {
+	header := prefix[:sealHeaderSize]
+	var nonce [sealNonceSize]byte
+	copy(nonce[:], header[8:])
+
+	var buf []byte
+	if s.encrypt {
+		gcm, err := newGCM(s.key)
+		if err != nil {
+			return nil, err
+		}
+		buf, err = gcm.Open(nil, nonce[:gcm.NonceSize()], body, s.additionalData(destination, header))
+		if err != nil {
+			return nil, errors.Wrap(err, "unable to decrypt the message")
+		}
+	} else {
+		if len(body) < sealSignatureSize {
+			return nil, errors.New("the message is too short")
+		}
+		buf = body[:len(body)-sealSignatureSize]
+		if !hmac.Equal(body[len(buf):], s.signature(sealBodyPurpose, destination, header, buf)) {
+			return nil, errors.New("invalid message signature")
+		}
+	}
+
+	if !s.markSeen(nonce) {
+		return nil, errors.New("the message was already received")
+	}
+	return buf, nil
+}
+
+// open checks a whole message received for the given destination and returns its contents.
+func (s *messageSealer) open(message []byte, destination string) ([]byte, error) {
+	if len(message) < sealPrefixSize {
+		return nil, errors.New("the message is too short")
+	}
+	bodySize, err := s.openPrefix(message[:sealPrefixSize], destination)
+	if err != nil {
+		return nil, err
+	}
+	if len(message)-sealPrefixSize != bodySize {
+		return nil, errors.New("the message doesn't have the expected length")
+	}
+	return s.openBody(message[:sealPrefixSize], message[sealPrefixSize:], destination)
+}
+
+// markSeen records the nonce of a received message, and returns false if it was already
+// received. The nonces are kept in two generations, each covering twice the accepted time
+// window, so that a nonce is remembered for at least as long as its message is accepted.
+func (s *messageSealer) markSeen(nonce [sealNonceSize]byte) bool {
+	s.mut.Lock()
+	defer s.mut.Unlock()
+
+	if now := s.now(); now.Sub(s.rotatedAt) > 2*maxSealedMessageAge {
+		s.previousSeen = s.seen
+		s.seen = make(map[[sealNonceSize]byte]struct{})
+		s.rotatedAt = now
+	}
+
+	if _, ok := s.seen[nonce]; ok {
+		return false
+	}
+	if _, ok := s.previousSeen[nonce]; ok {
+		return false
+	}
+	s.seen[nonce] = struct{}{}
+	return true
+}
+
+func (s *messageSealer) additionalData(destination string, header []byte) []byte {
+	data := make([]byte, 0, len(s.name)+1+len(destination)+1+len(header))
+	data = append(data, s.name...)
+	data = append(data, 0)
+	data = append(data, destination...)
+	data = append(data, 0)
+	return append(data, header...)
+}
+
+func (s *messageSealer) signature(purpose, destination string, parts ...[]byte) []byte {
+	mac := hmac.New(sha256.New, s.key)
+	mac.Write([]byte(purpose))
+	mac.Write([]byte{0})
+	mac.Write([]byte(s.name))
+	mac.Write([]byte{0})
+	mac.Write([]byte(destination))
+	mac.Write([]byte{0})
+	for _, part := range parts {
+		mac.Write(part)
+	}
+	return mac.Sum(nil)
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,121 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package cluster
+
+import (
+	"crypto/sha256"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMessageSealer(t *testing.T) {
+	key := sha256.Sum256([]byte("key"))
+
+	for _, encrypt := range []bool{false, true} {
+		name := "signed"
+		if encrypt {
+			name = "encrypted"
+		}
+
+		t.Run(name, func(t *testing.T) {
+			newSealers := func() (*messageSealer, *messageSealer) {
+				return newMessageSealer(key[:], encrypt, "cluster"), newMessageSealer(key[:], encrypt, "cluster")
+			}
+
+			t.Run("messages are opened", func(t *testing.T) {
+				sender, receiver := newSealers()
+				message, err := sender.seal([]byte("message"), "node")
+				require.NoError(t, err)
+
+				buf, err := receiver.open(message, "node")
+				require.NoError(t, err)
+				assert.Equal(t, []byte("message"), buf)
+			})
+
+			t.Run("replayed messages are rejected", func(t *testing.T) {
+				sender, receiver := newSealers()
+				message, err := sender.seal([]byte("message"), "node")
+				require.NoError(t, err)
+
+				_, err = receiver.open(message, "node")
+				require.NoError(t, err)
+				_, err = receiver.open(message, "node")
+				require.Error(t, err)
+			})
+
+			t.Run("replayed messages are rejected after rotating the nonces", func(t *testing.T) {
+				sender, receiver := newSealers()
+				now := time.Now()
+				sender.now = func() time.Time { return now }
+				receiver.now = func() time.Time { return now }
+				message, err := sender.seal([]byte("message"), "node")
+				require.NoError(t, err)
+				_, err = receiver.open(message, "node")
+				require.NoError(t, err)
+
+				now = now.Add(maxSealedMessageAge)
+				other, err := sender.seal([]byte("other"), "node")
+				require.NoError(t, err)
+				_, err = receiver.open(other, "node")
+				require.NoError(t, err)
+
+				_, err = receiver.open(message, "node")
+				require.Error(t, err)
+			})
+
+			t.Run("expired messages are rejected", func(t *testing.T) {
+				sender, receiver := newSealers()
+				sender.now = func() time.Time { return time.Now().Add(-maxSealedMessageAge - time.Second) }
+				message, err := sender.seal([]byte("message"), "node")
+				require.NoError(t, err)
+
+				_, err = receiver.open(message, "node")
+				require.Error(t, err)
+			})
+
+			t.Run("tampered messages are rejected", func(t *testing.T) {
+				sender, receiver := newSealers()
+				message, err := sender.seal([]byte("message"), "node")
+				require.NoError(t, err)
+
+				for _, index := range []int{0, sealHeaderSize, sealPrefixSize, len(message) - 1} {
+					tampered := append([]byte{}, message...)
+					tampered[index] ^= 1
+					_, err = receiver.open(tampered, "node")
+					require.Error(t, err, index)
+				}
+			})
+
+			t.Run("messages sent to another destination are rejected", func(t *testing.T) {
+				sender, receiver := newSealers()
+				message, err := sender.seal([]byte("message"), "other")
+				require.NoError(t, err)
+
+				_, err = receiver.open(message, "node")
+				require.Error(t, err)
+				_, err = receiver.open(message, sealBroadcastDestination)
+				require.Error(t, err)
+
+				broadcast, err := sender.seal([]byte("message"), sealBroadcastDestination)
+				require.NoError(t, err)
+				_, err = receiver.open(broadcast, "node")
+				require.Error(t, err)
+				_, err = receiver.open(broadcast, sealBroadcastDestination)
+				require.NoError(t, err)
+			})
+
+			t.Run("messages of another cluster are rejected", func(t *testing.T) {
+				sender, _ := newSealers()
+				message, err := sender.seal([]byte("message"), "node")
+				require.NoError(t, err)
+
+				_, err = newMessageSealer(key[:], encrypt, "other").open(message, "node")
+				require.Error(t, err)
+			})
+		})
+	}
+}
@@ -0,0 +1,23 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package cluster
+
+import (
+	"context"
+
+	"github.com/mattermost/mattermost/server/public/model"
+)
+
+// Transport delivers the messages of the nodes of a cluster to each other.
+type Transport interface {
+	// Start starts receiving the messages sent to the node, and passes them to receive. The
+	// messages of a given event type must be passed in the order they were sent.
+	Start(receive func(buf []byte)) error
+	Stop()
+	// Broadcast sends a message to the given nodes. Transports that reach every node at once
+	// may ignore the list.
+	Broadcast(ctx context.Context, event model.ClusterEvent, nodes []*model.ClusterDiscovery, buf []byte) error
+	// Send sends a message to a single node.
+	Send(ctx context.Context, event model.ClusterEvent, node *model.ClusterDiscovery, buf []byte) error
+}
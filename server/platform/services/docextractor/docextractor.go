@@ -5,6 +5,7 @@ package docextractor
 
 import (
 	"io"
+	"time"
 
 	"github.com/mattermost/mattermost/server/public/shared/mlog"
 )
@@ -14,6 +15,13 @@ type ExtractSettings struct {
 	ArchiveRecursion bool
 	MMPreviewURL     string
 	MMPreviewSecret  string
+	// OCRCommand is the tesseract compatible command recognizing the text of images and
+	// scanned documents. OCR is disabled if empty.
+	OCRCommand   string
+	OCRLanguages string
+	OCRTimeout   time.Duration
+	// OOXMLLimits overrides the default limits of the OOXML formats, by file extension.
+	OOXMLLimits map[string]OOXMLLimits
 }
 
 // Extract extract the text from a document using the system default extractors
@@ -29,6 +37,10 @@ func ExtractWithExtraExtractors(logger mlog.LoggerIFace, filename string, r io.R
 	for _, extraExtractor := range extraExtractors {
 		enabledExtractors.Add(extraExtractor)
 	}
+	if settings.OCRCommand != "" {
+		enabledExtractors.Add(newOCRExtractor(settings.OCRCommand, settings.OCRLanguages, settings.OCRTimeout, pdfExtractor{}))
+	}
+	enabledExtractors.Add(newOOXMLExtractor(settings.OOXMLLimits))
 	enabledExtractors.Add(&documentExtractor{})
 	enabledExtractors.Add(&pdfExtractor{})
 
@@ -0,0 +1,200 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package docextractor
+
+// The OCR extractor recognizes the text of images, and of the PDF documents without a text
+// layer such as scanned documents, using a local tesseract compatible command run as:
+//
+//	<command> stdin stdout [-l <languages>]
+//
+// The command reads the image from its standard input and writes the recognized text to its
+// standard output.
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"os/exec"
+	"path"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	defaultOCRTimeout = 60 * time.Second
+
+	// ocrMaxPDFImages is the maximum number of images of a PDF document being recognized.
+	ocrMaxPDFImages = 50
+
+	ocrMaxErrorOutputBytes = 512
+)
+
+var ocrSupportedExtensions = map[string]bool{
+	"png":  true,
+	"jpg":  true,
+	"jpeg": true,
+	"gif":  true,
+	"bmp":  true,
+	"tif":  true,
+	"tiff": true,
+}
+
+// IsOCRSupported returns true if the text of the images with the given file name can be
+// extracted when OCR is enabled.
+func IsOCRSupported(filename string) bool {
+	extension := strings.ToLower(strings.TrimPrefix(path.Ext(filename), "."))
+	return ocrSupportedExtensions[extension]
+}
+
+type ocrExtractor struct {
+	command      string
+	languages    string
+	timeout      time.Duration
+	pdfExtractor pdfExtractor
+}
+
+func newOCRExtractor(command, languages string, timeout time.Duration, pdfExtractor pdfExtractor) *ocrExtractor {
+	if timeout <= 0 {
+		timeout = defaultOCRTimeout
+	}
+	return &ocrExtractor{command: command, languages: languages, timeout: timeout, pdfExtractor: pdfExtractor}
+}
+
+func (oe *ocrExtractor) Name() string {
+	return "ocrExtractor"
+}
+
+func (oe *ocrExtractor) Match(filename string) bool {
+	return IsOCRSupported(filename) || oe.pdfExtractor.Match(filename)
+}
+
+func (oe *ocrExtractor) Extract(filename string, r io.ReadSeeker) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), oe.timeout)
+	defer cancel()
+
+	if IsOCRSupported(filename) {
+		return oe.recognize(ctx, r)
+	}
+
+	// Only the documents without any text are recognized, as the text of the others is
+	// more accurate than what OCR could find.
+	text, pdfErr := oe.pdfExtractor.Extract(filename, r)
+	if pdfErr == nil && strings.TrimSpace(text) != "" {
+		return text, nil
+	}
+
+	if _, err := r.Seek(0, io.SeekStart); err != nil {
+		return "", errors.Wrap(err, "unable to read the document")
+	}
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return "", errors.Wrap(err, "unable to read the document")
+	}
+
+	images := pdfJPEGImages(data, ocrMaxPDFImages)
+	if len(images) == 0 {
+		return text, pdfErr
+	}
+
+	var recognized strings.Builder
+	for _, image := range images {
+		imageText, err := oe.recognize(ctx, bytes.NewReader(image))
+		if err != nil {
+			return "", err
+		}
+		recognized.WriteString(imageText)
+		recognized.WriteString("\n")
+	}
+	return recognized.String(), nil
+}
+
+func (oe *ocrExtractor) recognize(ctx context.Context, image io.Reader) (string, error) {
+	args := []string{"stdin", "stdout"}
+	if oe.languages != "" {
+		args = append(args, "-l", oe.languages)
+	}
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, oe.command, args...)
+	cmd.Stdin = image
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	// Don't wait for the processes started by the command once it is killed.
+	cmd.WaitDelay = time.Second
+
+	if err := cmd.Run(); err != nil {
+		if ctx.Err() != nil {
+			return "", errors.Wrap(ctx.Err(), "OCR command timed out")
+		}
+		output := stderr.String()
+		if len(output) > ocrMaxErrorOutputBytes {
+			output = output[:ocrMaxErrorOutputBytes]
+		}
+		return "", errors.Wrapf(err, "OCR command failed: %s", strings.TrimSpace(output))
+	}
+
+	return stdout.String(), nil
+}
+
+var (
+	pdfStreamKeyword    = []byte("stream")
+	pdfEndStreamKeyword = []byte("endstream")
+	pdfObjKeyword       = []byte("obj")
+	pdfDCTDecodeFilter  = []byte("/DCTDecode")
+	pdfImageSubtype     = regexp.MustCompile(`/Subtype\s*/Image\b`)
+	jpegStartOfImage    = []byte{0xFF, 0xD8}
+)
+
+// pdfDictionaryMaxBytes is the maximum distance between the start of an object and its
+// stream.
+const pdfDictionaryMaxBytes = 4096
+
+// pdfJPEGImages returns the JPEG images embedded in a PDF document, which is how scanners
+// store the pages they scan. The document is searched for the image streams, as the PDF
+// library doesn't decode JPEG streams.
+func pdfJPEGImages(data []byte, maxImages int) [][]byte {
+	var images [][]byte
+	offset := 0
+	for len(images) < maxImages {
+		index := bytes.Index(data[offset:], pdfStreamKeyword)
+		if index < 0 {
+			break
+		}
+		start := offset + index
+
+		dictionaryStart := start - pdfDictionaryMaxBytes
+		if dictionaryStart < offset {
+			dictionaryStart = offset
+		}
+		dictionary := data[dictionaryStart:start]
+		if objIndex := bytes.LastIndex(dictionary, pdfObjKeyword); objIndex >= 0 {
+			dictionary = dictionary[objIndex:]
+		}
+
+		// The stream keyword is followed by an end of line.
+		bodyStart := start + len(pdfStreamKeyword)
+		if bodyStart < len(data) && data[bodyStart] == '\r' {
+			bodyStart++
+		}
+		if bodyStart < len(data) && data[bodyStart] == '\n' {
+			bodyStart++
+		}
+
+		length := bytes.Index(data[bodyStart:], pdfEndStreamKeyword)
+		if length < 0 {
+			break
+		}
+		body := data[bodyStart : bodyStart+length]
+
+		if bytes.Contains(dictionary, pdfDCTDecodeFilter) && pdfImageSubtype.Match(dictionary) && bytes.HasPrefix(body, jpegStartOfImage) {
+			images = append(images, body)
+		}
+
+		offset = bodyStart + length + len(pdfEndStreamKeyword)
+	}
+	return images
+}
@@ -0,0 +1,142 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package docextractor
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+	"github.com/mattermost/mattermost/server/v8/channels/utils/testutils"
+)
+
+// createFakeOCRCommand writes a tesseract compatible command running script.
+func createFakeOCRCommand(t *testing.T, script string) string {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("the fake OCR command is a shell script")
+	}
+
+	command := filepath.Join(t.TempDir(), "tesseract")
+	require.NoError(t, os.WriteFile(command, []byte("#!/bin/sh\n"+script+"\n"), 0700))
+	return command
+}
+
+// fakeOCRScript "recognizes" the text of an image as its contents.
+const fakeOCRScript = `[ "$1" = stdin ] && [ "$2" = stdout ] && [ "$3" = -l ] || { echo "invalid arguments" >&2; exit 1; }
+printf 'recognized %s: ' "$4"
+cat`
+
+func createTestScannedPDF(images ...string) []byte {
+	var pdf bytes.Buffer
+	pdf.WriteString("%PDF-1.4\n")
+	for i, image := range images {
+		data := "\xFF\xD8" + image
+		fmt.Fprintf(&pdf, "%d 0 obj\n<< /Type /XObject /Subtype /Image /Filter /DCTDecode /Length %d >>\nstream\n%s\nendstream\nendobj\n", i+1, len(data), data)
+	}
+	// A stream that is not an image.
+	pdf.WriteString("9 0 obj\n<< /Filter /DCTDecode /Length 4 >>\nstream\n\xFF\xD8no\nendstream\nendobj\n")
+	pdf.WriteString("%%EOF\n")
+	return pdf.Bytes()
+}
+
+func TestOCRExtractor(t *testing.T) {
+	command := createFakeOCRCommand(t, fakeOCRScript)
+	extractor := newOCRExtractor(command, "eng+deu", 0, pdfExtractor{})
+
+	t.Run("match", func(t *testing.T) {
+		assert.True(t, extractor.Match("screenshot.png"))
+		assert.True(t, extractor.Match("photo.JPG"))
+		assert.True(t, extractor.Match("scan.pdf"))
+		assert.False(t, extractor.Match("document.docx"))
+		assert.False(t, extractor.Match("image.svg"))
+	})
+
+	t.Run("image", func(t *testing.T) {
+		text, err := extractor.Extract("screenshot.png", bytes.NewReader([]byte("some text")))
+		require.NoError(t, err)
+		assert.Equal(t, "recognized eng+deu: some text", text)
+	})
+
+	t.Run("pdf with text", func(t *testing.T) {
+		data, err := testutils.ReadTestFile("sample-doc.pdf")
+		require.NoError(t, err)
+
+		text, err := extractor.Extract("sample-doc.pdf", bytes.NewReader(data))
+		require.NoError(t, err)
+		assert.Equal(t, "This is a simple document that contains some text.", text)
+	})
+
+	t.Run("scanned pdf", func(t *testing.T) {
+		text, err := extractor.Extract("scan.pdf", bytes.NewReader(createTestScannedPDF("page1", "page2")))
+		require.NoError(t, err)
+		assert.Equal(t, "recognized eng+deu: \xFF\xD8page1\n\nrecognized eng+deu: \xFF\xD8page2\n\n", text)
+	})
+
+	t.Run("pdf without images", func(t *testing.T) {
+		_, err := extractor.Extract("scan.pdf", bytes.NewReader([]byte("not a pdf")))
+		require.Error(t, err)
+	})
+
+	t.Run("failing command", func(t *testing.T) {
+		extractor := newOCRExtractor(createFakeOCRCommand(t, `echo "failed to load image" >&2; exit 1`), "eng", 0, pdfExtractor{})
+		_, err := extractor.Extract("screenshot.png", bytes.NewReader([]byte("some text")))
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "failed to load image")
+	})
+
+	t.Run("missing command", func(t *testing.T) {
+		extractor := newOCRExtractor(filepath.Join(t.TempDir(), "missing"), "eng", 0, pdfExtractor{})
+		_, err := extractor.Extract("screenshot.png", bytes.NewReader([]byte("some text")))
+		require.Error(t, err)
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		extractor := newOCRExtractor(createFakeOCRCommand(t, `sleep 10`), "eng", 100*time.Millisecond, pdfExtractor{})
+		start := time.Now()
+		_, err := extractor.Extract("screenshot.png", bytes.NewReader([]byte("some text")))
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "timed out")
+		assert.Less(t, time.Since(start), 5*time.Second)
+	})
+}
+
+func TestPDFJPEGImages(t *testing.T) {
+	images := pdfJPEGImages(createTestScannedPDF("page1", "page2", "page3"), 10)
+	require.Len(t, images, 3)
+	assert.Equal(t, []byte("\xFF\xD8page1\n"), images[0])
+
+	images = pdfJPEGImages(createTestScannedPDF("page1", "page2", "page3"), 2)
+	require.Len(t, images, 2)
+
+	assert.Empty(t, pdfJPEGImages([]byte("stream without end"), 10))
+	assert.Empty(t, pdfJPEGImages(nil, 10))
+}
+
+func TestExtractWithOCR(t *testing.T) {
+	logger := mlog.CreateConsoleTestLogger(t)
+	settings := ExtractSettings{
+		OCRCommand:   createFakeOCRCommand(t, fakeOCRScript),
+		OCRLanguages: "eng",
+	}
+
+	text, err := Extract(logger, "screenshot.png", bytes.NewReader([]byte("some text")), settings)
+	require.NoError(t, err)
+	assert.Equal(t, "recognized eng: some text", text)
+
+	// The images are not recognized unless OCR is enabled.
+	data, err := testutils.ReadTestFile("testjpg.jpg")
+	require.NoError(t, err)
+	text, err = Extract(logger, "testjpg.jpg", bytes.NewReader(data), ExtractSettings{})
+	require.NoError(t, err)
+	assert.Empty(t, text)
+}
@@ -0,0 +1,427 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package docextractor
+
+// The OOXML extractor reads the text of the Office Open XML documents (docx, xlsx and pptx)
+// directly from their XML parts. As these documents are zip archives, the resources spent on
+// each of them are bounded to protect the server from zip bombs and oversized documents.
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"io"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// OOXMLLimits bounds the resources spent extracting the text of an OOXML document.
+type OOXMLLimits struct {
+	// MaxFileSize is the maximum size of the document, in bytes.
+	MaxFileSize int64
+	// MaxUncompressedSize is the maximum total size of the uncompressed parts read from the
+	// document, in bytes.
+	MaxUncompressedSize int64
+	// Timeout is the maximum time spent extracting the text of the document.
+	Timeout time.Duration
+}
+
+var defaultOOXMLLimits = map[string]OOXMLLimits{
+	"docx": {MaxFileSize: 50 * 1024 * 1024, MaxUncompressedSize: 100 * 1024 * 1024, Timeout: 30 * time.Second},
+	"pptx": {MaxFileSize: 200 * 1024 * 1024, MaxUncompressedSize: 100 * 1024 * 1024, Timeout: 30 * time.Second},
+	// Spreadsheets are mostly markup, so their parts are allowed to be larger.
+	"xlsx": {MaxFileSize: 50 * 1024 * 1024, MaxUncompressedSize: 250 * 1024 * 1024, Timeout: 60 * time.Second},
+}
+
+var (
+	errOOXMLTooLarge = errors.New("document exceeds the maximum size allowed for text extraction")
+	errOOXMLTimeout  = errors.New("document text extraction timed out")
+)
+
+// ooxmlDeadlineCheckInterval is the number of XML tokens decoded between two deadline checks.
+const ooxmlDeadlineCheckInterval = 1024
+
+type ooxmlExtractor struct {
+	limits map[string]OOXMLLimits
+}
+
+// newOOXMLExtractor returns an extractor using the default limits, except for the formats
+// overridden in limits.
+func newOOXMLExtractor(limits map[string]OOXMLLimits) *ooxmlExtractor {
+	merged := make(map[string]OOXMLLimits, len(defaultOOXMLLimits))
+	for extension, formatLimits := range defaultOOXMLLimits {
+		merged[extension] = formatLimits
+	}
+	for extension, formatLimits := range limits {
+		if _, ok := merged[extension]; ok {
+			merged[extension] = formatLimits
+		}
+	}
+	return &ooxmlExtractor{limits: merged}
+}
+
+func (oe *ooxmlExtractor) Name() string {
+	return "ooxmlExtractor"
+}
+
+func (oe *ooxmlExtractor) Match(filename string) bool {
+	extension := strings.TrimPrefix(path.Ext(filename), ".")
+	_, ok := oe.limits[extension]
+	return ok
+}
+
+func (oe *ooxmlExtractor) Extract(filename string, r io.ReadSeeker) (string, error) {
+	extension := strings.TrimPrefix(path.Ext(filename), ".")
+	limits, ok := oe.limits[extension]
+	if !ok {
+		return "", errors.New("unknown OOXML format")
+	}
+
+	size, err := r.Seek(0, io.SeekEnd)
+	if err != nil {
+		return "", errors.Wrap(err, "unable to get the document size")
+	}
+	if size > limits.MaxFileSize {
+		return "", errOOXMLTooLarge
+	}
+	if _, err = r.Seek(0, io.SeekStart); err != nil {
+		return "", errors.Wrap(err, "unable to read the document")
+	}
+
+	readerAt, ok := r.(io.ReaderAt)
+	if !ok {
+		data, err := io.ReadAll(r)
+		if err != nil {
+			return "", errors.Wrap(err, "unable to read the document")
+		}
+		readerAt = bytes.NewReader(data)
+	}
+
+	archive, err := zip.NewReader(readerAt, size)
+	if err != nil {
+		return "", errors.Wrap(err, "unable to open the document")
+	}
+
+	doc := &ooxmlDocument{
+		archive:   archive,
+		remaining: limits.MaxUncompressedSize,
+		deadline:  time.Now().Add(limits.Timeout),
+	}
+
+	switch extension {
+	case "docx":
+		return doc.docxText()
+	case "xlsx":
+		return doc.xlsxText()
+	default:
+		return doc.pptxText()
+	}
+}
+
+// ooxmlDocument reads the parts of an OOXML document within the limits of its format.
+type ooxmlDocument struct {
+	archive   *zip.Reader
+	remaining int64
+	deadline  time.Time
+}
+
+func (d *ooxmlDocument) part(name string) *zip.File {
+	for _, f := range d.archive.File {
+		if f.Name == name {
+			return f
+		}
+	}
+	return nil
+}
+
+// numberedParts returns the parts named dir/prefix<N>.xml, such as the slides of a
+// presentation, ordered by number.
+func (d *ooxmlDocument) numberedParts(dir, prefix string) []*zip.File {
+	pattern := regexp.MustCompile("^" + regexp.QuoteMeta(dir+"/"+prefix) + `(\d+)\.xml$`)
+
+	type numberedPart struct {
+		number int
+		file   *zip.File
+	}
+	var parts []numberedPart
+	for _, f := range d.archive.File {
+		if match := pattern.FindStringSubmatch(f.Name); match != nil {
+			number, err := strconv.Atoi(match[1])
+			if err != nil {
+				continue
+			}
+			parts = append(parts, numberedPart{number: number, file: f})
+		}
+	}
+	sort.Slice(parts, func(i, j int) bool { return parts[i].number < parts[j].number })
+
+	files := make([]*zip.File, 0, len(parts))
+	for _, p := range parts {
+		files = append(files, p.file)
+	}
+	return files
+}
+
+// decode calls handle with every XML token of a part, failing once the document exceeds its
+// uncompressed size or time limits.
+func (d *ooxmlDocument) decode(f *zip.File, handle func(xml.Token)) error {
+	rc, err := f.Open()
+	if err != nil {
+		return errors.Wrapf(err, "unable to open %s", f.Name)
+	}
+	defer rc.Close()
+
+	decoder := xml.NewDecoder(&ooxmlBudgetReader{r: rc, remaining: &d.remaining})
+	for n := 1; ; n++ {
+		if n%ooxmlDeadlineCheckInterval == 0 && time.Now().After(d.deadline) {
+			return errOOXMLTimeout
+		}
+
+		token, err := decoder.Token()
+		if err == io.EOF {
+			return nil
+		} else if err != nil {
+			if errors.Is(err, errOOXMLTooLarge) {
+				return errOOXMLTooLarge
+			}
+			return errors.Wrapf(err, "unable to parse %s", f.Name)
+		}
+		handle(token)
+	}
+}
+
+// decodeParagraphs writes the text of a WordprocessingML or DrawingML part, one paragraph per
+// line. The fallback content of the alternate content blocks is skipped, as it duplicates
+// the text of the chosen alternative.
+func (d *ooxmlDocument) decodeParagraphs(f *zip.File, text *strings.Builder) error {
+	inText := false
+	fallbackDepth := 0
+	return d.decode(f, func(token xml.Token) {
+		switch t := token.(type) {
+		case xml.StartElement:
+			switch {
+			case t.Name.Local == "Fallback" || fallbackDepth > 0:
+				fallbackDepth++
+			case t.Name.Local == "t":
+				inText = true
+			case t.Name.Local == "tab" && t.Name.Space == wordprocessingMLNamespace:
+				text.WriteString("\t")
+			case t.Name.Local == "br" || t.Name.Local == "cr":
+				text.WriteString("\n")
+			}
+		case xml.EndElement:
+			switch {
+			case fallbackDepth > 0:
+				fallbackDepth--
+			case t.Name.Local == "t":
+				inText = false
+			case t.Name.Local == "p":
+				text.WriteString("\n")
+			}
+		case xml.CharData:
+			if inText && fallbackDepth == 0 {
+				text.Write(t)
+			}
+		}
+	})
+}
+
+const wordprocessingMLNamespace = "http://schemas.openxmlformats.org/wordprocessingml/2006/main"
+
+func (d *ooxmlDocument) docxText() (string, error) {
+	main := d.part("word/document.xml")
+	if main == nil {
+		return "", errors.New("missing word/document.xml")
+	}
+
+	parts := []*zip.File{main}
+	parts = append(parts, d.numberedParts("word", "header")...)
+	parts = append(parts, d.numberedParts("word", "footer")...)
+	for _, name := range []string{"word/footnotes.xml", "word/endnotes.xml", "word/comments.xml"} {
+		if f := d.part(name); f != nil {
+			parts = append(parts, f)
+		}
+	}
+
+	var text strings.Builder
+	for _, f := range parts {
+		if err := d.decodeParagraphs(f, &text); err != nil {
+			return "", err
+		}
+	}
+	return text.String(), nil
+}
+
+func (d *ooxmlDocument) pptxText() (string, error) {
+	slides := d.numberedParts("ppt/slides", "slide")
+	if len(slides) == 0 {
+		return "", errors.New("missing slides")
+	}
+
+	parts := append(slides, d.numberedParts("ppt/notesSlides", "notesSlide")...)
+
+	var text strings.Builder
+	for _, f := range parts {
+		if err := d.decodeParagraphs(f, &text); err != nil {
+			return "", err
+		}
+		text.WriteString("\n")
+	}
+	return text.String(), nil
+}
+
+// xlsxText writes the cells of every sheet, separated by tabs, one row per line.
+func (d *ooxmlDocument) xlsxText() (string, error) {
+	sheets := d.numberedParts("xl/worksheets", "sheet")
+	if len(sheets) == 0 {
+		return "", errors.New("missing worksheets")
+	}
+
+	var sharedStrings []string
+	if f := d.part("xl/sharedStrings.xml"); f != nil {
+		var err error
+		if sharedStrings, err = d.xlsxSharedStrings(f); err != nil {
+			return "", err
+		}
+	}
+
+	var text strings.Builder
+	for _, f := range sheets {
+		if err := d.xlsxSheetText(f, sharedStrings, &text); err != nil {
+			return "", err
+		}
+		text.WriteString("\n")
+	}
+	return text.String(), nil
+}
+
+// xlsxSharedStrings reads the table of the strings referenced by the cells, skipping the
+// phonetic hints of the East Asian strings.
+func (d *ooxmlDocument) xlsxSharedStrings(f *zip.File) ([]string, error) {
+	var (
+		sharedStrings []string
+		current       strings.Builder
+		inItem        bool
+		inText        bool
+		phoneticDepth int
+	)
+	err := d.decode(f, func(token xml.Token) {
+		switch t := token.(type) {
+		case xml.StartElement:
+			switch t.Name.Local {
+			case "si":
+				inItem = true
+				current.Reset()
+			case "rPh":
+				phoneticDepth++
+			case "t":
+				inText = inItem && phoneticDepth == 0
+			}
+		case xml.EndElement:
+			switch t.Name.Local {
+			case "si":
+				inItem = false
+				sharedStrings = append(sharedStrings, current.String())
+			case "rPh":
+				phoneticDepth--
+			case "t":
+				inText = false
+			}
+		case xml.CharData:
+			if inText {
+				current.Write(t)
+			}
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	return sharedStrings, nil
+}
+
+func (d *ooxmlDocument) xlsxSheetText(f *zip.File, sharedStrings []string, text *strings.Builder) error {
+	var (
+		cellType  string
+		value     strings.Builder
+		inValue   bool
+		rowCells  int
+		cellValue = func() string {
+			if cellType != "s" {
+				return value.String()
+			}
+			index, err := strconv.Atoi(strings.TrimSpace(value.String()))
+			if err != nil || index < 0 || index >= len(sharedStrings) {
+				return ""
+			}
+			return sharedStrings[index]
+		}
+	)
+	return d.decode(f, func(token xml.Token) {
+		switch t := token.(type) {
+		case xml.StartElement:
+			switch t.Name.Local {
+			case "row":
+				rowCells = 0
+			case "c":
+				cellType = ""
+				for _, attr := range t.Attr {
+					if attr.Name.Local == "t" {
+						cellType = attr.Value
+					}
+				}
+				value.Reset()
+			case "v", "t":
+				inValue = true
+			}
+		case xml.EndElement:
+			switch t.Name.Local {
+			case "row":
+				if rowCells > 0 {
+					text.WriteString("\n")
+				}
+			case "c":
+				if v := cellValue(); v != "" {
+					if rowCells > 0 {
+						text.WriteString("\t")
+					}
+					text.WriteString(v)
+					rowCells++
+				}
+			case "v", "t":
+				inValue = false
+			}
+		case xml.CharData:
+			if inValue {
+				value.Write(t)
+			}
+		}
+	})
+}
+
+// ooxmlBudgetReader fails once more than the remaining number of bytes, shared by all the
+// parts of a document, are read.
+type ooxmlBudgetReader struct {
+	r         io.Reader
+	remaining *int64
+}
+
+func (br *ooxmlBudgetReader) Read(p []byte) (int, error) {
+	// Reading one more byte than allowed tells the documents exceeding the budget apart
+	// from those ending right at it.
+	if limit := *br.remaining + 1; int64(len(p)) > limit {
+		p = p[:limit]
+	}
+	n, err := br.r.Read(p)
+	*br.remaining -= int64(n)
+	if *br.remaining < 0 {
+		return n, errOOXMLTooLarge
+	}
+	return n, err
+}
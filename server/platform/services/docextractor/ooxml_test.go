@@ -0,0 +1,188 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package docextractor
+
+import (
+	"archive/zip"
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost/server/v8/channels/utils/testutils"
+)
+
+func createTestOOXMLDocument(t *testing.T, parts map[string]string) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	w := zip.NewWriter(&buf)
+	for name, content := range parts {
+		f, err := w.Create(name)
+		require.NoError(t, err)
+		_, err = f.Write([]byte(content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, w.Close())
+	return buf.Bytes()
+}
+
+const testDocxNamespaces = `xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main" xmlns:mc="http://schemas.openxmlformats.org/markup-compatibility/2006"`
+
+func TestOOXMLExtractor(t *testing.T) {
+	extractor := newOOXMLExtractor(nil)
+
+	t.Run("docx", func(t *testing.T) {
+		data, err := testutils.ReadTestFile("sample-doc.docx")
+		require.NoError(t, err)
+
+		text, err := extractor.Extract("sample-doc.docx", bytes.NewReader(data))
+		require.NoError(t, err)
+		assert.Equal(t, "This is a simple document that contains some text.\n", text)
+	})
+
+	t.Run("docx with headers and alternate content", func(t *testing.T) {
+		data := createTestOOXMLDocument(t, map[string]string{
+			"word/document.xml": `<w:document ` + testDocxNamespaces + `><w:body>` +
+				`<w:p><w:r><w:t>First</w:t><w:tab/><w:t>paragraph</w:t></w:r></w:p>` +
+				`<w:p><w:r><mc:AlternateContent><mc:Choice><w:t>Text box</w:t></mc:Choice><mc:Fallback><w:t>Text box</w:t></mc:Fallback></mc:AlternateContent></w:r></w:p>` +
+				`<w:p><w:r><w:delText>Deleted</w:delText><w:t>Second</w:t><w:br/><w:t>line</w:t></w:r></w:p>` +
+				`</w:body></w:document>`,
+			"word/header1.xml": `<w:hdr ` + testDocxNamespaces + `><w:p><w:r><w:t>Header</w:t></w:r></w:p></w:hdr>`,
+		})
+
+		text, err := extractor.Extract("document.docx", bytes.NewReader(data))
+		require.NoError(t, err)
+		assert.Equal(t, "First\tparagraph\nText box\nSecond\nline\nHeader\n", text)
+	})
+
+	t.Run("pptx", func(t *testing.T) {
+		data, err := testutils.ReadTestFile("sample-doc.pptx")
+		require.NoError(t, err)
+
+		text, err := extractor.Extract("sample-doc.pptx", bytes.NewReader(data))
+		require.NoError(t, err)
+		assert.Contains(t, text, "simple")
+		assert.Contains(t, text, "document")
+		assert.Contains(t, text, "contains")
+	})
+
+	t.Run("pptx slides are ordered by number", func(t *testing.T) {
+		slide := func(text string) string {
+			return `<p:sld xmlns:p="http://schemas.openxmlformats.org/presentationml/2006/main" xmlns:a="http://schemas.openxmlformats.org/drawingml/2006/main"><p:txBody><a:p><a:r><a:t>` + text + `</a:t></a:r></a:p></p:txBody></p:sld>`
+		}
+		data := createTestOOXMLDocument(t, map[string]string{
+			"ppt/slides/slide10.xml":          slide("Tenth"),
+			"ppt/slides/slide2.xml":           slide("Second"),
+			"ppt/slides/slide1.xml":           slide("First"),
+			"ppt/notesSlides/notesSlide1.xml": slide("Notes"),
+		})
+
+		text, err := extractor.Extract("presentation.pptx", bytes.NewReader(data))
+		require.NoError(t, err)
+		assert.Equal(t, "First\n\nSecond\n\nTenth\n\nNotes\n\n", text)
+	})
+
+	t.Run("xlsx", func(t *testing.T) {
+		data := createTestOOXMLDocument(t, map[string]string{
+			"xl/sharedStrings.xml": `<sst xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">` +
+				`<si><t>Hello</t></si>` +
+				`<si><r><t>Wor</t></r><r><t>ld</t></r><rPh><t>phonetic</t></rPh></si>` +
+				`</sst>`,
+			"xl/worksheets/sheet1.xml": `<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>` +
+				`<row r="1"><c r="A1" t="s"><v>0</v></c><c r="B1" t="s"><v>1</v></c></row>` +
+				`<row r="2"><c r="A2"><v>42</v></c><c r="B2" t="inlineStr"><is><t>inline</t></is></c><c r="C2"/></row>` +
+				`<row r="3"><c r="A3" t="s"><v>7</v></c></row>` +
+				`</sheetData></worksheet>`,
+			"xl/worksheets/sheet2.xml": `<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>` +
+				`<row r="1"><c r="A1" t="str"><f>A1&amp;"!"</f><v>Hello!</v></c></row>` +
+				`</sheetData></worksheet>`,
+		})
+
+		text, err := extractor.Extract("spreadsheet.xlsx", bytes.NewReader(data))
+		require.NoError(t, err)
+		assert.Equal(t, "Hello\tWorld\n42\tinline\n\nHello!\n\n", text)
+	})
+
+	t.Run("invalid documents", func(t *testing.T) {
+		data, err := testutils.ReadTestFile("sample-doc.pdf")
+		require.NoError(t, err)
+		_, err = extractor.Extract("sample-doc.docx", bytes.NewReader(data))
+		require.Error(t, err)
+
+		_, err = extractor.Extract("empty.xlsx", bytes.NewReader(createTestOOXMLDocument(t, map[string]string{})))
+		require.Error(t, err)
+
+		_, err = extractor.Extract("broken.docx", bytes.NewReader(createTestOOXMLDocument(t, map[string]string{
+			"word/document.xml": `<w:document ` + testDocxNamespaces + `><w:body>`,
+		})))
+		require.Error(t, err)
+	})
+}
+
+func TestOOXMLExtractorLimits(t *testing.T) {
+	data := createTestOOXMLDocument(t, map[string]string{
+		"word/document.xml": `<w:document ` + testDocxNamespaces + `><w:body>` +
+			strings.Repeat(`<w:p><w:r><w:t>text</w:t></w:r></w:p>`, 10000) +
+			`</w:body></w:document>`,
+	})
+
+	t.Run("default limits", func(t *testing.T) {
+		text, err := newOOXMLExtractor(nil).Extract("document.docx", bytes.NewReader(data))
+		require.NoError(t, err)
+		assert.Equal(t, strings.Repeat("text\n", 10000), text)
+	})
+
+	t.Run("only the given formats are overridden", func(t *testing.T) {
+		extractor := newOOXMLExtractor(map[string]OOXMLLimits{
+			"docx": {MaxFileSize: 1024},
+			"txt":  {MaxFileSize: 1024},
+		})
+		assert.Equal(t, defaultOOXMLLimits["xlsx"], extractor.limits["xlsx"])
+		assert.True(t, extractor.Match("document.docx"))
+		assert.False(t, extractor.Match("document.txt"))
+	})
+
+	t.Run("file too large", func(t *testing.T) {
+		extractor := newOOXMLExtractor(map[string]OOXMLLimits{
+			"docx": {MaxFileSize: int64(len(data)) - 1, MaxUncompressedSize: 1024 * 1024, Timeout: time.Minute},
+		})
+		_, err := extractor.Extract("document.docx", bytes.NewReader(data))
+		require.ErrorIs(t, err, errOOXMLTooLarge)
+	})
+
+	t.Run("uncompressed parts too large", func(t *testing.T) {
+		extractor := newOOXMLExtractor(map[string]OOXMLLimits{
+			"docx": {MaxFileSize: 1024 * 1024, MaxUncompressedSize: 64 * 1024, Timeout: time.Minute},
+		})
+		_, err := extractor.Extract("document.docx", bytes.NewReader(data))
+		require.ErrorIs(t, err, errOOXMLTooLarge)
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		extractor := newOOXMLExtractor(map[string]OOXMLLimits{
+			"docx": {MaxFileSize: 1024 * 1024, MaxUncompressedSize: 1024 * 1024, Timeout: -time.Second},
+		})
+		_, err := extractor.Extract("document.docx", bytes.NewReader(data))
+		require.ErrorIs(t, err, errOOXMLTimeout)
+	})
+
+	t.Run("document ending right at the limit", func(t *testing.T) {
+		small := createTestOOXMLDocument(t, map[string]string{
+			"word/document.xml": `<w:document ` + testDocxNamespaces + `><w:body><w:p><w:r><w:t>text</w:t></w:r></w:p></w:body></w:document>`,
+		})
+		archive, err := zip.NewReader(bytes.NewReader(small), int64(len(small)))
+		require.NoError(t, err)
+
+		extractor := newOOXMLExtractor(map[string]OOXMLLimits{
+			"docx": {MaxFileSize: 1024 * 1024, MaxUncompressedSize: int64(archive.File[0].UncompressedSize64), Timeout: time.Minute},
+		})
+		text, err := extractor.Extract("document.docx", bytes.NewReader(small))
+		require.NoError(t, err)
+		assert.Equal(t, "text\n", text)
+	})
+}
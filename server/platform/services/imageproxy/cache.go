@@ -0,0 +1,217 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package imageproxy
+
+import (
+	"container/list"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// Resized images larger than this fraction of the cache aren't cached.
+	resizedImageMaxSizeRatio = 8
+
+	// Resized images are fetched again once they are older than this, or than the max age of the
+	// original images, so that changes to the original images are eventually picked up.
+	resizedImageMaxAge = 24 * time.Hour
+)
+
+// resizedImageFileName matches the names of the cached images, and of the temporary files they
+// are written to, so that only those are removed from the cache directory.
+var resizedImageFileName = regexp.MustCompile(`^[0-9a-f]{64}(-[0-9]+\.tmp)?$`)
+
+type resizedImage struct {
+	key          string
+	contentType  string
+	cacheControl string
+	size         int64
+	expireAt     time.Time
+}
+
+// resizedImageCache keeps the resized images in a local directory, evicting the least recently
+// used ones once they take more than the maximum size of the cache.
+type resizedImageCache struct {
+	directory string
+	maxSize   int64
+
+	mut    sync.Mutex
+	images map[string]*list.Element
+	lru    *list.List
+	size   int64
+	tmpSeq int
+}
+
+// newResizedImageCache creates a cache of at most maxSize bytes in directory. The images that
+// were previously cached in the directory are removed, since their metadata is only kept in
+// memory.
+func newResizedImageCache(directory string, maxSize int64) (*resizedImageCache, error) {
+	if maxSize <= 0 {
+		return nil, errors.New("the maximum size of the resized image cache must be positive")
+	}
+	if err := os.MkdirAll(directory, 0750); err != nil {
+		return nil, errors.Wrapf(err, "unable to create the resized image cache directory %s", directory)
+	}
+
+	entries, err := os.ReadDir(directory)
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to read the resized image cache directory %s", directory)
+	}
+	for _, entry := range entries {
+		if !entry.IsDir() && resizedImageFileName.MatchString(entry.Name()) {
+			if err := os.Remove(filepath.Join(directory, entry.Name())); err != nil {
+				return nil, errors.Wrap(err, "unable to empty the resized image cache directory")
+			}
+		}
+	}
+
+	return &resizedImageCache{
+		directory: directory,
+		maxSize:   maxSize,
+		images:    make(map[string]*list.Element),
+		lru:       list.New(),
+	}, nil
+}
+
+// resizedImageKey returns the key of an image resized with the given options to the given
+// format, which may be empty if the image is served in its original format.
+func resizedImageKey(imageURL string, opts ImageOptions, format string) string {
+	hash := sha256.Sum256([]byte(fmt.Sprintf("%s\n%d\n%d\n%s\n%s", imageURL, opts.Width, opts.Height, opts.Fit, format)))
+	return hex.EncodeToString(hash[:])
+}
+
+// resizedImageCacheMaxAge returns how long an image resized from an original served with the
+// given Cache-Control header can be cached, or zero if it can't be. The cache is shared between
+// all the users, so private images are never cached.
+func resizedImageCacheMaxAge(cacheControl string) time.Duration {
+	maxAge, sharedMaxAge := time.Duration(-1), time.Duration(-1)
+	for _, directive := range strings.Split(cacheControl, ",") {
+		name, value, _ := strings.Cut(strings.TrimSpace(directive), "=")
+		switch strings.ToLower(name) {
+		case "no-store", "no-cache", "private":
+			return 0
+		case "max-age":
+			maxAge = parseCacheControlSeconds(value)
+		case "s-maxage":
+			sharedMaxAge = parseCacheControlSeconds(value)
+		}
+	}
+
+	if sharedMaxAge >= 0 {
+		maxAge = sharedMaxAge
+	}
+	if maxAge < 0 || maxAge > resizedImageMaxAge {
+		return resizedImageMaxAge
+	}
+	return maxAge
+}
+
+// parseCacheControlSeconds parses the number of seconds of a Cache-Control directive. Invalid
+// values are treated as zero, so that the image isn't cached.
+func parseCacheControlSeconds(value string) time.Duration {
+	seconds, err := strconv.ParseInt(strings.Trim(value, `"`), 10, 64)
+	if err != nil || seconds < 0 {
+		return 0
+	}
+	if seconds > int64(resizedImageMaxAge/time.Second) {
+		return resizedImageMaxAge
+	}
+	return time.Duration(seconds) * time.Second
+}
+
+func (c *resizedImageCache) path(key string) string {
+	return filepath.Join(c.directory, key)
+}
+
+// get returns a cached image along with its content type and cache control header, or nil if
+// it isn't cached.
+func (c *resizedImageCache) get(key string) ([]byte, string, string) {
+	c.mut.Lock()
+	elem, ok := c.images[key]
+	if !ok {
+		c.mut.Unlock()
+		return nil, "", ""
+	}
+	image := elem.Value.(*resizedImage)
+	if time.Now().After(image.expireAt) {
+		c.removeLocked(elem)
+		c.mut.Unlock()
+		return nil, "", ""
+	}
+	c.lru.MoveToFront(elem)
+	c.mut.Unlock()
+
+	data, err := os.ReadFile(c.path(key))
+	if err != nil {
+		// The file was evicted in the meantime, or removed from the directory.
+		c.mut.Lock()
+		if elem, ok := c.images[key]; ok && elem.Value.(*resizedImage) == image {
+			c.removeLocked(elem)
+		}
+		c.mut.Unlock()
+		return nil, "", ""
+	}
+	return data, image.contentType, image.cacheControl
+}
+
+// put adds an image to the cache for at most maxAge, evicting the least recently used images to
+// make room for it.
+func (c *resizedImageCache) put(key string, data []byte, contentType, cacheControl string, maxAge time.Duration) error {
+	size := int64(len(data))
+	if size > c.maxSize/resizedImageMaxSizeRatio || maxAge <= 0 {
+		return nil
+	}
+
+	c.mut.Lock()
+	c.tmpSeq++
+	tmpPath := fmt.Sprintf("%s-%d.tmp", c.path(key), c.tmpSeq)
+	c.mut.Unlock()
+
+	if err := os.WriteFile(tmpPath, data, 0640); err != nil {
+		os.Remove(tmpPath)
+		return errors.Wrap(err, "unable to write the resized image to the cache")
+	}
+
+	c.mut.Lock()
+	defer c.mut.Unlock()
+
+	if elem, ok := c.images[key]; ok {
+		c.removeLocked(elem)
+	}
+	if err := os.Rename(tmpPath, c.path(key)); err != nil {
+		os.Remove(tmpPath)
+		return errors.Wrap(err, "unable to add the resized image to the cache")
+	}
+
+	c.images[key] = c.lru.PushFront(&resizedImage{
+		key:          key,
+		contentType:  contentType,
+		cacheControl: cacheControl,
+		size:         size,
+		expireAt:     time.Now().Add(maxAge),
+	})
+	c.size += size
+
+	for c.size > c.maxSize {
+		c.removeLocked(c.lru.Back())
+	}
+	return nil
+}
+
+func (c *resizedImageCache) removeLocked(elem *list.Element) {
+	image := c.lru.Remove(elem).(*resizedImage)
+	delete(c.images, image.key)
+	c.size -= image.size
+	os.Remove(c.path(image.key))
+}
@@ -0,0 +1,131 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package imageproxy
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResizedImageCache(t *testing.T) {
+	t.Run("get and put", func(t *testing.T) {
+		cache, err := newResizedImageCache(t.TempDir(), 1024)
+		require.NoError(t, err)
+
+		key := resizedImageKey("https://example.com/image.png", ImageOptions{Width: 100}, "")
+		data, _, _ := cache.get(key)
+		assert.Nil(t, data)
+
+		require.NoError(t, cache.put(key, []byte("image"), "image/png", "max-age=60", time.Minute))
+
+		data, contentType, cacheControl := cache.get(key)
+		assert.Equal(t, []byte("image"), data)
+		assert.Equal(t, "image/png", contentType)
+		assert.Equal(t, "max-age=60", cacheControl)
+	})
+
+	t.Run("keys depend on the options and format", func(t *testing.T) {
+		imageURL := "https://example.com/image.png"
+		key := resizedImageKey(imageURL, ImageOptions{Width: 100}, "")
+		assert.Equal(t, key, resizedImageKey(imageURL, ImageOptions{Width: 100}, ""))
+		assert.NotEqual(t, key, resizedImageKey(imageURL, ImageOptions{Width: 200}, ""))
+		assert.NotEqual(t, key, resizedImageKey(imageURL, ImageOptions{Width: 100, Fit: ImageFitCover}, ""))
+		assert.NotEqual(t, key, resizedImageKey(imageURL, ImageOptions{Width: 100}, "webp"))
+		assert.NotEqual(t, key, resizedImageKey("https://example.com/other.png", ImageOptions{Width: 100}, ""))
+	})
+
+	t.Run("least recently used images are evicted", func(t *testing.T) {
+		cache, err := newResizedImageCache(t.TempDir(), 800)
+		require.NoError(t, err)
+
+		image := bytes.Repeat([]byte("a"), 100)
+		for _, key := range []string{"a", "b", "c", "d", "e", "f", "g", "h"} {
+			require.NoError(t, cache.put(resizedImageKey(key, ImageOptions{}, ""), image, "image/png", "", time.Hour))
+		}
+		data, _, _ := cache.get(resizedImageKey("a", ImageOptions{}, ""))
+		require.NotNil(t, data)
+
+		require.NoError(t, cache.put(resizedImageKey("i", ImageOptions{}, ""), image, "image/png", "", time.Hour))
+		assert.Equal(t, int64(800), cache.size)
+
+		data, _, _ = cache.get(resizedImageKey("a", ImageOptions{}, ""))
+		assert.NotNil(t, data)
+		data, _, _ = cache.get(resizedImageKey("b", ImageOptions{}, ""))
+		assert.Nil(t, data)
+		_, err = os.Stat(cache.path(resizedImageKey("b", ImageOptions{}, "")))
+		assert.True(t, os.IsNotExist(err))
+	})
+
+	t.Run("large images are not cached", func(t *testing.T) {
+		cache, err := newResizedImageCache(t.TempDir(), 800)
+		require.NoError(t, err)
+
+		key := resizedImageKey("large", ImageOptions{}, "")
+		require.NoError(t, cache.put(key, bytes.Repeat([]byte("a"), 101), "image/png", "", time.Hour))
+		data, _, _ := cache.get(key)
+		assert.Nil(t, data)
+	})
+
+	t.Run("expired images are removed", func(t *testing.T) {
+		cache, err := newResizedImageCache(t.TempDir(), 1024)
+		require.NoError(t, err)
+
+		key := resizedImageKey("expired", ImageOptions{}, "")
+		require.NoError(t, cache.put(key, []byte("image"), "image/png", "", time.Hour))
+		cache.images[key].Value.(*resizedImage).expireAt = time.Now().Add(-time.Minute)
+
+		data, _, _ := cache.get(key)
+		assert.Nil(t, data)
+		assert.Zero(t, cache.size)
+	})
+
+	t.Run("images without a max age are not cached", func(t *testing.T) {
+		cache, err := newResizedImageCache(t.TempDir(), 1024)
+		require.NoError(t, err)
+
+		key := resizedImageKey("no-store", ImageOptions{}, "")
+		require.NoError(t, cache.put(key, []byte("image"), "image/png", "no-store", 0))
+		data, _, _ := cache.get(key)
+		assert.Nil(t, data)
+	})
+
+	t.Run("previously cached images are removed", func(t *testing.T) {
+		directory := t.TempDir()
+		key := resizedImageKey("previous", ImageOptions{}, "")
+		require.NoError(t, os.WriteFile(filepath.Join(directory, key), []byte("image"), 0600))
+		require.NoError(t, os.WriteFile(filepath.Join(directory, "other.txt"), []byte("other"), 0600))
+
+		_, err := newResizedImageCache(directory, 1024)
+		require.NoError(t, err)
+
+		_, err = os.Stat(filepath.Join(directory, key))
+		assert.True(t, os.IsNotExist(err))
+		_, err = os.Stat(filepath.Join(directory, "other.txt"))
+		assert.NoError(t, err)
+	})
+}
+
+func TestResizedImageCacheMaxAge(t *testing.T) {
+	for cacheControl, expected := range map[string]time.Duration{
+		"":                                   resizedImageMaxAge,
+		"public":                             resizedImageMaxAge,
+		"max-age=60":                         time.Minute,
+		"public, max-age=2592000":            resizedImageMaxAge,
+		"max-age=60, s-maxage=120":           2 * time.Minute,
+		"max-age=0":                          0,
+		"max-age=invalid":                    0,
+		"no-store":                           0,
+		"No-Cache":                           0,
+		"max-age=2592000, private":           0,
+		"private=\"Set-Cookie\", max-age=60": 0,
+	} {
+		assert.Equal(t, expected, resizedImageCacheMaxAge(cacheControl), cacheControl)
+	}
+}
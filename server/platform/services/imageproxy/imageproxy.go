@@ -16,6 +16,7 @@ import (
 	"github.com/mattermost/mattermost/server/public/shared/configservice"
 	"github.com/mattermost/mattermost/server/public/shared/httpservice"
 	"github.com/mattermost/mattermost/server/public/shared/mlog"
+	"github.com/mattermost/mattermost/server/v8/channels/app/imaging"
 )
 
 var ErrNotEnabled = Error{errors.New("imageproxy.ImageProxy: image proxy not enabled")}
@@ -30,9 +31,10 @@ type ImageProxy struct {
 
 	Logger *mlog.Logger
 
-	siteURL *url.URL
-	lock    sync.RWMutex
-	backend ImageProxyBackend
+	siteURL        *url.URL
+	imageConverter *imaging.Converter
+	lock           sync.RWMutex
+	backend        ImageProxyBackend
 }
 
 // An ImageProxyBackend provides the functionality for different types of image proxies. An ImageProxy will construct
@@ -72,7 +74,7 @@ func (proxy *ImageProxy) makeBackend(proxySettings model.ImageProxySettings) Ima
 
 	switch *proxySettings.ImageProxyType {
 	case model.ImageProxyTypeLocal:
-		return makeLocalBackend(proxy)
+		return makeLocalBackend(proxy, proxySettings)
 	case model.ImageProxyTypeAtmosCamo:
 		return makeAtmosCamoBackend(proxy, proxySettings)
 	default:
@@ -100,6 +102,25 @@ func (proxy *ImageProxy) OnConfigChange(oldConfig, newConfig *model.Config) {
 	}
 }
 
+// SetImageConverter sets the converter used to decode and encode the images in the formats that
+// aren't supported natively, such as WebP, when resizing proxied images.
+func (proxy *ImageProxy) SetImageConverter(converter *imaging.Converter) {
+	proxy.lock.Lock()
+	defer proxy.lock.Unlock()
+
+	proxy.imageConverter = converter
+	proxy.backend = proxy.makeBackend(proxy.ConfigService.Config().ImageProxySettings)
+}
+
+// SupportsResizing returns true if the image proxy can resize the images it serves.
+func (proxy *ImageProxy) SupportsResizing() bool {
+	proxy.lock.RLock()
+	defer proxy.lock.RUnlock()
+
+	backend, ok := proxy.backend.(*LocalBackend)
+	return ok && backend.resizer != nil
+}
+
 // GetImage takes an HTTP request for an image and requests that image using the image proxy.
 func (proxy *ImageProxy) GetImage(w http.ResponseWriter, r *http.Request, imageURL string) {
 	proxy.lock.RLock()
@@ -128,6 +149,12 @@ func (proxy *ImageProxy) GetImageDirect(imageURL string) (io.ReadCloser, string,
 // GetProxiedImageURL takes the URL of an image and returns a URL that can be used to view that image through the
 // image proxy.
 func (proxy *ImageProxy) GetProxiedImageURL(imageURL string) string {
+	return proxy.GetResizedProxiedImageURL(imageURL, ImageOptions{})
+}
+
+// GetResizedProxiedImageURL takes the URL of an image and returns a URL that can be used to view that image through
+// the image proxy, resized with the given options if the proxy supports it.
+func (proxy *ImageProxy) GetResizedProxiedImageURL(imageURL string, opts ImageOptions) string {
 	if imageURL == "" || proxy.siteURL == nil || strings.HasPrefix(strings.ToLower(imageURL), "data:image/") {
 		return imageURL
 	}
@@ -153,7 +180,14 @@ func (proxy *ImageProxy) GetProxiedImageURL(imageURL string) string {
 		return parsedURL.String()
 	}
 
-	return proxy.siteURL.String() + "/api/v4/image?url=" + url.QueryEscape(parsedURL.String())
+	proxiedURL := proxy.siteURL.String() + "/api/v4/image?url=" + url.QueryEscape(parsedURL.String())
+	if !opts.IsZero() {
+		// The url parameter must stay first for getUnproxiedImageURL to recognize the URL.
+		query := url.Values{}
+		opts.addToQuery(query)
+		proxiedURL += "&" + query.Encode()
+	}
+	return proxiedURL
 }
 
 // GetUnproxiedImageURL takes the URL of an image on the image proxy and returns the original URL of the image.
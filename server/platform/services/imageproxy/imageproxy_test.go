@@ -80,6 +80,57 @@ func TestGetProxiedImageURL(t *testing.T) {
 	}
 }
 
+func TestGetResizedProxiedImageURL(t *testing.T) {
+	parsedURL, err := url.Parse("https://mattermost.example.com")
+	require.NoError(t, err)
+
+	imageURL := "https://mattermost.com/wp-content/uploads/2022/02/logoHorizontal.png"
+	proxiedURL := "https://mattermost.example.com/api/v4/image?url=https%3A%2F%2Fmattermost.com%2Fwp-content%2Fuploads%2F2022%2F02%2FlogoHorizontal.png"
+
+	proxy := ImageProxy{siteURL: parsedURL}
+
+	for _, test := range []struct {
+		Name     string
+		Input    string
+		Options  ImageOptions
+		Expected string
+	}{
+		{
+			Name:     "should not add empty options",
+			Input:    imageURL,
+			Expected: proxiedURL,
+		},
+		{
+			Name:     "should add the dimensions",
+			Input:    imageURL,
+			Options:  ImageOptions{Width: 400, Height: 300},
+			Expected: proxiedURL + "&height=300&width=400",
+		},
+		{
+			Name:     "should add the fit",
+			Input:    imageURL,
+			Options:  ImageOptions{Width: 400, Height: 300, Fit: ImageFitCover},
+			Expected: proxiedURL + "&fit=cover&height=300&width=400",
+		},
+		{
+			Name:     "should not add the default fit",
+			Input:    imageURL,
+			Options:  ImageOptions{Width: 400, Fit: ImageFitContain},
+			Expected: proxiedURL + "&width=400",
+		},
+		{
+			Name:     "should not resize an image on the Mattermost server",
+			Input:    "https://mattermost.example.com/static/logo.png",
+			Options:  ImageOptions{Width: 400},
+			Expected: "https://mattermost.example.com/static/logo.png",
+		},
+	} {
+		t.Run(test.Name, func(t *testing.T) {
+			assert.Equal(t, test.Expected, proxy.GetResizedProxiedImageURL(test.Input, test.Options))
+		})
+	}
+}
+
 func TestGetUnproxiedImageURL(t *testing.T) {
 	siteURL := "https://mattermost.example.com"
 
@@ -96,6 +147,11 @@ func TestGetUnproxiedImageURL(t *testing.T) {
 			Input:    proxiedURL,
 			Expected: imageURL,
 		},
+		{
+			Name:     "should remove proxy from a resized image",
+			Input:    proxiedURL + "&height=300&width=400",
+			Expected: imageURL,
+		},
 		{
 			Name:     "should not remove proxy from a relative image",
 			Input:    "/static/logo.png",
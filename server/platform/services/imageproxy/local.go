@@ -5,6 +5,7 @@ package imageproxy
 
 import (
 	"bufio"
+	"bytes"
 	"errors"
 	"fmt"
 	"io"
@@ -16,9 +17,11 @@ import (
 	"path"
 	"path/filepath"
 	"regexp"
+	"strconv"
 	"strings"
 	"time"
 
+	"github.com/mattermost/mattermost/server/public/model"
 	"github.com/mattermost/mattermost/server/public/shared/mlog"
 )
 
@@ -42,6 +45,11 @@ var ErrLocalRequestFailed = Error{errors.New("imageproxy.LocalBackend: failed to
 type LocalBackend struct {
 	client  *http.Client
 	baseURL *url.URL
+
+	// resizer and cache are only set when resizing is enabled.
+	resizer    *imageResizer
+	cache      *resizedImageCache
+	enableWebP bool
 }
 
 // URLError reports a malformed URL error.
@@ -54,7 +62,7 @@ func (e URLError) Error() string {
 	return fmt.Sprintf("malformed URL %q: %s", e.URL, e.Message)
 }
 
-func makeLocalBackend(proxy *ImageProxy) *LocalBackend {
+func makeLocalBackend(proxy *ImageProxy, proxySettings model.ImageProxySettings) *LocalBackend {
 	baseURL := proxy.siteURL
 	if baseURL == nil {
 		mlog.Warn("Failed to set base URL for image proxy. Relative image links may not work.")
@@ -62,10 +70,43 @@ func makeLocalBackend(proxy *ImageProxy) *LocalBackend {
 
 	client := proxy.HTTPService.MakeClient(false)
 
-	return &LocalBackend{
+	backend := &LocalBackend{
 		client:  client,
 		baseURL: baseURL,
 	}
+
+	if proxySettings.EnableResizing == nil || !*proxySettings.EnableResizing {
+		return backend
+	}
+
+	var maxResolution int64
+	if fileSettings := proxy.ConfigService.Config().FileSettings; fileSettings.MaxImageResolution != nil {
+		maxResolution = *fileSettings.MaxImageResolution
+	}
+	resizer, err := newImageResizer(proxy.imageConverter, maxResolution)
+	if err != nil {
+		mlog.Error("Failed to create the image resizer. Proxied images won't be resized.", mlog.Err(err))
+		return backend
+	}
+	backend.resizer = resizer
+	backend.enableWebP = proxySettings.EnableWebP != nil && *proxySettings.EnableWebP
+
+	directory := model.ImageProxySettingsDefaultResizedImageCacheDirectory
+	if proxySettings.ResizedImageCacheDirectory != nil {
+		directory = *proxySettings.ResizedImageCacheDirectory
+	}
+	maxSizeMB := model.ImageProxySettingsDefaultResizedImageCacheMaxSizeMB
+	if proxySettings.ResizedImageCacheMaxSizeMB != nil {
+		maxSizeMB = *proxySettings.ResizedImageCacheMaxSizeMB
+	}
+	cache, err := newResizedImageCache(directory, int64(maxSizeMB)*1024*1024)
+	if err != nil {
+		mlog.Warn("Failed to create the resized image cache. Resized images won't be cached.", mlog.Err(err))
+	} else {
+		backend.cache = cache
+	}
+
+	return backend
 }
 
 type contentTypeRecorder struct {
@@ -112,9 +153,109 @@ func (backend *LocalBackend) GetImage(w http.ResponseWriter, r *http.Request, im
 	w.Header().Set("Content-Security-Policy", "default-src 'none'; img-src data:; style-src 'unsafe-inline'")
 
 	rec := contentTypeRecorder{w, filepath.Base(u.Path)}
+
+	if backend.resizer != nil {
+		opts, err := parseImageOptions(r.URL.Query())
+		if err != nil {
+			http.Error(w, fmt.Sprintf("invalid image options: %v", err), http.StatusBadRequest)
+			return
+		}
+
+		webp := backend.enableWebP && acceptsWebP(r)
+		if webp {
+			// The response depends on whether the client accepts WebP images.
+			w.Header().Add("Vary", "Accept")
+		}
+		if !opts.IsZero() || webp {
+			backend.serveResizedImage(&rec, req, imageURL, opts, webp)
+			return
+		}
+	}
+
 	backend.ServeImage(&rec, req)
 }
 
+// serveResizedImage serves an image resized with the given options, and encoded in WebP when
+// webp is true. The original image is served when it can't be resized.
+func (backend *LocalBackend) serveResizedImage(w http.ResponseWriter, req *http.Request, imageURL string, opts ImageOptions, webp bool) {
+	format := ""
+	if webp {
+		format = "webp"
+	}
+	key := resizedImageKey(imageURL, opts, format)
+
+	if backend.cache != nil {
+		if data, contentType, cacheControl := backend.cache.get(key); data != nil {
+			writeResizedImage(w, data, contentType, cacheControl)
+			return
+		}
+	}
+
+	resp := backend.fetchImage(w, req)
+	if resp == nil {
+		return
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK || resp.ContentLength > maxResizedImageSourceBytes {
+		writeImage(w, resp, resp.Body)
+		return
+	}
+
+	// Only read as much as can be resized, and stream larger images as they are.
+	original, err := io.ReadAll(io.LimitReader(resp.Body, maxResizedImageSourceBytes+1))
+	if err != nil {
+		mlog.Warn("error reading remote image", mlog.Err(err))
+		w.Header().Del("Content-Length")
+		http.Error(w, fmt.Sprintf("error reading remote image: %v", err), http.StatusBadGateway)
+		return
+	}
+	if len(original) > maxResizedImageSourceBytes {
+		writeImage(w, resp, io.MultiReader(bytes.NewReader(original), resp.Body))
+		return
+	}
+
+	contentType, _, _ := mime.ParseMediaType(w.Header().Get("Content-Type"))
+	data, resizedContentType, err := backend.resizer.resize(original, contentType, opts, webp)
+	if err != nil {
+		mlog.Debug("Failed to resize proxied image", mlog.String("url", imageURL), mlog.Err(err))
+	}
+	if data == nil {
+		// The original image is served as it is, and isn't cached since it isn't a variant.
+		writeImage(w, resp, bytes.NewReader(original))
+		return
+	}
+
+	cacheControl := resp.Header.Get("Cache-Control")
+	if backend.cache != nil {
+		if err := backend.cache.put(key, data, resizedContentType, cacheControl, resizedImageCacheMaxAge(cacheControl)); err != nil {
+			mlog.Warn("Failed to cache resized image", mlog.String("url", imageURL), mlog.Err(err))
+		}
+	}
+
+	// The validators of the original image don't apply to the resized one.
+	w.Header().Del("Etag")
+	w.Header().Del("Last-Modified")
+	writeResizedImage(w, data, resizedContentType, cacheControl)
+}
+
+func writeResizedImage(w http.ResponseWriter, data []byte, contentType, cacheControl string) {
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Content-Length", strconv.Itoa(len(data)))
+	if cacheControl != "" {
+		w.Header().Set("Cache-Control", cacheControl)
+	}
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+	w.Header().Set("Content-Security-Policy", "script-src 'none'")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.Header().Set("X-XSS-Protection", "1; mode=block")
+
+	w.WriteHeader(http.StatusOK)
+	if _, err := w.Write(data); err != nil {
+		mlog.Warn("error writing resized image", mlog.Err(err))
+	}
+}
+
 func (backend *LocalBackend) GetImageDirect(imageURL string) (io.ReadCloser, string, error) {
 	// The interface to the proxy only exposes a ServeHTTP method, so fake a request to it
 	req, err := http.NewRequest(http.MethodGet, "/"+imageURL, nil)
@@ -134,16 +275,28 @@ func (backend *LocalBackend) GetImageDirect(imageURL string) (io.ReadCloser, str
 }
 
 func (backend *LocalBackend) ServeImage(w http.ResponseWriter, req *http.Request) {
+	resp := backend.fetchImage(w, req)
+	if resp == nil {
+		return
+	}
+	defer resp.Body.Close()
+
+	writeImage(w, resp, resp.Body)
+}
+
+// fetchImage requests the proxied image and sets the headers of the response to w. The response
+// is nil when it was already written to w, and its body must be closed otherwise.
+func (backend *LocalBackend) fetchImage(w http.ResponseWriter, req *http.Request) *http.Response {
 	proxyReq, err := newProxyRequest(req, backend.baseURL)
 	if err != nil {
 		http.Error(w, fmt.Sprintf("invalid request URL: %v", err), http.StatusBadRequest)
-		return
+		return nil
 	}
 
 	actualReq, err := http.NewRequest("GET", proxyReq.String(), nil)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
-		return
+		return nil
 	}
 	actualReq.Header.Set("Accept", strings.Join(imageContentTypes, ", "))
 
@@ -155,28 +308,31 @@ func (backend *LocalBackend) ServeImage(w http.ResponseWriter, req *http.Request
 			statusCode = http.StatusGatewayTimeout
 		}
 		http.Error(w, fmt.Sprintf("error fetching remote image: %v", err), statusCode)
-		return
+		return nil
 	}
-	// close the original resp.Body, even if we wrap it in a NopCloser below
-	defer resp.Body.Close()
 
 	copyHeader(w.Header(), resp.Header, "Cache-Control", "Last-Modified", "Expires", "Etag", "Link")
 
 	if should304(req, resp) {
+		resp.Body.Close()
 		w.WriteHeader(http.StatusNotModified)
-		return
+		return nil
 	}
 
 	contentType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type"))
 	if contentType == "" || contentType == "application/octet-stream" || contentType == "binary/octet-stream" {
-		// try to detect content type
+		// try to detect content type, and still close the original resp.Body
 		b := bufio.NewReader(resp.Body)
-		resp.Body = io.NopCloser(b)
+		resp.Body = struct {
+			io.Reader
+			io.Closer
+		}{b, resp.Body}
 		contentType = peekContentType(b)
 	}
 	if resp.ContentLength != 0 && !contentTypeMatches(imageContentTypes, contentType) {
+		resp.Body.Close()
 		http.Error(w, msgNotAllowed, http.StatusForbidden)
-		return
+		return nil
 	}
 	w.Header().Set("Content-Type", contentType)
 
@@ -194,8 +350,13 @@ func (backend *LocalBackend) ServeImage(w http.ResponseWriter, req *http.Request
 	// Block potential XSS attacks especially in legacy browsers which do not support CSP
 	w.Header().Set("X-XSS-Protection", "1; mode=block")
 
+	return resp
+}
+
+// writeImage writes the status of the proxied image response to w, followed by body.
+func writeImage(w http.ResponseWriter, resp *http.Response, body io.Reader) {
 	w.WriteHeader(resp.StatusCode)
-	if _, err := io.Copy(w, resp.Body); err != nil {
+	if _, err := io.Copy(w, body); err != nil {
 		mlog.Warn("error copying response", mlog.Err(err))
 	}
 }
@@ -4,9 +4,12 @@
 package imageproxy
 
 import (
+	"bytes"
+	"image/png"
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"strconv"
 	"testing"
 	"time"
 
@@ -226,6 +229,137 @@ func TestLocalBackend_GetImage(t *testing.T) {
 	})
 }
 
+func TestLocalBackend_GetResizedImage(t *testing.T) {
+	image := makeTestPNG(t, 200, 100)
+	svg := []byte(`<svg xmlns="http://www.w3.org/2000/svg" width="200" height="100"></svg>`)
+	requests := 0
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		switch r.URL.Path {
+		case "/image.png":
+			w.Header().Set("Cache-Control", "max-age=2592000")
+			w.Header().Set("Content-Type", "image/png")
+			w.WriteHeader(http.StatusOK)
+			w.Write(image)
+		case "/private.png":
+			w.Header().Set("Cache-Control", "max-age=2592000, private")
+			w.Header().Set("Content-Type", "image/png")
+			w.WriteHeader(http.StatusOK)
+			w.Write(image)
+		case "/image.svg":
+			w.Header().Set("Content-Type", "image/svg+xml")
+			w.WriteHeader(http.StatusOK)
+			w.Write(svg)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+
+	mock := httptest.NewServer(handler)
+	defer mock.Close()
+
+	proxy := makeTestLocalProxy()
+	cfg := proxy.ConfigService.Config()
+	cfg.ImageProxySettings.EnableResizing = model.NewPointer(true)
+	cfg.ImageProxySettings.ResizedImageCacheDirectory = model.NewPointer(t.TempDir())
+	cfg.ImageProxySettings.ResizedImageCacheMaxSizeMB = model.NewPointer(1)
+	proxy.SetImageConverter(nil)
+	require.True(t, proxy.SupportsResizing())
+
+	t.Run("resized image", func(t *testing.T) {
+		recorder := httptest.NewRecorder()
+		request, err := http.NewRequest(http.MethodGet, "/api/v4/image?width=50", nil)
+		require.NoError(t, err)
+		proxy.GetImage(recorder, request, mock.URL+"/image.png")
+		resp := recorder.Result()
+
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, "image/png", resp.Header.Get("Content-Type"))
+		assert.Equal(t, "max-age=2592000", resp.Header.Get("Cache-Control"))
+		assert.Equal(t, "nosniff", resp.Header.Get("X-Content-Type-Options"))
+
+		respBody, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		assert.Equal(t, strconv.Itoa(len(respBody)), resp.Header.Get("Content-Length"))
+
+		config, err := png.DecodeConfig(bytes.NewReader(respBody))
+		require.NoError(t, err)
+		assert.Equal(t, 50, config.Width)
+		assert.Equal(t, 25, config.Height)
+	})
+
+	t.Run("resized image is cached", func(t *testing.T) {
+		requestsBefore := requests
+
+		recorder := httptest.NewRecorder()
+		request, err := http.NewRequest(http.MethodGet, "/api/v4/image?width=50", nil)
+		require.NoError(t, err)
+		proxy.GetImage(recorder, request, mock.URL+"/image.png")
+
+		require.Equal(t, http.StatusOK, recorder.Code)
+		assert.Equal(t, requestsBefore, requests)
+	})
+
+	t.Run("private image is not cached", func(t *testing.T) {
+		for i := 0; i < 2; i++ {
+			requestsBefore := requests
+
+			recorder := httptest.NewRecorder()
+			request, err := http.NewRequest(http.MethodGet, "/api/v4/image?width=50", nil)
+			require.NoError(t, err)
+			proxy.GetImage(recorder, request, mock.URL+"/private.png")
+
+			require.Equal(t, http.StatusOK, recorder.Code)
+			assert.Equal(t, "max-age=2592000, private", recorder.Header().Get("Cache-Control"))
+			assert.Equal(t, requestsBefore+1, requests)
+		}
+	})
+
+	t.Run("image that can't be resized is served as it is and not cached", func(t *testing.T) {
+		for i := 0; i < 2; i++ {
+			requestsBefore := requests
+
+			recorder := httptest.NewRecorder()
+			request, err := http.NewRequest(http.MethodGet, "/api/v4/image?width=50", nil)
+			require.NoError(t, err)
+			proxy.GetImage(recorder, request, mock.URL+"/image.svg")
+
+			require.Equal(t, http.StatusOK, recorder.Code)
+			assert.Equal(t, "image/svg+xml", recorder.Header().Get("Content-Type"))
+			assert.Equal(t, svg, recorder.Body.Bytes())
+			assert.Equal(t, requestsBefore+1, requests)
+		}
+	})
+
+	t.Run("original image", func(t *testing.T) {
+		recorder := httptest.NewRecorder()
+		request, err := http.NewRequest(http.MethodGet, "/api/v4/image", nil)
+		require.NoError(t, err)
+		proxy.GetImage(recorder, request, mock.URL+"/image.png")
+
+		require.Equal(t, http.StatusOK, recorder.Code)
+		assert.Equal(t, image, recorder.Body.Bytes())
+	})
+
+	t.Run("invalid options", func(t *testing.T) {
+		recorder := httptest.NewRecorder()
+		request, err := http.NewRequest(http.MethodGet, "/api/v4/image?width=0", nil)
+		require.NoError(t, err)
+		proxy.GetImage(recorder, request, mock.URL+"/image.png")
+
+		assert.Equal(t, http.StatusBadRequest, recorder.Code)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		recorder := httptest.NewRecorder()
+		request, err := http.NewRequest(http.MethodGet, "/api/v4/image?width=50", nil)
+		require.NoError(t, err)
+		proxy.GetImage(recorder, request, mock.URL+"/missing.png")
+
+		assert.Equal(t, http.StatusNotFound, recorder.Code)
+	})
+}
+
 func TestLocalBackend_GetImageDirect(t *testing.T) {
 	t.Run("image", func(t *testing.T) {
 		handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
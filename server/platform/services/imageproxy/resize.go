@@ -0,0 +1,230 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package imageproxy
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"math"
+	"mime"
+	"net/http"
+	"net/url"
+	"runtime"
+	"strconv"
+	"strings"
+
+	"github.com/disintegration/imaging"
+
+	mmimaging "github.com/mattermost/mattermost/server/v8/channels/app/imaging"
+)
+
+const (
+	// ImageFitContain scales an image down to fit within the requested dimensions.
+	ImageFitContain = "contain"
+	// ImageFitCover scales an image down to cover the requested dimensions, and crops it to them.
+	ImageFitCover = "cover"
+
+	// MaxImageDimension is the largest width or height an image can be resized to.
+	MaxImageDimension = 4096
+
+	// Larger images are served as they are.
+	maxResizedImageSourceBytes = 32 * 1024 * 1024
+
+	resizedImageJPEGQuality = 90
+	resizedImageWebPQuality = 80
+)
+
+// The formats of the images that can be resized. Animated GIFs and SVGs are served as they are.
+var resizableContentTypes = map[string]bool{
+	"image/jpeg": true,
+	"image/png":  true,
+	"image/webp": true,
+	"image/bmp":  true,
+	"image/tiff": true,
+}
+
+// ImageOptions holds the dimensions a proxied image is resized to. Images are only ever scaled
+// down, keeping their aspect ratio, and either dimension can be left unset.
+type ImageOptions struct {
+	Width  int
+	Height int
+	// Fit is how the image is resized when both dimensions are set, ImageFitContain by default.
+	Fit string
+}
+
+// IsZero returns true if the options don't resize the image.
+func (o ImageOptions) IsZero() bool {
+	return o.Width == 0 && o.Height == 0
+}
+
+func (o ImageOptions) isCover() bool {
+	return o.Fit == ImageFitCover && o.Width > 0 && o.Height > 0
+}
+
+// FitDimensions returns the dimensions that an image of the given dimensions is resized to.
+func (o ImageOptions) FitDimensions(width, height int) (int, int) {
+	if o.IsZero() || width <= 0 || height <= 0 {
+		return width, height
+	}
+
+	if o.isCover() {
+		// The image covers the requested dimensions, or is cropped to them without being
+		// scaled when it's smaller.
+		return min(width, o.Width), min(height, o.Height)
+	}
+
+	scale := 1.0
+	if o.Width > 0 && o.Width < width {
+		scale = float64(o.Width) / float64(width)
+	}
+	if o.Height > 0 && o.Height < height {
+		scale = math.Min(scale, float64(o.Height)/float64(height))
+	}
+	if scale == 1 {
+		return width, height
+	}
+	return max(int(math.Round(float64(width)*scale)), 1), max(int(math.Round(float64(height)*scale)), 1)
+}
+
+func (o ImageOptions) addToQuery(query url.Values) {
+	if o.Width > 0 {
+		query.Set("width", strconv.Itoa(o.Width))
+	}
+	if o.Height > 0 {
+		query.Set("height", strconv.Itoa(o.Height))
+	}
+	if o.Fit != "" && o.Fit != ImageFitContain {
+		query.Set("fit", o.Fit)
+	}
+}
+
+func parseImageOptions(query url.Values) (ImageOptions, error) {
+	var opts ImageOptions
+	for _, dimension := range []struct {
+		name  string
+		value *int
+	}{{"width", &opts.Width}, {"height", &opts.Height}} {
+		str := query.Get(dimension.name)
+		if str == "" {
+			continue
+		}
+		value, err := strconv.Atoi(str)
+		if err != nil || value <= 0 || value > MaxImageDimension {
+			return ImageOptions{}, fmt.Errorf("the %s must be between 1 and %d", dimension.name, MaxImageDimension)
+		}
+		*dimension.value = value
+	}
+
+	opts.Fit = query.Get("fit")
+	if opts.Fit != "" && opts.Fit != ImageFitContain && opts.Fit != ImageFitCover {
+		return ImageOptions{}, fmt.Errorf("unknown fit %q", opts.Fit)
+	}
+	return opts, nil
+}
+
+// acceptsWebP returns true if the Accept header of the request explicitly accepts WebP images.
+func acceptsWebP(r *http.Request) bool {
+	for _, accept := range r.Header.Values("Accept") {
+		for _, mediaRange := range strings.Split(accept, ",") {
+			mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(mediaRange))
+			if err == nil && mediaType == "image/webp" && params["q"] != "0" {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// imageResizer resizes and re-encodes the proxied images.
+type imageResizer struct {
+	decoder       *mmimaging.Decoder
+	encoder       *mmimaging.Encoder
+	converter     *mmimaging.Converter
+	maxResolution int64
+}
+
+func newImageResizer(converter *mmimaging.Converter, maxResolution int64) (*imageResizer, error) {
+	decoder, err := mmimaging.NewDecoder(mmimaging.DecoderOptions{
+		ConcurrencyLevel: runtime.NumCPU(),
+		Converter:        converter,
+	})
+	if err != nil {
+		return nil, err
+	}
+	encoder, err := mmimaging.NewEncoder(mmimaging.EncoderOptions{
+		ConcurrencyLevel: runtime.NumCPU(),
+		Converter:        converter,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &imageResizer{
+		decoder:       decoder,
+		encoder:       encoder,
+		converter:     converter,
+		maxResolution: maxResolution,
+	}, nil
+}
+
+// resize resizes the given image, and encodes it in WebP if requested and possible. It returns
+// a nil image when the original should be served instead, because it can't be resized or
+// doesn't need to be.
+func (r *imageResizer) resize(data []byte, contentType string, opts ImageOptions, webp bool) ([]byte, string, error) {
+	if !resizableContentTypes[contentType] || len(data) > maxResizedImageSourceBytes {
+		return nil, "", nil
+	}
+
+	config, format, err := r.decoder.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, "", err
+	}
+	if r.maxResolution > 0 && int64(config.Width)*int64(config.Height) > r.maxResolution {
+		return nil, "", nil
+	}
+
+	outFormat := "png"
+	if webp && r.converter.IsEnabled() {
+		outFormat = "webp"
+	} else if format == "jpeg" {
+		outFormat = "jpeg"
+	}
+	width, height := opts.FitDimensions(config.Width, config.Height)
+	if width == config.Width && height == config.Height && outFormat == format {
+		return nil, "", nil
+	}
+
+	img, _, release, err := r.decoder.DecodeMemBounded(bytes.NewReader(data))
+	if err != nil {
+		return nil, "", err
+	}
+	defer release()
+
+	resized := resizeImage(img, opts, width, height)
+
+	var buf bytes.Buffer
+	switch outFormat {
+	case "webp":
+		err = r.encoder.EncodeWebP(&buf, resized, resizedImageWebPQuality)
+	case "jpeg":
+		err = r.encoder.EncodeJPEG(&buf, resized, resizedImageJPEGQuality)
+	default:
+		err = r.encoder.EncodePNG(&buf, resized)
+	}
+	if err != nil {
+		return nil, "", err
+	}
+	return buf.Bytes(), "image/" + outFormat, nil
+}
+
+func resizeImage(img image.Image, opts ImageOptions, width, height int) image.Image {
+	bounds := img.Bounds()
+	if width == bounds.Dx() && height == bounds.Dy() {
+		return img
+	}
+	if opts.isCover() {
+		return imaging.Fill(img, width, height, imaging.Center, imaging.Lanczos)
+	}
+	return imaging.Resize(img, width, height, imaging.Lanczos)
+}
@@ -0,0 +1,171 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package imageproxy
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func makeTestPNG(t *testing.T, width, height int) []byte {
+	t.Helper()
+
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for x := 0; x < width; x++ {
+		for y := 0; y < height; y++ {
+			img.Set(x, y, color.RGBA{uint8(x), uint8(y), 128, 255})
+		}
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, png.Encode(&buf, img))
+	return buf.Bytes()
+}
+
+func TestImageOptionsFitDimensions(t *testing.T) {
+	for _, test := range []struct {
+		Name           string
+		Options        ImageOptions
+		Width, Height  int
+		ExpectedWidth  int
+		ExpectedHeight int
+	}{
+		{"no options", ImageOptions{}, 800, 600, 800, 600},
+		{"width only", ImageOptions{Width: 400}, 800, 600, 400, 300},
+		{"height only", ImageOptions{Height: 300}, 800, 600, 400, 300},
+		{"contain in both dimensions", ImageOptions{Width: 400, Height: 400}, 800, 600, 400, 300},
+		{"contain in the limiting dimension", ImageOptions{Width: 400, Height: 100}, 800, 600, 133, 100},
+		{"no upscaling", ImageOptions{Width: 1600, Height: 1200}, 800, 600, 800, 600},
+		{"cover", ImageOptions{Width: 400, Height: 400, Fit: ImageFitCover}, 800, 600, 400, 400},
+		{"cover a larger box", ImageOptions{Width: 1000, Height: 400, Fit: ImageFitCover}, 800, 600, 800, 400},
+		{"cover with a single dimension", ImageOptions{Width: 400, Fit: ImageFitCover}, 800, 600, 400, 300},
+		{"tiny result", ImageOptions{Width: 1}, 800, 10, 1, 1},
+	} {
+		t.Run(test.Name, func(t *testing.T) {
+			width, height := test.Options.FitDimensions(test.Width, test.Height)
+			assert.Equal(t, test.ExpectedWidth, width)
+			assert.Equal(t, test.ExpectedHeight, height)
+		})
+	}
+}
+
+func TestParseImageOptions(t *testing.T) {
+	for _, test := range []struct {
+		Name     string
+		Query    string
+		Expected ImageOptions
+		Error    bool
+	}{
+		{Name: "no options", Query: "url=https%3A%2F%2Fexample.com%2Fimage.png"},
+		{Name: "dimensions", Query: "width=400&height=300", Expected: ImageOptions{Width: 400, Height: 300}},
+		{Name: "fit", Query: "width=400&height=300&fit=cover", Expected: ImageOptions{Width: 400, Height: 300, Fit: ImageFitCover}},
+		{Name: "invalid width", Query: "width=abc", Error: true},
+		{Name: "negative height", Query: "height=-1", Error: true},
+		{Name: "too large", Query: "width=4097", Error: true},
+		{Name: "unknown fit", Query: "width=400&fit=stretch", Error: true},
+	} {
+		t.Run(test.Name, func(t *testing.T) {
+			query, err := url.ParseQuery(test.Query)
+			require.NoError(t, err)
+
+			opts, err := parseImageOptions(query)
+			if test.Error {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, test.Expected, opts)
+		})
+	}
+}
+
+func TestAcceptsWebP(t *testing.T) {
+	for _, test := range []struct {
+		Accept   string
+		Expected bool
+	}{
+		{"", false},
+		{"*/*", false},
+		{"image/avif,image/webp,image/apng,image/*,*/*;q=0.8", true},
+		{"image/png, image/webp;q=0.9", true},
+		{"image/webp;q=0", false},
+	} {
+		t.Run(test.Accept, func(t *testing.T) {
+			r, err := http.NewRequest(http.MethodGet, "", nil)
+			require.NoError(t, err)
+			r.Header.Set("Accept", test.Accept)
+			assert.Equal(t, test.Expected, acceptsWebP(r))
+		})
+	}
+}
+
+func TestImageResizer(t *testing.T) {
+	resizer, err := newImageResizer(nil, 7680*4320)
+	require.NoError(t, err)
+
+	data := makeTestPNG(t, 200, 100)
+
+	t.Run("resize", func(t *testing.T) {
+		resized, contentType, err := resizer.resize(data, "image/png", ImageOptions{Width: 50}, false)
+		require.NoError(t, err)
+		require.NotNil(t, resized)
+		assert.Equal(t, "image/png", contentType)
+
+		config, err := png.DecodeConfig(bytes.NewReader(resized))
+		require.NoError(t, err)
+		assert.Equal(t, 50, config.Width)
+		assert.Equal(t, 25, config.Height)
+	})
+
+	t.Run("cover", func(t *testing.T) {
+		resized, _, err := resizer.resize(data, "image/png", ImageOptions{Width: 50, Height: 50, Fit: ImageFitCover}, false)
+		require.NoError(t, err)
+		require.NotNil(t, resized)
+
+		config, err := png.DecodeConfig(bytes.NewReader(resized))
+		require.NoError(t, err)
+		assert.Equal(t, 50, config.Width)
+		assert.Equal(t, 50, config.Height)
+	})
+
+	t.Run("original served when not resized", func(t *testing.T) {
+		resized, _, err := resizer.resize(data, "image/png", ImageOptions{Width: 400}, false)
+		require.NoError(t, err)
+		assert.Nil(t, resized)
+	})
+
+	t.Run("original served without a converter for WebP", func(t *testing.T) {
+		resized, _, err := resizer.resize(data, "image/png", ImageOptions{}, true)
+		require.NoError(t, err)
+		assert.Nil(t, resized)
+	})
+
+	t.Run("unsupported format", func(t *testing.T) {
+		resized, _, err := resizer.resize([]byte("<svg></svg>"), "image/svg+xml", ImageOptions{Width: 50}, false)
+		require.NoError(t, err)
+		assert.Nil(t, resized)
+	})
+
+	t.Run("resolution too large", func(t *testing.T) {
+		smallResizer, err := newImageResizer(nil, 100)
+		require.NoError(t, err)
+
+		resized, _, err := smallResizer.resize(data, "image/png", ImageOptions{Width: 50}, false)
+		require.NoError(t, err)
+		assert.Nil(t, resized)
+	})
+
+	t.Run("invalid image", func(t *testing.T) {
+		_, _, err := resizer.resize([]byte("not an image"), "image/png", ImageOptions{Width: 50}, false)
+		require.Error(t, err)
+	})
+}
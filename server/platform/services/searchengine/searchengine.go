@@ -21,10 +21,17 @@ func (seb *Broker) RegisterBleveEngine(be SearchEngineInterface) {
 	seb.BleveEngine = be
 }
 
+func (seb *Broker) RegisterSemanticEngine(se SearchEngineInterface) {
+	seb.SemanticEngine = se
+}
+
 type Broker struct {
 	cfg                 *model.Config
 	ElasticsearchEngine SearchEngineInterface
 	BleveEngine         SearchEngineInterface
+	// SemanticEngine only indexes posts, and is only used for the searches asking for it, so it
+	// isn't part of the active engines.
+	SemanticEngine SearchEngineInterface
 }
 
 func (seb *Broker) UpdateConfig(cfg *model.Config) *model.AppError {
@@ -37,6 +44,10 @@ func (seb *Broker) UpdateConfig(cfg *model.Config) *model.AppError {
 		seb.BleveEngine.UpdateConfig(cfg)
 	}
 
+	if seb.SemanticEngine != nil {
+		seb.SemanticEngine.UpdateConfig(cfg)
+	}
+
 	return nil
 }
 
@@ -51,6 +62,24 @@ func (seb *Broker) GetActiveEngines() []SearchEngineInterface {
 	return engines
 }
 
+// GetActivePostEngines returns the active engines indexing posts, which are the active engines and
+// the semantic engine.
+func (seb *Broker) GetActivePostEngines() []SearchEngineInterface {
+	engines := seb.GetActiveEngines()
+	if seb.SemanticEngine != nil && seb.SemanticEngine.IsActive() && seb.SemanticEngine.IsIndexingEnabled() {
+		engines = append(engines, seb.SemanticEngine)
+	}
+	return engines
+}
+
+// GetSemanticEngine returns the semantic engine if it can be searched, or nil otherwise.
+func (seb *Broker) GetSemanticEngine() SearchEngineInterface {
+	if seb.SemanticEngine != nil && seb.SemanticEngine.IsActive() && seb.SemanticEngine.IsSearchEnabled() {
+		return seb.SemanticEngine
+	}
+	return nil
+}
+
 func (seb *Broker) ActiveEngine() string {
 	activeEngines := seb.GetActiveEngines()
 	if len(activeEngines) > 0 {
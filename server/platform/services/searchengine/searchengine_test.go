@@ -40,3 +40,38 @@ func TestActiveEngine(t *testing.T) {
 
 	assert.Equal(t, "none", b.ActiveEngine())
 }
+
+func TestSemanticEngine(t *testing.T) {
+	cfg := &model.Config{}
+	cfg.SetDefaults()
+
+	b := NewBroker(cfg)
+	assert.Nil(t, b.GetSemanticEngine())
+	assert.Empty(t, b.GetActivePostEngines())
+
+	bleveMock := &mocks.SearchEngineInterface{}
+	bleveMock.On("IsActive").Return(true)
+	bleveMock.On("IsIndexingEnabled").Return(true)
+	bleveMock.On("GetName").Return("bleve")
+	b.BleveEngine = bleveMock
+
+	semanticMock := &mocks.SearchEngineInterface{}
+	semanticMock.On("IsActive").Return(true)
+	semanticMock.On("IsIndexingEnabled").Return(true)
+	semanticMock.On("IsSearchEnabled").Return(true)
+	b.RegisterSemanticEngine(semanticMock)
+
+	assert.Equal(t, []SearchEngineInterface{bleveMock}, b.GetActiveEngines())
+	assert.Equal(t, []SearchEngineInterface{bleveMock, semanticMock}, b.GetActivePostEngines())
+	assert.Equal(t, semanticMock, b.GetSemanticEngine())
+	assert.Equal(t, "bleve", b.ActiveEngine())
+
+	disabledMock := &mocks.SearchEngineInterface{}
+	disabledMock.On("IsActive").Return(true)
+	disabledMock.On("IsIndexingEnabled").Return(true)
+	disabledMock.On("IsSearchEnabled").Return(false)
+	b.RegisterSemanticEngine(disabledMock)
+
+	assert.Nil(t, b.GetSemanticEngine())
+	assert.Equal(t, []SearchEngineInterface{bleveMock, disabledMock}, b.GetActivePostEngines())
+}
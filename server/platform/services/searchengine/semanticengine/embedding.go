@@ -0,0 +1,219 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package semanticengine
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"io"
+	"math"
+	"net/http"
+	"strings"
+	"unicode"
+
+	"github.com/pkg/errors"
+)
+
+// maxHTTPResponseSize limits how much of a response from the embeddings service is read.
+const maxHTTPResponseSize = 64 * 1024 * 1024
+
+// An EmbeddingProvider computes the embeddings of texts, which are vectors whose cosine
+// similarity reflects how close the meanings of the texts are. Providers must be safe for
+// concurrent use.
+type EmbeddingProvider interface {
+	// Name identifies the provider and its model. Embeddings computed by providers with
+	// different names can't be compared.
+	Name() string
+	// Embed returns the embeddings of texts, in the same order.
+	Embed(ctx context.Context, texts []string) ([][]float32, error)
+}
+
+// LocalProvider computes embeddings without contacting any service, by hashing the words of the
+// text and their trigrams into a vector. Texts sharing words or parts of words get similar
+// embeddings, which makes the provider deterministic and suitable for tests and development
+// servers, but it doesn't know about synonyms.
+type LocalProvider struct {
+	dimensions int
+}
+
+func NewLocalProvider(dimensions int) *LocalProvider {
+	return &LocalProvider{
+		dimensions: dimensions,
+	}
+}
+
+func (p *LocalProvider) Name() string {
+	return fmt.Sprintf("local:%d", p.dimensions)
+}
+
+func (p *LocalProvider) Embed(ctx context.Context, texts []string) ([][]float32, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	embeddings := make([][]float32, len(texts))
+	for i, text := range texts {
+		embeddings[i] = p.embed(text)
+	}
+	return embeddings, nil
+}
+
+func (p *LocalProvider) embed(text string) []float32 {
+	embedding := make([]float32, p.dimensions)
+	for _, word := range tokenize(text) {
+		p.addFeature(embedding, word, 1)
+
+		padded := []rune("^" + word + "$")
+		for i := 0; i+3 <= len(padded); i++ {
+			p.addFeature(embedding, string(padded[i:i+3]), 0.5)
+		}
+	}
+	normalize(embedding)
+	return embedding
+}
+
+// addFeature adds weight to the dimension the feature hashes to. The sign of the weight also
+// depends on the hash, so that unrelated features cancel out rather than add up.
+func (p *LocalProvider) addFeature(embedding []float32, feature string, weight float32) {
+	h := fnv.New64a()
+	h.Write([]byte(feature))
+	sum := h.Sum64()
+
+	if sum>>63 == 1 {
+		weight = -weight
+	}
+	embedding[sum%uint64(p.dimensions)] += weight
+}
+
+// HTTPProvider computes embeddings using a service implementing the OpenAI embeddings API.
+type HTTPProvider struct {
+	client *http.Client
+	url    string
+	apiKey string
+	model  string
+}
+
+type httpEmbeddingsRequest struct {
+	Model string   `json:"model,omitempty"`
+	Input []string `json:"input"`
+}
+
+type httpEmbeddingsResponse struct {
+	Data []struct {
+		Index     int       `json:"index"`
+		Embedding []float32 `json:"embedding"`
+	} `json:"data"`
+	Error *struct {
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+func NewHTTPProvider(client *http.Client, url, apiKey, model string) *HTTPProvider {
+	return &HTTPProvider{
+		client: client,
+		url:    url,
+		apiKey: apiKey,
+		model:  model,
+	}
+}
+
+func (p *HTTPProvider) Name() string {
+	return "http:" + p.model
+}
+
+func (p *HTTPProvider) Embed(ctx context.Context, texts []string) ([][]float32, error) {
+	body, err := json.Marshal(httpEmbeddingsRequest{
+		Model: p.model,
+		Input: texts,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to encode embeddings request")
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.url, bytes.NewReader(body))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create embeddings request")
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+	if p.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+p.apiKey)
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to request embeddings")
+	}
+	defer resp.Body.Close()
+
+	var result httpEmbeddingsResponse
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxHTTPResponseSize)).Decode(&result); err != nil {
+		return nil, errors.Wrapf(err, "failed to decode embeddings response with status %d", resp.StatusCode)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		message := ""
+		if result.Error != nil {
+			message = result.Error.Message
+		}
+		return nil, fmt.Errorf("embeddings service returned status %d: %s", resp.StatusCode, message)
+	}
+
+	embeddings := make([][]float32, len(texts))
+	for _, data := range result.Data {
+		if data.Index < 0 || data.Index >= len(texts) {
+			return nil, fmt.Errorf("embeddings service returned an embedding for unknown input %d", data.Index)
+		}
+		normalize(data.Embedding)
+		embeddings[data.Index] = data.Embedding
+	}
+	for i, embedding := range embeddings {
+		if embedding == nil {
+			return nil, fmt.Errorf("embeddings service returned no embedding for input %d", i)
+		}
+	}
+
+	return embeddings, nil
+}
+
+// tokenize splits text into lower case words.
+func tokenize(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// normalize scales embedding to a length of 1, so that the cosine similarity of two embeddings is
+// their dot product.
+func normalize(embedding []float32) {
+	var sum float64
+	for _, v := range embedding {
+		sum += float64(v) * float64(v)
+	}
+	if sum == 0 {
+		return
+	}
+
+	norm := float32(math.Sqrt(sum))
+	for i := range embedding {
+		embedding[i] /= norm
+	}
+}
+
+// similarity returns the cosine similarity of two normalized embeddings, or 0 when they can't be
+// compared.
+func similarity(a, b []float32) float64 {
+	if len(a) != len(b) {
+		return 0
+	}
+
+	var dot float64
+	for i := range a {
+		dot += float64(a[i]) * float64(b[i])
+	}
+	return dot
+}
@@ -0,0 +1,115 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package semanticengine
+
+import (
+	"context"
+	"encoding/json"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLocalProvider(t *testing.T) {
+	provider := NewLocalProvider(256)
+	assert.Equal(t, "local:256", provider.Name())
+
+	texts := []string{
+		"The deployment failed last night",
+		"the deploy FAILED",
+		"What's for lunch today?",
+		"",
+	}
+	embeddings, err := provider.Embed(context.Background(), texts)
+	require.NoError(t, err)
+	require.Len(t, embeddings, 4)
+
+	t.Run("embeddings are deterministic", func(t *testing.T) {
+		again, err := NewLocalProvider(256).Embed(context.Background(), texts)
+		require.NoError(t, err)
+		assert.Equal(t, embeddings, again)
+	})
+
+	t.Run("embeddings are normalized", func(t *testing.T) {
+		for _, embedding := range embeddings[:3] {
+			require.Len(t, embedding, 256)
+			assert.InDelta(t, 1, similarity(embedding, embedding), 1e-5)
+		}
+		assert.Zero(t, similarity(embeddings[3], embeddings[3]))
+	})
+
+	t.Run("similar texts have similar embeddings", func(t *testing.T) {
+		assert.Greater(t, similarity(embeddings[0], embeddings[1]), similarity(embeddings[0], embeddings[2]))
+		assert.Greater(t, similarity(embeddings[0], embeddings[1]), 0.4)
+	})
+
+	t.Run("cancelled context", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		_, err := provider.Embed(ctx, texts)
+		require.ErrorIs(t, err, context.Canceled)
+	})
+}
+
+func TestHTTPProvider_Embed(t *testing.T) {
+	t.Run("should embed texts", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, http.MethodPost, r.Method)
+			assert.Equal(t, "Bearer secret", r.Header.Get("Authorization"))
+
+			var req httpEmbeddingsRequest
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+			assert.Equal(t, httpEmbeddingsRequest{Model: "small", Input: []string{"one", "two"}}, req)
+
+			// The embeddings may be returned in any order.
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"data": [{"index": 1, "embedding": [0, 2]}, {"index": 0, "embedding": [3, 4]}]}`))
+		}))
+		defer server.Close()
+
+		provider := NewHTTPProvider(server.Client(), server.URL, "secret", "small")
+		assert.Equal(t, "http:small", provider.Name())
+
+		embeddings, err := provider.Embed(context.Background(), []string{"one", "two"})
+		require.NoError(t, err)
+		assert.Equal(t, [][]float32{{0.6, 0.8}, {0, 1}}, embeddings)
+	})
+
+	t.Run("should fail on an error response", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte(`{"error": {"message": "invalid api key"}}`))
+		}))
+		defer server.Close()
+
+		provider := NewHTTPProvider(server.Client(), server.URL, "", "")
+		_, err := provider.Embed(context.Background(), []string{"one"})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "invalid api key")
+	})
+
+	t.Run("should fail on a missing embedding", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(`{"data": [{"index": 0, "embedding": [1, 0]}]}`))
+		}))
+		defer server.Close()
+
+		provider := NewHTTPProvider(server.Client(), server.URL, "", "")
+		_, err := provider.Embed(context.Background(), []string{"one", "two"})
+		require.Error(t, err)
+	})
+}
+
+func TestSimilarity(t *testing.T) {
+	a := []float32{1, 0}
+	b := []float32{float32(math.Sqrt2) / 2, float32(math.Sqrt2) / 2}
+
+	assert.InDelta(t, 1, similarity(a, a), 1e-6)
+	assert.InDelta(t, math.Sqrt2/2, similarity(a, b), 1e-6)
+	assert.Zero(t, similarity(a, []float32{1, 0, 0}))
+}
@@ -0,0 +1,44 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package semanticengine
+
+import (
+	"strings"
+
+	"github.com/mattermost/mattermost/server/public/model"
+)
+
+// postDocument is the form of a post the search terms are matched against.
+type postDocument struct {
+	Id        string
+	ChannelId string
+	UserId    string
+	CreateAt  int64
+	// Words are the distinct lower case words of the message.
+	Words []string
+	// Hashtags are the lower case hashtags of the post.
+	Hashtags  []string
+	Embedding []float32
+}
+
+func newPostDocument(post *model.Post, embedding []float32) *postDocument {
+	words := []string{}
+	seen := map[string]bool{}
+	for _, word := range tokenize(post.Message) {
+		if !seen[word] {
+			seen[word] = true
+			words = append(words, word)
+		}
+	}
+
+	return &postDocument{
+		Id:        post.Id,
+		ChannelId: post.ChannelId,
+		UserId:    post.UserId,
+		CreateAt:  post.CreateAt,
+		Words:     words,
+		Hashtags:  strings.Fields(strings.ToLower(post.Hashtags)),
+		Embedding: embedding,
+	}
+}
@@ -0,0 +1,201 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package indexer
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+	"github.com/mattermost/mattermost/server/public/shared/request"
+	"github.com/mattermost/mattermost/server/v8/channels/jobs"
+	"github.com/mattermost/mattermost/server/v8/platform/services/searchengine/semanticengine"
+)
+
+const (
+	timeBetweenBatches = 100 * time.Millisecond
+
+	// The embeddings of the posts of a batch are computed at once.
+	batchSize = 100
+)
+
+// SemanticIndexerWorker indexes the existing posts with the semantic search engine, such as after
+// enabling semantic search or changing the embedding provider. The new posts are indexed as they
+// are created.
+type SemanticIndexerWorker struct {
+	name      string
+	jobServer *jobs.JobServer
+	logger    mlog.LoggerIFace
+	engine    *semanticengine.SemanticEngine
+
+	stop    chan struct{}
+	stopped chan bool
+	jobs    chan model.Job
+}
+
+func MakeWorker(jobServer *jobs.JobServer, engine *semanticengine.SemanticEngine) *SemanticIndexerWorker {
+	const workerName = "SemanticIndexer"
+	return &SemanticIndexerWorker{
+		name:      workerName,
+		jobServer: jobServer,
+		logger:    jobServer.Logger().With(mlog.String("worker_name", workerName)),
+		engine:    engine,
+		stop:      make(chan struct{}),
+		stopped:   make(chan bool, 1),
+		jobs:      make(chan model.Job),
+	}
+}
+
+func (worker *SemanticIndexerWorker) Run() {
+	worker.logger.Debug("Worker started")
+	// We have to re-assign the stop channel again, because
+	// it might happen that the job was restarted due to a config change.
+	worker.stop = make(chan struct{}, 1)
+
+	defer func() {
+		worker.logger.Debug("Worker finished")
+		worker.stopped <- true
+	}()
+
+	for {
+		select {
+		case <-worker.stop:
+			worker.logger.Debug("Worker received stop signal")
+			return
+		case job := <-worker.jobs:
+			worker.DoJob(&job)
+		}
+	}
+}
+
+func (worker *SemanticIndexerWorker) Stop() {
+	worker.logger.Debug("Worker stopping")
+	close(worker.stop)
+	<-worker.stopped
+}
+
+func (worker *SemanticIndexerWorker) JobChannel() chan<- model.Job {
+	return worker.jobs
+}
+
+func (worker *SemanticIndexerWorker) IsEnabled(cfg *model.Config) bool {
+	return *cfg.SemanticSearchSettings.EnableIndexing
+}
+
+func (worker *SemanticIndexerWorker) DoJob(job *model.Job) {
+	logger := worker.logger.With(jobs.JobLoggerFields(job)...)
+	logger.Debug("Worker: Received a new candidate job.")
+	defer worker.jobServer.HandleJobPanic(logger, job)
+
+	if claimed, err := worker.jobServer.ClaimJob(job); err != nil {
+		logger.Warn("SemanticIndexerWorker experienced an error while trying to claim job", mlog.Err(err))
+		return
+	} else if !claimed {
+		return
+	}
+
+	c := request.EmptyContext(worker.logger)
+
+	var appErr *model.AppError
+	// We get the job again because ClaimJob changes the job status.
+	job, appErr = worker.jobServer.GetJob(c, job.Id)
+	if appErr != nil {
+		logger.Error("SemanticIndexerWorker: job execution error", mlog.Err(appErr))
+		worker.setJobError(logger, job, appErr)
+		return
+	}
+
+	// Resume from the progress saved in the job data, if any.
+	startPostID := job.Data["start_post_id"]
+	var startTime, donePostCount int64
+	if value := job.Data["start_create_at"]; value != "" {
+		if startTime, appErr = parseJobData("start_create_at", value); appErr != nil {
+			worker.setJobError(logger, job, appErr)
+			return
+		}
+	}
+	if value := job.Data["done_post_count"]; value != "" {
+		if donePostCount, appErr = parseJobData("done_post_count", value); appErr != nil {
+			worker.setJobError(logger, job, appErr)
+			return
+		}
+	}
+
+	for {
+		select {
+		case <-worker.stop:
+			logger.Info("Worker: Semantic indexing has been canceled via Worker Stop. Setting the job back to pending.")
+			if err := worker.jobServer.SetJobPending(job); err != nil {
+				worker.logger.Error("Worker: Failed to mark job as pending", mlog.Err(err))
+			}
+			return
+		case <-time.After(timeBetweenBatches):
+			posts, err := worker.jobServer.Store.Post().GetPostsBatchForIndexing(startTime, startPostID, batchSize)
+			if err != nil {
+				logger.Error("Worker: Failed to get posts for semantic indexing", mlog.Err(err))
+				worker.setJobError(logger, job, model.NewAppError("DoJob", "app.post.get_posts_batch_for_indexing.get.app_error", nil, "", http.StatusInternalServerError).Wrap(err))
+				return
+			}
+
+			if len(posts) == 0 {
+				logger.Info("SemanticIndexerWorker: Job is complete", mlog.Int("done_post_count", donePostCount))
+				worker.setJobSuccess(logger, job)
+				return
+			}
+
+			batch := make([]*model.Post, 0, len(posts))
+			for _, post := range posts {
+				batch = append(batch, &post.Post)
+			}
+			if appErr := worker.engine.IndexPosts(batch); appErr != nil {
+				logger.Error("Worker: Failed to index posts", mlog.Err(appErr))
+				worker.setJobError(logger, job, appErr)
+				return
+			}
+
+			lastPost := posts[len(posts)-1]
+			startPostID = lastPost.Id
+			startTime = lastPost.CreateAt
+			donePostCount += int64(len(posts))
+
+			if job.Data == nil {
+				job.Data = make(model.StringMap)
+			}
+			job.Data["start_post_id"] = startPostID
+			job.Data["start_create_at"] = strconv.FormatInt(startTime, 10)
+			job.Data["done_post_count"] = strconv.FormatInt(donePostCount, 10)
+			if err := worker.jobServer.UpdateInProgressJobData(job); err != nil {
+				logger.Warn("Worker: Failed to save the progress of the job", mlog.Err(err))
+			}
+		}
+	}
+}
+
+func parseJobData(key, value string) (int64, *model.AppError) {
+	parsed, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return 0, model.NewAppError("parseJobData", model.NoTranslation, nil, "key="+key, http.StatusInternalServerError).Wrap(err)
+	}
+	return parsed, nil
+}
+
+func (worker *SemanticIndexerWorker) setJobSuccess(logger mlog.LoggerIFace, job *model.Job) {
+	if err := worker.jobServer.SetJobProgress(job, 100); err != nil {
+		logger.Error("Worker: Failed to update progress for job", mlog.Err(err))
+		worker.setJobError(logger, job, err)
+	}
+
+	if err := worker.jobServer.SetJobSuccess(job); err != nil {
+		logger.Error("SemanticIndexerWorker: Failed to set success for job", mlog.Err(err))
+		worker.setJobError(logger, job, err)
+	}
+}
+
+func (worker *SemanticIndexerWorker) setJobError(logger mlog.LoggerIFace, job *model.Job, appError *model.AppError) {
+	if err := worker.jobServer.SetJobError(job, appError); err != nil {
+		logger.Error("SemanticIndexerWorker: Failed to set job error", mlog.Err(err))
+	}
+}
@@ -0,0 +1,515 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package semanticengine
+
+import (
+	"context"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+	"github.com/mattermost/mattermost/server/public/shared/request"
+)
+
+// queryTerm is a word of the search terms. A prefix term matches the words starting with it.
+type queryTerm struct {
+	word   string
+	prefix bool
+}
+
+func (t queryTerm) matches(word string) bool {
+	if t.prefix {
+		return strings.HasPrefix(word, t.word)
+	}
+	return word == t.word
+}
+
+// dateRange is a range of creation times, where a zero bound means the range is unbounded.
+type dateRange struct {
+	min int64
+	max int64
+}
+
+func (r dateRange) contains(millis int64) bool {
+	return (r.min == 0 || millis >= r.min) && (r.max == 0 || millis <= r.max)
+}
+
+// postQuery is a search for posts, built from the search params.
+type postQuery struct {
+	// text is the text whose embedding is compared with the embeddings of the posts. The posts
+	// are only ranked by date when it's empty.
+	text             string
+	terms            []queryTerm
+	excludedTerms    []queryTerm
+	hashtags         []string
+	excludedHashtags []string
+	orTerms          bool
+
+	inChannels       map[string]bool
+	excludedChannels map[string]bool
+	fromUsers        map[string]bool
+	excludedUsers    map[string]bool
+	dates            []dateRange
+	excludedDates    []dateRange
+}
+
+func parseQueryTerms(terms string) []queryTerm {
+	result := []queryTerm{}
+	for _, term := range strings.Fields(terms) {
+		prefix := strings.HasSuffix(term, "*")
+		words := tokenize(term)
+		for i, word := range words {
+			result = append(result, queryTerm{word: word, prefix: prefix && i == len(words)-1})
+		}
+	}
+	return result
+}
+
+func toSet(values []string) map[string]bool {
+	set := make(map[string]bool, len(values))
+	for _, value := range values {
+		set[value] = true
+	}
+	return set
+}
+
+func newPostQuery(searchParams []*model.SearchParams) *postQuery {
+	// The filters are the same in all the search params, so they are only read from the first.
+	params := searchParams[0]
+	q := &postQuery{
+		orTerms:          params.OrTerms,
+		inChannels:       toSet(params.InChannels),
+		excludedChannels: toSet(params.ExcludedChannels),
+		fromUsers:        toSet(params.FromUsers),
+		excludedUsers:    toSet(params.ExcludedUsers),
+	}
+
+	if params.OnDate != "" {
+		start, end := params.GetOnDateMillis()
+		q.dates = append(q.dates, dateRange{min: start, max: end})
+	} else {
+		if params.AfterDate != "" || params.BeforeDate != "" {
+			r := dateRange{}
+			if params.AfterDate != "" {
+				r.min = params.GetAfterDateMillis()
+			}
+			if params.BeforeDate != "" {
+				r.max = params.GetBeforeDateMillis()
+			}
+			q.dates = append(q.dates, r)
+		}
+		if params.ExcludedAfterDate != "" {
+			q.excludedDates = append(q.excludedDates, dateRange{min: params.GetExcludedAfterDateMillis()})
+		}
+		if params.ExcludedBeforeDate != "" {
+			q.excludedDates = append(q.excludedDates, dateRange{max: params.GetExcludedBeforeDateMillis()})
+		}
+		if params.ExcludedDate != "" {
+			start, end := params.GetExcludedDateMillis()
+			q.excludedDates = append(q.excludedDates, dateRange{min: start, max: end})
+		}
+	}
+
+	words := []string{}
+	for _, params := range searchParams {
+		if params.IsHashtag {
+			q.hashtags = append(q.hashtags, strings.Fields(strings.ToLower(params.Terms))...)
+			q.excludedHashtags = append(q.excludedHashtags, strings.Fields(strings.ToLower(params.ExcludedTerms))...)
+			continue
+		}
+
+		terms := parseQueryTerms(params.Terms)
+		q.terms = append(q.terms, terms...)
+		q.excludedTerms = append(q.excludedTerms, parseQueryTerms(params.ExcludedTerms)...)
+		for _, term := range terms {
+			words = append(words, term.word)
+		}
+	}
+	q.text = strings.Join(words, " ")
+
+	return q
+}
+
+// matchesFilters reports whether a post matches the filters of the query, regardless of its
+// score.
+func (q *postQuery) matchesFilters(doc *postDocument) bool {
+	if len(q.inChannels) > 0 && !q.inChannels[doc.ChannelId] {
+		return false
+	}
+	if q.excludedChannels[doc.ChannelId] {
+		return false
+	}
+	if len(q.fromUsers) > 0 && !q.fromUsers[doc.UserId] {
+		return false
+	}
+	if q.excludedUsers[doc.UserId] {
+		return false
+	}
+
+	for _, r := range q.dates {
+		if !r.contains(doc.CreateAt) {
+			return false
+		}
+	}
+	for _, r := range q.excludedDates {
+		if r.contains(doc.CreateAt) {
+			return false
+		}
+	}
+
+	for _, term := range q.excludedTerms {
+		for _, word := range doc.Words {
+			if term.matches(word) {
+				return false
+			}
+		}
+	}
+
+	if len(q.hashtags) > 0 || len(q.excludedHashtags) > 0 {
+		hashtags := toSet(doc.Hashtags)
+		for _, hashtag := range q.excludedHashtags {
+			if hashtags[hashtag] {
+				return false
+			}
+		}
+
+		found := 0
+		for _, hashtag := range q.hashtags {
+			if hashtags[hashtag] {
+				found++
+			}
+		}
+		if len(q.hashtags) > 0 && (found == 0 || (!q.orTerms && found < len(q.hashtags))) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// lexicalScore returns the share of the terms of the query found in a post, and the words of the
+// post matching them.
+func (q *postQuery) lexicalScore(doc *postDocument) (float64, []string) {
+	if len(q.terms) == 0 {
+		return 0, nil
+	}
+
+	found := 0
+	matches := []string{}
+	for _, term := range q.terms {
+		termFound := false
+		for _, word := range doc.Words {
+			if term.matches(word) {
+				termFound = true
+				matches = append(matches, word)
+			}
+		}
+		if termFound {
+			found++
+		}
+	}
+	return float64(found) / float64(len(q.terms)), matches
+}
+
+type scoredPost struct {
+	doc     *postDocument
+	score   float64
+	matches []string
+}
+
+// isIndexable reports whether a post is indexed. System posts and deleted posts aren't.
+func isIndexable(post *model.Post) bool {
+	return post.Type == "" && post.DeleteAt == 0
+}
+
+func (s *SemanticEngine) IndexPost(post *model.Post, teamId string) *model.AppError {
+	return s.IndexPosts([]*model.Post{post})
+}
+
+// IndexPosts indexes a batch of posts, computing the embeddings of their messages at once. The
+// posts that aren't indexable are removed from the index.
+func (s *SemanticEngine) IndexPosts(posts []*model.Post) *model.AppError {
+	provider, store, appErr := s.getProviderAndStore("Semanticengine.IndexPosts")
+	if appErr != nil {
+		return appErr
+	}
+
+	s.Mutex.RLock()
+	timeout := time.Duration(*s.cfg.SemanticSearchSettings.RequestTimeoutMilliseconds) * time.Millisecond
+	s.Mutex.RUnlock()
+
+	indexed := []*model.Post{}
+	messages := []string{}
+	for _, post := range posts {
+		if !isIndexable(post) {
+			if err := store.Delete(post.Id); err != nil {
+				return model.NewAppError("Semanticengine.IndexPosts", "semanticengine.delete_posts.error", nil, "", http.StatusInternalServerError).Wrap(err)
+			}
+			continue
+		}
+		indexed = append(indexed, post)
+		messages = append(messages, post.Message)
+	}
+	if len(indexed) == 0 {
+		return nil
+	}
+
+	// The embeddings are computed without holding the lock, as the provider may be a remote service.
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	embeddings, err := provider.Embed(ctx, messages)
+	if err != nil {
+		return model.NewAppError("Semanticengine.IndexPosts", "semanticengine.index_post.error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+
+	for i, post := range indexed {
+		if err := store.Save(model.NewPostEmbedding(post, provider.Name(), embeddings[i])); err != nil {
+			return model.NewAppError("Semanticengine.IndexPosts", "semanticengine.index_post.error", nil, "", http.StatusInternalServerError).Wrap(err)
+		}
+	}
+	return nil
+}
+
+// candidatesPageSize is the number of posts read from the store at once during a search.
+const candidatesPageSize = 1000
+
+// searchedChannels returns the ids of the channels searched by the query, among the channels the
+// user is a member of.
+func (q *postQuery) searchedChannels(channels model.ChannelList) []string {
+	channelIDs := []string{}
+	for _, channel := range channels {
+		if len(q.inChannels) > 0 && !q.inChannels[channel.Id] {
+			continue
+		}
+		if q.excludedChannels[channel.Id] {
+			continue
+		}
+		channelIDs = append(channelIDs, channel.Id)
+	}
+	return channelIDs
+}
+
+// candidateOptions returns the options selecting the posts that may match the query from the
+// store. The filters that can't be expressed with them are checked on the returned posts.
+func (q *postQuery) candidateOptions(channels model.ChannelList) model.PostEmbeddingSearchOptions {
+	opts := model.PostEmbeddingSearchOptions{
+		ChannelIDs:      q.searchedChannels(channels),
+		UserIDs:         make([]string, 0, len(q.fromUsers)),
+		ExcludedUserIDs: make([]string, 0, len(q.excludedUsers)),
+	}
+	for userID := range q.fromUsers {
+		opts.UserIDs = append(opts.UserIDs, userID)
+	}
+	for userID := range q.excludedUsers {
+		opts.ExcludedUserIDs = append(opts.ExcludedUserIDs, userID)
+	}
+	for _, r := range q.dates {
+		if r.min != 0 && r.min > opts.CreatedAfter {
+			opts.CreatedAfter = r.min
+		}
+		if r.max != 0 && (opts.CreatedBefore == 0 || r.max < opts.CreatedBefore) {
+			opts.CreatedBefore = r.max
+		}
+	}
+	return opts
+}
+
+func (s *SemanticEngine) SearchPosts(channels model.ChannelList, searchParams []*model.SearchParams, page, perPage int) ([]string, model.PostSearchMatches, *model.AppError) {
+	query := newPostQuery(searchParams)
+
+	provider, store, appErr := s.getProviderAndStore("Semanticengine.SearchPosts")
+	if appErr != nil {
+		return nil, nil, appErr
+	}
+
+	s.Mutex.RLock()
+	settings := s.cfg.SemanticSearchSettings
+	s.Mutex.RUnlock()
+
+	var embedding []float32
+	if query.text != "" {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Duration(*settings.RequestTimeoutMilliseconds)*time.Millisecond)
+		defer cancel()
+		embeddings, err := provider.Embed(ctx, []string{query.text})
+		if err != nil {
+			return nil, nil, model.NewAppError("Semanticengine.SearchPosts", "semanticengine.search_posts.error", nil, "", http.StatusInternalServerError).Wrap(err)
+		}
+		embedding = embeddings[0]
+	}
+
+	lexicalWeight := float64(*settings.LexicalWeightPercent) / 100
+	minimumScore := float64(*settings.MinimumScorePercent) / 100
+
+	// Only the posts of the channels the user is a member of are searched. The most recent posts
+	// matching the filters are scored, up to the maximum number of candidates. Without search
+	// terms, the posts are ranked by date, so the pages following the requested one aren't read.
+	opts := query.candidateOptions(channels)
+	results := []scoredPost{}
+	for scanned := 0; scanned < *settings.MaxCandidates; {
+		opts.Limit = min(candidatesPageSize, *settings.MaxCandidates-scanned)
+		posts, err := store.GetPostsForChannels(provider.Name(), opts)
+		if err != nil {
+			return nil, nil, model.NewAppError("Semanticengine.SearchPosts", "semanticengine.search_posts.error", nil, "", http.StatusInternalServerError).Wrap(err)
+		}
+
+		for _, post := range posts {
+			if !isIndexable(post.Post) {
+				continue
+			}
+
+			doc := newPostDocument(post.Post, post.Embedding)
+			if !query.matchesFilters(doc) {
+				continue
+			}
+
+			if query.text == "" {
+				results = append(results, scoredPost{doc: doc})
+				continue
+			}
+
+			lexical, matches := query.lexicalScore(doc)
+			semantic := max(similarity(embedding, doc.Embedding), 0)
+			score := (1-lexicalWeight)*semantic + lexicalWeight*lexical
+			if score < minimumScore {
+				continue
+			}
+			results = append(results, scoredPost{doc: doc, score: score, matches: matches})
+		}
+
+		scanned += len(posts)
+		if len(posts) < opts.Limit || (query.text == "" && len(results) >= (page+1)*perPage) {
+			break
+		}
+		last := posts[len(posts)-1].Post
+		opts.BeforeCreateAt = last.CreateAt
+		opts.BeforeID = last.Id
+	}
+
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].score != results[j].score {
+			return results[i].score > results[j].score
+		}
+		if results[i].doc.CreateAt != results[j].doc.CreateAt {
+			return results[i].doc.CreateAt > results[j].doc.CreateAt
+		}
+		return results[i].doc.Id > results[j].doc.Id
+	})
+
+	postIds := []string{}
+	matches := model.PostSearchMatches{}
+
+	start := page * perPage
+	if start >= len(results) {
+		return postIds, matches, nil
+	}
+	end := min(start+perPage, len(results))
+
+	for _, result := range results[start:end] {
+		postIds = append(postIds, result.doc.Id)
+		if len(result.matches) > 0 {
+			matches[result.doc.Id] = result.matches
+		}
+	}
+
+	return postIds, matches, nil
+}
+
+func (s *SemanticEngine) DeleteChannelPosts(rctx request.CTX, channelID string) *model.AppError {
+	store, appErr := s.getStore("Semanticengine.DeleteChannelPosts")
+	if appErr != nil {
+		return appErr
+	}
+
+	deleted, err := store.DeleteForChannel(channelID)
+	if err != nil {
+		return model.NewAppError("Semanticengine.DeleteChannelPosts", "semanticengine.delete_posts.error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+	rctx.Logger().Info("Posts for channel deleted", mlog.String("channel_id", channelID), mlog.Int("deleted", deleted))
+
+	return nil
+}
+
+func (s *SemanticEngine) DeleteUserPosts(rctx request.CTX, userID string) *model.AppError {
+	store, appErr := s.getStore("Semanticengine.DeleteUserPosts")
+	if appErr != nil {
+		return appErr
+	}
+
+	deleted, err := store.DeleteForUser(userID)
+	if err != nil {
+		return model.NewAppError("Semanticengine.DeleteUserPosts", "semanticengine.delete_posts.error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+	rctx.Logger().Info("Posts for user deleted", mlog.String("user_id", userID), mlog.Int("deleted", deleted))
+
+	return nil
+}
+
+func (s *SemanticEngine) DeletePost(post *model.Post) *model.AppError {
+	store, appErr := s.getStore("Semanticengine.DeletePost")
+	if appErr != nil {
+		return appErr
+	}
+
+	if err := store.Delete(post.Id); err != nil {
+		return model.NewAppError("Semanticengine.DeletePost", "semanticengine.delete_posts.error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+	return nil
+}
+
+// The semantic engine only indexes posts, so it ignores channels, users and files.
+
+func (s *SemanticEngine) IndexChannel(_ request.CTX, channel *model.Channel, userIDs, teamMemberIDs []string) *model.AppError {
+	return nil
+}
+
+func (s *SemanticEngine) SearchChannels(teamId, userID, term string, isGuest bool) ([]string, *model.AppError) {
+	return []string{}, nil
+}
+
+func (s *SemanticEngine) DeleteChannel(channel *model.Channel) *model.AppError {
+	return nil
+}
+
+func (s *SemanticEngine) IndexUser(_ request.CTX, user *model.User, teamsIds, channelsIds []string) *model.AppError {
+	return nil
+}
+
+func (s *SemanticEngine) SearchUsersInChannel(teamId, channelId string, restrictedToChannels []string, term string, options *model.UserSearchOptions) ([]string, []string, *model.AppError) {
+	return []string{}, []string{}, nil
+}
+
+func (s *SemanticEngine) SearchUsersInTeam(teamId string, restrictedToChannels []string, term string, options *model.UserSearchOptions) ([]string, *model.AppError) {
+	return []string{}, nil
+}
+
+func (s *SemanticEngine) DeleteUser(user *model.User) *model.AppError {
+	return nil
+}
+
+func (s *SemanticEngine) IndexFile(file *model.FileInfo, channelId string) *model.AppError {
+	return nil
+}
+
+func (s *SemanticEngine) SearchFiles(channels model.ChannelList, searchParams []*model.SearchParams, page, perPage int) ([]string, *model.AppError) {
+	return []string{}, nil
+}
+
+func (s *SemanticEngine) DeleteFile(fileID string) *model.AppError {
+	return nil
+}
+
+func (s *SemanticEngine) DeletePostFiles(rctx request.CTX, postID string) *model.AppError {
+	return nil
+}
+
+func (s *SemanticEngine) DeleteUserFiles(rctx request.CTX, userID string) *model.AppError {
+	return nil
+}
+
+func (s *SemanticEngine) DeleteFilesBatch(rctx request.CTX, endTime, limit int64) *model.AppError {
+	return nil
+}
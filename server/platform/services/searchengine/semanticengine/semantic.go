@@ -0,0 +1,256 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package semanticengine
+
+import (
+	"net/http"
+	"reflect"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/shared/httpservice"
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+	"github.com/mattermost/mattermost/server/public/shared/request"
+)
+
+const EngineName = "semantic"
+
+// EmbeddingStore keeps the embeddings of the indexed posts, so that they are shared by all the
+// nodes of a cluster. It is implemented by the PostEmbeddingStore of the store.
+type EmbeddingStore interface {
+	Save(embedding *model.PostEmbedding) error
+	GetPostsForChannels(provider string, opts model.PostEmbeddingSearchOptions) ([]*model.PostWithEmbedding, error)
+	Delete(postID string) error
+	DeleteForChannel(channelID string) (int64, error)
+	DeleteForUser(userID string) (int64, error)
+	DeleteBefore(endTime int64) (int64, error)
+	DeleteAll() error
+}
+
+// SemanticEngine is a search engine for posts, ranking them by the similarity of their
+// embeddings to the embedding of the search terms combined with how many of the search terms
+// they contain. The embeddings are computed by an EmbeddingProvider when the posts are indexed,
+// and are kept in the EmbeddingStore along with the name of the provider that computed them.
+type SemanticEngine struct {
+	Mutex       sync.RWMutex
+	ready       int32
+	cfg         *model.Config
+	httpService httpservice.HTTPService
+	provider    EmbeddingProvider
+	store       EmbeddingStore
+	indexSync   bool
+}
+
+func NewSemanticEngine(cfg *model.Config, httpService httpservice.HTTPService) *SemanticEngine {
+	return &SemanticEngine{
+		cfg:         cfg,
+		httpService: httpService,
+	}
+}
+
+// SetStore sets the store the embeddings are kept in. The posts can't be indexed or searched
+// until it is set, as the store is created after the search engines.
+func (s *SemanticEngine) SetStore(store EmbeddingStore) {
+	s.Mutex.Lock()
+	defer s.Mutex.Unlock()
+
+	s.store = store
+}
+
+func (s *SemanticEngine) makeProvider(settings model.SemanticSearchSettings) EmbeddingProvider {
+	switch *settings.EmbeddingProvider {
+	case model.EmbeddingProviderLocal:
+		return NewLocalProvider(*settings.EmbeddingDimensions)
+	case model.EmbeddingProviderHTTP:
+		client := s.httpService.MakeClient(true)
+		client.Timeout = time.Duration(*settings.RequestTimeoutMilliseconds) * time.Millisecond
+		return NewHTTPProvider(client, *settings.EmbeddingURL, *settings.EmbeddingAPIKey, *settings.EmbeddingModel)
+	default:
+		return nil
+	}
+}
+
+// setProvider replaces the embedding provider. The embeddings computed by the previous provider
+// can't be compared with the ones of the new provider, so they are ignored until the posts are
+// indexed again.
+func (s *SemanticEngine) setProvider(provider EmbeddingProvider) {
+	if s.provider != nil && provider != nil && s.provider.Name() != provider.Name() {
+		mlog.Warn("The embedding provider of semantic search changed. Run the semantic search indexing job to index the existing posts again.", mlog.String("provider", provider.Name()))
+	}
+	s.provider = provider
+}
+
+// getProviderAndStore returns the embedding provider and the store, or an error if either isn't
+// available.
+func (s *SemanticEngine) getProviderAndStore(where string) (EmbeddingProvider, EmbeddingStore, *model.AppError) {
+	s.Mutex.RLock()
+	defer s.Mutex.RUnlock()
+
+	if s.provider == nil {
+		return nil, nil, model.NewAppError(where, "semanticengine.no_embedding_provider.error", nil, "", http.StatusInternalServerError)
+	}
+	if s.store == nil {
+		return nil, nil, model.NewAppError(where, "semanticengine.no_store.error", nil, "", http.StatusInternalServerError)
+	}
+	return s.provider, s.store, nil
+}
+
+// getStore returns the store, or an error if it isn't available.
+func (s *SemanticEngine) getStore(where string) (EmbeddingStore, *model.AppError) {
+	s.Mutex.RLock()
+	defer s.Mutex.RUnlock()
+
+	if s.store == nil {
+		return nil, model.NewAppError(where, "semanticengine.no_store.error", nil, "", http.StatusInternalServerError)
+	}
+	return s.store, nil
+}
+
+func (s *SemanticEngine) open() *model.AppError {
+	if atomic.LoadInt32(&s.ready) != 0 {
+		return model.NewAppError("Semanticengine.Start", "semanticengine.already_started.error", nil, "", http.StatusInternalServerError)
+	}
+
+	s.setProvider(s.makeProvider(s.cfg.SemanticSearchSettings))
+
+	atomic.StoreInt32(&s.ready, 1)
+	return nil
+}
+
+func (s *SemanticEngine) Start() *model.AppError {
+	if !*s.cfg.SemanticSearchSettings.EnableIndexing {
+		return nil
+	}
+
+	s.Mutex.Lock()
+	defer s.Mutex.Unlock()
+
+	mlog.Info("EXPERIMENTAL: Starting semantic search", mlog.String("embedding_provider", *s.cfg.SemanticSearchSettings.EmbeddingProvider))
+
+	return s.open()
+}
+
+func (s *SemanticEngine) close() {
+	atomic.StoreInt32(&s.ready, 0)
+}
+
+func (s *SemanticEngine) Stop() *model.AppError {
+	s.Mutex.Lock()
+	defer s.Mutex.Unlock()
+
+	mlog.Info("Stopping semantic search")
+
+	s.close()
+	return nil
+}
+
+func (s *SemanticEngine) IsEnabled() bool {
+	return s.IsIndexingEnabled()
+}
+
+func (s *SemanticEngine) IsActive() bool {
+	return atomic.LoadInt32(&s.ready) == 1
+}
+
+func (s *SemanticEngine) IsIndexingSync() bool {
+	return s.indexSync
+}
+
+func (s *SemanticEngine) RefreshIndexes(_ request.CTX) *model.AppError {
+	return nil
+}
+
+func (s *SemanticEngine) GetVersion() int {
+	return 0
+}
+
+func (s *SemanticEngine) GetFullVersion() string {
+	return "0"
+}
+
+func (s *SemanticEngine) GetPlugins() []string {
+	return []string{}
+}
+
+func (s *SemanticEngine) GetName() string {
+	return EngineName
+}
+
+func (s *SemanticEngine) TestConfig(rctx request.CTX, cfg *model.Config) *model.AppError {
+	return nil
+}
+
+func (s *SemanticEngine) PurgeIndexes(rctx request.CTX) *model.AppError {
+	store, appErr := s.getStore("Semanticengine.PurgeIndexes")
+	if appErr != nil {
+		return appErr
+	}
+
+	rctx.Logger().Info("PurgeIndexes semantic search")
+	if err := store.DeleteAll(); err != nil {
+		return model.NewAppError("Semanticengine.PurgeIndexes", "semanticengine.purge_index.error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+	return nil
+}
+
+func (s *SemanticEngine) PurgeIndexList(rctx request.CTX, indexes []string) *model.AppError {
+	return model.NewAppError("Semanticengine.PurgeIndexList", "semanticengine.purge_list.not_implemented", nil, "not implemented", http.StatusNotFound)
+}
+
+func (s *SemanticEngine) DataRetentionDeleteIndexes(rctx request.CTX, cutoff time.Time) *model.AppError {
+	store, appErr := s.getStore("Semanticengine.DataRetentionDeleteIndexes")
+	if appErr != nil {
+		return appErr
+	}
+
+	deleted, err := store.DeleteBefore(model.GetMillisForTime(cutoff))
+	if err != nil {
+		return model.NewAppError("Semanticengine.DataRetentionDeleteIndexes", "semanticengine.delete_posts.error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+	rctx.Logger().Info("Posts deleted from the semantic search index", mlog.Int("deleted", deleted))
+	return nil
+}
+
+func (s *SemanticEngine) IsAutocompletionEnabled() bool {
+	return false
+}
+
+func (s *SemanticEngine) IsIndexingEnabled() bool {
+	return *s.cfg.SemanticSearchSettings.EnableIndexing
+}
+
+func (s *SemanticEngine) IsSearchEnabled() bool {
+	return *s.cfg.SemanticSearchSettings.EnableSearching
+}
+
+func (s *SemanticEngine) UpdateConfig(cfg *model.Config) {
+	s.Mutex.Lock()
+	defer s.Mutex.Unlock()
+
+	if reflect.DeepEqual(cfg.SemanticSearchSettings, s.cfg.SemanticSearchSettings) {
+		return
+	}
+
+	mlog.Info("UpdateConf semantic search")
+
+	if *cfg.SemanticSearchSettings.EnableIndexing != *s.cfg.SemanticSearchSettings.EnableIndexing {
+		s.close()
+		s.cfg = cfg
+		if *cfg.SemanticSearchSettings.EnableIndexing {
+			if err := s.open(); err != nil {
+				mlog.Error("Error starting semantic search after updating the config", mlog.Err(err))
+			}
+		}
+		return
+	}
+
+	s.cfg = cfg
+	s.setProvider(s.makeProvider(cfg.SemanticSearchSettings))
+}
+
+func (s *SemanticEngine) IsChannelsIndexVerified() bool {
+	return true
+}
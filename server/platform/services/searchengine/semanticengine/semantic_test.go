@@ -0,0 +1,450 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package semanticengine
+
+import (
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/shared/request"
+)
+
+// testEmbeddingStore keeps the embeddings in memory, along with the posts they belong to so that
+// it can return them like the SQL store joining the embeddings with the posts.
+type testEmbeddingStore struct {
+	mut        sync.Mutex
+	posts      map[string]*model.Post
+	embeddings map[string]*model.PostEmbedding
+	requests   int
+}
+
+func newTestEmbeddingStore() *testEmbeddingStore {
+	return &testEmbeddingStore{
+		posts:      make(map[string]*model.Post),
+		embeddings: make(map[string]*model.PostEmbedding),
+	}
+}
+
+func (s *testEmbeddingStore) addPosts(posts ...*model.Post) {
+	s.mut.Lock()
+	defer s.mut.Unlock()
+
+	for _, post := range posts {
+		s.posts[post.Id] = post
+	}
+}
+
+func (s *testEmbeddingStore) Save(embedding *model.PostEmbedding) error {
+	s.mut.Lock()
+	defer s.mut.Unlock()
+
+	if appErr := embedding.IsValid(); appErr != nil {
+		return appErr
+	}
+	s.embeddings[embedding.PostId] = embedding
+	return nil
+}
+
+func (s *testEmbeddingStore) GetPostsForChannels(provider string, opts model.PostEmbeddingSearchOptions) ([]*model.PostWithEmbedding, error) {
+	s.mut.Lock()
+	defer s.mut.Unlock()
+
+	channels := toSet(opts.ChannelIDs)
+	users := toSet(opts.UserIDs)
+	excludedUsers := toSet(opts.ExcludedUserIDs)
+	posts := []*model.PostWithEmbedding{}
+	for _, embedding := range s.embeddings {
+		post, ok := s.posts[embedding.PostId]
+		if !ok || post.DeleteAt != 0 || embedding.Provider != provider || !channels[embedding.ChannelId] {
+			continue
+		}
+		if (len(users) > 0 && !users[embedding.UserId]) || excludedUsers[embedding.UserId] {
+			continue
+		}
+		if (opts.CreatedAfter != 0 && embedding.CreateAt < opts.CreatedAfter) || (opts.CreatedBefore != 0 && embedding.CreateAt > opts.CreatedBefore) {
+			continue
+		}
+		if opts.BeforeID != "" && (embedding.CreateAt > opts.BeforeCreateAt || (embedding.CreateAt == opts.BeforeCreateAt && embedding.PostId >= opts.BeforeID)) {
+			continue
+		}
+		posts = append(posts, &model.PostWithEmbedding{Post: post, Embedding: embedding.Embedding})
+	}
+
+	sort.Slice(posts, func(i, j int) bool {
+		if posts[i].Post.CreateAt != posts[j].Post.CreateAt {
+			return posts[i].Post.CreateAt > posts[j].Post.CreateAt
+		}
+		return posts[i].Post.Id > posts[j].Post.Id
+	})
+	s.requests++
+	return posts[:min(len(posts), opts.Limit)], nil
+}
+
+func (s *testEmbeddingStore) Delete(postID string) error {
+	s.deleteWhere(func(embedding *model.PostEmbedding) bool { return embedding.PostId == postID })
+	return nil
+}
+
+func (s *testEmbeddingStore) deleteWhere(fn func(embedding *model.PostEmbedding) bool) int64 {
+	s.mut.Lock()
+	defer s.mut.Unlock()
+
+	var deleted int64
+	for postID, embedding := range s.embeddings {
+		if fn(embedding) {
+			delete(s.embeddings, postID)
+			deleted++
+		}
+	}
+	return deleted
+}
+
+func (s *testEmbeddingStore) DeleteForChannel(channelID string) (int64, error) {
+	return s.deleteWhere(func(embedding *model.PostEmbedding) bool { return embedding.ChannelId == channelID }), nil
+}
+
+func (s *testEmbeddingStore) DeleteForUser(userID string) (int64, error) {
+	return s.deleteWhere(func(embedding *model.PostEmbedding) bool { return embedding.UserId == userID }), nil
+}
+
+func (s *testEmbeddingStore) DeleteBefore(endTime int64) (int64, error) {
+	return s.deleteWhere(func(embedding *model.PostEmbedding) bool { return embedding.CreateAt < endTime }), nil
+}
+
+func (s *testEmbeddingStore) DeleteAll() error {
+	s.deleteWhere(func(embedding *model.PostEmbedding) bool { return true })
+	return nil
+}
+
+func makeTestConfig() *model.Config {
+	cfg := &model.Config{}
+	cfg.SetDefaults()
+	cfg.SemanticSearchSettings.EnableIndexing = model.NewPointer(true)
+	cfg.SemanticSearchSettings.EnableSearching = model.NewPointer(true)
+	return cfg
+}
+
+func startTestEngine(t *testing.T, cfg *model.Config, store *testEmbeddingStore) *SemanticEngine {
+	t.Helper()
+
+	engine := NewSemanticEngine(cfg, nil)
+	engine.indexSync = true
+	engine.SetStore(store)
+	require.Nil(t, engine.Start())
+	t.Cleanup(func() {
+		engine.Stop()
+	})
+	return engine
+}
+
+func createPost(userId, channelId, message string, createAt int64) *model.Post {
+	post := &model.Post{
+		Message:   message,
+		ChannelId: channelId,
+		UserId:    userId,
+		CreateAt:  createAt,
+	}
+	post.PreSave()
+	return post
+}
+
+func indexPosts(t *testing.T, engine *SemanticEngine, posts ...*model.Post) {
+	t.Helper()
+
+	engine.store.(*testEmbeddingStore).addPosts(posts...)
+	for _, post := range posts {
+		require.Nil(t, engine.IndexPost(post, "team"))
+	}
+}
+
+func search(t *testing.T, engine *SemanticEngine, channels model.ChannelList, terms string) []string {
+	t.Helper()
+
+	paramsList := model.ParseSearchParams(terms, 0)
+	require.NotEmpty(t, paramsList)
+	ids, _, appErr := engine.SearchPosts(channels, paramsList, 0, 20)
+	require.Nil(t, appErr)
+	return ids
+}
+
+func TestSemanticEngineLifecycle(t *testing.T) {
+	t.Run("not started when indexing is disabled", func(t *testing.T) {
+		cfg := makeTestConfig()
+		cfg.SemanticSearchSettings.EnableIndexing = model.NewPointer(false)
+		cfg.SemanticSearchSettings.EnableSearching = model.NewPointer(false)
+
+		engine := NewSemanticEngine(cfg, nil)
+		require.Nil(t, engine.Start())
+		assert.False(t, engine.IsActive())
+		assert.False(t, engine.IsEnabled())
+	})
+
+	t.Run("posts can't be indexed without a store", func(t *testing.T) {
+		engine := NewSemanticEngine(makeTestConfig(), nil)
+		require.Nil(t, engine.Start())
+		defer engine.Stop()
+
+		appErr := engine.IndexPost(createPost(model.NewId(), model.NewId(), "the deployment failed", 1000), "team")
+		require.NotNil(t, appErr)
+		assert.Equal(t, "semanticengine.no_store.error", appErr.Id)
+	})
+
+	t.Run("the index is kept in the store", func(t *testing.T) {
+		cfg := makeTestConfig()
+		store := newTestEmbeddingStore()
+		channel := &model.Channel{Id: model.NewId()}
+		post := createPost(model.NewId(), channel.Id, "the deployment failed", 1000)
+
+		engine := startTestEngine(t, cfg, store)
+		indexPosts(t, engine, post)
+		require.Nil(t, engine.Stop())
+		assert.False(t, engine.IsActive())
+
+		// Another engine, such as the one of another node of the cluster, finds the post.
+		engine = startTestEngine(t, cfg, store)
+		assert.Equal(t, []string{post.Id}, search(t, engine, model.ChannelList{channel}, "deployment"))
+	})
+
+	t.Run("the embeddings of another provider are ignored", func(t *testing.T) {
+		cfg := makeTestConfig()
+		store := newTestEmbeddingStore()
+		channel := &model.Channel{Id: model.NewId()}
+
+		engine := startTestEngine(t, cfg, store)
+		indexPosts(t, engine, createPost(model.NewId(), channel.Id, "the deployment failed", 1000))
+		require.Nil(t, engine.Stop())
+
+		otherCfg := cfg.Clone()
+		otherCfg.SemanticSearchSettings.EmbeddingDimensions = model.NewPointer(128)
+		engine = startTestEngine(t, otherCfg, store)
+		assert.Empty(t, search(t, engine, model.ChannelList{channel}, "deployment"))
+
+		engine.UpdateConfig(cfg)
+		assert.True(t, engine.IsActive())
+		assert.Len(t, search(t, engine, model.ChannelList{channel}, "deployment"), 1)
+	})
+
+	t.Run("disabling indexing stops the engine", func(t *testing.T) {
+		cfg := makeTestConfig()
+		engine := startTestEngine(t, cfg, newTestEmbeddingStore())
+
+		newCfg := cfg.Clone()
+		newCfg.SemanticSearchSettings.EnableIndexing = model.NewPointer(false)
+		newCfg.SemanticSearchSettings.EnableSearching = model.NewPointer(false)
+		engine.UpdateConfig(newCfg)
+		assert.False(t, engine.IsActive())
+
+		engine.UpdateConfig(cfg)
+		assert.True(t, engine.IsActive())
+	})
+
+	t.Run("purge indexes", func(t *testing.T) {
+		channel := &model.Channel{Id: model.NewId()}
+
+		engine := startTestEngine(t, makeTestConfig(), newTestEmbeddingStore())
+		indexPosts(t, engine, createPost(model.NewId(), channel.Id, "the deployment failed", 1000))
+
+		require.Nil(t, engine.PurgeIndexes(request.TestContext(t)))
+		assert.Empty(t, search(t, engine, model.ChannelList{channel}, "deployment"))
+	})
+}
+
+func TestSemanticEngineIndexPosts(t *testing.T) {
+	engine := startTestEngine(t, makeTestConfig(), newTestEmbeddingStore())
+	channel := &model.Channel{Id: model.NewId()}
+
+	post := createPost(model.NewId(), channel.Id, "the deployment failed", 1000)
+	deleted := createPost(model.NewId(), channel.Id, "the deployment was deleted", 2000)
+	deleted.DeleteAt = model.GetMillis()
+	system := createPost(model.NewId(), channel.Id, "deployment joined the channel", 3000)
+	system.Type = model.PostTypeJoinChannel
+	engine.store.(*testEmbeddingStore).addPosts(post, deleted, system)
+
+	require.Nil(t, engine.IndexPosts([]*model.Post{post, deleted, system}))
+	assert.Equal(t, []string{post.Id}, search(t, engine, model.ChannelList{channel}, "deployment"))
+	assert.Len(t, engine.store.(*testEmbeddingStore).embeddings, 1)
+}
+
+func TestSemanticEngineSearchPosts(t *testing.T) {
+	engine := startTestEngine(t, makeTestConfig(), newTestEmbeddingStore())
+
+	userA := model.NewId()
+	userB := model.NewId()
+	channelA := &model.Channel{Id: model.NewId()}
+	channelB := &model.Channel{Id: model.NewId()}
+	channelC := &model.Channel{Id: model.NewId()}
+	channels := model.ChannelList{channelA, channelB}
+
+	deployFailed := createPost(userA, channelA.Id, "The deployment of the release failed", 1000)
+	deployRetry := createPost(userB, channelB.Id, "Retrying the deploy now", 2000)
+	lunch := createPost(userA, channelA.Id, "Who wants pizza for lunch?", 3000)
+	hashtag := createPost(userB, channelA.Id, "Deployed the hotfix #release", 4000)
+	hashtag.Hashtags = "#release"
+	private := createPost(userA, channelC.Id, "The deployment of the release failed again", 5000)
+	system := createPost(userA, channelA.Id, "deployment joined the channel", 6000)
+	system.Type = model.PostTypeJoinChannel
+	indexPosts(t, engine, deployFailed, deployRetry, lunch, hashtag, private, system)
+
+	t.Run("ranks posts by meaning", func(t *testing.T) {
+		ids := search(t, engine, channels, "deployment semantic:true")
+		assert.Equal(t, []string{deployFailed.Id, deployRetry.Id}, ids)
+	})
+
+	t.Run("only searches the channels of the user", func(t *testing.T) {
+		ids := search(t, engine, channels, "deployment release failed")
+		assert.NotContains(t, ids, private.Id)
+
+		ids = search(t, engine, model.ChannelList{channelC}, "deployment release failed")
+		assert.Equal(t, []string{private.Id}, ids)
+	})
+
+	t.Run("ignores system posts", func(t *testing.T) {
+		ids := search(t, engine, channels, "deployment joined the channel")
+		assert.NotContains(t, ids, system.Id)
+	})
+
+	t.Run("returns the matching words", func(t *testing.T) {
+		paramsList := model.ParseSearchParams("deploy*", 0)
+		ids, matches, appErr := engine.SearchPosts(channels, paramsList, 0, 20)
+		require.Nil(t, appErr)
+		require.Contains(t, ids, deployFailed.Id)
+		assert.Equal(t, []string{"deployment"}, matches[deployFailed.Id])
+		assert.Equal(t, []string{"deploy"}, matches[deployRetry.Id])
+	})
+
+	t.Run("filters", func(t *testing.T) {
+		params := &model.SearchParams{Terms: "deployment", FromUsers: []string{userB}}
+		ids, _, appErr := engine.SearchPosts(channels, []*model.SearchParams{params}, 0, 20)
+		require.Nil(t, appErr)
+		assert.NotContains(t, ids, deployFailed.Id)
+		assert.Contains(t, ids, deployRetry.Id)
+
+		params = &model.SearchParams{Terms: "deployment", ExcludedChannels: []string{channelA.Id}}
+		ids, _, appErr = engine.SearchPosts(channels, []*model.SearchParams{params}, 0, 20)
+		require.Nil(t, appErr)
+		assert.Equal(t, []string{deployRetry.Id}, ids)
+
+		params = &model.SearchParams{Terms: "deployment", ExcludedTerms: "failed"}
+		ids, _, appErr = engine.SearchPosts(channels, []*model.SearchParams{params}, 0, 20)
+		require.Nil(t, appErr)
+		assert.NotContains(t, ids, deployFailed.Id)
+		assert.NotEmpty(t, ids)
+	})
+
+	t.Run("hashtags", func(t *testing.T) {
+		ids := search(t, engine, channels, "#release")
+		assert.Equal(t, []string{hashtag.Id}, ids)
+
+		ids = search(t, engine, channels, "#unknown")
+		assert.Empty(t, ids)
+	})
+
+	t.Run("filters without terms are sorted by date", func(t *testing.T) {
+		params := &model.SearchParams{InChannels: []string{channelA.Id}}
+		ids, _, appErr := engine.SearchPosts(channels, []*model.SearchParams{params}, 0, 20)
+		require.Nil(t, appErr)
+		assert.Equal(t, []string{hashtag.Id, lunch.Id, deployFailed.Id}, ids)
+	})
+
+	t.Run("paging", func(t *testing.T) {
+		params := &model.SearchParams{InChannels: []string{channelA.Id}}
+		ids, _, appErr := engine.SearchPosts(channels, []*model.SearchParams{params}, 1, 2)
+		require.Nil(t, appErr)
+		assert.Equal(t, []string{deployFailed.Id}, ids)
+
+		ids, _, appErr = engine.SearchPosts(channels, []*model.SearchParams{params}, 2, 2)
+		require.Nil(t, appErr)
+		assert.Empty(t, ids)
+	})
+
+	t.Run("only the most recent candidates are scored", func(t *testing.T) {
+		cfg := makeTestConfig()
+		cfg.SemanticSearchSettings.MaxCandidates = model.NewPointer(2)
+		store := engine.store.(*testEmbeddingStore)
+		capped := startTestEngine(t, cfg, store)
+
+		params := &model.SearchParams{InChannels: []string{channelA.Id}}
+		ids, _, appErr := capped.SearchPosts(channels, []*model.SearchParams{params}, 0, 20)
+		require.Nil(t, appErr)
+		assert.Equal(t, []string{hashtag.Id, lunch.Id}, ids)
+
+		// Without search terms, the posts following the requested page aren't read.
+		requests := store.requests
+		ids, _, appErr = engine.SearchPosts(channels, []*model.SearchParams{params}, 0, 1)
+		require.Nil(t, appErr)
+		assert.Equal(t, []string{hashtag.Id}, ids)
+		assert.Equal(t, requests+1, store.requests)
+	})
+}
+
+func TestSemanticEngineHybridRanking(t *testing.T) {
+	cfg := makeTestConfig()
+	engine := startTestEngine(t, cfg, newTestEmbeddingStore())
+
+	channel := &model.Channel{Id: model.NewId()}
+	exact := createPost(model.NewId(), channel.Id, "kubernetes", 1000)
+	similar := createPost(model.NewId(), channel.Id, "kubernetes cluster upgrade kubernetes nodes", 2000)
+	indexPosts(t, engine, exact, similar)
+
+	t.Run("only lexical", func(t *testing.T) {
+		newCfg := cfg.Clone()
+		newCfg.SemanticSearchSettings.LexicalWeightPercent = model.NewPointer(100)
+		newCfg.SemanticSearchSettings.MinimumScorePercent = model.NewPointer(100)
+		engine.UpdateConfig(newCfg)
+
+		ids := search(t, engine, model.ChannelList{channel}, "kubernetes upgrade")
+		assert.Equal(t, []string{similar.Id}, ids)
+	})
+
+	t.Run("only semantic", func(t *testing.T) {
+		newCfg := cfg.Clone()
+		newCfg.SemanticSearchSettings.LexicalWeightPercent = model.NewPointer(0)
+		newCfg.SemanticSearchSettings.MinimumScorePercent = model.NewPointer(0)
+		engine.UpdateConfig(newCfg)
+
+		ids := search(t, engine, model.ChannelList{channel}, "kubernetes")
+		assert.Equal(t, []string{exact.Id, similar.Id}, ids)
+	})
+}
+
+func TestSemanticEngineDelete(t *testing.T) {
+	engine := startTestEngine(t, makeTestConfig(), newTestEmbeddingStore())
+	rctx := request.TestContext(t)
+
+	userA := model.NewId()
+	userB := model.NewId()
+	channelA := &model.Channel{Id: model.NewId()}
+	channelB := &model.Channel{Id: model.NewId()}
+	channels := model.ChannelList{channelA, channelB}
+
+	post1 := createPost(userA, channelA.Id, "deployment one", 1000)
+	post2 := createPost(userB, channelA.Id, "deployment two", 2000)
+	post3 := createPost(userA, channelB.Id, "deployment three", 3000)
+	post4 := createPost(userB, channelB.Id, "deployment four", model.GetMillis())
+	indexPosts(t, engine, post1, post2, post3, post4)
+	require.Len(t, search(t, engine, channels, "deployment"), 4)
+
+	require.Nil(t, engine.DeletePost(post1))
+	assert.ElementsMatch(t, []string{post2.Id, post3.Id, post4.Id}, search(t, engine, channels, "deployment"))
+
+	post2.DeleteAt = model.GetMillis()
+	require.Nil(t, engine.IndexPost(post2, "team"))
+	assert.ElementsMatch(t, []string{post3.Id, post4.Id}, search(t, engine, channels, "deployment"))
+
+	require.Nil(t, engine.DataRetentionDeleteIndexes(rctx, time.UnixMilli(10000)))
+	assert.ElementsMatch(t, []string{post4.Id}, search(t, engine, channels, "deployment"))
+
+	indexPosts(t, engine, post1, post3)
+	require.Nil(t, engine.DeleteUserPosts(rctx, userA))
+	assert.ElementsMatch(t, []string{post4.Id}, search(t, engine, channels, "deployment"))
+
+	require.Nil(t, engine.DeleteChannelPosts(rctx, channelB.Id))
+	assert.Empty(t, search(t, engine, channels, "deployment"))
+}
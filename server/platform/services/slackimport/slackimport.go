@@ -91,8 +91,8 @@ type Actions struct {
 	CreateGroupChannel     func(request.CTX, []string) (*model.Channel, *model.AppError)
 	CreateChannel          func(*model.Channel, bool) (*model.Channel, *model.AppError)
 	DoUploadFile           func(time.Time, string, string, string, string, []byte) (*model.FileInfo, *model.AppError)
-	GenerateThumbnailImage func(request.CTX, image.Image, string, string)
-	GeneratePreviewImage   func(request.CTX, image.Image, string, string)
+	GenerateThumbnailImage func(request.CTX, image.Image, string, string) error
+	GeneratePreviewImage   func(request.CTX, image.Image, string, string) error
 	InvalidateAllCaches    func()
 	MaxPostSize            func() int
 	PrepareImage           func(fileData []byte) (image.Image, string, func(), error)
@@ -792,7 +792,7 @@ func (si *SlackImporter) oldImportFile(rctx request.CTX, timestamp time.Time, fi
 		return nil, err
 	}
 
-	if fileInfo.IsImage() && !fileInfo.IsSvg() {
+	if fileInfo.IsImage() && !fileInfo.IsSvg() && fileInfo.ThumbnailPath != "" {
 		img, imgType, release, err := si.actions.PrepareImage(data)
 		if err != nil {
 			return nil, err
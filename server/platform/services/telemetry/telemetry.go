@@ -75,8 +75,12 @@ const (
 	TrackConfigGuestAccounts     = "config_guest_accounts"
 	TrackConfigImageProxy        = "config_image_proxy"
 	TrackConfigBleve             = "config_bleve"
+	TrackConfigSemanticSearch    = "config_semantic_search"
 	TrackConfigExport            = "config_export"
 	TrackConfigWrangler          = "config_wrangler"
+	TrackConfigScim              = "config_scim"
+	TrackConfigTranslation       = "config_translation"
+	TrackConfigVirusScan         = "config_virus_scan"
 	TrackFeatureFlags            = "config_feature_flags"
 	TrackPermissionsGeneral      = "permissions_general"
 	TrackPermissionsSystemScheme = "permissions_system_scheme"
@@ -491,6 +495,11 @@ func (ts *TelemetryService) trackConfig() {
 		"persistent_notification_max_count":                       *cfg.ServiceSettings.PersistentNotificationMaxCount,
 		"persistent_notification_max_recipients":                  *cfg.ServiceSettings.PersistentNotificationMaxRecipients,
 		"allow_synced_drafts":                                     *cfg.ServiceSettings.AllowSyncedDrafts,
+		"scheduled_posts":                                         *cfg.ServiceSettings.ScheduledPosts,
+		"enable_saved_searches":                                   *cfg.ServiceSettings.EnableSavedSearches,
+		"enable_post_expiry":                                      *cfg.ServiceSettings.EnablePostExpiry,
+		"enable_read_receipts":                                    *cfg.ServiceSettings.EnableReadReceipts,
+		"read_receipts_max_channel_members":                       *cfg.ServiceSettings.ReadReceiptsMaxChannelMembers,
 		"refresh_post_stats_run_time":                             *cfg.ServiceSettings.RefreshPostStatsRunTime,
 		"maximum_payload_size":                                    *cfg.ServiceSettings.MaximumPayloadSizeBytes,
 		"maximum_url_length":                                      *cfg.ServiceSettings.MaximumURLLength,
@@ -562,6 +571,8 @@ func (ts *TelemetryService) trackConfig() {
 		"file_compress":         *cfg.ExperimentalAuditSettings.FileCompress,
 		"file_max_queue_size":   *cfg.ExperimentalAuditSettings.FileMaxQueueSize,
 		"advanced_logging_json": len(cfg.ExperimentalAuditSettings.AdvancedLoggingJSON) != 0,
+		"database_enabled":      *cfg.ExperimentalAuditSettings.DatabaseEnabled,
+		"database_max_age_days": *cfg.ExperimentalAuditSettings.DatabaseMaxAgeDays,
 	})
 
 	ts.SendTelemetry(TrackConfigNotificationLog, map[string]any{
@@ -590,16 +601,33 @@ func (ts *TelemetryService) trackConfig() {
 		"isabsolute_directory":          filepath.IsAbs(*cfg.FileSettings.Directory),
 		"extract_content":               *cfg.FileSettings.ExtractContent,
 		"archive_recursion":             *cfg.FileSettings.ArchiveRecursion,
+		"enable_ocr":                    *cfg.FileSettings.EnableOCR,
+		"ocr_languages":                 *cfg.FileSettings.OCRLanguages,
+		"ocr_timeout_seconds":           *cfg.FileSettings.OCRTimeoutSeconds,
+		"enable_image_converter":        *cfg.FileSettings.EnableImageConverter,
+		"image_converter_timeout":       *cfg.FileSettings.ImageConverterTimeoutSeconds,
+		"enable_webp_previews":          *cfg.FileSettings.EnableWebPPreviews,
+		"webp_preview_quality":          *cfg.FileSettings.WebPPreviewQuality,
+		"enable_ffmpeg":                 *cfg.FileSettings.EnableFFmpeg,
+		"ffmpeg_timeout_seconds":        *cfg.FileSettings.FFmpegTimeoutSeconds,
+		"enable_media_transcoding":      *cfg.FileSettings.EnableMediaTranscoding,
+		"media_transcoding_timeout":     *cfg.FileSettings.MediaTranscodingTimeoutSeconds,
+		"enable_deduplication":          *cfg.FileSettings.EnableDeduplication,
 		"amazon_s3_ssl":                 *cfg.FileSettings.AmazonS3SSL,
 		"amazon_s3_sse":                 *cfg.FileSettings.AmazonS3SSE,
 		"amazon_s3_signv2":              *cfg.FileSettings.AmazonS3SignV2,
 		"amazon_s3_trace":               *cfg.FileSettings.AmazonS3Trace,
+		"enable_file_cache":             *cfg.FileSettings.EnableFileCache,
+		"file_cache_max_size_mb":        *cfg.FileSettings.FileCacheMaxSizeMB,
 		"max_file_size":                 *cfg.FileSettings.MaxFileSize,
 		"max_image_resolution":          *cfg.FileSettings.MaxImageResolution,
 		"max_image_decoder_concurrency": *cfg.FileSettings.MaxImageDecoderConcurrency,
 		"enable_file_attachments":       *cfg.FileSettings.EnableFileAttachments,
 		"enable_mobile_upload":          *cfg.FileSettings.EnableMobileUpload,
 		"enable_mobile_download":        *cfg.FileSettings.EnableMobileDownload,
+		"encryption_enabled":            *cfg.FileSettings.EncryptionEnabled,
+		"encryption_key_provider":       *cfg.FileSettings.EncryptionKeyProvider,
+		"encryption_migration_mode":     *cfg.FileSettings.EncryptionMigrationMode,
 	})
 
 	ts.SendTelemetry(TrackConfigEmail, map[string]any{
@@ -637,6 +665,8 @@ func (ts *TelemetryService) trackConfig() {
 		"max_burst":                *cfg.RateLimitSettings.MaxBurst,
 		"memory_store_size":        *cfg.RateLimitSettings.MemoryStoreSize,
 		"isdefault_vary_by_header": isDefault(cfg.RateLimitSettings.VaryByHeader, ""),
+		"route_quotas":             len(cfg.RateLimitSettings.RouteQuotas),
+		"token_quotas":             len(cfg.RateLimitSettings.TokenQuotas),
 	})
 
 	ts.SendTelemetry(TrackConfigPrivacy, map[string]any{
@@ -753,6 +783,7 @@ func (ts *TelemetryService) trackConfig() {
 		"enable_experimental_gossip_encryption": *cfg.ClusterSettings.EnableExperimentalGossipEncryption,
 		"enable_gossip_compression":             *cfg.ClusterSettings.EnableGossipCompression,
 		"read_only_config":                      *cfg.ClusterSettings.ReadOnlyConfig,
+		"message_transport":                     *cfg.ClusterSettings.MessageTransport,
 	})
 
 	ts.SendTelemetry(TrackConfigMetrics, map[string]any{
@@ -868,6 +899,9 @@ func (ts *TelemetryService) trackConfig() {
 		"image_proxy_type":                     *cfg.ImageProxySettings.ImageProxyType,
 		"isdefault_remote_image_proxy_url":     isDefault(*cfg.ImageProxySettings.RemoteImageProxyURL, ""),
 		"isdefault_remote_image_proxy_options": isDefault(*cfg.ImageProxySettings.RemoteImageProxyOptions, ""),
+		"enable_resizing":                      *cfg.ImageProxySettings.EnableResizing,
+		"resized_image_cache_max_size_mb":      *cfg.ImageProxySettings.ResizedImageCacheMaxSizeMB,
+		"enable_webp":                          *cfg.ImageProxySettings.EnableWebP,
 	})
 
 	ts.SendTelemetry(TrackConfigBleve, map[string]any{
@@ -877,10 +911,38 @@ func (ts *TelemetryService) trackConfig() {
 		"bulk_indexing_batch_size": *cfg.BleveSettings.BatchSize,
 	})
 
+	ts.SendTelemetry(TrackConfigSemanticSearch, map[string]any{
+		"enable_indexing":              *cfg.SemanticSearchSettings.EnableIndexing,
+		"enable_searching":             *cfg.SemanticSearchSettings.EnableSearching,
+		"embedding_provider":           *cfg.SemanticSearchSettings.EmbeddingProvider,
+		"embedding_dimensions":         *cfg.SemanticSearchSettings.EmbeddingDimensions,
+		"request_timeout_milliseconds": *cfg.SemanticSearchSettings.RequestTimeoutMilliseconds,
+		"lexical_weight_percent":       *cfg.SemanticSearchSettings.LexicalWeightPercent,
+		"minimum_score_percent":        *cfg.SemanticSearchSettings.MinimumScorePercent,
+		"max_candidates":               *cfg.SemanticSearchSettings.MaxCandidates,
+	})
+
 	ts.SendTelemetry(TrackConfigExport, map[string]any{
 		"retention_days": *cfg.ExportSettings.RetentionDays,
 	})
 
+	ts.SendTelemetry(TrackConfigScim, map[string]any{
+		"enable":       *cfg.ScimSettings.Enable,
+		"auth_service": *cfg.ScimSettings.AuthService,
+	})
+
+	ts.SendTelemetry(TrackConfigTranslation, map[string]any{
+		"enable":                       *cfg.TranslationSettings.Enable,
+		"provider":                     *cfg.TranslationSettings.Provider,
+		"request_timeout_milliseconds": *cfg.TranslationSettings.RequestTimeoutMilliseconds,
+	})
+
+	ts.SendTelemetry(TrackConfigVirusScan, map[string]any{
+		"enable":                    *cfg.VirusScanSettings.Enable,
+		"driver":                    *cfg.VirusScanSettings.Driver,
+		"scan_timeout_milliseconds": *cfg.VirusScanSettings.ScanTimeoutMilliseconds,
+	})
+
 	ts.SendTelemetry(TrackConfigWrangler, map[string]any{
 		"permitted_wrangler_users":                       cfg.WranglerSettings.PermittedWranglerRoles,
 		"allowed_email_domain":                           cfg.WranglerSettings.AllowedEmailDomain,
@@ -0,0 +1,96 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package translation
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/pkg/errors"
+
+	"github.com/mattermost/mattermost/server/public/model"
+)
+
+// maxHTTPResponseSize limits how much of a response from the translation service is read.
+const maxHTTPResponseSize = 1024 * 1024
+
+// HTTPProvider translates text using a service implementing the LibreTranslate API.
+type HTTPProvider struct {
+	client *http.Client
+	url    string
+	apiKey string
+}
+
+type httpTranslateRequest struct {
+	Q      string `json:"q"`
+	Source string `json:"source"`
+	Target string `json:"target"`
+	Format string `json:"format"`
+	APIKey string `json:"api_key,omitempty"`
+}
+
+type httpTranslateResponse struct {
+	TranslatedText   string `json:"translatedText"`
+	DetectedLanguage *struct {
+		Language string `json:"language"`
+	} `json:"detectedLanguage"`
+	Error string `json:"error"`
+}
+
+func NewHTTPProvider(client *http.Client, url, apiKey string) *HTTPProvider {
+	return &HTTPProvider{
+		client: client,
+		url:    url,
+		apiKey: apiKey,
+	}
+}
+
+func (p *HTTPProvider) Translate(ctx context.Context, text, targetLanguage string) (*model.Translation, error) {
+	body, err := json.Marshal(httpTranslateRequest{
+		Q:      text,
+		Source: "auto",
+		Target: targetLanguage,
+		Format: "text",
+		APIKey: p.apiKey,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to encode translation request")
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.url, bytes.NewReader(body))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create translation request")
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to request translation")
+	}
+	defer resp.Body.Close()
+
+	var result httpTranslateResponse
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxHTTPResponseSize)).Decode(&result); err != nil {
+		return nil, errors.Wrapf(err, "failed to decode translation response with status %d", resp.StatusCode)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("translation service returned status %d: %s", resp.StatusCode, result.Error)
+	}
+
+	translation := &model.Translation{
+		Language: targetLanguage,
+		Text:     result.TranslatedText,
+	}
+	if result.DetectedLanguage != nil {
+		translation.SourceLanguage = result.DetectedLanguage.Language
+	}
+
+	return translation, nil
+}
@@ -0,0 +1,71 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package translation
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost/server/public/model"
+)
+
+func TestHTTPProvider_Translate(t *testing.T) {
+	t.Run("should translate text", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, http.MethodPost, r.Method)
+
+			var req httpTranslateRequest
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+			assert.Equal(t, httpTranslateRequest{
+				Q:      "Bonjour",
+				Source: "auto",
+				Target: "en",
+				Format: "text",
+				APIKey: "secret",
+			}, req)
+
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"translatedText": "Hello", "detectedLanguage": {"confidence": 90, "language": "fr"}}`))
+		}))
+		defer server.Close()
+
+		provider := NewHTTPProvider(server.Client(), server.URL, "secret")
+
+		translation, err := provider.Translate(context.Background(), "Bonjour", "en")
+		require.NoError(t, err)
+		assert.Equal(t, &model.Translation{Language: "en", SourceLanguage: "fr", Text: "Hello"}, translation)
+	})
+
+	t.Run("should return the error of the service", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"error": "xx is not supported"}`))
+		}))
+		defer server.Close()
+
+		provider := NewHTTPProvider(server.Client(), server.URL, "")
+
+		_, err := provider.Translate(context.Background(), "Bonjour", "xx")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "xx is not supported")
+	})
+
+	t.Run("should fail on an invalid response", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(`<html></html>`))
+		}))
+		defer server.Close()
+
+		provider := NewHTTPProvider(server.Client(), server.URL, "")
+
+		_, err := provider.Translate(context.Background(), "Bonjour", "en")
+		require.Error(t, err)
+	})
+}
@@ -0,0 +1,145 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package translation
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/shared/configservice"
+	"github.com/mattermost/mattermost/server/public/shared/httpservice"
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+)
+
+var (
+	ErrNotEnabled       = errors.New("translation.Translator: machine translation not enabled")
+	ErrNoPluginProvider = errors.New("translation.Translator: no plugin is registered as a translation provider")
+	ErrNoTranslation    = errors.New("translation.Translator: the provider did not return a translation")
+)
+
+// A Provider translates text to another language. Providers must be safe for concurrent use.
+type Provider interface {
+	// Translate returns the translation of text to targetLanguage. The source language is
+	// detected by the provider.
+	Translate(ctx context.Context, text, targetLanguage string) (*model.Translation, error)
+}
+
+// Translator is the public interface for the machine translation of posts. An instance of Translator
+// should be created using MakeTranslator, and delegates to the provider selected in the
+// TranslationSettings provided by the ConfigService.
+type Translator struct {
+	ConfigService    configservice.ConfigService
+	configListenerID string
+
+	HTTPService httpservice.HTTPService
+
+	Logger *mlog.Logger
+
+	lock           sync.RWMutex
+	settings       model.TranslationSettings
+	provider       Provider
+	pluginProvider Provider
+}
+
+func MakeTranslator(configService configservice.ConfigService, httpService httpservice.HTTPService, logger *mlog.Logger) *Translator {
+	translator := &Translator{
+		ConfigService: configService,
+		HTTPService:   httpService,
+		Logger:        logger,
+	}
+
+	translator.configListenerID = translator.ConfigService.AddConfigListener(translator.OnConfigChange)
+
+	translator.settings = translator.ConfigService.Config().TranslationSettings
+	translator.provider = translator.makeProvider(translator.settings)
+
+	return translator
+}
+
+func (t *Translator) makeProvider(settings model.TranslationSettings) Provider {
+	if !*settings.Enable {
+		return nil
+	}
+
+	switch *settings.Provider {
+	case model.TranslationProviderLocal:
+		return NewLocalProvider()
+	case model.TranslationProviderHTTP:
+		client := t.HTTPService.MakeClient(true)
+		client.Timeout = time.Duration(*settings.RequestTimeoutMilliseconds) * time.Millisecond
+		return NewHTTPProvider(client, *settings.ProviderURL, *settings.APIKey)
+	case model.TranslationProviderPlugin:
+		return t.pluginProvider
+	default:
+		return nil
+	}
+}
+
+func (t *Translator) Close() {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	t.ConfigService.RemoveConfigListener(t.configListenerID)
+}
+
+func (t *Translator) OnConfigChange(oldConfig, newConfig *model.Config) {
+	if !reflect.DeepEqual(oldConfig.TranslationSettings, newConfig.TranslationSettings) {
+		t.lock.Lock()
+		defer t.lock.Unlock()
+
+		t.settings = newConfig.TranslationSettings
+		t.provider = t.makeProvider(t.settings)
+	}
+}
+
+// SetPluginProvider sets the provider used when TranslationSettings select plugins as the
+// translation provider.
+func (t *Translator) SetPluginProvider(provider Provider) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	t.pluginProvider = provider
+	t.provider = t.makeProvider(t.settings)
+}
+
+// IsEnabled reports whether posts can be machine translated.
+func (t *Translator) IsEnabled() bool {
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+
+	return *t.settings.Enable
+}
+
+// Translate returns the translation of text to targetLanguage using the configured provider.
+func (t *Translator) Translate(ctx context.Context, text, targetLanguage string) (*model.Translation, error) {
+	t.lock.RLock()
+	enabled := *t.settings.Enable
+	provider := t.provider
+	t.lock.RUnlock()
+
+	if !enabled {
+		return nil, ErrNotEnabled
+	}
+
+	if provider == nil {
+		return nil, ErrNoPluginProvider
+	}
+
+	translation, err := provider.Translate(ctx, text, targetLanguage)
+	if err != nil {
+		return nil, err
+	}
+
+	if translation == nil {
+		return nil, ErrNoTranslation
+	}
+
+	translation.Language = targetLanguage
+
+	return translation, nil
+}
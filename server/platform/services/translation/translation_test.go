@@ -0,0 +1,103 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package translation
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/shared/httpservice"
+	"github.com/mattermost/mattermost/server/v8/channels/utils/testutils"
+)
+
+type testPluginProvider struct {
+	translation *model.Translation
+}
+
+func (p *testPluginProvider) Translate(ctx context.Context, text, targetLanguage string) (*model.Translation, error) {
+	return p.translation, nil
+}
+
+func makeTestTranslator(provider string) *Translator {
+	cfg := &model.Config{}
+	cfg.SetDefaults()
+	cfg.TranslationSettings.Enable = model.NewPointer(true)
+	cfg.TranslationSettings.Provider = model.NewPointer(provider)
+	cfg.TranslationSettings.ProviderURL = model.NewPointer("http://translate.example.com/translate")
+
+	configService := &testutils.StaticConfigService{Cfg: cfg}
+
+	return MakeTranslator(configService, httpservice.MakeHTTPService(configService), nil)
+}
+
+func TestTranslate(t *testing.T) {
+	t.Run("should translate with the local provider", func(t *testing.T) {
+		translator := makeTestTranslator(model.TranslationProviderLocal)
+
+		translation, err := translator.Translate(context.Background(), "Hello", "de")
+		require.NoError(t, err)
+		assert.Equal(t, &model.Translation{Language: "de", Text: "[de] Hello"}, translation)
+	})
+
+	t.Run("should fail when translation is disabled", func(t *testing.T) {
+		translator := makeTestTranslator(model.TranslationProviderLocal)
+
+		newConfig := translator.ConfigService.Config().Clone()
+		newConfig.TranslationSettings.Enable = model.NewPointer(false)
+		translator.ConfigService.(*testutils.StaticConfigService).UpdateConfig(newConfig)
+
+		assert.False(t, translator.IsEnabled())
+		_, err := translator.Translate(context.Background(), "Hello", "de")
+		require.ErrorIs(t, err, ErrNotEnabled)
+	})
+
+	t.Run("should fail when no plugin is registered", func(t *testing.T) {
+		translator := makeTestTranslator(model.TranslationProviderPlugin)
+
+		_, err := translator.Translate(context.Background(), "Hello", "de")
+		require.ErrorIs(t, err, ErrNoPluginProvider)
+	})
+
+	t.Run("should translate with a plugin provider", func(t *testing.T) {
+		translator := makeTestTranslator(model.TranslationProviderPlugin)
+
+		translator.SetPluginProvider(&testPluginProvider{
+			translation: &model.Translation{SourceLanguage: "en", Text: "Hallo"},
+		})
+
+		translation, err := translator.Translate(context.Background(), "Hello", "de")
+		require.NoError(t, err)
+		assert.Equal(t, &model.Translation{Language: "de", SourceLanguage: "en", Text: "Hallo"}, translation)
+	})
+
+	t.Run("should fail when the provider returns no translation", func(t *testing.T) {
+		translator := makeTestTranslator(model.TranslationProviderPlugin)
+		translator.SetPluginProvider(&testPluginProvider{})
+
+		_, err := translator.Translate(context.Background(), "Hello", "de")
+		require.ErrorIs(t, err, ErrNoTranslation)
+	})
+}
+
+func TestOnConfigChange(t *testing.T) {
+	translator := makeTestTranslator(model.TranslationProviderLocal)
+	require.IsType(t, &LocalProvider{}, translator.provider)
+
+	newConfig := translator.ConfigService.Config().Clone()
+	newConfig.TranslationSettings.Provider = model.NewPointer(model.TranslationProviderHTTP)
+	translator.ConfigService.(*testutils.StaticConfigService).UpdateConfig(newConfig)
+
+	require.IsType(t, &HTTPProvider{}, translator.provider)
+	assert.Equal(t, "http://translate.example.com/translate", translator.provider.(*HTTPProvider).url)
+
+	newConfig = translator.ConfigService.Config().Clone()
+	newConfig.TranslationSettings.Provider = model.NewPointer(model.TranslationProviderPlugin)
+	translator.ConfigService.(*testutils.StaticConfigService).UpdateConfig(newConfig)
+
+	require.Nil(t, translator.provider)
+}
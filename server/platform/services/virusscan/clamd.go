@@ -0,0 +1,190 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package virusscan
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"io"
+	"net"
+	"net/url"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// clamdChunkSize is the size of the chunks the contents of a file are streamed in. It must
+	// be lower than the StreamMaxLength of the daemon.
+	clamdChunkSize = 64 * 1024
+
+	clamdResponseMaxBytes = 4 * 1024
+)
+
+// ClamdScanner scans files using a clamd daemon, streaming their contents with the INSTREAM
+// command so that the daemon does not need access to the file store.
+type ClamdScanner struct {
+	network string
+	address string
+	dialer  net.Dialer
+}
+
+// NewClamdScanner returns a scanner for the clamd daemon listening at address, either
+// tcp://host:port or unix:///path/to/clamd.sock.
+func NewClamdScanner(address string) (*ClamdScanner, error) {
+	u, err := url.Parse(address)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to parse clamd address")
+	}
+
+	switch u.Scheme {
+	case "tcp":
+		if u.Host == "" {
+			return nil, errors.New("missing host in clamd address")
+		}
+		return &ClamdScanner{network: "tcp", address: u.Host}, nil
+	case "unix":
+		if u.Path == "" {
+			return nil, errors.New("missing path in clamd address")
+		}
+		return &ClamdScanner{network: "unix", address: u.Path}, nil
+	default:
+		return nil, errors.Errorf("unsupported clamd address scheme %q", u.Scheme)
+	}
+}
+
+func (c *ClamdScanner) dial(ctx context.Context) (net.Conn, error) {
+	conn, err := c.dialer.DialContext(ctx, c.network, c.address)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to connect to clamd")
+	}
+
+	if deadline, ok := ctx.Deadline(); ok {
+		if err := conn.SetDeadline(deadline); err != nil {
+			conn.Close()
+			return nil, errors.Wrap(err, "failed to set clamd connection deadline")
+		}
+	}
+
+	return conn, nil
+}
+
+// Ping checks that the daemon is reachable.
+func (c *ClamdScanner) Ping(ctx context.Context) error {
+	conn, err := c.dial(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	if _, err = conn.Write([]byte("zPING\x00")); err != nil {
+		return errors.Wrap(err, "failed to send clamd command")
+	}
+
+	response, err := readClamdResponse(conn)
+	if err != nil {
+		return err
+	}
+
+	if response != "PONG" {
+		return errors.Errorf("unexpected clamd response %q", response)
+	}
+
+	return nil
+}
+
+func (c *ClamdScanner) Scan(ctx context.Context, r io.Reader) (*Result, error) {
+	conn, err := c.dial(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	streamErr := streamToClamd(conn, r)
+
+	// The daemon replies and closes the connection early when the stream exceeds its size
+	// limit, in which case its response explains the failure better than the write error.
+	response, err := readClamdResponse(conn)
+	if err != nil {
+		if streamErr != nil {
+			return nil, streamErr
+		}
+		return nil, err
+	}
+
+	return parseClamdScanResponse(response)
+}
+
+func streamToClamd(conn net.Conn, r io.Reader) error {
+	w := bufio.NewWriterSize(conn, clamdChunkSize+4)
+
+	if _, err := w.WriteString("zINSTREAM\x00"); err != nil {
+		return errors.Wrap(err, "failed to send clamd command")
+	}
+
+	chunk := make([]byte, clamdChunkSize)
+	var size [4]byte
+	for {
+		n, readErr := io.ReadFull(r, chunk)
+		if n > 0 {
+			binary.BigEndian.PutUint32(size[:], uint32(n))
+			if _, err := w.Write(size[:]); err != nil {
+				return errors.Wrap(err, "failed to stream file to clamd")
+			}
+			if _, err := w.Write(chunk[:n]); err != nil {
+				return errors.Wrap(err, "failed to stream file to clamd")
+			}
+		}
+
+		if readErr == io.EOF || readErr == io.ErrUnexpectedEOF {
+			break
+		} else if readErr != nil {
+			return errors.Wrap(readErr, "failed to read file")
+		}
+	}
+
+	// A zero length chunk marks the end of the stream.
+	binary.BigEndian.PutUint32(size[:], 0)
+	if _, err := w.Write(size[:]); err != nil {
+		return errors.Wrap(err, "failed to stream file to clamd")
+	}
+
+	if err := w.Flush(); err != nil {
+		return errors.Wrap(err, "failed to stream file to clamd")
+	}
+
+	return nil
+}
+
+// readClamdResponse reads a null terminated response, as requested by the z prefix of the
+// commands.
+func readClamdResponse(conn net.Conn) (string, error) {
+	response, err := bufio.NewReader(io.LimitReader(conn, clamdResponseMaxBytes)).ReadString('\x00')
+	if err != nil && (err != io.EOF || response == "") {
+		return "", errors.Wrap(err, "failed to read clamd response")
+	}
+
+	return strings.TrimSpace(strings.TrimSuffix(response, "\x00")), nil
+}
+
+// parseClamdScanResponse parses responses such as "stream: OK",
+// "stream: Eicar-Signature FOUND" or "INSTREAM size limit exceeded. ERROR".
+func parseClamdScanResponse(response string) (*Result, error) {
+	message := strings.TrimSpace(strings.TrimPrefix(response, "stream:"))
+
+	switch {
+	case message == "OK":
+		return &Result{}, nil
+	case strings.HasSuffix(message, " FOUND"):
+		return &Result{
+			Infected:  true,
+			Signature: strings.TrimSpace(strings.TrimSuffix(message, " FOUND")),
+		}, nil
+	case strings.HasSuffix(message, " ERROR"):
+		return nil, errors.Errorf("clamd failed to scan file: %s", strings.TrimSpace(strings.TrimSuffix(message, " ERROR")))
+	default:
+		return nil, errors.Errorf("unexpected clamd response %q", response)
+	}
+}
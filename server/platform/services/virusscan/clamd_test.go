@@ -0,0 +1,235 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package virusscan
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/binary"
+	"io"
+	"net"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testSignature = "Eicar-Test-Signature"
+
+// fakeClamd implements the parts of the clamd protocol used by ClamdScanner. It reports the
+// streams containing "EICAR" as infected and rejects those longer than maxStreamLength.
+type fakeClamd struct {
+	listener        net.Listener
+	maxStreamLength int
+	received        chan []byte
+}
+
+func startFakeClamd(t *testing.T, network string, maxStreamLength int) *fakeClamd {
+	t.Helper()
+
+	address := "127.0.0.1:0"
+	if network == "unix" {
+		address = filepath.Join(t.TempDir(), "clamd.sock")
+	}
+
+	listener, err := net.Listen(network, address)
+	require.NoError(t, err)
+
+	daemon := &fakeClamd{
+		listener:        listener,
+		maxStreamLength: maxStreamLength,
+		received:        make(chan []byte, 10),
+	}
+	go daemon.serve()
+	t.Cleanup(func() { listener.Close() })
+
+	return daemon
+}
+
+func (d *fakeClamd) address() string {
+	if d.listener.Addr().Network() == "unix" {
+		return "unix://" + d.listener.Addr().String()
+	}
+	return "tcp://" + d.listener.Addr().String()
+}
+
+func (d *fakeClamd) serve() {
+	for {
+		conn, err := d.listener.Accept()
+		if err != nil {
+			return
+		}
+		go d.handle(conn)
+	}
+}
+
+func (d *fakeClamd) handle(conn net.Conn) {
+	defer conn.Close()
+
+	r := bufio.NewReader(conn)
+	command, err := r.ReadString('\x00')
+	if err != nil {
+		return
+	}
+
+	switch command {
+	case "zPING\x00":
+		conn.Write([]byte("PONG\x00"))
+	case "zINSTREAM\x00":
+		var stream []byte
+		for {
+			var size uint32
+			if err := binary.Read(r, binary.BigEndian, &size); err != nil {
+				return
+			}
+			if size == 0 {
+				break
+			}
+			if len(stream)+int(size) > d.maxStreamLength {
+				conn.Write([]byte("INSTREAM size limit exceeded. ERROR\x00"))
+				// Unlike clamd, wait for the client to close the connection so that the
+				// response is not lost to a connection reset.
+				io.Copy(io.Discard, r)
+				return
+			}
+			chunk := make([]byte, size)
+			if _, err := io.ReadFull(r, chunk); err != nil {
+				return
+			}
+			stream = append(stream, chunk...)
+		}
+		d.received <- stream
+
+		if bytes.Contains(stream, []byte("EICAR")) {
+			conn.Write([]byte("stream: " + testSignature + " FOUND\x00"))
+		} else {
+			conn.Write([]byte("stream: OK\x00"))
+		}
+	default:
+		conn.Write([]byte("UNKNOWN COMMAND\x00"))
+	}
+}
+
+func TestNewClamdScanner(t *testing.T) {
+	scanner, err := NewClamdScanner("tcp://localhost:3310")
+	require.NoError(t, err)
+	assert.Equal(t, "tcp", scanner.network)
+	assert.Equal(t, "localhost:3310", scanner.address)
+
+	scanner, err = NewClamdScanner("unix:///var/run/clamav/clamd.ctl")
+	require.NoError(t, err)
+	assert.Equal(t, "unix", scanner.network)
+	assert.Equal(t, "/var/run/clamav/clamd.ctl", scanner.address)
+
+	for _, address := range []string{"", "tcp://", "unix://", "http://localhost:3310"} {
+		_, err = NewClamdScanner(address)
+		assert.Error(t, err, address)
+	}
+}
+
+func TestClamdScanner(t *testing.T) {
+	for _, network := range []string{"tcp", "unix"} {
+		t.Run(network, func(t *testing.T) {
+			daemon := startFakeClamd(t, network, 1024*1024)
+
+			scanner, err := NewClamdScanner(daemon.address())
+			require.NoError(t, err)
+
+			t.Run("should ping the daemon", func(t *testing.T) {
+				require.NoError(t, scanner.Ping(context.Background()))
+			})
+
+			t.Run("should stream the file in chunks", func(t *testing.T) {
+				data := bytes.Repeat([]byte("clean "), clamdChunkSize/2)
+
+				result, err := scanner.Scan(context.Background(), bytes.NewReader(data))
+				require.NoError(t, err)
+				assert.Equal(t, &Result{}, result)
+				assert.Equal(t, data, <-daemon.received)
+			})
+
+			t.Run("should scan an empty file", func(t *testing.T) {
+				result, err := scanner.Scan(context.Background(), bytes.NewReader(nil))
+				require.NoError(t, err)
+				assert.False(t, result.Infected)
+				assert.Empty(t, <-daemon.received)
+			})
+
+			t.Run("should report the virus found", func(t *testing.T) {
+				result, err := scanner.Scan(context.Background(), strings.NewReader("X5O!P%@AP[4\\PZX54(P^)7CC)7}$EICAR"))
+				require.NoError(t, err)
+				assert.Equal(t, &Result{Infected: true, Signature: testSignature}, result)
+				<-daemon.received
+			})
+
+			t.Run("should return the error of the daemon", func(t *testing.T) {
+				daemon := startFakeClamd(t, network, clamdChunkSize)
+				scanner, err := NewClamdScanner(daemon.address())
+				require.NoError(t, err)
+
+				_, err = scanner.Scan(context.Background(), bytes.NewReader(make([]byte, 3*clamdChunkSize)))
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), "INSTREAM size limit exceeded")
+			})
+		})
+	}
+
+	t.Run("should fail when the daemon is unreachable", func(t *testing.T) {
+		listener, err := net.Listen("tcp", "127.0.0.1:0")
+		require.NoError(t, err)
+		address := "tcp://" + listener.Addr().String()
+		listener.Close()
+
+		scanner, err := NewClamdScanner(address)
+		require.NoError(t, err)
+
+		_, err = scanner.Scan(context.Background(), strings.NewReader("data"))
+		require.Error(t, err)
+		require.Error(t, scanner.Ping(context.Background()))
+	})
+
+	t.Run("should give up after the deadline", func(t *testing.T) {
+		listener, err := net.Listen("tcp", "127.0.0.1:0")
+		require.NoError(t, err)
+		defer listener.Close()
+
+		// Accept the connection but never reply.
+		go func() {
+			conn, err := listener.Accept()
+			if err == nil {
+				defer conn.Close()
+				io.Copy(io.Discard, conn)
+			}
+		}()
+
+		scanner, err := NewClamdScanner("tcp://" + listener.Addr().String())
+		require.NoError(t, err)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+		defer cancel()
+
+		_, err = scanner.Scan(ctx, strings.NewReader("data"))
+		require.Error(t, err)
+	})
+}
+
+func TestParseClamdScanResponse(t *testing.T) {
+	result, err := parseClamdScanResponse("stream: OK")
+	require.NoError(t, err)
+	assert.False(t, result.Infected)
+
+	result, err = parseClamdScanResponse("stream: Win.Test.EICAR_HDB-1 FOUND")
+	require.NoError(t, err)
+	assert.Equal(t, &Result{Infected: true, Signature: "Win.Test.EICAR_HDB-1"}, result)
+
+	_, err = parseClamdScanResponse("stream: Can't allocate memory ERROR")
+	require.EqualError(t, err, "clamd failed to scan file: Can't allocate memory")
+
+	_, err = parseClamdScanResponse("UNKNOWN COMMAND")
+	require.Error(t, err)
+}
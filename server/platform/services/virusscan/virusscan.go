@@ -0,0 +1,156 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package virusscan
+
+import (
+	"context"
+	"errors"
+	"io"
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/shared/configservice"
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+)
+
+var (
+	ErrNotEnabled = errors.New("virusscan.Service: virus scanning not enabled")
+	ErrNoScanner  = errors.New("virusscan.Service: no scanner is available for the configured driver")
+)
+
+// Result is the outcome of scanning a file.
+type Result struct {
+	// Infected is true when the scanner found a virus in the file.
+	Infected bool
+	// Signature is the name of the virus found, if any.
+	Signature string
+}
+
+// A Scanner scans the contents of files for viruses. Scanners must be safe for concurrent use.
+type Scanner interface {
+	// Scan reads r until EOF and returns whether its contents are infected. An error is
+	// returned when the scanner could not tell.
+	Scan(ctx context.Context, r io.Reader) (*Result, error)
+}
+
+// Service is the public interface for the virus scanning of uploaded files. An instance of
+// Service should be created using MakeService, and delegates to the scanner selected in the
+// VirusScanSettings provided by the ConfigService.
+type Service struct {
+	ConfigService    configservice.ConfigService
+	configListenerID string
+
+	Logger *mlog.Logger
+
+	lock     sync.RWMutex
+	settings model.VirusScanSettings
+	scanner  Scanner
+	override Scanner
+}
+
+func MakeService(configService configservice.ConfigService, logger *mlog.Logger) *Service {
+	service := &Service{
+		ConfigService: configService,
+		Logger:        logger,
+	}
+
+	service.configListenerID = service.ConfigService.AddConfigListener(service.OnConfigChange)
+
+	service.settings = service.ConfigService.Config().VirusScanSettings
+	service.scanner = service.makeScanner(service.settings)
+
+	return service
+}
+
+func (s *Service) makeScanner(settings model.VirusScanSettings) Scanner {
+	if !*settings.Enable {
+		return nil
+	}
+
+	if s.override != nil {
+		return s.override
+	}
+
+	switch *settings.Driver {
+	case model.VirusScanDriverClamd:
+		scanner, err := NewClamdScanner(*settings.ClamdAddress)
+		if err != nil {
+			if s.Logger != nil {
+				s.Logger.Warn("Invalid clamd address", mlog.String("address", *settings.ClamdAddress), mlog.Err(err))
+			}
+			return nil
+		}
+		return scanner
+	default:
+		return nil
+	}
+}
+
+func (s *Service) Close() {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.ConfigService.RemoveConfigListener(s.configListenerID)
+}
+
+func (s *Service) OnConfigChange(oldConfig, newConfig *model.Config) {
+	if !reflect.DeepEqual(oldConfig.VirusScanSettings, newConfig.VirusScanSettings) {
+		s.lock.Lock()
+		defer s.lock.Unlock()
+
+		s.settings = newConfig.VirusScanSettings
+		s.scanner = s.makeScanner(s.settings)
+	}
+}
+
+// SetScanner replaces the scanner selected by the driver of the VirusScanSettings. Passing nil
+// restores it. Scanning still has to be enabled in the settings.
+func (s *Service) SetScanner(scanner Scanner) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.override = scanner
+	s.scanner = s.makeScanner(s.settings)
+}
+
+// IsEnabled reports whether uploaded files are scanned.
+func (s *Service) IsEnabled() bool {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	return *s.settings.Enable
+}
+
+// Timeout returns the maximum time to wait for a file to be scanned.
+func (s *Service) Timeout() time.Duration {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	return time.Duration(*s.settings.ScanTimeoutMilliseconds) * time.Millisecond
+}
+
+// Scan scans the contents of r using the configured scanner, giving up after the configured
+// timeout.
+func (s *Service) Scan(ctx context.Context, r io.Reader) (*Result, error) {
+	s.lock.RLock()
+	enabled := *s.settings.Enable
+	scanner := s.scanner
+	timeout := time.Duration(*s.settings.ScanTimeoutMilliseconds) * time.Millisecond
+	s.lock.RUnlock()
+
+	if !enabled {
+		return nil, ErrNotEnabled
+	}
+
+	if scanner == nil {
+		return nil, ErrNoScanner
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	return scanner.Scan(ctx, r)
+}
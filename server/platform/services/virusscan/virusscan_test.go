@@ -0,0 +1,93 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package virusscan
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/v8/channels/utils/testutils"
+)
+
+type testScanner struct {
+	result *Result
+}
+
+func (s *testScanner) Scan(ctx context.Context, r io.Reader) (*Result, error) {
+	if _, err := io.Copy(io.Discard, r); err != nil {
+		return nil, err
+	}
+	return s.result, nil
+}
+
+func makeTestService(t *testing.T, address string) *Service {
+	cfg := &model.Config{}
+	cfg.SetDefaults()
+	cfg.VirusScanSettings.Enable = model.NewPointer(true)
+	cfg.VirusScanSettings.ClamdAddress = model.NewPointer(address)
+
+	service := MakeService(&testutils.StaticConfigService{Cfg: cfg}, nil)
+	t.Cleanup(service.Close)
+
+	return service
+}
+
+func TestScan(t *testing.T) {
+	t.Run("should scan with clamd", func(t *testing.T) {
+		daemon := startFakeClamd(t, "tcp", 1024*1024)
+		service := makeTestService(t, daemon.address())
+
+		assert.True(t, service.IsEnabled())
+		result, err := service.Scan(context.Background(), strings.NewReader("EICAR"))
+		require.NoError(t, err)
+		assert.Equal(t, &Result{Infected: true, Signature: testSignature}, result)
+	})
+
+	t.Run("should fail when scanning is disabled", func(t *testing.T) {
+		service := makeTestService(t, model.VirusScanSettingsDefaultClamdAddress)
+
+		newConfig := service.ConfigService.Config().Clone()
+		newConfig.VirusScanSettings.Enable = model.NewPointer(false)
+		service.ConfigService.(*testutils.StaticConfigService).UpdateConfig(newConfig)
+
+		assert.False(t, service.IsEnabled())
+		_, err := service.Scan(context.Background(), strings.NewReader("data"))
+		require.ErrorIs(t, err, ErrNotEnabled)
+	})
+
+	t.Run("should use the scanner set", func(t *testing.T) {
+		service := makeTestService(t, model.VirusScanSettingsDefaultClamdAddress)
+
+		service.SetScanner(&testScanner{result: &Result{Infected: true, Signature: "test"}})
+		result, err := service.Scan(context.Background(), strings.NewReader("data"))
+		require.NoError(t, err)
+		assert.Equal(t, "test", result.Signature)
+
+		// The scanner set is kept when the settings change.
+		newConfig := service.ConfigService.Config().Clone()
+		newConfig.VirusScanSettings.ScanTimeoutMilliseconds = model.NewPointer(1000)
+		service.ConfigService.(*testutils.StaticConfigService).UpdateConfig(newConfig)
+
+		result, err = service.Scan(context.Background(), strings.NewReader("data"))
+		require.NoError(t, err)
+		assert.Equal(t, "test", result.Signature)
+	})
+
+	t.Run("should fail when no scanner is available", func(t *testing.T) {
+		service := makeTestService(t, model.VirusScanSettingsDefaultClamdAddress)
+
+		newConfig := service.ConfigService.Config().Clone()
+		newConfig.VirusScanSettings.Driver = model.NewPointer("unknown")
+		service.ConfigService.(*testutils.StaticConfigService).UpdateConfig(newConfig)
+
+		_, err := service.Scan(context.Background(), strings.NewReader("data"))
+		require.ErrorIs(t, err, ErrNoScanner)
+	})
+}
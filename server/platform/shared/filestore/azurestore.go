@@ -0,0 +1,626 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package filestore
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+)
+
+const (
+	azureAPIVersion       = "2021-08-06"
+	azureBlockSize        = 4 * 1024 * 1024
+	azureListPageSize     = 5000
+	azureCopyPollInterval = 100 * time.Millisecond
+)
+
+// AzureFileBackend stores files as block blobs in an Azure Blob Storage container,
+// talking to the Blob service REST API with Shared Key authorization.
+type AzureFileBackend struct {
+	accountName      string
+	accountKey       []byte
+	container        string
+	pathPrefix       string
+	containerURL     *url.URL
+	client           *http.Client
+	timeout          time.Duration
+	signedURLExpires time.Duration
+}
+
+// AzureFileBackendError is returned when a request to the Blob service fails.
+type AzureFileBackendError struct {
+	StatusCode int
+	Code       string `xml:"Code"`
+	Message    string `xml:"Message"`
+}
+
+var _ FileBackendWithLinkGenerator = (*AzureFileBackend)(nil)
+
+func (e *AzureFileBackendError) Error() string {
+	if e.Message == "" {
+		return fmt.Sprintf("azure blob storage request failed with status %d %s", e.StatusCode, e.Code)
+	}
+	return fmt.Sprintf("azure blob storage request failed with status %d %s: %s", e.StatusCode, e.Code, e.Message)
+}
+
+func isAzureStatus(err error, statusCode int) bool {
+	var azureErr *AzureFileBackendError
+	return errors.As(err, &azureErr) && azureErr.StatusCode == statusCode
+}
+
+// NewAzureFileBackend returns an instance of an AzureFileBackend. The endpoint defaults to
+// the public Azure cloud, and can be set to reach sovereign clouds or the Azurite emulator.
+func NewAzureFileBackend(settings FileBackendSettings) (*AzureFileBackend, error) {
+	accountKey, err := base64.StdEncoding.DecodeString(settings.AzureAccountKey)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to decode the azure account key")
+	}
+
+	endpoint := settings.AzureEndpoint
+	if endpoint == "" {
+		endpoint = fmt.Sprintf("https://%s.blob.core.windows.net", settings.AzureAccountName)
+	}
+	containerURL, err := url.Parse(strings.TrimSuffix(endpoint, "/") + "/" + settings.AzureContainer)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to parse the azure endpoint")
+	}
+
+	return &AzureFileBackend{
+		accountName:      settings.AzureAccountName,
+		accountKey:       accountKey,
+		container:        settings.AzureContainer,
+		pathPrefix:       settings.AzurePathPrefix,
+		containerURL:     containerURL,
+		client:           newHTTPBackendClient(settings.SkipVerify),
+		timeout:          time.Duration(settings.AzureRequestTimeoutMilliseconds) * time.Millisecond,
+		signedURLExpires: time.Duration(settings.AzureSignedURLExpiresSeconds) * time.Second,
+	}, nil
+}
+
+func (b *AzureFileBackend) DriverName() string {
+	return driverAzure
+}
+
+func (b *AzureFileBackend) blobName(path string) string {
+	return filepath.Join(b.pathPrefix, path)
+}
+
+func (b *AzureFileBackend) blobURL(blob string, query url.Values) *url.URL {
+	u := *b.containerURL
+	u.Path += "/" + blob
+	u.RawPath = ""
+	u.RawQuery = query.Encode()
+	return &u
+}
+
+func (b *AzureFileBackend) containerRequestURL(query url.Values) *url.URL {
+	u := *b.containerURL
+	query.Set("restype", "container")
+	u.RawQuery = query.Encode()
+	return &u
+}
+
+// sign computes the Shared Key authorization of a request, as described in
+// https://learn.microsoft.com/en-us/rest/api/storageservices/authorize-with-shared-key
+func (b *AzureFileBackend) sign(req *http.Request) string {
+	var contentLength string
+	if req.ContentLength > 0 {
+		contentLength = strconv.FormatInt(req.ContentLength, 10)
+	}
+
+	var msHeaders []string
+	for name := range req.Header {
+		if name = strings.ToLower(name); strings.HasPrefix(name, "x-ms-") {
+			msHeaders = append(msHeaders, name)
+		}
+	}
+	sort.Strings(msHeaders)
+
+	var canonicalized strings.Builder
+	for _, name := range msHeaders {
+		canonicalized.WriteString(name + ":" + strings.TrimSpace(req.Header.Get(name)) + "\n")
+	}
+
+	canonicalized.WriteString("/" + b.accountName + req.URL.EscapedPath())
+	query := req.URL.Query()
+	params := make([]string, 0, len(query))
+	for name := range query {
+		params = append(params, name)
+	}
+	sort.Strings(params)
+	for _, name := range params {
+		values := query[name]
+		sort.Strings(values)
+		canonicalized.WriteString("\n" + strings.ToLower(name) + ":" + strings.Join(values, ","))
+	}
+
+	stringToSign := strings.Join([]string{
+		req.Method,
+		req.Header.Get("Content-Encoding"),
+		req.Header.Get("Content-Language"),
+		contentLength,
+		req.Header.Get("Content-MD5"),
+		req.Header.Get("Content-Type"),
+		"", // Date, replaced by x-ms-date
+		req.Header.Get("If-Modified-Since"),
+		req.Header.Get("If-Match"),
+		req.Header.Get("If-None-Match"),
+		req.Header.Get("If-Unmodified-Since"),
+		req.Header.Get("Range"),
+		canonicalized.String(),
+	}, "\n")
+
+	mac := hmac.New(sha256.New, b.accountKey)
+	mac.Write([]byte(stringToSign))
+	return "SharedKey " + b.accountName + ":" + base64.StdEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// do sends an authorized request to the Blob service. Responses with an error status are
+// returned as an AzureFileBackendError, otherwise the caller must close the body.
+func (b *AzureFileBackend) do(ctx context.Context, method string, u *url.URL, header http.Header, body []byte) (*http.Response, error) {
+	var bodyReader io.Reader
+	if body != nil {
+		bodyReader = bytes.NewReader(body)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), bodyReader)
+	if err != nil {
+		return nil, err
+	}
+	for name, values := range header {
+		req.Header[name] = values
+	}
+	req.Header.Set("x-ms-date", time.Now().UTC().Format(http.TimeFormat))
+	req.Header.Set("x-ms-version", azureAPIVersion)
+	req.Header.Set("Authorization", b.sign(req))
+
+	resp, err := b.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode >= http.StatusMultipleChoices {
+		defer resp.Body.Close()
+		azureErr := &AzureFileBackendError{}
+		// The body of HEAD requests is empty, and errors in other bodies are best effort.
+		_ = xml.NewDecoder(resp.Body).Decode(azureErr)
+		azureErr.StatusCode = resp.StatusCode
+		if code := resp.Header.Get("x-ms-error-code"); code != "" {
+			azureErr.Code = code
+		}
+		return nil, azureErr
+	}
+	return resp, nil
+}
+
+// doAndClose sends a request to the Blob service, discarding the response body.
+func (b *AzureFileBackend) doAndClose(ctx context.Context, method string, u *url.URL, header http.Header, body []byte) (http.Header, error) {
+	resp, err := b.do(ctx, method, u, header, body)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	_, err = io.Copy(io.Discard, resp.Body)
+	return resp.Header, err
+}
+
+func (b *AzureFileBackend) TestConnection() error {
+	ctx, cancel := context.WithTimeout(context.Background(), b.timeout)
+	defer cancel()
+	if _, err := b.doAndClose(ctx, http.MethodHead, b.containerRequestURL(url.Values{}), nil, nil); err != nil {
+		if isAzureStatus(err, http.StatusNotFound) {
+			return errors.Errorf("the azure container %s does not exist", b.container)
+		}
+		return errors.Wrap(err, "unable to check if the azure container exists")
+	}
+	mlog.Debug("Connection to Azure Blob Storage is good. Container exists.")
+	return nil
+}
+
+// MakeContainer creates the container of the backend, unless it already exists.
+func (b *AzureFileBackend) MakeContainer() error {
+	ctx, cancel := context.WithTimeout(context.Background(), b.timeout)
+	defer cancel()
+	if _, err := b.doAndClose(ctx, http.MethodPut, b.containerRequestURL(url.Values{}), nil, nil); err != nil && !isAzureStatus(err, http.StatusConflict) {
+		return errors.Wrap(err, "unable to create the azure container")
+	}
+	return nil
+}
+
+func (b *AzureFileBackend) properties(path string) (http.Header, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), b.timeout)
+	defer cancel()
+	return b.doAndClose(ctx, http.MethodHead, b.blobURL(b.blobName(path), nil), nil, nil)
+}
+
+// Caller must close the first return value
+func (b *AzureFileBackend) Reader(path string) (ReadCloseSeeker, error) {
+	size, err := b.FileSize(path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to open file %s", path)
+	}
+
+	u := b.blobURL(b.blobName(path), nil)
+	open := func(ctx context.Context, offset int64) (io.ReadCloser, error) {
+		header := http.Header{}
+		if offset > 0 {
+			header.Set("x-ms-range", fmt.Sprintf("bytes=%d-", offset))
+		}
+		resp, err := b.do(ctx, http.MethodGet, u, header, nil)
+		if err != nil {
+			return nil, errors.Wrapf(err, "unable to read file %s", path)
+		}
+		return resp.Body, nil
+	}
+
+	return newRangeReader(open, size, b.timeout), nil
+}
+
+func (b *AzureFileBackend) ReadFile(path string) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), b.timeout)
+	defer cancel()
+	resp, err := b.do(ctx, http.MethodGet, b.blobURL(b.blobName(path), nil), nil, nil)
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to open file %s", path)
+	}
+	defer resp.Body.Close()
+
+	f, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to read file %s", path)
+	}
+	return f, nil
+}
+
+func (b *AzureFileBackend) FileExists(path string) (bool, error) {
+	_, err := b.properties(path)
+	if err == nil {
+		return true, nil
+	}
+	if isAzureStatus(err, http.StatusNotFound) {
+		return false, nil
+	}
+	return false, errors.Wrapf(err, "unable to know if file %s exists", path)
+}
+
+func (b *AzureFileBackend) FileSize(path string) (int64, error) {
+	header, err := b.properties(path)
+	if err != nil {
+		return 0, errors.Wrapf(err, "unable to get file size for %s", path)
+	}
+
+	size, err := strconv.ParseInt(header.Get("Content-Length"), 10, 64)
+	if err != nil {
+		return 0, errors.Wrapf(err, "unable to get file size for %s", path)
+	}
+	return size, nil
+}
+
+func (b *AzureFileBackend) FileModTime(path string) (time.Time, error) {
+	header, err := b.properties(path)
+	if err != nil {
+		return time.Time{}, errors.Wrapf(err, "unable to get modification time for file %s", path)
+	}
+
+	modTime, err := http.ParseTime(header.Get("Last-Modified"))
+	if err != nil {
+		return time.Time{}, errors.Wrapf(err, "unable to get modification time for file %s", path)
+	}
+	return modTime, nil
+}
+
+// CopyFile copies a blob within the container, waiting for the copy to complete since the
+// Blob service may copy blobs asynchronously.
+func (b *AzureFileBackend) CopyFile(oldPath, newPath string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), b.timeout)
+	defer cancel()
+
+	dst := b.blobURL(b.blobName(newPath), nil)
+	header := http.Header{}
+	header.Set("x-ms-copy-source", b.blobURL(b.blobName(oldPath), nil).String())
+	respHeader, err := b.doAndClose(ctx, http.MethodPut, dst, header, nil)
+	if err != nil {
+		return errors.Wrapf(err, "unable to copy file from %s to %s", oldPath, newPath)
+	}
+
+	status := respHeader.Get("x-ms-copy-status")
+	for status == "pending" {
+		select {
+		case <-ctx.Done():
+			return errors.Wrapf(ctx.Err(), "unable to copy file from %s to %s", oldPath, newPath)
+		case <-time.After(azureCopyPollInterval):
+		}
+
+		respHeader, err = b.doAndClose(ctx, http.MethodHead, dst, nil, nil)
+		if err != nil {
+			return errors.Wrapf(err, "unable to copy file from %s to %s", oldPath, newPath)
+		}
+		status = respHeader.Get("x-ms-copy-status")
+	}
+	if status != "success" {
+		return errors.Errorf("unable to copy file from %s to %s: copy status is %s", oldPath, newPath, status)
+	}
+
+	return nil
+}
+
+func (b *AzureFileBackend) MoveFile(oldPath, newPath string) error {
+	if err := b.CopyFile(oldPath, newPath); err != nil {
+		return errors.Wrapf(err, "unable to copy the file to %s to the new destination", newPath)
+	}
+
+	if err := b.RemoveFile(oldPath); err != nil {
+		return errors.Wrapf(err, "unable to remove the file old file %s", oldPath)
+	}
+
+	return nil
+}
+
+func (b *AzureFileBackend) WriteFile(fr io.Reader, path string) (int64, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), b.timeout)
+	defer cancel()
+
+	return b.WriteFileContext(ctx, fr, path)
+}
+
+// WriteFileContext uploads the data as blocks before committing them, so that the blob can
+// be appended to later on.
+func (b *AzureFileBackend) WriteFileContext(ctx context.Context, fr io.Reader, path string) (int64, error) {
+	blob := b.blobName(path)
+	written, blockIDs, err := b.putBlocks(ctx, fr, blob)
+	if err != nil {
+		return 0, errors.Wrapf(err, "unable write the data in the file %s", path)
+	}
+
+	if err := b.putBlockList(ctx, blob, nil, blockIDs); err != nil {
+		return 0, errors.Wrapf(err, "unable write the data in the file %s", path)
+	}
+
+	return written, nil
+}
+
+type azureBlockList struct {
+	XMLName   xml.Name `xml:"BlockList"`
+	Committed []string `xml:"Committed"`
+	Latest    []string `xml:"Latest"`
+}
+
+type azureBlockListResponse struct {
+	CommittedBlocks []string `xml:"CommittedBlocks>Block>Name"`
+}
+
+// putBlocks uploads the data as uncommitted blocks of the blob, returning their identifiers.
+func (b *AzureFileBackend) putBlocks(ctx context.Context, fr io.Reader, blob string) (int64, []string, error) {
+	var (
+		written  int64
+		blockIDs []string
+		buf      bytes.Buffer
+	)
+	for {
+		buf.Reset()
+		n, err := io.CopyN(&buf, fr, azureBlockSize)
+		if err != nil && err != io.EOF {
+			return 0, nil, err
+		}
+
+		if n > 0 {
+			// Block identifiers must have the same length within a blob.
+			blockID := base64.StdEncoding.EncodeToString([]byte(model.NewId()))
+			u := b.blobURL(blob, url.Values{"comp": {"block"}, "blockid": {blockID}})
+			if _, putErr := b.doAndClose(ctx, http.MethodPut, u, nil, buf.Bytes()); putErr != nil {
+				return 0, nil, putErr
+			}
+			written += n
+			blockIDs = append(blockIDs, blockID)
+		}
+
+		if err == io.EOF {
+			return written, blockIDs, nil
+		}
+	}
+}
+
+// putBlockList commits the blocks of the blob, replacing its content.
+func (b *AzureFileBackend) putBlockList(ctx context.Context, blob string, committed, latest []string) error {
+	body, err := xml.Marshal(azureBlockList{Committed: committed, Latest: latest})
+	if err != nil {
+		return err
+	}
+
+	header := http.Header{}
+	header.Set("Content-Type", "application/xml")
+	header.Set("x-ms-blob-content-type", fileContentType(blob))
+	u := b.blobURL(blob, url.Values{"comp": {"blocklist"}})
+	_, err = b.doAndClose(ctx, http.MethodPut, u, header, append([]byte(xml.Header), body...))
+	return err
+}
+
+// AppendFile uploads the data as new blocks and commits them after the existing ones.
+func (b *AzureFileBackend) AppendFile(fr io.Reader, path string) (int64, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), b.timeout)
+	defer cancel()
+
+	blob := b.blobName(path)
+	resp, err := b.do(ctx, http.MethodGet, b.blobURL(blob, url.Values{"comp": {"blocklist"}, "blocklisttype": {"committed"}}), nil, nil)
+	if err != nil {
+		return 0, errors.Wrapf(err, "unable to find the file %s to append the data", path)
+	}
+	defer resp.Body.Close()
+
+	var blockList azureBlockListResponse
+	if err = xml.NewDecoder(resp.Body).Decode(&blockList); err != nil {
+		return 0, errors.Wrapf(err, "unable to read the blocks of the file %s", path)
+	}
+	// Blobs not uploaded as blocks have no committed blocks to append to.
+	if size := resp.Header.Get("x-ms-blob-content-length"); len(blockList.CommittedBlocks) == 0 && size != "" && size != "0" {
+		return 0, errors.Errorf("unable append the data in the file %s: the blob has no blocks", path)
+	}
+
+	written, blockIDs, err := b.putBlocks(ctx, fr, blob)
+	if err != nil {
+		return 0, errors.Wrapf(err, "unable append the data in the file %s", path)
+	}
+
+	if err := b.putBlockList(ctx, blob, blockList.CommittedBlocks, blockIDs); err != nil {
+		return 0, errors.Wrapf(err, "unable append the data in the file %s", path)
+	}
+
+	return written, nil
+}
+
+func (b *AzureFileBackend) RemoveFile(path string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), b.timeout)
+	defer cancel()
+	if _, err := b.doAndClose(ctx, http.MethodDelete, b.blobURL(b.blobName(path), nil), nil, nil); err != nil && !isAzureStatus(err, http.StatusNotFound) {
+		return errors.Wrapf(err, "unable to remove the file %s", path)
+	}
+
+	return nil
+}
+
+type azureListResponse struct {
+	Blobs      []string `xml:"Blobs>Blob>Name"`
+	Prefixes   []string `xml:"Blobs>BlobPrefix>Name"`
+	NextMarker string   `xml:"NextMarker"`
+}
+
+// listBlobs returns the names of the blobs starting with the prefix. Unless recursive, the
+// names are only listed up to the next "/", like directories.
+func (b *AzureFileBackend) listBlobs(ctx context.Context, prefix string, recursive bool) ([]string, error) {
+	query := url.Values{
+		"comp":       {"list"},
+		"prefix":     {prefix},
+		"maxresults": {strconv.Itoa(azureListPageSize)},
+	}
+	if !recursive {
+		query.Set("delimiter", "/")
+	}
+
+	var names []string
+	for {
+		resp, err := b.do(ctx, http.MethodGet, b.containerRequestURL(query), nil, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		var list azureListResponse
+		err = xml.NewDecoder(resp.Body).Decode(&list)
+		resp.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+
+		names = append(names, list.Blobs...)
+		names = append(names, list.Prefixes...)
+		if list.NextMarker == "" {
+			return names, nil
+		}
+		query.Set("marker", list.NextMarker)
+	}
+}
+
+func (b *AzureFileBackend) listDirectory(path string, recursion bool) ([]string, error) {
+	prefix := b.blobName(path)
+	if prefix != "" && !strings.HasSuffix(prefix, "/") {
+		prefix += "/"
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), b.timeout)
+	defer cancel()
+	names, err := b.listBlobs(ctx, prefix, recursion)
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to list the directory %s", path)
+	}
+
+	var paths []string
+	for _, name := range names {
+		// We strip the path prefix that gets applied,
+		// so that it remains transparent to the application.
+		trimmed := strings.Trim(strings.TrimPrefix(name, b.pathPrefix), "/")
+		if trimmed != "" {
+			paths = append(paths, trimmed)
+		}
+	}
+
+	return paths, nil
+}
+
+func (b *AzureFileBackend) ListDirectory(path string) ([]string, error) {
+	return b.listDirectory(path, false)
+}
+
+func (b *AzureFileBackend) ListDirectoryRecursively(path string) ([]string, error) {
+	return b.listDirectory(path, true)
+}
+
+func (b *AzureFileBackend) RemoveDirectory(path string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), b.timeout)
+	defer cancel()
+	names, err := b.listBlobs(ctx, strings.TrimSuffix(b.blobName(path), "/")+"/", true)
+	if err != nil {
+		return errors.Wrapf(err, "unable to remove the directory %s", path)
+	}
+
+	for _, name := range names {
+		if _, err := b.doAndClose(ctx, http.MethodDelete, b.blobURL(name, nil), nil, nil); err != nil && !isAzureStatus(err, http.StatusNotFound) {
+			return errors.Wrapf(err, "unable to remove the directory %s", path)
+		}
+	}
+
+	return nil
+}
+
+// GeneratePublicLink returns a URL signed with a read-only service SAS, as described in
+// https://learn.microsoft.com/en-us/rest/api/storageservices/create-service-sas
+func (b *AzureFileBackend) GeneratePublicLink(path string) (string, time.Duration, error) {
+	blob := b.blobName(path)
+	expiry := time.Now().UTC().Add(b.signedURLExpires).Format("2006-01-02T15:04:05Z")
+
+	stringToSign := strings.Join([]string{
+		"r",    // signed permissions
+		"",     // signed start
+		expiry, // signed expiry
+		"/blob/" + b.accountName + "/" + b.container + "/" + blob,
+		"",              // signed identifier
+		"",              // signed IP
+		"",              // signed protocol
+		azureAPIVersion, // signed version
+		"b",             // signed resource
+		"",              // signed snapshot time
+		"",              // signed encryption scope
+		"",              // cache control
+		"attachment",    // content disposition
+		"",              // content encoding
+		"",              // content language
+		"",              // content type
+	}, "\n")
+	mac := hmac.New(sha256.New, b.accountKey)
+	mac.Write([]byte(stringToSign))
+
+	u := b.blobURL(blob, url.Values{
+		"sv":   {azureAPIVersion},
+		"sp":   {"r"},
+		"se":   {expiry},
+		"sr":   {"b"},
+		"rscd": {"attachment"},
+		"sig":  {base64.StdEncoding.EncodeToString(mac.Sum(nil))},
+	})
+	return u.String(), b.signedURLExpires, nil
+}
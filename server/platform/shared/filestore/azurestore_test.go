@@ -0,0 +1,94 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package filestore
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestAzureFileBackend(t *testing.T, endpoint string) *AzureFileBackend {
+	t.Helper()
+
+	backend, err := NewAzureFileBackend(FileBackendSettings{
+		DriverName:                      driverAzure,
+		AzureAccountName:                "devstoreaccount1",
+		AzureAccountKey:                 azuriteAccountKey,
+		AzureContainer:                  "mattermost-test",
+		AzureEndpoint:                   endpoint,
+		AzureRequestTimeoutMilliseconds: 5000,
+		AzureSignedURLExpiresSeconds:    3600,
+	})
+	require.NoError(t, err)
+	return backend
+}
+
+func azureTestSignature(t *testing.T, stringToSign string) string {
+	t.Helper()
+
+	key, err := base64.StdEncoding.DecodeString(azuriteAccountKey)
+	require.NoError(t, err)
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(stringToSign))
+	return base64.StdEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func TestAzureFileBackendSharedKey(t *testing.T) {
+	var req *http.Request
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		req = r
+		w.Header().Set("x-ms-error-code", "BlobNotFound")
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	backend := newTestAzureFileBackend(t, server.URL+"/devstoreaccount1")
+	_, err := backend.AppendFile(nil, "dir/file name.txt")
+	require.Error(t, err)
+	assert.True(t, isAzureStatus(err, http.StatusNotFound))
+
+	require.NotNil(t, req)
+	stringToSign := "GET\n\n\n\n\n\n\n\n\n\n\n\n" +
+		"x-ms-date:" + req.Header.Get("x-ms-date") + "\n" +
+		"x-ms-version:" + azureAPIVersion + "\n" +
+		"/devstoreaccount1/devstoreaccount1/mattermost-test/dir/file%20name.txt\n" +
+		"blocklisttype:committed\n" +
+		"comp:blocklist"
+	assert.Equal(t, "SharedKey devstoreaccount1:"+azureTestSignature(t, stringToSign), req.Header.Get("Authorization"))
+}
+
+func TestAzureFileBackendGeneratePublicLink(t *testing.T) {
+	backend := newTestAzureFileBackend(t, "")
+
+	link, expires, err := backend.GeneratePublicLink("dir/file.txt")
+	require.NoError(t, err)
+	assert.Equal(t, time.Hour, expires)
+
+	u, err := url.Parse(link)
+	require.NoError(t, err)
+	assert.Equal(t, "https", u.Scheme)
+	assert.Equal(t, "devstoreaccount1.blob.core.windows.net", u.Host)
+	assert.Equal(t, "/mattermost-test/dir/file.txt", u.Path)
+
+	query := u.Query()
+	assert.Equal(t, "r", query.Get("sp"))
+	assert.Equal(t, "b", query.Get("sr"))
+	assert.Equal(t, "attachment", query.Get("rscd"))
+	expiry, err := time.Parse(time.RFC3339, query.Get("se"))
+	require.NoError(t, err)
+	assert.WithinDuration(t, time.Now().Add(time.Hour), expiry, time.Minute)
+
+	stringToSign := "r\n\n" + query.Get("se") + "\n/blob/devstoreaccount1/mattermost-test/dir/file.txt\n\n\n\n" +
+		azureAPIVersion + "\nb\n\n\n\nattachment\n\n\n"
+	assert.Equal(t, azureTestSignature(t, stringToSign), query.Get("sig"))
+}
@@ -0,0 +1,447 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package filestore
+
+import (
+	"container/list"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+)
+
+// Files larger than this fraction of the cache aren't cached, so that a single large download
+// doesn't evict most of the cache.
+const cachedFileMaxSizeRatio = 8
+
+// cacheFileName matches the names of the cached files, and of the temporary files they are
+// downloaded to, so that only those are removed from the cache directory.
+var cacheFileName = regexp.MustCompile(`^[0-9a-f]{64}(-[0-9]+\.tmp)?$`)
+
+var errCachedFileInvalidated = errors.New("the file was invalidated while being cached")
+
+// FileCacheMetrics counts the hits and misses of a CachedFileBackend. It's implemented by
+// einterfaces.MetricsInterface.
+type FileCacheMetrics interface {
+	IncrementFileCacheHitCounter()
+	IncrementFileCacheMissCounter()
+}
+
+type cachedFile struct {
+	path string
+	size int64
+}
+
+// cacheFill tracks a file being downloaded to the cache, so that it isn't added to the cache
+// if it's invalidated in the meantime.
+type cacheFill struct {
+	invalidated bool
+}
+
+// CachedFileBackend keeps the files read from another backend in a local directory, evicting
+// the least recently used ones once they take more than the maximum size of the cache. Cached
+// files are invalidated when they are changed through the backend, and the invalidation
+// listener is notified so that the other servers of a cluster can invalidate their copies.
+//
+// The files are cached as they are stored in the other backend, so it must be wrapped by the
+// EncryptedFileBackend, and not the other way around, for the cached files to be encrypted.
+type CachedFileBackend struct {
+	backend   FileBackend
+	directory string
+	maxSize   int64
+
+	mut          sync.Mutex
+	files        map[string]*list.Element
+	lru          *list.List
+	size         int64
+	fills        map[string]*cacheFill
+	metrics      FileCacheMetrics
+	onInvalidate func(path string, directory bool)
+}
+
+// NewCachedFileBackend creates a cache of at most maxSize bytes in directory in front of
+// backend. Since the files may have changed while the cache wasn't running, the files that
+// were previously cached in the directory are removed.
+func NewCachedFileBackend(backend FileBackend, directory string, maxSize int64) (*CachedFileBackend, error) {
+	if maxSize <= 0 {
+		return nil, errors.New("the maximum size of the file cache must be positive")
+	}
+	if err := os.MkdirAll(directory, 0750); err != nil {
+		return nil, errors.Wrapf(err, "unable to create the file cache directory %s", directory)
+	}
+
+	entries, err := os.ReadDir(directory)
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to read the file cache directory %s", directory)
+	}
+	for _, entry := range entries {
+		if !entry.IsDir() && cacheFileName.MatchString(entry.Name()) {
+			if err := os.Remove(filepath.Join(directory, entry.Name())); err != nil {
+				return nil, errors.Wrap(err, "unable to empty the file cache directory")
+			}
+		}
+	}
+
+	return &CachedFileBackend{
+		backend:   backend,
+		directory: directory,
+		maxSize:   maxSize,
+		files:     make(map[string]*list.Element),
+		lru:       list.New(),
+		fills:     make(map[string]*cacheFill),
+	}, nil
+}
+
+// GetCachedFileBackend returns the CachedFileBackend that backend wraps, if any.
+func GetCachedFileBackend(backend FileBackend) *CachedFileBackend {
+	for {
+		if cached, ok := backend.(*CachedFileBackend); ok {
+			return cached
+		}
+		wrapper, ok := backend.(interface{ Unwrap() FileBackend })
+		if !ok {
+			return nil
+		}
+		backend = wrapper.Unwrap()
+	}
+}
+
+// Unwrap returns the backend the cached files are read from.
+func (b *CachedFileBackend) Unwrap() FileBackend {
+	return b.backend
+}
+
+// SetMetrics sets the metrics that the cache hits and misses are counted in.
+func (b *CachedFileBackend) SetMetrics(metrics FileCacheMetrics) {
+	b.mut.Lock()
+	defer b.mut.Unlock()
+	b.metrics = metrics
+}
+
+// SetInvalidationListener sets the function called when files are invalidated because they
+// were changed through the backend. directory is true when all the files under path were
+// invalidated.
+func (b *CachedFileBackend) SetInvalidationListener(fn func(path string, directory bool)) {
+	b.mut.Lock()
+	defer b.mut.Unlock()
+	b.onInvalidate = fn
+}
+
+// Size returns the total size of the cached files.
+func (b *CachedFileBackend) Size() int64 {
+	b.mut.Lock()
+	defer b.mut.Unlock()
+	return b.size
+}
+
+// Invalidate removes a file from the cache, or all the files under path when directory is
+// true, without notifying the invalidation listener. It's meant for the invalidations
+// received from the other servers.
+func (b *CachedFileBackend) Invalidate(path string, directory bool) {
+	b.mut.Lock()
+	defer b.mut.Unlock()
+
+	if !directory {
+		if fill, ok := b.fills[path]; ok {
+			fill.invalidated = true
+		}
+		if elem, ok := b.files[path]; ok {
+			b.remove(elem)
+		}
+		return
+	}
+
+	prefix := strings.TrimSuffix(path, "/") + "/"
+	if prefix == "/" {
+		prefix = ""
+	}
+	for filePath, fill := range b.fills {
+		if strings.HasPrefix(filePath, prefix) {
+			fill.invalidated = true
+		}
+	}
+	for filePath, elem := range b.files {
+		if strings.HasPrefix(filePath, prefix) {
+			b.remove(elem)
+		}
+	}
+}
+
+func (b *CachedFileBackend) invalidate(path string, directory bool) {
+	b.Invalidate(path, directory)
+
+	b.mut.Lock()
+	onInvalidate := b.onInvalidate
+	b.mut.Unlock()
+	if onInvalidate != nil {
+		onInvalidate(path, directory)
+	}
+}
+
+// remove removes a file from the cache. It must be called with the lock held.
+func (b *CachedFileBackend) remove(elem *list.Element) {
+	file := b.lru.Remove(elem).(*cachedFile)
+	delete(b.files, file.path)
+	b.size -= file.size
+
+	if err := os.Remove(b.cachePath(file.path)); err != nil && !os.IsNotExist(err) {
+		mlog.Warn("Unable to remove cached file", mlog.String("path", file.path), mlog.Err(err))
+	}
+}
+
+func (b *CachedFileBackend) cachePath(path string) string {
+	sum := sha256.Sum256([]byte(path))
+	return filepath.Join(b.directory, hex.EncodeToString(sum[:]))
+}
+
+func (b *CachedFileBackend) countHit(hit bool) {
+	b.mut.Lock()
+	metrics := b.metrics
+	b.mut.Unlock()
+
+	if metrics == nil {
+		return
+	}
+	if hit {
+		metrics.IncrementFileCacheHitCounter()
+	} else {
+		metrics.IncrementFileCacheMissCounter()
+	}
+}
+
+// open opens the cached copy of a file, if any.
+func (b *CachedFileBackend) open(path string) *os.File {
+	b.mut.Lock()
+	defer b.mut.Unlock()
+
+	elem, ok := b.files[path]
+	if !ok {
+		return nil
+	}
+
+	f, err := os.Open(b.cachePath(path))
+	if err != nil {
+		// The cached file was removed from the directory.
+		b.remove(elem)
+		return nil
+	}
+	b.lru.MoveToFront(elem)
+	return f
+}
+
+// fill downloads a file to the cache and opens the cached copy. It returns a nil file when
+// the file shouldn't be cached, or is already being downloaded by another request.
+func (b *CachedFileBackend) fill(path string) (*os.File, error) {
+	b.mut.Lock()
+	if _, ok := b.fills[path]; ok {
+		b.mut.Unlock()
+		return nil, nil
+	}
+	fill := &cacheFill{}
+	b.fills[path] = fill
+	b.mut.Unlock()
+
+	defer func() {
+		b.mut.Lock()
+		delete(b.fills, path)
+		b.mut.Unlock()
+	}()
+
+	size, err := b.backend.FileSize(path)
+	if err != nil {
+		return nil, err
+	}
+	if size > b.maxSize/cachedFileMaxSizeRatio {
+		return nil, nil
+	}
+
+	r, err := b.backend.Reader(path)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+
+	cachePath := b.cachePath(path)
+	tmp, err := os.CreateTemp(b.directory, filepath.Base(cachePath)+"-*.tmp")
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to create a file in the file cache")
+	}
+	defer os.Remove(tmp.Name())
+
+	size, err = io.Copy(tmp, r)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to cache file %s", path)
+	}
+
+	b.mut.Lock()
+	defer b.mut.Unlock()
+
+	if fill.invalidated {
+		return nil, errCachedFileInvalidated
+	}
+	if err := os.Rename(tmp.Name(), cachePath); err != nil {
+		return nil, errors.Wrapf(err, "unable to cache file %s", path)
+	}
+	f, err := os.Open(cachePath)
+	if err != nil {
+		os.Remove(cachePath)
+		return nil, errors.Wrapf(err, "unable to open cached file %s", path)
+	}
+
+	b.files[path] = b.lru.PushFront(&cachedFile{path: path, size: size})
+	b.size += size
+	for b.size > b.maxSize {
+		b.remove(b.lru.Back())
+	}
+
+	return f, nil
+}
+
+func (b *CachedFileBackend) DriverName() string {
+	return b.backend.DriverName()
+}
+
+func (b *CachedFileBackend) TestConnection() error {
+	return b.backend.TestConnection()
+}
+
+func (b *CachedFileBackend) Reader(path string) (ReadCloseSeeker, error) {
+	if f := b.open(path); f != nil {
+		b.countHit(true)
+		return f, nil
+	}
+	b.countHit(false)
+
+	f, err := b.fill(path)
+	if err != nil {
+		mlog.Debug("Unable to cache file", mlog.String("path", path), mlog.Err(err))
+	}
+	if f == nil {
+		return b.backend.Reader(path)
+	}
+	return f, nil
+}
+
+func (b *CachedFileBackend) ReadFile(path string) ([]byte, error) {
+	r, err := b.Reader(path)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to read file %s", path)
+	}
+	return data, nil
+}
+
+func (b *CachedFileBackend) FileExists(path string) (bool, error) {
+	b.mut.Lock()
+	_, ok := b.files[path]
+	b.mut.Unlock()
+	if ok {
+		return true, nil
+	}
+
+	return b.backend.FileExists(path)
+}
+
+func (b *CachedFileBackend) FileSize(path string) (int64, error) {
+	b.mut.Lock()
+	elem, ok := b.files[path]
+	b.mut.Unlock()
+	if ok {
+		return elem.Value.(*cachedFile).size, nil
+	}
+
+	return b.backend.FileSize(path)
+}
+
+func (b *CachedFileBackend) FileModTime(path string) (time.Time, error) {
+	return b.backend.FileModTime(path)
+}
+
+func (b *CachedFileBackend) CopyFile(oldPath, newPath string) error {
+	defer b.invalidate(newPath, false)
+	return b.backend.CopyFile(oldPath, newPath)
+}
+
+func (b *CachedFileBackend) MoveFile(oldPath, newPath string) error {
+	defer b.invalidate(oldPath, false)
+	defer b.invalidate(newPath, false)
+	return b.backend.MoveFile(oldPath, newPath)
+}
+
+func (b *CachedFileBackend) WriteFile(fr io.Reader, path string) (int64, error) {
+	return b.WriteFileContext(context.Background(), fr, path)
+}
+
+func (b *CachedFileBackend) WriteFileContext(ctx context.Context, fr io.Reader, path string) (int64, error) {
+	defer b.invalidate(path, false)
+	return TryWriteFileContext(ctx, b.backend, &contextReader{ctx: ctx, r: fr}, path)
+}
+
+func (b *CachedFileBackend) AppendFile(fr io.Reader, path string) (int64, error) {
+	defer b.invalidate(path, false)
+	return b.backend.AppendFile(fr, path)
+}
+
+func (b *CachedFileBackend) RemoveFile(path string) error {
+	defer b.invalidate(path, false)
+	return b.backend.RemoveFile(path)
+}
+
+func (b *CachedFileBackend) ListDirectory(path string) ([]string, error) {
+	return b.backend.ListDirectory(path)
+}
+
+func (b *CachedFileBackend) ListDirectoryRecursively(path string) ([]string, error) {
+	return b.backend.ListDirectoryRecursively(path)
+}
+
+func (b *CachedFileBackend) RemoveDirectory(path string) error {
+	defer b.invalidate(path, true)
+	return b.backend.RemoveDirectory(path)
+}
+
+// GeneratePublicLink generates a link to the file in the other backend, if it supports it.
+func (b *CachedFileBackend) GeneratePublicLink(path string) (string, time.Duration, error) {
+	linkGenerator, ok := b.backend.(FileBackendWithLinkGenerator)
+	if !ok {
+		return "", 0, errors.Errorf("the %s file backend doesn't support public links", b.backend.DriverName())
+	}
+	return linkGenerator.GeneratePublicLink(path)
+}
+
+// contextReader stops reading once its context is done, for the backends that don't support
+// contexts.
+type contextReader struct {
+	ctx context.Context
+	r   io.Reader
+}
+
+func (r *contextReader) Read(p []byte) (int, error) {
+	if err := r.ctx.Err(); err != nil {
+		return 0, err
+	}
+	n, err := r.r.Read(p)
+	if ctxErr := r.ctx.Err(); ctxErr != nil {
+		return 0, ctxErr
+	}
+	return n, err
+}
@@ -0,0 +1,232 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package filestore
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost/server/public/model"
+)
+
+type testFileCacheMetrics struct {
+	hits   int
+	misses int
+}
+
+func (m *testFileCacheMetrics) IncrementFileCacheHitCounter() {
+	m.hits++
+}
+
+func (m *testFileCacheMetrics) IncrementFileCacheMissCounter() {
+	m.misses++
+}
+
+// newTestCachedBackend creates a cache in front of a local backend, returning the directory
+// of the local backend.
+func newTestCachedBackend(t *testing.T, maxSize int64) (*CachedFileBackend, string) {
+	t.Helper()
+
+	dir := t.TempDir()
+	backend, err := NewCachedFileBackend(&LocalFileBackend{directory: dir}, t.TempDir(), maxSize)
+	require.NoError(t, err)
+	return backend, dir
+}
+
+func TestCachedFileBackend(t *testing.T) {
+	t.Run("files are read from the cache", func(t *testing.T) {
+		backend, dir := newTestCachedBackend(t, 1024)
+		metrics := &testFileCacheMetrics{}
+		backend.SetMetrics(metrics)
+
+		_, err := backend.WriteFile(bytes.NewReader([]byte("cached")), "dir/file")
+		require.NoError(t, err)
+
+		data, err := backend.ReadFile("dir/file")
+		require.NoError(t, err)
+		assert.Equal(t, "cached", string(data))
+		assert.Equal(t, 0, metrics.hits)
+		assert.Equal(t, 1, metrics.misses)
+		assert.EqualValues(t, 6, backend.Size())
+
+		// Changes made behind the back of the cache aren't seen until invalidated.
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "dir/file"), []byte("changed"), 0600))
+
+		data, err = backend.ReadFile("dir/file")
+		require.NoError(t, err)
+		assert.Equal(t, "cached", string(data))
+		assert.Equal(t, 1, metrics.hits)
+
+		size, err := backend.FileSize("dir/file")
+		require.NoError(t, err)
+		assert.EqualValues(t, 6, size)
+
+		backend.Invalidate("dir/file", false)
+		assert.Zero(t, backend.Size())
+
+		data, err = backend.ReadFile("dir/file")
+		require.NoError(t, err)
+		assert.Equal(t, "changed", string(data))
+		assert.Equal(t, 2, metrics.misses)
+	})
+
+	t.Run("changes invalidate the cache and notify the listener", func(t *testing.T) {
+		backend, _ := newTestCachedBackend(t, 1024)
+		var invalidated []string
+		backend.SetInvalidationListener(func(path string, directory bool) {
+			if directory {
+				path += "/"
+			}
+			invalidated = append(invalidated, path)
+		})
+
+		readFile := func(path string) string {
+			t.Helper()
+			data, err := backend.ReadFile(path)
+			require.NoError(t, err)
+			return string(data)
+		}
+
+		_, err := backend.WriteFile(bytes.NewReader([]byte("one")), "dir/file")
+		require.NoError(t, err)
+		assert.Equal(t, "one", readFile("dir/file"))
+
+		_, err = backend.WriteFile(bytes.NewReader([]byte("two")), "dir/file")
+		require.NoError(t, err)
+		assert.Equal(t, "two", readFile("dir/file"))
+
+		_, err = backend.AppendFile(bytes.NewReader([]byte("three")), "dir/file")
+		require.NoError(t, err)
+		assert.Equal(t, "twothree", readFile("dir/file"))
+
+		_, err = backend.WriteFile(bytes.NewReader([]byte("other")), "dir/other")
+		require.NoError(t, err)
+		assert.Equal(t, "other", readFile("dir/other"))
+		require.NoError(t, backend.CopyFile("dir/file", "dir/other"))
+		assert.Equal(t, "twothree", readFile("dir/other"))
+
+		require.NoError(t, backend.MoveFile("dir/other", "dir/moved"))
+		_, err = backend.ReadFile("dir/other")
+		require.Error(t, err)
+
+		require.NoError(t, backend.RemoveFile("dir/file"))
+		exists, err := backend.FileExists("dir/file")
+		require.NoError(t, err)
+		assert.False(t, exists)
+
+		assert.Equal(t, "twothree", readFile("dir/moved"))
+		require.NoError(t, backend.RemoveDirectory("dir"))
+		exists, err = backend.FileExists("dir/moved")
+		require.NoError(t, err)
+		assert.False(t, exists)
+		assert.Zero(t, backend.Size())
+
+		assert.Equal(t, []string{
+			"dir/file",
+			"dir/file",
+			"dir/file",
+			"dir/other",
+			"dir/other",
+			"dir/moved",
+			"dir/other",
+			"dir/file",
+			"dir/",
+		}, invalidated)
+	})
+
+	t.Run("least recently used files are evicted", func(t *testing.T) {
+		backend, _ := newTestCachedBackend(t, 8*10)
+		metrics := &testFileCacheMetrics{}
+		backend.SetMetrics(metrics)
+
+		for _, name := range []string{"a", "b", "c", "d", "e", "f", "g", "h", "i"} {
+			_, err := backend.WriteFile(bytes.NewReader(bytes.Repeat([]byte(name), 10)), name)
+			require.NoError(t, err)
+		}
+		for _, name := range []string{"a", "b", "c", "d", "e", "f", "g", "h", "a", "i"} {
+			_, err := backend.ReadFile(name)
+			require.NoError(t, err)
+		}
+		assert.Equal(t, 1, metrics.hits)
+		assert.EqualValues(t, 80, backend.Size())
+
+		// b was evicted to make room for i, while a was used again.
+		_, err := backend.ReadFile("a")
+		require.NoError(t, err)
+		assert.Equal(t, 2, metrics.hits)
+		_, err = backend.ReadFile("b")
+		require.NoError(t, err)
+		assert.Equal(t, 2, metrics.hits)
+	})
+
+	t.Run("large files are not cached", func(t *testing.T) {
+		backend, _ := newTestCachedBackend(t, 8*10)
+
+		_, err := backend.WriteFile(bytes.NewReader(make([]byte, 11)), "large")
+		require.NoError(t, err)
+
+		data, err := backend.ReadFile("large")
+		require.NoError(t, err)
+		assert.Len(t, data, 11)
+		assert.Zero(t, backend.Size())
+	})
+
+	t.Run("missing files are not cached", func(t *testing.T) {
+		backend, _ := newTestCachedBackend(t, 1024)
+
+		_, err := backend.ReadFile("missing")
+		require.Error(t, err)
+
+		_, err = backend.Reader("missing")
+		require.Error(t, err)
+		assert.Zero(t, backend.Size())
+	})
+
+	t.Run("only cached files are removed from the directory", func(t *testing.T) {
+		cacheDir := t.TempDir()
+		backend, err := NewCachedFileBackend(&LocalFileBackend{directory: t.TempDir()}, cacheDir, 1024)
+		require.NoError(t, err)
+
+		_, err = backend.WriteFile(bytes.NewReader([]byte("cached")), "file")
+		require.NoError(t, err)
+		_, err = backend.ReadFile("file")
+		require.NoError(t, err)
+		require.NoError(t, os.WriteFile(filepath.Join(cacheDir, "other"), []byte("other"), 0600))
+
+		_, err = NewCachedFileBackend(&LocalFileBackend{directory: t.TempDir()}, cacheDir, 1024)
+		require.NoError(t, err)
+
+		entries, err := os.ReadDir(cacheDir)
+		require.NoError(t, err)
+		require.Len(t, entries, 1)
+		assert.Equal(t, "other", entries[0].Name())
+	})
+
+	t.Run("cached backend is found behind the encryption", func(t *testing.T) {
+		backend, err := NewFileBackend(FileBackendSettings{
+			DriverName:            driverLocal,
+			Directory:             t.TempDir(),
+			CacheEnabled:          true,
+			CacheDirectory:        t.TempDir(),
+			CacheMaxSizeBytes:     1024,
+			EncryptionEnabled:     true,
+			EncryptionKeyProvider: model.FileEncryptionKeyProviderConfig,
+			EncryptionKeys:        map[string][]byte{"key1": bytes.Repeat([]byte{1}, encryptionKeySize)},
+			EncryptionActiveKeyId: "key1",
+		})
+		require.NoError(t, err)
+
+		_, ok := backend.(*EncryptedFileBackend)
+		require.True(t, ok)
+		require.NotNil(t, GetCachedFileBackend(backend))
+		assert.IsType(t, &LocalFileBackend{}, UnwrapFileBackend(backend))
+
+		assert.Nil(t, GetCachedFileBackend(&LocalFileBackend{}))
+	})
+}
@@ -0,0 +1,734 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package filestore
+
+import (
+	"bytes"
+	"context"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/mattermost/mattermost/server/public/model"
+)
+
+// Encrypted files start with a header holding the data key of the file, wrapped with a
+// key-encryption key, followed by one or more segments: writing a file stores its first
+// segment and each append adds another one. A segment holds its contents split into chunks
+// of a fixed size, the last one possibly shorter, followed by the size of its contents.
+//
+// Each chunk is sealed with AES-GCM under its own random nonce and authenticated together
+// with its offset in the file and whether it ends its segment, so that chunks can be
+// decrypted independently when seeking but can't be reordered, and a segment cut at a chunk
+// boundary is detected. Since appending never rewrites the existing segments, removing whole
+// segments from the end of a file can't be detected from the file alone.
+const (
+	encryptionMagic        = "MMFENC01"
+	encryptionChunkSize    = 64 * 1024
+	encryptionMaxChunkSize = 16 * 1024 * 1024
+	encryptionNonceSize    = 12
+	encryptionTagSize      = 16
+	encryptionChunkExtra   = encryptionNonceSize + encryptionTagSize
+	encryptionTrailerSize  = 8
+)
+
+// EncryptedFileBackend encrypts the files written to another backend with a data key per
+// file. Files that were written before encryption was enabled are only read as they are in
+// migration mode, until the file encryption job has encrypted them; otherwise anyone able
+// to write to the underlying storage could substitute the contents of any file.
+//
+// It deliberately doesn't implement FileBackendWithLinkGenerator, since links to the
+// underlying storage would serve the encrypted contents.
+type EncryptedFileBackend struct {
+	backend       FileBackend
+	keys          KeyWrapper
+	migrationMode bool
+}
+
+func NewEncryptedFileBackend(backend FileBackend, keys KeyWrapper, migrationMode bool) *EncryptedFileBackend {
+	return &EncryptedFileBackend{
+		backend:       backend,
+		keys:          keys,
+		migrationMode: migrationMode,
+	}
+}
+
+// Unwrap returns the backend the encrypted files are stored in.
+func (b *EncryptedFileBackend) Unwrap() FileBackend {
+	return b.backend
+}
+
+// ActiveKeyId returns the id of the key-encryption key that new files are encrypted with.
+func (b *EncryptedFileBackend) ActiveKeyId() string {
+	return b.keys.ActiveKeyId()
+}
+
+func (b *EncryptedFileBackend) DriverName() string {
+	return b.backend.DriverName()
+}
+
+func (b *EncryptedFileBackend) TestConnection() error {
+	if err := b.backend.TestConnection(); err != nil {
+		return err
+	}
+
+	if _, _, err := b.keys.WrapKey(make([]byte, encryptionKeySize)); err != nil {
+		return errors.Wrap(err, "unable to use the active key-encryption key")
+	}
+
+	return nil
+}
+
+func (b *EncryptedFileBackend) Reader(path string) (ReadCloseSeeker, error) {
+	r, fc, err := b.open(path, b.migrationMode)
+	if err != nil {
+		return nil, err
+	}
+	if fc == nil {
+		return r, nil
+	}
+
+	return newDecryptingReader(r, fc)
+}
+
+func (b *EncryptedFileBackend) ReadFile(path string) ([]byte, error) {
+	r, err := b.Reader(path)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to read file %s", path)
+	}
+	return data, nil
+}
+
+func (b *EncryptedFileBackend) FileExists(path string) (bool, error) {
+	return b.backend.FileExists(path)
+}
+
+func (b *EncryptedFileBackend) FileSize(path string) (int64, error) {
+	r, fc, err := b.open(path, b.migrationMode)
+	if err != nil {
+		return 0, err
+	}
+	defer r.Close()
+
+	if fc == nil {
+		return b.backend.FileSize(path)
+	}
+
+	segments, err := readEncryptedSegments(r, fc)
+	if err != nil {
+		return 0, errors.Wrapf(err, "unable to get the size of the file %s", path)
+	}
+	return segments[len(segments)-1].end(), nil
+}
+
+// CopyFile and MoveFile don't need to re-encrypt anything, since the encrypted contents
+// don't depend on the path of the file.
+func (b *EncryptedFileBackend) CopyFile(oldPath, newPath string) error {
+	return b.backend.CopyFile(oldPath, newPath)
+}
+
+func (b *EncryptedFileBackend) MoveFile(oldPath, newPath string) error {
+	return b.backend.MoveFile(oldPath, newPath)
+}
+
+func (b *EncryptedFileBackend) WriteFile(fr io.Reader, path string) (int64, error) {
+	return b.WriteFileContext(context.Background(), fr, path)
+}
+
+func (b *EncryptedFileBackend) WriteFileContext(ctx context.Context, fr io.Reader, path string) (int64, error) {
+	fc, header, err := b.newFileCipher()
+	if err != nil {
+		return 0, err
+	}
+
+	enc := newEncryptingReader(ctx, fr, fc, 0)
+	written, err := TryWriteFileContext(ctx, b.backend, io.MultiReader(bytes.NewReader(header), enc), path)
+	if err != nil {
+		return fc.storedChunksSize(written - fc.headerSize), err
+	}
+	return enc.written, nil
+}
+
+// AppendFile encrypts the appended data with the data key of the file as a new segment, so
+// that only the appended data is written. A segment is only readable once it's complete,
+// so nothing is reported as written when the append fails.
+func (b *EncryptedFileBackend) AppendFile(fr io.Reader, path string) (int64, error) {
+	r, fc, err := b.open(path, b.migrationMode)
+	if err != nil {
+		return 0, err
+	}
+
+	if fc == nil {
+		// A file written before encryption was enabled is encrypted as a whole.
+		appended := &countingReader{r: fr}
+		if err := b.replaceFile(path, io.MultiReader(r, appended), true, r.Close); err != nil {
+			return 0, err
+		}
+		return appended.n, nil
+	}
+
+	segments, err := readEncryptedSegments(r, fc)
+	r.Close()
+	if err != nil {
+		return 0, errors.Wrapf(err, "unable to read the file %s", path)
+	}
+
+	enc := newEncryptingReader(context.Background(), fr, fc, segments[len(segments)-1].end())
+	if _, err := b.backend.AppendFile(enc, path); err != nil {
+		return 0, err
+	}
+	return enc.written, nil
+}
+
+func (b *EncryptedFileBackend) RemoveFile(path string) error {
+	return b.backend.RemoveFile(path)
+}
+
+func (b *EncryptedFileBackend) FileModTime(path string) (time.Time, error) {
+	return b.backend.FileModTime(path)
+}
+
+func (b *EncryptedFileBackend) ListDirectory(path string) ([]string, error) {
+	return b.backend.ListDirectory(path)
+}
+
+func (b *EncryptedFileBackend) ListDirectoryRecursively(path string) ([]string, error) {
+	return b.backend.ListDirectoryRecursively(path)
+}
+
+func (b *EncryptedFileBackend) RemoveDirectory(path string) error {
+	return b.backend.RemoveDirectory(path)
+}
+
+// EncryptFile encrypts a file that was written before encryption was enabled, or wraps
+// the data key of an encrypted file with the active key-encryption key when the file
+// was encrypted with an older one. It reports whether the file was rewritten.
+func (b *EncryptedFileBackend) EncryptFile(path string) (bool, error) {
+	r, fc, err := b.open(path, true)
+	if err != nil {
+		return false, err
+	}
+
+	if fc == nil {
+		return true, b.replaceFile(path, r, true, r.Close)
+	}
+
+	if fc.keyId == b.keys.ActiveKeyId() {
+		r.Close()
+		return false, nil
+	}
+
+	// Only the header changes; the segments are copied as they are.
+	keyId, wrappedKey, err := b.keys.WrapKey(fc.dataKey)
+	if err != nil {
+		r.Close()
+		return false, err
+	}
+	header := marshalEncryptionHeader(fc.chunkSize, keyId, wrappedKey)
+	if _, err := r.Seek(fc.headerSize, io.SeekStart); err != nil {
+		r.Close()
+		return false, errors.Wrapf(err, "unable to read the file %s", path)
+	}
+
+	return true, b.replaceFile(path, io.MultiReader(bytes.NewReader(header), r), false, r.Close)
+}
+
+// replaceFile writes the contents to a temporary file that is then moved over the file,
+// so that the file is never left half written. closeSource is called once the contents
+// have been read, since they usually come from the file being replaced.
+func (b *EncryptedFileBackend) replaceFile(path string, contents io.Reader, encrypt bool, closeSource func() error) error {
+	tmpPath := fmt.Sprintf("%s.%s.tmp", path, model.NewId())
+
+	var err error
+	if encrypt {
+		_, err = b.WriteFile(contents, tmpPath)
+	} else {
+		_, err = b.backend.WriteFile(contents, tmpPath)
+	}
+	closeSource()
+	if err != nil {
+		b.backend.RemoveFile(tmpPath)
+		return errors.Wrapf(err, "unable to rewrite the file %s", path)
+	}
+
+	if err := b.backend.MoveFile(tmpPath, path); err != nil {
+		b.backend.RemoveFile(tmpPath)
+		return errors.Wrapf(err, "unable to rewrite the file %s", path)
+	}
+
+	return nil
+}
+
+// open opens a file of the underlying backend, returning the cipher of the file
+// positioned after its header, or a nil cipher when the file isn't encrypted and
+// unencrypted files are allowed.
+func (b *EncryptedFileBackend) open(path string, allowUnencrypted bool) (ReadCloseSeeker, *fileCipher, error) {
+	r, err := b.backend.Reader(path)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	header, err := readEncryptionHeader(r)
+	if err != nil {
+		r.Close()
+		return nil, nil, errors.Wrapf(err, "unable to read the encryption header of the file %s", path)
+	}
+
+	if header == nil {
+		if !allowUnencrypted {
+			r.Close()
+			return nil, nil, errors.Errorf("the file %s isn't encrypted", path)
+		}
+		if _, err := r.Seek(0, io.SeekStart); err != nil {
+			r.Close()
+			return nil, nil, errors.Wrapf(err, "unable to read the file %s", path)
+		}
+		return r, nil, nil
+	}
+
+	dataKey, err := b.keys.UnwrapKey(header.keyId, header.wrappedKey)
+	if err != nil {
+		r.Close()
+		return nil, nil, errors.Wrapf(err, "unable to decrypt the file %s", path)
+	}
+
+	fc, err := newFileCipher(dataKey, header)
+	if err != nil {
+		r.Close()
+		return nil, nil, err
+	}
+
+	return r, fc, nil
+}
+
+func (b *EncryptedFileBackend) newFileCipher() (*fileCipher, []byte, error) {
+	dataKey := make([]byte, encryptionKeySize)
+	if _, err := rand.Read(dataKey); err != nil {
+		return nil, nil, errors.Wrap(err, "unable to generate data key")
+	}
+
+	keyId, wrappedKey, err := b.keys.WrapKey(dataKey)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "unable to wrap data key")
+	}
+
+	header := &encryptionHeader{
+		chunkSize:  encryptionChunkSize,
+		keyId:      keyId,
+		wrappedKey: wrappedKey,
+	}
+	fc, err := newFileCipher(dataKey, header)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return fc, marshalEncryptionHeader(header.chunkSize, keyId, wrappedKey), nil
+}
+
+type encryptionHeader struct {
+	chunkSize  int64
+	keyId      string
+	wrappedKey []byte
+	size       int64
+}
+
+func marshalEncryptionHeader(chunkSize int64, keyId string, wrappedKey []byte) []byte {
+	header := make([]byte, 0, len(encryptionMagic)+4+1+len(keyId)+2+len(wrappedKey))
+	header = append(header, encryptionMagic...)
+	header = binary.BigEndian.AppendUint32(header, uint32(chunkSize))
+	header = append(header, byte(len(keyId)))
+	header = append(header, keyId...)
+	header = binary.BigEndian.AppendUint16(header, uint16(len(wrappedKey)))
+	header = append(header, wrappedKey...)
+	return header
+}
+
+// readEncryptionHeader reads the header of an encrypted file, returning nil if the file
+// isn't encrypted.
+func readEncryptionHeader(r io.Reader) (*encryptionHeader, error) {
+	magic := make([]byte, len(encryptionMagic))
+	if _, err := io.ReadFull(r, magic); err == io.EOF || err == io.ErrUnexpectedEOF {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	if string(magic) != encryptionMagic {
+		return nil, nil
+	}
+
+	var fixed [5]byte
+	if _, err := io.ReadFull(r, fixed[:]); err != nil {
+		return nil, errors.Wrap(err, "truncated header")
+	}
+	chunkSize := int64(binary.BigEndian.Uint32(fixed[:4]))
+	if chunkSize == 0 || chunkSize > encryptionMaxChunkSize {
+		return nil, errors.Errorf("invalid chunk size %d", chunkSize)
+	}
+
+	keyId := make([]byte, fixed[4])
+	if _, err := io.ReadFull(r, keyId); err != nil {
+		return nil, errors.Wrap(err, "truncated header")
+	}
+
+	var wrappedLen [2]byte
+	if _, err := io.ReadFull(r, wrappedLen[:]); err != nil {
+		return nil, errors.Wrap(err, "truncated header")
+	}
+	wrappedKey := make([]byte, binary.BigEndian.Uint16(wrappedLen[:]))
+	if _, err := io.ReadFull(r, wrappedKey); err != nil {
+		return nil, errors.Wrap(err, "truncated header")
+	}
+
+	return &encryptionHeader{
+		chunkSize:  chunkSize,
+		keyId:      string(keyId),
+		wrappedKey: wrappedKey,
+		size:       int64(len(encryptionMagic) + len(fixed) + len(keyId) + len(wrappedLen) + len(wrappedKey)),
+	}, nil
+}
+
+// fileCipher encrypts and decrypts the chunks of a file.
+type fileCipher struct {
+	aead       cipher.AEAD
+	dataKey    []byte
+	keyId      string
+	chunkSize  int64
+	headerSize int64
+}
+
+func newFileCipher(dataKey []byte, header *encryptionHeader) (*fileCipher, error) {
+	aead, err := newAEAD(dataKey)
+	if err != nil {
+		return nil, err
+	}
+
+	headerSize := header.size
+	if headerSize == 0 {
+		headerSize = int64(len(marshalEncryptionHeader(header.chunkSize, header.keyId, header.wrappedKey)))
+	}
+
+	return &fileCipher{
+		aead:       aead,
+		dataKey:    dataKey,
+		keyId:      header.keyId,
+		chunkSize:  header.chunkSize,
+		headerSize: headerSize,
+	}, nil
+}
+
+func (fc *fileCipher) sealChunk(dst, plaintext []byte, offset int64, final bool) ([]byte, error) {
+	start := len(dst)
+	dst = append(dst, make([]byte, encryptionNonceSize)...)
+	nonce := dst[start:]
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, errors.Wrap(err, "unable to generate nonce")
+	}
+
+	return fc.aead.Seal(dst, nonce, plaintext, chunkAdditionalData(offset, final)), nil
+}
+
+func (fc *fileCipher) openChunk(dst, chunk []byte, offset int64, final bool) ([]byte, error) {
+	if len(chunk) < encryptionChunkExtra {
+		return nil, errors.New("truncated chunk")
+	}
+
+	plaintext, err := fc.aead.Open(dst, chunk[:encryptionNonceSize], chunk[encryptionNonceSize:], chunkAdditionalData(offset, final))
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to decrypt the chunk at offset %d", offset)
+	}
+	return plaintext, nil
+}
+
+// segmentChunks returns the number of chunks of a segment holding size bytes. A segment
+// always has at least one chunk, so that its end is authenticated even when it's empty.
+func (fc *fileCipher) segmentChunks(size int64) int64 {
+	return max(1, (size+fc.chunkSize-1)/fc.chunkSize)
+}
+
+// segmentSize returns the size of an encrypted segment holding size bytes.
+func (fc *fileCipher) segmentSize(size int64) int64 {
+	return size + fc.segmentChunks(size)*encryptionChunkExtra + encryptionTrailerSize
+}
+
+// storedChunksSize returns the size of the contents held by the complete chunks among
+// the given number of encrypted bytes. It tells how much of the contents made it to the
+// file when a write fails part way.
+func (fc *fileCipher) storedChunksSize(encryptedWritten int64) int64 {
+	if encryptedWritten <= 0 {
+		return 0
+	}
+	return (encryptedWritten / (fc.chunkSize + encryptionChunkExtra)) * fc.chunkSize
+}
+
+func chunkAdditionalData(offset int64, final bool) []byte {
+	data := binary.BigEndian.AppendUint64(nil, uint64(offset))
+	if final {
+		return append(data, 1)
+	}
+	return append(data, 0)
+}
+
+// encryptedSegment locates a segment in an encrypted file.
+type encryptedSegment struct {
+	// offset is where the contents of the segment start in the contents of the file, and
+	// storedOffset where the segment starts in the encrypted file.
+	offset       int64
+	storedOffset int64
+	size         int64
+}
+
+func (s encryptedSegment) end() int64 {
+	return s.offset + s.size
+}
+
+// readEncryptedSegments locates the segments of an encrypted file by following their
+// trailers from the end of the file. The trailers aren't authenticated on their own, but
+// a wrong size moves the end of the segment, which then fails to decrypt.
+func readEncryptedSegments(r io.ReadSeeker, fc *fileCipher) ([]encryptedSegment, error) {
+	end, err := r.Seek(0, io.SeekEnd)
+	if err != nil {
+		return nil, err
+	}
+
+	var segments []encryptedSegment
+	var trailer [encryptionTrailerSize]byte
+	for end > fc.headerSize {
+		if end-fc.headerSize < encryptionTrailerSize {
+			return nil, errors.New("truncated segment")
+		}
+		if _, err := r.Seek(end-encryptionTrailerSize, io.SeekStart); err != nil {
+			return nil, err
+		}
+		if _, err := io.ReadFull(r, trailer[:]); err != nil {
+			return nil, err
+		}
+
+		size := int64(binary.BigEndian.Uint64(trailer[:]))
+		if size < 0 || size > end || fc.segmentSize(size) > end-fc.headerSize {
+			return nil, errors.New("truncated segment")
+		}
+		end -= fc.segmentSize(size)
+		segments = append(segments, encryptedSegment{storedOffset: end, size: size})
+	}
+	if len(segments) == 0 {
+		return nil, errors.New("the file has no segments")
+	}
+
+	slices.Reverse(segments)
+	for i := 1; i < len(segments); i++ {
+		segments[i].offset = segments[i-1].end()
+	}
+	return segments, nil
+}
+
+// encryptingReader reads a segment holding the encrypted contents of another reader. It
+// stops with an error once the context is done, so that deadlines apply to any backend.
+type encryptingReader struct {
+	ctx    context.Context
+	src    io.Reader
+	fc     *fileCipher
+	offset int64
+	chunk  []byte
+	out    []byte
+
+	// A byte is read past each full chunk to find out whether it's the last one.
+	peek    [1]byte
+	peeked  bool
+	pending []byte
+	final   bool
+	done    bool
+	written int64
+}
+
+func newEncryptingReader(ctx context.Context, src io.Reader, fc *fileCipher, offset int64) *encryptingReader {
+	return &encryptingReader{
+		ctx:    ctx,
+		src:    src,
+		fc:     fc,
+		offset: offset,
+		chunk:  make([]byte, fc.chunkSize),
+		out:    make([]byte, 0, fc.chunkSize+encryptionChunkExtra),
+	}
+}
+
+func (r *encryptingReader) Read(p []byte) (int, error) {
+	for len(r.pending) == 0 {
+		if r.done {
+			return 0, io.EOF
+		}
+
+		if r.final {
+			r.pending = binary.BigEndian.AppendUint64(r.out[:0], uint64(r.written))
+			r.done = true
+			break
+		}
+
+		if err := r.sealNextChunk(); err != nil {
+			return 0, err
+		}
+	}
+
+	n := copy(p, r.pending)
+	r.pending = r.pending[n:]
+	return n, nil
+}
+
+func (r *encryptingReader) sealNextChunk() error {
+	n := 0
+	if r.peeked {
+		n = copy(r.chunk, r.peek[:])
+		r.peeked = false
+	}
+
+	read, err := io.ReadFull(r.src, r.chunk[n:])
+	n += read
+	if ctxErr := r.ctx.Err(); ctxErr != nil {
+		return ctxErr
+	}
+	if err == io.EOF || err == io.ErrUnexpectedEOF {
+		r.final = true
+	} else if err != nil {
+		return err
+	} else if _, err := io.ReadFull(r.src, r.peek[:]); err == io.EOF {
+		r.final = true
+	} else if err != nil {
+		return err
+	} else {
+		r.peeked = true
+	}
+
+	sealed, err := r.fc.sealChunk(r.out[:0], r.chunk[:n], r.offset+r.written, r.final)
+	if err != nil {
+		return err
+	}
+	r.pending = sealed
+	r.written += int64(n)
+	return nil
+}
+
+// decryptingReader reads the contents of an encrypted file, decrypting the chunk that
+// holds the current position as needed.
+type decryptingReader struct {
+	r        ReadCloseSeeker
+	fc       *fileCipher
+	segments []encryptedSegment
+	size     int64
+	pos      int64
+
+	// chunkOffset is the offset in the contents of the decrypted chunk, if any.
+	chunkOffset int64
+	chunk       []byte
+	plaintext   []byte
+}
+
+func newDecryptingReader(r ReadCloseSeeker, fc *fileCipher) (*decryptingReader, error) {
+	segments, err := readEncryptedSegments(r, fc)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to read the segments of the file")
+	}
+
+	return &decryptingReader{
+		r:           r,
+		fc:          fc,
+		segments:    segments,
+		size:        segments[len(segments)-1].end(),
+		chunkOffset: -1,
+		chunk:       make([]byte, fc.chunkSize+encryptionChunkExtra),
+	}, nil
+}
+
+func (r *decryptingReader) Read(p []byte) (int, error) {
+	if r.pos >= r.size {
+		return 0, io.EOF
+	}
+
+	// Empty segments are skipped, since they hold no position.
+	segment := r.segments[sort.Search(len(r.segments), func(i int) bool {
+		return r.segments[i].end() > r.pos
+	})]
+	index := (r.pos - segment.offset) / r.fc.chunkSize
+	chunkOffset := segment.offset + index*r.fc.chunkSize
+	if chunkOffset != r.chunkOffset {
+		if err := r.loadChunk(segment, index); err != nil {
+			return 0, err
+		}
+	}
+
+	n := copy(p, r.plaintext[r.pos-chunkOffset:])
+	r.pos += int64(n)
+	return n, nil
+}
+
+func (r *decryptingReader) loadChunk(segment encryptedSegment, index int64) error {
+	offset := segment.storedOffset + index*(r.fc.chunkSize+encryptionChunkExtra)
+	length := min(r.fc.chunkSize, segment.size-index*r.fc.chunkSize) + encryptionChunkExtra
+	final := index == r.fc.segmentChunks(segment.size)-1
+
+	if _, err := r.r.Seek(offset, io.SeekStart); err != nil {
+		return err
+	}
+	if _, err := io.ReadFull(r.r, r.chunk[:length]); err != nil {
+		return err
+	}
+
+	chunkOffset := segment.offset + index*r.fc.chunkSize
+	plaintext, err := r.fc.openChunk(r.plaintext[:0], r.chunk[:length], chunkOffset, final)
+	if err != nil {
+		r.chunkOffset = -1
+		return err
+	}
+
+	r.plaintext = plaintext
+	r.chunkOffset = chunkOffset
+	return nil
+}
+
+func (r *decryptingReader) Seek(offset int64, whence int) (int64, error) {
+	var pos int64
+	switch whence {
+	case io.SeekStart:
+		pos = offset
+	case io.SeekCurrent:
+		pos = r.pos + offset
+	case io.SeekEnd:
+		pos = r.size + offset
+	default:
+		return 0, errors.New("invalid whence")
+	}
+
+	if pos < 0 {
+		return 0, errors.New("negative position")
+	}
+
+	r.pos = pos
+	return pos, nil
+}
+
+func (r *decryptingReader) Close() error {
+	return r.r.Close()
+}
+
+type countingReader struct {
+	r io.Reader
+	n int64
+}
+
+func (r *countingReader) Read(p []byte) (int, error) {
+	n, err := r.r.Read(p)
+	r.n += int64(n)
+	return n, err
+}
@@ -0,0 +1,304 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package filestore
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/binary"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestEncryptedBackend(t *testing.T, keys map[string][]byte, activeKeyId string) (*EncryptedFileBackend, string) {
+	t.Helper()
+
+	dir := t.TempDir()
+	wrapper, err := NewStaticKeyWrapper(keys, activeKeyId)
+	require.NoError(t, err)
+
+	return NewEncryptedFileBackend(&LocalFileBackend{directory: dir}, wrapper, false), dir
+}
+
+func randomBytes(t *testing.T, n int) []byte {
+	t.Helper()
+
+	data := make([]byte, n)
+	_, err := rand.Read(data)
+	require.NoError(t, err)
+	return data
+}
+
+func TestEncryptedFileBackend(t *testing.T) {
+	keys := map[string][]byte{
+		"old": bytes.Repeat([]byte{1}, encryptionKeySize),
+		"new": bytes.Repeat([]byte{2}, encryptionKeySize),
+	}
+
+	t.Run("contents are encrypted at rest", func(t *testing.T) {
+		backend, dir := newTestEncryptedBackend(t, keys, "old")
+		data := bytes.Repeat([]byte("secret "), 1000)
+
+		written, err := backend.WriteFile(bytes.NewReader(data), "file")
+		require.NoError(t, err)
+		assert.EqualValues(t, len(data), written)
+
+		raw, err := os.ReadFile(filepath.Join(dir, "file"))
+		require.NoError(t, err)
+		assert.NotContains(t, string(raw), "secret")
+
+		read, err := backend.ReadFile("file")
+		require.NoError(t, err)
+		assert.Equal(t, data, read)
+
+		size, err := backend.FileSize("file")
+		require.NoError(t, err)
+		assert.EqualValues(t, len(data), size)
+	})
+
+	t.Run("reader seeks across chunks", func(t *testing.T) {
+		backend, _ := newTestEncryptedBackend(t, keys, "old")
+		data := randomBytes(t, 3*encryptionChunkSize+123)
+
+		_, err := backend.WriteFile(bytes.NewReader(data), "file")
+		require.NoError(t, err)
+
+		r, err := backend.Reader("file")
+		require.NoError(t, err)
+		defer r.Close()
+
+		for _, offset := range []int64{encryptionChunkSize + 10, 5, 3 * encryptionChunkSize, encryptionChunkSize - 1} {
+			pos, err := r.Seek(offset, io.SeekStart)
+			require.NoError(t, err)
+			assert.Equal(t, offset, pos)
+
+			buf := make([]byte, 100)
+			n, err := io.ReadFull(r, buf)
+			require.NoError(t, err)
+			assert.Equal(t, data[offset:offset+int64(n)], buf[:n])
+		}
+
+		end, err := r.Seek(-10, io.SeekEnd)
+		require.NoError(t, err)
+		assert.EqualValues(t, len(data)-10, end)
+		rest, err := io.ReadAll(r)
+		require.NoError(t, err)
+		assert.Equal(t, data[len(data)-10:], rest)
+	})
+
+	t.Run("append on and off chunk boundaries", func(t *testing.T) {
+		backend, dir := newTestEncryptedBackend(t, keys, "old")
+		first := randomBytes(t, encryptionChunkSize)
+		second := randomBytes(t, 1000)
+		third := randomBytes(t, encryptionChunkSize)
+
+		_, err := backend.WriteFile(bytes.NewReader(first), "file")
+		require.NoError(t, err)
+		before, err := os.ReadFile(filepath.Join(dir, "file"))
+		require.NoError(t, err)
+
+		written, err := backend.AppendFile(bytes.NewReader(second), "file")
+		require.NoError(t, err)
+		assert.EqualValues(t, len(second), written)
+
+		written, err = backend.AppendFile(bytes.NewReader(third), "file")
+		require.NoError(t, err)
+		assert.EqualValues(t, len(third), written)
+
+		// Appending doesn't rewrite the existing contents.
+		after, err := os.ReadFile(filepath.Join(dir, "file"))
+		require.NoError(t, err)
+		assert.Equal(t, before, after[:len(before)])
+
+		data := append(append(first, second...), third...)
+		read, err := backend.ReadFile("file")
+		require.NoError(t, err)
+		assert.Equal(t, data, read)
+
+		size, err := backend.FileSize("file")
+		require.NoError(t, err)
+		assert.EqualValues(t, len(data), size)
+
+		r, err := backend.Reader("file")
+		require.NoError(t, err)
+		defer r.Close()
+		_, err = r.Seek(encryptionChunkSize+900, io.SeekStart)
+		require.NoError(t, err)
+		buf := make([]byte, 200)
+		_, err = io.ReadFull(r, buf)
+		require.NoError(t, err)
+		assert.Equal(t, data[encryptionChunkSize+900:encryptionChunkSize+1100], buf)
+
+		files, err := backend.ListDirectory("")
+		require.NoError(t, err)
+		assert.Equal(t, []string{"file"}, files, "no temporary files should be left behind")
+	})
+
+	t.Run("copied and moved files stay readable", func(t *testing.T) {
+		backend, _ := newTestEncryptedBackend(t, keys, "old")
+		data := []byte("contents")
+
+		_, err := backend.WriteFile(bytes.NewReader(data), "a/file")
+		require.NoError(t, err)
+		require.NoError(t, backend.CopyFile("a/file", "b/file"))
+		require.NoError(t, backend.MoveFile("a/file", "c/file"))
+
+		for _, path := range []string{"b/file", "c/file"} {
+			read, err := backend.ReadFile(path)
+			require.NoError(t, err)
+			assert.Equal(t, data, read)
+		}
+	})
+
+	t.Run("files written before encryption are read in migration mode and encrypted in place", func(t *testing.T) {
+		backend, dir := newTestEncryptedBackend(t, keys, "old")
+		data := []byte("written before encryption was enabled")
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "file"), data, 0600))
+
+		_, err := backend.ReadFile("file")
+		require.Error(t, err)
+		_, err = backend.AppendFile(bytes.NewReader(data), "file")
+		require.Error(t, err)
+
+		migrating := NewEncryptedFileBackend(backend.Unwrap(), backend.keys, true)
+		read, err := migrating.ReadFile("file")
+		require.NoError(t, err)
+		assert.Equal(t, data, read)
+
+		encrypted, err := backend.EncryptFile("file")
+		require.NoError(t, err)
+		assert.True(t, encrypted)
+
+		raw, err := os.ReadFile(filepath.Join(dir, "file"))
+		require.NoError(t, err)
+		assert.True(t, bytes.HasPrefix(raw, []byte(encryptionMagic)))
+
+		read, err = backend.ReadFile("file")
+		require.NoError(t, err)
+		assert.Equal(t, data, read)
+
+		encrypted, err = backend.EncryptFile("file")
+		require.NoError(t, err)
+		assert.False(t, encrypted)
+	})
+
+	t.Run("key rotation rewraps the data key", func(t *testing.T) {
+		backend, dir := newTestEncryptedBackend(t, keys, "old")
+		data := randomBytes(t, 2*encryptionChunkSize+5)
+		_, err := backend.WriteFile(bytes.NewReader(data), "file")
+		require.NoError(t, err)
+
+		rotated, err := NewStaticKeyWrapper(keys, "new")
+		require.NoError(t, err)
+		backend = NewEncryptedFileBackend(backend.Unwrap(), rotated, false)
+
+		encrypted, err := backend.EncryptFile("file")
+		require.NoError(t, err)
+		assert.True(t, encrypted)
+
+		// The file can no longer be read without the new key.
+		onlyOld, err := NewStaticKeyWrapper(map[string][]byte{"old": keys["old"]}, "old")
+		require.NoError(t, err)
+		_, err = NewEncryptedFileBackend(&LocalFileBackend{directory: dir}, onlyOld, false).ReadFile("file")
+		require.Error(t, err)
+
+		read, err := backend.ReadFile("file")
+		require.NoError(t, err)
+		assert.Equal(t, data, read)
+	})
+
+	t.Run("tampered contents are detected", func(t *testing.T) {
+		backend, dir := newTestEncryptedBackend(t, keys, "old")
+		_, err := backend.WriteFile(bytes.NewReader([]byte("contents")), "file")
+		require.NoError(t, err)
+
+		path := filepath.Join(dir, "file")
+		raw, err := os.ReadFile(path)
+		require.NoError(t, err)
+		raw[len(raw)-1] ^= 1
+		require.NoError(t, os.WriteFile(path, raw, 0600))
+
+		_, err = backend.ReadFile("file")
+		require.Error(t, err)
+	})
+
+	t.Run("truncated contents are detected", func(t *testing.T) {
+		backend, dir := newTestEncryptedBackend(t, keys, "old")
+		data := randomBytes(t, 2*encryptionChunkSize)
+		_, err := backend.WriteFile(bytes.NewReader(data), "file")
+		require.NoError(t, err)
+
+		path := filepath.Join(dir, "file")
+		raw, err := os.ReadFile(path)
+		require.NoError(t, err)
+
+		header, err := readEncryptionHeader(bytes.NewReader(raw))
+		require.NoError(t, err)
+		chunkEnd := int(header.size) + encryptionChunkSize + encryptionChunkExtra
+		for _, size := range []int{chunkEnd, chunkEnd + encryptionTrailerSize, len(raw) - 1, int(header.size)} {
+			require.NoError(t, os.WriteFile(path, raw[:size], 0600))
+			_, err = backend.ReadFile("file")
+			require.Error(t, err, size)
+		}
+
+		// Cutting the file at a chunk boundary and fixing the size of the segment is detected,
+		// since the chunks that don't end their segment are sealed as such.
+		truncated := binary.BigEndian.AppendUint64(raw[:chunkEnd:chunkEnd], encryptionChunkSize)
+		require.NoError(t, os.WriteFile(path, truncated, 0600))
+		_, err = backend.ReadFile("file")
+		require.Error(t, err)
+	})
+
+	t.Run("empty files", func(t *testing.T) {
+		backend, _ := newTestEncryptedBackend(t, keys, "old")
+		_, err := backend.WriteFile(bytes.NewReader(nil), "file")
+		require.NoError(t, err)
+
+		read, err := backend.ReadFile("file")
+		require.NoError(t, err)
+		assert.Empty(t, read)
+
+		_, err = backend.AppendFile(bytes.NewReader([]byte("appended")), "file")
+		require.NoError(t, err)
+		_, err = backend.AppendFile(bytes.NewReader(nil), "file")
+		require.NoError(t, err)
+		read, err = backend.ReadFile("file")
+		require.NoError(t, err)
+		assert.Equal(t, []byte("appended"), read)
+	})
+}
+
+func TestLocalKMS(t *testing.T) {
+	dir := t.TempDir()
+
+	kms, err := NewLocalKMS(dir, "key1")
+	require.NoError(t, err)
+
+	info, err := os.Stat(filepath.Join(dir, "key1.key"))
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+
+	dataKey := bytes.Repeat([]byte{7}, encryptionKeySize)
+	keyId, wrapped, err := kms.WrapKey(dataKey)
+	require.NoError(t, err)
+	assert.Equal(t, "key1", keyId)
+
+	// A new instance, such as another server, uses the same key.
+	other, err := NewLocalKMS(dir, "key2")
+	require.NoError(t, err)
+	unwrapped, err := other.UnwrapKey(keyId, wrapped)
+	require.NoError(t, err)
+	assert.Equal(t, dataKey, unwrapped)
+
+	_, err = other.UnwrapKey("missing", wrapped)
+	require.Error(t, err)
+
+	_, err = NewLocalKMS(dir, "../key")
+	require.Error(t, err)
+}
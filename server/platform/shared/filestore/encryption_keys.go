@@ -0,0 +1,226 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package filestore
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+const encryptionKeySize = 32
+
+// KeyWrapper encrypts and decrypts the per-file data keys with a key-encryption key.
+// Keys are identified by an id that is stored next to each wrapped data key, so that
+// files remain readable after the active key is rotated.
+type KeyWrapper interface {
+	ActiveKeyId() string
+	WrapKey(dataKey []byte) (keyId string, wrapped []byte, err error)
+	UnwrapKey(keyId string, wrapped []byte) ([]byte, error)
+}
+
+// StaticKeyWrapper wraps data keys with a fixed set of key-encryption keys, such as the
+// ones given in the config.
+type StaticKeyWrapper struct {
+	keys        map[string][]byte
+	activeKeyId string
+}
+
+func NewStaticKeyWrapper(keys map[string][]byte, activeKeyId string) (*StaticKeyWrapper, error) {
+	for id, key := range keys {
+		if len(key) != encryptionKeySize {
+			return nil, errors.Errorf("key-encryption key %s must be %d bytes long", id, encryptionKeySize)
+		}
+	}
+	if _, ok := keys[activeKeyId]; !ok {
+		return nil, errors.Errorf("active key-encryption key %s not found", activeKeyId)
+	}
+
+	return &StaticKeyWrapper{keys: keys, activeKeyId: activeKeyId}, nil
+}
+
+func (w *StaticKeyWrapper) ActiveKeyId() string {
+	return w.activeKeyId
+}
+
+func (w *StaticKeyWrapper) WrapKey(dataKey []byte) (string, []byte, error) {
+	wrapped, err := wrapKey(w.keys[w.activeKeyId], w.activeKeyId, dataKey)
+	if err != nil {
+		return "", nil, err
+	}
+	return w.activeKeyId, wrapped, nil
+}
+
+func (w *StaticKeyWrapper) UnwrapKey(keyId string, wrapped []byte) ([]byte, error) {
+	kek, ok := w.keys[keyId]
+	if !ok {
+		return nil, errors.Errorf("key-encryption key %s not found", keyId)
+	}
+	return unwrapKey(kek, keyId, wrapped)
+}
+
+// LocalKMS is a stand-in for a key management service that keeps the key-encryption keys
+// in files in a local directory, one <id>.key file per key. The active key is generated
+// the first time it is needed.
+type LocalKMS struct {
+	directory   string
+	activeKeyId string
+
+	mut  sync.Mutex
+	keys map[string][]byte
+}
+
+func NewLocalKMS(directory, activeKeyId string) (*LocalKMS, error) {
+	kms := &LocalKMS{
+		directory:   directory,
+		activeKeyId: activeKeyId,
+		keys:        map[string][]byte{},
+	}
+
+	if _, err := kms.getKey(activeKeyId, true); err != nil {
+		return nil, err
+	}
+
+	return kms, nil
+}
+
+func (k *LocalKMS) ActiveKeyId() string {
+	return k.activeKeyId
+}
+
+func (k *LocalKMS) WrapKey(dataKey []byte) (string, []byte, error) {
+	kek, err := k.getKey(k.activeKeyId, true)
+	if err != nil {
+		return "", nil, err
+	}
+
+	wrapped, err := wrapKey(kek, k.activeKeyId, dataKey)
+	if err != nil {
+		return "", nil, err
+	}
+	return k.activeKeyId, wrapped, nil
+}
+
+func (k *LocalKMS) UnwrapKey(keyId string, wrapped []byte) ([]byte, error) {
+	kek, err := k.getKey(keyId, false)
+	if err != nil {
+		return nil, err
+	}
+	return unwrapKey(kek, keyId, wrapped)
+}
+
+func (k *LocalKMS) getKey(keyId string, create bool) ([]byte, error) {
+	if keyId == "" || strings.ContainsAny(keyId, `/\`) || keyId == "." || keyId == ".." {
+		return nil, errors.Errorf("invalid key-encryption key id %q", keyId)
+	}
+
+	k.mut.Lock()
+	defer k.mut.Unlock()
+
+	if key, ok := k.keys[keyId]; ok {
+		return key, nil
+	}
+
+	keyPath := filepath.Join(k.directory, keyId+".key")
+	key, err := readKeyFile(keyId, keyPath)
+	if os.IsNotExist(errors.Cause(err)) && create {
+		key, err = createKeyFile(k.directory, keyId, keyPath)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	k.keys[keyId] = key
+	return key, nil
+}
+
+func readKeyFile(keyId, keyPath string) ([]byte, error) {
+	data, err := os.ReadFile(keyPath)
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to read key-encryption key %s", keyId)
+	}
+
+	key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(data)))
+	if err != nil || len(key) != encryptionKeySize {
+		return nil, errors.Errorf("key-encryption key %s is malformed", keyId)
+	}
+	return key, nil
+}
+
+func createKeyFile(directory, keyId, keyPath string) ([]byte, error) {
+	key := make([]byte, encryptionKeySize)
+	if _, err := rand.Read(key); err != nil {
+		return nil, errors.Wrap(err, "unable to generate key-encryption key")
+	}
+
+	if err := os.MkdirAll(directory, 0700); err != nil {
+		return nil, errors.Wrapf(err, "unable to create key directory %s", directory)
+	}
+
+	// O_EXCL so that servers sharing the directory never overwrite each other's key.
+	f, err := os.OpenFile(keyPath, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+	if os.IsExist(err) {
+		return readKeyFile(keyId, keyPath)
+	} else if err != nil {
+		return nil, errors.Wrapf(err, "unable to create key-encryption key %s", keyId)
+	}
+	defer f.Close()
+
+	if _, err := f.WriteString(base64.StdEncoding.EncodeToString(key)); err != nil {
+		return nil, errors.Wrapf(err, "unable to write key-encryption key %s", keyId)
+	}
+
+	return key, nil
+}
+
+// wrapKey encrypts a data key with AES-GCM, binding it to the id of the key-encryption key.
+func wrapKey(kek []byte, keyId string, dataKey []byte) ([]byte, error) {
+	aead, err := newAEAD(kek)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, errors.Wrap(err, "unable to generate nonce")
+	}
+
+	return aead.Seal(nonce, nonce, dataKey, []byte(keyId)), nil
+}
+
+func unwrapKey(kek []byte, keyId string, wrapped []byte) ([]byte, error) {
+	aead, err := newAEAD(kek)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(wrapped) < aead.NonceSize() {
+		return nil, errors.New("wrapped data key is too short")
+	}
+
+	dataKey, err := aead.Open(nil, wrapped[:aead.NonceSize()], wrapped[aead.NonceSize():], []byte(keyId))
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to unwrap data key with key-encryption key %s", keyId)
+	}
+	return dataKey, nil
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to create cipher")
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to create cipher")
+	}
+	return aead, nil
+}
@@ -15,6 +15,8 @@ import (
 const (
 	driverS3    = "amazons3"
 	driverLocal = "local"
+	driverAzure = "azureblob"
+	driverGCS   = "gcs"
 )
 
 type ReadCloseSeeker interface {
@@ -46,6 +48,18 @@ type FileBackendWithLinkGenerator interface {
 	GeneratePublicLink(path string) (string, time.Duration, error)
 }
 
+// UnwrapFileBackend returns the backend that files are stored in, looking through any
+// wrapping backend such as the EncryptedFileBackend.
+func UnwrapFileBackend(backend FileBackend) FileBackend {
+	for {
+		wrapper, ok := backend.(interface{ Unwrap() FileBackend })
+		if !ok {
+			return backend
+		}
+		backend = wrapper.Unwrap()
+	}
+}
+
 type FileBackendSettings struct {
 	DriverName                         string
 	Directory                          string
@@ -63,14 +77,81 @@ type FileBackendSettings struct {
 	AmazonS3RequestTimeoutMilliseconds int64
 	AmazonS3PresignExpiresSeconds      int64
 	AmazonS3UploadPartSizeBytes        int64
+
+	AzureAccountName                string
+	AzureAccountKey                 string
+	AzureContainer                  string
+	AzurePathPrefix                 string
+	AzureEndpoint                   string
+	AzureRequestTimeoutMilliseconds int64
+	AzureSignedURLExpiresSeconds    int64
+
+	GCSBucket                     string
+	GCSPathPrefix                 string
+	GCSServiceAccountKey          string
+	GCSEndpoint                   string
+	GCSRequestTimeoutMilliseconds int64
+	GCSSignedURLExpiresSeconds    int64
+
+	CacheEnabled      bool
+	CacheDirectory    string
+	CacheMaxSizeBytes int64
+
+	EncryptionEnabled           bool
+	EncryptionKeyProvider       string
+	EncryptionKeys              map[string][]byte
+	EncryptionActiveKeyId       string
+	EncryptionLocalKMSDirectory string
+	EncryptionMigrationMode     bool
 }
 
 func NewFileBackendSettingsFromConfig(fileSettings *model.FileSettings, enableComplianceFeature bool, skipVerify bool) FileBackendSettings {
-	if *fileSettings.DriverName == model.ImageDriverLocal {
+	settings := newFileBackendSettingsFromConfig(fileSettings, enableComplianceFeature, skipVerify)
+
+	if fileSettings.EncryptionEnabled != nil && *fileSettings.EncryptionEnabled {
+		// The config is validated before it's loaded, so the keys can be parsed.
+		keys, _ := fileSettings.GetEncryptionKeys()
+		settings.EncryptionEnabled = true
+		settings.EncryptionKeyProvider = *fileSettings.EncryptionKeyProvider
+		settings.EncryptionKeys = keys
+		settings.EncryptionActiveKeyId = *fileSettings.EncryptionActiveKeyId
+		settings.EncryptionLocalKMSDirectory = *fileSettings.EncryptionLocalKMSDirectory
+		settings.EncryptionMigrationMode = fileSettings.EncryptionMigrationMode != nil && *fileSettings.EncryptionMigrationMode
+	}
+
+	return settings
+}
+
+func newFileBackendSettingsFromConfig(fileSettings *model.FileSettings, enableComplianceFeature bool, skipVerify bool) FileBackendSettings {
+	switch *fileSettings.DriverName {
+	case model.ImageDriverLocal:
 		return FileBackendSettings{
 			DriverName: *fileSettings.DriverName,
 			Directory:  *fileSettings.Directory,
 		}
+	case model.ImageDriverAzure:
+		return FileBackendSettings{
+			DriverName:                      *fileSettings.DriverName,
+			AzureAccountName:                *fileSettings.AzureAccountName,
+			AzureAccountKey:                 *fileSettings.AzureAccountKey,
+			AzureContainer:                  *fileSettings.AzureContainer,
+			AzurePathPrefix:                 *fileSettings.AzurePathPrefix,
+			AzureEndpoint:                   *fileSettings.AzureEndpoint,
+			AzureRequestTimeoutMilliseconds: *fileSettings.AzureRequestTimeoutMilliseconds,
+			AzureSignedURLExpiresSeconds:    *fileSettings.AzureSignedURLExpiresSeconds,
+			SkipVerify:                      skipVerify,
+		}
+	case model.ImageDriverGCS:
+		return FileBackendSettings{
+			DriverName:                    *fileSettings.DriverName,
+			GCSBucket:                     *fileSettings.GCSBucket,
+			GCSPathPrefix:                 *fileSettings.GCSPathPrefix,
+			GCSServiceAccountKey:          *fileSettings.GCSServiceAccountKey,
+			GCSEndpoint:                   *fileSettings.GCSEndpoint,
+			GCSRequestTimeoutMilliseconds: *fileSettings.GCSRequestTimeoutMilliseconds,
+			GCSSignedURLExpiresSeconds:    *fileSettings.GCSSignedURLExpiresSeconds,
+			SkipVerify:                    skipVerify,
+		}
 	}
 	return FileBackendSettings{
 		DriverName:                         *fileSettings.DriverName,
@@ -117,6 +198,19 @@ func NewExportFileBackendSettingsFromConfig(fileSettings *model.FileSettings, en
 }
 
 func (settings *FileBackendSettings) CheckMandatoryS3Fields() error {
+	switch settings.DriverName {
+	case driverAzure:
+		if settings.AzureAccountName == "" || settings.AzureContainer == "" {
+			return errors.New("missing azure container settings")
+		}
+		return nil
+	case driverGCS:
+		if settings.GCSBucket == "" {
+			return errors.New("missing gcs bucket settings")
+		}
+		return nil
+	}
+
 	if settings.AmazonS3Bucket == "" {
 		return errors.New("missing s3 bucket settings")
 	}
@@ -140,6 +234,40 @@ func NewExportFileBackend(settings FileBackendSettings) (FileBackend, error) {
 }
 
 func newFileBackend(settings FileBackendSettings, canBeCloud bool) (FileBackend, error) {
+	backend, err := newDriverFileBackend(settings, canBeCloud)
+	if err != nil {
+		return nil, err
+	}
+
+	if settings.CacheEnabled {
+		backend, err = NewCachedFileBackend(backend, settings.CacheDirectory, settings.CacheMaxSizeBytes)
+		if err != nil {
+			return nil, errors.Wrap(err, "unable to create the file cache")
+		}
+	}
+
+	if !settings.EncryptionEnabled {
+		return backend, nil
+	}
+
+	keys, err := newKeyWrapper(settings)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to load the file encryption keys")
+	}
+	return NewEncryptedFileBackend(backend, keys, settings.EncryptionMigrationMode), nil
+}
+
+func newKeyWrapper(settings FileBackendSettings) (KeyWrapper, error) {
+	switch settings.EncryptionKeyProvider {
+	case model.FileEncryptionKeyProviderConfig:
+		return NewStaticKeyWrapper(settings.EncryptionKeys, settings.EncryptionActiveKeyId)
+	case model.FileEncryptionKeyProviderLocalKMS:
+		return NewLocalKMS(settings.EncryptionLocalKMSDirectory, settings.EncryptionActiveKeyId)
+	}
+	return nil, errors.Errorf("unknown key provider %q", settings.EncryptionKeyProvider)
+}
+
+func newDriverFileBackend(settings FileBackendSettings, canBeCloud bool) (FileBackend, error) {
 	switch settings.DriverName {
 	case driverS3:
 		newBackendFn := NewS3FileBackend
@@ -151,6 +279,18 @@ func newFileBackend(settings FileBackendSettings, canBeCloud bool) (FileBackend,
 			return nil, errors.Wrap(err, "unable to connect to the s3 backend")
 		}
 		return backend, nil
+	case driverAzure:
+		backend, err := NewAzureFileBackend(settings)
+		if err != nil {
+			return nil, errors.Wrap(err, "unable to connect to the azure backend")
+		}
+		return backend, nil
+	case driverGCS:
+		backend, err := NewGCSFileBackend(settings)
+		if err != nil {
+			return nil, errors.Wrap(err, "unable to connect to the gcs backend")
+		}
+		return backend, nil
 	case driverLocal:
 		return &LocalFileBackend{
 			directory: settings.Directory,
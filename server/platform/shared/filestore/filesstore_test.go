@@ -11,6 +11,7 @@ import (
 	"math"
 	"math/rand"
 	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 	"time"
@@ -53,6 +54,39 @@ func TestLocalFileBackendTestSuite(t *testing.T) {
 	})
 }
 
+func TestEncryptedLocalFileBackendTestSuite(t *testing.T) {
+	dir, err := os.MkdirTemp("", "")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	suite.Run(t, &FileBackendTestSuite{
+		settings: FileBackendSettings{
+			DriverName:            driverLocal,
+			Directory:             dir,
+			EncryptionEnabled:     true,
+			EncryptionKeyProvider: model.FileEncryptionKeyProviderConfig,
+			EncryptionKeys:        map[string][]byte{"key1": bytes.Repeat([]byte{1}, encryptionKeySize)},
+			EncryptionActiveKeyId: "key1",
+		},
+	})
+}
+
+func TestCachedLocalFileBackendTestSuite(t *testing.T) {
+	dir, err := os.MkdirTemp("", "")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	suite.Run(t, &FileBackendTestSuite{
+		settings: FileBackendSettings{
+			DriverName:        driverLocal,
+			Directory:         filepath.Join(dir, "data"),
+			CacheEnabled:      true,
+			CacheDirectory:    filepath.Join(dir, "cache"),
+			CacheMaxSizeBytes: 1024 * 1024,
+		},
+	})
+}
+
 func TestS3FileBackendTestSuite(t *testing.T) {
 	runBackendTest(t, false)
 }
@@ -90,11 +124,68 @@ func runBackendTest(t *testing.T, encrypt bool) {
 	})
 }
 
+// azuriteAccountKey is the well-known key of the account of the Azurite emulator.
+const azuriteAccountKey = "Eby8vdM02xNOcqFlqUwJPLlmEtlCDXJ1OUzFT50uSRZ6IFsuFq2UVErCz4I6tq/K1SZFPTOtr/KBHBeksoGMGw=="
+
+func TestAzureFileBackendTestSuite(t *testing.T) {
+	host := os.Getenv("CI_AZURITE_HOST")
+	if host == "" {
+		host = "localhost"
+	}
+
+	port := os.Getenv("CI_AZURITE_PORT")
+	if port == "" {
+		port = "10000"
+	}
+
+	suite.Run(t, &FileBackendTestSuite{
+		settings: FileBackendSettings{
+			DriverName:                      driverAzure,
+			AzureAccountName:                "devstoreaccount1",
+			AzureAccountKey:                 azuriteAccountKey,
+			AzureContainer:                  "mattermost-test",
+			AzureEndpoint:                   fmt.Sprintf("http://%s:%s/devstoreaccount1", host, port),
+			AzureRequestTimeoutMilliseconds: 5000,
+			AzureSignedURLExpiresSeconds:    60,
+		},
+	})
+}
+
+func TestGCSFileBackendTestSuite(t *testing.T) {
+	host := os.Getenv("CI_FAKE_GCS_HOST")
+	if host == "" {
+		host = "localhost"
+	}
+
+	port := os.Getenv("CI_FAKE_GCS_PORT")
+	if port == "" {
+		port = "4443"
+	}
+
+	suite.Run(t, &FileBackendTestSuite{
+		settings: FileBackendSettings{
+			DriverName:                    driverGCS,
+			GCSBucket:                     "mattermost-test",
+			GCSEndpoint:                   fmt.Sprintf("http://%s:%s", host, port),
+			GCSRequestTimeoutMilliseconds: 5000,
+			GCSSignedURLExpiresSeconds:    60,
+		},
+	})
+}
+
 func (s *FileBackendTestSuite) SetupTest() {
 	backend, err := NewFileBackend(s.settings)
 	require.NoError(s.T(), err)
 	s.backend = backend
 
+	// The emulators start without any container or bucket.
+	switch emulated := backend.(type) {
+	case *AzureFileBackend:
+		s.NoError(emulated.MakeContainer())
+	case *GCSFileBackend:
+		s.NoError(emulated.MakeBucket())
+	}
+
 	// This is needed to create the bucket if it doesn't exist.
 	err = s.backend.TestConnection()
 	if _, ok := err.(*S3FileBackendNoBucketError); ok {
@@ -0,0 +1,755 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package filestore
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/pkg/errors"
+
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+)
+
+const (
+	gcsDefaultEndpoint = "https://storage.googleapis.com"
+	gcsDefaultTokenURI = "https://oauth2.googleapis.com/token"
+	gcsTokenScope      = "https://www.googleapis.com/auth/devstorage.read_write"
+	// Chunks of resumable uploads must be a multiple of 256KiB.
+	gcsUploadChunkSize = 32 * 256 * 1024
+	gcsListPageSize    = 1000
+	// Signed URLs can't be valid for more than 7 days.
+	gcsSignedURLMaxExpires = 7 * 24 * time.Hour
+)
+
+// GCSFileBackend stores files as objects in a Google Cloud Storage bucket, talking to
+// the JSON API with the OAuth 2.0 tokens of a service account.
+type GCSFileBackend struct {
+	bucket           string
+	pathPrefix       string
+	endpoint         string
+	emulated         bool
+	key              *gcsServiceAccountKey
+	privateKey       *rsa.PrivateKey
+	client           *http.Client
+	timeout          time.Duration
+	signedURLExpires time.Duration
+
+	tokenMut    sync.Mutex
+	token       string
+	tokenExpiry time.Time
+}
+
+// GCSFileBackendError is returned when a request to the JSON API fails.
+type GCSFileBackendError struct {
+	StatusCode int
+	Message    string
+}
+
+type gcsServiceAccountKey struct {
+	ProjectID    string `json:"project_id"`
+	PrivateKeyID string `json:"private_key_id"`
+	PrivateKey   string `json:"private_key"`
+	ClientEmail  string `json:"client_email"`
+	TokenURI     string `json:"token_uri"`
+}
+
+type gcsObject struct {
+	Name    string    `json:"name"`
+	Size    int64     `json:"size,string"`
+	Updated time.Time `json:"updated"`
+}
+
+var _ FileBackendWithLinkGenerator = (*GCSFileBackend)(nil)
+
+func (e *GCSFileBackendError) Error() string {
+	return fmt.Sprintf("google cloud storage request failed with status %d: %s", e.StatusCode, e.Message)
+}
+
+func isGCSStatus(err error, statusCode int) bool {
+	var gcsErr *GCSFileBackendError
+	return errors.As(err, &gcsErr) && gcsErr.StatusCode == statusCode
+}
+
+// NewGCSFileBackend returns an instance of a GCSFileBackend. Requests are not authorized
+// when no service account key is given, which is only expected with an emulator.
+func NewGCSFileBackend(settings FileBackendSettings) (*GCSFileBackend, error) {
+	backend := &GCSFileBackend{
+		bucket:           settings.GCSBucket,
+		pathPrefix:       settings.GCSPathPrefix,
+		endpoint:         strings.TrimSuffix(settings.GCSEndpoint, "/"),
+		emulated:         settings.GCSEndpoint != "",
+		client:           newHTTPBackendClient(settings.SkipVerify),
+		timeout:          time.Duration(settings.GCSRequestTimeoutMilliseconds) * time.Millisecond,
+		signedURLExpires: time.Duration(settings.GCSSignedURLExpiresSeconds) * time.Second,
+	}
+	if backend.endpoint == "" {
+		backend.endpoint = gcsDefaultEndpoint
+	}
+	if backend.signedURLExpires > gcsSignedURLMaxExpires {
+		backend.signedURLExpires = gcsSignedURLMaxExpires
+	}
+
+	if settings.GCSServiceAccountKey != "" {
+		var key gcsServiceAccountKey
+		if err := json.Unmarshal([]byte(settings.GCSServiceAccountKey), &key); err != nil {
+			return nil, errors.Wrap(err, "unable to parse the gcs service account key")
+		}
+		privateKey, err := jwt.ParseRSAPrivateKeyFromPEM([]byte(key.PrivateKey))
+		if err != nil {
+			return nil, errors.Wrap(err, "unable to parse the private key of the gcs service account")
+		}
+		if key.TokenURI == "" {
+			key.TokenURI = gcsDefaultTokenURI
+		}
+		backend.key = &key
+		backend.privateKey = privateKey
+	}
+
+	return backend, nil
+}
+
+func (b *GCSFileBackend) DriverName() string {
+	return driverGCS
+}
+
+func (b *GCSFileBackend) objectName(path string) string {
+	return filepath.Join(b.pathPrefix, path)
+}
+
+func (b *GCSFileBackend) bucketURL(query url.Values) string {
+	return b.endpoint + "/storage/v1/b/" + url.PathEscape(b.bucket) + "?" + query.Encode()
+}
+
+func (b *GCSFileBackend) objectURL(object, suffix string, query url.Values) string {
+	return b.endpoint + "/storage/v1/b/" + url.PathEscape(b.bucket) + "/o/" + url.PathEscape(object) + suffix + "?" + query.Encode()
+}
+
+func (b *GCSFileBackend) uploadURL(query url.Values) string {
+	return b.endpoint + "/upload/storage/v1/b/" + url.PathEscape(b.bucket) + "/o?" + query.Encode()
+}
+
+// accessToken returns an OAuth 2.0 token of the service account, exchanging a signed JWT
+// for a new token when the previous one expires.
+func (b *GCSFileBackend) accessToken(ctx context.Context) (string, error) {
+	if b.key == nil {
+		return "", nil
+	}
+
+	b.tokenMut.Lock()
+	defer b.tokenMut.Unlock()
+	if b.token != "" && time.Now().Add(time.Minute).Before(b.tokenExpiry) {
+		return b.token, nil
+	}
+
+	now := time.Now()
+	assertion := jwt.NewWithClaims(jwt.SigningMethodRS256, jwt.MapClaims{
+		"iss":   b.key.ClientEmail,
+		"scope": gcsTokenScope,
+		"aud":   b.key.TokenURI,
+		"iat":   now.Unix(),
+		"exp":   now.Add(time.Hour).Unix(),
+	})
+	assertion.Header["kid"] = b.key.PrivateKeyID
+	signed, err := assertion.SignedString(b.privateKey)
+	if err != nil {
+		return "", errors.Wrap(err, "unable to sign the token request")
+	}
+
+	form := url.Values{
+		"grant_type": {"urn:ietf:params:oauth:grant-type:jwt-bearer"},
+		"assertion":  {signed},
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, b.key.TokenURI, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	resp, err := b.client.Do(req)
+	if err != nil {
+		return "", errors.Wrap(err, "unable to request an access token")
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return "", errors.Errorf("unable to request an access token: status %d: %s", resp.StatusCode, body)
+	}
+
+	var token struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int64  `json:"expires_in"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&token); err != nil {
+		return "", errors.Wrap(err, "unable to decode the access token")
+	}
+
+	b.token = token.AccessToken
+	b.tokenExpiry = now.Add(time.Duration(token.ExpiresIn) * time.Second)
+	return b.token, nil
+}
+
+// do sends an authorized request to the JSON API. Responses with an error status are
+// returned as a GCSFileBackendError, otherwise the caller must close the body.
+func (b *GCSFileBackend) do(ctx context.Context, method, u string, header http.Header, body []byte) (*http.Response, error) {
+	token, err := b.accessToken(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var bodyReader io.Reader
+	if body != nil {
+		bodyReader = bytes.NewReader(body)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, u, bodyReader)
+	if err != nil {
+		return nil, err
+	}
+	for name, values := range header {
+		req.Header[name] = values
+	}
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	resp, err := b.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	// Resumable uploads answer with a 308 status until the last chunk is received.
+	if resp.StatusCode >= http.StatusMultipleChoices && resp.StatusCode != http.StatusPermanentRedirect {
+		defer resp.Body.Close()
+		var errResp struct {
+			Error struct {
+				Message string `json:"message"`
+			} `json:"error"`
+		}
+		// Errors in the body are best effort, the body of HEAD requests being empty.
+		_ = json.NewDecoder(resp.Body).Decode(&errResp)
+		return nil, &GCSFileBackendError{StatusCode: resp.StatusCode, Message: errResp.Error.Message}
+	}
+	return resp, nil
+}
+
+// doJSON sends a request to the JSON API, decoding the response body into the result if any.
+func (b *GCSFileBackend) doJSON(ctx context.Context, method, u string, request, result any) error {
+	var (
+		header http.Header
+		body   []byte
+	)
+	if request != nil {
+		var err error
+		if body, err = json.Marshal(request); err != nil {
+			return err
+		}
+		header = http.Header{"Content-Type": {"application/json"}}
+	}
+
+	resp, err := b.do(ctx, method, u, header, body)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if result == nil {
+		_, err = io.Copy(io.Discard, resp.Body)
+		return err
+	}
+	return json.NewDecoder(resp.Body).Decode(result)
+}
+
+func (b *GCSFileBackend) TestConnection() error {
+	ctx, cancel := context.WithTimeout(context.Background(), b.timeout)
+	defer cancel()
+
+	// If a path prefix is present, we list the objects under the path since the service
+	// account might only be allowed to access the path prefix.
+	var err error
+	if b.pathPrefix != "" {
+		err = b.doJSON(ctx, http.MethodGet, b.objectListURL(url.Values{"prefix": {b.pathPrefix}, "maxResults": {"1"}}), nil, nil)
+	} else {
+		err = b.doJSON(ctx, http.MethodGet, b.bucketURL(url.Values{}), nil, nil)
+	}
+	if err != nil {
+		if isGCSStatus(err, http.StatusNotFound) {
+			return errors.Errorf("the gcs bucket %s does not exist", b.bucket)
+		}
+		return errors.Wrap(err, "unable to check if the gcs bucket exists")
+	}
+	mlog.Debug("Connection to Google Cloud Storage is good. Bucket exists.")
+	return nil
+}
+
+// MakeBucket creates the bucket of the backend in the project of the service account,
+// unless it already exists.
+func (b *GCSFileBackend) MakeBucket() error {
+	ctx, cancel := context.WithTimeout(context.Background(), b.timeout)
+	defer cancel()
+
+	query := url.Values{}
+	if b.key != nil {
+		query.Set("project", b.key.ProjectID)
+	}
+	u := b.endpoint + "/storage/v1/b?" + query.Encode()
+	if err := b.doJSON(ctx, http.MethodPost, u, map[string]string{"name": b.bucket}, nil); err != nil && !isGCSStatus(err, http.StatusConflict) {
+		return errors.Wrap(err, "unable to create the gcs bucket")
+	}
+	return nil
+}
+
+func (b *GCSFileBackend) object(path string) (*gcsObject, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), b.timeout)
+	defer cancel()
+
+	var object gcsObject
+	if err := b.doJSON(ctx, http.MethodGet, b.objectURL(b.objectName(path), "", url.Values{}), nil, &object); err != nil {
+		return nil, err
+	}
+	return &object, nil
+}
+
+// Caller must close the first return value
+func (b *GCSFileBackend) Reader(path string) (ReadCloseSeeker, error) {
+	object, err := b.object(path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to open file %s", path)
+	}
+
+	u := b.objectURL(b.objectName(path), "", url.Values{"alt": {"media"}})
+	open := func(ctx context.Context, offset int64) (io.ReadCloser, error) {
+		header := http.Header{}
+		if offset > 0 {
+			header.Set("Range", fmt.Sprintf("bytes=%d-", offset))
+		}
+		resp, err := b.do(ctx, http.MethodGet, u, header, nil)
+		if err != nil {
+			return nil, errors.Wrapf(err, "unable to read file %s", path)
+		}
+		return resp.Body, nil
+	}
+
+	return newRangeReader(open, object.Size, b.timeout), nil
+}
+
+func (b *GCSFileBackend) ReadFile(path string) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), b.timeout)
+	defer cancel()
+	resp, err := b.do(ctx, http.MethodGet, b.objectURL(b.objectName(path), "", url.Values{"alt": {"media"}}), nil, nil)
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to open file %s", path)
+	}
+	defer resp.Body.Close()
+
+	f, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to read file %s", path)
+	}
+	return f, nil
+}
+
+func (b *GCSFileBackend) FileExists(path string) (bool, error) {
+	_, err := b.object(path)
+	if err == nil {
+		return true, nil
+	}
+	if isGCSStatus(err, http.StatusNotFound) {
+		return false, nil
+	}
+	return false, errors.Wrapf(err, "unable to know if file %s exists", path)
+}
+
+func (b *GCSFileBackend) FileSize(path string) (int64, error) {
+	object, err := b.object(path)
+	if err != nil {
+		return 0, errors.Wrapf(err, "unable to get file size for %s", path)
+	}
+	return object.Size, nil
+}
+
+func (b *GCSFileBackend) FileModTime(path string) (time.Time, error) {
+	object, err := b.object(path)
+	if err != nil {
+		return time.Time{}, errors.Wrapf(err, "unable to get modification time for file %s", path)
+	}
+	return object.Updated, nil
+}
+
+// CopyFile rewrites the object to the new path, which may take several requests for
+// large objects.
+func (b *GCSFileBackend) CopyFile(oldPath, newPath string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), b.timeout)
+	defer cancel()
+
+	suffix := "/rewriteTo/b/" + url.PathEscape(b.bucket) + "/o/" + url.PathEscape(b.objectName(newPath))
+	query := url.Values{}
+	for {
+		var rewrite struct {
+			Done         bool   `json:"done"`
+			RewriteToken string `json:"rewriteToken"`
+		}
+		if err := b.doJSON(ctx, http.MethodPost, b.objectURL(b.objectName(oldPath), suffix, query), struct{}{}, &rewrite); err != nil {
+			return errors.Wrapf(err, "unable to copy file from %s to %s", oldPath, newPath)
+		}
+		if rewrite.Done {
+			return nil
+		}
+		query.Set("rewriteToken", rewrite.RewriteToken)
+	}
+}
+
+func (b *GCSFileBackend) MoveFile(oldPath, newPath string) error {
+	if err := b.CopyFile(oldPath, newPath); err != nil {
+		return errors.Wrapf(err, "unable to copy the file to %s to the new destination", newPath)
+	}
+
+	if err := b.RemoveFile(oldPath); err != nil {
+		return errors.Wrapf(err, "unable to remove the file old file %s", oldPath)
+	}
+
+	return nil
+}
+
+func (b *GCSFileBackend) WriteFile(fr io.Reader, path string) (int64, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), b.timeout)
+	defer cancel()
+
+	return b.WriteFileContext(ctx, fr, path)
+}
+
+func (b *GCSFileBackend) WriteFileContext(ctx context.Context, fr io.Reader, path string) (int64, error) {
+	written, err := b.writeObject(ctx, fr, b.objectName(path))
+	if err != nil {
+		return 0, errors.Wrapf(err, "unable write the data in the file %s", path)
+	}
+	return written, nil
+}
+
+// writeObject uploads small objects in a single request, and larger ones in chunks of a
+// resumable upload.
+func (b *GCSFileBackend) writeObject(ctx context.Context, fr io.Reader, object string) (int64, error) {
+	contentType := fileContentType(object)
+
+	var buf bytes.Buffer
+	n, err := io.CopyN(&buf, fr, gcsUploadChunkSize)
+	if err != nil && err != io.EOF {
+		return 0, err
+	}
+	if err == io.EOF {
+		header := http.Header{"Content-Type": {contentType}}
+		resp, err := b.do(ctx, http.MethodPost, b.uploadURL(url.Values{"uploadType": {"media"}, "name": {object}}), header, buf.Bytes())
+		if err != nil {
+			return 0, err
+		}
+		resp.Body.Close()
+		return n, nil
+	}
+
+	header := http.Header{
+		"Content-Type":          {"application/json"},
+		"X-Upload-Content-Type": {contentType},
+	}
+	metadata, err := json.Marshal(map[string]string{"contentType": contentType})
+	if err != nil {
+		return 0, err
+	}
+	resp, err := b.do(ctx, http.MethodPost, b.uploadURL(url.Values{"uploadType": {"resumable"}, "name": {object}}), header, metadata)
+	if err != nil {
+		return 0, err
+	}
+	resp.Body.Close()
+	session, err := b.sessionURL(resp.Header.Get("Location"))
+	if err != nil {
+		return 0, err
+	}
+
+	var offset int64
+	for {
+		if err := b.uploadChunk(ctx, session, buf.Bytes(), offset, false); err != nil {
+			return 0, err
+		}
+		offset += n
+
+		buf.Reset()
+		n, err = io.CopyN(&buf, fr, gcsUploadChunkSize)
+		if err != nil && err != io.EOF {
+			return 0, err
+		}
+		if err == io.EOF {
+			if err := b.uploadChunk(ctx, session, buf.Bytes(), offset, true); err != nil {
+				return 0, err
+			}
+			return offset + n, nil
+		}
+	}
+}
+
+// sessionURL returns the URL of a resumable upload session. Emulators may not know the
+// address they are reached at, so the session is resolved against the endpoint instead.
+func (b *GCSFileBackend) sessionURL(location string) (string, error) {
+	session, err := url.Parse(location)
+	if err != nil || location == "" {
+		return "", errors.Errorf("invalid resumable upload session %q", location)
+	}
+	if b.emulated {
+		endpoint, err := url.Parse(b.endpoint)
+		if err != nil {
+			return "", err
+		}
+		session.Scheme = endpoint.Scheme
+		session.Host = endpoint.Host
+	}
+	return session.String(), nil
+}
+
+func (b *GCSFileBackend) uploadChunk(ctx context.Context, session string, data []byte, offset int64, final bool) error {
+	end := offset + int64(len(data))
+	var contentRange string
+	switch {
+	case len(data) == 0:
+		contentRange = fmt.Sprintf("bytes */%d", end)
+	case final:
+		contentRange = fmt.Sprintf("bytes %d-%d/%d", offset, end-1, end)
+	default:
+		contentRange = fmt.Sprintf("bytes %d-%d/*", offset, end-1)
+	}
+
+	resp, err := b.do(ctx, http.MethodPut, session, http.Header{"Content-Range": {contentRange}}, data)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if final {
+		if resp.StatusCode == http.StatusPermanentRedirect {
+			return errors.New("the resumable upload was not completed")
+		}
+		return nil
+	}
+
+	// The service may persist less than the whole chunk, which isn't expected for chunks
+	// of a multiple of 256KiB.
+	if persisted := resp.Header.Get("Range"); persisted != "" && persisted != fmt.Sprintf("bytes=0-%d", end-1) {
+		return errors.Errorf("unexpected range %q persisted by the resumable upload", persisted)
+	}
+	return nil
+}
+
+// AppendFile uploads the data to a temporary object before composing it with the existing
+// one. Objects can't be composed from more than 1024 components, which limits the number of
+// times a file can be appended to.
+func (b *GCSFileBackend) AppendFile(fr io.Reader, path string) (int64, error) {
+	object := b.objectName(path)
+	if _, err := b.object(path); err != nil {
+		return 0, errors.Wrapf(err, "unable to find the file %s to append the data", path)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), b.timeout)
+	defer cancel()
+
+	partName := object + ".part"
+	written, err := b.writeObject(ctx, fr, partName)
+	if err != nil {
+		return 0, errors.Wrapf(err, "unable append the data in the file %s", path)
+	}
+	defer func() {
+		ctx2, cancel2 := context.WithTimeout(context.Background(), b.timeout)
+		defer cancel2()
+		b.doJSON(ctx2, http.MethodDelete, b.objectURL(partName, "", url.Values{}), nil, nil)
+	}()
+
+	compose := map[string]any{
+		"sourceObjects": []map[string]string{{"name": object}, {"name": partName}},
+		"destination":   map[string]string{"contentType": fileContentType(object)},
+	}
+	if err := b.doJSON(ctx, http.MethodPost, b.objectURL(object, "/compose", url.Values{}), compose, nil); err != nil {
+		return 0, errors.Wrapf(err, "unable append the data in the file %s", path)
+	}
+
+	return written, nil
+}
+
+func (b *GCSFileBackend) RemoveFile(path string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), b.timeout)
+	defer cancel()
+	if err := b.doJSON(ctx, http.MethodDelete, b.objectURL(b.objectName(path), "", url.Values{}), nil, nil); err != nil && !isGCSStatus(err, http.StatusNotFound) {
+		return errors.Wrapf(err, "unable to remove the file %s", path)
+	}
+
+	return nil
+}
+
+func (b *GCSFileBackend) objectListURL(query url.Values) string {
+	return b.endpoint + "/storage/v1/b/" + url.PathEscape(b.bucket) + "/o?" + query.Encode()
+}
+
+// listObjects returns the names of the objects starting with the prefix. Unless recursive,
+// the names are only listed up to the next "/", like directories.
+func (b *GCSFileBackend) listObjects(ctx context.Context, prefix string, recursive bool) ([]string, error) {
+	query := url.Values{
+		"prefix":     {prefix},
+		"maxResults": {strconv.Itoa(gcsListPageSize)},
+	}
+	if !recursive {
+		query.Set("delimiter", "/")
+	}
+
+	var names []string
+	for {
+		var list struct {
+			Items         []gcsObject `json:"items"`
+			Prefixes      []string    `json:"prefixes"`
+			NextPageToken string      `json:"nextPageToken"`
+		}
+		if err := b.doJSON(ctx, http.MethodGet, b.objectListURL(query), nil, &list); err != nil {
+			return nil, err
+		}
+
+		for _, item := range list.Items {
+			names = append(names, item.Name)
+		}
+		names = append(names, list.Prefixes...)
+		if list.NextPageToken == "" {
+			return names, nil
+		}
+		query.Set("pageToken", list.NextPageToken)
+	}
+}
+
+func (b *GCSFileBackend) listDirectory(path string, recursion bool) ([]string, error) {
+	prefix := b.objectName(path)
+	if prefix != "" && !strings.HasSuffix(prefix, "/") {
+		prefix += "/"
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), b.timeout)
+	defer cancel()
+	names, err := b.listObjects(ctx, prefix, recursion)
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to list the directory %s", path)
+	}
+
+	var paths []string
+	for _, name := range names {
+		// We strip the path prefix that gets applied,
+		// so that it remains transparent to the application.
+		trimmed := strings.Trim(strings.TrimPrefix(name, b.pathPrefix), "/")
+		if trimmed != "" {
+			paths = append(paths, trimmed)
+		}
+	}
+
+	return paths, nil
+}
+
+func (b *GCSFileBackend) ListDirectory(path string) ([]string, error) {
+	return b.listDirectory(path, false)
+}
+
+func (b *GCSFileBackend) ListDirectoryRecursively(path string) ([]string, error) {
+	return b.listDirectory(path, true)
+}
+
+func (b *GCSFileBackend) RemoveDirectory(path string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), b.timeout)
+	defer cancel()
+	names, err := b.listObjects(ctx, strings.TrimSuffix(b.objectName(path), "/")+"/", true)
+	if err != nil {
+		return errors.Wrapf(err, "unable to remove the directory %s", path)
+	}
+
+	for _, name := range names {
+		if err := b.doJSON(ctx, http.MethodDelete, b.objectURL(name, "", url.Values{}), nil, nil); err != nil && !isGCSStatus(err, http.StatusNotFound) {
+			return errors.Wrapf(err, "unable to remove the directory %s", path)
+		}
+	}
+
+	return nil
+}
+
+// gcsEscape escapes a string as required by the canonical requests of signed URLs.
+func gcsEscape(s string) string {
+	return strings.ReplaceAll(url.QueryEscape(s), "+", "%20")
+}
+
+// GeneratePublicLink returns a URL signed with the V4 signing process, as described in
+// https://cloud.google.com/storage/docs/access-control/signing-urls-manually
+func (b *GCSFileBackend) GeneratePublicLink(path string) (string, time.Duration, error) {
+	if b.key == nil {
+		return "", 0, errors.New("unable to generate public link without a gcs service account key")
+	}
+
+	endpoint, err := url.Parse(b.endpoint)
+	if err != nil {
+		return "", 0, errors.Wrapf(err, "unable to generate public link for %s", path)
+	}
+
+	segments := strings.Split(b.objectName(path), "/")
+	for i, segment := range segments {
+		segments[i] = gcsEscape(segment)
+	}
+	resource := "/" + gcsEscape(b.bucket) + "/" + strings.Join(segments, "/")
+
+	now := time.Now().UTC()
+	datetime := now.Format("20060102T150405Z")
+	scope := now.Format("20060102") + "/auto/storage/goog4_request"
+	query := map[string]string{
+		"X-Goog-Algorithm":             "GOOG4-RSA-SHA256",
+		"X-Goog-Credential":            b.key.ClientEmail + "/" + scope,
+		"X-Goog-Date":                  datetime,
+		"X-Goog-Expires":               strconv.FormatInt(int64(b.signedURLExpires/time.Second), 10),
+		"X-Goog-SignedHeaders":         "host",
+		"response-content-disposition": "attachment",
+	}
+	names := make([]string, 0, len(query))
+	for name := range query {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	params := make([]string, 0, len(names))
+	for _, name := range names {
+		params = append(params, gcsEscape(name)+"="+gcsEscape(query[name]))
+	}
+	canonicalQuery := strings.Join(params, "&")
+
+	canonicalRequest := strings.Join([]string{
+		http.MethodGet,
+		resource,
+		canonicalQuery,
+		"host:" + endpoint.Host,
+		"",
+		"host",
+		"UNSIGNED-PAYLOAD",
+	}, "\n")
+	requestHash := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := strings.Join([]string{
+		"GOOG4-RSA-SHA256",
+		datetime,
+		scope,
+		hex.EncodeToString(requestHash[:]),
+	}, "\n")
+
+	hash := sha256.Sum256([]byte(stringToSign))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, b.privateKey, crypto.SHA256, hash[:])
+	if err != nil {
+		return "", 0, errors.Wrapf(err, "unable to generate public link for %s", path)
+	}
+
+	link := endpoint.Scheme + "://" + endpoint.Host + resource + "?" + canonicalQuery + "&X-Goog-Signature=" + hex.EncodeToString(signature)
+	return link, b.signedURLExpires, nil
+}
@@ -0,0 +1,156 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package filestore
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestGCSServiceAccountKey returns the JSON key of a service account using the given
+// token endpoint.
+func newTestGCSServiceAccountKey(t *testing.T, tokenURI string) (string, *rsa.PrivateKey) {
+	t.Helper()
+
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	der, err := x509.MarshalPKCS8PrivateKey(privateKey)
+	require.NoError(t, err)
+
+	key, err := json.Marshal(map[string]string{
+		"type":           "service_account",
+		"project_id":     "mattermost",
+		"private_key_id": "key-id",
+		"private_key":    string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})),
+		"client_email":   "files@mattermost.iam.gserviceaccount.com",
+		"token_uri":      tokenURI,
+	})
+	require.NoError(t, err)
+	return string(key), privateKey
+}
+
+func TestGCSFileBackendAccessToken(t *testing.T) {
+	var (
+		privateKey    *rsa.PrivateKey
+		tokenRequests int
+		authorization string
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/token" {
+			tokenRequests++
+			require.NoError(t, r.ParseForm())
+			assert.Equal(t, "urn:ietf:params:oauth:grant-type:jwt-bearer", r.PostForm.Get("grant_type"))
+
+			claims := jwt.MapClaims{}
+			token, err := jwt.ParseWithClaims(r.PostForm.Get("assertion"), claims, func(token *jwt.Token) (any, error) {
+				return &privateKey.PublicKey, nil
+			})
+			require.NoError(t, err)
+			assert.Equal(t, "key-id", token.Header["kid"])
+			assert.Equal(t, "files@mattermost.iam.gserviceaccount.com", claims["iss"])
+			assert.Equal(t, gcsTokenScope, claims["scope"])
+
+			w.Write([]byte(`{"access_token": "token", "expires_in": 3600, "token_type": "Bearer"}`))
+			return
+		}
+
+		authorization = r.Header.Get("Authorization")
+		w.Write([]byte(`{"name": "dir/file.txt", "size": "42", "updated": "2024-01-02T03:04:05.000Z"}`))
+	}))
+	defer server.Close()
+
+	key, testPrivateKey := newTestGCSServiceAccountKey(t, server.URL+"/token")
+	privateKey = testPrivateKey
+	backend, err := NewGCSFileBackend(FileBackendSettings{
+		DriverName:                    driverGCS,
+		GCSBucket:                     "mattermost-test",
+		GCSServiceAccountKey:          key,
+		GCSEndpoint:                   server.URL,
+		GCSRequestTimeoutMilliseconds: 5000,
+		GCSSignedURLExpiresSeconds:    3600,
+	})
+	require.NoError(t, err)
+
+	size, err := backend.FileSize("dir/file.txt")
+	require.NoError(t, err)
+	assert.EqualValues(t, 42, size)
+	assert.Equal(t, "Bearer token", authorization)
+
+	modTime, err := backend.FileModTime("dir/file.txt")
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC), modTime)
+
+	// The token is reused until it expires.
+	assert.Equal(t, 1, tokenRequests)
+}
+
+func TestGCSFileBackendGeneratePublicLink(t *testing.T) {
+	t.Run("service account key is required", func(t *testing.T) {
+		backend, err := NewGCSFileBackend(FileBackendSettings{
+			DriverName:  driverGCS,
+			GCSBucket:   "mattermost-test",
+			GCSEndpoint: "http://localhost:4443",
+		})
+		require.NoError(t, err)
+
+		_, _, err = backend.GeneratePublicLink("dir/file.txt")
+		require.Error(t, err)
+	})
+
+	t.Run("link is signed by the service account", func(t *testing.T) {
+		key, privateKey := newTestGCSServiceAccountKey(t, "")
+		backend, err := NewGCSFileBackend(FileBackendSettings{
+			DriverName:                    driverGCS,
+			GCSBucket:                     "mattermost-test",
+			GCSPathPrefix:                 "prefix",
+			GCSServiceAccountKey:          key,
+			GCSRequestTimeoutMilliseconds: 5000,
+			GCSSignedURLExpiresSeconds:    3600,
+		})
+		require.NoError(t, err)
+
+		link, expires, err := backend.GeneratePublicLink("dir/file name.txt")
+		require.NoError(t, err)
+		assert.Equal(t, time.Hour, expires)
+
+		u, err := url.Parse(link)
+		require.NoError(t, err)
+		assert.Equal(t, "storage.googleapis.com", u.Host)
+		assert.Equal(t, "/mattermost-test/prefix/dir/file%20name.txt", u.EscapedPath())
+
+		query := u.Query()
+		assert.Equal(t, "GOOG4-RSA-SHA256", query.Get("X-Goog-Algorithm"))
+		assert.Equal(t, "3600", query.Get("X-Goog-Expires"))
+		assert.Equal(t, "attachment", query.Get("response-content-disposition"))
+		assert.True(t, strings.HasPrefix(query.Get("X-Goog-Credential"), "files@mattermost.iam.gserviceaccount.com/"))
+
+		signedQuery, _, found := strings.Cut(u.RawQuery, "&X-Goog-Signature=")
+		require.True(t, found)
+		canonicalRequest := "GET\n/mattermost-test/prefix/dir/file%20name.txt\n" + signedQuery + "\nhost:storage.googleapis.com\n\nhost\nUNSIGNED-PAYLOAD"
+		requestHash := sha256.Sum256([]byte(canonicalRequest))
+		scope := strings.TrimPrefix(query.Get("X-Goog-Credential"), "files@mattermost.iam.gserviceaccount.com/")
+		stringToSign := "GOOG4-RSA-SHA256\n" + query.Get("X-Goog-Date") + "\n" + scope + "\n" + hex.EncodeToString(requestHash[:])
+
+		signature, err := hex.DecodeString(query.Get("X-Goog-Signature"))
+		require.NoError(t, err)
+		hash := sha256.Sum256([]byte(stringToSign))
+		require.NoError(t, rsa.VerifyPKCS1v15(&privateKey.PublicKey, crypto.SHA256, hash[:], signature))
+	})
+}
@@ -0,0 +1,124 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package filestore
+
+import (
+	"context"
+	"crypto/tls"
+	"io"
+	"net/http"
+	"path/filepath"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// newHTTPBackendClient returns the client used by the backends talking directly to an
+// HTTP API.
+func newHTTPBackendClient(skipVerify bool) *http.Client {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if skipVerify {
+		transport.TLSClientConfig = &tls.Config{InsecureSkipVerify: true}
+	}
+	return &http.Client{Transport: transport}
+}
+
+// fileContentType returns the content type files are stored with, matching the S3 backend.
+func fileContentType(path string) string {
+	if ext := filepath.Ext(path); isFileExtImage(ext) {
+		return getImageMimeType(ext)
+	}
+	return "binary/octet-stream"
+}
+
+// rangeOpenFunc opens the body of an object starting at the given offset.
+type rangeOpenFunc func(ctx context.Context, offset int64) (io.ReadCloser, error)
+
+// rangeReader reads an object of a known size from an HTTP based backend. The body is
+// opened lazily and seeking re-opens it with a range request at the new offset.
+type rangeReader struct {
+	ctx    context.Context
+	open   rangeOpenFunc
+	size   int64
+	offset int64
+	body   io.ReadCloser
+	timer  *time.Timer
+	cancel context.CancelFunc
+}
+
+var _ ReadCloseSeeker = (*rangeReader)(nil)
+
+// newRangeReader returns a reader for an object of the given size. The requests made by the
+// reader are canceled after the timeout, unless CancelTimeout is called before.
+func newRangeReader(open rangeOpenFunc, size int64, timeout time.Duration) *rangeReader {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &rangeReader{
+		ctx:    ctx,
+		open:   open,
+		size:   size,
+		timer:  time.AfterFunc(timeout, cancel),
+		cancel: cancel,
+	}
+}
+
+func (r *rangeReader) Read(p []byte) (int, error) {
+	if r.offset >= r.size {
+		return 0, io.EOF
+	}
+
+	if r.body == nil {
+		body, err := r.open(r.ctx, r.offset)
+		if err != nil {
+			return 0, err
+		}
+		r.body = body
+	}
+
+	n, err := r.body.Read(p)
+	r.offset += int64(n)
+	if err == io.EOF && r.offset < r.size {
+		err = io.ErrUnexpectedEOF
+	}
+	return n, err
+}
+
+func (r *rangeReader) Seek(offset int64, whence int) (int64, error) {
+	var newOffset int64
+	switch whence {
+	case io.SeekStart:
+		newOffset = offset
+	case io.SeekCurrent:
+		newOffset = r.offset + offset
+	case io.SeekEnd:
+		newOffset = r.size + offset
+	default:
+		return 0, errors.Errorf("invalid whence %d", whence)
+	}
+	if newOffset < 0 {
+		return 0, errors.New("negative position")
+	}
+
+	if newOffset != r.offset && r.body != nil {
+		r.body.Close()
+		r.body = nil
+	}
+	r.offset = newOffset
+	return newOffset, nil
+}
+
+func (r *rangeReader) Close() error {
+	r.timer.Stop()
+	r.cancel()
+	if r.body == nil {
+		return nil
+	}
+	return r.body.Close()
+}
+
+// CancelTimeout attempts to cancel the timeout for this reader. It allows calling
+// code to ignore the timeout in case of longer running operations. The methods returns
+// false if the timeout has already fired.
+func (r *rangeReader) CancelTimeout() bool {
+	return r.timer.Stop()
+}
@@ -0,0 +1,53 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package filestore
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRangeReader(t *testing.T) {
+	data := []byte("0123456789")
+	var offsets []int64
+	open := func(ctx context.Context, offset int64) (io.ReadCloser, error) {
+		offsets = append(offsets, offset)
+		return io.NopCloser(bytes.NewReader(data[offset:])), nil
+	}
+
+	reader := newRangeReader(open, int64(len(data)), time.Minute)
+	defer reader.Close()
+
+	buf := make([]byte, 4)
+	_, err := io.ReadFull(reader, buf)
+	require.NoError(t, err)
+	assert.Equal(t, "0123", string(buf))
+
+	// Seeking to the current position keeps the body open.
+	offset, err := reader.Seek(0, io.SeekCurrent)
+	require.NoError(t, err)
+	assert.EqualValues(t, 4, offset)
+	_, err = io.ReadFull(reader, buf[:2])
+	require.NoError(t, err)
+	assert.Equal(t, "45", string(buf[:2]))
+
+	offset, err = reader.Seek(-3, io.SeekEnd)
+	require.NoError(t, err)
+	assert.EqualValues(t, 7, offset)
+	rest, err := io.ReadAll(reader)
+	require.NoError(t, err)
+	assert.Equal(t, "789", string(rest))
+
+	_, err = reader.Seek(-1, io.SeekStart)
+	require.Error(t, err)
+
+	assert.Equal(t, []int64{0, 7}, offsets)
+	assert.True(t, reader.CancelTimeout())
+}
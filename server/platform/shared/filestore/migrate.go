@@ -0,0 +1,106 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package filestore
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"io"
+
+	"github.com/pkg/errors"
+)
+
+// ErrFileMigrationMismatch is returned by MigrateFile when the copy of a file doesn't match
+// the original once written.
+var ErrFileMigrationMismatch = errors.New("the copied file doesn't match the original")
+
+// MigrateFile copies the file at the given path from one backend to another, unless the
+// destination already holds an identical copy of it, so that an interrupted migration can be
+// resumed. The copy is read back and compared with the original by size and SHA-256 checksum.
+// It returns whether the file was copied.
+//
+// The files are copied as they are stored, so the backends shouldn't be wrapped by the
+// EncryptedFileBackend for encrypted files to be readable with the same keys once copied.
+func MigrateFile(ctx context.Context, src, dst FileBackend, path string) (bool, error) {
+	size, err := src.FileSize(path)
+	if err != nil {
+		return false, errors.Wrapf(err, "unable to get the size of %s", path)
+	}
+
+	exists, err := dst.FileExists(path)
+	if err != nil {
+		return false, errors.Wrapf(err, "unable to check if %s was already copied", path)
+	}
+	if exists {
+		identical, err := identicalFiles(src, dst, path, size)
+		if err != nil {
+			return false, err
+		}
+		if identical {
+			return false, nil
+		}
+	}
+
+	reader, err := src.Reader(path)
+	if err != nil {
+		return false, errors.Wrapf(err, "unable to open %s", path)
+	}
+	defer reader.Close()
+
+	hash := sha256.New()
+	written, err := TryWriteFileContext(ctx, dst, io.TeeReader(reader, hash), path)
+	if err != nil {
+		return false, errors.Wrapf(err, "unable to copy %s", path)
+	}
+	if written != size {
+		return false, errors.Wrapf(ErrFileMigrationMismatch, "%d bytes of %s were copied instead of %d", written, path, size)
+	}
+
+	checksum, err := fileChecksum(dst, path)
+	if err != nil {
+		return false, err
+	}
+	if !bytes.Equal(checksum, hash.Sum(nil)) {
+		return false, errors.Wrapf(ErrFileMigrationMismatch, "the checksum of the copy of %s differs", path)
+	}
+
+	return true, nil
+}
+
+// identicalFiles checks whether the destination's copy of a file of the given size has the
+// same contents as the source's.
+func identicalFiles(src, dst FileBackend, path string, size int64) (bool, error) {
+	dstSize, err := dst.FileSize(path)
+	if err != nil {
+		return false, errors.Wrapf(err, "unable to get the size of the copy of %s", path)
+	}
+	if dstSize != size {
+		return false, nil
+	}
+
+	srcChecksum, err := fileChecksum(src, path)
+	if err != nil {
+		return false, err
+	}
+	dstChecksum, err := fileChecksum(dst, path)
+	if err != nil {
+		return false, err
+	}
+	return bytes.Equal(srcChecksum, dstChecksum), nil
+}
+
+func fileChecksum(backend FileBackend, path string) ([]byte, error) {
+	reader, err := backend.Reader(path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to open %s", path)
+	}
+	defer reader.Close()
+
+	hash := sha256.New()
+	if _, err := io.Copy(hash, reader); err != nil {
+		return nil, errors.Wrapf(err, "unable to read %s", path)
+	}
+	return hash.Sum(nil), nil
+}
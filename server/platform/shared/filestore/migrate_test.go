@@ -0,0 +1,78 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package filestore
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// corruptingFileBackend flips the first byte of the files written to it.
+type corruptingFileBackend struct {
+	*LocalFileBackend
+}
+
+func (b *corruptingFileBackend) WriteFile(fr io.Reader, path string) (int64, error) {
+	data, err := io.ReadAll(fr)
+	if err != nil {
+		return 0, err
+	}
+	data[0] ^= 0xff
+	return b.LocalFileBackend.WriteFile(bytes.NewReader(data), path)
+}
+
+func TestMigrateFile(t *testing.T) {
+	src := &LocalFileBackend{directory: t.TempDir()}
+	dst := &LocalFileBackend{directory: t.TempDir()}
+
+	data := []byte("migrated file contents")
+	_, err := src.WriteFile(bytes.NewReader(data), "dir/file.txt")
+	require.NoError(t, err)
+
+	t.Run("file is copied", func(t *testing.T) {
+		copied, err := MigrateFile(context.Background(), src, dst, "dir/file.txt")
+		require.NoError(t, err)
+		assert.True(t, copied)
+
+		copy, err := dst.ReadFile("dir/file.txt")
+		require.NoError(t, err)
+		assert.Equal(t, data, copy)
+	})
+
+	t.Run("identical copy is skipped", func(t *testing.T) {
+		copied, err := MigrateFile(context.Background(), src, dst, "dir/file.txt")
+		require.NoError(t, err)
+		assert.False(t, copied)
+	})
+
+	t.Run("different copy is overwritten", func(t *testing.T) {
+		_, err := dst.WriteFile(bytes.NewReader([]byte("migrated file CONTENTS")), "dir/file.txt")
+		require.NoError(t, err)
+
+		copied, err := MigrateFile(context.Background(), src, dst, "dir/file.txt")
+		require.NoError(t, err)
+		assert.True(t, copied)
+
+		copy, err := dst.ReadFile("dir/file.txt")
+		require.NoError(t, err)
+		assert.Equal(t, data, copy)
+	})
+
+	t.Run("corrupted copy is detected", func(t *testing.T) {
+		corrupting := &corruptingFileBackend{&LocalFileBackend{directory: t.TempDir()}}
+		_, err := MigrateFile(context.Background(), src, corrupting, "dir/file.txt")
+		require.Error(t, err)
+		assert.ErrorIs(t, err, ErrFileMigrationMismatch)
+	})
+
+	t.Run("missing file", func(t *testing.T) {
+		_, err := MigrateFile(context.Background(), src, dst, "dir/missing.txt")
+		require.Error(t, err)
+	})
+}
@@ -0,0 +1,329 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package mfa
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"math/big"
+	"net/url"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// InvalidWebAuthnResponse indicates that a WebAuthn ceremony response failed validation.
+var InvalidWebAuthnResponse = errors.New("invalid webauthn response")
+
+// COSE algorithm identifiers, see https://www.iana.org/assignments/cose/cose.xhtml#algorithms
+const (
+	WebAuthnAlgES256 = -7
+	WebAuthnAlgEdDSA = -8
+	WebAuthnAlgRS256 = -257
+)
+
+// WebAuthnAlgorithms lists the supported public key algorithms, in order of preference.
+var WebAuthnAlgorithms = []int{WebAuthnAlgES256, WebAuthnAlgEdDSA, WebAuthnAlgRS256}
+
+const (
+	webAuthnTypeCreate = "webauthn.create"
+	webAuthnTypeGet    = "webauthn.get"
+
+	webAuthnFlagUserPresent            = 0x01
+	webAuthnFlagAttestedCredentialData = 0x40
+
+	// The fixed part of the authenticator data: rpIdHash (32), flags (1) and signCount (4).
+	webAuthnAuthDataMinLength = 37
+	webAuthnAAGUIDLength      = 16
+	webAuthnMinRSAKeyBits     = 2048
+)
+
+// WebAuthn verifies the registration and authentication ceremonies defined by
+// https://www.w3.org/TR/webauthn-2/ on behalf of a single relying party.
+type WebAuthn struct {
+	rpID   string
+	origin string
+}
+
+// WebAuthnCredential is a public key credential created during registration.
+type WebAuthnCredential struct {
+	ID        []byte
+	PublicKey []byte // COSE_Key encoded public key
+	SignCount uint32
+	AAGUID    []byte
+}
+
+type webAuthnClientData struct {
+	Type      string `json:"type"`
+	Challenge string `json:"challenge"`
+	Origin    string `json:"origin"`
+}
+
+// NewWebAuthn returns a relying party for the given site URL. The relying party ID is
+// the host name of the site and the expected origin is its scheme and host.
+func NewWebAuthn(siteURL string) (*WebAuthn, error) {
+	u, err := url.Parse(strings.TrimSpace(siteURL))
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to parse the site url")
+	}
+
+	if (u.Scheme != "https" && u.Scheme != "http") || u.Hostname() == "" {
+		return nil, errors.Errorf("invalid site url %q", siteURL)
+	}
+
+	return &WebAuthn{
+		rpID:   u.Hostname(),
+		origin: u.Scheme + "://" + u.Host,
+	}, nil
+}
+
+// RelyingPartyID returns the relying party ID that credentials are scoped to.
+func (w *WebAuthn) RelyingPartyID() string {
+	return w.rpID
+}
+
+// ParseWebAuthnChallenge returns the challenge that was signed by the client, so that
+// the caller can find the ceremony a response belongs to.
+func ParseWebAuthnChallenge(clientDataJSON []byte) ([]byte, error) {
+	var clientData webAuthnClientData
+	if err := json.Unmarshal(clientDataJSON, &clientData); err != nil {
+		return nil, errors.Wrap(InvalidWebAuthnResponse, "unable to parse the client data")
+	}
+
+	challenge, err := base64.RawURLEncoding.DecodeString(clientData.Challenge)
+	if err != nil || len(challenge) == 0 {
+		return nil, errors.Wrap(InvalidWebAuthnResponse, "invalid challenge")
+	}
+
+	return challenge, nil
+}
+
+// VerifyRegistration validates the response to a credential creation request and
+// returns the new credential. Attestation statements are not verified since
+// attestation conveyance "none" is requested.
+func (w *WebAuthn) VerifyRegistration(challenge, clientDataJSON, attestationObject []byte) (*WebAuthnCredential, error) {
+	if err := w.verifyClientData(clientDataJSON, webAuthnTypeCreate, challenge); err != nil {
+		return nil, err
+	}
+
+	decoded, rest, err := decodeCBOR(attestationObject)
+	if err != nil || len(rest) != 0 {
+		return nil, errors.Wrap(InvalidWebAuthnResponse, "unable to decode the attestation object")
+	}
+
+	attestation, ok := decoded.(map[any]any)
+	if !ok {
+		return nil, errors.Wrap(InvalidWebAuthnResponse, "invalid attestation object")
+	}
+
+	authData, ok := attestation["authData"].([]byte)
+	if !ok {
+		return nil, errors.Wrap(InvalidWebAuthnResponse, "missing authenticator data")
+	}
+
+	flags, signCount, err := w.verifyAuthenticatorData(authData)
+	if err != nil {
+		return nil, err
+	}
+
+	if flags&webAuthnFlagAttestedCredentialData == 0 {
+		return nil, errors.Wrap(InvalidWebAuthnResponse, "missing attested credential data")
+	}
+
+	data := authData[webAuthnAuthDataMinLength:]
+	if len(data) < webAuthnAAGUIDLength+2 {
+		return nil, errors.Wrap(InvalidWebAuthnResponse, "invalid attested credential data")
+	}
+
+	aaguid := data[:webAuthnAAGUIDLength]
+	idLength := int(binary.BigEndian.Uint16(data[webAuthnAAGUIDLength:]))
+	data = data[webAuthnAAGUIDLength+2:]
+	if idLength == 0 || len(data) < idLength {
+		return nil, errors.Wrap(InvalidWebAuthnResponse, "invalid credential id")
+	}
+
+	credentialID := data[:idLength]
+	data = data[idLength:]
+
+	_, rest, err = decodeCBOR(data)
+	if err != nil {
+		return nil, errors.Wrap(InvalidWebAuthnResponse, "unable to decode the credential public key")
+	}
+	publicKey := data[:len(data)-len(rest)]
+
+	if _, _, err := parseCOSEKey(publicKey); err != nil {
+		return nil, err
+	}
+
+	return &WebAuthnCredential{
+		ID:        bytes.Clone(credentialID),
+		PublicKey: bytes.Clone(publicKey),
+		SignCount: signCount,
+		AAGUID:    bytes.Clone(aaguid),
+	}, nil
+}
+
+// VerifyAssertion validates the response to an authentication request made with the
+// given credential and returns the new signature counter of the authenticator.
+func (w *WebAuthn) VerifyAssertion(challenge []byte, credential *WebAuthnCredential, clientDataJSON, authenticatorData, signature []byte) (uint32, error) {
+	if err := w.verifyClientData(clientDataJSON, webAuthnTypeGet, challenge); err != nil {
+		return 0, err
+	}
+
+	_, signCount, err := w.verifyAuthenticatorData(authenticatorData)
+	if err != nil {
+		return 0, err
+	}
+
+	publicKey, alg, err := parseCOSEKey(credential.PublicKey)
+	if err != nil {
+		return 0, err
+	}
+
+	clientDataHash := sha256.Sum256(clientDataJSON)
+	message := append(bytes.Clone(authenticatorData), clientDataHash[:]...)
+	if err := verifyWebAuthnSignature(publicKey, alg, message, signature); err != nil {
+		return 0, err
+	}
+
+	// A counter that doesn't increase is a signal that the authenticator may have been
+	// cloned. Authenticators that don't implement counters always report zero.
+	if (signCount != 0 || credential.SignCount != 0) && signCount <= credential.SignCount {
+		return 0, errors.Wrap(InvalidWebAuthnResponse, "signature counter did not increase")
+	}
+
+	return signCount, nil
+}
+
+func (w *WebAuthn) verifyClientData(clientDataJSON []byte, ceremonyType string, challenge []byte) error {
+	var clientData webAuthnClientData
+	if err := json.Unmarshal(clientDataJSON, &clientData); err != nil {
+		return errors.Wrap(InvalidWebAuthnResponse, "unable to parse the client data")
+	}
+
+	if clientData.Type != ceremonyType {
+		return errors.Wrapf(InvalidWebAuthnResponse, "unexpected ceremony type %q", clientData.Type)
+	}
+
+	signed, err := base64.RawURLEncoding.DecodeString(clientData.Challenge)
+	if err != nil || len(challenge) == 0 || subtle.ConstantTimeCompare(signed, challenge) != 1 {
+		return errors.Wrap(InvalidWebAuthnResponse, "challenge mismatch")
+	}
+
+	if clientData.Origin != w.origin {
+		return errors.Wrapf(InvalidWebAuthnResponse, "unexpected origin %q", clientData.Origin)
+	}
+
+	return nil
+}
+
+func (w *WebAuthn) verifyAuthenticatorData(authData []byte) (byte, uint32, error) {
+	if len(authData) < webAuthnAuthDataMinLength {
+		return 0, 0, errors.Wrap(InvalidWebAuthnResponse, "invalid authenticator data")
+	}
+
+	rpIDHash := sha256.Sum256([]byte(w.rpID))
+	if subtle.ConstantTimeCompare(authData[:32], rpIDHash[:]) != 1 {
+		return 0, 0, errors.Wrap(InvalidWebAuthnResponse, "relying party id mismatch")
+	}
+
+	flags := authData[32]
+	if flags&webAuthnFlagUserPresent == 0 {
+		return 0, 0, errors.Wrap(InvalidWebAuthnResponse, "user not present")
+	}
+
+	return flags, binary.BigEndian.Uint32(authData[33:37]), nil
+}
+
+// parseCOSEKey decodes a COSE_Key as defined by RFC 9053 into a public key.
+func parseCOSEKey(data []byte) (crypto.PublicKey, int, error) {
+	decoded, rest, err := decodeCBOR(data)
+	if err != nil || len(rest) != 0 {
+		return nil, 0, errors.Wrap(InvalidWebAuthnResponse, "unable to decode the public key")
+	}
+
+	key, ok := decoded.(map[any]any)
+	if !ok {
+		return nil, 0, errors.Wrap(InvalidWebAuthnResponse, "invalid public key")
+	}
+
+	kty, _ := key[int64(1)].(int64)
+	alg, _ := key[int64(3)].(int64)
+	crv, _ := key[int64(-1)].(int64)
+	x, _ := key[int64(-2)].([]byte)
+
+	switch {
+	case kty == 2 && alg == WebAuthnAlgES256 && crv == 1:
+		y, _ := key[int64(-3)].([]byte)
+		if len(x) != 32 || len(y) != 32 {
+			return nil, 0, errors.Wrap(InvalidWebAuthnResponse, "invalid ec2 public key")
+		}
+
+		point := append(append([]byte{0x04}, x...), y...)
+		if _, err := ecdh.P256().NewPublicKey(point); err != nil {
+			return nil, 0, errors.Wrap(InvalidWebAuthnResponse, "invalid ec2 public key")
+		}
+
+		return &ecdsa.PublicKey{
+			Curve: elliptic.P256(),
+			X:     new(big.Int).SetBytes(x),
+			Y:     new(big.Int).SetBytes(y),
+		}, WebAuthnAlgES256, nil
+	case kty == 1 && alg == WebAuthnAlgEdDSA && crv == 6:
+		if len(x) != ed25519.PublicKeySize {
+			return nil, 0, errors.Wrap(InvalidWebAuthnResponse, "invalid okp public key")
+		}
+
+		return ed25519.PublicKey(bytes.Clone(x)), WebAuthnAlgEdDSA, nil
+	case kty == 3 && alg == WebAuthnAlgRS256:
+		n, _ := key[int64(-1)].([]byte)
+		e, _ := key[int64(-2)].([]byte)
+		if len(e) == 0 || len(e) > 4 {
+			return nil, 0, errors.Wrap(InvalidWebAuthnResponse, "invalid rsa public key")
+		}
+
+		publicKey := &rsa.PublicKey{
+			N: new(big.Int).SetBytes(n),
+			E: int(new(big.Int).SetBytes(e).Int64()),
+		}
+		if publicKey.N.BitLen() < webAuthnMinRSAKeyBits || publicKey.E < 3 {
+			return nil, 0, errors.Wrap(InvalidWebAuthnResponse, "invalid rsa public key")
+		}
+
+		return publicKey, WebAuthnAlgRS256, nil
+	default:
+		return nil, 0, errors.Wrapf(InvalidWebAuthnResponse, "unsupported public key algorithm %d", alg)
+	}
+}
+
+func verifyWebAuthnSignature(publicKey crypto.PublicKey, alg int, message, signature []byte) error {
+	valid := false
+
+	switch key := publicKey.(type) {
+	case *ecdsa.PublicKey:
+		hash := sha256.Sum256(message)
+		valid = ecdsa.VerifyASN1(key, hash[:], signature)
+	case ed25519.PublicKey:
+		valid = ed25519.Verify(key, message, signature)
+	case *rsa.PublicKey:
+		hash := sha256.Sum256(message)
+		valid = rsa.VerifyPKCS1v15(key, crypto.SHA256, hash[:], signature) == nil
+	}
+
+	if !valid {
+		return errors.Wrapf(InvalidWebAuthnResponse, "invalid signature for algorithm %d", alg)
+	}
+
+	return nil
+}
@@ -0,0 +1,264 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package mfa
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// encodeCBOR is a minimal encoder for the values used by the tests below.
+func encodeCBOR(v any) []byte {
+	header := func(major byte, n uint64) []byte {
+		switch {
+		case n < 24:
+			return []byte{major<<5 | byte(n)}
+		case n <= 0xff:
+			return []byte{major<<5 | 24, byte(n)}
+		case n <= 0xffff:
+			return binary.BigEndian.AppendUint16([]byte{major<<5 | 25}, uint16(n))
+		default:
+			return binary.BigEndian.AppendUint32([]byte{major<<5 | 26}, uint32(n))
+		}
+	}
+
+	switch v := v.(type) {
+	case int:
+		if v < 0 {
+			return header(cborMajorNegative, uint64(-1-v))
+		}
+		return header(cborMajorUnsigned, uint64(v))
+	case []byte:
+		return append(header(cborMajorBytes, uint64(len(v))), v...)
+	case string:
+		return append(header(cborMajorText, uint64(len(v))), v...)
+	case map[any]any:
+		keys := make([]any, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Slice(keys, func(i, j int) bool { return string(encodeCBOR(keys[i])) < string(encodeCBOR(keys[j])) })
+
+		out := header(cborMajorMap, uint64(len(v)))
+		for _, key := range keys {
+			out = append(out, encodeCBOR(key)...)
+			out = append(out, encodeCBOR(v[key])...)
+		}
+		return out
+	}
+	panic("unsupported type")
+}
+
+type testAuthenticator struct {
+	t            *testing.T
+	rpID         string
+	origin       string
+	credentialID []byte
+	signCount    uint32
+	ecKey        *ecdsa.PrivateKey
+	edKey        ed25519.PrivateKey
+}
+
+func newTestAuthenticator(t *testing.T, rpID, origin string, ed bool) *testAuthenticator {
+	a := &testAuthenticator{t: t, rpID: rpID, origin: origin, credentialID: make([]byte, 32)}
+	_, err := rand.Read(a.credentialID)
+	require.NoError(t, err)
+
+	if ed {
+		_, a.edKey, err = ed25519.GenerateKey(rand.Reader)
+	} else {
+		a.ecKey, err = ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	}
+	require.NoError(t, err)
+
+	return a
+}
+
+func (a *testAuthenticator) coseKey() []byte {
+	if a.edKey != nil {
+		return encodeCBOR(map[any]any{1: 1, 3: WebAuthnAlgEdDSA, -1: 6, -2: []byte(a.edKey.Public().(ed25519.PublicKey))})
+	}
+
+	x := a.ecKey.PublicKey.X.FillBytes(make([]byte, 32))
+	y := a.ecKey.PublicKey.Y.FillBytes(make([]byte, 32))
+	return encodeCBOR(map[any]any{1: 2, 3: WebAuthnAlgES256, -1: 1, -2: x, -3: y})
+}
+
+func (a *testAuthenticator) clientData(ceremonyType string, challenge []byte) []byte {
+	data, err := json.Marshal(map[string]any{
+		"type":        ceremonyType,
+		"challenge":   base64.RawURLEncoding.EncodeToString(challenge),
+		"origin":      a.origin,
+		"crossOrigin": false,
+	})
+	require.NoError(a.t, err)
+	return data
+}
+
+func (a *testAuthenticator) authData(flags byte) []byte {
+	rpIDHash := sha256.Sum256([]byte(a.rpID))
+	data := append(rpIDHash[:], flags)
+	return binary.BigEndian.AppendUint32(data, a.signCount)
+}
+
+func (a *testAuthenticator) register(challenge []byte) ([]byte, []byte) {
+	authData := a.authData(webAuthnFlagUserPresent | webAuthnFlagAttestedCredentialData)
+	authData = append(authData, make([]byte, webAuthnAAGUIDLength)...)
+	authData = binary.BigEndian.AppendUint16(authData, uint16(len(a.credentialID)))
+	authData = append(authData, a.credentialID...)
+	authData = append(authData, a.coseKey()...)
+
+	attestationObject := encodeCBOR(map[any]any{
+		"fmt":      "none",
+		"attStmt":  map[any]any{},
+		"authData": authData,
+	})
+
+	return a.clientData(webAuthnTypeCreate, challenge), attestationObject
+}
+
+func (a *testAuthenticator) assert(challenge []byte) ([]byte, []byte, []byte) {
+	a.signCount++
+	clientData := a.clientData(webAuthnTypeGet, challenge)
+	authData := a.authData(webAuthnFlagUserPresent)
+
+	clientDataHash := sha256.Sum256(clientData)
+	message := append(append([]byte(nil), authData...), clientDataHash[:]...)
+
+	var signature []byte
+	if a.edKey != nil {
+		signature = ed25519.Sign(a.edKey, message)
+	} else {
+		hash := sha256.Sum256(message)
+		var err error
+		signature, err = ecdsa.SignASN1(rand.Reader, a.ecKey, hash[:])
+		require.NoError(a.t, err)
+	}
+
+	return clientData, authData, signature
+}
+
+func TestNewWebAuthn(t *testing.T) {
+	w, err := NewWebAuthn("https://chat.example.com:8065/subpath")
+	require.NoError(t, err)
+	assert.Equal(t, "chat.example.com", w.RelyingPartyID())
+	assert.Equal(t, "https://chat.example.com:8065", w.origin)
+
+	_, err = NewWebAuthn("")
+	require.Error(t, err)
+
+	_, err = NewWebAuthn("ftp://chat.example.com")
+	require.Error(t, err)
+}
+
+func TestWebAuthnCeremonies(t *testing.T) {
+	w, err := NewWebAuthn("https://chat.example.com")
+	require.NoError(t, err)
+
+	for name, ed := range map[string]bool{"ES256": false, "EdDSA": true} {
+		t.Run(name, func(t *testing.T) {
+			authenticator := newTestAuthenticator(t, "chat.example.com", "https://chat.example.com", ed)
+
+			challenge := []byte("registration-challenge")
+			clientData, attestationObject := authenticator.register(challenge)
+
+			parsed, err := ParseWebAuthnChallenge(clientData)
+			require.NoError(t, err)
+			assert.Equal(t, challenge, parsed)
+
+			credential, err := w.VerifyRegistration(challenge, clientData, attestationObject)
+			require.NoError(t, err)
+			assert.Equal(t, authenticator.credentialID, credential.ID)
+			assert.Equal(t, authenticator.coseKey(), credential.PublicKey)
+			assert.Len(t, credential.AAGUID, webAuthnAAGUIDLength)
+
+			challenge = []byte("login-challenge")
+			clientData, authData, signature := authenticator.assert(challenge)
+			signCount, err := w.VerifyAssertion(challenge, credential, clientData, authData, signature)
+			require.NoError(t, err)
+			assert.Equal(t, uint32(1), signCount)
+			credential.SignCount = signCount
+
+			t.Run("replayed counter", func(t *testing.T) {
+				_, err := w.VerifyAssertion(challenge, credential, clientData, authData, signature)
+				require.True(t, errors.Is(err, InvalidWebAuthnResponse))
+			})
+
+			t.Run("wrong challenge", func(t *testing.T) {
+				clientData, authData, signature := authenticator.assert([]byte("other"))
+				_, err := w.VerifyAssertion(challenge, credential, clientData, authData, signature)
+				require.True(t, errors.Is(err, InvalidWebAuthnResponse))
+			})
+
+			t.Run("tampered authenticator data", func(t *testing.T) {
+				clientData, authData, signature := authenticator.assert(challenge)
+				authData[len(authData)-1]++
+				_, err := w.VerifyAssertion(challenge, credential, clientData, authData, signature)
+				require.True(t, errors.Is(err, InvalidWebAuthnResponse))
+			})
+
+			t.Run("wrong ceremony type", func(t *testing.T) {
+				clientData, attestationObject := authenticator.register(challenge)
+				_, err := w.VerifyAssertion(challenge, credential, clientData, attestationObject, nil)
+				require.True(t, errors.Is(err, InvalidWebAuthnResponse))
+			})
+		})
+	}
+
+	t.Run("wrong origin", func(t *testing.T) {
+		authenticator := newTestAuthenticator(t, "chat.example.com", "https://evil.example.com", false)
+		clientData, attestationObject := authenticator.register([]byte("challenge"))
+		_, err := w.VerifyRegistration([]byte("challenge"), clientData, attestationObject)
+		require.True(t, errors.Is(err, InvalidWebAuthnResponse))
+	})
+
+	t.Run("wrong relying party", func(t *testing.T) {
+		authenticator := newTestAuthenticator(t, "evil.example.com", "https://chat.example.com", false)
+		clientData, attestationObject := authenticator.register([]byte("challenge"))
+		_, err := w.VerifyRegistration([]byte("challenge"), clientData, attestationObject)
+		require.True(t, errors.Is(err, InvalidWebAuthnResponse))
+	})
+
+	t.Run("user not present", func(t *testing.T) {
+		authenticator := newTestAuthenticator(t, "chat.example.com", "https://chat.example.com", false)
+		clientData, _ := authenticator.register([]byte("challenge"))
+		attestationObject := encodeCBOR(map[any]any{"fmt": "none", "authData": authenticator.authData(0)})
+		_, err := w.VerifyRegistration([]byte("challenge"), clientData, attestationObject)
+		require.True(t, errors.Is(err, InvalidWebAuthnResponse))
+	})
+}
+
+func TestDecodeCBOR(t *testing.T) {
+	value, rest, err := decodeCBOR([]byte{0xa2, 0x01, 0x02, 0x20, 0x63, 'a', 'b', 'c', 0xff})
+	require.NoError(t, err)
+	assert.Equal(t, map[any]any{int64(1): int64(2), int64(-1): "abc"}, value)
+	assert.Equal(t, []byte{0xff}, rest)
+
+	value, _, err = decodeCBOR([]byte{0xf9, 0x3c, 0x00})
+	require.NoError(t, err)
+	assert.Equal(t, 1.0, value)
+
+	for name, data := range map[string][]byte{
+		"empty":             {},
+		"truncated bytes":   {0x43, 0x01},
+		"indefinite length": {0x5f},
+		"duplicate key":     {0xa2, 0x01, 0x01, 0x01, 0x02},
+		"huge array":        {0x9b, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff},
+	} {
+		_, _, err := decodeCBOR(data)
+		assert.Error(t, err, name)
+	}
+}
@@ -0,0 +1,145 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package model
+
+import (
+	"net/http"
+	"unicode/utf8"
+)
+
+const (
+	AuditRecordEventNameMaxRunes  = 256
+	AuditRecordStatusMaxRunes     = 32
+	AuditRecordUserIdMaxRunes     = 64
+	AuditRecordClientMaxRunes     = 512
+	AuditRecordIpAddressMaxRunes  = 64
+	AuditRecordObjectTypeMaxRunes = 64
+
+	// AuditRecordMaxObjectIds caps the number of objects a record is indexed by.
+	AuditRecordMaxObjectIds = 20
+
+	AuditRecordSearchDefaultPerPage = 60
+	AuditRecordSearchMaxPerPage     = 200
+)
+
+// AuditRecord is an audit record kept in the database, so that it can be searched. It is
+// indexed by event name, actor, status and the ids of the objects the event refers to.
+type AuditRecord struct {
+	Id         string      `json:"id"`
+	CreateAt   int64       `json:"create_at"`
+	EventName  string      `json:"event_name"`
+	Status     string      `json:"status"`
+	UserId     string      `json:"user_id"`
+	SessionId  string      `json:"session_id"`
+	Client     string      `json:"client"`
+	IpAddress  string      `json:"ip_address"`
+	ObjectType string      `json:"object_type"`
+	ObjectIds  StringArray `json:"object_ids"`
+
+	// Data is the complete audit record, encoded as JSON.
+	Data string `json:"data"`
+}
+
+func (r *AuditRecord) PreSave() {
+	if r.Id == "" {
+		r.Id = NewId()
+	}
+
+	if r.CreateAt == 0 {
+		r.CreateAt = GetMillis()
+	}
+
+	r.EventName = truncateRunes(r.EventName, AuditRecordEventNameMaxRunes)
+	r.Status = truncateRunes(r.Status, AuditRecordStatusMaxRunes)
+	r.UserId = truncateRunes(r.UserId, AuditRecordUserIdMaxRunes)
+	r.Client = truncateRunes(r.Client, AuditRecordClientMaxRunes)
+	r.IpAddress = truncateRunes(r.IpAddress, AuditRecordIpAddressMaxRunes)
+	r.ObjectType = truncateRunes(r.ObjectType, AuditRecordObjectTypeMaxRunes)
+
+	if r.ObjectIds == nil {
+		r.ObjectIds = StringArray{}
+	}
+	if len(r.ObjectIds) > AuditRecordMaxObjectIds {
+		r.ObjectIds = r.ObjectIds[:AuditRecordMaxObjectIds]
+	}
+}
+
+func truncateRunes(s string, maxRunes int) string {
+	if utf8.RuneCountInString(s) > maxRunes {
+		return string([]rune(s)[:maxRunes])
+	}
+	return s
+}
+
+func (r *AuditRecord) IsValid() *AppError {
+	if !IsValidId(r.Id) {
+		return NewAppError("AuditRecord.IsValid", "model.audit_record.is_valid.id.app_error", nil, "", http.StatusBadRequest)
+	}
+
+	if r.CreateAt == 0 {
+		return NewAppError("AuditRecord.IsValid", "model.audit_record.is_valid.create_at.app_error", nil, "id="+r.Id, http.StatusBadRequest)
+	}
+
+	if r.EventName == "" {
+		return NewAppError("AuditRecord.IsValid", "model.audit_record.is_valid.event_name.app_error", nil, "id="+r.Id, http.StatusBadRequest)
+	}
+
+	if r.SessionId != "" && !IsValidId(r.SessionId) {
+		return NewAppError("AuditRecord.IsValid", "model.audit_record.is_valid.session_id.app_error", nil, "id="+r.Id, http.StatusBadRequest)
+	}
+
+	for _, objectID := range r.ObjectIds {
+		if !IsValidId(objectID) {
+			return NewAppError("AuditRecord.IsValid", "model.audit_record.is_valid.object_ids.app_error", nil, "id="+r.Id, http.StatusBadRequest)
+		}
+	}
+
+	return nil
+}
+
+// AuditRecordSearch filters the audit records returned by a search. Empty fields match
+// all records. Records are returned newest first.
+type AuditRecordSearch struct {
+	EventName string `json:"event_name,omitempty"`
+	UserId    string `json:"user_id,omitempty"`
+	Status    string `json:"status,omitempty"`
+	ObjectId  string `json:"object_id,omitempty"`
+
+	// Since and Until restrict the search to the records created in the given range,
+	// in milliseconds since the epoch. Both ends are inclusive.
+	Since int64 `json:"since,omitempty"`
+	Until int64 `json:"until,omitempty"`
+
+	Page    int `json:"page"`
+	PerPage int `json:"per_page"`
+}
+
+func (s *AuditRecordSearch) IsValid() *AppError {
+	if s.ObjectId != "" && !IsValidId(s.ObjectId) {
+		return NewAppError("AuditRecordSearch.IsValid", "model.audit_record_search.is_valid.object_id.app_error", nil, "", http.StatusBadRequest)
+	}
+
+	if s.Since < 0 || s.Until < 0 || (s.Until > 0 && s.Since > s.Until) {
+		return NewAppError("AuditRecordSearch.IsValid", "model.audit_record_search.is_valid.time_range.app_error", nil, "", http.StatusBadRequest)
+	}
+
+	if s.Page < 0 || s.PerPage < 0 || s.PerPage > AuditRecordSearchMaxPerPage {
+		return NewAppError("AuditRecordSearch.IsValid", "model.audit_record_search.is_valid.paging.app_error", map[string]any{"MaxPerPage": AuditRecordSearchMaxPerPage}, "", http.StatusBadRequest)
+	}
+
+	return nil
+}
+
+func (s *AuditRecordSearch) Auditable() map[string]any {
+	return map[string]any{
+		"event_name": s.EventName,
+		"user_id":    s.UserId,
+		"status":     s.Status,
+		"object_id":  s.ObjectId,
+		"since":      s.Since,
+		"until":      s.Until,
+		"page":       s.Page,
+		"per_page":   s.PerPage,
+	}
+}
@@ -0,0 +1,89 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package model
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuditRecordPreSave(t *testing.T) {
+	objectIDs := StringArray{}
+	for i := 0; i < AuditRecordMaxObjectIds+5; i++ {
+		objectIDs = append(objectIDs, NewId())
+	}
+
+	r := &AuditRecord{
+		EventName: strings.Repeat("e", AuditRecordEventNameMaxRunes+1),
+		Client:    strings.Repeat("ü", AuditRecordClientMaxRunes+10),
+		ObjectIds: objectIDs,
+	}
+	r.PreSave()
+
+	assert.True(t, IsValidId(r.Id))
+	assert.NotZero(t, r.CreateAt)
+	assert.Len(t, r.EventName, AuditRecordEventNameMaxRunes)
+	assert.Equal(t, strings.Repeat("ü", AuditRecordClientMaxRunes), r.Client)
+	assert.Equal(t, objectIDs[:AuditRecordMaxObjectIds], r.ObjectIds)
+	require.Nil(t, r.IsValid())
+}
+
+func TestAuditRecordIsValid(t *testing.T) {
+	r := &AuditRecord{
+		EventName: "updateChannel",
+		Status:    "success",
+		UserId:    NewId(),
+		SessionId: NewId(),
+		ObjectIds: StringArray{NewId()},
+	}
+	r.PreSave()
+	require.Nil(t, r.IsValid())
+
+	invalid := *r
+	invalid.Id = "invalid"
+	require.NotNil(t, invalid.IsValid())
+
+	invalid = *r
+	invalid.CreateAt = 0
+	require.NotNil(t, invalid.IsValid())
+
+	invalid = *r
+	invalid.EventName = ""
+	require.NotNil(t, invalid.IsValid())
+
+	invalid = *r
+	invalid.SessionId = "invalid"
+	require.NotNil(t, invalid.IsValid())
+
+	invalid = *r
+	invalid.ObjectIds = StringArray{"invalid"}
+	require.NotNil(t, invalid.IsValid())
+}
+
+func TestAuditRecordSearchIsValid(t *testing.T) {
+	for name, tc := range map[string]struct {
+		search *AuditRecordSearch
+		valid  bool
+	}{
+		"empty":               {&AuditRecordSearch{}, true},
+		"all filters":         {&AuditRecordSearch{EventName: "updateChannel", UserId: NewId(), Status: "fail", ObjectId: NewId(), Since: 1, Until: 2, PerPage: 10}, true},
+		"invalid object id":   {&AuditRecordSearch{ObjectId: "invalid"}, false},
+		"negative since":      {&AuditRecordSearch{Since: -1}, false},
+		"since after until":   {&AuditRecordSearch{Since: 2, Until: 1}, false},
+		"since without until": {&AuditRecordSearch{Since: 2}, true},
+		"negative page":       {&AuditRecordSearch{Page: -1}, false},
+		"per page too large":  {&AuditRecordSearch{PerPage: AuditRecordSearchMaxPerPage + 1}, false},
+	} {
+		t.Run(name, func(t *testing.T) {
+			if tc.valid {
+				assert.Nil(t, tc.search.IsValid())
+			} else {
+				assert.NotNil(t, tc.search.IsValid())
+			}
+		})
+	}
+}
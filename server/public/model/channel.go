@@ -33,6 +33,9 @@ const (
 	ChannelPurposeMaxRunes     = 250
 	ChannelCacheSize           = 25000
 
+	// ChannelPostExpiryMaxMinutes is the longest a channel can keep its posts before they expire.
+	ChannelPostExpiryMaxMinutes = 365 * 24 * 60
+
 	ChannelSortByUsername = "username"
 	ChannelSortByStatus   = "status"
 )
@@ -59,6 +62,10 @@ type Channel struct {
 	TotalMsgCountRoot int64          `json:"total_msg_count_root"`
 	PolicyID          *string        `json:"policy_id"`
 	LastRootPostAt    int64          `json:"last_root_post_at"`
+
+	// PostExpiryMinutes is the number of minutes after which posts in the channel are
+	// permanently deleted. A value of 0 means that posts never expire.
+	PostExpiryMinutes int64 `json:"post_expiry_minutes"`
 }
 
 func (o *Channel) Auditable() map[string]interface{} {
@@ -72,6 +79,7 @@ func (o *Channel) Auditable() map[string]interface{} {
 		"last_post_at":         o.LastPostAt,
 		"last_root_post_at":    o.LastRootPostAt,
 		"policy_id":            o.PolicyID,
+		"post_expiry_minutes":  o.PostExpiryMinutes,
 		"props":                o.Props,
 		"scheme_id":            o.SchemeId,
 		"shared":               o.Shared,
@@ -104,13 +112,16 @@ type ChannelPatch struct {
 	Header           *string `json:"header"`
 	Purpose          *string `json:"purpose"`
 	GroupConstrained *bool   `json:"group_constrained"`
+
+	PostExpiryMinutes *int64 `json:"post_expiry_minutes"`
 }
 
 func (c *ChannelPatch) Auditable() map[string]interface{} {
 	return map[string]interface{}{
-		"header":            c.Header,
-		"group_constrained": c.GroupConstrained,
-		"purpose":           c.Purpose,
+		"header":              c.Header,
+		"group_constrained":   c.GroupConstrained,
+		"purpose":             c.Purpose,
+		"post_expiry_minutes": c.PostExpiryMinutes,
 	}
 }
 
@@ -253,6 +264,10 @@ func (o *Channel) IsValid() *AppError {
 		return NewAppError("Channel.IsValid", "model.channel.is_valid.creator_id.app_error", nil, "", http.StatusBadRequest)
 	}
 
+	if o.PostExpiryMinutes < 0 || o.PostExpiryMinutes > ChannelPostExpiryMaxMinutes {
+		return NewAppError("Channel.IsValid", "model.channel.is_valid.post_expiry_minutes.app_error", map[string]any{"Max": ChannelPostExpiryMaxMinutes}, "id="+o.Id, http.StatusBadRequest)
+	}
+
 	if o.Type != ChannelTypeDirect && o.Type != ChannelTypeGroup {
 		userIds := strings.Split(o.Name, "__")
 		if ok := gmNameRegex.MatchString(o.Name); ok || (o.Type != ChannelTypeDirect && len(userIds) == 2 && IsValidId(userIds[0]) && IsValidId(userIds[1])) {
@@ -311,6 +326,10 @@ func (o *Channel) Patch(patch *ChannelPatch) {
 	if patch.GroupConstrained != nil {
 		o.GroupConstrained = patch.GroupConstrained
 	}
+
+	if patch.PostExpiryMinutes != nil {
+		o.PostExpiryMinutes = *patch.PostExpiryMinutes
+	}
 }
 
 func (o *Channel) MakeNonNil() {
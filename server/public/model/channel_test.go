@@ -77,6 +77,15 @@ func TestChannelIsValid(t *testing.T) {
 	o.Purpose = "1234"
 	require.Nil(t, o.IsValid())
 
+	o.PostExpiryMinutes = -1
+	require.NotNil(t, o.IsValid())
+
+	o.PostExpiryMinutes = ChannelPostExpiryMaxMinutes + 1
+	require.NotNil(t, o.IsValid())
+
+	o.PostExpiryMinutes = 60
+	require.Nil(t, o.IsValid())
+
 	o.Purpose = strings.Repeat("0123456789", 25)
 	require.Nil(t, o.IsValid())
 
@@ -35,6 +35,8 @@ const (
 	HeaderRemoteclusterId           = "X-RemoteCluster-Id"
 	HeaderRequestedWith             = "X-Requested-With"
 	HeaderRequestedWithXML          = "XMLHttpRequest"
+	HeaderIntegrationTimestamp      = "X-Mattermost-Request-Timestamp"
+	HeaderIntegrationSignature      = "X-Mattermost-Signature"
 	HeaderFirstInaccessiblePostTime = "First-Inaccessible-Post-Time"
 	HeaderFirstInaccessibleFileTime = "First-Inaccessible-File-Time"
 	HeaderRange                     = "Range"
@@ -452,6 +454,22 @@ func (c *Client4) draftsRoute() string {
 	return "/drafts"
 }
 
+func (c *Client4) scheduledPostsRoute() string {
+	return c.postsRoute() + "/schedule"
+}
+
+func (c *Client4) scheduledPostRoute(scheduledPostId string) string {
+	return fmt.Sprintf(c.scheduledPostsRoute()+"/%v", scheduledPostId)
+}
+
+func (c *Client4) savedSearchesRoute() string {
+	return c.postsRoute() + "/saved_searches"
+}
+
+func (c *Client4) savedSearchRoute(savedSearchId string) string {
+	return fmt.Sprintf(c.savedSearchesRoute()+"/%v", savedSearchId)
+}
+
 func (c *Client4) emojisRoute() string {
 	return "/emoji"
 }
@@ -1526,6 +1544,81 @@ func (c *Client4) GenerateMfaSecret(ctx context.Context, userId string) (*MfaSec
 	return &secret, BuildResponse(r), nil
 }
 
+// GetWebAuthnCredentials returns the WebAuthn credentials registered by a user.
+func (c *Client4) GetWebAuthnCredentials(ctx context.Context, userId string) ([]*WebAuthnCredential, *Response, error) {
+	r, err := c.DoAPIGet(ctx, c.userRoute(userId)+"/webauthn", "")
+	if err != nil {
+		return nil, BuildResponse(r), err
+	}
+	defer closeBody(r)
+	var list []*WebAuthnCredential
+	if err := json.NewDecoder(r.Body).Decode(&list); err != nil {
+		return nil, nil, NewAppError("GetWebAuthnCredentials", "api.unmarshal_error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+	return list, BuildResponse(r), nil
+}
+
+// GetWebAuthnCreationOptions starts the registration of a WebAuthn credential and returns
+// the options to pass to the authenticator. Must be logged in as the user.
+func (c *Client4) GetWebAuthnCreationOptions(ctx context.Context, userId string) (*WebAuthnCreationOptions, *Response, error) {
+	r, err := c.DoAPIPost(ctx, c.userRoute(userId)+"/webauthn/options", "")
+	if err != nil {
+		return nil, BuildResponse(r), err
+	}
+	defer closeBody(r)
+	var options WebAuthnCreationOptions
+	if err := json.NewDecoder(r.Body).Decode(&options); err != nil {
+		return nil, nil, NewAppError("GetWebAuthnCreationOptions", "api.unmarshal_error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+	return &options, BuildResponse(r), nil
+}
+
+// RegisterWebAuthnCredential completes the registration of a WebAuthn credential
+// with the response of the authenticator. Must be logged in as the user.
+func (c *Client4) RegisterWebAuthnCredential(ctx context.Context, userId string, registration *WebAuthnRegistration) (*WebAuthnCredential, *Response, error) {
+	buf, err := json.Marshal(registration)
+	if err != nil {
+		return nil, nil, NewAppError("RegisterWebAuthnCredential", "api.marshal_error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+	r, err := c.DoAPIPostBytes(ctx, c.userRoute(userId)+"/webauthn", buf)
+	if err != nil {
+		return nil, BuildResponse(r), err
+	}
+	defer closeBody(r)
+	var credential WebAuthnCredential
+	if err := json.NewDecoder(r.Body).Decode(&credential); err != nil {
+		return nil, nil, NewAppError("RegisterWebAuthnCredential", "api.unmarshal_error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+	return &credential, BuildResponse(r), nil
+}
+
+// DeleteWebAuthnCredential revokes a WebAuthn credential of a user. Removing the last
+// credential of a user without a one-time password disables multi-factor authentication.
+func (c *Client4) DeleteWebAuthnCredential(ctx context.Context, userId, credentialId string) (*Response, error) {
+	r, err := c.DoAPIDelete(ctx, c.userRoute(userId)+"/webauthn/"+credentialId)
+	if err != nil {
+		return BuildResponse(r), err
+	}
+	defer closeBody(r)
+	return BuildResponse(r), nil
+}
+
+// GetWebAuthnRequestOptions starts a WebAuthn authentication for the given login id. The
+// resulting assertion, encoded as JSON, is passed as the MFA token when logging in.
+func (c *Client4) GetWebAuthnRequestOptions(ctx context.Context, loginId string) (*WebAuthnRequestOptions, *Response, error) {
+	requestBody := map[string]string{"login_id": loginId}
+	r, err := c.DoAPIPost(ctx, c.usersRoute()+"/login/webauthn/options", MapToJSON(requestBody))
+	if err != nil {
+		return nil, BuildResponse(r), err
+	}
+	defer closeBody(r)
+	var options WebAuthnRequestOptions
+	if err := json.NewDecoder(r.Body).Decode(&options); err != nil {
+		return nil, nil, NewAppError("GetWebAuthnRequestOptions", "api.unmarshal_error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+	return &options, BuildResponse(r), nil
+}
+
 // UpdateUserPassword updates a user's password. Must be logged in as the user or be a system administrator.
 func (c *Client4) UpdateUserPassword(ctx context.Context, userId, currentPassword, newPassword string) (*Response, error) {
 	requestBody := map[string]string{"current_password": currentPassword, "new_password": newPassword}
@@ -4610,6 +4703,21 @@ func (c *Client4) DownloadFilePreview(ctx context.Context, fileId string, downlo
 	return data, BuildResponse(r), nil
 }
 
+// GetFileRendition gets the bytes of the web-friendly rendition of a video.
+func (c *Client4) GetFileRendition(ctx context.Context, fileId string) ([]byte, *Response, error) {
+	r, err := c.DoAPIGet(ctx, c.fileRoute(fileId)+"/rendition", "")
+	if err != nil {
+		return nil, BuildResponse(r), err
+	}
+	defer closeBody(r)
+
+	data, err := io.ReadAll(r.Body)
+	if err != nil {
+		return nil, BuildResponse(r), NewAppError("GetFileRendition", "model.client.read_file.app_error", nil, "", r.StatusCode).Wrap(err)
+	}
+	return data, BuildResponse(r), nil
+}
+
 // GetFileInfo gets all the file info objects.
 func (c *Client4) GetFileInfo(ctx context.Context, fileId string) (*FileInfo, *Response, error) {
 	r, err := c.DoAPIGet(ctx, c.fileRoute(fileId)+"/info", "")
@@ -4625,6 +4733,48 @@ func (c *Client4) GetFileInfo(ctx context.Context, fileId string) (*FileInfo, *R
 	return &fi, BuildResponse(r), nil
 }
 
+// GetQuarantinedFiles returns a page of the files that were found to be infected, or could not
+// be scanned for viruses, most recent first.
+func (c *Client4) GetQuarantinedFiles(ctx context.Context, page, perPage int) ([]*FileInfo, *Response, error) {
+	query := fmt.Sprintf("?page=%v&per_page=%v", page, perPage)
+	r, err := c.DoAPIGet(ctx, "/quarantine"+c.filesRoute()+query, "")
+	if err != nil {
+		return nil, BuildResponse(r), err
+	}
+	defer closeBody(r)
+
+	var list []*FileInfo
+	if err := json.NewDecoder(r.Body).Decode(&list); err != nil {
+		return nil, nil, NewAppError("GetQuarantinedFiles", "api.unmarshal_error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+	return list, BuildResponse(r), nil
+}
+
+// ReleaseQuarantinedFile marks a quarantined file as clean so that it can be downloaded.
+func (c *Client4) ReleaseQuarantinedFile(ctx context.Context, fileId string) (*FileInfo, *Response, error) {
+	r, err := c.DoAPIPost(ctx, c.fileRoute(fileId)+"/quarantine/release", "")
+	if err != nil {
+		return nil, BuildResponse(r), err
+	}
+	defer closeBody(r)
+
+	var fi FileInfo
+	if err := json.NewDecoder(r.Body).Decode(&fi); err != nil {
+		return nil, nil, NewAppError("ReleaseQuarantinedFile", "api.unmarshal_error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+	return &fi, BuildResponse(r), nil
+}
+
+// DeleteQuarantinedFile permanently deletes a quarantined file.
+func (c *Client4) DeleteQuarantinedFile(ctx context.Context, fileId string) (*Response, error) {
+	r, err := c.DoAPIDelete(ctx, c.fileRoute(fileId)+"/quarantine")
+	if err != nil {
+		return BuildResponse(r), err
+	}
+	defer closeBody(r)
+	return BuildResponse(r), nil
+}
+
 // GetFileInfosForPost gets all the file info objects attached to a post.
 func (c *Client4) GetFileInfosForPost(ctx context.Context, postId string, etag string) ([]*FileInfo, *Response, error) {
 	r, err := c.DoAPIGet(ctx, c.postRoute(postId)+"/files/info", etag)
@@ -5191,6 +5341,51 @@ func (c *Client4) RegenOutgoingHookToken(ctx context.Context, hookId string) (*O
 	return &ow, BuildResponse(r), nil
 }
 
+// RegenOutgoingHookSigningSecret regenerates the secret used to sign the outgoing webhook requests.
+func (c *Client4) RegenOutgoingHookSigningSecret(ctx context.Context, hookId string) (*OutgoingWebhook, *Response, error) {
+	r, err := c.DoAPIPost(ctx, c.outgoingWebhookRoute(hookId)+"/regen_signing_secret", "")
+	if err != nil {
+		return nil, BuildResponse(r), err
+	}
+	defer closeBody(r)
+	var ow OutgoingWebhook
+	if err := json.NewDecoder(r.Body).Decode(&ow); err != nil {
+		return nil, nil, NewAppError("RegenOutgoingHookSigningSecret", "api.unmarshal_error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+	return &ow, BuildResponse(r), nil
+}
+
+// GetOutgoingWebhookDeadLetters returns a page of the deliveries of an outgoing webhook that
+// failed too many times and were moved to the dead-letter list.
+func (c *Client4) GetOutgoingWebhookDeadLetters(ctx context.Context, hookId string, page int, perPage int) ([]*OutgoingWebhookDelivery, *Response, error) {
+	query := fmt.Sprintf("?page=%v&per_page=%v", page, perPage)
+	r, err := c.DoAPIGet(ctx, c.outgoingWebhookRoute(hookId)+"/deliveries"+query, "")
+	if err != nil {
+		return nil, BuildResponse(r), err
+	}
+	defer closeBody(r)
+	var deliveries []*OutgoingWebhookDelivery
+	if err := json.NewDecoder(r.Body).Decode(&deliveries); err != nil {
+		return nil, nil, NewAppError("GetOutgoingWebhookDeadLetters", "api.unmarshal_error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+	return deliveries, BuildResponse(r), nil
+}
+
+// ReplayOutgoingWebhookDelivery moves a dead-lettered delivery of an outgoing webhook back to
+// the retry queue.
+func (c *Client4) ReplayOutgoingWebhookDelivery(ctx context.Context, hookId, deliveryId string) (*OutgoingWebhookDelivery, *Response, error) {
+	r, err := c.DoAPIPost(ctx, c.outgoingWebhookRoute(hookId)+"/deliveries/"+deliveryId+"/replay", "")
+	if err != nil {
+		return nil, BuildResponse(r), err
+	}
+	defer closeBody(r)
+	var delivery OutgoingWebhookDelivery
+	if err := json.NewDecoder(r.Body).Decode(&delivery); err != nil {
+		return nil, nil, NewAppError("ReplayOutgoingWebhookDelivery", "api.unmarshal_error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+	return &delivery, BuildResponse(r), nil
+}
+
 // DeleteOutgoingWebhook delete the outgoing webhook on the system requested by Hook Id.
 func (c *Client4) DeleteOutgoingWebhook(ctx context.Context, hookId string) (*Response, error) {
 	r, err := c.DoAPIDelete(ctx, c.outgoingWebhookRoute(hookId))
@@ -5843,6 +6038,25 @@ func (c *Client4) GetAudits(ctx context.Context, page int, perPage int, etag str
 	return audits, BuildResponse(r), nil
 }
 
+// SearchAuditRecords returns the audit records kept in the database that match the search.
+func (c *Client4) SearchAuditRecords(ctx context.Context, search *AuditRecordSearch) ([]*AuditRecord, *Response, error) {
+	buf, err := json.Marshal(search)
+	if err != nil {
+		return nil, nil, NewAppError("SearchAuditRecords", "api.marshal_error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+	r, err := c.DoAPIPostBytes(ctx, "/audits/search", buf)
+	if err != nil {
+		return nil, BuildResponse(r), err
+	}
+	defer closeBody(r)
+
+	var records []*AuditRecord
+	if err := json.NewDecoder(r.Body).Decode(&records); err != nil {
+		return nil, BuildResponse(r), NewAppError("SearchAuditRecords", "api.unmarshal_error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+	return records, BuildResponse(r), nil
+}
+
 // Brand Section
 
 // GetBrandImage retrieves the previously uploaded brand image.
@@ -6563,6 +6777,191 @@ func (c *Client4) DeleteDraft(ctx context.Context, userId, channelId, rootId str
 	return df, BuildResponse(r), nil
 }
 
+// Scheduled Posts Section
+
+// CreateScheduledPost schedules a post to be created on behalf of the current user at ScheduledAt.
+func (c *Client4) CreateScheduledPost(ctx context.Context, scheduledPost *ScheduledPost) (*ScheduledPost, *Response, error) {
+	buf, err := json.Marshal(scheduledPost)
+	if err != nil {
+		return nil, nil, NewAppError("CreateScheduledPost", "api.marshal_error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+
+	r, err := c.DoAPIPostBytes(ctx, c.scheduledPostsRoute(), buf)
+	if err != nil {
+		return nil, BuildResponse(r), err
+	}
+	defer closeBody(r)
+
+	var sp ScheduledPost
+	err = json.NewDecoder(r.Body).Decode(&sp)
+	if err != nil {
+		return nil, nil, NewAppError("CreateScheduledPost", "api.unmarshal_error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+	return &sp, BuildResponse(r), nil
+}
+
+// GetUserScheduledPosts returns the current user's scheduled posts for a team, including
+// the ones scheduled in direct and group message channels.
+func (c *Client4) GetUserScheduledPosts(ctx context.Context, teamId string) ([]*ScheduledPost, *Response, error) {
+	r, err := c.DoAPIGet(ctx, c.postsRoute()+"/scheduled"+c.teamRoute(teamId), "")
+	if err != nil {
+		return nil, BuildResponse(r), err
+	}
+	defer closeBody(r)
+
+	var scheduledPosts []*ScheduledPost
+	err = json.NewDecoder(r.Body).Decode(&scheduledPosts)
+	if err != nil {
+		return nil, nil, NewAppError("GetUserScheduledPosts", "api.unmarshal_error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+	return scheduledPosts, BuildResponse(r), nil
+}
+
+// UpdateScheduledPost updates the message, props, files and schedule of a scheduled post.
+func (c *Client4) UpdateScheduledPost(ctx context.Context, scheduledPost *ScheduledPost) (*ScheduledPost, *Response, error) {
+	buf, err := json.Marshal(scheduledPost)
+	if err != nil {
+		return nil, nil, NewAppError("UpdateScheduledPost", "api.marshal_error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+
+	r, err := c.DoAPIPutBytes(ctx, c.scheduledPostRoute(scheduledPost.Id), buf)
+	if err != nil {
+		return nil, BuildResponse(r), err
+	}
+	defer closeBody(r)
+
+	var sp ScheduledPost
+	err = json.NewDecoder(r.Body).Decode(&sp)
+	if err != nil {
+		return nil, nil, NewAppError("UpdateScheduledPost", "api.unmarshal_error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+	return &sp, BuildResponse(r), nil
+}
+
+// DeleteScheduledPost deletes a scheduled post and returns the deleted record.
+func (c *Client4) DeleteScheduledPost(ctx context.Context, scheduledPostId string) (*ScheduledPost, *Response, error) {
+	r, err := c.DoAPIDelete(ctx, c.scheduledPostRoute(scheduledPostId))
+	if err != nil {
+		return nil, BuildResponse(r), err
+	}
+	defer closeBody(r)
+
+	var sp ScheduledPost
+	err = json.NewDecoder(r.Body).Decode(&sp)
+	if err != nil {
+		return nil, nil, NewAppError("DeleteScheduledPost", "api.unmarshal_error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+	return &sp, BuildResponse(r), nil
+}
+
+// Saved Searches Section
+
+// CreateSavedSearch saves a post search for the current user.
+func (c *Client4) CreateSavedSearch(ctx context.Context, savedSearch *SavedSearch) (*SavedSearch, *Response, error) {
+	buf, err := json.Marshal(savedSearch)
+	if err != nil {
+		return nil, nil, NewAppError("CreateSavedSearch", "api.marshal_error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+
+	r, err := c.DoAPIPostBytes(ctx, c.savedSearchesRoute(), buf)
+	if err != nil {
+		return nil, BuildResponse(r), err
+	}
+	defer closeBody(r)
+
+	var ss SavedSearch
+	err = json.NewDecoder(r.Body).Decode(&ss)
+	if err != nil {
+		return nil, nil, NewAppError("CreateSavedSearch", "api.unmarshal_error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+	return &ss, BuildResponse(r), nil
+}
+
+// GetSavedSearches returns the current user's saved searches for a team.
+func (c *Client4) GetSavedSearches(ctx context.Context, teamId string) ([]*SavedSearch, *Response, error) {
+	r, err := c.DoAPIGet(ctx, c.savedSearchesRoute()+c.teamRoute(teamId), "")
+	if err != nil {
+		return nil, BuildResponse(r), err
+	}
+	defer closeBody(r)
+
+	var savedSearches []*SavedSearch
+	err = json.NewDecoder(r.Body).Decode(&savedSearches)
+	if err != nil {
+		return nil, nil, NewAppError("GetSavedSearches", "api.unmarshal_error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+	return savedSearches, BuildResponse(r), nil
+}
+
+// GetSavedSearch returns a saved search of the current user.
+func (c *Client4) GetSavedSearch(ctx context.Context, savedSearchId string) (*SavedSearch, *Response, error) {
+	r, err := c.DoAPIGet(ctx, c.savedSearchRoute(savedSearchId), "")
+	if err != nil {
+		return nil, BuildResponse(r), err
+	}
+	defer closeBody(r)
+
+	var ss SavedSearch
+	err = json.NewDecoder(r.Body).Decode(&ss)
+	if err != nil {
+		return nil, nil, NewAppError("GetSavedSearch", "api.unmarshal_error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+	return &ss, BuildResponse(r), nil
+}
+
+// UpdateSavedSearch updates the name, terms and subscription of a saved search.
+func (c *Client4) UpdateSavedSearch(ctx context.Context, savedSearch *SavedSearch) (*SavedSearch, *Response, error) {
+	buf, err := json.Marshal(savedSearch)
+	if err != nil {
+		return nil, nil, NewAppError("UpdateSavedSearch", "api.marshal_error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+
+	r, err := c.DoAPIPutBytes(ctx, c.savedSearchRoute(savedSearch.Id), buf)
+	if err != nil {
+		return nil, BuildResponse(r), err
+	}
+	defer closeBody(r)
+
+	var ss SavedSearch
+	err = json.NewDecoder(r.Body).Decode(&ss)
+	if err != nil {
+		return nil, nil, NewAppError("UpdateSavedSearch", "api.unmarshal_error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+	return &ss, BuildResponse(r), nil
+}
+
+// DeleteSavedSearch deletes a saved search and returns the deleted record.
+func (c *Client4) DeleteSavedSearch(ctx context.Context, savedSearchId string) (*SavedSearch, *Response, error) {
+	r, err := c.DoAPIDelete(ctx, c.savedSearchRoute(savedSearchId))
+	if err != nil {
+		return nil, BuildResponse(r), err
+	}
+	defer closeBody(r)
+
+	var ss SavedSearch
+	err = json.NewDecoder(r.Body).Decode(&ss)
+	if err != nil {
+		return nil, nil, NewAppError("DeleteSavedSearch", "api.unmarshal_error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+	return &ss, BuildResponse(r), nil
+}
+
+// RunSavedSearch runs a saved search and returns a page of the posts it finds.
+func (c *Client4) RunSavedSearch(ctx context.Context, savedSearchId string, page, perPage int) (*PostSearchResults, *Response, error) {
+	query := fmt.Sprintf("?page=%v&per_page=%v", page, perPage)
+	r, err := c.DoAPIGet(ctx, c.savedSearchRoute(savedSearchId)+"/results"+query, "")
+	if err != nil {
+		return nil, BuildResponse(r), err
+	}
+	defer closeBody(r)
+
+	var psr PostSearchResults
+	if err := json.NewDecoder(r.Body).Decode(&psr); err != nil {
+		return nil, nil, NewAppError("RunSavedSearch", "api.unmarshal_error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+	return &psr, BuildResponse(r), nil
+}
+
 // Commands Section
 
 // CreateCommand will create a new command if the user have the right permissions.
@@ -6745,6 +7144,16 @@ func (c *Client4) RegenCommandToken(ctx context.Context, commandId string) (stri
 	return MapFromJSON(r.Body)["token"], BuildResponse(r), nil
 }
 
+// RegenCommandSigningSecret will create a new secret used to sign the command requests if the user have the right permissions.
+func (c *Client4) RegenCommandSigningSecret(ctx context.Context, commandId string) (string, *Response, error) {
+	r, err := c.DoAPIPut(ctx, c.commandRoute(commandId)+"/regen_signing_secret", "")
+	if err != nil {
+		return "", BuildResponse(r), err
+	}
+	defer closeBody(r)
+	return MapFromJSON(r.Body)["signing_secret"], BuildResponse(r), nil
+}
+
 // Status Section
 
 // GetUserStatus returns a user based on the provided user id string.
@@ -9067,6 +9476,67 @@ func (c *Client4) UnacknowledgePost(ctx context.Context, postId, userId string)
 	return BuildResponse(r), nil
 }
 
+// GetPostReadReceipts returns the users that have seen a post.
+func (c *Client4) GetPostReadReceipts(ctx context.Context, postId string) ([]*PostReadReceipt, *Response, error) {
+	r, err := c.DoAPIGet(ctx, c.postRoute(postId)+"/read_receipts", "")
+	if err != nil {
+		return nil, BuildResponse(r), err
+	}
+	defer closeBody(r)
+	var receipts []*PostReadReceipt
+	if jsonErr := json.NewDecoder(r.Body).Decode(&receipts); jsonErr != nil {
+		return nil, nil, NewAppError("GetPostReadReceipts", "api.unmarshal_error", nil, "", http.StatusInternalServerError).Wrap(jsonErr)
+	}
+	return receipts, BuildResponse(r), nil
+}
+
+// VoteOnPoll replaces the votes of the current user in a poll. An empty list of
+// options retracts the user's votes.
+func (c *Client4) VoteOnPoll(ctx context.Context, postId string, optionIds []string) (*PollMetadata, *Response, error) {
+	buf, err := json.Marshal(PollVoteRequest{OptionIds: optionIds})
+	if err != nil {
+		return nil, nil, NewAppError("VoteOnPoll", "api.marshal_error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+	r, err := c.DoAPIPostBytes(ctx, c.postRoute(postId)+"/poll/vote", buf)
+	if err != nil {
+		return nil, BuildResponse(r), err
+	}
+	defer closeBody(r)
+	var metadata *PollMetadata
+	if jsonErr := json.NewDecoder(r.Body).Decode(&metadata); jsonErr != nil {
+		return nil, nil, NewAppError("VoteOnPoll", "api.unmarshal_error", nil, "", http.StatusInternalServerError).Wrap(jsonErr)
+	}
+	return metadata, BuildResponse(r), nil
+}
+
+func (c *Client4) ClosePoll(ctx context.Context, postId string) (*PollMetadata, *Response, error) {
+	r, err := c.DoAPIPost(ctx, c.postRoute(postId)+"/poll/close", "")
+	if err != nil {
+		return nil, BuildResponse(r), err
+	}
+	defer closeBody(r)
+	var metadata *PollMetadata
+	if jsonErr := json.NewDecoder(r.Body).Decode(&metadata); jsonErr != nil {
+		return nil, nil, NewAppError("ClosePoll", "api.unmarshal_error", nil, "", http.StatusInternalServerError).Wrap(jsonErr)
+	}
+	return metadata, BuildResponse(r), nil
+}
+
+// TranslatePost returns a post with the machine translation of its message to the given
+// language included in its metadata.
+func (c *Client4) TranslatePost(ctx context.Context, postId, lang string) (*Post, *Response, error) {
+	r, err := c.DoAPIGet(ctx, c.postRoute(postId)+"/translate?lang="+url.QueryEscape(lang), "")
+	if err != nil {
+		return nil, BuildResponse(r), err
+	}
+	defer closeBody(r)
+	var post Post
+	if jsonErr := json.NewDecoder(r.Body).Decode(&post); jsonErr != nil {
+		return nil, nil, NewAppError("TranslatePost", "api.unmarshal_error", nil, "", http.StatusInternalServerError).Wrap(jsonErr)
+	}
+	return &post, BuildResponse(r), nil
+}
+
 func (c *Client4) AddUserToGroupSyncables(ctx context.Context, userID string) (*Response, error) {
 	r, err := c.DoAPIPost(ctx, c.ldapRoute()+"/users/"+userID+"/group_sync_memberships", "")
 	if err != nil {
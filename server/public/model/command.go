@@ -18,6 +18,7 @@ const (
 type Command struct {
 	Id               string `json:"id"`
 	Token            string `json:"token"`
+	SigningSecret    string `json:"signing_secret"`
 	CreateAt         int64  `json:"create_at"`
 	UpdateAt         int64  `json:"update_at"`
 	DeleteAt         int64  `json:"delete_at"`
@@ -70,6 +71,10 @@ func (o *Command) IsValid() *AppError {
 		return NewAppError("Command.IsValid", "model.command.is_valid.token.app_error", nil, "", http.StatusBadRequest)
 	}
 
+	if o.SigningSecret != "" && len(o.SigningSecret) != IntegrationSigningSecretLength {
+		return NewAppError("Command.IsValid", "model.command.is_valid.signing_secret.app_error", nil, "", http.StatusBadRequest)
+	}
+
 	if o.CreateAt == 0 {
 		return NewAppError("Command.IsValid", "model.command.is_valid.create_at.app_error", nil, "", http.StatusBadRequest)
 	}
@@ -138,6 +143,10 @@ func (o *Command) PreSave() {
 		o.Token = NewId()
 	}
 
+	if o.SigningSecret == "" {
+		o.SigningSecret = NewIntegrationSigningSecret()
+	}
+
 	o.CreateAt = GetMillis()
 	o.UpdateAt = o.CreateAt
 }
@@ -148,6 +157,7 @@ func (o *Command) PreUpdate() {
 
 func (o *Command) Sanitize() {
 	o.Token = ""
+	o.SigningSecret = ""
 	o.CreatorId = ""
 	o.Method = ""
 	o.URL = ""
@@ -5,6 +5,7 @@ package model
 
 import (
 	"regexp"
+	"strconv"
 	"time"
 )
 
@@ -19,6 +20,9 @@ type CompliancePost struct {
 	ChannelDisplayName string
 	ChannelType        string
 
+	// ChannelPostExpiryMinutes is the post expiry policy of the channel at export time.
+	ChannelPostExpiryMinutes int64
+
 	// From User
 	UserUsername string
 	UserEmail    string
@@ -29,6 +33,7 @@ type CompliancePost struct {
 	PostCreateAt   int64
 	PostUpdateAt   int64
 	PostDeleteAt   int64
+	PostExpireAt   int64
 	PostRootId     string
 	PostOriginalId string
 	PostMessage    string
@@ -65,6 +70,11 @@ func CompliancePostHeader() []string {
 		"PostProps",
 		"PostHashtags",
 		"PostFileIds",
+
+		// Columns added after the original export format are appended so that existing
+		// consumers of the CSV keep reading the same columns.
+		"ChannelPostExpiryMinutes",
+		"PostExpireAt",
 	}
 }
 
@@ -86,6 +96,16 @@ func (cp *CompliancePost) Row() []string {
 		postUpdateAt = time.Unix(0, cp.PostUpdateAt*int64(1000*1000)).Format(time.RFC3339)
 	}
 
+	channelPostExpiryMinutes := ""
+	if cp.ChannelPostExpiryMinutes > 0 {
+		channelPostExpiryMinutes = strconv.FormatInt(cp.ChannelPostExpiryMinutes, 10)
+	}
+
+	postExpireAt := ""
+	if cp.PostExpireAt > 0 {
+		postExpireAt = time.Unix(0, cp.PostExpireAt*int64(1000*1000)).Format(time.RFC3339)
+	}
+
 	userType := "user"
 	if cp.IsBot {
 		userType = "bot"
@@ -108,7 +128,6 @@ func (cp *CompliancePost) Row() []string {
 		time.Unix(0, cp.PostCreateAt*int64(1000*1000)).Format(time.RFC3339),
 		postUpdateAt,
 		postDeleteAt,
-
 		cp.PostRootId,
 		cp.PostOriginalId,
 		cleanComplianceStrings(cp.PostMessage),
@@ -116,5 +135,8 @@ func (cp *CompliancePost) Row() []string {
 		cp.PostProps,
 		cp.PostHashtags,
 		cp.PostFileIds,
+
+		channelPostExpiryMinutes,
+		postExpireAt,
 	}
 }
@@ -10,7 +10,10 @@ import (
 )
 
 func TestCompliancePostHeader(t *testing.T) {
-	require.Equal(t, "TeamName", CompliancePostHeader()[0])
+	header := CompliancePostHeader()
+	require.Equal(t, "TeamName", header[0])
+	require.Equal(t, "PostFileIds", header[19])
+	require.Equal(t, []string{"ChannelPostExpiryMinutes", "PostExpireAt"}, header[20:])
 }
 
 func TestCompliancePost(t *testing.T) {
@@ -18,7 +21,31 @@ func TestCompliancePost(t *testing.T) {
 	r := o.Row()
 
 	require.Equal(t, "test", r[0])
-	require.Equal(t, "files", r[len(r)-1])
+	require.Equal(t, "files", r[19])
+	require.Len(t, r, len(CompliancePostHeader()))
+}
+
+func TestCompliancePostExpiry(t *testing.T) {
+	header := CompliancePostHeader()
+	indexOf := func(name string) int {
+		for i, h := range header {
+			if h == name {
+				return i
+			}
+		}
+		require.Failf(t, "missing header", "header %s not found", name)
+		return -1
+	}
+
+	o := CompliancePost{}
+	r := o.Row()
+	require.Equal(t, "", r[indexOf("ChannelPostExpiryMinutes")])
+	require.Equal(t, "", r[indexOf("PostExpireAt")])
+
+	o = CompliancePost{ChannelPostExpiryMinutes: 60, PostExpireAt: 1}
+	r = o.Row()
+	require.Equal(t, "60", r[indexOf("ChannelPostExpiryMinutes")])
+	require.NotEmpty(t, r[indexOf("PostExpireAt")])
 }
 
 var cleanTests = []struct {
@@ -5,7 +5,10 @@ package model
 
 import (
 	"crypto/tls"
+	"encoding/base64"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"io"
 	"math"
 	"net"
@@ -33,6 +36,13 @@ const (
 
 	ImageDriverLocal = "local"
 	ImageDriverS3    = "amazons3"
+	ImageDriverAzure = "azureblob"
+	ImageDriverGCS   = "gcs"
+
+	FileEncryptionKeyProviderConfig   = "config"
+	FileEncryptionKeyProviderLocalKMS = "local_kms"
+	FileEncryptionKeySize             = 32
+	FileEncryptionKeyIdMaxLength      = 64
 
 	DatabaseDriverMysql    = "mysql"
 	DatabaseDriverPostgres = "postgres"
@@ -101,6 +111,9 @@ const (
 	CacheTypeLRU   = "lru"
 	CacheTypeRedis = "redis"
 
+	ClusterMessageTransportHTTP  = "http"
+	ClusterMessageTransportRedis = "redis"
+
 	SitenameMaxLength = 30
 
 	ServiceSettingsDefaultSiteURL                = "http://localhost:8065"
@@ -118,6 +131,8 @@ const (
 	ServiceSettingsDefaultMaxURLLength           = 2048
 	ServiceSettingsMaxUniqueReactionsPerPost     = 500
 
+	ServiceSettingsDefaultReadReceiptsMaxChannelMembers = 10
+
 	TeamSettingsDefaultSiteName              = "Mattermost"
 	TeamSettingsDefaultMaxUsersPerTeam       = 50
 	TeamSettingsDefaultCustomBrandText       = ""
@@ -129,6 +144,19 @@ const (
 	FileSettingsDefaultDirectory                   = "./data/"
 	FileSettingsDefaultS3UploadPartSizeBytes       = 5 * 1024 * 1024   // 5MB
 	FileSettingsDefaultS3ExportUploadPartSizeBytes = 100 * 1024 * 1024 // 100MB
+	FileSettingsDefaultSignedURLExpiresSeconds     = 21600             // 6h
+	FileSettingsDefaultEncryptionLocalKMSDirectory = "./kms/"
+	FileSettingsDefaultOCRCommand                  = "tesseract"
+	FileSettingsDefaultOCRLanguages                = "eng"
+	FileSettingsDefaultOCRTimeoutSeconds           = 60
+	FileSettingsDefaultImageConverterCommand       = "magick"
+	FileSettingsDefaultImageConverterTimeout       = 30
+	FileSettingsDefaultWebPPreviewQuality          = 80
+	FileSettingsDefaultFFmpegCommand               = "ffmpeg"
+	FileSettingsDefaultFFmpegTimeoutSeconds        = 30
+	FileSettingsDefaultMediaTranscodingTimeout     = 3600
+	FileSettingsDefaultFileCacheDirectory          = "./filecache/"
+	FileSettingsDefaultFileCacheMaxSizeMB          = 1024
 
 	ImportSettingsDefaultDirectory     = "./import"
 	ImportSettingsDefaultRetentionDays = 30
@@ -136,6 +164,20 @@ const (
 	ExportSettingsDefaultDirectory     = "./export"
 	ExportSettingsDefaultRetentionDays = 30
 
+	ScimSettingsMinimumTokenLength = 32
+
+	TranslationSettingsDefaultRequestTimeoutMilliseconds = 10000
+
+	VirusScanSettingsDefaultClamdAddress            = "tcp://localhost:3310"
+	VirusScanSettingsDefaultScanTimeoutMilliseconds = 60000
+
+	SemanticSearchSettingsDefaultEmbeddingDimensions        = 256
+	SemanticSearchSettingsMaxEmbeddingDimensions            = 4096
+	SemanticSearchSettingsDefaultRequestTimeoutMilliseconds = 10000
+	SemanticSearchSettingsDefaultLexicalWeightPercent       = 30
+	SemanticSearchSettingsDefaultMinimumScorePercent        = 10
+	SemanticSearchSettingsDefaultMaxCandidates              = 10000
+
 	EmailSettingsDefaultFeedbackOrganization = ""
 
 	SupportSettingsDefaultTermsOfServiceLink = "https://mattermost.com/pl/terms-of-use/"
@@ -247,6 +289,18 @@ const (
 	ImageProxyTypeLocal     = "local"
 	ImageProxyTypeAtmosCamo = "atmos/camo"
 
+	ImageProxySettingsDefaultResizedImageCacheDirectory = "./imagecache/"
+	ImageProxySettingsDefaultResizedImageCacheMaxSizeMB = 256
+
+	TranslationProviderLocal  = "local"
+	TranslationProviderHTTP   = "http"
+	TranslationProviderPlugin = "plugin"
+
+	VirusScanDriverClamd = "clamd"
+
+	EmbeddingProviderLocal = "local"
+	EmbeddingProviderHTTP  = "http"
+
 	GoogleSettingsDefaultScope           = "profile email"
 	GoogleSettingsDefaultAuthEndpoint    = "https://accounts.google.com/o/oauth2/v2/auth"
 	GoogleSettingsDefaultTokenEndpoint   = "https://www.googleapis.com/oauth2/v4/token"
@@ -411,6 +465,11 @@ type ServiceSettings struct {
 	ManagedResourcePaths                              *string `access:"environment_web_server,write_restrictable,cloud_restrictable"`
 	EnableCustomGroups                                *bool   `access:"site_users_and_teams"`
 	AllowSyncedDrafts                                 *bool   `access:"site_posts"`
+	ScheduledPosts                                    *bool   `access:"site_posts"`
+	EnableSavedSearches                               *bool   `access:"site_posts"`
+	EnablePostExpiry                                  *bool   `access:"site_posts"`
+	EnableReadReceipts                                *bool   `access:"site_posts"`
+	ReadReceiptsMaxChannelMembers                     *int    `access:"site_posts"`
 	UniqueEmojiReactionLimitPerPost                   *int    `access:"site_posts"`
 	RefreshPostStatsRunTime                           *string `access:"site_users_and_teams"`
 	MaximumPayloadSizeBytes                           *int64  `access:"environment_file_storage,write_restrictable,cloud_restrictable"`
@@ -911,6 +970,26 @@ func (s *ServiceSettings) SetDefaults(isUpdate bool) {
 		s.AllowSyncedDrafts = NewPointer(true)
 	}
 
+	if s.ScheduledPosts == nil {
+		s.ScheduledPosts = NewPointer(true)
+	}
+
+	if s.EnableSavedSearches == nil {
+		s.EnableSavedSearches = NewPointer(true)
+	}
+
+	if s.EnablePostExpiry == nil {
+		s.EnablePostExpiry = NewPointer(false)
+	}
+
+	if s.EnableReadReceipts == nil {
+		s.EnableReadReceipts = NewPointer(false)
+	}
+
+	if s.ReadReceiptsMaxChannelMembers == nil {
+		s.ReadReceiptsMaxChannelMembers = NewPointer(ServiceSettingsDefaultReadReceiptsMaxChannelMembers)
+	}
+
 	if s.UniqueEmojiReactionLimitPerPost == nil {
 		s.UniqueEmojiReactionLimitPerPost = NewPointer(ServiceSettingsDefaultUniqueReactionsPerPost)
 	}
@@ -985,6 +1064,7 @@ type ClusterSettings struct {
 	EnableExperimentalGossipEncryption *bool   `access:"environment_high_availability,write_restrictable,cloud_restrictable"`
 	ReadOnlyConfig                     *bool   `access:"environment_high_availability,write_restrictable,cloud_restrictable"`
 	GossipPort                         *int    `access:"environment_high_availability,write_restrictable,cloud_restrictable"` // telemetry: none
+	MessageTransport                   *string `access:"environment_high_availability,write_restrictable,cloud_restrictable"`
 }
 
 func (s *ClusterSettings) SetDefaults() {
@@ -1031,6 +1111,23 @@ func (s *ClusterSettings) SetDefaults() {
 	if s.GossipPort == nil {
 		s.GossipPort = NewPointer(8074)
 	}
+
+	if s.MessageTransport == nil {
+		s.MessageTransport = NewPointer(ClusterMessageTransportHTTP)
+	}
+}
+
+func (s *ClusterSettings) isValid(cacheSettings *CacheSettings) *AppError {
+	if *s.MessageTransport != ClusterMessageTransportHTTP && *s.MessageTransport != ClusterMessageTransportRedis {
+		return NewAppError("Config.IsValid", "model.config.is_valid.cluster_message_transport.app_error", map[string]any{"Value": *s.MessageTransport}, "", http.StatusBadRequest)
+	}
+
+	// The Redis transport shares the connections of the Redis cache.
+	if *s.MessageTransport == ClusterMessageTransportRedis && *cacheSettings.CacheType != CacheTypeRedis {
+		return NewAppError("Config.IsValid", "model.config.is_valid.cluster_redis_transport.app_error", nil, "", http.StatusBadRequest)
+	}
+
+	return nil
 }
 
 type MetricsSettings struct {
@@ -1462,6 +1559,13 @@ type ExperimentalAuditSettings struct {
 	FileCompress        *bool           `access:"experimental_features,write_restrictable,cloud_restrictable"`
 	FileMaxQueueSize    *int            `access:"experimental_features,write_restrictable,cloud_restrictable"`
 	AdvancedLoggingJSON json.RawMessage `access:"experimental_features,write_restrictable"`
+
+	// DatabaseEnabled additionally writes audit records to the database, where they can be
+	// searched.
+	DatabaseEnabled *bool `access:"experimental_features,write_restrictable,cloud_restrictable"`
+	// DatabaseMaxAgeDays is how long audit records are kept in the database, or 0 to keep
+	// them forever.
+	DatabaseMaxAgeDays *int `access:"experimental_features,write_restrictable,cloud_restrictable"`
 }
 
 func (s *ExperimentalAuditSettings) SetDefaults() {
@@ -1469,6 +1573,14 @@ func (s *ExperimentalAuditSettings) SetDefaults() {
 		s.FileEnabled = NewPointer(false)
 	}
 
+	if s.DatabaseEnabled == nil {
+		s.DatabaseEnabled = NewPointer(false)
+	}
+
+	if s.DatabaseMaxAgeDays == nil {
+		s.DatabaseMaxAgeDays = NewPointer(365)
+	}
+
 	if s.FileName == nil {
 		s.FileName = NewPointer("")
 	}
@@ -1613,6 +1725,21 @@ type FileSettings struct {
 	EnablePublicLink                   *bool   `access:"site_public_links,cloud_restrictable"`
 	ExtractContent                     *bool   `access:"environment_file_storage,write_restrictable"`
 	ArchiveRecursion                   *bool   `access:"environment_file_storage,write_restrictable"`
+	EnableOCR                          *bool   `access:"environment_file_storage,write_restrictable"`
+	OCRCommand                         *string `access:"environment_file_storage,write_restrictable,cloud_restrictable"` // telemetry: none
+	OCRLanguages                       *string `access:"environment_file_storage,write_restrictable"`
+	OCRTimeoutSeconds                  *int    `access:"environment_file_storage,write_restrictable"`
+	EnableImageConverter               *bool   `access:"environment_file_storage,write_restrictable"`
+	ImageConverterCommand              *string `access:"environment_file_storage,write_restrictable,cloud_restrictable"` // telemetry: none
+	ImageConverterTimeoutSeconds       *int    `access:"environment_file_storage,write_restrictable"`
+	EnableWebPPreviews                 *bool   `access:"environment_file_storage,write_restrictable"`
+	WebPPreviewQuality                 *int    `access:"environment_file_storage,write_restrictable"`
+	EnableFFmpeg                       *bool   `access:"environment_file_storage,write_restrictable"`
+	FFmpegCommand                      *string `access:"environment_file_storage,write_restrictable,cloud_restrictable"` // telemetry: none
+	FFmpegTimeoutSeconds               *int    `access:"environment_file_storage,write_restrictable"`
+	EnableMediaTranscoding             *bool   `access:"environment_file_storage,write_restrictable"`
+	MediaTranscodingTimeoutSeconds     *int    `access:"environment_file_storage,write_restrictable"`
+	EnableDeduplication                *bool   `access:"environment_file_storage,write_restrictable,cloud_restrictable"`
 	PublicLinkSalt                     *string `access:"site_public_links,cloud_restrictable"`                           // telemetry: none
 	InitialFont                        *string `access:"environment_file_storage,cloud_restrictable"`                    // telemetry: none
 	AmazonS3AccessKeyId                *string `access:"environment_file_storage,write_restrictable,cloud_restrictable"` // telemetry: none
@@ -1627,6 +1754,32 @@ type FileSettings struct {
 	AmazonS3Trace                      *bool   `access:"environment_file_storage,write_restrictable,cloud_restrictable"`
 	AmazonS3RequestTimeoutMilliseconds *int64  `access:"environment_file_storage,write_restrictable,cloud_restrictable"` // telemetry: none
 	AmazonS3UploadPartSizeBytes        *int64  `access:"environment_file_storage,write_restrictable,cloud_restrictable"` // telemetry: none
+	// Azure Blob Storage settings
+	AzureAccountName                *string `access:"environment_file_storage,write_restrictable,cloud_restrictable"` // telemetry: none
+	AzureAccountKey                 *string `access:"environment_file_storage,write_restrictable,cloud_restrictable"` // telemetry: none
+	AzureContainer                  *string `access:"environment_file_storage,write_restrictable,cloud_restrictable"` // telemetry: none
+	AzurePathPrefix                 *string `access:"environment_file_storage,write_restrictable,cloud_restrictable"` // telemetry: none
+	AzureEndpoint                   *string `access:"environment_file_storage,write_restrictable,cloud_restrictable"` // telemetry: none
+	AzureRequestTimeoutMilliseconds *int64  `access:"environment_file_storage,write_restrictable,cloud_restrictable"` // telemetry: none
+	AzureSignedURLExpiresSeconds    *int64  `access:"environment_file_storage,write_restrictable,cloud_restrictable"` // telemetry: none
+	// Google Cloud Storage settings
+	GCSBucket                     *string `access:"environment_file_storage,write_restrictable,cloud_restrictable"` // telemetry: none
+	GCSPathPrefix                 *string `access:"environment_file_storage,write_restrictable,cloud_restrictable"` // telemetry: none
+	GCSServiceAccountKey          *string `access:"environment_file_storage,write_restrictable,cloud_restrictable"` // telemetry: none
+	GCSEndpoint                   *string `access:"environment_file_storage,write_restrictable,cloud_restrictable"` // telemetry: none
+	GCSRequestTimeoutMilliseconds *int64  `access:"environment_file_storage,write_restrictable,cloud_restrictable"` // telemetry: none
+	GCSSignedURLExpiresSeconds    *int64  `access:"environment_file_storage,write_restrictable,cloud_restrictable"` // telemetry: none
+	// Local disk cache settings
+	EnableFileCache    *bool   `access:"environment_file_storage,write_restrictable,cloud_restrictable"`
+	FileCacheDirectory *string `access:"environment_file_storage,write_restrictable,cloud_restrictable"` // telemetry: none
+	FileCacheMaxSizeMB *int    `access:"environment_file_storage,write_restrictable,cloud_restrictable"`
+	// Encryption at rest settings
+	EncryptionEnabled           *bool   `access:"environment_file_storage,write_restrictable,cloud_restrictable"`
+	EncryptionKeyProvider       *string `access:"environment_file_storage,write_restrictable,cloud_restrictable"`
+	EncryptionKeys              *string `access:"environment_file_storage,write_restrictable,cloud_restrictable"` // telemetry: none
+	EncryptionActiveKeyId       *string `access:"environment_file_storage,write_restrictable,cloud_restrictable"` // telemetry: none
+	EncryptionLocalKMSDirectory *string `access:"environment_file_storage,write_restrictable,cloud_restrictable"` // telemetry: none
+	EncryptionMigrationMode     *bool   `access:"environment_file_storage,write_restrictable,cloud_restrictable"`
 	// Export store settings
 	DedicatedExportStore                     *bool   `access:"environment_file_storage,write_restrictable"`
 	ExportDriverName                         *string `access:"environment_file_storage,write_restrictable"`
@@ -1691,6 +1844,66 @@ func (s *FileSettings) SetDefaults(isUpdate bool) {
 		s.ArchiveRecursion = NewPointer(false)
 	}
 
+	if s.EnableOCR == nil {
+		s.EnableOCR = NewPointer(false)
+	}
+
+	if s.OCRCommand == nil {
+		s.OCRCommand = NewPointer(FileSettingsDefaultOCRCommand)
+	}
+
+	if s.OCRLanguages == nil {
+		s.OCRLanguages = NewPointer(FileSettingsDefaultOCRLanguages)
+	}
+
+	if s.OCRTimeoutSeconds == nil {
+		s.OCRTimeoutSeconds = NewPointer(FileSettingsDefaultOCRTimeoutSeconds)
+	}
+
+	if s.EnableImageConverter == nil {
+		s.EnableImageConverter = NewPointer(false)
+	}
+
+	if s.ImageConverterCommand == nil {
+		s.ImageConverterCommand = NewPointer(FileSettingsDefaultImageConverterCommand)
+	}
+
+	if s.ImageConverterTimeoutSeconds == nil {
+		s.ImageConverterTimeoutSeconds = NewPointer(FileSettingsDefaultImageConverterTimeout)
+	}
+
+	if s.EnableWebPPreviews == nil {
+		s.EnableWebPPreviews = NewPointer(false)
+	}
+
+	if s.WebPPreviewQuality == nil {
+		s.WebPPreviewQuality = NewPointer(FileSettingsDefaultWebPPreviewQuality)
+	}
+
+	if s.EnableFFmpeg == nil {
+		s.EnableFFmpeg = NewPointer(false)
+	}
+
+	if s.FFmpegCommand == nil {
+		s.FFmpegCommand = NewPointer(FileSettingsDefaultFFmpegCommand)
+	}
+
+	if s.FFmpegTimeoutSeconds == nil {
+		s.FFmpegTimeoutSeconds = NewPointer(FileSettingsDefaultFFmpegTimeoutSeconds)
+	}
+
+	if s.EnableMediaTranscoding == nil {
+		s.EnableMediaTranscoding = NewPointer(false)
+	}
+
+	if s.MediaTranscodingTimeoutSeconds == nil {
+		s.MediaTranscodingTimeoutSeconds = NewPointer(FileSettingsDefaultMediaTranscodingTimeout)
+	}
+
+	if s.EnableDeduplication == nil {
+		s.EnableDeduplication = NewPointer(false)
+	}
+
 	if isUpdate {
 		// When updating an existing configuration, ensure link salt has been specified.
 		if s.PublicLinkSalt == nil || *s.PublicLinkSalt == "" {
@@ -1756,6 +1969,94 @@ func (s *FileSettings) SetDefaults(isUpdate bool) {
 		s.AmazonS3UploadPartSizeBytes = NewPointer(int64(FileSettingsDefaultS3UploadPartSizeBytes))
 	}
 
+	if s.AzureAccountName == nil {
+		s.AzureAccountName = NewPointer("")
+	}
+
+	if s.AzureAccountKey == nil {
+		s.AzureAccountKey = NewPointer("")
+	}
+
+	if s.AzureContainer == nil {
+		s.AzureContainer = NewPointer("")
+	}
+
+	if s.AzurePathPrefix == nil {
+		s.AzurePathPrefix = NewPointer("")
+	}
+
+	if s.AzureEndpoint == nil {
+		s.AzureEndpoint = NewPointer("")
+	}
+
+	if s.AzureRequestTimeoutMilliseconds == nil {
+		s.AzureRequestTimeoutMilliseconds = NewPointer(int64(30000))
+	}
+
+	if s.AzureSignedURLExpiresSeconds == nil {
+		s.AzureSignedURLExpiresSeconds = NewPointer(int64(FileSettingsDefaultSignedURLExpiresSeconds))
+	}
+
+	if s.GCSBucket == nil {
+		s.GCSBucket = NewPointer("")
+	}
+
+	if s.GCSPathPrefix == nil {
+		s.GCSPathPrefix = NewPointer("")
+	}
+
+	if s.GCSServiceAccountKey == nil {
+		s.GCSServiceAccountKey = NewPointer("")
+	}
+
+	if s.GCSEndpoint == nil {
+		s.GCSEndpoint = NewPointer("")
+	}
+
+	if s.GCSRequestTimeoutMilliseconds == nil {
+		s.GCSRequestTimeoutMilliseconds = NewPointer(int64(30000))
+	}
+
+	if s.GCSSignedURLExpiresSeconds == nil {
+		s.GCSSignedURLExpiresSeconds = NewPointer(int64(FileSettingsDefaultSignedURLExpiresSeconds))
+	}
+
+	if s.EnableFileCache == nil {
+		s.EnableFileCache = NewPointer(false)
+	}
+
+	if s.FileCacheDirectory == nil || *s.FileCacheDirectory == "" {
+		s.FileCacheDirectory = NewPointer(FileSettingsDefaultFileCacheDirectory)
+	}
+
+	if s.FileCacheMaxSizeMB == nil {
+		s.FileCacheMaxSizeMB = NewPointer(FileSettingsDefaultFileCacheMaxSizeMB)
+	}
+
+	if s.EncryptionEnabled == nil {
+		s.EncryptionEnabled = NewPointer(false)
+	}
+
+	if s.EncryptionKeyProvider == nil {
+		s.EncryptionKeyProvider = NewPointer(FileEncryptionKeyProviderConfig)
+	}
+
+	if s.EncryptionKeys == nil {
+		s.EncryptionKeys = NewPointer("")
+	}
+
+	if s.EncryptionActiveKeyId == nil {
+		s.EncryptionActiveKeyId = NewPointer("")
+	}
+
+	if s.EncryptionLocalKMSDirectory == nil || *s.EncryptionLocalKMSDirectory == "" {
+		s.EncryptionLocalKMSDirectory = NewPointer(FileSettingsDefaultEncryptionLocalKMSDirectory)
+	}
+
+	if s.EncryptionMigrationMode == nil {
+		s.EncryptionMigrationMode = NewPointer(false)
+	}
+
 	if s.DedicatedExportStore == nil {
 		s.DedicatedExportStore = NewPointer(false)
 	}
@@ -1998,6 +2299,13 @@ func (s *EmailSettings) SetDefaults(isUpdate bool) {
 	}
 }
 
+// RateLimitQuota is the rate and burst allowed for a subset of the requests, overriding
+// the ones of RateLimitSettings.
+type RateLimitQuota struct {
+	PerSec   int
+	MaxBurst int
+}
+
 type RateLimitSettings struct {
 	Enable           *bool  `access:"environment_rate_limiting,write_restrictable,cloud_restrictable"`
 	PerSec           *int   `access:"environment_rate_limiting,write_restrictable,cloud_restrictable"`
@@ -2006,6 +2314,16 @@ type RateLimitSettings struct {
 	VaryByRemoteAddr *bool  `access:"environment_rate_limiting,write_restrictable,cloud_restrictable"`
 	VaryByUser       *bool  `access:"environment_rate_limiting,write_restrictable,cloud_restrictable"`
 	VaryByHeader     string `access:"environment_rate_limiting,write_restrictable,cloud_restrictable"`
+
+	// RouteQuotas overrides the quota of the requests to the given route or below it, such
+	// as "/api/v4/users/login". Routes match whole path segments, and the longest matching
+	// route wins. They apply per remote address as well as per user.
+	RouteQuotas map[string]*RateLimitQuota `access:"environment_rate_limiting,write_restrictable,cloud_restrictable"`
+
+	// TokenQuotas overrides the per-user quota of the requests authenticated with the
+	// personal access token of the given id. Like the per-user quota, they only apply when
+	// VaryByUser is set.
+	TokenQuotas map[string]*RateLimitQuota `access:"environment_rate_limiting,write_restrictable,cloud_restrictable"`
 }
 
 func (s *RateLimitSettings) SetDefaults() {
@@ -2032,6 +2350,14 @@ func (s *RateLimitSettings) SetDefaults() {
 	if s.VaryByUser == nil {
 		s.VaryByUser = NewPointer(false)
 	}
+
+	if s.RouteQuotas == nil {
+		s.RouteQuotas = make(map[string]*RateLimitQuota)
+	}
+
+	if s.TokenQuotas == nil {
+		s.TokenQuotas = make(map[string]*RateLimitQuota)
+	}
 }
 
 type PrivacySettings struct {
@@ -2757,6 +3083,48 @@ func (s *SamlSettings) SetDefaults() {
 	}
 }
 
+// ScimSettings defines configuration settings for the SCIM 2.0 user and group provisioning endpoint.
+type ScimSettings struct {
+	Enable *bool `access:"authentication_saml"`
+	// The bearer token identity providers must present to call the SCIM endpoint.
+	Token *string `access:"authentication_saml,write_restrictable,cloud_restrictable"` // telemetry: none
+	// The auth service assigned to provisioned users, matched against their SCIM externalId.
+	AuthService *string `access:"authentication_saml"`
+}
+
+func (s *ScimSettings) SetDefaults() {
+	if s.Enable == nil {
+		s.Enable = NewPointer(false)
+	}
+
+	if s.Token == nil {
+		s.Token = NewPointer("")
+	}
+
+	if s.AuthService == nil {
+		s.AuthService = NewPointer(UserAuthServiceSaml)
+	}
+}
+
+func (s *ScimSettings) isValid() *AppError {
+	if !*s.Enable {
+		return nil
+	}
+
+	// The sanitized token is long enough to pass the length check, but must never become the token.
+	if len(*s.Token) < ScimSettingsMinimumTokenLength || *s.Token == FakeSetting {
+		return NewAppError("Config.IsValid", "model.config.is_valid.scim_token.app_error", map[string]any{"MinLength": ScimSettingsMinimumTokenLength}, "", http.StatusBadRequest)
+	}
+
+	switch *s.AuthService {
+	case UserAuthServiceSaml, UserAuthServiceLdap, ServiceOpenid, ServiceGitlab, ServiceGoogle, ServiceOffice365:
+	default:
+		return NewAppError("Config.IsValid", "model.config.is_valid.scim_auth_service.app_error", nil, "", http.StatusBadRequest)
+	}
+
+	return nil
+}
+
 type NativeAppSettings struct {
 	AppCustomURLSchemes    []string `access:"site_customization,write_restrictable,cloud_restrictable"` // telemetry: none
 	AppDownloadLink        *string  `access:"site_customization,write_restrictable,cloud_restrictable"`
@@ -2949,6 +3317,118 @@ func (bs *BleveSettings) SetDefaults() {
 	}
 }
 
+// SemanticSearchSettings defines configuration settings for the semantic search of posts, which
+// ranks posts by the similarity of their embeddings to the embedding of the search terms.
+type SemanticSearchSettings struct {
+	EnableIndexing  *bool `access:"experimental_bleve"`
+	EnableSearching *bool `access:"experimental_bleve"`
+	// The provider computing the embeddings: local or http. The local provider hashes the words
+	// of the text and is only meant for testing.
+	EmbeddingProvider *string `access:"experimental_bleve"`
+	// The URL of the OpenAI compatible embeddings endpoint used by the http provider.
+	EmbeddingURL    *string `access:"experimental_bleve,write_restrictable,cloud_restrictable"` // telemetry: none
+	EmbeddingAPIKey *string `access:"experimental_bleve,write_restrictable,cloud_restrictable"` // telemetry: none
+	EmbeddingModel  *string `access:"experimental_bleve"`                                       // telemetry: none
+	// The size of the embeddings computed by the local provider.
+	EmbeddingDimensions *int `access:"experimental_bleve"`
+	// The maximum time to wait for the http provider to compute embeddings.
+	RequestTimeoutMilliseconds *int `access:"experimental_bleve"`
+	// How much the words matching the search terms weigh in the score of a post, the rest of
+	// the score being the similarity of the embeddings.
+	LexicalWeightPercent *int `access:"experimental_bleve"`
+	// The score below which posts aren't returned.
+	MinimumScorePercent *int `access:"experimental_bleve"`
+	// The maximum number of posts scored for a search, the most recent posts matching the
+	// filters of the search being scored first.
+	MaxCandidates *int `access:"experimental_bleve"`
+}
+
+func (s *SemanticSearchSettings) SetDefaults() {
+	if s.EnableIndexing == nil {
+		s.EnableIndexing = NewPointer(false)
+	}
+
+	if s.EnableSearching == nil {
+		s.EnableSearching = NewPointer(false)
+	}
+
+	if s.EmbeddingProvider == nil {
+		s.EmbeddingProvider = NewPointer(EmbeddingProviderLocal)
+	}
+
+	if s.EmbeddingURL == nil {
+		s.EmbeddingURL = NewPointer("")
+	}
+
+	if s.EmbeddingAPIKey == nil {
+		s.EmbeddingAPIKey = NewPointer("")
+	}
+
+	if s.EmbeddingModel == nil {
+		s.EmbeddingModel = NewPointer("")
+	}
+
+	if s.EmbeddingDimensions == nil {
+		s.EmbeddingDimensions = NewPointer(SemanticSearchSettingsDefaultEmbeddingDimensions)
+	}
+
+	if s.RequestTimeoutMilliseconds == nil {
+		s.RequestTimeoutMilliseconds = NewPointer(SemanticSearchSettingsDefaultRequestTimeoutMilliseconds)
+	}
+
+	if s.LexicalWeightPercent == nil {
+		s.LexicalWeightPercent = NewPointer(SemanticSearchSettingsDefaultLexicalWeightPercent)
+	}
+
+	if s.MinimumScorePercent == nil {
+		s.MinimumScorePercent = NewPointer(SemanticSearchSettingsDefaultMinimumScorePercent)
+	}
+
+	if s.MaxCandidates == nil {
+		s.MaxCandidates = NewPointer(SemanticSearchSettingsDefaultMaxCandidates)
+	}
+}
+
+func (s *SemanticSearchSettings) isValid() *AppError {
+	if !*s.EnableIndexing {
+		if *s.EnableSearching {
+			return NewAppError("Config.IsValid", "model.config.is_valid.semantic_search.enable_searching.app_error", nil, "", http.StatusBadRequest)
+		}
+		return nil
+	}
+
+	switch *s.EmbeddingProvider {
+	case EmbeddingProviderLocal:
+		if *s.EmbeddingDimensions <= 0 || *s.EmbeddingDimensions > SemanticSearchSettingsMaxEmbeddingDimensions {
+			return NewAppError("Config.IsValid", "model.config.is_valid.semantic_search.embedding_dimensions.app_error", map[string]any{"Max": SemanticSearchSettingsMaxEmbeddingDimensions}, "", http.StatusBadRequest)
+		}
+	case EmbeddingProviderHTTP:
+		if !IsValidHTTPURL(*s.EmbeddingURL) {
+			return NewAppError("Config.IsValid", "model.config.is_valid.semantic_search.embedding_url.app_error", nil, "", http.StatusBadRequest)
+		}
+	default:
+		return NewAppError("Config.IsValid", "model.config.is_valid.semantic_search.embedding_provider.app_error", nil, "", http.StatusBadRequest)
+	}
+
+	if *s.RequestTimeoutMilliseconds <= 0 {
+		return NewAppError("Config.IsValid", "model.config.is_valid.semantic_search.request_timeout.app_error", nil, "", http.StatusBadRequest)
+	}
+
+	if *s.LexicalWeightPercent < 0 || *s.LexicalWeightPercent > 100 {
+		return NewAppError("Config.IsValid", "model.config.is_valid.semantic_search.lexical_weight.app_error", nil, "", http.StatusBadRequest)
+	}
+
+	if *s.MinimumScorePercent < 0 || *s.MinimumScorePercent > 100 {
+		return NewAppError("Config.IsValid", "model.config.is_valid.semantic_search.minimum_score.app_error", nil, "", http.StatusBadRequest)
+	}
+
+	if *s.MaxCandidates <= 0 {
+		return NewAppError("Config.IsValid", "model.config.is_valid.semantic_search.max_candidates.app_error", nil, "", http.StatusBadRequest)
+	}
+
+	return nil
+}
+
 type DataRetentionSettings struct {
 	EnableMessageDeletion          *bool   `access:"compliance_data_retention_policy"`
 	EnableFileDeletion             *bool   `access:"compliance_data_retention_policy"`
@@ -3370,6 +3850,15 @@ type ImageProxySettings struct {
 	ImageProxyType          *string `access:"environment_image_proxy"`
 	RemoteImageProxyURL     *string `access:"environment_image_proxy"`
 	RemoteImageProxyOptions *string `access:"environment_image_proxy"`
+	// Resizing of the images served by the local image proxy to the width, height and fit
+	// requested by the proxied URLs. The resized images are kept in a cache of at most
+	// ResizedImageCacheMaxSizeMB in ResizedImageCacheDirectory.
+	EnableResizing             *bool   `access:"environment_image_proxy"`
+	ResizedImageCacheDirectory *string `access:"environment_image_proxy,write_restrictable,cloud_restrictable"` // telemetry: none
+	ResizedImageCacheMaxSizeMB *int    `access:"environment_image_proxy,write_restrictable,cloud_restrictable"`
+	// Whether the resized images are encoded in WebP for the clients accepting it. This
+	// requires the image converter to be enabled.
+	EnableWebP *bool `access:"environment_image_proxy"`
 }
 
 func (s *ImageProxySettings) SetDefaults() {
@@ -3388,6 +3877,144 @@ func (s *ImageProxySettings) SetDefaults() {
 	if s.RemoteImageProxyOptions == nil {
 		s.RemoteImageProxyOptions = NewPointer("")
 	}
+
+	if s.EnableResizing == nil {
+		s.EnableResizing = NewPointer(false)
+	}
+
+	if s.ResizedImageCacheDirectory == nil || *s.ResizedImageCacheDirectory == "" {
+		s.ResizedImageCacheDirectory = NewPointer(ImageProxySettingsDefaultResizedImageCacheDirectory)
+	}
+
+	if s.ResizedImageCacheMaxSizeMB == nil {
+		s.ResizedImageCacheMaxSizeMB = NewPointer(ImageProxySettingsDefaultResizedImageCacheMaxSizeMB)
+	}
+
+	if s.EnableWebP == nil {
+		s.EnableWebP = NewPointer(false)
+	}
+}
+
+// TranslationSettings defines configuration settings for the machine translation of posts.
+type TranslationSettings struct {
+	Enable *bool `access:"site_localization"`
+	// The provider used to translate posts: local, http or plugin. The local provider does not
+	// translate anything and is only meant for testing.
+	Provider *string `access:"site_localization"`
+	// The URL of the LibreTranslate compatible service used by the http provider.
+	ProviderURL *string `access:"site_localization,write_restrictable,cloud_restrictable"` // telemetry: none
+	APIKey      *string `access:"site_localization,write_restrictable,cloud_restrictable"` // telemetry: none
+	// The maximum time to wait for the http provider to translate a post.
+	RequestTimeoutMilliseconds *int `access:"site_localization"`
+}
+
+func (s *TranslationSettings) SetDefaults() {
+	if s.Enable == nil {
+		s.Enable = NewPointer(false)
+	}
+
+	if s.Provider == nil {
+		s.Provider = NewPointer(TranslationProviderLocal)
+	}
+
+	if s.ProviderURL == nil {
+		s.ProviderURL = NewPointer("")
+	}
+
+	if s.APIKey == nil {
+		s.APIKey = NewPointer("")
+	}
+
+	if s.RequestTimeoutMilliseconds == nil {
+		s.RequestTimeoutMilliseconds = NewPointer(TranslationSettingsDefaultRequestTimeoutMilliseconds)
+	}
+}
+
+func (s *TranslationSettings) isValid() *AppError {
+	if !*s.Enable {
+		return nil
+	}
+
+	switch *s.Provider {
+	case TranslationProviderLocal, TranslationProviderPlugin:
+	case TranslationProviderHTTP:
+		if !IsValidHTTPURL(*s.ProviderURL) {
+			return NewAppError("Config.IsValid", "model.config.is_valid.translation_provider_url.app_error", nil, "", http.StatusBadRequest)
+		}
+	default:
+		return NewAppError("Config.IsValid", "model.config.is_valid.translation_provider.app_error", nil, "", http.StatusBadRequest)
+	}
+
+	if *s.RequestTimeoutMilliseconds <= 0 {
+		return NewAppError("Config.IsValid", "model.config.is_valid.translation_request_timeout.app_error", nil, "", http.StatusBadRequest)
+	}
+
+	return nil
+}
+
+// VirusScanSettings defines configuration settings for the virus scanning of uploaded files.
+type VirusScanSettings struct {
+	Enable *bool `access:"environment_file_storage,write_restrictable,cloud_restrictable"`
+	// The scanner used to scan files. Only clamd is supported.
+	Driver *string `access:"environment_file_storage,write_restrictable,cloud_restrictable"`
+	// The address of the clamd daemon, either tcp://host:port or unix:///path/to/clamd.sock.
+	ClamdAddress *string `access:"environment_file_storage,write_restrictable,cloud_restrictable"` // telemetry: none
+	// The maximum time to wait for a file to be scanned.
+	ScanTimeoutMilliseconds *int `access:"environment_file_storage,write_restrictable,cloud_restrictable"`
+}
+
+func (s *VirusScanSettings) SetDefaults() {
+	if s.Enable == nil {
+		s.Enable = NewPointer(false)
+	}
+
+	if s.Driver == nil {
+		s.Driver = NewPointer(VirusScanDriverClamd)
+	}
+
+	if s.ClamdAddress == nil {
+		s.ClamdAddress = NewPointer(VirusScanSettingsDefaultClamdAddress)
+	}
+
+	if s.ScanTimeoutMilliseconds == nil {
+		s.ScanTimeoutMilliseconds = NewPointer(VirusScanSettingsDefaultScanTimeoutMilliseconds)
+	}
+}
+
+func (s *VirusScanSettings) isValid() *AppError {
+	if !*s.Enable {
+		return nil
+	}
+
+	if *s.Driver != VirusScanDriverClamd {
+		return NewAppError("Config.IsValid", "model.config.is_valid.virus_scan_driver.app_error", nil, "", http.StatusBadRequest)
+	}
+
+	if !IsValidClamdAddress(*s.ClamdAddress) {
+		return NewAppError("Config.IsValid", "model.config.is_valid.virus_scan_clamd_address.app_error", nil, "", http.StatusBadRequest)
+	}
+
+	if *s.ScanTimeoutMilliseconds <= 0 {
+		return NewAppError("Config.IsValid", "model.config.is_valid.virus_scan_timeout.app_error", nil, "", http.StatusBadRequest)
+	}
+
+	return nil
+}
+
+// IsValidClamdAddress reports whether address is a tcp://host:port or unix:///path address.
+func IsValidClamdAddress(address string) bool {
+	u, err := url.Parse(address)
+	if err != nil {
+		return false
+	}
+
+	switch u.Scheme {
+	case "tcp":
+		return u.Hostname() != "" && u.Port() != ""
+	case "unix":
+		return u.Host == "" && u.Path != ""
+	}
+	return false
 }
 
 // ImportSettings defines configuration settings for file imports.
@@ -3520,6 +4147,7 @@ type Config struct {
 	ComplianceSettings        ComplianceSettings
 	LocalizationSettings      LocalizationSettings
 	SamlSettings              SamlSettings
+	ScimSettings              ScimSettings
 	NativeAppSettings         NativeAppSettings
 	CacheSettings             CacheSettings
 	ClusterSettings           ClusterSettings
@@ -3528,6 +4156,7 @@ type Config struct {
 	AnalyticsSettings         AnalyticsSettings
 	ElasticsearchSettings     ElasticsearchSettings
 	BleveSettings             BleveSettings
+	SemanticSearchSettings    SemanticSearchSettings
 	DataRetentionSettings     DataRetentionSettings
 	MessageExportSettings     MessageExportSettings
 	JobSettings               JobSettings
@@ -3535,6 +4164,8 @@ type Config struct {
 	DisplaySettings           DisplaySettings
 	GuestAccountsSettings     GuestAccountsSettings
 	ImageProxySettings        ImageProxySettings
+	TranslationSettings       TranslationSettings
+	VirusScanSettings         VirusScanSettings
 	CloudSettings             CloudSettings  // telemetry: none
 	FeatureFlags              *FeatureFlags  `access:"*_read" json:",omitempty"`
 	ImportSettings            ImportSettings // telemetry: none
@@ -3603,6 +4234,7 @@ func (o *Config) SetDefaults() {
 
 	o.LdapSettings.SetDefaults()
 	o.SamlSettings.SetDefaults()
+	o.ScimSettings.SetDefaults()
 
 	if o.TeamSettings.TeammateNameDisplay == nil {
 		o.TeamSettings.TeammateNameDisplay = NewPointer(ShowUsername)
@@ -3637,6 +4269,7 @@ func (o *Config) SetDefaults() {
 	o.LocalizationSettings.SetDefaults()
 	o.ElasticsearchSettings.SetDefaults()
 	o.BleveSettings.SetDefaults()
+	o.SemanticSearchSettings.SetDefaults()
 	o.NativeAppSettings.SetDefaults()
 	o.DataRetentionSettings.SetDefaults()
 	o.RateLimitSettings.SetDefaults()
@@ -3648,6 +4281,8 @@ func (o *Config) SetDefaults() {
 	o.DisplaySettings.SetDefaults()
 	o.GuestAccountsSettings.SetDefaults()
 	o.ImageProxySettings.SetDefaults()
+	o.TranslationSettings.SetDefaults()
+	o.VirusScanSettings.SetDefaults()
 	o.CloudSettings.SetDefaults()
 	if o.FeatureFlags == nil {
 		o.FeatureFlags = &FeatureFlags{}
@@ -3671,6 +4306,10 @@ func (o *Config) IsValid() *AppError {
 		return appErr
 	}
 
+	if appErr := o.ClusterSettings.isValid(&o.CacheSettings); appErr != nil {
+		return appErr
+	}
+
 	if *o.ServiceSettings.SiteURL == "" && *o.ServiceSettings.AllowCookiesForSubdomains {
 		return NewAppError("Config.IsValid", "model.config.is_valid.allow_cookies_for_subdomains.app_error", nil, "", http.StatusBadRequest)
 	}
@@ -3703,6 +4342,10 @@ func (o *Config) IsValid() *AppError {
 		return appErr
 	}
 
+	if appErr := o.ScimSettings.isValid(); appErr != nil {
+		return appErr
+	}
+
 	if *o.PasswordSettings.MinimumLength < PasswordMinimumLength || *o.PasswordSettings.MinimumLength > PasswordMaximumLength {
 		return NewAppError("Config.IsValid", "model.config.is_valid.password_length.app_error", map[string]any{"MinLength": PasswordMinimumLength, "MaxLength": PasswordMaximumLength}, "", http.StatusBadRequest)
 	}
@@ -3723,6 +4366,10 @@ func (o *Config) IsValid() *AppError {
 		return appErr
 	}
 
+	if appErr := o.SemanticSearchSettings.isValid(); appErr != nil {
+		return appErr
+	}
+
 	if appErr := o.DataRetentionSettings.isValid(); appErr != nil {
 		return appErr
 	}
@@ -3747,6 +4394,14 @@ func (o *Config) IsValid() *AppError {
 		return appErr
 	}
 
+	if appErr := o.TranslationSettings.isValid(); appErr != nil {
+		return appErr
+	}
+
+	if appErr := o.VirusScanSettings.isValid(); appErr != nil {
+		return appErr
+	}
+
 	if appErr := o.ImportSettings.isValid(); appErr != nil {
 		return appErr
 	}
@@ -3839,7 +4494,17 @@ func (s *FileSettings) isValid() *AppError {
 		return NewAppError("Config.IsValid", "model.config.is_valid.max_file_size.app_error", nil, "", http.StatusBadRequest)
 	}
 
-	if !(*s.DriverName == ImageDriverLocal || *s.DriverName == ImageDriverS3) {
+	switch *s.DriverName {
+	case ImageDriverLocal, ImageDriverS3:
+	case ImageDriverAzure:
+		if appErr := s.isValidAzure(); appErr != nil {
+			return appErr
+		}
+	case ImageDriverGCS:
+		if appErr := s.isValidGCS(); appErr != nil {
+			return appErr
+		}
+	default:
 		return NewAppError("Config.IsValid", "model.config.is_valid.file_driver.app_error", nil, "", http.StatusBadRequest)
 	}
 
@@ -3859,9 +4524,192 @@ func (s *FileSettings) isValid() *AppError {
 		return NewAppError("Config.IsValid", "model.config.is_valid.amazons3_timeout.app_error", map[string]any{"Value": *s.MaxImageDecoderConcurrency}, "", http.StatusBadRequest)
 	}
 
+	if *s.EnableFileCache && *s.FileCacheMaxSizeMB <= 0 {
+		return NewAppError("Config.IsValid", "model.config.is_valid.file_cache_max_size.app_error", map[string]any{"Value": *s.FileCacheMaxSizeMB}, "", http.StatusBadRequest)
+	}
+
+	if *s.EncryptionEnabled {
+		if appErr := s.isValidEncryption(); appErr != nil {
+			return appErr
+		}
+	}
+
+	if *s.EnableOCR {
+		if *s.OCRCommand == "" {
+			return NewAppError("Config.IsValid", "model.config.is_valid.ocr_command.app_error", nil, "", http.StatusBadRequest)
+		}
+
+		// Languages are given as in tesseract, such as eng+deu.
+		validOCRLanguages := regexp.MustCompile(`^[A-Za-z0-9_]+(\+[A-Za-z0-9_]+)*$`)
+		if !validOCRLanguages.MatchString(*s.OCRLanguages) {
+			return NewAppError("Config.IsValid", "model.config.is_valid.ocr_languages.app_error", nil, "", http.StatusBadRequest)
+		}
+
+		if *s.OCRTimeoutSeconds <= 0 {
+			return NewAppError("Config.IsValid", "model.config.is_valid.ocr_timeout.app_error", nil, "", http.StatusBadRequest)
+		}
+	}
+
+	if *s.EnableImageConverter {
+		if *s.ImageConverterCommand == "" {
+			return NewAppError("Config.IsValid", "model.config.is_valid.image_converter_command.app_error", nil, "", http.StatusBadRequest)
+		}
+
+		if *s.ImageConverterTimeoutSeconds <= 0 {
+			return NewAppError("Config.IsValid", "model.config.is_valid.image_converter_timeout.app_error", nil, "", http.StatusBadRequest)
+		}
+	}
+
+	if *s.EnableWebPPreviews {
+		// WebP images are encoded by the image converter.
+		if !*s.EnableImageConverter {
+			return NewAppError("Config.IsValid", "model.config.is_valid.webp_previews_converter.app_error", nil, "", http.StatusBadRequest)
+		}
+
+		if *s.WebPPreviewQuality < 1 || *s.WebPPreviewQuality > 100 {
+			return NewAppError("Config.IsValid", "model.config.is_valid.webp_preview_quality.app_error", map[string]any{"Value": *s.WebPPreviewQuality}, "", http.StatusBadRequest)
+		}
+	}
+
+	if *s.EnableFFmpeg {
+		if *s.FFmpegCommand == "" {
+			return NewAppError("Config.IsValid", "model.config.is_valid.ffmpeg_command.app_error", nil, "", http.StatusBadRequest)
+		}
+
+		if *s.FFmpegTimeoutSeconds <= 0 {
+			return NewAppError("Config.IsValid", "model.config.is_valid.ffmpeg_timeout.app_error", nil, "", http.StatusBadRequest)
+		}
+	}
+
+	if *s.EnableMediaTranscoding {
+		// Videos are transcoded by FFmpeg.
+		if !*s.EnableFFmpeg {
+			return NewAppError("Config.IsValid", "model.config.is_valid.media_transcoding_ffmpeg.app_error", nil, "", http.StatusBadRequest)
+		}
+
+		if *s.MediaTranscodingTimeoutSeconds <= 0 {
+			return NewAppError("Config.IsValid", "model.config.is_valid.media_transcoding_timeout.app_error", nil, "", http.StatusBadRequest)
+		}
+	}
+
 	return nil
 }
 
+func (s *FileSettings) isValidAzure() *AppError {
+	if *s.AzureAccountName == "" {
+		return NewAppError("Config.IsValid", "model.config.is_valid.azure_account_name.app_error", nil, "", http.StatusBadRequest)
+	}
+
+	if _, err := base64.StdEncoding.DecodeString(*s.AzureAccountKey); *s.AzureAccountKey == "" || err != nil {
+		return NewAppError("Config.IsValid", "model.config.is_valid.azure_account_key.app_error", nil, "", http.StatusBadRequest)
+	}
+
+	if *s.AzureContainer == "" {
+		return NewAppError("Config.IsValid", "model.config.is_valid.azure_container.app_error", nil, "", http.StatusBadRequest)
+	}
+
+	if *s.AzureEndpoint != "" {
+		if u, err := url.Parse(*s.AzureEndpoint); err != nil || u.Host == "" || (u.Scheme != "http" && u.Scheme != "https") {
+			return NewAppError("Config.IsValid", "model.config.is_valid.azure_endpoint.app_error", nil, "", http.StatusBadRequest)
+		}
+	}
+
+	if *s.AzureRequestTimeoutMilliseconds <= 0 {
+		return NewAppError("Config.IsValid", "model.config.is_valid.azure_timeout.app_error", map[string]any{"Value": *s.AzureRequestTimeoutMilliseconds}, "", http.StatusBadRequest)
+	}
+
+	if *s.AzureSignedURLExpiresSeconds <= 0 {
+		return NewAppError("Config.IsValid", "model.config.is_valid.azure_signed_url_expires.app_error", map[string]any{"Value": *s.AzureSignedURLExpiresSeconds}, "", http.StatusBadRequest)
+	}
+
+	return nil
+}
+
+func (s *FileSettings) isValidGCS() *AppError {
+	if *s.GCSBucket == "" {
+		return NewAppError("Config.IsValid", "model.config.is_valid.gcs_bucket.app_error", nil, "", http.StatusBadRequest)
+	}
+
+	// Requests are only sent without credentials to emulators.
+	if (*s.GCSServiceAccountKey == "" && *s.GCSEndpoint == "") || (*s.GCSServiceAccountKey != "" && !json.Valid([]byte(*s.GCSServiceAccountKey))) {
+		return NewAppError("Config.IsValid", "model.config.is_valid.gcs_service_account_key.app_error", nil, "", http.StatusBadRequest)
+	}
+
+	if *s.GCSEndpoint != "" {
+		if u, err := url.Parse(*s.GCSEndpoint); err != nil || u.Host == "" || (u.Scheme != "http" && u.Scheme != "https") {
+			return NewAppError("Config.IsValid", "model.config.is_valid.gcs_endpoint.app_error", nil, "", http.StatusBadRequest)
+		}
+	}
+
+	if *s.GCSRequestTimeoutMilliseconds <= 0 {
+		return NewAppError("Config.IsValid", "model.config.is_valid.gcs_timeout.app_error", map[string]any{"Value": *s.GCSRequestTimeoutMilliseconds}, "", http.StatusBadRequest)
+	}
+
+	if *s.GCSSignedURLExpiresSeconds <= 0 || *s.GCSSignedURLExpiresSeconds > 7*24*60*60 {
+		return NewAppError("Config.IsValid", "model.config.is_valid.gcs_signed_url_expires.app_error", map[string]any{"Value": *s.GCSSignedURLExpiresSeconds}, "", http.StatusBadRequest)
+	}
+
+	return nil
+}
+
+func (s *FileSettings) isValidEncryption() *AppError {
+	if *s.EncryptionActiveKeyId == "" || len(*s.EncryptionActiveKeyId) > FileEncryptionKeyIdMaxLength {
+		return NewAppError("Config.IsValid", "model.config.is_valid.file_encryption_active_key.app_error", nil, "", http.StatusBadRequest)
+	}
+
+	switch *s.EncryptionKeyProvider {
+	case FileEncryptionKeyProviderConfig:
+		keys, err := s.GetEncryptionKeys()
+		if err != nil {
+			return NewAppError("Config.IsValid", "model.config.is_valid.file_encryption_keys.app_error", nil, "", http.StatusBadRequest).Wrap(err)
+		}
+		if _, ok := keys[*s.EncryptionActiveKeyId]; !ok {
+			return NewAppError("Config.IsValid", "model.config.is_valid.file_encryption_active_key.app_error", nil, "", http.StatusBadRequest)
+		}
+	case FileEncryptionKeyProviderLocalKMS:
+		if *s.EncryptionLocalKMSDirectory == "" {
+			return NewAppError("Config.IsValid", "model.config.is_valid.file_encryption_kms_directory.app_error", nil, "", http.StatusBadRequest)
+		}
+	default:
+		return NewAppError("Config.IsValid", "model.config.is_valid.file_encryption_key_provider.app_error", nil, "", http.StatusBadRequest)
+	}
+
+	return nil
+}
+
+// GetEncryptionKeys parses EncryptionKeys, a comma separated list of id:key pairs where
+// each key is a base64 encoded 256 bit key.
+func (s *FileSettings) GetEncryptionKeys() (map[string][]byte, error) {
+	keys := map[string][]byte{}
+	if s.EncryptionKeys == nil {
+		return keys, nil
+	}
+
+	for _, pair := range strings.Split(*s.EncryptionKeys, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+
+		id, encoded, found := strings.Cut(pair, ":")
+		if !found || id == "" || len(id) > FileEncryptionKeyIdMaxLength {
+			return nil, errors.New("encryption keys must be given as id:key pairs")
+		}
+
+		key, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			return nil, fmt.Errorf("encryption key %q is not valid base64: %w", id, err)
+		}
+		if len(key) != FileEncryptionKeySize {
+			return nil, fmt.Errorf("encryption key %q must be %d bytes long", id, FileEncryptionKeySize)
+		}
+
+		keys[id] = key
+	}
+
+	return keys, nil
+}
+
 func (s *EmailSettings) isValid() *AppError {
 	if !(*s.ConnectionSecurity == ConnSecurityNone || *s.ConnectionSecurity == ConnSecurityTLS || *s.ConnectionSecurity == ConnSecurityStarttls || *s.ConnectionSecurity == ConnSecurityPlain) {
 		return NewAppError("Config.IsValid", "model.config.is_valid.email_security.app_error", nil, "", http.StatusBadRequest)
@@ -3895,6 +4743,38 @@ func (s *RateLimitSettings) isValid() *AppError {
 		return NewAppError("Config.IsValid", "model.config.is_valid.max_burst.app_error", nil, "", http.StatusBadRequest)
 	}
 
+	for route, quota := range s.RouteQuotas {
+		if !strings.HasPrefix(route, "/") {
+			return NewAppError("Config.IsValid", "model.config.is_valid.rate_route.app_error", map[string]any{"Route": route}, "", http.StatusBadRequest)
+		}
+
+		if appErr := quota.isValid(); appErr != nil {
+			return appErr
+		}
+	}
+
+	for tokenID, quota := range s.TokenQuotas {
+		if !IsValidId(tokenID) {
+			return NewAppError("Config.IsValid", "model.config.is_valid.rate_token.app_error", map[string]any{"TokenId": tokenID}, "", http.StatusBadRequest)
+		}
+
+		if appErr := quota.isValid(); appErr != nil {
+			return appErr
+		}
+	}
+
+	return nil
+}
+
+func (q *RateLimitQuota) isValid() *AppError {
+	if q == nil || q.PerSec <= 0 {
+		return NewAppError("Config.IsValid", "model.config.is_valid.rate_sec.app_error", nil, "", http.StatusBadRequest)
+	}
+
+	if q.MaxBurst <= 0 {
+		return NewAppError("Config.IsValid", "model.config.is_valid.max_burst.app_error", nil, "", http.StatusBadRequest)
+	}
+
 	return nil
 }
 
@@ -4143,6 +5023,10 @@ func (s *ServiceSettings) isValid() *AppError {
 		return NewAppError("Config.IsValid", "model.config.is_valid.persistent_notifications_recipients.app_error", nil, "", http.StatusBadRequest)
 	}
 
+	if *s.ReadReceiptsMaxChannelMembers <= 0 {
+		return NewAppError("Config.IsValid", "model.config.is_valid.read_receipts_max_channel_members.app_error", nil, "", http.StatusBadRequest)
+	}
+
 	// we check if file has a valid parent, the server will try to create the socket
 	// file if it doesn't exist, but we need to be sure if the directory exist or not
 	if *s.EnableLocalMode {
@@ -4341,7 +5225,9 @@ func (s *ImageProxySettings) isValid() *AppError {
 	if *s.Enable {
 		switch *s.ImageProxyType {
 		case ImageProxyTypeLocal:
-			// No other settings to validate
+			if *s.EnableResizing && *s.ResizedImageCacheMaxSizeMB <= 0 {
+				return NewAppError("Config.IsValid", "model.config.is_valid.image_proxy_cache_max_size.app_error", map[string]any{"Value": *s.ResizedImageCacheMaxSizeMB}, "", http.StatusBadRequest)
+			}
 		case ImageProxyTypeAtmosCamo:
 			if *s.RemoteImageProxyURL == "" {
 				return NewAppError("Config.IsValid", "model.config.is_valid.atmos_camo_image_proxy_url.app_error", nil, "", http.StatusBadRequest)
@@ -4379,6 +5265,18 @@ func (o *Config) Sanitize() {
 		*o.FileSettings.AmazonS3SecretAccessKey = FakeSetting
 	}
 
+	if o.FileSettings.AzureAccountKey != nil && *o.FileSettings.AzureAccountKey != "" {
+		*o.FileSettings.AzureAccountKey = FakeSetting
+	}
+
+	if o.FileSettings.GCSServiceAccountKey != nil && *o.FileSettings.GCSServiceAccountKey != "" {
+		*o.FileSettings.GCSServiceAccountKey = FakeSetting
+	}
+
+	if o.FileSettings.EncryptionKeys != nil && *o.FileSettings.EncryptionKeys != "" {
+		*o.FileSettings.EncryptionKeys = FakeSetting
+	}
+
 	if o.EmailSettings.SMTPPassword != nil && *o.EmailSettings.SMTPPassword != "" {
 		*o.EmailSettings.SMTPPassword = FakeSetting
 	}
@@ -4432,6 +5330,18 @@ func (o *Config) Sanitize() {
 	if o.ServiceSettings.SplitKey != nil {
 		*o.ServiceSettings.SplitKey = FakeSetting
 	}
+
+	if o.ScimSettings.Token != nil && *o.ScimSettings.Token != "" {
+		*o.ScimSettings.Token = FakeSetting
+	}
+
+	if o.TranslationSettings.APIKey != nil && *o.TranslationSettings.APIKey != "" {
+		*o.TranslationSettings.APIKey = FakeSetting
+	}
+
+	if o.SemanticSearchSettings.EmbeddingAPIKey != nil && *o.SemanticSearchSettings.EmbeddingAPIKey != "" {
+		*o.SemanticSearchSettings.EmbeddingAPIKey = FakeSetting
+	}
 }
 
 // structToMapFilteredByTag converts a struct into a map removing those fields that has the tag passed
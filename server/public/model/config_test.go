@@ -4,6 +4,7 @@
 package model
 
 import (
+	"encoding/base64"
 	"encoding/json"
 	"fmt"
 	"os"
@@ -161,6 +162,323 @@ func TestConfigDefaultFileSettingsS3SSE(t *testing.T) {
 	require.False(t, *c1.FileSettings.AmazonS3SSE)
 }
 
+func TestConfigFileSettingsEncryption(t *testing.T) {
+	key := base64.StdEncoding.EncodeToString(make([]byte, FileEncryptionKeySize))
+
+	newConfig := func() *Config {
+		c := &Config{}
+		c.SetDefaults()
+		*c.FileSettings.EncryptionEnabled = true
+		*c.FileSettings.EncryptionKeys = "key1:" + key + ", key2:" + key
+		*c.FileSettings.EncryptionActiveKeyId = "key2"
+		return c
+	}
+
+	t.Run("valid keys", func(t *testing.T) {
+		c := newConfig()
+		require.Nil(t, c.FileSettings.isValid())
+
+		keys, err := c.FileSettings.GetEncryptionKeys()
+		require.NoError(t, err)
+		assert.Len(t, keys, 2)
+		assert.Len(t, keys["key1"], FileEncryptionKeySize)
+	})
+
+	t.Run("active key must be configured", func(t *testing.T) {
+		c := newConfig()
+		*c.FileSettings.EncryptionActiveKeyId = "key3"
+		appErr := c.FileSettings.isValid()
+		require.NotNil(t, appErr)
+		assert.Equal(t, "model.config.is_valid.file_encryption_active_key.app_error", appErr.Id)
+	})
+
+	t.Run("keys must be 32 bytes", func(t *testing.T) {
+		c := newConfig()
+		*c.FileSettings.EncryptionKeys = "key2:" + base64.StdEncoding.EncodeToString([]byte("short"))
+		appErr := c.FileSettings.isValid()
+		require.NotNil(t, appErr)
+		assert.Equal(t, "model.config.is_valid.file_encryption_keys.app_error", appErr.Id)
+	})
+
+	t.Run("local kms does not need keys", func(t *testing.T) {
+		c := newConfig()
+		*c.FileSettings.EncryptionKeyProvider = FileEncryptionKeyProviderLocalKMS
+		*c.FileSettings.EncryptionKeys = ""
+		require.Nil(t, c.FileSettings.isValid())
+
+		*c.FileSettings.EncryptionKeyProvider = "vault"
+		appErr := c.FileSettings.isValid()
+		require.NotNil(t, appErr)
+		assert.Equal(t, "model.config.is_valid.file_encryption_key_provider.app_error", appErr.Id)
+	})
+}
+
+func TestConfigFileSettingsOCR(t *testing.T) {
+	newConfig := func() *Config {
+		c := &Config{}
+		c.SetDefaults()
+		*c.FileSettings.EnableOCR = true
+		return c
+	}
+
+	t.Run("defaults", func(t *testing.T) {
+		c := newConfig()
+		require.Nil(t, c.FileSettings.isValid())
+		assert.Equal(t, FileSettingsDefaultOCRCommand, *c.FileSettings.OCRCommand)
+		assert.Equal(t, FileSettingsDefaultOCRLanguages, *c.FileSettings.OCRLanguages)
+	})
+
+	t.Run("command is required", func(t *testing.T) {
+		c := newConfig()
+		*c.FileSettings.OCRCommand = ""
+		appErr := c.FileSettings.isValid()
+		require.NotNil(t, appErr)
+		assert.Equal(t, "model.config.is_valid.ocr_command.app_error", appErr.Id)
+
+		*c.FileSettings.EnableOCR = false
+		require.Nil(t, c.FileSettings.isValid())
+	})
+
+	t.Run("languages", func(t *testing.T) {
+		c := newConfig()
+		for _, languages := range []string{"eng", "eng+deu", "chi_sim+eng"} {
+			*c.FileSettings.OCRLanguages = languages
+			assert.Nil(t, c.FileSettings.isValid(), languages)
+		}
+
+		for _, languages := range []string{"", "eng+", "eng deu", "eng;rm"} {
+			*c.FileSettings.OCRLanguages = languages
+			appErr := c.FileSettings.isValid()
+			require.NotNil(t, appErr, languages)
+			assert.Equal(t, "model.config.is_valid.ocr_languages.app_error", appErr.Id)
+		}
+	})
+
+	t.Run("timeout must be positive", func(t *testing.T) {
+		c := newConfig()
+		*c.FileSettings.OCRTimeoutSeconds = 0
+		appErr := c.FileSettings.isValid()
+		require.NotNil(t, appErr)
+		assert.Equal(t, "model.config.is_valid.ocr_timeout.app_error", appErr.Id)
+	})
+}
+
+func TestConfigFileSettingsImageConverter(t *testing.T) {
+	newConfig := func() *Config {
+		c := &Config{}
+		c.SetDefaults()
+		*c.FileSettings.EnableImageConverter = true
+		*c.FileSettings.EnableWebPPreviews = true
+		return c
+	}
+
+	t.Run("defaults", func(t *testing.T) {
+		c := newConfig()
+		require.Nil(t, c.FileSettings.isValid())
+		assert.Equal(t, FileSettingsDefaultImageConverterCommand, *c.FileSettings.ImageConverterCommand)
+		assert.Equal(t, FileSettingsDefaultWebPPreviewQuality, *c.FileSettings.WebPPreviewQuality)
+	})
+
+	t.Run("command is required", func(t *testing.T) {
+		c := newConfig()
+		*c.FileSettings.EnableWebPPreviews = false
+		*c.FileSettings.ImageConverterCommand = ""
+		appErr := c.FileSettings.isValid()
+		require.NotNil(t, appErr)
+		assert.Equal(t, "model.config.is_valid.image_converter_command.app_error", appErr.Id)
+
+		*c.FileSettings.EnableImageConverter = false
+		require.Nil(t, c.FileSettings.isValid())
+	})
+
+	t.Run("timeout must be positive", func(t *testing.T) {
+		c := newConfig()
+		*c.FileSettings.ImageConverterTimeoutSeconds = 0
+		appErr := c.FileSettings.isValid()
+		require.NotNil(t, appErr)
+		assert.Equal(t, "model.config.is_valid.image_converter_timeout.app_error", appErr.Id)
+	})
+
+	t.Run("webp previews require the converter", func(t *testing.T) {
+		c := newConfig()
+		*c.FileSettings.EnableImageConverter = false
+		appErr := c.FileSettings.isValid()
+		require.NotNil(t, appErr)
+		assert.Equal(t, "model.config.is_valid.webp_previews_converter.app_error", appErr.Id)
+	})
+
+	t.Run("webp preview quality", func(t *testing.T) {
+		c := newConfig()
+		for _, quality := range []int{1, 50, 100} {
+			*c.FileSettings.WebPPreviewQuality = quality
+			assert.Nil(t, c.FileSettings.isValid(), quality)
+		}
+
+		for _, quality := range []int{-1, 0, 101} {
+			*c.FileSettings.WebPPreviewQuality = quality
+			appErr := c.FileSettings.isValid()
+			require.NotNil(t, appErr, quality)
+			assert.Equal(t, "model.config.is_valid.webp_preview_quality.app_error", appErr.Id)
+		}
+	})
+}
+
+func TestConfigFileSettingsFFmpeg(t *testing.T) {
+	newConfig := func() *Config {
+		c := &Config{}
+		c.SetDefaults()
+		*c.FileSettings.EnableFFmpeg = true
+		*c.FileSettings.EnableMediaTranscoding = true
+		return c
+	}
+
+	t.Run("defaults", func(t *testing.T) {
+		c := newConfig()
+		require.Nil(t, c.FileSettings.isValid())
+		assert.Equal(t, FileSettingsDefaultFFmpegCommand, *c.FileSettings.FFmpegCommand)
+		assert.Equal(t, FileSettingsDefaultFFmpegTimeoutSeconds, *c.FileSettings.FFmpegTimeoutSeconds)
+		assert.Equal(t, FileSettingsDefaultMediaTranscodingTimeout, *c.FileSettings.MediaTranscodingTimeoutSeconds)
+	})
+
+	t.Run("command is required", func(t *testing.T) {
+		c := newConfig()
+		*c.FileSettings.EnableMediaTranscoding = false
+		*c.FileSettings.FFmpegCommand = ""
+		appErr := c.FileSettings.isValid()
+		require.NotNil(t, appErr)
+		assert.Equal(t, "model.config.is_valid.ffmpeg_command.app_error", appErr.Id)
+
+		*c.FileSettings.EnableFFmpeg = false
+		require.Nil(t, c.FileSettings.isValid())
+	})
+
+	t.Run("timeouts must be positive", func(t *testing.T) {
+		c := newConfig()
+		*c.FileSettings.FFmpegTimeoutSeconds = 0
+		appErr := c.FileSettings.isValid()
+		require.NotNil(t, appErr)
+		assert.Equal(t, "model.config.is_valid.ffmpeg_timeout.app_error", appErr.Id)
+
+		c = newConfig()
+		*c.FileSettings.MediaTranscodingTimeoutSeconds = -1
+		appErr = c.FileSettings.isValid()
+		require.NotNil(t, appErr)
+		assert.Equal(t, "model.config.is_valid.media_transcoding_timeout.app_error", appErr.Id)
+	})
+
+	t.Run("transcoding requires ffmpeg", func(t *testing.T) {
+		c := newConfig()
+		*c.FileSettings.EnableFFmpeg = false
+		appErr := c.FileSettings.isValid()
+		require.NotNil(t, appErr)
+		assert.Equal(t, "model.config.is_valid.media_transcoding_ffmpeg.app_error", appErr.Id)
+	})
+}
+
+func TestConfigFileSettingsFileCache(t *testing.T) {
+	c := &Config{}
+	c.SetDefaults()
+	assert.False(t, *c.FileSettings.EnableFileCache)
+	assert.Equal(t, FileSettingsDefaultFileCacheDirectory, *c.FileSettings.FileCacheDirectory)
+	assert.Equal(t, FileSettingsDefaultFileCacheMaxSizeMB, *c.FileSettings.FileCacheMaxSizeMB)
+
+	*c.FileSettings.EnableFileCache = true
+	require.Nil(t, c.FileSettings.isValid())
+
+	*c.FileSettings.FileCacheMaxSizeMB = 0
+	appErr := c.FileSettings.isValid()
+	require.NotNil(t, appErr)
+	assert.Equal(t, "model.config.is_valid.file_cache_max_size.app_error", appErr.Id)
+
+	*c.FileSettings.EnableFileCache = false
+	require.Nil(t, c.FileSettings.isValid())
+}
+
+func TestConfigFileSettingsAzure(t *testing.T) {
+	c := &Config{}
+	c.SetDefaults()
+	*c.FileSettings.DriverName = ImageDriverAzure
+	assert.Equal(t, int64(FileSettingsDefaultSignedURLExpiresSeconds), *c.FileSettings.AzureSignedURLExpiresSeconds)
+
+	appErr := c.FileSettings.isValid()
+	require.NotNil(t, appErr)
+	assert.Equal(t, "model.config.is_valid.azure_account_name.app_error", appErr.Id)
+
+	*c.FileSettings.AzureAccountName = "account"
+	*c.FileSettings.AzureAccountKey = "not base64"
+	appErr = c.FileSettings.isValid()
+	require.NotNil(t, appErr)
+	assert.Equal(t, "model.config.is_valid.azure_account_key.app_error", appErr.Id)
+
+	*c.FileSettings.AzureAccountKey = "a2V5"
+	appErr = c.FileSettings.isValid()
+	require.NotNil(t, appErr)
+	assert.Equal(t, "model.config.is_valid.azure_container.app_error", appErr.Id)
+
+	*c.FileSettings.AzureContainer = "container"
+	require.Nil(t, c.FileSettings.isValid())
+
+	*c.FileSettings.AzureEndpoint = "localhost:10000"
+	appErr = c.FileSettings.isValid()
+	require.NotNil(t, appErr)
+	assert.Equal(t, "model.config.is_valid.azure_endpoint.app_error", appErr.Id)
+
+	*c.FileSettings.AzureEndpoint = "http://localhost:10000/devstoreaccount1"
+	require.Nil(t, c.FileSettings.isValid())
+}
+
+func TestConfigFileSettingsGCS(t *testing.T) {
+	c := &Config{}
+	c.SetDefaults()
+	*c.FileSettings.DriverName = ImageDriverGCS
+
+	appErr := c.FileSettings.isValid()
+	require.NotNil(t, appErr)
+	assert.Equal(t, "model.config.is_valid.gcs_bucket.app_error", appErr.Id)
+
+	*c.FileSettings.GCSBucket = "bucket"
+	appErr = c.FileSettings.isValid()
+	require.NotNil(t, appErr)
+	assert.Equal(t, "model.config.is_valid.gcs_service_account_key.app_error", appErr.Id)
+
+	// Emulators are used without credentials.
+	*c.FileSettings.GCSEndpoint = "http://localhost:4443"
+	require.Nil(t, c.FileSettings.isValid())
+
+	*c.FileSettings.GCSServiceAccountKey = "{"
+	appErr = c.FileSettings.isValid()
+	require.NotNil(t, appErr)
+	assert.Equal(t, "model.config.is_valid.gcs_service_account_key.app_error", appErr.Id)
+
+	*c.FileSettings.GCSServiceAccountKey = `{"type": "service_account"}`
+	require.Nil(t, c.FileSettings.isValid())
+
+	*c.FileSettings.GCSSignedURLExpiresSeconds = 8 * 24 * 60 * 60
+	appErr = c.FileSettings.isValid()
+	require.NotNil(t, appErr)
+	assert.Equal(t, "model.config.is_valid.gcs_signed_url_expires.app_error", appErr.Id)
+}
+
+func TestConfigClusterSettingsMessageTransport(t *testing.T) {
+	c := &Config{}
+	c.SetDefaults()
+	assert.Equal(t, ClusterMessageTransportHTTP, *c.ClusterSettings.MessageTransport)
+	require.Nil(t, c.ClusterSettings.isValid(&c.CacheSettings))
+
+	*c.ClusterSettings.MessageTransport = "gossip"
+	appErr := c.ClusterSettings.isValid(&c.CacheSettings)
+	require.NotNil(t, appErr)
+	assert.Equal(t, "model.config.is_valid.cluster_message_transport.app_error", appErr.Id)
+
+	*c.ClusterSettings.MessageTransport = ClusterMessageTransportRedis
+	appErr = c.ClusterSettings.isValid(&c.CacheSettings)
+	require.NotNil(t, appErr)
+	assert.Equal(t, "model.config.is_valid.cluster_redis_transport.app_error", appErr.Id)
+
+	*c.CacheSettings.CacheType = CacheTypeRedis
+	require.Nil(t, c.ClusterSettings.isValid(&c.CacheSettings))
+}
+
 func TestConfigDefaultSignatureAlgorithm(t *testing.T) {
 	c1 := Config{}
 	c1.SetDefaults()
@@ -834,6 +1152,10 @@ func TestImageProxySettingsSetDefaults(t *testing.T) {
 		assert.Equal(t, ImageProxyTypeLocal, *ips.ImageProxyType)
 		assert.Equal(t, "", *ips.RemoteImageProxyURL)
 		assert.Equal(t, "", *ips.RemoteImageProxyOptions)
+		assert.Equal(t, false, *ips.EnableResizing)
+		assert.Equal(t, ImageProxySettingsDefaultResizedImageCacheDirectory, *ips.ResizedImageCacheDirectory)
+		assert.Equal(t, ImageProxySettingsDefaultResizedImageCacheMaxSizeMB, *ips.ResizedImageCacheMaxSizeMB)
+		assert.Equal(t, false, *ips.EnableWebP)
 	})
 }
 
@@ -844,6 +1166,8 @@ func TestImageProxySettingsIsValid(t *testing.T) {
 		ImageProxyType          string
 		RemoteImageProxyURL     string
 		RemoteImageProxyOptions string
+		EnableResizing          bool
+		CacheMaxSizeMB          int
 		ExpectError             bool
 	}{
 		{
@@ -873,6 +1197,22 @@ func TestImageProxySettingsIsValid(t *testing.T) {
 			RemoteImageProxyOptions: "garbage",
 			ExpectError:             false,
 		},
+		{
+			Name:           "local, resizing",
+			Enable:         true,
+			ImageProxyType: ImageProxyTypeLocal,
+			EnableResizing: true,
+			CacheMaxSizeMB: 256,
+			ExpectError:    false,
+		},
+		{
+			Name:           "local, resizing with invalid cache size",
+			Enable:         true,
+			ImageProxyType: ImageProxyTypeLocal,
+			EnableResizing: true,
+			CacheMaxSizeMB: 0,
+			ExpectError:    true,
+		},
 		{
 			Name:                    "atmos/camo",
 			Enable:                  true,
@@ -900,11 +1240,14 @@ func TestImageProxySettingsIsValid(t *testing.T) {
 	} {
 		t.Run(test.Name, func(t *testing.T) {
 			ips := &ImageProxySettings{
-				Enable:                  &test.Enable,
-				ImageProxyType:          &test.ImageProxyType,
-				RemoteImageProxyURL:     &test.RemoteImageProxyURL,
-				RemoteImageProxyOptions: &test.RemoteImageProxyOptions,
+				Enable:                     &test.Enable,
+				ImageProxyType:             &test.ImageProxyType,
+				RemoteImageProxyURL:        &test.RemoteImageProxyURL,
+				RemoteImageProxyOptions:    &test.RemoteImageProxyOptions,
+				EnableResizing:             &test.EnableResizing,
+				ResizedImageCacheMaxSizeMB: &test.CacheMaxSizeMB,
 			}
+			ips.SetDefaults()
 
 			appErr := ips.isValid()
 			if test.ExpectError {
@@ -1376,9 +1719,14 @@ func TestConfigSanitize(t *testing.T) {
 
 	*c.LdapSettings.BindPassword = "foo"
 	*c.FileSettings.AmazonS3SecretAccessKey = "bar"
+	*c.FileSettings.AzureAccountKey = "azure"
+	*c.FileSettings.GCSServiceAccountKey = "gcs"
 	*c.EmailSettings.SMTPPassword = "baz"
 	*c.GitLabSettings.Secret = "bingo"
 	*c.OpenIdSettings.Secret = "secret"
+	*c.ScimSettings.Token = "token"
+	*c.TranslationSettings.APIKey = "key"
+	*c.SemanticSearchSettings.EmbeddingAPIKey = "embedding"
 	c.SqlSettings.DataSourceReplicas = []string{"stuff"}
 	c.SqlSettings.DataSourceSearchReplicas = []string{"stuff"}
 	c.SqlSettings.ReplicaLagSettings = []*ReplicaLagSettings{{
@@ -1392,9 +1740,14 @@ func TestConfigSanitize(t *testing.T) {
 	assert.Equal(t, FakeSetting, *c.LdapSettings.BindPassword)
 	assert.Equal(t, FakeSetting, *c.FileSettings.PublicLinkSalt)
 	assert.Equal(t, FakeSetting, *c.FileSettings.AmazonS3SecretAccessKey)
+	assert.Equal(t, FakeSetting, *c.FileSettings.AzureAccountKey)
+	assert.Equal(t, FakeSetting, *c.FileSettings.GCSServiceAccountKey)
 	assert.Equal(t, FakeSetting, *c.EmailSettings.SMTPPassword)
 	assert.Equal(t, FakeSetting, *c.GitLabSettings.Secret)
 	assert.Equal(t, FakeSetting, *c.OpenIdSettings.Secret)
+	assert.Equal(t, FakeSetting, *c.ScimSettings.Token)
+	assert.Equal(t, FakeSetting, *c.TranslationSettings.APIKey)
+	assert.Equal(t, FakeSetting, *c.SemanticSearchSettings.EmbeddingAPIKey)
 	assert.Equal(t, FakeSetting, *c.SqlSettings.DataSource)
 	assert.Equal(t, FakeSetting, *c.SqlSettings.AtRestEncryptKey)
 	assert.Equal(t, FakeSetting, *c.ElasticsearchSettings.Password)
@@ -1567,6 +1920,186 @@ func TestConfigExportSettingsIsValid(t *testing.T) {
 	require.Equal(t, "model.config.is_valid.export.retention_days_too_low.app_error", appErr.Id)
 }
 
+func TestConfigScimSettingsIsValid(t *testing.T) {
+	cfg := Config{}
+	cfg.SetDefaults()
+
+	require.False(t, *cfg.ScimSettings.Enable)
+	require.Equal(t, UserAuthServiceSaml, *cfg.ScimSettings.AuthService)
+	require.Nil(t, cfg.ScimSettings.isValid())
+
+	*cfg.ScimSettings.Enable = true
+	*cfg.ScimSettings.Token = "short"
+	appErr := cfg.ScimSettings.isValid()
+	require.NotNil(t, appErr)
+	require.Equal(t, "model.config.is_valid.scim_token.app_error", appErr.Id)
+
+	*cfg.ScimSettings.Token = FakeSetting
+	appErr = cfg.ScimSettings.isValid()
+	require.NotNil(t, appErr)
+	require.Equal(t, "model.config.is_valid.scim_token.app_error", appErr.Id)
+
+	*cfg.ScimSettings.Token = NewId() + NewId()
+	require.Nil(t, cfg.ScimSettings.isValid())
+
+	*cfg.ScimSettings.AuthService = "email"
+	appErr = cfg.ScimSettings.isValid()
+	require.NotNil(t, appErr)
+	require.Equal(t, "model.config.is_valid.scim_auth_service.app_error", appErr.Id)
+}
+
+func TestConfigTranslationSettingsIsValid(t *testing.T) {
+	cfg := Config{}
+	cfg.SetDefaults()
+
+	require.False(t, *cfg.TranslationSettings.Enable)
+	require.Equal(t, TranslationProviderLocal, *cfg.TranslationSettings.Provider)
+	require.Nil(t, cfg.TranslationSettings.isValid())
+
+	*cfg.TranslationSettings.Enable = true
+	require.Nil(t, cfg.TranslationSettings.isValid())
+
+	*cfg.TranslationSettings.Provider = TranslationProviderHTTP
+	appErr := cfg.TranslationSettings.isValid()
+	require.NotNil(t, appErr)
+	require.Equal(t, "model.config.is_valid.translation_provider_url.app_error", appErr.Id)
+
+	*cfg.TranslationSettings.ProviderURL = "https://translate.example.com/translate"
+	require.Nil(t, cfg.TranslationSettings.isValid())
+
+	*cfg.TranslationSettings.RequestTimeoutMilliseconds = 0
+	appErr = cfg.TranslationSettings.isValid()
+	require.NotNil(t, appErr)
+	require.Equal(t, "model.config.is_valid.translation_request_timeout.app_error", appErr.Id)
+
+	*cfg.TranslationSettings.Provider = "unknown"
+	appErr = cfg.TranslationSettings.isValid()
+	require.NotNil(t, appErr)
+	require.Equal(t, "model.config.is_valid.translation_provider.app_error", appErr.Id)
+}
+
+func TestConfigSemanticSearchSettingsIsValid(t *testing.T) {
+	cfg := Config{}
+	cfg.SetDefaults()
+
+	require.False(t, *cfg.SemanticSearchSettings.EnableIndexing)
+	require.Equal(t, EmbeddingProviderLocal, *cfg.SemanticSearchSettings.EmbeddingProvider)
+	require.Nil(t, cfg.SemanticSearchSettings.isValid())
+
+	*cfg.SemanticSearchSettings.EnableSearching = true
+	appErr := cfg.SemanticSearchSettings.isValid()
+	require.NotNil(t, appErr)
+	require.Equal(t, "model.config.is_valid.semantic_search.enable_searching.app_error", appErr.Id)
+
+	*cfg.SemanticSearchSettings.EnableIndexing = true
+	require.Nil(t, cfg.SemanticSearchSettings.isValid())
+
+	*cfg.SemanticSearchSettings.EmbeddingDimensions = 0
+	appErr = cfg.SemanticSearchSettings.isValid()
+	require.NotNil(t, appErr)
+	require.Equal(t, "model.config.is_valid.semantic_search.embedding_dimensions.app_error", appErr.Id)
+
+	*cfg.SemanticSearchSettings.EmbeddingProvider = EmbeddingProviderHTTP
+	appErr = cfg.SemanticSearchSettings.isValid()
+	require.NotNil(t, appErr)
+	require.Equal(t, "model.config.is_valid.semantic_search.embedding_url.app_error", appErr.Id)
+
+	*cfg.SemanticSearchSettings.EmbeddingURL = "https://embeddings.example.com/v1/embeddings"
+	require.Nil(t, cfg.SemanticSearchSettings.isValid())
+
+	*cfg.SemanticSearchSettings.RequestTimeoutMilliseconds = 0
+	appErr = cfg.SemanticSearchSettings.isValid()
+	require.NotNil(t, appErr)
+	require.Equal(t, "model.config.is_valid.semantic_search.request_timeout.app_error", appErr.Id)
+	*cfg.SemanticSearchSettings.RequestTimeoutMilliseconds = SemanticSearchSettingsDefaultRequestTimeoutMilliseconds
+
+	*cfg.SemanticSearchSettings.LexicalWeightPercent = 101
+	appErr = cfg.SemanticSearchSettings.isValid()
+	require.NotNil(t, appErr)
+	require.Equal(t, "model.config.is_valid.semantic_search.lexical_weight.app_error", appErr.Id)
+	*cfg.SemanticSearchSettings.LexicalWeightPercent = SemanticSearchSettingsDefaultLexicalWeightPercent
+
+	*cfg.SemanticSearchSettings.MinimumScorePercent = -1
+	appErr = cfg.SemanticSearchSettings.isValid()
+	require.NotNil(t, appErr)
+	require.Equal(t, "model.config.is_valid.semantic_search.minimum_score.app_error", appErr.Id)
+
+	*cfg.SemanticSearchSettings.EmbeddingProvider = "unknown"
+	appErr = cfg.SemanticSearchSettings.isValid()
+	require.NotNil(t, appErr)
+	require.Equal(t, "model.config.is_valid.semantic_search.embedding_provider.app_error", appErr.Id)
+}
+
+func TestConfigVirusScanSettingsIsValid(t *testing.T) {
+	cfg := Config{}
+	cfg.SetDefaults()
+
+	require.False(t, *cfg.VirusScanSettings.Enable)
+	require.Equal(t, VirusScanDriverClamd, *cfg.VirusScanSettings.Driver)
+	require.Nil(t, cfg.VirusScanSettings.isValid())
+
+	*cfg.VirusScanSettings.Enable = true
+	require.Nil(t, cfg.VirusScanSettings.isValid())
+
+	for _, address := range []string{"unix:///var/run/clamav/clamd.ctl", "tcp://[::1]:3310"} {
+		*cfg.VirusScanSettings.ClamdAddress = address
+		require.Nil(t, cfg.VirusScanSettings.isValid(), address)
+	}
+
+	for _, address := range []string{"", "localhost:3310", "tcp://localhost", "unix://", "http://localhost:3310"} {
+		*cfg.VirusScanSettings.ClamdAddress = address
+		appErr := cfg.VirusScanSettings.isValid()
+		require.NotNil(t, appErr, address)
+		require.Equal(t, "model.config.is_valid.virus_scan_clamd_address.app_error", appErr.Id)
+	}
+	*cfg.VirusScanSettings.ClamdAddress = VirusScanSettingsDefaultClamdAddress
+
+	*cfg.VirusScanSettings.ScanTimeoutMilliseconds = 0
+	appErr := cfg.VirusScanSettings.isValid()
+	require.NotNil(t, appErr)
+	require.Equal(t, "model.config.is_valid.virus_scan_timeout.app_error", appErr.Id)
+
+	*cfg.VirusScanSettings.Driver = "unknown"
+	appErr = cfg.VirusScanSettings.isValid()
+	require.NotNil(t, appErr)
+	require.Equal(t, "model.config.is_valid.virus_scan_driver.app_error", appErr.Id)
+}
+
+func TestConfigRateLimitSettingsIsValid(t *testing.T) {
+	cfg := Config{}
+	cfg.SetDefaults()
+
+	require.Empty(t, cfg.RateLimitSettings.RouteQuotas)
+	require.Empty(t, cfg.RateLimitSettings.TokenQuotas)
+	require.Nil(t, cfg.RateLimitSettings.isValid())
+
+	cfg.RateLimitSettings.RouteQuotas["/api/v4/users/login"] = &RateLimitQuota{PerSec: 1, MaxBurst: 5}
+	cfg.RateLimitSettings.TokenQuotas[NewId()] = &RateLimitQuota{PerSec: 100, MaxBurst: 500}
+	require.Nil(t, cfg.RateLimitSettings.isValid())
+
+	cfg.RateLimitSettings.RouteQuotas["api/v4/posts"] = &RateLimitQuota{PerSec: 1, MaxBurst: 5}
+	appErr := cfg.RateLimitSettings.isValid()
+	require.NotNil(t, appErr)
+	require.Equal(t, "model.config.is_valid.rate_route.app_error", appErr.Id)
+	delete(cfg.RateLimitSettings.RouteQuotas, "api/v4/posts")
+
+	cfg.RateLimitSettings.RouteQuotas["/api/v4/posts"] = &RateLimitQuota{PerSec: 0, MaxBurst: 5}
+	appErr = cfg.RateLimitSettings.isValid()
+	require.NotNil(t, appErr)
+	require.Equal(t, "model.config.is_valid.rate_sec.app_error", appErr.Id)
+
+	cfg.RateLimitSettings.RouteQuotas["/api/v4/posts"] = &RateLimitQuota{PerSec: 1, MaxBurst: 0}
+	appErr = cfg.RateLimitSettings.isValid()
+	require.NotNil(t, appErr)
+	require.Equal(t, "model.config.is_valid.max_burst.app_error", appErr.Id)
+	delete(cfg.RateLimitSettings.RouteQuotas, "/api/v4/posts")
+
+	cfg.RateLimitSettings.TokenQuotas["not-a-token-id"] = &RateLimitQuota{PerSec: 1, MaxBurst: 1}
+	appErr = cfg.RateLimitSettings.isValid()
+	require.NotNil(t, appErr)
+	require.Equal(t, "model.config.is_valid.rate_token.app_error", appErr.Id)
+}
+
 func TestConfigServiceSettingsIsValid(t *testing.T) {
 	t.Run("local socket file should exist if local mode enabled", func(t *testing.T) {
 		cfg := Config{}
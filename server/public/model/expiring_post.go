@@ -0,0 +1,62 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package model
+
+import (
+	"net/http"
+)
+
+// PostPropsExpiryMinutes overrides the post expiry of the channel for a single post. The
+// override can only shorten the expiry of a channel that has one.
+const PostPropsExpiryMinutes = "expiry_minutes"
+
+// ExpiringPost records when a post is due to be permanently deleted.
+type ExpiringPost struct {
+	PostId    string `json:"post_id"`
+	ChannelId string `json:"channel_id"`
+	ExpireAt  int64  `json:"expire_at"`
+}
+
+func (e *ExpiringPost) IsValid() *AppError {
+	if !IsValidId(e.PostId) {
+		return NewAppError("ExpiringPost.IsValid", "model.expiring_post.is_valid.post_id.app_error", nil, "post_id="+e.PostId, http.StatusBadRequest)
+	}
+
+	if !IsValidId(e.ChannelId) {
+		return NewAppError("ExpiringPost.IsValid", "model.expiring_post.is_valid.channel_id.app_error", nil, "channel_id="+e.ChannelId, http.StatusBadRequest)
+	}
+
+	if e.ExpireAt <= 0 {
+		return NewAppError("ExpiringPost.IsValid", "model.expiring_post.is_valid.expire_at.app_error", nil, "", http.StatusBadRequest)
+	}
+
+	return nil
+}
+
+// GetExpiryMinutes returns the expiry requested in the props of the post, or 0 if none
+// was requested.
+func (o *Post) GetExpiryMinutes() int64 {
+	switch v := o.GetProp(PostPropsExpiryMinutes).(type) {
+	case float64:
+		return int64(v)
+	case int64:
+		return v
+	case int:
+		return int64(v)
+	default:
+		return 0
+	}
+}
+
+// PostExpiryMinutesFor returns the number of minutes after which a post in the channel
+// expires, or 0 if it never does. The expiry requested by the post only applies if it
+// is shorter than the one of the channel.
+func (o *Channel) PostExpiryMinutesFor(post *Post) int64 {
+	minutes := o.PostExpiryMinutes
+	if requested := post.GetExpiryMinutes(); requested > 0 && (minutes == 0 || requested < minutes) {
+		minutes = requested
+	}
+
+	return minutes
+}
@@ -0,0 +1,69 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExpiringPostIsValid(t *testing.T) {
+	e := &ExpiringPost{
+		PostId:    NewId(),
+		ChannelId: NewId(),
+		ExpireAt:  GetMillis(),
+	}
+	require.Nil(t, e.IsValid())
+
+	invalid := *e
+	invalid.PostId = "invalid"
+	require.NotNil(t, invalid.IsValid())
+
+	invalid = *e
+	invalid.ChannelId = ""
+	require.NotNil(t, invalid.IsValid())
+
+	invalid = *e
+	invalid.ExpireAt = 0
+	require.NotNil(t, invalid.IsValid())
+}
+
+func TestPostGetExpiryMinutes(t *testing.T) {
+	post := &Post{}
+	assert.EqualValues(t, 0, post.GetExpiryMinutes())
+
+	post.AddProp(PostPropsExpiryMinutes, float64(30))
+	assert.EqualValues(t, 30, post.GetExpiryMinutes())
+
+	post.AddProp(PostPropsExpiryMinutes, 15)
+	assert.EqualValues(t, 15, post.GetExpiryMinutes())
+
+	post.AddProp(PostPropsExpiryMinutes, "10")
+	assert.EqualValues(t, 0, post.GetExpiryMinutes())
+}
+
+func TestChannelPostExpiryMinutesFor(t *testing.T) {
+	for name, tc := range map[string]struct {
+		channelMinutes int64
+		postMinutes    int64
+		expected       int64
+	}{
+		"no expiry":                    {0, 0, 0},
+		"channel expiry":               {60, 0, 60},
+		"post expiry":                  {0, 30, 30},
+		"shorter post expiry":          {60, 30, 30},
+		"longer post expiry (ignored)": {60, 90, 60},
+	} {
+		t.Run(name, func(t *testing.T) {
+			channel := &Channel{PostExpiryMinutes: tc.channelMinutes}
+			post := &Post{}
+			if tc.postMinutes > 0 {
+				post.AddProp(PostPropsExpiryMinutes, float64(tc.postMinutes))
+			}
+			assert.Equal(t, tc.expected, channel.PostExpiryMinutesFor(post))
+		})
+	}
+}
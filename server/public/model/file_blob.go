@@ -0,0 +1,73 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package model
+
+import (
+	"encoding/hex"
+	"net/http"
+)
+
+const (
+	FileBlobPathPrefix = "blobs/"
+	FileBlobHashLength = 64
+
+	// FileBlobTombstoneRefCount marks a blob whose contents are being removed from the file
+	// store. It can't be referenced again until its row is deleted.
+	FileBlobTombstoneRefCount = -1
+)
+
+// FileBlob is the contents of an uploaded file that is stored once, under a path derived
+// from its SHA-256, and shared by every FileInfo with the same ContentHash. RefCount is
+// the number of FileInfos referencing it; a blob is only removed from the file store
+// once its RefCount drops to zero.
+type FileBlob struct {
+	Hash     string `json:"hash"`
+	Path     string `json:"-"`
+	Size     int64  `json:"size"`
+	RefCount int64  `json:"ref_count"`
+	CreateAt int64  `json:"create_at"`
+	UpdateAt int64  `json:"update_at"`
+}
+
+func (b *FileBlob) IsValid() *AppError {
+	if !IsValidFileBlobHash(b.Hash) {
+		return NewAppError("FileBlob.IsValid", "model.file_blob.is_valid.hash.app_error", nil, "", http.StatusBadRequest)
+	}
+
+	if b.Path == "" {
+		return NewAppError("FileBlob.IsValid", "model.file_blob.is_valid.path.app_error", nil, "hash="+b.Hash, http.StatusBadRequest)
+	}
+
+	if b.Size < 0 {
+		return NewAppError("FileBlob.IsValid", "model.file_blob.is_valid.size.app_error", nil, "hash="+b.Hash, http.StatusBadRequest)
+	}
+
+	return nil
+}
+
+// IsValidFileBlobHash reports whether hash is a lower case, hex encoded SHA-256.
+func IsValidFileBlobHash(hash string) bool {
+	if len(hash) != FileBlobHashLength {
+		return false
+	}
+
+	for _, c := range hash {
+		if (c < '0' || c > '9') && (c < 'a' || c > 'f') {
+			return false
+		}
+	}
+
+	return true
+}
+
+// FileBlobPath returns the path in the file store of the blob with the given hash. Blobs
+// are spread over two levels of directories to keep the directories small.
+func FileBlobPath(hash string) string {
+	return FileBlobPathPrefix + hash[0:2] + "/" + hash[2:4] + "/" + hash
+}
+
+// FileBlobHash encodes a SHA-256 sum as a blob hash.
+func FileBlobHash(sum []byte) string {
+	return hex.EncodeToString(sum)
+}
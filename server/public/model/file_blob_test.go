@@ -0,0 +1,48 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package model
+
+import (
+	"crypto/sha256"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileBlobIsValid(t *testing.T) {
+	sum := sha256.Sum256([]byte("contents"))
+	hash := FileBlobHash(sum[:])
+	b := &FileBlob{
+		Hash:     hash,
+		Path:     FileBlobPath(hash),
+		Size:     8,
+		RefCount: 1,
+	}
+	require.Nil(t, b.IsValid())
+
+	invalid := *b
+	invalid.Hash = strings.ToUpper(hash)
+	require.NotNil(t, invalid.IsValid())
+
+	invalid = *b
+	invalid.Hash = hash[1:]
+	require.NotNil(t, invalid.IsValid())
+
+	invalid = *b
+	invalid.Path = ""
+	require.NotNil(t, invalid.IsValid())
+
+	invalid = *b
+	invalid.Size = -1
+	require.NotNil(t, invalid.IsValid())
+}
+
+func TestFileBlobPath(t *testing.T) {
+	sum := sha256.Sum256([]byte("contents"))
+	hash := FileBlobHash(sum[:])
+
+	assert.Equal(t, "blobs/"+hash[0:2]+"/"+hash[2:4]+"/"+hash, FileBlobPath(hash))
+}
@@ -15,6 +15,18 @@ const (
 	FileinfoSortBySize    = "Size"
 )
 
+const (
+	// FileScanStatusPending is set on files uploaded while virus scanning is enabled until
+	// they are scanned. Files uploaded before that have an empty scan status.
+	FileScanStatusPending  = "pending"
+	FileScanStatusClean    = "clean"
+	FileScanStatusInfected = "infected"
+	// FileScanStatusFailed is set when the scanner could not tell whether a file is clean.
+	FileScanStatusFailed = "failed"
+
+	FileScanResultMaxRunes = 256
+)
+
 // GetFileInfosOptions contains options for getting FileInfos
 type GetFileInfosOptions struct {
 	// UserIds optionally limits the FileInfos to those created by the given users.
@@ -29,6 +41,8 @@ type GetFileInfosOptions struct {
 	SortBy string `json:"sort_by"`
 	// SortDescending changes the sort direction to descending order when true.
 	SortDescending bool `json:"sort_descending"`
+	// ScanStatuses optionally limits the FileInfos to those with the given virus scan statuses.
+	ScanStatuses []string `json:"scan_statuses"`
 }
 
 type FileInfo struct {
@@ -57,6 +71,14 @@ type FileInfo struct {
 	Content         string  `json:"-"`
 	RemoteId        *string `json:"remote_id"`
 	Archived        bool    `json:"archived"`
+	ContentHash     string  `json:"-"` // set when Path is a deduplicated FileBlob
+	ScanStatus      string  `json:"scan_status,omitempty"`
+	ScanResult      string  `json:"scan_result,omitempty"` // the signature found by the virus scanner, or why the scan failed
+	// Duration is the length of video and audio files in milliseconds.
+	Duration      int64  `json:"duration,omitempty"`
+	VideoCodec    string `json:"video_codec,omitempty"`
+	AudioCodec    string `json:"audio_codec,omitempty"`
+	RenditionPath string `json:"-"` // the web-friendly transcoding of a video, not sent back to the client
 }
 
 func (fi *FileInfo) Auditable() map[string]interface{} {
@@ -117,9 +139,27 @@ func (fi *FileInfo) IsValid() *AppError {
 		return NewAppError("FileInfo.IsValid", "model.file_info.is_valid.path.app_error", nil, "id="+fi.Id, http.StatusBadRequest)
 	}
 
+	if !IsValidFileScanStatus(fi.ScanStatus) {
+		return NewAppError("FileInfo.IsValid", "model.file_info.is_valid.scan_status.app_error", nil, "id="+fi.Id, http.StatusBadRequest)
+	}
+
 	return nil
 }
 
+// IsQuarantined reports whether the file was found to be infected, or could not be scanned,
+// and was not released by an admin.
+func (fi *FileInfo) IsQuarantined() bool {
+	return fi.ScanStatus == FileScanStatusInfected || fi.ScanStatus == FileScanStatusFailed
+}
+
+func IsValidFileScanStatus(status string) bool {
+	switch status {
+	case "", FileScanStatusPending, FileScanStatusClean, FileScanStatusInfected, FileScanStatusFailed:
+		return true
+	}
+	return false
+}
+
 func (fi *FileInfo) IsImage() bool {
 	return strings.HasPrefix(fi.MimeType, "image")
 }
@@ -128,6 +168,14 @@ func (fi *FileInfo) IsSvg() bool {
 	return fi.MimeType == "image/svg+xml"
 }
 
+func (fi *FileInfo) IsVideo() bool {
+	return strings.HasPrefix(fi.MimeType, "video")
+}
+
+func (fi *FileInfo) IsAudio() bool {
+	return strings.HasPrefix(fi.MimeType, "audio")
+}
+
 func NewInfo(name string) *FileInfo {
 	info := &FileInfo{
 		Name: name,
@@ -174,4 +222,5 @@ func (fi *FileInfo) MakeContentInaccessible() {
 	fi.Path = ""
 	fi.PreviewPath = ""
 	fi.ThumbnailPath = ""
+	fi.RenditionPath = ""
 }
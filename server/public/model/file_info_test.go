@@ -60,6 +60,27 @@ func TestFileInfoIsValid(t *testing.T) {
 		assert.Nil(t, info.IsValid(), "creatorId isn't valid")
 		info.CreatorId = creatorId
 	})
+
+	t.Run("Unknown scan status is not valid", func(t *testing.T) {
+		info.ScanStatus = "unknown"
+		assert.NotNil(t, info.IsValid(), "unknown ScanStatus isn't valid")
+		info.ScanStatus = FileScanStatusPending
+		assert.Nil(t, info.IsValid())
+		info.ScanStatus = ""
+	})
+}
+
+func TestFileInfoIsQuarantined(t *testing.T) {
+	for status, quarantined := range map[string]bool{
+		"":                     false,
+		FileScanStatusPending:  false,
+		FileScanStatusClean:    false,
+		FileScanStatusInfected: true,
+		FileScanStatusFailed:   true,
+	} {
+		info := &FileInfo{ScanStatus: status}
+		assert.Equal(t, quarantined, info.IsQuarantined(), status)
+	}
 }
 
 func TestFileInfoIsImage(t *testing.T) {
@@ -74,3 +95,17 @@ func TestFileInfoIsImage(t *testing.T) {
 		assert.False(t, info.IsImage(), "Text file should not be considered as an image")
 	})
 }
+
+func TestFileInfoIsVideoOrAudio(t *testing.T) {
+	info := &FileInfo{MimeType: "video/mp4"}
+	assert.True(t, info.IsVideo())
+	assert.False(t, info.IsAudio())
+
+	info.MimeType = "audio/mpeg"
+	assert.False(t, info.IsVideo())
+	assert.True(t, info.IsAudio())
+
+	info.MimeType = "image/png"
+	assert.False(t, info.IsVideo())
+	assert.False(t, info.IsAudio())
+}
@@ -6,6 +6,7 @@ package model
 import (
 	"net/http"
 	"regexp"
+	"strings"
 )
 
 const (
@@ -287,6 +288,12 @@ func (group *Group) GetRemoteId() string {
 	return SafeDereference(group.RemoteId)
 }
 
+// IsScimManaged returns true if the group is provisioned by an identity provider through the
+// SCIM endpoint, in which case its members are managed by the identity provider.
+func (group *Group) IsScimManaged() bool {
+	return group.Source == GroupSourceCustom && strings.HasPrefix(group.GetRemoteId(), ScimGroupRemoteIDPrefix)
+}
+
 func (group *Group) GetMemberCount() int {
 	return SafeDereference(group.MemberCount)
 }
@@ -0,0 +1,61 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package model
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	IntegrationSignatureVersion    = "v1"
+	IntegrationSigningSecretLength = 32
+
+	// IntegrationSignatureMaxAge is how old a signed request may be before a
+	// receiver should treat it as a replay.
+	IntegrationSignatureMaxAge = 5 * time.Minute
+)
+
+// NewIntegrationSigningSecret returns a random secret used to sign the requests
+// sent to outgoing webhooks and slash commands.
+func NewIntegrationSigningSecret() string {
+	return NewRandomString(IntegrationSigningSecretLength)
+}
+
+// SignIntegrationPayload returns the value of the HeaderIntegrationSignature header
+// for a request sent at the given unix timestamp (in seconds). The signature is an
+// HMAC-SHA256 of "v1:<timestamp>:<payload>" keyed with the secret, where the payload
+// is the request body, or the raw query string for GET requests.
+func SignIntegrationPayload(secret string, timestamp int64, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(IntegrationSignatureVersion + ":" + strconv.FormatInt(timestamp, 10) + ":"))
+	mac.Write(payload)
+
+	return IntegrationSignatureVersion + "=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// VerifyIntegrationSignature checks a signature produced by SignIntegrationPayload,
+// rejecting requests whose timestamp is further than IntegrationSignatureMaxAge from now.
+func VerifyIntegrationSignature(secret, signature, timestamp string, payload []byte, now time.Time) bool {
+	if secret == "" || !strings.HasPrefix(signature, IntegrationSignatureVersion+"=") {
+		return false
+	}
+
+	ts, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return false
+	}
+
+	age := now.Sub(time.Unix(ts, 0))
+	if age > IntegrationSignatureMaxAge || age < -IntegrationSignatureMaxAge {
+		return false
+	}
+
+	expected := SignIntegrationPayload(secret, ts, payload)
+	return hmac.Equal([]byte(expected), []byte(signature))
+}
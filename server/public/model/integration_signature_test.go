@@ -0,0 +1,53 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package model
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSignIntegrationPayload(t *testing.T) {
+	secret := NewIntegrationSigningSecret()
+	require.Len(t, secret, IntegrationSigningSecretLength)
+
+	payload := []byte("token=abc&text=hello")
+	now := time.Now()
+	timestamp := now.Unix()
+
+	signature := SignIntegrationPayload(secret, timestamp, payload)
+	assert.Regexp(t, "^v1=[0-9a-f]{64}$", signature)
+	assert.Equal(t, signature, SignIntegrationPayload(secret, timestamp, payload))
+
+	t.Run("valid signature", func(t *testing.T) {
+		assert.True(t, VerifyIntegrationSignature(secret, signature, strconv.FormatInt(timestamp, 10), payload, now))
+	})
+
+	t.Run("tampered payload", func(t *testing.T) {
+		assert.False(t, VerifyIntegrationSignature(secret, signature, strconv.FormatInt(timestamp, 10), []byte("token=abc&text=bye"), now))
+	})
+
+	t.Run("tampered timestamp", func(t *testing.T) {
+		assert.False(t, VerifyIntegrationSignature(secret, signature, strconv.FormatInt(timestamp+1, 10), payload, now))
+	})
+
+	t.Run("wrong secret", func(t *testing.T) {
+		assert.False(t, VerifyIntegrationSignature(NewIntegrationSigningSecret(), signature, strconv.FormatInt(timestamp, 10), payload, now))
+		assert.False(t, VerifyIntegrationSignature("", signature, strconv.FormatInt(timestamp, 10), payload, now))
+	})
+
+	t.Run("replayed request", func(t *testing.T) {
+		later := now.Add(IntegrationSignatureMaxAge + time.Second)
+		assert.False(t, VerifyIntegrationSignature(secret, signature, strconv.FormatInt(timestamp, 10), payload, later))
+	})
+
+	t.Run("malformed input", func(t *testing.T) {
+		assert.False(t, VerifyIntegrationSignature(secret, "v0=abc", strconv.FormatInt(timestamp, 10), payload, now))
+		assert.False(t, VerifyIntegrationSignature(secret, signature, "not-a-number", payload, now))
+	})
+}
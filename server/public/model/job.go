@@ -40,6 +40,17 @@ const (
 	JobTypeDeleteOrphanDraftsMigration   = "delete_orphan_drafts_migration"
 	JobTypeExportUsersToCSV              = "export_users_to_csv"
 	JobTypeDeleteDmsPreferencesMigration = "delete_dms_preferences_migration"
+	JobTypeScheduledPosts                = "scheduled_posts"
+	JobTypeOutgoingWebhookDeliveries     = "outgoing_webhook_deliveries"
+	JobTypeDeleteExpiredPosts            = "delete_expired_posts"
+	JobTypeFileEncryption                = "file_encryption"
+	JobTypeFileDeduplication             = "file_deduplication"
+	JobTypePurgeFileBlobs                = "purge_file_blobs"
+	JobTypeRegenerateFilePreviews        = "regenerate_file_previews"
+	JobTypeTranscodeMedia                = "transcode_media"
+	JobTypeFileMigration                 = "file_migration"
+	JobTypeSemanticSearchIndexing        = "semantic_search_indexing"
+	JobTypeDeleteExpiredAuditRecords     = "delete_expired_audit_records"
 
 	JobStatusPending         = "pending"
 	JobStatusInProgress      = "in_progress"
@@ -72,6 +83,17 @@ var AllJobTypes = [...]string{
 	JobTypeLastAccessibleFile,
 	JobTypeCleanupDesktopTokens,
 	JobTypeRefreshPostStats,
+	JobTypeScheduledPosts,
+	JobTypeOutgoingWebhookDeliveries,
+	JobTypeDeleteExpiredPosts,
+	JobTypeFileEncryption,
+	JobTypeFileDeduplication,
+	JobTypePurgeFileBlobs,
+	JobTypeRegenerateFilePreviews,
+	JobTypeTranscodeMedia,
+	JobTypeFileMigration,
+	JobTypeSemanticSearchIndexing,
+	JobTypeDeleteExpiredAuditRecords,
 }
 
 type Job struct {
@@ -15,6 +15,9 @@ type MessageExport struct {
 	ChannelDisplayName *string
 	ChannelType        *ChannelType
 
+	// ChannelPostExpiryMinutes is the post expiry policy of the channel at export time.
+	ChannelPostExpiryMinutes *int64
+
 	UserId    *string
 	UserEmail *string
 	Username  *string
@@ -24,6 +27,7 @@ type MessageExport struct {
 	PostCreateAt   *int64
 	PostUpdateAt   *int64
 	PostDeleteAt   *int64
+	PostExpireAt   *int64
 	PostMessage    *string
 	PostType       *string
 	PostRootId     *string
@@ -12,22 +12,23 @@ import (
 )
 
 type OutgoingWebhook struct {
-	Id           string      `json:"id"`
-	Token        string      `json:"token"`
-	CreateAt     int64       `json:"create_at"`
-	UpdateAt     int64       `json:"update_at"`
-	DeleteAt     int64       `json:"delete_at"`
-	CreatorId    string      `json:"creator_id"`
-	ChannelId    string      `json:"channel_id"`
-	TeamId       string      `json:"team_id"`
-	TriggerWords StringArray `json:"trigger_words"`
-	TriggerWhen  int         `json:"trigger_when"`
-	CallbackURLs StringArray `json:"callback_urls"`
-	DisplayName  string      `json:"display_name"`
-	Description  string      `json:"description"`
-	ContentType  string      `json:"content_type"`
-	Username     string      `json:"username"`
-	IconURL      string      `json:"icon_url"`
+	Id            string      `json:"id"`
+	Token         string      `json:"token"`
+	SigningSecret string      `json:"signing_secret"`
+	CreateAt      int64       `json:"create_at"`
+	UpdateAt      int64       `json:"update_at"`
+	DeleteAt      int64       `json:"delete_at"`
+	CreatorId     string      `json:"creator_id"`
+	ChannelId     string      `json:"channel_id"`
+	TeamId        string      `json:"team_id"`
+	TriggerWords  StringArray `json:"trigger_words"`
+	TriggerWhen   int         `json:"trigger_when"`
+	CallbackURLs  StringArray `json:"callback_urls"`
+	DisplayName   string      `json:"display_name"`
+	Description   string      `json:"description"`
+	ContentType   string      `json:"content_type"`
+	Username      string      `json:"username"`
+	IconURL       string      `json:"icon_url"`
 }
 
 func (o *OutgoingWebhook) Auditable() map[string]interface{} {
@@ -105,6 +106,10 @@ func (o *OutgoingWebhook) IsValid() *AppError {
 		return NewAppError("OutgoingWebhook.IsValid", "model.outgoing_hook.is_valid.token.app_error", nil, "", http.StatusBadRequest)
 	}
 
+	if o.SigningSecret != "" && len(o.SigningSecret) != IntegrationSigningSecretLength {
+		return NewAppError("OutgoingWebhook.IsValid", "model.outgoing_hook.is_valid.signing_secret.app_error", nil, "", http.StatusBadRequest)
+	}
+
 	if o.CreateAt == 0 {
 		return NewAppError("OutgoingWebhook.IsValid", "model.outgoing_hook.is_valid.create_at.app_error", nil, "id="+o.Id, http.StatusBadRequest)
 	}
@@ -183,6 +188,10 @@ func (o *OutgoingWebhook) PreSave() {
 		o.Token = NewId()
 	}
 
+	if o.SigningSecret == "" {
+		o.SigningSecret = NewIntegrationSigningSecret()
+	}
+
 	o.CreateAt = GetMillis()
 	o.UpdateAt = o.CreateAt
 }
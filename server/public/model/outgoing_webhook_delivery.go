@@ -0,0 +1,126 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package model
+
+import (
+	"net/http"
+	"unicode/utf8"
+)
+
+const (
+	OutgoingWebhookDeliveryStatusPending    = "pending"
+	OutgoingWebhookDeliveryStatusDeadLetter = "dead_letter"
+
+	OutgoingWebhookDeliveryLastErrorMaxRunes = 1024
+)
+
+// OutgoingWebhookDelivery is an outgoing webhook request that failed and is waiting to be
+// retried, or that failed too many times and was moved to the dead-letter list.
+type OutgoingWebhookDelivery struct {
+	Id            string `json:"id"`
+	CreateAt      int64  `json:"create_at"`
+	UpdateAt      int64  `json:"update_at"`
+	HookId        string `json:"hook_id"`
+	TeamId        string `json:"team_id"`
+	ChannelId     string `json:"channel_id"`
+	PostId        string `json:"post_id"`
+	CallbackURL   string `json:"callback_url"`
+	ContentType   string `json:"content_type"`
+	Payload       string `json:"payload"`
+	Status        string `json:"status"`
+	Attempts      int    `json:"attempts"`
+	LastError     string `json:"last_error"`
+	LastAttemptAt int64  `json:"last_attempt_at"`
+	NextAttemptAt int64  `json:"next_attempt_at"`
+}
+
+func (d *OutgoingWebhookDelivery) Auditable() map[string]any {
+	return map[string]any{
+		"id":              d.Id,
+		"create_at":       d.CreateAt,
+		"update_at":       d.UpdateAt,
+		"hook_id":         d.HookId,
+		"team_id":         d.TeamId,
+		"channel_id":      d.ChannelId,
+		"post_id":         d.PostId,
+		"callback_url":    d.CallbackURL,
+		"status":          d.Status,
+		"attempts":        d.Attempts,
+		"last_attempt_at": d.LastAttemptAt,
+		"next_attempt_at": d.NextAttemptAt,
+	}
+}
+
+func (d *OutgoingWebhookDelivery) IsValid() *AppError {
+	if !IsValidId(d.Id) {
+		return NewAppError("OutgoingWebhookDelivery.IsValid", "model.outgoing_hook_delivery.is_valid.id.app_error", nil, "", http.StatusBadRequest)
+	}
+
+	if d.CreateAt == 0 {
+		return NewAppError("OutgoingWebhookDelivery.IsValid", "model.outgoing_hook_delivery.is_valid.create_at.app_error", nil, "id="+d.Id, http.StatusBadRequest)
+	}
+
+	if d.UpdateAt == 0 {
+		return NewAppError("OutgoingWebhookDelivery.IsValid", "model.outgoing_hook_delivery.is_valid.update_at.app_error", nil, "id="+d.Id, http.StatusBadRequest)
+	}
+
+	if !IsValidId(d.HookId) {
+		return NewAppError("OutgoingWebhookDelivery.IsValid", "model.outgoing_hook_delivery.is_valid.hook_id.app_error", nil, "id="+d.Id, http.StatusBadRequest)
+	}
+
+	if !IsValidId(d.TeamId) {
+		return NewAppError("OutgoingWebhookDelivery.IsValid", "model.outgoing_hook_delivery.is_valid.team_id.app_error", nil, "id="+d.Id, http.StatusBadRequest)
+	}
+
+	if !IsValidId(d.ChannelId) {
+		return NewAppError("OutgoingWebhookDelivery.IsValid", "model.outgoing_hook_delivery.is_valid.channel_id.app_error", nil, "id="+d.Id, http.StatusBadRequest)
+	}
+
+	if !IsValidId(d.PostId) {
+		return NewAppError("OutgoingWebhookDelivery.IsValid", "model.outgoing_hook_delivery.is_valid.post_id.app_error", nil, "id="+d.Id, http.StatusBadRequest)
+	}
+
+	if len(d.CallbackURL) > 1024 || !IsValidHTTPURL(d.CallbackURL) {
+		return NewAppError("OutgoingWebhookDelivery.IsValid", "model.outgoing_hook_delivery.is_valid.callback_url.app_error", nil, "id="+d.Id, http.StatusBadRequest)
+	}
+
+	if d.Status != OutgoingWebhookDeliveryStatusPending && d.Status != OutgoingWebhookDeliveryStatusDeadLetter {
+		return NewAppError("OutgoingWebhookDelivery.IsValid", "model.outgoing_hook_delivery.is_valid.status.app_error", nil, "id="+d.Id, http.StatusBadRequest)
+	}
+
+	return nil
+}
+
+func (d *OutgoingWebhookDelivery) PreSave() {
+	if d.Id == "" {
+		d.Id = NewId()
+	}
+
+	if d.Status == "" {
+		d.Status = OutgoingWebhookDeliveryStatusPending
+	}
+
+	d.CreateAt = GetMillis()
+	d.UpdateAt = d.CreateAt
+	d.truncateLastError()
+}
+
+func (d *OutgoingWebhookDelivery) PreUpdate() {
+	d.UpdateAt = GetMillis()
+	d.truncateLastError()
+}
+
+// Requeue moves a delivery back to the pending state so it's retried right away with a
+// fresh set of attempts.
+func (d *OutgoingWebhookDelivery) Requeue() {
+	d.Status = OutgoingWebhookDeliveryStatusPending
+	d.Attempts = 0
+	d.NextAttemptAt = GetMillis()
+}
+
+func (d *OutgoingWebhookDelivery) truncateLastError() {
+	if utf8.RuneCountInString(d.LastError) > OutgoingWebhookDeliveryLastErrorMaxRunes {
+		d.LastError = string([]rune(d.LastError)[:OutgoingWebhookDeliveryLastErrorMaxRunes])
+	}
+}
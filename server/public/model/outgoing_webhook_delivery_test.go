@@ -0,0 +1,63 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package model
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOutgoingWebhookDeliveryIsValid(t *testing.T) {
+	d := OutgoingWebhookDelivery{}
+	assert.NotNil(t, d.IsValid(), "empty declaration should be invalid")
+
+	d.PreSave()
+	assert.NotNil(t, d.IsValid(), "delivery without a hook should be invalid")
+
+	d.HookId = NewId()
+	d.TeamId = NewId()
+	d.ChannelId = NewId()
+	d.PostId = NewId()
+	assert.NotNil(t, d.IsValid(), "delivery without a callback URL should be invalid")
+
+	d.CallbackURL = "nowhere.com/"
+	assert.NotNil(t, d.IsValid(), "%s for CallbackURL should be invalid", d.CallbackURL)
+
+	d.CallbackURL = "http://nowhere.com/"
+	assert.Nil(t, d.IsValid())
+
+	d.Status = "unknown"
+	assert.NotNil(t, d.IsValid(), "%s for Status should be invalid", d.Status)
+
+	d.Status = OutgoingWebhookDeliveryStatusDeadLetter
+	assert.Nil(t, d.IsValid())
+}
+
+func TestOutgoingWebhookDeliveryPreSave(t *testing.T) {
+	d := OutgoingWebhookDelivery{
+		LastError: strings.Repeat("a", OutgoingWebhookDeliveryLastErrorMaxRunes+1),
+	}
+	d.PreSave()
+
+	require.NotEmpty(t, d.Id)
+	assert.Equal(t, OutgoingWebhookDeliveryStatusPending, d.Status)
+	assert.NotZero(t, d.CreateAt)
+	assert.Equal(t, d.CreateAt, d.UpdateAt)
+	assert.Len(t, d.LastError, OutgoingWebhookDeliveryLastErrorMaxRunes)
+}
+
+func TestOutgoingWebhookDeliveryRequeue(t *testing.T) {
+	d := OutgoingWebhookDelivery{
+		Status:   OutgoingWebhookDeliveryStatusDeadLetter,
+		Attempts: 6,
+	}
+	d.Requeue()
+
+	assert.Equal(t, OutgoingWebhookDeliveryStatusPending, d.Status)
+	assert.Zero(t, d.Attempts)
+	assert.NotZero(t, d.NextAttemptAt)
+}
@@ -0,0 +1,227 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package model
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	PostPropsPoll = "poll"
+
+	PollQuestionMaxRunes = 300
+	PollOptionMaxRunes   = 200
+	PollMinOptions       = 2
+	PollMaxOptions       = 20
+)
+
+type PollOption struct {
+	Id   string `json:"id"`
+	Text string `json:"text"`
+}
+
+// Poll is the definition of a poll. It is stored in the props of a post of type PostTypePoll,
+// while the votes are kept in their own store.
+type Poll struct {
+	Question       string        `json:"question"`
+	Options        []*PollOption `json:"options"`
+	Anonymous      bool          `json:"anonymous"`
+	MultipleChoice bool          `json:"multiple_choice"`
+
+	// CloseAt is the time after which the poll no longer accepts votes. A value of 0 means
+	// that the poll stays open until it is closed manually.
+	CloseAt int64 `json:"close_at"`
+
+	// ClosedAt is the time at which the poll was closed manually.
+	ClosedAt int64 `json:"closed_at"`
+}
+
+func (p *Poll) PreSave() {
+	p.Question = strings.TrimSpace(p.Question)
+
+	for _, option := range p.Options {
+		if option == nil {
+			continue
+		}
+		if option.Id == "" {
+			option.Id = NewId()
+		}
+		option.Text = strings.TrimSpace(option.Text)
+	}
+}
+
+func (p *Poll) IsValid() *AppError {
+	if p.Question == "" || utf8.RuneCountInString(p.Question) > PollQuestionMaxRunes {
+		return NewAppError("Poll.IsValid", "model.poll.is_valid.question.app_error", map[string]any{"MaxLength": PollQuestionMaxRunes}, "", http.StatusBadRequest)
+	}
+
+	if len(p.Options) < PollMinOptions || len(p.Options) > PollMaxOptions {
+		return NewAppError("Poll.IsValid", "model.poll.is_valid.options.app_error", map[string]any{"Min": PollMinOptions, "Max": PollMaxOptions}, "", http.StatusBadRequest)
+	}
+
+	seen := make(map[string]bool, len(p.Options))
+	for _, option := range p.Options {
+		if option == nil || !IsValidId(option.Id) || seen[option.Id] {
+			return NewAppError("Poll.IsValid", "model.poll.is_valid.option_id.app_error", nil, "", http.StatusBadRequest)
+		}
+		seen[option.Id] = true
+
+		if option.Text == "" || utf8.RuneCountInString(option.Text) > PollOptionMaxRunes {
+			return NewAppError("Poll.IsValid", "model.poll.is_valid.option_text.app_error", map[string]any{"MaxLength": PollOptionMaxRunes}, "", http.StatusBadRequest)
+		}
+	}
+
+	if p.CloseAt < 0 || p.ClosedAt < 0 {
+		return NewAppError("Poll.IsValid", "model.poll.is_valid.close_at.app_error", nil, "", http.StatusBadRequest)
+	}
+
+	return nil
+}
+
+// IsClosed reports whether the poll no longer accepts votes at the given time.
+func (p *Poll) IsClosed(now int64) bool {
+	return p.ClosedAt > 0 || (p.CloseAt > 0 && now >= p.CloseAt)
+}
+
+func (p *Poll) HasOption(optionID string) bool {
+	for _, option := range p.Options {
+		if option.Id == optionID {
+			return true
+		}
+	}
+	return false
+}
+
+// GetPoll returns the poll definition stored in the props of the post.
+func (o *Post) GetPoll() (*Poll, error) {
+	prop := o.GetProp(PostPropsPoll)
+	if prop == nil {
+		return nil, errors.New("post has no poll")
+	}
+
+	// The prop is a *Poll before the post is saved and a map afterwards, so round-trip it
+	// through JSON to handle both.
+	b, err := json.Marshal(prop)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal poll prop")
+	}
+
+	var poll Poll
+	if err := json.Unmarshal(b, &poll); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal poll prop")
+	}
+
+	return &poll, nil
+}
+
+// SetPoll stores the poll definition in the props of the post.
+func (o *Post) SetPoll(poll *Poll) error {
+	b, err := json.Marshal(poll)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal poll")
+	}
+
+	var prop map[string]any
+	if err := json.Unmarshal(b, &prop); err != nil {
+		return errors.Wrap(err, "failed to unmarshal poll")
+	}
+
+	o.AddProp(PostPropsPoll, prop)
+	return nil
+}
+
+type PollVote struct {
+	PostId   string `json:"post_id"`
+	UserId   string `json:"user_id"`
+	OptionId string `json:"option_id"`
+	CreateAt int64  `json:"create_at"`
+}
+
+func (v *PollVote) IsValid() *AppError {
+	if !IsValidId(v.PostId) {
+		return NewAppError("PollVote.IsValid", "model.poll_vote.is_valid.post_id.app_error", nil, "post_id="+v.PostId, http.StatusBadRequest)
+	}
+
+	if !IsValidId(v.UserId) {
+		return NewAppError("PollVote.IsValid", "model.poll_vote.is_valid.user_id.app_error", nil, "user_id="+v.UserId, http.StatusBadRequest)
+	}
+
+	if !IsValidId(v.OptionId) {
+		return NewAppError("PollVote.IsValid", "model.poll_vote.is_valid.option_id.app_error", nil, "option_id="+v.OptionId, http.StatusBadRequest)
+	}
+
+	if v.CreateAt == 0 {
+		return NewAppError("PollVote.IsValid", "model.poll_vote.is_valid.create_at.app_error", nil, "", http.StatusBadRequest)
+	}
+
+	return nil
+}
+
+type PollVoteRequest struct {
+	OptionIds []string `json:"option_ids"`
+}
+
+// PollMetadata holds the tallies of a poll. It is attached to the metadata of poll posts
+// before they are sent to the client.
+type PollMetadata struct {
+	// Votes maps each option id to the number of votes it received.
+	Votes map[string]int64 `json:"votes"`
+
+	// Voters maps each option id to the ids of the users that voted for it. It is omitted
+	// for anonymous polls.
+	Voters map[string][]string `json:"voters,omitempty"`
+
+	VoterCount int64 `json:"voter_count"`
+	Closed     bool  `json:"closed"`
+
+	// UserVotes holds the options the current user voted for.
+	UserVotes []string `json:"user_votes,omitempty"`
+}
+
+// NewPollMetadata tallies the votes of a poll. Votes for options that are no longer part
+// of the poll are ignored. If userID is not empty, the votes of that user are included.
+func NewPollMetadata(poll *Poll, votes []*PollVote, userID string, now int64) *PollMetadata {
+	metadata := &PollMetadata{
+		Votes:  make(map[string]int64, len(poll.Options)),
+		Closed: poll.IsClosed(now),
+	}
+	if !poll.Anonymous {
+		metadata.Voters = make(map[string][]string, len(poll.Options))
+	}
+
+	for _, option := range poll.Options {
+		metadata.Votes[option.Id] = 0
+	}
+
+	voters := make(map[string]bool)
+	for _, vote := range votes {
+		if _, ok := metadata.Votes[vote.OptionId]; !ok {
+			continue
+		}
+
+		metadata.Votes[vote.OptionId]++
+		if metadata.Voters != nil {
+			metadata.Voters[vote.OptionId] = append(metadata.Voters[vote.OptionId], vote.UserId)
+		}
+		if !voters[vote.UserId] {
+			voters[vote.UserId] = true
+			metadata.VoterCount++
+		}
+		if userID != "" && vote.UserId == userID {
+			metadata.UserVotes = append(metadata.UserVotes, vote.OptionId)
+		}
+	}
+
+	for _, userIDs := range metadata.Voters {
+		sort.Strings(userIDs)
+	}
+
+	return metadata
+}
@@ -0,0 +1,208 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package model
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestPoll() *Poll {
+	poll := &Poll{
+		Question: " What for lunch? ",
+		Options: []*PollOption{
+			{Text: "Pizza"},
+			{Text: " Sushi "},
+		},
+	}
+	poll.PreSave()
+	return poll
+}
+
+func TestPollPreSave(t *testing.T) {
+	poll := newTestPoll()
+
+	assert.Equal(t, "What for lunch?", poll.Question)
+	assert.Equal(t, "Sushi", poll.Options[1].Text)
+	for _, option := range poll.Options {
+		assert.True(t, IsValidId(option.Id))
+	}
+
+	id := poll.Options[0].Id
+	poll.PreSave()
+	assert.Equal(t, id, poll.Options[0].Id)
+}
+
+func TestPollIsValid(t *testing.T) {
+	require.Nil(t, newTestPoll().IsValid())
+
+	testCases := map[string]struct {
+		update func(poll *Poll)
+		errID  string
+	}{
+		"empty question": {
+			func(poll *Poll) { poll.Question = "" },
+			"model.poll.is_valid.question.app_error",
+		},
+		"question too long": {
+			func(poll *Poll) { poll.Question = strings.Repeat("a", PollQuestionMaxRunes+1) },
+			"model.poll.is_valid.question.app_error",
+		},
+		"too few options": {
+			func(poll *Poll) { poll.Options = poll.Options[:1] },
+			"model.poll.is_valid.options.app_error",
+		},
+		"too many options": {
+			func(poll *Poll) {
+				for len(poll.Options) <= PollMaxOptions {
+					poll.Options = append(poll.Options, &PollOption{Id: NewId(), Text: "Option"})
+				}
+			},
+			"model.poll.is_valid.options.app_error",
+		},
+		"duplicate option id": {
+			func(poll *Poll) { poll.Options[1].Id = poll.Options[0].Id },
+			"model.poll.is_valid.option_id.app_error",
+		},
+		"nil option": {
+			func(poll *Poll) { poll.Options[1] = nil },
+			"model.poll.is_valid.option_id.app_error",
+		},
+		"empty option text": {
+			func(poll *Poll) { poll.Options[1].Text = "" },
+			"model.poll.is_valid.option_text.app_error",
+		},
+		"negative close at": {
+			func(poll *Poll) { poll.CloseAt = -1 },
+			"model.poll.is_valid.close_at.app_error",
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			poll := newTestPoll()
+			tc.update(poll)
+
+			appErr := poll.IsValid()
+			require.NotNil(t, appErr)
+			assert.Equal(t, tc.errID, appErr.Id)
+		})
+	}
+}
+
+func TestPollIsClosed(t *testing.T) {
+	poll := newTestPoll()
+	assert.False(t, poll.IsClosed(GetMillis()))
+
+	poll.CloseAt = 1000
+	assert.False(t, poll.IsClosed(999))
+	assert.True(t, poll.IsClosed(1000))
+
+	poll.CloseAt = 0
+	poll.ClosedAt = 500
+	assert.True(t, poll.IsClosed(0))
+}
+
+func TestPostGetSetPoll(t *testing.T) {
+	post := &Post{}
+	_, err := post.GetPoll()
+	require.Error(t, err)
+
+	poll := newTestPoll()
+	post.AddProp(PostPropsPoll, poll)
+
+	got, err := post.GetPoll()
+	require.NoError(t, err)
+	assert.Equal(t, poll, got)
+
+	poll.Anonymous = true
+	require.NoError(t, post.SetPoll(poll))
+	assert.IsType(t, map[string]any{}, post.GetProp(PostPropsPoll))
+
+	// Simulate the post being loaded back from the database.
+	b, err := json.Marshal(post)
+	require.NoError(t, err)
+	var loaded Post
+	require.NoError(t, json.Unmarshal(b, &loaded))
+
+	got, err = loaded.GetPoll()
+	require.NoError(t, err)
+	assert.Equal(t, poll, got)
+}
+
+func TestPollVoteIsValid(t *testing.T) {
+	vote := PollVote{}
+
+	appErr := vote.IsValid()
+	require.NotNil(t, appErr)
+	assert.Equal(t, "model.poll_vote.is_valid.post_id.app_error", appErr.Id)
+
+	vote.PostId = NewId()
+	appErr = vote.IsValid()
+	require.NotNil(t, appErr)
+	assert.Equal(t, "model.poll_vote.is_valid.user_id.app_error", appErr.Id)
+
+	vote.UserId = NewId()
+	appErr = vote.IsValid()
+	require.NotNil(t, appErr)
+	assert.Equal(t, "model.poll_vote.is_valid.option_id.app_error", appErr.Id)
+
+	vote.OptionId = NewId()
+	appErr = vote.IsValid()
+	require.NotNil(t, appErr)
+	assert.Equal(t, "model.poll_vote.is_valid.create_at.app_error", appErr.Id)
+
+	vote.CreateAt = GetMillis()
+	require.Nil(t, vote.IsValid())
+}
+
+func TestNewPollMetadata(t *testing.T) {
+	poll := newTestPoll()
+	pizza := poll.Options[0].Id
+	sushi := poll.Options[1].Id
+	userID1 := NewId()
+	userID2 := NewId()
+
+	votes := []*PollVote{
+		{UserId: userID1, OptionId: pizza},
+		{UserId: userID1, OptionId: sushi},
+		{UserId: userID2, OptionId: sushi},
+		{UserId: userID2, OptionId: NewId()},
+	}
+
+	t.Run("visible votes", func(t *testing.T) {
+		metadata := NewPollMetadata(poll, votes, userID1, GetMillis())
+
+		assert.Equal(t, map[string]int64{pizza: 1, sushi: 2}, metadata.Votes)
+		assert.Equal(t, int64(2), metadata.VoterCount)
+		assert.Equal(t, []string{userID1}, metadata.Voters[pizza])
+		assert.ElementsMatch(t, []string{userID1, userID2}, metadata.Voters[sushi])
+		assert.Equal(t, []string{pizza, sushi}, metadata.UserVotes)
+		assert.False(t, metadata.Closed)
+	})
+
+	t.Run("anonymous votes", func(t *testing.T) {
+		anonymousPoll := *poll
+		anonymousPoll.Anonymous = true
+		anonymousPoll.ClosedAt = GetMillis()
+
+		metadata := NewPollMetadata(&anonymousPoll, votes, "", GetMillis())
+
+		assert.Equal(t, map[string]int64{pizza: 1, sushi: 2}, metadata.Votes)
+		assert.Nil(t, metadata.Voters)
+		assert.Nil(t, metadata.UserVotes)
+		assert.True(t, metadata.Closed)
+	})
+
+	t.Run("no votes", func(t *testing.T) {
+		metadata := NewPollMetadata(poll, nil, userID1, GetMillis())
+
+		assert.Equal(t, map[string]int64{pizza: 0, sushi: 0}, metadata.Votes)
+		assert.Zero(t, metadata.VoterCount)
+	})
+}
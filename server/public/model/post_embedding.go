@@ -0,0 +1,86 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package model
+
+import (
+	"net/http"
+)
+
+const PostEmbeddingProviderMaxLength = 128
+
+// PostEmbedding is the embedding of the message of a post, computed by an embedding provider for
+// semantic search. The channel, user and creation time of the post are kept along with it, so
+// that the embeddings can be deleted with the posts of a channel or a user, or by data retention.
+type PostEmbedding struct {
+	PostId    string    `json:"post_id"`
+	ChannelId string    `json:"channel_id"`
+	UserId    string    `json:"user_id"`
+	CreateAt  int64     `json:"create_at"`
+	Provider  string    `json:"provider"`
+	Embedding []float32 `json:"embedding"`
+	UpdateAt  int64     `json:"update_at"`
+}
+
+// PostEmbeddingSearchOptions selects the posts whose embeddings are compared with the embedding
+// of a search. The posts are returned from the most recent, a page at a time.
+type PostEmbeddingSearchOptions struct {
+	ChannelIDs []string
+	// UserIDs restricts the posts to the ones of the given users when it isn't empty.
+	UserIDs         []string
+	ExcludedUserIDs []string
+	// CreatedAfter and CreatedBefore bound the creation time of the posts, inclusively, when
+	// they aren't zero.
+	CreatedAfter  int64
+	CreatedBefore int64
+	// BeforeCreateAt and BeforeID are the creation time and the id of the last post of the
+	// previous page, if any.
+	BeforeCreateAt int64
+	BeforeID       string
+	Limit          int
+}
+
+// PostWithEmbedding is a post along with the embedding of its message.
+type PostWithEmbedding struct {
+	Post      *Post
+	Embedding []float32
+}
+
+func NewPostEmbedding(post *Post, provider string, embedding []float32) *PostEmbedding {
+	return &PostEmbedding{
+		PostId:    post.Id,
+		ChannelId: post.ChannelId,
+		UserId:    post.UserId,
+		CreateAt:  post.CreateAt,
+		Provider:  provider,
+		Embedding: embedding,
+	}
+}
+
+func (e *PostEmbedding) PreSave() {
+	e.UpdateAt = GetMillis()
+}
+
+func (e *PostEmbedding) IsValid() *AppError {
+	if !IsValidId(e.PostId) {
+		return NewAppError("PostEmbedding.IsValid", "model.post_embedding.is_valid.post_id.app_error", nil, "", http.StatusBadRequest)
+	}
+
+	if !IsValidId(e.ChannelId) {
+		return NewAppError("PostEmbedding.IsValid", "model.post_embedding.is_valid.channel_id.app_error", nil, "post_id="+e.PostId, http.StatusBadRequest)
+	}
+
+	if !IsValidId(e.UserId) {
+		return NewAppError("PostEmbedding.IsValid", "model.post_embedding.is_valid.user_id.app_error", nil, "post_id="+e.PostId, http.StatusBadRequest)
+	}
+
+	if e.Provider == "" || len(e.Provider) > PostEmbeddingProviderMaxLength {
+		return NewAppError("PostEmbedding.IsValid", "model.post_embedding.is_valid.provider.app_error", nil, "post_id="+e.PostId, http.StatusBadRequest)
+	}
+
+	if len(e.Embedding) == 0 {
+		return NewAppError("PostEmbedding.IsValid", "model.post_embedding.is_valid.embedding.app_error", nil, "post_id="+e.PostId, http.StatusBadRequest)
+	}
+
+	return nil
+}
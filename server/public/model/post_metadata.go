@@ -28,6 +28,12 @@ type PostMetadata struct {
 
 	// Acknowledgements holds acknowledgements made by users to the post
 	Acknowledgements []*PostAcknowledgement `json:"acknowledgements,omitempty"`
+
+	// Poll holds the vote tallies if the post is a poll.
+	Poll *PollMetadata `json:"poll,omitempty"`
+
+	// Translations holds the machine translations of the post that were requested, keyed by language.
+	Translations map[string]*Translation `json:"translations,omitempty"`
 }
 
 func (p *PostMetadata) Auditable() map[string]any {
@@ -47,6 +53,8 @@ func (p *PostMetadata) Auditable() map[string]any {
 		"reactions":        p.Reactions,
 		"priority":         p.Priority,
 		"acknowledgements": p.Acknowledgements,
+		"poll":             p.Poll,
+		"translations":     p.Translations,
 	}
 }
 
@@ -94,6 +102,33 @@ func (p *PostMetadata) Copy() *PostMetadata {
 		}
 	}
 
+	var pollCopy *PollMetadata
+	if p.Poll != nil {
+		pollCopy = &PollMetadata{
+			Votes:      make(map[string]int64, len(p.Poll.Votes)),
+			VoterCount: p.Poll.VoterCount,
+			Closed:     p.Poll.Closed,
+			UserVotes:  append([]string(nil), p.Poll.UserVotes...),
+		}
+		for k, v := range p.Poll.Votes {
+			pollCopy.Votes[k] = v
+		}
+		if p.Poll.Voters != nil {
+			pollCopy.Voters = make(map[string][]string, len(p.Poll.Voters))
+			for k, v := range p.Poll.Voters {
+				pollCopy.Voters[k] = append([]string(nil), v...)
+			}
+		}
+	}
+
+	var translationsCopy map[string]*Translation
+	if p.Translations != nil {
+		translationsCopy = make(map[string]*Translation, len(p.Translations))
+		for k, v := range p.Translations {
+			translationsCopy[k] = v
+		}
+	}
+
 	return &PostMetadata{
 		Embeds:           embedsCopy,
 		Emojis:           emojisCopy,
@@ -102,5 +137,7 @@ func (p *PostMetadata) Copy() *PostMetadata {
 		Reactions:        reactionsCopy,
 		Priority:         postPriorityCopy,
 		Acknowledgements: acknowledgementsCopy,
+		Poll:             pollCopy,
+		Translations:     translationsCopy,
 	}
 }
@@ -0,0 +1,12 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package model
+
+// PostReadReceipt indicates that a user has seen a post. Receipts are derived from the
+// LastViewedAt of the user's channel membership rather than stored individually.
+type PostReadReceipt struct {
+	PostId       string `json:"post_id"`
+	UserId       string `json:"user_id"`
+	LastViewedAt int64  `json:"last_viewed_at"`
+}
@@ -53,6 +53,10 @@ const (
 	PreferenceCategoryNotifications = "notifications"
 	PreferenceNameEmailInterval     = "email_interval"
 
+	// PreferenceNameSendReadReceipts is stored under PreferenceCategoryAdvancedSettings. A value
+	// of "false" stops other users from seeing when the user has read their posts.
+	PreferenceNameSendReadReceipts = "send_read_receipts"
+
 	PreferenceEmailIntervalNoBatchingSeconds = "30"  // the "immediate" setting is actually 30s
 	PreferenceEmailIntervalBatchingSeconds   = "900" // fifteen minutes is 900 seconds
 	PreferenceEmailIntervalImmediately       = "immediately"
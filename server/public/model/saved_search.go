@@ -0,0 +1,158 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package model
+
+import (
+	"net/http"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	SavedSearchNameMaxRunes  = 64
+	SavedSearchTermsMaxRunes = 1024
+	SavedSearchMaxPerUser    = 100
+
+	// SavedSearchAlertNotification sends a websocket event to the subscriber for each new post
+	// matching the saved search.
+	SavedSearchAlertNotification = "notification"
+	// SavedSearchAlertDirectMessage sends a direct message from the system bot to the subscriber
+	// for each new post matching the saved search.
+	SavedSearchAlertDirectMessage = "direct_message"
+)
+
+// SavedSearch is a post search that a user can run again later. Users subscribed to a saved
+// search are alerted of the new posts matching it as they are created.
+type SavedSearch struct {
+	Id             string `json:"id"`
+	CreateAt       int64  `json:"create_at"`
+	UpdateAt       int64  `json:"update_at"`
+	UserId         string `json:"user_id"`
+	TeamId         string `json:"team_id"`
+	Name           string `json:"name"`
+	Terms          string `json:"terms"`
+	IsOrSearch     bool   `json:"is_or_search"`
+	TimeZoneOffset int    `json:"time_zone_offset"`
+	Subscribed     bool   `json:"subscribed"`
+	AlertType      string `json:"alert_type"`
+
+	// The IDs of the channels and users named in the filters of the terms. They're resolved when
+	// the saved search is saved, so that matching new posts against it doesn't look them up.
+	InChannelIds       StringArray `json:"-"`
+	ExcludedChannelIds StringArray `json:"-"`
+	FromUserIds        StringArray `json:"-"`
+	ExcludedUserIds    StringArray `json:"-"`
+}
+
+func (s *SavedSearch) IsValid() *AppError {
+	if !IsValidId(s.Id) {
+		return NewAppError("SavedSearch.IsValid", "model.saved_search.is_valid.id.app_error", nil, "", http.StatusBadRequest)
+	}
+
+	if s.CreateAt == 0 {
+		return NewAppError("SavedSearch.IsValid", "model.saved_search.is_valid.create_at.app_error", nil, "id="+s.Id, http.StatusBadRequest)
+	}
+
+	if s.UpdateAt == 0 {
+		return NewAppError("SavedSearch.IsValid", "model.saved_search.is_valid.update_at.app_error", nil, "id="+s.Id, http.StatusBadRequest)
+	}
+
+	if !IsValidId(s.UserId) {
+		return NewAppError("SavedSearch.IsValid", "model.saved_search.is_valid.user_id.app_error", nil, "id="+s.Id, http.StatusBadRequest)
+	}
+
+	if !IsValidId(s.TeamId) {
+		return NewAppError("SavedSearch.IsValid", "model.saved_search.is_valid.team_id.app_error", nil, "id="+s.Id, http.StatusBadRequest)
+	}
+
+	if s.Name == "" || utf8.RuneCountInString(s.Name) > SavedSearchNameMaxRunes {
+		return NewAppError("SavedSearch.IsValid", "model.saved_search.is_valid.name.app_error", map[string]any{"Max": SavedSearchNameMaxRunes}, "id="+s.Id, http.StatusBadRequest)
+	}
+
+	if utf8.RuneCountInString(s.Terms) > SavedSearchTermsMaxRunes || len(s.SearchParams()) == 0 {
+		return NewAppError("SavedSearch.IsValid", "model.saved_search.is_valid.terms.app_error", map[string]any{"Max": SavedSearchTermsMaxRunes}, "id="+s.Id, http.StatusBadRequest)
+	}
+
+	if s.AlertType != SavedSearchAlertNotification && s.AlertType != SavedSearchAlertDirectMessage {
+		return NewAppError("SavedSearch.IsValid", "model.saved_search.is_valid.alert_type.app_error", nil, "id="+s.Id, http.StatusBadRequest)
+	}
+
+	// New posts are matched against subscribed saved searches by their terms, which can't
+	// reproduce the results of a semantic search.
+	if s.Subscribed && s.IsSemantic() {
+		return NewAppError("SavedSearch.IsValid", "model.saved_search.is_valid.subscribed_semantic.app_error", nil, "id="+s.Id, http.StatusBadRequest)
+	}
+
+	return nil
+}
+
+func (s *SavedSearch) PreSave() {
+	if s.Id == "" {
+		s.Id = NewId()
+	}
+
+	s.CreateAt = GetMillis()
+	s.UpdateAt = s.CreateAt
+	s.PreCommit()
+}
+
+func (s *SavedSearch) PreUpdate() {
+	s.UpdateAt = GetMillis()
+	s.PreCommit()
+}
+
+func (s *SavedSearch) PreCommit() {
+	s.Name = strings.TrimSpace(s.Name)
+	s.Terms = strings.TrimSpace(s.Terms)
+
+	if s.AlertType == "" {
+		s.AlertType = SavedSearchAlertNotification
+	}
+}
+
+// SearchParams parses the terms of the saved search. Searching for "*" isn't allowed, so those
+// params are left out.
+func (s *SavedSearch) SearchParams() []*SearchParams {
+	paramsList := []*SearchParams{}
+	for _, params := range ParseSearchParams(strings.TrimSpace(s.Terms), s.TimeZoneOffset) {
+		if params.Terms == "*" {
+			continue
+		}
+		params.OrTerms = s.IsOrSearch
+		paramsList = append(paramsList, params)
+	}
+	return paramsList
+}
+
+// IsSemantic reports whether the terms of the saved search ask for a semantic search.
+func (s *SavedSearch) IsSemantic() bool {
+	for _, params := range s.SearchParams() {
+		if params.Semantic {
+			return true
+		}
+	}
+	return false
+}
+
+// RestoreNonUpdatableFields copies the fields a client is not allowed to change from the stored
+// saved search.
+func (s *SavedSearch) RestoreNonUpdatableFields(originalSavedSearch *SavedSearch) {
+	s.Id = originalSavedSearch.Id
+	s.CreateAt = originalSavedSearch.CreateAt
+	s.UserId = originalSavedSearch.UserId
+	s.TeamId = originalSavedSearch.TeamId
+}
+
+func (s *SavedSearch) Auditable() map[string]any {
+	return map[string]any{
+		"id":           s.Id,
+		"create_at":    s.CreateAt,
+		"update_at":    s.UpdateAt,
+		"user_id":      s.UserId,
+		"team_id":      s.TeamId,
+		"is_or_search": s.IsOrSearch,
+		"subscribed":   s.Subscribed,
+		"alert_type":   s.AlertType,
+	}
+}
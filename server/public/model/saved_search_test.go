@@ -0,0 +1,138 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package model
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSavedSearchIsValid(t *testing.T) {
+	newSavedSearch := func() *SavedSearch {
+		savedSearch := &SavedSearch{
+			UserId: NewId(),
+			TeamId: NewId(),
+			Name:   "Production errors",
+			Terms:  "from:alertbot in:prod error",
+		}
+		savedSearch.PreSave()
+		return savedSearch
+	}
+
+	t.Run("valid saved search", func(t *testing.T) {
+		savedSearch := newSavedSearch()
+		assert.Nil(t, savedSearch.IsValid())
+		assert.Equal(t, SavedSearchAlertNotification, savedSearch.AlertType)
+	})
+
+	t.Run("invalid id", func(t *testing.T) {
+		savedSearch := newSavedSearch()
+		savedSearch.Id = "invalid"
+		assert.NotNil(t, savedSearch.IsValid())
+	})
+
+	t.Run("invalid user and team", func(t *testing.T) {
+		savedSearch := newSavedSearch()
+		savedSearch.UserId = ""
+		assert.NotNil(t, savedSearch.IsValid())
+
+		savedSearch = newSavedSearch()
+		savedSearch.TeamId = "invalid"
+		assert.NotNil(t, savedSearch.IsValid())
+	})
+
+	t.Run("invalid name", func(t *testing.T) {
+		savedSearch := newSavedSearch()
+		savedSearch.Name = "   "
+		savedSearch.PreUpdate()
+		assert.NotNil(t, savedSearch.IsValid())
+
+		savedSearch.Name = strings.Repeat("a", SavedSearchNameMaxRunes+1)
+		assert.NotNil(t, savedSearch.IsValid())
+
+		savedSearch.Name = strings.Repeat("a", SavedSearchNameMaxRunes)
+		assert.Nil(t, savedSearch.IsValid())
+	})
+
+	t.Run("invalid terms", func(t *testing.T) {
+		savedSearch := newSavedSearch()
+		savedSearch.Terms = ""
+		assert.NotNil(t, savedSearch.IsValid())
+
+		savedSearch.Terms = "*"
+		assert.NotNil(t, savedSearch.IsValid())
+
+		savedSearch.Terms = strings.Repeat("a", SavedSearchTermsMaxRunes+1)
+		assert.NotNil(t, savedSearch.IsValid())
+
+		savedSearch.Terms = "from:alertbot"
+		assert.Nil(t, savedSearch.IsValid())
+	})
+
+	t.Run("invalid alert type", func(t *testing.T) {
+		savedSearch := newSavedSearch()
+		savedSearch.AlertType = "email"
+		assert.NotNil(t, savedSearch.IsValid())
+
+		savedSearch.AlertType = SavedSearchAlertDirectMessage
+		assert.Nil(t, savedSearch.IsValid())
+	})
+
+	t.Run("subscribed semantic search", func(t *testing.T) {
+		savedSearch := newSavedSearch()
+		savedSearch.Terms = "deployment failures semantic:true"
+		assert.Nil(t, savedSearch.IsValid())
+
+		savedSearch.Subscribed = true
+		assert.NotNil(t, savedSearch.IsValid())
+
+		savedSearch.Terms = "deployment failures"
+		assert.Nil(t, savedSearch.IsValid())
+	})
+}
+
+func TestSavedSearchSearchParams(t *testing.T) {
+	savedSearch := &SavedSearch{
+		Terms:          "error #prod from:alertbot",
+		IsOrSearch:     true,
+		TimeZoneOffset: 3600,
+	}
+
+	paramsList := savedSearch.SearchParams()
+	require.Len(t, paramsList, 2)
+	for _, params := range paramsList {
+		assert.True(t, params.OrTerms)
+		assert.Equal(t, 3600, params.TimeZoneOffset)
+		assert.Equal(t, []string{"alertbot"}, params.FromUsers)
+	}
+	assert.Equal(t, "error", paramsList[0].Terms)
+	assert.Equal(t, "#prod", paramsList[1].Terms)
+}
+
+func TestSavedSearchRestoreNonUpdatableFields(t *testing.T) {
+	original := &SavedSearch{
+		Id:       NewId(),
+		CreateAt: 1,
+		UserId:   NewId(),
+		TeamId:   NewId(),
+		Name:     "original",
+	}
+	updated := &SavedSearch{
+		Id:       NewId(),
+		CreateAt: 2,
+		UserId:   NewId(),
+		TeamId:   NewId(),
+		Name:     "updated",
+	}
+
+	updated.RestoreNonUpdatableFields(original)
+	assert.Equal(t, original.Id, updated.Id)
+	assert.Equal(t, original.CreateAt, updated.CreateAt)
+	assert.Equal(t, original.UserId, updated.UserId)
+	assert.Equal(t, original.TeamId, updated.TeamId)
+	assert.Equal(t, "updated", updated.Name)
+}
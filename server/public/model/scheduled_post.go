@@ -0,0 +1,150 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package model
+
+import (
+	"net/http"
+)
+
+const (
+	ScheduledPostErrorCodeUnknown             = "unknown"
+	ScheduledPostErrorCodeChannelArchived     = "channel_archived"
+	ScheduledPostErrorCodeChannelNotFound     = "channel_not_found"
+	ScheduledPostErrorCodeUserDoesNotExist    = "user_missing"
+	ScheduledPostErrorCodeUserDeleted         = "user_deleted"
+	ScheduledPostErrorCodeNoChannelPermission = "no_channel_permission"
+	ScheduledPostErrorCodeNoChannelMember     = "no_channel_member"
+	ScheduledPostErrorCodeThreadDeleted       = "thread_deleted"
+	ScheduledPostErrorCodeUnableToSend        = "unable_to_send"
+	ScheduledPostErrorCodeInvalidPost         = "invalid_post"
+)
+
+// ScheduledPost is a draft that the server will post on behalf of its author
+// once ScheduledAt has passed. Posts that could not be delivered are kept with
+// ProcessedAt and ErrorCode set so the author can see why.
+type ScheduledPost struct {
+	Draft
+	Id          string `json:"id"`
+	ScheduledAt int64  `json:"scheduled_at"`
+	ProcessedAt int64  `json:"processed_at"`
+	ErrorCode   string `json:"error_code"`
+}
+
+func (s *ScheduledPost) IsValid(maxMessageSize int) *AppError {
+	draftAppErr := s.Draft.IsValid(maxMessageSize)
+	if draftAppErr != nil {
+		return draftAppErr
+	}
+
+	return s.BaseIsValid()
+}
+
+func (s *ScheduledPost) BaseIsValid() *AppError {
+	if !IsValidId(s.Id) {
+		return NewAppError("ScheduledPost.IsValid", "model.scheduled_post.is_valid.id.app_error", nil, "id="+s.Id, http.StatusBadRequest)
+	}
+
+	if s.Message == "" && len(s.FileIds) == 0 {
+		return NewAppError("ScheduledPost.IsValid", "model.scheduled_post.is_valid.empty_post.app_error", nil, "id="+s.Id, http.StatusBadRequest)
+	}
+
+	if (s.ScheduledAt < s.CreateAt) && s.ProcessedAt == 0 {
+		return NewAppError("ScheduledPost.IsValid", "model.scheduled_post.is_valid.scheduled_at.app_error", nil, "id="+s.Id, http.StatusBadRequest)
+	}
+
+	if s.ProcessedAt < s.ScheduledAt && s.ProcessedAt != 0 {
+		return NewAppError("ScheduledPost.IsValid", "model.scheduled_post.is_valid.processed_at.app_error", nil, "id="+s.Id, http.StatusBadRequest)
+	}
+
+	return nil
+}
+
+func (s *ScheduledPost) PreSave() {
+	if s.Id == "" {
+		s.Id = NewId()
+	}
+
+	s.ProcessedAt = 0
+	s.ErrorCode = ""
+
+	s.Draft.PreSave()
+}
+
+func (s *ScheduledPost) PreUpdate() {
+	s.Draft.UpdateAt = GetMillis()
+	s.Draft.PreCommit()
+}
+
+// ToPost converts a scheduled post to a regular, mattermost post object.
+func (s *ScheduledPost) ToPost() *Post {
+	post := &Post{
+		UserId:    s.UserId,
+		ChannelId: s.ChannelId,
+		Message:   s.Message,
+		FileIds:   s.FileIds,
+		RootId:    s.RootId,
+	}
+
+	for key, value := range s.GetProps() {
+		post.AddProp(key, value)
+	}
+
+	if len(s.Priority) > 0 {
+		priority := &PostPriority{}
+		if value, ok := s.Priority["priority"].(string); ok {
+			priority.Priority = NewPointer(value)
+		}
+		if value, ok := s.Priority["requested_ack"].(bool); ok {
+			priority.RequestedAck = NewPointer(value)
+		}
+		if value, ok := s.Priority["persistent_notifications"].(bool); ok {
+			priority.PersistentNotifications = NewPointer(value)
+		}
+
+		post.Metadata = &PostMetadata{Priority: priority}
+	}
+
+	return post
+}
+
+func (s *ScheduledPost) Auditable() map[string]any {
+	var metaData map[string]any
+	if s.Metadata != nil {
+		metaData = s.Metadata.Auditable()
+	}
+
+	return map[string]any{
+		"id":           s.Id,
+		"create_at":    s.CreateAt,
+		"update_at":    s.UpdateAt,
+		"user_id":      s.UserId,
+		"channel_id":   s.ChannelId,
+		"root_id":      s.RootId,
+		"props":        s.GetProps(),
+		"file_ids":     s.FileIds,
+		"metadata":     metaData,
+		"scheduled_at": s.ScheduledAt,
+		"processed_at": s.ProcessedAt,
+		"error_code":   s.ErrorCode,
+	}
+}
+
+// RestoreNonUpdatableFields copies the fields a client is not allowed to
+// change from the stored scheduled post.
+func (s *ScheduledPost) RestoreNonUpdatableFields(originalScheduledPost *ScheduledPost) {
+	s.Id = originalScheduledPost.Id
+	s.CreateAt = originalScheduledPost.CreateAt
+	s.UserId = originalScheduledPost.UserId
+	s.ChannelId = originalScheduledPost.ChannelId
+	s.RootId = originalScheduledPost.RootId
+}
+
+// SanitizeInput clears the fields only the server may set.
+func (s *ScheduledPost) SanitizeInput() {
+	s.CreateAt = 0
+
+	if s.Metadata != nil {
+		s.Metadata.Embeds = nil
+	}
+}
@@ -0,0 +1,139 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScheduledPostIsValid(t *testing.T) {
+	maxMessageSize := 10000
+
+	newScheduledPost := func() *ScheduledPost {
+		scheduledPost := &ScheduledPost{
+			Draft: Draft{
+				UserId:    NewId(),
+				ChannelId: NewId(),
+				Message:   "this is a scheduled post",
+			},
+			ScheduledAt: GetMillis() + 100000,
+		}
+		scheduledPost.PreSave()
+		return scheduledPost
+	}
+
+	t.Run("valid scheduled post", func(t *testing.T) {
+		assert.Nil(t, newScheduledPost().IsValid(maxMessageSize))
+	})
+
+	t.Run("invalid id", func(t *testing.T) {
+		scheduledPost := newScheduledPost()
+		scheduledPost.Id = "invalid"
+		assert.NotNil(t, scheduledPost.IsValid(maxMessageSize))
+	})
+
+	t.Run("empty message and no files", func(t *testing.T) {
+		scheduledPost := newScheduledPost()
+		scheduledPost.Message = ""
+		assert.NotNil(t, scheduledPost.IsValid(maxMessageSize))
+
+		scheduledPost.FileIds = []string{NewId()}
+		assert.Nil(t, scheduledPost.IsValid(maxMessageSize))
+	})
+
+	t.Run("scheduled in the past", func(t *testing.T) {
+		scheduledPost := newScheduledPost()
+		scheduledPost.ScheduledAt = scheduledPost.CreateAt - 1
+		assert.NotNil(t, scheduledPost.IsValid(maxMessageSize))
+	})
+
+	t.Run("processed before scheduled time", func(t *testing.T) {
+		scheduledPost := newScheduledPost()
+		scheduledPost.ProcessedAt = scheduledPost.ScheduledAt - 1
+		assert.NotNil(t, scheduledPost.IsValid(maxMessageSize))
+
+		scheduledPost.ProcessedAt = scheduledPost.ScheduledAt + 1
+		assert.Nil(t, scheduledPost.IsValid(maxMessageSize))
+	})
+}
+
+func TestScheduledPostPreSave(t *testing.T) {
+	scheduledPost := &ScheduledPost{
+		ProcessedAt: 1,
+		ErrorCode:   ScheduledPostErrorCodeChannelArchived,
+	}
+	scheduledPost.PreSave()
+
+	assert.True(t, IsValidId(scheduledPost.Id))
+	assert.NotZero(t, scheduledPost.CreateAt)
+	assert.Equal(t, scheduledPost.CreateAt, scheduledPost.UpdateAt)
+	assert.Zero(t, scheduledPost.ProcessedAt)
+	assert.Empty(t, scheduledPost.ErrorCode)
+	assert.NotNil(t, scheduledPost.FileIds)
+}
+
+func TestScheduledPostToPost(t *testing.T) {
+	scheduledPost := &ScheduledPost{
+		Draft: Draft{
+			UserId:    NewId(),
+			ChannelId: NewId(),
+			RootId:    NewId(),
+			Message:   "message",
+			FileIds:   []string{NewId()},
+			Props:     StringInterface{"key": "value"},
+			Priority: StringInterface{
+				"priority":      PostPriorityUrgent,
+				"requested_ack": true,
+			},
+		},
+	}
+
+	post := scheduledPost.ToPost()
+	assert.Equal(t, scheduledPost.UserId, post.UserId)
+	assert.Equal(t, scheduledPost.ChannelId, post.ChannelId)
+	assert.Equal(t, scheduledPost.RootId, post.RootId)
+	assert.Equal(t, scheduledPost.Message, post.Message)
+	assert.Equal(t, StringArray(scheduledPost.FileIds), post.FileIds)
+	assert.Equal(t, "value", post.GetProp("key"))
+
+	require.NotNil(t, post.GetPriority())
+	assert.Equal(t, PostPriorityUrgent, *post.GetPriority().Priority)
+	assert.True(t, *post.GetPriority().RequestedAck)
+	assert.Nil(t, post.GetPriority().PersistentNotifications)
+}
+
+func TestScheduledPostRestoreNonUpdatableFields(t *testing.T) {
+	original := &ScheduledPost{
+		Draft: Draft{
+			CreateAt:  1,
+			UserId:    NewId(),
+			ChannelId: NewId(),
+			RootId:    NewId(),
+		},
+		Id: NewId(),
+	}
+
+	updated := &ScheduledPost{
+		Draft: Draft{
+			CreateAt:  2,
+			UserId:    NewId(),
+			ChannelId: NewId(),
+			Message:   "updated",
+		},
+		Id:          NewId(),
+		ScheduledAt: 3,
+	}
+	updated.RestoreNonUpdatableFields(original)
+
+	assert.Equal(t, original.Id, updated.Id)
+	assert.Equal(t, original.CreateAt, updated.CreateAt)
+	assert.Equal(t, original.UserId, updated.UserId)
+	assert.Equal(t, original.ChannelId, updated.ChannelId)
+	assert.Equal(t, original.RootId, updated.RootId)
+	assert.Equal(t, "updated", updated.Message)
+	assert.Equal(t, int64(3), updated.ScheduledAt)
+}
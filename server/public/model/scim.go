@@ -0,0 +1,724 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package model
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	ScimSchemaUser                  = "urn:ietf:params:scim:schemas:core:2.0:User"
+	ScimSchemaGroup                 = "urn:ietf:params:scim:schemas:core:2.0:Group"
+	ScimSchemaServiceProviderConfig = "urn:ietf:params:scim:schemas:core:2.0:ServiceProviderConfig"
+	ScimSchemaListResponse          = "urn:ietf:params:scim:api:messages:2.0:ListResponse"
+	ScimSchemaPatchOp               = "urn:ietf:params:scim:api:messages:2.0:PatchOp"
+	ScimSchemaError                 = "urn:ietf:params:scim:api:messages:2.0:Error"
+
+	ScimResourceTypeUser                  = "User"
+	ScimResourceTypeGroup                 = "Group"
+	ScimResourceTypeServiceProviderConfig = "ServiceProviderConfig"
+
+	ScimPatchOpAdd     = "add"
+	ScimPatchOpRemove  = "remove"
+	ScimPatchOpReplace = "replace"
+
+	ScimErrorTypeInvalidFilter = "invalidFilter"
+	ScimErrorTypeInvalidSyntax = "invalidSyntax"
+	ScimErrorTypeInvalidPath   = "invalidPath"
+	ScimErrorTypeInvalidValue  = "invalidValue"
+	ScimErrorTypeUniqueness    = "uniqueness"
+
+	// ScimGroupRemoteIDPrefix marks the RemoteId of custom groups provisioned through SCIM.
+	ScimGroupRemoteIDPrefix = "scim:"
+
+	ScimDefaultCount = 100
+	ScimMaxCount     = 200
+)
+
+type ScimMeta struct {
+	ResourceType string `json:"resourceType"`
+	Created      string `json:"created,omitempty"`
+	LastModified string `json:"lastModified,omitempty"`
+	Location     string `json:"location,omitempty"`
+}
+
+type ScimName struct {
+	Formatted  string `json:"formatted,omitempty"`
+	GivenName  string `json:"givenName,omitempty"`
+	FamilyName string `json:"familyName,omitempty"`
+}
+
+type ScimEmail struct {
+	Value   string `json:"value"`
+	Type    string `json:"type,omitempty"`
+	Primary bool   `json:"primary,omitempty"`
+}
+
+type ScimUser struct {
+	Schemas     []string    `json:"schemas"`
+	Id          string      `json:"id,omitempty"`
+	ExternalId  string      `json:"externalId,omitempty"`
+	UserName    string      `json:"userName"`
+	Name        *ScimName   `json:"name,omitempty"`
+	DisplayName string      `json:"displayName,omitempty"`
+	NickName    string      `json:"nickName,omitempty"`
+	Title       string      `json:"title,omitempty"`
+	Emails      []ScimEmail `json:"emails,omitempty"`
+	Active      *bool       `json:"active,omitempty"`
+	Meta        *ScimMeta   `json:"meta,omitempty"`
+}
+
+type ScimMember struct {
+	Value   string `json:"value"`
+	Display string `json:"display,omitempty"`
+	Ref     string `json:"$ref,omitempty"`
+}
+
+type ScimGroup struct {
+	Schemas     []string     `json:"schemas"`
+	Id          string       `json:"id,omitempty"`
+	ExternalId  string       `json:"externalId,omitempty"`
+	DisplayName string       `json:"displayName"`
+	Members     []ScimMember `json:"members,omitempty"`
+	Meta        *ScimMeta    `json:"meta,omitempty"`
+}
+
+type ScimListResponse struct {
+	Schemas      []string `json:"schemas"`
+	TotalResults int64    `json:"totalResults"`
+	StartIndex   int      `json:"startIndex"`
+	ItemsPerPage int      `json:"itemsPerPage"`
+	Resources    any      `json:"Resources"`
+}
+
+type ScimPatchOperation struct {
+	Op    string          `json:"op"`
+	Path  string          `json:"path,omitempty"`
+	Value json.RawMessage `json:"value,omitempty"`
+}
+
+type ScimPatchRequest struct {
+	Schemas    []string              `json:"schemas"`
+	Operations []*ScimPatchOperation `json:"Operations"`
+}
+
+// ScimGroupOperation is a single change to a group resulting from a SCIM PATCH request.
+type ScimGroupOperation struct {
+	Op string
+	// Attribute is one of "displayname", "externalid" or "members".
+	Attribute string
+	Value     string
+	// MemberIDs are the user ids the operation applies to. A "remove" operation on members
+	// without any ids removes every member of the group.
+	MemberIDs []string
+}
+
+type ScimError struct {
+	Schemas    []string `json:"schemas"`
+	ScimType   string   `json:"scimType,omitempty"`
+	Detail     string   `json:"detail,omitempty"`
+	Status     string   `json:"status"`
+	StatusCode int      `json:"-"`
+}
+
+type ScimSupported struct {
+	Supported bool `json:"supported"`
+}
+
+type ScimBulkSupport struct {
+	Supported      bool `json:"supported"`
+	MaxOperations  int  `json:"maxOperations"`
+	MaxPayloadSize int  `json:"maxPayloadSize"`
+}
+
+type ScimFilterSupport struct {
+	Supported  bool `json:"supported"`
+	MaxResults int  `json:"maxResults"`
+}
+
+type ScimAuthenticationScheme struct {
+	Type        string `json:"type"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+type ScimServiceProviderConfig struct {
+	Schemas               []string                   `json:"schemas"`
+	Patch                 ScimSupported              `json:"patch"`
+	Bulk                  ScimBulkSupport            `json:"bulk"`
+	Filter                ScimFilterSupport          `json:"filter"`
+	ChangePassword        ScimSupported              `json:"changePassword"`
+	Sort                  ScimSupported              `json:"sort"`
+	ETag                  ScimSupported              `json:"etag"`
+	AuthenticationSchemes []ScimAuthenticationScheme `json:"authenticationSchemes"`
+	Meta                  *ScimMeta                  `json:"meta,omitempty"`
+}
+
+// ScimFilterClause is a single "eq" comparison of a SCIM filter. The attribute is lower-cased
+// since SCIM attribute names are case insensitive.
+type ScimFilterClause struct {
+	Attribute string
+	Value     string
+}
+
+// ScimFilter is a SCIM filter made of comparisons that must all match.
+type ScimFilter []*ScimFilterClause
+
+func NewScimError(statusCode int, scimType, detail string) *ScimError {
+	return &ScimError{
+		Schemas:    []string{ScimSchemaError},
+		ScimType:   scimType,
+		Detail:     detail,
+		Status:     strconv.Itoa(statusCode),
+		StatusCode: statusCode,
+	}
+}
+
+func (e *ScimError) Error() string {
+	if e.ScimType == "" {
+		return e.Detail
+	}
+	return e.ScimType + ": " + e.Detail
+}
+
+func NewScimListResponse(resources any, totalResults int64, startIndex, itemsPerPage int) *ScimListResponse {
+	return &ScimListResponse{
+		Schemas:      []string{ScimSchemaListResponse},
+		TotalResults: totalResults,
+		StartIndex:   startIndex,
+		ItemsPerPage: itemsPerPage,
+		Resources:    resources,
+	}
+}
+
+func NewScimServiceProviderConfig(baseURL string) *ScimServiceProviderConfig {
+	return &ScimServiceProviderConfig{
+		Schemas: []string{ScimSchemaServiceProviderConfig},
+		Patch:   ScimSupported{Supported: true},
+		Filter:  ScimFilterSupport{Supported: true, MaxResults: ScimMaxCount},
+		AuthenticationSchemes: []ScimAuthenticationScheme{
+			{
+				Type:        "oauthbearertoken",
+				Name:        "OAuth Bearer Token",
+				Description: "Authentication with the bearer token configured in ScimSettings.Token",
+			},
+		},
+		Meta: &ScimMeta{
+			ResourceType: ScimResourceTypeServiceProviderConfig,
+			Location:     baseURL + "/ServiceProviderConfig",
+		},
+	}
+}
+
+func scimTime(millis int64) string {
+	return time.UnixMilli(millis).UTC().Format(time.RFC3339)
+}
+
+// ScimUsername maps a SCIM userName onto a Mattermost username. Identity providers commonly
+// use email addresses as user names, in which case only the local part is kept.
+func ScimUsername(userName string) string {
+	username := strings.ToLower(strings.TrimSpace(userName))
+	if !IsValidUsername(username) {
+		if local, _, found := strings.Cut(username, "@"); found {
+			return local
+		}
+	}
+	return username
+}
+
+func ScimUserFromUser(user *User, baseURL string) *ScimUser {
+	su := &ScimUser{
+		Schemas:     []string{ScimSchemaUser},
+		Id:          user.Id,
+		ExternalId:  SafeDereference(user.AuthData),
+		UserName:    user.Username,
+		DisplayName: user.GetFullName(),
+		NickName:    user.Nickname,
+		Title:       user.Position,
+		Active:      NewPointer(user.DeleteAt == 0),
+		Meta: &ScimMeta{
+			ResourceType: ScimResourceTypeUser,
+			Created:      scimTime(user.CreateAt),
+			LastModified: scimTime(user.UpdateAt),
+			Location:     baseURL + "/Users/" + user.Id,
+		},
+	}
+
+	if su.DisplayName == "" {
+		su.DisplayName = user.Username
+	}
+
+	if user.FirstName != "" || user.LastName != "" {
+		su.Name = &ScimName{
+			Formatted:  user.GetFullName(),
+			GivenName:  user.FirstName,
+			FamilyName: user.LastName,
+		}
+	}
+
+	if user.Email != "" {
+		su.Emails = []ScimEmail{{Value: user.Email, Type: "work", Primary: true}}
+	}
+
+	return su
+}
+
+func (su *ScimUser) IsValid() *ScimError {
+	if ScimUsername(su.UserName) == "" {
+		return NewScimError(http.StatusBadRequest, ScimErrorTypeInvalidValue, "userName is required")
+	}
+
+	if su.PrimaryEmail() == "" {
+		return NewScimError(http.StatusBadRequest, ScimErrorTypeInvalidValue, "an email address is required")
+	}
+
+	return nil
+}
+
+func (su *ScimUser) primaryEmailIndex() int {
+	for i, email := range su.Emails {
+		if email.Primary {
+			return i
+		}
+	}
+	return 0
+}
+
+// PrimaryEmail returns the email marked as primary, or the first one if none is.
+func (su *ScimUser) PrimaryEmail() string {
+	if len(su.Emails) == 0 {
+		return ""
+	}
+	return su.Emails[su.primaryEmailIndex()].Value
+}
+
+// ApplyTo copies the attributes of the SCIM user onto the given user. The externalId and
+// active attributes are left to the caller since changing them has side effects.
+func (su *ScimUser) ApplyTo(user *User) {
+	user.Username = ScimUsername(su.UserName)
+	user.Nickname = su.NickName
+	user.Position = su.Title
+
+	user.FirstName = ""
+	user.LastName = ""
+	if su.Name != nil {
+		user.FirstName = su.Name.GivenName
+		user.LastName = su.Name.FamilyName
+	}
+
+	if email := su.PrimaryEmail(); email != "" {
+		user.Email = strings.ToLower(email)
+	}
+}
+
+// ApplyPatch applies the operations of a SCIM PATCH request to the user. Attributes that
+// have no Mattermost counterpart are ignored.
+func (su *ScimUser) ApplyPatch(patch *ScimPatchRequest) *ScimError {
+	return patch.forEachOperation(su.applyOperation)
+}
+
+func (su *ScimUser) applyOperation(op, path string, value json.RawMessage) *ScimError {
+	attribute := strings.TrimPrefix(strings.ToLower(path), strings.ToLower(ScimSchemaUser)+":")
+	remove := op == ScimPatchOpRemove
+
+	var stringValue string
+	if !remove && attribute != "active" && attribute != "name" && attribute != "emails" {
+		var err *ScimError
+		if stringValue, err = scimString(value); err != nil {
+			return err
+		}
+	}
+
+	switch {
+	case attribute == "active":
+		if remove {
+			return nil
+		}
+		active, err := scimBool(value)
+		if err != nil {
+			return err
+		}
+		su.Active = &active
+	case attribute == "username":
+		su.UserName = stringValue
+	case attribute == "externalid":
+		su.ExternalId = stringValue
+	case attribute == "displayname":
+		su.DisplayName = stringValue
+	case attribute == "nickname":
+		su.NickName = stringValue
+	case attribute == "title":
+		su.Title = stringValue
+	case attribute == "name":
+		su.Name = nil
+		if !remove {
+			if err := json.Unmarshal(value, &su.Name); err != nil {
+				return NewScimError(http.StatusBadRequest, ScimErrorTypeInvalidValue, "invalid value for name")
+			}
+		}
+	case strings.HasPrefix(attribute, "name."):
+		if su.Name == nil {
+			su.Name = &ScimName{}
+		}
+		switch strings.TrimPrefix(attribute, "name.") {
+		case "givenname":
+			su.Name.GivenName = stringValue
+		case "familyname":
+			su.Name.FamilyName = stringValue
+		case "formatted":
+			su.Name.Formatted = stringValue
+		}
+	case attribute == "emails":
+		if remove {
+			su.Emails = nil
+			return nil
+		}
+		var emails []ScimEmail
+		if err := json.Unmarshal(value, &emails); err != nil {
+			return NewScimError(http.StatusBadRequest, ScimErrorTypeInvalidValue, "invalid value for emails")
+		}
+		if op == ScimPatchOpAdd {
+			su.Emails = append(su.Emails, emails...)
+		} else {
+			su.Emails = emails
+		}
+	case strings.HasPrefix(attribute, "emails["):
+		// Paths such as emails[type eq "work"].value address the one email Mattermost keeps.
+		if len(su.Emails) == 0 {
+			su.Emails = []ScimEmail{{Type: "work", Primary: true}}
+		}
+		su.Emails[su.primaryEmailIndex()].Value = stringValue
+	}
+
+	return nil
+}
+
+// ScimGroupRemoteID returns the RemoteId of a group provisioned through SCIM. Groups created
+// without an externalId get a generated one so that the RemoteId stays unique.
+func ScimGroupRemoteID(externalID string) string {
+	if externalID == "" {
+		externalID = NewId()
+	}
+	return ScimGroupRemoteIDPrefix + externalID
+}
+
+func ScimGroupFromGroup(group *Group, members []*User, baseURL string) *ScimGroup {
+	sg := &ScimGroup{
+		Schemas:     []string{ScimSchemaGroup},
+		Id:          group.Id,
+		ExternalId:  strings.TrimPrefix(group.GetRemoteId(), ScimGroupRemoteIDPrefix),
+		DisplayName: group.DisplayName,
+		Meta: &ScimMeta{
+			ResourceType: ScimResourceTypeGroup,
+			Created:      scimTime(group.CreateAt),
+			LastModified: scimTime(group.UpdateAt),
+			Location:     baseURL + "/Groups/" + group.Id,
+		},
+	}
+
+	for _, member := range members {
+		sg.Members = append(sg.Members, ScimMember{
+			Value:   member.Id,
+			Display: member.Username,
+			Ref:     baseURL + "/Users/" + member.Id,
+		})
+	}
+
+	return sg
+}
+
+func (sg *ScimGroup) IsValid() *ScimError {
+	if sg.DisplayName == "" {
+		return NewScimError(http.StatusBadRequest, ScimErrorTypeInvalidValue, "displayName is required")
+	}
+
+	if len(ScimGroupRemoteID(sg.ExternalId)) > GroupRemoteIDMaxLength {
+		return NewScimError(http.StatusBadRequest, ScimErrorTypeInvalidValue, "externalId is too long")
+	}
+
+	return nil
+}
+
+// MemberIDs returns the user ids of the group members.
+func (sg *ScimGroup) MemberIDs() []string {
+	ids := make([]string, 0, len(sg.Members))
+	for _, member := range sg.Members {
+		ids = append(ids, member.Value)
+	}
+	return ids
+}
+
+// GroupOperations translates the operations of a SCIM PATCH request into changes to a group.
+// Attributes that have no Mattermost counterpart are ignored.
+func (r *ScimPatchRequest) GroupOperations() ([]*ScimGroupOperation, *ScimError) {
+	var operations []*ScimGroupOperation
+	err := r.forEachOperation(func(op, path string, value json.RawMessage) *ScimError {
+		operation, err := parseScimGroupOperation(op, path, value)
+		if err != nil {
+			return err
+		}
+		if operation != nil {
+			operations = append(operations, operation)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return operations, nil
+}
+
+func parseScimGroupOperation(op, path string, value json.RawMessage) (*ScimGroupOperation, *ScimError) {
+	attribute := strings.TrimPrefix(strings.ToLower(path), strings.ToLower(ScimSchemaGroup)+":")
+	operation := &ScimGroupOperation{Op: op, Attribute: attribute}
+	remove := op == ScimPatchOpRemove
+
+	switch {
+	case attribute == "displayname" || attribute == "externalid":
+		if remove {
+			return operation, nil
+		}
+		stringValue, err := scimString(value)
+		if err != nil {
+			return nil, err
+		}
+		operation.Value = stringValue
+	case attribute == "members":
+		if remove && len(value) == 0 {
+			return operation, nil
+		}
+		var members []ScimMember
+		if err := json.Unmarshal(value, &members); err != nil {
+			return nil, NewScimError(http.StatusBadRequest, ScimErrorTypeInvalidValue, "invalid value for members")
+		}
+		operation.MemberIDs = (&ScimGroup{Members: members}).MemberIDs()
+	case strings.HasPrefix(attribute, "members["):
+		// The only supported form is members[value eq "<user id>"], used to remove a single member.
+		expression, _, found := strings.Cut(path[len("members["):], "]")
+		if !found || !remove {
+			return nil, NewScimError(http.StatusBadRequest, ScimErrorTypeInvalidPath, fmt.Sprintf("unsupported path %q", path))
+		}
+		filter, err := ParseScimFilter(expression)
+		if err != nil {
+			return nil, NewScimError(http.StatusBadRequest, ScimErrorTypeInvalidPath, err.Detail)
+		}
+		if len(filter) != 1 || filter[0].Attribute != "value" {
+			return nil, NewScimError(http.StatusBadRequest, ScimErrorTypeInvalidPath, fmt.Sprintf("unsupported path %q", path))
+		}
+		operation.Attribute = "members"
+		operation.MemberIDs = []string{filter[0].Value}
+	default:
+		return nil, nil
+	}
+
+	return operation, nil
+}
+
+// forEachOperation calls fn with the lower-cased operation, the attribute path and the value of
+// every operation of the request. Operations without a path carry an object whose keys are paths.
+func (r *ScimPatchRequest) forEachOperation(fn func(op, path string, value json.RawMessage) *ScimError) *ScimError {
+	for _, operation := range r.Operations {
+		op := strings.ToLower(operation.Op)
+		if op != ScimPatchOpAdd && op != ScimPatchOpRemove && op != ScimPatchOpReplace {
+			return NewScimError(http.StatusBadRequest, ScimErrorTypeInvalidSyntax, fmt.Sprintf("unsupported operation %q", operation.Op))
+		}
+
+		if operation.Path != "" {
+			if err := fn(op, operation.Path, operation.Value); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if op == ScimPatchOpRemove {
+			return NewScimError(http.StatusBadRequest, ScimErrorTypeInvalidPath, "remove operations require a path")
+		}
+
+		var values map[string]json.RawMessage
+		if err := json.Unmarshal(operation.Value, &values); err != nil {
+			return NewScimError(http.StatusBadRequest, ScimErrorTypeInvalidValue, "operations without a path require an object value")
+		}
+
+		paths := make([]string, 0, len(values))
+		for path := range values {
+			paths = append(paths, path)
+		}
+		sort.Strings(paths)
+
+		for _, path := range paths {
+			if err := fn(op, path, values[path]); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func scimString(value json.RawMessage) (string, *ScimError) {
+	var s string
+	if err := json.Unmarshal(value, &s); err != nil {
+		return "", NewScimError(http.StatusBadRequest, ScimErrorTypeInvalidValue, fmt.Sprintf("expected a string, got %s", value))
+	}
+	return s, nil
+}
+
+// scimBool parses a boolean value, which some identity providers send as a string.
+func scimBool(value json.RawMessage) (bool, *ScimError) {
+	var b bool
+	if err := json.Unmarshal(value, &b); err == nil {
+		return b, nil
+	}
+
+	var s string
+	if err := json.Unmarshal(value, &s); err == nil {
+		if b, err := strconv.ParseBool(strings.ToLower(s)); err == nil {
+			return b, nil
+		}
+	}
+
+	return false, NewScimError(http.StatusBadRequest, ScimErrorTypeInvalidValue, fmt.Sprintf("expected a boolean, got %s", value))
+}
+
+// ParseScimFilter parses a SCIM filter made of "eq" comparisons joined by "and", such as
+// `userName eq "alice" and active eq true`. Other operators aren't supported.
+func ParseScimFilter(filter string) (ScimFilter, *ScimError) {
+	tokens, err := tokenizeScimFilter(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	// Every comparison is made of three tokens, and comparisons are separated by a fourth one.
+	if len(tokens)%4 != 3 {
+		return nil, NewScimError(http.StatusBadRequest, ScimErrorTypeInvalidFilter, fmt.Sprintf("incomplete filter %q", filter))
+	}
+
+	var result ScimFilter
+	for i := 0; i < len(tokens); i += 4 {
+		if !strings.EqualFold(tokens[i+1], "eq") {
+			return nil, NewScimError(http.StatusBadRequest, ScimErrorTypeInvalidFilter, fmt.Sprintf("unsupported operator %q", tokens[i+1]))
+		}
+
+		value := tokens[i+2]
+		if strings.HasPrefix(value, `"`) {
+			if err := json.Unmarshal([]byte(value), &value); err != nil {
+				return nil, NewScimError(http.StatusBadRequest, ScimErrorTypeInvalidFilter, fmt.Sprintf("invalid value %s", tokens[i+2]))
+			}
+		} else {
+			value = strings.ToLower(value)
+		}
+
+		result = append(result, &ScimFilterClause{
+			Attribute: strings.ToLower(tokens[i]),
+			Value:     value,
+		})
+
+		if i+3 < len(tokens) && !strings.EqualFold(tokens[i+3], "and") {
+			return nil, NewScimError(http.StatusBadRequest, ScimErrorTypeInvalidFilter, fmt.Sprintf("unsupported logical operator %q", tokens[i+3]))
+		}
+	}
+
+	return result, nil
+}
+
+func tokenizeScimFilter(filter string) ([]string, *ScimError) {
+	var tokens []string
+	for i := 0; i < len(filter); {
+		switch filter[i] {
+		case ' ', '\t':
+			i++
+		case '"':
+			end := i + 1
+			for ; end < len(filter) && filter[end] != '"'; end++ {
+				if filter[end] == '\\' {
+					end++
+				}
+			}
+			if end >= len(filter) {
+				return nil, NewScimError(http.StatusBadRequest, ScimErrorTypeInvalidFilter, fmt.Sprintf("unterminated string in filter %q", filter))
+			}
+			tokens = append(tokens, filter[i:end+1])
+			i = end + 1
+		default:
+			end := strings.IndexAny(filter[i:], " \t\"")
+			if end == -1 {
+				end = len(filter)
+			} else {
+				end += i
+			}
+			tokens = append(tokens, filter[i:end])
+			i = end
+		}
+	}
+	return tokens, nil
+}
+
+// Get returns the value the filter compares the given attribute with, if any.
+func (f ScimFilter) Get(attribute string) (string, bool) {
+	for _, clause := range f {
+		if clause.Attribute == attribute {
+			return clause.Value, true
+		}
+	}
+	return "", false
+}
+
+// MatchesUser returns true if the user satisfies every comparison of the filter.
+func (f ScimFilter) MatchesUser(su *ScimUser) bool {
+	for _, clause := range f {
+		var matches bool
+		switch clause.Attribute {
+		case "id":
+			matches = clause.Value == su.Id
+		case "externalid":
+			matches = clause.Value == su.ExternalId
+		case "username":
+			matches = ScimUsername(clause.Value) == su.UserName
+		case "displayname":
+			matches = strings.EqualFold(clause.Value, su.DisplayName)
+		case "active":
+			matches = su.Active != nil && clause.Value == strconv.FormatBool(*su.Active)
+		case "emails", "emails.value":
+			for _, email := range su.Emails {
+				matches = matches || strings.EqualFold(clause.Value, email.Value)
+			}
+		}
+
+		if !matches {
+			return false
+		}
+	}
+	return true
+}
+
+// MatchesGroup returns true if the group satisfies every comparison of the filter.
+func (f ScimFilter) MatchesGroup(sg *ScimGroup) bool {
+	for _, clause := range f {
+		var matches bool
+		switch clause.Attribute {
+		case "id":
+			matches = clause.Value == sg.Id
+		case "externalid":
+			matches = clause.Value == sg.ExternalId
+		case "displayname":
+			matches = strings.EqualFold(clause.Value, sg.DisplayName)
+		case "members", "members.value":
+			for _, member := range sg.Members {
+				matches = matches || clause.Value == member.Value
+			}
+		}
+
+		if !matches {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,261 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package model
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScimUsername(t *testing.T) {
+	assert.Equal(t, "alice", ScimUsername("Alice"))
+	assert.Equal(t, "alice.smith", ScimUsername(" alice.smith "))
+	assert.Equal(t, "alice", ScimUsername("Alice@example.com"))
+}
+
+func TestScimUserFromUser(t *testing.T) {
+	user := &User{
+		Id:        NewId(),
+		Username:  "alice",
+		Email:     "alice@example.com",
+		FirstName: "Alice",
+		LastName:  "Smith",
+		Position:  "Engineer",
+		AuthData:  NewPointer("ext-1"),
+		CreateAt:  1000,
+		UpdateAt:  2000,
+		DeleteAt:  3000,
+	}
+
+	su := ScimUserFromUser(user, "http://localhost/scim/v2")
+	assert.Equal(t, []string{ScimSchemaUser}, su.Schemas)
+	assert.Equal(t, user.Id, su.Id)
+	assert.Equal(t, "ext-1", su.ExternalId)
+	assert.Equal(t, "alice", su.UserName)
+	assert.Equal(t, "Alice Smith", su.DisplayName)
+	assert.Equal(t, "Engineer", su.Title)
+	assert.Equal(t, "alice@example.com", su.PrimaryEmail())
+	require.NotNil(t, su.Active)
+	assert.False(t, *su.Active)
+	require.NotNil(t, su.Name)
+	assert.Equal(t, "Alice", su.Name.GivenName)
+	assert.Equal(t, "Smith", su.Name.FamilyName)
+	assert.Equal(t, "http://localhost/scim/v2/Users/"+user.Id, su.Meta.Location)
+
+	updated := &User{}
+	su.ApplyTo(updated)
+	assert.Equal(t, user.Username, updated.Username)
+	assert.Equal(t, user.Email, updated.Email)
+	assert.Equal(t, user.FirstName, updated.FirstName)
+	assert.Equal(t, user.LastName, updated.LastName)
+	assert.Equal(t, user.Position, updated.Position)
+}
+
+func TestScimUserIsValid(t *testing.T) {
+	su := &ScimUser{UserName: "alice", Emails: []ScimEmail{{Value: "alice@example.com"}}}
+	assert.Nil(t, su.IsValid())
+
+	su.UserName = ""
+	require.NotNil(t, su.IsValid())
+	assert.Equal(t, ScimErrorTypeInvalidValue, su.IsValid().ScimType)
+
+	su.UserName = "alice"
+	su.Emails = nil
+	require.NotNil(t, su.IsValid())
+}
+
+func TestScimUserApplyPatch(t *testing.T) {
+	newUser := func() *ScimUser {
+		return &ScimUser{
+			UserName: "alice",
+			Name:     &ScimName{GivenName: "Alice", FamilyName: "Smith"},
+			Emails: []ScimEmail{
+				{Value: "alice@home.example.com", Type: "home"},
+				{Value: "alice@example.com", Type: "work", Primary: true},
+			},
+			Active: NewPointer(true),
+		}
+	}
+
+	parse := func(t *testing.T, data string) *ScimPatchRequest {
+		t.Helper()
+		var patch ScimPatchRequest
+		require.NoError(t, json.Unmarshal([]byte(data), &patch))
+		return &patch
+	}
+
+	t.Run("replace with path", func(t *testing.T) {
+		su := newUser()
+		err := su.ApplyPatch(parse(t, `{"Operations":[
+			{"op":"Replace","path":"name.givenName","value":"Alicia"},
+			{"op":"replace","path":"emails[type eq \"work\"].value","value":"alicia@example.com"},
+			{"op":"replace","path":"active","value":"False"}
+		]}`))
+		require.Nil(t, err)
+		assert.Equal(t, "Alicia", su.Name.GivenName)
+		assert.Equal(t, "Smith", su.Name.FamilyName)
+		assert.Equal(t, "alicia@example.com", su.PrimaryEmail())
+		assert.False(t, *su.Active)
+	})
+
+	t.Run("replace without path", func(t *testing.T) {
+		su := newUser()
+		err := su.ApplyPatch(parse(t, `{"Operations":[
+			{"op":"replace","value":{"active":false,"userName":"alicia","urn:ietf:params:scim:schemas:core:2.0:User:title":"Manager"}}
+		]}`))
+		require.Nil(t, err)
+		assert.False(t, *su.Active)
+		assert.Equal(t, "alicia", su.UserName)
+		assert.Equal(t, "Manager", su.Title)
+	})
+
+	t.Run("remove", func(t *testing.T) {
+		su := newUser()
+		err := su.ApplyPatch(parse(t, `{"Operations":[{"op":"remove","path":"name.familyName"}]}`))
+		require.Nil(t, err)
+		assert.Equal(t, "", su.Name.FamilyName)
+	})
+
+	t.Run("unknown attributes are ignored", func(t *testing.T) {
+		su := newUser()
+		err := su.ApplyPatch(parse(t, `{"Operations":[{"op":"add","path":"urn:ietf:params:scim:schemas:extension:enterprise:2.0:User:department","value":"R&D"}]}`))
+		require.Nil(t, err)
+		assert.Equal(t, newUser(), su)
+	})
+
+	t.Run("invalid operation", func(t *testing.T) {
+		err := newUser().ApplyPatch(parse(t, `{"Operations":[{"op":"move","path":"title","value":"x"}]}`))
+		require.NotNil(t, err)
+		assert.Equal(t, ScimErrorTypeInvalidSyntax, err.ScimType)
+		assert.Equal(t, http.StatusBadRequest, err.StatusCode)
+	})
+
+	t.Run("invalid value", func(t *testing.T) {
+		err := newUser().ApplyPatch(parse(t, `{"Operations":[{"op":"replace","path":"active","value":"maybe"}]}`))
+		require.NotNil(t, err)
+		assert.Equal(t, ScimErrorTypeInvalidValue, err.ScimType)
+	})
+}
+
+func TestScimPatchRequestGroupOperations(t *testing.T) {
+	userID1 := NewId()
+	userID2 := NewId()
+
+	var patch ScimPatchRequest
+	require.NoError(t, json.Unmarshal([]byte(`{"Operations":[
+		{"op":"add","path":"members","value":[{"value":"`+userID1+`"},{"value":"`+userID2+`"}]},
+		{"op":"remove","path":"members[value eq \"`+userID1+`\"]"},
+		{"op":"replace","value":{"displayName":"Engineering"}},
+		{"op":"remove","path":"members"},
+		{"op":"add","path":"urn:ietf:params:scim:schemas:extension:custom:2.0:Group:color","value":"blue"}
+	]}`), &patch))
+
+	operations, err := patch.GroupOperations()
+	require.Nil(t, err)
+	require.Len(t, operations, 4)
+
+	assert.Equal(t, &ScimGroupOperation{Op: ScimPatchOpAdd, Attribute: "members", MemberIDs: []string{userID1, userID2}}, operations[0])
+	assert.Equal(t, &ScimGroupOperation{Op: ScimPatchOpRemove, Attribute: "members", MemberIDs: []string{userID1}}, operations[1])
+	assert.Equal(t, &ScimGroupOperation{Op: ScimPatchOpReplace, Attribute: "displayname", Value: "Engineering"}, operations[2])
+	assert.Equal(t, &ScimGroupOperation{Op: ScimPatchOpRemove, Attribute: "members"}, operations[3])
+
+	patch = ScimPatchRequest{Operations: []*ScimPatchOperation{{Op: "add", Path: `members[value eq "` + userID1 + `"]`}}}
+	_, err = patch.GroupOperations()
+	require.NotNil(t, err)
+	assert.Equal(t, ScimErrorTypeInvalidPath, err.ScimType)
+}
+
+func TestScimGroup(t *testing.T) {
+	group := &Group{
+		Id:          NewId(),
+		DisplayName: "Engineering",
+		Source:      GroupSourceCustom,
+		RemoteId:    NewPointer(ScimGroupRemoteID("ext-1")),
+	}
+	assert.True(t, group.IsScimManaged())
+
+	member := &User{Id: NewId(), Username: "alice"}
+	sg := ScimGroupFromGroup(group, []*User{member}, "http://localhost/scim/v2")
+	assert.Equal(t, "ext-1", sg.ExternalId)
+	assert.Equal(t, []string{member.Id}, sg.MemberIDs())
+	assert.Equal(t, "alice", sg.Members[0].Display)
+	assert.Nil(t, sg.IsValid())
+
+	sg.DisplayName = ""
+	assert.NotNil(t, sg.IsValid())
+
+	assert.False(t, (&Group{Source: GroupSourceCustom}).IsScimManaged())
+	assert.False(t, (&Group{Source: GroupSourceLdap, RemoteId: NewPointer("scim:ext-1")}).IsScimManaged())
+	assert.Len(t, ScimGroupRemoteID(""), len(ScimGroupRemoteIDPrefix)+26)
+}
+
+func TestParseScimFilter(t *testing.T) {
+	t.Run("valid filters", func(t *testing.T) {
+		filter, err := ParseScimFilter(`userName eq "alice@example.com"`)
+		require.Nil(t, err)
+		assert.Equal(t, ScimFilter{{Attribute: "username", Value: "alice@example.com"}}, filter)
+
+		filter, err = ParseScimFilter(`externalId EQ "a \"quoted\" id" and active eq TRUE`)
+		require.Nil(t, err)
+		assert.Equal(t, ScimFilter{
+			{Attribute: "externalid", Value: `a "quoted" id`},
+			{Attribute: "active", Value: "true"},
+		}, filter)
+
+		value, ok := filter.Get("externalid")
+		assert.True(t, ok)
+		assert.Equal(t, `a "quoted" id`, value)
+		_, ok = filter.Get("username")
+		assert.False(t, ok)
+	})
+
+	t.Run("invalid filters", func(t *testing.T) {
+		for _, filter := range []string{
+			``,
+			`userName`,
+			`userName eq`,
+			`userName co "alice"`,
+			`userName eq "alice" or userName eq "bob"`,
+			`userName eq "alice" and`,
+			`userName eq "alice`,
+		} {
+			_, err := ParseScimFilter(filter)
+			require.NotNil(t, err, filter)
+			assert.Equal(t, ScimErrorTypeInvalidFilter, err.ScimType, filter)
+		}
+	})
+
+	t.Run("matching", func(t *testing.T) {
+		su := &ScimUser{
+			Id:         NewId(),
+			UserName:   "alice",
+			ExternalId: "ext-1",
+			Emails:     []ScimEmail{{Value: "Alice@Example.com"}},
+			Active:     NewPointer(true),
+		}
+
+		for filter, expected := range map[string]bool{
+			`userName eq "Alice@example.com"`:             true,
+			`emails eq "alice@example.com"`:               true,
+			`emails.value eq "bob@example.com"`:           false,
+			`externalId eq "ext-1" and active eq true`:    true,
+			`externalId eq "EXT-1"`:                       false,
+			`id eq "` + su.Id + `" and userName eq "bob"`: false,
+			`unsupported eq "value"`:                      false,
+		} {
+			parsed, err := ParseScimFilter(filter)
+			require.Nil(t, err, filter)
+			assert.Equal(t, expected, parsed.MatchesUser(su), filter)
+		}
+
+		sg := &ScimGroup{Id: NewId(), DisplayName: "Engineering", Members: []ScimMember{{Value: su.Id}}}
+		parsed, err := ParseScimFilter(`displayName eq "engineering" and members eq "` + su.Id + `"`)
+		require.Nil(t, err)
+		assert.True(t, parsed.MatchesGroup(sg))
+	})
+}
@@ -6,8 +6,10 @@ package model
 import (
 	"net/http"
 	"regexp"
+	"slices"
 	"strings"
 	"time"
+	"unicode"
 )
 
 var searchTermPuncStart = regexp.MustCompile(`^[^\pL\d\s#"]+`)
@@ -35,6 +37,8 @@ type SearchParams struct {
 	// True if this search doesn't originate from a "current user".
 	SearchWithoutUserId bool   `json:"search_without_user_id,omitempty"`
 	Modifier            string `json:"modifier"`
+	// True if the posts are ranked by their meaning rather than by the words they contain.
+	Semantic bool `json:"semantic,omitempty"`
 }
 
 // Returns the epoch timestamp of the start of the day specified by SearchParams.AfterDate
@@ -109,7 +113,7 @@ func (p *SearchParams) GetExcludedDateMillis() (int64, int64) {
 	return GetStartOfDayMillis(date, p.TimeZoneOffset), GetEndOfDayMillis(date, p.TimeZoneOffset)
 }
 
-var searchFlags = [...]string{"from", "channel", "in", "before", "after", "on", "ext"}
+var searchFlags = [...]string{"from", "channel", "in", "before", "after", "on", "ext", "semantic"}
 
 type flag struct {
 	name    string
@@ -270,6 +274,7 @@ func ParseSearchParams(text string, timeZoneOffset int) []*SearchParams {
 	excludedDate := ""
 	excludedExtensions := []string{}
 	extensions := []string{}
+	semantic := false
 
 	for _, flag := range flags {
 		if flag.name == "in" || flag.name == "channel" {
@@ -308,6 +313,8 @@ func ParseSearchParams(text string, timeZoneOffset int) []*SearchParams {
 			} else {
 				extensions = append(extensions, flag.value)
 			}
+		} else if flag.name == "semantic" {
+			semantic = !flag.exclude && strings.EqualFold(flag.value, "true")
 		}
 	}
 
@@ -331,6 +338,7 @@ func ParseSearchParams(text string, timeZoneOffset int) []*SearchParams {
 			OnDate:             onDate,
 			ExcludedDate:       excludedDate,
 			TimeZoneOffset:     timeZoneOffset,
+			Semantic:           semantic,
 		})
 	}
 
@@ -352,6 +360,7 @@ func ParseSearchParams(text string, timeZoneOffset int) []*SearchParams {
 			OnDate:             onDate,
 			ExcludedDate:       excludedDate,
 			TimeZoneOffset:     timeZoneOffset,
+			Semantic:           semantic,
 		})
 	}
 
@@ -381,6 +390,7 @@ func ParseSearchParams(text string, timeZoneOffset int) []*SearchParams {
 			OnDate:             onDate,
 			ExcludedDate:       excludedDate,
 			TimeZoneOffset:     timeZoneOffset,
+			Semantic:           semantic,
 		})
 	}
 
@@ -396,3 +406,166 @@ func IsSearchParamsListValid(paramsList []*SearchParams) *AppError {
 	}
 	return nil
 }
+
+// MatchesPost reports whether the post would be found by a search with these params. The channels
+// and users of the params must already be converted to IDs, as they are before running a search.
+func (p *SearchParams) MatchesPost(post *Post) bool {
+	if post.DeleteAt != 0 || post.IsSystemMessage() {
+		return false
+	}
+
+	if len(p.InChannels) > 0 && !slices.Contains(p.InChannels, post.ChannelId) {
+		return false
+	}
+	if slices.Contains(p.ExcludedChannels, post.ChannelId) {
+		return false
+	}
+	if len(p.FromUsers) > 0 && !slices.Contains(p.FromUsers, post.UserId) {
+		return false
+	}
+	if slices.Contains(p.ExcludedUsers, post.UserId) {
+		return false
+	}
+
+	if !p.matchesCreateAt(post.CreateAt) {
+		return false
+	}
+
+	if p.IsHashtag {
+		return p.matchesHashtags(post.Hashtags)
+	}
+	return p.matchesMessage(post.Message)
+}
+
+// matchesCreateAt applies the date filters the same way the database search does.
+func (p *SearchParams) matchesCreateAt(createAt int64) bool {
+	if p.OnDate != "" {
+		start, end := p.GetOnDateMillis()
+		return createAt >= start && createAt <= end
+	}
+
+	if p.ExcludedDate != "" {
+		start, end := p.GetExcludedDateMillis()
+		if createAt >= start && createAt <= end {
+			return false
+		}
+	}
+	if p.AfterDate != "" && createAt < p.GetAfterDateMillis() {
+		return false
+	}
+	if p.BeforeDate != "" && createAt > p.GetBeforeDateMillis() {
+		return false
+	}
+	if p.ExcludedAfterDate != "" && createAt >= p.GetExcludedAfterDateMillis() {
+		return false
+	}
+	if p.ExcludedBeforeDate != "" && createAt <= p.GetExcludedBeforeDateMillis() {
+		return false
+	}
+
+	return true
+}
+
+func (p *SearchParams) matchesHashtags(postHashtags string) bool {
+	hashtags := strings.Fields(strings.ToLower(postHashtags))
+
+	for _, excluded := range strings.Fields(strings.ToLower(p.ExcludedTerms)) {
+		if slices.Contains(hashtags, excluded) {
+			return false
+		}
+	}
+
+	terms := strings.Fields(strings.ToLower(p.Terms))
+	if len(terms) == 0 {
+		return true
+	}
+
+	found := 0
+	for _, term := range terms {
+		if slices.Contains(hashtags, term) {
+			found++
+		}
+	}
+	if p.OrTerms {
+		return found > 0
+	}
+	return found == len(terms)
+}
+
+func (p *SearchParams) matchesMessage(message string) bool {
+	words := searchTextWords(message)
+
+	for _, phrase := range parseSearchPhrases(p.ExcludedTerms) {
+		if phrase.foundIn(words) {
+			return false
+		}
+	}
+
+	phrases := parseSearchPhrases(p.Terms)
+	if len(phrases) == 0 {
+		return true
+	}
+
+	found := 0
+	for _, phrase := range phrases {
+		if phrase.foundIn(words) {
+			found++
+		}
+	}
+	if p.OrTerms {
+		return found > 0
+	}
+	return found == len(phrases)
+}
+
+// searchPhrase is a search term as the lower case words it's made of. The words must follow each
+// other in a message, and the last one is only a prefix for terms ending with a wildcard.
+type searchPhrase struct {
+	words  []string
+	prefix bool
+}
+
+func parseSearchPhrases(terms string) []searchPhrase {
+	phrases := []searchPhrase{}
+	for _, term := range splitWords(terms) {
+		quoted := len(term) > 1 && strings.HasPrefix(term, `"`) && strings.HasSuffix(term, `"`)
+		words := searchTextWords(term)
+		if len(words) == 0 {
+			continue
+		}
+		phrases = append(phrases, searchPhrase{
+			words:  words,
+			prefix: !quoted && strings.HasSuffix(term, "*"),
+		})
+	}
+	return phrases
+}
+
+func (s searchPhrase) foundIn(words []string) bool {
+	for start := 0; start+len(s.words) <= len(words); start++ {
+		if s.matchesAt(words[start:]) {
+			return true
+		}
+	}
+	return false
+}
+
+func (s searchPhrase) matchesAt(words []string) bool {
+	for i, word := range s.words {
+		if s.prefix && i == len(s.words)-1 {
+			if !strings.HasPrefix(words[i], word) {
+				return false
+			}
+		} else if words[i] != word {
+			return false
+		}
+	}
+	return true
+}
+
+// searchTextWords splits text into lower case words, ignoring punctuation.
+func searchTextWords(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
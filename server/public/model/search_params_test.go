@@ -1664,6 +1664,41 @@ func TestParseSearchParams(t *testing.T) {
 				},
 			},
 		},
+		{
+			Name:  "input with semantic:true should result in a semantic search",
+			Input: "deploy the release semantic:true",
+			Output: []*SearchParams{
+				{
+					Terms:              "deploy the release",
+					ExcludedTerms:      "",
+					IsHashtag:          false,
+					InChannels:         []string{},
+					ExcludedChannels:   []string{},
+					FromUsers:          []string{},
+					ExcludedUsers:      []string{},
+					Extensions:         []string{},
+					ExcludedExtensions: []string{},
+					Semantic:           true,
+				},
+			},
+		},
+		{
+			Name:  "input with semantic:false should result in a regular search",
+			Input: "semantic:false deploy",
+			Output: []*SearchParams{
+				{
+					Terms:              "deploy",
+					ExcludedTerms:      "",
+					IsHashtag:          false,
+					InChannels:         []string{},
+					ExcludedChannels:   []string{},
+					FromUsers:          []string{},
+					ExcludedUsers:      []string{},
+					Extensions:         []string{},
+					ExcludedExtensions: []string{},
+				},
+			},
+		},
 	} {
 		t.Run(testCase.Name, func(t *testing.T) {
 			require.Equal(t, testCase.Output, ParseSearchParams(testCase.Input, 0))
@@ -1797,3 +1832,74 @@ func TestIsSearchParamsListValid(t *testing.T) {
 	appErr = IsSearchParamsListValid([]*SearchParams{})
 	assert.Nil(t, appErr)
 }
+
+func TestSearchParamsMatchesPost(t *testing.T) {
+	channelId := NewId()
+	userId := NewId()
+	createAt := time.Date(2024, 5, 14, 12, 0, 0, 0, time.UTC).UnixMilli()
+
+	newPost := func(message string) *Post {
+		post := &Post{
+			ChannelId: channelId,
+			UserId:    userId,
+			CreateAt:  createAt,
+			Message:   message,
+		}
+		post.Hashtags, _ = ParseHashtags(message)
+		return post
+	}
+
+	for _, tc := range []struct {
+		Name    string
+		Params  SearchParams
+		Message string
+		Matches bool
+	}{
+		{"single term", SearchParams{Terms: "error"}, "An ERROR happened", true},
+		{"missing term", SearchParams{Terms: "error"}, "All good", false},
+		{"partial word", SearchParams{Terms: "err"}, "An error happened", false},
+		{"wildcard", SearchParams{Terms: "err*"}, "An error happened", true},
+		{"all terms", SearchParams{Terms: "error prod"}, "error in prod", true},
+		{"some terms", SearchParams{Terms: "error prod"}, "error in staging", false},
+		{"or terms", SearchParams{Terms: "error prod", OrTerms: true}, "error in staging", true},
+		{"phrase", SearchParams{Terms: `"disk full"`}, "the disk is full", false},
+		{"matching phrase", SearchParams{Terms: `"disk full"`}, "Disk full on db-1", true},
+		{"hyphenated term", SearchParams{Terms: "db-1"}, "Disk full on db-1", true},
+		{"excluded term", SearchParams{Terms: "error", ExcludedTerms: "staging"}, "error in staging", false},
+		{"no terms", SearchParams{}, "anything", true},
+		{"hashtag", SearchParams{Terms: "#prod", IsHashtag: true}, "error #Prod", true},
+		{"missing hashtag", SearchParams{Terms: "#prod", IsHashtag: true}, "error in prod", false},
+		{"excluded hashtag", SearchParams{Terms: "#prod", ExcludedTerms: "#ignore", IsHashtag: true}, "error #prod #ignore", false},
+		{"in channel", SearchParams{InChannels: []string{channelId}}, "message", true},
+		{"in other channel", SearchParams{InChannels: []string{NewId()}}, "message", false},
+		{"excluded channel", SearchParams{ExcludedChannels: []string{channelId}}, "message", false},
+		{"from user", SearchParams{FromUsers: []string{userId}}, "message", true},
+		{"from other user", SearchParams{FromUsers: []string{NewId()}}, "message", false},
+		{"excluded user", SearchParams{ExcludedUsers: []string{userId}}, "message", false},
+		{"on date", SearchParams{OnDate: "2024-05-14"}, "message", true},
+		{"on other date", SearchParams{OnDate: "2024-05-15"}, "message", false},
+		{"after date", SearchParams{AfterDate: "2024-05-13"}, "message", true},
+		{"after later date", SearchParams{AfterDate: "2024-05-14"}, "message", false},
+		{"before date", SearchParams{BeforeDate: "2024-05-15"}, "message", true},
+		{"before earlier date", SearchParams{BeforeDate: "2024-05-14"}, "message", false},
+		{"excluded date", SearchParams{ExcludedDate: "2024-05-14"}, "message", false},
+	} {
+		t.Run(tc.Name, func(t *testing.T) {
+			assert.Equal(t, tc.Matches, tc.Params.MatchesPost(newPost(tc.Message)))
+		})
+	}
+
+	t.Run("deleted and system posts", func(t *testing.T) {
+		params := &SearchParams{Terms: "joined"}
+
+		post := newPost("joined the channel")
+		require.True(t, params.MatchesPost(post))
+
+		post.Type = PostTypeJoinChannel
+		assert.False(t, params.MatchesPost(post))
+
+		post = newPost("joined the channel")
+		post.DeleteAt = GetMillis()
+		assert.False(t, params.MatchesPost(post))
+	})
+}
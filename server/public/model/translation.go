@@ -0,0 +1,27 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package model
+
+import (
+	"regexp"
+)
+
+var translationLanguagePattern = regexp.MustCompile(`^[a-z]{2,3}(-[A-Za-z0-9]{2,8})*$`)
+
+// Translation is the machine translation of a piece of text, such as the message of a post.
+type Translation struct {
+	// Language is the language the text was translated to.
+	Language string `json:"language"`
+
+	// SourceLanguage is the language of the original text, if the provider was able to detect it.
+	SourceLanguage string `json:"source_language,omitempty"`
+
+	Text string `json:"text"`
+}
+
+// IsValidTranslationLanguage reports whether lang looks like a language tag that can be passed
+// to a translation provider, such as "de" or "pt-BR".
+func IsValidTranslationLanguage(lang string) bool {
+	return len(lang) <= 35 && translationLanguagePattern.MatchString(lang)
+}
@@ -0,0 +1,21 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package model
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsValidTranslationLanguage(t *testing.T) {
+	for _, lang := range []string{"de", "fil", "pt-BR", "zh-Hans", "sr-Latn-RS"} {
+		assert.True(t, IsValidTranslationLanguage(lang), lang)
+	}
+
+	for _, lang := range []string{"", "d", "DE", "german", "pt_BR", "de-", "en-x", "../en", "de&q=x", "en-" + strings.Repeat("a", 40)} {
+		assert.False(t, IsValidTranslationLanguage(lang), lang)
+	}
+}
@@ -0,0 +1,169 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package model
+
+import (
+	"net/http"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	WebAuthnCredentialNameMaxRunes = 64
+	WebAuthnCredentialIdMaxLength  = 512
+
+	WebAuthnPublicKeyCredentialType = "public-key"
+	WebAuthnTimeout                 = 1000 * 60 * 5 // 5 minutes
+)
+
+// WebAuthnCredential is a WebAuthn public key credential registered by a user as a
+// second authentication factor.
+type WebAuthnCredential struct {
+	Id           string `json:"id"`
+	UserId       string `json:"user_id"`
+	Name         string `json:"name"`
+	CredentialId string `json:"credential_id"`
+	PublicKey    string `json:"-"`
+	SignCount    int64  `json:"sign_count"`
+	AAGUID       string `json:"aaguid"`
+	CreateAt     int64  `json:"create_at"`
+	LastUsedAt   int64  `json:"last_used_at"`
+}
+
+func (c *WebAuthnCredential) Auditable() map[string]any {
+	return map[string]any{
+		"id":            c.Id,
+		"user_id":       c.UserId,
+		"name":          c.Name,
+		"credential_id": c.CredentialId,
+		"aaguid":        c.AAGUID,
+		"create_at":     c.CreateAt,
+		"last_used_at":  c.LastUsedAt,
+	}
+}
+
+func (c *WebAuthnCredential) PreSave() {
+	if c.Id == "" {
+		c.Id = NewId()
+	}
+
+	c.Name = strings.TrimSpace(c.Name)
+
+	if c.CreateAt == 0 {
+		c.CreateAt = GetMillis()
+	}
+}
+
+func (c *WebAuthnCredential) IsValid() *AppError {
+	if !IsValidId(c.Id) {
+		return NewAppError("WebAuthnCredential.IsValid", "model.webauthn_credential.is_valid.id.app_error", nil, "", http.StatusBadRequest)
+	}
+
+	if !IsValidId(c.UserId) {
+		return NewAppError("WebAuthnCredential.IsValid", "model.webauthn_credential.is_valid.user_id.app_error", nil, "", http.StatusBadRequest)
+	}
+
+	if c.Name == "" || utf8.RuneCountInString(c.Name) > WebAuthnCredentialNameMaxRunes {
+		return NewAppError("WebAuthnCredential.IsValid", "model.webauthn_credential.is_valid.name.app_error", map[string]any{"MaxLength": WebAuthnCredentialNameMaxRunes}, "", http.StatusBadRequest)
+	}
+
+	if c.CredentialId == "" || len(c.CredentialId) > WebAuthnCredentialIdMaxLength {
+		return NewAppError("WebAuthnCredential.IsValid", "model.webauthn_credential.is_valid.credential_id.app_error", nil, "", http.StatusBadRequest)
+	}
+
+	if c.PublicKey == "" {
+		return NewAppError("WebAuthnCredential.IsValid", "model.webauthn_credential.is_valid.public_key.app_error", nil, "", http.StatusBadRequest)
+	}
+
+	if c.CreateAt == 0 {
+		return NewAppError("WebAuthnCredential.IsValid", "model.webauthn_credential.is_valid.create_at.app_error", nil, "", http.StatusBadRequest)
+	}
+
+	return nil
+}
+
+// The types below follow the JSON serialization of the WebAuthn Level 3 specification
+// so that clients can pass them straight to PublicKeyCredential.parseCreationOptionsFromJSON,
+// PublicKeyCredential.parseRequestOptionsFromJSON and PublicKeyCredential.toJSON.
+// Binary values are base64url encoded without padding.
+
+type WebAuthnRelyingParty struct {
+	Id   string `json:"id"`
+	Name string `json:"name"`
+}
+
+type WebAuthnUserEntity struct {
+	Id          string `json:"id"`
+	Name        string `json:"name"`
+	DisplayName string `json:"displayName"`
+}
+
+type WebAuthnCredentialParameter struct {
+	Type string `json:"type"`
+	Alg  int    `json:"alg"`
+}
+
+type WebAuthnCredentialDescriptor struct {
+	Type string `json:"type"`
+	Id   string `json:"id"`
+}
+
+type WebAuthnAuthenticatorSelection struct {
+	ResidentKey      string `json:"residentKey"`
+	UserVerification string `json:"userVerification"`
+}
+
+type WebAuthnCreationOptions struct {
+	Challenge              string                         `json:"challenge"`
+	RelyingParty           WebAuthnRelyingParty           `json:"rp"`
+	User                   WebAuthnUserEntity             `json:"user"`
+	PubKeyCredParams       []WebAuthnCredentialParameter  `json:"pubKeyCredParams"`
+	Timeout                int64                          `json:"timeout"`
+	ExcludeCredentials     []WebAuthnCredentialDescriptor `json:"excludeCredentials"`
+	AuthenticatorSelection WebAuthnAuthenticatorSelection `json:"authenticatorSelection"`
+	Attestation            string                         `json:"attestation"`
+}
+
+type WebAuthnRequestOptions struct {
+	Challenge        string                         `json:"challenge"`
+	Timeout          int64                          `json:"timeout"`
+	RelyingPartyId   string                         `json:"rpId"`
+	AllowCredentials []WebAuthnCredentialDescriptor `json:"allowCredentials"`
+	UserVerification string                         `json:"userVerification"`
+}
+
+type WebAuthnAttestationResponse struct {
+	ClientDataJSON    string `json:"clientDataJSON"`
+	AttestationObject string `json:"attestationObject"`
+}
+
+// WebAuthnRegistration is the result of a credential creation ceremony, along with
+// the name the user chose for the credential.
+type WebAuthnRegistration struct {
+	Name     string                      `json:"name"`
+	Id       string                      `json:"id"`
+	Type     string                      `json:"type"`
+	Response WebAuthnAttestationResponse `json:"response"`
+}
+
+type WebAuthnAssertionResponse struct {
+	ClientDataJSON    string `json:"clientDataJSON"`
+	AuthenticatorData string `json:"authenticatorData"`
+	Signature         string `json:"signature"`
+	UserHandle        string `json:"userHandle,omitempty"`
+}
+
+// WebAuthnAssertion is the result of an authentication ceremony. It is sent as the
+// MFA token when logging in.
+type WebAuthnAssertion struct {
+	Id       string                    `json:"id"`
+	Type     string                    `json:"type"`
+	Response WebAuthnAssertionResponse `json:"response"`
+}
+
+// IsWebAuthnAssertion reports whether an MFA token holds a WebAuthn assertion rather
+// than a one-time password.
+func IsWebAuthnAssertion(token string) bool {
+	return strings.HasPrefix(strings.TrimSpace(token), "{")
+}
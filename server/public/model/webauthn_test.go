@@ -0,0 +1,83 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package model
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWebAuthnCredentialIsValid(t *testing.T) {
+	c := WebAuthnCredential{}
+
+	appErr := c.IsValid()
+	require.NotNil(t, appErr)
+	require.Equal(t, "model.webauthn_credential.is_valid.id.app_error", appErr.Id)
+
+	c.Id = NewId()
+	appErr = c.IsValid()
+	require.NotNil(t, appErr)
+	require.Equal(t, "model.webauthn_credential.is_valid.user_id.app_error", appErr.Id)
+
+	c.UserId = NewId()
+	appErr = c.IsValid()
+	require.NotNil(t, appErr)
+	require.Equal(t, "model.webauthn_credential.is_valid.name.app_error", appErr.Id)
+
+	c.Name = strings.Repeat("ü", WebAuthnCredentialNameMaxRunes+1)
+	appErr = c.IsValid()
+	require.NotNil(t, appErr)
+	require.Equal(t, "model.webauthn_credential.is_valid.name.app_error", appErr.Id)
+
+	c.Name = strings.Repeat("ü", WebAuthnCredentialNameMaxRunes)
+	appErr = c.IsValid()
+	require.NotNil(t, appErr)
+	require.Equal(t, "model.webauthn_credential.is_valid.credential_id.app_error", appErr.Id)
+
+	c.CredentialId = strings.Repeat("a", WebAuthnCredentialIdMaxLength+1)
+	appErr = c.IsValid()
+	require.NotNil(t, appErr)
+	require.Equal(t, "model.webauthn_credential.is_valid.credential_id.app_error", appErr.Id)
+
+	c.CredentialId = "AAECAw"
+	appErr = c.IsValid()
+	require.NotNil(t, appErr)
+	require.Equal(t, "model.webauthn_credential.is_valid.public_key.app_error", appErr.Id)
+
+	c.PublicKey = "pQECAyYgASFYIA"
+	appErr = c.IsValid()
+	require.NotNil(t, appErr)
+	require.Equal(t, "model.webauthn_credential.is_valid.create_at.app_error", appErr.Id)
+
+	c.CreateAt = GetMillis()
+	require.Nil(t, c.IsValid())
+}
+
+func TestWebAuthnCredentialPreSave(t *testing.T) {
+	c := WebAuthnCredential{Name: "  YubiKey  "}
+	c.PreSave()
+
+	assert.True(t, IsValidId(c.Id))
+	assert.Equal(t, "YubiKey", c.Name)
+	assert.NotZero(t, c.CreateAt)
+}
+
+func TestWebAuthnCredentialJSON(t *testing.T) {
+	c := WebAuthnCredential{Id: NewId(), PublicKey: "pQECAyYgASFYIA"}
+
+	b, err := json.Marshal(c)
+	require.NoError(t, err)
+	assert.NotContains(t, string(b), c.PublicKey)
+}
+
+func TestIsWebAuthnAssertion(t *testing.T) {
+	assert.False(t, IsWebAuthnAssertion(""))
+	assert.False(t, IsWebAuthnAssertion("123456"))
+	assert.True(t, IsWebAuthnAssertion(`{"id":"AAECAw","type":"public-key"}`))
+	assert.True(t, IsWebAuthnAssertion(` {"id":"AAECAw"}`))
+}
@@ -91,6 +91,12 @@ const (
 	WebsocketEventChannelBookmarkSorted               WebsocketEventType = "channel_bookmark_sorted"
 	WebsocketPresenceIndicator                        WebsocketEventType = "presence"
 	WebsocketPostedNotifyAck                          WebsocketEventType = "posted_notify_ack"
+	WebsocketScheduledPostCreated                     WebsocketEventType = "scheduled_post_created"
+	WebsocketScheduledPostUpdated                     WebsocketEventType = "scheduled_post_updated"
+	WebsocketScheduledPostDeleted                     WebsocketEventType = "scheduled_post_deleted"
+	WebsocketEventSavedSearchMatched                  WebsocketEventType = "saved_search_matched"
+	WebsocketEventPollUpdated                         WebsocketEventType = "poll_updated"
+	WebsocketEventReadReceiptUpdated                  WebsocketEventType = "read_receipt_updated"
 )
 
 type WebSocketMessage interface {
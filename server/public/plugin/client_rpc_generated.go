@@ -1161,6 +1161,44 @@ func (s *hooksRPCServer) GenerateSupportData(args *Z_GenerateSupportDataArgs, re
 	return nil
 }
 
+func init() {
+	hookNameToId["TranslateText"] = TranslateTextID
+}
+
+type Z_TranslateTextArgs struct {
+	A *Context
+	B string
+	C string
+}
+
+type Z_TranslateTextReturns struct {
+	A *model.Translation
+	B error
+}
+
+func (g *hooksRPCClient) TranslateText(c *Context, text, targetLanguage string) (*model.Translation, error) {
+	_args := &Z_TranslateTextArgs{c, text, targetLanguage}
+	_returns := &Z_TranslateTextReturns{}
+	if g.implemented[TranslateTextID] {
+		if err := g.client.Call("Plugin.TranslateText", _args, _returns); err != nil {
+			g.log.Error("RPC call TranslateText to plugin failed.", mlog.Err(err))
+		}
+	}
+	return _returns.A, _returns.B
+}
+
+func (s *hooksRPCServer) TranslateText(args *Z_TranslateTextArgs, returns *Z_TranslateTextReturns) error {
+	if hook, ok := s.impl.(interface {
+		TranslateText(c *Context, text, targetLanguage string) (*model.Translation, error)
+	}); ok {
+		returns.A, returns.B = hook.TranslateText(args.A, args.B, args.C)
+		returns.B = encodableError(returns.B)
+	} else {
+		return encodableError(fmt.Errorf("Hook TranslateText called but not implemented."))
+	}
+	return nil
+}
+
 type Z_RegisterCommandArgs struct {
 	A *model.Command
 }
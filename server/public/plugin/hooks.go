@@ -61,6 +61,7 @@ const (
 	OnSharedChannelsAttachmentSyncMsgID       = 43
 	OnSharedChannelsProfileImageSyncMsgID     = 44
 	GenerateSupportDataID                     = 45
+	TranslateTextID                           = 46
 	TotalHooksID                              = iota
 )
 
@@ -389,4 +390,14 @@ type Hooks interface {
 	//
 	// Minimum server version: 9.8
 	GenerateSupportData(c *Context) ([]*model.FileData, error)
+
+	// TranslateText is invoked when a post needs to be machine translated and the translation
+	// provider in TranslationSettings is set to plugin. Implementing this hook registers the plugin
+	// as a translation provider.
+	//
+	// Return the translation of the text to the target language, or nil to let another plugin
+	// translate it.
+	//
+	// Minimum server version: 10.2
+	TranslateText(c *Context, text, targetLanguage string) (*model.Translation, error)
 }
@@ -291,3 +291,10 @@ func (hooks *hooksTimerLayer) GenerateSupportData(c *Context) ([]*model.FileData
 	hooks.recordTime(startTime, "GenerateSupportData", _returnsB == nil)
 	return _returnsA, _returnsB
 }
+
+func (hooks *hooksTimerLayer) TranslateText(c *Context, text, targetLanguage string) (*model.Translation, error) {
+	startTime := timePkg.Now()
+	_returnsA, _returnsB := hooks.hooksImpl.TranslateText(c, text, targetLanguage)
+	hooks.recordTime(startTime, "TranslateText", _returnsB == nil)
+	return _returnsA, _returnsB
+}
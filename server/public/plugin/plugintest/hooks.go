@@ -534,6 +534,36 @@ func (_m *Hooks) ServeMetrics(c *plugin.Context, w http.ResponseWriter, r *http.
 	_m.Called(c, w, r)
 }
 
+// TranslateText provides a mock function with given fields: c, text, targetLanguage
+func (_m *Hooks) TranslateText(c *plugin.Context, text string, targetLanguage string) (*model.Translation, error) {
+	ret := _m.Called(c, text, targetLanguage)
+
+	if len(ret) == 0 {
+		panic("no return value specified for TranslateText")
+	}
+
+	var r0 *model.Translation
+	var r1 error
+	if rf, ok := ret.Get(0).(func(*plugin.Context, string, string) (*model.Translation, error)); ok {
+		return rf(c, text, targetLanguage)
+	}
+	if rf, ok := ret.Get(0).(func(*plugin.Context, string, string) *model.Translation); ok {
+		r0 = rf(c, text, targetLanguage)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.Translation)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(*plugin.Context, string, string) error); ok {
+		r1 = rf(c, text, targetLanguage)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // UserHasBeenCreated provides a mock function with given fields: c, user
 func (_m *Hooks) UserHasBeenCreated(c *plugin.Context, user *model.User) {
 	_m.Called(c, user)